@@ -58,7 +58,7 @@ func (suite *AuthIntegrationTestSuite) SetupSuite() {
 	// Initialize repositories and services
 	userRepo := repositories.NewUserRepositorySharded(suite.shardMgr)
 	cooperativeRepo := repositories.NewCooperativeRepository(suite.shardMgr)
-	suite.userService = services.NewUserServiceAuth(userRepo, cooperativeRepo, suite.jwtManager)
+	suite.userService = services.NewUserServiceAuth(userRepo, cooperativeRepo, suite.jwtManager, nil)
 	
 	// Initialize controller
 	suite.authController = controllers.NewAuthController(suite.userService)
@@ -7,14 +7,119 @@ type Config struct {
 	Port        string
 	Environment string
 	JWTSecret   string
+	// BaseCurrency is the reporting currency that multi-currency amounts are converted to
+	BaseCurrency string
+	// PaymentProvider collects investment payments unless a request names another
+	PaymentProvider string
+	// PaymentSimulatorSecret signs the built-in simulator's callbacks; the simulator
+	// is not available in production
+	PaymentSimulatorSecret string
+	// PayoutCSVColumns, PayoutCSVDelimiter and PayoutCSVHeader describe the bank's
+	// CSV bulk transfer layout; empty values keep the default layout
+	PayoutCSVColumns   string
+	PayoutCSVDelimiter string
+	PayoutCSVHeader    bool
+	// ZakatNisab (in the base currency), ZakatRate (percent), ZakatHaulDays and
+	// ZakatHaulRule (per_holding or portfolio) govern zakat statements
+	ZakatNisab    string
+	ZakatRate     string
+	ZakatHaulDays string
+	ZakatHaulRule string
+	// WithdrawalNoticeDays and WithdrawalPenalty (a fraction of the redemption
+	// value) govern early withdrawals for cooperatives without an active
+	// investment policy
+	WithdrawalNoticeDays string
+	WithdrawalPenalty    string
+	// WithdrawalCompletionInterval is how often withdrawals whose refund was not
+	// settled are retried, as a Go duration
+	WithdrawalCompletionInterval string
+	// StakeTradeCompletionInterval is how often approved stake trades whose
+	// transfer was not recorded in the ledger are retried, as a Go duration
+	StakeTradeCompletionInterval string
+	// FundingDeadlineInterval is how often projects past their funding deadline
+	// are closed, as a Go duration
+	FundingDeadlineInterval string
+	// StatementInterval is how often due investor statements are generated,
+	// as a Go duration
+	StatementInterval string
+	// TaxDefaultJurisdiction is the jurisdiction cooperatives withhold tax
+	// under until they choose one (ID or MY)
+	TaxDefaultJurisdiction string
+	// SanctionsLists is a comma-separated list of watchlist files (CSV or UN
+	// consolidated list XML), each optionally named as name=path; members,
+	// businesses and payout beneficiaries are not screened when it is empty
+	SanctionsLists string
+	// SanctionsReloadInterval is how often changed list files are reloaded, as a
+	// Go duration
+	SanctionsReloadInterval string
+	// SanctionsNameScore and SanctionsNameOnlyScore are the least name
+	// similarity (0 to 1) for a potential match with and without an agreeing
+	// date of birth; SanctionsBirthYearTolerance is how many years apart dates
+	// of birth may be and still agree
+	SanctionsNameScore          string
+	SanctionsNameOnlyScore      string
+	SanctionsBirthYearTolerance string
+	// TransferInterval is how often due fund transfers are executed, as a Go
+	// duration; TransferMaxRetries, TransferRetryBaseDelay and
+	// TransferRetryMaxDelay govern retries of transient failures, and
+	// TransferBatchSize caps the transfers executed per run
+	TransferInterval       string
+	TransferMaxRetries     string
+	TransferRetryBaseDelay string
+	TransferRetryMaxDelay  string
+	TransferBatchSize      string
+	// InvestmentReservationTTL is how long a pending investment holds its
+	// amount against the project's funding goal, and
+	// InvestmentReservationExpiryInterval how often lapsed holds are
+	// released, both as Go durations
+	InvestmentReservationTTL            string
+	InvestmentReservationExpiryInterval string
 }
 
 func Load() *Config {
 	return &Config{
-		DatabaseURL: getEnv("DATABASE_URL", ""),
-		Port:        getEnv("PORT", "8080"),
-		Environment: getEnv("ENVIRONMENT", "development"),
-		JWTSecret:   getEnv("JWT_SECRET", "your-super-secret-jwt-key"),
+		DatabaseURL:  getEnv("DATABASE_URL", ""),
+		Port:         getEnv("PORT", "8080"),
+		Environment:  getEnv("ENVIRONMENT", "development"),
+		JWTSecret:    getEnv("JWT_SECRET", "your-super-secret-jwt-key"),
+		BaseCurrency: getEnv("BASE_CURRENCY", "IDR"),
+
+		PaymentProvider:        getEnv("PAYMENT_PROVIDER", "simulator"),
+		PaymentSimulatorSecret: getEnv("PAYMENT_SIMULATOR_SECRET", "payment-simulator-secret"),
+
+		PayoutCSVColumns:   getEnv("PAYOUT_CSV_COLUMNS", ""),
+		PayoutCSVDelimiter: getEnv("PAYOUT_CSV_DELIMITER", ","),
+		PayoutCSVHeader:    getEnv("PAYOUT_CSV_HEADER", "true") == "true",
+
+		ZakatNisab:    getEnv("ZAKAT_NISAB", "85000000"),
+		ZakatRate:     getEnv("ZAKAT_RATE", "2.5"),
+		ZakatHaulDays: getEnv("ZAKAT_HAUL_DAYS", "354"),
+		ZakatHaulRule: getEnv("ZAKAT_HAUL_RULE", "per_holding"),
+
+		WithdrawalNoticeDays:         getEnv("WITHDRAWAL_NOTICE_DAYS", "30"),
+		WithdrawalPenalty:            getEnv("WITHDRAWAL_PENALTY", "0.02"),
+		WithdrawalCompletionInterval: getEnv("WITHDRAWAL_COMPLETION_INTERVAL", "15m"),
+		StakeTradeCompletionInterval: getEnv("STAKE_TRADE_COMPLETION_INTERVAL", "15m"),
+
+		FundingDeadlineInterval: getEnv("FUNDING_DEADLINE_INTERVAL", "15m"),
+		StatementInterval:       getEnv("STATEMENT_INTERVAL", "24h"),
+
+		TaxDefaultJurisdiction: getEnv("TAX_DEFAULT_JURISDICTION", "ID"),
+
+		SanctionsLists:              getEnv("SANCTIONS_LISTS", ""),
+		SanctionsReloadInterval:     getEnv("SANCTIONS_RELOAD_INTERVAL", "1h"),
+		SanctionsNameScore:          getEnv("SANCTIONS_NAME_SCORE", "0.85"),
+		SanctionsNameOnlyScore:      getEnv("SANCTIONS_NAME_ONLY_SCORE", "0.92"),
+		SanctionsBirthYearTolerance: getEnv("SANCTIONS_BIRTH_YEAR_TOLERANCE", "1"),
+
+		TransferInterval:       getEnv("TRANSFER_INTERVAL", "30s"),
+		TransferMaxRetries:     getEnv("TRANSFER_MAX_RETRIES", "3"),
+		TransferRetryBaseDelay: getEnv("TRANSFER_RETRY_BASE_DELAY", "1m"),
+		TransferRetryMaxDelay:  getEnv("TRANSFER_RETRY_MAX_DELAY", "1h"),
+		TransferBatchSize:      getEnv("TRANSFER_BATCH_SIZE", "50"),
+
+		InvestmentReservationTTL:            getEnv("INVESTMENT_RESERVATION_TTL", "30m"),
+		InvestmentReservationExpiryInterval: getEnv("INVESTMENT_RESERVATION_EXPIRY_INTERVAL", "1m"),
 	}
 }
 
@@ -23,4 +128,4 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
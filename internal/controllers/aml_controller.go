@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AMLController handles AML monitoring rule and compliance case API endpoints
+type AMLController struct {
+	amlService services.AMLMonitoringService
+}
+
+// NewAMLController creates a new AML controller
+func NewAMLController(amlService services.AMLMonitoringService) *AMLController {
+	return &AMLController{
+		amlService: amlService,
+	}
+}
+
+// ListRules lists the active monitoring rules; with a cooperative_id query
+// parameter, those that apply to that cooperative
+func (c *AMLController) ListRules(ctx *gin.Context) {
+	var cooperativeID *uuid.UUID
+	if idStr := ctx.Query("cooperative_id"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+			return
+		}
+		cooperativeID = &id
+	}
+
+	rules, err := c.amlService.ListRules(ctx, cooperativeID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get AML rules", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "AML rules retrieved successfully", rules)
+}
+
+// CreateRule configures a monitoring rule
+func (c *AMLController) CreateRule(ctx *gin.Context) {
+	var req entities.CreateAMLRuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	rule, err := c.amlService.CreateRule(ctx, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to create AML rule", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "AML rule created successfully", rule)
+}
+
+// DeactivateRule withdraws a monitoring rule
+func (c *AMLController) DeactivateRule(ctx *gin.Context) {
+	ruleID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid rule ID", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.amlService.DeactivateRule(ctx, ruleID, userID); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to deactivate AML rule", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "AML rule deactivated successfully", nil)
+}
+
+// ListCases lists a cooperative's alert cases, optionally by status or subject
+func (c *AMLController) ListCases(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	page, limit := paginationQuery(ctx)
+	filter := &entities.AMLCaseFilter{
+		Status: ctx.Query("status"),
+		Page:   page,
+		Limit:  limit,
+	}
+	if subjectStr := ctx.Query("subject_id"); subjectStr != "" {
+		subjectID, err := uuid.Parse(subjectStr)
+		if err != nil {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid subject ID", err)
+			return
+		}
+		filter.SubjectID = &subjectID
+	}
+
+	cases, total, err := c.amlService.ListCases(ctx, cooperativeID, filter)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get AML cases", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "AML cases retrieved successfully", utils.PaginatedResponse{
+		Data:       cases,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
+
+// GetCase returns a case with its alerts, flagged transactions and history
+func (c *AMLController) GetCase(ctx *gin.Context) {
+	cooperativeID, caseID, ok := amlCaseParams(ctx)
+	if !ok {
+		return
+	}
+
+	amlCase, err := c.amlService.GetCase(ctx, cooperativeID, caseID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Failed to get AML case", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "AML case retrieved successfully", amlCase)
+}
+
+// Investigate assigns an open case to the current officer for investigation
+func (c *AMLController) Investigate(ctx *gin.Context) {
+	c.act(ctx, "investigation started", c.amlService.StartInvestigation)
+}
+
+// Escalate escalates a case with the current officer's notes
+func (c *AMLController) Escalate(ctx *gin.Context) {
+	c.act(ctx, "escalated", c.amlService.EscalateCase)
+}
+
+// Close closes a case with the current officer's conclusion
+func (c *AMLController) Close(ctx *gin.Context) {
+	cooperativeID, caseID, ok := amlCaseParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req entities.CloseAMLCaseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	amlCase, err := c.amlService.CloseCase(ctx, cooperativeID, caseID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to close AML case", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "AML case closed successfully", amlCase)
+}
+
+// ExportReport downloads a case's suspicious transaction report
+func (c *AMLController) ExportReport(ctx *gin.Context) {
+	cooperativeID, caseID, ok := amlCaseParams(ctx)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	file, err := c.amlService.ExportSuspiciousTransactionReport(ctx, cooperativeID, caseID, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Failed to export suspicious transaction report", err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+	ctx.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+type amlCaseAction func(ctx context.Context, cooperativeID, caseID, officerID uuid.UUID, req *entities.AMLCaseActionRequest) (*entities.AMLCase, error)
+
+// act moves a case on in its workflow with the officer's optional notes
+func (c *AMLController) act(ctx *gin.Context, outcome string, action amlCaseAction) {
+	cooperativeID, caseID, ok := amlCaseParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req entities.AMLCaseActionRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	amlCase, err := action(ctx, cooperativeID, caseID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to update AML case", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "AML case "+outcome+" successfully", amlCase)
+}
+
+func amlCaseParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	caseID, err := uuid.Parse(ctx.Param("case_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid case ID", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return cooperativeID, caseID, true
+}
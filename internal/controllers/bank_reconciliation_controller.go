@@ -0,0 +1,290 @@
+package controllers
+
+import (
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BankReconciliationController handles bank statement import and reconciliation API endpoints
+type BankReconciliationController struct {
+	reconciliationService services.BankReconciliationService
+}
+
+// NewBankReconciliationController creates a new bank reconciliation controller
+func NewBankReconciliationController(reconciliationService services.BankReconciliationService) *BankReconciliationController {
+	return &BankReconciliationController{
+		reconciliationService: reconciliationService,
+	}
+}
+
+// ImportStatement uploads a CSV or MT940 statement and matches its lines
+func (c *BankReconciliationController) ImportStatement(ctx *gin.Context) {
+	var req entities.ImportBankStatementRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Statement file is required", err)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to read statement file", err)
+		return
+	}
+	defer file.Close()
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := c.reconciliationService.ImportStatement(ctx, &req, fileHeader.Filename, file, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to import statement", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Statement imported successfully", result)
+}
+
+// GetStatements lists a cooperative's imported statements
+func (c *BankReconciliationController) GetStatements(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	page, limit := paginationQuery(ctx)
+
+	statements, total, err := c.reconciliationService.GetStatements(ctx, cooperativeID, page, limit)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get statements", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Statements retrieved successfully", utils.PaginatedResponse{
+		Data:       statements,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
+
+// GetStatement gets an imported statement
+func (c *BankReconciliationController) GetStatement(ctx *gin.Context) {
+	statementID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid statement ID", err)
+		return
+	}
+
+	statement, err := c.reconciliationService.GetStatement(ctx, statementID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Statement not found", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Statement retrieved successfully", statement)
+}
+
+// GetStatementLines lists the lines of a statement, optionally filtered by status
+func (c *BankReconciliationController) GetStatementLines(ctx *gin.Context) {
+	statementID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid statement ID", err)
+		return
+	}
+
+	statement, err := c.reconciliationService.GetStatement(ctx, statementID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Statement not found", err)
+		return
+	}
+
+	page, limit := paginationQuery(ctx)
+
+	lines, total, err := c.reconciliationService.GetStatementLines(ctx, &entities.BankStatementLineFilter{
+		CooperativeID: statement.CooperativeID,
+		StatementID:   &statementID,
+		Status:        ctx.Query("status"),
+		Page:          page,
+		Limit:         limit,
+	})
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get statement lines", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Statement lines retrieved successfully", utils.PaginatedResponse{
+		Data:       lines,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
+
+// GetExceptions lists the cooperative's statement lines waiting for manual resolution
+func (c *BankReconciliationController) GetExceptions(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	page, limit := paginationQuery(ctx)
+
+	lines, total, err := c.reconciliationService.GetExceptions(ctx, cooperativeID, page, limit)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get reconciliation exceptions", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Reconciliation exceptions retrieved successfully", utils.PaginatedResponse{
+		Data:       lines,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
+
+// Reconcile re-runs automatic matching over the cooperative's open statement lines
+func (c *BankReconciliationController) Reconcile(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := c.reconciliationService.ReconcileCooperative(ctx, cooperativeID, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to reconcile statement lines", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Statement lines reconciled successfully", result)
+}
+
+// ManualMatch matches an exception to an investment or transfer
+func (c *BankReconciliationController) ManualMatch(ctx *gin.Context) {
+	lineID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid statement line ID", err)
+		return
+	}
+
+	var req entities.ManualMatchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	line, err := c.reconciliationService.ManualMatch(ctx, lineID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to match statement line", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Statement line matched successfully", line)
+}
+
+// IgnoreLine resolves an exception that needs no match
+func (c *BankReconciliationController) IgnoreLine(ctx *gin.Context) {
+	lineID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid statement line ID", err)
+		return
+	}
+
+	var req entities.IgnoreStatementLineRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	line, err := c.reconciliationService.IgnoreLine(ctx, lineID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to ignore statement line", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Statement line ignored successfully", line)
+}
+
+// ValidatePaymentReference checks a payment reference against the cooperative's pending payments
+func (c *BankReconciliationController) ValidatePaymentReference(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	reference := ctx.Query("reference")
+	valid, err := c.reconciliationService.ValidatePaymentReference(ctx, cooperativeID, reference)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to validate payment reference", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Payment reference validated successfully", gin.H{
+		"reference": reference,
+		"valid":     valid,
+	})
+}
+
+// paginationQuery reads the page and limit query parameters with the usual defaults
+func paginationQuery(ctx *gin.Context) (int, int) {
+	page := utils.GetIntQuery(ctx, "page", 1)
+	limit := utils.GetIntQuery(ctx, "limit", 20)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return page, limit
+}
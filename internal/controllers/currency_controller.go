@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CurrencyController handles currency registry and exchange rate API endpoints
+type CurrencyController struct {
+	currencyService services.CurrencyService
+}
+
+// NewCurrencyController creates a new currency controller
+func NewCurrencyController(currencyService services.CurrencyService) *CurrencyController {
+	return &CurrencyController{
+		currencyService: currencyService,
+	}
+}
+
+// ListCurrencies lists the registry currencies
+func (c *CurrencyController) ListCurrencies(ctx *gin.Context) {
+	activeOnly := ctx.DefaultQuery("active", "true") == "true"
+
+	currencies, err := c.currencyService.ListCurrencies(ctx, activeOnly)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get currencies", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Currencies retrieved successfully", gin.H{
+		"base_currency": c.currencyService.BaseCurrency(),
+		"currencies":    currencies,
+	})
+}
+
+// CreateCurrency adds or updates a registry currency
+func (c *CurrencyController) CreateCurrency(ctx *gin.Context) {
+	var req entities.CreateCurrencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	currency, err := c.currencyService.CreateCurrency(ctx, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to save currency", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Currency saved successfully", currency)
+}
+
+// ListExchangeRates lists the exchange rate history, optionally filtered by pair
+func (c *CurrencyController) ListExchangeRates(ctx *gin.Context) {
+	page := utils.GetIntQuery(ctx, "page", 1)
+	limit := utils.GetIntQuery(ctx, "limit", 20)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	rates, total, err := c.currencyService.ListExchangeRates(ctx, ctx.Query("base"), ctx.Query("quote"), page, limit)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get exchange rates", err)
+		return
+	}
+
+	response := utils.PaginatedResponse{
+		Data:       rates,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Exchange rates retrieved successfully", response)
+}
+
+// CreateExchangeRate records a new exchange rate
+func (c *CurrencyController) CreateExchangeRate(ctx *gin.Context) {
+	var req entities.CreateExchangeRateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	rate, err := c.currencyService.CreateExchangeRate(ctx, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to create exchange rate", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Exchange rate created successfully", rate)
+}
+
+// GetExchangeRate gets the rate between two currencies, optionally as of a time
+func (c *CurrencyController) GetExchangeRate(ctx *gin.Context) {
+	at := time.Now()
+	if atStr := ctx.Query("at"); atStr != "" {
+		parsed, err := time.Parse(time.RFC3339, atStr)
+		if err != nil {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid at time format", err)
+			return
+		}
+		at = parsed
+	}
+
+	rate, err := c.currencyService.GetExchangeRate(ctx, ctx.Query("from"), ctx.Query("to"), at)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Exchange rate not found", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Exchange rate retrieved successfully", rate)
+}
+
+// ConvertAmount converts an amount at the rate effective at the requested time
+func (c *CurrencyController) ConvertAmount(ctx *gin.Context) {
+	var req entities.ConvertAmountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	at := time.Now()
+	if req.AsOf != nil {
+		at = *req.AsOf
+	}
+
+	amount, err := req.Amount.InCurrency(req.From)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid amount", err)
+		return
+	}
+
+	converted, err := c.currencyService.Convert(ctx, amount, req.To, at)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to convert amount", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Amount converted successfully", converted)
+}
+
+// currentUserID returns the authenticated user ID, writing an error response if there is none
+func currentUserID(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(ctx, http.StatusUnauthorized, "User not authenticated", nil)
+		return uuid.Nil, false
+	}
+
+	id, ok := userID.(uuid.UUID)
+	if !ok {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Invalid user ID format", nil)
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
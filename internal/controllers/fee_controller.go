@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FeeController handles promotional fee waiver and fee document API endpoints
+type FeeController struct {
+	feeService services.FeeService
+}
+
+// NewFeeController creates a new fee controller
+func NewFeeController(feeService services.FeeService) *FeeController {
+	return &FeeController{
+		feeService: feeService,
+	}
+}
+
+// CreateWaiver creates a promotional fee waiver
+func (c *FeeController) CreateWaiver(ctx *gin.Context) {
+	var req entities.CreateFeeWaiverRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	waiver, err := c.feeService.CreateWaiver(ctx, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to create fee waiver", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Fee waiver created successfully", waiver)
+}
+
+// GetWaivers lists fee waivers, only those in force unless all=true
+func (c *FeeController) GetWaivers(ctx *gin.Context) {
+	waivers, err := c.feeService.ListWaivers(ctx, ctx.Query("fee_type"), ctx.Query("all") != "true")
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get fee waivers", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Fee waivers retrieved successfully", waivers)
+}
+
+// RevokeWaiver ends a fee waiver before it expires
+func (c *FeeController) RevokeWaiver(ctx *gin.Context) {
+	waiverID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid waiver ID", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.feeService.RevokeWaiver(ctx, waiverID, userID); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to revoke fee waiver", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Fee waiver revoked successfully", nil)
+}
+
+// GetInvoices lists a cooperative's fee invoices
+func (c *FeeController) GetInvoices(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	page, limit := paginationQuery(ctx)
+
+	invoices, total, err := c.feeService.ListInvoices(ctx, cooperativeID, page, limit)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get fee invoices", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Fee invoices retrieved successfully", utils.PaginatedResponse{
+		Data:       invoices,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
+
+// GetCreditNotes lists a cooperative's fee credit notes
+func (c *FeeController) GetCreditNotes(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	page, limit := paginationQuery(ctx)
+
+	creditNotes, total, err := c.feeService.ListCreditNotes(ctx, cooperativeID, page, limit)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get fee credit notes", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Fee credit notes retrieved successfully", utils.PaginatedResponse{
+		Data:       creditNotes,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
@@ -225,6 +225,190 @@ func (c *FundManagementController) GetProjectDisbursements(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Project disbursements retrieved successfully", "data": response})
 }
 
+// DefineDisbursementTranches splits a funded project's money over its milestones (admin/cooperative admin)
+func (c *FundManagementController) DefineDisbursementTranches(ctx *gin.Context) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	var req entities.DefineDisbursementTranchesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed"})
+		return
+	}
+
+	definerUUID, ok := fundManagementUserID(ctx)
+	if !ok {
+		return
+	}
+
+	tranches, err := c.fundManagementService.DefineDisbursementTranches(ctx, projectID, &req, definerUUID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to define disbursement tranches"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Disbursement tranches defined successfully", "data": tranches})
+}
+
+// GetDisbursementPlan gets a project's tranches and released, outstanding and unused funds
+func (c *FundManagementController) GetDisbursementPlan(ctx *gin.Context) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	plan, err := c.fundManagementService.GetDisbursementPlan(ctx, projectID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get disbursement plan"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Disbursement plan retrieved successfully", "data": plan})
+}
+
+// SubmitMilestoneEvidence marks a milestone completed with its evidence (project owner)
+func (c *FundManagementController) SubmitMilestoneEvidence(ctx *gin.Context) {
+	projectID, trancheID, ok := trancheParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req entities.SubmitMilestoneEvidenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed"})
+		return
+	}
+
+	submitterUUID, ok := fundManagementUserID(ctx)
+	if !ok {
+		return
+	}
+
+	tranche, err := c.fundManagementService.SubmitMilestoneEvidence(ctx, projectID, trancheID, &req, submitterUUID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to submit milestone evidence"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Milestone evidence submitted successfully", "data": tranche})
+}
+
+// VerifyMilestone approves or rejects a completed milestone's evidence (admin/cooperative admin)
+func (c *FundManagementController) VerifyMilestone(ctx *gin.Context) {
+	projectID, trancheID, ok := trancheParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req entities.VerifyMilestoneRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed"})
+		return
+	}
+
+	verifierUUID, ok := fundManagementUserID(ctx)
+	if !ok {
+		return
+	}
+
+	tranche, err := c.fundManagementService.VerifyMilestone(ctx, projectID, trancheID, &req, verifierUUID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to verify milestone"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Milestone reviewed successfully", "data": tranche})
+}
+
+// CloseDisbursementTranche stops releases from a tranche, keeping the rest for refund (admin/cooperative admin)
+func (c *FundManagementController) CloseDisbursementTranche(ctx *gin.Context) {
+	projectID, trancheID, ok := trancheParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req entities.CloseDisbursementTrancheRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed"})
+		return
+	}
+
+	closerUUID, ok := fundManagementUserID(ctx)
+	if !ok {
+		return
+	}
+
+	tranche, err := c.fundManagementService.CloseDisbursementTranche(ctx, projectID, trancheID, &req, closerUUID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to close disbursement tranche"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Disbursement tranche closed successfully", "data": tranche})
+}
+
+// trancheParams parses the project and tranche IDs from the path
+func trancheParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	trancheID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tranche ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return projectID, trancheID, true
+}
+
+// fundManagementUserID gets the authenticated user's ID from the context
+func fundManagementUserID(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return uuid.Nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
+
 // CreateFundUsage handles FR-047: Track fund usage and business performance
 func (c *FundManagementController) CreateFundUsage(ctx *gin.Context) {
 	var req entities.CreateFundUsageRequest
@@ -358,7 +542,7 @@ func (c *FundManagementController) GetCooperativeFundBalance(ctx *gin.Context) {
 		return
 	}
 
-	balance, err := c.fundManagementService.GetCooperativeFundBalance(ctx, cooperativeID)
+	balances, err := c.fundManagementService.GetCooperativeFundBalance(ctx, cooperativeID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get cooperative fund balance"})
 		return
@@ -366,8 +550,7 @@ func (c *FundManagementController) GetCooperativeFundBalance(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Cooperative fund balance retrieved successfully", "data": map[string]interface{}{
 		"cooperative_id": cooperativeID,
-		"balance":        balance,
-		"currency":       "IDR",
+		"balances":       balances,
 	}})
 }
 
@@ -380,7 +563,7 @@ func (c *FundManagementController) GetProjectFundBalance(ctx *gin.Context) {
 		return
 	}
 
-	balance, err := c.fundManagementService.GetProjectFundBalance(ctx, projectID)
+	balances, err := c.fundManagementService.GetProjectFundBalance(ctx, projectID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get project fund balance"})
 		return
@@ -388,8 +571,7 @@ func (c *FundManagementController) GetProjectFundBalance(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Project fund balance retrieved successfully", "data": map[string]interface{}{
 		"project_id": projectID,
-		"balance":    balance,
-		"currency":   "IDR",
+		"balances":   balances,
 	}})
 }
 
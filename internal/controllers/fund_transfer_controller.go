@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FundTransferController handles fund transfer administration API endpoints
+type FundTransferController struct {
+	fundMonitoringService services.FundMonitoringService
+}
+
+// NewFundTransferController creates a new fund transfer controller
+func NewFundTransferController(fundMonitoringService services.FundMonitoringService) *FundTransferController {
+	return &FundTransferController{
+		fundMonitoringService: fundMonitoringService,
+	}
+}
+
+// ListDeadLettered lists transfers that failed permanently or exhausted their retries
+func (c *FundTransferController) ListDeadLettered(ctx *gin.Context) {
+	page, limit := paginationQuery(ctx)
+
+	transfers, total, err := c.fundMonitoringService.ListDeadLetteredTransfers(ctx, page, limit)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get dead-lettered transfers", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Dead-lettered transfers retrieved successfully", utils.PaginatedResponse{
+		Data:       transfers,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
+
+// GetTransfer returns a fund transfer
+func (c *FundTransferController) GetTransfer(ctx *gin.Context) {
+	transferID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid transfer ID", err)
+		return
+	}
+
+	transfer, err := c.fundMonitoringService.GetFundTransfer(ctx, transferID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Failed to get fund transfer", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Fund transfer retrieved successfully", transfer)
+}
+
+// ListAttempts lists every attempt made to execute a fund transfer
+func (c *FundTransferController) ListAttempts(ctx *gin.Context) {
+	transferID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid transfer ID", err)
+		return
+	}
+
+	attempts, err := c.fundMonitoringService.ListTransferAttempts(ctx, transferID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Failed to get transfer attempts", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Transfer attempts retrieved successfully", attempts)
+}
+
+// RequeueTransfer returns a dead-lettered transfer to the retry queue
+func (c *FundTransferController) RequeueTransfer(ctx *gin.Context) {
+	transferID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid transfer ID", err)
+		return
+	}
+
+	var req entities.RequeueFundTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	transfer, err := c.fundMonitoringService.RequeueDeadLetteredTransfer(ctx, transferID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to requeue fund transfer", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Fund transfer requeued successfully", transfer)
+}
+
+// CancelTransfer cancels a transfer that has not been executed
+func (c *FundTransferController) CancelTransfer(ctx *gin.Context) {
+	transferID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid transfer ID", err)
+		return
+	}
+
+	var req entities.CancelFundTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.fundMonitoringService.CancelFundTransfer(ctx, transferID, userID, req.Reason); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to cancel fund transfer", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Fund transfer cancelled successfully", nil)
+}
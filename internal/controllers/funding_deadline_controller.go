@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FundingDeadlineController handles closing projects' funding at their deadline
+type FundingDeadlineController struct {
+	deadlineService services.FundingDeadlineService
+}
+
+// NewFundingDeadlineController creates a new funding deadline controller
+func NewFundingDeadlineController(deadlineService services.FundingDeadlineService) *FundingDeadlineController {
+	return &FundingDeadlineController{
+		deadlineService: deadlineService,
+	}
+}
+
+// CloseProjectFunding closes a project's funding once its deadline has passed (admin)
+func (c *FundingDeadlineController) CloseProjectFunding(ctx *gin.Context) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid project ID", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	closure, err := c.deadlineService.CloseProjectFunding(ctx, projectID, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to close project funding", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Project funding closed successfully", closure)
+}
+
+// GetProjectClosure gets how a project's funding was closed (admin)
+func (c *FundingDeadlineController) GetProjectClosure(ctx *gin.Context) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid project ID", err)
+		return
+	}
+
+	closure, err := c.deadlineService.GetProjectClosure(ctx, projectID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Funding closure not found", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Funding closure retrieved successfully", closure)
+}
+
+// ProcessDueProjects closes every project past its funding deadline now rather than on the next scheduled run (admin)
+func (c *FundingDeadlineController) ProcessDueProjects(ctx *gin.Context) {
+	run, err := c.deadlineService.ProcessDueProjects(ctx, time.Now())
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to process funding deadlines", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Funding deadlines processed", run)
+}
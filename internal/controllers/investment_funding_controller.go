@@ -16,12 +16,14 @@ import (
 // InvestmentFundingController handles investment and funding API endpoints
 type InvestmentFundingController struct {
 	investmentFundingService services.InvestmentFundingService
+	paymentService           services.PaymentService
 }
 
 // NewInvestmentFundingController creates a new investment funding controller
-func NewInvestmentFundingController(investmentFundingService services.InvestmentFundingService) *InvestmentFundingController {
+func NewInvestmentFundingController(investmentFundingService services.InvestmentFundingService, paymentService services.PaymentService) *InvestmentFundingController {
 	return &InvestmentFundingController{
 		investmentFundingService: investmentFundingService,
+		paymentService:           paymentService,
 	}
 }
 
@@ -59,7 +61,19 @@ func (c *InvestmentFundingController) CreateInvestment(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{"message": "Investment created successfully", "data": investment})
+	if req.PaymentMethod == "" {
+		ctx.JSON(http.StatusCreated, gin.H{"message": "Investment created successfully", "data": investment})
+		return
+	}
+
+	// Issue payment instructions, e.g. a virtual account, for the new investment
+	payment, err := c.paymentService.CreatePaymentRequest(ctx, investment, req.PaymentMethod, req.PaymentProvider)
+	if err != nil {
+		ctx.JSON(http.StatusCreated, gin.H{"message": "Investment created but payment request failed", "data": investment, "payment_error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Investment created successfully", "data": investment, "payment": payment})
 }
 
 // ValidateInvestmentEligibility handles FR-042: Validate investor eligibility and funds availability
@@ -72,7 +86,7 @@ func (c *InvestmentFundingController) ValidateInvestmentEligibility(ctx *gin.Con
 	}
 
 	amountStr := ctx.Query("amount")
-	amount, err := strconv.ParseFloat(amountStr, 64)
+	amount, err := entities.ParseMoney(amountStr, ctx.DefaultQuery("currency", "IDR"))
 	if err != nil {
 		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid amount", err)
 		return
@@ -140,17 +154,23 @@ func (c *InvestmentFundingController) GetProjectFundingProgress(ctx *gin.Context
 		return
 	}
 
-	currentFunding, fundingGoal, investorCount, err := c.investmentFundingService.GetProjectFundingProgress(ctx, projectID)
+	funding, err := c.investmentFundingService.GetProjectFundingProgress(ctx, projectID)
 	if err != nil {
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get funding progress", err)
 		return
 	}
 
+	fundingRatio, _ := funding.Committed.Ratio(funding.FundingGoal).Float64()
+	reservedRatio, _ := funding.Reserved.Ratio(funding.FundingGoal).Float64()
 	progress := map[string]interface{}{
-		"current_funding":  currentFunding,
-		"funding_goal":     fundingGoal,
-		"funding_progress": (currentFunding / fundingGoal) * 100,
-		"investor_count":   investorCount,
+		"committed_funding": funding.Committed,
+		"reserved_funding":  funding.Reserved,
+		"available_funding": funding.Available,
+		"funding_goal":      funding.FundingGoal,
+		"funding_progress":  fundingRatio * 100,
+		"reserved_progress": reservedRatio * 100,
+		"investor_count":    funding.InvestorCount,
+		"reservation_count": funding.ReservationCount,
 	}
 
 	utils.SuccessResponse(ctx, http.StatusOK, "Funding progress retrieved successfully", progress)
@@ -464,8 +484,8 @@ func (c *InvestmentFundingController) SetProjectInvestmentLimits(ctx *gin.Contex
 	}
 
 	var req struct {
-		MinAmount float64 `json:"min_amount" validate:"required,min=0"`
-		MaxAmount float64 `json:"max_amount" validate:"required,min=0"`
+		MinAmount entities.Money `json:"min_amount" validate:"required,min=0"`
+		MaxAmount entities.Money `json:"max_amount" validate:"required,min=0"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
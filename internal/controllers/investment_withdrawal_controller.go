@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// InvestmentWithdrawalController handles early withdrawal API endpoints
+type InvestmentWithdrawalController struct {
+	withdrawalService services.InvestmentWithdrawalService
+}
+
+// NewInvestmentWithdrawalController creates a new investment withdrawal controller
+func NewInvestmentWithdrawalController(withdrawalService services.InvestmentWithdrawalService) *InvestmentWithdrawalController {
+	return &InvestmentWithdrawalController{
+		withdrawalService: withdrawalService,
+	}
+}
+
+// RequestWithdrawal asks to withdraw some or all of the current investor's investment
+func (c *InvestmentWithdrawalController) RequestWithdrawal(ctx *gin.Context) {
+	investmentID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid investment ID", err)
+		return
+	}
+
+	var req entities.CreateInvestmentWithdrawalRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	withdrawal, err := c.withdrawalService.RequestWithdrawal(ctx, investmentID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to request withdrawal", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Withdrawal requested successfully", withdrawal)
+}
+
+// CancelWithdrawal withdraws the current investor's open withdrawal request
+func (c *InvestmentWithdrawalController) CancelWithdrawal(ctx *gin.Context) {
+	projectID, withdrawalID, ok := withdrawalParams(ctx)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	withdrawal, err := c.withdrawalService.CancelWithdrawal(ctx, projectID, withdrawalID, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to cancel withdrawal", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Withdrawal cancelled successfully", withdrawal)
+}
+
+// GetMyWithdrawals lists the current investor's withdrawals
+func (c *InvestmentWithdrawalController) GetMyWithdrawals(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	withdrawals, err := c.withdrawalService.GetInvestorWithdrawals(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get withdrawals", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Withdrawals retrieved successfully", withdrawals)
+}
+
+// GetCooperativeWithdrawals lists a cooperative's withdrawals, optionally by status (admin)
+func (c *InvestmentWithdrawalController) GetCooperativeWithdrawals(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	withdrawals, err := c.withdrawalService.GetCooperativeWithdrawals(ctx, cooperativeID, ctx.Query("status"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get withdrawals", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Withdrawals retrieved successfully", withdrawals)
+}
+
+// GetWithdrawal gets a withdrawal's details (admin)
+func (c *InvestmentWithdrawalController) GetWithdrawal(ctx *gin.Context) {
+	projectID, withdrawalID, ok := withdrawalParams(ctx)
+	if !ok {
+		return
+	}
+
+	withdrawal, err := c.withdrawalService.GetWithdrawal(ctx, projectID, withdrawalID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Withdrawal not found", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Withdrawal retrieved successfully", withdrawal)
+}
+
+// ReviewWithdrawal approves or rejects a withdrawal for the cooperative (admin)
+func (c *InvestmentWithdrawalController) ReviewWithdrawal(ctx *gin.Context) {
+	projectID, withdrawalID, ok := withdrawalParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req entities.ReviewInvestmentWithdrawalRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	withdrawal, err := c.withdrawalService.ReviewWithdrawal(ctx, projectID, withdrawalID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to review withdrawal", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Withdrawal reviewed successfully", withdrawal)
+}
+
+// CompleteWithdrawal refunds an approved withdrawal once its notice period has passed (admin)
+func (c *InvestmentWithdrawalController) CompleteWithdrawal(ctx *gin.Context) {
+	projectID, withdrawalID, ok := withdrawalParams(ctx)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	withdrawal, err := c.withdrawalService.CompleteWithdrawal(ctx, projectID, withdrawalID, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to complete withdrawal", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Withdrawal completed successfully", withdrawal)
+}
+
+// withdrawalParams parses the project and withdrawal IDs from the path
+func withdrawalParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid project ID", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	withdrawalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid withdrawal ID", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return projectID, withdrawalID, true
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// InvestorStatementController handles periodic investor statement API endpoints
+type InvestorStatementController struct {
+	statementService services.InvestorStatementService
+}
+
+// NewInvestorStatementController creates a new investor statement controller
+func NewInvestorStatementController(statementService services.InvestorStatementService) *InvestorStatementController {
+	return &InvestorStatementController{
+		statementService: statementService,
+	}
+}
+
+// GetMyStatements lists the current investor's archived statements
+func (c *InvestorStatementController) GetMyStatements(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	statements, err := c.statementService.ListStatements(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get investor statements", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Investor statements retrieved successfully", statements)
+}
+
+// DownloadStatement downloads one of the current investor's statements as PDF
+// or CSV
+func (c *InvestorStatementController) DownloadStatement(ctx *gin.Context) {
+	statementID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid statement ID", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	format := ctx.DefaultQuery("format", entities.StatementFormatPDF)
+
+	file, err := c.statementService.GetStatementFile(ctx, statementID, userID, format)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Failed to download investor statement", err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+	ctx.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+// GenerateStatements generates, or backfills, the statements of a completed period
+func (c *InvestorStatementController) GenerateStatements(ctx *gin.Context) {
+	var req entities.GenerateInvestorStatementsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	run, err := c.statementService.GenerateStatements(ctx, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to generate investor statements", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Investor statements generated successfully", run)
+}
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LedgerController handles double-entry ledger API endpoints
+type LedgerController struct {
+	ledgerService services.LedgerService
+}
+
+// NewLedgerController creates a new ledger controller
+func NewLedgerController(ledgerService services.LedgerService) *LedgerController {
+	return &LedgerController{
+		ledgerService: ledgerService,
+	}
+}
+
+// GetCooperativeAccounts lists the ledger accounts of a cooperative
+func (c *LedgerController) GetCooperativeAccounts(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	accounts, err := c.ledgerService.ListAccounts(ctx, cooperativeID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get ledger accounts", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Ledger accounts retrieved successfully", accounts)
+}
+
+// GetAccountBalance gets a ledger account balance, optionally as of a date
+func (c *LedgerController) GetAccountBalance(ctx *gin.Context) {
+	accountID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	var asOf *time.Time
+	if asOfStr := ctx.Query("as_of"); asOfStr != "" {
+		parsed, err := time.Parse("2006-01-02", asOfStr)
+		if err != nil {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid as_of date format", err)
+			return
+		}
+		// Include everything posted on the requested day
+		endOfDay := parsed.Add(24*time.Hour - time.Nanosecond)
+		asOf = &endOfDay
+	}
+
+	balance, err := c.ledgerService.GetAccountBalance(ctx, accountID, asOf)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Failed to get account balance", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Account balance retrieved successfully", balance)
+}
+
+// GetAccountEntries gets the journal entries posted to a ledger account
+func (c *LedgerController) GetAccountEntries(ctx *gin.Context) {
+	accountID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	page := utils.GetIntQuery(ctx, "page", 1)
+	limit := utils.GetIntQuery(ctx, "limit", 10)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	entries, total, err := c.ledgerService.GetAccountEntries(ctx, accountID, page, limit)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get journal entries", err)
+		return
+	}
+
+	response := utils.PaginatedResponse{
+		Data:       entries,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Journal entries retrieved successfully", response)
+}
+
+// GetJournalEntry gets a journal entry with its lines
+func (c *LedgerController) GetJournalEntry(ctx *gin.Context) {
+	entryID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid journal entry ID", err)
+		return
+	}
+
+	entry, err := c.ledgerService.GetJournalEntry(ctx, entryID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Journal entry not found", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Journal entry retrieved successfully", entry)
+}
+
+// PostJournalEntry posts a manual adjusting journal entry
+func (c *LedgerController) PostJournalEntry(ctx *gin.Context) {
+	var req entities.PostJournalEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(ctx, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	posterID, ok := userID.(uuid.UUID)
+	if !ok {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Invalid user ID format", nil)
+		return
+	}
+
+	entry, err := c.ledgerService.PostJournalEntry(ctx, &req, posterID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to post journal entry", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Journal entry posted successfully", entry)
+}
+
+// GetTrialBalance gets the trial balance of a cooperative ledger
+func (c *LedgerController) GetTrialBalance(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	trialBalance, err := c.ledgerService.GetTrialBalance(ctx, cooperativeID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get trial balance", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Trial balance retrieved successfully", trialBalance)
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"net/http"
+
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NotificationController handles the current user's notifications
+type NotificationController struct {
+	notificationService services.NotificationService
+}
+
+// NewNotificationController creates a new notification controller
+func NewNotificationController(notificationService services.NotificationService) *NotificationController {
+	return &NotificationController{
+		notificationService: notificationService,
+	}
+}
+
+// GetMyNotifications lists the current user's latest notifications (?unread=true for unread only)
+func (c *NotificationController) GetMyNotifications(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	notifications, err := c.notificationService.GetUserNotifications(ctx, userID, ctx.Query("unread") == "true")
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get notifications", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Notifications retrieved successfully", notifications)
+}
+
+// MarkAsRead marks one of the current user's notifications read
+func (c *NotificationController) MarkAsRead(ctx *gin.Context) {
+	notificationID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid notification ID", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.notificationService.MarkAsRead(ctx, userID, notificationID); err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Failed to mark notification read", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Notification marked as read", nil)
+}
@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PaymentController handles payment request and payment provider callback API endpoints
+type PaymentController struct {
+	paymentService services.PaymentService
+	simulator      *services.SimulatorPaymentProvider
+}
+
+// NewPaymentController creates a new payment controller. The simulator endpoints
+// are only served when a simulator is given.
+func NewPaymentController(paymentService services.PaymentService, simulator *services.SimulatorPaymentProvider) *PaymentController {
+	return &PaymentController{
+		paymentService: paymentService,
+		simulator:      simulator,
+	}
+}
+
+// GetPaymentRequest gets one of the investor's payment requests
+func (c *PaymentController) GetPaymentRequest(ctx *gin.Context) {
+	payment, ok := c.ownPaymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Payment request retrieved successfully", payment)
+}
+
+// RefreshPaymentStatus queries the provider for the latest payment status
+func (c *PaymentController) RefreshPaymentStatus(ctx *gin.Context) {
+	payment, ok := c.ownPaymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	payment, err := c.paymentService.RefreshPaymentStatus(ctx, payment.ID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadGateway, "Failed to refresh payment status", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Payment status refreshed successfully", payment)
+}
+
+// HandleCallback receives asynchronous payment notifications. Callbacks are
+// authenticated by the provider's signature rather than a user token.
+func (c *PaymentController) HandleCallback(ctx *gin.Context) {
+	payload, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to read callback", err)
+		return
+	}
+
+	payment, err := c.paymentService.HandleCallback(ctx, ctx.Param("provider"), payload, ctx.Request.Header)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to process callback", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Callback processed successfully", gin.H{
+		"payment_id": payment.ID,
+		"status":     payment.Status,
+	})
+}
+
+// SimulatePayment pays a simulator payment request and delivers the resulting callback
+func (c *PaymentController) SimulatePayment(ctx *gin.Context) {
+	var req entities.SimulatePaymentRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+
+	payment, ok := c.simulatorPaymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	var amount *entities.Money
+	if req.Amount != nil {
+		paid, err := req.Amount.InCurrency(payment.Currency)
+		if err != nil {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid payment amount", err)
+			return
+		}
+		amount = &paid
+	}
+
+	payload, headers, err := c.simulator.Pay(ctx, payment.ProviderReference, amount)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Simulated payment refused", err)
+		return
+	}
+
+	c.deliverSimulatorCallback(ctx, payload, headers, "Simulated payment received")
+}
+
+// SimulateExpiry lets a simulator payment request lapse and delivers the resulting callback
+func (c *PaymentController) SimulateExpiry(ctx *gin.Context) {
+	payment, ok := c.simulatorPaymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	payload, headers, err := c.simulator.Expire(ctx, payment.ProviderReference)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Simulated expiry refused", err)
+		return
+	}
+
+	c.deliverSimulatorCallback(ctx, payload, headers, "Simulated payment expired")
+}
+
+func (c *PaymentController) deliverSimulatorCallback(ctx *gin.Context, payload []byte, headers http.Header, message string) {
+	payment, err := c.paymentService.HandleCallback(ctx, c.simulator.Name(), payload, headers)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to process simulated callback", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, message, payment)
+}
+
+// simulatorPaymentRequest loads the investor's payment request and checks that the simulator issued it
+func (c *PaymentController) simulatorPaymentRequest(ctx *gin.Context) (*entities.PaymentRequest, bool) {
+	if c.simulator == nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Payment simulator is not enabled", nil)
+		return nil, false
+	}
+
+	payment, ok := c.ownPaymentRequest(ctx)
+	if !ok {
+		return nil, false
+	}
+	if payment.Provider != c.simulator.Name() {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Payment request was not issued by the simulator", nil)
+		return nil, false
+	}
+
+	return payment, true
+}
+
+// ownPaymentRequest loads the payment request in the path, writing an error
+// response unless it belongs to the authenticated investor
+func (c *PaymentController) ownPaymentRequest(ctx *gin.Context) (*entities.PaymentRequest, bool) {
+	paymentID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid payment request ID", err)
+		return nil, false
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	payment, err := c.paymentService.GetPaymentRequest(ctx, paymentID)
+	if err != nil || payment.InvestorID != userID {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Payment request not found", err)
+		return nil, false
+	}
+
+	return payment, true
+}
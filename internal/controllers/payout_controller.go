@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PayoutController handles payout batching and bank file API endpoints
+type PayoutController struct {
+	payoutService services.PayoutService
+}
+
+// NewPayoutController creates a new payout controller
+func NewPayoutController(payoutService services.PayoutService) *PayoutController {
+	return &PayoutController{
+		payoutService: payoutService,
+	}
+}
+
+// GetPayoutQueue summarises a cooperative's queued payouts per escrow account
+func (c *PayoutController) GetPayoutQueue(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	queue, err := c.payoutService.GetPayoutQueue(ctx, cooperativeID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get payout queue", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Payout queue retrieved successfully", queue)
+}
+
+// UpdatePayoutBeneficiary supplies or corrects a payout's beneficiary account
+func (c *PayoutController) UpdatePayoutBeneficiary(ctx *gin.Context) {
+	itemID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid payout ID", err)
+		return
+	}
+
+	var req entities.UpdatePayoutBeneficiaryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	item, err := c.payoutService.UpdatePayoutBeneficiary(ctx, itemID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to update payout beneficiary", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Payout beneficiary updated successfully", item)
+}
+
+// CreatePayoutBatch batches the queued payouts of an escrow account
+func (c *PayoutController) CreatePayoutBatch(ctx *gin.Context) {
+	var req entities.CreatePayoutBatchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	batch, err := c.payoutService.CreatePayoutBatch(ctx, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to create payout batch", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Payout batch created successfully", batch)
+}
+
+// GetPayoutBatches lists a cooperative's payout batches
+func (c *PayoutController) GetPayoutBatches(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	page, limit := paginationQuery(ctx)
+
+	batches, total, err := c.payoutService.GetPayoutBatches(ctx, cooperativeID, page, limit)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get payout batches", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Payout batches retrieved successfully", utils.PaginatedResponse{
+		Data:       batches,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
+
+// GetPayoutBatch gets a payout batch with its payouts
+func (c *PayoutController) GetPayoutBatch(ctx *gin.Context) {
+	batchID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid payout batch ID", err)
+		return
+	}
+
+	batch, err := c.payoutService.GetPayoutBatch(ctx, batchID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Payout batch not found", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Payout batch retrieved successfully", batch)
+}
+
+// ExportPayoutBatch downloads the bank file of a batch as pain.001 XML or CSV
+func (c *PayoutController) ExportPayoutBatch(ctx *gin.Context) {
+	batchID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid payout batch ID", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	format := ctx.DefaultQuery("format", entities.PayoutFileFormatPain001)
+
+	file, err := c.payoutService.ExportPayoutBatch(ctx, batchID, format, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to export payout batch", err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+	ctx.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+// ImportPayoutReturn uploads the bank's pain.002 or CSV return file for a batch
+func (c *PayoutController) ImportPayoutReturn(ctx *gin.Context) {
+	batchID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid payout batch ID", err)
+		return
+	}
+
+	var req entities.ImportPayoutReturnRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Return file is required", err)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to read return file", err)
+		return
+	}
+	defer file.Close()
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := c.payoutService.ImportPayoutReturn(ctx, batchID, &req, file, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to import return file", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Return file imported successfully", result)
+}
@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PortfolioPerformanceController handles investment performance API endpoints
+type PortfolioPerformanceController struct {
+	performanceService services.PortfolioPerformanceService
+}
+
+// NewPortfolioPerformanceController creates a new portfolio performance controller
+func NewPortfolioPerformanceController(performanceService services.PortfolioPerformanceService) *PortfolioPerformanceController {
+	return &PortfolioPerformanceController{
+		performanceService: performanceService,
+	}
+}
+
+// GetMyPerformance measures the current investor's portfolio
+func (c *PortfolioPerformanceController) GetMyPerformance(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	start, end, ok := performancePeriod(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := c.performanceService.GetInvestorPerformance(ctx, userID, start, end)
+	c.respond(ctx, report, err)
+}
+
+// GetInvestorPerformance measures an investor's portfolio
+func (c *PortfolioPerformanceController) GetInvestorPerformance(ctx *gin.Context) {
+	investorID, err := uuid.Parse(ctx.Param("investor_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid investor ID", err)
+		return
+	}
+
+	start, end, ok := performancePeriod(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := c.performanceService.GetInvestorPerformance(ctx, investorID, start, end)
+	c.respond(ctx, report, err)
+}
+
+// GetProjectPerformance measures the investments in a project
+func (c *PortfolioPerformanceController) GetProjectPerformance(ctx *gin.Context) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid project ID", err)
+		return
+	}
+
+	start, end, ok := performancePeriod(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := c.performanceService.GetProjectPerformance(ctx, projectID, start, end)
+	c.respond(ctx, report, err)
+}
+
+// GetCooperativePerformance measures the investments in a cooperative's projects
+func (c *PortfolioPerformanceController) GetCooperativePerformance(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	start, end, ok := performancePeriod(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := c.performanceService.GetCooperativePerformance(ctx, cooperativeID, start, end)
+	c.respond(ctx, report, err)
+}
+
+func (c *PortfolioPerformanceController) respond(ctx *gin.Context, report *entities.PerformanceReport, err error) {
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get portfolio performance", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Portfolio performance retrieved successfully", report)
+}
+
+// performancePeriod reads the optional start_date and end_date query
+// parameters; the end date is inclusive
+func performancePeriod(ctx *gin.Context) (*time.Time, *time.Time, bool) {
+	var start, end *time.Time
+
+	if startDateStr := ctx.Query("start_date"); startDateStr != "" {
+		startDate, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid start date format", err)
+			return nil, nil, false
+		}
+		start = &startDate
+	}
+
+	if endDateStr := ctx.Query("end_date"); endDateStr != "" {
+		endDate, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid end date format", err)
+			return nil, nil, false
+		}
+		endDate = endDate.AddDate(0, 0, 1)
+		end = &endDate
+	}
+
+	if start != nil && end != nil && !start.Before(*end) {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid date range", errors.New("start date must not be after end date"))
+		return nil, nil, false
+	}
+
+	return start, end, true
+}
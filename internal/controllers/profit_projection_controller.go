@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ProfitProjectionController handles profit projection API endpoints
+type ProfitProjectionController struct {
+	projectionService services.ProfitProjectionService
+}
+
+// NewProfitProjectionController creates a new profit projection controller
+func NewProfitProjectionController(projectionService services.ProfitProjectionService) *ProfitProjectionController {
+	return &ProfitProjectionController{
+		projectionService: projectionService,
+	}
+}
+
+// SimulateProjection projects a project's investor returns under revenue and
+// expense assumptions
+func (c *ProfitProjectionController) SimulateProjection(ctx *gin.Context) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid project ID", err)
+		return
+	}
+
+	var req entities.ProfitProjectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	simulation, err := c.projectionService.SimulateProjection(ctx, projectID, &req)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to project profit sharing", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Profit projection simulated successfully", simulation)
+}
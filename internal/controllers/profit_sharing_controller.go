@@ -16,12 +16,14 @@ import (
 // ProfitSharingController handles profit sharing API endpoints
 type ProfitSharingController struct {
 	profitSharingService services.ProfitSharingService
+	lossSharingService   services.LossSharingService
 }
 
 // NewProfitSharingController creates a new profit sharing controller
-func NewProfitSharingController(profitSharingService services.ProfitSharingService) *ProfitSharingController {
+func NewProfitSharingController(profitSharingService services.ProfitSharingService, lossSharingService services.LossSharingService) *ProfitSharingController {
 	return &ProfitSharingController{
 		profitSharingService: profitSharingService,
+		lossSharingService:   lossSharingService,
 	}
 }
 
@@ -267,6 +269,47 @@ func (c *ProfitSharingController) GetProjectProfitDistributions(ctx *gin.Context
 	ctx.JSON(http.StatusOK, gin.H{"message": "Project profit distributions retrieved successfully", "data": response})
 }
 
+// GetProjectLossHistory handles FR-052: a project's losses period by period
+func (c *ProfitSharingController) GetProjectLossHistory(ctx *gin.Context) {
+	projectIDStr := ctx.Param("project_id")
+	projectID, err := uuid.Parse(projectIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	history, err := c.lossSharingService.GetProjectLossHistory(ctx, projectID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get project loss history"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Project loss history retrieved successfully", "data": history})
+}
+
+// GetMyLossShares gets the current investor's shares of project losses
+func (c *ProfitSharingController) GetMyLossShares(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	investorID, ok := userID.(uuid.UUID)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+
+	shares, err := c.lossSharingService.GetInvestorLossShares(ctx, investorID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get loss shares"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Loss shares retrieved successfully", "data": shares})
+}
+
 // CreateTaxDocumentation handles FR-057: Tax-compliant documentation
 func (c *ProfitSharingController) CreateTaxDocumentation(ctx *gin.Context) {
 	var req entities.CreateTaxDocumentationRequest
@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ProjectContractController handles project contract and murabahah installment API endpoints
+type ProjectContractController struct {
+	contractService      services.ProjectContractService
+	profitSharingService services.ProfitSharingService
+}
+
+// NewProjectContractController creates a new project contract controller
+func NewProjectContractController(contractService services.ProjectContractService, profitSharingService services.ProfitSharingService) *ProjectContractController {
+	return &ProjectContractController{
+		contractService:      contractService,
+		profitSharingService: profitSharingService,
+	}
+}
+
+// SetContract sets the contract type and terms a project runs under
+func (c *ProjectContractController) SetContract(ctx *gin.Context) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid project ID", err)
+		return
+	}
+
+	var req entities.SetProjectContractRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	contract, err := c.contractService.SetContract(ctx, projectID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to set project contract", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Project contract set successfully", contract)
+}
+
+// GetContract gets a project's contract with its installment schedule
+func (c *ProjectContractController) GetContract(ctx *gin.Context) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid project ID", err)
+		return
+	}
+
+	contract, err := c.contractService.GetContract(ctx, projectID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get project contract", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Project contract retrieved successfully", contract)
+}
+
+// GetInstallments gets a murabahah project's installment schedule
+func (c *ProjectContractController) GetInstallments(ctx *gin.Context) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid project ID", err)
+		return
+	}
+
+	installments, err := c.contractService.GetInstallments(ctx, projectID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get installments", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Installments retrieved successfully", installments)
+}
+
+// RecordInstallmentPayment records the business paying a murabahah installment
+func (c *ProjectContractController) RecordInstallmentPayment(ctx *gin.Context) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid project ID", err)
+		return
+	}
+
+	installmentID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid installment ID", err)
+		return
+	}
+
+	var req entities.RecordInstallmentPaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	installment, err := c.contractService.RecordInstallmentPayment(ctx, projectID, installmentID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to record installment payment", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Installment payment recorded successfully", installment)
+}
+
+// SettleInstallment distributes a paid murabahah installment to the project's investors
+func (c *ProjectContractController) SettleInstallment(ctx *gin.Context) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid project ID", err)
+		return
+	}
+
+	installmentID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid installment ID", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	distribution, err := c.profitSharingService.SettleInstallment(ctx, projectID, installmentID, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to settle installment", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Installment settled successfully", distribution)
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReinvestmentController handles investors' reinvestment preference API endpoints
+type ReinvestmentController struct {
+	reinvestmentService services.ReinvestmentService
+}
+
+// NewReinvestmentController creates a new reinvestment controller
+func NewReinvestmentController(reinvestmentService services.ReinvestmentService) *ReinvestmentController {
+	return &ReinvestmentController{
+		reinvestmentService: reinvestmentService,
+	}
+}
+
+// SetPreference creates or replaces the current investor's reinvestment preference
+func (c *ReinvestmentController) SetPreference(ctx *gin.Context) {
+	var req entities.SetReinvestmentPreferenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	preference, err := c.reinvestmentService.SetPreference(ctx, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to set reinvestment preference", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Reinvestment preference saved successfully", preference)
+}
+
+// GetPreference returns the current investor's reinvestment preference for a cooperative
+func (c *ReinvestmentController) GetPreference(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	preference, err := c.reinvestmentService.GetPreference(ctx, cooperativeID, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get reinvestment preference", err)
+		return
+	}
+	if preference == nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "No reinvestment preference set", nil)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Reinvestment preference retrieved successfully", preference)
+}
+
+// RemovePreference stops reinvesting the current investor's profit shares
+func (c *ReinvestmentController) RemovePreference(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.reinvestmentService.RemovePreference(ctx, cooperativeID, userID); err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Failed to remove reinvestment preference", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Reinvestment preference removed successfully", nil)
+}
+
+// GetReinvestments lists the current investor's reinvestments in a cooperative
+func (c *ReinvestmentController) GetReinvestments(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	reinvestments, err := c.reinvestmentService.GetInvestorReinvestments(ctx, cooperativeID, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get reinvestments", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Reinvestments retrieved successfully", reinvestments)
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SanctionsScreeningController handles sanctions list and screening review API endpoints
+type SanctionsScreeningController struct {
+	screeningService services.SanctionsScreeningService
+}
+
+// NewSanctionsScreeningController creates a new sanctions screening controller
+func NewSanctionsScreeningController(screeningService services.SanctionsScreeningService) *SanctionsScreeningController {
+	return &SanctionsScreeningController{
+		screeningService: screeningService,
+	}
+}
+
+// GetLists returns the configured watchlists as last loaded
+func (c *SanctionsScreeningController) GetLists(ctx *gin.Context) {
+	utils.SuccessResponse(ctx, http.StatusOK, "Sanctions lists retrieved successfully", c.screeningService.GetLists(ctx))
+}
+
+// ReloadLists reloads the watchlist files that changed since they were loaded
+func (c *SanctionsScreeningController) ReloadLists(ctx *gin.Context) {
+	utils.SuccessResponse(ctx, http.StatusOK, "Sanctions lists reloaded", c.screeningService.ReloadLists(ctx))
+}
+
+// ListScreenings lists screenings, optionally by status or subject reference
+func (c *SanctionsScreeningController) ListScreenings(ctx *gin.Context) {
+	page, limit := paginationQuery(ctx)
+	filter := &entities.SanctionsScreeningFilter{
+		Status:    ctx.Query("status"),
+		Reference: ctx.Query("reference"),
+		Page:      page,
+		Limit:     limit,
+	}
+
+	screenings, total, err := c.screeningService.ListScreenings(ctx, filter)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get sanctions screenings", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Sanctions screenings retrieved successfully", utils.PaginatedResponse{
+		Data:       screenings,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
+
+// GetScreening returns a screening with its potential matches
+func (c *SanctionsScreeningController) GetScreening(ctx *gin.Context) {
+	screeningID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid screening ID", err)
+		return
+	}
+
+	screening, err := c.screeningService.GetScreening(ctx, screeningID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Failed to get sanctions screening", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Sanctions screening retrieved successfully", screening)
+}
+
+// ReviewScreening clears or confirms a screening's potential matches
+func (c *SanctionsScreeningController) ReviewScreening(ctx *gin.Context) {
+	screeningID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid screening ID", err)
+		return
+	}
+
+	var req entities.ReviewSanctionsScreeningRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	screening, err := c.screeningService.ReviewScreening(ctx, screeningID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to review sanctions screening", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Sanctions screening reviewed successfully", screening)
+}
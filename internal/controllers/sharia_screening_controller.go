@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ShariaScreeningController handles Sharia screening rule and evaluation API endpoints
+type ShariaScreeningController struct {
+	screeningService services.ShariaScreeningService
+}
+
+// NewShariaScreeningController creates a new Sharia screening controller
+func NewShariaScreeningController(screeningService services.ShariaScreeningService) *ShariaScreeningController {
+	return &ShariaScreeningController{
+		screeningService: screeningService,
+	}
+}
+
+// GetRuleSets lists the rule sets a cooperative is screened under
+func (c *ShariaScreeningController) GetRuleSets(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	ruleSets, err := c.screeningService.GetActiveRuleSets(ctx, cooperativeID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get screening rule sets", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Screening rule sets retrieved successfully", ruleSets)
+}
+
+// CreateRuleSet adds a supervisory board's screening rules for a cooperative
+func (c *ShariaScreeningController) CreateRuleSet(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	var req entities.CreateShariaScreeningRuleSetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	ruleSet, err := c.screeningService.CreateRuleSet(ctx, cooperativeID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to create screening rule set", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Screening rule set created successfully", ruleSet)
+}
+
+// UpdateRuleSet replaces a supervisory board's screening rules
+func (c *ShariaScreeningController) UpdateRuleSet(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	ruleSetID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid rule set ID", err)
+		return
+	}
+
+	var req entities.CreateShariaScreeningRuleSetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	ruleSet, err := c.screeningService.UpdateRuleSet(ctx, cooperativeID, ruleSetID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to update screening rule set", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Screening rule set updated successfully", ruleSet)
+}
+
+// DeactivateRuleSet stops screening under a rule set
+func (c *ShariaScreeningController) DeactivateRuleSet(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	ruleSetID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid rule set ID", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.screeningService.DeactivateRuleSet(ctx, cooperativeID, ruleSetID, userID); err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to deactivate screening rule set", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Screening rule set deactivated successfully", nil)
+}
+
+// Evaluate screens a business and its profit terms under a cooperative's rules
+func (c *ShariaScreeningController) Evaluate(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	var subject entities.ShariaScreeningSubject
+	if err := ctx.ShouldBindJSON(&subject); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	evaluation, err := c.screeningService.Evaluate(ctx, cooperativeID, &subject)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to evaluate screening rules", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Screening evaluated successfully", evaluation)
+}
+
+// ScreenProject screens a project's business activity
+func (c *ShariaScreeningController) ScreenProject(ctx *gin.Context) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid project ID", err)
+		return
+	}
+
+	evaluation, err := c.screeningService.ScreenProject(ctx, projectID, nil)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to screen project", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Project screened successfully", evaluation)
+}
@@ -0,0 +1,289 @@
+package controllers
+
+import (
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StakeMarketController handles the secondary market API endpoints
+type StakeMarketController struct {
+	marketService services.StakeMarketService
+}
+
+// NewStakeMarketController creates a new stake market controller
+func NewStakeMarketController(marketService services.StakeMarketService) *StakeMarketController {
+	return &StakeMarketController{
+		marketService: marketService,
+	}
+}
+
+// CreateListing lists all or part of the current investor's investment for sale
+func (c *StakeMarketController) CreateListing(ctx *gin.Context) {
+	investmentID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid investment ID", err)
+		return
+	}
+
+	var req entities.CreateStakeListingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	listing, err := c.marketService.CreateListing(ctx, investmentID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to create listing", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Listing created successfully", listing)
+}
+
+// CancelListing takes the current investor's open listing off the market
+func (c *StakeMarketController) CancelListing(ctx *gin.Context) {
+	projectID, listingID, ok := stakeMarketParams(ctx, "listing")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	listing, err := c.marketService.CancelListing(ctx, projectID, listingID, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to cancel listing", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Listing cancelled successfully", listing)
+}
+
+// GetListing gets a listing with its bids
+func (c *StakeMarketController) GetListing(ctx *gin.Context) {
+	projectID, listingID, ok := stakeMarketParams(ctx, "listing")
+	if !ok {
+		return
+	}
+
+	listing, err := c.marketService.GetListing(ctx, projectID, listingID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Listing not found", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Listing retrieved successfully", listing)
+}
+
+// GetCooperativeListings lists a cooperative's open listings
+func (c *StakeMarketController) GetCooperativeListings(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	listings, err := c.marketService.GetCooperativeListings(ctx, cooperativeID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get listings", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Listings retrieved successfully", listings)
+}
+
+// PlaceBid bids for a listed stake as the current member
+func (c *StakeMarketController) PlaceBid(ctx *gin.Context) {
+	projectID, listingID, ok := stakeMarketParams(ctx, "listing")
+	if !ok {
+		return
+	}
+
+	var req entities.PlaceStakeBidRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	bid, err := c.marketService.PlaceBid(ctx, projectID, listingID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to place bid", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Bid placed successfully", bid)
+}
+
+// BuyAtAsk agrees to buy a listed stake at its asking price
+func (c *StakeMarketController) BuyAtAsk(ctx *gin.Context) {
+	projectID, listingID, ok := stakeMarketParams(ctx, "listing")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	trade, err := c.marketService.BuyAtAsk(ctx, projectID, listingID, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to buy stake", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Trade submitted for approval", trade)
+}
+
+// WithdrawBid withdraws the current member's open bid
+func (c *StakeMarketController) WithdrawBid(ctx *gin.Context) {
+	projectID, bidID, ok := stakeMarketParams(ctx, "bid")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	bid, err := c.marketService.WithdrawBid(ctx, projectID, bidID, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to withdraw bid", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Bid withdrawn successfully", bid)
+}
+
+// AcceptBid accepts a bid on the current investor's listing
+func (c *StakeMarketController) AcceptBid(ctx *gin.Context) {
+	projectID, bidID, ok := stakeMarketParams(ctx, "bid")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	trade, err := c.marketService.AcceptBid(ctx, projectID, bidID, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to accept bid", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Trade submitted for approval", trade)
+}
+
+// GetCooperativeTrades lists a cooperative's trades, optionally by status (admin)
+func (c *StakeMarketController) GetCooperativeTrades(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	trades, err := c.marketService.GetCooperativeTrades(ctx, cooperativeID, ctx.Query("status"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get trades", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Trades retrieved successfully", trades)
+}
+
+// GetTrade gets a trade's details (admin)
+func (c *StakeMarketController) GetTrade(ctx *gin.Context) {
+	projectID, tradeID, ok := stakeMarketParams(ctx, "trade")
+	if !ok {
+		return
+	}
+
+	trade, err := c.marketService.GetTrade(ctx, projectID, tradeID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Trade not found", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Trade retrieved successfully", trade)
+}
+
+// ReviewTrade approves or rejects a trade for the cooperative (admin)
+func (c *StakeMarketController) ReviewTrade(ctx *gin.Context) {
+	projectID, tradeID, ok := stakeMarketParams(ctx, "trade")
+	if !ok {
+		return
+	}
+
+	var req entities.ReviewStakeTradeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	trade, err := c.marketService.ReviewTrade(ctx, projectID, tradeID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to review trade", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Trade reviewed successfully", trade)
+}
+
+// stakeMarketParams parses the project ID and the named listing, bid or trade
+// ID from the path
+func stakeMarketParams(ctx *gin.Context, name string) (uuid.UUID, uuid.UUID, bool) {
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid project ID", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid "+name+" ID", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return projectID, id, true
+}
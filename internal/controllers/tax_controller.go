@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TaxController handles tax withholding configuration and report API endpoints
+type TaxController struct {
+	taxService services.TaxService
+}
+
+// NewTaxController creates a new tax controller
+func NewTaxController(taxService services.TaxService) *TaxController {
+	return &TaxController{
+		taxService: taxService,
+	}
+}
+
+// GetMyTaxProfile returns the current investor's tax profile
+func (c *TaxController) GetMyTaxProfile(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	profile, err := c.taxService.GetInvestorProfile(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Failed to get tax profile", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Tax profile retrieved successfully", profile)
+}
+
+// SaveMyTaxProfile sets the current investor's type, tax ID and tax residence
+func (c *TaxController) SaveMyTaxProfile(ctx *gin.Context) {
+	var req entities.SaveInvestorTaxProfileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	profile, err := c.taxService.SaveInvestorProfile(ctx, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to save tax profile", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Tax profile saved successfully", profile)
+}
+
+// GetJurisdiction returns a jurisdiction with its withholding rates
+func (c *TaxController) GetJurisdiction(ctx *gin.Context) {
+	profile, err := c.taxService.GetJurisdictionProfile(ctx, ctx.Param("jurisdiction"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Failed to get tax jurisdiction", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Tax jurisdiction retrieved successfully", profile)
+}
+
+// CreateRate configures a withholding rate
+func (c *TaxController) CreateRate(ctx *gin.Context) {
+	var req entities.CreateTaxWithholdingRateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	rate, err := c.taxService.CreateRate(ctx, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to create withholding rate", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Withholding rate created successfully", rate)
+}
+
+// DeactivateRate withdraws a withholding rate
+func (c *TaxController) DeactivateRate(ctx *gin.Context) {
+	rateID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid rate ID", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.taxService.DeactivateRate(ctx, rateID, userID); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to deactivate withholding rate", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Withholding rate deactivated successfully", nil)
+}
+
+// SetCooperativeJurisdiction sets the jurisdiction a cooperative withholds under
+func (c *TaxController) SetCooperativeJurisdiction(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	var req entities.SetCooperativeTaxJurisdictionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.taxService.SetCooperativeJurisdiction(ctx, cooperativeID, &req, userID); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to set tax jurisdiction", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Tax jurisdiction set successfully", req)
+}
+
+// GetWithholdingReport totals the tax a cooperative withheld in the month,
+// quarter or year containing the given date (defaults to the current month)
+func (c *TaxController) GetWithholdingReport(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	taxPeriod := ctx.DefaultQuery("tax_period", entities.TaxPeriodMonthly)
+	if taxPeriod != entities.TaxPeriodMonthly && taxPeriod != entities.TaxPeriodQuarterly && taxPeriod != entities.TaxPeriodAnnual {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid tax period", nil)
+		return
+	}
+
+	at := time.Now()
+	if dateStr := ctx.Query("date"); dateStr != "" {
+		at, err = time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid date format", err)
+			return
+		}
+	}
+
+	report, err := c.taxService.GetWithholdingReport(ctx, cooperativeID, taxPeriod, at, ctx.Query("currency"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get withholding report", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Withholding report retrieved successfully", report)
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/services"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ZakatController handles zakat statement and payment API endpoints
+type ZakatController struct {
+	zakatService services.ZakatService
+}
+
+// NewZakatController creates a new zakat controller
+func NewZakatController(zakatService services.ZakatService) *ZakatController {
+	return &ZakatController{
+		zakatService: zakatService,
+	}
+}
+
+// GetZakatStatement assesses zakat on the current investor's portfolio at the
+// end of the as_of date, today by default
+func (c *ZakatController) GetZakatStatement(ctx *gin.Context) {
+	asOf := time.Now()
+	if asOfStr := ctx.Query("as_of"); asOfStr != "" {
+		date, err := time.Parse("2006-01-02", asOfStr)
+		if err != nil {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid as_of date format", err)
+			return
+		}
+		asOf = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	statement, err := c.zakatService.GetZakatStatement(ctx, userID, asOf)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get zakat statement", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Zakat statement retrieved successfully", statement)
+}
+
+// GetZakatRecipient returns the account a cooperative designates for zakat
+func (c *ZakatController) GetZakatRecipient(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	recipient, err := c.zakatService.GetRecipient(ctx, cooperativeID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Zakat recipient not found", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Zakat recipient retrieved successfully", recipient)
+}
+
+// SetZakatRecipient designates a cooperative's zakat recipient account (admin)
+func (c *ZakatController) SetZakatRecipient(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	var req entities.SetZakatRecipientRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	recipient, err := c.zakatService.SetRecipient(ctx, cooperativeID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to set zakat recipient", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Zakat recipient saved successfully", recipient)
+}
+
+// CreateZakatPayment gives the current investor transfer instructions for paying zakat
+func (c *ZakatController) CreateZakatPayment(ctx *gin.Context) {
+	var req entities.CreateZakatPaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	payment, err := c.zakatService.CreatePayment(ctx, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to create zakat payment", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Zakat payment created successfully", payment)
+}
+
+// GetMyZakatPayments lists the current investor's zakat payments
+func (c *ZakatController) GetMyZakatPayments(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	payments, err := c.zakatService.GetInvestorPayments(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get zakat payments", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Zakat payments retrieved successfully", payments)
+}
+
+// ConfirmZakatPayment records that the recipient received a zakat payment (admin)
+func (c *ZakatController) ConfirmZakatPayment(ctx *gin.Context) {
+	cooperativeID, err := uuid.Parse(ctx.Param("cooperative_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cooperative ID", err)
+		return
+	}
+
+	paymentID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid zakat payment ID", err)
+		return
+	}
+
+	var req entities.ConfirmZakatPaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	payment, err := c.zakatService.ConfirmPayment(ctx, cooperativeID, paymentID, &req, userID)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Failed to confirm zakat payment", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Zakat payment confirmed successfully", payment)
+}
@@ -105,6 +105,20 @@ func (sm *ShardManager) GetAllShards() ([]*sql.DB, error) {
 	return shards, nil
 }
 
+// GetReadShard returns the first available shard, for reading reference data
+// that is replicated to every shard
+func (sm *ShardManager) GetReadShard() (*sql.DB, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	for _, shard := range sm.shards {
+		if shard != nil {
+			return shard, nil
+		}
+	}
+	return nil, fmt.Errorf("no shard available")
+}
+
 // ExecuteOnShard executes a query on a specific shard with retries
 func (sm *ShardManager) ExecuteOnShard(ctx context.Context, shardIndex int, query string, args ...interface{}) (*sql.Rows, error) {
 	sm.mu.RLock()
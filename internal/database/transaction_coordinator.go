@@ -6,6 +6,8 @@ import (
 	"log"
 	"time"
 
+	"comfunds/internal/entities"
+
 	"github.com/google/uuid"
 )
 
@@ -52,7 +54,7 @@ func (tc *TransactionCoordinator) ExecuteDistributedTransaction(ctx context.Cont
 }
 
 // CreateInvestmentTransaction creates an investment with proper ACID guarantees
-func (tc *TransactionCoordinator) CreateInvestmentTransaction(ctx context.Context, projectID, investorID string, amount float64) (string, error) {
+func (tc *TransactionCoordinator) CreateInvestmentTransaction(ctx context.Context, projectID, investorID string, amount entities.Money) (string, error) {
 	var investmentID string
 	
 	err := tc.ExecuteDistributedTransaction(ctx, func(dtx *DistributedTransaction) error {
@@ -90,7 +92,7 @@ func (tc *TransactionCoordinator) CreateInvestmentTransaction(ctx context.Contex
 			return fmt.Errorf("project not found or not accepting investments")
 		}
 
-		var fundingGoal, currentFunding float64
+		var fundingGoal, currentFunding entities.Money
 		var status string
 		var profitSharingRatio string
 		
@@ -100,7 +102,7 @@ func (tc *TransactionCoordinator) CreateInvestmentTransaction(ctx context.Contex
 		}
 
 		// Check if investment would exceed funding goal
-		if currentFunding+amount > fundingGoal {
+		if currentFunding.Add(amount).GreaterThan(fundingGoal) {
 			return fmt.Errorf("investment would exceed funding goal")
 		}
 
@@ -154,7 +156,7 @@ func (tc *TransactionCoordinator) CreateInvestmentTransaction(ctx context.Contex
 			return fmt.Errorf("failed to update project funding: %w", err)
 		}
 
-		log.Printf("Created investment %s: %s invested %f in project %s", investmentID, investorID, amount, projectID)
+		log.Printf("Created investment %s: %s invested %s in project %s", investmentID, investorID, amount.Decimal(), projectID)
 		return nil
 	})
 
@@ -166,7 +168,7 @@ func (tc *TransactionCoordinator) CreateInvestmentTransaction(ctx context.Contex
 }
 
 // DistributeProfits distributes profits to investors with ACID guarantees
-func (tc *TransactionCoordinator) DistributeProfits(ctx context.Context, projectID string, businessProfit float64) error {
+func (tc *TransactionCoordinator) DistributeProfits(ctx context.Context, projectID string, businessProfit entities.Money) error {
 	return tc.ExecuteDistributedTransaction(ctx, func(dtx *DistributedTransaction) error {
 		// Determine project shard
 		_, projectShardIndex, err := tc.shardMgr.GetShardByID(projectID)
@@ -198,7 +200,7 @@ func (tc *TransactionCoordinator) DistributeProfits(ctx context.Context, project
 		}
 
 		// 2. Calculate total profit to distribute (assuming 70% to investors)
-		investorProfitShare := businessProfit * 0.70
+		investorProfitShare := businessProfit.Percent(70, entities.RoundHalfUp)
 
 		// 3. Create profit distribution record
 		distributionID := uuid.New().String()
@@ -226,18 +228,18 @@ func (tc *TransactionCoordinator) DistributeProfits(ctx context.Context, project
 		defer rows.Close()
 
 		// Calculate total investment amount
-		var totalInvestment float64
+		var totalInvestment entities.Money
 		var investments []struct {
 			ID         string
 			InvestorID string
-			Amount     float64
+			Amount     entities.Money
 		}
 
 		for rows.Next() {
 			var inv struct {
 				ID         string
 				InvestorID string
-				Amount     float64
+				Amount     entities.Money
 			}
 			
 			err = rows.Scan(&inv.ID, &inv.InvestorID, &inv.Amount)
@@ -246,17 +248,28 @@ func (tc *TransactionCoordinator) DistributeProfits(ctx context.Context, project
 			}
 			
 			investments = append(investments, inv)
-			totalInvestment += inv.Amount
+			totalInvestment = totalInvestment.Add(inv.Amount)
 		}
 
-		if totalInvestment == 0 {
+		if totalInvestment.IsZero() {
 			return fmt.Errorf("no confirmed investments found for project")
 		}
 
-		// 5. Create return records for each investor
-		for _, inv := range investments {
-			returnAmount := (inv.Amount / totalInvestment) * investorProfitShare
-			returnPercentage := (returnAmount / inv.Amount) * 100
+		// 5. Split the investor share pro rata so the returns add up to it exactly
+		weights := make([]entities.Money, len(investments))
+		for i, inv := range investments {
+			weights[i] = inv.Amount
+		}
+		returnAmounts, err := entities.AllocateByWeight(investorProfitShare, weights)
+		if err != nil {
+			return fmt.Errorf("failed to allocate investor returns: %w", err)
+		}
+
+		// 6. Create return records for each investor
+		for i, inv := range investments {
+			returnAmount := returnAmounts[i]
+			returnPercentage, _ := returnAmount.Ratio(inv.Amount).Float64()
+			returnPercentage *= 100
 
 			returnID := uuid.New().String()
 			txRef := fmt.Sprintf("RTN-%d-%s", time.Now().Unix(), uuid.New().String()[:8])
@@ -272,7 +285,7 @@ func (tc *TransactionCoordinator) DistributeProfits(ctx context.Context, project
 			}
 		}
 
-		log.Printf("Distributed profit for project %s: business profit %f, investor share %f", projectID, businessProfit, investorProfitShare)
+		log.Printf("Distributed profit for project %s: business profit %s, investor share %s", projectID, businessProfit.Decimal(), investorProfitShare.Decimal())
 		return nil
 	})
 }
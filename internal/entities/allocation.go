@@ -0,0 +1,223 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// AllocationHolder is one party an amount is split across. Weight is any
+// non-negative proportion, typically the holder's investment. Minimum and Cap
+// bound the holder's part; a zero Minimum and a nil Cap leave it unbounded.
+type AllocationHolder struct {
+	Weight  *big.Rat
+	Minimum Money
+	Cap     *Money
+}
+
+// AllocationWeight returns an amount as an allocation weight
+func AllocationWeight(amount Money) *big.Rat {
+	return amount.rat()
+}
+
+// AllocateByWeight splits total across amounts in proportion to their size, e.g.
+// a profit across investments; see Allocate
+func AllocateByWeight(total Money, weights []Money) ([]Money, error) {
+	holders := make([]AllocationHolder, len(weights))
+	for i, weight := range weights {
+		holders[i] = AllocationHolder{Weight: AllocationWeight(weight)}
+	}
+	return Allocate(total, holders)
+}
+
+// Allocate splits total across holders in proportion to their weights, rounded
+// to the currency's minor unit with the largest-remainder method: every holder
+// gets the rounded-down share and the minor units left over go one each to the
+// largest remainders, ties going to the earlier holder. The parts are returned in
+// holder order and always sum to total exactly.
+//
+// Holders whose proportional share falls below their minimum or above their cap
+// are fixed at that bound and the rest is re-split across the others. A negative
+// total, such as a loss, is split as its absolute value and the parts negated.
+func Allocate(total Money, holders []AllocationHolder) ([]Money, error) {
+	if len(holders) == 0 {
+		return nil, errors.New("allocation needs at least one holder")
+	}
+
+	currency := total.Currency()
+	if currency == "" {
+		return nil, ErrCurrencyRequired
+	}
+	target := total.Abs().MinorUnits()
+
+	minimums := make([]int64, len(holders))
+	caps := make([]int64, len(holders))
+	var totalMinimum int64
+	capped := true
+	for i, holder := range holders {
+		if holder.Weight == nil || holder.Weight.Sign() < 0 {
+			return nil, fmt.Errorf("allocation holder %d has an invalid weight", i)
+		}
+		if holder.Minimum.IsNegative() {
+			return nil, fmt.Errorf("allocation holder %d has a negative minimum", i)
+		}
+		minimum, err := holder.Minimum.InCurrency(currency)
+		if err != nil {
+			return nil, fmt.Errorf("allocation holder %d: %w", i, err)
+		}
+		minimums[i] = minimum.MinorUnits()
+		totalMinimum += minimums[i]
+
+		caps[i] = -1
+		if holder.Cap != nil {
+			cap, err := holder.Cap.InCurrency(currency)
+			if err != nil {
+				return nil, fmt.Errorf("allocation holder %d: %w", i, err)
+			}
+			caps[i] = cap.MinorUnits()
+			if caps[i] < minimums[i] {
+				return nil, fmt.Errorf("allocation holder %d has a cap below its minimum", i)
+			}
+		} else {
+			capped = false
+		}
+	}
+
+	if totalMinimum > target {
+		return nil, fmt.Errorf("minimums of %s exceed the amount allocated", NewMoney(totalMinimum, currency).Decimal())
+	}
+	if capped {
+		var totalCap int64
+		for _, c := range caps {
+			totalCap += c
+		}
+		if totalCap < target {
+			return nil, fmt.Errorf("caps of %s are below the amount allocated", NewMoney(totalCap, currency).Decimal())
+		}
+	}
+
+	shares := proportionalShares(target, holders, minimums, caps)
+	if shares == nil {
+		return nil, errors.New("allocation has no weight to split the amount by")
+	}
+
+	parts := largestRemainder(target, shares)
+	result := make([]Money, len(parts))
+	for i, units := range parts {
+		if total.IsNegative() {
+			units = -units
+		}
+		result[i] = NewMoney(units, currency)
+	}
+
+	return result, nil
+}
+
+// proportionalShares returns each holder's exact share of target in minor units,
+// clamped to its bounds. Holders are fixed at a bound in rounds: in each round the
+// side (minimums or caps) with the larger total violation is fixed and the
+// remainder re-split, which converges on the bounded proportional split. It
+// returns nil if an amount is left for holders that all have zero weight.
+func proportionalShares(target int64, holders []AllocationHolder, minimums, caps []int64) []*big.Rat {
+	shares := make([]*big.Rat, len(holders))
+	fixed := make([]bool, len(holders))
+	remaining := target
+
+	for {
+		totalWeight := new(big.Rat)
+		for i, holder := range holders {
+			if !fixed[i] {
+				totalWeight.Add(totalWeight, holder.Weight)
+			}
+		}
+
+		for i, holder := range holders {
+			if fixed[i] {
+				continue
+			}
+			if totalWeight.Sign() == 0 {
+				shares[i] = new(big.Rat)
+				continue
+			}
+			share := new(big.Rat).SetInt64(remaining)
+			share.Mul(share, holder.Weight)
+			shares[i] = share.Quo(share, totalWeight)
+		}
+
+		underMinimum, overCap := new(big.Rat), new(big.Rat)
+		for i := range holders {
+			if fixed[i] {
+				continue
+			}
+			if minimum := new(big.Rat).SetInt64(minimums[i]); shares[i].Cmp(minimum) < 0 {
+				underMinimum.Add(underMinimum, minimum.Sub(minimum, shares[i]))
+			}
+			if caps[i] >= 0 {
+				if c := new(big.Rat).SetInt64(caps[i]); shares[i].Cmp(c) > 0 {
+					overCap.Add(overCap, new(big.Rat).Sub(shares[i], c))
+				}
+			}
+		}
+
+		if underMinimum.Sign() == 0 && overCap.Sign() == 0 {
+			break
+		}
+
+		fixMinimums := underMinimum.Cmp(overCap) >= 0
+		for i := range holders {
+			if fixed[i] {
+				continue
+			}
+			bound := int64(-1)
+			if fixMinimums && shares[i].Cmp(new(big.Rat).SetInt64(minimums[i])) < 0 {
+				bound = minimums[i]
+			} else if !fixMinimums && caps[i] >= 0 && shares[i].Cmp(new(big.Rat).SetInt64(caps[i])) > 0 {
+				bound = caps[i]
+			}
+			if bound >= 0 {
+				fixed[i] = true
+				shares[i] = new(big.Rat).SetInt64(bound)
+				remaining -= bound
+			}
+		}
+	}
+
+	var allocated big.Rat
+	for _, share := range shares {
+		allocated.Add(&allocated, share)
+	}
+	if allocated.Cmp(new(big.Rat).SetInt64(target)) != 0 {
+		return nil
+	}
+
+	return shares
+}
+
+// largestRemainder rounds exact shares summing to target down to whole minor
+// units and hands the units left over to the largest fractional remainders
+func largestRemainder(target int64, shares []*big.Rat) []int64 {
+	parts := make([]int64, len(shares))
+	remainders := make([]*big.Rat, len(shares))
+	var allocated int64
+	for i, share := range shares {
+		floor := new(big.Int).Quo(share.Num(), share.Denom())
+		parts[i] = floor.Int64()
+		remainders[i] = new(big.Rat).Sub(share, new(big.Rat).SetInt(floor))
+		allocated += parts[i]
+	}
+
+	order := make([]int, len(shares))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+
+	for _, i := range order[:target-allocated] {
+		parts[i]++
+	}
+
+	return parts
+}
@@ -0,0 +1,130 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func minorUnits(parts []Money) []int64 {
+	units := make([]int64, len(parts))
+	for i, part := range parts {
+		units[i] = part.MinorUnits()
+	}
+	return units
+}
+
+func moneyPtr(amount, currency string) *Money {
+	m := MustParseMoney(amount, currency)
+	return &m
+}
+
+func TestAllocateByWeight_LargestRemainder(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    Money
+		weights  []Money
+		expected []int64
+	}{
+		{"even thirds", NewMoney(100, "MYR"), []Money{NewMoney(1, "MYR"), NewMoney(1, "MYR"), NewMoney(1, "MYR")}, []int64{34, 33, 33}},
+		{"largest remainder wins", NewMoney(100, "MYR"), []Money{NewMoney(1, "MYR"), NewMoney(2, "MYR"), NewMoney(4, "MYR")}, []int64{14, 29, 57}},
+		{"exact split", MustParseMoney("210000", "IDR"), []Money{MustParseMoney("50000", "IDR"), MustParseMoney("150000", "IDR")}, []int64{5250000, 15750000}},
+		{"zero decimal currency", NewMoney(10, "JPY"), []Money{NewMoney(1, "JPY"), NewMoney(1, "JPY"), NewMoney(1, "JPY")}, []int64{4, 3, 3}},
+		{"zero weight gets nothing", NewMoney(7, "MYR"), []Money{NewMoney(0, "MYR"), NewMoney(5, "MYR")}, []int64{0, 7}},
+		{"loss is negated", NewMoney(-100, "MYR"), []Money{NewMoney(1, "MYR"), NewMoney(1, "MYR"), NewMoney(1, "MYR")}, []int64{-34, -33, -33}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := AllocateByWeight(tt.total, tt.weights)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, minorUnits(parts))
+			assert.True(t, SumMoney(parts...).Equal(tt.total))
+			assert.Equal(t, tt.total.Currency(), parts[0].Currency())
+		})
+	}
+}
+
+func TestAllocate_SumsExactly(t *testing.T) {
+	// Weights whose float shares famously drift: 1/3 and 1/7 of odd totals
+	weights := []Money{
+		MustParseMoney("33333.33", "IDR"),
+		MustParseMoney("14285.71", "IDR"),
+		MustParseMoney("0.01", "IDR"),
+		MustParseMoney("52380.95", "IDR"),
+	}
+	for _, total := range []string{"0.01", "0.03", "999.99", "1000000.01", "73.37"} {
+		amount := MustParseMoney(total, "IDR")
+		parts, err := AllocateByWeight(amount, weights)
+		require.NoError(t, err)
+		assert.True(t, SumMoney(parts...).Equal(amount), total)
+	}
+}
+
+func TestAllocate_MinimumsAndCaps(t *testing.T) {
+	one := big.NewRat(1, 1)
+
+	// The small holder is lifted to its minimum and the others share the rest
+	parts, err := Allocate(NewMoney(1000, "MYR"), []AllocationHolder{
+		{Weight: big.NewRat(1, 1), Minimum: NewMoney(100, "MYR")},
+		{Weight: big.NewRat(50, 1)},
+		{Weight: big.NewRat(49, 1)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{100, 455, 445}, minorUnits(parts))
+
+	// A capped holder's excess is re-split across the uncapped ones
+	parts, err = Allocate(NewMoney(1000, "MYR"), []AllocationHolder{
+		{Weight: one, Cap: moneyPtr("2.00", "MYR")},
+		{Weight: one},
+		{Weight: one},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{200, 400, 400}, minorUnits(parts))
+
+	// Capping one holder can push another past its own cap
+	parts, err = Allocate(NewMoney(1000, "MYR"), []AllocationHolder{
+		{Weight: one, Cap: moneyPtr("1.00", "MYR")},
+		{Weight: one, Cap: moneyPtr("4.00", "MYR")},
+		{Weight: one},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{100, 400, 500}, minorUnits(parts))
+
+	// A zero-weight holder with a minimum still receives it
+	parts, err = Allocate(NewMoney(1000, "MYR"), []AllocationHolder{
+		{Weight: new(big.Rat), Minimum: NewMoney(1, "MYR")},
+		{Weight: one},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 999}, minorUnits(parts))
+}
+
+func TestAllocate_Invalid(t *testing.T) {
+	one := big.NewRat(1, 1)
+
+	tests := []struct {
+		name    string
+		total   Money
+		holders []AllocationHolder
+	}{
+		{"no holders", NewMoney(100, "MYR"), nil},
+		{"missing weight", NewMoney(100, "MYR"), []AllocationHolder{{}}},
+		{"negative weight", NewMoney(100, "MYR"), []AllocationHolder{{Weight: big.NewRat(-1, 1)}}},
+		{"no weight", NewMoney(100, "MYR"), []AllocationHolder{{Weight: new(big.Rat)}, {Weight: new(big.Rat)}}},
+		{"minimums exceed total", NewMoney(100, "MYR"), []AllocationHolder{{Weight: one, Minimum: NewMoney(60, "MYR")}, {Weight: one, Minimum: NewMoney(60, "MYR")}}},
+		{"caps below total", NewMoney(100, "MYR"), []AllocationHolder{{Weight: one, Cap: moneyPtr("0.40", "MYR")}, {Weight: one, Cap: moneyPtr("0.40", "MYR")}}},
+		{"cap below minimum", NewMoney(100, "MYR"), []AllocationHolder{{Weight: one, Minimum: NewMoney(50, "MYR"), Cap: moneyPtr("0.40", "MYR")}}},
+		{"cap in another currency", NewMoney(100, "MYR"), []AllocationHolder{{Weight: one, Minimum: NewMoney(50, "MYR"), Cap: moneyPtr("1.00", "USD")}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := Allocate(tt.total, tt.holders)
+			assert.Error(t, err)
+			assert.Nil(t, parts)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AMLRule is a configurable transaction monitoring rule. Rules without a
+// cooperative apply platform-wide; a cooperative's own rules apply on top.
+// Amounts are compared in the rule's currency.
+type AMLRule struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	CooperativeID *uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	Name          string     `json:"name" db:"name"`
+	RuleType      string     `json:"rule_type" db:"rule_type"`
+	// Threshold is the reporting threshold for amount_threshold and
+	// structuring, the total in the window for velocity, and the smallest
+	// amount considered for round_amount and new_bank_account
+	Threshold Money  `json:"threshold" db:"threshold"`
+	Currency  string `json:"currency" db:"currency"`
+	// MinCount is how many transactions in the window trigger velocity,
+	// structuring and round_amount
+	MinCount    int `json:"min_count" db:"min_count"`
+	WindowHours int `json:"window_hours" db:"window_hours"`
+	// MarginPercent is how far below the threshold counts as just under it for
+	// structuring, and the least share of the investment withdrawn for
+	// invest_then_withdraw
+	MarginPercent float64   `json:"margin_percent" db:"margin_percent"`
+	RoundingUnit  Money     `json:"rounding_unit" db:"rounding_unit"` // round_amount: amounts that are a multiple of this
+	Severity      string    `json:"severity" db:"severity"`           // low, medium, high
+	IsActive      bool      `json:"is_active" db:"is_active"`
+	CreatedBy     uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Window is the period before a transaction the rule looks back over
+func (r *AMLRule) Window() time.Duration {
+	return time.Duration(r.WindowHours) * time.Hour
+}
+
+// CreateAMLRuleRequest configures a monitoring rule; which fields are required
+// depends on the rule type
+type CreateAMLRuleRequest struct {
+	CooperativeID *uuid.UUID `json:"cooperative_id"` // platform-wide when unset
+	Name          string     `json:"name" validate:"required,min=3,max=255"`
+	RuleType      string     `json:"rule_type" validate:"required,oneof=amount_threshold velocity structuring round_amount invest_then_withdraw new_bank_account"`
+	Threshold     Money      `json:"threshold"`
+	Currency      string     `json:"currency" validate:"required,len=3"`
+	MinCount      int        `json:"min_count" validate:"min=0,max=1000"`
+	WindowHours   int        `json:"window_hours" validate:"min=0,max=8760"`
+	MarginPercent float64    `json:"margin_percent" validate:"min=0,max=100"`
+	RoundingUnit  Money      `json:"rounding_unit"`
+	Severity      string     `json:"severity" validate:"required,oneof=low medium high"`
+}
+
+// AMLTransaction is a transfer or investment as it was monitored. The subject
+// is the member the money comes from or goes to.
+type AMLTransaction struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	CooperativeID   uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	ProjectID       uuid.UUID `json:"project_id" db:"project_id"`
+	SubjectID       uuid.UUID `json:"subject_id" db:"subject_id"`
+	SourceType      string    `json:"source_type" db:"source_type"` // fund_transfer, investment
+	SourceID        uuid.UUID `json:"source_id" db:"source_id"`
+	Reference       string    `json:"reference" db:"reference"`
+	TransactionType string    `json:"transaction_type" db:"transaction_type"` // investment, profit_distribution, withdrawal, refund, fee
+	Direction       string    `json:"direction" db:"direction"`               // inbound, outbound
+	Amount          Money     `json:"amount" db:"amount"`
+	Currency        string    `json:"currency" db:"currency"`
+	BankAccount     string    `json:"bank_account" db:"bank_account"`
+	OccurredAt      time.Time `json:"occurred_at" db:"occurred_at"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// AMLBankAccountUse is when a subject first transacted through a bank account
+type AMLBankAccountUse struct {
+	BankAccount string    `json:"bank_account"`
+	FirstUsedAt time.Time `json:"first_used_at"`
+}
+
+// AMLAlert is a rule a transaction triggered
+type AMLAlert struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	CaseID        uuid.UUID `json:"case_id" db:"case_id"`
+	TransactionID uuid.UUID `json:"transaction_id" db:"transaction_id"`
+	RuleID        uuid.UUID `json:"rule_id" db:"rule_id"`
+	RuleName      string    `json:"rule_name" db:"rule_name"`
+	RuleType      string    `json:"rule_type" db:"rule_type"`
+	Severity      string    `json:"severity" db:"severity"`
+	Description   string    `json:"description" db:"description"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// AMLCase groups the alerts on a subject for a compliance officer to review.
+// New alerts join the subject's case until it is closed.
+type AMLCase struct {
+	ID            uuid.UUID         `json:"id" db:"id"`
+	CaseNumber    string            `json:"case_number" db:"case_number"`
+	CooperativeID uuid.UUID         `json:"cooperative_id" db:"cooperative_id"`
+	SubjectID     uuid.UUID         `json:"subject_id" db:"subject_id"`
+	Status        string            `json:"status" db:"status"`     // open, investigating, escalated, closed
+	Severity      string            `json:"severity" db:"severity"` // highest of its alerts
+	AssignedTo    *uuid.UUID        `json:"assigned_to" db:"assigned_to"`
+	ClosureReason string            `json:"closure_reason,omitempty" db:"closure_reason"` // false_positive, no_further_action, reported
+	ClosureNotes  string            `json:"closure_notes,omitempty" db:"closure_notes"`
+	OpenedAt      time.Time         `json:"opened_at" db:"opened_at"`
+	EscalatedAt   *time.Time        `json:"escalated_at" db:"escalated_at"`
+	ClosedAt      *time.Time        `json:"closed_at" db:"closed_at"`
+	ClosedBy      *uuid.UUID        `json:"closed_by" db:"closed_by"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at" db:"updated_at"`
+	Alerts        []*AMLAlert       `json:"alerts,omitempty"`
+	Transactions  []*AMLTransaction `json:"transactions,omitempty"`
+	Events        []*AMLCaseEvent   `json:"events,omitempty"`
+}
+
+// AMLCaseEvent records a step in a case's workflow
+type AMLCaseEvent struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	CaseID     uuid.UUID  `json:"case_id" db:"case_id"`
+	Action     string     `json:"action" db:"action"` // opened, alerted, investigation_started, escalated, closed
+	FromStatus string     `json:"from_status,omitempty" db:"from_status"`
+	ToStatus   string     `json:"to_status" db:"to_status"`
+	Notes      string     `json:"notes,omitempty" db:"notes"`
+	ActorID    *uuid.UUID `json:"actor_id" db:"actor_id"` // nil when monitoring raised it
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// AMLCaseFilter for querying a cooperative's cases
+type AMLCaseFilter struct {
+	Status    string     `json:"status"`
+	SubjectID *uuid.UUID `json:"subject_id"`
+	Page      int        `json:"page"`
+	Limit     int        `json:"limit"`
+}
+
+// AMLCaseActionRequest moves a case on in its workflow
+type AMLCaseActionRequest struct {
+	Notes string `json:"notes" validate:"max=2000"`
+}
+
+// CloseAMLCaseRequest closes a case with the officer's conclusion
+type CloseAMLCaseRequest struct {
+	Reason string `json:"reason" validate:"required,oneof=false_positive no_further_action reported"`
+	Notes  string `json:"notes" validate:"required,max=2000"`
+}
+
+// SuspiciousTransactionReportFile is a case's transactions and alerts, as
+// filed with the financial intelligence unit
+type SuspiciousTransactionReportFile struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"-"`
+}
+
+// AML monitoring constants
+const (
+	AMLRuleAmountThreshold    = "amount_threshold"
+	AMLRuleVelocity           = "velocity"
+	AMLRuleStructuring        = "structuring"
+	AMLRuleRoundAmount        = "round_amount"
+	AMLRuleInvestThenWithdraw = "invest_then_withdraw"
+	AMLRuleNewBankAccount     = "new_bank_account"
+
+	AMLSeverityLow    = "low"
+	AMLSeverityMedium = "medium"
+	AMLSeverityHigh   = "high"
+
+	AMLSourceFundTransfer = "fund_transfer"
+	AMLSourceInvestment   = "investment"
+
+	AMLDirectionInbound  = "inbound"
+	AMLDirectionOutbound = "outbound"
+
+	AMLCaseStatusOpen          = "open"
+	AMLCaseStatusInvestigating = "investigating"
+	AMLCaseStatusEscalated     = "escalated"
+	AMLCaseStatusClosed        = "closed"
+
+	AMLCaseActionOpened               = "opened"
+	AMLCaseActionAlerted              = "alerted"
+	AMLCaseActionInvestigationStarted = "investigation_started"
+	AMLCaseActionEscalated            = "escalated"
+	AMLCaseActionClosed               = "closed"
+
+	AMLClosureFalsePositive   = "false_positive"
+	AMLClosureNoFurtherAction = "no_further_action"
+	AMLClosureReported        = "reported"
+)
+
+// AMLSeverityRank orders severities from low to high
+func AMLSeverityRank(severity string) int {
+	switch severity {
+	case AMLSeverityHigh:
+		return 3
+	case AMLSeverityMedium:
+		return 2
+	case AMLSeverityLow:
+		return 1
+	}
+	return 0
+}
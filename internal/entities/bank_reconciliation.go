@@ -0,0 +1,142 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BankStatement is an imported bank statement of a cooperative escrow account
+type BankStatement struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	CooperativeID   uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
+	EscrowAccountID uuid.UUID  `json:"escrow_account_id" db:"escrow_account_id"`
+	Format          string     `json:"format" db:"format"` // csv, mt940
+	FileName        string     `json:"file_name" db:"file_name"`
+	FileHash        string     `json:"file_hash" db:"file_hash"` // SHA-256 of the uploaded file, rejects duplicate imports
+	StatementRef    string     `json:"statement_ref" db:"statement_ref"`
+	AccountNumber   string     `json:"account_number" db:"account_number"`
+	Currency        string     `json:"currency" db:"currency"`
+	OpeningBalance  *Money     `json:"opening_balance" db:"opening_balance"`
+	ClosingBalance  *Money     `json:"closing_balance" db:"closing_balance"`
+	PeriodStart     *time.Time `json:"period_start" db:"period_start"`
+	PeriodEnd       *time.Time `json:"period_end" db:"period_end"`
+	LineCount       int        `json:"line_count" db:"line_count"`
+	MatchedCount    int        `json:"matched_count" db:"matched_count"`
+	ExceptionCount  int        `json:"exception_count" db:"exception_count"`
+	ImportedBy      uuid.UUID  `json:"imported_by" db:"imported_by"`
+	ImportedAt      time.Time  `json:"imported_at" db:"imported_at"`
+}
+
+// BankStatementLine is a single booked transaction of a bank statement
+type BankStatementLine struct {
+	ID                  uuid.UUID  `json:"id" db:"id"`
+	StatementID         uuid.UUID  `json:"statement_id" db:"statement_id"`
+	CooperativeID       uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
+	LineNumber          int        `json:"line_number" db:"line_number"`
+	ValueDate           time.Time  `json:"value_date" db:"value_date"`
+	BookingDate         *time.Time `json:"booking_date" db:"booking_date"`
+	Direction           string     `json:"direction" db:"direction"` // credit (money in), debit (money out)
+	Amount              Money      `json:"amount" db:"amount"`       // always positive
+	Currency            string     `json:"currency" db:"currency"`
+	Reference           string     `json:"reference" db:"reference"`
+	Description         string     `json:"description" db:"description"`
+	CounterpartyName    string     `json:"counterparty_name" db:"counterparty_name"`
+	CounterpartyAccount string     `json:"counterparty_account" db:"counterparty_account"`
+	BankTransactionID   string     `json:"bank_transaction_id" db:"bank_transaction_id"`
+	Status              string     `json:"status" db:"status"` // unmatched, matched, exception, ignored
+	ExceptionReason     string     `json:"exception_reason" db:"exception_reason"`
+	MatchType           string     `json:"match_type" db:"match_type"` // investment, fund_transfer
+	MatchedEntityID     *uuid.UUID `json:"matched_entity_id" db:"matched_entity_id"`
+	MatchMethod         string     `json:"match_method" db:"match_method"` // reference, amount_date, manual
+	MatchedBy           *uuid.UUID `json:"matched_by" db:"matched_by"`
+	MatchedAt           *time.Time `json:"matched_at" db:"matched_at"`
+	Notes               string     `json:"notes" db:"notes"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// ReconciliationCandidate is a pending investment or transfer a statement line can settle
+type ReconciliationCandidate struct {
+	EntityType   string    `json:"entity_type"` // investment, fund_transfer
+	EntityID     uuid.UUID `json:"entity_id"`
+	Direction    string    `json:"direction"` // credit for money expected in, debit for money expected out
+	Reference    string    `json:"reference"`
+	Amount       Money     `json:"amount"`
+	ExpectedDate time.Time `json:"expected_date"`
+}
+
+// ReconciliationTolerance controls how loosely statement lines match candidates
+type ReconciliationTolerance struct {
+	AmountMinorUnits int64 `json:"amount_minor_units"` // allowed difference, e.g. bank charges deducted in transit
+	DateDays         int   `json:"date_days"`          // allowed distance between value date and expected date
+}
+
+// ReconciliationResult summarises an import or reconciliation run
+type ReconciliationResult struct {
+	Statement  *BankStatement       `json:"statement"`
+	Matched    []*BankStatementLine `json:"matched"`
+	Exceptions []*BankStatementLine `json:"exceptions"`
+}
+
+// ImportBankStatementRequest describes an uploaded statement file
+type ImportBankStatementRequest struct {
+	CooperativeID   uuid.UUID `form:"cooperative_id" validate:"required"`
+	EscrowAccountID uuid.UUID `form:"escrow_account_id"`
+	Format          string    `form:"format" validate:"required,oneof=csv mt940"`
+	Currency        string    `form:"currency" validate:"omitempty,len=3"` // required for CSV files without a currency column
+}
+
+// ManualMatchRequest resolves an exception by matching it to a specific entity
+type ManualMatchRequest struct {
+	MatchType string    `json:"match_type" validate:"required,oneof=investment fund_transfer"`
+	EntityID  uuid.UUID `json:"entity_id" validate:"required"`
+	Notes     string    `json:"notes" validate:"max=1000"`
+}
+
+// IgnoreStatementLineRequest resolves an exception that needs no matching, e.g. bank charges
+type IgnoreStatementLineRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+
+// BankStatementLineFilter for listing statement lines
+type BankStatementLineFilter struct {
+	CooperativeID uuid.UUID  `json:"cooperative_id"`
+	StatementID   *uuid.UUID `json:"statement_id"`
+	Status        string     `json:"status"`
+	Page          int        `json:"page"`
+	Limit         int        `json:"limit"`
+}
+
+// InvestmentPaymentReference returns the reference an investor quotes when paying for an investment
+func InvestmentPaymentReference(investmentID uuid.UUID) string {
+	return fmt.Sprintf("INV-%s", strings.ToUpper(strings.ReplaceAll(investmentID.String(), "-", "")[:12]))
+}
+
+// Bank reconciliation constants
+const (
+	BankStatementFormatCSV   = "csv"
+	BankStatementFormatMT940 = "mt940"
+
+	StatementLineDirectionCredit = "credit"
+	StatementLineDirectionDebit  = "debit"
+
+	StatementLineStatusUnmatched = "unmatched"
+	StatementLineStatusMatched   = "matched"
+	StatementLineStatusException = "exception"
+	StatementLineStatusIgnored   = "ignored"
+
+	ReconciliationExceptionNoMatch        = "no_match"
+	ReconciliationExceptionAmbiguous      = "ambiguous"
+	ReconciliationExceptionAmountMismatch = "amount_mismatch"
+	ReconciliationExceptionCurrency       = "currency_mismatch"
+
+	ReconciliationMatchTypeInvestment   = "investment"
+	ReconciliationMatchTypeFundTransfer = "fund_transfer"
+
+	ReconciliationMatchMethodReference  = "reference"
+	ReconciliationMatchMethodAmountDate = "amount_date"
+	ReconciliationMatchMethodManual     = "manual"
+)
@@ -84,6 +84,7 @@ type BusinessFinancialReport struct {
 	Currency         string                 `json:"currency" db:"currency"`
 	TotalRevenue     float64                `json:"total_revenue" db:"total_revenue"`
 	TotalExpenses    float64                `json:"total_expenses" db:"total_expenses"`
+	InterestIncome   float64                `json:"interest_income" db:"interest_income"` // part of total revenue earned from interest
 	NetIncome        float64                `json:"net_income" db:"net_income"`
 	GrossProfit      float64                `json:"gross_profit" db:"gross_profit"`
 	OperatingIncome  float64                `json:"operating_income" db:"operating_income"`
@@ -211,6 +212,7 @@ type CreateFinancialReportRequest struct {
 	Currency         string                 `json:"currency" validate:"required,len=3"`
 	TotalRevenue     float64                `json:"total_revenue" validate:"required,min=0"`
 	TotalExpenses    float64                `json:"total_expenses" validate:"required,min=0"`
+	InterestIncome   float64                `json:"interest_income" validate:"min=0,ltefield=TotalRevenue"`
 	Assets           float64                `json:"assets" validate:"min=0"`
 	Liabilities      float64                `json:"liabilities" validate:"min=0"`
 	CashFlow         float64                `json:"cash_flow"`
@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Currency is an entry of the platform currency registry
+type Currency struct {
+	Code       string    `json:"code" db:"code"` // ISO 4217, e.g. IDR, MYR, USD
+	Name       string    `json:"name" db:"name"`
+	Symbol     string    `json:"symbol" db:"symbol"`
+	MinorUnits int       `json:"minor_units" db:"minor_units"` // decimal places of the minor unit
+	IsActive   bool      `json:"is_active" db:"is_active"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ExchangeRate is the rate from a base currency to a quote currency that applies
+// from EffectiveAt until a newer rate for the same pair becomes effective.
+// Rate is the number of quote currency units per one base currency unit.
+type ExchangeRate struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	BaseCurrency  string    `json:"base_currency" db:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency" db:"quote_currency"`
+	Rate          Rate      `json:"rate" db:"rate"`
+	Source        string    `json:"source" db:"source"` // manual, bank_indonesia, bank_negara, provider
+	EffectiveAt   time.Time `json:"effective_at" db:"effective_at"`
+	CreatedBy     uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// ConvertedAmount is an amount in its original currency together with its
+// value in another currency at the rate effective at the conversion time
+type ConvertedAmount struct {
+	Original        Money      `json:"original"`
+	Converted       Money      `json:"converted"`
+	ExchangeRate    Rate       `json:"exchange_rate"`
+	RateID          *uuid.UUID `json:"rate_id,omitempty"`
+	RateEffectiveAt *time.Time `json:"rate_effective_at,omitempty"`
+	ConvertedAt     time.Time  `json:"converted_at"`
+}
+
+// CreateCurrencyRequest for adding or updating a registry currency
+type CreateCurrencyRequest struct {
+	Code       string `json:"code" validate:"required,len=3,uppercase"`
+	Name       string `json:"name" validate:"required,max=100"`
+	Symbol     string `json:"symbol" validate:"max=10"`
+	MinorUnits int    `json:"minor_units" validate:"min=0,max=4"`
+	IsActive   bool   `json:"is_active"`
+}
+
+// CreateExchangeRateRequest for recording a new exchange rate
+type CreateExchangeRateRequest struct {
+	BaseCurrency  string     `json:"base_currency" validate:"required,len=3"`
+	QuoteCurrency string     `json:"quote_currency" validate:"required,len=3,nefield=BaseCurrency"`
+	Rate          Rate       `json:"rate" validate:"required"`
+	Source        string     `json:"source" validate:"omitempty,oneof=manual bank_indonesia bank_negara provider"`
+	EffectiveAt   *time.Time `json:"effective_at"` // defaults to now
+}
+
+// ConvertAmountRequest for converting an amount at a point in time
+type ConvertAmountRequest struct {
+	Amount Money      `json:"amount" validate:"required"`
+	From   string     `json:"from" validate:"required,len=3"`
+	To     string     `json:"to" validate:"required,len=3"`
+	AsOf   *time.Time `json:"as_of"` // defaults to now
+}
+
+// Exchange rate sources
+const (
+	ExchangeRateSourceManual        = "manual"
+	ExchangeRateSourceBankIndonesia = "bank_indonesia"
+	ExchangeRateSourceBankNegara    = "bank_negara"
+	ExchangeRateSourceProvider      = "provider"
+)
@@ -0,0 +1,108 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DisbursementTranche is the share of a funded project's money set aside for
+// one of its milestones (FR-046). Disbursements draw on a tranche only once the
+// owner has completed the milestone and the cooperative has verified the
+// evidence; budget left when the tranche is closed is kept for refund.
+type DisbursementTranche struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	ProjectID         uuid.UUID  `json:"project_id" db:"project_id"`
+	CooperativeID     uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
+	MilestoneID       uuid.UUID  `json:"milestone_id" db:"milestone_id"`
+	MilestoneTitle    string     `json:"milestone_title" db:"milestone_title"`
+	MilestoneStatus   string     `json:"milestone_status" db:"milestone_status"` // pending, completed
+	Sequence          int        `json:"sequence" db:"sequence"`
+	Currency          string     `json:"currency" db:"currency"`
+	Budget            Money      `json:"budget" db:"budget"`
+	ReleasedAmount    Money      `json:"released_amount" db:"released_amount"`
+	UnusedAmount      Money      `json:"unused_amount" db:"unused_amount"` // set when the tranche is closed
+	Status            string     `json:"status" db:"status"`               // planned, open, released, closed
+	EvidenceDocuments []string   `json:"evidence_documents" db:"evidence_documents"`
+	CompletionNotes   string     `json:"completion_notes" db:"completion_notes"`
+	CompletedBy       *uuid.UUID `json:"completed_by" db:"completed_by"`
+	CompletedAt       *time.Time `json:"completed_at" db:"completed_at"`
+	VerifiedBy        *uuid.UUID `json:"verified_by" db:"verified_by"`
+	VerifiedAt        *time.Time `json:"verified_at" db:"verified_at"`
+	VerificationNotes string     `json:"verification_notes" db:"verification_notes"`
+	ClosedBy          *uuid.UUID `json:"closed_by" db:"closed_by"`
+	ClosedAt          *time.Time `json:"closed_at" db:"closed_at"`
+	CloseReason       string     `json:"close_reason" db:"close_reason"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// RemainingBudget is the part of the budget not yet released nor set aside for refund
+func (t *DisbursementTranche) RemainingBudget() Money {
+	return t.Budget.Sub(t.ReleasedAmount).Sub(t.UnusedAmount)
+}
+
+// Releasable reports whether disbursements may draw on the tranche
+func (t *DisbursementTranche) Releasable() bool {
+	return t.Status == DisbursementTrancheStatusOpen && t.MilestoneStatus == MilestoneStatusCompleted && t.VerifiedAt != nil
+}
+
+// DisbursementProject is the funding of a project whose money is disbursed in tranches
+type DisbursementProject struct {
+	ProjectID     uuid.UUID `json:"project_id" db:"project_id"`
+	CooperativeID uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	BusinessID    uuid.UUID `json:"business_id" db:"business_id"`
+	OwnerID       uuid.UUID `json:"owner_id" db:"owner_id"`
+	Currency      string    `json:"currency" db:"currency"`
+	Status        string    `json:"status" db:"status"`
+	FundedAmount  Money     `json:"funded_amount" db:"current_funding"`
+}
+
+// DisbursementPlan summarises how a project's funding is being released
+type DisbursementPlan struct {
+	ProjectID    uuid.UUID              `json:"project_id"`
+	Currency     string                 `json:"currency"`
+	FundedAmount Money                  `json:"funded_amount"`
+	Released     Money                  `json:"released"`
+	Outstanding  Money                  `json:"outstanding"` // requested or approved but not yet disbursed
+	Unused       Money                  `json:"unused"`      // closed tranche budget awaiting refund
+	Tranches     []*DisbursementTranche `json:"tranches"`
+}
+
+// DisbursementTrancheInput is the budget of one milestone in a disbursement plan
+type DisbursementTrancheInput struct {
+	MilestoneID    uuid.UUID `json:"milestone_id" validate:"required"`
+	MilestoneTitle string    `json:"milestone_title" validate:"required,max=255"`
+	Budget         Money     `json:"budget" validate:"required"`
+}
+
+// DefineDisbursementTranchesRequest splits a funded project's money over its
+// milestones; the budgets must add up to the funded amount
+type DefineDisbursementTranchesRequest struct {
+	Tranches []DisbursementTrancheInput `json:"tranches" validate:"required,min=1,dive"`
+}
+
+// SubmitMilestoneEvidenceRequest marks a milestone completed for the cooperative to verify
+type SubmitMilestoneEvidenceRequest struct {
+	EvidenceDocuments []string `json:"evidence_documents" validate:"required,min=1,dive,required"`
+	Notes             string   `json:"notes" validate:"max=1000"`
+}
+
+// VerifyMilestoneRequest records the cooperative's decision on a milestone's evidence
+type VerifyMilestoneRequest struct {
+	Approve bool   `json:"approve"`
+	Notes   string `json:"notes" validate:"required_if=Approve false,max=1000"`
+}
+
+// CloseDisbursementTrancheRequest stops further releases from a tranche
+type CloseDisbursementTrancheRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+
+// Disbursement tranche constants
+const (
+	DisbursementTrancheStatusPlanned  = "planned"
+	DisbursementTrancheStatusOpen     = "open"
+	DisbursementTrancheStatusReleased = "released"
+	DisbursementTrancheStatusClosed   = "closed"
+)
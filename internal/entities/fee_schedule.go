@@ -0,0 +1,146 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ComFundsFeeTier is one band of a tiered or banded fee schedule. Tiers are
+// ordered by UpTo; the last tier leaves UpTo unset and covers any larger amount.
+type ComFundsFeeTier struct {
+	UpTo          *Money  `json:"up_to"`
+	FeePercentage float64 `json:"fee_percentage" validate:"min=0,max=100"`
+}
+
+// FeeScopeRank orders schedules by how specifically they target a project:
+// project schedules override cooperative ones, which override global ones
+func (f *ComFundsFee) FeeScopeRank() int {
+	switch {
+	case f.ProjectID != nil:
+		return 2
+	case f.CooperativeID != nil:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AppliesTo reports whether the schedule charges the project's fee at the given time
+func (f *ComFundsFee) AppliesTo(project *FeeProject, at time.Time) bool {
+	if !f.IsActive || !f.IsEnabled {
+		return false
+	}
+	if at.Before(f.EffectiveFrom) || (f.EffectiveTo != nil && at.After(*f.EffectiveTo)) {
+		return false
+	}
+	if f.ApplicableTo == ComFundsFeeApplicableToSpecificProjects && f.ProjectID == nil {
+		return false
+	}
+	if f.ProjectID != nil && *f.ProjectID != project.ProjectID {
+		return false
+	}
+	if f.CooperativeID != nil && *f.CooperativeID != project.CooperativeID {
+		return false
+	}
+	return f.Currency == "" || f.Currency == project.Currency
+}
+
+// FeeWaiver is a promotional discount on a fee type, for every project or for
+// one cooperative or project, until it expires or is revoked
+type FeeWaiver struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	FeeType          string     `json:"fee_type" db:"fee_type"`
+	CooperativeID    *uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	ProjectID        *uuid.UUID `json:"project_id" db:"project_id"`
+	WaiverPercentage float64    `json:"waiver_percentage" db:"waiver_percentage"` // share of the fee waived
+	ValidFrom        time.Time  `json:"valid_from" db:"valid_from"`
+	ExpiresAt        time.Time  `json:"expires_at" db:"expires_at"`
+	Reason           string     `json:"reason" db:"reason"`
+	IsActive         bool       `json:"is_active" db:"is_active"`
+	CreatedBy        uuid.UUID  `json:"created_by" db:"created_by"`
+	RevokedBy        *uuid.UUID `json:"revoked_by" db:"revoked_by"`
+	RevokedAt        *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// AppliesTo reports whether the waiver discounts the project's fee at the given time
+func (w *FeeWaiver) AppliesTo(project *FeeProject, feeType string, at time.Time) bool {
+	if !w.IsActive || w.FeeType != feeType {
+		return false
+	}
+	if at.Before(w.ValidFrom) || !at.Before(w.ExpiresAt) {
+		return false
+	}
+	if w.ProjectID != nil && *w.ProjectID != project.ProjectID {
+		return false
+	}
+	return w.CooperativeID == nil || *w.CooperativeID == project.CooperativeID
+}
+
+// FeeProject is the project a fee is charged for, with the cooperative that invoices it
+type FeeProject struct {
+	ProjectID     uuid.UUID `json:"project_id" db:"project_id"`
+	CooperativeID uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	BusinessID    uuid.UUID `json:"business_id" db:"business_id"`
+	Currency      string    `json:"currency" db:"currency"`
+}
+
+// FeeInvoice bills a business for a collected project fee. Invoices are
+// numbered per cooperative and year.
+type FeeInvoice struct {
+	ID                   uuid.UUID `json:"id" db:"id"`
+	InvoiceNumber        string    `json:"invoice_number" db:"invoice_number"`
+	CooperativeID        uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	ProjectID            uuid.UUID `json:"project_id" db:"project_id"`
+	BusinessID           uuid.UUID `json:"business_id" db:"business_id"`
+	FeeCalculationID     uuid.UUID `json:"fee_calculation_id" db:"fee_calculation_id"`
+	Currency             string    `json:"currency" db:"currency"`
+	GrossAmount          Money     `json:"gross_amount" db:"gross_amount"`
+	DiscountAmount       Money     `json:"discount_amount" db:"discount_amount"` // promotional waiver
+	NetAmount            Money     `json:"net_amount" db:"net_amount"`
+	CollectionMethod     string    `json:"collection_method" db:"collection_method"`
+	TransactionReference string    `json:"transaction_reference" db:"transaction_reference"`
+	IssuedBy             uuid.UUID `json:"issued_by" db:"issued_by"`
+	IssuedAt             time.Time `json:"issued_at" db:"issued_at"`
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`
+}
+
+// FeeCreditNote records a calculated project fee being waived. Credit notes
+// are numbered per cooperative and year.
+type FeeCreditNote struct {
+	ID               uuid.UUID `json:"id" db:"id"`
+	CreditNoteNumber string    `json:"credit_note_number" db:"credit_note_number"`
+	CooperativeID    uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	ProjectID        uuid.UUID `json:"project_id" db:"project_id"`
+	BusinessID       uuid.UUID `json:"business_id" db:"business_id"`
+	FeeCalculationID uuid.UUID `json:"fee_calculation_id" db:"fee_calculation_id"`
+	Currency         string    `json:"currency" db:"currency"`
+	Amount           Money     `json:"amount" db:"amount"`
+	Reason           string    `json:"reason" db:"reason"`
+	IssuedBy         uuid.UUID `json:"issued_by" db:"issued_by"`
+	IssuedAt         time.Time `json:"issued_at" db:"issued_at"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateFeeWaiverRequest creates a promotional fee waiver
+type CreateFeeWaiverRequest struct {
+	FeeType          string     `json:"fee_type" validate:"required,oneof=platform_fee success_fee transaction_fee"`
+	CooperativeID    *uuid.UUID `json:"cooperative_id"`
+	ProjectID        *uuid.UUID `json:"project_id"`
+	WaiverPercentage float64    `json:"waiver_percentage" validate:"gt=0,max=100"`
+	ValidFrom        *time.Time `json:"valid_from"`
+	ExpiresAt        time.Time  `json:"expires_at" validate:"required"`
+	Reason           string     `json:"reason" validate:"required,max=1000"`
+}
+
+// Fee schedule constants
+const (
+	FeeTierModeFlat   = "flat"
+	FeeTierModeBanded = "banded"
+	FeeTierModeTiered = "tiered"
+
+	FeeDocumentTypeInvoice    = "invoice"
+	FeeDocumentTypeCreditNote = "credit_note"
+)
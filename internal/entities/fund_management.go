@@ -13,7 +13,8 @@ type FundDisbursement struct {
 	BusinessID           uuid.UUID              `json:"business_id" db:"business_id"`
 	CooperativeID        uuid.UUID              `json:"cooperative_id" db:"cooperative_id"`
 	MilestoneID          uuid.UUID              `json:"milestone_id" db:"milestone_id"`
-	DisbursementAmount   float64                `json:"disbursement_amount" db:"disbursement_amount"`
+	TrancheID            uuid.UUID              `json:"tranche_id" db:"tranche_id"`
+	DisbursementAmount   Money                  `json:"disbursement_amount" db:"disbursement_amount"`
 	Currency             string                 `json:"currency" db:"currency"`
 	DisbursementType     string                 `json:"disbursement_type" db:"disbursement_type"` // milestone, partial, final
 	DisbursementReason   string                 `json:"disbursement_reason" db:"disbursement_reason"`
@@ -39,13 +40,13 @@ type FundUsage struct {
 	BusinessID         uuid.UUID              `json:"business_id" db:"business_id"`
 	DisbursementID     uuid.UUID              `json:"disbursement_id" db:"disbursement_id"`
 	UsageCategory      string                 `json:"usage_category" db:"usage_category"` // equipment, marketing, operations, expansion, other
-	UsageAmount        float64                `json:"usage_amount" db:"usage_amount"`
+	UsageAmount        Money                  `json:"usage_amount" db:"usage_amount"`
 	Currency           string                 `json:"currency" db:"currency"`
 	UsageDescription   string                 `json:"usage_description" db:"usage_description"`
 	UsageDate          time.Time              `json:"usage_date" db:"usage_date"`
 	PerformanceMetrics map[string]interface{} `json:"performance_metrics" db:"performance_metrics"`
-	RevenueGenerated   float64                `json:"revenue_generated" db:"revenue_generated"`
-	CostSavings        float64                `json:"cost_savings" db:"cost_savings"`
+	RevenueGenerated   Money                  `json:"revenue_generated" db:"revenue_generated"`
+	CostSavings        Money                  `json:"cost_savings" db:"cost_savings"`
 	ROI                float64                `json:"roi" db:"roi"` // Return on Investment percentage
 	Documents          []string               `json:"documents" db:"documents"`
 	Receipts           []string               `json:"receipts" db:"receipts"`
@@ -65,11 +66,11 @@ type FundRefund struct {
 	CooperativeID        uuid.UUID              `json:"cooperative_id" db:"cooperative_id"`
 	RefundType           string                 `json:"refund_type" db:"refund_type"` // minimum_funding_failed, project_cancelled, investor_request
 	RefundReason         string                 `json:"refund_reason" db:"refund_reason"`
-	TotalRefundAmount    float64                `json:"total_refund_amount" db:"total_refund_amount"`
+	TotalRefundAmount    Money                  `json:"total_refund_amount" db:"total_refund_amount"`
 	Currency             string                 `json:"currency" db:"currency"`
 	RefundPercentage     float64                `json:"refund_percentage" db:"refund_percentage"` // percentage of original investment
-	ProcessingFee        float64                `json:"processing_fee" db:"processing_fee"`
-	NetRefundAmount      float64                `json:"net_refund_amount" db:"net_refund_amount"`
+	ProcessingFee        Money                  `json:"processing_fee" db:"processing_fee"`
+	NetRefundAmount      Money                  `json:"net_refund_amount" db:"net_refund_amount"`
 	Status               string                 `json:"status" db:"status"` // pending, processing, completed, failed, cancelled
 	InitiatedBy          uuid.UUID              `json:"initiated_by" db:"initiated_by"`
 	InitiatedAt          time.Time              `json:"initiated_at" db:"initiated_at"`
@@ -90,10 +91,10 @@ type InvestorRefund struct {
 	FundRefundID         uuid.UUID  `json:"fund_refund_id" db:"fund_refund_id"`
 	InvestmentID         uuid.UUID  `json:"investment_id" db:"investment_id"`
 	InvestorID           uuid.UUID  `json:"investor_id" db:"investor_id"`
-	OriginalInvestment   float64    `json:"original_investment" db:"original_investment"`
-	RefundAmount         float64    `json:"refund_amount" db:"refund_amount"`
-	ProcessingFee        float64    `json:"processing_fee" db:"processing_fee"`
-	NetRefundAmount      float64    `json:"net_refund_amount" db:"net_refund_amount"`
+	OriginalInvestment   Money      `json:"original_investment" db:"original_investment"`
+	RefundAmount         Money      `json:"refund_amount" db:"refund_amount"`
+	ProcessingFee        Money      `json:"processing_fee" db:"processing_fee"`
+	NetRefundAmount      Money      `json:"net_refund_amount" db:"net_refund_amount"`
 	Status               string     `json:"status" db:"status"` // pending, processing, completed, failed
 	BankAccount          string     `json:"bank_account" db:"bank_account"`
 	TransactionReference string     `json:"transaction_reference" db:"transaction_reference"`
@@ -108,7 +109,7 @@ type InvestorRefund struct {
 type CreateFundDisbursementRequest struct {
 	ProjectID          uuid.UUID `json:"project_id" validate:"required"`
 	MilestoneID        uuid.UUID `json:"milestone_id" validate:"required"`
-	DisbursementAmount float64   `json:"disbursement_amount" validate:"required,min=0"`
+	DisbursementAmount Money     `json:"disbursement_amount" validate:"required,min=0"`
 	Currency           string    `json:"currency" validate:"required,len=3"`
 	DisbursementType   string    `json:"disbursement_type" validate:"required,oneof=milestone partial final"`
 	DisbursementReason string    `json:"disbursement_reason" validate:"required"`
@@ -127,12 +128,12 @@ type CreateFundUsageRequest struct {
 	ProjectID          uuid.UUID              `json:"project_id" validate:"required"`
 	DisbursementID     uuid.UUID              `json:"disbursement_id" validate:"required"`
 	UsageCategory      string                 `json:"usage_category" validate:"required,oneof=equipment marketing operations expansion other"`
-	UsageAmount        float64                `json:"usage_amount" validate:"required,min=0"`
+	UsageAmount        Money                  `json:"usage_amount" validate:"required,min=0"`
 	Currency           string                 `json:"currency" validate:"required,len=3"`
 	UsageDescription   string                 `json:"usage_description" validate:"required"`
 	UsageDate          time.Time              `json:"usage_date" validate:"required"`
-	RevenueGenerated   *float64               `json:"revenue_generated"`
-	CostSavings        *float64               `json:"cost_savings"`
+	RevenueGenerated   *Money                 `json:"revenue_generated"`
+	CostSavings        *Money                 `json:"cost_savings"`
 	PerformanceMetrics map[string]interface{} `json:"performance_metrics"`
 	Documents          []string               `json:"documents"`
 	Receipts           []string               `json:"receipts"`
@@ -143,7 +144,7 @@ type CreateFundRefundRequest struct {
 	ProjectID     uuid.UUID `json:"project_id" validate:"required"`
 	RefundType    string    `json:"refund_type" validate:"required,oneof=minimum_funding_failed project_cancelled investor_request"`
 	RefundReason  string    `json:"refund_reason" validate:"required"`
-	ProcessingFee float64   `json:"processing_fee" validate:"min=0"`
+	ProcessingFee Money     `json:"processing_fee" validate:"min=0"`
 }
 
 // FundDisbursementFilter for searching disbursements
@@ -155,8 +156,8 @@ type FundDisbursementFilter struct {
 	DisbursementType *string    `json:"disbursement_type"`
 	StartDate        *time.Time `json:"start_date"`
 	EndDate          *time.Time `json:"end_date"`
-	MinAmount        *float64   `json:"min_amount"`
-	MaxAmount        *float64   `json:"max_amount"`
+	MinAmount        *Money     `json:"min_amount"`
+	MaxAmount        *Money     `json:"max_amount"`
 	Page             int        `json:"page" validate:"min=1"`
 	Limit            int        `json:"limit" validate:"min=1,max=100"`
 }
@@ -170,8 +171,8 @@ type FundUsageFilter struct {
 	IsVerified     *bool      `json:"is_verified"`
 	StartDate      *time.Time `json:"start_date"`
 	EndDate        *time.Time `json:"end_date"`
-	MinAmount      *float64   `json:"min_amount"`
-	MaxAmount      *float64   `json:"max_amount"`
+	MinAmount      *Money     `json:"min_amount"`
+	MaxAmount      *Money     `json:"max_amount"`
 	Page           int        `json:"page" validate:"min=1"`
 	Limit          int        `json:"limit" validate:"min=1,max=100"`
 }
@@ -191,17 +192,17 @@ type FundRefundFilter struct {
 
 // FundManagementSummary for reporting
 type FundManagementSummary struct {
-	TotalDisbursements   int     `json:"total_disbursements"`
-	TotalDisbursedAmount float64 `json:"total_disbursed_amount"`
-	PendingDisbursements int     `json:"pending_disbursements"`
-	PendingAmount        float64 `json:"pending_amount"`
-	TotalFundUsage       int     `json:"total_fund_usage"`
-	TotalUsageAmount     float64 `json:"total_usage_amount"`
-	TotalRefunds         int     `json:"total_refunds"`
-	TotalRefundAmount    float64 `json:"total_refund_amount"`
-	ProcessingRefunds    int     `json:"processing_refunds"`
-	ProcessingAmount     float64 `json:"processing_amount"`
-	Currency             string  `json:"currency"`
+	TotalDisbursements   int    `json:"total_disbursements"`
+	TotalDisbursedAmount Money  `json:"total_disbursed_amount"`
+	PendingDisbursements int    `json:"pending_disbursements"`
+	PendingAmount        Money  `json:"pending_amount"`
+	TotalFundUsage       int    `json:"total_fund_usage"`
+	TotalUsageAmount     Money  `json:"total_usage_amount"`
+	TotalRefunds         int    `json:"total_refunds"`
+	TotalRefundAmount    Money  `json:"total_refund_amount"`
+	ProcessingRefunds    int    `json:"processing_refunds"`
+	ProcessingAmount     Money  `json:"processing_amount"`
+	Currency             string `json:"currency"`
 }
 
 // Fund constants
@@ -18,14 +18,17 @@ type FundTransfer struct {
 	FromUserID            *uuid.UUID             `json:"from_user_id" db:"from_user_id"`     // Investor
 	ToUserID              *uuid.UUID             `json:"to_user_id" db:"to_user_id"`       // Business Owner
 	TransferType          string                 `json:"transfer_type" db:"transfer_type"`   // investment, profit_distribution, withdrawal, refund
-	Amount                float64                `json:"amount" db:"amount"`
+	Amount                Money                  `json:"amount" db:"amount"`
 	Currency              string                 `json:"currency" db:"currency"`
-	ExchangeRate          float64                `json:"exchange_rate" db:"exchange_rate"`
-	Fee                   float64                `json:"fee" db:"fee"`
-	NetAmount             float64                `json:"net_amount" db:"net_amount"`
-	Status                string                 `json:"status" db:"status"`                 // pending, processing, completed, failed, cancelled
+	ExchangeRate          Rate                   `json:"exchange_rate" db:"exchange_rate"`             // Currency to SettlementCurrency, fixed at transaction time
+	SettlementCurrency    string                 `json:"settlement_currency" db:"settlement_currency"` // Currency the receiving account is credited in
+	SettlementAmount      Money                  `json:"settlement_amount" db:"settlement_amount"`     // NetAmount converted at ExchangeRate
+	Fee                   Money                  `json:"fee" db:"fee"`
+	NetAmount             Money                  `json:"net_amount" db:"net_amount"`
+	Status                string                 `json:"status" db:"status"`                 // pending, processing, completed, failed, dead_lettered, cancelled
 	PaymentMethod         string                 `json:"payment_method" db:"payment_method"` // bank_transfer, digital_wallet, cash
 	PaymentReference      string                 `json:"payment_reference" db:"payment_reference"`
+	BankAccount           string                 `json:"bank_account" db:"bank_account"` // Member's account paid from or to
 	BankTransactionID     string                 `json:"bank_transaction_id" db:"bank_transaction_id"`
 	Description           string                 `json:"description" db:"description"`
 	Notes                 string                 `json:"notes" db:"notes"`
@@ -37,8 +40,13 @@ type FundTransfer struct {
 	FailureReason         string                 `json:"failure_reason" db:"failure_reason"`
 	RetryCount            int                    `json:"retry_count" db:"retry_count"`
 	MaxRetries            int                    `json:"max_retries" db:"max_retries"`
+	NextAttemptAt         *time.Time             `json:"next_attempt_at" db:"next_attempt_at"` // When a pending or failed transfer is next executed
+	DeadLetteredAt        *time.Time             `json:"dead_lettered_at" db:"dead_lettered_at"`
 	InitiatedBy           uuid.UUID              `json:"initiated_by" db:"initiated_by"`
 	ApprovedBy            *uuid.UUID             `json:"approved_by" db:"approved_by"`
+	CancelledBy           *uuid.UUID             `json:"cancelled_by" db:"cancelled_by"`
+	CancelledAt           *time.Time             `json:"cancelled_at" db:"cancelled_at"`
+	CancellationReason    string                 `json:"cancellation_reason" db:"cancellation_reason"`
 	CreatedAt             time.Time              `json:"created_at" db:"created_at"`
 	UpdatedAt             time.Time              `json:"updated_at" db:"updated_at"`
 }
@@ -51,16 +59,16 @@ type ProfitDistributionMonitoring struct {
 	CooperativeID          uuid.UUID              `json:"cooperative_id" db:"cooperative_id"`
 	PeriodStart            time.Time              `json:"period_start" db:"period_start"`
 	PeriodEnd              time.Time              `json:"period_end" db:"period_end"`
-	TotalRevenue           float64                `json:"total_revenue" db:"total_revenue"`
-	TotalExpenses          float64                `json:"total_expenses" db:"total_expenses"`
-	NetProfit              float64                `json:"net_profit" db:"net_profit"`
-	DistributableProfit    float64                `json:"distributable_profit" db:"distributable_profit"`
-	InvestorShare          float64                `json:"investor_share" db:"investor_share"`
-	CooperativeShare       float64                `json:"cooperative_share" db:"cooperative_share"`
-	BusinessOwnerShare     float64                `json:"business_owner_share" db:"business_owner_share"`
-	AdminFee               float64                `json:"admin_fee" db:"admin_fee"`
-	TotalDistributed       float64                `json:"total_distributed" db:"total_distributed"`
-	PendingDistribution    float64                `json:"pending_distribution" db:"pending_distribution"`
+	TotalRevenue           Money                  `json:"total_revenue" db:"total_revenue"`
+	TotalExpenses          Money                  `json:"total_expenses" db:"total_expenses"`
+	NetProfit              Money                  `json:"net_profit" db:"net_profit"`
+	DistributableProfit    Money                  `json:"distributable_profit" db:"distributable_profit"`
+	InvestorShare          Money                  `json:"investor_share" db:"investor_share"`
+	CooperativeShare       Money                  `json:"cooperative_share" db:"cooperative_share"`
+	BusinessOwnerShare     Money                  `json:"business_owner_share" db:"business_owner_share"`
+	AdminFee               Money                  `json:"admin_fee" db:"admin_fee"`
+	TotalDistributed       Money                  `json:"total_distributed" db:"total_distributed"`
+	PendingDistribution    Money                  `json:"pending_distribution" db:"pending_distribution"`
 	Status                 string                 `json:"status" db:"status"` // calculated, approved, distributed, completed
 	CalculationMethod      string                 `json:"calculation_method" db:"calculation_method"`
 	ApprovalRequired       bool                   `json:"approval_required" db:"approval_required"`
@@ -87,11 +95,12 @@ const (
 	TransferTypeRefund             = "refund"
 	TransferTypeFee                = "fee"
 
-	TransferStatusPending    = "pending"
-	TransferStatusProcessing = "processing"
-	TransferStatusCompleted  = "completed"
-	TransferStatusFailed     = "failed"
-	TransferStatusCancelled  = "cancelled"
+	TransferStatusPending      = "pending"
+	TransferStatusProcessing   = "processing"
+	TransferStatusCompleted    = "completed"
+	TransferStatusFailed       = "failed"
+	TransferStatusDeadLettered = "dead_lettered"
+	TransferStatusCancelled    = "cancelled"
 
 	PaymentMethodBankTransfer  = "bank_transfer"
 	PaymentMethodDigitalWallet = "digital_wallet"
@@ -107,26 +116,28 @@ const (
 
 // CreateFundTransferRequest for initiating transfers
 type CreateFundTransferRequest struct {
-	ProjectID         uuid.UUID              `json:"project_id" validate:"required"`
-	InvestmentID      *uuid.UUID             `json:"investment_id"`
-	FromAccountID     uuid.UUID              `json:"from_account_id" validate:"required"`
-	ToAccountID       uuid.UUID              `json:"to_account_id" validate:"required"`
-	FromUserID        *uuid.UUID             `json:"from_user_id"`
-	ToUserID          *uuid.UUID             `json:"to_user_id"`
-	TransferType      string                 `json:"transfer_type" validate:"required,oneof=investment profit_distribution withdrawal refund fee"`
-	Amount            float64                `json:"amount" validate:"required,gt=0"`
-	Currency          string                 `json:"currency" validate:"required,len=3"`
-	PaymentMethod     string                 `json:"payment_method" validate:"required,oneof=bank_transfer digital_wallet cash check"`
-	PaymentReference  string                 `json:"payment_reference"`
-	Description       string                 `json:"description" validate:"required,max=500"`
-	Notes             string                 `json:"notes" validate:"max=1000"`
-	ScheduledAt       *time.Time             `json:"scheduled_at"`
-	Metadata          map[string]interface{} `json:"metadata"`
+	ProjectID          uuid.UUID              `json:"project_id" validate:"required"`
+	InvestmentID       *uuid.UUID             `json:"investment_id"`
+	FromAccountID      uuid.UUID              `json:"from_account_id" validate:"required"`
+	ToAccountID        uuid.UUID              `json:"to_account_id" validate:"required"`
+	FromUserID         *uuid.UUID             `json:"from_user_id"`
+	ToUserID           *uuid.UUID             `json:"to_user_id"`
+	TransferType       string                 `json:"transfer_type" validate:"required,oneof=investment profit_distribution withdrawal refund fee"`
+	Amount             Money                  `json:"amount" validate:"required,gt=0"`
+	Currency           string                 `json:"currency" validate:"required,len=3"`
+	SettlementCurrency string                 `json:"settlement_currency" validate:"omitempty,len=3"` // defaults to Currency
+	PaymentMethod      string                 `json:"payment_method" validate:"required,oneof=bank_transfer digital_wallet cash check"`
+	PaymentReference   string                 `json:"payment_reference"`
+	BankAccount        string                 `json:"bank_account" validate:"max=100"` // member's account paid from or to
+	Description        string                 `json:"description" validate:"required,max=500"`
+	Notes              string                 `json:"notes" validate:"max=1000"`
+	ScheduledAt        *time.Time             `json:"scheduled_at"`
+	Metadata           map[string]interface{} `json:"metadata"`
 }
 
 // UpdateFundTransferRequest for updating transfer details
 type UpdateFundTransferRequest struct {
-	Status              string     `json:"status" validate:"oneof=pending processing completed failed cancelled"`
+	Status              string     `json:"status" validate:"oneof=pending processing completed failed dead_lettered cancelled"`
 	PaymentReference    string     `json:"payment_reference"`
 	BankTransactionID   string     `json:"bank_transaction_id"`
 	Notes               string     `json:"notes" validate:"max=1000"`
@@ -146,8 +157,8 @@ type FundTransferFilter struct {
 	Status           string     `json:"status"`
 	PaymentMethod    string     `json:"payment_method"`
 	Currency         string     `json:"currency"`
-	MinAmount        float64    `json:"min_amount"`
-	MaxAmount        float64    `json:"max_amount"`
+	MinAmount        Money      `json:"min_amount"`
+	MaxAmount        Money      `json:"max_amount"`
 	StartDate        *time.Time `json:"start_date"`
 	EndDate          *time.Time `json:"end_date"`
 	Page             int        `json:"page"`
@@ -156,13 +167,45 @@ type FundTransferFilter struct {
 	SortOrder        string     `json:"sort_order"` // asc, desc
 }
 
+// FundTransferAttempt records one execution of a fund transfer by the transfer
+// provider, whether it succeeded or not
+type FundTransferAttempt struct {
+	ID                uuid.UUID `json:"id" db:"id"`
+	TransferID        uuid.UUID `json:"transfer_id" db:"transfer_id"`
+	AttemptNumber     int       `json:"attempt_number" db:"attempt_number"` // 1 for the first execution
+	Provider          string    `json:"provider" db:"provider"`
+	Outcome           string    `json:"outcome" db:"outcome"` // succeeded, transient_failure, permanent_failure
+	ProviderReference string    `json:"provider_reference" db:"provider_reference"`
+	ErrorMessage      string    `json:"error_message" db:"error_message"`
+	StartedAt         time.Time `json:"started_at" db:"started_at"`
+	FinishedAt        time.Time `json:"finished_at" db:"finished_at"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+}
+
+// FundTransferAttempt outcomes
+const (
+	TransferAttemptSucceeded        = "succeeded"
+	TransferAttemptTransientFailure = "transient_failure"
+	TransferAttemptPermanentFailure = "permanent_failure"
+)
+
+// RequeueFundTransferRequest returns a dead-lettered transfer to the retry queue
+type RequeueFundTransferRequest struct {
+	Notes string `json:"notes" validate:"max=1000"`
+}
+
+// CancelFundTransferRequest cancels a transfer that has not been executed
+type CancelFundTransferRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
 // CreateProfitDistributionRequest for calculating distributions
 type CreateProfitDistributionRequest struct {
 	ProjectID           uuid.UUID              `json:"project_id" validate:"required"`
 	PeriodStart         time.Time              `json:"period_start" validate:"required"`
 	PeriodEnd           time.Time              `json:"period_end" validate:"required"`
-	TotalRevenue        float64                `json:"total_revenue" validate:"required,gte=0"`
-	TotalExpenses       float64                `json:"total_expenses" validate:"required,gte=0"`
+	TotalRevenue        Money                  `json:"total_revenue" validate:"required,gte=0"`
+	TotalExpenses       Money                  `json:"total_expenses" validate:"required,gte=0"`
 	CalculationMethod   string                 `json:"calculation_method" validate:"required"`
 	Documents           []string               `json:"documents"`
 	FinancialStatements map[string]interface{} `json:"financial_statements"`
@@ -177,8 +220,8 @@ type ProfitDistributionFilter struct {
 	Status        string     `json:"status"`
 	PeriodStart   *time.Time `json:"period_start"`
 	PeriodEnd     *time.Time `json:"period_end"`
-	MinProfit     float64    `json:"min_profit"`
-	MaxProfit     float64    `json:"max_profit"`
+	MinProfit     Money      `json:"min_profit"`
+	MaxProfit     Money      `json:"max_profit"`
 	Page          int        `json:"page"`
 	Limit         int        `json:"limit"`
 	SortBy        string     `json:"sort_by"`    // created_at, net_profit, period_end
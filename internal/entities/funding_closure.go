@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FundingProject is the funding state of a project as its funding deadline
+// approaches
+type FundingProject struct {
+	ProjectID       uuid.UUID `json:"project_id" db:"project_id"`
+	CooperativeID   uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	OwnerID         uuid.UUID `json:"owner_id" db:"owner_id"`
+	Title           string    `json:"title" db:"title"`
+	Currency        string    `json:"currency" db:"currency"`
+	Status          string    `json:"status" db:"status"`
+	FundingDeadline time.Time `json:"funding_deadline" db:"funding_deadline"`
+	CurrentFunding  Money     `json:"current_funding" db:"current_funding"`
+	MinimumFunding  Money     `json:"minimum_funding" db:"minimum_funding"` // the funding goal when no minimum is set
+}
+
+// DeadlinePassed reports whether the project's funding window has closed
+func (p *FundingProject) DeadlinePassed(now time.Time) bool {
+	return !now.Before(p.FundingDeadline)
+}
+
+// MeetsMinimum reports whether funding reaches the project's minimum
+func (p *FundingProject) MeetsMinimum(funding Money) bool {
+	return funding.IsPositive() && !funding.LessThan(p.MinimumFunding)
+}
+
+// ProjectFundingClosure records how a project's funding was closed at its
+// deadline: funded, with the success fee calculated, or refunded to its
+// investors for missing its minimum
+type ProjectFundingClosure struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	ProjectID        uuid.UUID  `json:"project_id" db:"project_id"`
+	CooperativeID    uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
+	Currency         string     `json:"currency" db:"currency"`
+	Outcome          string     `json:"outcome" db:"outcome"` // funded, minimum_not_met
+	FundingDeadline  time.Time  `json:"funding_deadline" db:"funding_deadline"`
+	CurrentFunding   Money      `json:"current_funding" db:"current_funding"`
+	MinimumFunding   Money      `json:"minimum_funding" db:"minimum_funding"`
+	InvestorCount    int        `json:"investor_count" db:"investor_count"`
+	FeeCalculationID *uuid.UUID `json:"fee_calculation_id" db:"fee_calculation_id"`
+	FeePercentage    *float64   `json:"fee_percentage" db:"fee_percentage"`
+	FeeAmount        *Money     `json:"fee_amount" db:"fee_amount"`
+	FundRefundID     *uuid.UUID `json:"fund_refund_id" db:"fund_refund_id"` // nil when nobody had invested
+	ClosedBy         uuid.UUID  `json:"closed_by" db:"closed_by"`           // uuid.Nil when closed by the scheduler
+	ClosedAt         time.Time  `json:"closed_at" db:"closed_at"`
+	RefundPostedAt   *time.Time `json:"refund_posted_at" db:"refund_posted_at"` // nil until the refund is paid out of escrow in the ledger
+	NotifiedAt       *time.Time `json:"notified_at" db:"notified_at"`           // nil until the owner and investors are told the outcome
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
+
+// FundingDeadlineRun summarises one pass of the funding deadline scheduler
+type FundingDeadlineRun struct {
+	AsOf     time.Time                `json:"as_of"`
+	Closures []*ProjectFundingClosure `json:"closures"`
+	Failures []FundingDeadlineFailure `json:"failures"`
+}
+
+// FundingDeadlineFailure is a project the scheduler could not close; it is
+// retried on the next run
+type FundingDeadlineFailure struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	Error     string    `json:"error"`
+}
+
+// Funding closure constants
+const (
+	ProjectFundingOutcomeFunded        = "funded"
+	ProjectFundingOutcomeMinimumNotMet = "minimum_not_met"
+)
@@ -12,7 +12,7 @@ type InvestmentExtended struct {
 	InvestorID           uuid.UUID              `json:"investor_id" db:"investor_id"`
 	ProjectID            uuid.UUID              `json:"project_id" db:"project_id"`
 	CooperativeID        uuid.UUID              `json:"cooperative_id" db:"cooperative_id"`
-	Amount               float64                `json:"amount" db:"amount"`
+	Amount               Money                  `json:"amount" db:"amount"`
 	Currency             string                 `json:"currency" db:"currency"`
 	InvestmentType       string                 `json:"investment_type" db:"investment_type"`             // full, partial
 	InvestmentPercentage float64                `json:"investment_percentage" db:"investment_percentage"` // percentage of total funding
@@ -20,6 +20,7 @@ type InvestmentExtended struct {
 	ApprovalStatus       string                 `json:"approval_status" db:"approval_status"`
 	ApprovedBy           *uuid.UUID             `json:"approved_by" db:"approved_by"`
 	ApprovedAt           *time.Time             `json:"approved_at" db:"approved_at"`
+	ReservedUntil        *time.Time             `json:"reserved_until" db:"-"` // when the pending investment's hold on the funding goal lapses
 	RejectionReason      string                 `json:"rejection_reason" db:"rejection_reason"`
 	EscrowAccountID      uuid.UUID              `json:"escrow_account_id" db:"escrow_account_id"`
 	TransferReference    string                 `json:"transfer_reference" db:"transfer_reference"`
@@ -28,7 +29,7 @@ type InvestmentExtended struct {
 	ExpectedReturnDate   *time.Time             `json:"expected_return_date" db:"expected_return_date"`
 	ActualReturn         float64                `json:"actual_return" db:"actual_return"`
 	ActualReturnDate     *time.Time             `json:"actual_return_date" db:"actual_return_date"`
-	ProfitSharingAmount  float64                `json:"profit_sharing_amount" db:"profit_sharing_amount"`
+	ProfitSharingAmount  Money                  `json:"profit_sharing_amount" db:"profit_sharing_amount"`
 	ProfitSharingDate    *time.Time             `json:"profit_sharing_date" db:"profit_sharing_date"`
 	RiskLevel            string                 `json:"risk_level" db:"risk_level"` // low, medium, high
 	ShariaCompliant      bool                   `json:"sharia_compliant" db:"sharia_compliant"`
@@ -49,9 +50,9 @@ type EscrowAccount struct {
 	BankName           string    `json:"bank_name" db:"bank_name"`
 	BankCode           string    `json:"bank_code" db:"bank_code"`
 	Currency           string    `json:"currency" db:"currency"`
-	Balance            float64   `json:"balance" db:"balance"`
-	TotalInvestments   float64   `json:"total_investments" db:"total_investments"`
-	TotalDistributions float64   `json:"total_distributions" db:"total_distributions"`
+	Balance            Money     `json:"balance" db:"balance"`
+	TotalInvestments   Money     `json:"total_investments" db:"total_investments"`
+	TotalDistributions Money     `json:"total_distributions" db:"total_distributions"`
 	Status             string    `json:"status" db:"status"` // active, suspended, closed
 	IsActive           bool      `json:"is_active" db:"is_active"`
 	CreatedAt          time.Time `json:"created_at" db:"created_at"`
@@ -64,7 +65,7 @@ type InvestmentRequest struct {
 	InvestorID      uuid.UUID  `json:"investor_id" db:"investor_id"`
 	ProjectID       uuid.UUID  `json:"project_id" db:"project_id"`
 	CooperativeID   uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
-	Amount          float64    `json:"amount" db:"amount"`
+	Amount          Money      `json:"amount" db:"amount"`
 	Currency        string     `json:"currency" db:"currency"`
 	InvestmentType  string     `json:"investment_type" db:"investment_type"`
 	Status          string     `json:"status" db:"status"` // pending, approved, rejected
@@ -78,17 +79,20 @@ type InvestmentRequest struct {
 
 // CreateInvestmentExtendedRequest for FR-041 and FR-042
 type CreateInvestmentExtendedRequest struct {
-	ProjectID      uuid.UUID `json:"project_id" validate:"required"`
-	Amount         float64   `json:"amount" validate:"required,min=0"`
-	Currency       string    `json:"currency" validate:"required,len=3"`
-	InvestmentType string    `json:"investment_type" validate:"required,oneof=full partial"`
+	ProjectID       uuid.UUID `json:"project_id" validate:"required"`
+	CooperativeID   uuid.UUID `json:"cooperative_id"`
+	Amount          Money     `json:"amount" validate:"required,min=0"`
+	Currency        string    `json:"currency" validate:"required,len=3"`
+	InvestmentType  string    `json:"investment_type" validate:"required,oneof=full partial"`
+	PaymentMethod   string    `json:"payment_method" validate:"omitempty,oneof=bank_transfer digital_wallet"` // requests payment instructions when set
+	PaymentProvider string    `json:"payment_provider"`                                                       // defaults to the configured provider
 }
 
 // UpdateInvestmentRequest for investment updates
 type UpdateInvestmentRequest struct {
-	Amount         *float64 `json:"amount" validate:"omitempty,min=0"`
-	InvestmentType *string  `json:"investment_type" validate:"omitempty,oneof=full partial"`
-	Status         *string  `json:"status" validate:"omitempty,oneof=pending approved rejected active completed cancelled"`
+	Amount         *Money  `json:"amount" validate:"omitempty,min=0"`
+	InvestmentType *string `json:"investment_type" validate:"omitempty,oneof=full partial"`
+	Status         *string `json:"status" validate:"omitempty,oneof=pending approved rejected active completed cancelled"`
 }
 
 // InvestmentApprovalRequest for FR-042
@@ -99,14 +103,23 @@ type InvestmentApprovalRequest struct {
 	RejectionReason string    `json:"rejection_reason" validate:"required_if=ApprovalStatus rejected"`
 }
 
+// InvestmentPaymentConfirmation records the receipt of an investor's payment into escrow
+type InvestmentPaymentConfirmation struct {
+	Amount            Money     `json:"amount"`
+	Reference         string    `json:"reference"`
+	BankTransactionID string    `json:"bank_transaction_id"`
+	Source            string    `json:"source"` // bank_statement, payment_provider, manual
+	PaidAt            time.Time `json:"paid_at"`
+}
+
 // InvestmentFilter for searching investments
 type InvestmentFilter struct {
 	InvestorID     *uuid.UUID `json:"investor_id"`
 	ProjectID      *uuid.UUID `json:"project_id"`
 	CooperativeID  *uuid.UUID `json:"cooperative_id"`
 	Status         *string    `json:"status"`
-	MinAmount      *float64   `json:"min_amount"`
-	MaxAmount      *float64   `json:"max_amount"`
+	MinAmount      *Money     `json:"min_amount"`
+	MaxAmount      *Money     `json:"max_amount"`
 	Currency       *string    `json:"currency"`
 	InvestmentType *string    `json:"investment_type"`
 	StartDate      *time.Time `json:"start_date"`
@@ -119,25 +132,37 @@ type InvestmentFilter struct {
 type InvestmentEligibilityCheck struct {
 	InvestorID     uuid.UUID `json:"investor_id"`
 	ProjectID      uuid.UUID `json:"project_id"`
-	Amount         float64   `json:"amount"`
+	Amount         Money     `json:"amount"`
 	IsEligible     bool      `json:"is_eligible"`
 	Reasons        []string  `json:"reasons"`
-	AvailableFunds float64   `json:"available_funds"`
-	MinInvestment  float64   `json:"min_investment"`
-	MaxInvestment  float64   `json:"max_investment"`
+	AvailableFunds Money     `json:"available_funds"`
+	MinInvestment  Money     `json:"min_investment"`
+	MaxInvestment  Money     `json:"max_investment"`
 }
 
 // InvestmentSummary for reporting
 type InvestmentSummary struct {
 	TotalInvestments     int     `json:"total_investments"`
-	TotalAmount          float64 `json:"total_amount"`
+	TotalAmount          Money   `json:"total_amount"`
 	ActiveInvestments    int     `json:"active_investments"`
-	ActiveAmount         float64 `json:"active_amount"`
+	ActiveAmount         Money   `json:"active_amount"`
 	CompletedInvestments int     `json:"completed_investments"`
-	CompletedAmount      float64 `json:"completed_amount"`
-	TotalReturns         float64 `json:"total_returns"`
+	CompletedAmount      Money   `json:"completed_amount"`
+	TotalReturns         Money   `json:"total_returns"`
 	AverageReturn        float64 `json:"average_return"`
-	Currency             string  `json:"currency"`
+	Currency             string  `json:"currency"` // base currency when holdings span several currencies
+	// Amounts per original currency with their base currency values
+	ByCurrency []*InvestmentCurrencyBreakdown `json:"by_currency,omitempty"`
+}
+
+// InvestmentCurrencyBreakdown is the part of an investment summary held in one currency
+type InvestmentCurrencyBreakdown struct {
+	Currency        string          `json:"currency"`
+	Investments     int             `json:"investments"`
+	TotalAmount     ConvertedAmount `json:"total_amount"`
+	ActiveAmount    ConvertedAmount `json:"active_amount"`
+	CompletedAmount ConvertedAmount `json:"completed_amount"`
+	TotalReturns    ConvertedAmount `json:"total_returns"`
 }
 
 // Investment constants
@@ -152,6 +177,10 @@ const (
 	InvestmentTypeFull    = "full"
 	InvestmentTypePartial = "partial"
 
+	PaymentConfirmationSourceBankStatement   = "bank_statement"
+	PaymentConfirmationSourcePaymentProvider = "payment_provider"
+	PaymentConfirmationSourceManual          = "manual"
+
 	EscrowAccountStatusActive    = "active"
 	EscrowAccountStatusSuspended = "suspended"
 	EscrowAccountStatusClosed    = "closed"
@@ -12,8 +12,8 @@ type InvestmentPolicyExtended struct {
 	CooperativeID         uuid.UUID              `json:"cooperative_id" db:"cooperative_id"`
 	Name                  string                 `json:"name" db:"name"`
 	Description           string                 `json:"description" db:"description"`
-	MinInvestmentAmount   float64                `json:"min_investment_amount" db:"min_investment_amount"`
-	MaxInvestmentAmount   float64                `json:"max_investment_amount" db:"max_investment_amount"`
+	MinInvestmentAmount   Money                  `json:"min_investment_amount" db:"min_investment_amount"`
+	MaxInvestmentAmount   Money                  `json:"max_investment_amount" db:"max_investment_amount"`
 	AllowedSectors        []string               `json:"allowed_sectors" db:"allowed_sectors"`
 	RiskLevels            []string               `json:"risk_levels" db:"risk_levels"`
 	ShariaCompliantOnly   bool                   `json:"sharia_compliant_only" db:"sharia_compliant_only"`
@@ -44,8 +44,8 @@ type ProfitSharingRulesExtended struct {
 	AdminFee              float64                `json:"admin_fee" db:"admin_fee"`                   // percentage
 	DistributionMethod    string                 `json:"distribution_method" db:"distribution_method"` // monthly, quarterly, yearly
 	DistributionDay       int                    `json:"distribution_day" db:"distribution_day"`     // day of month/quarter
-	MinProfitThreshold    float64                `json:"min_profit_threshold" db:"min_profit_threshold"`
-	MaxDistributionAmount float64                `json:"max_distribution_amount" db:"max_distribution_amount"`
+	MinProfitThreshold    Money                  `json:"min_profit_threshold" db:"min_profit_threshold"`
+	MaxDistributionAmount Money                  `json:"max_distribution_amount" db:"max_distribution_amount"`
 	LossHandlingMethod    string                 `json:"loss_handling_method" db:"loss_handling_method"` // carry_forward, shared, absorb
 	TaxHandling           string                 `json:"tax_handling" db:"tax_handling"`             // gross, net
 	ReinvestmentOption    bool                   `json:"reinvestment_option" db:"reinvestment_option"`
@@ -64,8 +64,8 @@ type ProfitSharingRulesExtended struct {
 type CreateInvestmentPolicyRequest struct {
 	Name                  string                 `json:"name" validate:"required,min=3,max=100"`
 	Description           string                 `json:"description" validate:"required,min=10,max=500"`
-	MinInvestmentAmount   float64                `json:"min_investment_amount" validate:"required,min=1"`
-	MaxInvestmentAmount   float64                `json:"max_investment_amount" validate:"required,gtfield=MinInvestmentAmount"`
+	MinInvestmentAmount   Money                  `json:"min_investment_amount" validate:"required,min=1"`
+	MaxInvestmentAmount   Money                  `json:"max_investment_amount" validate:"required,gtfield=MinInvestmentAmount"`
 	AllowedSectors        []string               `json:"allowed_sectors" validate:"required,min=1"`
 	RiskLevels            []string               `json:"risk_levels" validate:"required,min=1,dive,oneof=low medium high"`
 	ShariaCompliantOnly   bool                   `json:"sharia_compliant_only"`
@@ -90,8 +90,8 @@ type CreateProfitSharingRulesRequest struct {
 	AdminFee              float64                `json:"admin_fee" validate:"min=0,max=0.1"` // 0-10%
 	DistributionMethod    string                 `json:"distribution_method" validate:"required,oneof=monthly quarterly yearly"`
 	DistributionDay       int                    `json:"distribution_day" validate:"required,min=1,max=31"`
-	MinProfitThreshold    float64                `json:"min_profit_threshold" validate:"required,min=0"`
-	MaxDistributionAmount float64                `json:"max_distribution_amount" validate:"min=0"`
+	MinProfitThreshold    Money                  `json:"min_profit_threshold" validate:"required,min=0"`
+	MaxDistributionAmount Money                  `json:"max_distribution_amount" validate:"min=0"`
 	LossHandlingMethod    string                 `json:"loss_handling_method" validate:"required,oneof=carry_forward shared absorb"`
 	TaxHandling           string                 `json:"tax_handling" validate:"required,oneof=gross net"`
 	ReinvestmentOption    bool                   `json:"reinvestment_option"`
@@ -106,8 +106,8 @@ type CreateProfitSharingRulesRequest struct {
 type UpdateInvestmentPolicyRequest struct {
 	Name                  string                 `json:"name" validate:"min=3,max=100"`
 	Description           string                 `json:"description" validate:"min=10,max=500"`
-	MinInvestmentAmount   float64                `json:"min_investment_amount" validate:"min=1"`
-	MaxInvestmentAmount   float64                `json:"max_investment_amount"`
+	MinInvestmentAmount   Money                  `json:"min_investment_amount" validate:"min=1"`
+	MaxInvestmentAmount   Money                  `json:"max_investment_amount"`
 	AllowedSectors        []string               `json:"allowed_sectors" validate:"min=1"`
 	RiskLevels            []string               `json:"risk_levels" validate:"min=1,dive,oneof=low medium high"`
 	ShariaCompliantOnly   *bool                  `json:"sharia_compliant_only"`
@@ -132,8 +132,8 @@ type UpdateProfitSharingRulesRequest struct {
 	AdminFee              float64                `json:"admin_fee" validate:"min=0,max=0.1"`
 	DistributionMethod    string                 `json:"distribution_method" validate:"oneof=monthly quarterly yearly"`
 	DistributionDay       int                    `json:"distribution_day" validate:"min=1,max=31"`
-	MinProfitThreshold    float64                `json:"min_profit_threshold" validate:"min=0"`
-	MaxDistributionAmount float64                `json:"max_distribution_amount" validate:"min=0"`
+	MinProfitThreshold    Money                  `json:"min_profit_threshold" validate:"min=0"`
+	MaxDistributionAmount Money                  `json:"max_distribution_amount" validate:"min=0"`
 	LossHandlingMethod    string                 `json:"loss_handling_method" validate:"oneof=carry_forward shared absorb"`
 	TaxHandling           string                 `json:"tax_handling" validate:"oneof=gross net"`
 	ReinvestmentOption    *bool                  `json:"reinvestment_option"`
@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvestmentReservation holds a pending investment's amount against its
+// project's remaining funding goal, so that investments awaiting approval
+// cannot together be promised more than the goal has room for
+type InvestmentReservation struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	InvestmentID  uuid.UUID  `json:"investment_id" db:"investment_id"`
+	ProjectID     uuid.UUID  `json:"project_id" db:"project_id"`
+	InvestorID    uuid.UUID  `json:"investor_id" db:"investor_id"`
+	Amount        Money      `json:"amount" db:"amount"`
+	Currency      string     `json:"currency" db:"currency"`
+	Status        string     `json:"status" db:"status"` // active, converted, released, expired
+	ExpiresAt     time.Time  `json:"expires_at" db:"expires_at"`
+	ConvertedAt   *time.Time `json:"converted_at" db:"converted_at"`
+	ReleasedAt    *time.Time `json:"released_at" db:"released_at"`
+	ReleaseReason string     `json:"release_reason" db:"release_reason"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsHeld reports whether the reservation still holds its amount at now
+func (r *InvestmentReservation) IsHeld(now time.Time) bool {
+	return r.Status == InvestmentReservationStatusActive && now.Before(r.ExpiresAt)
+}
+
+// ProjectFundingProgress splits a project's funding goal into the amount
+// committed by approved investments, the amount reserved by investments
+// awaiting approval and the amount still available to new investors
+type ProjectFundingProgress struct {
+	ProjectID        uuid.UUID `json:"project_id"`
+	Currency         string    `json:"currency"`
+	FundingGoal      Money     `json:"funding_goal"`
+	Committed        Money     `json:"committed"`
+	Reserved         Money     `json:"reserved"`
+	Available        Money     `json:"available"`
+	InvestorCount    int       `json:"investor_count"`
+	ReservationCount int       `json:"reservation_count"`
+}
+
+// NewProjectFundingProgress derives the available amount from the goal and
+// the committed and reserved amounts
+func NewProjectFundingProgress(projectID uuid.UUID, goal, committed, reserved Money, investorCount, reservationCount int) *ProjectFundingProgress {
+	available := goal.Sub(committed).Sub(reserved)
+	if available.IsNegative() {
+		available = ZeroMoney(goal.Currency())
+	}
+
+	return &ProjectFundingProgress{
+		ProjectID:        projectID,
+		Currency:         goal.Currency(),
+		FundingGoal:      goal,
+		Committed:        committed,
+		Reserved:         reserved,
+		Available:        available,
+		InvestorCount:    investorCount,
+		ReservationCount: reservationCount,
+	}
+}
+
+// InvestmentReservation statuses
+const (
+	InvestmentReservationStatusActive    = "active"
+	InvestmentReservationStatusConverted = "converted"
+	InvestmentReservationStatusReleased  = "released"
+	InvestmentReservationStatusExpired   = "expired"
+)
@@ -0,0 +1,106 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvestmentPosition is an investor's confirmed capital in a project, as
+// recorded against the project's funding
+type InvestmentPosition struct {
+	InvestmentID        uuid.UUID `json:"investment_id" db:"id"`
+	InvestorID          uuid.UUID `json:"investor_id" db:"investor_id"`
+	ProjectID           uuid.UUID `json:"project_id" db:"project_id"`
+	CooperativeID       uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	Amount              Money     `json:"amount" db:"amount"`
+	Currency            string    `json:"currency" db:"currency"` // empty when the project has no contract currency
+	ContractType        string    `json:"contract_type" db:"contract_type"`
+	Status              string    `json:"status" db:"status"`
+	OwnershipPercentage float64   `json:"ownership_percentage" db:"profit_sharing_percentage"` // percentage of the project's funding
+	ProjectFunding      Money     `json:"project_funding" db:"current_funding"`
+}
+
+// WithdrawalTerms are the notice and penalty an early exit is held to
+type WithdrawalTerms struct {
+	NoticeDays int     `json:"notice_days"`
+	Penalty    float64 `json:"penalty"` // fraction of the redemption value, e.g. 0.02 for 2%
+}
+
+// InvestmentWithdrawal is an investor's request to take capital out of a
+// project before it completes. The withdrawal takes effect once the notice
+// period has passed and the cooperative has approved it; the investor is then
+// refunded the redemption value less the early withdrawal penalty.
+type InvestmentWithdrawal struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	InvestmentID  uuid.UUID `json:"investment_id" db:"investment_id"`
+	InvestorID    uuid.UUID `json:"investor_id" db:"investor_id"`
+	ProjectID     uuid.UUID `json:"project_id" db:"project_id"`
+	CooperativeID uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	Currency      string    `json:"currency" db:"currency"`
+
+	// Capital leaves the project at its principal; the investor bears their
+	// share of unrecovered losses on it, so the redemption value is never
+	// guaranteed to return the full principal
+	InvestedAmount  Money   `json:"invested_amount" db:"invested_amount"`
+	WithdrawnAmount Money   `json:"withdrawn_amount" db:"withdrawn_amount"`
+	UnrecoveredLoss Money   `json:"unrecovered_loss" db:"unrecovered_loss"`
+	RedemptionValue Money   `json:"redemption_value" db:"redemption_value"`
+	Penalty         float64 `json:"penalty" db:"penalty"` // fraction of the redemption value
+	PenaltyAmount   Money   `json:"penalty_amount" db:"penalty_amount"`
+	NetAmount       Money   `json:"net_amount" db:"net_amount"`
+
+	NoticeDays  int       `json:"notice_days" db:"notice_days"`
+	RequestedAt time.Time `json:"requested_at" db:"requested_at"`
+	ExitDate    time.Time `json:"exit_date" db:"exit_date"` // no earlier than the end of the notice period
+	Reason      string    `json:"reason" db:"reason"`
+	BankAccount string    `json:"bank_account" db:"bank_account"`
+
+	Status      string     `json:"status" db:"status"` // requested, approved, rejected, cancelled, completed
+	ReviewedBy  *uuid.UUID `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt  *time.Time `json:"reviewed_at" db:"reviewed_at"`
+	ReviewNotes string     `json:"review_notes" db:"review_notes"`
+
+	FundRefundID    *uuid.UUID `json:"fund_refund_id" db:"fund_refund_id"`
+	OwnershipBefore float64    `json:"ownership_before" db:"ownership_before"` // percentage of the project's funding
+	OwnershipAfter  float64    `json:"ownership_after" db:"ownership_after"`
+	CompletedBy     *uuid.UUID `json:"completed_by" db:"completed_by"`
+	CompletedAt     *time.Time `json:"completed_at" db:"completed_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsFullExit reports whether the withdrawal takes out all of the investment
+func (w *InvestmentWithdrawal) IsFullExit() bool {
+	return w.WithdrawnAmount.Equal(w.InvestedAmount)
+}
+
+// IsOpen reports whether the withdrawal is still awaiting a decision or completion
+func (w *InvestmentWithdrawal) IsOpen() bool {
+	return w.Status == InvestmentWithdrawalStatusRequested || w.Status == InvestmentWithdrawalStatusApproved
+}
+
+// CreateInvestmentWithdrawalRequest asks to withdraw some or all of an investment
+type CreateInvestmentWithdrawalRequest struct {
+	ProjectID   uuid.UUID  `json:"project_id" validate:"required"`
+	Amount      *Money     `json:"amount"`    // defaults to the whole investment
+	ExitDate    *time.Time `json:"exit_date"` // defaults to the end of the notice period
+	Reason      string     `json:"reason" validate:"required,max=1000"`
+	BankAccount string     `json:"bank_account" validate:"required,max=50"`
+}
+
+// ReviewInvestmentWithdrawalRequest records the cooperative's decision on a withdrawal
+type ReviewInvestmentWithdrawalRequest struct {
+	Approve bool   `json:"approve"`
+	Notes   string `json:"notes" validate:"max=1000"`
+}
+
+// Investment withdrawal constants
+const (
+	InvestmentWithdrawalStatusRequested  = "requested"
+	InvestmentWithdrawalStatusApproved   = "approved"
+	InvestmentWithdrawalStatusCompleting = "completing" // exited, refund being posted and queued
+	InvestmentWithdrawalStatusRejected   = "rejected"
+	InvestmentWithdrawalStatusCancelled  = "cancelled"
+	InvestmentWithdrawalStatusCompleted  = "completed"
+)
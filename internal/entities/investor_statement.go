@@ -0,0 +1,125 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvestorStatement is an investor's account statement with a cooperative for a
+// month, quarter or year. It is built from the investor's ledger account and
+// archived with its PDF and CSV renderings.
+type InvestorStatement struct {
+	ID              uuid.UUID                `json:"id" db:"id"`
+	InvestorID      uuid.UUID                `json:"investor_id" db:"investor_id"`
+	CooperativeID   uuid.UUID                `json:"cooperative_id" db:"cooperative_id"`
+	CooperativeName string                   `json:"cooperative_name" db:"cooperative_name"`
+	Currency        string                   `json:"currency" db:"currency"`
+	PeriodType      string                   `json:"period_type" db:"period_type"` // monthly, quarterly, yearly
+	PeriodStart     time.Time                `json:"period_start" db:"period_start"`
+	PeriodEnd       time.Time                `json:"period_end" db:"period_end"` // exclusive
+	OpeningBalance  Money                    `json:"opening_balance" db:"opening_balance"`
+	Investments     Money                    `json:"investments" db:"investments"`
+	Distributions   Money                    `json:"distributions" db:"distributions"` // net of tax withheld
+	TaxWithheld     Money                    `json:"tax_withheld" db:"tax_withheld"`
+	Fees            Money                    `json:"fees" db:"fees"` // deducted from refunds
+	Refunds         Money                    `json:"refunds" db:"refunds"`
+	Transfers       Money                    `json:"transfers" db:"transfers"` // net stakes bought less sold
+	Adjustments     Money                    `json:"adjustments" db:"adjustments"`
+	ClosingBalance  Money                    `json:"closing_balance" db:"closing_balance"`
+	Lines           []InvestorStatementLine  `json:"lines,omitempty" db:"lines"`
+	Files           []*InvestorStatementFile `json:"-"`
+	GeneratedAt     time.Time                `json:"generated_at" db:"generated_at"`
+}
+
+// InvestorStatementLine is one movement on the investor's account. Amount is
+// positive when it increases the investor's position.
+type InvestorStatementLine struct {
+	PostedAt    time.Time  `json:"posted_at"`
+	EntryNumber string     `json:"entry_number"`
+	EntryType   string     `json:"entry_type"`
+	Description string     `json:"description"`
+	ProjectID   *uuid.UUID `json:"project_id,omitempty"`
+	Amount      Money      `json:"amount"`
+	TaxWithheld Money      `json:"tax_withheld"`
+	Fee         Money      `json:"fee"`
+	Balance     Money      `json:"balance"`
+}
+
+// InvestorStatementFile is an archived rendering of a statement
+type InvestorStatementFile struct {
+	Format      string `json:"format"` // pdf, csv
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"-"`
+}
+
+// StatementLedgerLine is a posting to an investor's ledger account together
+// with the entry totals its tax and fees are apportioned from
+type StatementLedgerLine struct {
+	PostedAt      time.Time  `json:"posted_at" db:"posted_at"`
+	EntryNumber   string     `json:"entry_number" db:"entry_number"`
+	EntryType     string     `json:"entry_type" db:"entry_type"`
+	Description   string     `json:"description" db:"description"`
+	ProjectID     *uuid.UUID `json:"project_id" db:"project_id"`
+	Debit         Money      `json:"debit" db:"debit"`
+	Credit        Money      `json:"credit" db:"credit"`
+	EntryTax      Money      `json:"entry_tax" db:"entry_tax"`           // credited to tax payable by the entry
+	EntryFees     Money      `json:"entry_fees" db:"entry_fees"`         // credited to platform fees by the entry
+	EntryInvestor Money      `json:"entry_investor" db:"entry_investor"` // posted to all investors by the entry
+}
+
+// StatementBranding is how a cooperative presents itself on statements
+type StatementBranding struct {
+	CooperativeName string `json:"cooperative_name"`
+	LogoURL         string `json:"logo_url"`
+}
+
+// InvestorStatementRun summarises a statement generation run
+type InvestorStatementRun struct {
+	PeriodType  string                     `json:"period_type"`
+	PeriodStart time.Time                  `json:"period_start"`
+	Generated   int                        `json:"generated"`
+	Skipped     int                        `json:"skipped"` // already archived or without activity
+	Failures    []InvestorStatementFailure `json:"failures"`
+}
+
+// InvestorStatementFailure records a statement that could not be generated
+type InvestorStatementFailure struct {
+	InvestorID    uuid.UUID `json:"investor_id"`
+	CooperativeID uuid.UUID `json:"cooperative_id"`
+	Currency      string    `json:"currency"`
+	Error         string    `json:"error"`
+}
+
+// GenerateInvestorStatementsRequest generates, or backfills, the statements of a period
+type GenerateInvestorStatementsRequest struct {
+	PeriodType  string    `json:"period_type" validate:"required,oneof=monthly quarterly yearly"`
+	PeriodStart time.Time `json:"period_start" validate:"required"`
+}
+
+// StatementPeriod returns the month, quarter or year containing t
+func StatementPeriod(periodType string, t time.Time) (time.Time, time.Time) {
+	year, month, _ := t.Date()
+	switch periodType {
+	case StatementPeriodYearly:
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(1, 0, 0)
+	case StatementPeriodQuarterly:
+		start := time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 3, 0)
+	default:
+		start := time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 1, 0)
+	}
+}
+
+// Investor statement constants
+const (
+	StatementPeriodMonthly   = "monthly"
+	StatementPeriodQuarterly = "quarterly"
+	StatementPeriodYearly    = "yearly"
+
+	StatementFormatPDF = "pdf"
+	StatementFormatCSV = "csv"
+)
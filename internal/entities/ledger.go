@@ -0,0 +1,155 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LedgerAccount represents an account in a cooperative's double-entry general ledger
+type LedgerAccount struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	CooperativeID uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
+	Code          string     `json:"code" db:"code"`
+	Name          string     `json:"name" db:"name"`
+	AccountType   string     `json:"account_type" db:"account_type"` // asset, liability, equity, income, expense
+	Category      string     `json:"category" db:"category"`         // escrow, investor, business, platform_fee, tax_payable
+	OwnerID       *uuid.UUID `json:"owner_id" db:"owner_id"`         // investor or business the account is held for
+	Currency      string     `json:"currency" db:"currency"`
+	IsActive      bool       `json:"is_active" db:"is_active"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// JournalEntry represents a balanced set of debits and credits posted to the ledger
+type JournalEntry struct {
+	ID            uuid.UUID     `json:"id" db:"id"`
+	EntryNumber   string        `json:"entry_number" db:"entry_number"`
+	CooperativeID uuid.UUID     `json:"cooperative_id" db:"cooperative_id"`
+	ProjectID     *uuid.UUID    `json:"project_id" db:"project_id"`
+	EntryType     string        `json:"entry_type" db:"entry_type"` // investment, disbursement, refund, profit_distribution, fee_collection, stake_transfer, adjustment
+	ReferenceType string        `json:"reference_type" db:"reference_type"`
+	ReferenceID   uuid.UUID     `json:"reference_id" db:"reference_id"`
+	Description   string        `json:"description" db:"description"`
+	Currency      string        `json:"currency" db:"currency"`
+	TotalAmount   Money         `json:"total_amount" db:"total_amount"`
+	PostedBy      uuid.UUID     `json:"posted_by" db:"posted_by"`
+	PostedAt      time.Time     `json:"posted_at" db:"posted_at"`
+	Lines         []JournalLine `json:"lines" db:"-"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+}
+
+// JournalLine represents a single debit or credit within a journal entry
+type JournalLine struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	EntryID   uuid.UUID `json:"entry_id" db:"entry_id"`
+	AccountID uuid.UUID `json:"account_id" db:"account_id"`
+	Debit     Money     `json:"debit" db:"debit"`
+	Credit    Money     `json:"credit" db:"credit"`
+	Memo      string    `json:"memo" db:"memo"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// LedgerAccountBalance is an account balance derived from its posted journal lines
+type LedgerAccountBalance struct {
+	AccountID    uuid.UUID `json:"account_id"`
+	Code         string    `json:"code"`
+	Name         string    `json:"name"`
+	AccountType  string    `json:"account_type"`
+	Category     string    `json:"category"`
+	Currency     string    `json:"currency"`
+	TotalDebits  Money     `json:"total_debits"`
+	TotalCredits Money     `json:"total_credits"`
+	Balance      Money     `json:"balance"` // signed by the account's normal balance side
+	AsOf         time.Time `json:"as_of"`
+}
+
+// TrialBalance lists every account balance of a cooperative ledger
+type TrialBalance struct {
+	CooperativeID uuid.UUID               `json:"cooperative_id"`
+	Accounts      []*LedgerAccountBalance `json:"accounts"`
+	TotalDebits   Money                   `json:"total_debits"`  // only set when the ledger has a single currency
+	TotalCredits  Money                   `json:"total_credits"` // only set when the ledger has a single currency
+	ByCurrency    []*TrialBalanceTotals   `json:"by_currency"`
+	IsBalanced    bool                    `json:"is_balanced"`
+	GeneratedAt   time.Time               `json:"generated_at"`
+}
+
+// TrialBalanceTotals are the trial balance totals of one ledger currency
+type TrialBalanceTotals struct {
+	Currency     string `json:"currency"`
+	TotalDebits  Money  `json:"total_debits"`
+	TotalCredits Money  `json:"total_credits"`
+	IsBalanced   bool   `json:"is_balanced"`
+}
+
+// LedgerCurrencyTotals are the debits and credits posted in one currency
+type LedgerCurrencyTotals struct {
+	Currency     string `json:"currency"`
+	TotalDebits  Money  `json:"total_debits"`
+	TotalCredits Money  `json:"total_credits"`
+}
+
+// PostJournalEntryRequest for posting a manual journal entry
+type PostJournalEntryRequest struct {
+	CooperativeID uuid.UUID                `json:"cooperative_id" validate:"required"`
+	ProjectID     *uuid.UUID               `json:"project_id"`
+	EntryType     string                   `json:"entry_type" validate:"required,oneof=investment disbursement refund profit_distribution fee_collection stake_transfer adjustment"`
+	ReferenceType string                   `json:"reference_type" validate:"required"`
+	ReferenceID   uuid.UUID                `json:"reference_id" validate:"required"`
+	Description   string                   `json:"description" validate:"required,max=500"`
+	Currency      string                   `json:"currency" validate:"required,len=3"`
+	Lines         []PostJournalLineRequest `json:"lines" validate:"required,min=2,dive"`
+}
+
+// PostJournalLineRequest for a single line of a journal entry
+type PostJournalLineRequest struct {
+	AccountID uuid.UUID `json:"account_id" validate:"required"`
+	Debit     Money     `json:"debit" validate:"min=0"`
+	Credit    Money     `json:"credit" validate:"min=0"`
+	Memo      string    `json:"memo" validate:"max=255"`
+}
+
+// Ledger constants
+const (
+	LedgerAccountTypeAsset     = "asset"
+	LedgerAccountTypeLiability = "liability"
+	LedgerAccountTypeEquity    = "equity"
+	LedgerAccountTypeIncome    = "income"
+	LedgerAccountTypeExpense   = "expense"
+
+	LedgerAccountCategoryEscrow      = "escrow"
+	LedgerAccountCategoryInvestor    = "investor"
+	LedgerAccountCategoryBusiness    = "business"
+	LedgerAccountCategoryPlatformFee = "platform_fee"
+	LedgerAccountCategoryTaxPayable  = "tax_payable"
+	// Payouts the bank returned, owed to their beneficiaries until paid again
+	LedgerAccountCategoryPayoutsPending = "payouts_pending"
+
+	JournalEntryTypeInvestment         = "investment"
+	JournalEntryTypeDisbursement       = "disbursement"
+	JournalEntryTypeRefund             = "refund"
+	JournalEntryTypeProfitDistribution = "profit_distribution"
+	JournalEntryTypeFeeCollection      = "fee_collection"
+	JournalEntryTypeStakeTransfer      = "stake_transfer"
+	JournalEntryTypeAdjustment         = "adjustment"
+)
+
+// LedgerAccountTypeForCategory returns the account type used for a ledger account category
+func LedgerAccountTypeForCategory(category string) string {
+	switch category {
+	case LedgerAccountCategoryEscrow, LedgerAccountCategoryBusiness:
+		return LedgerAccountTypeAsset
+	case LedgerAccountCategoryInvestor, LedgerAccountCategoryTaxPayable, LedgerAccountCategoryPayoutsPending:
+		return LedgerAccountTypeLiability
+	case LedgerAccountCategoryPlatformFee:
+		return LedgerAccountTypeIncome
+	default:
+		return ""
+	}
+}
+
+// IsDebitNormal reports whether an account type increases with debits
+func IsDebitNormal(accountType string) bool {
+	return accountType == LedgerAccountTypeAsset || accountType == LedgerAccountTypeExpense
+}
@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectLoss is a project's net loss for one profit calculation period (FR-052).
+// Under mudarabah the capital providers bear a loss in proportion to their capital
+// unless the cooperative absorbs it; a carried-forward loss is recovered from the
+// project's later profits before any profit is shared.
+type ProjectLoss struct {
+	ID                  uuid.UUID `json:"id" db:"id"`
+	ProjectID           uuid.UUID `json:"project_id" db:"project_id"`
+	CooperativeID       uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	ProfitCalculationID uuid.UUID `json:"profit_calculation_id" db:"profit_calculation_id"`
+	PeriodStart         time.Time `json:"period_start" db:"period_start"`
+	PeriodEnd           time.Time `json:"period_end" db:"period_end"`
+	LossHandlingMethod  string    `json:"loss_handling_method" db:"loss_handling_method"` // carry_forward, shared, absorb
+	BorneBy             string    `json:"borne_by" db:"borne_by"`                         // investors, cooperative
+	LossAmount          Money     `json:"loss_amount" db:"loss_amount"`
+	RecoveredAmount     Money     `json:"recovered_amount" db:"recovered_amount"` // offset by later profits
+	Currency            string    `json:"currency" db:"currency"`
+	Status              string    `json:"status" db:"status"` // carried_forward, recovered, written_off, absorbed
+	RecordedBy          uuid.UUID `json:"recorded_by" db:"recorded_by"`
+	CreatedAt           time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at" db:"updated_at"`
+
+	Shares []*InvestorLossShare `json:"shares,omitempty" db:"-"`
+}
+
+// OutstandingAmount returns the part of a carried-forward loss not yet recovered
+func (l *ProjectLoss) OutstandingAmount() Money {
+	if l.Status != ProjectLossStatusCarriedForward {
+		return ZeroMoney(l.Currency)
+	}
+	return l.LossAmount.Sub(l.RecoveredAmount)
+}
+
+// InvestorLossShare is an investor's part of a project loss
+type InvestorLossShare struct {
+	ID                   uuid.UUID `json:"id" db:"id"`
+	ProjectLossID        uuid.UUID `json:"project_loss_id" db:"project_loss_id"`
+	ProjectID            uuid.UUID `json:"project_id" db:"project_id"`
+	InvestmentID         uuid.UUID `json:"investment_id" db:"investment_id"`
+	InvestorID           uuid.UUID `json:"investor_id" db:"investor_id"`
+	PeriodStart          time.Time `json:"period_start" db:"period_start"`
+	PeriodEnd            time.Time `json:"period_end" db:"period_end"`
+	OriginalInvestment   Money     `json:"original_investment" db:"original_investment"`
+	InvestmentPercentage float64   `json:"investment_percentage" db:"investment_percentage"` // percentage of total project investment
+	LossAmount           Money     `json:"loss_amount" db:"loss_amount"`
+	RecoveredAmount      Money     `json:"recovered_amount" db:"recovered_amount"`
+	Currency             string    `json:"currency" db:"currency"`
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ProjectLossRecovery records a period's profit offsetting a carried-forward loss
+type ProjectLossRecovery struct {
+	ID                  uuid.UUID `json:"id" db:"id"`
+	ProjectLossID       uuid.UUID `json:"project_loss_id" db:"project_loss_id"`
+	ProjectID           uuid.UUID `json:"project_id" db:"project_id"`
+	ProfitCalculationID uuid.UUID `json:"profit_calculation_id" db:"profit_calculation_id"`
+	Amount              Money     `json:"amount" db:"amount"`
+	Currency            string    `json:"currency" db:"currency"`
+	RecordedBy          uuid.UUID `json:"recorded_by" db:"recorded_by"`
+	CreatedAt           time.Time `json:"created_at" db:"created_at"`
+}
+
+// ProjectLossHistory is a project's period-over-period loss record
+type ProjectLossHistory struct {
+	ProjectID      uuid.UUID              `json:"project_id"`
+	Currency       string                 `json:"currency"`
+	TotalLoss      Money                  `json:"total_loss"`
+	TotalRecovered Money                  `json:"total_recovered"`
+	CarriedForward Money                  `json:"carried_forward"` // still to be offset against future profits
+	Losses         []*ProjectLoss         `json:"losses"`
+	Recoveries     []*ProjectLossRecovery `json:"recoveries"`
+}
+
+// Loss sharing constants
+const (
+	LossHandlingCarryForward = "carry_forward"
+	LossHandlingShared       = "shared"
+	LossHandlingAbsorb       = "absorb"
+
+	LossBorneByInvestors   = "investors"
+	LossBorneByCooperative = "cooperative"
+
+	ProjectLossStatusCarriedForward = "carried_forward"
+	ProjectLossStatusRecovered      = "recovered"
+	ProjectLossStatusWrittenOff     = "written_off"
+	ProjectLossStatusAbsorbed       = "absorbed"
+)
@@ -0,0 +1,599 @@
+package entities
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RoundingMode controls how a value is rounded to a currency's minor unit
+type RoundingMode int
+
+const (
+	RoundHalfUp   RoundingMode = iota // 0.5 rounds away from zero
+	RoundHalfEven                     // 0.5 rounds to the nearest even unit (banker's rounding)
+	RoundDown                         // truncate toward zero
+	RoundUp                           // round away from zero
+)
+
+// ErrCurrencyMismatch is returned when amounts in different currencies are combined
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// ErrCurrencyRequired is returned when an amount needs a currency to be exact
+var ErrCurrencyRequired = errors.New("money: currency is required")
+
+// DefaultMinorUnits is used for currencies that are not known to the platform
+const DefaultMinorUnits = 2
+
+// MaxMinorUnits is the most decimal places a registered currency can have;
+// money columns are NUMERIC(20,4) to hold them
+const MaxMinorUnits = 4
+
+// maxUnlabelledScale bounds the precision of an amount read without a currency
+const maxUnlabelledScale = 18
+
+// currencyMinorUnits holds the ISO 4217 minor unit digits of supported currencies
+var currencyMinorUnits = map[string]int{
+	"IDR": 2,
+	"MYR": 2,
+	"SGD": 2,
+	"USD": 2,
+	"EUR": 2,
+	"SAR": 2,
+	"AED": 2,
+	"BND": 2,
+	"JPY": 0,
+	"KWD": 3,
+	"BHD": 3,
+}
+
+var currencyMinorUnitsMu sync.RWMutex
+
+// CurrencyMinorUnits returns the number of decimal places of a currency's minor unit
+func CurrencyMinorUnits(currency string) int {
+	currencyMinorUnitsMu.RLock()
+	defer currencyMinorUnitsMu.RUnlock()
+
+	if units, ok := currencyMinorUnits[strings.ToUpper(currency)]; ok {
+		return units
+	}
+	return DefaultMinorUnits
+}
+
+// RegisterCurrencyMinorUnits adds a currency from the registry. It must be called
+// before amounts in that currency are created, normally while loading the registry
+// at startup, since existing values are not rescaled.
+func RegisterCurrencyMinorUnits(currency string, minorUnits int) {
+	currencyMinorUnitsMu.Lock()
+	defer currencyMinorUnitsMu.Unlock()
+
+	currencyMinorUnits[strings.ToUpper(currency)] = minorUnits
+}
+
+// Money is an exact monetary amount held as an integer number of minor units
+// (e.g. sen for MYR) together with its ISO 4217 currency code. Money values are
+// immutable; arithmetic returns new values and never goes through float64.
+//
+// An amount read without a currency, from a DECIMAL column or a bare JSON
+// number, keeps the exact precision it was written with until WithCurrency or
+// InCurrency gives it one; its scale is never assumed.
+type Money struct {
+	units    int64
+	scale    int
+	currency string
+}
+
+// NewMoney creates an amount from a number of minor units. Minor units mean
+// nothing without a currency, so only a zero amount can be created without one.
+func NewMoney(minorUnits int64, currency string) Money {
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		if minorUnits != 0 {
+			panic(ErrCurrencyRequired)
+		}
+		return Money{}
+	}
+	return Money{units: minorUnits, scale: CurrencyMinorUnits(currency), currency: currency}
+}
+
+// ZeroMoney returns a zero amount in a currency
+func ZeroMoney(currency string) Money {
+	return NewMoney(0, currency)
+}
+
+// ParseMoney parses a decimal string such as "1500.25", rounding half up to the currency's minor unit
+func ParseMoney(amount, currency string) (Money, error) {
+	return ParseMoneyWithMode(amount, currency, RoundHalfUp)
+}
+
+// ParseMoneyWithMode parses a decimal string using an explicit rounding mode
+func ParseMoneyWithMode(amount, currency string, mode RoundingMode) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok {
+		return Money{}, fmt.Errorf("invalid money amount: %q", amount)
+	}
+
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		return Money{}, fmt.Errorf("%w for %q", ErrCurrencyRequired, amount)
+	}
+	scale := CurrencyMinorUnits(currency)
+	units, err := ratToMinorUnits(r, scale, mode)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{units: units, scale: scale, currency: currency}, nil
+}
+
+// parseUnlabelledMoney parses a decimal string read without a currency at
+// exactly the precision it was written with
+func parseUnlabelledMoney(amount string) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok {
+		return Money{}, fmt.Errorf("invalid money amount: %q", amount)
+	}
+
+	for scale := 0; scale <= maxUnlabelledScale; scale++ {
+		scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(scale)))
+		if !scaled.IsInt() {
+			continue
+		}
+		if !scaled.Num().IsInt64() {
+			return Money{}, fmt.Errorf("money amount out of range")
+		}
+		return Money{units: scaled.Num().Int64(), scale: scale}, nil
+	}
+	return Money{}, fmt.Errorf("money amount %q has too many decimal places", amount)
+}
+
+// MustParseMoney is like ParseMoney but panics on invalid input; intended for constants and tests
+func MustParseMoney(amount, currency string) Money {
+	m, err := ParseMoney(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// NewMoneyFromFloat converts a float amount using its shortest decimal
+// representation, so 0.1 becomes exactly 10 minor units. It exists for legacy
+// inputs only; new code should work in Money throughout.
+func NewMoneyFromFloat(amount float64, currency string) Money {
+	m, err := ParseMoney(strconv.FormatFloat(amount, 'f', -1, 64), currency)
+	if err != nil {
+		return ZeroMoney(currency)
+	}
+	return m
+}
+
+// MinorUnits returns the amount as an integer number of minor units of its
+// currency, or of its own precision for an amount without a currency
+func (m Money) MinorUnits() int64 {
+	return m.units
+}
+
+// Currency returns the ISO 4217 currency code
+func (m Money) Currency() string {
+	return m.currency
+}
+
+// Scale returns the number of decimal places the amount is held to: its
+// currency's minor unit, or for an amount without a currency the precision it
+// was read with
+func (m Money) Scale() int {
+	return m.scale
+}
+
+// WithCurrency returns the amount in another currency code without conversion,
+// rescaling the minor units if the currencies have a different number of decimals.
+// It is used to attach a currency to amounts read without one; amounts from a
+// request go through InCurrency so that a conflicting currency is rejected.
+func (m Money) WithCurrency(currency string) Money {
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		return m
+	}
+
+	rescaled := m.rescale(CurrencyMinorUnits(currency))
+	rescaled.currency = currency
+	return rescaled
+}
+
+// InCurrency returns the amount in currency, attaching it to an amount received
+// without a currency. An amount already in another currency is not relabelled;
+// it returns ErrCurrencyMismatch. With no currency to attach, an amount that
+// has none returns ErrCurrencyRequired.
+func (m Money) InCurrency(currency string) (Money, error) {
+	if currency == "" {
+		if m.currency == "" && !m.IsZero() {
+			return Money{}, ErrCurrencyRequired
+		}
+		return m, nil
+	}
+	if err := m.CheckCurrency(currency); err != nil {
+		return Money{}, err
+	}
+	return m.WithCurrency(currency), nil
+}
+
+// CheckCurrency returns ErrCurrencyMismatch unless the amount is in currency or
+// has no currency yet
+func (m Money) CheckCurrency(currency string) error {
+	currency = strings.ToUpper(currency)
+	if m.currency != "" && m.currency != currency {
+		return fmt.Errorf("%w: %s amount where %s was expected", ErrCurrencyMismatch, m.currency, currency)
+	}
+	return nil
+}
+
+// CheckSameCurrency returns ErrCurrencyMismatch unless the amounts that have a
+// currency all have the same one
+func CheckSameCurrency(amounts ...Money) error {
+	currency := ""
+	for _, amount := range amounts {
+		if currency == "" {
+			currency = amount.currency
+			continue
+		}
+		if err := amount.CheckCurrency(currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Convert returns the amount converted into another currency at rate, the number
+// of target currency units per unit of m's currency, rounded to the target's minor unit
+func (m Money) Convert(rate *big.Rat, currency string, mode RoundingMode) Money {
+	r := m.rat()
+	r.Mul(r, rate)
+
+	currency = strings.ToUpper(currency)
+	scale := CurrencyMinorUnits(currency)
+	units, _ := ratToMinorUnits(r, scale, mode)
+	return Money{units: units, scale: scale, currency: currency}
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool { return m.units == 0 }
+
+// IsPositive reports whether the amount is greater than zero
+func (m Money) IsPositive() bool { return m.units > 0 }
+
+// IsNegative reports whether the amount is less than zero
+func (m Money) IsNegative() bool { return m.units < 0 }
+
+// Sign returns -1, 0 or +1
+func (m Money) Sign() int {
+	switch {
+	case m.units < 0:
+		return -1
+	case m.units > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Add returns m + other. Amounts without a currency adopt the other operand's
+// currency. Adding two different currencies panics with ErrCurrencyMismatch, so
+// amounts from different sources are checked with CheckCurrency first.
+func (m Money) Add(other Money) Money {
+	a, b := m.align(other)
+	return Money{units: a.units + b.units, scale: a.scale, currency: a.currency}
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) Money {
+	a, b := m.align(other)
+	return Money{units: a.units - b.units, scale: a.scale, currency: a.currency}
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{units: -m.units, scale: m.scale, currency: m.currency}
+}
+
+// Abs returns |m|
+func (m Money) Abs() Money {
+	if m.units < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// MulInt returns m multiplied by an integer
+func (m Money) MulInt(n int64) Money {
+	return Money{units: m.units * n, scale: m.scale, currency: m.currency}
+}
+
+// Mul returns m multiplied by a decimal factor, rounded to the minor unit
+func (m Money) Mul(factor float64, mode RoundingMode) Money {
+	return m.MulRat(floatToRat(factor), mode)
+}
+
+// MulRat returns m multiplied by an exact rational factor, rounded to the minor unit
+func (m Money) MulRat(factor *big.Rat, mode RoundingMode) Money {
+	r := new(big.Rat).SetInt64(m.units)
+	r.Mul(r, factor)
+	units, _ := ratToMinorUnits(r, 0, mode)
+	return Money{units: units, scale: m.scale, currency: m.currency}
+}
+
+// Percent returns pct percent of m, rounded to the minor unit
+func (m Money) Percent(pct float64, mode RoundingMode) Money {
+	r := floatToRat(pct)
+	r.Quo(r, big.NewRat(100, 1))
+	return m.MulRat(r, mode)
+}
+
+// Ratio returns m / total as an exact rational number; it returns zero if total is zero
+func (m Money) Ratio(total Money) *big.Rat {
+	if total.units == 0 {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Quo(m.rat(), total.rat())
+}
+
+// Cmp compares two amounts of the same currency and returns -1, 0 or +1
+func (m Money) Cmp(other Money) int {
+	m, other = m.align(other)
+	switch {
+	case m.units < other.units:
+		return -1
+	case m.units > other.units:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Equal reports whether two amounts are equal
+func (m Money) Equal(other Money) bool { return m.Cmp(other) == 0 }
+
+// GreaterThan reports whether m > other
+func (m Money) GreaterThan(other Money) bool { return m.Cmp(other) > 0 }
+
+// GreaterThanOrEqual reports whether m >= other
+func (m Money) GreaterThanOrEqual(other Money) bool { return m.Cmp(other) >= 0 }
+
+// LessThan reports whether m < other
+func (m Money) LessThan(other Money) bool { return m.Cmp(other) < 0 }
+
+// LessThanOrEqual reports whether m <= other
+func (m Money) LessThanOrEqual(other Money) bool { return m.Cmp(other) <= 0 }
+
+// Min returns the smaller of two amounts
+func (m Money) Min(other Money) Money {
+	if m.LessThan(other) {
+		return m
+	}
+	return other
+}
+
+// Max returns the larger of two amounts
+func (m Money) Max(other Money) Money {
+	if m.GreaterThan(other) {
+		return m
+	}
+	return other
+}
+
+// Float64 returns an approximate float value, for display, ratios and statistics only
+func (m Money) Float64() float64 {
+	f, _ := m.rat().Float64()
+	return f
+}
+
+// Decimal returns the amount as an exact decimal string, e.g. "1500.25"
+func (m Money) Decimal() string {
+	return m.rat().FloatString(m.scale)
+}
+
+// String returns the amount with its currency, e.g. "1500.25 MYR"
+func (m Money) String() string {
+	if m.currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.currency
+}
+
+// moneyJSON is the wire format of Money
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as {"amount":"1500.25","currency":"MYR"}; the amount
+// is a string so that clients never parse it into a binary float.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{Amount: m.Decimal(), Currency: m.currency})
+}
+
+// UnmarshalJSON accepts the object form as well as a bare number or decimal string;
+// the latter take the currency from the enclosing request.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*m = Money{}
+		return nil
+	}
+
+	currency := m.currency
+	raw := data
+	if len(data) > 0 && data[0] == '{' {
+		var obj moneyJSON
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return fmt.Errorf("invalid money: %w", err)
+		}
+		if obj.Currency != "" {
+			currency = obj.Currency
+		}
+		raw = obj.Amount
+	}
+
+	amount := strings.Trim(string(raw), `"`)
+	if amount == "" {
+		*m = ZeroMoney(currency)
+		return nil
+	}
+
+	parsed, err := parseMoneyIn(amount, currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value implements driver.Valuer; amounts are stored as exact DECIMAL strings
+func (m Money) Value() (driver.Value, error) {
+	return m.Decimal(), nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns. The currency, if already set
+// on the destination, determines the minor unit; otherwise the amount keeps the
+// column's exact value until a currency is attached.
+func (m *Money) Scan(src interface{}) error {
+	var amount string
+	switch v := src.(type) {
+	case nil:
+		*m = ZeroMoney(m.currency)
+		return nil
+	case []byte:
+		amount = string(v)
+	case string:
+		amount = v
+	case int64:
+		amount = strconv.FormatInt(v, 10)
+	case float64:
+		amount = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+
+	parsed, err := parseMoneyIn(amount, m.currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// parseMoneyIn parses an amount in currency, or exactly as written if the
+// currency is not known yet
+func parseMoneyIn(amount, currency string) (Money, error) {
+	if currency == "" {
+		return parseUnlabelledMoney(amount)
+	}
+	return ParseMoney(amount, currency)
+}
+
+// SumMoney adds up amounts of the same currency
+func SumMoney(amounts ...Money) Money {
+	var total Money
+	for _, amount := range amounts {
+		total = total.Add(amount)
+	}
+	return total
+}
+
+// align brings two amounts to a common currency and scale. An amount without a
+// currency takes the other's; two amounts without one take the finer scale.
+// Amounts in different currencies panic with ErrCurrencyMismatch.
+func (m Money) align(other Money) (Money, Money) {
+	switch {
+	case m.currency == other.currency:
+	case m.currency == "":
+		m = m.WithCurrency(other.currency)
+	case other.currency == "":
+		other = other.WithCurrency(m.currency)
+	default:
+		panic(fmt.Errorf("money: %w between %s and %s", ErrCurrencyMismatch, m.currency, other.currency))
+	}
+
+	if m.scale < other.scale {
+		m = m.rescale(other.scale)
+	} else if other.scale < m.scale {
+		other = other.rescale(m.scale)
+	}
+	return m, other
+}
+
+// rescale returns the amount held to scale decimal places, rounding half up
+// when the scale is reduced
+func (m Money) rescale(scale int) Money {
+	if m.scale == scale {
+		return Money{units: m.units, scale: scale, currency: m.currency}
+	}
+	units, _ := ratToMinorUnits(m.rat(), scale, RoundHalfUp)
+	return Money{units: units, scale: scale, currency: m.currency}
+}
+
+// rat returns the exact value of the amount
+func (m Money) rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(m.units), pow10(m.scale))
+}
+
+// ratToMinorUnits scales r by 10^scale and rounds it to an integer
+func ratToMinorUnits(r *big.Rat, scale int, mode RoundingMode) (int64, error) {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(scale)))
+
+	num := scaled.Num()
+	den := scaled.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	if rem.Sign() != 0 {
+		// Compare twice the remainder with the denominator to find the half point
+		twiceRem := new(big.Int).Abs(rem)
+		twiceRem.Lsh(twiceRem, 1)
+		half := twiceRem.Cmp(den)
+		awayFromZero := false
+
+		switch mode {
+		case RoundHalfUp:
+			awayFromZero = half >= 0
+		case RoundHalfEven:
+			awayFromZero = half > 0 || (half == 0 && quo.Bit(0) == 1)
+		case RoundUp:
+			awayFromZero = true
+		case RoundDown:
+			awayFromZero = false
+		}
+
+		if awayFromZero {
+			if num.Sign() < 0 {
+				quo.Sub(quo, big.NewInt(1))
+			} else {
+				quo.Add(quo, big.NewInt(1))
+			}
+		}
+	}
+
+	if !quo.IsInt64() {
+		return 0, fmt.Errorf("money amount out of range")
+	}
+	return quo.Int64(), nil
+}
+
+func floatToRat(f float64) *big.Rat {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,199 @@
+package entities
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func unlabelled(amount string) Money {
+	m, err := parseUnlabelledMoney(amount)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency string
+		expected int64
+		wantErr  bool
+	}{
+		{"whole amount", "1500", "IDR", 150000, false},
+		{"two decimals", "1500.25", "MYR", 150025, false},
+		{"rounds half up", "10.005", "MYR", 1001, false},
+		{"negative", "-3.10", "MYR", -310, false},
+		{"zero minor units", "1500.6", "JPY", 1501, false},
+		{"three minor units", "1.2345", "KWD", 1235, false},
+		{"invalid", "abc", "IDR", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseMoney(tt.amount, tt.currency)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, m.MinorUnits())
+			assert.Equal(t, tt.currency, m.Currency())
+		})
+	}
+}
+
+func TestMoney_RoundingModes(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		mode     RoundingMode
+		expected int64
+	}{
+		{"half up", "2.345", RoundHalfUp, 235},
+		{"half up negative", "-2.345", RoundHalfUp, -235},
+		{"half even down", "2.345", RoundHalfEven, 234},
+		{"half even up", "2.355", RoundHalfEven, 236},
+		{"half even above half", "2.3451", RoundHalfEven, 235},
+		{"down", "2.349", RoundDown, 234},
+		{"down negative", "-2.349", RoundDown, -234},
+		{"up", "2.341", RoundUp, 235},
+		{"up negative", "-2.341", RoundUp, -235},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseMoneyWithMode(tt.amount, "MYR", tt.mode)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, m.MinorUnits())
+		})
+	}
+}
+
+func TestMoney_FloatDriftAvoided(t *testing.T) {
+	// 0.1 + 0.2 != 0.3 in float64, but must be exact in Money
+	a := NewMoneyFromFloat(0.1, "MYR")
+	b := NewMoneyFromFloat(0.2, "MYR")
+
+	assert.True(t, a.Add(b).Equal(MustParseMoney("0.3", "MYR")))
+	assert.Equal(t, "0.30", a.Add(b).Decimal())
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := MustParseMoney("100.00", "IDR")
+	b := MustParseMoney("30.50", "IDR")
+
+	assert.Equal(t, "130.50", a.Add(b).Decimal())
+	assert.Equal(t, "69.50", a.Sub(b).Decimal())
+	assert.Equal(t, "-100.00", a.Neg().Decimal())
+	assert.Equal(t, "300.00", a.MulInt(3).Decimal())
+	assert.Equal(t, "2.50", a.Percent(2.5, RoundHalfUp).Decimal())
+	assert.Equal(t, "33.33", a.MulRat(big.NewRat(1, 3), RoundHalfUp).Decimal())
+	assert.True(t, a.GreaterThan(b))
+	assert.True(t, b.LessThan(a))
+	assert.Equal(t, b, a.Min(b))
+	assert.Equal(t, "130.50", SumMoney(a, b).Decimal())
+}
+
+func TestMoney_CurrencyMismatchPanics(t *testing.T) {
+	assert.Panics(t, func() {
+		MustParseMoney("1", "IDR").Add(MustParseMoney("1", "MYR"))
+	})
+}
+
+func TestMoney_InCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   Money
+		currency string
+		wantErr  bool
+	}{
+		{"same currency", MustParseMoney("10", "MYR"), "MYR", false},
+		{"no currency yet", unlabelled("10"), "myr", false},
+		{"other currency", MustParseMoney("10", "IDR"), "MYR", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := tt.amount.InCurrency(tt.currency)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrCurrencyMismatch)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "MYR", m.Currency())
+			assert.Equal(t, int64(1000), m.MinorUnits())
+		})
+	}
+
+	assert.NoError(t, CheckSameCurrency(ZeroMoney(""), MustParseMoney("1", "MYR"), MustParseMoney("2", "MYR")))
+	assert.ErrorIs(t, CheckSameCurrency(MustParseMoney("1", "MYR"), MustParseMoney("2", "IDR")), ErrCurrencyMismatch)
+}
+
+func TestMoney_JSON(t *testing.T) {
+	m := MustParseMoney("1500.25", "MYR")
+
+	data, err := json.Marshal(m)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"1500.25","currency":"MYR"}`, string(data))
+
+	var decoded Money
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, m, decoded)
+
+	// Bare numbers are accepted for request bodies and keep their precision
+	// until the request's currency is attached
+	var bare Money
+	assert.NoError(t, json.Unmarshal([]byte(`1000.125`), &bare))
+	assert.Equal(t, "", bare.Currency())
+	assert.Equal(t, "1000.125", bare.Decimal())
+	assert.Equal(t, MustParseMoney("1000.125", "KWD"), bare.WithCurrency("KWD"))
+	assert.Equal(t, MustParseMoney("1000.13", "IDR"), bare.WithCurrency("IDR"))
+}
+
+func TestMoney_SQL(t *testing.T) {
+	m := MustParseMoney("99.90", "IDR")
+
+	value, err := m.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "99.90", value)
+
+	scanned := ZeroMoney("IDR")
+	assert.NoError(t, scanned.Scan([]byte("99.90")))
+	assert.True(t, m.Equal(scanned))
+
+	assert.NoError(t, scanned.Scan(nil))
+	assert.True(t, scanned.IsZero())
+
+	// A column scanned before its currency is known loses no digits
+	var kwd Money
+	assert.NoError(t, kwd.Scan([]byte("12.3450")))
+	assert.Equal(t, MustParseMoney("12.345", "KWD"), kwd.WithCurrency("KWD"))
+
+	value, err = MustParseMoney("12.345", "KWD").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "12.345", value)
+}
+
+func TestMoney_RequiresCurrency(t *testing.T) {
+	_, err := ParseMoney("10", "")
+	assert.ErrorIs(t, err, ErrCurrencyRequired)
+
+	_, err = unlabelled("10").InCurrency("")
+	assert.ErrorIs(t, err, ErrCurrencyRequired)
+
+	assert.Equal(t, Money{}, ZeroMoney(""))
+	assert.Panics(t, func() { NewMoney(10, "") })
+}
+
+func TestMoney_Convert(t *testing.T) {
+	m := MustParseMoney("100.00", "USD")
+
+	assert.Equal(t, MustParseMoney("1600000", "IDR"), m.Convert(big.NewRat(16000, 1), "idr", RoundHalfUp))
+	assert.Equal(t, "333.33 MYR", MustParseMoney("1000", "SGD").Convert(big.NewRat(1, 3), "MYR", RoundHalfUp).String())
+	assert.Equal(t, int64(15000), MustParseMoney("100", "USD").Convert(big.NewRat(150, 1), "JPY", RoundHalfUp).MinorUnits())
+}
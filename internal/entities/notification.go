@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification is a message to a user about something that happened to their
+// projects or investments
+type Notification struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	Type       string     `json:"type" db:"type"`
+	Title      string     `json:"title" db:"title"`
+	Message    string     `json:"message" db:"message"`
+	EntityType string     `json:"entity_type" db:"entity_type"`
+	EntityID   *uuid.UUID `json:"entity_id" db:"entity_id"`
+	ReadAt     *time.Time `json:"read_at" db:"read_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Notification type constants
+const (
+	NotificationTypeProjectFunded        = "project_funded"
+	NotificationTypeProjectFundingFailed = "project_funding_failed"
+)
@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentRequest is a request for an investor to pay for an investment through a
+// payment provider, e.g. a virtual account number or a wallet checkout
+type PaymentRequest struct {
+	ID                    uuid.UUID  `json:"id" db:"id"`
+	InvestmentID          uuid.UUID  `json:"investment_id" db:"investment_id"`
+	CooperativeID         uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
+	InvestorID            uuid.UUID  `json:"investor_id" db:"investor_id"`
+	Provider              string     `json:"provider" db:"provider"`
+	Method                string     `json:"method" db:"method"` // bank_transfer, digital_wallet
+	Status                string     `json:"status" db:"status"` // pending, paid, expired, failed, cancelled
+	Amount                Money      `json:"amount" db:"amount"`
+	Currency              string     `json:"currency" db:"currency"`
+	Reference             string     `json:"reference" db:"reference"` // investment payment reference
+	ProviderReference     string     `json:"provider_reference" db:"provider_reference"`
+	VirtualAccountNumber  string     `json:"virtual_account_number,omitempty" db:"virtual_account_number"`
+	BankCode              string     `json:"bank_code,omitempty" db:"bank_code"`
+	PaymentURL            string     `json:"payment_url,omitempty" db:"payment_url"`
+	ExpiresAt             time.Time  `json:"expires_at" db:"expires_at"`
+	PaidAmount            *Money     `json:"paid_amount" db:"paid_amount"`
+	PaidAt                *time.Time `json:"paid_at" db:"paid_at"`
+	ProviderTransactionID string     `json:"provider_transaction_id" db:"provider_transaction_id"`
+	FailureReason         string     `json:"failure_reason" db:"failure_reason"`
+	CreatedAt             time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// PaymentInstruction tells the investor how to pay, as issued by the provider
+type PaymentInstruction struct {
+	ProviderReference    string    `json:"provider_reference"`
+	VirtualAccountNumber string    `json:"virtual_account_number"`
+	BankCode             string    `json:"bank_code"`
+	PaymentURL           string    `json:"payment_url"`
+	ExpiresAt            time.Time `json:"expires_at"`
+}
+
+// PaymentEvent is a payment status reported by a provider, either through an
+// asynchronous callback or a status query
+type PaymentEvent struct {
+	ProviderReference     string     `json:"provider_reference"`
+	ProviderTransactionID string     `json:"provider_transaction_id"`
+	Status                string     `json:"status"` // pending, paid, expired, failed
+	Amount                Money      `json:"amount"`
+	PaidAt                *time.Time `json:"paid_at"`
+	FailureReason         string     `json:"failure_reason"`
+}
+
+// CreatePaymentRequestRequest asks for payment instructions for a pending investment
+type CreatePaymentRequestRequest struct {
+	InvestmentID  uuid.UUID `json:"investment_id" validate:"required"`
+	CooperativeID uuid.UUID `json:"cooperative_id" validate:"required"`
+	Provider      string    `json:"provider"` // defaults to the configured provider
+	Method        string    `json:"method" validate:"required,oneof=bank_transfer digital_wallet"`
+}
+
+// SimulatePaymentRequest pays a simulator payment request, defaulting to the requested amount
+type SimulatePaymentRequest struct {
+	Amount *Money `json:"amount"`
+}
+
+// IsFinal reports whether the payment request can no longer change status
+func (p *PaymentRequest) IsFinal() bool {
+	return p.Status != PaymentRequestStatusPending
+}
+
+// Payment request constants
+const (
+	PaymentRequestStatusPending   = "pending"
+	PaymentRequestStatusPaid      = "paid"
+	PaymentRequestStatusExpired   = "expired"
+	PaymentRequestStatusFailed    = "failed"
+	PaymentRequestStatusCancelled = "cancelled"
+
+	PaymentProviderSimulator = "simulator"
+)
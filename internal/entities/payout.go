@@ -0,0 +1,155 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PayoutItem is an approved payment out of a cooperative's escrow account waiting
+// to be executed by the bank: a fund disbursement to a business, an investor
+// refund or an investor profit share
+type PayoutItem struct {
+	ID                  uuid.UUID  `json:"id" db:"id"`
+	CooperativeID       uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
+	EscrowAccountID     uuid.UUID  `json:"escrow_account_id" db:"escrow_account_id"`
+	BatchID             *uuid.UUID `json:"batch_id" db:"batch_id"`
+	PayoutType          string     `json:"payout_type" db:"payout_type"` // disbursement, refund, profit_share
+	SourceID            uuid.UUID  `json:"source_id" db:"source_id"`     // the disbursement, investor refund or investor profit share
+	BeneficiaryName     string     `json:"beneficiary_name" db:"beneficiary_name"`
+	BeneficiaryAccount  string     `json:"beneficiary_account" db:"beneficiary_account"`
+	BeneficiaryBankCode string     `json:"beneficiary_bank_code" db:"beneficiary_bank_code"`
+	Amount              Money      `json:"amount" db:"amount"`
+	Currency            string     `json:"currency" db:"currency"`
+	Reference           string     `json:"reference" db:"reference"` // end-to-end ID quoted to the bank and echoed in its return file
+	Description         string     `json:"description" db:"description"`
+	Status              string     `json:"status" db:"status"` // queued, batched, completed, failed
+	FailureReason       string     `json:"failure_reason" db:"failure_reason"`
+	BankReference       string     `json:"bank_reference" db:"bank_reference"`
+	CompletedAt         *time.Time `json:"completed_at" db:"completed_at"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsFinal reports whether the bank has settled or rejected the payout
+func (i *PayoutItem) IsFinal() bool {
+	return i.Status == PayoutItemStatusCompleted || i.Status == PayoutItemStatusFailed
+}
+
+// PayoutBatch groups the payouts drawn from one escrow account into a single bank
+// file. The debtor fields describe the escrow account's bank account.
+type PayoutBatch struct {
+	ID              uuid.UUID     `json:"id" db:"id"`
+	CooperativeID   uuid.UUID     `json:"cooperative_id" db:"cooperative_id"`
+	EscrowAccountID uuid.UUID     `json:"escrow_account_id" db:"escrow_account_id"`
+	Reference       string        `json:"reference" db:"reference"` // message ID of the bank file
+	Currency        string        `json:"currency" db:"currency"`
+	DebtorName      string        `json:"debtor_name" db:"debtor_name"`
+	DebtorAccount   string        `json:"debtor_account" db:"debtor_account"`
+	DebtorBankCode  string        `json:"debtor_bank_code" db:"debtor_bank_code"`
+	ExecutionDate   time.Time     `json:"execution_date" db:"execution_date"`
+	Status          string        `json:"status" db:"status"` // draft, exported, completed, partially_completed, failed
+	ItemCount       int           `json:"item_count" db:"item_count"`
+	TotalAmount     Money         `json:"total_amount" db:"total_amount"`
+	CompletedCount  int           `json:"completed_count" db:"completed_count"`
+	FailedCount     int           `json:"failed_count" db:"failed_count"`
+	ExportFormat    string        `json:"export_format" db:"export_format"` // pain001, csv
+	ExportedBy      *uuid.UUID    `json:"exported_by" db:"exported_by"`
+	ExportedAt      *time.Time    `json:"exported_at" db:"exported_at"`
+	CreatedBy       uuid.UUID     `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at" db:"updated_at"`
+	Items           []*PayoutItem `json:"items,omitempty" db:"-"`
+}
+
+// PayoutQueueGroup summarises the queued payouts drawn from one escrow account
+type PayoutQueueGroup struct {
+	EscrowAccountID    uuid.UUID `json:"escrow_account_id"`
+	Currency           string    `json:"currency"`
+	ItemCount          int       `json:"item_count"`
+	TotalAmount        Money     `json:"total_amount"`
+	MissingBeneficiary int       `json:"missing_beneficiary"` // payouts held back until a beneficiary account is given
+}
+
+// PayoutFile is a generated bank file
+type PayoutFile struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"-"`
+}
+
+// PayoutReturnLine is the bank's result for one payout in a return file
+type PayoutReturnLine struct {
+	Reference     string `json:"reference"`
+	Status        string `json:"status"` // completed, failed, pending
+	Reason        string `json:"reason"`
+	BankReference string `json:"bank_reference"`
+}
+
+// PayoutReturnResult summarises an imported return file
+type PayoutReturnResult struct {
+	Batch     *PayoutBatch  `json:"batch"`
+	Completed []*PayoutItem `json:"completed"`
+	Failed    []*PayoutItem `json:"failed"`
+	Pending   int           `json:"pending"`   // lines the bank has not settled yet
+	Unchanged int           `json:"unchanged"` // lines for payouts that were already settled
+	Unmatched []string      `json:"unmatched"` // references not found in the batch
+}
+
+// CreatePayoutBatchRequest batches the queued payouts of an escrow account
+type CreatePayoutBatchRequest struct {
+	CooperativeID   uuid.UUID  `json:"cooperative_id" validate:"required"`
+	EscrowAccountID uuid.UUID  `json:"escrow_account_id" validate:"required"`
+	Currency        string     `json:"currency" validate:"required,len=3"`
+	DebtorName      string     `json:"debtor_name" validate:"required,max=140"`
+	DebtorAccount   string     `json:"debtor_account" validate:"required,max=34"`
+	DebtorBankCode  string     `json:"debtor_bank_code" validate:"required,max=35"`
+	ExecutionDate   *time.Time `json:"execution_date"` // defaults to today
+}
+
+// UpdatePayoutBeneficiaryRequest supplies or corrects where a payout is paid to
+type UpdatePayoutBeneficiaryRequest struct {
+	BeneficiaryName     string `json:"beneficiary_name" validate:"required,max=140"`
+	BeneficiaryAccount  string `json:"beneficiary_account" validate:"required,max=34"`
+	BeneficiaryBankCode string `json:"beneficiary_bank_code" validate:"max=35"`
+}
+
+// ImportPayoutReturnRequest describes an uploaded bank return file
+type ImportPayoutReturnRequest struct {
+	Format string `form:"format" validate:"required,oneof=pain002 csv"`
+}
+
+// PayoutReference returns the identifier quoted to the bank for a payout or a
+// batch; it fits the 35 characters ISO 20022 allows
+func PayoutReference(id uuid.UUID) string {
+	return strings.ToUpper(strings.ReplaceAll(id.String(), "-", ""))
+}
+
+// Payout constants
+const (
+	PayoutTypeDisbursement = "disbursement"
+	PayoutTypeRefund       = "refund"
+	PayoutTypeProfitShare  = "profit_share"
+
+	PayoutItemStatusQueued    = "queued"
+	PayoutItemStatusBatched   = "batched"
+	PayoutItemStatusCompleted = "completed"
+	PayoutItemStatusFailed    = "failed"
+
+	PayoutBatchStatusDraft              = "draft"
+	PayoutBatchStatusExported           = "exported"
+	PayoutBatchStatusCompleted          = "completed"
+	PayoutBatchStatusPartiallyCompleted = "partially_completed"
+	PayoutBatchStatusFailed             = "failed"
+
+	PayoutFileFormatPain001 = "pain001"
+	PayoutFileFormatCSV     = "csv"
+
+	PayoutReturnFormatPain002 = "pain002"
+	PayoutReturnFormatCSV     = "csv"
+
+	PayoutReturnStatusCompleted = "completed"
+	PayoutReturnStatusFailed    = "failed"
+	PayoutReturnStatusPending   = "pending"
+)
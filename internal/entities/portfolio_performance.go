@@ -0,0 +1,125 @@
+package entities
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PerformancePosition is an investment whose performance is measured, with the
+// return its project expects to pay
+type PerformancePosition struct {
+	InvestmentID         uuid.UUID `json:"investment_id" db:"investment_id"`
+	InvestorID           uuid.UUID `json:"investor_id" db:"investor_id"`
+	ProjectID            uuid.UUID `json:"project_id" db:"project_id"`
+	CooperativeID        uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	ProjectTitle         string    `json:"project_title" db:"project_title"`
+	ContractType         string    `json:"contract_type" db:"contract_type"`
+	Currency             string    `json:"currency" db:"currency"`
+	Status               string    `json:"status" db:"status"`
+	InvestedAt           time.Time `json:"invested_at" db:"invested_at"`
+	ExpectedReturn       *float64  `json:"expected_return" db:"expected_return"`               // percentage over the period
+	ExpectedReturnPeriod int       `json:"expected_return_period" db:"expected_return_period"` // in months
+}
+
+// PerformanceEvent is a dated change to an investment. Cash is signed from the
+// investor's side: negative when the investor pays in, positive when they
+// receive. Principal and loss changes move the position's carrying value,
+// which is its outstanding capital less its unrecovered share of losses.
+type PerformanceEvent struct {
+	InvestmentID    uuid.UUID `json:"investment_id" db:"investment_id"`
+	InvestorID      uuid.UUID `json:"investor_id" db:"investor_id"`
+	ProjectID       uuid.UUID `json:"project_id" db:"project_id"`
+	EventType       string    `json:"event_type" db:"event_type"`
+	OccurredAt      time.Time `json:"occurred_at" db:"occurred_at"`
+	Cash            Money     `json:"cash" db:"cash"`
+	PrincipalChange Money     `json:"principal_change" db:"principal_change"`
+	LossChange      Money     `json:"loss_change" db:"loss_change"`
+}
+
+// PerformanceFilter selects the investments and period performance is measured over
+type PerformanceFilter struct {
+	InvestorID    *uuid.UUID
+	ProjectID     *uuid.UUID
+	CooperativeID *uuid.UUID
+	Start         *time.Time // from the first investment when unset
+	End           *time.Time // exclusive; now when unset
+}
+
+// PerformanceResult measures a set of cash flows over a period. Positions held
+// at the start enter at their carrying value and those still held at the end
+// leave at it. XIRR and ExpectedAnnualReturn are annualized percentages; TWR is the
+// percentage return over the whole period. Returns are nil when they cannot
+// be measured, such as before any capital is at risk.
+type PerformanceResult struct {
+	OpeningValue         Money    `json:"opening_value"`
+	Invested             Money    `json:"invested"`
+	Distributions        Money    `json:"distributions"`
+	CapitalReturned      Money    `json:"capital_returned"` // refunds, exits and stakes sold
+	CurrentValue         Money    `json:"current_value"`
+	RealizedResult       Money    `json:"realized_result"`
+	UnrealizedResult     Money    `json:"unrealized_result"`
+	TotalResult          Money    `json:"total_result"`
+	XIRR                 *float64 `json:"xirr"`
+	TWR                  *float64 `json:"twr"`
+	ExpectedAnnualReturn *float64 `json:"expected_annual_return"`
+	// ReturnVsExpected is XIRR less the expected annual return, in percentage points
+	ReturnVsExpected *float64 `json:"return_vs_expected"`
+}
+
+// InvestmentPerformance is the performance of one investment
+type InvestmentPerformance struct {
+	PerformancePosition
+	PerformanceResult
+}
+
+// CurrencyPerformance totals the investments held in one currency
+type CurrencyPerformance struct {
+	Currency string `json:"currency"`
+	PerformanceResult
+	Investments     int `json:"investments"`
+	OpenInvestments int `json:"open_investments"`
+}
+
+// PerformanceReport is the performance of an investor's, project's or
+// cooperative's investments. Total is in the base currency, with flows in
+// other currencies converted at the rate on their date.
+type PerformanceReport struct {
+	Scope       string                   `json:"scope"` // investor, project, cooperative
+	ScopeID     uuid.UUID                `json:"scope_id"`
+	Start       *time.Time               `json:"start,omitempty"`
+	End         time.Time                `json:"end"`
+	Total       *CurrencyPerformance     `json:"total"`
+	ByCurrency  []*CurrencyPerformance   `json:"by_currency"`
+	Investments []*InvestmentPerformance `json:"investments"`
+}
+
+// AnnualizedExpectedReturn converts the project's expected return over its
+// period into a yearly rate comparable with XIRR
+func (p *PerformancePosition) AnnualizedExpectedReturn() *float64 {
+	if p.ExpectedReturn == nil {
+		return nil
+	}
+	if p.ExpectedReturnPeriod <= 0 || p.ExpectedReturnPeriod == 12 {
+		rate := *p.ExpectedReturn
+		return &rate
+	}
+	rate := (math.Pow(1+*p.ExpectedReturn/100, 12/float64(p.ExpectedReturnPeriod)) - 1) * 100
+	return &rate
+}
+
+// Performance constants
+const (
+	PerformanceScopeInvestor    = "investor"
+	PerformanceScopeProject     = "project"
+	PerformanceScopeCooperative = "cooperative"
+
+	PerformanceEventInvestment   = "investment"
+	PerformanceEventDistribution = "distribution"
+	PerformanceEventRefund       = "refund"
+	PerformanceEventExit         = "exit"
+	PerformanceEventStakeBought  = "stake_bought"
+	PerformanceEventStakeSold    = "stake_sold"
+	PerformanceEventLoss         = "loss"
+)
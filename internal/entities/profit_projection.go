@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectionAssumption describes an uncertain amount per distribution period.
+// Worst and Best are the outcomes that hurt and help investors most, so for
+// expenses Worst is the higher amount.
+type ProjectionAssumption struct {
+	// Distribution is how the amount varies:
+	//   fixed:      always Base
+	//   scenario:   Worst, Base or Best, with WorstProbability and BestProbability
+	//   triangular: anywhere between Worst and Best, most likely around Base
+	//   normal:     around Base with StdDev, never below zero
+	Distribution     string  `json:"distribution" validate:"required,oneof=fixed scenario triangular normal"`
+	Base             Money   `json:"base" validate:"required"`
+	Worst            Money   `json:"worst"`
+	Best             Money   `json:"best"`
+	StdDev           Money   `json:"std_dev"`
+	WorstProbability float64 `json:"worst_probability" validate:"min=0,max=100"` // percentage; scenario only, 25 when neither is set
+	BestProbability  float64 `json:"best_probability" validate:"min=0,max=100"`  // percentage; scenario only, 25 when neither is set
+	// Persistent draws the amount once per simulation for every period, for a
+	// plan that is off throughout rather than from period to period
+	Persistent bool `json:"persistent"`
+}
+
+// ProfitProjectionRequest configures a Monte Carlo projection of a project's
+// profit sharing. Revenue and expenses are per distribution period.
+type ProfitProjectionRequest struct {
+	Revenue      ProjectionAssumption `json:"revenue" validate:"required"`
+	Expenses     ProjectionAssumption `json:"expenses" validate:"required"`
+	Periods      int                  `json:"periods" validate:"required,min=1,max=120"`
+	PeriodMonths int                  `json:"period_months" validate:"omitempty,oneof=1 3 6 12"` // 3 when unset
+	StartDate    *time.Time           `json:"start_date"`                                        // now when unset
+	// LossHandlingMethod defaults to the method of the project's last recorded
+	// loss, or carry_forward
+	LossHandlingMethod string `json:"loss_handling_method" validate:"omitempty,oneof=carry_forward shared absorb"`
+	InvestorCapital    Money  `json:"investor_capital"`                                    // the project's current funding when zero
+	Simulations        int    `json:"simulations" validate:"omitempty,min=100,max=100000"` // 10000 when unset
+	Seed               *int64 `json:"seed"`                                                // repeats a run; random when unset
+}
+
+// ProjectionPercentiles summarizes a simulated amount across all runs
+type ProjectionPercentiles struct {
+	P5   Money `json:"p5"`
+	P25  Money `json:"p25"`
+	P50  Money `json:"p50"`
+	P75  Money `json:"p75"`
+	P95  Money `json:"p95"`
+	Mean Money `json:"mean"`
+}
+
+// ProjectedDistributionPeriod is a distribution period's simulated outcome.
+// Amount is the mean investor distribution and Percentage its share of the
+// total.
+type ProjectedDistributionPeriod struct {
+	DistributionPeriod
+	Distribution ProjectionPercentiles `json:"distribution"`
+	// CumulativeReturn is the investors' result up to the end of the period:
+	// distributions less capital lost and not yet recovered
+	CumulativeReturn ProjectionPercentiles `json:"cumulative_return"`
+	// LossProbability is the percentage of runs in which investors bear a loss
+	// in the period; CumulativeLossProbability the percentage in which they are
+	// behind at its end
+	LossProbability           float64 `json:"loss_probability"`
+	CumulativeLossProbability float64 `json:"cumulative_loss_probability"`
+}
+
+// ProfitProjectionSimulation is the outcome of a Monte Carlo profit projection
+type ProfitProjectionSimulation struct {
+	ProjectID          uuid.UUID                     `json:"project_id"`
+	ContractType       string                        `json:"contract_type"`
+	Currency           string                        `json:"currency"`
+	LossHandlingMethod string                        `json:"loss_handling_method"`
+	InvestorCapital    Money                         `json:"investor_capital"`
+	CarriedForwardLoss Money                         `json:"carried_forward_loss"` // recovered before profit is shared
+	InvestorRatio      float64                       `json:"investor_ratio"`       // percentage of distributable profit
+	Simulations        int                           `json:"simulations"`
+	Seed               int64                         `json:"seed"`
+	Periods            []ProjectedDistributionPeriod `json:"periods"`
+	TotalReturn        ProjectionPercentiles         `json:"total_return"`
+	// ReturnRate percentiles are the total return as a percentage of capital
+	ReturnRate map[string]float64 `json:"return_rate"`
+	// LossProbability is the percentage of runs in which investors end the
+	// projection behind: the capital they lose outweighs their distributions
+	LossProbability float64   `json:"loss_probability"`
+	CalculatedAt    time.Time `json:"calculated_at"`
+}
+
+// Projection constants
+const (
+	ProjectionDistributionFixed      = "fixed"
+	ProjectionDistributionScenario   = "scenario"
+	ProjectionDistributionTriangular = "triangular"
+	ProjectionDistributionNormal     = "normal"
+
+	DistributionPeriodStatusProjected = "projected"
+)
@@ -8,32 +8,37 @@ import (
 
 // ProfitCalculation represents Sharia-compliant profit calculation (FR-050 to FR-053)
 type ProfitCalculation struct {
-	ID                 uuid.UUID              `json:"id" db:"id"`
-	ProjectID          uuid.UUID              `json:"project_id" db:"project_id"`
-	BusinessID         uuid.UUID              `json:"business_id" db:"business_id"`
-	CooperativeID      uuid.UUID              `json:"cooperative_id" db:"cooperative_id"`
-	CalculationPeriod  string                 `json:"calculation_period" db:"calculation_period"` // monthly, quarterly, annual
-	StartDate          time.Time              `json:"start_date" db:"start_date"`
-	EndDate            time.Time              `json:"end_date" db:"end_date"`
-	TotalRevenue       float64                `json:"total_revenue" db:"total_revenue"`
-	TotalExpenses      float64                `json:"total_expenses" db:"total_expenses"`
-	NetProfit          float64                `json:"net_profit" db:"net_profit"`
-	TotalLoss          float64                `json:"total_loss" db:"total_loss"`
-	ProfitSharingRatio map[string]float64     `json:"profit_sharing_ratio" db:"profit_sharing_ratio"` // investor: 70, business: 30
-	InvestorShare      float64                `json:"investor_share" db:"investor_share"`
-	BusinessShare      float64                `json:"business_share" db:"business_share"`
-	CooperativeShare   float64                `json:"cooperative_share" db:"cooperative_share"`
-	ShariaCompliant    bool                   `json:"sharia_compliant" db:"sharia_compliant"`
-	ComplianceNotes    string                 `json:"compliance_notes" db:"compliance_notes"`
-	VerificationStatus string                 `json:"verification_status" db:"verification_status"` // pending, verified, rejected
-	VerifiedBy         *uuid.UUID             `json:"verified_by" db:"verified_by"`
-	VerifiedAt         *time.Time             `json:"verified_at" db:"verified_at"`
-	RejectionReason    string                 `json:"rejection_reason" db:"rejection_reason"`
-	Documents          []string               `json:"documents" db:"documents"`
-	Metadata           map[string]interface{} `json:"metadata" db:"metadata"`
-	IsActive           bool                   `json:"is_active" db:"is_active"`
-	CreatedAt          time.Time              `json:"created_at" db:"created_at"`
-	UpdatedAt          time.Time              `json:"updated_at" db:"updated_at"`
+	ID                 uuid.UUID                  `json:"id" db:"id"`
+	ProjectID          uuid.UUID                  `json:"project_id" db:"project_id"`
+	BusinessID         uuid.UUID                  `json:"business_id" db:"business_id"`
+	CooperativeID      uuid.UUID                  `json:"cooperative_id" db:"cooperative_id"`
+	ContractType       string                     `json:"contract_type" db:"contract_type"`           // mudarabah, musyarakah
+	CalculationPeriod  string                     `json:"calculation_period" db:"calculation_period"` // monthly, quarterly, annual
+	StartDate          time.Time                  `json:"start_date" db:"start_date"`
+	EndDate            time.Time                  `json:"end_date" db:"end_date"`
+	TotalRevenue       Money                      `json:"total_revenue" db:"total_revenue"`
+	TotalExpenses      Money                      `json:"total_expenses" db:"total_expenses"`
+	NetProfit          Money                      `json:"net_profit" db:"net_profit"`
+	TotalLoss          Money                      `json:"total_loss" db:"total_loss"`                     // the investors' part of the loss
+	PartnerLoss        Money                      `json:"partner_loss" db:"partner_loss"`                 // borne by a musyarakah partner's capital
+	LossOffset         Money                      `json:"loss_offset" db:"loss_offset"`                   // carried-forward losses recovered before the profit is shared
+	LossHandlingMethod string                     `json:"loss_handling_method" db:"loss_handling_method"` // carry_forward, shared, absorb
+	ProfitSharingRatio map[string]float64         `json:"profit_sharing_ratio" db:"profit_sharing_ratio"` // investor: 70, business: 30
+	InvestorShare      Money                      `json:"investor_share" db:"investor_share"`
+	BusinessShare      Money                      `json:"business_share" db:"business_share"`
+	CooperativeShare   Money                      `json:"cooperative_share" db:"cooperative_share"`
+	ShariaCompliant    bool                       `json:"sharia_compliant" db:"sharia_compliant"`
+	ShariaScreening    *ShariaScreeningEvaluation `json:"sharia_screening,omitempty" db:"-"` // which screening rules passed or failed
+	ComplianceNotes    string                     `json:"compliance_notes" db:"compliance_notes"`
+	VerificationStatus string                     `json:"verification_status" db:"verification_status"` // pending, verified, rejected
+	VerifiedBy         *uuid.UUID                 `json:"verified_by" db:"verified_by"`
+	VerifiedAt         *time.Time                 `json:"verified_at" db:"verified_at"`
+	RejectionReason    string                     `json:"rejection_reason" db:"rejection_reason"`
+	Documents          []string                   `json:"documents" db:"documents"`
+	Metadata           map[string]interface{}     `json:"metadata" db:"metadata"`
+	IsActive           bool                       `json:"is_active" db:"is_active"`
+	CreatedAt          time.Time                  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time                  `json:"updated_at" db:"updated_at"`
 }
 
 // ProfitDistributionExtended represents profit distribution to investors (FR-054 to FR-057)
@@ -42,8 +47,8 @@ type ProfitDistributionExtended struct {
 	ProfitCalculationID     uuid.UUID              `json:"profit_calculation_id" db:"profit_calculation_id"`
 	ProjectID               uuid.UUID              `json:"project_id" db:"project_id"`
 	CooperativeID           uuid.UUID              `json:"cooperative_id" db:"cooperative_id"`
-	DistributionType        string                 `json:"distribution_type" db:"distribution_type"` // profit, loss_compensation
-	TotalDistributionAmount float64                `json:"total_distribution_amount" db:"total_distribution_amount"`
+	DistributionType        string                 `json:"distribution_type" db:"distribution_type"` // profit, loss_compensation, murabahah_installment
+	TotalDistributionAmount Money                  `json:"total_distribution_amount" db:"total_distribution_amount"`
 	Currency                string                 `json:"currency" db:"currency"`
 	DistributionDate        time.Time              `json:"distribution_date" db:"distribution_date"`
 	Status                  string                 `json:"status" db:"status"` // pending, processing, completed, failed, cancelled
@@ -66,12 +71,13 @@ type InvestorProfitShare struct {
 	ProfitDistributionID uuid.UUID  `json:"profit_distribution_id" db:"profit_distribution_id"`
 	InvestmentID         uuid.UUID  `json:"investment_id" db:"investment_id"`
 	InvestorID           uuid.UUID  `json:"investor_id" db:"investor_id"`
-	OriginalInvestment   float64    `json:"original_investment" db:"original_investment"`
+	OriginalInvestment   Money      `json:"original_investment" db:"original_investment"`
 	InvestmentPercentage float64    `json:"investment_percentage" db:"investment_percentage"` // percentage of total project investment
-	ProfitShareAmount    float64    `json:"profit_share_amount" db:"profit_share_amount"`
-	TaxAmount            float64    `json:"tax_amount" db:"tax_amount"`
-	NetProfitShare       float64    `json:"net_profit_share" db:"net_profit_share"`
-	Status               string     `json:"status" db:"status"` // pending, processed, completed, failed
+	ProfitShareAmount    Money      `json:"profit_share_amount" db:"profit_share_amount"`
+	TaxAmount            Money      `json:"tax_amount" db:"tax_amount"`
+	NetProfitShare       Money      `json:"net_profit_share" db:"net_profit_share"`
+	ReinvestedAmount     Money      `json:"reinvested_amount" db:"reinvested_amount"` // rolled into a new investment instead of paid out
+	Status               string     `json:"status" db:"status"`                       // pending, processed, completed, failed
 	BankAccount          string     `json:"bank_account" db:"bank_account"`
 	TransactionReference string     `json:"transaction_reference" db:"transaction_reference"`
 	ProcessedAt          *time.Time `json:"processed_at" db:"processed_at"`
@@ -82,6 +88,11 @@ type InvestorProfitShare struct {
 	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
 }
 
+// PayableAmount returns the part of the net profit share paid out to the investor
+func (s *InvestorProfitShare) PayableAmount() Money {
+	return s.NetProfitShare.Sub(s.ReinvestedAmount)
+}
+
 // TaxDocumentation represents tax-compliant documentation for profit distributions (FR-057)
 type TaxDocumentation struct {
 	ID                   uuid.UUID  `json:"id" db:"id"`
@@ -90,8 +101,8 @@ type TaxDocumentation struct {
 	DocumentNumber       string     `json:"document_number" db:"document_number"`
 	TaxYear              int        `json:"tax_year" db:"tax_year"`
 	TaxPeriod            string     `json:"tax_period" db:"tax_period"` // monthly, quarterly, annual
-	TotalTaxableAmount   float64    `json:"total_taxable_amount" db:"total_taxable_amount"`
-	TotalTaxAmount       float64    `json:"total_tax_amount" db:"total_tax_amount"`
+	TotalTaxableAmount   Money      `json:"total_taxable_amount" db:"total_taxable_amount"`
+	TotalTaxAmount       Money      `json:"total_tax_amount" db:"total_tax_amount"`
 	TaxRate              float64    `json:"tax_rate" db:"tax_rate"`
 	Currency             string     `json:"currency" db:"currency"`
 	IssuedDate           time.Time  `json:"issued_date" db:"issued_date"`
@@ -113,9 +124,10 @@ type CreateProfitCalculationRequest struct {
 	CalculationPeriod  string             `json:"calculation_period" validate:"required,oneof=monthly quarterly annual"`
 	StartDate          time.Time          `json:"start_date" validate:"required"`
 	EndDate            time.Time          `json:"end_date" validate:"required"`
-	TotalRevenue       float64            `json:"total_revenue" validate:"required,min=0"`
-	TotalExpenses      float64            `json:"total_expenses" validate:"required,min=0"`
-	ProfitSharingRatio map[string]float64 `json:"profit_sharing_ratio" validate:"required"`
+	TotalRevenue       Money              `json:"total_revenue" validate:"required,min=0"`
+	TotalExpenses      Money              `json:"total_expenses" validate:"required,min=0"`
+	ProfitSharingRatio map[string]float64 `json:"profit_sharing_ratio"`                                                        // defaults to the project contract's ratio
+	LossHandlingMethod string             `json:"loss_handling_method" validate:"omitempty,oneof=carry_forward shared absorb"` // defaults to carry_forward
 	Documents          []string           `json:"documents"`
 	ComplianceNotes    string             `json:"compliance_notes"`
 }
@@ -147,8 +159,8 @@ type CreateTaxDocumentationRequest struct {
 	DocumentType         string    `json:"document_type" validate:"required,oneof=tax_certificate withholding_tax annual_report"`
 	TaxYear              int       `json:"tax_year" validate:"required,min=2020"`
 	TaxPeriod            string    `json:"tax_period" validate:"required,oneof=monthly quarterly annual"`
-	TaxRate              float64   `json:"tax_rate" validate:"required,min=0,max=100"`
-	DueDate              time.Time `json:"due_date" validate:"required"`
+	TaxRate              float64   `json:"tax_rate" validate:"min=0,max=100"` // ignored once tax is withheld by jurisdiction
+	DueDate              time.Time `json:"due_date"`                          // defaults to the withholding remittance date
 	ComplianceNotes      string    `json:"compliance_notes"`
 }
 
@@ -162,8 +174,8 @@ type ProfitCalculationFilter struct {
 	ShariaCompliant    *bool      `json:"sharia_compliant"`
 	StartDate          *time.Time `json:"start_date"`
 	EndDate            *time.Time `json:"end_date"`
-	MinProfit          *float64   `json:"min_profit"`
-	MaxProfit          *float64   `json:"max_profit"`
+	MinProfit          *Money     `json:"min_profit"`
+	MaxProfit          *Money     `json:"max_profit"`
 	Page               int        `json:"page" validate:"min=1"`
 	Limit              int        `json:"limit" validate:"min=1,max=100"`
 }
@@ -176,8 +188,8 @@ type ProfitDistributionExtendedFilter struct {
 	Status           *string    `json:"status"`
 	StartDate        *time.Time `json:"start_date"`
 	EndDate          *time.Time `json:"end_date"`
-	MinAmount        *float64   `json:"min_amount"`
-	MaxAmount        *float64   `json:"max_amount"`
+	MinAmount        *Money     `json:"min_amount"`
+	MaxAmount        *Money     `json:"max_amount"`
 	Page             int        `json:"page" validate:"min=1"`
 	Limit            int        `json:"limit" validate:"min=1,max=100"`
 }
@@ -194,24 +206,32 @@ type TaxDocumentationFilter struct {
 	Limit                int        `json:"limit" validate:"min=1,max=100"`
 }
 
-// ComFundsFee represents the platform fee structure
+// ComFundsFee represents the platform fee structure. A schedule charges a flat
+// FeePercentage unless it has tiers: banded schedules charge the whole amount at
+// the rate of the band it falls in, tiered schedules charge each slice of the
+// amount at its own tier's rate.
 type ComFundsFee struct {
-	ID            uuid.UUID  `json:"id" db:"id"`
-	FeeType       string     `json:"fee_type" db:"fee_type"` // platform_fee, success_fee, transaction_fee
-	FeePercentage float64    `json:"fee_percentage" db:"fee_percentage"`
-	FeeAmount     float64    `json:"fee_amount" db:"fee_amount"`
-	IsEnabled     bool       `json:"is_enabled" db:"is_enabled"`
-	MinimumAmount float64    `json:"minimum_amount" db:"minimum_amount"`
-	MaximumAmount float64    `json:"maximum_amount" db:"maximum_amount"`
-	ApplicableTo  string     `json:"applicable_to" db:"applicable_to"` // all_projects, successful_funding, specific_projects
-	ProjectID     *uuid.UUID `json:"project_id" db:"project_id"`
-	CooperativeID *uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
-	EffectiveFrom time.Time  `json:"effective_from" db:"effective_from"`
-	EffectiveTo   *time.Time `json:"effective_to" db:"effective_to"`
-	Description   string     `json:"description" db:"description"`
-	IsActive      bool       `json:"is_active" db:"is_active"`
-	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+	ID            uuid.UUID         `json:"id" db:"id"`
+	FeeType       string            `json:"fee_type" db:"fee_type"` // platform_fee, success_fee, transaction_fee
+	FeePercentage float64           `json:"fee_percentage" db:"fee_percentage"`
+	TierMode      string            `json:"tier_mode" db:"tier_mode"` // flat, banded, tiered
+	Tiers         []ComFundsFeeTier `json:"tiers" db:"tiers"`
+	Priority      int               `json:"priority" db:"priority"` // breaks ties between schedules of the same scope
+	Currency      string            `json:"currency" db:"currency"`
+	FeeAmount     Money             `json:"fee_amount" db:"fee_amount"`
+	IsEnabled     bool              `json:"is_enabled" db:"is_enabled"`
+	MinimumAmount Money             `json:"minimum_amount" db:"minimum_amount"`
+	MaximumAmount Money             `json:"maximum_amount" db:"maximum_amount"`
+	ApplicableTo  string            `json:"applicable_to" db:"applicable_to"` // all_projects, successful_funding, specific_projects
+	ProjectID     *uuid.UUID        `json:"project_id" db:"project_id"`
+	CooperativeID *uuid.UUID        `json:"cooperative_id" db:"cooperative_id"`
+	EffectiveFrom time.Time         `json:"effective_from" db:"effective_from"`
+	EffectiveTo   *time.Time        `json:"effective_to" db:"effective_to"`
+	Description   string            `json:"description" db:"description"`
+	IsActive      bool              `json:"is_active" db:"is_active"`
+	CreatedBy     uuid.UUID         `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at" db:"updated_at"`
 }
 
 // ProjectFeeCalculation represents fee calculation for a specific project
@@ -219,11 +239,20 @@ type ProjectFeeCalculation struct {
 	ID                   uuid.UUID  `json:"id" db:"id"`
 	ProjectID            uuid.UUID  `json:"project_id" db:"project_id"`
 	CooperativeID        uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
-	TotalFundingAmount   float64    `json:"total_funding_amount" db:"total_funding_amount"`
+	BusinessID           uuid.UUID  `json:"business_id" db:"business_id"`
+	TotalFundingAmount   Money      `json:"total_funding_amount" db:"total_funding_amount"`
 	FeePercentage        float64    `json:"fee_percentage" db:"fee_percentage"`
-	FeeAmount            float64    `json:"fee_amount" db:"fee_amount"`
-	NetAmountAfterFee    float64    `json:"net_amount_after_fee" db:"net_amount_after_fee"`
+	FeeAmount            Money      `json:"fee_amount" db:"fee_amount"`
+	NetAmountAfterFee    Money      `json:"net_amount_after_fee" db:"net_amount_after_fee"`
 	FeeStatus            string     `json:"fee_status" db:"fee_status"` // pending, calculated, collected, waived
+	Currency             string     `json:"currency" db:"currency"`
+	FeeScheduleID        *uuid.UUID `json:"fee_schedule_id" db:"fee_schedule_id"`
+	GrossFeeAmount       Money      `json:"gross_fee_amount" db:"gross_fee_amount"` // before promotional waivers
+	WaiverID             *uuid.UUID `json:"waiver_id" db:"waiver_id"`
+	WaivedAmount         Money      `json:"waived_amount" db:"waived_amount"`
+	InvoiceID            *uuid.UUID `json:"invoice_id" db:"invoice_id"`
+	CreditNoteID         *uuid.UUID `json:"credit_note_id" db:"credit_note_id"`
+	FundingClosureID     *uuid.UUID `json:"funding_closure_id" db:"funding_closure_id"` // set for success fees charged when funding closed
 	CalculatedAt         time.Time  `json:"calculated_at" db:"calculated_at"`
 	CollectedAt          *time.Time `json:"collected_at" db:"collected_at"`
 	CollectedBy          *uuid.UUID `json:"collected_by" db:"collected_by"`
@@ -236,24 +265,31 @@ type ProjectFeeCalculation struct {
 
 // CreateComFundsFeeRequest for creating/updating fee structure
 type CreateComFundsFeeRequest struct {
-	FeeType       string     `json:"fee_type" validate:"required,oneof=platform_fee success_fee transaction_fee"`
-	FeePercentage float64    `json:"fee_percentage" validate:"required,min=0,max=100"`
-	IsEnabled     bool       `json:"is_enabled"`
-	MinimumAmount float64    `json:"minimum_amount" validate:"min=0"`
-	MaximumAmount float64    `json:"maximum_amount" validate:"min=0"`
-	ApplicableTo  string     `json:"applicable_to" validate:"required,oneof=all_projects successful_funding specific_projects"`
-	ProjectID     *uuid.UUID `json:"project_id"`
-	CooperativeID *uuid.UUID `json:"cooperative_id"`
-	EffectiveFrom time.Time  `json:"effective_from" validate:"required"`
-	EffectiveTo   *time.Time `json:"effective_to"`
-	Description   string     `json:"description"`
+	FeeType       string            `json:"fee_type" validate:"required,oneof=platform_fee success_fee transaction_fee"`
+	FeePercentage float64           `json:"fee_percentage" validate:"required_without=Tiers,min=0,max=100"`
+	TierMode      string            `json:"tier_mode" validate:"omitempty,oneof=flat banded tiered"`
+	Tiers         []ComFundsFeeTier `json:"tiers" validate:"required_if=TierMode banded,required_if=TierMode tiered,dive"`
+	Priority      int               `json:"priority" validate:"min=0"`
+	Currency      string            `json:"currency" validate:"omitempty,len=3"`
+	IsEnabled     bool              `json:"is_enabled"`
+	MinimumAmount Money             `json:"minimum_amount" validate:"min=0"`
+	MaximumAmount Money             `json:"maximum_amount" validate:"min=0"`
+	ApplicableTo  string            `json:"applicable_to" validate:"required,oneof=all_projects successful_funding specific_projects"`
+	ProjectID     *uuid.UUID        `json:"project_id" validate:"required_if=ApplicableTo specific_projects"`
+	CooperativeID *uuid.UUID        `json:"cooperative_id"`
+	EffectiveFrom time.Time         `json:"effective_from" validate:"required"`
+	EffectiveTo   *time.Time        `json:"effective_to"`
+	Description   string            `json:"description"`
 }
 
 // CalculateProjectFeeRequest for calculating project fees
 type CalculateProjectFeeRequest struct {
 	ProjectID          uuid.UUID `json:"project_id" validate:"required"`
-	TotalFundingAmount float64   `json:"total_funding_amount" validate:"required,min=0"`
+	TotalFundingAmount Money     `json:"total_funding_amount" validate:"required,min=0"`
 	CalculateDate      time.Time `json:"calculate_date" validate:"required"`
+	// FundingClosureID charges the fee on a funding closure; a closure is
+	// charged once and later requests return its existing calculation
+	FundingClosureID *uuid.UUID `json:"-"`
 }
 
 // CollectProjectFeeRequest for collecting project fees
@@ -284,26 +320,26 @@ type ProjectFeeCalculationFilter struct {
 	FeeStatus     *string    `json:"fee_status"`
 	StartDate     *time.Time `json:"start_date"`
 	EndDate       *time.Time `json:"end_date"`
-	MinAmount     *float64   `json:"min_amount"`
-	MaxAmount     *float64   `json:"max_amount"`
+	MinAmount     *Money     `json:"min_amount"`
+	MaxAmount     *Money     `json:"max_amount"`
 	Page          int        `json:"page" validate:"min=1"`
 	Limit         int        `json:"limit" validate:"min=1,max=100"`
 }
 
 // ProfitSharingSummary for reporting
 type ProfitSharingSummary struct {
-	TotalCalculations      int     `json:"total_calculations"`
-	TotalProfit            float64 `json:"total_profit"`
-	TotalLoss              float64 `json:"total_loss"`
-	TotalDistributions     int     `json:"total_distributions"`
-	TotalDistributedAmount float64 `json:"total_distributed_amount"`
-	PendingDistributions   int     `json:"pending_distributions"`
-	PendingAmount          float64 `json:"pending_amount"`
-	TotalTaxAmount         float64 `json:"total_tax_amount"`
-	TotalTaxDocuments      int     `json:"total_tax_documents"`
-	TotalPlatformFees      float64 `json:"total_platform_fees"`
-	TotalFeeCollections    int     `json:"total_fee_collections"`
-	Currency               string  `json:"currency"`
+	TotalCalculations      int    `json:"total_calculations"`
+	TotalProfit            Money  `json:"total_profit"`
+	TotalLoss              Money  `json:"total_loss"`
+	TotalDistributions     int    `json:"total_distributions"`
+	TotalDistributedAmount Money  `json:"total_distributed_amount"`
+	PendingDistributions   int    `json:"pending_distributions"`
+	PendingAmount          Money  `json:"pending_amount"`
+	TotalTaxAmount         Money  `json:"total_tax_amount"`
+	TotalTaxDocuments      int    `json:"total_tax_documents"`
+	TotalPlatformFees      Money  `json:"total_platform_fees"`
+	TotalFeeCollections    int    `json:"total_fee_collections"`
+	Currency               string `json:"currency"`
 }
 
 // Profit constants
@@ -324,6 +360,7 @@ const (
 
 	ProfitDistributionTypeProfit           = "profit"
 	ProfitDistributionTypeLossCompensation = "loss_compensation"
+	ProfitDistributionTypeInstallment      = "murabahah_installment"
 
 	InvestorProfitShareStatusPending   = "pending"
 	InvestorProfitShareStatusProcessed = "processed"
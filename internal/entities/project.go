@@ -78,6 +78,7 @@ const (
 	ProjectStatusPendingApproval = "pending_approval"
 	ProjectStatusApproved        = "approved"
 	ProjectStatusActive          = "active"
+	ProjectStatusFunded          = "funded"
 	ProjectStatusCompleted       = "completed"
 	ProjectStatusCancelled       = "cancelled"
 	ProjectStatusRejected        = "rejected"
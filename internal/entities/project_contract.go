@@ -0,0 +1,112 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectContract is the financing contract a project runs under and its
+// contract-specific terms. Projects without one are profit-sharing mudarabah
+// under the default ratio.
+type ProjectContract struct {
+	ProjectID          uuid.UUID          `json:"project_id" db:"project_id"`
+	CooperativeID      uuid.UUID          `json:"cooperative_id" db:"cooperative_id"`
+	ContractType       string             `json:"contract_type" db:"contract_type"` // mudarabah, musyarakah, murabahah
+	Currency           string             `json:"currency" db:"currency"`
+	ProfitSharingRatio map[string]float64 `json:"profit_sharing_ratio" db:"profit_sharing_ratio"` // mudarabah and musyarakah
+
+	// Musyarakah: the business partner's own capital, invested alongside the
+	// investors'. Losses are shared in proportion to capital.
+	PartnerCapital  Money `json:"partner_capital" db:"partner_capital"`
+	InvestorCapital Money `json:"investor_capital" db:"-"` // the project's current funding
+
+	// Murabahah: the asset bought with investors' funds is sold to the business
+	// at cost plus an agreed markup, paid in installments
+	AssetDescription          string     `json:"asset_description" db:"asset_description"`
+	CostPrice                 Money      `json:"cost_price" db:"cost_price"`
+	Markup                    Money      `json:"markup" db:"markup"`
+	InstallmentCount          int        `json:"installment_count" db:"installment_count"`
+	InstallmentIntervalMonths int        `json:"installment_interval_months" db:"installment_interval_months"`
+	FirstInstallmentDate      *time.Time `json:"first_installment_date" db:"first_installment_date"`
+
+	CreatedBy    uuid.UUID               `json:"created_by" db:"created_by"`
+	CreatedAt    time.Time               `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time               `json:"updated_at" db:"updated_at"`
+	Installments []*MurabahahInstallment `json:"installments,omitempty" db:"-"`
+}
+
+// SalePrice returns the murabahah sale price the business pays in installments
+func (c *ProjectContract) SalePrice() Money {
+	return c.CostPrice.Add(c.Markup)
+}
+
+// InvestorCapitalRatio returns the investors' share of a musyarakah's capital
+// as a percentage. Losses are borne in this ratio.
+func (c *ProjectContract) InvestorCapitalRatio() float64 {
+	total := c.InvestorCapital.Add(c.PartnerCapital)
+	if !total.IsPositive() {
+		return 100
+	}
+	ratio, _ := c.InvestorCapital.Ratio(total).Float64()
+	return ratio * 100
+}
+
+// SetProjectContractRequest sets the contract a project runs under
+type SetProjectContractRequest struct {
+	ContractType              string             `json:"contract_type" validate:"required,oneof=mudarabah musyarakah murabahah"`
+	Currency                  string             `json:"currency" validate:"required,len=3"`
+	ProfitSharingRatio        map[string]float64 `json:"profit_sharing_ratio"`                                   // defaults to 70/25/5
+	PartnerCapital            Money              `json:"partner_capital"`                                        // musyarakah
+	AssetDescription          string             `json:"asset_description" validate:"max=1000"`                  // murabahah
+	CostPrice                 Money              `json:"cost_price"`                                             // murabahah
+	Markup                    Money              `json:"markup"`                                                 // murabahah
+	InstallmentCount          int                `json:"installment_count" validate:"omitempty,min=1,max=120"`   // murabahah
+	InstallmentIntervalMonths int                `json:"installment_interval_months" validate:"omitempty,min=1"` // murabahah, defaults to 1
+	FirstInstallmentDate      *time.Time         `json:"first_installment_date"`                                 // murabahah
+}
+
+// MurabahahInstallment is one scheduled payment of a murabahah sale price. Each
+// installment repays part of the cost price and part of the markup, and once
+// paid is settled to the project's investors.
+type MurabahahInstallment struct {
+	ID                   uuid.UUID  `json:"id" db:"id"`
+	ProjectID            uuid.UUID  `json:"project_id" db:"project_id"`
+	CooperativeID        uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
+	Sequence             int        `json:"sequence" db:"sequence"`
+	DueDate              time.Time  `json:"due_date" db:"due_date"`
+	Amount               Money      `json:"amount" db:"amount"`
+	PrincipalAmount      Money      `json:"principal_amount" db:"principal_amount"` // repays the cost price
+	ProfitAmount         Money      `json:"profit_amount" db:"profit_amount"`       // the markup
+	Currency             string     `json:"currency" db:"currency"`
+	Status               string     `json:"status" db:"status"` // scheduled, paid, settled
+	PaidAt               *time.Time `json:"paid_at" db:"paid_at"`
+	PaymentReference     string     `json:"payment_reference" db:"payment_reference"`
+	ProfitDistributionID *uuid.UUID `json:"profit_distribution_id" db:"profit_distribution_id"` // the settlement to investors
+	SettledAt            *time.Time `json:"settled_at" db:"settled_at"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsOverdue reports whether a scheduled installment is past its due date
+func (i *MurabahahInstallment) IsOverdue(now time.Time) bool {
+	return i.Status == MurabahahInstallmentStatusScheduled && now.After(i.DueDate)
+}
+
+// RecordInstallmentPaymentRequest records the business paying an installment
+type RecordInstallmentPaymentRequest struct {
+	Amount           Money      `json:"amount" validate:"required"`
+	PaymentReference string     `json:"payment_reference" validate:"required,max=255"`
+	PaidAt           *time.Time `json:"paid_at"` // defaults to now
+}
+
+// Contract constants
+const (
+	ContractTypeMudarabah  = "mudarabah"
+	ContractTypeMusyarakah = "musyarakah"
+	ContractTypeMurabahah  = "murabahah"
+
+	MurabahahInstallmentStatusScheduled = "scheduled"
+	MurabahahInstallmentStatusPaid      = "paid"
+	MurabahahInstallmentStatusSettled   = "settled"
+)
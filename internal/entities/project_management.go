@@ -15,16 +15,17 @@ type ProjectExtended struct {
 	CooperativeID         uuid.UUID              `json:"cooperative_id" db:"cooperative_id"`
 	OwnerID               uuid.UUID              `json:"owner_id" db:"owner_id"`
 	Category              string                 `json:"category" db:"category"` // startup, expansion, equipment, research
-	FundingGoal           float64                `json:"funding_goal" db:"funding_goal"`
+	FundingGoal           Money                  `json:"funding_goal" db:"funding_goal"`
 	Currency              string                 `json:"currency" db:"currency"`
-	CurrentFunding        float64                `json:"current_funding" db:"current_funding"`
+	CurrentFunding        Money                  `json:"current_funding" db:"current_funding"`
 	FundingProgress       float64                `json:"funding_progress" db:"funding_progress"` // percentage
-	MinFundingRequired    float64                `json:"min_funding_required" db:"min_funding_required"`
+	MinFundingRequired    Money                  `json:"min_funding_required" db:"min_funding_required"`
 	StartDate             time.Time              `json:"start_date" db:"start_date"`
 	EndDate               time.Time              `json:"end_date" db:"end_date"`
 	Duration              int                    `json:"duration" db:"duration"` // in days
 	Timeline              []ProjectMilestone     `json:"timeline" db:"timeline"`
 	ProfitSharingTerms    *ProfitSharingTerms    `json:"profit_sharing_terms" db:"profit_sharing_terms"`
+	ContractType          string                 `json:"contract_type" db:"contract_type"` // mudarabah, musyarakah, murabahah
 	IntendedUseOfFunds    string                 `json:"intended_use_of_funds" db:"intended_use_of_funds"`
 	DetailedUseOfFunds    map[string]interface{} `json:"detailed_use_of_funds" db:"detailed_use_of_funds"`
 	RiskLevel             string                 `json:"risk_level" db:"risk_level"` // low, medium, high
@@ -61,8 +62,8 @@ type ProjectMilestone struct {
 	CompletedAt *time.Time             `json:"completed_at" db:"completed_at"`
 	Status      string                 `json:"status" db:"status"` // pending, in_progress, completed, delayed, cancelled
 	Progress    float64                `json:"progress" db:"progress"` // percentage
-	Budget      float64                `json:"budget" db:"budget"`
-	Spent       float64                `json:"spent" db:"spent"`
+	Budget      Money                  `json:"budget" db:"budget"`
+	Spent       Money                  `json:"spent" db:"spent"`
 	Deliverables []string              `json:"deliverables" db:"deliverables"`
 	Notes       string                 `json:"notes" db:"notes"`
 	AssignedTo  *uuid.UUID             `json:"assigned_to" db:"assigned_to"`
@@ -77,7 +78,7 @@ type ProfitSharingTerms struct {
 	BusinessOwnerShare  float64                `json:"business_owner_share"` // Percentage for business owner
 	CooperativeShare    float64                `json:"cooperative_share"`    // Percentage for cooperative
 	DistributionMethod  string                 `json:"distribution_method"`  // monthly, quarterly, yearly, on_completion
-	MinProfitThreshold  float64                `json:"min_profit_threshold"` // Minimum profit before distribution
+	MinProfitThreshold  Money                  `json:"min_profit_threshold"` // Minimum profit before distribution
 	FirstDistribution   int                    `json:"first_distribution"`   // Months after project completion
 	RiskAdjustment      float64                `json:"risk_adjustment"`      // Risk-based adjustment factor
 	CustomTerms         map[string]interface{} `json:"custom_terms"`
@@ -144,9 +145,9 @@ type CreateProjectExtendedRequest struct {
 	Title                 string                 `json:"title" validate:"required,min=5,max=200"`
 	Description           string                 `json:"description" validate:"required,min=20,max=2000"`
 	Category              string                 `json:"category" validate:"required,oneof=startup expansion equipment research technology agriculture manufacturing services other"`
-	FundingGoal           float64                `json:"funding_goal" validate:"required,min=1000"`
+	FundingGoal           Money                  `json:"funding_goal" validate:"required,min=1000"`
 	Currency              string                 `json:"currency" validate:"required,len=3"`
-	MinFundingRequired    float64                `json:"min_funding_required" validate:"required,min=100"`
+	MinFundingRequired    Money                  `json:"min_funding_required" validate:"required,min=100"`
 	StartDate             time.Time              `json:"start_date" validate:"required"`
 	EndDate               time.Time              `json:"end_date" validate:"required"`
 	IntendedUseOfFunds    string                 `json:"intended_use_of_funds" validate:"required,min=10,max=500"`
@@ -155,6 +156,7 @@ type CreateProjectExtendedRequest struct {
 	ExpectedReturn        float64                `json:"expected_return" validate:"required,min=0,max=100"`
 	ExpectedReturnPeriod  int                    `json:"expected_return_period" validate:"required,min=1,max=60"`
 	ShariaCompliant       bool                   `json:"sharia_compliant"`
+	ContractType          string                 `json:"contract_type" validate:"omitempty,oneof=mudarabah musyarakah murabahah"` // defaults to mudarabah
 	ComplianceNotes       string                 `json:"compliance_notes" validate:"max=1000"`
 	FundingDeadline       time.Time              `json:"funding_deadline" validate:"required"`
 	Documents             []string               `json:"documents"`
@@ -167,8 +169,8 @@ type UpdateProjectExtendedRequest struct {
 	Title                 string                 `json:"title" validate:"min=5,max=200"`
 	Description           string                 `json:"description" validate:"min=20,max=2000"`
 	Category              string                 `json:"category" validate:"oneof=startup expansion equipment research technology agriculture manufacturing services other"`
-	FundingGoal           float64                `json:"funding_goal" validate:"min=1000"`
-	MinFundingRequired    float64                `json:"min_funding_required" validate:"min=100"`
+	FundingGoal           Money                  `json:"funding_goal" validate:"min=1000"`
+	MinFundingRequired    Money                  `json:"min_funding_required" validate:"min=100"`
 	EndDate               time.Time              `json:"end_date"`
 	IntendedUseOfFunds    string                 `json:"intended_use_of_funds" validate:"min=10,max=500"`
 	DetailedUseOfFunds    map[string]interface{} `json:"detailed_use_of_funds"`
@@ -188,7 +190,7 @@ type CreateMilestoneRequest struct {
 	Description  string                 `json:"description" validate:"required,min=10,max=500"`
 	Type         string                 `json:"type" validate:"required,oneof=planning development testing launch completion"`
 	DueDate      time.Time              `json:"due_date" validate:"required"`
-	Budget       float64                `json:"budget" validate:"min=0"`
+	Budget       Money                  `json:"budget" validate:"min=0"`
 	Deliverables []string               `json:"deliverables"`
 	Notes        string                 `json:"notes" validate:"max=1000"`
 	AssignedTo   *uuid.UUID             `json:"assigned_to"`
@@ -203,8 +205,8 @@ type UpdateMilestoneRequest struct {
 	DueDate      time.Time              `json:"due_date"`
 	Status       string                 `json:"status" validate:"oneof=pending in_progress completed delayed cancelled"`
 	Progress     float64                `json:"progress" validate:"min=0,max=100"`
-	Budget       float64                `json:"budget" validate:"min=0"`
-	Spent        float64                `json:"spent" validate:"min=0"`
+	Budget       Money                  `json:"budget" validate:"min=0"`
+	Spent        Money                  `json:"spent" validate:"min=0"`
 	Deliverables []string               `json:"deliverables"`
 	Notes        string                 `json:"notes" validate:"max=1000"`
 	AssignedTo   *uuid.UUID             `json:"assigned_to"`
@@ -235,8 +237,8 @@ type ProjectFilter struct {
 	Category         string     `json:"category"`
 	Status           string     `json:"status"`
 	RiskLevel        string     `json:"risk_level"`
-	MinFundingGoal   float64    `json:"min_funding_goal"`
-	MaxFundingGoal   float64    `json:"max_funding_goal"`
+	MinFundingGoal   Money      `json:"min_funding_goal"`
+	MaxFundingGoal   Money      `json:"max_funding_goal"`
 	ShariaCompliant  *bool      `json:"sharia_compliant"`
 	IsFunded         *bool      `json:"is_funded"`
 	Page             int        `json:"page"`
@@ -262,12 +264,12 @@ type ProjectRejectionRequest struct {
 // ProfitSharingProjection for FR-035
 type ProfitSharingProjection struct {
 	ProjectID           uuid.UUID              `json:"project_id"`
-	TotalInvestment     float64                `json:"total_investment"`
-	ExpectedProfit      float64                `json:"expected_profit"`
+	TotalInvestment     Money                  `json:"total_investment"`
+	ExpectedProfit      Money                  `json:"expected_profit"`
 	ExpectedReturnRate  float64                `json:"expected_return_rate"`
-	InvestorShare       float64                `json:"investor_share"`
-	BusinessOwnerShare  float64                `json:"business_owner_share"`
-	CooperativeShare    float64                `json:"cooperative_share"`
+	InvestorShare       Money                  `json:"investor_share"`
+	BusinessOwnerShare  Money                  `json:"business_owner_share"`
+	CooperativeShare    Money                  `json:"cooperative_share"`
 	DistributionSchedule []DistributionPeriod  `json:"distribution_schedule"`
 	RiskFactors         map[string]interface{} `json:"risk_factors"`
 	CalculatedAt        time.Time              `json:"calculated_at"`
@@ -277,7 +279,7 @@ type ProfitSharingProjection struct {
 type DistributionPeriod struct {
 	Period     string  `json:"period"`
 	Date       time.Time `json:"date"`
-	Amount     float64 `json:"amount"`
+	Amount     Money   `json:"amount"`
 	Percentage float64 `json:"percentage"`
 	Status     string  `json:"status"` // scheduled, completed, cancelled
 }
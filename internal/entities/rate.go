@@ -0,0 +1,131 @@
+package entities
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RateScale is the number of decimal places an exchange rate is stored with,
+// matching the NUMERIC(24,12) rate columns
+const RateScale = 12
+
+// Rate is an exact exchange rate. It is held as a rational number from the
+// database column through inverse and cross rates to the converted amount, so
+// that no precision is lost to binary floats. The zero value is a rate of 0.
+type Rate struct {
+	r *big.Rat
+}
+
+// NewRate returns a rate with the exact value of r
+func NewRate(r *big.Rat) Rate {
+	return Rate{r: new(big.Rat).Set(r)}
+}
+
+// ParseRate parses a decimal string such as "3350.125"
+func ParseRate(rate string) (Rate, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok {
+		return Rate{}, fmt.Errorf("invalid exchange rate %q", rate)
+	}
+	return Rate{r: r}, nil
+}
+
+// MustParseRate is like ParseRate but panics on error; for constants and tests
+func MustParseRate(rate string) Rate {
+	r, err := ParseRate(rate)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Rat returns the exact value of the rate
+func (r Rate) Rat() *big.Rat {
+	if r.r == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(r.r)
+}
+
+// IsPositive reports whether the rate is greater than zero
+func (r Rate) IsPositive() bool { return r.r != nil && r.r.Sign() > 0 }
+
+// Inv returns the inverse rate; the rate must not be zero
+func (r Rate) Inv() Rate {
+	return Rate{r: new(big.Rat).Inv(r.Rat())}
+}
+
+// Mul returns the product of two rates, as for a cross rate through a third currency
+func (r Rate) Mul(other Rate) Rate {
+	return Rate{r: new(big.Rat).Mul(r.Rat(), other.Rat())}
+}
+
+// Equal reports whether two rates have the same value
+func (r Rate) Equal(other Rate) bool { return r.Rat().Cmp(other.Rat()) == 0 }
+
+// String formats the rate as a decimal rounded to RateScale places, without
+// trailing zeros
+func (r Rate) String() string {
+	s := r.Rat().FloatString(RateScale)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// MarshalJSON encodes the rate as a decimal string so that clients never parse
+// it into a binary float
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a bare number
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*r = Rate{}
+		return nil
+	}
+
+	parsed, err := ParseRate(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements driver.Valuer; rates are stored as exact NUMERIC strings
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Scan implements sql.Scanner for NUMERIC columns
+func (r *Rate) Scan(src interface{}) error {
+	var rate string
+	switch v := src.(type) {
+	case nil:
+		*r = Rate{}
+		return nil
+	case []byte:
+		rate = string(v)
+	case string:
+		rate = v
+	case int64:
+		rate = strconv.FormatInt(v, 10)
+	case float64:
+		rate = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into Rate", src)
+	}
+
+	parsed, err := ParseRate(rate)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRate_InverseAndCrossStayExact(t *testing.T) {
+	usdIDR := MustParseRate("16000")
+	idrMYR := MustParseRate("0.0003")
+
+	// 16000 * 0.0003 is 4.800000000000001 in float64
+	assert.Equal(t, "4.8", usdIDR.Mul(idrMYR).String())
+	assert.Equal(t, 0, usdIDR.Mul(idrMYR).Rat().Cmp(big.NewRat(24, 5)))
+
+	// The inverse of a rate with no finite decimal keeps its exact value and is
+	// only rounded for display
+	inv := MustParseRate("3").Inv()
+	assert.Equal(t, "0.333333333333", inv.String())
+	converted := MustParseMoney("3000000000000", "SGD").Convert(inv.Rat(), "MYR", RoundHalfUp)
+	assert.Equal(t, "1000000000000.00 MYR", converted.String())
+}
+
+func TestRate_JSON(t *testing.T) {
+	rate := MustParseRate("3350.125")
+
+	data, err := json.Marshal(rate)
+	assert.NoError(t, err)
+	assert.Equal(t, `"3350.125"`, string(data))
+
+	var decoded Rate
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, rate.Equal(decoded))
+
+	var bare Rate
+	assert.NoError(t, json.Unmarshal([]byte(`0.000062500001`), &bare))
+	assert.Equal(t, "0.000062500001", bare.String())
+
+	assert.Error(t, json.Unmarshal([]byte(`"abc"`), &bare))
+}
+
+func TestRate_SQL(t *testing.T) {
+	var scanned Rate
+	assert.NoError(t, scanned.Scan([]byte("0.000062500000")))
+	assert.True(t, MustParseRate("0.0000625").Equal(scanned))
+	assert.True(t, scanned.IsPositive())
+
+	value, err := scanned.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0000625", value)
+
+	assert.NoError(t, scanned.Scan(nil))
+	assert.False(t, scanned.IsPositive())
+}
@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReinvestmentPreference is an investor's standing instruction to roll part of
+// their profit shares from a cooperative's projects into a new investment
+type ReinvestmentPreference struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	InvestorID      uuid.UUID  `json:"investor_id" db:"investor_id"`
+	CooperativeID   uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
+	Percentage      float64    `json:"percentage" db:"percentage"`   // of the net profit share
+	TargetType      string     `json:"target_type" db:"target_type"` // same_project, project, any_eligible
+	TargetProjectID *uuid.UUID `json:"target_project_id,omitempty" db:"target_project_id"`
+	IsActive        bool       `json:"is_active" db:"is_active"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// SetReinvestmentPreferenceRequest creates or replaces an investor's preference for a cooperative
+type SetReinvestmentPreferenceRequest struct {
+	CooperativeID   uuid.UUID  `json:"cooperative_id" validate:"required"`
+	Percentage      float64    `json:"percentage" validate:"required,gt=0,lte=100"`
+	TargetType      string     `json:"target_type" validate:"required,oneof=same_project project any_eligible"`
+	TargetProjectID *uuid.UUID `json:"target_project_id"` // required when target_type is project
+}
+
+// Reinvestment records what happened to one profit share's reinvestment: either
+// the investment it created or why the amount was paid out instead
+type Reinvestment struct {
+	ID                   uuid.UUID  `json:"id" db:"id"`
+	PreferenceID         uuid.UUID  `json:"preference_id" db:"preference_id"`
+	ProfitDistributionID uuid.UUID  `json:"profit_distribution_id" db:"profit_distribution_id"`
+	ProfitShareID        uuid.UUID  `json:"profit_share_id" db:"profit_share_id"`
+	InvestorID           uuid.UUID  `json:"investor_id" db:"investor_id"`
+	CooperativeID        uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
+	SourceProjectID      uuid.UUID  `json:"source_project_id" db:"source_project_id"`
+	TargetProjectID      *uuid.UUID `json:"target_project_id,omitempty" db:"target_project_id"`
+	InvestmentID         *uuid.UUID `json:"investment_id,omitempty" db:"investment_id"`
+	Amount               Money      `json:"amount" db:"amount"`
+	Currency             string     `json:"currency" db:"currency"`
+	Status               string     `json:"status" db:"status"` // invested, skipped
+	Reason               string     `json:"reason,omitempty" db:"reason"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Reinvestment constants
+const (
+	ReinvestmentTargetSameProject = "same_project"
+	ReinvestmentTargetProject     = "project"
+	ReinvestmentTargetAnyEligible = "any_eligible"
+
+	ReinvestmentStatusInvested = "invested"
+	ReinvestmentStatusSkipped  = "skipped"
+)
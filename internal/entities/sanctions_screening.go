@@ -0,0 +1,146 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SanctionsListEntry is a designated person or organization on a watchlist
+type SanctionsListEntry struct {
+	ListName    string               `json:"list_name"`
+	EntryID     string               `json:"entry_id"`
+	EntryType   string               `json:"entry_type"` // individual, entity
+	Name        string               `json:"name"`
+	Aliases     []string             `json:"aliases,omitempty"`
+	BirthDates  []SanctionsBirthDate `json:"birth_dates,omitempty"`
+	Nationality string               `json:"nationality,omitempty"`
+	Program     string               `json:"program,omitempty"`
+}
+
+// SanctionsBirthDate is a date of birth as precise as the list gives it;
+// Month and Day are zero when only the year is known
+type SanctionsBirthDate struct {
+	Year  int `json:"year"`
+	Month int `json:"month,omitempty"`
+	Day   int `json:"day,omitempty"`
+}
+
+// String formats the date as YYYY, YYYY-MM or YYYY-MM-DD
+func (d SanctionsBirthDate) String() string {
+	switch {
+	case d.Month == 0:
+		return fmt.Sprintf("%04d", d.Year)
+	case d.Day == 0:
+		return fmt.Sprintf("%04d-%02d", d.Year, d.Month)
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// SanctionsList is a watchlist as last loaded from its file
+type SanctionsList struct {
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	Format     string    `json:"format"` // csv, xml
+	EntryCount int       `json:"entry_count"`
+	ModifiedAt time.Time `json:"modified_at"` // the file's modification time
+	LoadedAt   time.Time `json:"loaded_at"`
+	LoadError  string    `json:"load_error,omitempty"` // the last reload failed; the previous entries stay in use
+}
+
+// SanctionsScreeningSubject is who is being screened and why. Reference
+// identifies the subject across screenings, so reviewed matches are not
+// raised again for them.
+type SanctionsScreeningSubject struct {
+	SubjectType   string     `json:"subject_type"` // individual, business, beneficiary
+	Reference     string     `json:"reference"`
+	SubjectID     *uuid.UUID `json:"subject_id"`
+	Name          string     `json:"name"`
+	DateOfBirth   *time.Time `json:"date_of_birth"`
+	Purpose       string     `json:"purpose"` // registration, membership, business, payout
+	CooperativeID *uuid.UUID `json:"cooperative_id"`
+}
+
+// SanctionsMatch is a list entry a subject's name resembles
+type SanctionsMatch struct {
+	ListName    string   `json:"list_name"`
+	EntryID     string   `json:"entry_id"`
+	EntryName   string   `json:"entry_name"`
+	MatchedName string   `json:"matched_name"` // the entry's name or alias that matched
+	NameScore   float64  `json:"name_score"`   // 0 to 1
+	BirthDates  []string `json:"birth_dates,omitempty"`
+	// DateOfBirthMatched is set when the subject's date of birth agrees with
+	// one of the entry's; when either side has none it is not compared
+	DateOfBirthMatched bool   `json:"date_of_birth_matched"`
+	Program            string `json:"program,omitempty"`
+}
+
+// Key identifies the list entry matched
+func (m *SanctionsMatch) Key() string {
+	return m.ListName + "/" + m.EntryID
+}
+
+// SanctionsScreening is the outcome of screening a subject. Potential
+// matches hold the action that triggered the screening until a compliance
+// officer clears or confirms them.
+type SanctionsScreening struct {
+	ID                 uuid.UUID         `json:"id" db:"id"`
+	SubjectType        string            `json:"subject_type" db:"subject_type"`
+	SubjectReference   string            `json:"subject_reference" db:"subject_reference"`
+	SubjectID          *uuid.UUID        `json:"subject_id" db:"subject_id"`
+	SubjectName        string            `json:"subject_name" db:"subject_name"`
+	SubjectDateOfBirth *time.Time        `json:"subject_date_of_birth" db:"subject_date_of_birth"`
+	Purpose            string            `json:"purpose" db:"purpose"`
+	CooperativeID      *uuid.UUID        `json:"cooperative_id" db:"cooperative_id"`
+	Status             string            `json:"status" db:"status"` // clear, pending_review, cleared, confirmed
+	Matches            []*SanctionsMatch `json:"matches" db:"matches"`
+	ReviewedBy         *uuid.UUID        `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt         *time.Time        `json:"reviewed_at" db:"reviewed_at"`
+	ReviewNotes        string            `json:"review_notes,omitempty" db:"review_notes"`
+	CreatedAt          time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// IsBlocking reports whether the screened action must not go ahead
+func (s *SanctionsScreening) IsBlocking() bool {
+	return s.Status == SanctionsScreeningStatusPendingReview || s.Status == SanctionsScreeningStatusConfirmed
+}
+
+// SanctionsScreeningFilter for querying screenings
+type SanctionsScreeningFilter struct {
+	Status    string `json:"status"`
+	Reference string `json:"reference"`
+	Page      int    `json:"page"`
+	Limit     int    `json:"limit"`
+}
+
+// ReviewSanctionsScreeningRequest records a compliance officer's decision on
+// a screening's potential matches
+type ReviewSanctionsScreeningRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=cleared confirmed"`
+	Notes    string `json:"notes" validate:"required,max=2000"`
+}
+
+// Sanctions screening constants
+const (
+	SanctionsEntryTypeIndividual = "individual"
+	SanctionsEntryTypeEntity     = "entity"
+
+	SanctionsListFormatCSV = "csv"
+	SanctionsListFormatXML = "xml"
+
+	SanctionsSubjectIndividual  = "individual"
+	SanctionsSubjectBusiness    = "business"
+	SanctionsSubjectBeneficiary = "beneficiary"
+
+	SanctionsPurposeRegistration = "registration"
+	SanctionsPurposeMembership   = "membership"
+	SanctionsPurposeBusiness     = "business"
+	SanctionsPurposePayout       = "payout"
+
+	SanctionsScreeningStatusClear         = "clear"
+	SanctionsScreeningStatusPendingReview = "pending_review"
+	SanctionsScreeningStatusCleared       = "cleared"
+	SanctionsScreeningStatusConfirmed     = "confirmed"
+)
@@ -0,0 +1,113 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShariaScreeningRuleSet is the screening rules a Sharia supervisory board sets
+// for a cooperative. A nil ratio threshold or an empty list disables that rule.
+type ShariaScreeningRuleSet struct {
+	ID                      uuid.UUID `json:"id" db:"id"`
+	CooperativeID           uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	SupervisoryBoard        string    `json:"supervisory_board" db:"supervisory_board"` // e.g. DSN-MUI or the cooperative's own board
+	Name                    string    `json:"name" db:"name"`
+	ProhibitedSectors       []string  `json:"prohibited_sectors" db:"prohibited_sectors"`
+	ProhibitedKeywords      []string  `json:"prohibited_keywords" db:"prohibited_keywords"`
+	MaxDebtToAssetRatio     *float64  `json:"max_debt_to_asset_ratio" db:"max_debt_to_asset_ratio"`     // percentage
+	MaxInterestIncomeRatio  *float64  `json:"max_interest_income_ratio" db:"max_interest_income_ratio"` // percentage of total revenue
+	GuaranteedReturnPhrases []string  `json:"guaranteed_return_phrases" db:"guaranteed_return_phrases"` // flag profit terms promising a return
+	IsActive                bool      `json:"is_active" db:"is_active"`
+	CreatedBy               uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt               time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt               time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateShariaScreeningRuleSetRequest sets a supervisory board's screening rules for a cooperative
+type CreateShariaScreeningRuleSetRequest struct {
+	SupervisoryBoard        string   `json:"supervisory_board" validate:"required,max=100"`
+	Name                    string   `json:"name" validate:"required,min=3,max=255"`
+	ProhibitedSectors       []string `json:"prohibited_sectors"`
+	ProhibitedKeywords      []string `json:"prohibited_keywords"`
+	MaxDebtToAssetRatio     *float64 `json:"max_debt_to_asset_ratio" validate:"omitempty,min=0,max=100"`
+	MaxInterestIncomeRatio  *float64 `json:"max_interest_income_ratio" validate:"omitempty,min=0,max=100"`
+	GuaranteedReturnPhrases []string `json:"guaranteed_return_phrases"`
+}
+
+// ShariaScreeningSubject is what a rule set is evaluated against. Rules whose
+// inputs are missing are reported as not applicable.
+type ShariaScreeningSubject struct {
+	BusinessName       string                   `json:"business_name"`
+	Sector             string                   `json:"sector"`
+	Activity           string                   `json:"activity"`          // free text describing the business or project
+	ProfitTerms        string                   `json:"profit_terms"`      // how investors are promised to be paid
+	FixedReturnRate    *float64                 `json:"fixed_return_rate"` // percentage promised regardless of outcome
+	ProfitSharingRatio map[string]float64       `json:"profit_sharing_ratio"`
+	FinancialReport    *BusinessFinancialReport `json:"financial_report"`
+}
+
+// ShariaRuleResult explains how one rule was evaluated
+type ShariaRuleResult struct {
+	Rule   string `json:"rule"`
+	Status string `json:"status"` // passed, failed, not_applicable
+	Detail string `json:"detail"`
+}
+
+// ShariaScreeningResult is the outcome of one rule set
+type ShariaScreeningResult struct {
+	RuleSetID        uuid.UUID          `json:"rule_set_id"`
+	SupervisoryBoard string             `json:"supervisory_board"`
+	RuleSetName      string             `json:"rule_set_name"`
+	Compliant        bool               `json:"compliant"`
+	Rules            []ShariaRuleResult `json:"rules"`
+}
+
+// ShariaScreeningEvaluation is the outcome of every rule set that applies to a
+// cooperative; it is compliant only if each of them is
+type ShariaScreeningEvaluation struct {
+	CooperativeID uuid.UUID                `json:"cooperative_id"`
+	ProjectID     *uuid.UUID               `json:"project_id,omitempty"`
+	Compliant     bool                     `json:"compliant"`
+	Results       []*ShariaScreeningResult `json:"results"`
+	EvaluatedAt   time.Time                `json:"evaluated_at"`
+}
+
+// Failures describes each failed rule, prefixed with the board that set it
+func (e *ShariaScreeningEvaluation) Failures() []string {
+	var failures []string
+	for _, result := range e.Results {
+		for _, rule := range result.Rules {
+			if rule.Status == ShariaRuleStatusFailed {
+				failures = append(failures, fmt.Sprintf("%s: %s", result.SupervisoryBoard, rule.Detail))
+			}
+		}
+	}
+	return failures
+}
+
+// ShariaProjectActivity is the business activity of a project, as screened
+type ShariaProjectActivity struct {
+	ProjectID           uuid.UUID `json:"project_id"`
+	CooperativeID       uuid.UUID `json:"cooperative_id"`
+	ProjectTitle        string    `json:"project_title"`
+	ProjectDescription  string    `json:"project_description"`
+	BusinessName        string    `json:"business_name"`
+	BusinessType        string    `json:"business_type"`
+	BusinessDescription string    `json:"business_description"`
+}
+
+// Sharia screening constants
+const (
+	ShariaRuleProfitSharingRatio  = "profit_sharing_ratio"
+	ShariaRuleProhibitedSector    = "prohibited_sector"
+	ShariaRuleProhibitedKeyword   = "prohibited_keyword"
+	ShariaRuleDebtToAssetRatio    = "debt_to_asset_ratio"
+	ShariaRuleInterestIncomeRatio = "interest_income_ratio"
+	ShariaRuleGuaranteedReturn    = "guaranteed_return"
+
+	ShariaRuleStatusPassed        = "passed"
+	ShariaRuleStatusFailed        = "failed"
+	ShariaRuleStatusNotApplicable = "not_applicable"
+)
@@ -0,0 +1,103 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StakeListing offers all or part of a member's investment to other members of
+// the same cooperative. The stake carries the capital and its future profit
+// shares; the price is agreed between the members.
+type StakeListing struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	InvestmentID  uuid.UUID `json:"investment_id" db:"investment_id"`
+	SellerID      uuid.UUID `json:"seller_id" db:"seller_id"`
+	ProjectID     uuid.UUID `json:"project_id" db:"project_id"`
+	CooperativeID uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	Currency      string    `json:"currency" db:"currency"`
+	Amount        Money     `json:"amount" db:"amount"` // capital offered
+	AskPrice      Money     `json:"ask_price" db:"ask_price"`
+	Status        string    `json:"status" db:"status"` // open, pending_approval, sold, cancelled
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+
+	Bids []*StakeBid `json:"bids,omitempty" db:"-"`
+}
+
+// StakeBid is a member's offer to buy a listed stake at a price
+type StakeBid struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	ListingID uuid.UUID `json:"listing_id" db:"listing_id"`
+	ProjectID uuid.UUID `json:"project_id" db:"project_id"`
+	BuyerID   uuid.UUID `json:"buyer_id" db:"buyer_id"`
+	Price     Money     `json:"price" db:"price"`
+	Status    string    `json:"status" db:"status"` // open, accepted, rejected, withdrawn
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// StakeTrade is an agreed sale of a listed stake awaiting, or settled by, the
+// cooperative's approval. On approval the capital moves from the seller's
+// investment to the buyer's and both members' ownership is recalculated.
+type StakeTrade struct {
+	ID                 uuid.UUID  `json:"id" db:"id"`
+	ListingID          uuid.UUID  `json:"listing_id" db:"listing_id"`
+	BidID              *uuid.UUID `json:"bid_id" db:"bid_id"` // nil when bought at the asking price
+	ProjectID          uuid.UUID  `json:"project_id" db:"project_id"`
+	CooperativeID      uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
+	SellerID           uuid.UUID  `json:"seller_id" db:"seller_id"`
+	BuyerID            uuid.UUID  `json:"buyer_id" db:"buyer_id"`
+	SellerInvestmentID uuid.UUID  `json:"seller_investment_id" db:"seller_investment_id"`
+	BuyerInvestmentID  *uuid.UUID `json:"buyer_investment_id" db:"buyer_investment_id"` // set on completion
+	Currency           string     `json:"currency" db:"currency"`
+	Amount             Money      `json:"amount" db:"amount"` // capital transferred
+	Price              Money      `json:"price" db:"price"`   // paid by the buyer to the seller
+	Status             string     `json:"status" db:"status"` // pending_approval, completed, rejected
+
+	PaymentReference     string     `json:"payment_reference" db:"payment_reference"` // the buyer's payment to the seller
+	ReviewedBy           *uuid.UUID `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt           *time.Time `json:"reviewed_at" db:"reviewed_at"`
+	ReviewNotes          string     `json:"review_notes" db:"review_notes"`
+	SellerOwnershipAfter float64    `json:"seller_ownership_after" db:"seller_ownership_after"` // percentage of the project's funding
+	BuyerOwnershipAfter  float64    `json:"buyer_ownership_after" db:"buyer_ownership_after"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreateStakeListingRequest lists all or part of an investment for sale
+type CreateStakeListingRequest struct {
+	ProjectID uuid.UUID `json:"project_id" validate:"required"`
+	Amount    *Money    `json:"amount"` // defaults to the whole investment
+	AskPrice  Money     `json:"ask_price" validate:"required"`
+}
+
+// PlaceStakeBidRequest bids for a listed stake
+type PlaceStakeBidRequest struct {
+	Price Money `json:"price" validate:"required"`
+}
+
+// ReviewStakeTradeRequest records the cooperative's decision on a trade
+type ReviewStakeTradeRequest struct {
+	Approve          bool   `json:"approve"`
+	PaymentReference string `json:"payment_reference" validate:"required_if=Approve true,max=255"`
+	Notes            string `json:"notes" validate:"max=1000"`
+}
+
+// Stake market constants
+const (
+	StakeListingStatusOpen            = "open"
+	StakeListingStatusPendingApproval = "pending_approval"
+	StakeListingStatusSold            = "sold"
+	StakeListingStatusCancelled       = "cancelled"
+
+	StakeBidStatusOpen      = "open"
+	StakeBidStatusAccepted  = "accepted"
+	StakeBidStatusRejected  = "rejected"
+	StakeBidStatusWithdrawn = "withdrawn"
+
+	StakeTradeStatusPendingApproval = "pending_approval"
+	StakeTradeStatusCompleting      = "completing" // approved, transfer being recorded
+	StakeTradeStatusCompleted       = "completed"
+	StakeTradeStatusRejected        = "rejected"
+)
@@ -0,0 +1,178 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaxJurisdiction describes a tax authority whose withholding rules a
+// cooperative follows. Tax withheld in a period must be remitted within
+// RemittanceDays of the period's end.
+type TaxJurisdiction struct {
+	Code           string `json:"code"`
+	Name           string `json:"name"`
+	Currency       string `json:"currency"`
+	RemittanceDays int    `json:"remittance_days"`
+}
+
+// TaxJurisdictions are the jurisdictions withholding rates can be configured for
+var TaxJurisdictions = map[string]TaxJurisdiction{
+	TaxJurisdictionIndonesia: {Code: TaxJurisdictionIndonesia, Name: "Indonesia", Currency: "IDR", RemittanceDays: 15},
+	TaxJurisdictionMalaysia:  {Code: TaxJurisdictionMalaysia, Name: "Malaysia", Currency: "MYR", RemittanceDays: 30},
+}
+
+// TaxWithholdingRate is the rate withheld from profit paid to one type of
+// investor under a jurisdiction, from EffectiveFrom until it is superseded by a
+// later rate or EffectiveTo passes
+type TaxWithholdingRate struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	Jurisdiction  string     `json:"jurisdiction" db:"jurisdiction"`
+	InvestorType  string     `json:"investor_type" db:"investor_type"` // individual, corporate
+	HasTaxID      bool       `json:"has_tax_id" db:"has_tax_id"`
+	Rate          float64    `json:"rate" db:"rate"` // percentage of the taxable amount
+	EffectiveFrom time.Time  `json:"effective_from" db:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to" db:"effective_to"`
+	Description   string     `json:"description" db:"description"`
+	IsActive      bool       `json:"is_active" db:"is_active"`
+	CreatedBy     uuid.UUID  `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// AppliesTo reports whether the rate covers the investor at the given time
+func (r *TaxWithholdingRate) AppliesTo(jurisdiction string, investorType string, hasTaxID bool, at time.Time) bool {
+	if !r.IsActive || r.Jurisdiction != jurisdiction || r.InvestorType != investorType || r.HasTaxID != hasTaxID {
+		return false
+	}
+	return !at.Before(r.EffectiveFrom) && (r.EffectiveTo == nil || at.Before(*r.EffectiveTo))
+}
+
+// TaxJurisdictionProfile is a jurisdiction with its withholding rates
+type TaxJurisdictionProfile struct {
+	TaxJurisdiction
+	Rates []*TaxWithholdingRate `json:"rates"`
+}
+
+// InvestorTaxProfile is how an investor is treated for withholding. A tax ID
+// only counts in the jurisdiction of the investor's tax residence.
+type InvestorTaxProfile struct {
+	InvestorID   uuid.UUID `json:"investor_id" db:"investor_id"`
+	InvestorType string    `json:"investor_type" db:"investor_type"`
+	TaxID        string    `json:"tax_id" db:"tax_id"`
+	TaxResidence string    `json:"tax_residence" db:"tax_residence"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// HasTaxIDIn reports whether the investor holds a tax ID in the jurisdiction
+func (p *InvestorTaxProfile) HasTaxIDIn(jurisdiction string) bool {
+	return p.TaxID != "" && p.TaxResidence == jurisdiction
+}
+
+// TaxWithholding records the tax withheld from an investor's share of a distribution
+type TaxWithholding struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	DistributionID uuid.UUID `json:"distribution_id" db:"distribution_id"`
+	CooperativeID  uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	ProjectID      uuid.UUID `json:"project_id" db:"project_id"`
+	InvestmentID   uuid.UUID `json:"investment_id" db:"investment_id"`
+	InvestorID     uuid.UUID `json:"investor_id" db:"investor_id"`
+	Jurisdiction   string    `json:"jurisdiction" db:"jurisdiction"`
+	InvestorType   string    `json:"investor_type" db:"investor_type"`
+	HasTaxID       bool      `json:"has_tax_id" db:"has_tax_id"`
+	RateID         uuid.UUID `json:"rate_id" db:"rate_id"`
+	Rate           float64   `json:"rate" db:"rate"`
+	TaxableAmount  Money     `json:"taxable_amount" db:"taxable_amount"`
+	TaxAmount      Money     `json:"tax_amount" db:"tax_amount"`
+	Currency       string    `json:"currency" db:"currency"`
+	WithheldAt     time.Time `json:"withheld_at" db:"withheld_at"`
+}
+
+// TaxWithholdingFilter selects a cooperative's withholdings for a report
+type TaxWithholdingFilter struct {
+	DistributionID *uuid.UUID
+	Start          *time.Time
+	End            *time.Time // exclusive
+	Currency       string
+}
+
+// TaxWithholdingReportLine totals the withholdings at one rate for one type of investor
+type TaxWithholdingReportLine struct {
+	InvestorType     string  `json:"investor_type" db:"investor_type"`
+	HasTaxID         bool    `json:"has_tax_id" db:"has_tax_id"`
+	Rate             float64 `json:"rate" db:"rate"`
+	WithholdingCount int     `json:"withholding_count" db:"withholding_count"`
+	InvestorCount    int     `json:"investor_count" db:"investor_count"`
+	TaxableAmount    Money   `json:"taxable_amount" db:"taxable_amount"`
+	TaxAmount        Money   `json:"tax_amount" db:"tax_amount"`
+}
+
+// TaxWithholdingReport aggregates the tax a cooperative withheld in a period,
+// or on one distribution, for remittance and tax documents
+type TaxWithholdingReport struct {
+	CooperativeID      uuid.UUID                  `json:"cooperative_id"`
+	DistributionID     *uuid.UUID                 `json:"distribution_id,omitempty"`
+	Jurisdiction       string                     `json:"jurisdiction"`
+	TaxPeriod          string                     `json:"tax_period"` // monthly, quarterly, annual
+	PeriodStart        time.Time                  `json:"period_start"`
+	PeriodEnd          time.Time                  `json:"period_end"` // exclusive
+	DueDate            time.Time                  `json:"due_date"`
+	Currency           string                     `json:"currency"`
+	Lines              []TaxWithholdingReportLine `json:"lines"`
+	TotalTaxableAmount Money                      `json:"total_taxable_amount"`
+	TotalTaxAmount     Money                      `json:"total_tax_amount"`
+}
+
+// EffectiveRate returns the tax withheld as a percentage of the taxable amount
+func (r *TaxWithholdingReport) EffectiveRate() float64 {
+	if !r.TotalTaxableAmount.IsPositive() {
+		return 0
+	}
+	rate, _ := r.TotalTaxAmount.Ratio(r.TotalTaxableAmount).Float64()
+	return rate * 100
+}
+
+// CreateTaxWithholdingRateRequest configures a jurisdiction's withholding rate
+type CreateTaxWithholdingRateRequest struct {
+	Jurisdiction  string     `json:"jurisdiction" validate:"required,oneof=ID MY"`
+	InvestorType  string     `json:"investor_type" validate:"required,oneof=individual corporate"`
+	HasTaxID      bool       `json:"has_tax_id"`
+	Rate          float64    `json:"rate" validate:"min=0,max=100"`
+	EffectiveFrom time.Time  `json:"effective_from" validate:"required"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+	Description   string     `json:"description"`
+}
+
+// SaveInvestorTaxProfileRequest sets how an investor is treated for withholding
+type SaveInvestorTaxProfileRequest struct {
+	InvestorType string `json:"investor_type" validate:"required,oneof=individual corporate"`
+	TaxID        string `json:"tax_id" validate:"max=50"`
+	TaxResidence string `json:"tax_residence" validate:"required,len=2,uppercase"`
+}
+
+// SetCooperativeTaxJurisdictionRequest sets the jurisdiction a cooperative withholds under
+type SetCooperativeTaxJurisdictionRequest struct {
+	Jurisdiction string `json:"jurisdiction" validate:"required,oneof=ID MY"`
+}
+
+// TaxReportPeriod returns the month, quarter or year containing t
+func TaxReportPeriod(taxPeriod string, t time.Time) (time.Time, time.Time) {
+	switch taxPeriod {
+	case TaxPeriodAnnual:
+		return StatementPeriod(StatementPeriodYearly, t)
+	case TaxPeriodQuarterly:
+		return StatementPeriod(StatementPeriodQuarterly, t)
+	default:
+		return StatementPeriod(StatementPeriodMonthly, t)
+	}
+}
+
+// Tax withholding constants
+const (
+	TaxJurisdictionIndonesia = "ID"
+	TaxJurisdictionMalaysia  = "MY"
+
+	TaxInvestorTypeIndividual = "individual"
+	TaxInvestorTypeCorporate  = "corporate"
+)
@@ -16,6 +16,7 @@ type User struct {
 	CooperativeID *uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
 	Roles            []string   `json:"roles" db:"roles"`
 	KYCStatus        string     `json:"kyc_status" db:"kyc_status"`
+	DateOfBirth      *time.Time `json:"date_of_birth" db:"date_of_birth"`
 	UserProfileImage *string    `json:"user_profile_image" db:"user_profile_image"`
 	IsActive         bool       `json:"is_active" db:"is_active"`
 	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
@@ -29,6 +30,7 @@ type CreateUserRequest struct {
 	Phone         string     `json:"phone" validate:"required"`
 	Address          string     `json:"address" validate:"required"`
 	CooperativeID    *uuid.UUID `json:"cooperative_id"`
+	DateOfBirth      *time.Time `json:"date_of_birth"` // screened against sanctions lists
 	UserProfileImage *string    `json:"user_profile_image" validate:"omitempty,url,max=500"`
 	Roles            []string   `json:"roles" validate:"required,dive,oneof=guest member business_owner investor admin"`
 }
@@ -0,0 +1,111 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ZakatHolding is one investment valued for zakat: its capital plus the profit
+// shared to it but not yet distributed
+type ZakatHolding struct {
+	InvestmentID        uuid.UUID       `json:"investment_id"`
+	ProjectID           uuid.UUID       `json:"project_id"`
+	CooperativeID       uuid.UUID       `json:"cooperative_id"`
+	Principal           Money           `json:"principal"`
+	UndistributedProfit Money           `json:"undistributed_profit"`
+	Currency            string          `json:"currency"`
+	HeldSince           time.Time       `json:"held_since"`
+	HaulCompleted       bool            `json:"haul_completed"` // held for a full haul by the valuation date
+	Value               ConvertedAmount `json:"value"`          // principal plus undistributed profit in the base currency
+	Zakatable           bool            `json:"zakatable"`
+}
+
+// ZakatStatement is an investor's zakat assessment of their portfolio at a valuation date
+type ZakatStatement struct {
+	InvestorID       uuid.UUID       `json:"investor_id"`
+	AsOf             time.Time       `json:"as_of"`
+	Currency         string          `json:"currency"` // base currency every amount is valued in
+	Nisab            Money           `json:"nisab"`
+	Rate             float64         `json:"rate"` // percentage
+	HaulDays         int             `json:"haul_days"`
+	HaulRule         string          `json:"haul_rule"` // per_holding, portfolio
+	Holdings         []*ZakatHolding `json:"holdings"`
+	TotalValue       Money           `json:"total_value"`
+	ZakatableValue   Money           `json:"zakatable_value"`
+	NisabReached     bool            `json:"nisab_reached"`
+	ZakatDue         Money           `json:"zakat_due"`
+	ZakatPaid        Money           `json:"zakat_paid"` // confirmed payments within the haul ending at the valuation date
+	ZakatOutstanding Money           `json:"zakat_outstanding"`
+	GeneratedAt      time.Time       `json:"generated_at"`
+}
+
+// ZakatRecipient is the account a cooperative designates for its members' zakat
+type ZakatRecipient struct {
+	CooperativeID uuid.UUID `json:"cooperative_id" db:"cooperative_id"`
+	Name          string    `json:"name" db:"name"` // e.g. the cooperative's amil or a national zakat agency
+	AccountName   string    `json:"account_name" db:"account_name"`
+	AccountNumber string    `json:"account_number" db:"account_number"`
+	BankCode      string    `json:"bank_code" db:"bank_code"`
+	IsActive      bool      `json:"is_active" db:"is_active"`
+	UpdatedBy     uuid.UUID `json:"updated_by" db:"updated_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SetZakatRecipientRequest designates a cooperative's zakat recipient account
+type SetZakatRecipientRequest struct {
+	Name          string `json:"name" validate:"required,min=3,max=255"`
+	AccountName   string `json:"account_name" validate:"required,max=140"`
+	AccountNumber string `json:"account_number" validate:"required,max=34"`
+	BankCode      string `json:"bank_code" validate:"required,max=11"`
+}
+
+// ZakatPayment is an investor's zakat transfer to a cooperative's designated recipient
+type ZakatPayment struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	InvestorID        uuid.UUID  `json:"investor_id" db:"investor_id"`
+	CooperativeID     uuid.UUID  `json:"cooperative_id" db:"cooperative_id"`
+	RecipientName     string     `json:"recipient_name" db:"recipient_name"`
+	RecipientAccount  string     `json:"recipient_account" db:"recipient_account"`
+	RecipientBankCode string     `json:"recipient_bank_code" db:"recipient_bank_code"`
+	Amount            Money      `json:"amount" db:"amount"`
+	Currency          string     `json:"currency" db:"currency"`
+	Reference         string     `json:"reference" db:"reference"` // quoted by the investor on the transfer
+	Status            string     `json:"status" db:"status"`       // pending, paid, cancelled
+	BankReference     string     `json:"bank_reference" db:"bank_reference"`
+	PaidAt            *time.Time `json:"paid_at" db:"paid_at"`
+	ConfirmedBy       *uuid.UUID `json:"confirmed_by" db:"confirmed_by"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreateZakatPaymentRequest starts a zakat payment to a cooperative's recipient
+type CreateZakatPaymentRequest struct {
+	CooperativeID uuid.UUID `json:"cooperative_id" validate:"required"`
+	Amount        Money     `json:"amount" validate:"required,gt=0"`
+	Currency      string    `json:"currency" validate:"required,len=3"`
+}
+
+// ConfirmZakatPaymentRequest records that the recipient received a zakat payment
+type ConfirmZakatPaymentRequest struct {
+	BankReference string     `json:"bank_reference" validate:"required,max=100"`
+	PaidAt        *time.Time `json:"paid_at"` // defaults to the confirmation time
+}
+
+// ZakatPaymentReference is the transfer reference quoted for a zakat payment
+func ZakatPaymentReference(paymentID uuid.UUID) string {
+	return fmt.Sprintf("ZKT-%s", strings.ToUpper(strings.ReplaceAll(paymentID.String(), "-", "")[:12]))
+}
+
+// Zakat constants
+const (
+	ZakatHaulRulePerHolding = "per_holding"
+	ZakatHaulRulePortfolio  = "portfolio"
+
+	ZakatPaymentStatusPending   = "pending"
+	ZakatPaymentStatusPaid      = "paid"
+	ZakatPaymentStatusCancelled = "cancelled"
+)
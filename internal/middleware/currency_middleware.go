@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CurrencyMismatchRecovery answers a request that combined amounts in different
+// currencies with a 400 instead of letting Money's panic reach the server's
+// recovery. Services check currencies where amounts enter; this covers any
+// combination they missed. Other panics are passed on.
+func CurrencyMismatchRecovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			err, ok := recovered.(error)
+			if !ok || !errors.Is(err, entities.ErrCurrencyMismatch) {
+				panic(recovered)
+			}
+
+			utils.ErrorResponse(c, http.StatusBadRequest, "Amounts in different currencies cannot be combined", err)
+			c.Abort()
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,621 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// AMLRepository stores AML monitoring rules, which are reference data
+// replicated to every shard, and the monitored transactions, alerts and cases
+// of each cooperative, which live on the cooperative's shard
+type AMLRepository interface {
+	// Rules
+	CreateRule(ctx context.Context, rule *entities.AMLRule) error
+	GetRule(ctx context.Context, ruleID uuid.UUID) (*entities.AMLRule, error)
+	DeactivateRule(ctx context.Context, ruleID uuid.UUID) error
+	// ListRules returns the active platform-wide rules together with the
+	// cooperative's own, or every active rule when no cooperative is given
+	ListRules(ctx context.Context, cooperativeID *uuid.UUID) ([]*entities.AMLRule, error)
+
+	// Monitored transactions
+	GetProjectCooperativeID(ctx context.Context, projectID uuid.UUID) (uuid.UUID, error)
+	CreateTransaction(ctx context.Context, transaction *entities.AMLTransaction) error
+	ListSubjectTransactions(ctx context.Context, cooperativeID, subjectID uuid.UUID, since time.Time) ([]*entities.AMLTransaction, error)
+	// ListBankAccountUses returns each bank account the subject has transacted
+	// through with when it was first used
+	ListBankAccountUses(ctx context.Context, cooperativeID, subjectID uuid.UUID) ([]*entities.AMLBankAccountUse, error)
+	// ListFlaggedTransactions returns the transactions alerted on in cases that
+	// are not closed
+	ListFlaggedTransactions(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.AMLTransaction, error)
+
+	// Cases
+	// RaiseAlerts adds alerts to the subject's case that is not closed, opening
+	// one under the cooperative's next case number when there is none, in one
+	// transaction
+	RaiseAlerts(ctx context.Context, cooperativeID, subjectID uuid.UUID, alerts []*entities.AMLAlert, raisedAt time.Time) (*entities.AMLCase, error)
+	// GetCase returns a case with its alerts, their transactions and its events
+	GetCase(ctx context.Context, cooperativeID, caseID uuid.UUID) (*entities.AMLCase, error)
+	ListCases(ctx context.Context, cooperativeID uuid.UUID, filter *entities.AMLCaseFilter) ([]*entities.AMLCase, int, error)
+	// TransitionCase saves a case moved on from fromStatus and records the
+	// event, in one transaction; it fails if the case has moved on meanwhile
+	TransitionCase(ctx context.Context, amlCase *entities.AMLCase, fromStatus string, event *entities.AMLCaseEvent) error
+}
+
+type amlRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewAMLRepository(shardMgr *database.ShardManager) AMLRepository {
+	return &amlRepository{shardMgr: shardMgr}
+}
+
+const amlRuleColumns = `id, cooperative_id, name, rule_type, threshold, currency, min_count, window_hours,
+	margin_percent, rounding_unit, severity, is_active, created_by, created_at, updated_at`
+
+func scanAMLRule(row interface{ Scan(...interface{}) error }) (*entities.AMLRule, error) {
+	rule := &entities.AMLRule{}
+	err := row.Scan(
+		&rule.ID, &rule.CooperativeID, &rule.Name, &rule.RuleType, &rule.Threshold, &rule.Currency, &rule.MinCount,
+		&rule.WindowHours, &rule.MarginPercent, &rule.RoundingUnit, &rule.Severity, &rule.IsActive, &rule.CreatedBy,
+		&rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.Threshold = rule.Threshold.WithCurrency(rule.Currency)
+	rule.RoundingUnit = rule.RoundingUnit.WithCurrency(rule.Currency)
+	return rule, nil
+}
+
+const amlTransactionColumns = `id, cooperative_id, project_id, subject_id, source_type, source_id, reference,
+	transaction_type, direction, amount, currency, bank_account, occurred_at, created_at`
+
+func scanAMLTransaction(row interface{ Scan(...interface{}) error }) (*entities.AMLTransaction, error) {
+	t := &entities.AMLTransaction{}
+	var reference, bankAccount sql.NullString
+	err := row.Scan(
+		&t.ID, &t.CooperativeID, &t.ProjectID, &t.SubjectID, &t.SourceType, &t.SourceID, &reference,
+		&t.TransactionType, &t.Direction, &t.Amount, &t.Currency, &bankAccount, &t.OccurredAt, &t.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Reference = reference.String
+	t.BankAccount = bankAccount.String
+	t.Amount = t.Amount.WithCurrency(t.Currency)
+	return t, nil
+}
+
+const amlAlertColumns = `id, case_id, transaction_id, rule_id, rule_name, rule_type, severity, description, created_at`
+
+func scanAMLAlert(row interface{ Scan(...interface{}) error }) (*entities.AMLAlert, error) {
+	a := &entities.AMLAlert{}
+	err := row.Scan(
+		&a.ID, &a.CaseID, &a.TransactionID, &a.RuleID, &a.RuleName, &a.RuleType, &a.Severity, &a.Description,
+		&a.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+const amlCaseColumns = `id, case_number, cooperative_id, subject_id, status, severity, assigned_to, closure_reason,
+	closure_notes, opened_at, escalated_at, closed_at, closed_by, created_at, updated_at`
+
+func scanAMLCase(row interface{ Scan(...interface{}) error }) (*entities.AMLCase, error) {
+	c := &entities.AMLCase{}
+	var closureReason, closureNotes sql.NullString
+	err := row.Scan(
+		&c.ID, &c.CaseNumber, &c.CooperativeID, &c.SubjectID, &c.Status, &c.Severity, &c.AssignedTo, &closureReason,
+		&closureNotes, &c.OpenedAt, &c.EscalatedAt, &c.ClosedAt, &c.ClosedBy, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ClosureReason = closureReason.String
+	c.ClosureNotes = closureNotes.String
+	return c, nil
+}
+
+const amlCaseEventColumns = `id, case_id, action, from_status, to_status, notes, actor_id, created_at`
+
+func scanAMLCaseEvent(row interface{ Scan(...interface{}) error }) (*entities.AMLCaseEvent, error) {
+	e := &entities.AMLCaseEvent{}
+	var fromStatus, notes sql.NullString
+	err := row.Scan(&e.ID, &e.CaseID, &e.Action, &fromStatus, &e.ToStatus, &notes, &e.ActorID, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	e.FromStatus = fromStatus.String
+	e.Notes = notes.String
+	return e, nil
+}
+
+func (r *amlRepository) CreateRule(ctx context.Context, rule *entities.AMLRule) error {
+	query := `
+		INSERT INTO aml_rules (` + amlRuleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	err := r.shardMgr.ExecuteOnAllShards(ctx, query,
+		rule.ID, rule.CooperativeID, rule.Name, rule.RuleType, rule.Threshold, rule.Currency, rule.MinCount,
+		rule.WindowHours, rule.MarginPercent, rule.RoundingUnit, rule.Severity, rule.IsActive, rule.CreatedBy,
+		rule.CreatedAt, rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create AML rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *amlRepository) GetRule(ctx context.Context, ruleID uuid.UUID) (*entities.AMLRule, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := scanAMLRule(shard.QueryRowContext(ctx, `
+		SELECT `+amlRuleColumns+` FROM aml_rules WHERE id = $1
+	`, ruleID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("AML rule not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AML rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *amlRepository) DeactivateRule(ctx context.Context, ruleID uuid.UUID) error {
+	query := `UPDATE aml_rules SET is_active = false WHERE id = $1`
+
+	if err := r.shardMgr.ExecuteOnAllShards(ctx, query, ruleID); err != nil {
+		return fmt.Errorf("failed to deactivate AML rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *amlRepository) ListRules(ctx context.Context, cooperativeID *uuid.UUID) ([]*entities.AMLRule, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+amlRuleColumns+`
+		FROM aml_rules
+		WHERE is_active = true
+			AND ($1::uuid IS NULL OR cooperative_id IS NULL OR cooperative_id = $1)
+		ORDER BY cooperative_id NULLS FIRST, rule_type, created_at
+	`, cooperativeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list AML rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*entities.AMLRule
+	for rows.Next() {
+		rule, err := scanAMLRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan AML rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (r *amlRepository) GetProjectCooperativeID(ctx context.Context, projectID uuid.UUID) (uuid.UUID, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	var cooperativeID uuid.UUID
+	err = shard.QueryRowContext(ctx, `
+		SELECT b.cooperative_id
+		FROM projects p
+		JOIN businesses b ON b.id = p.business_id
+		WHERE p.id = $1
+	`, projectID).Scan(&cooperativeID)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, fmt.Errorf("project not found")
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return cooperativeID, nil
+}
+
+func (r *amlRepository) CreateTransaction(ctx context.Context, t *entities.AMLTransaction) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(t.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	_, err = shard.ExecContext(ctx, `
+		INSERT INTO aml_transactions (`+amlTransactionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`,
+		t.ID, t.CooperativeID, t.ProjectID, t.SubjectID, t.SourceType, t.SourceID, nullString(t.Reference),
+		t.TransactionType, t.Direction, t.Amount, t.Currency, nullString(t.BankAccount), t.OccurredAt, t.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create AML transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *amlRepository) ListSubjectTransactions(ctx context.Context, cooperativeID, subjectID uuid.UUID, since time.Time) ([]*entities.AMLTransaction, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+amlTransactionColumns+`
+		FROM aml_transactions
+		WHERE cooperative_id = $1 AND subject_id = $2 AND occurred_at >= $3
+		ORDER BY occurred_at
+	`, cooperativeID, subjectID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list AML transactions: %w", err)
+	}
+
+	return collectAMLTransactions(rows)
+}
+
+func (r *amlRepository) ListBankAccountUses(ctx context.Context, cooperativeID, subjectID uuid.UUID) ([]*entities.AMLBankAccountUse, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT bank_account, MIN(occurred_at)
+		FROM aml_transactions
+		WHERE cooperative_id = $1 AND subject_id = $2 AND bank_account IS NOT NULL
+		GROUP BY bank_account
+		ORDER BY MIN(occurred_at)
+	`, cooperativeID, subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bank account uses: %w", err)
+	}
+	defer rows.Close()
+
+	var uses []*entities.AMLBankAccountUse
+	for rows.Next() {
+		use := &entities.AMLBankAccountUse{}
+		if err := rows.Scan(&use.BankAccount, &use.FirstUsedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan bank account use: %w", err)
+		}
+		uses = append(uses, use)
+	}
+
+	return uses, rows.Err()
+}
+
+func (r *amlRepository) ListFlaggedTransactions(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.AMLTransaction, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+amlTransactionColumns+`
+		FROM aml_transactions
+		WHERE id IN (
+			SELECT a.transaction_id
+			FROM aml_alerts a
+			JOIN aml_cases c ON c.id = a.case_id
+			WHERE c.cooperative_id = $1 AND c.status <> $2
+		)
+		ORDER BY occurred_at DESC
+	`, cooperativeID, entities.AMLCaseStatusClosed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flagged AML transactions: %w", err)
+	}
+
+	return collectAMLTransactions(rows)
+}
+
+func collectAMLTransactions(rows *sql.Rows) ([]*entities.AMLTransaction, error) {
+	defer rows.Close()
+
+	var transactions []*entities.AMLTransaction
+	for rows.Next() {
+		transaction, err := scanAMLTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan AML transaction: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, rows.Err()
+}
+
+// nextAMLCaseNumber allocates the cooperative's next case number in the year,
+// e.g. AML-2026-000001
+func nextAMLCaseNumber(ctx context.Context, tx *sql.Tx, cooperativeID uuid.UUID, openedAt time.Time) (string, error) {
+	year := openedAt.Year()
+
+	var number int
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO aml_case_sequences (cooperative_id, year, last_number)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (cooperative_id, year) DO UPDATE SET
+			last_number = aml_case_sequences.last_number + 1
+		RETURNING last_number
+	`, cooperativeID, year).Scan(&number)
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate AML case number: %w", err)
+	}
+
+	return fmt.Sprintf("AML-%d-%06d", year, number), nil
+}
+
+func insertAMLCaseEvent(ctx context.Context, tx *sql.Tx, event *entities.AMLCaseEvent) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO aml_case_events (`+amlCaseEventColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, event.ID, event.CaseID, event.Action, nullString(event.FromStatus), event.ToStatus, nullString(event.Notes),
+		event.ActorID, event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record AML case event: %w", err)
+	}
+	return nil
+}
+
+func (r *amlRepository) RaiseAlerts(ctx context.Context, cooperativeID, subjectID uuid.UUID, alerts []*entities.AMLAlert, raisedAt time.Time) (*entities.AMLCase, error) {
+	_, shardIndex, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	severity := ""
+	for _, alert := range alerts {
+		if entities.AMLSeverityRank(alert.Severity) > entities.AMLSeverityRank(severity) {
+			severity = alert.Severity
+		}
+	}
+
+	event := &entities.AMLCaseEvent{
+		ID:        uuid.New(),
+		Action:    entities.AMLCaseActionAlerted,
+		Notes:     fmt.Sprintf("%d alert(s) raised", len(alerts)),
+		CreatedAt: raisedAt,
+	}
+
+	amlCase, err := scanAMLCase(tx.QueryRowContext(ctx, `
+		SELECT `+amlCaseColumns+`
+		FROM aml_cases
+		WHERE cooperative_id = $1 AND subject_id = $2 AND status <> $3
+		FOR UPDATE
+	`, cooperativeID, subjectID, entities.AMLCaseStatusClosed))
+	switch {
+	case err == sql.ErrNoRows:
+		amlCase = &entities.AMLCase{
+			ID:            uuid.New(),
+			CooperativeID: cooperativeID,
+			SubjectID:     subjectID,
+			Status:        entities.AMLCaseStatusOpen,
+			Severity:      severity,
+			OpenedAt:      raisedAt,
+			CreatedAt:     raisedAt,
+			UpdatedAt:     raisedAt,
+		}
+		amlCase.CaseNumber, err = nextAMLCaseNumber(ctx, tx, cooperativeID, raisedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO aml_cases (`+amlCaseColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		`,
+			amlCase.ID, amlCase.CaseNumber, amlCase.CooperativeID, amlCase.SubjectID, amlCase.Status, amlCase.Severity,
+			amlCase.AssignedTo, nullString(amlCase.ClosureReason), nullString(amlCase.ClosureNotes), amlCase.OpenedAt,
+			amlCase.EscalatedAt, amlCase.ClosedAt, amlCase.ClosedBy, amlCase.CreatedAt, amlCase.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open AML case: %w", err)
+		}
+		event.Action = entities.AMLCaseActionOpened
+	case err != nil:
+		return nil, fmt.Errorf("failed to get AML case: %w", err)
+	case entities.AMLSeverityRank(severity) > entities.AMLSeverityRank(amlCase.Severity):
+		amlCase.Severity = severity
+		if _, err := tx.ExecContext(ctx, `UPDATE aml_cases SET severity = $2 WHERE id = $1`, amlCase.ID, severity); err != nil {
+			return nil, fmt.Errorf("failed to update AML case: %w", err)
+		}
+	}
+
+	for _, alert := range alerts {
+		alert.CaseID = amlCase.ID
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO aml_alerts (`+amlAlertColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, alert.ID, alert.CaseID, alert.TransactionID, alert.RuleID, alert.RuleName, alert.RuleType, alert.Severity,
+			alert.Description, alert.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create AML alert: %w", err)
+		}
+	}
+
+	event.CaseID = amlCase.ID
+	event.ToStatus = amlCase.Status
+	if event.Action == entities.AMLCaseActionAlerted {
+		event.FromStatus = amlCase.Status
+	}
+	if err := insertAMLCaseEvent(ctx, tx, event); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	amlCase.Alerts = alerts
+	return amlCase, nil
+}
+
+func (r *amlRepository) GetCase(ctx context.Context, cooperativeID, caseID uuid.UUID) (*entities.AMLCase, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	amlCase, err := scanAMLCase(shard.QueryRowContext(ctx, `
+		SELECT `+amlCaseColumns+` FROM aml_cases WHERE id = $1 AND cooperative_id = $2
+	`, caseID, cooperativeID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("AML case not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AML case: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+amlAlertColumns+` FROM aml_alerts WHERE case_id = $1 ORDER BY created_at, rule_name
+	`, caseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list AML alerts: %w", err)
+	}
+	for rows.Next() {
+		alert, err := scanAMLAlert(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan AML alert: %w", err)
+		}
+		amlCase.Alerts = append(amlCase.Alerts, alert)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = shard.QueryContext(ctx, `
+		SELECT `+amlTransactionColumns+`
+		FROM aml_transactions
+		WHERE id IN (SELECT transaction_id FROM aml_alerts WHERE case_id = $1)
+		ORDER BY occurred_at
+	`, caseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list AML transactions: %w", err)
+	}
+	if amlCase.Transactions, err = collectAMLTransactions(rows); err != nil {
+		return nil, err
+	}
+
+	rows, err = shard.QueryContext(ctx, `
+		SELECT `+amlCaseEventColumns+` FROM aml_case_events WHERE case_id = $1 ORDER BY created_at
+	`, caseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list AML case events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanAMLCaseEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan AML case event: %w", err)
+		}
+		amlCase.Events = append(amlCase.Events, event)
+	}
+
+	return amlCase, rows.Err()
+}
+
+func (r *amlRepository) ListCases(ctx context.Context, cooperativeID uuid.UUID, filter *entities.AMLCaseFilter) ([]*entities.AMLCase, int, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	where := ` WHERE cooperative_id = $1
+		AND ($2::text IS NULL OR status = $2)
+		AND ($3::uuid IS NULL OR subject_id = $3)`
+	args := []interface{}{cooperativeID, nullString(filter.Status), filter.SubjectID}
+
+	var total int
+	if err := shard.QueryRowContext(ctx, `SELECT COUNT(*) FROM aml_cases`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count AML cases: %w", err)
+	}
+
+	query := `SELECT ` + amlCaseColumns + ` FROM aml_cases` + where + `
+		ORDER BY opened_at DESC
+		LIMIT $4 OFFSET $5`
+
+	rows, err := shard.QueryContext(ctx, query, append(args, filter.Limit, (filter.Page-1)*filter.Limit)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list AML cases: %w", err)
+	}
+	defer rows.Close()
+
+	var cases []*entities.AMLCase
+	for rows.Next() {
+		amlCase, err := scanAMLCase(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan AML case: %w", err)
+		}
+		cases = append(cases, amlCase)
+	}
+
+	return cases, total, rows.Err()
+}
+
+func (r *amlRepository) TransitionCase(ctx context.Context, amlCase *entities.AMLCase, fromStatus string, event *entities.AMLCaseEvent) error {
+	_, shardIndex, err := r.shardMgr.GetShardByCooperativeID(amlCase.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = execOne(ctx, tx, "AML case has moved on since it was read", `
+		UPDATE aml_cases
+		SET status = $2, assigned_to = $3, closure_reason = $4, closure_notes = $5, escalated_at = $6,
+			closed_at = $7, closed_by = $8
+		WHERE id = $1 AND status = $9
+	`, amlCase.ID, amlCase.Status, amlCase.AssignedTo, nullString(amlCase.ClosureReason),
+		nullString(amlCase.ClosureNotes), amlCase.EscalatedAt, amlCase.ClosedAt, amlCase.ClosedBy, fromStatus)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAMLCaseEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,448 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// BankReconciliationRepository stores imported bank statements and their lines.
+// Statements live on the cooperative's shard alongside its ledger.
+type BankReconciliationRepository interface {
+	CreateStatement(ctx context.Context, statement *entities.BankStatement, lines []*entities.BankStatementLine) error
+	GetStatement(ctx context.Context, id uuid.UUID) (*entities.BankStatement, error)
+	// GetStatementByFileHash returns the statement imported from an identical file, or nil if there is none
+	GetStatementByFileHash(ctx context.Context, cooperativeID uuid.UUID, fileHash string) (*entities.BankStatement, error)
+	ListStatements(ctx context.Context, cooperativeID uuid.UUID, limit, offset int) ([]*entities.BankStatement, int, error)
+	RefreshStatementCounts(ctx context.Context, statement *entities.BankStatement) error
+
+	GetLine(ctx context.Context, id uuid.UUID) (*entities.BankStatementLine, error)
+	ListLines(ctx context.Context, filter *entities.BankStatementLineFilter) ([]*entities.BankStatementLine, int, error)
+	ListOpenLines(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.BankStatementLine, error)
+	UpdateLine(ctx context.Context, line *entities.BankStatementLine) error
+	// ExistingTransactionIDs returns which of the bank transaction IDs were already imported
+	ExistingTransactionIDs(ctx context.Context, cooperativeID uuid.UUID, transactionIDs []string) (map[string]bool, error)
+	// MatchedEntityIDs returns which of the entities are already settled by a matched line
+	MatchedEntityIDs(ctx context.Context, cooperativeID uuid.UUID, entityIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+}
+
+type bankReconciliationRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewBankReconciliationRepository(shardMgr *database.ShardManager) BankReconciliationRepository {
+	return &bankReconciliationRepository{shardMgr: shardMgr}
+}
+
+const bankStatementColumns = `id, cooperative_id, escrow_account_id, format, file_name, file_hash, statement_ref, account_number,
+	currency, opening_balance, closing_balance, period_start, period_end, line_count, matched_count, exception_count,
+	imported_by, imported_at`
+
+const bankStatementLineColumns = `id, statement_id, cooperative_id, line_number, value_date, booking_date, direction, amount,
+	currency, reference, description, counterparty_name, counterparty_account, bank_transaction_id, status,
+	exception_reason, match_type, matched_entity_id, match_method, matched_by, matched_at, notes, created_at, updated_at`
+
+func scanBankStatement(row interface{ Scan(...interface{}) error }) (*entities.BankStatement, error) {
+	statement := &entities.BankStatement{}
+	var escrowAccountID *uuid.UUID
+	var statementRef, accountNumber sql.NullString
+	err := row.Scan(
+		&statement.ID, &statement.CooperativeID, &escrowAccountID, &statement.Format, &statement.FileName,
+		&statement.FileHash, &statementRef, &accountNumber, &statement.Currency, &statement.OpeningBalance,
+		&statement.ClosingBalance, &statement.PeriodStart, &statement.PeriodEnd, &statement.LineCount,
+		&statement.MatchedCount, &statement.ExceptionCount, &statement.ImportedBy, &statement.ImportedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if escrowAccountID != nil {
+		statement.EscrowAccountID = *escrowAccountID
+	}
+	statement.StatementRef = statementRef.String
+	statement.AccountNumber = accountNumber.String
+	if statement.OpeningBalance != nil {
+		balance := statement.OpeningBalance.WithCurrency(statement.Currency)
+		statement.OpeningBalance = &balance
+	}
+	if statement.ClosingBalance != nil {
+		balance := statement.ClosingBalance.WithCurrency(statement.Currency)
+		statement.ClosingBalance = &balance
+	}
+	return statement, nil
+}
+
+func scanBankStatementLine(row interface{ Scan(...interface{}) error }) (*entities.BankStatementLine, error) {
+	line := &entities.BankStatementLine{}
+	var reference, description, counterpartyName, counterpartyAccount sql.NullString
+	var exceptionReason, matchType, matchMethod, notes sql.NullString
+	err := row.Scan(
+		&line.ID, &line.StatementID, &line.CooperativeID, &line.LineNumber, &line.ValueDate, &line.BookingDate,
+		&line.Direction, &line.Amount, &line.Currency, &reference, &description, &counterpartyName,
+		&counterpartyAccount, &line.BankTransactionID, &line.Status, &exceptionReason, &matchType,
+		&line.MatchedEntityID, &matchMethod, &line.MatchedBy, &line.MatchedAt, &notes, &line.CreatedAt, &line.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	line.Amount = line.Amount.WithCurrency(line.Currency)
+	line.Reference = reference.String
+	line.Description = description.String
+	line.CounterpartyName = counterpartyName.String
+	line.CounterpartyAccount = counterpartyAccount.String
+	line.ExceptionReason = exceptionReason.String
+	line.MatchType = matchType.String
+	line.MatchMethod = matchMethod.String
+	line.Notes = notes.String
+	return line, nil
+}
+
+// nullString stores empty strings as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func (r *bankReconciliationRepository) CreateStatement(ctx context.Context, statement *entities.BankStatement, lines []*entities.BankStatementLine) error {
+	_, shardIndex, err := r.shardMgr.GetShardByCooperativeID(statement.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var escrowAccountID *uuid.UUID
+	if statement.EscrowAccountID != uuid.Nil {
+		escrowAccountID = &statement.EscrowAccountID
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO bank_statements (`+bankStatementColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+	`,
+		statement.ID, statement.CooperativeID, escrowAccountID, statement.Format, statement.FileName,
+		statement.FileHash, nullString(statement.StatementRef), nullString(statement.AccountNumber), statement.Currency,
+		statement.OpeningBalance, statement.ClosingBalance, statement.PeriodStart, statement.PeriodEnd,
+		statement.LineCount, statement.MatchedCount, statement.ExceptionCount, statement.ImportedBy, statement.ImportedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert bank statement: %w", err)
+	}
+
+	for _, line := range lines {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO bank_statement_lines (`+bankStatementLineColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+		`,
+			line.ID, line.StatementID, line.CooperativeID, line.LineNumber, line.ValueDate, line.BookingDate,
+			line.Direction, line.Amount, line.Currency, nullString(line.Reference), nullString(line.Description),
+			nullString(line.CounterpartyName), nullString(line.CounterpartyAccount), line.BankTransactionID, line.Status,
+			nullString(line.ExceptionReason), nullString(line.MatchType), line.MatchedEntityID, nullString(line.MatchMethod),
+			line.MatchedBy, line.MatchedAt, nullString(line.Notes), line.CreatedAt, line.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert bank statement line %d: %w", line.LineNumber, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit bank statement: %w", err)
+	}
+
+	return nil
+}
+
+func (r *bankReconciliationRepository) GetStatement(ctx context.Context, id uuid.UUID) (*entities.BankStatement, error) {
+	// Query all shards to find the statement
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	query := `SELECT ` + bankStatementColumns + ` FROM bank_statements WHERE id = $1`
+
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		statement, err := scanBankStatement(shard.QueryRowContext(ctx, query, id))
+		if err == nil {
+			return statement, nil
+		}
+	}
+
+	return nil, fmt.Errorf("bank statement not found")
+}
+
+func (r *bankReconciliationRepository) GetStatementByFileHash(ctx context.Context, cooperativeID uuid.UUID, fileHash string) (*entities.BankStatement, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `SELECT ` + bankStatementColumns + ` FROM bank_statements WHERE cooperative_id = $1 AND file_hash = $2`
+
+	statement, err := scanBankStatement(shard.QueryRowContext(ctx, query, cooperativeID, fileHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank statement: %w", err)
+	}
+
+	return statement, nil
+}
+
+func (r *bankReconciliationRepository) ListStatements(ctx context.Context, cooperativeID uuid.UUID, limit, offset int) ([]*entities.BankStatement, int, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	var total int
+	if err := shard.QueryRowContext(ctx, `SELECT COUNT(*) FROM bank_statements WHERE cooperative_id = $1`, cooperativeID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count bank statements: %w", err)
+	}
+
+	query := `
+		SELECT ` + bankStatementColumns + `
+		FROM bank_statements
+		WHERE cooperative_id = $1
+		ORDER BY imported_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := shard.QueryContext(ctx, query, cooperativeID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list bank statements: %w", err)
+	}
+	defer rows.Close()
+
+	var statements []*entities.BankStatement
+	for rows.Next() {
+		statement, err := scanBankStatement(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan bank statement: %w", err)
+		}
+		statements = append(statements, statement)
+	}
+
+	return statements, total, rows.Err()
+}
+
+func (r *bankReconciliationRepository) RefreshStatementCounts(ctx context.Context, statement *entities.BankStatement) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(statement.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `
+		UPDATE bank_statements s SET
+			matched_count = (SELECT COUNT(*) FROM bank_statement_lines l WHERE l.statement_id = s.id AND l.status = 'matched'),
+			exception_count = (SELECT COUNT(*) FROM bank_statement_lines l WHERE l.statement_id = s.id AND l.status = 'exception')
+		WHERE s.id = $1
+		RETURNING matched_count, exception_count
+	`
+
+	if err := shard.QueryRowContext(ctx, query, statement.ID).Scan(&statement.MatchedCount, &statement.ExceptionCount); err != nil {
+		return fmt.Errorf("failed to refresh bank statement counts: %w", err)
+	}
+
+	return nil
+}
+
+func (r *bankReconciliationRepository) GetLine(ctx context.Context, id uuid.UUID) (*entities.BankStatementLine, error) {
+	// Query all shards to find the line
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	query := `SELECT ` + bankStatementLineColumns + ` FROM bank_statement_lines WHERE id = $1`
+
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		line, err := scanBankStatementLine(shard.QueryRowContext(ctx, query, id))
+		if err == nil {
+			return line, nil
+		}
+	}
+
+	return nil, fmt.Errorf("bank statement line not found")
+}
+
+func (r *bankReconciliationRepository) ListLines(ctx context.Context, filter *entities.BankStatementLineFilter) ([]*entities.BankStatementLine, int, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(filter.CooperativeID.String())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	where := ` WHERE cooperative_id = $1 AND ($2::uuid IS NULL OR statement_id = $2) AND ($3 = '' OR status = $3)`
+	args := []interface{}{filter.CooperativeID, filter.StatementID, filter.Status}
+
+	var total int
+	if err := shard.QueryRowContext(ctx, `SELECT COUNT(*) FROM bank_statement_lines`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count bank statement lines: %w", err)
+	}
+
+	query := `SELECT ` + bankStatementLineColumns + ` FROM bank_statement_lines` + where + `
+		ORDER BY value_date DESC, line_number
+		LIMIT $4 OFFSET $5`
+
+	rows, err := shard.QueryContext(ctx, query, append(args, filter.Limit, (filter.Page-1)*filter.Limit)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list bank statement lines: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []*entities.BankStatementLine
+	for rows.Next() {
+		line, err := scanBankStatementLine(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan bank statement line: %w", err)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, total, rows.Err()
+}
+
+func (r *bankReconciliationRepository) ListOpenLines(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.BankStatementLine, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `
+		SELECT ` + bankStatementLineColumns + `
+		FROM bank_statement_lines
+		WHERE cooperative_id = $1 AND status IN ('unmatched', 'exception')
+		ORDER BY value_date, line_number
+	`
+
+	rows, err := shard.QueryContext(ctx, query, cooperativeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open bank statement lines: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []*entities.BankStatementLine
+	for rows.Next() {
+		line, err := scanBankStatementLine(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bank statement line: %w", err)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+func (r *bankReconciliationRepository) UpdateLine(ctx context.Context, line *entities.BankStatementLine) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(line.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	line.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE bank_statement_lines SET
+			status = $2, exception_reason = $3, match_type = $4, matched_entity_id = $5,
+			match_method = $6, matched_by = $7, matched_at = $8, notes = $9, updated_at = $10
+		WHERE id = $1
+	`
+
+	result, err := shard.ExecContext(ctx, query,
+		line.ID, line.Status, nullString(line.ExceptionReason), nullString(line.MatchType), line.MatchedEntityID,
+		nullString(line.MatchMethod), line.MatchedBy, line.MatchedAt, nullString(line.Notes), line.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update bank statement line: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("bank statement line not found")
+	}
+
+	return nil
+}
+
+func (r *bankReconciliationRepository) ExistingTransactionIDs(ctx context.Context, cooperativeID uuid.UUID, transactionIDs []string) (map[string]bool, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `
+		SELECT bank_transaction_id FROM bank_statement_lines
+		WHERE cooperative_id = $1 AND bank_transaction_id = ANY($2)
+	`
+
+	rows, err := shard.QueryContext(ctx, query, cooperativeID, pq.Array(transactionIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bank transaction IDs: %w", err)
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan bank transaction ID: %w", err)
+		}
+		existing[id] = true
+	}
+
+	return existing, rows.Err()
+}
+
+func (r *bankReconciliationRepository) MatchedEntityIDs(ctx context.Context, cooperativeID uuid.UUID, entityIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	ids := make([]string, len(entityIDs))
+	for i, id := range entityIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT matched_entity_id FROM bank_statement_lines
+		WHERE cooperative_id = $1 AND status = 'matched' AND matched_entity_id = ANY($2::uuid[])
+	`
+
+	rows, err := shard.QueryContext(ctx, query, cooperativeID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check matched entities: %w", err)
+	}
+	defer rows.Close()
+
+	matched := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan matched entity ID: %w", err)
+		}
+		matched[id] = true
+	}
+
+	return matched, rows.Err()
+}
@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// CurrencyRepository stores the currency registry and exchange rates. Both are
+// reference data replicated to every shard, so writes go to all shards and reads
+// are served by the first available one.
+type CurrencyRepository interface {
+	UpsertCurrency(ctx context.Context, currency *entities.Currency) error
+	GetCurrency(ctx context.Context, code string) (*entities.Currency, error)
+	ListCurrencies(ctx context.Context, activeOnly bool) ([]*entities.Currency, error)
+	CreateExchangeRate(ctx context.Context, rate *entities.ExchangeRate) error
+	// GetEffectiveRate returns the latest rate for the pair effective at the given time, or nil if there is none
+	GetEffectiveRate(ctx context.Context, base, quote string, at time.Time) (*entities.ExchangeRate, error)
+	ListExchangeRates(ctx context.Context, base, quote string, limit, offset int) ([]*entities.ExchangeRate, int, error)
+}
+
+type currencyRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewCurrencyRepository(shardMgr *database.ShardManager) CurrencyRepository {
+	return &currencyRepository{shardMgr: shardMgr}
+}
+
+const exchangeRateColumns = `id, base_currency, quote_currency, rate, source, effective_at, created_by, created_at`
+
+func scanExchangeRate(row interface{ Scan(...interface{}) error }) (*entities.ExchangeRate, error) {
+	rate := &entities.ExchangeRate{}
+	err := row.Scan(
+		&rate.ID, &rate.BaseCurrency, &rate.QuoteCurrency, &rate.Rate, &rate.Source,
+		&rate.EffectiveAt, &rate.CreatedBy, &rate.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (r *currencyRepository) UpsertCurrency(ctx context.Context, currency *entities.Currency) error {
+	currency.Code = strings.ToUpper(currency.Code)
+
+	now := time.Now()
+	if currency.CreatedAt.IsZero() {
+		currency.CreatedAt = now
+	}
+	currency.UpdatedAt = now
+
+	query := `
+		INSERT INTO currencies (code, name, symbol, minor_units, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (code) DO UPDATE SET
+			name = EXCLUDED.name,
+			symbol = EXCLUDED.symbol,
+			minor_units = EXCLUDED.minor_units,
+			is_active = EXCLUDED.is_active,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	err := r.shardMgr.ExecuteOnAllShards(ctx, query,
+		currency.Code, currency.Name, currency.Symbol, currency.MinorUnits, currency.IsActive,
+		currency.CreatedAt, currency.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save currency: %w", err)
+	}
+
+	return nil
+}
+
+func (r *currencyRepository) GetCurrency(ctx context.Context, code string) (*entities.Currency, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT code, name, symbol, minor_units, is_active, created_at, updated_at
+		FROM currencies WHERE code = $1
+	`
+
+	currency := &entities.Currency{}
+	err = shard.QueryRowContext(ctx, query, strings.ToUpper(code)).Scan(
+		&currency.Code, &currency.Name, &currency.Symbol, &currency.MinorUnits,
+		&currency.IsActive, &currency.CreatedAt, &currency.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("currency not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get currency: %w", err)
+	}
+
+	return currency, nil
+}
+
+func (r *currencyRepository) ListCurrencies(ctx context.Context, activeOnly bool) ([]*entities.Currency, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT code, name, symbol, minor_units, is_active, created_at, updated_at
+		FROM currencies
+	`
+	if activeOnly {
+		query += ` WHERE is_active = true`
+	}
+	query += ` ORDER BY code`
+
+	rows, err := shard.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list currencies: %w", err)
+	}
+	defer rows.Close()
+
+	var currencies []*entities.Currency
+	for rows.Next() {
+		currency := &entities.Currency{}
+		if err := rows.Scan(
+			&currency.Code, &currency.Name, &currency.Symbol, &currency.MinorUnits,
+			&currency.IsActive, &currency.CreatedAt, &currency.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan currency: %w", err)
+		}
+		currencies = append(currencies, currency)
+	}
+
+	return currencies, rows.Err()
+}
+
+func (r *currencyRepository) CreateExchangeRate(ctx context.Context, rate *entities.ExchangeRate) error {
+	// The ID is assigned here so that every shard holds the same row
+	if rate.ID == uuid.Nil {
+		rate.ID = uuid.New()
+	}
+	rate.BaseCurrency = strings.ToUpper(rate.BaseCurrency)
+	rate.QuoteCurrency = strings.ToUpper(rate.QuoteCurrency)
+	rate.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO exchange_rates (` + exchangeRateColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	err := r.shardMgr.ExecuteOnAllShards(ctx, query,
+		rate.ID, rate.BaseCurrency, rate.QuoteCurrency, rate.Rate, rate.Source,
+		rate.EffectiveAt, rate.CreatedBy, rate.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create exchange rate: %w", err)
+	}
+
+	return nil
+}
+
+func (r *currencyRepository) GetEffectiveRate(ctx context.Context, base, quote string, at time.Time) (*entities.ExchangeRate, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + exchangeRateColumns + `
+		FROM exchange_rates
+		WHERE base_currency = $1 AND quote_currency = $2 AND effective_at <= $3
+		ORDER BY effective_at DESC, created_at DESC
+		LIMIT 1
+	`
+
+	rate, err := scanExchangeRate(shard.QueryRowContext(ctx, query, strings.ToUpper(base), strings.ToUpper(quote), at))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+
+	return rate, nil
+}
+
+func (r *currencyRepository) ListExchangeRates(ctx context.Context, base, quote string, limit, offset int) ([]*entities.ExchangeRate, int, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	where := ` WHERE ($1 = '' OR base_currency = $1) AND ($2 = '' OR quote_currency = $2)`
+	base = strings.ToUpper(base)
+	quote = strings.ToUpper(quote)
+
+	var total int
+	if err := shard.QueryRowContext(ctx, `SELECT COUNT(*) FROM exchange_rates`+where, base, quote).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count exchange rates: %w", err)
+	}
+
+	query := `SELECT ` + exchangeRateColumns + ` FROM exchange_rates` + where + `
+		ORDER BY effective_at DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := shard.QueryContext(ctx, query, base, quote, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list exchange rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []*entities.ExchangeRate
+	for rows.Next() {
+		rate, err := scanExchangeRate(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan exchange rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, total, rows.Err()
+}
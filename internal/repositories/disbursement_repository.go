@@ -0,0 +1,619 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// DisbursementRepository stores the milestone tranches of funded projects and
+// the disbursements drawn on them (FR-046). Both live on the project's shard.
+type DisbursementRepository interface {
+	GetDisbursementProject(ctx context.Context, projectID uuid.UUID) (*entities.DisbursementProject, error)
+
+	// CreateTranches records a project's tranches. It fails if the project is
+	// no longer funded, its funding differs from fundedAmount or its tranches
+	// were already defined.
+	CreateTranches(ctx context.Context, projectID uuid.UUID, fundedAmount entities.Money, tranches []*entities.DisbursementTranche) error
+	ListTranches(ctx context.Context, projectID uuid.UUID) ([]*entities.DisbursementTranche, error)
+	GetTranche(ctx context.Context, projectID, trancheID uuid.UUID) (*entities.DisbursementTranche, error)
+	// GetMilestoneTranche returns the tranche of a milestone, or nil if the
+	// milestone has none
+	GetMilestoneTranche(ctx context.Context, projectID, milestoneID uuid.UUID) (*entities.DisbursementTranche, error)
+	// UpdateTrancheMilestone saves a tranche's milestone completion and
+	// verification if its status and milestone status are still fromStatus and
+	// fromMilestoneStatus, reporting whether they were
+	UpdateTrancheMilestone(ctx context.Context, tranche *entities.DisbursementTranche, fromStatus, fromMilestoneStatus string) (bool, error)
+	// CloseTranche closes a planned or open tranche with no disbursement
+	// awaiting release and sets its remaining budget aside as unused
+	CloseTranche(ctx context.Context, projectID, trancheID, closerID uuid.UUID, reason string, closedAt time.Time) (*entities.DisbursementTranche, error)
+
+	// CreateDisbursement records a disbursement against its tranche. It fails
+	// unless the tranche is open and its remaining budget, less the
+	// disbursements already awaiting release, covers the amount.
+	CreateDisbursement(ctx context.Context, disbursement *entities.FundDisbursement, requesterID uuid.UUID) error
+	GetDisbursement(ctx context.Context, disbursementID uuid.UUID) (*entities.FundDisbursement, error)
+	ListProjectDisbursements(ctx context.Context, projectID uuid.UUID, limit, offset int) ([]*entities.FundDisbursement, int, error)
+	// GetOutstandingAmount sums the project's disbursements requested or
+	// approved but not yet disbursed
+	GetOutstandingAmount(ctx context.Context, projectID uuid.UUID, currency string) (entities.Money, error)
+	// UpdateDisbursementStatus saves an approval or rejection of a disbursement
+	// still in fromStatus, reporting whether it was
+	UpdateDisbursementStatus(ctx context.Context, disbursement *entities.FundDisbursement, fromStatus string) (bool, error)
+	// MarkDisbursed releases an approved disbursement from its tranche. The
+	// tranche is marked released once its budget is used up, or closed with
+	// the rest of its budget unused after a final disbursement.
+	MarkDisbursed(ctx context.Context, disbursement *entities.FundDisbursement, disbursedAt time.Time) (*entities.DisbursementTranche, error)
+}
+
+type disbursementRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewDisbursementRepository(shardMgr *database.ShardManager) DisbursementRepository {
+	return &disbursementRepository{shardMgr: shardMgr}
+}
+
+const disbursementTrancheColumns = `id, project_id, cooperative_id, milestone_id, milestone_title, milestone_status,
+	sequence, currency, budget, released_amount, unused_amount, status, evidence_documents, completion_notes,
+	completed_by, completed_at, verified_by, verified_at, verification_notes, closed_by, closed_at, close_reason,
+	created_at, updated_at`
+
+func scanDisbursementTranche(row interface{ Scan(...interface{}) error }) (*entities.DisbursementTranche, error) {
+	t := &entities.DisbursementTranche{}
+	var completionNotes, verificationNotes, closeReason sql.NullString
+	err := row.Scan(
+		&t.ID, &t.ProjectID, &t.CooperativeID, &t.MilestoneID, &t.MilestoneTitle, &t.MilestoneStatus,
+		&t.Sequence, &t.Currency, &t.Budget, &t.ReleasedAmount, &t.UnusedAmount, &t.Status,
+		pq.Array(&t.EvidenceDocuments), &completionNotes, &t.CompletedBy, &t.CompletedAt, &t.VerifiedBy,
+		&t.VerifiedAt, &verificationNotes, &t.ClosedBy, &t.ClosedAt, &closeReason, &t.CreatedAt, &t.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	t.CompletionNotes = completionNotes.String
+	t.VerificationNotes = verificationNotes.String
+	t.CloseReason = closeReason.String
+	t.Budget = t.Budget.WithCurrency(t.Currency)
+	t.ReleasedAmount = t.ReleasedAmount.WithCurrency(t.Currency)
+	t.UnusedAmount = t.UnusedAmount.WithCurrency(t.Currency)
+	return t, nil
+}
+
+const fundDisbursementColumns = `id, project_id, business_id, cooperative_id, milestone_id, tranche_id,
+	disbursement_amount, currency, disbursement_type, disbursement_reason, status, approved_by, approved_at,
+	disbursed_at, rejection_reason, bank_account, transaction_reference, created_at, updated_at`
+
+func scanFundDisbursement(row interface{ Scan(...interface{}) error }) (*entities.FundDisbursement, error) {
+	d := &entities.FundDisbursement{}
+	var rejectionReason, transactionReference sql.NullString
+	err := row.Scan(
+		&d.ID, &d.ProjectID, &d.BusinessID, &d.CooperativeID, &d.MilestoneID, &d.TrancheID,
+		&d.DisbursementAmount, &d.Currency, &d.DisbursementType, &d.DisbursementReason, &d.Status, &d.ApprovedBy,
+		&d.ApprovedAt, &d.DisbursedAt, &rejectionReason, &d.BankAccount, &transactionReference, &d.CreatedAt,
+		&d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	d.RejectionReason = rejectionReason.String
+	d.TransactionReference = transactionReference.String
+	d.DisbursementAmount = d.DisbursementAmount.WithCurrency(d.Currency)
+	d.IsActive = true
+	return d, nil
+}
+
+func (r *disbursementRepository) GetDisbursementProject(ctx context.Context, projectID uuid.UUID) (*entities.DisbursementProject, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	project := &entities.DisbursementProject{}
+	err = shard.QueryRowContext(ctx, `
+		SELECT p.id, b.cooperative_id, b.id, b.owner_id, COALESCE(c.currency, ''), p.status, p.current_funding
+		FROM projects p
+		JOIN businesses b ON b.id = p.business_id
+		LEFT JOIN project_contracts c ON c.project_id = p.id
+		WHERE p.id = $1
+	`, projectID).Scan(
+		&project.ProjectID, &project.CooperativeID, &project.BusinessID, &project.OwnerID, &project.Currency,
+		&project.Status, &project.FundedAmount,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("project not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	project.FundedAmount = project.FundedAmount.WithCurrency(project.Currency)
+	return project, nil
+}
+
+func (r *disbursementRepository) CreateTranches(ctx context.Context, projectID uuid.UUID, fundedAmount entities.Money, tranches []*entities.DisbursementTranche) error {
+	_, shardIndex, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	var funding entities.Money
+	err = tx.QueryRowContext(ctx, `
+		SELECT status, current_funding FROM projects WHERE id = $1 FOR UPDATE
+	`, projectID).Scan(&status, &funding)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("project not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock project: %w", err)
+	}
+	if status != entities.ProjectStatusFunded {
+		return fmt.Errorf("project is %s, not funded", status)
+	}
+	if !funding.WithCurrency(fundedAmount.Currency()).Equal(fundedAmount) {
+		return fmt.Errorf("project funding changed while defining tranches; retry")
+	}
+
+	var existing int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM disbursement_tranches WHERE project_id = $1
+	`, projectID).Scan(&existing)
+	if err != nil {
+		return fmt.Errorf("failed to check existing tranches: %w", err)
+	}
+	if existing > 0 {
+		return fmt.Errorf("project disbursement tranches are already defined")
+	}
+
+	for _, t := range tranches {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO disbursement_tranches (id, project_id, cooperative_id, milestone_id, milestone_title,
+				milestone_status, sequence, currency, budget, released_amount, unused_amount, status,
+				evidence_documents, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		`,
+			t.ID, t.ProjectID, t.CooperativeID, t.MilestoneID, t.MilestoneTitle, t.MilestoneStatus, t.Sequence,
+			t.Currency, t.Budget, t.ReleasedAmount, t.UnusedAmount, t.Status, pq.Array(t.EvidenceDocuments),
+			t.CreatedAt, t.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create disbursement tranche: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit disbursement tranches: %w", err)
+	}
+
+	return nil
+}
+
+func (r *disbursementRepository) ListTranches(ctx context.Context, projectID uuid.UUID) ([]*entities.DisbursementTranche, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+disbursementTrancheColumns+`
+		FROM disbursement_tranches
+		WHERE project_id = $1
+		ORDER BY sequence
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list disbursement tranches: %w", err)
+	}
+	defer rows.Close()
+
+	var tranches []*entities.DisbursementTranche
+	for rows.Next() {
+		tranche, err := scanDisbursementTranche(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan disbursement tranche: %w", err)
+		}
+		tranches = append(tranches, tranche)
+	}
+
+	return tranches, rows.Err()
+}
+
+func (r *disbursementRepository) GetTranche(ctx context.Context, projectID, trancheID uuid.UUID) (*entities.DisbursementTranche, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tranche, err := scanDisbursementTranche(shard.QueryRowContext(ctx, `
+		SELECT `+disbursementTrancheColumns+`
+		FROM disbursement_tranches
+		WHERE id = $1 AND project_id = $2
+	`, trancheID, projectID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("disbursement tranche not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disbursement tranche: %w", err)
+	}
+
+	return tranche, nil
+}
+
+func (r *disbursementRepository) GetMilestoneTranche(ctx context.Context, projectID, milestoneID uuid.UUID) (*entities.DisbursementTranche, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tranche, err := scanDisbursementTranche(shard.QueryRowContext(ctx, `
+		SELECT `+disbursementTrancheColumns+`
+		FROM disbursement_tranches
+		WHERE project_id = $1 AND milestone_id = $2
+	`, projectID, milestoneID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disbursement tranche: %w", err)
+	}
+
+	return tranche, nil
+}
+
+func (r *disbursementRepository) UpdateTrancheMilestone(ctx context.Context, tranche *entities.DisbursementTranche, fromStatus, fromMilestoneStatus string) (bool, error) {
+	shard, _, err := r.shardMgr.GetShardByID(tranche.ProjectID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE disbursement_tranches
+		SET milestone_status = $3, status = $4, evidence_documents = $5, completion_notes = $6, completed_by = $7,
+			completed_at = $8, verified_by = $9, verified_at = $10, verification_notes = $11, updated_at = $12
+		WHERE id = $1 AND project_id = $2 AND status = $13 AND milestone_status = $14
+	`,
+		tranche.ID, tranche.ProjectID, tranche.MilestoneStatus, tranche.Status, pq.Array(tranche.EvidenceDocuments),
+		nullString(tranche.CompletionNotes), tranche.CompletedBy, tranche.CompletedAt, tranche.VerifiedBy,
+		tranche.VerifiedAt, nullString(tranche.VerificationNotes), tranche.UpdatedAt, fromStatus,
+		fromMilestoneStatus)
+	if err != nil {
+		return false, fmt.Errorf("failed to update disbursement tranche: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+func (r *disbursementRepository) CloseTranche(ctx context.Context, projectID, trancheID, closerID uuid.UUID, reason string, closedAt time.Time) (*entities.DisbursementTranche, error) {
+	_, shardIndex, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tranche, err := lockDisbursementTranche(ctx, tx, projectID, trancheID)
+	if err != nil {
+		return nil, err
+	}
+	if tranche.Status != entities.DisbursementTrancheStatusPlanned && tranche.Status != entities.DisbursementTrancheStatusOpen {
+		return nil, fmt.Errorf("disbursement tranche is %s and cannot be closed", tranche.Status)
+	}
+
+	outstanding, err := trancheOutstandingAmount(ctx, tx, tranche)
+	if err != nil {
+		return nil, err
+	}
+	if outstanding.IsPositive() {
+		return nil, fmt.Errorf("disbursement tranche has %s awaiting release; approve or reject it first", outstanding)
+	}
+
+	tranche.UnusedAmount = tranche.UnusedAmount.Add(tranche.RemainingBudget())
+	tranche.Status = entities.DisbursementTrancheStatusClosed
+	tranche.ClosedBy = &closerID
+	tranche.ClosedAt = &closedAt
+	tranche.CloseReason = reason
+	tranche.UpdatedAt = closedAt
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE disbursement_tranches
+		SET status = $3, unused_amount = $4, closed_by = $5, closed_at = $6, close_reason = $7, updated_at = $6
+		WHERE id = $1 AND project_id = $2
+	`, tranche.ID, tranche.ProjectID, tranche.Status, tranche.UnusedAmount, tranche.ClosedBy, tranche.ClosedAt,
+		nullString(tranche.CloseReason))
+	if err != nil {
+		return nil, fmt.Errorf("failed to close disbursement tranche: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tranche closure: %w", err)
+	}
+
+	return tranche, nil
+}
+
+// lockDisbursementTranche reads a tranche for update
+func lockDisbursementTranche(ctx context.Context, tx *sql.Tx, projectID, trancheID uuid.UUID) (*entities.DisbursementTranche, error) {
+	tranche, err := scanDisbursementTranche(tx.QueryRowContext(ctx, `
+		SELECT `+disbursementTrancheColumns+`
+		FROM disbursement_tranches
+		WHERE id = $1 AND project_id = $2
+		FOR UPDATE
+	`, trancheID, projectID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("disbursement tranche not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock disbursement tranche: %w", err)
+	}
+
+	return tranche, nil
+}
+
+// trancheOutstandingAmount sums the tranche's disbursements awaiting release
+func trancheOutstandingAmount(ctx context.Context, tx *sql.Tx, tranche *entities.DisbursementTranche) (entities.Money, error) {
+	var outstanding entities.Money
+	err := tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(disbursement_amount), 0)
+		FROM fund_disbursements
+		WHERE tranche_id = $1 AND status IN ('pending', 'approved')
+	`, tranche.ID).Scan(&outstanding)
+	if err != nil {
+		return entities.Money{}, fmt.Errorf("failed to sum outstanding disbursements: %w", err)
+	}
+
+	return outstanding.WithCurrency(tranche.Currency), nil
+}
+
+func (r *disbursementRepository) CreateDisbursement(ctx context.Context, disbursement *entities.FundDisbursement, requesterID uuid.UUID) error {
+	_, shardIndex, err := r.shardMgr.GetShardByID(disbursement.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tranche, err := lockDisbursementTranche(ctx, tx, disbursement.ProjectID, disbursement.TrancheID)
+	if err != nil {
+		return err
+	}
+	if !tranche.Releasable() {
+		return fmt.Errorf("milestone %s has not been verified for release", tranche.MilestoneTitle)
+	}
+
+	outstanding, err := trancheOutstandingAmount(ctx, tx, tranche)
+	if err != nil {
+		return err
+	}
+	available := tranche.RemainingBudget().Sub(outstanding)
+	if available.LessThan(disbursement.DisbursementAmount) {
+		return fmt.Errorf("milestone %s has %s of its budget left to disburse, requested %s", tranche.MilestoneTitle, available, disbursement.DisbursementAmount)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO fund_disbursements (id, project_id, business_id, cooperative_id, milestone_id, tranche_id,
+			disbursement_amount, currency, disbursement_type, disbursement_reason, status, bank_account, created_by,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`,
+		disbursement.ID, disbursement.ProjectID, disbursement.BusinessID, disbursement.CooperativeID,
+		disbursement.MilestoneID, disbursement.TrancheID, disbursement.DisbursementAmount, disbursement.Currency,
+		disbursement.DisbursementType, disbursement.DisbursementReason, disbursement.Status,
+		disbursement.BankAccount, requesterID, disbursement.CreatedAt, disbursement.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create fund disbursement: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit fund disbursement: %w", err)
+	}
+
+	return nil
+}
+
+func (r *disbursementRepository) GetDisbursement(ctx context.Context, disbursementID uuid.UUID) (*entities.FundDisbursement, error) {
+	// Disbursements are looked up without their project, so search every shard
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		disbursement, err := scanFundDisbursement(shard.QueryRowContext(ctx, `
+			SELECT `+fundDisbursementColumns+`
+			FROM fund_disbursements
+			WHERE id = $1
+		`, disbursementID))
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get fund disbursement: %w", err)
+		}
+		return disbursement, nil
+	}
+
+	return nil, fmt.Errorf("fund disbursement not found")
+}
+
+func (r *disbursementRepository) ListProjectDisbursements(ctx context.Context, projectID uuid.UUID, limit, offset int) ([]*entities.FundDisbursement, int, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	var total int
+	err = shard.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM fund_disbursements WHERE project_id = $1
+	`, projectID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count fund disbursements: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+fundDisbursementColumns+`
+		FROM fund_disbursements
+		WHERE project_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, projectID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list fund disbursements: %w", err)
+	}
+	defer rows.Close()
+
+	var disbursements []*entities.FundDisbursement
+	for rows.Next() {
+		disbursement, err := scanFundDisbursement(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan fund disbursement: %w", err)
+		}
+		disbursements = append(disbursements, disbursement)
+	}
+
+	return disbursements, total, rows.Err()
+}
+
+func (r *disbursementRepository) GetOutstandingAmount(ctx context.Context, projectID uuid.UUID, currency string) (entities.Money, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return entities.Money{}, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	var outstanding entities.Money
+	err = shard.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(disbursement_amount), 0)
+		FROM fund_disbursements
+		WHERE project_id = $1 AND status IN ('pending', 'approved')
+	`, projectID).Scan(&outstanding)
+	if err != nil {
+		return entities.Money{}, fmt.Errorf("failed to sum outstanding disbursements: %w", err)
+	}
+
+	return outstanding.WithCurrency(currency), nil
+}
+
+func (r *disbursementRepository) UpdateDisbursementStatus(ctx context.Context, disbursement *entities.FundDisbursement, fromStatus string) (bool, error) {
+	shard, _, err := r.shardMgr.GetShardByID(disbursement.ProjectID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE fund_disbursements
+		SET status = $3, approved_by = $4, approved_at = $5, rejection_reason = $6, updated_at = $7
+		WHERE id = $1 AND project_id = $2 AND status = $8
+	`,
+		disbursement.ID, disbursement.ProjectID, disbursement.Status, disbursement.ApprovedBy,
+		disbursement.ApprovedAt, nullString(disbursement.RejectionReason), disbursement.UpdatedAt, fromStatus)
+	if err != nil {
+		return false, fmt.Errorf("failed to update fund disbursement: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+func (r *disbursementRepository) MarkDisbursed(ctx context.Context, disbursement *entities.FundDisbursement, disbursedAt time.Time) (*entities.DisbursementTranche, error) {
+	_, shardIndex, err := r.shardMgr.GetShardByID(disbursement.ProjectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tranche, err := lockDisbursementTranche(ctx, tx, disbursement.ProjectID, disbursement.TrancheID)
+	if err != nil {
+		return nil, err
+	}
+	if tranche.Status != entities.DisbursementTrancheStatusOpen {
+		return nil, fmt.Errorf("disbursement tranche is %s and cannot release funds", tranche.Status)
+	}
+	if tranche.RemainingBudget().LessThan(disbursement.DisbursementAmount) {
+		return nil, fmt.Errorf("milestone %s has %s of its budget left to disburse, requested %s", tranche.MilestoneTitle, tranche.RemainingBudget(), disbursement.DisbursementAmount)
+	}
+
+	err = execOne(ctx, tx, "disbursement is no longer approved", `
+		UPDATE fund_disbursements
+		SET status = $3, disbursed_at = $4, updated_at = $4
+		WHERE id = $1 AND project_id = $2 AND status = $5
+	`, disbursement.ID, disbursement.ProjectID, entities.FundDisbursementStatusDisbursed, disbursedAt,
+		entities.FundDisbursementStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	tranche.ReleasedAmount = tranche.ReleasedAmount.Add(disbursement.DisbursementAmount)
+	remaining := tranche.RemainingBudget()
+	if remaining.IsZero() {
+		tranche.Status = entities.DisbursementTrancheStatusReleased
+	} else if disbursement.DisbursementType == entities.FundDisbursementTypeFinal {
+		// A final disbursement ends the milestone's releases once nothing else
+		// is awaiting release from it
+		outstanding, err := trancheOutstandingAmount(ctx, tx, tranche)
+		if err != nil {
+			return nil, err
+		}
+		if outstanding.IsZero() {
+			tranche.Status = entities.DisbursementTrancheStatusClosed
+			tranche.UnusedAmount = tranche.UnusedAmount.Add(remaining)
+			tranche.ClosedAt = &disbursedAt
+			tranche.CloseReason = "Final disbursement released"
+		}
+	}
+	tranche.UpdatedAt = disbursedAt
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE disbursement_tranches
+		SET released_amount = $3, unused_amount = $4, status = $5, closed_at = $6, close_reason = $7, updated_at = $8
+		WHERE id = $1 AND project_id = $2
+	`, tranche.ID, tranche.ProjectID, tranche.ReleasedAmount, tranche.UnusedAmount, tranche.Status,
+		tranche.ClosedAt, nullString(tranche.CloseReason), tranche.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release disbursement tranche: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit disbursement release: %w", err)
+	}
+
+	return tranche, nil
+}
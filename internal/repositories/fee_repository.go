@@ -0,0 +1,726 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// FeeRepository stores ComFunds fee schedules and promotional waivers, which are
+// reference data replicated to every shard, and the fee calculations, invoices
+// and credit notes of each cooperative, which live on the cooperative's shard
+type FeeRepository interface {
+	// Fee schedules
+	SaveFee(ctx context.Context, fee *entities.ComFundsFee) error
+	GetFee(ctx context.Context, feeID uuid.UUID) (*entities.ComFundsFee, error)
+	ListFees(ctx context.Context, filter *entities.ComFundsFeeFilter) ([]*entities.ComFundsFee, int, error)
+	// ListActiveFees returns the enabled schedules of a fee type, whatever their scope
+	ListActiveFees(ctx context.Context, feeType string) ([]*entities.ComFundsFee, error)
+
+	// Waivers
+	CreateWaiver(ctx context.Context, waiver *entities.FeeWaiver) error
+	RevokeWaiver(ctx context.Context, waiverID, revokerID uuid.UUID, revokedAt time.Time) error
+	ListWaivers(ctx context.Context, feeType string, activeOnly bool) ([]*entities.FeeWaiver, error)
+
+	// Calculations
+	GetFeeProject(ctx context.Context, projectID uuid.UUID) (*entities.FeeProject, error)
+	// CreateCalculation saves a calculation and, when a promotional waiver
+	// discounted it, the credit note for the waived amount, in one transaction
+	CreateCalculation(ctx context.Context, calculation *entities.ProjectFeeCalculation, creditNote *entities.FeeCreditNote) error
+	GetCalculation(ctx context.Context, calculationID uuid.UUID) (*entities.ProjectFeeCalculation, error)
+	// GetClosureCalculation returns the success fee charged on a funding
+	// closure, or nil if none has been
+	GetClosureCalculation(ctx context.Context, cooperativeID, closureID uuid.UUID) (*entities.ProjectFeeCalculation, error)
+	ListCalculations(ctx context.Context, cooperativeID uuid.UUID, filter *entities.ProjectFeeCalculationFilter) ([]*entities.ProjectFeeCalculation, int, error)
+
+	// CollectFee marks a calculated fee collected, issues its invoice under the
+	// cooperative's next invoice number and posts its ledger entry, in one transaction
+	CollectFee(ctx context.Context, calculation *entities.ProjectFeeCalculation, invoice *entities.FeeInvoice, entry *entities.JournalEntry) error
+	// WaiveFee marks a calculated fee waived and issues its credit note under
+	// the cooperative's next credit note number, in one transaction
+	WaiveFee(ctx context.Context, calculation *entities.ProjectFeeCalculation, creditNote *entities.FeeCreditNote) error
+	ListInvoices(ctx context.Context, cooperativeID uuid.UUID, limit, offset int) ([]*entities.FeeInvoice, int, error)
+	ListCreditNotes(ctx context.Context, cooperativeID uuid.UUID, limit, offset int) ([]*entities.FeeCreditNote, int, error)
+}
+
+type feeRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewFeeRepository(shardMgr *database.ShardManager) FeeRepository {
+	return &feeRepository{shardMgr: shardMgr}
+}
+
+const comFundsFeeColumns = `id, fee_type, fee_percentage, tier_mode, tiers, priority, COALESCE(currency, ''), is_enabled,
+	minimum_amount, maximum_amount, applicable_to, project_id, cooperative_id, effective_from, effective_to,
+	description, is_active, created_by, created_at, updated_at`
+
+func scanComFundsFee(row interface{ Scan(...interface{}) error }) (*entities.ComFundsFee, error) {
+	fee := &entities.ComFundsFee{}
+	var tiersJSON []byte
+	var description sql.NullString
+	err := row.Scan(
+		&fee.ID, &fee.FeeType, &fee.FeePercentage, &fee.TierMode, &tiersJSON, &fee.Priority, &fee.Currency,
+		&fee.IsEnabled, &fee.MinimumAmount, &fee.MaximumAmount, &fee.ApplicableTo, &fee.ProjectID,
+		&fee.CooperativeID, &fee.EffectiveFrom, &fee.EffectiveTo, &description, &fee.IsActive, &fee.CreatedBy,
+		&fee.CreatedAt, &fee.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(tiersJSON, &fee.Tiers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fee tiers: %w", err)
+	}
+	fee.Description = description.String
+	fee.MinimumAmount = fee.MinimumAmount.WithCurrency(fee.Currency)
+	fee.MaximumAmount = fee.MaximumAmount.WithCurrency(fee.Currency)
+	return fee, nil
+}
+
+const feeWaiverColumns = `id, fee_type, cooperative_id, project_id, waiver_percentage, valid_from, expires_at, reason,
+	is_active, created_by, revoked_by, revoked_at, created_at, updated_at`
+
+func scanFeeWaiver(row interface{ Scan(...interface{}) error }) (*entities.FeeWaiver, error) {
+	w := &entities.FeeWaiver{}
+	err := row.Scan(
+		&w.ID, &w.FeeType, &w.CooperativeID, &w.ProjectID, &w.WaiverPercentage, &w.ValidFrom, &w.ExpiresAt,
+		&w.Reason, &w.IsActive, &w.CreatedBy, &w.RevokedBy, &w.RevokedAt, &w.CreatedAt, &w.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+const projectFeeCalculationColumns = `id, project_id, cooperative_id, business_id, total_funding_amount, currency,
+	fee_schedule_id, fee_percentage, gross_fee_amount, waiver_id, waived_amount, fee_amount, net_amount_after_fee,
+	fee_status, calculated_at, collected_at, collected_by, transaction_reference, invoice_id, credit_note_id, notes,
+	funding_closure_id, is_active, created_at, updated_at`
+
+func scanProjectFeeCalculation(row interface{ Scan(...interface{}) error }) (*entities.ProjectFeeCalculation, error) {
+	c := &entities.ProjectFeeCalculation{}
+	var transactionReference, notes sql.NullString
+	err := row.Scan(
+		&c.ID, &c.ProjectID, &c.CooperativeID, &c.BusinessID, &c.TotalFundingAmount, &c.Currency,
+		&c.FeeScheduleID, &c.FeePercentage, &c.GrossFeeAmount, &c.WaiverID, &c.WaivedAmount, &c.FeeAmount,
+		&c.NetAmountAfterFee, &c.FeeStatus, &c.CalculatedAt, &c.CollectedAt, &c.CollectedBy, &transactionReference,
+		&c.InvoiceID, &c.CreditNoteID, &notes, &c.FundingClosureID, &c.IsActive, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.TransactionReference = transactionReference.String
+	c.Notes = notes.String
+	c.TotalFundingAmount = c.TotalFundingAmount.WithCurrency(c.Currency)
+	c.GrossFeeAmount = c.GrossFeeAmount.WithCurrency(c.Currency)
+	c.WaivedAmount = c.WaivedAmount.WithCurrency(c.Currency)
+	c.FeeAmount = c.FeeAmount.WithCurrency(c.Currency)
+	c.NetAmountAfterFee = c.NetAmountAfterFee.WithCurrency(c.Currency)
+	return c, nil
+}
+
+const feeInvoiceColumns = `id, invoice_number, cooperative_id, project_id, business_id, fee_calculation_id, currency,
+	gross_amount, discount_amount, net_amount, collection_method, transaction_reference, issued_by, issued_at,
+	created_at`
+
+func scanFeeInvoice(row interface{ Scan(...interface{}) error }) (*entities.FeeInvoice, error) {
+	inv := &entities.FeeInvoice{}
+	var transactionReference sql.NullString
+	err := row.Scan(
+		&inv.ID, &inv.InvoiceNumber, &inv.CooperativeID, &inv.ProjectID, &inv.BusinessID, &inv.FeeCalculationID,
+		&inv.Currency, &inv.GrossAmount, &inv.DiscountAmount, &inv.NetAmount, &inv.CollectionMethod,
+		&transactionReference, &inv.IssuedBy, &inv.IssuedAt, &inv.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	inv.TransactionReference = transactionReference.String
+	inv.GrossAmount = inv.GrossAmount.WithCurrency(inv.Currency)
+	inv.DiscountAmount = inv.DiscountAmount.WithCurrency(inv.Currency)
+	inv.NetAmount = inv.NetAmount.WithCurrency(inv.Currency)
+	return inv, nil
+}
+
+const feeCreditNoteColumns = `id, credit_note_number, cooperative_id, project_id, business_id, fee_calculation_id,
+	currency, amount, reason, issued_by, issued_at, created_at`
+
+func scanFeeCreditNote(row interface{ Scan(...interface{}) error }) (*entities.FeeCreditNote, error) {
+	cn := &entities.FeeCreditNote{}
+	err := row.Scan(
+		&cn.ID, &cn.CreditNoteNumber, &cn.CooperativeID, &cn.ProjectID, &cn.BusinessID, &cn.FeeCalculationID,
+		&cn.Currency, &cn.Amount, &cn.Reason, &cn.IssuedBy, &cn.IssuedAt, &cn.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cn.Amount = cn.Amount.WithCurrency(cn.Currency)
+	return cn, nil
+}
+
+func (r *feeRepository) SaveFee(ctx context.Context, fee *entities.ComFundsFee) error {
+	tiersJSON, err := json.Marshal(fee.Tiers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal fee tiers: %w", err)
+	}
+	if fee.Tiers == nil {
+		tiersJSON = []byte("[]")
+	}
+
+	query := `
+		INSERT INTO comfunds_fees (id, fee_type, fee_percentage, tier_mode, tiers, priority, currency, is_enabled,
+			minimum_amount, maximum_amount, applicable_to, project_id, cooperative_id, effective_from, effective_to,
+			description, is_active, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		ON CONFLICT (id) DO UPDATE SET
+			fee_type = EXCLUDED.fee_type,
+			fee_percentage = EXCLUDED.fee_percentage,
+			tier_mode = EXCLUDED.tier_mode,
+			tiers = EXCLUDED.tiers,
+			priority = EXCLUDED.priority,
+			currency = EXCLUDED.currency,
+			is_enabled = EXCLUDED.is_enabled,
+			minimum_amount = EXCLUDED.minimum_amount,
+			maximum_amount = EXCLUDED.maximum_amount,
+			applicable_to = EXCLUDED.applicable_to,
+			project_id = EXCLUDED.project_id,
+			cooperative_id = EXCLUDED.cooperative_id,
+			effective_from = EXCLUDED.effective_from,
+			effective_to = EXCLUDED.effective_to,
+			description = EXCLUDED.description,
+			is_active = EXCLUDED.is_active,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	err = r.shardMgr.ExecuteOnAllShards(ctx, query,
+		fee.ID, fee.FeeType, fee.FeePercentage, fee.TierMode, tiersJSON, fee.Priority, nullString(fee.Currency),
+		fee.IsEnabled, fee.MinimumAmount, fee.MaximumAmount, fee.ApplicableTo, fee.ProjectID, fee.CooperativeID,
+		fee.EffectiveFrom, fee.EffectiveTo, nullString(fee.Description), fee.IsActive, fee.CreatedBy,
+		fee.CreatedAt, fee.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save fee schedule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *feeRepository) GetFee(ctx context.Context, feeID uuid.UUID) (*entities.ComFundsFee, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := scanComFundsFee(shard.QueryRowContext(ctx, `
+		SELECT `+comFundsFeeColumns+` FROM comfunds_fees WHERE id = $1
+	`, feeID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("fee schedule not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee schedule: %w", err)
+	}
+
+	return fee, nil
+}
+
+func (r *feeRepository) ListFees(ctx context.Context, filter *entities.ComFundsFeeFilter) ([]*entities.ComFundsFee, int, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	where := ` WHERE is_active = true
+		AND ($1::text IS NULL OR fee_type = $1)
+		AND ($2::boolean IS NULL OR is_enabled = $2)
+		AND ($3::text IS NULL OR applicable_to = $3)
+		AND ($4::uuid IS NULL OR project_id = $4)
+		AND ($5::uuid IS NULL OR cooperative_id = $5)
+		AND ($6::timestamptz IS NULL OR effective_from >= $6)
+		AND ($7::timestamptz IS NULL OR effective_from <= $7)`
+	args := []interface{}{filter.FeeType, filter.IsEnabled, filter.ApplicableTo, filter.ProjectID,
+		filter.CooperativeID, filter.EffectiveFrom, filter.EffectiveTo}
+
+	var total int
+	if err := shard.QueryRowContext(ctx, `SELECT COUNT(*) FROM comfunds_fees`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count fee schedules: %w", err)
+	}
+
+	query := `SELECT ` + comFundsFeeColumns + ` FROM comfunds_fees` + where + `
+		ORDER BY effective_from DESC
+		LIMIT $8 OFFSET $9`
+
+	rows, err := shard.QueryContext(ctx, query, append(args, filter.Limit, (filter.Page-1)*filter.Limit)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list fee schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var fees []*entities.ComFundsFee
+	for rows.Next() {
+		fee, err := scanComFundsFee(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan fee schedule: %w", err)
+		}
+		fees = append(fees, fee)
+	}
+
+	return fees, total, rows.Err()
+}
+
+func (r *feeRepository) ListActiveFees(ctx context.Context, feeType string) ([]*entities.ComFundsFee, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+comFundsFeeColumns+`
+		FROM comfunds_fees
+		WHERE fee_type = $1 AND is_active = true AND is_enabled = true
+		ORDER BY effective_from DESC
+	`, feeType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fee schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var fees []*entities.ComFundsFee
+	for rows.Next() {
+		fee, err := scanComFundsFee(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan fee schedule: %w", err)
+		}
+		fees = append(fees, fee)
+	}
+
+	return fees, rows.Err()
+}
+
+func (r *feeRepository) CreateWaiver(ctx context.Context, waiver *entities.FeeWaiver) error {
+	query := `
+		INSERT INTO fee_waivers (` + feeWaiverColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	err := r.shardMgr.ExecuteOnAllShards(ctx, query,
+		waiver.ID, waiver.FeeType, waiver.CooperativeID, waiver.ProjectID, waiver.WaiverPercentage,
+		waiver.ValidFrom, waiver.ExpiresAt, waiver.Reason, waiver.IsActive, waiver.CreatedBy, waiver.RevokedBy,
+		waiver.RevokedAt, waiver.CreatedAt, waiver.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create fee waiver: %w", err)
+	}
+
+	return nil
+}
+
+func (r *feeRepository) RevokeWaiver(ctx context.Context, waiverID, revokerID uuid.UUID, revokedAt time.Time) error {
+	query := `
+		UPDATE fee_waivers
+		SET is_active = false, revoked_by = $2, revoked_at = $3
+		WHERE id = $1 AND is_active = true
+	`
+
+	if err := r.shardMgr.ExecuteOnAllShards(ctx, query, waiverID, revokerID, revokedAt); err != nil {
+		return fmt.Errorf("failed to revoke fee waiver: %w", err)
+	}
+
+	return nil
+}
+
+func (r *feeRepository) ListWaivers(ctx context.Context, feeType string, activeOnly bool) ([]*entities.FeeWaiver, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + feeWaiverColumns + `
+		FROM fee_waivers
+		WHERE ($1 = '' OR fee_type = $1)
+	`
+	if activeOnly {
+		query += ` AND is_active = true AND expires_at > NOW()`
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := shard.QueryContext(ctx, query, feeType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fee waivers: %w", err)
+	}
+	defer rows.Close()
+
+	var waivers []*entities.FeeWaiver
+	for rows.Next() {
+		waiver, err := scanFeeWaiver(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan fee waiver: %w", err)
+		}
+		waivers = append(waivers, waiver)
+	}
+
+	return waivers, rows.Err()
+}
+
+func (r *feeRepository) GetFeeProject(ctx context.Context, projectID uuid.UUID) (*entities.FeeProject, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	project := &entities.FeeProject{}
+	err = shard.QueryRowContext(ctx, `
+		SELECT p.id, b.cooperative_id, b.id, COALESCE(c.currency, '')
+		FROM projects p
+		JOIN businesses b ON b.id = p.business_id
+		LEFT JOIN project_contracts c ON c.project_id = p.id
+		WHERE p.id = $1
+	`, projectID).Scan(&project.ProjectID, &project.CooperativeID, &project.BusinessID, &project.Currency)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("project not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+func (r *feeRepository) CreateCalculation(ctx context.Context, calculation *entities.ProjectFeeCalculation, creditNote *entities.FeeCreditNote) error {
+	_, shardIndex, err := r.shardMgr.GetShardByCooperativeID(calculation.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO project_fee_calculations (`+projectFeeCalculationColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25)
+	`,
+		calculation.ID, calculation.ProjectID, calculation.CooperativeID, calculation.BusinessID,
+		calculation.TotalFundingAmount, calculation.Currency, calculation.FeeScheduleID, calculation.FeePercentage,
+		calculation.GrossFeeAmount, calculation.WaiverID, calculation.WaivedAmount, calculation.FeeAmount,
+		calculation.NetAmountAfterFee, calculation.FeeStatus, calculation.CalculatedAt, calculation.CollectedAt,
+		calculation.CollectedBy, nullString(calculation.TransactionReference), calculation.InvoiceID, calculation.CreditNoteID,
+		nullString(calculation.Notes), calculation.FundingClosureID, calculation.IsActive, calculation.CreatedAt, calculation.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create project fee calculation: %w", err)
+	}
+
+	if creditNote != nil {
+		if err := insertFeeCreditNote(ctx, tx, creditNote); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *feeRepository) GetCalculation(ctx context.Context, calculationID uuid.UUID) (*entities.ProjectFeeCalculation, error) {
+	// Calculations are looked up without their cooperative, so search every shard
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		calculation, err := scanProjectFeeCalculation(shard.QueryRowContext(ctx, `
+			SELECT `+projectFeeCalculationColumns+`
+			FROM project_fee_calculations
+			WHERE id = $1
+		`, calculationID))
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project fee calculation: %w", err)
+		}
+		return calculation, nil
+	}
+
+	return nil, fmt.Errorf("project fee calculation not found")
+}
+
+func (r *feeRepository) GetClosureCalculation(ctx context.Context, cooperativeID, closureID uuid.UUID) (*entities.ProjectFeeCalculation, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	calculation, err := scanProjectFeeCalculation(shard.QueryRowContext(ctx, `
+		SELECT `+projectFeeCalculationColumns+`
+		FROM project_fee_calculations
+		WHERE funding_closure_id = $1
+	`, closureID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project fee calculation: %w", err)
+	}
+
+	return calculation, nil
+}
+
+func (r *feeRepository) ListCalculations(ctx context.Context, cooperativeID uuid.UUID, filter *entities.ProjectFeeCalculationFilter) ([]*entities.ProjectFeeCalculation, int, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	where := ` WHERE cooperative_id = $1
+		AND ($2::uuid IS NULL OR project_id = $2)
+		AND ($3::text IS NULL OR fee_status = $3)
+		AND ($4::timestamptz IS NULL OR calculated_at >= $4)
+		AND ($5::timestamptz IS NULL OR calculated_at <= $5)`
+	args := []interface{}{cooperativeID, filter.ProjectID, filter.FeeStatus, filter.StartDate, filter.EndDate}
+
+	var total int
+	if err := shard.QueryRowContext(ctx, `SELECT COUNT(*) FROM project_fee_calculations`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count project fee calculations: %w", err)
+	}
+
+	query := `SELECT ` + projectFeeCalculationColumns + ` FROM project_fee_calculations` + where + `
+		ORDER BY calculated_at DESC
+		LIMIT $6 OFFSET $7`
+
+	rows, err := shard.QueryContext(ctx, query, append(args, filter.Limit, (filter.Page-1)*filter.Limit)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list project fee calculations: %w", err)
+	}
+	defer rows.Close()
+
+	var calculations []*entities.ProjectFeeCalculation
+	for rows.Next() {
+		calculation, err := scanProjectFeeCalculation(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan project fee calculation: %w", err)
+		}
+		calculations = append(calculations, calculation)
+	}
+
+	return calculations, total, rows.Err()
+}
+
+// nextFeeDocumentNumber allocates the cooperative's next number for a document
+// type in the year, e.g. INV-2026-000001
+func nextFeeDocumentNumber(ctx context.Context, tx *sql.Tx, cooperativeID uuid.UUID, documentType, prefix string, issuedAt time.Time) (string, error) {
+	year := issuedAt.Year()
+
+	var number int
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO fee_document_sequences (cooperative_id, document_type, year, last_number)
+		VALUES ($1, $2, $3, 1)
+		ON CONFLICT (cooperative_id, document_type, year) DO UPDATE SET
+			last_number = fee_document_sequences.last_number + 1
+		RETURNING last_number
+	`, cooperativeID, documentType, year).Scan(&number)
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate %s number: %w", documentType, err)
+	}
+
+	return fmt.Sprintf("%s-%d-%06d", prefix, year, number), nil
+}
+
+func (r *feeRepository) CollectFee(ctx context.Context, calculation *entities.ProjectFeeCalculation, invoice *entities.FeeInvoice, entry *entities.JournalEntry) error {
+	_, shardIndex, err := r.shardMgr.GetShardByCooperativeID(calculation.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = execOne(ctx, tx, "project fee is no longer awaiting collection", `
+		UPDATE project_fee_calculations
+		SET fee_status = $2, collected_at = $3, collected_by = $4, transaction_reference = $5, invoice_id = $6
+		WHERE id = $1 AND fee_status = $7
+	`, calculation.ID, entities.ProjectFeeStatusCollected, invoice.IssuedAt, invoice.IssuedBy,
+		nullString(invoice.TransactionReference), invoice.ID, entities.ProjectFeeStatusCalculated)
+	if err != nil {
+		return err
+	}
+
+	invoice.InvoiceNumber, err = nextFeeDocumentNumber(ctx, tx, invoice.CooperativeID, entities.FeeDocumentTypeInvoice, "INV", invoice.IssuedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO fee_invoices (`+feeInvoiceColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`,
+		invoice.ID, invoice.InvoiceNumber, invoice.CooperativeID, invoice.ProjectID, invoice.BusinessID,
+		invoice.FeeCalculationID, invoice.Currency, invoice.GrossAmount, invoice.DiscountAmount, invoice.NetAmount,
+		invoice.CollectionMethod, nullString(invoice.TransactionReference), invoice.IssuedBy, invoice.IssuedAt,
+		invoice.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create fee invoice: %w", err)
+	}
+
+	// The ledger is sharded by cooperative too, so the entry commits with the invoice
+	if err := insertJournalEntry(ctx, tx, entry, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *feeRepository) WaiveFee(ctx context.Context, calculation *entities.ProjectFeeCalculation, creditNote *entities.FeeCreditNote) error {
+	_, shardIndex, err := r.shardMgr.GetShardByCooperativeID(calculation.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = execOne(ctx, tx, "project fee is no longer awaiting collection", `
+		UPDATE project_fee_calculations
+		SET fee_status = $2, credit_note_id = $3, notes = $4
+		WHERE id = $1 AND fee_status = $5
+	`, calculation.ID, entities.ProjectFeeStatusWaived, creditNote.ID, nullString(creditNote.Reason),
+		entities.ProjectFeeStatusCalculated)
+	if err != nil {
+		return err
+	}
+
+	if err := insertFeeCreditNote(ctx, tx, creditNote); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// insertFeeCreditNote issues a credit note under the cooperative's next
+// credit note number within tx
+func insertFeeCreditNote(ctx context.Context, tx *sql.Tx, creditNote *entities.FeeCreditNote) error {
+	var err error
+	creditNote.CreditNoteNumber, err = nextFeeDocumentNumber(ctx, tx, creditNote.CooperativeID, entities.FeeDocumentTypeCreditNote, "CN", creditNote.IssuedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO fee_credit_notes (`+feeCreditNoteColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`,
+		creditNote.ID, creditNote.CreditNoteNumber, creditNote.CooperativeID, creditNote.ProjectID,
+		creditNote.BusinessID, creditNote.FeeCalculationID, creditNote.Currency, creditNote.Amount,
+		creditNote.Reason, creditNote.IssuedBy, creditNote.IssuedAt, creditNote.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create fee credit note: %w", err)
+	}
+
+	return nil
+}
+
+func (r *feeRepository) ListInvoices(ctx context.Context, cooperativeID uuid.UUID, limit, offset int) ([]*entities.FeeInvoice, int, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	var total int
+	if err := shard.QueryRowContext(ctx, `SELECT COUNT(*) FROM fee_invoices WHERE cooperative_id = $1`, cooperativeID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count fee invoices: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+feeInvoiceColumns+`
+		FROM fee_invoices
+		WHERE cooperative_id = $1
+		ORDER BY issued_at DESC, invoice_number DESC
+		LIMIT $2 OFFSET $3
+	`, cooperativeID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list fee invoices: %w", err)
+	}
+	defer rows.Close()
+
+	var invoices []*entities.FeeInvoice
+	for rows.Next() {
+		invoice, err := scanFeeInvoice(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan fee invoice: %w", err)
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	return invoices, total, rows.Err()
+}
+
+func (r *feeRepository) ListCreditNotes(ctx context.Context, cooperativeID uuid.UUID, limit, offset int) ([]*entities.FeeCreditNote, int, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	var total int
+	if err := shard.QueryRowContext(ctx, `SELECT COUNT(*) FROM fee_credit_notes WHERE cooperative_id = $1`, cooperativeID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count fee credit notes: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+feeCreditNoteColumns+`
+		FROM fee_credit_notes
+		WHERE cooperative_id = $1
+		ORDER BY issued_at DESC, credit_note_number DESC
+		LIMIT $2 OFFSET $3
+	`, cooperativeID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list fee credit notes: %w", err)
+	}
+	defer rows.Close()
+
+	var creditNotes []*entities.FeeCreditNote
+	for rows.Next() {
+		creditNote, err := scanFeeCreditNote(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan fee credit note: %w", err)
+		}
+		creditNotes = append(creditNotes, creditNote)
+	}
+
+	return creditNotes, total, rows.Err()
+}
@@ -0,0 +1,422 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// FundTransferRepository stores fund transfers and the attempts made to execute
+// them (FR-021). Both live on the transfer's project shard.
+type FundTransferRepository interface {
+	// GetProjectCooperativeID returns the cooperative of the project's business
+	GetProjectCooperativeID(ctx context.Context, projectID uuid.UUID) (uuid.UUID, error)
+	CreateTransfer(ctx context.Context, transfer *entities.FundTransfer) error
+	GetTransfer(ctx context.Context, transferID uuid.UUID) (*entities.FundTransfer, error)
+	// ListDeadLettered returns dead-lettered transfers on every shard, most
+	// recently dead-lettered first
+	ListDeadLettered(ctx context.Context, limit, offset int) ([]*entities.FundTransfer, int, error)
+	ListAttempts(ctx context.Context, transfer *entities.FundTransfer) ([]*entities.FundTransferAttempt, error)
+
+	// ProcessDueTransfers executes up to limit pending or failed transfers due
+	// by now, one at a time. Each is locked with FOR UPDATE SKIP LOCKED so
+	// concurrent workers never pick the same transfer, and stays locked while
+	// process executes it. The transfer as process leaves it is saved along
+	// with the attempt it returns, numbered after the transfer's earlier
+	// attempts, in the same transaction. The transfers whose attempt was
+	// committed are returned. A shard that fails is left for the next run
+	// while the other shards are processed, and its error is returned with the
+	// transfers processed elsewhere.
+	ProcessDueTransfers(ctx context.Context, now time.Time, limit int, process func(*entities.FundTransfer) *entities.FundTransferAttempt) ([]*entities.FundTransfer, error)
+	// RequeueTransfer returns a dead-lettered transfer to the queue with its
+	// retries reset, failing if it is no longer dead-lettered
+	RequeueTransfer(ctx context.Context, transfer *entities.FundTransfer) error
+	// CancelTransfer cancels a pending, failed or dead-lettered transfer,
+	// failing if it was executed or cancelled in the meantime
+	CancelTransfer(ctx context.Context, transfer *entities.FundTransfer) error
+}
+
+type fundTransferRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewFundTransferRepository(shardMgr *database.ShardManager) FundTransferRepository {
+	return &fundTransferRepository{shardMgr: shardMgr}
+}
+
+const fundTransferColumns = `id, transfer_number, project_id, investment_id, cooperative_id, from_account_id,
+	to_account_id, from_user_id, to_user_id, transfer_type, amount, currency, exchange_rate, settlement_currency,
+	settlement_amount, fee, net_amount, status, payment_method, payment_reference, bank_account,
+	bank_transaction_id, description, notes, metadata, scheduled_at, processed_at, completed_at, failed_at,
+	failure_reason, retry_count, max_retries, next_attempt_at, dead_lettered_at, initiated_by, approved_by,
+	cancelled_by, cancelled_at, cancellation_reason, created_at, updated_at`
+
+func scanFundTransfer(row interface{ Scan(...interface{}) error }) (*entities.FundTransfer, error) {
+	t := &entities.FundTransfer{}
+	var paymentReference, bankAccount, bankTransactionID, notes, failureReason, cancellationReason sql.NullString
+	var metadataJSON []byte
+	err := row.Scan(
+		&t.ID, &t.TransferNumber, &t.ProjectID, &t.InvestmentID, &t.CooperativeID, &t.FromAccountID,
+		&t.ToAccountID, &t.FromUserID, &t.ToUserID, &t.TransferType, &t.Amount, &t.Currency, &t.ExchangeRate,
+		&t.SettlementCurrency, &t.SettlementAmount, &t.Fee, &t.NetAmount, &t.Status, &t.PaymentMethod,
+		&paymentReference, &bankAccount, &bankTransactionID, &t.Description, &notes, &metadataJSON,
+		&t.ScheduledAt, &t.ProcessedAt, &t.CompletedAt, &t.FailedAt, &failureReason, &t.RetryCount,
+		&t.MaxRetries, &t.NextAttemptAt, &t.DeadLetteredAt, &t.InitiatedBy, &t.ApprovedBy, &t.CancelledBy,
+		&t.CancelledAt, &cancellationReason, &t.CreatedAt, &t.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(metadataJSON, &t.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transfer metadata: %w", err)
+	}
+	t.PaymentReference = paymentReference.String
+	t.BankAccount = bankAccount.String
+	t.BankTransactionID = bankTransactionID.String
+	t.Notes = notes.String
+	t.FailureReason = failureReason.String
+	t.CancellationReason = cancellationReason.String
+	t.Amount = t.Amount.WithCurrency(t.Currency)
+	t.Fee = t.Fee.WithCurrency(t.Currency)
+	t.NetAmount = t.NetAmount.WithCurrency(t.Currency)
+	t.SettlementAmount = t.SettlementAmount.WithCurrency(t.SettlementCurrency)
+	return t, nil
+}
+
+const fundTransferAttemptColumns = `id, transfer_id, attempt_number, provider, outcome, provider_reference,
+	error_message, started_at, finished_at, created_at`
+
+func scanFundTransferAttempt(row interface{ Scan(...interface{}) error }) (*entities.FundTransferAttempt, error) {
+	a := &entities.FundTransferAttempt{}
+	var providerReference, errorMessage sql.NullString
+	err := row.Scan(
+		&a.ID, &a.TransferID, &a.AttemptNumber, &a.Provider, &a.Outcome, &providerReference, &errorMessage,
+		&a.StartedAt, &a.FinishedAt, &a.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	a.ProviderReference = providerReference.String
+	a.ErrorMessage = errorMessage.String
+	return a, nil
+}
+
+func (r *fundTransferRepository) GetProjectCooperativeID(ctx context.Context, projectID uuid.UUID) (uuid.UUID, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	var cooperativeID uuid.UUID
+	err = shard.QueryRowContext(ctx, `
+		SELECT b.cooperative_id
+		FROM projects p
+		JOIN businesses b ON b.id = p.business_id
+		WHERE p.id = $1
+	`, projectID).Scan(&cooperativeID)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, fmt.Errorf("project not found")
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get project cooperative: %w", err)
+	}
+
+	return cooperativeID, nil
+}
+
+func (r *fundTransferRepository) CreateTransfer(ctx context.Context, transfer *entities.FundTransfer) error {
+	shard, _, err := r.shardMgr.GetShardByID(transfer.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	metadataJSON, err := json.Marshal(transfer.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal transfer metadata: %w", err)
+	}
+	if transfer.Metadata == nil {
+		metadataJSON = []byte("{}")
+	}
+
+	query := `
+		INSERT INTO fund_transfers (` + fundTransferColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21,
+			$22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41)
+	`
+
+	_, err = shard.ExecContext(ctx, query,
+		transfer.ID, transfer.TransferNumber, transfer.ProjectID, transfer.InvestmentID, transfer.CooperativeID,
+		transfer.FromAccountID, transfer.ToAccountID, transfer.FromUserID, transfer.ToUserID, transfer.TransferType,
+		transfer.Amount, transfer.Currency, transfer.ExchangeRate, transfer.SettlementCurrency,
+		transfer.SettlementAmount, transfer.Fee, transfer.NetAmount, transfer.Status, transfer.PaymentMethod,
+		nullString(transfer.PaymentReference), nullString(transfer.BankAccount),
+		nullString(transfer.BankTransactionID), transfer.Description, nullString(transfer.Notes), metadataJSON,
+		transfer.ScheduledAt, transfer.ProcessedAt, transfer.CompletedAt, transfer.FailedAt,
+		nullString(transfer.FailureReason), transfer.RetryCount, transfer.MaxRetries, transfer.NextAttemptAt,
+		transfer.DeadLetteredAt, transfer.InitiatedBy, transfer.ApprovedBy, transfer.CancelledBy,
+		transfer.CancelledAt, nullString(transfer.CancellationReason), transfer.CreatedAt, transfer.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create fund transfer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *fundTransferRepository) GetTransfer(ctx context.Context, transferID uuid.UUID) (*entities.FundTransfer, error) {
+	// Transfers are looked up without their project, so search every shard
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		transfer, err := scanFundTransfer(shard.QueryRowContext(ctx, `
+			SELECT `+fundTransferColumns+` FROM fund_transfers WHERE id = $1
+		`, transferID))
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get fund transfer: %w", err)
+		}
+		return transfer, nil
+	}
+
+	return nil, fmt.Errorf("fund transfer not found")
+}
+
+func (r *fundTransferRepository) ListDeadLettered(ctx context.Context, limit, offset int) ([]*entities.FundTransfer, int, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	// The dead-letter queue is expected to stay short, so it is merged and
+	// paged in memory
+	var transfers []*entities.FundTransfer
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		rows, err := shard.QueryContext(ctx, `
+			SELECT `+fundTransferColumns+` FROM fund_transfers WHERE status = $1
+		`, entities.TransferStatusDeadLettered)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list dead-lettered transfers: %w", err)
+		}
+
+		for rows.Next() {
+			transfer, err := scanFundTransfer(rows)
+			if err != nil {
+				rows.Close()
+				return nil, 0, fmt.Errorf("failed to scan fund transfer: %w", err)
+			}
+			transfers = append(transfers, transfer)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list dead-lettered transfers: %w", err)
+		}
+	}
+
+	sort.Slice(transfers, func(i, j int) bool {
+		return transfers[i].DeadLetteredAt.After(*transfers[j].DeadLetteredAt)
+	})
+
+	total := len(transfers)
+	if offset >= total {
+		return nil, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return transfers[offset:end], total, nil
+}
+
+func (r *fundTransferRepository) ListAttempts(ctx context.Context, transfer *entities.FundTransfer) ([]*entities.FundTransferAttempt, error) {
+	shard, _, err := r.shardMgr.GetShardByID(transfer.ProjectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+fundTransferAttemptColumns+`
+		FROM fund_transfer_attempts
+		WHERE transfer_id = $1
+		ORDER BY attempt_number
+	`, transfer.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfer attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []*entities.FundTransferAttempt
+	for rows.Next() {
+		attempt, err := scanFundTransferAttempt(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transfer attempt: %w", err)
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, rows.Err()
+}
+
+func (r *fundTransferRepository) ProcessDueTransfers(ctx context.Context, now time.Time, limit int, process func(*entities.FundTransfer) *entities.FundTransferAttempt) ([]*entities.FundTransfer, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	var processed []*entities.FundTransfer
+	var shardErrs []error
+	for shardIndex, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		for len(processed) < limit {
+			transfer, err := r.processNextDueTransfer(ctx, shardIndex, now, process)
+			if err != nil {
+				shardErrs = append(shardErrs, fmt.Errorf("shard %d: %w", shardIndex, err))
+				break
+			}
+			if transfer == nil {
+				break
+			}
+			processed = append(processed, transfer)
+		}
+	}
+
+	return processed, errors.Join(shardErrs...)
+}
+
+// processNextDueTransfer executes the shard's earliest due transfer not locked
+// by another worker, returning it once its attempt is committed, or nil if no
+// transfer was due
+func (r *fundTransferRepository) processNextDueTransfer(ctx context.Context, shardIndex int, now time.Time, process func(*entities.FundTransfer) *entities.FundTransferAttempt) (*entities.FundTransfer, error) {
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	transfer, err := scanFundTransfer(tx.QueryRowContext(ctx, `
+		SELECT `+fundTransferColumns+`
+		FROM fund_transfers
+		WHERE status IN ($1, $2) AND next_attempt_at <= $3
+		ORDER BY next_attempt_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`, entities.TransferStatusPending, entities.TransferStatusFailed, now))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock due transfer: %w", err)
+	}
+
+	attempt := process(transfer)
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE fund_transfers
+		SET status = $2, bank_transaction_id = $3, processed_at = $4, completed_at = $5, failed_at = $6,
+			failure_reason = $7, retry_count = $8, next_attempt_at = $9, dead_lettered_at = $10
+		WHERE id = $1
+	`,
+		transfer.ID, transfer.Status, nullString(transfer.BankTransactionID), transfer.ProcessedAt,
+		transfer.CompletedAt, transfer.FailedAt, nullString(transfer.FailureReason), transfer.RetryCount,
+		transfer.NextAttemptAt, transfer.DeadLetteredAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update fund transfer: %w", err)
+	}
+
+	// Attempts are numbered across requeues; the transfer's lock keeps the
+	// numbering free of races
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO fund_transfer_attempts (`+fundTransferAttemptColumns+`)
+		VALUES ($1, $2, (SELECT COALESCE(MAX(attempt_number), 0) + 1 FROM fund_transfer_attempts WHERE transfer_id = $2),
+			$3, $4, $5, $6, $7, $8, $9)
+		RETURNING attempt_number
+	`,
+		attempt.ID, attempt.TransferID, attempt.Provider, attempt.Outcome, nullString(attempt.ProviderReference),
+		nullString(attempt.ErrorMessage), attempt.StartedAt, attempt.FinishedAt, attempt.CreatedAt,
+	).Scan(&attempt.AttemptNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record transfer attempt: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transfer attempt: %w", err)
+	}
+
+	return transfer, nil
+}
+
+func (r *fundTransferRepository) RequeueTransfer(ctx context.Context, transfer *entities.FundTransfer) error {
+	shard, _, err := r.shardMgr.GetShardByID(transfer.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE fund_transfers
+		SET status = $2, retry_count = $3, next_attempt_at = $4, dead_lettered_at = NULL
+		WHERE id = $1 AND status = $5
+	`, transfer.ID, transfer.Status, transfer.RetryCount, transfer.NextAttemptAt, entities.TransferStatusDeadLettered)
+	if err != nil {
+		return fmt.Errorf("failed to requeue fund transfer: %w", err)
+	}
+
+	return expectOneRow(result, "fund transfer is no longer dead-lettered")
+}
+
+func (r *fundTransferRepository) CancelTransfer(ctx context.Context, transfer *entities.FundTransfer) error {
+	shard, _, err := r.shardMgr.GetShardByID(transfer.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	// A transfer being executed is locked, so this waits for the attempt and
+	// then only cancels it if the attempt did not complete it
+	result, err := shard.ExecContext(ctx, `
+		UPDATE fund_transfers
+		SET status = $2, cancelled_by = $3, cancelled_at = $4, cancellation_reason = $5, next_attempt_at = NULL
+		WHERE id = $1 AND status IN ($6, $7, $8)
+	`, transfer.ID, transfer.Status, transfer.CancelledBy, transfer.CancelledAt, nullString(transfer.CancellationReason),
+		entities.TransferStatusPending, entities.TransferStatusFailed, entities.TransferStatusDeadLettered)
+	if err != nil {
+		return fmt.Errorf("failed to cancel fund transfer: %w", err)
+	}
+
+	return expectOneRow(result, "fund transfer can no longer be cancelled")
+}
+
+func expectOneRow(result sql.Result, conflict string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected != 1 {
+		return fmt.Errorf("%s", conflict)
+	}
+	return nil
+}
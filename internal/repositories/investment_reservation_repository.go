@@ -0,0 +1,337 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// InvestmentReservationRepository stores the funding reservations of pending
+// investments. Reservations live on their project's shard and are made and
+// converted with the project row locked, so the committed and reserved
+// amounts together never exceed the funding goal.
+type InvestmentReservationRepository interface {
+	// Reserve records a reservation if the project is open for investment and
+	// its goal, less the committed funding and the reservations still held at
+	// now, has room for the amount
+	Reserve(ctx context.Context, reservation *entities.InvestmentReservation, now time.Time) error
+	GetReservation(ctx context.Context, investmentID uuid.UUID) (*entities.InvestmentReservation, error)
+	// ConvertReservation adds a reservation's amount to the project's
+	// committed funding. A reservation that expired is only converted if the
+	// goal still has room for it.
+	ConvertReservation(ctx context.Context, reservation *entities.InvestmentReservation, now time.Time) error
+	// ReleaseReservation releases an active reservation, reporting whether it
+	// was still active
+	ReleaseReservation(ctx context.Context, reservation *entities.InvestmentReservation) (bool, error)
+	// ExpireReservations marks active reservations past their expiry on every
+	// shard as expired
+	ExpireReservations(ctx context.Context, now time.Time) (int, error)
+	// GetFundingProgress returns the project's committed, reserved and
+	// available funding at now in the project's currency, or in baseCurrency
+	// for a project without a contract currency
+	GetFundingProgress(ctx context.Context, projectID uuid.UUID, baseCurrency string, now time.Time) (*entities.ProjectFundingProgress, error)
+}
+
+type investmentReservationRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewInvestmentReservationRepository(shardMgr *database.ShardManager) InvestmentReservationRepository {
+	return &investmentReservationRepository{shardMgr: shardMgr}
+}
+
+const investmentReservationColumns = `id, investment_id, project_id, investor_id, amount, currency, status, expires_at,
+	converted_at, released_at, release_reason, created_at, updated_at`
+
+func scanInvestmentReservation(row interface{ Scan(...interface{}) error }) (*entities.InvestmentReservation, error) {
+	r := &entities.InvestmentReservation{}
+	var releaseReason sql.NullString
+	err := row.Scan(
+		&r.ID, &r.InvestmentID, &r.ProjectID, &r.InvestorID, &r.Amount, &r.Currency, &r.Status, &r.ExpiresAt,
+		&r.ConvertedAt, &r.ReleasedAt, &releaseReason, &r.CreatedAt, &r.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	r.ReleaseReason = releaseReason.String
+	r.Amount = r.Amount.WithCurrency(r.Currency)
+	return r, nil
+}
+
+// lockFundingProject locks the project row and returns its goal and committed
+// funding in currency, which must match the project's currency when it has one
+func lockFundingProject(ctx context.Context, tx *sql.Tx, projectID uuid.UUID, currency string) (string, entities.Money, entities.Money, error) {
+	var status, projectCurrency string
+	var goal, committed entities.Money
+	err := tx.QueryRowContext(ctx, `
+		SELECT p.status, COALESCE(c.currency, ''), p.funding_goal, p.current_funding
+		FROM projects p
+		LEFT JOIN project_contracts c ON c.project_id = p.id
+		WHERE p.id = $1
+		FOR UPDATE OF p
+	`, projectID).Scan(&status, &projectCurrency, &goal, &committed)
+	if err == sql.ErrNoRows {
+		return "", entities.Money{}, entities.Money{}, fmt.Errorf("project not found")
+	}
+	if err != nil {
+		return "", entities.Money{}, entities.Money{}, fmt.Errorf("failed to lock project: %w", err)
+	}
+	if projectCurrency != "" && projectCurrency != currency {
+		return "", entities.Money{}, entities.Money{}, fmt.Errorf("investment currency %s does not match project currency %s", currency, projectCurrency)
+	}
+
+	return status, goal.WithCurrency(currency), committed.WithCurrency(currency), nil
+}
+
+// heldReservations sums the project's reservations held at now, leaving out
+// the excluded one
+func heldReservations(ctx context.Context, q rowQuerier, projectID, excludeID uuid.UUID, currency string, now time.Time) (entities.Money, int, error) {
+	var reserved entities.Money
+	var count int
+	err := q.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount), 0), COUNT(*)
+		FROM investment_reservations
+		WHERE project_id = $1 AND status = $2 AND expires_at > $3 AND id <> $4
+	`, projectID, entities.InvestmentReservationStatusActive, now, excludeID).Scan(&reserved, &count)
+	if err != nil {
+		return entities.Money{}, 0, fmt.Errorf("failed to sum held reservations: %w", err)
+	}
+
+	return reserved.WithCurrency(currency), count, nil
+}
+
+func (r *investmentReservationRepository) Reserve(ctx context.Context, reservation *entities.InvestmentReservation, now time.Time) error {
+	_, shardIndex, err := r.shardMgr.GetShardByID(reservation.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	status, goal, committed, err := lockFundingProject(ctx, tx, reservation.ProjectID, reservation.Currency)
+	if err != nil {
+		return err
+	}
+	if status != entities.ProjectStatusApproved && status != entities.ProjectStatusActive {
+		return fmt.Errorf("project is %s and not open for investment", status)
+	}
+
+	reserved, _, err := heldReservations(ctx, tx, reservation.ProjectID, uuid.Nil, reservation.Currency, now)
+	if err != nil {
+		return err
+	}
+	available := goal.Sub(committed).Sub(reserved)
+	if reservation.Amount.GreaterThan(available) {
+		if available.IsNegative() {
+			available = entities.ZeroMoney(reservation.Currency)
+		}
+		return fmt.Errorf("project has only %s of its funding goal available", available)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO investment_reservations (`+investmentReservationColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`,
+		reservation.ID, reservation.InvestmentID, reservation.ProjectID, reservation.InvestorID, reservation.Amount,
+		reservation.Currency, reservation.Status, reservation.ExpiresAt, reservation.ConvertedAt,
+		reservation.ReleasedAt, nullString(reservation.ReleaseReason), reservation.CreatedAt, reservation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create investment reservation: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit investment reservation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *investmentReservationRepository) GetReservation(ctx context.Context, investmentID uuid.UUID) (*entities.InvestmentReservation, error) {
+	// Reservations are looked up by investment without their project, so
+	// search every shard
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		reservation, err := scanInvestmentReservation(shard.QueryRowContext(ctx, `
+			SELECT `+investmentReservationColumns+` FROM investment_reservations WHERE investment_id = $1
+		`, investmentID))
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get investment reservation: %w", err)
+		}
+		return reservation, nil
+	}
+
+	return nil, fmt.Errorf("investment reservation not found")
+}
+
+func (r *investmentReservationRepository) ConvertReservation(ctx context.Context, reservation *entities.InvestmentReservation, now time.Time) error {
+	_, shardIndex, err := r.shardMgr.GetShardByID(reservation.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, goal, committed, err := lockFundingProject(ctx, tx, reservation.ProjectID, reservation.Currency)
+	if err != nil {
+		return err
+	}
+
+	current, err := scanInvestmentReservation(tx.QueryRowContext(ctx, `
+		SELECT `+investmentReservationColumns+` FROM investment_reservations WHERE id = $1 FOR UPDATE
+	`, reservation.ID))
+	if err != nil {
+		return fmt.Errorf("failed to lock investment reservation: %w", err)
+	}
+
+	switch {
+	case current.IsHeld(now):
+	case current.Status == entities.InvestmentReservationStatusActive || current.Status == entities.InvestmentReservationStatusExpired:
+		// The hold lapsed; the investment still fits if nobody took its place
+		reserved, _, err := heldReservations(ctx, tx, reservation.ProjectID, reservation.ID, reservation.Currency, now)
+		if err != nil {
+			return err
+		}
+		if committed.Add(reserved).Add(current.Amount).GreaterThan(goal) {
+			return fmt.Errorf("investment reservation expired and the funding goal no longer has room for it")
+		}
+	default:
+		return fmt.Errorf("investment reservation is %s", current.Status)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE projects SET current_funding = current_funding + $2 WHERE id = $1
+	`, reservation.ProjectID, current.Amount)
+	if err != nil {
+		return fmt.Errorf("failed to commit project funding: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE investment_reservations SET status = $2, converted_at = $3 WHERE id = $1
+	`, reservation.ID, entities.InvestmentReservationStatusConverted, now)
+	if err != nil {
+		return fmt.Errorf("failed to convert investment reservation: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit investment reservation: %w", err)
+	}
+
+	reservation.Status = entities.InvestmentReservationStatusConverted
+	reservation.ConvertedAt = &now
+	return nil
+}
+
+func (r *investmentReservationRepository) ReleaseReservation(ctx context.Context, reservation *entities.InvestmentReservation) (bool, error) {
+	shard, _, err := r.shardMgr.GetShardByID(reservation.ProjectID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE investment_reservations
+		SET status = $2, released_at = $3, release_reason = $4
+		WHERE id = $1 AND status = $5
+	`, reservation.ID, reservation.Status, reservation.ReleasedAt, nullString(reservation.ReleaseReason),
+		entities.InvestmentReservationStatusActive)
+	if err != nil {
+		return false, fmt.Errorf("failed to release investment reservation: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (r *investmentReservationRepository) ExpireReservations(ctx context.Context, now time.Time) (int, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	expired := 0
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		result, err := shard.ExecContext(ctx, `
+			UPDATE investment_reservations
+			SET status = $1, released_at = expires_at, release_reason = 'reservation expired'
+			WHERE status = $2 AND expires_at <= $3
+		`, entities.InvestmentReservationStatusExpired, entities.InvestmentReservationStatusActive, now)
+		if err != nil {
+			return expired, fmt.Errorf("failed to expire investment reservations: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return expired, fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		expired += int(affected)
+	}
+
+	return expired, nil
+}
+
+func (r *investmentReservationRepository) GetFundingProgress(ctx context.Context, projectID uuid.UUID, baseCurrency string, now time.Time) (*entities.ProjectFundingProgress, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	var currency string
+	var goal, committed entities.Money
+	var investorCount int
+	err = shard.QueryRowContext(ctx, `
+		SELECT COALESCE(c.currency, ''), p.funding_goal, p.current_funding,
+			(SELECT COUNT(DISTINCT i.investor_id) FROM investments i WHERE i.project_id = p.id AND i.status = 'confirmed')
+		FROM projects p
+		LEFT JOIN project_contracts c ON c.project_id = p.id
+		WHERE p.id = $1
+	`, projectID).Scan(&currency, &goal, &committed, &investorCount)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("project not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project funding: %w", err)
+	}
+	if currency == "" {
+		currency = baseCurrency
+	}
+
+	reserved, reservationCount, err := heldReservations(ctx, shard, projectID, uuid.Nil, currency, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.NewProjectFundingProgress(projectID, goal.WithCurrency(currency), committed.WithCurrency(currency),
+		reserved, investorCount, reservationCount), nil
+}
@@ -0,0 +1,440 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// InvestmentWithdrawalRepository stores investors' early withdrawal requests.
+// Withdrawals live on the project's shard alongside the investments they
+// reduce.
+type InvestmentWithdrawalRepository interface {
+	// GetInvestmentPosition returns an investment with its project's funding and
+	// cooperative
+	GetInvestmentPosition(ctx context.Context, projectID, investmentID uuid.UUID) (*entities.InvestmentPosition, error)
+
+	CreateWithdrawal(ctx context.Context, withdrawal *entities.InvestmentWithdrawal) error
+	GetWithdrawal(ctx context.Context, projectID, withdrawalID uuid.UUID) (*entities.InvestmentWithdrawal, error)
+	// GetOpenWithdrawal returns the investment's requested or approved
+	// withdrawal, or nil if it has none
+	GetOpenWithdrawal(ctx context.Context, projectID, investmentID uuid.UUID) (*entities.InvestmentWithdrawal, error)
+	ListInvestorWithdrawals(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestmentWithdrawal, error)
+	ListCooperativeWithdrawals(ctx context.Context, cooperativeID uuid.UUID, status string) ([]*entities.InvestmentWithdrawal, error)
+
+	// UpdateWithdrawalStatus saves a withdrawal's review if it is still in
+	// fromStatus, reporting whether it was
+	UpdateWithdrawalStatus(ctx context.Context, withdrawal *entities.InvestmentWithdrawal, fromStatus string) (bool, error)
+	// ExitWithdrawal moves an approved withdrawal to completing and, in the same
+	// transaction, records its refund, takes the withdrawn capital out of the
+	// investment and the project's funding and recalculates the ownership of the
+	// project's investments. It fails without changes unless the investment is
+	// still confirmed and holds the withdrawn amount. OwnershipBefore and
+	// OwnershipAfter are filled in.
+	ExitWithdrawal(ctx context.Context, withdrawal *entities.InvestmentWithdrawal, refund *entities.FundRefund, investorRefunds []*entities.InvestorRefund) error
+	// FinishWithdrawal marks a completing withdrawal completed once its refund
+	// has been posted and queued for payout
+	FinishWithdrawal(ctx context.Context, withdrawal *entities.InvestmentWithdrawal) error
+	// ListCompletingWithdrawals returns the withdrawals that left their project
+	// but whose refund has not been settled
+	ListCompletingWithdrawals(ctx context.Context) ([]*entities.InvestmentWithdrawal, error)
+	// GetWithdrawalRefund returns the refund recorded when a withdrawal exited
+	// its project
+	GetWithdrawalRefund(ctx context.Context, withdrawal *entities.InvestmentWithdrawal) (*entities.FundRefund, []*entities.InvestorRefund, error)
+}
+
+type investmentWithdrawalRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewInvestmentWithdrawalRepository(shardMgr *database.ShardManager) InvestmentWithdrawalRepository {
+	return &investmentWithdrawalRepository{shardMgr: shardMgr}
+}
+
+const investmentWithdrawalColumns = `id, investment_id, investor_id, project_id, cooperative_id, currency,
+	invested_amount, withdrawn_amount, unrecovered_loss, redemption_value, penalty, penalty_amount, net_amount,
+	notice_days, requested_at, exit_date, reason, bank_account, status, reviewed_by, reviewed_at, review_notes,
+	fund_refund_id, ownership_before, ownership_after, completed_by, completed_at, created_at, updated_at`
+
+func scanInvestmentWithdrawal(row interface{ Scan(...interface{}) error }) (*entities.InvestmentWithdrawal, error) {
+	w := &entities.InvestmentWithdrawal{}
+	var reviewNotes sql.NullString
+	err := row.Scan(
+		&w.ID, &w.InvestmentID, &w.InvestorID, &w.ProjectID, &w.CooperativeID, &w.Currency,
+		&w.InvestedAmount, &w.WithdrawnAmount, &w.UnrecoveredLoss, &w.RedemptionValue, &w.Penalty, &w.PenaltyAmount,
+		&w.NetAmount, &w.NoticeDays, &w.RequestedAt, &w.ExitDate, &w.Reason, &w.BankAccount, &w.Status,
+		&w.ReviewedBy, &w.ReviewedAt, &reviewNotes, &w.FundRefundID, &w.OwnershipBefore, &w.OwnershipAfter,
+		&w.CompletedBy, &w.CompletedAt, &w.CreatedAt, &w.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	w.ReviewNotes = reviewNotes.String
+	w.InvestedAmount = w.InvestedAmount.WithCurrency(w.Currency)
+	w.WithdrawnAmount = w.WithdrawnAmount.WithCurrency(w.Currency)
+	w.UnrecoveredLoss = w.UnrecoveredLoss.WithCurrency(w.Currency)
+	w.RedemptionValue = w.RedemptionValue.WithCurrency(w.Currency)
+	w.PenaltyAmount = w.PenaltyAmount.WithCurrency(w.Currency)
+	w.NetAmount = w.NetAmount.WithCurrency(w.Currency)
+	return w, nil
+}
+
+func (r *investmentWithdrawalRepository) GetInvestmentPosition(ctx context.Context, projectID, investmentID uuid.UUID) (*entities.InvestmentPosition, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	position, err := queryInvestmentPosition(ctx, shard, "i.id = $1 AND i.project_id = $2", investmentID, projectID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("investment not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get investment: %w", err)
+	}
+
+	return position, nil
+}
+
+// rowQuerier is a shard or a transaction on one
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// queryInvestmentPosition reads the investment matching where, which refers to
+// the investments table as i
+func queryInvestmentPosition(ctx context.Context, q rowQuerier, where string, args ...interface{}) (*entities.InvestmentPosition, error) {
+	position := &entities.InvestmentPosition{}
+	err := q.QueryRowContext(ctx, `
+		SELECT i.id, i.investor_id, i.project_id, b.cooperative_id, i.amount, COALESCE(c.currency, ''),
+			p.contract_type, i.status, i.profit_sharing_percentage, p.current_funding
+		FROM investments i
+		JOIN projects p ON p.id = i.project_id
+		JOIN businesses b ON b.id = p.business_id
+		LEFT JOIN project_contracts c ON c.project_id = p.id
+		WHERE `+where, args...).Scan(
+		&position.InvestmentID, &position.InvestorID, &position.ProjectID, &position.CooperativeID, &position.Amount,
+		&position.Currency, &position.ContractType, &position.Status, &position.OwnershipPercentage,
+		&position.ProjectFunding,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	position.Amount = position.Amount.WithCurrency(position.Currency)
+	position.ProjectFunding = position.ProjectFunding.WithCurrency(position.Currency)
+	return position, nil
+}
+
+func (r *investmentWithdrawalRepository) CreateWithdrawal(ctx context.Context, w *entities.InvestmentWithdrawal) error {
+	shard, _, err := r.shardMgr.GetShardByID(w.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	_, err = shard.ExecContext(ctx, `
+		INSERT INTO investment_withdrawals (`+investmentWithdrawalColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+			$21, $22, $23, $24, $25, $26, $27, $28, $29)
+	`,
+		w.ID, w.InvestmentID, w.InvestorID, w.ProjectID, w.CooperativeID, w.Currency,
+		w.InvestedAmount, w.WithdrawnAmount, w.UnrecoveredLoss, w.RedemptionValue, w.Penalty, w.PenaltyAmount,
+		w.NetAmount, w.NoticeDays, w.RequestedAt, w.ExitDate, w.Reason, w.BankAccount, w.Status,
+		w.ReviewedBy, w.ReviewedAt, nullString(w.ReviewNotes), w.FundRefundID, w.OwnershipBefore, w.OwnershipAfter,
+		w.CompletedBy, w.CompletedAt, w.CreatedAt, w.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create investment withdrawal: %w", err)
+	}
+
+	return nil
+}
+
+func (r *investmentWithdrawalRepository) GetWithdrawal(ctx context.Context, projectID, withdrawalID uuid.UUID) (*entities.InvestmentWithdrawal, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	withdrawal, err := scanInvestmentWithdrawal(shard.QueryRowContext(ctx, `
+		SELECT `+investmentWithdrawalColumns+`
+		FROM investment_withdrawals
+		WHERE id = $1 AND project_id = $2
+	`, withdrawalID, projectID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("investment withdrawal not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get investment withdrawal: %w", err)
+	}
+
+	return withdrawal, nil
+}
+
+func (r *investmentWithdrawalRepository) GetOpenWithdrawal(ctx context.Context, projectID, investmentID uuid.UUID) (*entities.InvestmentWithdrawal, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	withdrawal, err := scanInvestmentWithdrawal(shard.QueryRowContext(ctx, `
+		SELECT `+investmentWithdrawalColumns+`
+		FROM investment_withdrawals
+		WHERE investment_id = $1 AND status IN ($2, $3, $4)
+	`, investmentID, entities.InvestmentWithdrawalStatusRequested, entities.InvestmentWithdrawalStatusApproved,
+		entities.InvestmentWithdrawalStatusCompleting))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open investment withdrawal: %w", err)
+	}
+
+	return withdrawal, nil
+}
+
+func (r *investmentWithdrawalRepository) ListInvestorWithdrawals(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestmentWithdrawal, error) {
+	return r.listAcrossShards(ctx, `
+		SELECT `+investmentWithdrawalColumns+`
+		FROM investment_withdrawals
+		WHERE investor_id = $1
+		ORDER BY requested_at DESC
+	`, investorID)
+}
+
+func (r *investmentWithdrawalRepository) ListCooperativeWithdrawals(ctx context.Context, cooperativeID uuid.UUID, status string) ([]*entities.InvestmentWithdrawal, error) {
+	return r.listAcrossShards(ctx, `
+		SELECT `+investmentWithdrawalColumns+`
+		FROM investment_withdrawals
+		WHERE cooperative_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY exit_date, requested_at
+	`, cooperativeID, status)
+}
+
+func (r *investmentWithdrawalRepository) ListCompletingWithdrawals(ctx context.Context) ([]*entities.InvestmentWithdrawal, error) {
+	return r.listAcrossShards(ctx, `
+		SELECT `+investmentWithdrawalColumns+`
+		FROM investment_withdrawals
+		WHERE status = $1
+		ORDER BY updated_at
+	`, entities.InvestmentWithdrawalStatusCompleting)
+}
+
+func (r *investmentWithdrawalRepository) listAcrossShards(ctx context.Context, query string, args ...interface{}) ([]*entities.InvestmentWithdrawal, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	var withdrawals []*entities.InvestmentWithdrawal
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		shardWithdrawals, err := r.queryWithdrawals(ctx, shard, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		withdrawals = append(withdrawals, shardWithdrawals...)
+	}
+
+	return withdrawals, nil
+}
+
+func (r *investmentWithdrawalRepository) queryWithdrawals(ctx context.Context, shard *sql.DB, query string, args ...interface{}) ([]*entities.InvestmentWithdrawal, error) {
+	rows, err := shard.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list investment withdrawals: %w", err)
+	}
+	defer rows.Close()
+
+	var withdrawals []*entities.InvestmentWithdrawal
+	for rows.Next() {
+		withdrawal, err := scanInvestmentWithdrawal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan investment withdrawal: %w", err)
+		}
+		withdrawals = append(withdrawals, withdrawal)
+	}
+
+	return withdrawals, rows.Err()
+}
+
+func (r *investmentWithdrawalRepository) UpdateWithdrawalStatus(ctx context.Context, w *entities.InvestmentWithdrawal, fromStatus string) (bool, error) {
+	shard, _, err := r.shardMgr.GetShardByID(w.ProjectID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE investment_withdrawals SET
+			status = $3, reviewed_by = $4, reviewed_at = $5, review_notes = $6, updated_at = $7
+		WHERE id = $1 AND status = $2
+	`, w.ID, fromStatus, w.Status, w.ReviewedBy, w.ReviewedAt, nullString(w.ReviewNotes), w.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to update investment withdrawal: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+func (r *investmentWithdrawalRepository) ExitWithdrawal(ctx context.Context, w *entities.InvestmentWithdrawal, refund *entities.FundRefund, investorRefunds []*entities.InvestorRefund) error {
+	_, shardIndex, err := r.shardMgr.GetShardByID(w.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the project first so concurrent exits and investments see the same funding
+	var funding entities.Money
+	err = tx.QueryRowContext(ctx, `SELECT current_funding FROM projects WHERE id = $1 FOR UPDATE`, w.ProjectID).Scan(&funding)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("project not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock project: %w", err)
+	}
+	funding = funding.WithCurrency(w.Currency)
+
+	var amount entities.Money
+	var status string
+	err = tx.QueryRowContext(ctx, `
+		SELECT amount, status, profit_sharing_percentage FROM investments WHERE id = $1 FOR UPDATE
+	`, w.InvestmentID).Scan(&amount, &status, &w.OwnershipBefore)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("investment not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock investment: %w", err)
+	}
+	amount = amount.WithCurrency(w.Currency)
+	if status != "confirmed" {
+		return fmt.Errorf("investment is %s and cannot be withdrawn from", status)
+	}
+	if amount.LessThan(w.WithdrawnAmount) || funding.LessThan(w.WithdrawnAmount) {
+		return fmt.Errorf("investment has only %s left to withdraw", amount.Min(funding))
+	}
+
+	if err := insertFundRefund(ctx, tx, refund, investorRefunds); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE investment_withdrawals SET
+			status = $3, fund_refund_id = $4, completed_by = $5, completed_at = $6, unrecovered_loss = $7,
+			redemption_value = $8, penalty_amount = $9, net_amount = $10, updated_at = $11
+		WHERE id = $1 AND status = $2
+	`,
+		w.ID, entities.InvestmentWithdrawalStatusApproved, entities.InvestmentWithdrawalStatusCompleting,
+		w.FundRefundID, w.CompletedBy, w.CompletedAt, w.UnrecoveredLoss, w.RedemptionValue, w.PenaltyAmount,
+		w.NetAmount, w.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to complete investment withdrawal: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	} else if affected != 1 {
+		return fmt.Errorf("withdrawal was updated concurrently")
+	}
+
+	if amount.Equal(w.WithdrawnAmount) {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE investments SET status = 'refunded', profit_sharing_percentage = 0, updated_at = $2 WHERE id = $1
+		`, w.InvestmentID, w.UpdatedAt)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE investments SET amount = amount - $2, updated_at = $3 WHERE id = $1
+		`, w.InvestmentID, w.WithdrawnAmount, w.UpdatedAt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reduce investment: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE projects SET current_funding = current_funding - $2 WHERE id = $1
+	`, w.ProjectID, w.WithdrawnAmount)
+	if err != nil {
+		return fmt.Errorf("failed to reduce project funding: %w", err)
+	}
+
+	// Every remaining investor now owns a larger part of the smaller project
+	_, err = tx.ExecContext(ctx, `
+		UPDATE investments i SET
+			profit_sharing_percentage = ROUND(i.amount * 100 / p.current_funding, 2), updated_at = $2
+		FROM projects p
+		WHERE p.id = i.project_id AND i.project_id = $1 AND i.status = 'confirmed' AND p.current_funding > 0
+	`, w.ProjectID, w.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to recalculate ownership: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT profit_sharing_percentage FROM investments WHERE id = $1
+	`, w.InvestmentID).Scan(&w.OwnershipAfter)
+	if err != nil {
+		return fmt.Errorf("failed to get remaining ownership: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE investment_withdrawals SET ownership_before = $2, ownership_after = $3 WHERE id = $1
+	`, w.ID, w.OwnershipBefore, w.OwnershipAfter)
+	if err != nil {
+		return fmt.Errorf("failed to record ownership change: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit investment withdrawal: %w", err)
+	}
+
+	return nil
+}
+
+func (r *investmentWithdrawalRepository) FinishWithdrawal(ctx context.Context, w *entities.InvestmentWithdrawal) error {
+	shard, _, err := r.shardMgr.GetShardByID(w.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE investment_withdrawals SET status = $3, updated_at = $4 WHERE id = $1 AND status = $2
+	`, w.ID, entities.InvestmentWithdrawalStatusCompleting, entities.InvestmentWithdrawalStatusCompleted, w.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to finish investment withdrawal: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	} else if affected != 1 {
+		return fmt.Errorf("investment withdrawal is no longer being completed")
+	}
+
+	return nil
+}
+
+func (r *investmentWithdrawalRepository) GetWithdrawalRefund(ctx context.Context, w *entities.InvestmentWithdrawal) (*entities.FundRefund, []*entities.InvestorRefund, error) {
+	if w.FundRefundID == nil {
+		return nil, nil, fmt.Errorf("investment withdrawal has no refund")
+	}
+
+	shard, _, err := r.shardMgr.GetShardByID(w.ProjectID.String())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	return getFundRefund(ctx, shard, *w.FundRefundID)
+}
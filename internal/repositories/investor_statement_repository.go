@@ -0,0 +1,338 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// InvestorStatementRepository reads investors' ledger accounts for statements
+// and archives the statements on the cooperative's shard
+type InvestorStatementRepository interface {
+	// ListInvestorAccounts returns every investor ledger account opened before the given time
+	ListInvestorAccounts(ctx context.Context, openedBefore time.Time) ([]*entities.LedgerAccount, error)
+	GetStatementBranding(ctx context.Context, cooperativeID uuid.UUID) (*entities.StatementBranding, error)
+	// GetAccountPosition returns the investor's position (credits less debits) before the given time
+	GetAccountPosition(ctx context.Context, account *entities.LedgerAccount, before time.Time) (entities.Money, error)
+	GetStatementLines(ctx context.Context, account *entities.LedgerAccount, start, end time.Time) ([]*entities.StatementLedgerLine, error)
+
+	StatementExists(ctx context.Context, account *entities.LedgerAccount, periodType string, periodStart time.Time) (bool, error)
+	// CreateStatement archives a statement; it returns false if the period was already archived
+	CreateStatement(ctx context.Context, statement *entities.InvestorStatement) (bool, error)
+	// ListInvestorStatements returns an investor's archived statements without their lines or files
+	ListInvestorStatements(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestorStatement, error)
+	GetStatement(ctx context.Context, statementID uuid.UUID) (*entities.InvestorStatement, error)
+}
+
+type investorStatementRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewInvestorStatementRepository(shardMgr *database.ShardManager) InvestorStatementRepository {
+	return &investorStatementRepository{shardMgr: shardMgr}
+}
+
+const investorStatementColumns = `id, investor_id, cooperative_id, cooperative_name, currency, period_type, period_start,
+	period_end, opening_balance, investments, distributions, tax_withheld, fees, refunds, transfers, adjustments,
+	closing_balance, generated_at`
+
+func scanInvestorStatement(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*entities.InvestorStatement, error) {
+	s := &entities.InvestorStatement{}
+	dest := []interface{}{
+		&s.ID, &s.InvestorID, &s.CooperativeID, &s.CooperativeName, &s.Currency, &s.PeriodType, &s.PeriodStart,
+		&s.PeriodEnd, &s.OpeningBalance, &s.Investments, &s.Distributions, &s.TaxWithheld, &s.Fees, &s.Refunds,
+		&s.Transfers, &s.Adjustments, &s.ClosingBalance, &s.GeneratedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	for _, amount := range []*entities.Money{
+		&s.OpeningBalance, &s.Investments, &s.Distributions, &s.TaxWithheld, &s.Fees, &s.Refunds,
+		&s.Transfers, &s.Adjustments, &s.ClosingBalance,
+	} {
+		*amount = amount.WithCurrency(s.Currency)
+	}
+	return s, nil
+}
+
+func (r *investorStatementRepository) ListInvestorAccounts(ctx context.Context, openedBefore time.Time) ([]*entities.LedgerAccount, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	var accounts []*entities.LedgerAccount
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		rows, err := shard.QueryContext(ctx, `
+			SELECT `+ledgerAccountColumns+`
+			FROM ledger_accounts
+			WHERE category = $1 AND owner_id IS NOT NULL AND created_at < $2
+			ORDER BY cooperative_id, owner_id, currency
+		`, entities.LedgerAccountCategoryInvestor, openedBefore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list investor accounts: %w", err)
+		}
+
+		for rows.Next() {
+			account, err := scanLedgerAccount(rows)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan investor account: %w", err)
+			}
+			accounts = append(accounts, account)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to list investor accounts: %w", err)
+		}
+	}
+
+	return accounts, nil
+}
+
+func (r *investorStatementRepository) GetStatementBranding(ctx context.Context, cooperativeID uuid.UUID) (*entities.StatementBranding, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	branding := &entities.StatementBranding{}
+	err = shard.QueryRowContext(ctx, `
+		SELECT name, COALESCE(cooperative_image, '') FROM cooperatives WHERE id = $1
+	`, cooperativeID).Scan(&branding.CooperativeName, &branding.LogoURL)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("cooperative not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cooperative: %w", err)
+	}
+
+	return branding, nil
+}
+
+func (r *investorStatementRepository) GetAccountPosition(ctx context.Context, account *entities.LedgerAccount, before time.Time) (entities.Money, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(account.CooperativeID.String())
+	if err != nil {
+		return entities.Money{}, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	var position entities.Money
+	err = shard.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(l.credit - l.debit), 0)
+		FROM journal_lines l
+		JOIN journal_entries e ON e.id = l.entry_id
+		WHERE l.account_id = $1 AND e.posted_at < $2
+	`, account.ID, before).Scan(&position)
+	if err != nil {
+		return entities.Money{}, fmt.Errorf("failed to get account position: %w", err)
+	}
+
+	return position.WithCurrency(account.Currency), nil
+}
+
+func (r *investorStatementRepository) GetStatementLines(ctx context.Context, account *entities.LedgerAccount, start, end time.Time) ([]*entities.StatementLedgerLine, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(account.CooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	// Tax and fees are credited to shared accounts in the same entry as the
+	// investor postings, so each entry's totals come along to apportion them
+	rows, err := shard.QueryContext(ctx, `
+		SELECT e.posted_at, e.entry_number, e.entry_type, e.description, e.project_id, l.debit, l.credit,
+		       COALESCE(SUM(o.credit) FILTER (WHERE a.category = $4), 0),
+		       COALESCE(SUM(o.credit) FILTER (WHERE a.category = $5), 0),
+		       COALESCE(SUM(o.debit + o.credit) FILTER (WHERE a.category = $6), 0)
+		FROM journal_lines l
+		JOIN journal_entries e ON e.id = l.entry_id
+		JOIN journal_lines o ON o.entry_id = e.id
+		JOIN ledger_accounts a ON a.id = o.account_id
+		WHERE l.account_id = $1 AND e.posted_at >= $2 AND e.posted_at < $3
+		GROUP BY l.id, e.id
+		ORDER BY e.posted_at, e.entry_number
+	`, account.ID, start, end, entities.LedgerAccountCategoryTaxPayable, entities.LedgerAccountCategoryPlatformFee,
+		entities.LedgerAccountCategoryInvestor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statement lines: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []*entities.StatementLedgerLine
+	for rows.Next() {
+		line := &entities.StatementLedgerLine{}
+		if err := rows.Scan(
+			&line.PostedAt, &line.EntryNumber, &line.EntryType, &line.Description, &line.ProjectID, &line.Debit,
+			&line.Credit, &line.EntryTax, &line.EntryFees, &line.EntryInvestor,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan statement line: %w", err)
+		}
+		line.Debit = line.Debit.WithCurrency(account.Currency)
+		line.Credit = line.Credit.WithCurrency(account.Currency)
+		line.EntryTax = line.EntryTax.WithCurrency(account.Currency)
+		line.EntryFees = line.EntryFees.WithCurrency(account.Currency)
+		line.EntryInvestor = line.EntryInvestor.WithCurrency(account.Currency)
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+func (r *investorStatementRepository) StatementExists(ctx context.Context, account *entities.LedgerAccount, periodType string, periodStart time.Time) (bool, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(account.CooperativeID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	var exists bool
+	err = shard.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM investor_statements
+			WHERE investor_id = $1 AND cooperative_id = $2 AND currency = $3 AND period_type = $4 AND period_start = $5
+		)
+	`, account.OwnerID, account.CooperativeID, account.Currency, periodType, periodStart).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check investor statement: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *investorStatementRepository) CreateStatement(ctx context.Context, statement *entities.InvestorStatement) (bool, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(statement.CooperativeID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	linesJSON, err := json.Marshal(statement.Lines)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal statement lines: %w", err)
+	}
+	if statement.Lines == nil {
+		linesJSON = []byte("[]")
+	}
+
+	var pdfContent, csvContent []byte
+	for _, file := range statement.Files {
+		switch file.Format {
+		case entities.StatementFormatPDF:
+			pdfContent = file.Content
+		case entities.StatementFormatCSV:
+			csvContent = file.Content
+		}
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		INSERT INTO investor_statements (`+investorStatementColumns+`, lines, pdf_content, csv_content)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+		ON CONFLICT (investor_id, cooperative_id, currency, period_type, period_start) DO NOTHING
+	`,
+		statement.ID, statement.InvestorID, statement.CooperativeID, statement.CooperativeName, statement.Currency,
+		statement.PeriodType, statement.PeriodStart, statement.PeriodEnd, statement.OpeningBalance,
+		statement.Investments, statement.Distributions, statement.TaxWithheld, statement.Fees, statement.Refunds,
+		statement.Transfers, statement.Adjustments, statement.ClosingBalance, statement.GeneratedAt, linesJSON,
+		pdfContent, csvContent,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create investor statement: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+func (r *investorStatementRepository) ListInvestorStatements(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestorStatement, error) {
+	// An investor may hold accounts with cooperatives on any shard
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	var statements []*entities.InvestorStatement
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		rows, err := shard.QueryContext(ctx, `
+			SELECT `+investorStatementColumns+`
+			FROM investor_statements
+			WHERE investor_id = $1
+		`, investorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list investor statements: %w", err)
+		}
+
+		for rows.Next() {
+			statement, err := scanInvestorStatement(rows)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan investor statement: %w", err)
+			}
+			statements = append(statements, statement)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to list investor statements: %w", err)
+		}
+	}
+
+	sort.SliceStable(statements, func(i, j int) bool {
+		return statements[i].PeriodEnd.After(statements[j].PeriodEnd)
+	})
+	return statements, nil
+}
+
+func (r *investorStatementRepository) GetStatement(ctx context.Context, statementID uuid.UUID) (*entities.InvestorStatement, error) {
+	// Statements are looked up without their cooperative, so search every shard
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		var linesJSON, pdfContent, csvContent []byte
+		statement, err := scanInvestorStatement(shard.QueryRowContext(ctx, `
+			SELECT `+investorStatementColumns+`, lines, pdf_content, csv_content
+			FROM investor_statements
+			WHERE id = $1
+		`, statementID), &linesJSON, &pdfContent, &csvContent)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get investor statement: %w", err)
+		}
+
+		if err := json.Unmarshal(linesJSON, &statement.Lines); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal statement lines: %w", err)
+		}
+		statement.Files = []*entities.InvestorStatementFile{
+			{Format: entities.StatementFormatPDF, Content: pdfContent},
+			{Format: entities.StatementFormatCSV, Content: csvContent},
+		}
+		return statement, nil
+	}
+
+	return nil, fmt.Errorf("investor statement not found")
+}
@@ -0,0 +1,562 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// LedgerRepository stores ledger accounts and journal entries. All rows of a
+// cooperative's ledger live on the cooperative's shard so that an entry and its
+// lines can be written in a single local transaction.
+type LedgerRepository interface {
+	CreateAccount(ctx context.Context, account *entities.LedgerAccount) error
+	GetAccountByID(ctx context.Context, id uuid.UUID) (*entities.LedgerAccount, error)
+	GetAccountByCode(ctx context.Context, cooperativeID uuid.UUID, code, currency string) (*entities.LedgerAccount, error)
+	ListAccounts(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.LedgerAccount, error)
+	PostEntry(ctx context.Context, entry *entities.JournalEntry) error
+	PostFundedEntry(ctx context.Context, entry *entities.JournalEntry, fundingAccountID uuid.UUID) error
+	GetEntryByID(ctx context.Context, id uuid.UUID) (*entities.JournalEntry, error)
+	GetEntriesByAccount(ctx context.Context, account *entities.LedgerAccount, limit, offset int) ([]*entities.JournalEntry, int, error)
+	GetAccountTotals(ctx context.Context, account *entities.LedgerAccount, asOf *time.Time) (entities.Money, entities.Money, error) // debits, credits
+	GetProjectCategoryTotals(ctx context.Context, projectID uuid.UUID, category string) ([]*entities.LedgerCurrencyTotals, error)
+}
+
+var (
+	// ErrLedgerAccountNotFound is returned when a cooperative has no ledger
+	// account with the requested code and currency
+	ErrLedgerAccountNotFound = errors.New("ledger account not found")
+	// ErrLedgerAccountExists is returned when an account with the same code and
+	// currency was opened first, e.g. by a concurrent posting
+	ErrLedgerAccountExists = errors.New("ledger account already exists")
+	// ErrFundRefundPosted is returned when a fund refund, which is paid out of
+	// escrow by a single entry, has already been posted
+	ErrFundRefundPosted = errors.New("fund refund already posted")
+	// ErrStakeTransferPosted is returned when a stake trade, which is recorded
+	// by a single entry, has already been posted
+	ErrStakeTransferPosted = errors.New("stake transfer already posted")
+)
+
+type ledgerRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewLedgerRepository(shardMgr *database.ShardManager) LedgerRepository {
+	return &ledgerRepository{shardMgr: shardMgr}
+}
+
+const ledgerAccountColumns = `id, cooperative_id, code, name, account_type, category, owner_id, currency, is_active, created_at, updated_at`
+
+func scanLedgerAccount(row interface{ Scan(...interface{}) error }) (*entities.LedgerAccount, error) {
+	account := &entities.LedgerAccount{}
+	err := row.Scan(
+		&account.ID, &account.CooperativeID, &account.Code, &account.Name, &account.AccountType,
+		&account.Category, &account.OwnerID, &account.Currency, &account.IsActive,
+		&account.CreatedAt, &account.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func (r *ledgerRepository) CreateAccount(ctx context.Context, account *entities.LedgerAccount) error {
+	if account.ID == uuid.Nil {
+		account.ID = uuid.New()
+	}
+
+	// Ledger rows are sharded by cooperative
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(account.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	now := time.Now()
+	account.CreatedAt = now
+	account.UpdatedAt = now
+
+	query := `
+		INSERT INTO ledger_accounts (` + ledgerAccountColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT ON CONSTRAINT unique_ledger_account_code DO NOTHING
+	`
+
+	result, err := shard.ExecContext(ctx, query,
+		account.ID, account.CooperativeID, account.Code, account.Name, account.AccountType,
+		account.Category, account.OwnerID, account.Currency, account.IsActive,
+		account.CreatedAt, account.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create ledger account: %w", err)
+	}
+
+	created, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to create ledger account: %w", err)
+	}
+	if created == 0 {
+		return ErrLedgerAccountExists
+	}
+
+	return nil
+}
+
+func (r *ledgerRepository) GetAccountByID(ctx context.Context, id uuid.UUID) (*entities.LedgerAccount, error) {
+	// Query all shards to find the account
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	query := `SELECT ` + ledgerAccountColumns + ` FROM ledger_accounts WHERE id = $1`
+
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		account, err := scanLedgerAccount(shard.QueryRowContext(ctx, query, id))
+		if err == nil {
+			return account, nil
+		}
+	}
+
+	return nil, fmt.Errorf("ledger account not found")
+}
+
+func (r *ledgerRepository) GetAccountByCode(ctx context.Context, cooperativeID uuid.UUID, code, currency string) (*entities.LedgerAccount, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `
+		SELECT ` + ledgerAccountColumns + `
+		FROM ledger_accounts
+		WHERE cooperative_id = $1 AND code = $2 AND currency = $3
+	`
+
+	account, err := scanLedgerAccount(shard.QueryRowContext(ctx, query, cooperativeID, code, currency))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrLedgerAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get ledger account: %w", err)
+	}
+
+	return account, nil
+}
+
+func (r *ledgerRepository) ListAccounts(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.LedgerAccount, error) {
+	_, shardIndex, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `
+		SELECT ` + ledgerAccountColumns + `
+		FROM ledger_accounts
+		WHERE cooperative_id = $1
+		ORDER BY code
+	`
+
+	rows, err := r.shardMgr.ExecuteOnShard(ctx, shardIndex, query, cooperativeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledger accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*entities.LedgerAccount
+	for rows.Next() {
+		account, err := scanLedgerAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ledger account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+func (r *ledgerRepository) PostEntry(ctx context.Context, entry *entities.JournalEntry) error {
+	return r.postEntry(ctx, entry, nil)
+}
+
+// PostFundedEntry posts an entry that draws on the entry project's funds in an
+// account. The account row is locked for the check, so concurrent entries
+// drawing on it cannot both spend the same balance.
+func (r *ledgerRepository) PostFundedEntry(ctx context.Context, entry *entities.JournalEntry, fundingAccountID uuid.UUID) error {
+	return r.postEntry(ctx, entry, &fundingAccountID)
+}
+
+func (r *ledgerRepository) postEntry(ctx context.Context, entry *entities.JournalEntry, fundingAccountID *uuid.UUID) error {
+	_, shardIndex, err := r.shardMgr.GetShardByCooperativeID(entry.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	// The entry and all of its lines are written atomically; the database
+	// additionally rejects unbalanced entries at commit time.
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertJournalEntry(ctx, tx, entry, fundingAccountID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit journal entry: %w", err)
+	}
+
+	return nil
+}
+
+// insertJournalEntry writes a journal entry and its lines within tx, which must
+// be on the cooperative's shard. Other repositories use it to post an entry in
+// the same transaction as the records it accounts for.
+func insertJournalEntry(ctx context.Context, tx *sql.Tx, entry *entities.JournalEntry, fundingAccountID *uuid.UUID) error {
+	// A retried refund is reported before its escrow balance is checked again,
+	// and a retried stake transfer before it is posted twice; unique indexes on
+	// these entries back this against concurrent posts
+	var repeated error
+	switch {
+	case entry.EntryType == entities.JournalEntryTypeRefund && entry.ReferenceType == "fund_refund":
+		repeated = ErrFundRefundPosted
+	case entry.EntryType == entities.JournalEntryTypeStakeTransfer && entry.ReferenceType == "stake_trade":
+		repeated = ErrStakeTransferPosted
+	}
+	if repeated != nil {
+		var posted bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM journal_entries WHERE reference_id = $1 AND reference_type = $2 AND entry_type = $3)
+		`, entry.ReferenceID, entry.ReferenceType, entry.EntryType).Scan(&posted)
+		if err != nil {
+			return fmt.Errorf("failed to check %s entry: %w", entry.ReferenceType, err)
+		}
+		if posted {
+			return repeated
+		}
+	}
+
+	// Lock the funding account before the line accounts are checked so that the
+	// row lock is taken in one step rather than upgraded
+	if fundingAccountID != nil {
+		if err := checkProjectFunds(ctx, tx, entry, *fundingAccountID); err != nil {
+			return err
+		}
+	}
+
+	// Lines may only post to the entry's own ledger in the entry's currency;
+	// the accounts are locked against changes until the entry commits
+	checked := make(map[uuid.UUID]bool, len(entry.Lines))
+	for _, line := range entry.Lines {
+		if checked[line.AccountID] {
+			continue
+		}
+		if err := checkJournalAccount(ctx, tx, entry, line.AccountID); err != nil {
+			return err
+		}
+		checked[line.AccountID] = true
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO journal_entries (id, entry_number, cooperative_id, project_id, entry_type, reference_type,
+		                             reference_id, description, currency, total_amount, posted_by, posted_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`,
+		entry.ID, entry.EntryNumber, entry.CooperativeID, entry.ProjectID, entry.EntryType, entry.ReferenceType,
+		entry.ReferenceID, entry.Description, entry.Currency, entry.TotalAmount, entry.PostedBy, entry.PostedAt, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert journal entry: %w", err)
+	}
+
+	for i := range entry.Lines {
+		line := &entry.Lines[i]
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO journal_lines (id, entry_id, account_id, debit, credit, memo, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, line.ID, line.EntryID, line.AccountID, line.Debit, line.Credit, line.Memo, line.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert journal line: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkJournalAccount checks an account a journal line posts to exists, belongs
+// to the entry's cooperative and is held in the entry's currency
+func checkJournalAccount(ctx context.Context, tx *sql.Tx, entry *entities.JournalEntry, accountID uuid.UUID) error {
+	var cooperativeID uuid.UUID
+	var currency string
+	var isActive bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT cooperative_id, currency, is_active FROM ledger_accounts WHERE id = $1 FOR SHARE
+	`, accountID).Scan(&cooperativeID, &currency, &isActive)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("ledger account %s not found", accountID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ledger account: %w", err)
+	}
+
+	switch {
+	case cooperativeID != entry.CooperativeID:
+		return fmt.Errorf("ledger account %s belongs to another cooperative", accountID)
+	case currency != entry.Currency:
+		return fmt.Errorf("%w: ledger account %s is held in %s, not %s", entities.ErrCurrencyMismatch, accountID, currency, entry.Currency)
+	case !isActive:
+		return fmt.Errorf("ledger account %s is inactive", accountID)
+	}
+	return nil
+}
+
+// checkProjectFunds locks an account and checks the entry project's balance in
+// it covers what the entry takes out of it
+func checkProjectFunds(ctx context.Context, tx *sql.Tx, entry *entities.JournalEntry, accountID uuid.UUID) error {
+	if entry.ProjectID == nil {
+		return fmt.Errorf("journal entry drawing on project funds has no project")
+	}
+
+	var lockedID uuid.UUID
+	err := tx.QueryRowContext(ctx, `SELECT id FROM ledger_accounts WHERE id = $1 FOR UPDATE`, accountID).Scan(&lockedID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("ledger account %s not found", accountID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock ledger account: %w", err)
+	}
+
+	var balance entities.Money
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(l.debit), 0) - COALESCE(SUM(l.credit), 0)
+		FROM journal_lines l
+		JOIN journal_entries e ON e.id = l.entry_id
+		WHERE l.account_id = $1 AND e.project_id = $2
+	`, accountID, *entry.ProjectID).Scan(&balance)
+	if err != nil {
+		return fmt.Errorf("failed to get project balance: %w", err)
+	}
+	balance = balance.WithCurrency(entry.Currency)
+
+	drawn := entities.ZeroMoney(entry.Currency)
+	for _, line := range entry.Lines {
+		if line.AccountID == accountID {
+			drawn = drawn.Add(line.Credit).Sub(line.Debit)
+		}
+	}
+
+	if balance.LessThan(drawn) {
+		return fmt.Errorf("insufficient project escrow balance: available %s, requested %s", balance.Decimal(), drawn.Decimal())
+	}
+	return nil
+}
+
+func (r *ledgerRepository) GetEntryByID(ctx context.Context, id uuid.UUID) (*entities.JournalEntry, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	query := `
+		SELECT id, entry_number, cooperative_id, project_id, entry_type, reference_type, reference_id,
+		       description, currency, total_amount, posted_by, posted_at, created_at
+		FROM journal_entries
+		WHERE id = $1
+	`
+
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		entry := &entities.JournalEntry{}
+		err = shard.QueryRowContext(ctx, query, id).Scan(
+			&entry.ID, &entry.EntryNumber, &entry.CooperativeID, &entry.ProjectID, &entry.EntryType,
+			&entry.ReferenceType, &entry.ReferenceID, &entry.Description, &entry.Currency,
+			&entry.TotalAmount, &entry.PostedBy, &entry.PostedAt, &entry.CreatedAt,
+		)
+		if err != nil {
+			continue
+		}
+		entry.TotalAmount = entry.TotalAmount.WithCurrency(entry.Currency)
+
+		entry.Lines, err = r.getLines(ctx, shard, entry.ID, entry.Currency)
+		if err != nil {
+			return nil, err
+		}
+		return entry, nil
+	}
+
+	return nil, fmt.Errorf("journal entry not found")
+}
+
+func (r *ledgerRepository) getLines(ctx context.Context, shard *sql.DB, entryID uuid.UUID, currency string) ([]entities.JournalLine, error) {
+	rows, err := shard.QueryContext(ctx, `
+		SELECT id, entry_id, account_id, debit, credit, COALESCE(memo, ''), created_at
+		FROM journal_lines
+		WHERE entry_id = $1
+		ORDER BY debit DESC, created_at
+	`, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get journal lines: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []entities.JournalLine
+	for rows.Next() {
+		line := entities.JournalLine{Debit: entities.ZeroMoney(currency), Credit: entities.ZeroMoney(currency)}
+		if err := rows.Scan(&line.ID, &line.EntryID, &line.AccountID, &line.Debit, &line.Credit, &line.Memo, &line.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan journal line: %w", err)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+func (r *ledgerRepository) GetEntriesByAccount(ctx context.Context, account *entities.LedgerAccount, limit, offset int) ([]*entities.JournalEntry, int, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(account.CooperativeID.String())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT DISTINCT e.id, e.entry_number, e.cooperative_id, e.project_id, e.entry_type, e.reference_type,
+		       e.reference_id, e.description, e.currency, e.total_amount, e.posted_by, e.posted_at, e.created_at
+		FROM journal_entries e
+		JOIN journal_lines l ON l.entry_id = e.id
+		WHERE l.account_id = $1
+		ORDER BY e.posted_at DESC
+		LIMIT $2 OFFSET $3
+	`, account.ID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get journal entries: %w", err)
+	}
+
+	var entries []*entities.JournalEntry
+	for rows.Next() {
+		entry := &entities.JournalEntry{}
+		err = rows.Scan(
+			&entry.ID, &entry.EntryNumber, &entry.CooperativeID, &entry.ProjectID, &entry.EntryType,
+			&entry.ReferenceType, &entry.ReferenceID, &entry.Description, &entry.Currency,
+			&entry.TotalAmount, &entry.PostedBy, &entry.PostedAt, &entry.CreatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, 0, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		entry.TotalAmount = entry.TotalAmount.WithCurrency(entry.Currency)
+		entries = append(entries, entry)
+	}
+	rows.Close()
+
+	for _, entry := range entries {
+		entry.Lines, err = r.getLines(ctx, shard, entry.ID, entry.Currency)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var total int
+	err = shard.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT entry_id) FROM journal_lines WHERE account_id = $1
+	`, account.ID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count journal entries: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+func (r *ledgerRepository) GetAccountTotals(ctx context.Context, account *entities.LedgerAccount, asOf *time.Time) (entities.Money, entities.Money, error) {
+	debits, credits := entities.ZeroMoney(account.Currency), entities.ZeroMoney(account.Currency)
+
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(account.CooperativeID.String())
+	if err != nil {
+		return debits, credits, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `
+		SELECT COALESCE(SUM(l.debit), 0), COALESCE(SUM(l.credit), 0)
+		FROM journal_lines l
+		JOIN journal_entries e ON e.id = l.entry_id
+		WHERE l.account_id = $1
+	`
+	args := []interface{}{account.ID}
+	if asOf != nil {
+		query += " AND e.posted_at <= $2"
+		args = append(args, *asOf)
+	}
+
+	if err := shard.QueryRowContext(ctx, query, args...).Scan(&debits, &credits); err != nil {
+		return debits, credits, fmt.Errorf("failed to get account totals: %w", err)
+	}
+
+	return debits, credits, nil
+}
+
+func (r *ledgerRepository) GetProjectCategoryTotals(ctx context.Context, projectID uuid.UUID, category string) ([]*entities.LedgerCurrencyTotals, error) {
+	// A project's cooperative is not known here, so sum across every shard
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	// Amounts in different currencies cannot be added, so totals are kept per currency
+	query := `
+		SELECT a.currency, COALESCE(SUM(l.debit), 0), COALESCE(SUM(l.credit), 0)
+		FROM journal_lines l
+		JOIN journal_entries e ON e.id = l.entry_id
+		JOIN ledger_accounts a ON a.id = l.account_id
+		WHERE e.project_id = $1 AND a.category = $2
+		GROUP BY a.currency
+		ORDER BY a.currency
+	`
+
+	byCurrency := make(map[string]*entities.LedgerCurrencyTotals)
+	var totals []*entities.LedgerCurrencyTotals
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		rows, err := shard.QueryContext(ctx, query, projectID, category)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project totals: %w", err)
+		}
+		for rows.Next() {
+			var currency string
+			var debits, credits entities.Money
+			if err := rows.Scan(&currency, &debits, &credits); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan project totals: %w", err)
+			}
+
+			total, ok := byCurrency[currency]
+			if !ok {
+				total = &entities.LedgerCurrencyTotals{
+					Currency:     currency,
+					TotalDebits:  entities.ZeroMoney(currency),
+					TotalCredits: entities.ZeroMoney(currency),
+				}
+				byCurrency[currency] = total
+				totals = append(totals, total)
+			}
+			total.TotalDebits = total.TotalDebits.Add(debits.WithCurrency(currency))
+			total.TotalCredits = total.TotalCredits.Add(credits.WithCurrency(currency))
+		}
+		rows.Close()
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// NotificationRepository stores user notifications on their recipient's shard
+type NotificationRepository interface {
+	CreateNotifications(ctx context.Context, notifications []*entities.Notification) error
+	ListUserNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]*entities.Notification, error)
+	// MarkRead marks the user's notification read, reporting whether it was unread
+	MarkRead(ctx context.Context, userID, notificationID uuid.UUID, readAt time.Time) (bool, error)
+}
+
+type notificationRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewNotificationRepository(shardMgr *database.ShardManager) NotificationRepository {
+	return &notificationRepository{shardMgr: shardMgr}
+}
+
+func (r *notificationRepository) CreateNotifications(ctx context.Context, notifications []*entities.Notification) error {
+	for _, n := range notifications {
+		shard, _, err := r.shardMgr.GetShardByID(n.UserID.String())
+		if err != nil {
+			return fmt.Errorf("failed to get shard: %w", err)
+		}
+
+		_, err = shard.ExecContext(ctx, `
+			INSERT INTO notifications (id, user_id, type, title, message, entity_type, entity_id, read_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, n.ID, n.UserID, n.Type, n.Title, n.Message, nullString(n.EntityType), n.EntityID, n.ReadAt, n.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create notification: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *notificationRepository) ListUserNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]*entities.Notification, error) {
+	shard, _, err := r.shardMgr.GetShardByID(userID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT id, user_id, type, title, message, entity_type, entity_id, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*entities.Notification
+	for rows.Next() {
+		n := &entities.Notification{}
+		var entityType sql.NullString
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.Title, &n.Message, &entityType, &n.EntityID, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		n.EntityType = entityType.String
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, notificationID uuid.UUID, readAt time.Time) (bool, error) {
+	shard, _, err := r.shardMgr.GetShardByID(userID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE notifications SET read_at = $3 WHERE id = $1 AND user_id = $2 AND read_at IS NULL
+	`, notificationID, userID, readAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
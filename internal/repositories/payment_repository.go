@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// PaymentRepository stores payment requests issued through payment providers.
+// Requests live on the cooperative's shard alongside its ledger.
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *entities.PaymentRequest) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.PaymentRequest, error)
+	GetByProviderReference(ctx context.Context, provider, providerReference string) (*entities.PaymentRequest, error)
+	// GetPendingByInvestment returns the investment's open payment request, or nil if there is none
+	GetPendingByInvestment(ctx context.Context, cooperativeID, investmentID uuid.UUID) (*entities.PaymentRequest, error)
+	// UpdateStatus saves the payment request only if its stored status is still fromStatus,
+	// reporting whether it did; this keeps concurrent callbacks from applying twice
+	UpdateStatus(ctx context.Context, payment *entities.PaymentRequest, fromStatus string) (bool, error)
+}
+
+type paymentRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewPaymentRepository(shardMgr *database.ShardManager) PaymentRepository {
+	return &paymentRepository{shardMgr: shardMgr}
+}
+
+const paymentRequestColumns = `id, investment_id, cooperative_id, investor_id, provider, method, status, amount, currency,
+	reference, provider_reference, virtual_account_number, bank_code, payment_url, expires_at, paid_amount, paid_at,
+	provider_transaction_id, failure_reason, created_at, updated_at`
+
+func scanPaymentRequest(row interface{ Scan(...interface{}) error }) (*entities.PaymentRequest, error) {
+	payment := &entities.PaymentRequest{}
+	var virtualAccountNumber, bankCode, paymentURL, providerTransactionID, failureReason sql.NullString
+	err := row.Scan(
+		&payment.ID, &payment.InvestmentID, &payment.CooperativeID, &payment.InvestorID, &payment.Provider,
+		&payment.Method, &payment.Status, &payment.Amount, &payment.Currency, &payment.Reference,
+		&payment.ProviderReference, &virtualAccountNumber, &bankCode, &paymentURL, &payment.ExpiresAt,
+		&payment.PaidAmount, &payment.PaidAt, &providerTransactionID, &failureReason, &payment.CreatedAt, &payment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	payment.Amount = payment.Amount.WithCurrency(payment.Currency)
+	if payment.PaidAmount != nil {
+		paid := payment.PaidAmount.WithCurrency(payment.Currency)
+		payment.PaidAmount = &paid
+	}
+	payment.VirtualAccountNumber = virtualAccountNumber.String
+	payment.BankCode = bankCode.String
+	payment.PaymentURL = paymentURL.String
+	payment.ProviderTransactionID = providerTransactionID.String
+	payment.FailureReason = failureReason.String
+	return payment, nil
+}
+
+func (r *paymentRepository) Create(ctx context.Context, payment *entities.PaymentRequest) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(payment.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `
+		INSERT INTO payment_requests (` + paymentRequestColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+	`
+
+	_, err = shard.ExecContext(ctx, query,
+		payment.ID, payment.InvestmentID, payment.CooperativeID, payment.InvestorID, payment.Provider, payment.Method,
+		payment.Status, payment.Amount, payment.Currency, payment.Reference, payment.ProviderReference,
+		nullString(payment.VirtualAccountNumber), nullString(payment.BankCode), nullString(payment.PaymentURL),
+		payment.ExpiresAt, payment.PaidAmount, payment.PaidAt, nullString(payment.ProviderTransactionID),
+		nullString(payment.FailureReason), payment.CreatedAt, payment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create payment request: %w", err)
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.PaymentRequest, error) {
+	return r.findOnAllShards(ctx, `SELECT `+paymentRequestColumns+` FROM payment_requests WHERE id = $1`, id)
+}
+
+func (r *paymentRepository) GetByProviderReference(ctx context.Context, provider, providerReference string) (*entities.PaymentRequest, error) {
+	// Callbacks carry only the provider's reference, so every shard is searched
+	return r.findOnAllShards(ctx,
+		`SELECT `+paymentRequestColumns+` FROM payment_requests WHERE provider = $1 AND provider_reference = $2`,
+		provider, providerReference)
+}
+
+func (r *paymentRepository) findOnAllShards(ctx context.Context, query string, args ...interface{}) (*entities.PaymentRequest, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		payment, err := scanPaymentRequest(shard.QueryRowContext(ctx, query, args...))
+		if err == nil {
+			return payment, nil
+		}
+	}
+
+	return nil, fmt.Errorf("payment request not found")
+}
+
+func (r *paymentRepository) GetPendingByInvestment(ctx context.Context, cooperativeID, investmentID uuid.UUID) (*entities.PaymentRequest, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `SELECT ` + paymentRequestColumns + ` FROM payment_requests WHERE investment_id = $1 AND status = $2`
+
+	payment, err := scanPaymentRequest(shard.QueryRowContext(ctx, query, investmentID, entities.PaymentRequestStatusPending))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment request: %w", err)
+	}
+
+	return payment, nil
+}
+
+func (r *paymentRepository) UpdateStatus(ctx context.Context, payment *entities.PaymentRequest, fromStatus string) (bool, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(payment.CooperativeID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	payment.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE payment_requests SET
+			status = $3, paid_amount = $4, paid_at = $5, provider_transaction_id = $6,
+			failure_reason = $7, updated_at = $8
+		WHERE id = $1 AND status = $2
+	`
+
+	result, err := shard.ExecContext(ctx, query,
+		payment.ID, fromStatus, payment.Status, payment.PaidAmount, payment.PaidAt,
+		nullString(payment.ProviderTransactionID), nullString(payment.FailureReason), payment.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to update payment request: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
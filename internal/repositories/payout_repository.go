@@ -0,0 +1,435 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PayoutRepository stores queued payouts and the batches they are sent to the bank
+// in. Payouts live on the cooperative's shard alongside its ledger.
+type PayoutRepository interface {
+	// CreateItems queues the items together; it returns ErrPayoutItemQueued and
+	// queues none of them if any of their sources is already queued or paid
+	CreateItems(ctx context.Context, items []*entities.PayoutItem) error
+	GetItem(ctx context.Context, id uuid.UUID) (*entities.PayoutItem, error)
+	ListQueuedItems(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.PayoutItem, error)
+	UpdateItem(ctx context.Context, item *entities.PayoutItem) error
+
+	// CreateBatch saves the batch and moves its items out of the queue; it fails
+	// if any item was batched concurrently
+	CreateBatch(ctx context.Context, batch *entities.PayoutBatch, items []*entities.PayoutItem) error
+	GetBatch(ctx context.Context, id uuid.UUID) (*entities.PayoutBatch, error)
+	ListBatches(ctx context.Context, cooperativeID uuid.UUID, limit, offset int) ([]*entities.PayoutBatch, int, error)
+	ListBatchItems(ctx context.Context, batch *entities.PayoutBatch) ([]*entities.PayoutItem, error)
+	UpdateBatch(ctx context.Context, batch *entities.PayoutBatch) error
+	// SaveReturn saves the bank's results for the items and the batch's new counts together
+	SaveReturn(ctx context.Context, batch *entities.PayoutBatch, items []*entities.PayoutItem) error
+}
+
+// ErrPayoutItemQueued is returned when a payout source already has an item
+// that has not failed
+var ErrPayoutItemQueued = errors.New("payout already queued")
+
+type payoutRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewPayoutRepository(shardMgr *database.ShardManager) PayoutRepository {
+	return &payoutRepository{shardMgr: shardMgr}
+}
+
+const payoutItemColumns = `id, cooperative_id, escrow_account_id, batch_id, payout_type, source_id, beneficiary_name,
+	beneficiary_account, beneficiary_bank_code, amount, currency, reference, description, status, failure_reason,
+	bank_reference, completed_at, created_at, updated_at`
+
+const payoutBatchColumns = `id, cooperative_id, escrow_account_id, reference, currency, debtor_name, debtor_account,
+	debtor_bank_code, execution_date, status, item_count, total_amount, completed_count, failed_count, export_format,
+	exported_by, exported_at, created_by, created_at, updated_at`
+
+func scanPayoutItem(row interface{ Scan(...interface{}) error }) (*entities.PayoutItem, error) {
+	item := &entities.PayoutItem{}
+	var beneficiaryName, beneficiaryAccount, beneficiaryBankCode, description sql.NullString
+	var failureReason, bankReference sql.NullString
+	err := row.Scan(
+		&item.ID, &item.CooperativeID, &item.EscrowAccountID, &item.BatchID, &item.PayoutType, &item.SourceID,
+		&beneficiaryName, &beneficiaryAccount, &beneficiaryBankCode, &item.Amount, &item.Currency, &item.Reference,
+		&description, &item.Status, &failureReason, &bankReference, &item.CompletedAt, &item.CreatedAt, &item.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	item.Amount = item.Amount.WithCurrency(item.Currency)
+	item.BeneficiaryName = beneficiaryName.String
+	item.BeneficiaryAccount = beneficiaryAccount.String
+	item.BeneficiaryBankCode = beneficiaryBankCode.String
+	item.Description = description.String
+	item.FailureReason = failureReason.String
+	item.BankReference = bankReference.String
+	return item, nil
+}
+
+func scanPayoutBatch(row interface{ Scan(...interface{}) error }) (*entities.PayoutBatch, error) {
+	batch := &entities.PayoutBatch{}
+	var exportFormat sql.NullString
+	err := row.Scan(
+		&batch.ID, &batch.CooperativeID, &batch.EscrowAccountID, &batch.Reference, &batch.Currency, &batch.DebtorName,
+		&batch.DebtorAccount, &batch.DebtorBankCode, &batch.ExecutionDate, &batch.Status, &batch.ItemCount,
+		&batch.TotalAmount, &batch.CompletedCount, &batch.FailedCount, &exportFormat, &batch.ExportedBy,
+		&batch.ExportedAt, &batch.CreatedBy, &batch.CreatedAt, &batch.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	batch.TotalAmount = batch.TotalAmount.WithCurrency(batch.Currency)
+	batch.ExportFormat = exportFormat.String
+	return batch, nil
+}
+
+func (r *payoutRepository) CreateItems(ctx context.Context, items []*entities.PayoutItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	_, shardIndex, err := r.shardMgr.GetShardByCooperativeID(items[0].CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, item := range items {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO payout_items (`+payoutItemColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+			ON CONFLICT (payout_type, source_id) WHERE status <> 'failed' DO NOTHING
+		`,
+			item.ID, item.CooperativeID, item.EscrowAccountID, item.BatchID, item.PayoutType, item.SourceID,
+			nullString(item.BeneficiaryName), nullString(item.BeneficiaryAccount), nullString(item.BeneficiaryBankCode),
+			item.Amount, item.Currency, item.Reference, nullString(item.Description), item.Status,
+			nullString(item.FailureReason), nullString(item.BankReference), item.CompletedAt, item.CreatedAt, item.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert payout item for %s %s: %w", item.PayoutType, item.SourceID, err)
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		} else if affected == 0 {
+			return fmt.Errorf("%s %s: %w", item.PayoutType, item.SourceID, ErrPayoutItemQueued)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit payout items: %w", err)
+	}
+
+	return nil
+}
+
+func (r *payoutRepository) GetItem(ctx context.Context, id uuid.UUID) (*entities.PayoutItem, error) {
+	// Query all shards to find the item
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	query := `SELECT ` + payoutItemColumns + ` FROM payout_items WHERE id = $1`
+
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		item, err := scanPayoutItem(shard.QueryRowContext(ctx, query, id))
+		if err == nil {
+			return item, nil
+		}
+	}
+
+	return nil, fmt.Errorf("payout item not found")
+}
+
+func (r *payoutRepository) ListQueuedItems(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.PayoutItem, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `
+		SELECT ` + payoutItemColumns + `
+		FROM payout_items
+		WHERE cooperative_id = $1 AND status = 'queued'
+		ORDER BY created_at, reference
+	`
+
+	return r.queryItems(ctx, shard, query, cooperativeID)
+}
+
+func (r *payoutRepository) UpdateItem(ctx context.Context, item *entities.PayoutItem) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(item.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	item.UpdatedAt = time.Now()
+
+	result, err := shard.ExecContext(ctx, updatePayoutItemQuery, payoutItemUpdateArgs(item)...)
+	if err != nil {
+		return fmt.Errorf("failed to update payout item: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("payout item not found")
+	}
+
+	return nil
+}
+
+const updatePayoutItemQuery = `
+	UPDATE payout_items SET
+		beneficiary_name = $2, beneficiary_account = $3, beneficiary_bank_code = $4, status = $5,
+		failure_reason = $6, bank_reference = $7, completed_at = $8, updated_at = $9
+	WHERE id = $1
+`
+
+func payoutItemUpdateArgs(item *entities.PayoutItem) []interface{} {
+	return []interface{}{
+		item.ID, nullString(item.BeneficiaryName), nullString(item.BeneficiaryAccount),
+		nullString(item.BeneficiaryBankCode), item.Status, nullString(item.FailureReason),
+		nullString(item.BankReference), item.CompletedAt, item.UpdatedAt,
+	}
+}
+
+func (r *payoutRepository) CreateBatch(ctx context.Context, batch *entities.PayoutBatch, items []*entities.PayoutItem) error {
+	_, shardIndex, err := r.shardMgr.GetShardByCooperativeID(batch.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO payout_batches (`+payoutBatchColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+	`,
+		batch.ID, batch.CooperativeID, batch.EscrowAccountID, batch.Reference, batch.Currency, batch.DebtorName,
+		batch.DebtorAccount, batch.DebtorBankCode, batch.ExecutionDate, batch.Status, batch.ItemCount,
+		batch.TotalAmount, batch.CompletedCount, batch.FailedCount, nullString(batch.ExportFormat), batch.ExportedBy,
+		batch.ExportedAt, batch.CreatedBy, batch.CreatedAt, batch.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert payout batch: %w", err)
+	}
+
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID.String()
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE payout_items SET batch_id = $1, status = 'batched', updated_at = $2
+		WHERE id = ANY($3::uuid[]) AND status = 'queued'
+	`, batch.ID, batch.CreatedAt, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to batch payout items: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected != int64(len(items)) {
+		return fmt.Errorf("payout queue changed while batching; retry")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit payout batch: %w", err)
+	}
+
+	return nil
+}
+
+func (r *payoutRepository) GetBatch(ctx context.Context, id uuid.UUID) (*entities.PayoutBatch, error) {
+	// Query all shards to find the batch
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	query := `SELECT ` + payoutBatchColumns + ` FROM payout_batches WHERE id = $1`
+
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		batch, err := scanPayoutBatch(shard.QueryRowContext(ctx, query, id))
+		if err == nil {
+			return batch, nil
+		}
+	}
+
+	return nil, fmt.Errorf("payout batch not found")
+}
+
+func (r *payoutRepository) ListBatches(ctx context.Context, cooperativeID uuid.UUID, limit, offset int) ([]*entities.PayoutBatch, int, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	var total int
+	if err := shard.QueryRowContext(ctx, `SELECT COUNT(*) FROM payout_batches WHERE cooperative_id = $1`, cooperativeID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count payout batches: %w", err)
+	}
+
+	query := `
+		SELECT ` + payoutBatchColumns + `
+		FROM payout_batches
+		WHERE cooperative_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := shard.QueryContext(ctx, query, cooperativeID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list payout batches: %w", err)
+	}
+	defer rows.Close()
+
+	var batches []*entities.PayoutBatch
+	for rows.Next() {
+		batch, err := scanPayoutBatch(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan payout batch: %w", err)
+		}
+		batches = append(batches, batch)
+	}
+
+	return batches, total, rows.Err()
+}
+
+func (r *payoutRepository) ListBatchItems(ctx context.Context, batch *entities.PayoutBatch) ([]*entities.PayoutItem, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(batch.CooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `
+		SELECT ` + payoutItemColumns + `
+		FROM payout_items
+		WHERE batch_id = $1
+		ORDER BY created_at, reference
+	`
+
+	return r.queryItems(ctx, shard, query, batch.ID)
+}
+
+func (r *payoutRepository) UpdateBatch(ctx context.Context, batch *entities.PayoutBatch) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(batch.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	batch.UpdatedAt = time.Now()
+
+	result, err := shard.ExecContext(ctx, updatePayoutBatchQuery, payoutBatchUpdateArgs(batch)...)
+	if err != nil {
+		return fmt.Errorf("failed to update payout batch: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("payout batch not found")
+	}
+
+	return nil
+}
+
+const updatePayoutBatchQuery = `
+	UPDATE payout_batches SET
+		status = $2, completed_count = $3, failed_count = $4, export_format = $5,
+		exported_by = $6, exported_at = $7, updated_at = $8
+	WHERE id = $1
+`
+
+func payoutBatchUpdateArgs(batch *entities.PayoutBatch) []interface{} {
+	return []interface{}{
+		batch.ID, batch.Status, batch.CompletedCount, batch.FailedCount, nullString(batch.ExportFormat),
+		batch.ExportedBy, batch.ExportedAt, batch.UpdatedAt,
+	}
+}
+
+func (r *payoutRepository) SaveReturn(ctx context.Context, batch *entities.PayoutBatch, items []*entities.PayoutItem) error {
+	_, shardIndex, err := r.shardMgr.GetShardByCooperativeID(batch.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, item := range items {
+		item.UpdatedAt = now
+		if _, err := tx.ExecContext(ctx, updatePayoutItemQuery, payoutItemUpdateArgs(item)...); err != nil {
+			return fmt.Errorf("failed to update payout item %s: %w", item.Reference, err)
+		}
+	}
+
+	batch.UpdatedAt = now
+	if _, err := tx.ExecContext(ctx, updatePayoutBatchQuery, payoutBatchUpdateArgs(batch)...); err != nil {
+		return fmt.Errorf("failed to update payout batch: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit payout return: %w", err)
+	}
+
+	return nil
+}
+
+func (r *payoutRepository) queryItems(ctx context.Context, shard *sql.DB, query string, args ...interface{}) ([]*entities.PayoutItem, error) {
+	rows, err := shard.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payout items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*entities.PayoutItem
+	for rows.Next() {
+		item, err := scanPayoutItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payout item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
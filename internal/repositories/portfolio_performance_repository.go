@@ -0,0 +1,261 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// PortfolioPerformanceRepository reads the dated cash flows investment
+// performance is measured from. Investments live on their project's shard and
+// distributions in their cooperative's ledger, so every shard is read.
+type PortfolioPerformanceRepository interface {
+	// ListPositions returns the investments in the filter's scope made before its end
+	ListPositions(ctx context.Context, filter *entities.PerformanceFilter) ([]*entities.PerformancePosition, error)
+	// ListEvents returns the scope's investments, refunds, exits, stake trades and
+	// losses before the filter's end, ordered by date
+	ListEvents(ctx context.Context, filter *entities.PerformanceFilter) ([]*entities.PerformanceEvent, error)
+	// ListDistributions returns the profit credited to investors in the scope
+	// before the filter's end. Distributions are credited per investor and
+	// project, so their investment ID is not set.
+	ListDistributions(ctx context.Context, filter *entities.PerformanceFilter) ([]*entities.PerformanceEvent, error)
+}
+
+type portfolioPerformanceRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewPortfolioPerformanceRepository(shardMgr *database.ShardManager) PortfolioPerformanceRepository {
+	return &portfolioPerformanceRepository{shardMgr: shardMgr}
+}
+
+// performancePositionsQuery selects the investments in scope; $1 to $3 are the
+// investor, project and cooperative, each matching everything when null, and
+// $4 is the end of the period
+const performancePositionsQuery = `
+	SELECT i.id, i.investor_id, i.project_id, b.cooperative_id, p.title, p.contract_type,
+		COALESCE(c.currency, '') AS currency, i.status, i.investment_date, p.expected_return,
+		COALESCE(p.expected_return_period, 0) AS expected_return_period, i.amount
+	FROM investments i
+	JOIN projects p ON p.id = i.project_id
+	JOIN businesses b ON b.id = p.business_id
+	LEFT JOIN project_contracts c ON c.project_id = p.id
+	WHERE i.status NOT IN ('pending', 'cancelled') AND i.investment_date < $4
+		AND ($1::uuid IS NULL OR i.investor_id = $1)
+		AND ($2::uuid IS NULL OR i.project_id = $2)
+		AND ($3::uuid IS NULL OR b.cooperative_id = $3)`
+
+func performanceArgs(filter *entities.PerformanceFilter) []interface{} {
+	end := time.Now()
+	if filter.End != nil {
+		end = *filter.End
+	}
+	return []interface{}{filter.InvestorID, filter.ProjectID, filter.CooperativeID, end}
+}
+
+func (r *portfolioPerformanceRepository) ListPositions(ctx context.Context, filter *entities.PerformanceFilter) ([]*entities.PerformancePosition, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	var positions []*entities.PerformancePosition
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		rows, err := shard.QueryContext(ctx, performancePositionsQuery+`
+			ORDER BY i.investment_date, i.id
+		`, performanceArgs(filter)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list investment positions: %w", err)
+		}
+
+		for rows.Next() {
+			position := &entities.PerformancePosition{}
+			var expectedReturn sql.NullFloat64
+			var amount entities.Money // only used by the events query
+			err := rows.Scan(
+				&position.InvestmentID, &position.InvestorID, &position.ProjectID, &position.CooperativeID,
+				&position.ProjectTitle, &position.ContractType, &position.Currency, &position.Status,
+				&position.InvestedAt, &expectedReturn, &position.ExpectedReturnPeriod, &amount,
+			)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan investment position: %w", err)
+			}
+			if expectedReturn.Valid {
+				position.ExpectedReturn = &expectedReturn.Float64
+			}
+			positions = append(positions, position)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list investment positions: %w", err)
+		}
+	}
+
+	return positions, nil
+}
+
+func (r *portfolioPerformanceRepository) ListEvents(ctx context.Context, filter *entities.PerformanceFilter) ([]*entities.PerformanceEvent, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	// Full exits leave an investment's amount as it was and only change its
+	// status, so its own contribution is what it still holds plus the capital
+	// that has left it, less the stakes bought into it
+	query := `
+		WITH positions AS (` + performancePositionsQuery + `
+		), exits AS (
+			SELECT p.id,
+				COALESCE((SELECT SUM(w.withdrawn_amount) FROM investment_withdrawals w
+					WHERE w.investment_id = p.id AND w.status = 'completed'), 0)
+				+ COALESCE((SELECT SUM(r.original_investment) FROM investor_refunds r
+					WHERE r.investment_id = p.id AND r.status <> 'failed'), 0)
+				+ COALESCE((SELECT SUM(t.amount) FROM stake_trades t
+					WHERE t.seller_investment_id = p.id AND t.status = 'completed'), 0)
+				- COALESCE((SELECT SUM(t.amount) FROM stake_trades t
+					WHERE t.buyer_investment_id = p.id AND t.status = 'completed'), 0) AS net_exited
+			FROM positions p
+		)
+		SELECT investment_id, investor_id, project_id, event_type, occurred_at, cash, principal_change, loss_change, currency
+		FROM (
+			SELECT p.id AS investment_id, p.investor_id, p.project_id, $5::text AS event_type, p.investment_date AS occurred_at,
+				-(CASE WHEN p.status = 'confirmed' THEN p.amount ELSE 0 END + x.net_exited) AS cash,
+				CASE WHEN p.status = 'confirmed' THEN p.amount ELSE 0 END + x.net_exited AS principal_change,
+				0 AS loss_change, p.currency
+			FROM positions p JOIN exits x ON x.id = p.id
+			UNION ALL
+			SELECT p.id, p.investor_id, p.project_id, $6::text, COALESCE(r.completed_at, r.created_at),
+				r.net_refund_amount, -r.original_investment, 0, p.currency
+			FROM investor_refunds r JOIN positions p ON p.id = r.investment_id
+			WHERE r.status <> 'failed'
+			UNION ALL
+			SELECT p.id, p.investor_id, p.project_id, $7::text, w.completed_at,
+				w.net_amount, -w.withdrawn_amount, -w.unrecovered_loss, p.currency
+			FROM investment_withdrawals w JOIN positions p ON p.id = w.investment_id
+			WHERE w.status = 'completed'
+			UNION ALL
+			SELECT p.id, p.investor_id, p.project_id, $8::text, COALESCE(t.reviewed_at, t.updated_at),
+				-t.price, t.amount, 0, p.currency
+			FROM stake_trades t JOIN positions p ON p.id = t.buyer_investment_id
+			WHERE t.status = 'completed'
+			UNION ALL
+			SELECT p.id, p.investor_id, p.project_id, $9::text, COALESCE(t.reviewed_at, t.updated_at),
+				t.price, -t.amount, 0, p.currency
+			FROM stake_trades t JOIN positions p ON p.id = t.seller_investment_id
+			WHERE t.status = 'completed'
+			UNION ALL
+			SELECT p.id, p.investor_id, p.project_id, $10::text, l.period_end,
+				0, 0, l.loss_amount - l.recovered_amount, p.currency
+			FROM investor_loss_shares l JOIN positions p ON p.id = l.investment_id
+			WHERE l.loss_amount > l.recovered_amount
+		) e
+		WHERE occurred_at < $4 AND (cash <> 0 OR principal_change <> 0 OR loss_change <> 0)
+		ORDER BY occurred_at, investment_id`
+	args := append(performanceArgs(filter),
+		entities.PerformanceEventInvestment, entities.PerformanceEventRefund, entities.PerformanceEventExit,
+		entities.PerformanceEventStakeBought, entities.PerformanceEventStakeSold, entities.PerformanceEventLoss)
+
+	var events []*entities.PerformanceEvent
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		rows, err := shard.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list performance events: %w", err)
+		}
+
+		for rows.Next() {
+			event, err := scanPerformanceEvent(rows, &entities.PerformanceEvent{})
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			events = append(events, event)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list performance events: %w", err)
+		}
+	}
+
+	return events, nil
+}
+
+func (r *portfolioPerformanceRepository) ListDistributions(ctx context.Context, filter *entities.PerformanceFilter) ([]*entities.PerformanceEvent, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	var events []*entities.PerformanceEvent
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		rows, err := shard.QueryContext(ctx, `
+			SELECT $5::uuid, a.owner_id, e.project_id, $6::text, e.posted_at, l.credit, 0, 0, a.currency
+			FROM journal_lines l
+			JOIN ledger_accounts a ON a.id = l.account_id
+			JOIN journal_entries e ON e.id = l.entry_id
+			WHERE e.entry_type = $7 AND a.category = $8 AND l.credit > 0 AND e.project_id IS NOT NULL
+				AND e.posted_at < $4
+				AND ($1::uuid IS NULL OR a.owner_id = $1)
+				AND ($2::uuid IS NULL OR e.project_id = $2)
+				AND ($3::uuid IS NULL OR e.cooperative_id = $3)
+			ORDER BY e.posted_at, e.entry_number
+		`, append(performanceArgs(filter), uuid.Nil, entities.PerformanceEventDistribution,
+			entities.JournalEntryTypeProfitDistribution, entities.LedgerAccountCategoryInvestor)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list distributions: %w", err)
+		}
+
+		for rows.Next() {
+			event, err := scanPerformanceEvent(rows, &entities.PerformanceEvent{})
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			events = append(events, event)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list distributions: %w", err)
+		}
+	}
+
+	return events, nil
+}
+
+func scanPerformanceEvent(row interface{ Scan(...interface{}) error }, event *entities.PerformanceEvent) (*entities.PerformanceEvent, error) {
+	var currency string
+	err := row.Scan(
+		&event.InvestmentID, &event.InvestorID, &event.ProjectID, &event.EventType, &event.OccurredAt,
+		&event.Cash, &event.PrincipalChange, &event.LossChange, &currency,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan performance event: %w", err)
+	}
+
+	event.Cash = event.Cash.WithCurrency(currency)
+	event.PrincipalChange = event.PrincipalChange.WithCurrency(currency)
+	event.LossChange = event.LossChange.WithCurrency(currency)
+	return event, nil
+}
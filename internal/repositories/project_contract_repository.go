@@ -0,0 +1,286 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// ProjectContractRepository stores projects' financing contracts and murabahah
+// installment schedules. Both live on the project's shard.
+type ProjectContractRepository interface {
+	// SaveContract creates or replaces a project's contract together with its
+	// installment schedule, filling in the project's cooperative; it fails if
+	// any installment was already paid
+	SaveContract(ctx context.Context, contract *entities.ProjectContract, installments []*entities.MurabahahInstallment) error
+	GetContract(ctx context.Context, projectID uuid.UUID) (*entities.ProjectContract, error)
+
+	ListInstallments(ctx context.Context, projectID uuid.UUID) ([]*entities.MurabahahInstallment, error)
+	GetInstallment(ctx context.Context, projectID, installmentID uuid.UUID) (*entities.MurabahahInstallment, error)
+	// UpdateInstallment saves an installment's payment and settlement if it is
+	// still in fromStatus, reporting whether it was
+	UpdateInstallment(ctx context.Context, installment *entities.MurabahahInstallment, fromStatus string) (bool, error)
+}
+
+type projectContractRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewProjectContractRepository(shardMgr *database.ShardManager) ProjectContractRepository {
+	return &projectContractRepository{shardMgr: shardMgr}
+}
+
+const projectContractColumns = `c.project_id, c.cooperative_id, c.contract_type, c.currency, c.profit_sharing_ratio,
+	c.partner_capital, c.asset_description, c.cost_price, c.markup, c.installment_count, c.installment_interval_months,
+	c.first_installment_date, c.created_by, c.created_at, c.updated_at, p.current_funding`
+
+const murabahahInstallmentColumns = `id, project_id, cooperative_id, sequence, due_date, amount, principal_amount,
+	profit_amount, currency, status, paid_at, payment_reference, profit_distribution_id, settled_at, created_at, updated_at`
+
+func scanProjectContract(row interface{ Scan(...interface{}) error }) (*entities.ProjectContract, error) {
+	contract := &entities.ProjectContract{}
+	var ratioJSON []byte
+	var assetDescription sql.NullString
+	err := row.Scan(
+		&contract.ProjectID, &contract.CooperativeID, &contract.ContractType, &contract.Currency, &ratioJSON,
+		&contract.PartnerCapital, &assetDescription, &contract.CostPrice, &contract.Markup, &contract.InstallmentCount,
+		&contract.InstallmentIntervalMonths, &contract.FirstInstallmentDate, &contract.CreatedBy, &contract.CreatedAt,
+		&contract.UpdatedAt, &contract.InvestorCapital,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ratioJSON) > 0 {
+		if err := json.Unmarshal(ratioJSON, &contract.ProfitSharingRatio); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal profit sharing ratio: %w", err)
+		}
+	}
+	contract.AssetDescription = assetDescription.String
+	contract.PartnerCapital = contract.PartnerCapital.WithCurrency(contract.Currency)
+	contract.InvestorCapital = contract.InvestorCapital.WithCurrency(contract.Currency)
+	contract.CostPrice = contract.CostPrice.WithCurrency(contract.Currency)
+	contract.Markup = contract.Markup.WithCurrency(contract.Currency)
+	return contract, nil
+}
+
+func scanMurabahahInstallment(row interface{ Scan(...interface{}) error }) (*entities.MurabahahInstallment, error) {
+	installment := &entities.MurabahahInstallment{}
+	var paymentReference sql.NullString
+	err := row.Scan(
+		&installment.ID, &installment.ProjectID, &installment.CooperativeID, &installment.Sequence, &installment.DueDate,
+		&installment.Amount, &installment.PrincipalAmount, &installment.ProfitAmount, &installment.Currency,
+		&installment.Status, &installment.PaidAt, &paymentReference, &installment.ProfitDistributionID,
+		&installment.SettledAt, &installment.CreatedAt, &installment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	installment.PaymentReference = paymentReference.String
+	installment.Amount = installment.Amount.WithCurrency(installment.Currency)
+	installment.PrincipalAmount = installment.PrincipalAmount.WithCurrency(installment.Currency)
+	installment.ProfitAmount = installment.ProfitAmount.WithCurrency(installment.Currency)
+	return installment, nil
+}
+
+func (r *projectContractRepository) SaveContract(ctx context.Context, contract *entities.ProjectContract, installments []*entities.MurabahahInstallment) error {
+	_, shardIndex, err := r.shardMgr.GetShardByID(contract.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	ratioJSON, err := json.Marshal(contract.ProfitSharingRatio)
+	if err != nil {
+		return fmt.Errorf("failed to marshal profit sharing ratio: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT b.cooperative_id
+		FROM projects p
+		JOIN businesses b ON b.id = p.business_id
+		WHERE p.id = $1
+		FOR UPDATE OF p
+	`, contract.ProjectID).Scan(&contract.CooperativeID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("project not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get project cooperative: %w", err)
+	}
+	for _, installment := range installments {
+		installment.CooperativeID = contract.CooperativeID
+	}
+
+	var paid int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM murabahah_installments
+		WHERE project_id = $1 AND status <> $2
+	`, contract.ProjectID, entities.MurabahahInstallmentStatusScheduled).Scan(&paid)
+	if err != nil {
+		return fmt.Errorf("failed to check installments: %w", err)
+	}
+	if paid > 0 {
+		return fmt.Errorf("contract has paid installments and can no longer be changed")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO project_contracts (project_id, cooperative_id, contract_type, currency, profit_sharing_ratio,
+			partner_capital, asset_description, cost_price, markup, installment_count, installment_interval_months,
+			first_installment_date, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (project_id) DO UPDATE SET
+			contract_type = EXCLUDED.contract_type, currency = EXCLUDED.currency,
+			profit_sharing_ratio = EXCLUDED.profit_sharing_ratio, partner_capital = EXCLUDED.partner_capital,
+			asset_description = EXCLUDED.asset_description, cost_price = EXCLUDED.cost_price,
+			markup = EXCLUDED.markup, installment_count = EXCLUDED.installment_count,
+			installment_interval_months = EXCLUDED.installment_interval_months,
+			first_installment_date = EXCLUDED.first_installment_date, updated_at = EXCLUDED.updated_at
+	`,
+		contract.ProjectID, contract.CooperativeID, contract.ContractType, contract.Currency, ratioJSON,
+		contract.PartnerCapital, nullString(contract.AssetDescription), contract.CostPrice, contract.Markup,
+		contract.InstallmentCount, contract.InstallmentIntervalMonths, contract.FirstInstallmentDate,
+		contract.CreatedBy, contract.CreatedAt, contract.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save project contract: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE projects SET contract_type = $2 WHERE id = $1`, contract.ProjectID, contract.ContractType)
+	if err != nil {
+		return fmt.Errorf("failed to update project contract type: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM murabahah_installments WHERE project_id = $1`, contract.ProjectID); err != nil {
+		return fmt.Errorf("failed to clear installment schedule: %w", err)
+	}
+
+	for _, installment := range installments {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO murabahah_installments (`+murabahahInstallmentColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		`,
+			installment.ID, installment.ProjectID, installment.CooperativeID, installment.Sequence, installment.DueDate,
+			installment.Amount, installment.PrincipalAmount, installment.ProfitAmount, installment.Currency,
+			installment.Status, installment.PaidAt, nullString(installment.PaymentReference),
+			installment.ProfitDistributionID, installment.SettledAt, installment.CreatedAt, installment.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert installment %d: %w", installment.Sequence, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit project contract: %w", err)
+	}
+
+	return nil
+}
+
+func (r *projectContractRepository) GetContract(ctx context.Context, projectID uuid.UUID) (*entities.ProjectContract, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	contract, err := scanProjectContract(shard.QueryRowContext(ctx, `
+		SELECT `+projectContractColumns+`
+		FROM project_contracts c
+		JOIN projects p ON p.id = c.project_id
+		WHERE c.project_id = $1
+	`, projectID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project contract: %w", err)
+	}
+
+	return contract, nil
+}
+
+func (r *projectContractRepository) ListInstallments(ctx context.Context, projectID uuid.UUID) ([]*entities.MurabahahInstallment, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+murabahahInstallmentColumns+`
+		FROM murabahah_installments
+		WHERE project_id = $1
+		ORDER BY sequence
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list installments: %w", err)
+	}
+	defer rows.Close()
+
+	var installments []*entities.MurabahahInstallment
+	for rows.Next() {
+		installment, err := scanMurabahahInstallment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan installment: %w", err)
+		}
+		installments = append(installments, installment)
+	}
+
+	return installments, rows.Err()
+}
+
+func (r *projectContractRepository) GetInstallment(ctx context.Context, projectID, installmentID uuid.UUID) (*entities.MurabahahInstallment, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	installment, err := scanMurabahahInstallment(shard.QueryRowContext(ctx, `
+		SELECT `+murabahahInstallmentColumns+`
+		FROM murabahah_installments
+		WHERE id = $1 AND project_id = $2
+	`, installmentID, projectID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("installment not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get installment: %w", err)
+	}
+
+	return installment, nil
+}
+
+func (r *projectContractRepository) UpdateInstallment(ctx context.Context, installment *entities.MurabahahInstallment, fromStatus string) (bool, error) {
+	shard, _, err := r.shardMgr.GetShardByID(installment.ProjectID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE murabahah_installments SET
+			status = $3, paid_at = $4, payment_reference = $5, profit_distribution_id = $6, settled_at = $7,
+			updated_at = $8
+		WHERE id = $1 AND status = $2
+	`,
+		installment.ID, fromStatus, installment.Status, installment.PaidAt, nullString(installment.PaymentReference),
+		installment.ProfitDistributionID, installment.SettledAt, installment.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to update installment: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
@@ -0,0 +1,556 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// ProjectFundingRepository reads projects' funding against their deadlines and
+// records how their funding was closed. Closures live on the project's shard.
+type ProjectFundingRepository interface {
+	GetFundingProject(ctx context.Context, projectID uuid.UUID) (*entities.FundingProject, error)
+	// ListDueProjects returns the projects still raising funds whose deadline is
+	// at or before asOf
+	ListDueProjects(ctx context.Context, asOf time.Time) ([]*entities.FundingProject, error)
+	ListConfirmedInvestments(ctx context.Context, projectID uuid.UUID) ([]*entities.Investment, error)
+
+	// GetClosure returns the project's funding closure, or nil if its funding
+	// has not been closed
+	GetClosure(ctx context.Context, projectID uuid.UUID) (*entities.ProjectFundingClosure, error)
+	// CloseFunding records the closure and, in the same transaction, ends the
+	// project's fundraising: a funded project is marked funded, otherwise it is
+	// cancelled and refund, when set, is recorded with its investor refunds and
+	// the refunded investments. Unpaid investments are cancelled and the funding
+	// they reserved is released either way.
+	// It fails if the project is no longer raising funds or its funding has
+	// changed since closure.CurrentFunding was read.
+	CloseFunding(ctx context.Context, closure *entities.ProjectFundingClosure, refund *entities.FundRefund, investorRefunds []*entities.InvestorRefund) error
+	// ListUnfinishedClosures returns the closures still awaiting their success
+	// fee or the ledger posting of their refund, or whose outcome has not been
+	// notified yet
+	ListUnfinishedClosures(ctx context.Context) ([]*entities.ProjectFundingClosure, error)
+	// RecordClosureFee records the success fee charged on a funded closure. It
+	// fails if the closure already has a fee.
+	RecordClosureFee(ctx context.Context, closure *entities.ProjectFundingClosure) error
+	// GetClosureRefund returns the refund recorded when the closure cancelled its
+	// project, with its investor refunds
+	GetClosureRefund(ctx context.Context, closure *entities.ProjectFundingClosure) (*entities.FundRefund, []*entities.InvestorRefund, error)
+	// MarkClosureRefundPosted records when the closure's refund was posted to the ledger
+	MarkClosureRefundPosted(ctx context.Context, closure *entities.ProjectFundingClosure, postedAt time.Time) error
+	// MarkClosureNotified records when the closure's outcome was notified
+	MarkClosureNotified(ctx context.Context, closure *entities.ProjectFundingClosure, notifiedAt time.Time) error
+	// MarkFunded marks a project that is raising funds as funded, reporting
+	// whether it was
+	MarkFunded(ctx context.Context, projectID uuid.UUID, fundedAt time.Time) (bool, error)
+}
+
+type projectFundingRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewProjectFundingRepository(shardMgr *database.ShardManager) ProjectFundingRepository {
+	return &projectFundingRepository{shardMgr: shardMgr}
+}
+
+// Projects without a minimum must reach their goal
+const fundingProjectQuery = `
+	SELECT p.id, b.cooperative_id, b.owner_id, p.title, COALESCE(c.currency, ''), p.status, p.funding_deadline,
+		p.current_funding, COALESCE(p.minimum_funding, p.funding_goal)
+	FROM projects p
+	JOIN businesses b ON b.id = p.business_id
+	LEFT JOIN project_contracts c ON c.project_id = p.id`
+
+func scanFundingProject(row interface{ Scan(...interface{}) error }) (*entities.FundingProject, error) {
+	project := &entities.FundingProject{}
+	err := row.Scan(
+		&project.ProjectID, &project.CooperativeID, &project.OwnerID, &project.Title, &project.Currency,
+		&project.Status, &project.FundingDeadline, &project.CurrentFunding, &project.MinimumFunding,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	project.CurrentFunding = project.CurrentFunding.WithCurrency(project.Currency)
+	project.MinimumFunding = project.MinimumFunding.WithCurrency(project.Currency)
+	return project, nil
+}
+
+func (r *projectFundingRepository) GetFundingProject(ctx context.Context, projectID uuid.UUID) (*entities.FundingProject, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	project, err := scanFundingProject(shard.QueryRowContext(ctx, fundingProjectQuery+`
+		WHERE p.id = $1 AND p.funding_deadline IS NOT NULL
+	`, projectID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("project not found or has no funding deadline")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project funding: %w", err)
+	}
+
+	return project, nil
+}
+
+func (r *projectFundingRepository) ListDueProjects(ctx context.Context, asOf time.Time) ([]*entities.FundingProject, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	var projects []*entities.FundingProject
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		rows, err := shard.QueryContext(ctx, fundingProjectQuery+`
+			WHERE p.status = 'active' AND p.funding_deadline <= $1
+			ORDER BY p.funding_deadline
+		`, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list due projects: %w", err)
+		}
+
+		for rows.Next() {
+			project, err := scanFundingProject(rows)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan project funding: %w", err)
+			}
+			projects = append(projects, project)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list due projects: %w", err)
+		}
+	}
+
+	return projects, nil
+}
+
+func (r *projectFundingRepository) ListConfirmedInvestments(ctx context.Context, projectID uuid.UUID) ([]*entities.Investment, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT id, project_id, investor_id, amount, investment_date, profit_sharing_percentage, status, transaction_ref,
+			created_at, updated_at
+		FROM investments
+		WHERE project_id = $1 AND status = 'confirmed'
+		ORDER BY investment_date, id
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list investments: %w", err)
+	}
+	defer rows.Close()
+
+	var investments []*entities.Investment
+	for rows.Next() {
+		investment := &entities.Investment{}
+		err := rows.Scan(
+			&investment.ID, &investment.ProjectID, &investment.InvestorID, &investment.Amount,
+			&investment.InvestmentDate, &investment.ProfitSharingPercentage, &investment.Status,
+			&investment.TransactionRef, &investment.CreatedAt, &investment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan investment: %w", err)
+		}
+		investments = append(investments, investment)
+	}
+
+	return investments, rows.Err()
+}
+
+const fundingClosureColumns = `id, project_id, cooperative_id, currency, outcome, funding_deadline, current_funding,
+	minimum_funding, investor_count, fee_calculation_id, fee_percentage, fee_amount, fund_refund_id, closed_by,
+	closed_at, refund_posted_at, notified_at, created_at`
+
+func scanFundingClosure(row interface{ Scan(...interface{}) error }) (*entities.ProjectFundingClosure, error) {
+	closure := &entities.ProjectFundingClosure{}
+	var feeAmount *entities.Money
+	err := row.Scan(
+		&closure.ID, &closure.ProjectID, &closure.CooperativeID, &closure.Currency, &closure.Outcome,
+		&closure.FundingDeadline, &closure.CurrentFunding, &closure.MinimumFunding, &closure.InvestorCount,
+		&closure.FeeCalculationID, &closure.FeePercentage, &feeAmount, &closure.FundRefundID, &closure.ClosedBy,
+		&closure.ClosedAt, &closure.RefundPostedAt, &closure.NotifiedAt, &closure.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	closure.CurrentFunding = closure.CurrentFunding.WithCurrency(closure.Currency)
+	closure.MinimumFunding = closure.MinimumFunding.WithCurrency(closure.Currency)
+	if feeAmount != nil {
+		fee := feeAmount.WithCurrency(closure.Currency)
+		closure.FeeAmount = &fee
+	}
+	return closure, nil
+}
+
+func (r *projectFundingRepository) GetClosure(ctx context.Context, projectID uuid.UUID) (*entities.ProjectFundingClosure, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	closure, err := scanFundingClosure(shard.QueryRowContext(ctx, `
+		SELECT `+fundingClosureColumns+`
+		FROM project_funding_closures
+		WHERE project_id = $1
+	`, projectID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding closure: %w", err)
+	}
+
+	return closure, nil
+}
+
+func (r *projectFundingRepository) ListUnfinishedClosures(ctx context.Context) ([]*entities.ProjectFundingClosure, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	var closures []*entities.ProjectFundingClosure
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		rows, err := shard.QueryContext(ctx, `
+			SELECT `+fundingClosureColumns+`
+			FROM project_funding_closures
+			WHERE (outcome = $1 AND fee_calculation_id IS NULL)
+				OR (fund_refund_id IS NOT NULL AND refund_posted_at IS NULL)
+				OR notified_at IS NULL
+			ORDER BY closed_at
+		`, entities.ProjectFundingOutcomeFunded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list unfinished closures: %w", err)
+		}
+
+		for rows.Next() {
+			closure, err := scanFundingClosure(rows)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan funding closure: %w", err)
+			}
+			closures = append(closures, closure)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list unfinished closures: %w", err)
+		}
+	}
+
+	return closures, nil
+}
+
+func (r *projectFundingRepository) RecordClosureFee(ctx context.Context, closure *entities.ProjectFundingClosure) error {
+	shard, _, err := r.shardMgr.GetShardByID(closure.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE project_funding_closures SET fee_calculation_id = $2, fee_percentage = $3, fee_amount = $4
+		WHERE id = $1 AND outcome = $5 AND fee_calculation_id IS NULL
+	`, closure.ID, closure.FeeCalculationID, closure.FeePercentage, closure.FeeAmount, entities.ProjectFundingOutcomeFunded)
+	if err != nil {
+		return fmt.Errorf("failed to record success fee: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected != 1 {
+		return fmt.Errorf("funding closure already has a success fee")
+	}
+
+	return nil
+}
+
+func (r *projectFundingRepository) GetClosureRefund(ctx context.Context, closure *entities.ProjectFundingClosure) (*entities.FundRefund, []*entities.InvestorRefund, error) {
+	if closure.FundRefundID == nil {
+		return nil, nil, fmt.Errorf("funding closure has no refund")
+	}
+
+	shard, _, err := r.shardMgr.GetShardByID(closure.ProjectID.String())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	return getFundRefund(ctx, shard, *closure.FundRefundID)
+}
+
+// getFundRefund reads a refund and its investor refunds from the project's shard
+func getFundRefund(ctx context.Context, shard *sql.DB, refundID uuid.UUID) (*entities.FundRefund, []*entities.InvestorRefund, error) {
+	refund := &entities.FundRefund{}
+	err := shard.QueryRowContext(ctx, `
+		SELECT id, project_id, cooperative_id, refund_type, refund_reason, total_refund_amount, currency,
+			refund_percentage, processing_fee, net_refund_amount, status, initiated_by, initiated_at, created_at,
+			updated_at
+		FROM fund_refunds
+		WHERE id = $1
+	`, refundID).Scan(
+		&refund.ID, &refund.ProjectID, &refund.CooperativeID, &refund.RefundType, &refund.RefundReason,
+		&refund.TotalRefundAmount, &refund.Currency, &refund.RefundPercentage, &refund.ProcessingFee,
+		&refund.NetRefundAmount, &refund.Status, &refund.InitiatedBy, &refund.InitiatedAt, &refund.CreatedAt,
+		&refund.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil, fmt.Errorf("fund refund not found")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get fund refund: %w", err)
+	}
+	refund.TotalRefundAmount = refund.TotalRefundAmount.WithCurrency(refund.Currency)
+	refund.ProcessingFee = refund.ProcessingFee.WithCurrency(refund.Currency)
+	refund.NetRefundAmount = refund.NetRefundAmount.WithCurrency(refund.Currency)
+	refund.IsActive = true
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT id, fund_refund_id, investment_id, investor_id, original_investment, refund_amount, processing_fee,
+			net_refund_amount, status, COALESCE(bank_account, ''), created_at, updated_at
+		FROM investor_refunds
+		WHERE fund_refund_id = $1
+		ORDER BY created_at, id
+	`, refund.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list investor refunds: %w", err)
+	}
+	defer rows.Close()
+
+	var investorRefunds []*entities.InvestorRefund
+	for rows.Next() {
+		ir := &entities.InvestorRefund{IsActive: true}
+		err := rows.Scan(&ir.ID, &ir.FundRefundID, &ir.InvestmentID, &ir.InvestorID, &ir.OriginalInvestment,
+			&ir.RefundAmount, &ir.ProcessingFee, &ir.NetRefundAmount, &ir.Status, &ir.BankAccount, &ir.CreatedAt,
+			&ir.UpdatedAt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan investor refund: %w", err)
+		}
+		ir.OriginalInvestment = ir.OriginalInvestment.WithCurrency(refund.Currency)
+		ir.RefundAmount = ir.RefundAmount.WithCurrency(refund.Currency)
+		ir.ProcessingFee = ir.ProcessingFee.WithCurrency(refund.Currency)
+		ir.NetRefundAmount = ir.NetRefundAmount.WithCurrency(refund.Currency)
+		investorRefunds = append(investorRefunds, ir)
+	}
+
+	return refund, investorRefunds, rows.Err()
+}
+
+func (r *projectFundingRepository) MarkClosureRefundPosted(ctx context.Context, closure *entities.ProjectFundingClosure, postedAt time.Time) error {
+	shard, _, err := r.shardMgr.GetShardByID(closure.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	_, err = shard.ExecContext(ctx, `
+		UPDATE project_funding_closures SET refund_posted_at = $2 WHERE id = $1 AND refund_posted_at IS NULL
+	`, closure.ID, postedAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark funding closure refund posted: %w", err)
+	}
+
+	return nil
+}
+
+func (r *projectFundingRepository) MarkClosureNotified(ctx context.Context, closure *entities.ProjectFundingClosure, notifiedAt time.Time) error {
+	shard, _, err := r.shardMgr.GetShardByID(closure.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	_, err = shard.ExecContext(ctx, `
+		UPDATE project_funding_closures SET notified_at = $2 WHERE id = $1 AND notified_at IS NULL
+	`, closure.ID, notifiedAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark funding closure notified: %w", err)
+	}
+
+	return nil
+}
+
+func (r *projectFundingRepository) CloseFunding(ctx context.Context, closure *entities.ProjectFundingClosure, refund *entities.FundRefund, investorRefunds []*entities.InvestorRefund) error {
+	_, shardIndex, err := r.shardMgr.GetShardByID(closure.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	var funding entities.Money
+	err = tx.QueryRowContext(ctx, `
+		SELECT status, current_funding FROM projects WHERE id = $1 FOR UPDATE
+	`, closure.ProjectID).Scan(&status, &funding)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("project not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock project: %w", err)
+	}
+	if status != entities.ProjectStatusActive {
+		return fmt.Errorf("project is %s and no longer raising funds", status)
+	}
+	if !funding.WithCurrency(closure.Currency).Equal(closure.CurrentFunding) {
+		return fmt.Errorf("project funding changed while closing; retry")
+	}
+
+	if closure.Outcome == entities.ProjectFundingOutcomeFunded {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE projects SET status = 'funded', funded_at = $2, updated_at = $2 WHERE id = $1
+		`, closure.ProjectID, closure.ClosedAt)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE projects SET status = 'cancelled', updated_at = $2 WHERE id = $1
+		`, closure.ProjectID, closure.ClosedAt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to close project funding: %w", err)
+	}
+
+	// Investments never paid for lapse with the funding window
+	_, err = tx.ExecContext(ctx, `
+		UPDATE investments SET status = 'cancelled', updated_at = $2 WHERE project_id = $1 AND status = 'pending'
+	`, closure.ProjectID, closure.ClosedAt)
+	if err != nil {
+		return fmt.Errorf("failed to cancel unpaid investments: %w", err)
+	}
+
+	// Their reservations no longer hold funding against a closed project
+	_, err = tx.ExecContext(ctx, `
+		UPDATE investment_reservations SET status = $2, released_at = $3, release_reason = 'project funding closed'
+		WHERE project_id = $1 AND status = $4
+	`, closure.ProjectID, entities.InvestmentReservationStatusReleased, closure.ClosedAt, entities.InvestmentReservationStatusActive)
+	if err != nil {
+		return fmt.Errorf("failed to release investment reservations: %w", err)
+	}
+
+	if refund != nil {
+		if err := r.createRefund(ctx, tx, refund, investorRefunds); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO project_funding_closures (id, project_id, cooperative_id, currency, outcome, funding_deadline,
+			current_funding, minimum_funding, investor_count, fee_calculation_id, fee_percentage, fee_amount,
+			fund_refund_id, closed_by, closed_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`,
+		closure.ID, closure.ProjectID, closure.CooperativeID, closure.Currency, closure.Outcome,
+		closure.FundingDeadline, closure.CurrentFunding, closure.MinimumFunding, closure.InvestorCount,
+		closure.FeeCalculationID, closure.FeePercentage, closure.FeeAmount, closure.FundRefundID, closure.ClosedBy,
+		closure.ClosedAt, closure.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record funding closure: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit funding closure: %w", err)
+	}
+
+	return nil
+}
+
+// createRefund records a refund of the project's confirmed investments
+func (r *projectFundingRepository) createRefund(ctx context.Context, tx *sql.Tx, refund *entities.FundRefund, investorRefunds []*entities.InvestorRefund) error {
+	if err := insertFundRefund(ctx, tx, refund, investorRefunds); err != nil {
+		return err
+	}
+
+	// Every confirmed investment must be covered by the refund
+	result, err := tx.ExecContext(ctx, `
+		UPDATE investments SET status = 'refunded', profit_sharing_percentage = 0, updated_at = $2
+		WHERE project_id = $1 AND status = 'confirmed'
+	`, refund.ProjectID, refund.InitiatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark investments refunded: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if int(affected) != len(investorRefunds) {
+		return fmt.Errorf("project investments changed while closing; retry")
+	}
+
+	return nil
+}
+
+// insertFundRefund saves a refund and its investor refunds in tx
+func insertFundRefund(ctx context.Context, tx *sql.Tx, refund *entities.FundRefund, investorRefunds []*entities.InvestorRefund) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO fund_refunds (id, project_id, cooperative_id, refund_type, refund_reason, total_refund_amount,
+			currency, refund_percentage, processing_fee, net_refund_amount, status, initiated_by, initiated_at,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`,
+		refund.ID, refund.ProjectID, refund.CooperativeID, refund.RefundType, refund.RefundReason,
+		refund.TotalRefundAmount, refund.Currency, refund.RefundPercentage, refund.ProcessingFee,
+		refund.NetRefundAmount, refund.Status, refund.InitiatedBy, refund.InitiatedAt, refund.CreatedAt,
+		refund.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create fund refund: %w", err)
+	}
+
+	for _, ir := range investorRefunds {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO investor_refunds (id, fund_refund_id, investment_id, investor_id, original_investment,
+				refund_amount, processing_fee, net_refund_amount, status, bank_account, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		`,
+			ir.ID, ir.FundRefundID, ir.InvestmentID, ir.InvestorID, ir.OriginalInvestment, ir.RefundAmount,
+			ir.ProcessingFee, ir.NetRefundAmount, ir.Status, nullString(ir.BankAccount), ir.CreatedAt, ir.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create investor refund: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *projectFundingRepository) MarkFunded(ctx context.Context, projectID uuid.UUID, fundedAt time.Time) (bool, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE projects SET status = 'funded', funded_at = $2, updated_at = $2 WHERE id = $1 AND status = 'active'
+	`, projectID, fundedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark project funded: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
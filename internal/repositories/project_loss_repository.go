@@ -0,0 +1,312 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// ProjectLossRepository stores project losses, the investors' shares of them and
+// the profits that later recovered them. Losses live on the project's shard.
+type ProjectLossRepository interface {
+	CreateLoss(ctx context.Context, loss *entities.ProjectLoss, shares []*entities.InvestorLossShare) error
+	ListProjectLosses(ctx context.Context, projectID uuid.UUID) ([]*entities.ProjectLoss, error)
+	ListLossShares(ctx context.Context, loss *entities.ProjectLoss) ([]*entities.InvestorLossShare, error)
+	ListInvestorLossShares(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestorLossShare, error)
+	ListRecoveries(ctx context.Context, projectID uuid.UUID) ([]*entities.ProjectLossRecovery, error)
+
+	// ApplyRecoveries saves recoveries together with the losses and shares they
+	// offset; it fails if any of the losses was offset concurrently
+	ApplyRecoveries(ctx context.Context, projectID uuid.UUID, recoveries []*entities.ProjectLossRecovery, losses []*entities.ProjectLoss, shares []*entities.InvestorLossShare) error
+}
+
+type projectLossRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewProjectLossRepository(shardMgr *database.ShardManager) ProjectLossRepository {
+	return &projectLossRepository{shardMgr: shardMgr}
+}
+
+const projectLossColumns = `id, project_id, cooperative_id, profit_calculation_id, period_start, period_end,
+	loss_handling_method, borne_by, loss_amount, recovered_amount, currency, status, recorded_by, created_at, updated_at`
+
+const investorLossShareColumns = `id, project_loss_id, project_id, investment_id, investor_id, period_start, period_end,
+	original_investment, investment_percentage, loss_amount, recovered_amount, currency, created_at, updated_at`
+
+const projectLossRecoveryColumns = `id, project_loss_id, project_id, profit_calculation_id, amount, currency,
+	recorded_by, created_at`
+
+func scanProjectLoss(row interface{ Scan(...interface{}) error }) (*entities.ProjectLoss, error) {
+	loss := &entities.ProjectLoss{}
+	err := row.Scan(
+		&loss.ID, &loss.ProjectID, &loss.CooperativeID, &loss.ProfitCalculationID, &loss.PeriodStart, &loss.PeriodEnd,
+		&loss.LossHandlingMethod, &loss.BorneBy, &loss.LossAmount, &loss.RecoveredAmount, &loss.Currency,
+		&loss.Status, &loss.RecordedBy, &loss.CreatedAt, &loss.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	loss.LossAmount = loss.LossAmount.WithCurrency(loss.Currency)
+	loss.RecoveredAmount = loss.RecoveredAmount.WithCurrency(loss.Currency)
+	return loss, nil
+}
+
+func scanInvestorLossShare(row interface{ Scan(...interface{}) error }) (*entities.InvestorLossShare, error) {
+	share := &entities.InvestorLossShare{}
+	err := row.Scan(
+		&share.ID, &share.ProjectLossID, &share.ProjectID, &share.InvestmentID, &share.InvestorID,
+		&share.PeriodStart, &share.PeriodEnd, &share.OriginalInvestment, &share.InvestmentPercentage,
+		&share.LossAmount, &share.RecoveredAmount, &share.Currency, &share.CreatedAt, &share.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	share.OriginalInvestment = share.OriginalInvestment.WithCurrency(share.Currency)
+	share.LossAmount = share.LossAmount.WithCurrency(share.Currency)
+	share.RecoveredAmount = share.RecoveredAmount.WithCurrency(share.Currency)
+	return share, nil
+}
+
+func (r *projectLossRepository) CreateLoss(ctx context.Context, loss *entities.ProjectLoss, shares []*entities.InvestorLossShare) error {
+	_, shardIndex, err := r.shardMgr.GetShardByID(loss.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO project_losses (`+projectLossColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`,
+		loss.ID, loss.ProjectID, loss.CooperativeID, loss.ProfitCalculationID, loss.PeriodStart, loss.PeriodEnd,
+		loss.LossHandlingMethod, loss.BorneBy, loss.LossAmount, loss.RecoveredAmount, loss.Currency, loss.Status,
+		loss.RecordedBy, loss.CreatedAt, loss.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert project loss: %w", err)
+	}
+
+	for _, share := range shares {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO investor_loss_shares (`+investorLossShareColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		`,
+			share.ID, share.ProjectLossID, share.ProjectID, share.InvestmentID, share.InvestorID, share.PeriodStart,
+			share.PeriodEnd, share.OriginalInvestment, share.InvestmentPercentage, share.LossAmount,
+			share.RecoveredAmount, share.Currency, share.CreatedAt, share.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert loss share for investment %s: %w", share.InvestmentID, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit project loss: %w", err)
+	}
+
+	return nil
+}
+
+func (r *projectLossRepository) ListProjectLosses(ctx context.Context, projectID uuid.UUID) ([]*entities.ProjectLoss, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+projectLossColumns+`
+		FROM project_losses
+		WHERE project_id = $1
+		ORDER BY period_end, created_at
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project losses: %w", err)
+	}
+	defer rows.Close()
+
+	var losses []*entities.ProjectLoss
+	for rows.Next() {
+		loss, err := scanProjectLoss(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project loss: %w", err)
+		}
+		losses = append(losses, loss)
+	}
+
+	return losses, rows.Err()
+}
+
+func (r *projectLossRepository) ListLossShares(ctx context.Context, loss *entities.ProjectLoss) ([]*entities.InvestorLossShare, error) {
+	shard, _, err := r.shardMgr.GetShardByID(loss.ProjectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `
+		SELECT ` + investorLossShareColumns + `
+		FROM investor_loss_shares
+		WHERE project_loss_id = $1
+		ORDER BY created_at, id
+	`
+
+	return r.queryShares(ctx, shard, query, loss.ID)
+}
+
+func (r *projectLossRepository) ListInvestorLossShares(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestorLossShare, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	query := `
+		SELECT ` + investorLossShareColumns + `
+		FROM investor_loss_shares
+		WHERE investor_id = $1
+		ORDER BY period_end, created_at
+	`
+
+	var shares []*entities.InvestorLossShare
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		shardShares, err := r.queryShares(ctx, shard, query, investorID)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, shardShares...)
+	}
+
+	return shares, nil
+}
+
+func (r *projectLossRepository) queryShares(ctx context.Context, shard *sql.DB, query string, args ...interface{}) ([]*entities.InvestorLossShare, error) {
+	rows, err := shard.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list investor loss shares: %w", err)
+	}
+	defer rows.Close()
+
+	var shares []*entities.InvestorLossShare
+	for rows.Next() {
+		share, err := scanInvestorLossShare(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan investor loss share: %w", err)
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, rows.Err()
+}
+
+func (r *projectLossRepository) ListRecoveries(ctx context.Context, projectID uuid.UUID) ([]*entities.ProjectLossRecovery, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+projectLossRecoveryColumns+`
+		FROM project_loss_recoveries
+		WHERE project_id = $1
+		ORDER BY created_at, id
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list loss recoveries: %w", err)
+	}
+	defer rows.Close()
+
+	var recoveries []*entities.ProjectLossRecovery
+	for rows.Next() {
+		recovery := &entities.ProjectLossRecovery{}
+		err := rows.Scan(
+			&recovery.ID, &recovery.ProjectLossID, &recovery.ProjectID, &recovery.ProfitCalculationID,
+			&recovery.Amount, &recovery.Currency, &recovery.RecordedBy, &recovery.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan loss recovery: %w", err)
+		}
+		recovery.Amount = recovery.Amount.WithCurrency(recovery.Currency)
+		recoveries = append(recoveries, recovery)
+	}
+
+	return recoveries, rows.Err()
+}
+
+func (r *projectLossRepository) ApplyRecoveries(ctx context.Context, projectID uuid.UUID, recoveries []*entities.ProjectLossRecovery, losses []*entities.ProjectLoss, shares []*entities.InvestorLossShare) error {
+	_, shardIndex, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	recovered := make(map[uuid.UUID]entities.Money, len(recoveries))
+	for _, recovery := range recoveries {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO project_loss_recoveries (`+projectLossRecoveryColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`,
+			recovery.ID, recovery.ProjectLossID, recovery.ProjectID, recovery.ProfitCalculationID, recovery.Amount,
+			recovery.Currency, recovery.RecordedBy, recovery.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert loss recovery: %w", err)
+		}
+		recovered[recovery.ProjectLossID] = recovery.Amount
+	}
+
+	now := time.Now()
+	for _, loss := range losses {
+		// Only apply the recovery on top of the amount it was calculated from
+		previous := loss.RecoveredAmount.Sub(recovered[loss.ID])
+		result, err := tx.ExecContext(ctx, `
+			UPDATE project_losses SET recovered_amount = $2, status = $3, updated_at = $4
+			WHERE id = $1 AND status = 'carried_forward' AND recovered_amount = $5
+		`, loss.ID, loss.RecoveredAmount, loss.Status, now, previous)
+		if err != nil {
+			return fmt.Errorf("failed to update project loss: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if affected != 1 {
+			return fmt.Errorf("carried-forward losses changed while recovering; retry")
+		}
+		loss.UpdatedAt = now
+	}
+
+	for _, share := range shares {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE investor_loss_shares SET recovered_amount = $2, updated_at = $3 WHERE id = $1
+		`, share.ID, share.RecoveredAmount, now)
+		if err != nil {
+			return fmt.Errorf("failed to update investor loss share: %w", err)
+		}
+		share.UpdatedAt = now
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit loss recoveries: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,304 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// ReinvestmentRepository stores investors' reinvestment preferences and the
+// reinvestments made under them. Both live on the cooperative's shard.
+type ReinvestmentRepository interface {
+	UpsertPreference(ctx context.Context, preference *entities.ReinvestmentPreference) error
+	GetPreference(ctx context.Context, cooperativeID, investorID uuid.UUID) (*entities.ReinvestmentPreference, error)
+	ListActivePreferences(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.ReinvestmentPreference, error)
+	DeactivatePreference(ctx context.Context, cooperativeID, investorID uuid.UUID) error
+
+	CreateReinvestments(ctx context.Context, reinvestments []*entities.Reinvestment) error
+	ListInvestorReinvestments(ctx context.Context, cooperativeID, investorID uuid.UUID) ([]*entities.Reinvestment, error)
+
+	// ListOpenProjects returns the cooperative's projects still accepting
+	// investment, the nearest funding deadline first
+	ListOpenProjects(ctx context.Context, cooperativeID uuid.UUID) ([]uuid.UUID, error)
+}
+
+type reinvestmentRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewReinvestmentRepository(shardMgr *database.ShardManager) ReinvestmentRepository {
+	return &reinvestmentRepository{shardMgr: shardMgr}
+}
+
+const reinvestmentPreferenceColumns = `id, investor_id, cooperative_id, percentage, target_type, target_project_id,
+	is_active, created_at, updated_at`
+
+const reinvestmentColumns = `id, preference_id, profit_distribution_id, profit_share_id, investor_id, cooperative_id,
+	source_project_id, target_project_id, investment_id, amount, currency, status, reason, created_at`
+
+func scanReinvestmentPreference(row interface{ Scan(...interface{}) error }) (*entities.ReinvestmentPreference, error) {
+	preference := &entities.ReinvestmentPreference{}
+	err := row.Scan(
+		&preference.ID, &preference.InvestorID, &preference.CooperativeID, &preference.Percentage,
+		&preference.TargetType, &preference.TargetProjectID, &preference.IsActive, &preference.CreatedAt,
+		&preference.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return preference, nil
+}
+
+func scanReinvestment(row interface{ Scan(...interface{}) error }) (*entities.Reinvestment, error) {
+	reinvestment := &entities.Reinvestment{}
+	var reason sql.NullString
+	err := row.Scan(
+		&reinvestment.ID, &reinvestment.PreferenceID, &reinvestment.ProfitDistributionID, &reinvestment.ProfitShareID,
+		&reinvestment.InvestorID, &reinvestment.CooperativeID, &reinvestment.SourceProjectID,
+		&reinvestment.TargetProjectID, &reinvestment.InvestmentID, &reinvestment.Amount, &reinvestment.Currency,
+		&reinvestment.Status, &reason, &reinvestment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reinvestment.Reason = reason.String
+	reinvestment.Amount = reinvestment.Amount.WithCurrency(reinvestment.Currency)
+	return reinvestment, nil
+}
+
+func (r *reinvestmentRepository) UpsertPreference(ctx context.Context, preference *entities.ReinvestmentPreference) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(preference.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	// An investor has one preference per cooperative; setting it again replaces it
+	row := shard.QueryRowContext(ctx, `
+		INSERT INTO reinvestment_preferences (`+reinvestmentPreferenceColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (investor_id, cooperative_id) DO UPDATE SET
+			percentage = EXCLUDED.percentage,
+			target_type = EXCLUDED.target_type,
+			target_project_id = EXCLUDED.target_project_id,
+			is_active = EXCLUDED.is_active,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`,
+		preference.ID, preference.InvestorID, preference.CooperativeID, preference.Percentage, preference.TargetType,
+		preference.TargetProjectID, preference.IsActive, preference.CreatedAt, preference.UpdatedAt)
+	if err := row.Scan(&preference.ID, &preference.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save reinvestment preference: %w", err)
+	}
+
+	return nil
+}
+
+func (r *reinvestmentRepository) GetPreference(ctx context.Context, cooperativeID, investorID uuid.UUID) (*entities.ReinvestmentPreference, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `SELECT ` + reinvestmentPreferenceColumns + ` FROM reinvestment_preferences
+		WHERE cooperative_id = $1 AND investor_id = $2 AND is_active = TRUE`
+
+	preference, err := scanReinvestmentPreference(shard.QueryRowContext(ctx, query, cooperativeID, investorID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reinvestment preference: %w", err)
+	}
+
+	return preference, nil
+}
+
+func (r *reinvestmentRepository) ListActivePreferences(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.ReinvestmentPreference, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+reinvestmentPreferenceColumns+`
+		FROM reinvestment_preferences
+		WHERE cooperative_id = $1 AND is_active = TRUE
+	`, cooperativeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reinvestment preferences: %w", err)
+	}
+	defer rows.Close()
+
+	var preferences []*entities.ReinvestmentPreference
+	for rows.Next() {
+		preference, err := scanReinvestmentPreference(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reinvestment preference: %w", err)
+		}
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, rows.Err()
+}
+
+func (r *reinvestmentRepository) DeactivatePreference(ctx context.Context, cooperativeID, investorID uuid.UUID) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE reinvestment_preferences SET is_active = FALSE, updated_at = $3
+		WHERE cooperative_id = $1 AND investor_id = $2 AND is_active = TRUE
+	`, cooperativeID, investorID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to deactivate reinvestment preference: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("reinvestment preference not found")
+	}
+
+	return nil
+}
+
+func (r *reinvestmentRepository) CreateReinvestments(ctx context.Context, reinvestments []*entities.Reinvestment) error {
+	if len(reinvestments) == 0 {
+		return nil
+	}
+
+	_, shardIndex, err := r.shardMgr.GetShardByCooperativeID(reinvestments[0].CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, reinvestment := range reinvestments {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO reinvestments (`+reinvestmentColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		`,
+			reinvestment.ID, reinvestment.PreferenceID, reinvestment.ProfitDistributionID, reinvestment.ProfitShareID,
+			reinvestment.InvestorID, reinvestment.CooperativeID, reinvestment.SourceProjectID,
+			reinvestment.TargetProjectID, reinvestment.InvestmentID, reinvestment.Amount, reinvestment.Currency,
+			reinvestment.Status, nullString(reinvestment.Reason), reinvestment.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert reinvestment for profit share %s: %w", reinvestment.ProfitShareID, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit reinvestments: %w", err)
+	}
+
+	return nil
+}
+
+func (r *reinvestmentRepository) ListInvestorReinvestments(ctx context.Context, cooperativeID, investorID uuid.UUID) ([]*entities.Reinvestment, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+reinvestmentColumns+`
+		FROM reinvestments
+		WHERE cooperative_id = $1 AND investor_id = $2
+		ORDER BY created_at DESC, id
+	`, cooperativeID, investorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reinvestments: %w", err)
+	}
+	defer rows.Close()
+
+	var reinvestments []*entities.Reinvestment
+	for rows.Next() {
+		reinvestment, err := scanReinvestment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reinvestment: %w", err)
+		}
+		reinvestments = append(reinvestments, reinvestment)
+	}
+
+	return reinvestments, rows.Err()
+}
+
+func (r *reinvestmentRepository) ListOpenProjects(ctx context.Context, cooperativeID uuid.UUID) ([]uuid.UUID, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	type openProject struct {
+		id       uuid.UUID
+		deadline sql.NullTime
+	}
+
+	// Projects are placed by their own ID, so look on every shard
+	var projects []openProject
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		rows, err := shard.QueryContext(ctx, `
+			SELECT p.id, p.funding_deadline
+			FROM projects p
+			JOIN businesses b ON b.id = p.business_id
+			WHERE b.cooperative_id = $1
+			  AND p.status = $2
+			  AND p.current_funding < p.funding_goal
+			  AND (p.funding_deadline IS NULL OR p.funding_deadline > NOW())
+		`, cooperativeID, entities.ProjectStatusActive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list open projects: %w", err)
+		}
+
+		for rows.Next() {
+			var project openProject
+			if err := rows.Scan(&project.id, &project.deadline); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan open project: %w", err)
+			}
+			projects = append(projects, project)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list open projects: %w", err)
+		}
+	}
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		a, b := projects[i].deadline, projects[j].deadline
+		if a.Valid != b.Valid {
+			return a.Valid
+		}
+		return a.Valid && a.Time.Before(b.Time)
+	})
+
+	ids := make([]uuid.UUID, len(projects))
+	for i, project := range projects {
+		ids[i] = project.id
+	}
+
+	return ids, nil
+}
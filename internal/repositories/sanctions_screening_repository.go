@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// SanctionsScreeningRepository stores sanctions screenings. Subjects are
+// screened before they belong to a cooperative, so screenings are platform-wide
+// compliance records replicated to every shard.
+type SanctionsScreeningRepository interface {
+	CreateScreening(ctx context.Context, screening *entities.SanctionsScreening) error
+	GetScreening(ctx context.Context, screeningID uuid.UUID) (*entities.SanctionsScreening, error)
+	ListScreenings(ctx context.Context, filter *entities.SanctionsScreeningFilter) ([]*entities.SanctionsScreening, int, error)
+	// ListSubjectReviews returns the subject's screenings that are pending or
+	// have been reviewed, newest first
+	ListSubjectReviews(ctx context.Context, subjectType, reference string) ([]*entities.SanctionsScreening, error)
+	// ReviewScreening saves the reviewer's decision on a screening pending review
+	ReviewScreening(ctx context.Context, screening *entities.SanctionsScreening) error
+}
+
+type sanctionsScreeningRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewSanctionsScreeningRepository(shardMgr *database.ShardManager) SanctionsScreeningRepository {
+	return &sanctionsScreeningRepository{shardMgr: shardMgr}
+}
+
+const sanctionsScreeningColumns = `id, subject_type, subject_reference, subject_id, subject_name, subject_date_of_birth,
+	purpose, cooperative_id, status, matches, reviewed_by, reviewed_at, review_notes, created_at, updated_at`
+
+func scanSanctionsScreening(row interface{ Scan(...interface{}) error }) (*entities.SanctionsScreening, error) {
+	s := &entities.SanctionsScreening{}
+	var matchesJSON []byte
+	var reviewNotes sql.NullString
+	err := row.Scan(
+		&s.ID, &s.SubjectType, &s.SubjectReference, &s.SubjectID, &s.SubjectName, &s.SubjectDateOfBirth,
+		&s.Purpose, &s.CooperativeID, &s.Status, &matchesJSON, &s.ReviewedBy, &s.ReviewedAt, &reviewNotes,
+		&s.CreatedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(matchesJSON, &s.Matches); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal screening matches: %w", err)
+	}
+	s.ReviewNotes = reviewNotes.String
+	return s, nil
+}
+
+func (r *sanctionsScreeningRepository) CreateScreening(ctx context.Context, screening *entities.SanctionsScreening) error {
+	matchesJSON, err := json.Marshal(screening.Matches)
+	if err != nil {
+		return fmt.Errorf("failed to marshal screening matches: %w", err)
+	}
+	if screening.Matches == nil {
+		matchesJSON = []byte("[]")
+	}
+
+	query := `
+		INSERT INTO sanctions_screenings (` + sanctionsScreeningColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	err = r.shardMgr.ExecuteOnAllShards(ctx, query,
+		screening.ID, screening.SubjectType, screening.SubjectReference, screening.SubjectID, screening.SubjectName,
+		screening.SubjectDateOfBirth, screening.Purpose, screening.CooperativeID, screening.Status, matchesJSON,
+		screening.ReviewedBy, screening.ReviewedAt, nullString(screening.ReviewNotes), screening.CreatedAt,
+		screening.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create sanctions screening: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sanctionsScreeningRepository) GetScreening(ctx context.Context, screeningID uuid.UUID) (*entities.SanctionsScreening, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, err
+	}
+
+	screening, err := scanSanctionsScreening(shard.QueryRowContext(ctx, `
+		SELECT `+sanctionsScreeningColumns+` FROM sanctions_screenings WHERE id = $1
+	`, screeningID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("sanctions screening not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sanctions screening: %w", err)
+	}
+
+	return screening, nil
+}
+
+func (r *sanctionsScreeningRepository) ListScreenings(ctx context.Context, filter *entities.SanctionsScreeningFilter) ([]*entities.SanctionsScreening, int, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	where := ` WHERE ($1::text IS NULL OR status = $1)
+		AND ($2::text IS NULL OR subject_reference = $2)`
+	args := []interface{}{nullString(filter.Status), nullString(filter.Reference)}
+
+	var total int
+	if err := shard.QueryRowContext(ctx, `SELECT COUNT(*) FROM sanctions_screenings`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count sanctions screenings: %w", err)
+	}
+
+	query := `SELECT ` + sanctionsScreeningColumns + ` FROM sanctions_screenings` + where + `
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := shard.QueryContext(ctx, query, append(args, filter.Limit, (filter.Page-1)*filter.Limit)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list sanctions screenings: %w", err)
+	}
+	defer rows.Close()
+
+	var screenings []*entities.SanctionsScreening
+	for rows.Next() {
+		screening, err := scanSanctionsScreening(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan sanctions screening: %w", err)
+		}
+		screenings = append(screenings, screening)
+	}
+
+	return screenings, total, rows.Err()
+}
+
+func (r *sanctionsScreeningRepository) ListSubjectReviews(ctx context.Context, subjectType, reference string) ([]*entities.SanctionsScreening, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + sanctionsScreeningColumns + ` FROM sanctions_screenings
+		WHERE subject_type = $1 AND subject_reference = $2 AND status IN ($3, $4, $5)
+		ORDER BY created_at DESC
+	`
+
+	rows, err := shard.QueryContext(ctx, query, subjectType, reference, entities.SanctionsScreeningStatusPendingReview,
+		entities.SanctionsScreeningStatusCleared, entities.SanctionsScreeningStatusConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subject screenings: %w", err)
+	}
+	defer rows.Close()
+
+	var screenings []*entities.SanctionsScreening
+	for rows.Next() {
+		screening, err := scanSanctionsScreening(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sanctions screening: %w", err)
+		}
+		screenings = append(screenings, screening)
+	}
+
+	return screenings, rows.Err()
+}
+
+func (r *sanctionsScreeningRepository) ReviewScreening(ctx context.Context, screening *entities.SanctionsScreening) error {
+	query := `
+		UPDATE sanctions_screenings
+		SET status = $2, reviewed_by = $3, reviewed_at = $4, review_notes = $5
+		WHERE id = $1 AND status = $6
+	`
+
+	err := r.shardMgr.ExecuteOnAllShards(ctx, query, screening.ID, screening.Status, screening.ReviewedBy,
+		screening.ReviewedAt, nullString(screening.ReviewNotes), entities.SanctionsScreeningStatusPendingReview)
+	if err != nil {
+		return fmt.Errorf("failed to review sanctions screening: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ShariaScreeningRepository stores cooperatives' Sharia screening rule sets and
+// reads the business activity of the projects they screen
+type ShariaScreeningRepository interface {
+	CreateRuleSet(ctx context.Context, ruleSet *entities.ShariaScreeningRuleSet) error
+	UpdateRuleSet(ctx context.Context, ruleSet *entities.ShariaScreeningRuleSet) error
+	GetRuleSet(ctx context.Context, cooperativeID, ruleSetID uuid.UUID) (*entities.ShariaScreeningRuleSet, error)
+	ListActiveRuleSets(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.ShariaScreeningRuleSet, error)
+	DeactivateRuleSet(ctx context.Context, cooperativeID, ruleSetID uuid.UUID) error
+
+	GetProjectActivity(ctx context.Context, projectID uuid.UUID) (*entities.ShariaProjectActivity, error)
+}
+
+type shariaScreeningRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewShariaScreeningRepository(shardMgr *database.ShardManager) ShariaScreeningRepository {
+	return &shariaScreeningRepository{shardMgr: shardMgr}
+}
+
+const shariaRuleSetColumns = `id, cooperative_id, supervisory_board, name, prohibited_sectors, prohibited_keywords,
+	max_debt_to_asset_ratio, max_interest_income_ratio, guaranteed_return_phrases, is_active, created_by,
+	created_at, updated_at`
+
+func scanShariaRuleSet(row interface{ Scan(...interface{}) error }) (*entities.ShariaScreeningRuleSet, error) {
+	ruleSet := &entities.ShariaScreeningRuleSet{}
+	var maxDebtToAsset, maxInterestIncome sql.NullFloat64
+	err := row.Scan(
+		&ruleSet.ID, &ruleSet.CooperativeID, &ruleSet.SupervisoryBoard, &ruleSet.Name,
+		pq.Array(&ruleSet.ProhibitedSectors), pq.Array(&ruleSet.ProhibitedKeywords),
+		&maxDebtToAsset, &maxInterestIncome, pq.Array(&ruleSet.GuaranteedReturnPhrases),
+		&ruleSet.IsActive, &ruleSet.CreatedBy, &ruleSet.CreatedAt, &ruleSet.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxDebtToAsset.Valid {
+		ruleSet.MaxDebtToAssetRatio = &maxDebtToAsset.Float64
+	}
+	if maxInterestIncome.Valid {
+		ruleSet.MaxInterestIncomeRatio = &maxInterestIncome.Float64
+	}
+	return ruleSet, nil
+}
+
+func (r *shariaScreeningRepository) CreateRuleSet(ctx context.Context, ruleSet *entities.ShariaScreeningRuleSet) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(ruleSet.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	_, err = shard.ExecContext(ctx, `
+		INSERT INTO sharia_screening_rule_sets (`+shariaRuleSetColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`,
+		ruleSet.ID, ruleSet.CooperativeID, ruleSet.SupervisoryBoard, ruleSet.Name,
+		pq.Array(ruleSet.ProhibitedSectors), pq.Array(ruleSet.ProhibitedKeywords),
+		ruleSet.MaxDebtToAssetRatio, ruleSet.MaxInterestIncomeRatio, pq.Array(ruleSet.GuaranteedReturnPhrases),
+		ruleSet.IsActive, ruleSet.CreatedBy, ruleSet.CreatedAt, ruleSet.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create sharia screening rule set: %w", err)
+	}
+
+	return nil
+}
+
+func (r *shariaScreeningRepository) UpdateRuleSet(ctx context.Context, ruleSet *entities.ShariaScreeningRuleSet) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(ruleSet.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE sharia_screening_rule_sets SET
+			supervisory_board = $3, name = $4, prohibited_sectors = $5, prohibited_keywords = $6,
+			max_debt_to_asset_ratio = $7, max_interest_income_ratio = $8, guaranteed_return_phrases = $9,
+			updated_at = $10
+		WHERE id = $1 AND cooperative_id = $2 AND is_active = TRUE
+	`,
+		ruleSet.ID, ruleSet.CooperativeID, ruleSet.SupervisoryBoard, ruleSet.Name,
+		pq.Array(ruleSet.ProhibitedSectors), pq.Array(ruleSet.ProhibitedKeywords),
+		ruleSet.MaxDebtToAssetRatio, ruleSet.MaxInterestIncomeRatio, pq.Array(ruleSet.GuaranteedReturnPhrases),
+		ruleSet.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update sharia screening rule set: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("sharia screening rule set not found")
+	}
+
+	return nil
+}
+
+func (r *shariaScreeningRepository) GetRuleSet(ctx context.Context, cooperativeID, ruleSetID uuid.UUID) (*entities.ShariaScreeningRuleSet, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `SELECT ` + shariaRuleSetColumns + ` FROM sharia_screening_rule_sets WHERE id = $1 AND cooperative_id = $2`
+
+	ruleSet, err := scanShariaRuleSet(shard.QueryRowContext(ctx, query, ruleSetID, cooperativeID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("sharia screening rule set not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sharia screening rule set: %w", err)
+	}
+
+	return ruleSet, nil
+}
+
+func (r *shariaScreeningRepository) ListActiveRuleSets(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.ShariaScreeningRuleSet, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+shariaRuleSetColumns+` FROM sharia_screening_rule_sets
+		WHERE cooperative_id = $1 AND is_active = TRUE
+		ORDER BY supervisory_board
+	`, cooperativeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sharia screening rule sets: %w", err)
+	}
+	defer rows.Close()
+
+	var ruleSets []*entities.ShariaScreeningRuleSet
+	for rows.Next() {
+		ruleSet, err := scanShariaRuleSet(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sharia screening rule set: %w", err)
+		}
+		ruleSets = append(ruleSets, ruleSet)
+	}
+
+	return ruleSets, rows.Err()
+}
+
+func (r *shariaScreeningRepository) DeactivateRuleSet(ctx context.Context, cooperativeID, ruleSetID uuid.UUID) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	_, err = shard.ExecContext(ctx, `
+		UPDATE sharia_screening_rule_sets SET is_active = FALSE, updated_at = NOW()
+		WHERE id = $1 AND cooperative_id = $2
+	`, ruleSetID, cooperativeID)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate sharia screening rule set: %w", err)
+	}
+
+	return nil
+}
+
+func (r *shariaScreeningRepository) GetProjectActivity(ctx context.Context, projectID uuid.UUID) (*entities.ShariaProjectActivity, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	activity := &entities.ShariaProjectActivity{}
+	var businessDescription sql.NullString
+	err = shard.QueryRowContext(ctx, `
+		SELECT p.id, b.cooperative_id, p.title, p.description, b.name, b.business_type, b.description
+		FROM projects p
+		JOIN businesses b ON b.id = p.business_id
+		WHERE p.id = $1
+	`, projectID).Scan(
+		&activity.ProjectID, &activity.CooperativeID, &activity.ProjectTitle, &activity.ProjectDescription,
+		&activity.BusinessName, &activity.BusinessType, &businessDescription,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("project not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project activity: %w", err)
+	}
+
+	activity.BusinessDescription = businessDescription.String
+	return activity, nil
+}
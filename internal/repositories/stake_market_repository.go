@@ -0,0 +1,715 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// StakeMarketRepository stores stake listings, bids and trades between members.
+// They live on the project's shard so that a trade can move capital between
+// the project's investments in one transaction.
+type StakeMarketRepository interface {
+	GetInvestmentPosition(ctx context.Context, projectID, investmentID uuid.UUID) (*entities.InvestmentPosition, error)
+	// GetInvestorPosition returns the investor's investment in the project, or
+	// nil if they have none
+	GetInvestorPosition(ctx context.Context, projectID, investorID uuid.UUID) (*entities.InvestmentPosition, error)
+
+	// CreateListing lists a stake; it fails if the investment is being withdrawn
+	// or its open listings would exceed the investment
+	CreateListing(ctx context.Context, listing *entities.StakeListing) error
+	GetListing(ctx context.Context, projectID, listingID uuid.UUID) (*entities.StakeListing, error)
+	ListOpenListings(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.StakeListing, error)
+	// UpdateListingStatus saves a listing's status if it is still in fromStatus,
+	// reporting whether it was
+	UpdateListingStatus(ctx context.Context, listing *entities.StakeListing, fromStatus string) (bool, error)
+
+	CreateBid(ctx context.Context, bid *entities.StakeBid) error
+	GetBid(ctx context.Context, projectID, bidID uuid.UUID) (*entities.StakeBid, error)
+	ListBids(ctx context.Context, projectID, listingID uuid.UUID) ([]*entities.StakeBid, error)
+	UpdateBidStatus(ctx context.Context, bid *entities.StakeBid, fromStatus string) (bool, error)
+
+	// CreateTrade records an agreed trade and takes its listing, and its bid if
+	// any, off the market until the cooperative decides
+	CreateTrade(ctx context.Context, trade *entities.StakeTrade) error
+	GetTrade(ctx context.Context, projectID, tradeID uuid.UUID) (*entities.StakeTrade, error)
+	ListCooperativeTrades(ctx context.Context, cooperativeID uuid.UUID, status string) ([]*entities.StakeTrade, error)
+	// RejectTrade records the cooperative's rejection and returns the listing
+	// to the market
+	RejectTrade(ctx context.Context, trade *entities.StakeTrade) error
+	// TransferTrade records the approval of a trade pending approval, moving it
+	// to completing, and in the same transaction moves the traded capital from
+	// the seller's investment to the buyer's and recalculates both members'
+	// ownership. BuyerInvestmentID and the ownership after the trade are filled
+	// in.
+	TransferTrade(ctx context.Context, trade *entities.StakeTrade) error
+	// FinishTrade marks a completing trade completed once its transfer has been
+	// recorded in the ledger
+	FinishTrade(ctx context.Context, trade *entities.StakeTrade) error
+	// ListCompletingTrades returns the trades whose capital has moved but whose
+	// transfer has not been recorded in the ledger
+	ListCompletingTrades(ctx context.Context) ([]*entities.StakeTrade, error)
+}
+
+type stakeMarketRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewStakeMarketRepository(shardMgr *database.ShardManager) StakeMarketRepository {
+	return &stakeMarketRepository{shardMgr: shardMgr}
+}
+
+const stakeListingColumns = `id, investment_id, seller_id, project_id, cooperative_id, currency, amount, ask_price,
+	status, created_at, updated_at`
+
+const stakeBidColumns = `id, listing_id, project_id, buyer_id, price, status, created_at, updated_at`
+
+const stakeTradeColumns = `id, listing_id, bid_id, project_id, cooperative_id, seller_id, buyer_id,
+	seller_investment_id, buyer_investment_id, currency, amount, price, status, payment_reference, reviewed_by,
+	reviewed_at, review_notes, seller_ownership_after, buyer_ownership_after, created_at, updated_at`
+
+func scanStakeListing(row interface{ Scan(...interface{}) error }) (*entities.StakeListing, error) {
+	listing := &entities.StakeListing{}
+	err := row.Scan(
+		&listing.ID, &listing.InvestmentID, &listing.SellerID, &listing.ProjectID, &listing.CooperativeID,
+		&listing.Currency, &listing.Amount, &listing.AskPrice, &listing.Status, &listing.CreatedAt, &listing.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	listing.Amount = listing.Amount.WithCurrency(listing.Currency)
+	listing.AskPrice = listing.AskPrice.WithCurrency(listing.Currency)
+	return listing, nil
+}
+
+func scanStakeBid(row interface{ Scan(...interface{}) error }) (*entities.StakeBid, error) {
+	bid := &entities.StakeBid{}
+	err := row.Scan(
+		&bid.ID, &bid.ListingID, &bid.ProjectID, &bid.BuyerID, &bid.Price, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return bid, nil
+}
+
+func scanStakeTrade(row interface{ Scan(...interface{}) error }) (*entities.StakeTrade, error) {
+	trade := &entities.StakeTrade{}
+	var paymentReference, reviewNotes sql.NullString
+	err := row.Scan(
+		&trade.ID, &trade.ListingID, &trade.BidID, &trade.ProjectID, &trade.CooperativeID, &trade.SellerID,
+		&trade.BuyerID, &trade.SellerInvestmentID, &trade.BuyerInvestmentID, &trade.Currency, &trade.Amount,
+		&trade.Price, &trade.Status, &paymentReference, &trade.ReviewedBy, &trade.ReviewedAt, &reviewNotes,
+		&trade.SellerOwnershipAfter, &trade.BuyerOwnershipAfter, &trade.CreatedAt, &trade.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	trade.PaymentReference = paymentReference.String
+	trade.ReviewNotes = reviewNotes.String
+	trade.Amount = trade.Amount.WithCurrency(trade.Currency)
+	trade.Price = trade.Price.WithCurrency(trade.Currency)
+	return trade, nil
+}
+
+func (r *stakeMarketRepository) GetInvestmentPosition(ctx context.Context, projectID, investmentID uuid.UUID) (*entities.InvestmentPosition, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	position, err := queryInvestmentPosition(ctx, shard, "i.id = $1 AND i.project_id = $2", investmentID, projectID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("investment not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get investment: %w", err)
+	}
+
+	return position, nil
+}
+
+func (r *stakeMarketRepository) GetInvestorPosition(ctx context.Context, projectID, investorID uuid.UUID) (*entities.InvestmentPosition, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	position, err := queryInvestmentPosition(ctx, shard, "i.project_id = $1 AND i.investor_id = $2", projectID, investorID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get investor's investment: %w", err)
+	}
+
+	return position, nil
+}
+
+func (r *stakeMarketRepository) CreateListing(ctx context.Context, listing *entities.StakeListing) error {
+	_, shardIndex, err := r.shardMgr.GetShardByID(listing.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var amount entities.Money
+	err = tx.QueryRowContext(ctx, `SELECT amount FROM investments WHERE id = $1 FOR UPDATE`, listing.InvestmentID).Scan(&amount)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("investment not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock investment: %w", err)
+	}
+	amount = amount.WithCurrency(listing.Currency)
+
+	var withdrawing bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM investment_withdrawals WHERE investment_id = $1 AND status IN ($2, $3, $4))
+	`, listing.InvestmentID, entities.InvestmentWithdrawalStatusRequested, entities.InvestmentWithdrawalStatusApproved,
+		entities.InvestmentWithdrawalStatusCompleting).Scan(&withdrawing)
+	if err != nil {
+		return fmt.Errorf("failed to check withdrawals: %w", err)
+	}
+	if withdrawing {
+		return fmt.Errorf("investment is being withdrawn and cannot be listed")
+	}
+
+	var listed entities.Money
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM stake_listings WHERE investment_id = $1 AND status IN ($2, $3)
+	`, listing.InvestmentID, entities.StakeListingStatusOpen, entities.StakeListingStatusPendingApproval).Scan(&listed)
+	if err != nil {
+		return fmt.Errorf("failed to sum open listings: %w", err)
+	}
+	if available := amount.Sub(listed.WithCurrency(listing.Currency)); available.LessThan(listing.Amount) {
+		return fmt.Errorf("only %s of the investment is not already listed", available)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO stake_listings (`+stakeListingColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`,
+		listing.ID, listing.InvestmentID, listing.SellerID, listing.ProjectID, listing.CooperativeID, listing.Currency,
+		listing.Amount, listing.AskPrice, listing.Status, listing.CreatedAt, listing.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create stake listing: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit stake listing: %w", err)
+	}
+
+	return nil
+}
+
+func (r *stakeMarketRepository) GetListing(ctx context.Context, projectID, listingID uuid.UUID) (*entities.StakeListing, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	listing, err := scanStakeListing(shard.QueryRowContext(ctx, `
+		SELECT `+stakeListingColumns+`
+		FROM stake_listings
+		WHERE id = $1 AND project_id = $2
+	`, listingID, projectID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("stake listing not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stake listing: %w", err)
+	}
+
+	return listing, nil
+}
+
+func (r *stakeMarketRepository) ListOpenListings(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.StakeListing, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	var listings []*entities.StakeListing
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		rows, err := shard.QueryContext(ctx, `
+			SELECT `+stakeListingColumns+`
+			FROM stake_listings
+			WHERE cooperative_id = $1 AND status = $2
+			ORDER BY created_at
+		`, cooperativeID, entities.StakeListingStatusOpen)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stake listings: %w", err)
+		}
+
+		for rows.Next() {
+			listing, err := scanStakeListing(rows)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan stake listing: %w", err)
+			}
+			listings = append(listings, listing)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stake listings: %w", err)
+		}
+	}
+
+	return listings, nil
+}
+
+func (r *stakeMarketRepository) UpdateListingStatus(ctx context.Context, listing *entities.StakeListing, fromStatus string) (bool, error) {
+	shard, _, err := r.shardMgr.GetShardByID(listing.ProjectID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE stake_listings SET status = $3, updated_at = $4 WHERE id = $1 AND status = $2
+	`, listing.ID, fromStatus, listing.Status, listing.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to update stake listing: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+func (r *stakeMarketRepository) CreateBid(ctx context.Context, bid *entities.StakeBid) error {
+	shard, _, err := r.shardMgr.GetShardByID(bid.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	_, err = shard.ExecContext(ctx, `
+		INSERT INTO stake_bids (`+stakeBidColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, bid.ID, bid.ListingID, bid.ProjectID, bid.BuyerID, bid.Price, bid.Status, bid.CreatedAt, bid.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create stake bid: %w", err)
+	}
+
+	return nil
+}
+
+func (r *stakeMarketRepository) GetBid(ctx context.Context, projectID, bidID uuid.UUID) (*entities.StakeBid, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	bid, err := scanStakeBid(shard.QueryRowContext(ctx, `
+		SELECT `+stakeBidColumns+`
+		FROM stake_bids
+		WHERE id = $1 AND project_id = $2
+	`, bidID, projectID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("stake bid not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stake bid: %w", err)
+	}
+
+	return bid, nil
+}
+
+func (r *stakeMarketRepository) ListBids(ctx context.Context, projectID, listingID uuid.UUID) ([]*entities.StakeBid, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+stakeBidColumns+`
+		FROM stake_bids
+		WHERE listing_id = $1
+		ORDER BY price DESC, created_at
+	`, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stake bids: %w", err)
+	}
+	defer rows.Close()
+
+	var bids []*entities.StakeBid
+	for rows.Next() {
+		bid, err := scanStakeBid(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stake bid: %w", err)
+		}
+		bids = append(bids, bid)
+	}
+
+	return bids, rows.Err()
+}
+
+func (r *stakeMarketRepository) UpdateBidStatus(ctx context.Context, bid *entities.StakeBid, fromStatus string) (bool, error) {
+	shard, _, err := r.shardMgr.GetShardByID(bid.ProjectID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE stake_bids SET status = $3, updated_at = $4 WHERE id = $1 AND status = $2
+	`, bid.ID, fromStatus, bid.Status, bid.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to update stake bid: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+func (r *stakeMarketRepository) CreateTrade(ctx context.Context, trade *entities.StakeTrade) error {
+	_, shardIndex, err := r.shardMgr.GetShardByID(trade.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := execOne(ctx, tx, "stake listing is no longer open", `
+		UPDATE stake_listings SET status = $3, updated_at = $4 WHERE id = $1 AND status = $2
+	`, trade.ListingID, entities.StakeListingStatusOpen, entities.StakeListingStatusPendingApproval, trade.CreatedAt); err != nil {
+		return err
+	}
+
+	if trade.BidID != nil {
+		if err := execOne(ctx, tx, "stake bid is no longer open", `
+			UPDATE stake_bids SET status = $3, updated_at = $4 WHERE id = $1 AND status = $2
+		`, *trade.BidID, entities.StakeBidStatusOpen, entities.StakeBidStatusAccepted, trade.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO stake_trades (`+stakeTradeColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+	`,
+		trade.ID, trade.ListingID, trade.BidID, trade.ProjectID, trade.CooperativeID, trade.SellerID, trade.BuyerID,
+		trade.SellerInvestmentID, trade.BuyerInvestmentID, trade.Currency, trade.Amount, trade.Price, trade.Status,
+		nullString(trade.PaymentReference), trade.ReviewedBy, trade.ReviewedAt, nullString(trade.ReviewNotes),
+		trade.SellerOwnershipAfter, trade.BuyerOwnershipAfter, trade.CreatedAt, trade.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create stake trade: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit stake trade: %w", err)
+	}
+
+	return nil
+}
+
+func (r *stakeMarketRepository) GetTrade(ctx context.Context, projectID, tradeID uuid.UUID) (*entities.StakeTrade, error) {
+	shard, _, err := r.shardMgr.GetShardByID(projectID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	trade, err := scanStakeTrade(shard.QueryRowContext(ctx, `
+		SELECT `+stakeTradeColumns+`
+		FROM stake_trades
+		WHERE id = $1 AND project_id = $2
+	`, tradeID, projectID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("stake trade not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stake trade: %w", err)
+	}
+
+	return trade, nil
+}
+
+func (r *stakeMarketRepository) ListCooperativeTrades(ctx context.Context, cooperativeID uuid.UUID, status string) ([]*entities.StakeTrade, error) {
+	return r.listTrades(ctx, `
+		SELECT `+stakeTradeColumns+`
+		FROM stake_trades
+		WHERE cooperative_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at
+	`, cooperativeID, status)
+}
+
+func (r *stakeMarketRepository) ListCompletingTrades(ctx context.Context) ([]*entities.StakeTrade, error) {
+	return r.listTrades(ctx, `
+		SELECT `+stakeTradeColumns+`
+		FROM stake_trades
+		WHERE status = $1
+		ORDER BY updated_at
+	`, entities.StakeTradeStatusCompleting)
+}
+
+func (r *stakeMarketRepository) listTrades(ctx context.Context, query string, args ...interface{}) ([]*entities.StakeTrade, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	var trades []*entities.StakeTrade
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		rows, err := shard.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stake trades: %w", err)
+		}
+
+		for rows.Next() {
+			trade, err := scanStakeTrade(rows)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan stake trade: %w", err)
+			}
+			trades = append(trades, trade)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stake trades: %w", err)
+		}
+	}
+
+	return trades, nil
+}
+
+func (r *stakeMarketRepository) RejectTrade(ctx context.Context, trade *entities.StakeTrade) error {
+	_, shardIndex, err := r.shardMgr.GetShardByID(trade.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.reviewTrade(ctx, tx, trade, entities.StakeTradeStatusPendingApproval); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE stake_listings SET status = $3, updated_at = $4 WHERE id = $1 AND status = $2
+	`, trade.ListingID, entities.StakeListingStatusPendingApproval, entities.StakeListingStatusOpen, trade.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to reopen stake listing: %w", err)
+	}
+
+	if trade.BidID != nil {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE stake_bids SET status = $3, updated_at = $4 WHERE id = $1 AND status = $2
+		`, *trade.BidID, entities.StakeBidStatusAccepted, entities.StakeBidStatusRejected, trade.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to reject stake bid: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit stake trade rejection: %w", err)
+	}
+
+	return nil
+}
+
+func (r *stakeMarketRepository) TransferTrade(ctx context.Context, trade *entities.StakeTrade) error {
+	_, shardIndex, err := r.shardMgr.GetShardByID(trade.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the project first, as withdrawals do, so ownership is computed
+	// against a stable funding total
+	var funding entities.Money
+	err = tx.QueryRowContext(ctx, `SELECT current_funding FROM projects WHERE id = $1 FOR UPDATE`, trade.ProjectID).Scan(&funding)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("project not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock project: %w", err)
+	}
+	if !funding.IsPositive() {
+		return fmt.Errorf("project has no funding to trade")
+	}
+
+	var sellerAmount entities.Money
+	var sellerStatus string
+	err = tx.QueryRowContext(ctx, `
+		SELECT amount, status FROM investments WHERE id = $1 FOR UPDATE
+	`, trade.SellerInvestmentID).Scan(&sellerAmount, &sellerStatus)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("seller's investment not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock seller's investment: %w", err)
+	}
+	sellerAmount = sellerAmount.WithCurrency(trade.Currency)
+	if sellerStatus != "confirmed" || sellerAmount.LessThan(trade.Amount) {
+		return fmt.Errorf("seller no longer holds the %s stake", trade.Amount)
+	}
+
+	if err := r.reviewTrade(ctx, tx, trade, entities.StakeTradeStatusPendingApproval); err != nil {
+		return err
+	}
+
+	if sellerAmount.Equal(trade.Amount) {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE investments SET status = 'transferred', profit_sharing_percentage = 0, updated_at = $2 WHERE id = $1
+		`, trade.SellerInvestmentID, trade.UpdatedAt)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE investments SET
+				amount = amount - $2, profit_sharing_percentage = ROUND((amount - $2) * 100 / $3, 2), updated_at = $4
+			WHERE id = $1
+		`, trade.SellerInvestmentID, trade.Amount, funding, trade.UpdatedAt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reduce seller's investment: %w", err)
+	}
+
+	// A buyer may add to their existing stake, or take the place of an
+	// investment they have since exited
+	var buyerInvestmentID uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO investments (id, project_id, investor_id, amount, profit_sharing_percentage, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, ROUND($4 * 100 / $5, 2), 'confirmed', $6, $6)
+		ON CONFLICT (project_id, investor_id) DO UPDATE SET
+			amount = CASE WHEN investments.status = 'confirmed' THEN investments.amount + EXCLUDED.amount ELSE EXCLUDED.amount END,
+			profit_sharing_percentage = ROUND(
+				CASE WHEN investments.status = 'confirmed' THEN investments.amount + EXCLUDED.amount ELSE EXCLUDED.amount END * 100 / $5, 2),
+			status = 'confirmed', updated_at = EXCLUDED.updated_at
+		WHERE investments.status <> 'pending'
+		RETURNING id, profit_sharing_percentage
+	`, uuid.New(), trade.ProjectID, trade.BuyerID, trade.Amount, funding, trade.UpdatedAt).Scan(&buyerInvestmentID, &trade.BuyerOwnershipAfter)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("buyer has a pending investment in the project")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to credit buyer's investment: %w", err)
+	}
+	trade.BuyerInvestmentID = &buyerInvestmentID
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT profit_sharing_percentage FROM investments WHERE id = $1
+	`, trade.SellerInvestmentID).Scan(&trade.SellerOwnershipAfter)
+	if err != nil {
+		return fmt.Errorf("failed to get seller's remaining ownership: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE stake_trades SET buyer_investment_id = $2, seller_ownership_after = $3, buyer_ownership_after = $4
+		WHERE id = $1
+	`, trade.ID, trade.BuyerInvestmentID, trade.SellerOwnershipAfter, trade.BuyerOwnershipAfter)
+	if err != nil {
+		return fmt.Errorf("failed to record ownership change: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE stake_listings SET status = $2, updated_at = $3 WHERE id = $1
+	`, trade.ListingID, entities.StakeListingStatusSold, trade.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to close stake listing: %w", err)
+	}
+
+	// Bids left on the sold listing can no longer be filled
+	_, err = tx.ExecContext(ctx, `
+		UPDATE stake_bids SET status = $3, updated_at = $4 WHERE listing_id = $1 AND status = $2
+	`, trade.ListingID, entities.StakeBidStatusOpen, entities.StakeBidStatusRejected, trade.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to close stake bids: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit stake trade: %w", err)
+	}
+
+	return nil
+}
+
+func (r *stakeMarketRepository) FinishTrade(ctx context.Context, trade *entities.StakeTrade) error {
+	shard, _, err := r.shardMgr.GetShardByID(trade.ProjectID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE stake_trades SET status = $3, updated_at = $4 WHERE id = $1 AND status = $2
+	`, trade.ID, entities.StakeTradeStatusCompleting, entities.StakeTradeStatusCompleted, trade.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to finish stake trade: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	} else if affected != 1 {
+		return fmt.Errorf("stake trade is no longer being completed")
+	}
+
+	return nil
+}
+
+// reviewTrade saves the cooperative's decision on a trade still in fromStatus
+func (r *stakeMarketRepository) reviewTrade(ctx context.Context, tx *sql.Tx, trade *entities.StakeTrade, fromStatus string) error {
+	return execOne(ctx, tx, "stake trade is no longer "+fromStatus, `
+		UPDATE stake_trades SET
+			status = $3, payment_reference = $4, reviewed_by = $5, reviewed_at = $6, review_notes = $7, updated_at = $8
+		WHERE id = $1 AND status = $2
+	`,
+		trade.ID, fromStatus, trade.Status, nullString(trade.PaymentReference),
+		trade.ReviewedBy, trade.ReviewedAt, nullString(trade.ReviewNotes), trade.UpdatedAt)
+}
+
+// execOne runs an update that must affect exactly one row, failing with
+// conflict otherwise
+func execOne(ctx context.Context, tx *sql.Tx, conflict string, query string, args ...interface{}) error {
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected != 1 {
+		return fmt.Errorf("%s", conflict)
+	}
+
+	return nil
+}
@@ -0,0 +1,346 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// TaxRepository stores withholding rates, the jurisdiction each cooperative
+// withholds under, investors' tax profiles and the tax withheld from
+// distributions
+type TaxRepository interface {
+	// Withholding rates are replicated to every shard
+	CreateRate(ctx context.Context, rate *entities.TaxWithholdingRate) error
+	DeactivateRate(ctx context.Context, rateID uuid.UUID) error
+	GetRate(ctx context.Context, rateID uuid.UUID) (*entities.TaxWithholdingRate, error)
+	ListRates(ctx context.Context, jurisdiction string) ([]*entities.TaxWithholdingRate, error)
+
+	// GetCooperativeJurisdiction returns an empty string if the cooperative has not set one
+	GetCooperativeJurisdiction(ctx context.Context, cooperativeID uuid.UUID) (string, error)
+	SetCooperativeJurisdiction(ctx context.Context, cooperativeID uuid.UUID, jurisdiction string, userID uuid.UUID) error
+
+	// GetInvestorProfile returns nil without an error if the investor has no profile
+	GetInvestorProfile(ctx context.Context, investorID uuid.UUID) (*entities.InvestorTaxProfile, error)
+	SaveInvestorProfile(ctx context.Context, profile *entities.InvestorTaxProfile) error
+
+	// CreateWithholdings records a distribution's withholdings in one transaction
+	CreateWithholdings(ctx context.Context, cooperativeID uuid.UUID, withholdings []*entities.TaxWithholding) error
+	ListWithholdings(ctx context.Context, cooperativeID uuid.UUID, filter *entities.TaxWithholdingFilter) ([]*entities.TaxWithholding, error)
+	// SummarizeWithholdings totals withholdings in the filter's currency by investor type and rate
+	SummarizeWithholdings(ctx context.Context, cooperativeID uuid.UUID, filter *entities.TaxWithholdingFilter) ([]entities.TaxWithholdingReportLine, error)
+}
+
+type taxRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewTaxRepository(shardMgr *database.ShardManager) TaxRepository {
+	return &taxRepository{shardMgr: shardMgr}
+}
+
+const taxWithholdingRateColumns = `id, jurisdiction, investor_type, has_tax_id, rate, effective_from, effective_to,
+	description, is_active, created_by, created_at, updated_at`
+
+const taxWithholdingColumns = `id, distribution_id, cooperative_id, project_id, investment_id, investor_id,
+	jurisdiction, investor_type, has_tax_id, rate_id, rate, taxable_amount, tax_amount, currency, withheld_at`
+
+// taxWithholdingWhere filters a cooperative's withholdings; $1 is the cooperative
+const taxWithholdingWhere = ` WHERE cooperative_id = $1
+	AND ($2::uuid IS NULL OR distribution_id = $2)
+	AND ($3::timestamptz IS NULL OR withheld_at >= $3)
+	AND ($4::timestamptz IS NULL OR withheld_at < $4)
+	AND ($5::text IS NULL OR currency = $5)`
+
+func scanTaxWithholdingRate(row interface{ Scan(...interface{}) error }) (*entities.TaxWithholdingRate, error) {
+	rate := &entities.TaxWithholdingRate{}
+	var description sql.NullString
+	err := row.Scan(
+		&rate.ID, &rate.Jurisdiction, &rate.InvestorType, &rate.HasTaxID, &rate.Rate, &rate.EffectiveFrom,
+		&rate.EffectiveTo, &description, &rate.IsActive, &rate.CreatedBy, &rate.CreatedAt, &rate.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rate.Description = description.String
+	return rate, nil
+}
+
+func scanTaxWithholding(row interface{ Scan(...interface{}) error }) (*entities.TaxWithholding, error) {
+	w := &entities.TaxWithholding{}
+	err := row.Scan(
+		&w.ID, &w.DistributionID, &w.CooperativeID, &w.ProjectID, &w.InvestmentID, &w.InvestorID,
+		&w.Jurisdiction, &w.InvestorType, &w.HasTaxID, &w.RateID, &w.Rate, &w.TaxableAmount, &w.TaxAmount,
+		&w.Currency, &w.WithheldAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	w.TaxableAmount = w.TaxableAmount.WithCurrency(w.Currency)
+	w.TaxAmount = w.TaxAmount.WithCurrency(w.Currency)
+	return w, nil
+}
+
+func (r *taxRepository) CreateRate(ctx context.Context, rate *entities.TaxWithholdingRate) error {
+	query := `
+		INSERT INTO tax_withholding_rates (` + taxWithholdingRateColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	err := r.shardMgr.ExecuteOnAllShards(ctx, query,
+		rate.ID, rate.Jurisdiction, rate.InvestorType, rate.HasTaxID, rate.Rate, rate.EffectiveFrom,
+		rate.EffectiveTo, nullString(rate.Description), rate.IsActive, rate.CreatedBy, rate.CreatedAt, rate.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create withholding rate: %w", err)
+	}
+
+	return nil
+}
+
+func (r *taxRepository) DeactivateRate(ctx context.Context, rateID uuid.UUID) error {
+	query := `UPDATE tax_withholding_rates SET is_active = false WHERE id = $1`
+
+	if err := r.shardMgr.ExecuteOnAllShards(ctx, query, rateID); err != nil {
+		return fmt.Errorf("failed to deactivate withholding rate: %w", err)
+	}
+
+	return nil
+}
+
+func (r *taxRepository) GetRate(ctx context.Context, rateID uuid.UUID) (*entities.TaxWithholdingRate, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err := scanTaxWithholdingRate(shard.QueryRowContext(ctx, `
+		SELECT `+taxWithholdingRateColumns+` FROM tax_withholding_rates WHERE id = $1
+	`, rateID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("withholding rate not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get withholding rate: %w", err)
+	}
+
+	return rate, nil
+}
+
+func (r *taxRepository) ListRates(ctx context.Context, jurisdiction string) ([]*entities.TaxWithholdingRate, error) {
+	shard, err := r.shardMgr.GetReadShard()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+taxWithholdingRateColumns+`
+		FROM tax_withholding_rates
+		WHERE jurisdiction = $1 AND is_active = true
+		ORDER BY investor_type, has_tax_id, effective_from DESC
+	`, jurisdiction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list withholding rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []*entities.TaxWithholdingRate
+	for rows.Next() {
+		rate, err := scanTaxWithholdingRate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan withholding rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, rows.Err()
+}
+
+func (r *taxRepository) GetCooperativeJurisdiction(ctx context.Context, cooperativeID uuid.UUID) (string, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	var jurisdiction string
+	err = shard.QueryRowContext(ctx, `
+		SELECT jurisdiction FROM cooperative_tax_jurisdictions WHERE cooperative_id = $1
+	`, cooperativeID).Scan(&jurisdiction)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get cooperative tax jurisdiction: %w", err)
+	}
+
+	return jurisdiction, nil
+}
+
+func (r *taxRepository) SetCooperativeJurisdiction(ctx context.Context, cooperativeID uuid.UUID, jurisdiction string, userID uuid.UUID) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	_, err = shard.ExecContext(ctx, `
+		INSERT INTO cooperative_tax_jurisdictions (cooperative_id, jurisdiction, updated_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (cooperative_id) DO UPDATE SET
+			jurisdiction = EXCLUDED.jurisdiction,
+			updated_by = EXCLUDED.updated_by
+	`, cooperativeID, jurisdiction, userID)
+	if err != nil {
+		return fmt.Errorf("failed to set cooperative tax jurisdiction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *taxRepository) GetInvestorProfile(ctx context.Context, investorID uuid.UUID) (*entities.InvestorTaxProfile, error) {
+	shard, _, err := r.shardMgr.GetShardByID(investorID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	profile := &entities.InvestorTaxProfile{}
+	var taxID sql.NullString
+	err = shard.QueryRowContext(ctx, `
+		SELECT investor_id, investor_type, tax_id, tax_residence, created_at, updated_at
+		FROM investor_tax_profiles
+		WHERE investor_id = $1
+	`, investorID).Scan(&profile.InvestorID, &profile.InvestorType, &taxID, &profile.TaxResidence,
+		&profile.CreatedAt, &profile.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get investor tax profile: %w", err)
+	}
+
+	profile.TaxID = taxID.String
+	return profile, nil
+}
+
+func (r *taxRepository) SaveInvestorProfile(ctx context.Context, profile *entities.InvestorTaxProfile) error {
+	shard, _, err := r.shardMgr.GetShardByID(profile.InvestorID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	err = shard.QueryRowContext(ctx, `
+		INSERT INTO investor_tax_profiles (investor_id, investor_type, tax_id, tax_residence)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (investor_id) DO UPDATE SET
+			investor_type = EXCLUDED.investor_type,
+			tax_id = EXCLUDED.tax_id,
+			tax_residence = EXCLUDED.tax_residence
+		RETURNING created_at, updated_at
+	`, profile.InvestorID, profile.InvestorType, nullString(profile.TaxID), profile.TaxResidence).
+		Scan(&profile.CreatedAt, &profile.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save investor tax profile: %w", err)
+	}
+
+	return nil
+}
+
+func (r *taxRepository) CreateWithholdings(ctx context.Context, cooperativeID uuid.UUID, withholdings []*entities.TaxWithholding) error {
+	_, shardIndex, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	tx, err := r.shardMgr.BeginTxOnShard(ctx, shardIndex)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, w := range withholdings {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO tax_withholdings (`+taxWithholdingColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		`, w.ID, w.DistributionID, w.CooperativeID, w.ProjectID, w.InvestmentID, w.InvestorID, w.Jurisdiction,
+			w.InvestorType, w.HasTaxID, w.RateID, w.Rate, w.TaxableAmount, w.TaxAmount, w.Currency, w.WithheldAt)
+		if err != nil {
+			return fmt.Errorf("failed to record tax withholding: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tax withholdings: %w", err)
+	}
+
+	return nil
+}
+
+func (r *taxRepository) ListWithholdings(ctx context.Context, cooperativeID uuid.UUID, filter *entities.TaxWithholdingFilter) ([]*entities.TaxWithholding, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT `+taxWithholdingColumns+`
+		FROM tax_withholdings`+taxWithholdingWhere+`
+		ORDER BY withheld_at, investor_id
+	`, taxWithholdingArgs(cooperativeID, filter)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tax withholdings: %w", err)
+	}
+	defer rows.Close()
+
+	var withholdings []*entities.TaxWithholding
+	for rows.Next() {
+		w, err := scanTaxWithholding(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tax withholding: %w", err)
+		}
+		withholdings = append(withholdings, w)
+	}
+
+	return withholdings, rows.Err()
+}
+
+func (r *taxRepository) SummarizeWithholdings(ctx context.Context, cooperativeID uuid.UUID, filter *entities.TaxWithholdingFilter) ([]entities.TaxWithholdingReportLine, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	rows, err := shard.QueryContext(ctx, `
+		SELECT investor_type, has_tax_id, rate, COUNT(*), COUNT(DISTINCT investor_id),
+		       COALESCE(SUM(taxable_amount), 0), COALESCE(SUM(tax_amount), 0)
+		FROM tax_withholdings`+taxWithholdingWhere+`
+		GROUP BY investor_type, has_tax_id, rate
+		ORDER BY investor_type, has_tax_id, rate
+	`, taxWithholdingArgs(cooperativeID, filter)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize tax withholdings: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []entities.TaxWithholdingReportLine
+	for rows.Next() {
+		var line entities.TaxWithholdingReportLine
+		if err := rows.Scan(&line.InvestorType, &line.HasTaxID, &line.Rate, &line.WithholdingCount,
+			&line.InvestorCount, &line.TaxableAmount, &line.TaxAmount); err != nil {
+			return nil, fmt.Errorf("failed to scan tax withholding summary: %w", err)
+		}
+		line.TaxableAmount = line.TaxableAmount.WithCurrency(filter.Currency)
+		line.TaxAmount = line.TaxAmount.WithCurrency(filter.Currency)
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+func taxWithholdingArgs(cooperativeID uuid.UUID, filter *entities.TaxWithholdingFilter) []interface{} {
+	return []interface{}{cooperativeID, filter.DistributionID, filter.Start, filter.End, nullString(filter.Currency)}
+}
@@ -0,0 +1,217 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"comfunds/internal/database"
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// ZakatRepository stores cooperatives' zakat recipient accounts and investors'
+// zakat payments to them. Both live on the cooperative's shard.
+type ZakatRepository interface {
+	UpsertRecipient(ctx context.Context, recipient *entities.ZakatRecipient) error
+	GetRecipient(ctx context.Context, cooperativeID uuid.UUID) (*entities.ZakatRecipient, error)
+
+	CreatePayment(ctx context.Context, payment *entities.ZakatPayment) error
+	GetPayment(ctx context.Context, cooperativeID, paymentID uuid.UUID) (*entities.ZakatPayment, error)
+	// UpdatePaymentStatus saves the payment only if it is still in fromStatus
+	UpdatePaymentStatus(ctx context.Context, payment *entities.ZakatPayment, fromStatus string) (bool, error)
+	ListInvestorPayments(ctx context.Context, investorID uuid.UUID) ([]*entities.ZakatPayment, error)
+}
+
+type zakatRepository struct {
+	shardMgr *database.ShardManager
+}
+
+func NewZakatRepository(shardMgr *database.ShardManager) ZakatRepository {
+	return &zakatRepository{shardMgr: shardMgr}
+}
+
+const zakatRecipientColumns = `cooperative_id, name, account_name, account_number, bank_code, is_active, updated_by,
+	created_at, updated_at`
+
+const zakatPaymentColumns = `id, investor_id, cooperative_id, recipient_name, recipient_account, recipient_bank_code,
+	amount, currency, reference, status, bank_reference, paid_at, confirmed_by, created_at, updated_at`
+
+func scanZakatPayment(row interface{ Scan(...interface{}) error }) (*entities.ZakatPayment, error) {
+	payment := &entities.ZakatPayment{}
+	var bankReference sql.NullString
+	err := row.Scan(
+		&payment.ID, &payment.InvestorID, &payment.CooperativeID, &payment.RecipientName, &payment.RecipientAccount,
+		&payment.RecipientBankCode, &payment.Amount, &payment.Currency, &payment.Reference, &payment.Status,
+		&bankReference, &payment.PaidAt, &payment.ConfirmedBy, &payment.CreatedAt, &payment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	payment.BankReference = bankReference.String
+	payment.Amount = payment.Amount.WithCurrency(payment.Currency)
+	return payment, nil
+}
+
+func (r *zakatRepository) UpsertRecipient(ctx context.Context, recipient *entities.ZakatRecipient) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(recipient.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	row := shard.QueryRowContext(ctx, `
+		INSERT INTO zakat_recipients (`+zakatRecipientColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (cooperative_id) DO UPDATE SET
+			name = EXCLUDED.name,
+			account_name = EXCLUDED.account_name,
+			account_number = EXCLUDED.account_number,
+			bank_code = EXCLUDED.bank_code,
+			is_active = EXCLUDED.is_active,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`,
+		recipient.CooperativeID, recipient.Name, recipient.AccountName, recipient.AccountNumber, recipient.BankCode,
+		recipient.IsActive, recipient.UpdatedBy, recipient.CreatedAt, recipient.UpdatedAt)
+	if err := row.Scan(&recipient.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save zakat recipient: %w", err)
+	}
+
+	return nil
+}
+
+func (r *zakatRepository) GetRecipient(ctx context.Context, cooperativeID uuid.UUID) (*entities.ZakatRecipient, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	recipient := &entities.ZakatRecipient{}
+	err = shard.QueryRowContext(ctx, `
+		SELECT `+zakatRecipientColumns+` FROM zakat_recipients WHERE cooperative_id = $1 AND is_active = TRUE
+	`, cooperativeID).Scan(
+		&recipient.CooperativeID, &recipient.Name, &recipient.AccountName, &recipient.AccountNumber,
+		&recipient.BankCode, &recipient.IsActive, &recipient.UpdatedBy, &recipient.CreatedAt, &recipient.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get zakat recipient: %w", err)
+	}
+
+	return recipient, nil
+}
+
+func (r *zakatRepository) CreatePayment(ctx context.Context, payment *entities.ZakatPayment) error {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(payment.CooperativeID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	_, err = shard.ExecContext(ctx, `
+		INSERT INTO zakat_payments (`+zakatPaymentColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`,
+		payment.ID, payment.InvestorID, payment.CooperativeID, payment.RecipientName, payment.RecipientAccount,
+		payment.RecipientBankCode, payment.Amount, payment.Currency, payment.Reference, payment.Status,
+		nullString(payment.BankReference), payment.PaidAt, payment.ConfirmedBy, payment.CreatedAt, payment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create zakat payment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *zakatRepository) GetPayment(ctx context.Context, cooperativeID, paymentID uuid.UUID) (*entities.ZakatPayment, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(cooperativeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	query := `SELECT ` + zakatPaymentColumns + ` FROM zakat_payments WHERE id = $1 AND cooperative_id = $2`
+
+	payment, err := scanZakatPayment(shard.QueryRowContext(ctx, query, paymentID, cooperativeID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("zakat payment not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get zakat payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+func (r *zakatRepository) UpdatePaymentStatus(ctx context.Context, payment *entities.ZakatPayment, fromStatus string) (bool, error) {
+	shard, _, err := r.shardMgr.GetShardByCooperativeID(payment.CooperativeID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	payment.UpdatedAt = time.Now()
+
+	result, err := shard.ExecContext(ctx, `
+		UPDATE zakat_payments SET
+			status = $3, bank_reference = $4, paid_at = $5, confirmed_by = $6, updated_at = $7
+		WHERE id = $1 AND status = $2
+	`,
+		payment.ID, fromStatus, payment.Status, nullString(payment.BankReference), payment.PaidAt,
+		payment.ConfirmedBy, payment.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to update zakat payment: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *zakatRepository) ListInvestorPayments(ctx context.Context, investorID uuid.UUID) ([]*entities.ZakatPayment, error) {
+	shards, err := r.shardMgr.GetAllShards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards: %w", err)
+	}
+
+	// An investor may pay through any of their cooperatives, so look on every shard
+	var payments []*entities.ZakatPayment
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+
+		rows, err := shard.QueryContext(ctx, `
+			SELECT `+zakatPaymentColumns+` FROM zakat_payments WHERE investor_id = $1
+		`, investorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list zakat payments: %w", err)
+		}
+
+		for rows.Next() {
+			payment, err := scanZakatPayment(rows)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan zakat payment: %w", err)
+			}
+			payments = append(payments, payment)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list zakat payments: %w", err)
+		}
+	}
+
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].CreatedAt.After(payments[j].CreatedAt)
+	})
+
+	return payments, nil
+}
@@ -0,0 +1,660 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// AMLMonitoringService screens every fund transfer and investment against the
+// configured anti-money-laundering rules and manages the cases compliance
+// officers review
+type AMLMonitoringService interface {
+	// Monitoring; alerts are grouped into a case on the transaction's subject
+	MonitorTransfer(ctx context.Context, transfer *entities.FundTransfer) ([]*entities.AMLAlert, error)
+	MonitorInvestment(ctx context.Context, investment *entities.InvestmentExtended) ([]*entities.AMLAlert, error)
+	// ListFlaggedTransactions returns the transactions alerted on in cases that
+	// are not closed
+	ListFlaggedTransactions(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.AMLTransaction, error)
+
+	// Rules
+	CreateRule(ctx context.Context, req *entities.CreateAMLRuleRequest, userID uuid.UUID) (*entities.AMLRule, error)
+	ListRules(ctx context.Context, cooperativeID *uuid.UUID) ([]*entities.AMLRule, error)
+	DeactivateRule(ctx context.Context, ruleID, userID uuid.UUID) error
+
+	// Case workflow: open, investigating, escalated, closed
+	ListCases(ctx context.Context, cooperativeID uuid.UUID, filter *entities.AMLCaseFilter) ([]*entities.AMLCase, int, error)
+	GetCase(ctx context.Context, cooperativeID, caseID uuid.UUID) (*entities.AMLCase, error)
+	StartInvestigation(ctx context.Context, cooperativeID, caseID, officerID uuid.UUID, req *entities.AMLCaseActionRequest) (*entities.AMLCase, error)
+	EscalateCase(ctx context.Context, cooperativeID, caseID, officerID uuid.UUID, req *entities.AMLCaseActionRequest) (*entities.AMLCase, error)
+	CloseCase(ctx context.Context, cooperativeID, caseID, officerID uuid.UUID, req *entities.CloseAMLCaseRequest) (*entities.AMLCase, error)
+	// ExportSuspiciousTransactionReport renders a case's flagged transactions
+	// as a CSV suspicious transaction report
+	ExportSuspiciousTransactionReport(ctx context.Context, cooperativeID, caseID, officerID uuid.UUID) (*entities.SuspiciousTransactionReportFile, error)
+}
+
+type amlMonitoringService struct {
+	amlRepo         repositories.AMLRepository
+	currencyService CurrencyService
+	auditService    AuditService
+}
+
+// NewAMLMonitoringService creates a new AML monitoring service. Transactions
+// are compared with rules in another currency at the rate effective when they
+// occurred; without a currency service such rules are skipped.
+func NewAMLMonitoringService(amlRepo repositories.AMLRepository, currencyService CurrencyService, auditService AuditService) AMLMonitoringService {
+	return &amlMonitoringService{
+		amlRepo:         amlRepo,
+		currencyService: currencyService,
+		auditService:    auditService,
+	}
+}
+
+func (s *amlMonitoringService) MonitorTransfer(ctx context.Context, transfer *entities.FundTransfer) ([]*entities.AMLAlert, error) {
+	// Money comes from the investor for investments and fees and goes to the
+	// member otherwise; transfers between the platform's own accounts have no
+	// member to monitor
+	direction, subjectID := entities.AMLDirectionOutbound, transfer.ToUserID
+	if transfer.TransferType == entities.TransferTypeLnvestment || transfer.TransferType == entities.TransferTypeFee {
+		direction, subjectID = entities.AMLDirectionInbound, transfer.FromUserID
+	}
+	if subjectID == nil {
+		return nil, nil
+	}
+
+	cooperativeID, err := s.amlRepo.GetProjectCooperativeID(ctx, transfer.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.monitor(ctx, &entities.AMLTransaction{
+		ID:              uuid.New(),
+		CooperativeID:   cooperativeID,
+		ProjectID:       transfer.ProjectID,
+		SubjectID:       *subjectID,
+		SourceType:      entities.AMLSourceFundTransfer,
+		SourceID:        transfer.ID,
+		Reference:       transfer.TransferNumber,
+		TransactionType: transfer.TransferType,
+		Direction:       direction,
+		Amount:          transfer.Amount,
+		Currency:        transfer.Currency,
+		BankAccount:     transfer.BankAccount,
+		OccurredAt:      transfer.CreatedAt,
+		CreatedAt:       time.Now(),
+	})
+}
+
+func (s *amlMonitoringService) MonitorInvestment(ctx context.Context, investment *entities.InvestmentExtended) ([]*entities.AMLAlert, error) {
+	cooperativeID := investment.CooperativeID
+	if cooperativeID == uuid.Nil {
+		var err error
+		if cooperativeID, err = s.amlRepo.GetProjectCooperativeID(ctx, investment.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.monitor(ctx, &entities.AMLTransaction{
+		ID:              uuid.New(),
+		CooperativeID:   cooperativeID,
+		ProjectID:       investment.ProjectID,
+		SubjectID:       investment.InvestorID,
+		SourceType:      entities.AMLSourceInvestment,
+		SourceID:        investment.ID,
+		Reference:       investment.TransferReference,
+		TransactionType: entities.TransferTypeLnvestment,
+		Direction:       entities.AMLDirectionInbound,
+		Amount:          investment.Amount,
+		Currency:        investment.Currency,
+		OccurredAt:      investment.CreatedAt,
+		CreatedAt:       time.Now(),
+	})
+}
+
+// monitor evaluates a transaction against the rules that apply to its
+// cooperative, records it and raises an alert for each rule it triggers
+func (s *amlMonitoringService) monitor(ctx context.Context, transaction *entities.AMLTransaction) ([]*entities.AMLAlert, error) {
+	rules, err := s.amlRepo.ListRules(ctx, &transaction.CooperativeID)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := &amlEvaluation{transaction: transaction}
+	var lookback time.Duration
+	checksBankAccount := false
+	for _, rule := range rules {
+		if rule.Window() > lookback {
+			lookback = rule.Window()
+		}
+		checksBankAccount = checksBankAccount || rule.RuleType == entities.AMLRuleNewBankAccount
+	}
+
+	if len(rules) > 0 {
+		evaluation.history, err = s.amlRepo.ListSubjectTransactions(ctx, transaction.CooperativeID, transaction.SubjectID, transaction.OccurredAt.Add(-lookback))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if checksBankAccount && transaction.BankAccount != "" {
+		evaluation.bankAccounts, err = s.amlRepo.ListBankAccountUses(ctx, transaction.CooperativeID, transaction.SubjectID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if evaluation.amounts, err = s.ruleAmounts(ctx, rules, append(evaluation.history, transaction)); err != nil {
+		return nil, err
+	}
+
+	alerts := evaluation.evaluate(rules)
+
+	if err := s.amlRepo.CreateTransaction(ctx, transaction); err != nil {
+		return nil, err
+	}
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+
+	amlCase, err := s.amlRepo.RaiseAlerts(ctx, transaction.CooperativeID, transaction.SubjectID, alerts, transaction.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     uuid.Nil,
+		Operation:  "raise_aml_alerts",
+		EntityType: "aml_case",
+		EntityID:   amlCase.ID,
+		NewValues:  alerts,
+	})
+
+	return alerts, nil
+}
+
+// ruleAmounts converts each transaction into every rule currency, keyed by
+// currency and then transaction
+func (s *amlMonitoringService) ruleAmounts(ctx context.Context, rules []*entities.AMLRule, transactions []*entities.AMLTransaction) (map[string]map[uuid.UUID]entities.Money, error) {
+	amounts := make(map[string]map[uuid.UUID]entities.Money)
+	for _, rule := range rules {
+		if amounts[rule.Currency] != nil {
+			continue
+		}
+
+		converted := make(map[uuid.UUID]entities.Money)
+		for _, t := range transactions {
+			if t.Currency == rule.Currency {
+				converted[t.ID] = t.Amount
+				continue
+			}
+			if s.currencyService == nil {
+				continue
+			}
+
+			conversion, err := s.currencyService.Convert(ctx, t.Amount, rule.Currency, t.OccurredAt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert transaction amount: %w", err)
+			}
+			converted[t.ID] = conversion.Converted
+		}
+		amounts[rule.Currency] = converted
+	}
+
+	return amounts, nil
+}
+
+// amlEvaluation is what a transaction is evaluated against: the subject's
+// earlier transactions within the longest rule window, the bank accounts
+// they used, and every amount in each rule currency
+type amlEvaluation struct {
+	transaction  *entities.AMLTransaction
+	history      []*entities.AMLTransaction
+	bankAccounts []*entities.AMLBankAccountUse
+	amounts      map[string]map[uuid.UUID]entities.Money
+}
+
+func (e *amlEvaluation) evaluate(rules []*entities.AMLRule) []*entities.AMLAlert {
+	var alerts []*entities.AMLAlert
+	for _, rule := range rules {
+		description, triggered := e.check(rule)
+		if !triggered {
+			continue
+		}
+
+		alerts = append(alerts, &entities.AMLAlert{
+			ID:            uuid.New(),
+			TransactionID: e.transaction.ID,
+			RuleID:        rule.ID,
+			RuleName:      rule.Name,
+			RuleType:      rule.RuleType,
+			Severity:      rule.Severity,
+			Description:   description,
+			CreatedAt:     e.transaction.CreatedAt,
+		})
+	}
+	return alerts
+}
+
+// check reports whether the transaction triggers a rule and why
+func (e *amlEvaluation) check(rule *entities.AMLRule) (string, bool) {
+	t := e.transaction
+	amount, ok := e.amount(rule, t)
+	if !ok {
+		return "", false
+	}
+
+	switch rule.RuleType {
+	case entities.AMLRuleAmountThreshold:
+		if amount.LessThan(rule.Threshold) {
+			return "", false
+		}
+		return fmt.Sprintf("%s of %s is at or above the %s threshold", amlTransactionLabel(t), amount, rule.Threshold), true
+
+	case entities.AMLRuleVelocity:
+		window := e.window(rule)
+		total := entities.ZeroMoney(rule.Currency)
+		for _, w := range window {
+			if a, ok := e.amount(rule, w); ok {
+				total = total.Add(a)
+			}
+		}
+		countHit := rule.MinCount > 1 && len(window) >= rule.MinCount
+		totalHit := rule.Threshold.IsPositive() && total.GreaterThanOrEqual(rule.Threshold)
+		if !countHit && !totalHit {
+			return "", false
+		}
+		return fmt.Sprintf("%d transactions totalling %s within %d hours", len(window), total, rule.WindowHours), true
+
+	case entities.AMLRuleStructuring:
+		floor := rule.Threshold.Percent(100-rule.MarginPercent, entities.RoundHalfUp)
+		justUnder := func(a entities.Money) bool {
+			return a.GreaterThanOrEqual(floor) && a.LessThan(rule.Threshold)
+		}
+		if !justUnder(amount) {
+			return "", false
+		}
+		count := 0
+		for _, w := range e.window(rule) {
+			if a, ok := e.amount(rule, w); ok && justUnder(a) {
+				count++
+			}
+		}
+		if count < maxInt(rule.MinCount, 2) {
+			return "", false
+		}
+		return fmt.Sprintf("%d transactions between %s and the %s threshold within %d hours", count, floor, rule.Threshold, rule.WindowHours), true
+
+	case entities.AMLRuleRoundAmount:
+		// Roundness only means something in the currency the member paid in
+		isRound := func(w *entities.AMLTransaction) bool {
+			return w.Currency == rule.Currency && rule.RoundingUnit.IsPositive() &&
+				w.Amount.GreaterThanOrEqual(rule.Threshold) && w.Amount.MinorUnits()%rule.RoundingUnit.MinorUnits() == 0
+		}
+		if !isRound(t) {
+			return "", false
+		}
+		count := 0
+		for _, w := range e.window(rule) {
+			if isRound(w) {
+				count++
+			}
+		}
+		if count < maxInt(rule.MinCount, 1) {
+			return "", false
+		}
+		return fmt.Sprintf("%d round amount transaction(s) in multiples of %s, the latest %s", count, rule.RoundingUnit, t.Amount), true
+
+	case entities.AMLRuleInvestThenWithdraw:
+		if t.TransactionType != entities.TransferTypeWithdrawal && t.TransactionType != entities.TransferTypeRefund {
+			return "", false
+		}
+		invested := entities.ZeroMoney(rule.Currency)
+		for _, w := range e.window(rule) {
+			if a, ok := e.amount(rule, w); ok && w.TransactionType == entities.TransferTypeLnvestment {
+				invested = invested.Add(a)
+			}
+		}
+		if !invested.IsPositive() || amount.LessThan(invested.Percent(rule.MarginPercent, entities.RoundHalfUp)) {
+			return "", false
+		}
+		return fmt.Sprintf("%s of %s within %d hours of investing %s", amlTransactionLabel(t), amount, rule.WindowHours, invested), true
+
+	case entities.AMLRuleNewBankAccount:
+		if t.BankAccount == "" || amount.LessThan(rule.Threshold) {
+			return "", false
+		}
+		// A brand new account is first used now; the member's first account is
+		// not a change
+		firstUsed := t.OccurredAt
+		for _, use := range e.bankAccounts {
+			if use.BankAccount == t.BankAccount {
+				firstUsed = use.FirstUsedAt
+			}
+		}
+		previous := ""
+		for _, use := range e.bankAccounts {
+			if use.BankAccount != t.BankAccount && use.FirstUsedAt.Before(firstUsed) {
+				previous = use.BankAccount
+			}
+		}
+		if previous == "" || firstUsed.Before(t.OccurredAt.Add(-rule.Window())) {
+			return "", false
+		}
+		return fmt.Sprintf("%s of %s through bank account %s, first used %s in place of %s",
+			amlTransactionLabel(t), amount, t.BankAccount, firstUsed.UTC().Format("2006-01-02 15:04"), previous), true
+	}
+
+	return "", false
+}
+
+func (e *amlEvaluation) amount(rule *entities.AMLRule, t *entities.AMLTransaction) (entities.Money, bool) {
+	amount, ok := e.amounts[rule.Currency][t.ID]
+	return amount, ok
+}
+
+// window returns the subject's transactions within the rule's window up to
+// and including the one evaluated
+func (e *amlEvaluation) window(rule *entities.AMLRule) []*entities.AMLTransaction {
+	since := e.transaction.OccurredAt.Add(-rule.Window())
+
+	var window []*entities.AMLTransaction
+	for _, t := range e.history {
+		if t.OccurredAt.After(since) && !t.OccurredAt.After(e.transaction.OccurredAt) {
+			window = append(window, t)
+		}
+	}
+	return append(window, e.transaction)
+}
+
+func amlTransactionLabel(t *entities.AMLTransaction) string {
+	label := strings.ReplaceAll(t.TransactionType, "_", " ")
+	if label == "" {
+		return "Transaction"
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func (s *amlMonitoringService) ListFlaggedTransactions(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.AMLTransaction, error) {
+	return s.amlRepo.ListFlaggedTransactions(ctx, cooperativeID)
+}
+
+func (s *amlMonitoringService) CreateRule(ctx context.Context, req *entities.CreateAMLRuleRequest, userID uuid.UUID) (*entities.AMLRule, error) {
+	if err := entities.CheckSameCurrency(entities.ZeroMoney(req.Currency), req.Threshold, req.RoundingUnit); err != nil {
+		return nil, fmt.Errorf("rule amounts must be in the rule's currency: %w", err)
+	}
+
+	now := time.Now()
+	rule := &entities.AMLRule{
+		ID:            uuid.New(),
+		CooperativeID: req.CooperativeID,
+		Name:          req.Name,
+		RuleType:      req.RuleType,
+		Threshold:     req.Threshold.WithCurrency(req.Currency),
+		Currency:      req.Currency,
+		MinCount:      req.MinCount,
+		WindowHours:   req.WindowHours,
+		MarginPercent: req.MarginPercent,
+		RoundingUnit:  req.RoundingUnit.WithCurrency(req.Currency),
+		Severity:      req.Severity,
+		IsActive:      true,
+		CreatedBy:     userID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := validateAMLRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.amlRepo.CreateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "create_aml_rule",
+		EntityType: "aml_rule",
+		EntityID:   rule.ID,
+		NewValues:  rule,
+	})
+
+	return rule, nil
+}
+
+// validateAMLRule checks a rule has the settings its type needs
+func validateAMLRule(rule *entities.AMLRule) error {
+	if rule.Threshold.IsNegative() || rule.RoundingUnit.IsNegative() {
+		return errors.New("threshold and rounding unit must not be negative")
+	}
+
+	needsWindow := rule.RuleType != entities.AMLRuleAmountThreshold &&
+		(rule.RuleType != entities.AMLRuleRoundAmount || rule.MinCount > 1)
+	if needsWindow && rule.WindowHours == 0 {
+		return fmt.Errorf("%s rules need a window", rule.RuleType)
+	}
+
+	switch rule.RuleType {
+	case entities.AMLRuleAmountThreshold:
+		if !rule.Threshold.IsPositive() {
+			return errors.New("amount threshold rules need a threshold")
+		}
+	case entities.AMLRuleVelocity:
+		if rule.MinCount < 2 && !rule.Threshold.IsPositive() {
+			return errors.New("velocity rules need a transaction count of at least 2 or a threshold")
+		}
+	case entities.AMLRuleStructuring:
+		if !rule.Threshold.IsPositive() || rule.MarginPercent <= 0 {
+			return errors.New("structuring rules need a threshold and a margin below it")
+		}
+	case entities.AMLRuleRoundAmount:
+		if !rule.RoundingUnit.IsPositive() {
+			return errors.New("round amount rules need a rounding unit")
+		}
+	}
+
+	return nil
+}
+
+func (s *amlMonitoringService) ListRules(ctx context.Context, cooperativeID *uuid.UUID) ([]*entities.AMLRule, error) {
+	return s.amlRepo.ListRules(ctx, cooperativeID)
+}
+
+func (s *amlMonitoringService) DeactivateRule(ctx context.Context, ruleID, userID uuid.UUID) error {
+	rule, err := s.amlRepo.GetRule(ctx, ruleID)
+	if err != nil {
+		return err
+	}
+	if !rule.IsActive {
+		return errors.New("AML rule is already inactive")
+	}
+
+	if err := s.amlRepo.DeactivateRule(ctx, ruleID); err != nil {
+		return err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "deactivate_aml_rule",
+		EntityType: "aml_rule",
+		EntityID:   ruleID,
+		NewValues:  map[string]interface{}{"is_active": false},
+	})
+
+	return nil
+}
+
+func (s *amlMonitoringService) ListCases(ctx context.Context, cooperativeID uuid.UUID, filter *entities.AMLCaseFilter) ([]*entities.AMLCase, int, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 10
+	}
+	return s.amlRepo.ListCases(ctx, cooperativeID, filter)
+}
+
+func (s *amlMonitoringService) GetCase(ctx context.Context, cooperativeID, caseID uuid.UUID) (*entities.AMLCase, error) {
+	return s.amlRepo.GetCase(ctx, cooperativeID, caseID)
+}
+
+func (s *amlMonitoringService) StartInvestigation(ctx context.Context, cooperativeID, caseID, officerID uuid.UUID, req *entities.AMLCaseActionRequest) (*entities.AMLCase, error) {
+	return s.transition(ctx, cooperativeID, caseID, officerID, entities.AMLCaseActionInvestigationStarted, req.Notes,
+		[]string{entities.AMLCaseStatusOpen}, func(amlCase *entities.AMLCase, now time.Time) {
+			amlCase.Status = entities.AMLCaseStatusInvestigating
+			amlCase.AssignedTo = &officerID
+		})
+}
+
+func (s *amlMonitoringService) EscalateCase(ctx context.Context, cooperativeID, caseID, officerID uuid.UUID, req *entities.AMLCaseActionRequest) (*entities.AMLCase, error) {
+	if strings.TrimSpace(req.Notes) == "" {
+		return nil, errors.New("escalation notes are required")
+	}
+
+	return s.transition(ctx, cooperativeID, caseID, officerID, entities.AMLCaseActionEscalated, req.Notes,
+		[]string{entities.AMLCaseStatusOpen, entities.AMLCaseStatusInvestigating}, func(amlCase *entities.AMLCase, now time.Time) {
+			amlCase.Status = entities.AMLCaseStatusEscalated
+			amlCase.EscalatedAt = &now
+			if amlCase.AssignedTo == nil {
+				amlCase.AssignedTo = &officerID
+			}
+		})
+}
+
+func (s *amlMonitoringService) CloseCase(ctx context.Context, cooperativeID, caseID, officerID uuid.UUID, req *entities.CloseAMLCaseRequest) (*entities.AMLCase, error) {
+	return s.transition(ctx, cooperativeID, caseID, officerID, entities.AMLCaseActionClosed, req.Notes,
+		[]string{entities.AMLCaseStatusOpen, entities.AMLCaseStatusInvestigating, entities.AMLCaseStatusEscalated}, func(amlCase *entities.AMLCase, now time.Time) {
+			amlCase.Status = entities.AMLCaseStatusClosed
+			amlCase.ClosureReason = req.Reason
+			amlCase.ClosureNotes = req.Notes
+			amlCase.ClosedAt = &now
+			amlCase.ClosedBy = &officerID
+		})
+}
+
+// transition moves a case on from one of the statuses it may be in and
+// records the step in its history
+func (s *amlMonitoringService) transition(ctx context.Context, cooperativeID, caseID, officerID uuid.UUID, action, notes string, from []string, apply func(*entities.AMLCase, time.Time)) (*entities.AMLCase, error) {
+	amlCase, err := s.amlRepo.GetCase(ctx, cooperativeID, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromStatus := amlCase.Status
+	allowed := false
+	for _, status := range from {
+		allowed = allowed || fromStatus == status
+	}
+	if !allowed {
+		return nil, fmt.Errorf("AML case is %s", fromStatus)
+	}
+
+	now := time.Now()
+	apply(amlCase, now)
+	event := &entities.AMLCaseEvent{
+		ID:         uuid.New(),
+		CaseID:     amlCase.ID,
+		Action:     action,
+		FromStatus: fromStatus,
+		ToStatus:   amlCase.Status,
+		Notes:      notes,
+		ActorID:    &officerID,
+		CreatedAt:  now,
+	}
+
+	if err := s.amlRepo.TransitionCase(ctx, amlCase, fromStatus, event); err != nil {
+		return nil, err
+	}
+	amlCase.Events = append(amlCase.Events, event)
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     officerID,
+		Operation:  "aml_case_" + action,
+		EntityType: "aml_case",
+		EntityID:   amlCase.ID,
+		NewValues:  event,
+	})
+
+	return amlCase, nil
+}
+
+func (s *amlMonitoringService) ExportSuspiciousTransactionReport(ctx context.Context, cooperativeID, caseID, officerID uuid.UUID) (*entities.SuspiciousTransactionReportFile, error) {
+	amlCase, err := s.amlRepo.GetCase(ctx, cooperativeID, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := renderSuspiciousTransactionReportCSV(amlCase)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     officerID,
+		Operation:  "export_suspicious_transaction_report",
+		EntityType: "aml_case",
+		EntityID:   amlCase.ID,
+		NewValues:  map[string]interface{}{"case_number": amlCase.CaseNumber, "status": amlCase.Status},
+	})
+
+	return &entities.SuspiciousTransactionReportFile{
+		FileName:    fmt.Sprintf("str-%s.csv", amlCase.CaseNumber),
+		ContentType: "text/csv",
+		Content:     content,
+	}, nil
+}
+
+// renderSuspiciousTransactionReportCSV writes a row per flagged transaction
+// with the case it belongs to and the rules it triggered
+func renderSuspiciousTransactionReportCSV(amlCase *entities.AMLCase) ([]byte, error) {
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+
+	alerts := make(map[uuid.UUID][]*entities.AMLAlert)
+	for _, alert := range amlCase.Alerts {
+		alerts[alert.TransactionID] = append(alerts[alert.TransactionID], alert)
+	}
+
+	rows := [][]string{{
+		"case_number", "cooperative_id", "subject_id", "case_status", "closure_reason", "occurred_at", "source_type",
+		"source_id", "reference", "transaction_type", "direction", "amount", "currency", "bank_account", "project_id",
+		"severity", "rules", "alerts",
+	}}
+	for _, t := range amlCase.Transactions {
+		severity := ""
+		var rules, descriptions []string
+		for _, alert := range alerts[t.ID] {
+			if entities.AMLSeverityRank(alert.Severity) > entities.AMLSeverityRank(severity) {
+				severity = alert.Severity
+			}
+			rules = append(rules, alert.RuleName)
+			descriptions = append(descriptions, alert.Description)
+		}
+
+		rows = append(rows, []string{
+			amlCase.CaseNumber, amlCase.CooperativeID.String(), amlCase.SubjectID.String(), amlCase.Status,
+			amlCase.ClosureReason, t.OccurredAt.UTC().Format(time.RFC3339), t.SourceType, t.SourceID.String(), t.Reference,
+			t.TransactionType, t.Direction, t.Amount.Decimal(), t.Currency, t.BankAccount, t.ProjectID.String(),
+			severity, strings.Join(rules, "; "), strings.Join(descriptions, "; "),
+		})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write suspicious transaction report CSV: %w", err)
+	}
+
+	return out.Bytes(), nil
+}
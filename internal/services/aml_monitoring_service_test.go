@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var amlNow = time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+// evaluateAML evaluates a transaction against one rule, with no currency
+// service so that only the rule's own currency is compared
+func evaluateAML(t *testing.T, rule *entities.AMLRule, transaction *entities.AMLTransaction, history []*entities.AMLTransaction, bankAccounts []*entities.AMLBankAccountUse) []*entities.AMLAlert {
+	service := &amlMonitoringService{}
+	amounts, err := service.ruleAmounts(context.Background(), []*entities.AMLRule{rule}, append(history, transaction))
+	require.NoError(t, err)
+
+	evaluation := &amlEvaluation{transaction: transaction, history: history, bankAccounts: bankAccounts, amounts: amounts}
+	return evaluation.evaluate([]*entities.AMLRule{rule})
+}
+
+func TestAMLEvaluation_Rules(t *testing.T) {
+	threshold := &entities.AMLRule{ID: uuid.New(), RuleType: entities.AMLRuleAmountThreshold, Threshold: idr("10000"), Currency: "IDR", RoundingUnit: idr("0"), IsActive: true}
+	velocity := &entities.AMLRule{ID: uuid.New(), RuleType: entities.AMLRuleVelocity, Threshold: idr("0"), Currency: "IDR", RoundingUnit: idr("0"), MinCount: 3, WindowHours: 24, IsActive: true}
+	structuring := &entities.AMLRule{ID: uuid.New(), RuleType: entities.AMLRuleStructuring, Threshold: idr("10000"), Currency: "IDR", RoundingUnit: idr("0"), MarginPercent: 10, MinCount: 3, WindowHours: 48, IsActive: true}
+	roundAmount := &entities.AMLRule{ID: uuid.New(), RuleType: entities.AMLRuleRoundAmount, Threshold: idr("5000"), Currency: "IDR", RoundingUnit: idr("1000"), IsActive: true}
+	investThenWithdraw := &entities.AMLRule{ID: uuid.New(), RuleType: entities.AMLRuleInvestThenWithdraw, Threshold: idr("0"), Currency: "IDR", RoundingUnit: idr("0"), MarginPercent: 50, WindowHours: 72, IsActive: true}
+	foreignThreshold := &entities.AMLRule{ID: uuid.New(), RuleType: entities.AMLRuleAmountThreshold, Threshold: entities.MustParseMoney("1", "USD"), Currency: "USD", RoundingUnit: entities.MustParseMoney("0", "USD"), IsActive: true}
+
+	investment := func(amount string, hoursAgo int) *entities.AMLTransaction {
+		at := amlNow.Add(-time.Duration(hoursAgo) * time.Hour)
+		return &entities.AMLTransaction{ID: uuid.New(), TransactionType: entities.TransferTypeLnvestment, Direction: entities.AMLDirectionInbound, Amount: idr(amount), Currency: "IDR", OccurredAt: at, CreatedAt: at}
+	}
+	outbound := func(transactionType, amount string, hoursAgo int) *entities.AMLTransaction {
+		at := amlNow.Add(-time.Duration(hoursAgo) * time.Hour)
+		return &entities.AMLTransaction{ID: uuid.New(), TransactionType: transactionType, Direction: entities.AMLDirectionOutbound, Amount: idr(amount), Currency: "IDR", OccurredAt: at, CreatedAt: at}
+	}
+
+	testCases := []struct {
+		name        string
+		rule        *entities.AMLRule
+		transaction *entities.AMLTransaction
+		history     []*entities.AMLTransaction
+		triggered   bool
+	}{
+		{"Amount at the threshold", threshold, investment("10000", 0), nil, true},
+		{"Amount below the threshold", threshold, investment("9999.99", 0), nil, false},
+		{"Third transaction in a day", velocity, investment("10", 0), []*entities.AMLTransaction{
+			investment("10", 20),
+			outbound(entities.TransferTypeWithdrawal, "10", 2),
+		}, true},
+		{"Earlier transaction outside the window", velocity, investment("10", 0), []*entities.AMLTransaction{
+			investment("10", 30),
+			investment("10", 2),
+		}, false},
+		{"Repeated amounts just under the threshold", structuring, investment("9900", 0), []*entities.AMLTransaction{
+			investment("9500", 30),
+			investment("9800", 5),
+		}, true},
+		{"Amount well under the threshold", structuring, investment("8000", 0), []*entities.AMLTransaction{
+			investment("9500", 30),
+			investment("9800", 5),
+		}, false},
+		{"Round amount", roundAmount, investment("7000", 0), nil, true},
+		{"Amount with cents", roundAmount, investment("7000.50", 0), nil, false},
+		{"Round amount below the minimum", roundAmount, investment("4000", 0), nil, false},
+		{"Most of an investment withdrawn", investThenWithdraw, outbound(entities.TransferTypeWithdrawal, "6000", 0), []*entities.AMLTransaction{
+			investment("10000", 24),
+		}, true},
+		{"Small part of an investment withdrawn", investThenWithdraw, outbound(entities.TransferTypeWithdrawal, "4000", 0), []*entities.AMLTransaction{
+			investment("10000", 24),
+		}, false},
+		{"Withdrawal long after investing", investThenWithdraw, outbound(entities.TransferTypeRefund, "10000", 0), []*entities.AMLTransaction{
+			investment("10000", 100),
+		}, false},
+		{"Rule in a currency with no rate", foreignThreshold, investment("100000", 0), nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			alerts := evaluateAML(t, tc.rule, tc.transaction, tc.history, nil)
+
+			if !tc.triggered {
+				assert.Empty(t, alerts)
+				return
+			}
+			require.Len(t, alerts, 1)
+			assert.Equal(t, tc.rule.ID, alerts[0].RuleID)
+			assert.Equal(t, tc.transaction.ID, alerts[0].TransactionID)
+			assert.NotEmpty(t, alerts[0].Description)
+		})
+	}
+}
+
+func TestAMLEvaluation_NewBankAccount(t *testing.T) {
+	rule := &entities.AMLRule{ID: uuid.New(), RuleType: entities.AMLRuleNewBankAccount, Threshold: idr("0"), Currency: "IDR", RoundingUnit: idr("0"), WindowHours: 168, IsActive: true}
+	longAgo := amlNow.AddDate(0, -3, 0)
+
+	testCases := []struct {
+		name         string
+		bankAccount  string
+		bankAccounts []*entities.AMLBankAccountUse
+		description  string
+	}{
+		{
+			name:         "Never used before, in place of the usual account",
+			bankAccount:  "222",
+			bankAccounts: []*entities.AMLBankAccountUse{{BankAccount: "111", FirstUsedAt: longAgo}},
+			description:  "in place of 111",
+		},
+		{
+			name:         "The usual account",
+			bankAccount:  "111",
+			bankAccounts: []*entities.AMLBankAccountUse{{BankAccount: "111", FirstUsedAt: longAgo}},
+		},
+		{
+			name:        "The member's first account is not a change",
+			bankAccount: "111",
+		},
+		{
+			name:        "Account changed before the window",
+			bankAccount: "222",
+			bankAccounts: []*entities.AMLBankAccountUse{
+				{BankAccount: "111", FirstUsedAt: longAgo},
+				{BankAccount: "222", FirstUsedAt: amlNow.AddDate(0, 0, -10)},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withdrawal := &entities.AMLTransaction{
+				ID:              uuid.New(),
+				TransactionType: entities.TransferTypeWithdrawal,
+				Direction:       entities.AMLDirectionOutbound,
+				Amount:          idr("5000"),
+				Currency:        "IDR",
+				BankAccount:     tc.bankAccount,
+				OccurredAt:      amlNow,
+				CreatedAt:       amlNow,
+			}
+
+			alerts := evaluateAML(t, rule, withdrawal, nil, tc.bankAccounts)
+
+			if tc.description == "" {
+				assert.Empty(t, alerts)
+				return
+			}
+			require.Len(t, alerts, 1)
+			assert.Contains(t, alerts[0].Description, tc.description)
+		})
+	}
+}
+
+func TestAMLMonitoringService_MonitorTransfer(t *testing.T) {
+	amlRepo := new(MockAMLRepository)
+	mockAuditService := new(MockAuditService)
+	service := NewAMLMonitoringService(amlRepo, nil, mockAuditService)
+	ctx := context.Background()
+
+	cooperativeID, memberID := uuid.New(), uuid.New()
+	transfer := &entities.FundTransfer{
+		ID:             uuid.New(),
+		TransferNumber: "TRF-1",
+		ProjectID:      uuid.New(),
+		ToUserID:       &memberID,
+		TransferType:   entities.TransferTypeWithdrawal,
+		Amount:         idr("15000"),
+		Currency:       "IDR",
+		BankAccount:    "222",
+		CreatedAt:      amlNow,
+	}
+	rules := []*entities.AMLRule{
+		{ID: uuid.New(), RuleType: entities.AMLRuleAmountThreshold, Threshold: idr("10000"), Currency: "IDR", RoundingUnit: idr("0"), Severity: entities.AMLSeverityHigh, IsActive: true},
+		{ID: uuid.New(), RuleType: entities.AMLRuleNewBankAccount, Threshold: idr("0"), Currency: "IDR", RoundingUnit: idr("0"), Severity: entities.AMLSeverityMedium, WindowHours: 24, IsActive: true},
+	}
+	amlCase := &entities.AMLCase{ID: uuid.New(), CooperativeID: cooperativeID, SubjectID: memberID, Status: entities.AMLCaseStatusOpen}
+
+	amlRepo.On("GetProjectCooperativeID", ctx, transfer.ProjectID).Return(cooperativeID, nil)
+	amlRepo.On("ListRules", ctx, &cooperativeID).Return(rules, nil)
+	amlRepo.On("ListSubjectTransactions", ctx, cooperativeID, memberID, amlNow.Add(-24*time.Hour)).Return([]*entities.AMLTransaction{}, nil)
+	amlRepo.On("ListBankAccountUses", ctx, cooperativeID, memberID).Return([]*entities.AMLBankAccountUse{
+		{BankAccount: "111", FirstUsedAt: amlNow.AddDate(-1, 0, 0)},
+	}, nil)
+	amlRepo.On("CreateTransaction", ctx, mock.MatchedBy(func(t *entities.AMLTransaction) bool {
+		return t.SubjectID == memberID && t.Direction == entities.AMLDirectionOutbound && t.SourceID == transfer.ID
+	})).Return(nil)
+	amlRepo.On("RaiseAlerts", ctx, cooperativeID, memberID, mock.Anything, mock.Anything).Return(amlCase, nil)
+	mockAuditService.On("LogOperation", mock.Anything, mock.Anything).Return(nil)
+
+	alerts, err := service.MonitorTransfer(ctx, transfer)
+
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, entities.AMLSeverityHigh, alerts[0].Severity)
+	assert.Equal(t, entities.AMLRuleNewBankAccount, alerts[1].RuleType)
+	amlRepo.AssertExpectations(t)
+
+	// Transfers between the platform's own accounts have no member to monitor
+	alerts, err = service.MonitorTransfer(ctx, &entities.FundTransfer{ID: uuid.New(), TransferType: entities.TransferTypeFee})
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+}
+
+func TestFundMonitoringService_DetectSuspiciousTransactions(t *testing.T) {
+	amlRepo := new(MockAMLRepository)
+	mockAuditService := new(MockAuditService)
+	service := NewFundMonitoringService(mockAuditService, nil, NewAMLMonitoringService(amlRepo, nil, mockAuditService), nil, nil, DefaultTransferRetryPolicy)
+	ctx := context.Background()
+
+	cooperativeID := uuid.New()
+	investment := &entities.AMLTransaction{
+		ID:              uuid.New(),
+		SourceType:      entities.AMLSourceInvestment,
+		SourceID:        uuid.New(),
+		SubjectID:       uuid.New(),
+		TransactionType: entities.TransferTypeLnvestment,
+		Direction:       entities.AMLDirectionInbound,
+		Amount:          idr("10000"),
+		Currency:        "IDR",
+		OccurredAt:      amlNow.Add(-time.Hour),
+		CreatedAt:       amlNow.Add(-time.Hour),
+	}
+	amlRepo.On("ListFlaggedTransactions", ctx, cooperativeID).Return([]*entities.AMLTransaction{investment}, nil)
+
+	transfers, err := service.DetectSuspiciousTransactions(ctx, cooperativeID)
+
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	assert.Equal(t, investment.SourceID, transfers[0].ID)
+	assert.Equal(t, investment.SourceID, *transfers[0].InvestmentID)
+	assert.Equal(t, investment.SubjectID, *transfers[0].FromUserID)
+	assert.Equal(t, idr("10000"), transfers[0].Amount)
+}
+
+func TestAMLMonitoringService_CaseWorkflow(t *testing.T) {
+	amlRepo := new(MockAMLRepository)
+	mockAuditService := new(MockAuditService)
+	service := NewAMLMonitoringService(amlRepo, nil, mockAuditService)
+	ctx := context.Background()
+
+	cooperativeID, officerID := uuid.New(), uuid.New()
+	openCase := &entities.AMLCase{ID: uuid.New(), CooperativeID: cooperativeID, Status: entities.AMLCaseStatusOpen}
+	closedCase := &entities.AMLCase{ID: uuid.New(), CooperativeID: cooperativeID, Status: entities.AMLCaseStatusClosed}
+
+	amlRepo.On("GetCase", ctx, cooperativeID, openCase.ID).Return(openCase, nil)
+	amlRepo.On("GetCase", ctx, cooperativeID, closedCase.ID).Return(closedCase, nil)
+	amlRepo.On("TransitionCase", ctx, openCase, entities.AMLCaseStatusOpen, mock.Anything).Return(nil)
+	mockAuditService.On("LogOperation", mock.Anything, mock.Anything).Return(nil)
+
+	investigated, err := service.StartInvestigation(ctx, cooperativeID, openCase.ID, officerID, &entities.AMLCaseActionRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, entities.AMLCaseStatusInvestigating, investigated.Status)
+	assert.Equal(t, officerID, *investigated.AssignedTo)
+	require.Len(t, investigated.Events, 1)
+	assert.Equal(t, entities.AMLCaseStatusOpen, investigated.Events[0].FromStatus)
+
+	_, err = service.EscalateCase(ctx, cooperativeID, closedCase.ID, officerID, &entities.AMLCaseActionRequest{})
+	assert.EqualError(t, err, "escalation notes are required")
+
+	_, err = service.CloseCase(ctx, cooperativeID, closedCase.ID, officerID, &entities.CloseAMLCaseRequest{Reason: entities.AMLClosureFalsePositive, Notes: "Known payroll"})
+	assert.EqualError(t, err, "AML case is closed")
+}
+
+func TestValidateAMLRule(t *testing.T) {
+	testCases := []struct {
+		name  string
+		rule  *entities.AMLRule
+		valid bool
+	}{
+		{name: "Amount threshold", rule: &entities.AMLRule{RuleType: entities.AMLRuleAmountThreshold, Threshold: idr("10000"), Currency: "IDR", RoundingUnit: idr("0")}, valid: true},
+		{name: "Amount threshold without a threshold", rule: &entities.AMLRule{RuleType: entities.AMLRuleAmountThreshold, Threshold: idr("0"), Currency: "IDR", RoundingUnit: idr("0")}},
+		{name: "Velocity without a window", rule: &entities.AMLRule{RuleType: entities.AMLRuleVelocity, Threshold: idr("0"), Currency: "IDR", RoundingUnit: idr("0"), MinCount: 5}},
+		{name: "Structuring without a count", rule: &entities.AMLRule{RuleType: entities.AMLRuleStructuring, Threshold: idr("10000"), Currency: "IDR", RoundingUnit: idr("0"), WindowHours: 24}},
+		{name: "Round amount", rule: &entities.AMLRule{RuleType: entities.AMLRuleRoundAmount, Threshold: idr("0"), Currency: "IDR", RoundingUnit: idr("1000")}, valid: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateAMLRule(tc.rule)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestRenderSuspiciousTransactionReportCSV(t *testing.T) {
+	transaction := &entities.AMLTransaction{
+		ID:              uuid.New(),
+		SourceType:      entities.AMLSourceFundTransfer,
+		TransactionType: entities.TransferTypeWithdrawal,
+		Direction:       entities.AMLDirectionOutbound,
+		Amount:          idr("15000"),
+		Currency:        "IDR",
+		BankAccount:     "222",
+		OccurredAt:      amlNow,
+		CreatedAt:       amlNow,
+	}
+	amlCase := &entities.AMLCase{
+		CaseNumber:   "AML-2026-000001",
+		Status:       entities.AMLCaseStatusEscalated,
+		Transactions: []*entities.AMLTransaction{transaction},
+		Alerts: []*entities.AMLAlert{
+			{TransactionID: transaction.ID, RuleName: "Large amount", Severity: entities.AMLSeverityHigh, Description: "over"},
+			{TransactionID: transaction.ID, RuleName: "New account", Severity: entities.AMLSeverityMedium, Description: "changed"},
+		},
+	}
+
+	content, err := renderSuspiciousTransactionReportCSV(amlCase)
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(string(content))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	record := make(map[string]string)
+	for i, column := range rows[0] {
+		record[column] = rows[1][i]
+	}
+	assert.Equal(t, "AML-2026-000001", record["case_number"])
+	assert.Equal(t, "15000.00", record["amount"])
+	assert.Equal(t, "222", record["bank_account"])
+	assert.Equal(t, entities.AMLSeverityHigh, record["severity"])
+	assert.Equal(t, "Large amount; New account", record["rules"])
+}
@@ -0,0 +1,557 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// BankReconciliationService imports escrow account bank statements and matches
+// their lines to pending investments and transfers. Lines that cannot be matched
+// automatically are queued as exceptions for manual resolution.
+type BankReconciliationService interface {
+	// Statements
+	ImportStatement(ctx context.Context, req *entities.ImportBankStatementRequest, fileName string, file io.Reader, importerID uuid.UUID) (*entities.ReconciliationResult, error)
+	GetStatement(ctx context.Context, statementID uuid.UUID) (*entities.BankStatement, error)
+	GetStatements(ctx context.Context, cooperativeID uuid.UUID, page, limit int) ([]*entities.BankStatement, int, error)
+	GetStatementLines(ctx context.Context, filter *entities.BankStatementLineFilter) ([]*entities.BankStatementLine, int, error)
+
+	// Matching
+	ReconcileCooperative(ctx context.Context, cooperativeID, userID uuid.UUID) (*entities.ReconciliationResult, error)
+	ValidatePaymentReference(ctx context.Context, cooperativeID uuid.UUID, reference string) (bool, error)
+
+	// Exceptions queue
+	GetExceptions(ctx context.Context, cooperativeID uuid.UUID, page, limit int) ([]*entities.BankStatementLine, int, error)
+	ManualMatch(ctx context.Context, lineID uuid.UUID, req *entities.ManualMatchRequest, userID uuid.UUID) (*entities.BankStatementLine, error)
+	IgnoreLine(ctx context.Context, lineID uuid.UUID, req *entities.IgnoreStatementLineRequest, userID uuid.UUID) (*entities.BankStatementLine, error)
+}
+
+// DefaultReconciliationTolerance matches amounts exactly and allows three days
+// between the expected date and the bank value date
+var DefaultReconciliationTolerance = entities.ReconciliationTolerance{AmountMinorUnits: 0, DateDays: 3}
+
+// bankReconciliationService implements BankReconciliationService
+type bankReconciliationService struct {
+	reconciliationRepo       repositories.BankReconciliationRepository
+	investmentFundingService InvestmentFundingService
+	fundMonitoringService    FundMonitoringService
+	auditService             AuditService
+	tolerance                entities.ReconciliationTolerance
+}
+
+// NewBankReconciliationService creates a new bank reconciliation service
+func NewBankReconciliationService(
+	reconciliationRepo repositories.BankReconciliationRepository,
+	investmentFundingService InvestmentFundingService,
+	fundMonitoringService FundMonitoringService,
+	auditService AuditService,
+	tolerance entities.ReconciliationTolerance,
+) BankReconciliationService {
+	return &bankReconciliationService{
+		reconciliationRepo:       reconciliationRepo,
+		investmentFundingService: investmentFundingService,
+		fundMonitoringService:    fundMonitoringService,
+		auditService:             auditService,
+		tolerance:                tolerance,
+	}
+}
+
+// ImportStatement parses an uploaded statement, stores its new lines and matches them
+func (s *bankReconciliationService) ImportStatement(ctx context.Context, req *entities.ImportBankStatementRequest, fileName string, file io.Reader, importerID uuid.UUID) (*entities.ReconciliationResult, error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statement file: %w", err)
+	}
+
+	hash := sha256.Sum256(content)
+	fileHash := hex.EncodeToString(hash[:])
+
+	existing, err := s.reconciliationRepo.GetStatementByFileHash(ctx, req.CooperativeID, fileHash)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("statement file was already imported on %s", existing.ImportedAt.Format("2006-01-02"))
+	}
+
+	statement, lines, err := ParseBankStatement(req.Format, bytes.NewReader(content), req.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse statement: %w", err)
+	}
+
+	// Statements often overlap; lines booked by an earlier import are skipped
+	transactionIDs := make([]string, len(lines))
+	for i, line := range lines {
+		transactionIDs[i] = line.BankTransactionID
+	}
+	imported, err := s.reconciliationRepo.ExistingTransactionIDs(ctx, req.CooperativeID, transactionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	statement.ID = uuid.New()
+	statement.CooperativeID = req.CooperativeID
+	statement.EscrowAccountID = req.EscrowAccountID
+	statement.FileName = fileName
+	statement.FileHash = fileHash
+	statement.ImportedBy = importerID
+	statement.ImportedAt = now
+
+	newLines := make([]*entities.BankStatementLine, 0, len(lines))
+	for _, line := range lines {
+		if imported[line.BankTransactionID] {
+			continue
+		}
+		line.StatementID = statement.ID
+		line.CooperativeID = req.CooperativeID
+		line.CreatedAt = now
+		line.UpdatedAt = now
+		newLines = append(newLines, line)
+	}
+	statement.LineCount = len(newLines)
+
+	if err := s.reconciliationRepo.CreateStatement(ctx, statement, newLines); err != nil {
+		return nil, fmt.Errorf("failed to save statement: %w", err)
+	}
+
+	result, err := s.matchLines(ctx, req.CooperativeID, newLines, importerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.reconciliationRepo.RefreshStatementCounts(ctx, statement); err != nil {
+		return nil, err
+	}
+	result.Statement = statement
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     importerID,
+		Operation:  "import_bank_statement",
+		EntityType: "bank_statement",
+		EntityID:   statement.ID,
+		NewValues: fmt.Sprintf("Imported %s statement %s with %d new lines (%d skipped): %d matched, %d exceptions",
+			statement.Format, fileName, len(newLines), len(lines)-len(newLines), len(result.Matched), len(result.Exceptions)),
+	})
+
+	return result, nil
+}
+
+// ReconcileCooperative re-runs automatic matching over every open line of a
+// cooperative, picking up investments and transfers created since the import
+func (s *bankReconciliationService) ReconcileCooperative(ctx context.Context, cooperativeID, userID uuid.UUID) (*entities.ReconciliationResult, error) {
+	lines, err := s.reconciliationRepo.ListOpenLines(ctx, cooperativeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.matchLines(ctx, cooperativeID, lines, userID)
+}
+
+// matchLines matches statement lines to the cooperative's pending investments
+// and transfers, confirming matches and queueing the rest as exceptions
+func (s *bankReconciliationService) matchLines(ctx context.Context, cooperativeID uuid.UUID, lines []*entities.BankStatementLine, userID uuid.UUID) (*entities.ReconciliationResult, error) {
+	result := &entities.ReconciliationResult{
+		Matched:    []*entities.BankStatementLine{},
+		Exceptions: []*entities.BankStatementLine{},
+	}
+	if len(lines) == 0 {
+		return result, nil
+	}
+
+	candidates, err := s.getCandidates(ctx, cooperativeID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		candidate, method, reason := matchStatementLine(line, candidates, s.tolerance)
+		if candidate != nil {
+			if err := s.settle(ctx, line, candidate, method, userID); err != nil {
+				reason = fmt.Sprintf("confirmation failed: %v", err)
+			} else {
+				candidates = removeCandidate(candidates, candidate)
+				result.Matched = append(result.Matched, line)
+				continue
+			}
+		}
+
+		line.Status = entities.StatementLineStatusException
+		line.ExceptionReason = reason
+		if err := s.reconciliationRepo.UpdateLine(ctx, line); err != nil {
+			return nil, err
+		}
+		result.Exceptions = append(result.Exceptions, line)
+	}
+
+	return result, nil
+}
+
+// getCandidates returns the cooperative's pending investments and transfers that
+// are not yet settled by a statement line
+func (s *bankReconciliationService) getCandidates(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.ReconciliationCandidate, error) {
+	var candidates []*entities.ReconciliationCandidate
+
+	investments, err := s.investmentFundingService.GetPendingInvestments(ctx, cooperativeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending investments: %w", err)
+	}
+	for _, investment := range investments {
+		reference := investment.TransferReference
+		if reference == "" {
+			reference = entities.InvestmentPaymentReference(investment.ID)
+		}
+		candidates = append(candidates, &entities.ReconciliationCandidate{
+			EntityType:   entities.ReconciliationMatchTypeInvestment,
+			EntityID:     investment.ID,
+			Direction:    entities.StatementLineDirectionCredit,
+			Reference:    reference,
+			Amount:       investment.Amount,
+			ExpectedDate: investment.CreatedAt,
+		})
+	}
+
+	transfers, _, err := s.fundMonitoringService.GetFundTransfers(ctx, &entities.FundTransferFilter{CooperativeID: &cooperativeID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fund transfers: %w", err)
+	}
+	for _, transfer := range transfers {
+		if transfer.Status != entities.TransferStatusPending && transfer.Status != entities.TransferStatusProcessing {
+			continue
+		}
+		candidates = append(candidates, transferCandidate(transfer))
+	}
+
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	entityIDs := make([]uuid.UUID, len(candidates))
+	for i, candidate := range candidates {
+		entityIDs[i] = candidate.EntityID
+	}
+	matched, err := s.reconciliationRepo.MatchedEntityIDs(ctx, cooperativeID, entityIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	open := candidates[:0]
+	for _, candidate := range candidates {
+		if !matched[candidate.EntityID] {
+			open = append(open, candidate)
+		}
+	}
+	return open, nil
+}
+
+// transferCandidate describes the bank movement a transfer is expected to produce
+// on the escrow account: investments flow in, everything else flows out
+func transferCandidate(transfer *entities.FundTransfer) *entities.ReconciliationCandidate {
+	direction := entities.StatementLineDirectionDebit
+	if transfer.TransferType == entities.TransferTypeLnvestment {
+		direction = entities.StatementLineDirectionCredit
+	}
+
+	reference := transfer.PaymentReference
+	if reference == "" {
+		reference = transfer.TransferNumber
+	}
+
+	expectedDate := transfer.CreatedAt
+	if transfer.ProcessedAt != nil {
+		expectedDate = *transfer.ProcessedAt
+	} else if transfer.ScheduledAt != nil {
+		expectedDate = *transfer.ScheduledAt
+	}
+
+	amount := transfer.NetAmount
+	if direction == entities.StatementLineDirectionCredit {
+		amount = transfer.Amount
+	}
+
+	return &entities.ReconciliationCandidate{
+		EntityType:   entities.ReconciliationMatchTypeFundTransfer,
+		EntityID:     transfer.ID,
+		Direction:    direction,
+		Reference:    reference,
+		Amount:       amount,
+		ExpectedDate: expectedDate,
+	}
+}
+
+// settle confirms the matched investment or transfer and marks the line matched
+func (s *bankReconciliationService) settle(ctx context.Context, line *entities.BankStatementLine, candidate *entities.ReconciliationCandidate, method string, userID uuid.UUID) error {
+	switch candidate.EntityType {
+	case entities.ReconciliationMatchTypeInvestment:
+		_, err := s.investmentFundingService.ConfirmInvestmentPayment(ctx, candidate.EntityID, line.CooperativeID, &entities.InvestmentPaymentConfirmation{
+			Amount:            line.Amount,
+			Reference:         line.Reference,
+			BankTransactionID: line.BankTransactionID,
+			Source:            entities.PaymentConfirmationSourceBankStatement,
+			PaidAt:            line.ValueDate,
+		}, userID)
+		if err != nil {
+			return err
+		}
+	case entities.ReconciliationMatchTypeFundTransfer:
+		completedAt := line.ValueDate
+		_, err := s.fundMonitoringService.UpdateFundTransfer(ctx, candidate.EntityID, &entities.UpdateFundTransferRequest{
+			Status:            entities.TransferStatusCompleted,
+			BankTransactionID: line.BankTransactionID,
+			CompletedAt:       &completedAt,
+		}, userID)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown match type: %s", candidate.EntityType)
+	}
+
+	now := time.Now()
+	entityID := candidate.EntityID
+	line.Status = entities.StatementLineStatusMatched
+	line.ExceptionReason = ""
+	line.MatchType = candidate.EntityType
+	line.MatchedEntityID = &entityID
+	line.MatchMethod = method
+	line.MatchedBy = &userID
+	line.MatchedAt = &now
+
+	return s.reconciliationRepo.UpdateLine(ctx, line)
+}
+
+// ManualMatch resolves an exception by matching the line to an investment or transfer
+func (s *bankReconciliationService) ManualMatch(ctx context.Context, lineID uuid.UUID, req *entities.ManualMatchRequest, userID uuid.UUID) (*entities.BankStatementLine, error) {
+	line, err := s.reconciliationRepo.GetLine(ctx, lineID)
+	if err != nil {
+		return nil, err
+	}
+	if line.Status == entities.StatementLineStatusMatched {
+		return nil, fmt.Errorf("statement line is already matched")
+	}
+
+	matched, err := s.reconciliationRepo.MatchedEntityIDs(ctx, line.CooperativeID, []uuid.UUID{req.EntityID})
+	if err != nil {
+		return nil, err
+	}
+	if matched[req.EntityID] {
+		return nil, fmt.Errorf("%s %s is already matched to another statement line", req.MatchType, req.EntityID)
+	}
+
+	line.Notes = req.Notes
+	candidate := &entities.ReconciliationCandidate{EntityType: req.MatchType, EntityID: req.EntityID}
+	if err := s.settle(ctx, line, candidate, entities.ReconciliationMatchMethodManual, userID); err != nil {
+		return nil, fmt.Errorf("failed to match statement line: %w", err)
+	}
+
+	s.refreshCounts(ctx, line)
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "match_bank_statement_line",
+		EntityType: "bank_statement_line",
+		EntityID:   line.ID,
+		NewValues:  fmt.Sprintf("Matched %s line %s to %s %s", line.Amount, line.BankTransactionID, req.MatchType, req.EntityID),
+	})
+
+	return line, nil
+}
+
+// IgnoreLine resolves an exception that needs no match, such as bank charges or interest
+func (s *bankReconciliationService) IgnoreLine(ctx context.Context, lineID uuid.UUID, req *entities.IgnoreStatementLineRequest, userID uuid.UUID) (*entities.BankStatementLine, error) {
+	line, err := s.reconciliationRepo.GetLine(ctx, lineID)
+	if err != nil {
+		return nil, err
+	}
+	if line.Status == entities.StatementLineStatusMatched {
+		return nil, fmt.Errorf("statement line is already matched")
+	}
+
+	now := time.Now()
+	line.Status = entities.StatementLineStatusIgnored
+	line.Notes = req.Reason
+	line.MatchedBy = &userID
+	line.MatchedAt = &now
+
+	if err := s.reconciliationRepo.UpdateLine(ctx, line); err != nil {
+		return nil, err
+	}
+
+	s.refreshCounts(ctx, line)
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "ignore_bank_statement_line",
+		EntityType: "bank_statement_line",
+		EntityID:   line.ID,
+		NewValues:  fmt.Sprintf("Ignored %s line %s: %s", line.Amount, line.BankTransactionID, req.Reason),
+	})
+
+	return line, nil
+}
+
+// refreshCounts updates the statement's matched and exception counts after a manual resolution
+func (s *bankReconciliationService) refreshCounts(ctx context.Context, line *entities.BankStatementLine) {
+	statement := &entities.BankStatement{ID: line.StatementID, CooperativeID: line.CooperativeID}
+	// The counts are informational; a failure here does not undo the resolution
+	_ = s.reconciliationRepo.RefreshStatementCounts(ctx, statement)
+}
+
+// GetStatement gets an imported statement
+func (s *bankReconciliationService) GetStatement(ctx context.Context, statementID uuid.UUID) (*entities.BankStatement, error) {
+	return s.reconciliationRepo.GetStatement(ctx, statementID)
+}
+
+// GetStatements lists a cooperative's imported statements
+func (s *bankReconciliationService) GetStatements(ctx context.Context, cooperativeID uuid.UUID, page, limit int) ([]*entities.BankStatement, int, error) {
+	page, limit = normalizePage(page, limit)
+	return s.reconciliationRepo.ListStatements(ctx, cooperativeID, limit, (page-1)*limit)
+}
+
+// GetStatementLines lists statement lines
+func (s *bankReconciliationService) GetStatementLines(ctx context.Context, filter *entities.BankStatementLineFilter) ([]*entities.BankStatementLine, int, error) {
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	return s.reconciliationRepo.ListLines(ctx, filter)
+}
+
+// GetExceptions lists the lines waiting for manual resolution
+func (s *bankReconciliationService) GetExceptions(ctx context.Context, cooperativeID uuid.UUID, page, limit int) ([]*entities.BankStatementLine, int, error) {
+	return s.GetStatementLines(ctx, &entities.BankStatementLineFilter{
+		CooperativeID: cooperativeID,
+		Status:        entities.StatementLineStatusException,
+		Page:          page,
+		Limit:         limit,
+	})
+}
+
+// paymentReferencePattern matches investment payment references and transfer numbers
+var paymentReferencePattern = regexp.MustCompile(`^(INV-[0-9A-F]{12}|TXN-\d{14}-[0-9a-f]{8})$`)
+
+// ValidatePaymentReference reports whether a reference quoted by a payer is well
+// formed and belongs to an investment or transfer of the cooperative that is
+// still awaiting payment
+func (s *bankReconciliationService) ValidatePaymentReference(ctx context.Context, cooperativeID uuid.UUID, reference string) (bool, error) {
+	reference = strings.TrimSpace(reference)
+	if !paymentReferencePattern.MatchString(reference) {
+		return false, nil
+	}
+
+	candidates, err := s.getCandidates(ctx, cooperativeID)
+	if err != nil {
+		return false, err
+	}
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate.Reference, reference) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// normalizeReference strips everything but letters and digits so that references
+// survive the reformatting banks apply to remittance information
+func normalizeReference(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// matchStatementLine finds the candidate a statement line settles. A quoted
+// reference takes precedence; without one, a line matches only a single candidate
+// with the same amount expected within the date tolerance. When no candidate
+// matches, the exception reason is returned instead.
+func matchStatementLine(line *entities.BankStatementLine, candidates []*entities.ReconciliationCandidate, tolerance entities.ReconciliationTolerance) (*entities.ReconciliationCandidate, string, string) {
+	text := normalizeReference(line.Reference + " " + line.Description)
+
+	var referenced, amountDate []*entities.ReconciliationCandidate
+	currencyMismatch := false
+	for _, candidate := range candidates {
+		if candidate.Direction != line.Direction {
+			continue
+		}
+
+		reference := normalizeReference(candidate.Reference)
+		hasReference := reference != "" && strings.Contains(text, reference)
+
+		if candidate.Amount.Currency() != line.Amount.Currency() {
+			currencyMismatch = currencyMismatch || hasReference
+			continue
+		}
+
+		if hasReference {
+			referenced = append(referenced, candidate)
+			continue
+		}
+
+		days := line.ValueDate.Sub(candidate.ExpectedDate).Hours() / 24
+		if candidate.Amount.Equal(line.Amount) && days >= -float64(tolerance.DateDays) && days <= float64(tolerance.DateDays) {
+			amountDate = append(amountDate, candidate)
+		}
+	}
+
+	if len(referenced) > 0 {
+		var withinTolerance []*entities.ReconciliationCandidate
+		for _, candidate := range referenced {
+			diff := line.Amount.Sub(candidate.Amount).Abs()
+			if diff.MinorUnits() <= tolerance.AmountMinorUnits {
+				withinTolerance = append(withinTolerance, candidate)
+			}
+		}
+		switch len(withinTolerance) {
+		case 1:
+			return withinTolerance[0], entities.ReconciliationMatchMethodReference, ""
+		case 0:
+			return nil, "", entities.ReconciliationExceptionAmountMismatch
+		default:
+			return nil, "", entities.ReconciliationExceptionAmbiguous
+		}
+	}
+
+	if currencyMismatch {
+		return nil, "", entities.ReconciliationExceptionCurrency
+	}
+
+	switch len(amountDate) {
+	case 1:
+		return amountDate[0], entities.ReconciliationMatchMethodAmountDate, ""
+	case 0:
+		return nil, "", entities.ReconciliationExceptionNoMatch
+	default:
+		return nil, "", entities.ReconciliationExceptionAmbiguous
+	}
+}
+
+func removeCandidate(candidates []*entities.ReconciliationCandidate, matched *entities.ReconciliationCandidate) []*entities.ReconciliationCandidate {
+	remaining := make([]*entities.ReconciliationCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate != matched {
+			remaining = append(remaining, candidate)
+		}
+	}
+	return remaining
+}
+
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return page, limit
+}
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testCSVStatement = `Date,Description,Reference,Debit,Credit,Transaction ID
+2024-03-01,TRANSFER FROM AHMAD,INV-0A1B2C3D4E5F,,"1,500,000.00",TX001
+2024-03-02,PROFIT PAYOUT,TXN-20240302101500-abcd1234,"250,000.00",,TX002
+2024-03-02,BANK CHARGE,,"6,500.00",,
+`
+
+const testMT940Statement = `:20:STMT240301
+:25:1234567890
+:28C:00001/001
+:60F:C240229IDR1000000,00
+:61:2403010301C1500000,00NTRFINV-0A1B2C3D4E5F//TX001
+:86:TRANSFER FROM AHMAD
+ INVESTMENT PAYMENT
+:61:240302RD250000,00NTRFNONREF//TX002
+:86:RETURNED PAYOUT
+:62F:C240302IDR2750000,00
+-
+`
+
+func TestParseBankStatement_CSVDebitCredit(t *testing.T) {
+	statement, lines, err := ParseBankStatement(entities.BankStatementFormatCSV, strings.NewReader(testCSVStatement), "idr")
+
+	require.NoError(t, err)
+	assert.Equal(t, "IDR", statement.Currency)
+	assert.Equal(t, 3, statement.LineCount)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), *statement.PeriodStart)
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), *statement.PeriodEnd)
+
+	require.Len(t, lines, 3)
+	assert.Equal(t, entities.StatementLineDirectionCredit, lines[0].Direction)
+	assert.Equal(t, entities.MustParseMoney("1500000", "IDR"), lines[0].Amount)
+	assert.Equal(t, "INV-0A1B2C3D4E5F", lines[0].Reference)
+	assert.Equal(t, "TX001", lines[0].BankTransactionID)
+
+	assert.Equal(t, entities.StatementLineDirectionDebit, lines[1].Direction)
+	assert.Equal(t, entities.MustParseMoney("250000", "IDR"), lines[1].Amount)
+
+	// Lines without a bank transaction ID get a synthetic one
+	assert.Equal(t, "20240302-d-650000-", lines[2].BankTransactionID)
+}
+
+func TestParseBankStatement_CSVSignedAmount(t *testing.T) {
+	csv := "\ufeffTanggal,Keterangan,Amount,Currency\n" +
+		"01/03/2024,SETORAN,\"1.500.000,00\",IDR\n" +
+		"01/03/2024,SETORAN,\"1.500.000,00\",IDR\n" +
+		"02/03/2024,BIAYA ADMIN,\"-6.500,00\",IDR\n"
+
+	_, lines, err := ParseBankStatement(entities.BankStatementFormatCSV, strings.NewReader(csv), "")
+
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	assert.Equal(t, entities.MustParseMoney("1500000", "IDR"), lines[0].Amount)
+	assert.Equal(t, entities.StatementLineDirectionDebit, lines[2].Direction)
+	assert.Equal(t, entities.MustParseMoney("6500", "IDR"), lines[2].Amount)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), lines[0].ValueDate)
+
+	// Identical lines in one statement keep distinct transaction IDs
+	assert.NotEqual(t, lines[0].BankTransactionID, lines[1].BankTransactionID)
+}
+
+func TestParseBankStatement_CSVMissingCurrency(t *testing.T) {
+	_, _, err := ParseBankStatement(entities.BankStatementFormatCSV, strings.NewReader("date,amount\n2024-03-01,100\n"), "")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "currency is required")
+}
+
+func TestParseBankStatement_MT940(t *testing.T) {
+	statement, lines, err := ParseBankStatement(entities.BankStatementFormatMT940, strings.NewReader(testMT940Statement), "")
+
+	require.NoError(t, err)
+	assert.Equal(t, "STMT240301", statement.StatementRef)
+	assert.Equal(t, "1234567890", statement.AccountNumber)
+	assert.Equal(t, "IDR", statement.Currency)
+	assert.Equal(t, entities.MustParseMoney("1000000", "IDR"), *statement.OpeningBalance)
+	assert.Equal(t, entities.MustParseMoney("2750000", "IDR"), *statement.ClosingBalance)
+
+	require.Len(t, lines, 2)
+	assert.Equal(t, entities.StatementLineDirectionCredit, lines[0].Direction)
+	assert.Equal(t, "INV-0A1B2C3D4E5F", lines[0].Reference)
+	assert.Equal(t, "TX001", lines[0].BankTransactionID)
+	assert.Equal(t, "TRANSFER FROM AHMAD INVESTMENT PAYMENT", lines[0].Description)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), *lines[0].BookingDate)
+
+	// A reversed debit books money back into the account
+	assert.Equal(t, entities.StatementLineDirectionCredit, lines[1].Direction)
+	assert.Equal(t, "", lines[1].Reference)
+	assert.Equal(t, "RETURNED PAYOUT", lines[1].Description)
+}
+
+func TestParseBankStatement_UnsupportedFormat(t *testing.T) {
+	_, _, err := ParseBankStatement("camt053", strings.NewReader(""), "IDR")
+
+	assert.Error(t, err)
+}
+
+func TestParseStatementAmount(t *testing.T) {
+	testCases := []struct {
+		value    string
+		currency string
+		expected string
+	}{
+		{"1,500,000.00", "IDR", "1500000"},
+		{"1.500.000,00", "IDR", "1500000"},
+		{"1.500.000", "IDR", "1500000"},
+		{"1,500", "IDR", "1500"},
+		{"12,50", "MYR", "12.50"},
+		{"-250.75", "MYR", "250.75"},
+		{"250.75 DR", "MYR", "250.75"},
+		{"Rp 10.000,00", "IDR", "10000"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			amount, err := parseStatementAmount(tc.value, tc.currency)
+
+			require.NoError(t, err)
+			assert.Equal(t, entities.MustParseMoney(tc.expected, tc.currency), amount)
+		})
+	}
+}
+
+func TestMatchStatementLine(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	credit := entities.StatementLineDirectionCredit
+	debit := entities.StatementLineDirectionDebit
+	statementLine := func(direction, amount, reference string, valueDate time.Time) *entities.BankStatementLine {
+		return &entities.BankStatementLine{ID: uuid.New(), Direction: direction, Amount: idr(amount), Currency: "IDR",
+			Reference: reference, ValueDate: valueDate, Status: entities.StatementLineStatusUnmatched}
+	}
+	investmentCandidate := func(direction, amount, reference string, expectedDate time.Time) *entities.ReconciliationCandidate {
+		return &entities.ReconciliationCandidate{EntityType: entities.ReconciliationMatchTypeInvestment, EntityID: uuid.New(),
+			Direction: direction, Reference: reference, Amount: idr(amount), ExpectedDate: expectedDate}
+	}
+
+	byReference := investmentCandidate(credit, "1500000", "INV-0A1B2C3D4E5F", day.AddDate(0, 0, -10))
+	sameAmount := investmentCandidate(credit, "1500000", "INV-FFFFFFFFFFFF", day)
+	payout := investmentCandidate(debit, "250000", "TXN-20240302101500-abcd1234", day)
+
+	testCases := []struct {
+		name       string
+		line       *entities.BankStatementLine
+		candidates []*entities.ReconciliationCandidate
+		expected   *entities.ReconciliationCandidate
+		method     string
+		reason     string
+	}{
+		{
+			name:       "Reference reformatted by bank",
+			line:       statementLine(credit, "1500000", "inv 0a1b2c3d4e5f", day),
+			candidates: []*entities.ReconciliationCandidate{byReference, sameAmount, payout},
+			expected:   byReference,
+			method:     entities.ReconciliationMatchMethodReference,
+		},
+		{
+			name:       "Reference with wrong amount",
+			line:       statementLine(credit, "1000000", "INV-0A1B2C3D4E5F", day),
+			candidates: []*entities.ReconciliationCandidate{byReference},
+			reason:     entities.ReconciliationExceptionAmountMismatch,
+		},
+		{
+			name:       "Amount and date",
+			line:       statementLine(credit, "1500000", "", day.AddDate(0, 0, 2)),
+			candidates: []*entities.ReconciliationCandidate{byReference, sameAmount},
+			expected:   sameAmount,
+			method:     entities.ReconciliationMatchMethodAmountDate,
+		},
+		{
+			name:       "Amount outside date tolerance",
+			line:       statementLine(credit, "1500000", "", day.AddDate(0, 0, 5)),
+			candidates: []*entities.ReconciliationCandidate{sameAmount},
+			reason:     entities.ReconciliationExceptionNoMatch,
+		},
+		{
+			name:       "Ambiguous amount",
+			line:       statementLine(credit, "1500000", "", day),
+			candidates: []*entities.ReconciliationCandidate{sameAmount, investmentCandidate(credit, "1500000", "INV-EEEEEEEEEEEE", day)},
+			reason:     entities.ReconciliationExceptionAmbiguous,
+		},
+		{
+			name:       "Direction must agree",
+			line:       statementLine(credit, "250000", "TXN-20240302101500-abcd1234", day),
+			candidates: []*entities.ReconciliationCandidate{payout},
+			reason:     entities.ReconciliationExceptionNoMatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			candidate, method, reason := matchStatementLine(tc.line, tc.candidates, DefaultReconciliationTolerance)
+
+			assert.Equal(t, tc.expected, candidate)
+			assert.Equal(t, tc.method, method)
+			assert.Equal(t, tc.reason, reason)
+		})
+	}
+}
+
+func TestMatchStatementLine_CurrencyMismatch(t *testing.T) {
+	day := time.Now()
+	line := &entities.BankStatementLine{ID: uuid.New(), Direction: entities.StatementLineDirectionCredit, Amount: idr("1500000"), Currency: "IDR",
+		Reference: "INV-0A1B2C3D4E5F", ValueDate: day, Status: entities.StatementLineStatusUnmatched}
+	candidate := &entities.ReconciliationCandidate{EntityType: entities.ReconciliationMatchTypeInvestment, EntityID: uuid.New(),
+		Direction: entities.StatementLineDirectionCredit, Reference: "INV-0A1B2C3D4E5F", Amount: entities.MustParseMoney("100", "MYR"), ExpectedDate: day}
+
+	matched, _, reason := matchStatementLine(line, []*entities.ReconciliationCandidate{candidate}, DefaultReconciliationTolerance)
+
+	assert.Nil(t, matched)
+	assert.Equal(t, entities.ReconciliationExceptionCurrency, reason)
+}
+
+func TestMatchStatementLine_AmountTolerance(t *testing.T) {
+	day := time.Now()
+	line := &entities.BankStatementLine{ID: uuid.New(), Direction: entities.StatementLineDirectionCredit, Amount: idr("1493500"), Currency: "IDR",
+		Reference: "INV-0A1B2C3D4E5F", ValueDate: day, Status: entities.StatementLineStatusUnmatched}
+	candidate := &entities.ReconciliationCandidate{EntityType: entities.ReconciliationMatchTypeInvestment, EntityID: uuid.New(),
+		Direction: entities.StatementLineDirectionCredit, Reference: "INV-0A1B2C3D4E5F", Amount: idr("1500000"), ExpectedDate: day}
+
+	// Bank charges of 6,500 deducted in transit
+	tolerance := entities.ReconciliationTolerance{AmountMinorUnits: 1000000, DateDays: 3}
+	matched, method, _ := matchStatementLine(line, []*entities.ReconciliationCandidate{candidate}, tolerance)
+
+	assert.Equal(t, candidate, matched)
+	assert.Equal(t, entities.ReconciliationMatchMethodReference, method)
+}
+
+func TestBankReconciliationService_ImportStatement(t *testing.T) {
+	mockRepo := new(MockBankReconciliationRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	investmentFundingService := NewInvestmentFundingService(mockAuditService, nil, nil, nil, nil, nil, 0)
+	fundMonitoringService := NewFundMonitoringService(mockAuditService, nil, nil, nil, nil, DefaultTransferRetryPolicy)
+	reconciliationService := NewBankReconciliationService(mockRepo, investmentFundingService, fundMonitoringService, mockAuditService, DefaultReconciliationTolerance)
+	ctx := context.Background()
+	cooperativeID := uuid.New()
+
+	mockRepo.On("GetStatementByFileHash", ctx, cooperativeID, mock.AnythingOfType("string")).Return(nil, nil)
+	mockRepo.On("ExistingTransactionIDs", ctx, cooperativeID, mock.Anything).Return(map[string]bool{"TX001": true}, nil)
+	mockRepo.On("CreateStatement", ctx, mock.AnythingOfType("*entities.BankStatement"), mock.Anything).Return(nil)
+	mockRepo.On("UpdateLine", ctx, mock.AnythingOfType("*entities.BankStatementLine")).Return(nil)
+	mockRepo.On("RefreshStatementCounts", ctx, mock.AnythingOfType("*entities.BankStatement")).Return(nil)
+
+	req := &entities.ImportBankStatementRequest{CooperativeID: cooperativeID, Format: entities.BankStatementFormatCSV, Currency: "IDR"}
+	result, err := reconciliationService.ImportStatement(ctx, req, "march.csv", strings.NewReader(testCSVStatement), uuid.New())
+
+	require.NoError(t, err)
+	assert.Equal(t, cooperativeID, result.Statement.CooperativeID)
+	assert.Len(t, result.Statement.FileHash, 64)
+
+	// TX001 came with an earlier statement; with nothing pending the rest go to the exceptions queue
+	assert.Equal(t, 2, result.Statement.LineCount)
+	assert.Empty(t, result.Matched)
+	require.Len(t, result.Exceptions, 2)
+	for _, line := range result.Exceptions {
+		assert.Equal(t, result.Statement.ID, line.StatementID)
+		assert.Equal(t, entities.StatementLineStatusException, line.Status)
+		assert.Equal(t, entities.ReconciliationExceptionNoMatch, line.ExceptionReason)
+	}
+}
+
+func TestBankReconciliationService_ImportStatement_Duplicate(t *testing.T) {
+	mockRepo := new(MockBankReconciliationRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	investmentFundingService := NewInvestmentFundingService(mockAuditService, nil, nil, nil, nil, nil, 0)
+	fundMonitoringService := NewFundMonitoringService(mockAuditService, nil, nil, nil, nil, DefaultTransferRetryPolicy)
+	reconciliationService := NewBankReconciliationService(mockRepo, investmentFundingService, fundMonitoringService, mockAuditService, DefaultReconciliationTolerance)
+	ctx := context.Background()
+	cooperativeID := uuid.New()
+
+	mockRepo.On("GetStatementByFileHash", ctx, cooperativeID, mock.AnythingOfType("string")).
+		Return(&entities.BankStatement{ID: uuid.New(), ImportedAt: time.Now()}, nil)
+
+	req := &entities.ImportBankStatementRequest{CooperativeID: cooperativeID, Format: entities.BankStatementFormatCSV, Currency: "IDR"}
+	result, err := reconciliationService.ImportStatement(ctx, req, "march.csv", strings.NewReader(testCSVStatement), uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "already imported")
+	mockRepo.AssertNotCalled(t, "CreateStatement", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBankReconciliationService_IgnoreLine(t *testing.T) {
+	mockRepo := new(MockBankReconciliationRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	investmentFundingService := NewInvestmentFundingService(mockAuditService, nil, nil, nil, nil, nil, 0)
+	fundMonitoringService := NewFundMonitoringService(mockAuditService, nil, nil, nil, nil, DefaultTransferRetryPolicy)
+	reconciliationService := NewBankReconciliationService(mockRepo, investmentFundingService, fundMonitoringService, mockAuditService, DefaultReconciliationTolerance)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	line := &entities.BankStatementLine{ID: uuid.New(), Direction: entities.StatementLineDirectionDebit, Amount: idr("6500"), Currency: "IDR",
+		ValueDate: time.Now(), Status: entities.StatementLineStatusException}
+	mockRepo.On("GetLine", ctx, line.ID).Return(line, nil)
+	mockRepo.On("UpdateLine", ctx, line).Return(nil)
+	mockRepo.On("RefreshStatementCounts", ctx, mock.AnythingOfType("*entities.BankStatement")).Return(nil)
+
+	result, err := reconciliationService.IgnoreLine(ctx, line.ID, &entities.IgnoreStatementLineRequest{Reason: "Monthly bank charge"}, userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatementLineStatusIgnored, result.Status)
+	assert.Equal(t, "Monthly bank charge", result.Notes)
+	assert.Equal(t, userID, *result.MatchedBy)
+}
+
+func TestBankReconciliationService_ManualMatch_AlreadyMatched(t *testing.T) {
+	mockRepo := new(MockBankReconciliationRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	investmentFundingService := NewInvestmentFundingService(mockAuditService, nil, nil, nil, nil, nil, 0)
+	fundMonitoringService := NewFundMonitoringService(mockAuditService, nil, nil, nil, nil, DefaultTransferRetryPolicy)
+	reconciliationService := NewBankReconciliationService(mockRepo, investmentFundingService, fundMonitoringService, mockAuditService, DefaultReconciliationTolerance)
+	ctx := context.Background()
+	investmentID := uuid.New()
+
+	line := &entities.BankStatementLine{ID: uuid.New(), Direction: entities.StatementLineDirectionCredit, Amount: idr("1500000"), Currency: "IDR",
+		ValueDate: time.Now(), Status: entities.StatementLineStatusException}
+	mockRepo.On("GetLine", ctx, line.ID).Return(line, nil)
+	mockRepo.On("MatchedEntityIDs", ctx, line.CooperativeID, []uuid.UUID{investmentID}).Return(map[uuid.UUID]bool{investmentID: true}, nil)
+
+	req := &entities.ManualMatchRequest{MatchType: entities.ReconciliationMatchTypeInvestment, EntityID: investmentID}
+	result, err := reconciliationService.ManualMatch(ctx, line.ID, req, uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "already matched")
+	mockRepo.AssertNotCalled(t, "UpdateLine", mock.Anything, mock.Anything)
+}
+
+func TestBankReconciliationService_ValidatePaymentReference(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	investmentFundingService := NewInvestmentFundingService(mockAuditService, nil, nil, nil, nil, nil, 0)
+	fundMonitoringService := NewFundMonitoringService(mockAuditService, nil, nil, nil, nil, DefaultTransferRetryPolicy)
+	reconciliationService := NewBankReconciliationService(new(MockBankReconciliationRepository), investmentFundingService, fundMonitoringService, mockAuditService, DefaultReconciliationTolerance)
+	ctx := context.Background()
+
+	// Malformed references are rejected without looking up pending payments
+	valid, err := reconciliationService.ValidatePaymentReference(ctx, uuid.New(), "PAY123")
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
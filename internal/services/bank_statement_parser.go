@@ -0,0 +1,436 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// ParseBankStatement parses a statement file in the given format. The returned
+// statement and lines are not yet tied to a cooperative or persisted. currency
+// is used for lines that do not state their own currency.
+func ParseBankStatement(format string, r io.Reader, currency string) (*entities.BankStatement, []*entities.BankStatementLine, error) {
+	switch format {
+	case entities.BankStatementFormatCSV:
+		return parseCSVStatement(r, currency)
+	case entities.BankStatementFormatMT940:
+		return parseMT940Statement(r, currency)
+	default:
+		return nil, nil, fmt.Errorf("unsupported bank statement format: %s", format)
+	}
+}
+
+// csvStatementColumns maps the header names used by the supported banks' CSV
+// exports to statement line fields
+var csvStatementColumns = map[string]string{
+	"date":                 "value_date",
+	"value_date":           "value_date",
+	"transaction_date":     "value_date",
+	"tanggal":              "value_date",
+	"booking_date":         "booking_date",
+	"posting_date":         "booking_date",
+	"description":          "description",
+	"narrative":            "description",
+	"remarks":              "description",
+	"keterangan":           "description",
+	"reference":            "reference",
+	"ref":                  "reference",
+	"payment_reference":    "reference",
+	"amount":               "amount",
+	"debit":                "debit",
+	"credit":               "credit",
+	"currency":             "currency",
+	"transaction_id":       "transaction_id",
+	"bank_reference":       "transaction_id",
+	"counterparty":         "counterparty_name",
+	"counterparty_name":    "counterparty_name",
+	"counterparty_account": "counterparty_account",
+}
+
+// parseCSVStatement parses a CSV statement with a header row. Amounts are either
+// a signed amount column or separate debit and credit columns.
+func parseCSVStatement(r io.Reader, currency string) (*entities.BankStatement, []*entities.BankStatementLine, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		key = strings.ReplaceAll(key, " ", "_")
+		if field, ok := csvStatementColumns[key]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+
+	if _, ok := columns["value_date"]; !ok {
+		return nil, nil, fmt.Errorf("CSV statement has no date column")
+	}
+	_, hasAmount := columns["amount"]
+	_, hasDebit := columns["debit"]
+	_, hasCredit := columns["credit"]
+	if !hasAmount && !(hasDebit || hasCredit) {
+		return nil, nil, fmt.Errorf("CSV statement has no amount or debit/credit columns")
+	}
+	if _, ok := columns["currency"]; !ok && currency == "" {
+		return nil, nil, fmt.Errorf("currency is required for CSV statements without a currency column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	statement := &entities.BankStatement{Format: entities.BankStatementFormatCSV, Currency: strings.ToUpper(currency)}
+	var lines []*entities.BankStatementLine
+
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		line := &entities.BankStatementLine{
+			ID:                  uuid.New(),
+			LineNumber:          len(lines) + 1,
+			Reference:           field(record, "reference"),
+			Description:         field(record, "description"),
+			CounterpartyName:    field(record, "counterparty_name"),
+			CounterpartyAccount: field(record, "counterparty_account"),
+			BankTransactionID:   field(record, "transaction_id"),
+			Status:              entities.StatementLineStatusUnmatched,
+		}
+
+		line.ValueDate, err = parseStatementDate(field(record, "value_date"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		if booking := field(record, "booking_date"); booking != "" {
+			bookingDate, err := parseStatementDate(booking)
+			if err != nil {
+				return nil, nil, fmt.Errorf("row %d: %w", row, err)
+			}
+			line.BookingDate = &bookingDate
+		}
+
+		line.Currency = strings.ToUpper(field(record, "currency"))
+		if line.Currency == "" {
+			line.Currency = statement.Currency
+		}
+
+		var amount string
+		switch {
+		case hasAmount && field(record, "amount") != "":
+			amount = field(record, "amount")
+			line.Direction = entities.StatementLineDirectionCredit
+			if strings.HasPrefix(amount, "-") || strings.HasSuffix(strings.ToUpper(amount), "DR") {
+				line.Direction = entities.StatementLineDirectionDebit
+			}
+		case field(record, "credit") != "" && !isZeroAmount(field(record, "credit")):
+			amount = field(record, "credit")
+			line.Direction = entities.StatementLineDirectionCredit
+		case field(record, "debit") != "" && !isZeroAmount(field(record, "debit")):
+			amount = field(record, "debit")
+			line.Direction = entities.StatementLineDirectionDebit
+		default:
+			return nil, nil, fmt.Errorf("row %d: no amount", row)
+		}
+
+		line.Amount, err = parseStatementAmount(amount, line.Currency)
+		if err != nil {
+			return nil, nil, fmt.Errorf("row %d: %w", row, err)
+		}
+
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return nil, nil, fmt.Errorf("CSV statement has no transactions")
+	}
+	assignTransactionIDs(lines)
+	setStatementPeriod(statement, lines)
+
+	return statement, lines, nil
+}
+
+var (
+	// :61:YYMMDD[MMDD](C|D|RC|RD)[funds code]amount N<type>reference[//bank reference]
+	mt940LinePattern    = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?([\d,]+)N([A-Z0-9]{3})([^/]*)(?://(.*))?$`)
+	mt940BalancePattern = regexp.MustCompile(`^(C|D)(\d{6})([A-Z]{3})([\d,]+)$`)
+	mt940TagPattern     = regexp.MustCompile(`^:(\d{2}[A-Z]?):(.*)$`)
+)
+
+// parseMT940Statement parses a SWIFT MT940 customer statement. Only the first
+// statement of a multi-statement file is read.
+func parseMT940Statement(r io.Reader, currency string) (*entities.BankStatement, []*entities.BankStatementLine, error) {
+	statement := &entities.BankStatement{Format: entities.BankStatementFormatMT940, Currency: strings.ToUpper(currency)}
+	var lines []*entities.BankStatementLine
+
+	// Fields may wrap over several physical lines; join them before parsing
+	var tags []struct{ tag, value string }
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r ")
+		if text == "" || text == "-" || strings.HasPrefix(text, "{") {
+			continue
+		}
+		if match := mt940TagPattern.FindStringSubmatch(text); match != nil {
+			tags = append(tags, struct{ tag, value string }{match[1], match[2]})
+			continue
+		}
+		if len(tags) > 0 {
+			tags[len(tags)-1].value += "\n" + text
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read MT940 statement: %w", err)
+	}
+
+	var current *entities.BankStatementLine
+parse:
+	for _, t := range tags {
+		switch t.tag {
+		case "20":
+			if statement.StatementRef != "" {
+				// Start of a second statement in the same file
+				break parse
+			}
+			statement.StatementRef = strings.TrimSpace(t.value)
+		case "25":
+			statement.AccountNumber = strings.TrimSpace(t.value)
+		case "60F", "60M":
+			balance, balanceCurrency, err := parseMT940Balance(t.value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("opening balance: %w", err)
+			}
+			statement.OpeningBalance = &balance
+			statement.Currency = balanceCurrency
+		case "62F", "62M":
+			balance, _, err := parseMT940Balance(t.value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("closing balance: %w", err)
+			}
+			statement.ClosingBalance = &balance
+		case "61":
+			line, err := parseMT940Line(t.value, statement.Currency)
+			if err != nil {
+				return nil, nil, fmt.Errorf("statement line %d: %w", len(lines)+1, err)
+			}
+			line.LineNumber = len(lines) + 1
+			lines = append(lines, line)
+			current = line
+		case "86":
+			// Information to account owner for the preceding :61: line
+			if current != nil {
+				info := strings.Join(strings.Fields(strings.ReplaceAll(t.value, "\n", " ")), " ")
+				if current.Description == "" {
+					current.Description = info
+				} else {
+					current.Description += " " + info
+				}
+			}
+		}
+	}
+
+	if statement.Currency == "" {
+		return nil, nil, fmt.Errorf("MT940 statement has no opening balance currency")
+	}
+	if len(lines) == 0 {
+		return nil, nil, fmt.Errorf("MT940 statement has no transactions")
+	}
+	assignTransactionIDs(lines)
+	setStatementPeriod(statement, lines)
+
+	return statement, lines, nil
+}
+
+func parseMT940Line(value, currency string) (*entities.BankStatementLine, error) {
+	first, rest, _ := strings.Cut(value, "\n")
+	match := mt940LinePattern.FindStringSubmatch(strings.TrimSpace(first))
+	if match == nil {
+		return nil, fmt.Errorf("invalid :61: field %q", first)
+	}
+
+	valueDate, err := time.Parse("060102", match[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid value date %q", match[1])
+	}
+
+	line := &entities.BankStatementLine{
+		ID:                uuid.New(),
+		ValueDate:         valueDate,
+		Currency:          currency,
+		Reference:         strings.TrimSpace(match[7]),
+		BankTransactionID: strings.TrimSpace(match[8]),
+		Description:       strings.TrimSpace(rest),
+		Status:            entities.StatementLineStatusUnmatched,
+	}
+	if line.Reference == "NONREF" {
+		line.Reference = ""
+	}
+
+	// Entry date carries no year; take the value date's, rolling over at year end
+	if match[2] != "" {
+		bookingDate, err := time.Parse("0102", match[2])
+		if err == nil {
+			bookingDate = bookingDate.AddDate(valueDate.Year(), 0, 0)
+			if bookingDate.Sub(valueDate) < -180*24*time.Hour {
+				bookingDate = bookingDate.AddDate(1, 0, 0)
+			}
+			line.BookingDate = &bookingDate
+		}
+	}
+
+	// Reversals book in the opposite direction
+	switch match[3] {
+	case "C", "RD":
+		line.Direction = entities.StatementLineDirectionCredit
+	case "D", "RC":
+		line.Direction = entities.StatementLineDirectionDebit
+	}
+
+	line.Amount, err = entities.ParseMoney(strings.Replace(match[5], ",", ".", 1), currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return line, nil
+}
+
+func parseMT940Balance(value string) (entities.Money, string, error) {
+	match := mt940BalancePattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return entities.Money{}, "", fmt.Errorf("invalid balance field %q", value)
+	}
+
+	amount, err := entities.ParseMoney(strings.Replace(match[4], ",", ".", 1), match[3])
+	if err != nil {
+		return entities.Money{}, "", err
+	}
+	if match[1] == "D" {
+		amount = amount.Neg()
+	}
+	return amount, match[3], nil
+}
+
+var statementDateLayouts = []string{"2006-01-02", "02/01/2006", "02-01-2006", "2006/01/02", "02.01.2006", "02 Jan 2006", "2 Jan 2006", "20060102"}
+
+// parseStatementDate parses the day-first and ISO dates used by Indonesian and Malaysian banks
+func parseStatementDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range statementDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// parseStatementAmount parses an unsigned amount in either 1,500,000.00 or
+// 1.500.000,00 notation; a sign or a CR/DR suffix is dropped
+func parseStatementAmount(value, currency string) (entities.Money, error) {
+	amount := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(value), " ", ""))
+	amount = strings.TrimSuffix(strings.TrimSuffix(amount, "CR"), "DR")
+	amount = strings.TrimLeft(amount, "+-")
+	amount = strings.TrimPrefix(strings.TrimPrefix(amount, "RP"), "RM")
+
+	lastComma := strings.LastIndex(amount, ",")
+	lastDot := strings.LastIndex(amount, ".")
+	switch {
+	case lastComma >= 0 && lastDot >= 0:
+		// Whichever separator comes last is the decimal separator
+		if lastComma > lastDot {
+			amount = strings.ReplaceAll(amount, ".", "")
+			amount = strings.Replace(amount, ",", ".", 1)
+		} else {
+			amount = strings.ReplaceAll(amount, ",", "")
+		}
+	case lastComma >= 0:
+		// A single comma followed by one or two digits is a decimal comma
+		if strings.Count(amount, ",") == 1 && len(amount)-lastComma-1 <= 2 {
+			amount = strings.Replace(amount, ",", ".", 1)
+		} else {
+			amount = strings.ReplaceAll(amount, ",", "")
+		}
+	case strings.Count(amount, ".") > 1:
+		amount = strings.ReplaceAll(amount, ".", "")
+	}
+
+	money, err := entities.ParseMoney(amount, currency)
+	if err != nil {
+		return entities.Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	return money.Abs(), nil
+}
+
+func isZeroAmount(value string) bool {
+	return strings.Trim(value, "0.,- ") == ""
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// assignTransactionIDs gives lines without a bank transaction ID a synthetic one
+// derived from their content, so that re-imports of overlapping statements do not
+// book them twice. Identical lines within one statement are numbered apart.
+func assignTransactionIDs(lines []*entities.BankStatementLine) {
+	seen := make(map[string]int)
+	for _, line := range lines {
+		if line.BankTransactionID != "" {
+			continue
+		}
+		id := fmt.Sprintf("%s-%s-%d-%s", line.ValueDate.Format("20060102"), line.Direction[:1], line.Amount.MinorUnits(), line.Reference)
+		seen[id]++
+		if seen[id] > 1 {
+			id = fmt.Sprintf("%s-%d", id, seen[id])
+		}
+		line.BankTransactionID = id
+	}
+}
+
+func setStatementPeriod(statement *entities.BankStatement, lines []*entities.BankStatementLine) {
+	start, end := lines[0].ValueDate, lines[0].ValueDate
+	for _, line := range lines[1:] {
+		if line.ValueDate.Before(start) {
+			start = line.ValueDate
+		}
+		if line.ValueDate.After(end) {
+			end = line.ValueDate
+		}
+	}
+	statement.PeriodStart = &start
+	statement.PeriodEnd = &end
+	statement.LineCount = len(lines)
+}
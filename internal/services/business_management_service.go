@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"comfunds/internal/entities"
@@ -59,12 +60,14 @@ type BusinessManagementService interface {
 }
 
 type businessManagementService struct {
-	auditService AuditService
+	auditService     AuditService
+	sanctionsService SanctionsScreeningService
 }
 
-func NewBusinessManagementService(auditService AuditService) BusinessManagementService {
+func NewBusinessManagementService(auditService AuditService, sanctionsService SanctionsScreeningService) BusinessManagementService {
 	return &businessManagementService{
-		auditService: auditService,
+		auditService:     auditService,
+		sanctionsService: sanctionsService,
 	}
 }
 
@@ -114,6 +117,17 @@ func (s *businessManagementService) CreateBusiness(ctx context.Context, req *ent
 		UpdatedAt:          time.Now(),
 	}
 
+	// Screen against sanctions lists before onboarding
+	if err := requireSanctionsClearance(ctx, s.sanctionsService, &entities.SanctionsScreeningSubject{
+		SubjectType: entities.SanctionsSubjectBusiness,
+		Reference:   "business:" + strings.ToUpper(strings.TrimSpace(req.RegistrationNumber)),
+		SubjectID:   &business.ID,
+		Name:        business.Name,
+		Purpose:     entities.SanctionsPurposeBusiness,
+	}); err != nil {
+		return nil, err
+	}
+
 	// In real implementation, save to repository
 	// createdBusiness, err := s.businessRepo.Create(ctx, business)
 
@@ -337,6 +351,7 @@ func (s *businessManagementService) GenerateFinancialReport(ctx context.Context,
 		Currency:         req.Currency,
 		TotalRevenue:     req.TotalRevenue,
 		TotalExpenses:    req.TotalExpenses,
+		InterestIncome:   req.InterestIncome,
 		NetIncome:        netIncome,
 		GrossProfit:      grossProfit,
 		OperatingIncome:  netIncome, // Simplified
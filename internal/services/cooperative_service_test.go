@@ -14,7 +14,6 @@ import (
 // Mocks are now in mocks_test.go to avoid redeclaration
 
 func TestCooperativeService_CreateCooperative_Success(t *testing.T) {
-	t.Skip("Skipping test due to interface implementation issues - needs mock service updates")
 	mockCoopRepo := new(MockCooperativeRepository)
 	mockUserRepo := new(MockUserRepositorySharded)
 	mockAuditService := new(MockAuditService)
@@ -67,16 +66,23 @@ func TestCooperativeService_CreateCooperative_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedCooperative, cooperative)
 	mockCoopRepo.AssertExpectations(t)
-	mockAuditRepo.AssertExpectations(t)
+	mockAuditService.AssertExpectations(t)
 }
 
 func TestCooperativeService_CreateCooperative_DuplicateRegistration(t *testing.T) {
-	t.Skip("Skipping test due to interface implementation issues - needs mock service updates")
 	mockCoopRepo := new(MockCooperativeRepository)
 	mockUserRepo := new(MockUserRepositorySharded)
-	mockAuditRepo := new(MockAuditRepository)
-	auditService := NewAuditService(mockAuditRepo)
-	cooperativeService := NewCooperativeService(mockCoopRepo, mockUserRepo, auditService)
+	mockAuditService := new(MockAuditService)
+	mockMemberRegistryService := new(MockMemberRegistryService)
+	cooperativeService := NewCooperativeService(
+		mockCoopRepo,
+		mockUserRepo,
+		mockAuditService,
+		new(MockInvestmentPolicyService),
+		new(MockProjectApprovalService),
+		new(MockFundMonitoringService),
+		mockMemberRegistryService,
+	)
 
 	creatorID := uuid.New()
 	req := &entities.CreateCooperativeRequest{
@@ -104,12 +110,19 @@ func TestCooperativeService_CreateCooperative_DuplicateRegistration(t *testing.T
 }
 
 func TestCooperativeService_VerifyCooperativeRegistration(t *testing.T) {
-	t.Skip("Skipping test due to interface implementation issues - needs mock service updates")
 	mockCoopRepo := new(MockCooperativeRepository)
 	mockUserRepo := new(MockUserRepositorySharded)
-	mockAuditRepo := new(MockAuditRepository)
-	auditService := NewAuditService(mockAuditRepo)
-	cooperativeService := NewCooperativeService(mockCoopRepo, mockUserRepo, auditService)
+	mockAuditService := new(MockAuditService)
+	mockMemberRegistryService := new(MockMemberRegistryService)
+	cooperativeService := NewCooperativeService(
+		mockCoopRepo,
+		mockUserRepo,
+		mockAuditService,
+		new(MockInvestmentPolicyService),
+		new(MockProjectApprovalService),
+		new(MockFundMonitoringService),
+		mockMemberRegistryService,
+	)
 
 	tests := []struct {
 		name          string
@@ -153,12 +166,19 @@ func TestCooperativeService_VerifyCooperativeRegistration(t *testing.T) {
 }
 
 func TestCooperativeService_GetCooperativeMembers(t *testing.T) {
-	t.Skip("Skipping test due to interface implementation issues - needs mock service updates")
 	mockCoopRepo := new(MockCooperativeRepository)
 	mockUserRepo := new(MockUserRepositorySharded)
-	mockAuditRepo := new(MockAuditRepository)
-	auditService := NewAuditService(mockAuditRepo)
-	cooperativeService := NewCooperativeService(mockCoopRepo, mockUserRepo, auditService)
+	mockAuditService := new(MockAuditService)
+	mockMemberRegistryService := new(MockMemberRegistryService)
+	cooperativeService := NewCooperativeService(
+		mockCoopRepo,
+		mockUserRepo,
+		mockAuditService,
+		new(MockInvestmentPolicyService),
+		new(MockProjectApprovalService),
+		new(MockFundMonitoringService),
+		mockMemberRegistryService,
+	)
 
 	cooperativeID := uuid.New()
 	expectedUsers := []*entities.User{
@@ -176,12 +196,12 @@ func TestCooperativeService_GetCooperativeMembers(t *testing.T) {
 		},
 	}
 
-	mockUserRepo.On("GetByCooperativeID", mock.Anything, cooperativeID, 10, 0).Return(expectedUsers, nil)
+	mockMemberRegistryService.On("GetCooperativeMembers", mock.Anything, cooperativeID, "active", 1, 10).Return(expectedUsers, 2, nil)
 
 	users, total, err := cooperativeService.GetCooperativeMembers(context.Background(), cooperativeID, 1, 10)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUsers, users)
-	assert.Equal(t, 2, total) // length of returned users
-	mockUserRepo.AssertExpectations(t)
+	assert.Equal(t, 2, total)
+	mockMemberRegistryService.AssertExpectations(t)
 }
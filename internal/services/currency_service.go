@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// CurrencyService manages the currency registry and converts amounts between
+// currencies at the exchange rate effective at the time of the transaction
+type CurrencyService interface {
+	// Registry
+	CreateCurrency(ctx context.Context, req *entities.CreateCurrencyRequest, userID uuid.UUID) (*entities.Currency, error)
+	ListCurrencies(ctx context.Context, activeOnly bool) ([]*entities.Currency, error)
+	LoadCurrencies(ctx context.Context) error
+
+	// Exchange rates
+	CreateExchangeRate(ctx context.Context, req *entities.CreateExchangeRateRequest, userID uuid.UUID) (*entities.ExchangeRate, error)
+	ListExchangeRates(ctx context.Context, base, quote string, page, limit int) ([]*entities.ExchangeRate, int, error)
+	GetExchangeRate(ctx context.Context, from, to string, at time.Time) (*entities.ExchangeRate, error)
+
+	// Conversion
+	Convert(ctx context.Context, amount entities.Money, to string, at time.Time) (*entities.ConvertedAmount, error)
+	ConvertToBase(ctx context.Context, amount entities.Money, at time.Time) (*entities.ConvertedAmount, error)
+	BaseCurrency() string
+}
+
+// DefaultBaseCurrency is the reporting currency when none is configured
+const DefaultBaseCurrency = "IDR"
+
+// currencyService implements CurrencyService
+type currencyService struct {
+	currencyRepo repositories.CurrencyRepository
+	auditService AuditService
+	baseCurrency string
+}
+
+// NewCurrencyService creates a new currency service
+func NewCurrencyService(currencyRepo repositories.CurrencyRepository, auditService AuditService, baseCurrency string) CurrencyService {
+	if baseCurrency == "" {
+		baseCurrency = DefaultBaseCurrency
+	}
+	return &currencyService{
+		currencyRepo: currencyRepo,
+		auditService: auditService,
+		baseCurrency: strings.ToUpper(baseCurrency),
+	}
+}
+
+// BaseCurrency returns the reporting currency
+func (s *currencyService) BaseCurrency() string {
+	return s.baseCurrency
+}
+
+// CreateCurrency adds a currency to the registry or updates an existing one
+func (s *currencyService) CreateCurrency(ctx context.Context, req *entities.CreateCurrencyRequest, userID uuid.UUID) (*entities.Currency, error) {
+	currency := &entities.Currency{
+		Code:       strings.ToUpper(req.Code),
+		Name:       req.Name,
+		Symbol:     req.Symbol,
+		MinorUnits: req.MinorUnits,
+		IsActive:   req.IsActive,
+	}
+
+	if err := s.currencyRepo.UpsertCurrency(ctx, currency); err != nil {
+		return nil, fmt.Errorf("failed to save currency: %w", err)
+	}
+
+	entities.RegisterCurrencyMinorUnits(currency.Code, currency.MinorUnits)
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "CREATE_CURRENCY",
+		EntityType: "currency",
+		EntityID:   uuid.Nil, // currencies are keyed by code
+		NewValues:  fmt.Sprintf(`{"code": "%s", "minor_units": %d, "is_active": %t}`, currency.Code, currency.MinorUnits, currency.IsActive),
+	})
+
+	return currency, nil
+}
+
+// ListCurrencies returns the registry currencies
+func (s *currencyService) ListCurrencies(ctx context.Context, activeOnly bool) ([]*entities.Currency, error) {
+	return s.currencyRepo.ListCurrencies(ctx, activeOnly)
+}
+
+// LoadCurrencies registers the minor-unit precision of every registry currency
+// so that Money values are scaled correctly. Called once at startup.
+func (s *currencyService) LoadCurrencies(ctx context.Context) error {
+	currencies, err := s.currencyRepo.ListCurrencies(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to load currencies: %w", err)
+	}
+
+	for _, currency := range currencies {
+		entities.RegisterCurrencyMinorUnits(currency.Code, currency.MinorUnits)
+	}
+
+	return nil
+}
+
+// CreateExchangeRate records a rate for a currency pair effective from the given time
+func (s *currencyService) CreateExchangeRate(ctx context.Context, req *entities.CreateExchangeRateRequest, userID uuid.UUID) (*entities.ExchangeRate, error) {
+	base := strings.ToUpper(req.BaseCurrency)
+	quote := strings.ToUpper(req.QuoteCurrency)
+	if base == quote {
+		return nil, fmt.Errorf("base and quote currency must differ")
+	}
+	if !req.Rate.IsPositive() {
+		return nil, fmt.Errorf("exchange rate must be positive")
+	}
+
+	for _, code := range []string{base, quote} {
+		currency, err := s.currencyRepo.GetCurrency(ctx, code)
+		if err != nil {
+			return nil, fmt.Errorf("unknown currency %s: %w", code, err)
+		}
+		if !currency.IsActive {
+			return nil, fmt.Errorf("currency %s is not active", code)
+		}
+	}
+
+	source := req.Source
+	if source == "" {
+		source = entities.ExchangeRateSourceManual
+	}
+	effectiveAt := time.Now()
+	if req.EffectiveAt != nil {
+		effectiveAt = *req.EffectiveAt
+	}
+
+	rate := &entities.ExchangeRate{
+		ID:            uuid.New(),
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          req.Rate,
+		Source:        source,
+		EffectiveAt:   effectiveAt,
+		CreatedBy:     userID,
+	}
+
+	if err := s.currencyRepo.CreateExchangeRate(ctx, rate); err != nil {
+		return nil, fmt.Errorf("failed to create exchange rate: %w", err)
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "CREATE_EXCHANGE_RATE",
+		EntityType: "exchange_rate",
+		EntityID:   rate.ID,
+		NewValues:  fmt.Sprintf(`{"pair": "%s/%s", "rate": "%s", "effective_at": "%s"}`, base, quote, rate.Rate, effectiveAt.Format(time.RFC3339)),
+	})
+
+	return rate, nil
+}
+
+// ListExchangeRates returns the rate history, optionally filtered by pair
+func (s *currencyService) ListExchangeRates(ctx context.Context, base, quote string, page, limit int) ([]*entities.ExchangeRate, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.currencyRepo.ListExchangeRates(ctx, base, quote, limit, (page-1)*limit)
+}
+
+// GetExchangeRate returns the rate from one currency to another effective at the
+// given time. A direct quote is preferred, then the inverse of the opposite quote,
+// then a cross rate through the base currency.
+func (s *currencyService) GetExchangeRate(ctx context.Context, from, to string, at time.Time) (*entities.ExchangeRate, error) {
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+	if from == to {
+		return &entities.ExchangeRate{BaseCurrency: from, QuoteCurrency: to, Rate: entities.MustParseRate("1"), EffectiveAt: at}, nil
+	}
+
+	rate, err := s.getPairRate(ctx, from, to, at)
+	if err != nil {
+		return nil, err
+	}
+	if rate != nil {
+		return rate, nil
+	}
+
+	if from != s.baseCurrency && to != s.baseCurrency {
+		fromBase, err := s.getPairRate(ctx, from, s.baseCurrency, at)
+		if err != nil {
+			return nil, err
+		}
+		baseTo, err := s.getPairRate(ctx, s.baseCurrency, to, at)
+		if err != nil {
+			return nil, err
+		}
+		if fromBase != nil && baseTo != nil {
+			// A cross rate is only as recent as the older of its two legs
+			effectiveAt := fromBase.EffectiveAt
+			if baseTo.EffectiveAt.Before(effectiveAt) {
+				effectiveAt = baseTo.EffectiveAt
+			}
+			return &entities.ExchangeRate{
+				BaseCurrency:  from,
+				QuoteCurrency: to,
+				Rate:          fromBase.Rate.Mul(baseTo.Rate),
+				Source:        fromBase.Source,
+				EffectiveAt:   effectiveAt,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no exchange rate from %s to %s effective at %s", from, to, at.Format(time.RFC3339))
+}
+
+// getPairRate returns the direct or inverted quote for a pair, or nil if neither exists
+func (s *currencyService) getPairRate(ctx context.Context, from, to string, at time.Time) (*entities.ExchangeRate, error) {
+	direct, err := s.currencyRepo.GetEffectiveRate(ctx, from, to, at)
+	if err != nil {
+		return nil, err
+	}
+	if direct != nil {
+		return direct, nil
+	}
+
+	inverse, err := s.currencyRepo.GetEffectiveRate(ctx, to, from, at)
+	if err != nil {
+		return nil, err
+	}
+	if inverse == nil {
+		return nil, nil
+	}
+
+	return &entities.ExchangeRate{
+		ID:            inverse.ID,
+		BaseCurrency:  from,
+		QuoteCurrency: to,
+		Rate:          inverse.Rate.Inv(),
+		Source:        inverse.Source,
+		EffectiveAt:   inverse.EffectiveAt,
+		CreatedBy:     inverse.CreatedBy,
+		CreatedAt:     inverse.CreatedAt,
+	}, nil
+}
+
+// Convert converts an amount into another currency at the rate effective at the given time
+func (s *currencyService) Convert(ctx context.Context, amount entities.Money, to string, at time.Time) (*entities.ConvertedAmount, error) {
+	if amount.Currency() == "" {
+		return nil, fmt.Errorf("amount has no currency")
+	}
+
+	rate, err := s.GetExchangeRate(ctx, amount.Currency(), to, at)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := &entities.ConvertedAmount{
+		Original:     amount,
+		Converted:    amount.Convert(rate.Rate.Rat(), to, entities.RoundHalfUp),
+		ExchangeRate: rate.Rate,
+		ConvertedAt:  at,
+	}
+	if rate.ID != uuid.Nil {
+		rateID := rate.ID
+		converted.RateID = &rateID
+	}
+	if !strings.EqualFold(amount.Currency(), to) {
+		effectiveAt := rate.EffectiveAt
+		converted.RateEffectiveAt = &effectiveAt
+	}
+
+	return converted, nil
+}
+
+// ConvertToBase converts an amount into the reporting currency
+func (s *currencyService) ConvertToBase(ctx context.Context, amount entities.Money, at time.Time) (*entities.ConvertedAmount, error) {
+	return s.Convert(ctx, amount, s.baseCurrency, at)
+}
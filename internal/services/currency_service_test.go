@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCurrencyService_GetExchangeRate_Direct(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockCurrencyRepository)
+	currencyService := NewCurrencyService(mockRepo, mockAuditService, "IDR")
+	ctx := context.Background()
+	at := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	rate := &entities.ExchangeRate{ID: uuid.New(), BaseCurrency: "MYR", QuoteCurrency: "IDR", Rate: entities.MustParseRate("3350"), EffectiveAt: at.AddDate(0, 0, -1)}
+	mockRepo.On("GetEffectiveRate", ctx, "MYR", "IDR", at).Return(rate, nil)
+
+	result, err := currencyService.GetExchangeRate(ctx, "myr", "IDR", at)
+
+	assert.NoError(t, err)
+	assert.Equal(t, rate, result)
+}
+
+func TestCurrencyService_GetExchangeRate_Inverse(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockCurrencyRepository)
+	currencyService := NewCurrencyService(mockRepo, mockAuditService, "IDR")
+	ctx := context.Background()
+	at := time.Now()
+
+	mockRepo.On("GetEffectiveRate", ctx, "IDR", "USD", at).Return(nil, nil)
+	mockRepo.On("GetEffectiveRate", ctx, "USD", "IDR", at).
+		Return(&entities.ExchangeRate{ID: uuid.New(), BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: entities.MustParseRate("16000"), EffectiveAt: at}, nil)
+
+	result, err := currencyService.GetExchangeRate(ctx, "IDR", "USD", at)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "IDR", result.BaseCurrency)
+	assert.Equal(t, "USD", result.QuoteCurrency)
+	assert.True(t, entities.MustParseRate("0.0000625").Equal(result.Rate))
+}
+
+func TestCurrencyService_GetExchangeRate_CrossViaBase(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockCurrencyRepository)
+	currencyService := NewCurrencyService(mockRepo, mockAuditService, "IDR")
+	ctx := context.Background()
+	at := time.Now()
+	older := at.AddDate(0, 0, -2)
+
+	mockRepo.On("GetEffectiveRate", ctx, "USD", "MYR", at).Return(nil, nil)
+	mockRepo.On("GetEffectiveRate", ctx, "MYR", "USD", at).Return(nil, nil)
+	mockRepo.On("GetEffectiveRate", ctx, "USD", "IDR", at).
+		Return(&entities.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: entities.MustParseRate("16000"), EffectiveAt: at}, nil)
+	mockRepo.On("GetEffectiveRate", ctx, "IDR", "MYR", at).
+		Return(&entities.ExchangeRate{BaseCurrency: "IDR", QuoteCurrency: "MYR", Rate: entities.MustParseRate("0.0003"), EffectiveAt: older}, nil)
+
+	result, err := currencyService.GetExchangeRate(ctx, "USD", "MYR", at)
+
+	assert.NoError(t, err)
+	assert.True(t, entities.MustParseRate("4.8").Equal(result.Rate)) // exact, not 4.800000000000001
+	assert.Equal(t, older, result.EffectiveAt)                       // the older leg
+}
+
+func TestCurrencyService_GetExchangeRate_Missing(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockCurrencyRepository)
+	currencyService := NewCurrencyService(mockRepo, mockAuditService, "IDR")
+	ctx := context.Background()
+	at := time.Now()
+
+	mockRepo.On("GetEffectiveRate", ctx, mock.Anything, mock.Anything, at).Return(nil, nil)
+
+	result, err := currencyService.GetExchangeRate(ctx, "MYR", "IDR", at)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "no exchange rate from MYR to IDR")
+}
+
+func TestCurrencyService_Convert(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockCurrencyRepository)
+	currencyService := NewCurrencyService(mockRepo, mockAuditService, "IDR")
+	ctx := context.Background()
+	at := time.Now()
+
+	rateID := uuid.New()
+	mockRepo.On("GetEffectiveRate", ctx, "MYR", "IDR", at).
+		Return(&entities.ExchangeRate{ID: rateID, BaseCurrency: "MYR", QuoteCurrency: "IDR", Rate: entities.MustParseRate("3350.125"), EffectiveAt: at}, nil)
+
+	converted, err := currencyService.ConvertToBase(ctx, entities.MustParseMoney("10.01", "MYR"), at)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entities.MustParseMoney("10.01", "MYR"), converted.Original)
+	assert.Equal(t, entities.MustParseMoney("33534.75", "IDR"), converted.Converted) // 33534.75125 rounded
+	assert.Equal(t, rateID, *converted.RateID)
+}
+
+func TestCurrencyService_Convert_SameCurrency(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockCurrencyRepository)
+	currencyService := NewCurrencyService(mockRepo, mockAuditService, "IDR")
+
+	converted, err := currencyService.Convert(context.Background(), entities.MustParseMoney("1500", "IDR"), "IDR", time.Now())
+
+	assert.NoError(t, err)
+	assert.Equal(t, converted.Original, converted.Converted)
+	assert.Equal(t, "1", converted.ExchangeRate.String())
+	assert.Nil(t, converted.RateEffectiveAt)
+	mockRepo.AssertNotCalled(t, "GetEffectiveRate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCurrencyService_CreateExchangeRate_InactiveCurrency(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockCurrencyRepository)
+	currencyService := NewCurrencyService(mockRepo, mockAuditService, "IDR")
+	ctx := context.Background()
+
+	mockRepo.On("GetCurrency", ctx, "USD").Return(&entities.Currency{Code: "USD", IsActive: true}, nil)
+	mockRepo.On("GetCurrency", ctx, "SGD").Return(&entities.Currency{Code: "SGD", IsActive: false}, nil)
+
+	req := &entities.CreateExchangeRateRequest{BaseCurrency: "USD", QuoteCurrency: "SGD", Rate: entities.MustParseRate("1.35")}
+	rate, err := currencyService.CreateExchangeRate(ctx, req, uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, rate)
+	assert.Contains(t, err.Error(), "not active")
+	mockRepo.AssertNotCalled(t, "CreateExchangeRate", mock.Anything, mock.Anything)
+}
+
+func TestCurrencyService_LoadCurrencies(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockCurrencyRepository)
+	currencyService := NewCurrencyService(mockRepo, mockAuditService, "IDR")
+	ctx := context.Background()
+
+	mockRepo.On("ListCurrencies", ctx, false).Return([]*entities.Currency{{Code: "BHD", MinorUnits: 3}}, nil)
+
+	assert.NoError(t, currencyService.LoadCurrencies(ctx))
+	assert.Equal(t, 3, entities.CurrencyMinorUnits("BHD"))
+}
+
+func TestCurrencyService_LoadCurrencies_Error(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockCurrencyRepository)
+	currencyService := NewCurrencyService(mockRepo, mockAuditService, "IDR")
+	ctx := context.Background()
+
+	mockRepo.On("ListCurrencies", ctx, false).Return([]*entities.Currency(nil), errors.New("connection refused"))
+
+	err := currencyService.LoadCurrencies(ctx)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load currencies")
+}
@@ -0,0 +1,684 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// FeeService is the ComFunds fee engine. It keeps the fee schedules, which may
+// be flat, banded or tiered by funding amount and scoped to a cooperative or a
+// project, resolves which schedule charges a project, applies promotional
+// waivers, and issues the invoices and credit notes of each cooperative.
+type FeeService interface {
+	// Fee schedules
+	CreateFee(ctx context.Context, req *entities.CreateComFundsFeeRequest, creatorID uuid.UUID) (*entities.ComFundsFee, error)
+	UpdateFee(ctx context.Context, feeID uuid.UUID, req *entities.CreateComFundsFeeRequest, updaterID uuid.UUID) (*entities.ComFundsFee, error)
+	SetFeeEnabled(ctx context.Context, feeID uuid.UUID, enabled bool, userID uuid.UUID) error
+	GetFee(ctx context.Context, feeID uuid.UUID) (*entities.ComFundsFee, error)
+	GetActiveFees(ctx context.Context, feeType string) ([]*entities.ComFundsFee, error)
+	SearchFees(ctx context.Context, filter *entities.ComFundsFeeFilter) ([]*entities.ComFundsFee, int, error)
+
+	// Promotional waivers
+	CreateWaiver(ctx context.Context, req *entities.CreateFeeWaiverRequest, creatorID uuid.UUID) (*entities.FeeWaiver, error)
+	RevokeWaiver(ctx context.Context, waiverID, revokerID uuid.UUID) error
+	ListWaivers(ctx context.Context, feeType string, activeOnly bool) ([]*entities.FeeWaiver, error)
+
+	// CalculateProjectFee charges a project's success fee under the schedule that
+	// takes precedence for it, less the most generous waiver in force
+	CalculateProjectFee(ctx context.Context, req *entities.CalculateProjectFeeRequest, calculatorID uuid.UUID) (*entities.ProjectFeeCalculation, error)
+	// CollectProjectFee collects a calculated fee, invoices the business and
+	// posts the collection to the ledger
+	CollectProjectFee(ctx context.Context, req *entities.CollectProjectFeeRequest, collectorID uuid.UUID) (*entities.FeeInvoice, error)
+	// WaiveProjectFee waives a calculated fee and issues a credit note for it
+	WaiveProjectFee(ctx context.Context, calculationID, waiverID uuid.UUID, reason string) (*entities.FeeCreditNote, error)
+	GetProjectFeeCalculation(ctx context.Context, calculationID uuid.UUID) (*entities.ProjectFeeCalculation, error)
+	GetProjectFeeCalculations(ctx context.Context, projectID uuid.UUID, page, limit int) ([]*entities.ProjectFeeCalculation, int, error)
+	SearchProjectFeeCalculations(ctx context.Context, cooperativeID uuid.UUID, filter *entities.ProjectFeeCalculationFilter) ([]*entities.ProjectFeeCalculation, int, error)
+
+	// Fee documents
+	ListInvoices(ctx context.Context, cooperativeID uuid.UUID, page, limit int) ([]*entities.FeeInvoice, int, error)
+	ListCreditNotes(ctx context.Context, cooperativeID uuid.UUID, page, limit int) ([]*entities.FeeCreditNote, int, error)
+}
+
+type feeService struct {
+	feeRepo         repositories.FeeRepository
+	currencyService CurrencyService
+	auditService    AuditService
+	ledgerService   LedgerService
+}
+
+// NewFeeService creates a new fee service
+func NewFeeService(feeRepo repositories.FeeRepository, currencyService CurrencyService, auditService AuditService, ledgerService LedgerService) FeeService {
+	return &feeService{
+		feeRepo:         feeRepo,
+		currencyService: currencyService,
+		auditService:    auditService,
+		ledgerService:   ledgerService,
+	}
+}
+
+// validateFeeSchedule checks a schedule's percentages, tiers and amount limits
+func validateFeeSchedule(req *entities.CreateComFundsFeeRequest) error {
+	if req.FeePercentage < 0 || req.FeePercentage > 100 {
+		return errors.New("fee percentage must be between 0 and 100")
+	}
+	amounts := []entities.Money{entities.ZeroMoney(req.Currency), req.MinimumAmount, req.MaximumAmount}
+	for _, tier := range req.Tiers {
+		if tier.UpTo != nil {
+			amounts = append(amounts, *tier.UpTo)
+		}
+	}
+	if err := entities.CheckSameCurrency(amounts...); err != nil {
+		return fmt.Errorf("fee schedule amounts must be in the schedule's currency: %w", err)
+	}
+	if req.MinimumAmount.IsPositive() && req.MaximumAmount.IsPositive() && req.MinimumAmount.GreaterThan(req.MaximumAmount) {
+		return errors.New("minimum amount cannot be greater than maximum amount")
+	}
+	if req.ApplicableTo == entities.ComFundsFeeApplicableToSpecificProjects && req.ProjectID == nil {
+		return errors.New("project is required for a project specific fee")
+	}
+	if req.EffectiveTo != nil && !req.EffectiveTo.After(req.EffectiveFrom) {
+		return errors.New("effective to date must be after effective from date")
+	}
+
+	if req.TierMode == "" || req.TierMode == entities.FeeTierModeFlat {
+		if len(req.Tiers) > 0 {
+			return errors.New("a flat fee cannot have tiers")
+		}
+		return nil
+	}
+
+	if len(req.Tiers) == 0 {
+		return fmt.Errorf("a %s fee must have at least one tier", req.TierMode)
+	}
+	var previous *entities.Money
+	for i, tier := range req.Tiers {
+		if tier.FeePercentage < 0 || tier.FeePercentage > 100 {
+			return errors.New("tier fee percentage must be between 0 and 100")
+		}
+		last := i == len(req.Tiers)-1
+		if tier.UpTo == nil {
+			if !last {
+				return errors.New("only the last tier can be open ended")
+			}
+			continue
+		}
+		if last {
+			return errors.New("the last tier must be open ended")
+		}
+		if !tier.UpTo.IsPositive() || (previous != nil && !tier.UpTo.GreaterThan(*previous)) {
+			return errors.New("tier limits must be positive and increasing")
+		}
+		previous = tier.UpTo
+	}
+
+	return nil
+}
+
+// newFeeSchedule builds the schedule described by a request, with its amounts in the schedule's currency
+func newFeeSchedule(req *entities.CreateComFundsFeeRequest) *entities.ComFundsFee {
+	currency := strings.ToUpper(req.Currency)
+	tierMode := req.TierMode
+	if tierMode == "" {
+		tierMode = entities.FeeTierModeFlat
+	}
+
+	var tiers []entities.ComFundsFeeTier
+	for _, tier := range req.Tiers {
+		if tier.UpTo != nil && currency != "" {
+			upTo := tier.UpTo.WithCurrency(currency)
+			tier.UpTo = &upTo
+		}
+		tiers = append(tiers, tier)
+	}
+
+	return &entities.ComFundsFee{
+		FeeType:       req.FeeType,
+		FeePercentage: req.FeePercentage,
+		TierMode:      tierMode,
+		Tiers:         tiers,
+		Priority:      req.Priority,
+		Currency:      currency,
+		FeeAmount:     entities.ZeroMoney(currency), // Will be calculated when applied
+		IsEnabled:     req.IsEnabled,
+		MinimumAmount: req.MinimumAmount.WithCurrency(currency),
+		MaximumAmount: req.MaximumAmount.WithCurrency(currency),
+		ApplicableTo:  req.ApplicableTo,
+		ProjectID:     req.ProjectID,
+		CooperativeID: req.CooperativeID,
+		EffectiveFrom: req.EffectiveFrom,
+		EffectiveTo:   req.EffectiveTo,
+		Description:   req.Description,
+		IsActive:      true,
+	}
+}
+
+func (s *feeService) CreateFee(ctx context.Context, req *entities.CreateComFundsFeeRequest, creatorID uuid.UUID) (*entities.ComFundsFee, error) {
+	if err := validateFeeSchedule(req); err != nil {
+		return nil, err
+	}
+	if req.EffectiveFrom.Before(time.Now()) {
+		return nil, errors.New("effective from date cannot be in the past")
+	}
+
+	now := time.Now()
+	fee := newFeeSchedule(req)
+	fee.ID = uuid.New()
+	fee.CreatedBy = creatorID
+	fee.CreatedAt = now
+	fee.UpdatedAt = now
+
+	if err := s.feeRepo.SaveFee(ctx, fee); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     creatorID,
+		Operation:  "create_comfunds_fee",
+		EntityType: "comfunds_fee",
+		EntityID:   fee.ID,
+		NewValues:  fmt.Sprintf("Created fee structure: type=%s, mode=%s, percentage=%f%%, tiers=%d, enabled=%t", fee.FeeType, fee.TierMode, fee.FeePercentage, len(fee.Tiers), fee.IsEnabled),
+	})
+
+	return fee, nil
+}
+
+func (s *feeService) UpdateFee(ctx context.Context, feeID uuid.UUID, req *entities.CreateComFundsFeeRequest, updaterID uuid.UUID) (*entities.ComFundsFee, error) {
+	existing, err := s.feeRepo.GetFee(ctx, feeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateFeeSchedule(req); err != nil {
+		return nil, err
+	}
+
+	fee := newFeeSchedule(req)
+	fee.ID = existing.ID
+	fee.IsActive = existing.IsActive
+	fee.CreatedBy = existing.CreatedBy
+	fee.CreatedAt = existing.CreatedAt
+	fee.UpdatedAt = time.Now()
+
+	if err := s.feeRepo.SaveFee(ctx, fee); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     updaterID,
+		Operation:  "update_comfunds_fee",
+		EntityType: "comfunds_fee",
+		EntityID:   fee.ID,
+		NewValues:  fmt.Sprintf("Updated fee structure: type=%s, mode=%s, percentage=%f%%, tiers=%d, enabled=%t", fee.FeeType, fee.TierMode, fee.FeePercentage, len(fee.Tiers), fee.IsEnabled),
+	})
+
+	return fee, nil
+}
+
+func (s *feeService) SetFeeEnabled(ctx context.Context, feeID uuid.UUID, enabled bool, userID uuid.UUID) error {
+	fee, err := s.feeRepo.GetFee(ctx, feeID)
+	if err != nil {
+		return err
+	}
+
+	fee.IsEnabled = enabled
+	fee.UpdatedAt = time.Now()
+	if err := s.feeRepo.SaveFee(ctx, fee); err != nil {
+		return err
+	}
+
+	operation, description := "enable_comfunds_fee", "Enabled ComFunds fee structure"
+	if !enabled {
+		operation, description = "disable_comfunds_fee", "Disabled ComFunds fee structure"
+	}
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  operation,
+		EntityType: "comfunds_fee",
+		EntityID:   feeID,
+		NewValues:  description,
+	})
+
+	return nil
+}
+
+func (s *feeService) GetFee(ctx context.Context, feeID uuid.UUID) (*entities.ComFundsFee, error) {
+	return s.feeRepo.GetFee(ctx, feeID)
+}
+
+func (s *feeService) GetActiveFees(ctx context.Context, feeType string) ([]*entities.ComFundsFee, error) {
+	return s.feeRepo.ListActiveFees(ctx, feeType)
+}
+
+func (s *feeService) SearchFees(ctx context.Context, filter *entities.ComFundsFeeFilter) ([]*entities.ComFundsFee, int, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 10
+	}
+	return s.feeRepo.ListFees(ctx, filter)
+}
+
+func (s *feeService) CreateWaiver(ctx context.Context, req *entities.CreateFeeWaiverRequest, creatorID uuid.UUID) (*entities.FeeWaiver, error) {
+	if req.WaiverPercentage <= 0 || req.WaiverPercentage > 100 {
+		return nil, errors.New("waiver percentage must be greater than 0 and at most 100")
+	}
+
+	now := time.Now()
+	validFrom := now
+	if req.ValidFrom != nil {
+		validFrom = *req.ValidFrom
+	}
+	if !req.ExpiresAt.After(validFrom) || !req.ExpiresAt.After(now) {
+		return nil, errors.New("waiver must expire in the future and after it becomes valid")
+	}
+
+	waiver := &entities.FeeWaiver{
+		ID:               uuid.New(),
+		FeeType:          req.FeeType,
+		CooperativeID:    req.CooperativeID,
+		ProjectID:        req.ProjectID,
+		WaiverPercentage: req.WaiverPercentage,
+		ValidFrom:        validFrom,
+		ExpiresAt:        req.ExpiresAt,
+		Reason:           req.Reason,
+		IsActive:         true,
+		CreatedBy:        creatorID,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.feeRepo.CreateWaiver(ctx, waiver); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     creatorID,
+		Operation:  "create_fee_waiver",
+		EntityType: "fee_waiver",
+		EntityID:   waiver.ID,
+		NewValues:  fmt.Sprintf("Created fee waiver: type=%s, percentage=%f%%, expires=%s", waiver.FeeType, waiver.WaiverPercentage, waiver.ExpiresAt.Format(time.RFC3339)),
+	})
+
+	return waiver, nil
+}
+
+func (s *feeService) RevokeWaiver(ctx context.Context, waiverID, revokerID uuid.UUID) error {
+	if err := s.feeRepo.RevokeWaiver(ctx, waiverID, revokerID, time.Now()); err != nil {
+		return err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     revokerID,
+		Operation:  "revoke_fee_waiver",
+		EntityType: "fee_waiver",
+		EntityID:   waiverID,
+		NewValues:  "Revoked fee waiver",
+	})
+
+	return nil
+}
+
+func (s *feeService) ListWaivers(ctx context.Context, feeType string, activeOnly bool) ([]*entities.FeeWaiver, error) {
+	return s.feeRepo.ListWaivers(ctx, feeType, activeOnly)
+}
+
+// resolveFeeSchedule picks the schedule that charges a project's fee: a project
+// schedule overrides a cooperative one, which overrides a global one; within a
+// scope the highest priority wins, then the schedule that took effect last
+func resolveFeeSchedule(fees []*entities.ComFundsFee, project *entities.FeeProject, at time.Time) *entities.ComFundsFee {
+	var candidates []*entities.ComFundsFee
+	for _, fee := range fees {
+		if fee.AppliesTo(project, at) {
+			candidates = append(candidates, fee)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.FeeScopeRank() != b.FeeScopeRank() {
+			return a.FeeScopeRank() > b.FeeScopeRank()
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return a.EffectiveFrom.After(b.EffectiveFrom)
+	})
+	return candidates[0]
+}
+
+// bestFeeWaiver returns the most generous waiver in force for the project's fee, or nil
+func bestFeeWaiver(waivers []*entities.FeeWaiver, project *entities.FeeProject, feeType string, at time.Time) *entities.FeeWaiver {
+	var best *entities.FeeWaiver
+	for _, waiver := range waivers {
+		if waiver.AppliesTo(project, feeType, at) && (best == nil || waiver.WaiverPercentage > best.WaiverPercentage) {
+			best = waiver
+		}
+	}
+	return best
+}
+
+// scheduleFee is the fee a schedule charges on an amount, within its minimum
+// and maximum. Banded schedules charge the whole amount at the rate of its band;
+// tiered schedules charge each slice of the amount at that tier's rate.
+func scheduleFee(fee *entities.ComFundsFee, amount entities.Money) entities.Money {
+	currency := amount.Currency()
+	var charged entities.Money
+
+	switch fee.TierMode {
+	case entities.FeeTierModeBanded:
+		charged = entities.ZeroMoney(currency)
+		for _, tier := range fee.Tiers {
+			if tier.UpTo == nil || amount.LessThanOrEqual(tier.UpTo.WithCurrency(currency)) {
+				charged = amount.Percent(tier.FeePercentage, entities.RoundHalfUp)
+				break
+			}
+		}
+	case entities.FeeTierModeTiered:
+		charged = entities.ZeroMoney(currency)
+		lower := entities.ZeroMoney(currency)
+		for _, tier := range fee.Tiers {
+			if !lower.LessThan(amount) {
+				break
+			}
+			upper := amount
+			if tier.UpTo != nil {
+				upper = amount.Min(tier.UpTo.WithCurrency(currency))
+			}
+			charged = charged.Add(upper.Sub(lower).Percent(tier.FeePercentage, entities.RoundHalfUp))
+			lower = upper
+		}
+	default:
+		charged = amount.Percent(fee.FeePercentage, entities.RoundHalfUp)
+	}
+
+	minimum := fee.MinimumAmount.WithCurrency(currency)
+	maximum := fee.MaximumAmount.WithCurrency(currency)
+	if minimum.IsPositive() && charged.LessThan(minimum) {
+		charged = minimum
+	}
+	if maximum.IsPositive() && charged.GreaterThan(maximum) {
+		charged = maximum
+	}
+
+	return charged
+}
+
+// effectiveFeePercentage is the fee as a percentage of the amount it was charged on
+func effectiveFeePercentage(fee, amount entities.Money) float64 {
+	pct, _ := new(big.Rat).Mul(fee.Ratio(amount), big.NewRat(100, 1)).Float64()
+	return pct
+}
+
+func (s *feeService) CalculateProjectFee(ctx context.Context, req *entities.CalculateProjectFeeRequest, calculatorID uuid.UUID) (*entities.ProjectFeeCalculation, error) {
+	if !req.TotalFundingAmount.IsPositive() {
+		return nil, errors.New("total funding amount must be greater than zero")
+	}
+
+	project, err := s.feeRepo.GetFeeProject(ctx, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A funding closure is charged once, however often its fee is requested
+	if req.FundingClosureID != nil {
+		existing, err := s.feeRepo.GetClosureCalculation(ctx, project.CooperativeID, *req.FundingClosureID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	}
+
+	if project.Currency == "" {
+		project.Currency = s.currencyService.BaseCurrency()
+	}
+	if currency := req.TotalFundingAmount.Currency(); currency != "" && currency != project.Currency {
+		return nil, fmt.Errorf("funding amount is in %s but the project is funded in %s", currency, project.Currency)
+	}
+	amount := req.TotalFundingAmount.WithCurrency(project.Currency)
+
+	at := req.CalculateDate
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	fees, err := s.feeRepo.ListActiveFees(ctx, entities.ComFundsFeeTypeSuccessFee)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active fees: %w", err)
+	}
+	schedule := resolveFeeSchedule(fees, project, at)
+	if schedule == nil {
+		return nil, errors.New("no active fee structure found")
+	}
+
+	waivers, err := s.feeRepo.ListWaivers(ctx, entities.ComFundsFeeTypeSuccessFee, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee waivers: %w", err)
+	}
+
+	grossFee := scheduleFee(schedule, amount)
+	waived := entities.ZeroMoney(project.Currency)
+	var waiverID *uuid.UUID
+	waiver := bestFeeWaiver(waivers, project, entities.ComFundsFeeTypeSuccessFee, at)
+	if waiver != nil {
+		waived = grossFee.Percent(waiver.WaiverPercentage, entities.RoundHalfUp)
+		waiverID = &waiver.ID
+	}
+	feeAmount := grossFee.Sub(waived)
+
+	status := entities.ProjectFeeStatusCalculated
+	if !feeAmount.IsPositive() {
+		status = entities.ProjectFeeStatusWaived
+	}
+
+	now := time.Now()
+	calculation := &entities.ProjectFeeCalculation{
+		ID:                 uuid.New(),
+		ProjectID:          project.ProjectID,
+		CooperativeID:      project.CooperativeID,
+		BusinessID:         project.BusinessID,
+		TotalFundingAmount: amount,
+		Currency:           project.Currency,
+		FeeScheduleID:      &schedule.ID,
+		FeePercentage:      effectiveFeePercentage(feeAmount, amount),
+		GrossFeeAmount:     grossFee,
+		WaiverID:           waiverID,
+		WaivedAmount:       waived,
+		FeeAmount:          feeAmount,
+		NetAmountAfterFee:  amount.Sub(feeAmount),
+		FeeStatus:          status,
+		FundingClosureID:   req.FundingClosureID,
+		CalculatedAt:       at,
+		IsActive:           true,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	// The promotional discount is credited to the business like a manual waiver
+	var creditNote *entities.FeeCreditNote
+	if waived.IsPositive() {
+		creditNote = &entities.FeeCreditNote{
+			ID:               uuid.New(),
+			CooperativeID:    calculation.CooperativeID,
+			ProjectID:        calculation.ProjectID,
+			BusinessID:       calculation.BusinessID,
+			FeeCalculationID: calculation.ID,
+			Currency:         calculation.Currency,
+			Amount:           waived,
+			Reason:           fmt.Sprintf("Promotional waiver %s: %s", waiver.ID, waiver.Reason),
+			IssuedBy:         calculatorID,
+			IssuedAt:         now,
+			CreatedAt:        now,
+		}
+		calculation.CreditNoteID = &creditNote.ID
+	}
+	if err := s.feeRepo.CreateCalculation(ctx, calculation, creditNote); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     calculatorID,
+		Operation:  "calculate_project_fee",
+		EntityType: "project_fee_calculation",
+		EntityID:   calculation.ID,
+		NewValues:  fmt.Sprintf("Calculated project fee: amount=%s, schedule=%s, gross=%s, waived=%s, fee=%s", amount.Decimal(), schedule.ID, grossFee.Decimal(), waived.Decimal(), feeAmount.Decimal()),
+	})
+
+	return calculation, nil
+}
+
+func (s *feeService) CollectProjectFee(ctx context.Context, req *entities.CollectProjectFeeRequest, collectorID uuid.UUID) (*entities.FeeInvoice, error) {
+	calculation, err := s.feeRepo.GetCalculation(ctx, req.ProjectFeeCalculationID)
+	if err != nil {
+		return nil, err
+	}
+	if calculation.FeeStatus != entities.ProjectFeeStatusCalculated {
+		return nil, fmt.Errorf("project fee cannot be collected in status %s", calculation.FeeStatus)
+	}
+
+	now := time.Now()
+	invoice := &entities.FeeInvoice{
+		ID:                   uuid.New(),
+		CooperativeID:        calculation.CooperativeID,
+		ProjectID:            calculation.ProjectID,
+		BusinessID:           calculation.BusinessID,
+		FeeCalculationID:     calculation.ID,
+		Currency:             calculation.Currency,
+		GrossAmount:          calculation.GrossFeeAmount,
+		DiscountAmount:       calculation.WaivedAmount,
+		NetAmount:            calculation.FeeAmount,
+		CollectionMethod:     req.CollectionMethod,
+		TransactionReference: req.TransactionReference,
+		IssuedBy:             collectorID,
+		IssuedAt:             now,
+		CreatedAt:            now,
+	}
+
+	// Charge the business and recognise the platform fee in the ledger in the
+	// same transaction that marks the fee collected and issues the invoice
+	entry, err := s.ledgerService.PrepareFeeInvoiceEntry(ctx, invoice, collectorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare fee collection ledger entry: %w", err)
+	}
+	if err := s.feeRepo.CollectFee(ctx, calculation, invoice, entry); err != nil {
+		return nil, err
+	}
+
+	calculation.FeeStatus = entities.ProjectFeeStatusCollected
+	calculation.CollectedAt = &now
+	calculation.CollectedBy = &collectorID
+	calculation.InvoiceID = &invoice.ID
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     collectorID,
+		Operation:  "collect_project_fee",
+		EntityType: "project_fee_calculation",
+		EntityID:   calculation.ID,
+		NewValues:  fmt.Sprintf("Collected project fee: invoice=%s, amount=%s, method=%s, reference=%s, ledger entry=%s", invoice.InvoiceNumber, invoice.NetAmount.Decimal(), req.CollectionMethod, req.TransactionReference, entry.EntryNumber),
+	})
+
+	return invoice, nil
+}
+
+func (s *feeService) WaiveProjectFee(ctx context.Context, calculationID, waiverID uuid.UUID, reason string) (*entities.FeeCreditNote, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, errors.New("waiver reason is required")
+	}
+
+	calculation, err := s.feeRepo.GetCalculation(ctx, calculationID)
+	if err != nil {
+		return nil, err
+	}
+	if calculation.FeeStatus != entities.ProjectFeeStatusCalculated {
+		return nil, fmt.Errorf("project fee cannot be waived in status %s", calculation.FeeStatus)
+	}
+
+	now := time.Now()
+	creditNote := &entities.FeeCreditNote{
+		ID:               uuid.New(),
+		CooperativeID:    calculation.CooperativeID,
+		ProjectID:        calculation.ProjectID,
+		BusinessID:       calculation.BusinessID,
+		FeeCalculationID: calculation.ID,
+		Currency:         calculation.Currency,
+		Amount:           calculation.FeeAmount,
+		Reason:           reason,
+		IssuedBy:         waiverID,
+		IssuedAt:         now,
+		CreatedAt:        now,
+	}
+	if err := s.feeRepo.WaiveFee(ctx, calculation, creditNote); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     waiverID,
+		Operation:  "waive_project_fee",
+		EntityType: "project_fee_calculation",
+		EntityID:   calculation.ID,
+		NewValues:  fmt.Sprintf("Waived project fee: credit_note=%s, amount=%s, reason=%s", creditNote.CreditNoteNumber, creditNote.Amount.Decimal(), reason),
+	})
+
+	return creditNote, nil
+}
+
+func (s *feeService) GetProjectFeeCalculation(ctx context.Context, calculationID uuid.UUID) (*entities.ProjectFeeCalculation, error) {
+	return s.feeRepo.GetCalculation(ctx, calculationID)
+}
+
+func (s *feeService) GetProjectFeeCalculations(ctx context.Context, projectID uuid.UUID, page, limit int) ([]*entities.ProjectFeeCalculation, int, error) {
+	project, err := s.feeRepo.GetFeeProject(ctx, projectID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.SearchProjectFeeCalculations(ctx, project.CooperativeID, &entities.ProjectFeeCalculationFilter{
+		ProjectID: &projectID,
+		Page:      page,
+		Limit:     limit,
+	})
+}
+
+func (s *feeService) SearchProjectFeeCalculations(ctx context.Context, cooperativeID uuid.UUID, filter *entities.ProjectFeeCalculationFilter) ([]*entities.ProjectFeeCalculation, int, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 10
+	}
+	return s.feeRepo.ListCalculations(ctx, cooperativeID, filter)
+}
+
+func (s *feeService) ListInvoices(ctx context.Context, cooperativeID uuid.UUID, page, limit int) ([]*entities.FeeInvoice, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	return s.feeRepo.ListInvoices(ctx, cooperativeID, limit, (page-1)*limit)
+}
+
+func (s *feeService) ListCreditNotes(ctx context.Context, cooperativeID uuid.UUID, page, limit int) ([]*entities.FeeCreditNote, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	return s.feeRepo.ListCreditNotes(ctx, cooperativeID, limit, (page-1)*limit)
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleFee_TieredVersusBanded(t *testing.T) {
+	upTo := idr("100000000")
+	tiers := []entities.ComFundsFeeTier{
+		{UpTo: &upTo, FeePercentage: 2},
+		{FeePercentage: 1},
+	}
+
+	testCases := []struct {
+		name     string
+		fee      *entities.ComFundsFee
+		amount   string
+		expected string
+	}{
+		// The whole amount falls in the 1% band
+		{"Banded", &entities.ComFundsFee{TierMode: entities.FeeTierModeBanded, Tiers: tiers}, "150000000", "1500000"},
+		// The first 100M at 2%, the remaining 50M at 1%
+		{"Tiered", &entities.ComFundsFee{TierMode: entities.FeeTierModeTiered, Tiers: tiers}, "150000000", "2500000"},
+		{"Banded below the first limit", &entities.ComFundsFee{TierMode: entities.FeeTierModeBanded, Tiers: tiers}, "50000000", "1000000"},
+		{"Tiered below the first limit", &entities.ComFundsFee{TierMode: entities.FeeTierModeTiered, Tiers: tiers}, "50000000", "1000000"},
+		{"Capped", &entities.ComFundsFee{TierMode: entities.FeeTierModeTiered, Tiers: tiers, MaximumAmount: idr("2000000")}, "150000000", "2000000"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, idr(tc.expected), scheduleFee(tc.fee, idr(tc.amount)))
+		})
+	}
+}
+
+func TestFeeService_CalculateProjectFee_CooperativeOverrideTakesPrecedence(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	feeRepo := new(MockFeeRepository)
+	feeService := NewFeeService(feeRepo, currencyService, mockAuditService, ledgerService)
+	ctx := context.Background()
+	now := time.Now()
+
+	project := &entities.FeeProject{ProjectID: uuid.New(), CooperativeID: uuid.New(), BusinessID: uuid.New(), Currency: "IDR"}
+	expiredAt := now.AddDate(0, 0, -1)
+	otherProjectID := uuid.New()
+	successFee, successfulFunding := entities.ComFundsFeeTypeSuccessFee, entities.ComFundsFeeApplicableToSuccessfulFunding
+	global := &entities.ComFundsFee{ID: uuid.New(), FeeType: successFee, FeePercentage: 2, TierMode: entities.FeeTierModeFlat, IsEnabled: true,
+		ApplicableTo: successfulFunding, Priority: 10, EffectiveFrom: now.AddDate(0, -6, 0), IsActive: true}
+	cooperative := &entities.ComFundsFee{ID: uuid.New(), FeeType: successFee, FeePercentage: 1.5, TierMode: entities.FeeTierModeFlat, IsEnabled: true,
+		ApplicableTo: successfulFunding, CooperativeID: &project.CooperativeID, EffectiveFrom: now.AddDate(0, -1, 0), IsActive: true}
+	expired := &entities.ComFundsFee{ID: uuid.New(), FeeType: successFee, FeePercentage: 1, TierMode: entities.FeeTierModeFlat, IsEnabled: true,
+		ApplicableTo: successfulFunding, CooperativeID: &project.CooperativeID, EffectiveFrom: now.AddDate(0, -3, 0), EffectiveTo: &expiredAt, IsActive: true}
+	otherProject := &entities.ComFundsFee{ID: uuid.New(), FeeType: successFee, FeePercentage: 0.5, TierMode: entities.FeeTierModeFlat, IsEnabled: true,
+		ApplicableTo: entities.ComFundsFeeApplicableToSpecificProjects, ProjectID: &otherProjectID, EffectiveFrom: now.AddDate(0, -1, 0), IsActive: true}
+
+	feeRepo.On("GetFeeProject", ctx, project.ProjectID).Return(project, nil)
+	feeRepo.On("ListActiveFees", ctx, entities.ComFundsFeeTypeSuccessFee).Return([]*entities.ComFundsFee{global, cooperative, expired, otherProject}, nil)
+	feeRepo.On("ListWaivers", ctx, entities.ComFundsFeeTypeSuccessFee, true).Return([]*entities.FeeWaiver{}, nil)
+	feeRepo.On("CreateCalculation", ctx, mock.AnythingOfType("*entities.ProjectFeeCalculation"), (*entities.FeeCreditNote)(nil)).Return(nil)
+
+	calculation, err := feeService.CalculateProjectFee(ctx, &entities.CalculateProjectFeeRequest{
+		ProjectID:          project.ProjectID,
+		TotalFundingAmount: idr("10000000"),
+		CalculateDate:      now,
+	}, uuid.New())
+
+	require.NoError(t, err)
+	assert.Equal(t, cooperative.ID, *calculation.FeeScheduleID)
+	assert.Equal(t, idr("150000"), calculation.FeeAmount)
+	assert.Equal(t, 1.5, calculation.FeePercentage)
+	assert.Equal(t, project.CooperativeID, calculation.CooperativeID)
+	assert.Equal(t, project.BusinessID, calculation.BusinessID)
+	assert.Equal(t, entities.ProjectFeeStatusCalculated, calculation.FeeStatus)
+	feeRepo.AssertCalled(t, "CreateCalculation", ctx, calculation, (*entities.FeeCreditNote)(nil))
+}
+
+func TestFeeService_CalculateProjectFee_AppliesUnexpiredWaiver(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	feeRepo := new(MockFeeRepository)
+	feeService := NewFeeService(feeRepo, currencyService, mockAuditService, ledgerService)
+	ctx := context.Background()
+	now := time.Now()
+
+	project := &entities.FeeProject{ProjectID: uuid.New(), CooperativeID: uuid.New(), BusinessID: uuid.New(), Currency: "IDR"}
+	expired := &entities.FeeWaiver{ID: uuid.New(), FeeType: entities.ComFundsFeeTypeSuccessFee, WaiverPercentage: 100, ValidFrom: now.AddDate(0, -2, 0), ExpiresAt: now.AddDate(0, 0, -1), IsActive: true}
+	launch := &entities.FeeWaiver{ID: uuid.New(), FeeType: entities.ComFundsFeeTypeSuccessFee, CooperativeID: &project.CooperativeID, WaiverPercentage: 50, ValidFrom: now.AddDate(0, 0, -7), ExpiresAt: now.AddDate(0, 1, 0), IsActive: true}
+
+	feeRepo.On("GetFeeProject", ctx, project.ProjectID).Return(project, nil)
+	feeRepo.On("ListActiveFees", ctx, entities.ComFundsFeeTypeSuccessFee).Return([]*entities.ComFundsFee{{
+		ID:            uuid.New(),
+		FeeType:       entities.ComFundsFeeTypeSuccessFee,
+		FeePercentage: 2,
+		TierMode:      entities.FeeTierModeFlat,
+		IsEnabled:     true,
+		ApplicableTo:  entities.ComFundsFeeApplicableToSuccessfulFunding,
+		EffectiveFrom: now.AddDate(-1, 0, 0),
+		IsActive:      true,
+	}}, nil)
+	feeRepo.On("ListWaivers", ctx, entities.ComFundsFeeTypeSuccessFee, true).Return([]*entities.FeeWaiver{expired, launch}, nil)
+	var creditNote *entities.FeeCreditNote
+	feeRepo.On("CreateCalculation", ctx, mock.AnythingOfType("*entities.ProjectFeeCalculation"), mock.AnythingOfType("*entities.FeeCreditNote")).Run(func(args mock.Arguments) {
+		creditNote = args.Get(2).(*entities.FeeCreditNote)
+	}).Return(nil)
+
+	calculation, err := feeService.CalculateProjectFee(ctx, &entities.CalculateProjectFeeRequest{
+		ProjectID:          project.ProjectID,
+		TotalFundingAmount: idr("1000000"),
+		CalculateDate:      now,
+	}, uuid.New())
+
+	require.NoError(t, err)
+	assert.Equal(t, launch.ID, *calculation.WaiverID)
+	assert.Equal(t, idr("20000"), calculation.GrossFeeAmount)
+	assert.Equal(t, idr("10000"), calculation.WaivedAmount)
+	assert.Equal(t, idr("10000"), calculation.FeeAmount)
+	assert.Equal(t, idr("990000"), calculation.NetAmountAfterFee)
+
+	// The waived half is credited to the business
+	require.NotNil(t, creditNote)
+	assert.Equal(t, idr("10000"), creditNote.Amount)
+	assert.Equal(t, calculation.ID, creditNote.FeeCalculationID)
+	assert.Equal(t, creditNote.ID, *calculation.CreditNoteID)
+}
+
+func TestFeeService_CalculateProjectFee_ReusesFundingClosureCalculation(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	feeRepo := new(MockFeeRepository)
+	feeService := NewFeeService(feeRepo, currencyService, mockAuditService, ledgerService)
+	ctx := context.Background()
+
+	project := &entities.FeeProject{ProjectID: uuid.New(), CooperativeID: uuid.New(), BusinessID: uuid.New(), Currency: "IDR"}
+	closureID := uuid.New()
+	charged := &entities.ProjectFeeCalculation{ID: uuid.New(), ProjectID: project.ProjectID, FundingClosureID: &closureID, FeeAmount: idr("1600")}
+	feeRepo.On("GetFeeProject", ctx, project.ProjectID).Return(project, nil)
+	feeRepo.On("GetClosureCalculation", ctx, project.CooperativeID, closureID).Return(charged, nil)
+
+	// A retried closure gets the fee it was already charged
+	calculation, err := feeService.CalculateProjectFee(ctx, &entities.CalculateProjectFeeRequest{
+		ProjectID:          project.ProjectID,
+		TotalFundingAmount: idr("80000"),
+		CalculateDate:      time.Now(),
+		FundingClosureID:   &closureID,
+	}, uuid.Nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, charged, calculation)
+	feeRepo.AssertNotCalled(t, "CreateCalculation", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFeeService_CollectProjectFee_IssuesInvoice(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	feeRepo := new(MockFeeRepository)
+	feeService := NewFeeService(feeRepo, currencyService, mockAuditService, ledgerService)
+	ctx := context.Background()
+
+	calculation := &entities.ProjectFeeCalculation{
+		ID:             uuid.New(),
+		ProjectID:      uuid.New(),
+		CooperativeID:  uuid.New(),
+		BusinessID:     uuid.New(),
+		Currency:       "IDR",
+		GrossFeeAmount: idr("20000"),
+		WaivedAmount:   idr("5000"),
+		FeeAmount:      idr("15000"),
+		FeeStatus:      entities.ProjectFeeStatusCalculated,
+	}
+	feeRepo.On("GetCalculation", ctx, calculation.ID).Return(calculation, nil)
+	ledgerRepo.On("GetAccountByCode", ctx, calculation.CooperativeID, mock.AnythingOfType("string"), "IDR").Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}, nil)
+	// The ledger entry is posted by the fee repository with the invoice
+	var posted *entities.JournalEntry
+	feeRepo.On("CollectFee", ctx, calculation, mock.AnythingOfType("*entities.FeeInvoice"), mock.AnythingOfType("*entities.JournalEntry")).Run(func(args mock.Arguments) {
+		args.Get(2).(*entities.FeeInvoice).InvoiceNumber = "INV-2026-000001"
+		posted = args.Get(3).(*entities.JournalEntry)
+	}).Return(nil)
+
+	invoice, err := feeService.CollectProjectFee(ctx, &entities.CollectProjectFeeRequest{
+		ProjectFeeCalculationID: calculation.ID,
+		CollectionMethod:        entities.ProjectFeeCollectionMethodBankTransfer,
+		TransactionReference:    "TRX-1",
+	}, uuid.New())
+
+	require.NoError(t, err)
+	assert.Equal(t, "INV-2026-000001", invoice.InvoiceNumber)
+	assert.Equal(t, calculation.CooperativeID, invoice.CooperativeID)
+	assert.Equal(t, idr("20000"), invoice.GrossAmount)
+	assert.Equal(t, idr("5000"), invoice.DiscountAmount)
+	assert.Equal(t, idr("15000"), invoice.NetAmount)
+	require.NotNil(t, posted)
+	assert.Equal(t, idr("15000"), posted.TotalAmount)
+	assert.Equal(t, "IDR", posted.Currency)
+	assert.Equal(t, entities.ProjectFeeStatusCollected, calculation.FeeStatus)
+	ledgerRepo.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything)
+}
+
+func TestFeeService_CollectProjectFee_NotCollectedWhenLedgerEntryFails(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	feeRepo := new(MockFeeRepository)
+	feeService := NewFeeService(feeRepo, currencyService, mockAuditService, ledgerService)
+	ctx := context.Background()
+
+	calculation := &entities.ProjectFeeCalculation{
+		ID:            uuid.New(),
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		BusinessID:    uuid.New(),
+		Currency:      "IDR",
+		FeeAmount:     idr("15000"),
+		FeeStatus:     entities.ProjectFeeStatusCalculated,
+	}
+	feeRepo.On("GetCalculation", ctx, calculation.ID).Return(calculation, nil)
+	ledgerRepo.On("GetAccountByCode", ctx, calculation.CooperativeID, mock.AnythingOfType("string"), "IDR").Return(nil, repositories.ErrLedgerAccountNotFound)
+	ledgerRepo.On("CreateAccount", ctx, mock.AnythingOfType("*entities.LedgerAccount")).Return(errors.New("shard unavailable"))
+
+	_, err := feeService.CollectProjectFee(ctx, &entities.CollectProjectFeeRequest{
+		ProjectFeeCalculationID: calculation.ID,
+		CollectionMethod:        entities.ProjectFeeCollectionMethodBankTransfer,
+	}, uuid.New())
+
+	require.Error(t, err)
+	feeRepo.AssertNotCalled(t, "CollectFee", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, entities.ProjectFeeStatusCalculated, calculation.FeeStatus)
+}
+
+func TestFeeService_WaiveProjectFee_IssuesCreditNote(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	feeRepo := new(MockFeeRepository)
+	feeService := NewFeeService(feeRepo, currencyService, mockAuditService, ledgerService)
+	ctx := context.Background()
+
+	calculation := &entities.ProjectFeeCalculation{
+		ID:            uuid.New(),
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		BusinessID:    uuid.New(),
+		Currency:      "IDR",
+		FeeAmount:     idr("20000"),
+		FeeStatus:     entities.ProjectFeeStatusCalculated,
+	}
+	feeRepo.On("GetCalculation", ctx, calculation.ID).Return(calculation, nil)
+	feeRepo.On("WaiveFee", ctx, calculation, mock.AnythingOfType("*entities.FeeCreditNote")).Run(func(args mock.Arguments) {
+		args.Get(2).(*entities.FeeCreditNote).CreditNoteNumber = "CN-2026-000001"
+	}).Return(nil)
+
+	_, err := feeService.WaiveProjectFee(ctx, calculation.ID, uuid.New(), "")
+	require.Error(t, err)
+
+	creditNote, err := feeService.WaiveProjectFee(ctx, calculation.ID, uuid.New(), "Hardship relief")
+	require.NoError(t, err)
+	assert.Equal(t, "CN-2026-000001", creditNote.CreditNoteNumber)
+	assert.Equal(t, idr("20000"), creditNote.Amount)
+	assert.Equal(t, calculation.ID, creditNote.FeeCalculationID)
+
+	calculation.FeeStatus = entities.ProjectFeeStatusCollected
+	_, err = feeService.WaiveProjectFee(ctx, calculation.ID, uuid.New(), "Too late")
+	assert.Error(t, err)
+}
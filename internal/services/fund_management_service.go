@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
 
 	"github.com/google/uuid"
 )
@@ -22,6 +24,13 @@ type FundManagementService interface {
 	GetProjectDisbursements(ctx context.Context, projectID uuid.UUID, page, limit int) ([]*entities.FundDisbursement, int, error)
 	SearchFundDisbursements(ctx context.Context, filter *entities.FundDisbursementFilter) ([]*entities.FundDisbursement, int, error)
 
+	// FR-046: Funded projects are released in tranches, one per milestone
+	DefineDisbursementTranches(ctx context.Context, projectID uuid.UUID, req *entities.DefineDisbursementTranchesRequest, definerID uuid.UUID) ([]*entities.DisbursementTranche, error)
+	GetDisbursementPlan(ctx context.Context, projectID uuid.UUID) (*entities.DisbursementPlan, error)
+	SubmitMilestoneEvidence(ctx context.Context, projectID, trancheID uuid.UUID, req *entities.SubmitMilestoneEvidenceRequest, submitterID uuid.UUID) (*entities.DisbursementTranche, error)
+	VerifyMilestone(ctx context.Context, projectID, trancheID uuid.UUID, req *entities.VerifyMilestoneRequest, verifierID uuid.UUID) (*entities.DisbursementTranche, error)
+	CloseDisbursementTranche(ctx context.Context, projectID, trancheID uuid.UUID, req *entities.CloseDisbursementTrancheRequest, closerID uuid.UUID) (*entities.DisbursementTranche, error)
+
 	// FR-047: System shall track fund usage and business performance
 	CreateFundUsage(ctx context.Context, req *entities.CreateFundUsageRequest, recorderID uuid.UUID) (*entities.FundUsage, error)
 	VerifyFundUsage(ctx context.Context, usageID, verifierID uuid.UUID, comments string) error
@@ -31,8 +40,8 @@ type FundManagementService interface {
 	CalculateFundUsageROI(ctx context.Context, projectID uuid.UUID) (float64, error)
 
 	// FR-048: Funds are held in cooperative account with proper audit trails
-	GetCooperativeFundBalance(ctx context.Context, cooperativeID uuid.UUID) (float64, error)
-	GetProjectFundBalance(ctx context.Context, projectID uuid.UUID) (float64, error)
+	GetCooperativeFundBalance(ctx context.Context, cooperativeID uuid.UUID) ([]entities.Money, error)
+	GetProjectFundBalance(ctx context.Context, projectID uuid.UUID) ([]entities.Money, error)
 	GetFundAuditTrail(ctx context.Context, projectID uuid.UUID, startDate, endDate time.Time) ([]map[string]interface{}, error)
 
 	// FR-049: System shall support fund refunds if project fails to meet minimum funding
@@ -42,7 +51,7 @@ type FundManagementService interface {
 	GetFundRefund(ctx context.Context, refundID uuid.UUID) (*entities.FundRefund, error)
 	GetProjectRefunds(ctx context.Context, projectID uuid.UUID, page, limit int) ([]*entities.FundRefund, int, error)
 	SearchFundRefunds(ctx context.Context, filter *entities.FundRefundFilter) ([]*entities.FundRefund, int, error)
-	CalculateRefundAmounts(ctx context.Context, projectID uuid.UUID, refundType string) (map[uuid.UUID]float64, error)
+	CalculateRefundAmounts(ctx context.Context, projectID uuid.UUID, refundType string) (map[uuid.UUID]entities.Money, error)
 
 	// Fund management reporting
 	GetFundManagementSummary(ctx context.Context, cooperativeID uuid.UUID, startDate, endDate time.Time) (*entities.FundManagementSummary, error)
@@ -51,41 +60,88 @@ type FundManagementService interface {
 
 // fundManagementService implements FundManagementService
 type fundManagementService struct {
-	auditService AuditService
+	disbursementRepo repositories.DisbursementRepository
+	currencyService  CurrencyService
+	auditService     AuditService
+	ledgerService    LedgerService
+	payoutService    PayoutService
 	// Add repositories when implemented
 }
 
-// NewFundManagementService creates a new fund management service
-func NewFundManagementService(auditService AuditService) FundManagementService {
+// NewFundManagementService creates a new fund management service. Processed
+// disbursements and refunds are queued for bank payout when payoutService is set.
+func NewFundManagementService(disbursementRepo repositories.DisbursementRepository, currencyService CurrencyService, auditService AuditService, ledgerService LedgerService, payoutService PayoutService) FundManagementService {
 	return &fundManagementService{
-		auditService: auditService,
+		disbursementRepo: disbursementRepo,
+		currencyService:  currencyService,
+		auditService:     auditService,
+		ledgerService:    ledgerService,
+		payoutService:    payoutService,
 	}
 }
 
-// CreateFundDisbursement implements FR-046: Fund disbursement to business owners
+// CreateFundDisbursement implements FR-046: Fund disbursement to business owners.
+// The disbursement draws on its milestone's tranche, so the milestone must be
+// completed and verified, and the amount must fit both the tranche's remaining
+// budget and the project's escrow balance.
 func (s *fundManagementService) CreateFundDisbursement(ctx context.Context, req *entities.CreateFundDisbursementRequest, requesterID uuid.UUID) (*entities.FundDisbursement, error) {
+	project, err := s.disbursementRepo.GetDisbursementProject(ctx, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	currency := s.projectCurrency(project)
+	if req.Currency != currency {
+		return nil, fmt.Errorf("project funds are held in %s, not %s", currency, req.Currency)
+	}
+
 	// Validate disbursement request
-	if req.DisbursementAmount <= 0 {
+	disbursementAmount, err := req.DisbursementAmount.InCurrency(currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid disbursement amount: %w", err)
+	}
+	if !disbursementAmount.IsPositive() {
 		return nil, errors.New("disbursement amount must be greater than zero")
 	}
 
+	tranche, err := s.disbursementRepo.GetMilestoneTranche(ctx, req.ProjectID, req.MilestoneID)
+	if err != nil {
+		return nil, err
+	}
+	if tranche == nil {
+		return nil, errors.New("milestone has no disbursement tranche")
+	}
+	if err := checkTrancheReleasable(tranche); err != nil {
+		return nil, err
+	}
+	if tranche.RemainingBudget().LessThan(disbursementAmount) {
+		return nil, fmt.Errorf("milestone %s has %s of its budget left to disburse, requested %s", tranche.MilestoneTitle, tranche.RemainingBudget(), disbursementAmount)
+	}
+	if err := s.checkEscrowCovers(ctx, req.ProjectID, currency, disbursementAmount); err != nil {
+		return nil, err
+	}
+
 	// Create disbursement record
+	now := time.Now()
 	disbursement := &entities.FundDisbursement{
 		ID:                 uuid.New(),
 		ProjectID:          req.ProjectID,
-		BusinessID:         uuid.Nil, // Will be set based on project's business
-		CooperativeID:      uuid.Nil, // Will be set based on project's cooperative
+		BusinessID:         project.BusinessID,
+		CooperativeID:      project.CooperativeID,
 		MilestoneID:        req.MilestoneID,
-		DisbursementAmount: req.DisbursementAmount,
-		Currency:           req.Currency,
+		TrancheID:          tranche.ID,
+		DisbursementAmount: disbursementAmount,
+		Currency:           currency,
 		DisbursementType:   req.DisbursementType,
 		DisbursementReason: req.DisbursementReason,
 		Status:             entities.FundDisbursementStatusPending,
 		BankAccount:        req.BankAccount,
 		EscrowAccountID:    uuid.Nil, // Will be set during processing
 		IsActive:           true,
-		CreatedAt:          time.Now(),
-		UpdatedAt:          time.Now(),
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := s.disbursementRepo.CreateDisbursement(ctx, disbursement, requesterID); err != nil {
+		return nil, err
 	}
 
 	// Log audit trail
@@ -94,15 +150,48 @@ func (s *fundManagementService) CreateFundDisbursement(ctx context.Context, req
 		Operation:  "create_fund_disbursement",
 		EntityType: "fund_disbursement",
 		EntityID:   disbursement.ID,
-		NewValues:  fmt.Sprintf("Created disbursement request for %f %s", req.DisbursementAmount, req.Currency),
+		NewValues:  fmt.Sprintf("Created disbursement request for %s from milestone %s", disbursementAmount, tranche.MilestoneTitle),
 	})
 
 	return disbursement, nil
 }
 
-// ApproveFundDisbursement approves a fund disbursement
+// ApproveFundDisbursement approves a fund disbursement once its milestone is
+// still releasable and escrow covers everything awaiting release
 func (s *fundManagementService) ApproveFundDisbursement(ctx context.Context, disbursementID, approverID uuid.UUID, comments string) error {
-	// Mock implementation - would update disbursement status to approved
+	disbursement, err := s.disbursementRepo.GetDisbursement(ctx, disbursementID)
+	if err != nil {
+		return err
+	}
+	if disbursement.Status != entities.FundDisbursementStatusPending {
+		return fmt.Errorf("disbursement is %s and cannot be approved", disbursement.Status)
+	}
+
+	tranche, err := s.disbursementRepo.GetTranche(ctx, disbursement.ProjectID, disbursement.TrancheID)
+	if err != nil {
+		return err
+	}
+	if err := checkTrancheReleasable(tranche); err != nil {
+		return err
+	}
+
+	// The outstanding amount already includes this disbursement
+	if err := s.checkEscrowCovers(ctx, disbursement.ProjectID, disbursement.Currency, entities.ZeroMoney(disbursement.Currency)); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	disbursement.Status = entities.FundDisbursementStatusApproved
+	disbursement.ApprovedBy = &approverID
+	disbursement.ApprovedAt = &now
+	disbursement.UpdatedAt = now
+	updated, err := s.disbursementRepo.UpdateDisbursementStatus(ctx, disbursement, entities.FundDisbursementStatusPending)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.New("disbursement is no longer pending")
+	}
 
 	// Log audit trail
 	s.auditService.LogOperation(ctx, &LogOperationRequest{
@@ -116,9 +205,27 @@ func (s *fundManagementService) ApproveFundDisbursement(ctx context.Context, dis
 	return nil
 }
 
-// RejectFundDisbursement rejects a fund disbursement
+// RejectFundDisbursement rejects a fund disbursement, returning its amount to
+// the tranche's budget
 func (s *fundManagementService) RejectFundDisbursement(ctx context.Context, disbursementID, rejecterID uuid.UUID, reason string) error {
-	// Mock implementation - would update disbursement status to rejected
+	disbursement, err := s.disbursementRepo.GetDisbursement(ctx, disbursementID)
+	if err != nil {
+		return err
+	}
+	if disbursement.Status != entities.FundDisbursementStatusPending {
+		return fmt.Errorf("disbursement is %s and cannot be rejected", disbursement.Status)
+	}
+
+	disbursement.Status = entities.FundDisbursementStatusRejected
+	disbursement.RejectionReason = reason
+	disbursement.UpdatedAt = time.Now()
+	updated, err := s.disbursementRepo.UpdateDisbursementStatus(ctx, disbursement, entities.FundDisbursementStatusPending)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.New("disbursement is no longer pending")
+	}
 
 	// Log audit trail
 	s.auditService.LogOperation(ctx, &LogOperationRequest{
@@ -132,13 +239,45 @@ func (s *fundManagementService) RejectFundDisbursement(ctx context.Context, disb
 	return nil
 }
 
-// ProcessFundDisbursement processes the actual fund transfer
+// ProcessFundDisbursement processes the actual fund transfer. The release is
+// committed against the tranche before the ledger and payout record it.
 func (s *fundManagementService) ProcessFundDisbursement(ctx context.Context, disbursementID, processorID uuid.UUID) error {
-	// Mock implementation - would:
-	// 1. Transfer funds from escrow account to business account
-	// 2. Update disbursement status to disbursed
-	// 3. Generate transaction reference
-	// 4. Update escrow account balance
+	disbursement, err := s.GetFundDisbursement(ctx, disbursementID)
+	if err != nil {
+		return fmt.Errorf("failed to get disbursement: %w", err)
+	}
+	if disbursement.Status != entities.FundDisbursementStatusApproved {
+		return fmt.Errorf("disbursement is %s and cannot be processed", disbursement.Status)
+	}
+
+	balance, err := s.ledgerService.GetProjectEscrowBalance(ctx, disbursement.ProjectID, disbursement.DisbursementAmount.Currency())
+	if err != nil {
+		return err
+	}
+	if balance.LessThan(disbursement.DisbursementAmount) {
+		return fmt.Errorf("insufficient project escrow balance: available %s, requested %s", balance, disbursement.DisbursementAmount)
+	}
+
+	now := time.Now()
+	tranche, err := s.disbursementRepo.MarkDisbursed(ctx, disbursement, now)
+	if err != nil {
+		return err
+	}
+
+	// Move the funds from escrow to the business in the ledger; the escrow
+	// balance is derived from the posted entry
+	if _, err := s.ledgerService.RecordDisbursement(ctx, disbursement, processorID); err != nil {
+		return fmt.Errorf("disbursement released but not recorded in ledger: %w", err)
+	}
+
+	// The bank transfer itself goes out in the next payout batch
+	if s.payoutService != nil {
+		if _, err := s.payoutService.QueueDisbursement(ctx, disbursement, processorID); err != nil {
+			return fmt.Errorf("disbursement released but payout was not queued: %w", err)
+		}
+	}
+	disbursement.Status = entities.FundDisbursementStatusDisbursed
+	disbursement.DisbursedAt = &now
 
 	// Log audit trail
 	s.auditService.LogOperation(ctx, &LogOperationRequest{
@@ -146,7 +285,7 @@ func (s *fundManagementService) ProcessFundDisbursement(ctx context.Context, dis
 		Operation:  "process_fund_disbursement",
 		EntityType: "fund_disbursement",
 		EntityID:   disbursementID,
-		NewValues:  "Processed fund disbursement transfer",
+		NewValues:  fmt.Sprintf("Released %s from milestone %s; tranche is %s", disbursement.DisbursementAmount, tranche.MilestoneTitle, tranche.Status),
 	})
 
 	return nil
@@ -154,48 +293,273 @@ func (s *fundManagementService) ProcessFundDisbursement(ctx context.Context, dis
 
 // GetFundDisbursement gets disbursement by ID
 func (s *fundManagementService) GetFundDisbursement(ctx context.Context, disbursementID uuid.UUID) (*entities.FundDisbursement, error) {
-	// Mock implementation
-	return &entities.FundDisbursement{
-		ID:                 disbursementID,
-		ProjectID:          uuid.New(),
-		BusinessID:         uuid.New(),
-		CooperativeID:      uuid.New(),
-		MilestoneID:        uuid.New(),
-		DisbursementAmount: 50000.0,
-		Currency:           "IDR",
-		DisbursementType:   entities.FundDisbursementTypeMilestone,
-		DisbursementReason: "Equipment purchase milestone completed",
-		Status:             entities.FundDisbursementStatusApproved,
-		BankAccount:        "1234567890",
-		IsActive:           true,
-		CreatedAt:          time.Now(),
-		UpdatedAt:          time.Now(),
-	}, nil
+	return s.disbursementRepo.GetDisbursement(ctx, disbursementID)
 }
 
 // GetProjectDisbursements gets project disbursements
 func (s *fundManagementService) GetProjectDisbursements(ctx context.Context, projectID uuid.UUID, page, limit int) ([]*entities.FundDisbursement, int, error) {
-	// Mock implementation
-	disbursements := []*entities.FundDisbursement{
-		{
-			ID:                 uuid.New(),
-			ProjectID:          projectID,
-			BusinessID:         uuid.New(),
-			CooperativeID:      uuid.New(),
-			MilestoneID:        uuid.New(),
-			DisbursementAmount: 50000.0,
-			Currency:           "IDR",
-			DisbursementType:   entities.FundDisbursementTypeMilestone,
-			DisbursementReason: "Equipment purchase milestone completed",
-			Status:             entities.FundDisbursementStatusDisbursed,
-			BankAccount:        "1234567890",
-			IsActive:           true,
-			CreatedAt:          time.Now(),
-			UpdatedAt:          time.Now(),
-		},
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
 	}
 
-	return disbursements, 1, nil
+	return s.disbursementRepo.ListProjectDisbursements(ctx, projectID, limit, (page-1)*limit)
+}
+
+// DefineDisbursementTranches splits a funded project's money over its
+// milestones. Every milestone gets one tranche and the budgets must add up to
+// exactly what the project raised.
+func (s *fundManagementService) DefineDisbursementTranches(ctx context.Context, projectID uuid.UUID, req *entities.DefineDisbursementTranchesRequest, definerID uuid.UUID) ([]*entities.DisbursementTranche, error) {
+	project, err := s.disbursementRepo.GetDisbursementProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.Status != entities.ProjectStatusFunded {
+		return nil, fmt.Errorf("project is %s; tranches are defined once it is funded", project.Status)
+	}
+
+	currency := s.projectCurrency(project)
+	funded := project.FundedAmount.WithCurrency(currency)
+
+	now := time.Now()
+	total := entities.ZeroMoney(currency)
+	seen := make(map[uuid.UUID]bool, len(req.Tranches))
+	tranches := make([]*entities.DisbursementTranche, 0, len(req.Tranches))
+	for i, input := range req.Tranches {
+		if seen[input.MilestoneID] {
+			return nil, fmt.Errorf("milestone %s has more than one tranche", input.MilestoneID)
+		}
+		seen[input.MilestoneID] = true
+
+		budget := input.Budget.WithCurrency(currency)
+		if !budget.IsPositive() {
+			return nil, fmt.Errorf("tranche budget for milestone %s must be greater than zero", input.MilestoneTitle)
+		}
+		total = total.Add(budget)
+
+		tranches = append(tranches, &entities.DisbursementTranche{
+			ID:                uuid.New(),
+			ProjectID:         projectID,
+			CooperativeID:     project.CooperativeID,
+			MilestoneID:       input.MilestoneID,
+			MilestoneTitle:    input.MilestoneTitle,
+			MilestoneStatus:   entities.MilestoneStatusPending,
+			Sequence:          i + 1,
+			Currency:          currency,
+			Budget:            budget,
+			ReleasedAmount:    entities.ZeroMoney(currency),
+			UnusedAmount:      entities.ZeroMoney(currency),
+			Status:            entities.DisbursementTrancheStatusPlanned,
+			EvidenceDocuments: []string{},
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		})
+	}
+	if !total.Equal(funded) {
+		return nil, fmt.Errorf("tranche budgets total %s but the project raised %s", total, funded)
+	}
+
+	if err := s.disbursementRepo.CreateTranches(ctx, projectID, funded, tranches); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     definerID,
+		Operation:  "define_disbursement_tranches",
+		EntityType: entities.AuditEntityProject,
+		EntityID:   projectID,
+		NewValues:  tranches,
+	})
+
+	return tranches, nil
+}
+
+// GetDisbursementPlan shows a project's tranches and how much of its funding
+// has been released, awaits release or is left over for refund
+func (s *fundManagementService) GetDisbursementPlan(ctx context.Context, projectID uuid.UUID) (*entities.DisbursementPlan, error) {
+	project, err := s.disbursementRepo.GetDisbursementProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	currency := s.projectCurrency(project)
+
+	tranches, err := s.disbursementRepo.ListTranches(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	outstanding, err := s.disbursementRepo.GetOutstandingAmount(ctx, projectID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &entities.DisbursementPlan{
+		ProjectID:    projectID,
+		Currency:     currency,
+		FundedAmount: project.FundedAmount.WithCurrency(currency),
+		Released:     entities.ZeroMoney(currency),
+		Outstanding:  outstanding,
+		Unused:       entities.ZeroMoney(currency),
+		Tranches:     tranches,
+	}
+	for _, tranche := range tranches {
+		plan.Released = plan.Released.Add(tranche.ReleasedAmount)
+		plan.Unused = plan.Unused.Add(tranche.UnusedAmount)
+	}
+
+	return plan, nil
+}
+
+// SubmitMilestoneEvidence lets the project owner mark a milestone completed,
+// with the evidence the cooperative verifies before its tranche is released
+func (s *fundManagementService) SubmitMilestoneEvidence(ctx context.Context, projectID, trancheID uuid.UUID, req *entities.SubmitMilestoneEvidenceRequest, submitterID uuid.UUID) (*entities.DisbursementTranche, error) {
+	project, err := s.disbursementRepo.GetDisbursementProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.OwnerID != submitterID {
+		return nil, errors.New("only the project owner can submit milestone evidence")
+	}
+
+	tranche, err := s.disbursementRepo.GetTranche(ctx, projectID, trancheID)
+	if err != nil {
+		return nil, err
+	}
+	if tranche.Status != entities.DisbursementTrancheStatusPlanned || tranche.MilestoneStatus != entities.MilestoneStatusPending {
+		return nil, fmt.Errorf("milestone %s is not awaiting completion", tranche.MilestoneTitle)
+	}
+
+	now := time.Now()
+	tranche.MilestoneStatus = entities.MilestoneStatusCompleted
+	tranche.EvidenceDocuments = req.EvidenceDocuments
+	tranche.CompletionNotes = req.Notes
+	tranche.CompletedBy = &submitterID
+	tranche.CompletedAt = &now
+	tranche.UpdatedAt = now
+	updated, err := s.disbursementRepo.UpdateTrancheMilestone(ctx, tranche, entities.DisbursementTrancheStatusPlanned, entities.MilestoneStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("milestone changed while submitting evidence; retry")
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     submitterID,
+		Operation:  "submit_milestone_evidence",
+		EntityType: entities.AuditEntityProject,
+		EntityID:   projectID,
+		NewValues:  fmt.Sprintf("Milestone %s completed with %d evidence documents", tranche.MilestoneTitle, len(tranche.EvidenceDocuments)),
+	})
+
+	return tranche, nil
+}
+
+// VerifyMilestone records the cooperative's review of a completed milestone.
+// Approving opens its tranche for release; rejecting sends the milestone back
+// to the owner with the reason.
+func (s *fundManagementService) VerifyMilestone(ctx context.Context, projectID, trancheID uuid.UUID, req *entities.VerifyMilestoneRequest, verifierID uuid.UUID) (*entities.DisbursementTranche, error) {
+	tranche, err := s.disbursementRepo.GetTranche(ctx, projectID, trancheID)
+	if err != nil {
+		return nil, err
+	}
+	if tranche.Status != entities.DisbursementTrancheStatusPlanned || tranche.MilestoneStatus != entities.MilestoneStatusCompleted {
+		return nil, fmt.Errorf("milestone %s is not awaiting verification", tranche.MilestoneTitle)
+	}
+
+	now := time.Now()
+	tranche.VerificationNotes = req.Notes
+	tranche.UpdatedAt = now
+	operation := "reject_milestone_evidence"
+	if req.Approve {
+		operation = "verify_milestone"
+		tranche.Status = entities.DisbursementTrancheStatusOpen
+		tranche.VerifiedBy = &verifierID
+		tranche.VerifiedAt = &now
+	} else {
+		tranche.MilestoneStatus = entities.MilestoneStatusPending
+		tranche.CompletedBy = nil
+		tranche.CompletedAt = nil
+	}
+
+	updated, err := s.disbursementRepo.UpdateTrancheMilestone(ctx, tranche, entities.DisbursementTrancheStatusPlanned, entities.MilestoneStatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("milestone changed while verifying; retry")
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     verifierID,
+		Operation:  operation,
+		EntityType: entities.AuditEntityProject,
+		EntityID:   projectID,
+		NewValues:  fmt.Sprintf("Milestone %s: %s", tranche.MilestoneTitle, req.Notes),
+	})
+
+	return tranche, nil
+}
+
+// CloseDisbursementTranche stops further releases from a tranche, keeping its
+// unreleased budget as unused so it can be refunded to investors later
+func (s *fundManagementService) CloseDisbursementTranche(ctx context.Context, projectID, trancheID uuid.UUID, req *entities.CloseDisbursementTrancheRequest, closerID uuid.UUID) (*entities.DisbursementTranche, error) {
+	tranche, err := s.disbursementRepo.CloseTranche(ctx, projectID, trancheID, closerID, req.Reason, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     closerID,
+		Operation:  "close_disbursement_tranche",
+		EntityType: entities.AuditEntityProject,
+		EntityID:   projectID,
+		NewValues:  fmt.Sprintf("Closed milestone %s tranche with %s unused: %s", tranche.MilestoneTitle, tranche.UnusedAmount, req.Reason),
+	})
+
+	return tranche, nil
+}
+
+// checkTrancheReleasable explains why a tranche cannot release funds yet
+func checkTrancheReleasable(tranche *entities.DisbursementTranche) error {
+	switch {
+	case tranche.Releasable():
+		return nil
+	case tranche.Status != entities.DisbursementTrancheStatusPlanned && tranche.Status != entities.DisbursementTrancheStatusOpen:
+		return fmt.Errorf("milestone %s tranche is %s", tranche.MilestoneTitle, tranche.Status)
+	case tranche.MilestoneStatus != entities.MilestoneStatusCompleted:
+		return fmt.Errorf("milestone %s has not been completed", tranche.MilestoneTitle)
+	default:
+		return fmt.Errorf("milestone %s has not been verified by the cooperative", tranche.MilestoneTitle)
+	}
+}
+
+// checkEscrowCovers checks the project's escrow balance covers amount on top of
+// the disbursements already awaiting release
+func (s *fundManagementService) checkEscrowCovers(ctx context.Context, projectID uuid.UUID, currency string, amount entities.Money) error {
+	balance, err := s.ledgerService.GetProjectEscrowBalance(ctx, projectID, currency)
+	if err != nil {
+		return err
+	}
+	outstanding, err := s.disbursementRepo.GetOutstandingAmount(ctx, projectID, currency)
+	if err != nil {
+		return err
+	}
+
+	available := balance.Sub(outstanding)
+	if available.LessThan(amount) {
+		return fmt.Errorf("insufficient project escrow balance: %s available after %s awaiting release, requested %s", available, outstanding, amount)
+	}
+	return nil
+}
+
+// projectCurrency is the currency a project's funds are held in
+func (s *fundManagementService) projectCurrency(project *entities.DisbursementProject) string {
+	if project.Currency != "" {
+		return project.Currency
+	}
+	return s.currencyService.BaseCurrency()
 }
 
 // SearchFundDisbursements searches disbursements with filters
@@ -207,25 +571,34 @@ func (s *fundManagementService) SearchFundDisbursements(ctx context.Context, fil
 // CreateFundUsage implements FR-047: Track fund usage and business performance
 func (s *fundManagementService) CreateFundUsage(ctx context.Context, req *entities.CreateFundUsageRequest, recorderID uuid.UUID) (*entities.FundUsage, error) {
 	// Validate usage request
-	if req.UsageAmount <= 0 {
-		return nil, errors.New("usage amount must be greater than zero")
+	usageAmount, err := req.UsageAmount.InCurrency(req.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid usage amount: %w", err)
 	}
-
-	// Calculate ROI if revenue data is provided
-	roi := 0.0
-	if req.RevenueGenerated != nil && *req.RevenueGenerated > 0 {
-		roi = (*req.RevenueGenerated / req.UsageAmount) * 100
+	if !usageAmount.IsPositive() {
+		return nil, errors.New("usage amount must be greater than zero")
 	}
 
 	// Handle optional fields
-	revenueGenerated := 0.0
+	revenueGenerated := entities.ZeroMoney(req.Currency)
 	if req.RevenueGenerated != nil {
-		revenueGenerated = *req.RevenueGenerated
+		if revenueGenerated, err = req.RevenueGenerated.InCurrency(req.Currency); err != nil {
+			return nil, fmt.Errorf("invalid revenue generated: %w", err)
+		}
 	}
 
-	costSavings := 0.0
+	costSavings := entities.ZeroMoney(req.Currency)
 	if req.CostSavings != nil {
-		costSavings = *req.CostSavings
+		if costSavings, err = req.CostSavings.InCurrency(req.Currency); err != nil {
+			return nil, fmt.Errorf("invalid cost savings: %w", err)
+		}
+	}
+
+	// Calculate ROI if revenue data is provided
+	roi := 0.0
+	if revenueGenerated.IsPositive() {
+		roi, _ = revenueGenerated.Ratio(usageAmount).Float64()
+		roi *= 100
 	}
 
 	// Create fund usage record
@@ -235,7 +608,7 @@ func (s *fundManagementService) CreateFundUsage(ctx context.Context, req *entiti
 		BusinessID:         uuid.Nil, // Will be set based on project's business
 		DisbursementID:     req.DisbursementID,
 		UsageCategory:      req.UsageCategory,
-		UsageAmount:        req.UsageAmount,
+		UsageAmount:        usageAmount,
 		Currency:           req.Currency,
 		UsageDescription:   req.UsageDescription,
 		UsageDate:          req.UsageDate,
@@ -257,7 +630,7 @@ func (s *fundManagementService) CreateFundUsage(ctx context.Context, req *entiti
 		Operation:  "create_fund_usage",
 		EntityType: "fund_usage",
 		EntityID:   usage.ID,
-		NewValues:  fmt.Sprintf("Recorded fund usage of %f %s for %s", req.UsageAmount, req.Currency, req.UsageCategory),
+		NewValues:  fmt.Sprintf("Recorded fund usage of %s for %s", usageAmount, req.UsageCategory),
 	})
 
 	return usage, nil
@@ -288,12 +661,12 @@ func (s *fundManagementService) GetFundUsage(ctx context.Context, usageID uuid.U
 		BusinessID:       uuid.New(),
 		DisbursementID:   uuid.New(),
 		UsageCategory:    entities.FundUsageCategoryEquipment,
-		UsageAmount:      25000.0,
+		UsageAmount:      entities.MustParseMoney("25000", "IDR"),
 		Currency:         "IDR",
 		UsageDescription: "Purchased new manufacturing equipment",
 		UsageDate:        time.Now(),
-		RevenueGenerated: 50000.0,
-		CostSavings:      10000.0,
+		RevenueGenerated: entities.MustParseMoney("50000", "IDR"),
+		CostSavings:      entities.MustParseMoney("10000", "IDR"),
 		ROI:              200.0,
 		IsVerified:       true,
 		IsActive:         true,
@@ -312,11 +685,11 @@ func (s *fundManagementService) GetDisbursementUsage(ctx context.Context, disbur
 			BusinessID:       uuid.New(),
 			DisbursementID:   disbursementID,
 			UsageCategory:    entities.FundUsageCategoryEquipment,
-			UsageAmount:      25000.0,
+			UsageAmount:      entities.MustParseMoney("25000", "IDR"),
 			Currency:         "IDR",
 			UsageDescription: "Purchased new manufacturing equipment",
 			UsageDate:        time.Now(),
-			RevenueGenerated: 50000.0,
+			RevenueGenerated: entities.MustParseMoney("50000", "IDR"),
 			ROI:              200.0,
 			IsVerified:       true,
 			IsActive:         true,
@@ -340,16 +713,16 @@ func (s *fundManagementService) CalculateFundUsageROI(ctx context.Context, proje
 	return 150.0, nil
 }
 
-// GetCooperativeFundBalance implements FR-048: Get cooperative fund balance
-func (s *fundManagementService) GetCooperativeFundBalance(ctx context.Context, cooperativeID uuid.UUID) (float64, error) {
-	// Mock implementation - would get from escrow account
-	return 1000000.0, nil
+// GetCooperativeFundBalance implements FR-048: Get cooperative fund balance,
+// one balance per currency the cooperative holds escrow in
+func (s *fundManagementService) GetCooperativeFundBalance(ctx context.Context, cooperativeID uuid.UUID) ([]entities.Money, error) {
+	return s.ledgerService.GetEscrowBalances(ctx, cooperativeID)
 }
 
-// GetProjectFundBalance gets project fund balance
-func (s *fundManagementService) GetProjectFundBalance(ctx context.Context, projectID uuid.UUID) (float64, error) {
-	// Mock implementation - would calculate from investments and disbursements
-	return 250000.0, nil
+// GetProjectFundBalance gets project fund balance, one balance per currency
+func (s *fundManagementService) GetProjectFundBalance(ctx context.Context, projectID uuid.UUID) ([]entities.Money, error) {
+	// Investments, disbursements, refunds and distributions for the project are all posted against escrow
+	return s.ledgerService.GetProjectEscrowBalances(ctx, projectID)
 }
 
 // GetFundAuditTrail gets fund audit trail
@@ -377,23 +750,28 @@ func (s *fundManagementService) GetFundAuditTrail(ctx context.Context, projectID
 
 // CreateFundRefund implements FR-049: Fund refunds if project fails
 func (s *fundManagementService) CreateFundRefund(ctx context.Context, req *entities.CreateFundRefundRequest, initiatorID uuid.UUID) (*entities.FundRefund, error) {
-	// Validate refund request
-	if req.ProcessingFee < 0 {
-		return nil, errors.New("processing fee cannot be negative")
-	}
-
-	// Calculate refund amounts
+	// Calculate refund amounts; they are in the currency of the project's escrow
 	refundAmounts, err := s.CalculateRefundAmounts(ctx, req.ProjectID, req.RefundType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate refund amounts: %w", err)
 	}
 
-	totalRefundAmount := 0.0
+	var totalRefundAmount entities.Money
 	for _, amount := range refundAmounts {
-		totalRefundAmount += amount
+		totalRefundAmount = totalRefundAmount.Add(amount)
+	}
+	currency := totalRefundAmount.Currency()
+
+	// Validate refund request
+	processingFee, err := req.ProcessingFee.InCurrency(currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid processing fee: %w", err)
+	}
+	if processingFee.IsNegative() {
+		return nil, errors.New("processing fee cannot be negative")
 	}
 
-	netRefundAmount := totalRefundAmount - req.ProcessingFee
+	netRefundAmount := totalRefundAmount.Sub(processingFee)
 
 	// Create fund refund record
 	refund := &entities.FundRefund{
@@ -403,9 +781,9 @@ func (s *fundManagementService) CreateFundRefund(ctx context.Context, req *entit
 		RefundType:        req.RefundType,
 		RefundReason:      req.RefundReason,
 		TotalRefundAmount: totalRefundAmount,
-		Currency:          "IDR", // Will be set based on project currency
+		Currency:          currency,
 		RefundPercentage:  100.0, // Will be calculated based on refund type
-		ProcessingFee:     req.ProcessingFee,
+		ProcessingFee:     processingFee,
 		NetRefundAmount:   netRefundAmount,
 		Status:            entities.FundRefundStatusPending,
 		InitiatedBy:       initiatorID,
@@ -422,7 +800,7 @@ func (s *fundManagementService) CreateFundRefund(ctx context.Context, req *entit
 		Operation:  "create_fund_refund",
 		EntityType: "fund_refund",
 		EntityID:   refund.ID,
-		NewValues:  fmt.Sprintf("Created refund request for %f %s", totalRefundAmount, refund.Currency),
+		NewValues:  fmt.Sprintf("Created refund request for %s", totalRefundAmount),
 	})
 
 	return refund, nil
@@ -430,10 +808,59 @@ func (s *fundManagementService) CreateFundRefund(ctx context.Context, req *entit
 
 // ProcessFundRefund processes the refund
 func (s *fundManagementService) ProcessFundRefund(ctx context.Context, refundID, processorID uuid.UUID) error {
-	// Mock implementation - would:
-	// 1. Calculate individual investor refunds
-	// 2. Update refund status to processing
-	// 3. Initiate bank transfers
+	refund, err := s.GetFundRefund(ctx, refundID)
+	if err != nil {
+		return fmt.Errorf("failed to get refund: %w", err)
+	}
+
+	// Calculate individual investor refunds
+	refundAmounts, err := s.CalculateRefundAmounts(ctx, refund.ProjectID, refund.RefundType)
+	if err != nil {
+		return fmt.Errorf("failed to calculate refund amounts: %w", err)
+	}
+
+	investorIDs := make([]uuid.UUID, 0, len(refundAmounts))
+	for investorID := range refundAmounts {
+		investorIDs = append(investorIDs, investorID)
+	}
+	sort.Slice(investorIDs, func(i, j int) bool { return investorIDs[i].String() < investorIDs[j].String() })
+
+	investorRefunds := make([]*entities.InvestorRefund, 0, len(refundAmounts))
+	for _, investorID := range investorIDs {
+		amount := refundAmounts[investorID]
+		investorRefunds = append(investorRefunds, &entities.InvestorRefund{
+			ID:                 uuid.New(),
+			FundRefundID:       refund.ID,
+			InvestorID:         investorID,
+			OriginalInvestment: amount,
+			RefundAmount:       amount,
+			Status:             entities.FundRefundStatusProcessing,
+			IsActive:           true,
+			CreatedAt:          time.Now(),
+			UpdatedAt:          time.Now(),
+		})
+	}
+
+	// The processing fee is shared pro rata and must add up to what was charged
+	processingFees, err := allocateProcessingFees(refund.ProcessingFee, investorRefunds)
+	if err != nil {
+		return fmt.Errorf("failed to allocate processing fee: %w", err)
+	}
+	for i, ir := range investorRefunds {
+		ir.ProcessingFee = processingFees[i]
+		ir.NetRefundAmount = ir.RefundAmount.Sub(processingFees[i])
+	}
+
+	// Pay the refunds out of escrow in a single balanced entry
+	if _, err := s.ledgerService.RecordRefund(ctx, refund, investorRefunds, processorID); err != nil {
+		return fmt.Errorf("failed to record refund in ledger: %w", err)
+	}
+
+	if s.payoutService != nil {
+		if _, err := s.payoutService.QueueInvestorRefunds(ctx, refund, investorRefunds, processorID); err != nil {
+			return fmt.Errorf("failed to queue refund payouts: %w", err)
+		}
+	}
 
 	// Log audit trail
 	s.auditService.LogOperation(ctx, &LogOperationRequest{
@@ -472,11 +899,11 @@ func (s *fundManagementService) GetFundRefund(ctx context.Context, refundID uuid
 		CooperativeID:     uuid.New(),
 		RefundType:        entities.FundRefundTypeMinimumFundingFailed,
 		RefundReason:      "Project failed to meet minimum funding requirement",
-		TotalRefundAmount: 100000.0,
+		TotalRefundAmount: entities.MustParseMoney("100000", "IDR"),
 		Currency:          "IDR",
 		RefundPercentage:  100.0,
-		ProcessingFee:     1000.0,
-		NetRefundAmount:   99000.0,
+		ProcessingFee:     entities.MustParseMoney("1000", "IDR"),
+		NetRefundAmount:   entities.MustParseMoney("99000", "IDR"),
 		Status:            entities.FundRefundStatusProcessing,
 		InitiatedBy:       uuid.New(),
 		InitiatedAt:       time.Now(),
@@ -496,11 +923,11 @@ func (s *fundManagementService) GetProjectRefunds(ctx context.Context, projectID
 			CooperativeID:     uuid.New(),
 			RefundType:        entities.FundRefundTypeMinimumFundingFailed,
 			RefundReason:      "Project failed to meet minimum funding requirement",
-			TotalRefundAmount: 100000.0,
+			TotalRefundAmount: entities.MustParseMoney("100000", "IDR"),
 			Currency:          "IDR",
 			RefundPercentage:  100.0,
-			ProcessingFee:     1000.0,
-			NetRefundAmount:   99000.0,
+			ProcessingFee:     entities.MustParseMoney("1000", "IDR"),
+			NetRefundAmount:   entities.MustParseMoney("99000", "IDR"),
 			Status:            entities.FundRefundStatusProcessing,
 			InitiatedBy:       uuid.New(),
 			InitiatedAt:       time.Now(),
@@ -520,35 +947,77 @@ func (s *fundManagementService) SearchFundRefunds(ctx context.Context, filter *e
 }
 
 // CalculateRefundAmounts calculates refund amounts for each investor
-func (s *fundManagementService) CalculateRefundAmounts(ctx context.Context, projectID uuid.UUID, refundType string) (map[uuid.UUID]float64, error) {
-	// Mock implementation - would calculate based on:
-	// 1. Individual investment amounts
-	// 2. Refund type and percentage
-	// 3. Processing fees
+func (s *fundManagementService) CalculateRefundAmounts(ctx context.Context, projectID uuid.UUID, refundType string) (map[uuid.UUID]entities.Money, error) {
+	// Mock implementation - would load the project's confirmed investments and the
+	// balance still held in its escrow account
+	investments := []*entities.Investment{
+		{ID: uuid.New(), ProjectID: projectID, InvestorID: uuid.New(), Amount: entities.MustParseMoney("25000", "IDR"), Status: "confirmed"},
+		{ID: uuid.New(), ProjectID: projectID, InvestorID: uuid.New(), Amount: entities.MustParseMoney("30000", "IDR"), Status: "confirmed"},
+		{ID: uuid.New(), ProjectID: projectID, InvestorID: uuid.New(), Amount: entities.MustParseMoney("45000", "IDR"), Status: "confirmed"},
+	}
+	escrowBalance := entities.MustParseMoney("100000", "IDR")
 
-	refundAmounts := map[uuid.UUID]float64{
-		uuid.New(): 25000.0,
-		uuid.New(): 30000.0,
-		uuid.New(): 45000.0,
+	return allocateRefundAmounts(escrowBalance, investments)
+}
+
+// allocateRefundAmounts splits what is left in escrow across the investments in
+// proportion to their amounts, never refunding an investment more than was paid
+// in. The refunds add up to the refundable amount exactly.
+func allocateRefundAmounts(escrowBalance entities.Money, investments []*entities.Investment) (map[uuid.UUID]entities.Money, error) {
+	if len(investments) == 0 {
+		return nil, errors.New("no confirmed investments to refund")
+	}
+
+	holders := make([]entities.AllocationHolder, len(investments))
+	totalInvestment := entities.ZeroMoney(escrowBalance.Currency())
+	for i, investment := range investments {
+		if err := investment.Amount.CheckCurrency(escrowBalance.Currency()); err != nil {
+			return nil, fmt.Errorf("investment %s: %w", investment.ID, err)
+		}
+		amount := investment.Amount
+		holders[i] = entities.AllocationHolder{Weight: entities.AllocationWeight(amount), Cap: &amount}
+		totalInvestment = totalInvestment.Add(amount)
+	}
+
+	amounts, err := entities.Allocate(escrowBalance.Min(totalInvestment), holders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate refunds: %w", err)
+	}
+
+	refundAmounts := make(map[uuid.UUID]entities.Money, len(investments))
+	for i, investment := range investments {
+		refundAmounts[investment.InvestorID] = refundAmounts[investment.InvestorID].Add(amounts[i])
 	}
 
 	return refundAmounts, nil
 }
 
+// allocateProcessingFees splits a refund's processing fee across the investor
+// refunds in proportion to their size. No investor is charged more than their
+// refund and the shares add up to the fee exactly.
+func allocateProcessingFees(processingFee entities.Money, investorRefunds []*entities.InvestorRefund) ([]entities.Money, error) {
+	holders := make([]entities.AllocationHolder, len(investorRefunds))
+	for i, ir := range investorRefunds {
+		amount := ir.RefundAmount
+		holders[i] = entities.AllocationHolder{Weight: entities.AllocationWeight(amount), Cap: &amount}
+	}
+	return entities.Allocate(processingFee, holders)
+}
+
 // GetFundManagementSummary gets fund management summary
 func (s *fundManagementService) GetFundManagementSummary(ctx context.Context, cooperativeID uuid.UUID, startDate, endDate time.Time) (*entities.FundManagementSummary, error) {
 	// Mock implementation
 	return &entities.FundManagementSummary{
 		TotalDisbursements:   25,
-		TotalDisbursedAmount: 2500000.0,
+		TotalDisbursedAmount: entities.MustParseMoney("2500000", "IDR"),
 		PendingDisbursements: 5,
-		PendingAmount:        500000.0,
+		PendingAmount:        entities.MustParseMoney("500000", "IDR"),
 		TotalFundUsage:       20,
-		TotalUsageAmount:     2000000.0,
+		TotalUsageAmount:     entities.MustParseMoney("2000000", "IDR"),
 		TotalRefunds:         3,
-		TotalRefundAmount:    300000.0,
+		TotalRefundAmount:    entities.MustParseMoney("300000", "IDR"),
 		ProcessingRefunds:    1,
-		ProcessingAmount:     100000.0,
+		ProcessingAmount:     entities.MustParseMoney("100000", "IDR"),
 		Currency:             "IDR",
 	}, nil
 }
@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocateRefundAmounts_ShortfallSharedProRata(t *testing.T) {
+	repeatInvestor := uuid.New()
+	investments := []*entities.Investment{
+		{ID: uuid.New(), InvestorID: uuid.New(), Amount: idr("333.33")},
+		{ID: uuid.New(), InvestorID: repeatInvestor, Amount: idr("333.33")},
+		{ID: uuid.New(), InvestorID: repeatInvestor, Amount: idr("333.34")},
+	}
+
+	// Only 100.00 is left in escrow: each investor recovers the same fraction
+	refunds, err := allocateRefundAmounts(idr("100"), investments)
+
+	require.NoError(t, err)
+	assert.Len(t, refunds, 2)
+	assert.Equal(t, idr("33.33"), refunds[investments[0].InvestorID])
+	assert.Equal(t, idr("66.67"), refunds[repeatInvestor])
+}
+
+func TestAllocateRefundAmounts_NeverMoreThanInvested(t *testing.T) {
+	investments := []*entities.Investment{
+		{ID: uuid.New(), InvestorID: uuid.New(), Amount: idr("25000")},
+		{ID: uuid.New(), InvestorID: uuid.New(), Amount: idr("75000")},
+	}
+
+	refunds, err := allocateRefundAmounts(idr("120000"), investments)
+
+	require.NoError(t, err)
+	assert.Equal(t, idr("25000"), refunds[investments[0].InvestorID])
+	assert.Equal(t, idr("75000"), refunds[investments[1].InvestorID])
+}
+
+func TestAllocateProcessingFees_SumsToFee(t *testing.T) {
+	investorRefunds := []*entities.InvestorRefund{
+		{RefundAmount: idr("25000")},
+		{RefundAmount: idr("30000")},
+		{RefundAmount: idr("45000")},
+	}
+
+	// Float shares of 1000.01 would leave a sen unaccounted for
+	fees, err := allocateProcessingFees(idr("1000.01"), investorRefunds)
+
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Money{idr("250.00"), idr("300.00"), idr("450.01")}, fees)
+	assert.True(t, entities.SumMoney(fees...).Equal(idr("1000.01")))
+}
+
+func TestAllocateInvestorProfitShares(t *testing.T) {
+	distribution := &entities.ProfitDistributionExtended{
+		ID:                      uuid.New(),
+		TotalDistributionAmount: idr("1000"),
+		Currency:                "IDR",
+	}
+	investments := []*entities.Investment{
+		{ID: uuid.New(), InvestorID: uuid.New(), Amount: idr("10000")},
+		{ID: uuid.New(), InvestorID: uuid.New(), Amount: idr("10000")},
+		{ID: uuid.New(), InvestorID: uuid.New(), Amount: idr("10000")},
+	}
+
+	shares, err := allocateInvestorProfitShares(distribution, investments)
+
+	require.NoError(t, err)
+	require.Len(t, shares, 3)
+	total := entities.ZeroMoney("IDR")
+	for i, share := range shares {
+		assert.Equal(t, investments[i].ID, share.InvestmentID)
+		assert.InDelta(t, 33.33, share.InvestmentPercentage, 0.01)
+		total = total.Add(share.ProfitShareAmount)
+	}
+	assert.Equal(t, idr("333.34"), shares[0].ProfitShareAmount)
+	assert.Equal(t, idr("333.33"), shares[2].ProfitShareAmount)
+	assert.True(t, total.Equal(idr("1000")))
+}
+
+func TestAllocateInvestorProfitShares_RejectsOtherCurrency(t *testing.T) {
+	distribution := &entities.ProfitDistributionExtended{
+		ID:                      uuid.New(),
+		TotalDistributionAmount: idr("1000"),
+		Currency:                "IDR",
+	}
+	investments := []*entities.Investment{
+		{ID: uuid.New(), InvestorID: uuid.New(), Amount: idr("10000")},
+		{ID: uuid.New(), InvestorID: uuid.New(), Amount: entities.MustParseMoney("100", "MYR")},
+	}
+
+	_, err := allocateInvestorProfitShares(distribution, investments)
+
+	assert.ErrorIs(t, err, entities.ErrCurrencyMismatch)
+}
+
+func TestFundManagementService_DefineDisbursementTranches_BudgetsMustMatchFunding(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	disbursementRepo := new(MockDisbursementRepository)
+	fundService := NewFundManagementService(disbursementRepo, currencyService, mockAuditService, ledgerService, nil)
+	ctx := context.Background()
+
+	project := &entities.DisbursementProject{
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		BusinessID:    uuid.New(),
+		OwnerID:       uuid.New(),
+		Status:        entities.ProjectStatusFunded,
+		FundedAmount:  idr("100000"),
+	}
+	disbursementRepo.On("GetDisbursementProject", ctx, project.ProjectID).Return(project, nil)
+
+	req := &entities.DefineDisbursementTranchesRequest{Tranches: []entities.DisbursementTrancheInput{
+		{MilestoneID: uuid.New(), MilestoneTitle: "Site preparation", Budget: idr("40000")},
+		{MilestoneID: uuid.New(), MilestoneTitle: "Equipment installed", Budget: idr("50000")},
+	}}
+	_, err := fundService.DefineDisbursementTranches(ctx, project.ProjectID, req, uuid.New())
+	assert.ErrorContains(t, err, "tranche budgets total")
+	disbursementRepo.AssertNotCalled(t, "CreateTranches", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	req.Tranches[1].Budget = idr("60000")
+	disbursementRepo.On("CreateTranches", ctx, project.ProjectID, idr("100000"), mock.AnythingOfType("[]*entities.DisbursementTranche")).Return(nil)
+	tranches, err := fundService.DefineDisbursementTranches(ctx, project.ProjectID, req, uuid.New())
+	require.NoError(t, err)
+	require.Len(t, tranches, 2)
+	assert.Equal(t, 2, tranches[1].Sequence)
+	assert.Equal(t, entities.DisbursementTrancheStatusPlanned, tranches[1].Status)
+	assert.Equal(t, entities.MilestoneStatusPending, tranches[1].MilestoneStatus)
+}
+
+func TestFundManagementService_CreateFundDisbursement_RequiresVerifiedMilestone(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	disbursementRepo := new(MockDisbursementRepository)
+	fundService := NewFundManagementService(disbursementRepo, currencyService, mockAuditService, ledgerService, nil)
+	ctx := context.Background()
+
+	project := &entities.DisbursementProject{
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		BusinessID:    uuid.New(),
+		OwnerID:       uuid.New(),
+		Status:        entities.ProjectStatusFunded,
+		FundedAmount:  idr("100000"),
+	}
+	tranche := &entities.DisbursementTranche{
+		ID:              uuid.New(),
+		ProjectID:       project.ProjectID,
+		CooperativeID:   project.CooperativeID,
+		MilestoneID:     uuid.New(),
+		MilestoneTitle:  "Equipment installed",
+		MilestoneStatus: entities.MilestoneStatusCompleted,
+		Currency:        "IDR",
+		Budget:          idr("60000"),
+		ReleasedAmount:  idr("0"),
+		UnusedAmount:    idr("0"),
+		Status:          entities.DisbursementTrancheStatusPlanned,
+	}
+	disbursementRepo.On("GetDisbursementProject", ctx, project.ProjectID).Return(project, nil)
+	disbursementRepo.On("GetMilestoneTranche", ctx, project.ProjectID, tranche.MilestoneID).Return(tranche, nil)
+
+	req := &entities.CreateFundDisbursementRequest{
+		ProjectID:          project.ProjectID,
+		MilestoneID:        tranche.MilestoneID,
+		DisbursementAmount: idr("10000"),
+		Currency:           "IDR",
+		DisbursementType:   entities.FundDisbursementTypeMilestone,
+		DisbursementReason: "Pay equipment supplier",
+		BankAccount:        "1234567890",
+	}
+
+	_, err := fundService.CreateFundDisbursement(ctx, req, uuid.New())
+	assert.ErrorContains(t, err, "has not been verified")
+	disbursementRepo.AssertNotCalled(t, "CreateDisbursement", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFundManagementService_CreateFundDisbursement_WithinBudgetAndEscrow(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	disbursementRepo := new(MockDisbursementRepository)
+	fundService := NewFundManagementService(disbursementRepo, currencyService, mockAuditService, ledgerService, nil)
+	ctx := context.Background()
+
+	project := &entities.DisbursementProject{
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		BusinessID:    uuid.New(),
+		OwnerID:       uuid.New(),
+		Status:        entities.ProjectStatusFunded,
+		FundedAmount:  idr("100000"),
+	}
+	// The milestone has been completed and verified
+	verifiedAt := time.Now()
+	tranche := &entities.DisbursementTranche{
+		ID:              uuid.New(),
+		ProjectID:       project.ProjectID,
+		CooperativeID:   project.CooperativeID,
+		MilestoneID:     uuid.New(),
+		MilestoneTitle:  "Equipment installed",
+		MilestoneStatus: entities.MilestoneStatusCompleted,
+		Currency:        "IDR",
+		Budget:          idr("60000"),
+		ReleasedAmount:  idr("20000"),
+		UnusedAmount:    idr("0"),
+		Status:          entities.DisbursementTrancheStatusOpen,
+		VerifiedAt:      &verifiedAt,
+	}
+	disbursementRepo.On("GetDisbursementProject", ctx, project.ProjectID).Return(project, nil)
+	disbursementRepo.On("GetMilestoneTranche", ctx, project.ProjectID, tranche.MilestoneID).Return(tranche, nil)
+	// 80000 is held in escrow, of which 30000 already awaits release
+	ledgerRepo.On("GetProjectCategoryTotals", ctx, project.ProjectID, entities.LedgerAccountCategoryEscrow).Return(idrTotals("100000", "20000"), nil)
+	disbursementRepo.On("GetOutstandingAmount", ctx, project.ProjectID, "IDR").Return(idr("30000"), nil)
+	disbursementRepo.On("CreateDisbursement", ctx, mock.AnythingOfType("*entities.FundDisbursement"), mock.Anything).Return(nil)
+
+	req := &entities.CreateFundDisbursementRequest{
+		ProjectID:          project.ProjectID,
+		MilestoneID:        tranche.MilestoneID,
+		DisbursementAmount: idr("45000"),
+		Currency:           "IDR",
+		DisbursementType:   entities.FundDisbursementTypeMilestone,
+		DisbursementReason: "Pay equipment supplier",
+		BankAccount:        "1234567890",
+	}
+
+	// Only 40000 of the tranche is left
+	_, err := fundService.CreateFundDisbursement(ctx, req, uuid.New())
+	assert.ErrorContains(t, err, "of its budget left")
+
+	// The escrow can cover only 50000 more
+	tranche.Budget = idr("100000")
+	req.DisbursementAmount = idr("55000")
+	_, err = fundService.CreateFundDisbursement(ctx, req, uuid.New())
+	assert.ErrorContains(t, err, "insufficient project escrow balance")
+	disbursementRepo.AssertNotCalled(t, "CreateDisbursement", mock.Anything, mock.Anything, mock.Anything)
+
+	req.DisbursementAmount = idr("40000")
+	disbursement, err := fundService.CreateFundDisbursement(ctx, req, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, tranche.ID, disbursement.TrancheID)
+	assert.Equal(t, project.BusinessID, disbursement.BusinessID)
+	assert.Equal(t, project.CooperativeID, disbursement.CooperativeID)
+	assert.Equal(t, entities.FundDisbursementStatusPending, disbursement.Status)
+}
+
+func TestFundManagementService_VerifyMilestone_RejectionReturnsMilestoneToOwner(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	disbursementRepo := new(MockDisbursementRepository)
+	fundService := NewFundManagementService(disbursementRepo, currencyService, mockAuditService, ledgerService, nil)
+	ctx := context.Background()
+
+	project := &entities.DisbursementProject{
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		BusinessID:    uuid.New(),
+		OwnerID:       uuid.New(),
+		Status:        entities.ProjectStatusFunded,
+		FundedAmount:  idr("100000"),
+	}
+	tranche := &entities.DisbursementTranche{
+		ID:              uuid.New(),
+		ProjectID:       project.ProjectID,
+		CooperativeID:   project.CooperativeID,
+		MilestoneID:     uuid.New(),
+		MilestoneTitle:  "Equipment installed",
+		MilestoneStatus: entities.MilestoneStatusCompleted,
+		Currency:        "IDR",
+		Budget:          idr("60000"),
+		ReleasedAmount:  idr("0"),
+		UnusedAmount:    idr("0"),
+		Status:          entities.DisbursementTrancheStatusPlanned,
+	}
+	disbursementRepo.On("GetTranche", ctx, project.ProjectID, tranche.ID).Return(tranche, nil)
+	disbursementRepo.On("UpdateTrancheMilestone", ctx, tranche, entities.DisbursementTrancheStatusPlanned, entities.MilestoneStatusCompleted).Return(true, nil)
+
+	reviewed, err := fundService.VerifyMilestone(ctx, project.ProjectID, tranche.ID, &entities.VerifyMilestoneRequest{Approve: false, Notes: "Invoices are missing"}, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, entities.MilestoneStatusPending, reviewed.MilestoneStatus)
+	assert.Equal(t, entities.DisbursementTrancheStatusPlanned, reviewed.Status)
+	assert.Nil(t, reviewed.VerifiedAt)
+	assert.False(t, reviewed.Releasable())
+}
+
+func TestFundManagementService_ProcessFundDisbursement_ReleasesFromTranche(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	disbursementRepo := new(MockDisbursementRepository)
+	fundService := NewFundManagementService(disbursementRepo, currencyService, mockAuditService, ledgerService, nil)
+	ctx := context.Background()
+
+	project := &entities.DisbursementProject{
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		BusinessID:    uuid.New(),
+		OwnerID:       uuid.New(),
+		Status:        entities.ProjectStatusFunded,
+		FundedAmount:  idr("100000"),
+	}
+	verifiedAt := time.Now()
+	tranche := &entities.DisbursementTranche{
+		ID:              uuid.New(),
+		ProjectID:       project.ProjectID,
+		CooperativeID:   project.CooperativeID,
+		MilestoneID:     uuid.New(),
+		MilestoneTitle:  "Equipment installed",
+		MilestoneStatus: entities.MilestoneStatusCompleted,
+		Currency:        "IDR",
+		Budget:          idr("60000"),
+		ReleasedAmount:  idr("0"),
+		UnusedAmount:    idr("0"),
+		Status:          entities.DisbursementTrancheStatusOpen,
+		VerifiedAt:      &verifiedAt,
+	}
+	disbursement := &entities.FundDisbursement{
+		ID:                 uuid.New(),
+		ProjectID:          project.ProjectID,
+		BusinessID:         project.BusinessID,
+		CooperativeID:      project.CooperativeID,
+		TrancheID:          tranche.ID,
+		DisbursementAmount: idr("60000"),
+		Currency:           "IDR",
+		Status:             entities.FundDisbursementStatusApproved,
+	}
+	disbursementRepo.On("GetDisbursement", ctx, disbursement.ID).Return(disbursement, nil)
+	ledgerRepo.On("GetProjectCategoryTotals", ctx, project.ProjectID, entities.LedgerAccountCategoryEscrow).Return(idrTotals("100000", "0"), nil)
+	released := *tranche
+	released.ReleasedAmount = idr("60000")
+	released.Status = entities.DisbursementTrancheStatusReleased
+	disbursementRepo.On("MarkDisbursed", ctx, disbursement, mock.AnythingOfType("time.Time")).Return(&released, nil)
+	ledgerRepo.On("GetAccountByCode", ctx, project.CooperativeID, mock.AnythingOfType("string"), "IDR").Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}, nil)
+	var posted *entities.JournalEntry
+	ledgerRepo.On("PostFundedEntry", ctx, mock.AnythingOfType("*entities.JournalEntry"), mock.AnythingOfType("uuid.UUID")).Run(func(args mock.Arguments) {
+		posted = args.Get(1).(*entities.JournalEntry)
+	}).Return(nil)
+
+	err := fundService.ProcessFundDisbursement(ctx, disbursement.ID, uuid.New())
+	require.NoError(t, err)
+	require.NotNil(t, posted)
+	assert.Equal(t, idr("60000"), posted.TotalAmount)
+	assert.Equal(t, entities.FundDisbursementStatusDisbursed, disbursement.Status)
+}
@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
 
 	"github.com/google/uuid"
 )
@@ -17,7 +19,16 @@ type FundMonitoringService interface {
 	GetFundTransfer(ctx context.Context, transferID uuid.UUID) (*entities.FundTransfer, error)
 	GetFundTransfers(ctx context.Context, filter *entities.FundTransferFilter) ([]*entities.FundTransfer, int, error)
 	CancelFundTransfer(ctx context.Context, transferID, cancellerID uuid.UUID, reason string) error
+	// ProcessPendingTransfers executes a batch of pending transfers and failed
+	// ones due for a retry through the transfer provider, returning how many
+	// were attempted. Transient failures are retried with exponential backoff;
+	// transfers that fail permanently or exhaust their retries are dead-lettered.
 	ProcessPendingTransfers(ctx context.Context) (int, error)
+	ListTransferAttempts(ctx context.Context, transferID uuid.UUID) ([]*entities.FundTransferAttempt, error)
+	ListDeadLetteredTransfers(ctx context.Context, page, limit int) ([]*entities.FundTransfer, int, error)
+	// RequeueDeadLetteredTransfer returns a dead-lettered transfer to the queue
+	// for immediate execution with a fresh set of retries
+	RequeueDeadLetteredTransfer(ctx context.Context, transferID uuid.UUID, req *entities.RequeueFundTransferRequest, adminID uuid.UUID) (*entities.FundTransfer, error)
 	
 	// FR-021: Profit Distribution Monitoring
 	CreateProfitDistribution(ctx context.Context, req *entities.CreateProfitDistributionRequest, calculatorID uuid.UUID) (*entities.ProfitDistributionMonitoring, error)
@@ -31,19 +42,30 @@ type FundMonitoringService interface {
 	GetProjectFundingStatus(ctx context.Context, projectID uuid.UUID) (map[string]interface{}, error)
 	GenerateFinancialReport(ctx context.Context, cooperativeID uuid.UUID, period string) (map[string]interface{}, error)
 	DetectSuspiciousTransactions(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.FundTransfer, error)
-	
-	// Integration with external payment systems
-	SyncBankTransactions(ctx context.Context, cooperativeID uuid.UUID) (int, error)
-	ValidatePaymentReference(ctx context.Context, reference string) (bool, error)
 }
 
 type fundMonitoringService struct {
-	auditService AuditService
+	auditService     AuditService
+	currencyService  CurrencyService
+	amlService       AMLMonitoringService
+	transferRepo     repositories.FundTransferRepository
+	transferProvider TransferProvider
+	retryPolicy      TransferRetryPolicy
 }
 
-func NewFundMonitoringService(auditService AuditService) FundMonitoringService {
+// NewFundMonitoringService creates a new fund monitoring service. When an AML
+// monitoring service is given, every transfer is screened against its rules
+// and suspicious transactions are those flagged in cases still under review.
+// Without a transfer repository transfers are not saved and cannot be
+// processed; without a transfer provider they are saved but left pending.
+func NewFundMonitoringService(auditService AuditService, currencyService CurrencyService, amlService AMLMonitoringService, transferRepo repositories.FundTransferRepository, transferProvider TransferProvider, retryPolicy TransferRetryPolicy) FundMonitoringService {
 	return &fundMonitoringService{
-		auditService: auditService,
+		auditService:     auditService,
+		currencyService:  currencyService,
+		amlService:       amlService,
+		transferRepo:     transferRepo,
+		transferProvider: transferProvider,
+		retryPolicy:      retryPolicy,
 	}
 }
 
@@ -57,47 +79,84 @@ func (s *fundMonitoringService) CreateFundTransfer(ctx context.Context, req *ent
 	transferNumber := s.generateTransferNumber()
 
 	// Calculate fees and net amount
-	fee := s.calculateTransferFee(req.Amount, req.TransferType, req.PaymentMethod)
-	netAmount := req.Amount - fee
+	amount, err := req.Amount.InCurrency(req.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transfer amount: %w", err)
+	}
+	fee := s.calculateTransferFee(amount, req.TransferType, req.PaymentMethod)
+	netAmount := amount.Sub(fee)
+
+	// Cross-currency transfers settle at the rate effective now
+	settlementCurrency := req.Currency
+	if req.SettlementCurrency != "" {
+		settlementCurrency = req.SettlementCurrency
+	}
+	settlement, err := s.currencyService.Convert(ctx, netAmount, settlementCurrency, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert transfer amount: %w", err)
+	}
+
+	cooperativeID := uuid.New() // Would get from project
+	if s.transferRepo != nil {
+		cooperativeID, err = s.transferRepo.GetProjectCooperativeID(ctx, req.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project cooperative: %w", err)
+		}
+	}
+
+	// Transfers wait in the queue until they are due, then the transfer
+	// worker executes them
+	now := time.Now()
+	nextAttemptAt := now
+	if req.ScheduledAt != nil && req.ScheduledAt.After(now) {
+		nextAttemptAt = *req.ScheduledAt
+	}
 
 	transfer := &entities.FundTransfer{
-		ID:                uuid.New(),
-		TransferNumber:    transferNumber,
-		ProjectID:         req.ProjectID,
-		InvestmentID:      req.InvestmentID,
-		CooperativeID:     uuid.New(), // Would get from project
-		FromAccountID:     req.FromAccountID,
-		ToAccountID:       req.ToAccountID,
-		FromUserID:        req.FromUserID,
-		ToUserID:          req.ToUserID,
-		TransferType:      req.TransferType,
-		Amount:            req.Amount,
-		Currency:          req.Currency,
-		ExchangeRate:      1.0, // Default for same currency
-		Fee:               fee,
-		NetAmount:         netAmount,
-		Status:            entities.TransferStatusPending,
-		PaymentMethod:     req.PaymentMethod,
-		PaymentReference:  req.PaymentReference,
-		Description:       req.Description,
-		Notes:             req.Notes,
-		ScheduledAt:       req.ScheduledAt,
-		Metadata:          req.Metadata,
-		MaxRetries:        3,
-		InitiatedBy:       initiatorID,
-		CreatedAt:         time.Now(),
-		UpdatedAt:         time.Now(),
-	}
-
-	// If scheduled for future, keep as pending; otherwise process immediately
-	if req.ScheduledAt == nil || req.ScheduledAt.Before(time.Now()) {
-		transfer.Status = entities.TransferStatusProcessing
-		now := time.Now()
-		transfer.ProcessedAt = &now
+		ID:                 uuid.New(),
+		TransferNumber:     transferNumber,
+		ProjectID:          req.ProjectID,
+		InvestmentID:       req.InvestmentID,
+		CooperativeID:      cooperativeID,
+		FromAccountID:      req.FromAccountID,
+		ToAccountID:        req.ToAccountID,
+		FromUserID:         req.FromUserID,
+		ToUserID:           req.ToUserID,
+		TransferType:       req.TransferType,
+		Amount:             amount,
+		Currency:           req.Currency,
+		ExchangeRate:       settlement.ExchangeRate,
+		SettlementCurrency: settlementCurrency,
+		SettlementAmount:   settlement.Converted,
+		Fee:                fee,
+		NetAmount:          netAmount,
+		Status:             entities.TransferStatusPending,
+		PaymentMethod:      req.PaymentMethod,
+		PaymentReference:   req.PaymentReference,
+		BankAccount:        req.BankAccount,
+		Description:        req.Description,
+		Notes:              req.Notes,
+		ScheduledAt:        req.ScheduledAt,
+		Metadata:           req.Metadata,
+		MaxRetries:         s.retryPolicy.MaxRetries,
+		NextAttemptAt:      &nextAttemptAt,
+		InitiatedBy:        initiatorID,
+		CreatedAt:          now,
+		UpdatedAt:          now,
 	}
 
-	// In real implementation, save to repository
-	// createdTransfer, err := s.transferRepo.Create(ctx, transfer)
+	if s.transferRepo != nil {
+		if err := s.transferRepo.CreateTransfer(ctx, transfer); err != nil {
+			return nil, fmt.Errorf("failed to create fund transfer: %w", err)
+		}
+	}
+
+	// Alerts open a case for compliance review without holding the transfer
+	if s.amlService != nil {
+		if _, err := s.amlService.MonitorTransfer(ctx, transfer); err != nil {
+			return nil, fmt.Errorf("failed to monitor transfer: %w", err)
+		}
+	}
 
 	// Log audit trail
 	s.auditService.LogOperation(ctx, &LogOperationRequest{
@@ -105,7 +164,7 @@ func (s *fundMonitoringService) CreateFundTransfer(ctx context.Context, req *ent
 		EntityID:   transfer.ID,
 		Operation:  entities.AuditOperationCreate,
 		UserID:     initiatorID,
-		Changes:    map[string]interface{}{"transfer_type": req.TransferType, "amount": req.Amount},
+		Changes:    map[string]interface{}{"transfer_type": req.TransferType, "amount": amount},
 		NewValues:  transfer,
 		Status:     entities.AuditStatusSuccess,
 	})
@@ -120,14 +179,15 @@ func (s *fundMonitoringService) CreateProfitDistribution(ctx context.Context, re
 	}
 
 	// Calculate profit distribution based on rules
-	netProfit := req.TotalRevenue - req.TotalExpenses
+	netProfit := req.TotalRevenue.Sub(req.TotalExpenses)
 	distributableProfit := netProfit // Could apply adjustments
 
 	// Get profit sharing rules (mock calculation)
-	investorShare := distributableProfit * 0.6    // 60%
-	cooperativeShare := distributableProfit * 0.2 // 20%
-	businessOwnerShare := distributableProfit * 0.15 // 15%
-	adminFee := distributableProfit * 0.05        // 5%
+	investorShare := distributableProfit.Percent(60, entities.RoundHalfUp)      // 60%
+	cooperativeShare := distributableProfit.Percent(20, entities.RoundHalfUp)   // 20%
+	businessOwnerShare := distributableProfit.Percent(15, entities.RoundHalfUp) // 15%
+	// Admin fee (5%) takes the remainder so the shares always add up exactly
+	adminFee := distributableProfit.Sub(investorShare).Sub(cooperativeShare).Sub(businessOwnerShare)
 
 	distributionNumber := s.generateDistributionNumber()
 
@@ -146,7 +206,7 @@ func (s *fundMonitoringService) CreateProfitDistribution(ctx context.Context, re
 		CooperativeShare:       cooperativeShare,
 		BusinessOwnerShare:     businessOwnerShare,
 		AdminFee:               adminFee,
-		TotalDistributed:       0, // Will be updated when distributed
+		TotalDistributed:       entities.ZeroMoney(distributableProfit.Currency()), // Will be updated when distributed
 		PendingDistribution:    distributableProfit,
 		Status:                 entities.DistributionStatusCalculated,
 		CalculationMethod:      req.CalculationMethod,
@@ -179,76 +239,323 @@ func (s *fundMonitoringService) CreateProfitDistribution(ctx context.Context, re
 }
 
 func (s *fundMonitoringService) GetCooperativeFundSummary(ctx context.Context, cooperativeID uuid.UUID, startDate, endDate time.Time) (map[string]interface{}, error) {
-	// Mock fund summary calculation
+	baseCurrency := s.currencyService.BaseCurrency()
+
+	// Mock per-currency totals for the period
+	// In real implementation, aggregate transfers and distributions grouped by currency
+	currencyTotals := map[string]map[string]entities.Money{
+		"IDR": mockFundTotals("IDR", "150000", "120000", "100000", "15000", "5000", "2500", "1800", "35700", "12000"),
+		"MYR": mockFundTotals("MYR", "42000", "35500", "30000", "4200", "1500", "700", "500", "9600", "3000"),
+	}
+
+	// Convert every currency's totals to the base currency at the rates effective at period end
+	baseTotals := make(map[string]entities.Money)
+	byCurrency := make(map[string]interface{})
+	for currency, totals := range currencyTotals {
+		converted := make(map[string]entities.Money, len(totals))
+		var rate *entities.ConvertedAmount
+		for key, amount := range totals {
+			result, err := s.currencyService.ConvertToBase(ctx, amount, endDate)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert %s fund totals: %w", currency, err)
+			}
+			converted[key] = result.Converted
+			baseTotals[key] = baseTotals[key].Add(result.Converted)
+			rate = result
+		}
+
+		breakdown := map[string]interface{}{
+			"original":      totals,
+			"converted":     converted,
+			"exchange_rate": rate.ExchangeRate,
+		}
+		if rate.RateEffectiveAt != nil {
+			breakdown["rate_effective_at"] = *rate.RateEffectiveAt
+		}
+		byCurrency[currency] = breakdown
+	}
+
 	summary := map[string]interface{}{
 		"cooperative_id": cooperativeID,
+		"base_currency":  baseCurrency,
 		"period": map[string]interface{}{
 			"start": startDate,
 			"end":   endDate,
 		},
 		"transfers": map[string]interface{}{
-			"total_inbound":  150000.0,
-			"total_outbound": 120000.0,
-			"net_flow":       30000.0,
+			"total_inbound":  baseTotals["total_inbound"],
+			"total_outbound": baseTotals["total_outbound"],
+			"net_flow":       baseTotals["total_inbound"].Sub(baseTotals["total_outbound"]),
 			"count_inbound":  45,
 			"count_outbound": 38,
 		},
 		"investments": map[string]interface{}{
-			"total_invested":    100000.0,
-			"active_projects":   12,
-			"pending_projects":  5,
+			"total_invested":     baseTotals["total_invested"],
+			"active_projects":    12,
+			"pending_projects":   5,
 			"completed_projects": 3,
 		},
 		"distributions": map[string]interface{}{
-			"total_distributed": 15000.0,
-			"pending_distribution": 5000.0,
-			"distribution_cycles": 2,
+			"total_distributed":    baseTotals["total_distributed"],
+			"pending_distribution": baseTotals["pending_distribution"],
+			"distribution_cycles":  2,
 		},
 		"fees": map[string]interface{}{
-			"transaction_fees": 2500.0,
-			"admin_fees":      1800.0,
-			"total_fees":      4300.0,
+			"transaction_fees": baseTotals["transaction_fees"],
+			"admin_fees":       baseTotals["admin_fees"],
+			"total_fees":       baseTotals["transaction_fees"].Add(baseTotals["admin_fees"]),
 		},
 		"balance": map[string]interface{}{
-			"available_balance": 35700.0,
-			"reserved_balance":  12000.0,
-			"total_balance":    47700.0,
+			"available_balance": baseTotals["available_balance"],
+			"reserved_balance":  baseTotals["reserved_balance"],
+			"total_balance":     baseTotals["available_balance"].Add(baseTotals["reserved_balance"]),
 		},
+		"by_currency": byCurrency,
 	}
 
 	return summary, nil
 }
 
-func (s *fundMonitoringService) DetectSuspiciousTransactions(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.FundTransfer, error) {
-	// Mock suspicious transaction detection
-	// In real implementation, apply rules like:
-	// - Unusually large amounts
-	// - Rapid succession of transfers
-	// - Transfers to/from blacklisted accounts
-	// - Patterns indicating money laundering
-	
-	return []*entities.FundTransfer{}, nil
+// mockFundTotals builds the mock fund totals of one currency
+func mockFundTotals(currency, inbound, outbound, invested, distributed, pendingDistribution, transactionFees, adminFees, available, reserved string) map[string]entities.Money {
+	return map[string]entities.Money{
+		"total_inbound":        entities.MustParseMoney(inbound, currency),
+		"total_outbound":       entities.MustParseMoney(outbound, currency),
+		"total_invested":       entities.MustParseMoney(invested, currency),
+		"total_distributed":    entities.MustParseMoney(distributed, currency),
+		"pending_distribution": entities.MustParseMoney(pendingDistribution, currency),
+		"transaction_fees":     entities.MustParseMoney(transactionFees, currency),
+		"admin_fees":           entities.MustParseMoney(adminFees, currency),
+		"available_balance":    entities.MustParseMoney(available, currency),
+		"reserved_balance":     entities.MustParseMoney(reserved, currency),
+	}
 }
 
-// Mock implementations for interface compliance
-func (s *fundMonitoringService) UpdateFundTransfer(ctx context.Context, transferID uuid.UUID, req *entities.UpdateFundTransferRequest, updaterID uuid.UUID) (*entities.FundTransfer, error) {
-	return nil, fmt.Errorf("not implemented - requires repository")
-}
+// DetectSuspiciousTransactions returns the transfers and investments AML
+// monitoring flagged in cases that are not yet closed
+func (s *fundMonitoringService) DetectSuspiciousTransactions(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.FundTransfer, error) {
+	if s.amlService == nil {
+		return []*entities.FundTransfer{}, nil
+	}
 
-func (s *fundMonitoringService) GetFundTransfer(ctx context.Context, transferID uuid.UUID) (*entities.FundTransfer, error) {
-	return nil, fmt.Errorf("not implemented - requires repository")
+	transactions, err := s.amlService.ListFlaggedTransactions(ctx, cooperativeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flagged transactions: %w", err)
+	}
+
+	transfers := make([]*entities.FundTransfer, 0, len(transactions))
+	for _, t := range transactions {
+		subjectID := t.SubjectID
+		transfer := &entities.FundTransfer{
+			ID:             t.SourceID,
+			TransferNumber: t.Reference,
+			ProjectID:      t.ProjectID,
+			CooperativeID:  t.CooperativeID,
+			TransferType:   t.TransactionType,
+			Amount:         t.Amount,
+			Currency:       t.Currency,
+			BankAccount:    t.BankAccount,
+			CreatedAt:      t.OccurredAt,
+		}
+		if t.SourceType == entities.AMLSourceInvestment {
+			investmentID := t.SourceID
+			transfer.InvestmentID = &investmentID
+		}
+		if t.Direction == entities.AMLDirectionInbound {
+			transfer.FromUserID = &subjectID
+		} else {
+			transfer.ToUserID = &subjectID
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, nil
 }
 
-func (s *fundMonitoringService) GetFundTransfers(ctx context.Context, filter *entities.FundTransferFilter) ([]*entities.FundTransfer, int, error) {
-	return []*entities.FundTransfer{}, 0, nil
+func (s *fundMonitoringService) GetFundTransfer(ctx context.Context, transferID uuid.UUID) (*entities.FundTransfer, error) {
+	if s.transferRepo == nil {
+		return nil, fmt.Errorf("fund transfers are not stored")
+	}
+	return s.transferRepo.GetTransfer(ctx, transferID)
 }
 
 func (s *fundMonitoringService) CancelFundTransfer(ctx context.Context, transferID, cancellerID uuid.UUID, reason string) error {
-	return fmt.Errorf("not implemented - requires repository")
+	transfer, err := s.GetFundTransfer(ctx, transferID)
+	if err != nil {
+		return err
+	}
+	switch transfer.Status {
+	case entities.TransferStatusPending, entities.TransferStatusFailed, entities.TransferStatusDeadLettered:
+	default:
+		return fmt.Errorf("cannot cancel a %s transfer", transfer.Status)
+	}
+
+	previousStatus := transfer.Status
+	now := time.Now()
+	transfer.Status = entities.TransferStatusCancelled
+	transfer.CancelledBy = &cancellerID
+	transfer.CancelledAt = &now
+	transfer.CancellationReason = reason
+	transfer.NextAttemptAt = nil
+
+	if err := s.transferRepo.CancelTransfer(ctx, transfer); err != nil {
+		return fmt.Errorf("failed to cancel fund transfer: %w", err)
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		EntityType: "fund_transfer",
+		EntityID:   transfer.ID,
+		Operation:  "cancel_transfer",
+		UserID:     cancellerID,
+		OldValues:  map[string]interface{}{"status": previousStatus},
+		NewValues:  map[string]interface{}{"status": transfer.Status, "reason": reason},
+		Status:     entities.AuditStatusSuccess,
+	})
+
+	return nil
 }
 
 func (s *fundMonitoringService) ProcessPendingTransfers(ctx context.Context) (int, error) {
-	return 0, fmt.Errorf("not implemented - requires repository and payment gateway")
+	if s.transferRepo == nil {
+		return 0, fmt.Errorf("fund transfers are not stored")
+	}
+	if s.transferProvider == nil {
+		return 0, fmt.Errorf("no transfer provider configured")
+	}
+
+	processed, err := s.transferRepo.ProcessDueTransfers(ctx, time.Now(), s.retryPolicy.BatchSize, func(transfer *entities.FundTransfer) *entities.FundTransferAttempt {
+		return s.executeTransfer(ctx, transfer)
+	})
+
+	// Only transfers whose attempt was committed are dead-lettered
+	for _, transfer := range processed {
+		if transfer.Status != entities.TransferStatusDeadLettered {
+			continue
+		}
+		s.auditService.LogOperation(ctx, &LogOperationRequest{
+			EntityType: "fund_transfer",
+			EntityID:   transfer.ID,
+			Operation:  "dead_letter_transfer",
+			UserID:     uuid.Nil,
+			NewValues:  map[string]interface{}{"retry_count": transfer.RetryCount, "failure_reason": transfer.FailureReason},
+			Status:     entities.AuditStatusSuccess,
+		})
+	}
+
+	if err != nil {
+		return len(processed), fmt.Errorf("failed to process pending transfers: %w", err)
+	}
+	return len(processed), nil
+}
+
+// executeTransfer sends a transfer through the provider and updates it with
+// the outcome, returning the attempt to record
+func (s *fundMonitoringService) executeTransfer(ctx context.Context, transfer *entities.FundTransfer) *entities.FundTransferAttempt {
+	startedAt := time.Now()
+	reference, err := s.transferProvider.ExecuteTransfer(ctx, transfer)
+	finishedAt := time.Now()
+
+	attempt := &entities.FundTransferAttempt{
+		ID:         uuid.New(),
+		TransferID: transfer.ID,
+		Provider:   s.transferProvider.Name(),
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+		CreatedAt:  finishedAt,
+	}
+	if transfer.ProcessedAt == nil {
+		transfer.ProcessedAt = &startedAt
+	}
+
+	if err == nil {
+		attempt.Outcome = entities.TransferAttemptSucceeded
+		attempt.ProviderReference = reference
+		transfer.Status = entities.TransferStatusCompleted
+		transfer.BankTransactionID = reference
+		transfer.CompletedAt = &finishedAt
+		transfer.NextAttemptAt = nil
+		return attempt
+	}
+
+	attempt.ErrorMessage = err.Error()
+	transfer.FailedAt = &finishedAt
+	transfer.FailureReason = err.Error()
+
+	var permanent *PermanentTransferError
+	if errors.As(err, &permanent) {
+		attempt.Outcome = entities.TransferAttemptPermanentFailure
+	} else {
+		attempt.Outcome = entities.TransferAttemptTransientFailure
+		if transfer.RetryCount < transfer.MaxRetries {
+			transfer.RetryCount++
+			transfer.Status = entities.TransferStatusFailed
+			nextAttemptAt := finishedAt.Add(s.retryPolicy.Backoff(transfer.RetryCount))
+			transfer.NextAttemptAt = &nextAttemptAt
+			return attempt
+		}
+	}
+
+	transfer.Status = entities.TransferStatusDeadLettered
+	transfer.DeadLetteredAt = &finishedAt
+	transfer.NextAttemptAt = nil
+	return attempt
+}
+
+func (s *fundMonitoringService) ListTransferAttempts(ctx context.Context, transferID uuid.UUID) ([]*entities.FundTransferAttempt, error) {
+	transfer, err := s.GetFundTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transferRepo.ListAttempts(ctx, transfer)
+}
+
+func (s *fundMonitoringService) ListDeadLetteredTransfers(ctx context.Context, page, limit int) ([]*entities.FundTransfer, int, error) {
+	if s.transferRepo == nil {
+		return nil, 0, fmt.Errorf("fund transfers are not stored")
+	}
+	return s.transferRepo.ListDeadLettered(ctx, limit, (page-1)*limit)
+}
+
+func (s *fundMonitoringService) RequeueDeadLetteredTransfer(ctx context.Context, transferID uuid.UUID, req *entities.RequeueFundTransferRequest, adminID uuid.UUID) (*entities.FundTransfer, error) {
+	transfer, err := s.GetFundTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.Status != entities.TransferStatusDeadLettered {
+		return nil, fmt.Errorf("only dead-lettered transfers can be requeued, transfer is %s", transfer.Status)
+	}
+
+	previousRetries := transfer.RetryCount
+	now := time.Now()
+	transfer.Status = entities.TransferStatusPending
+	transfer.RetryCount = 0
+	transfer.NextAttemptAt = &now
+	transfer.DeadLetteredAt = nil
+
+	if err := s.transferRepo.RequeueTransfer(ctx, transfer); err != nil {
+		return nil, fmt.Errorf("failed to requeue fund transfer: %w", err)
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		EntityType: "fund_transfer",
+		EntityID:   transfer.ID,
+		Operation:  "requeue_transfer",
+		UserID:     adminID,
+		OldValues:  map[string]interface{}{"status": entities.TransferStatusDeadLettered, "retry_count": previousRetries},
+		NewValues:  map[string]interface{}{"status": transfer.Status, "notes": req.Notes},
+		Status:     entities.AuditStatusSuccess,
+	})
+
+	return transfer, nil
+}
+
+// Mock implementations for interface compliance
+func (s *fundMonitoringService) UpdateFundTransfer(ctx context.Context, transferID uuid.UUID, req *entities.UpdateFundTransferRequest, updaterID uuid.UUID) (*entities.FundTransfer, error) {
+	return nil, fmt.Errorf("not implemented - requires repository")
+}
+
+func (s *fundMonitoringService) GetFundTransfers(ctx context.Context, filter *entities.FundTransferFilter) ([]*entities.FundTransfer, int, error) {
+	return []*entities.FundTransfer{}, 0, nil
 }
 
 func (s *fundMonitoringService) ApproveProfitDistribution(ctx context.Context, distributionID, approverID uuid.UUID) error {
@@ -275,17 +582,9 @@ func (s *fundMonitoringService) GenerateFinancialReport(ctx context.Context, coo
 	return map[string]interface{}{}, nil
 }
 
-func (s *fundMonitoringService) SyncBankTransactions(ctx context.Context, cooperativeID uuid.UUID) (int, error) {
-	return 0, fmt.Errorf("not implemented - requires bank API integration")
-}
-
-func (s *fundMonitoringService) ValidatePaymentReference(ctx context.Context, reference string) (bool, error) {
-	return len(reference) > 5, nil
-}
-
 // Helper methods
 func (s *fundMonitoringService) validateTransferRequest(req *entities.CreateFundTransferRequest) error {
-	if req.Amount <= 0 {
+	if !req.Amount.IsPositive() {
 		return fmt.Errorf("transfer amount must be positive")
 	}
 
@@ -301,11 +600,11 @@ func (s *fundMonitoringService) validateTransferRequest(req *entities.CreateFund
 }
 
 func (s *fundMonitoringService) validateProfitDistributionRequest(req *entities.CreateProfitDistributionRequest) error {
-	if req.TotalRevenue < 0 {
+	if req.TotalRevenue.IsNegative() {
 		return fmt.Errorf("total revenue cannot be negative")
 	}
 
-	if req.TotalExpenses < 0 {
+	if req.TotalExpenses.IsNegative() {
 		return fmt.Errorf("total expenses cannot be negative")
 	}
 
@@ -328,7 +627,7 @@ func (s *fundMonitoringService) generateDistributionNumber() string {
 	return fmt.Sprintf("DIST-%s-%s", timestamp, uuid.New().String()[:8])
 }
 
-func (s *fundMonitoringService) calculateTransferFee(amount float64, transferType, paymentMethod string) float64 {
+func (s *fundMonitoringService) calculateTransferFee(amount entities.Money, transferType, paymentMethod string) entities.Money {
 	// Mock fee calculation
 	baseFee := 0.0
 	percentageFee := 0.0
@@ -354,5 +653,5 @@ func (s *fundMonitoringService) calculateTransferFee(amount float64, transferTyp
 		baseFee += 0.0
 	}
 
-	return baseFee + (amount * percentageFee)
+	return entities.NewMoneyFromFloat(baseFee, amount.Currency()).Add(amount.Mul(percentageFee, entities.RoundHalfUp))
 }
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// TransferRetryPolicy governs how the transfer worker retries fund transfers
+// that fail transiently
+type TransferRetryPolicy struct {
+	// MaxRetries is how many times a new transfer is retried before it is
+	// dead-lettered
+	MaxRetries int
+	// BaseDelay is the wait before the first retry; each further retry waits
+	// twice as long as the one before, up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// BatchSize is the most transfers executed in one run of the worker
+	BatchSize int
+}
+
+// DefaultTransferRetryPolicy retries a transfer three times, one, two and four
+// minutes apart
+var DefaultTransferRetryPolicy = TransferRetryPolicy{MaxRetries: 3, BaseDelay: time.Minute, MaxDelay: time.Hour, BatchSize: 50}
+
+// NewTransferRetryPolicy parses the retry limit, the base and maximum backoff
+// delays as Go durations and the batch size
+func NewTransferRetryPolicy(maxRetries, baseDelay, maxDelay, batchSize string) (TransferRetryPolicy, error) {
+	retries, err := strconv.Atoi(maxRetries)
+	if err != nil || retries < 0 {
+		return TransferRetryPolicy{}, fmt.Errorf("transfer max retries must be a number of retries: %q", maxRetries)
+	}
+
+	base, err := time.ParseDuration(baseDelay)
+	if err != nil || base <= 0 {
+		return TransferRetryPolicy{}, fmt.Errorf("transfer retry base delay must be a positive duration: %q", baseDelay)
+	}
+
+	ceiling, err := time.ParseDuration(maxDelay)
+	if err != nil || ceiling < base {
+		return TransferRetryPolicy{}, fmt.Errorf("transfer retry max delay must be a duration of at least the base delay: %q", maxDelay)
+	}
+
+	batch, err := strconv.Atoi(batchSize)
+	if err != nil || batch <= 0 {
+		return TransferRetryPolicy{}, fmt.Errorf("transfer batch size must be a positive number: %q", batchSize)
+	}
+
+	return TransferRetryPolicy{MaxRetries: retries, BaseDelay: base, MaxDelay: ceiling, BatchSize: batch}, nil
+}
+
+// Backoff returns the wait before the given retry, counting from 1
+func (p TransferRetryPolicy) Backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	if delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// TransferRetryScheduler periodically runs the transfer worker
+type TransferRetryScheduler struct {
+	*intervalScheduler
+	fundMonitoringService FundMonitoringService
+}
+
+// NewTransferRetryScheduler creates a scheduler that runs every interval
+func NewTransferRetryScheduler(fundMonitoringService FundMonitoringService, interval time.Duration) *TransferRetryScheduler {
+	s := &TransferRetryScheduler{fundMonitoringService: fundMonitoringService}
+	s.intervalScheduler = newIntervalScheduler("Fund transfer", interval, s.runOnce)
+	return s
+}
+
+func (s *TransferRetryScheduler) runOnce(ctx context.Context) error {
+	processed, err := s.fundMonitoringService.ProcessPendingTransfers(ctx)
+	if processed > 0 {
+		log.Printf("Attempted %d fund transfers", processed)
+	}
+	return err
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedTransferProvider fails each transfer with the error scripted for its
+// transfer number and succeeds otherwise
+type scriptedTransferProvider struct {
+	failures map[string]error
+}
+
+func (p *scriptedTransferProvider) Name() string {
+	return "scripted"
+}
+
+func (p *scriptedTransferProvider) ExecuteTransfer(ctx context.Context, transfer *entities.FundTransfer) (string, error) {
+	if err := p.failures[transfer.TransferNumber]; err != nil {
+		return "", err
+	}
+	return "TRF-" + transfer.TransferNumber, nil
+}
+
+func TestTransferRetryPolicy_Backoff(t *testing.T) {
+	policy := TransferRetryPolicy{BaseDelay: time.Minute, MaxDelay: 10 * time.Minute}
+
+	assert.Equal(t, time.Minute, policy.Backoff(1))
+	assert.Equal(t, 2*time.Minute, policy.Backoff(2))
+	assert.Equal(t, 4*time.Minute, policy.Backoff(3))
+	assert.Equal(t, 8*time.Minute, policy.Backoff(4))
+	assert.Equal(t, 10*time.Minute, policy.Backoff(5))
+	assert.Equal(t, 10*time.Minute, policy.Backoff(100))
+}
+
+func TestNewTransferRetryPolicy(t *testing.T) {
+	policy, err := NewTransferRetryPolicy("5", "30s", "15m", "20")
+	require.NoError(t, err)
+	assert.Equal(t, TransferRetryPolicy{MaxRetries: 5, BaseDelay: 30 * time.Second, MaxDelay: 15 * time.Minute, BatchSize: 20}, policy)
+
+	_, err = NewTransferRetryPolicy("-1", "30s", "15m", "20")
+	assert.Error(t, err)
+	_, err = NewTransferRetryPolicy("3", "0s", "15m", "20")
+	assert.Error(t, err)
+	_, err = NewTransferRetryPolicy("3", "1h", "15m", "20")
+	assert.Error(t, err)
+	_, err = NewTransferRetryPolicy("3", "30s", "15m", "0")
+	assert.Error(t, err)
+}
+
+func TestFundMonitoringService_CreateFundTransfer_QueuesTransfer(t *testing.T) {
+	mockRepo := new(MockFundTransferRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	service := NewFundMonitoringService(mockAuditService, currencyService, nil, mockRepo, nil, DefaultTransferRetryPolicy)
+	ctx := context.Background()
+
+	projectID, cooperativeID := uuid.New(), uuid.New()
+	mockRepo.On("GetProjectCooperativeID", ctx, projectID).Return(cooperativeID, nil)
+	mockRepo.On("CreateTransfer", ctx, mock.AnythingOfType("*entities.FundTransfer")).Return(nil)
+
+	scheduledAt := time.Now().Add(24 * time.Hour)
+	transfer, err := service.CreateFundTransfer(ctx, &entities.CreateFundTransferRequest{
+		ProjectID:     projectID,
+		FromAccountID: uuid.New(),
+		ToAccountID:   uuid.New(),
+		TransferType:  entities.TransferTypeWithdrawal,
+		Amount:        idr("500000"),
+		Currency:      "IDR",
+		PaymentMethod: entities.PaymentMethodBankTransfer,
+		BankAccount:   "1234567890",
+		Description:   "Member withdrawal",
+		ScheduledAt:   &scheduledAt,
+	}, uuid.New())
+
+	require.NoError(t, err)
+	assert.Equal(t, cooperativeID, transfer.CooperativeID)
+	assert.Equal(t, entities.TransferStatusPending, transfer.Status)
+	assert.Nil(t, transfer.ProcessedAt)
+	assert.Equal(t, DefaultTransferRetryPolicy.MaxRetries, transfer.MaxRetries)
+	require.NotNil(t, transfer.NextAttemptAt)
+	assert.Equal(t, scheduledAt, *transfer.NextAttemptAt)
+	mockRepo.AssertCalled(t, "CreateTransfer", ctx, transfer)
+}
+
+func TestFundMonitoringService_ProcessPendingTransfers(t *testing.T) {
+	provider := &scriptedTransferProvider{failures: map[string]error{
+		"TRF-RETRY":     errors.New("gateway timeout"),
+		"TRF-EXHAUSTED": errors.New("gateway timeout"),
+		"TRF-REJECTED":  &PermanentTransferError{Reason: "account closed"},
+	}}
+	mockRepo := new(MockFundTransferRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service := NewFundMonitoringService(mockAuditService, nil, nil, mockRepo, provider, TransferRetryPolicy{MaxRetries: 3, BaseDelay: time.Minute, MaxDelay: 10 * time.Minute, BatchSize: 10})
+	ctx := context.Background()
+
+	succeeded := &entities.FundTransfer{ID: uuid.New(), TransferNumber: "TRF-OK", ProjectID: uuid.New(), Status: entities.TransferStatusPending, PaymentMethod: entities.PaymentMethodBankTransfer, BankAccount: "1234567890", NetAmount: idr("1000000"), MaxRetries: 3}
+	retried := &entities.FundTransfer{ID: uuid.New(), TransferNumber: "TRF-RETRY", ProjectID: uuid.New(), Status: entities.TransferStatusFailed, PaymentMethod: entities.PaymentMethodBankTransfer, BankAccount: "1234567890", NetAmount: idr("1000000"), RetryCount: 1, MaxRetries: 3}
+	exhausted := &entities.FundTransfer{ID: uuid.New(), TransferNumber: "TRF-EXHAUSTED", ProjectID: uuid.New(), Status: entities.TransferStatusFailed, PaymentMethod: entities.PaymentMethodBankTransfer, BankAccount: "1234567890", NetAmount: idr("1000000"), RetryCount: 3, MaxRetries: 3}
+	rejected := &entities.FundTransfer{ID: uuid.New(), TransferNumber: "TRF-REJECTED", ProjectID: uuid.New(), Status: entities.TransferStatusPending, PaymentMethod: entities.PaymentMethodBankTransfer, BankAccount: "1234567890", NetAmount: idr("1000000"), MaxRetries: 3}
+	mockRepo.On("ProcessDueTransfers", ctx, mock.AnythingOfType("time.Time"), 10).
+		Return([]*entities.FundTransfer{succeeded, retried, exhausted, rejected}, nil)
+
+	before := time.Now()
+	processed, err := service.ProcessPendingTransfers(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, processed)
+	require.Len(t, mockRepo.Attempts, 4)
+
+	assert.Equal(t, entities.TransferStatusCompleted, succeeded.Status)
+	assert.Equal(t, "TRF-TRF-OK", succeeded.BankTransactionID)
+	assert.NotNil(t, succeeded.ProcessedAt)
+	assert.NotNil(t, succeeded.CompletedAt)
+	assert.Nil(t, succeeded.NextAttemptAt)
+	assert.Equal(t, entities.TransferAttemptSucceeded, mockRepo.Attempts[0].Outcome)
+	assert.Equal(t, "TRF-TRF-OK", mockRepo.Attempts[0].ProviderReference)
+	assert.Equal(t, "scripted", mockRepo.Attempts[0].Provider)
+
+	// The second retry waits twice the base delay
+	assert.Equal(t, entities.TransferStatusFailed, retried.Status)
+	assert.Equal(t, 2, retried.RetryCount)
+	assert.Equal(t, "gateway timeout", retried.FailureReason)
+	require.NotNil(t, retried.NextAttemptAt)
+	assert.WithinDuration(t, before.Add(2*time.Minute), *retried.NextAttemptAt, 5*time.Second)
+	assert.Equal(t, entities.TransferAttemptTransientFailure, mockRepo.Attempts[1].Outcome)
+	assert.Equal(t, "gateway timeout", mockRepo.Attempts[1].ErrorMessage)
+
+	assert.Equal(t, entities.TransferStatusDeadLettered, exhausted.Status)
+	assert.Equal(t, 3, exhausted.RetryCount)
+	assert.NotNil(t, exhausted.DeadLetteredAt)
+	assert.Nil(t, exhausted.NextAttemptAt)
+	assert.Equal(t, entities.TransferAttemptTransientFailure, mockRepo.Attempts[2].Outcome)
+
+	// Permanent failures are not retried
+	assert.Equal(t, entities.TransferStatusDeadLettered, rejected.Status)
+	assert.Equal(t, 0, rejected.RetryCount)
+	assert.Equal(t, "account closed", rejected.FailureReason)
+	assert.Equal(t, entities.TransferAttemptPermanentFailure, mockRepo.Attempts[3].Outcome)
+
+	deadLetterAudits := 0
+	for _, call := range mockAuditService.Calls {
+		if call.Arguments.Get(1).(*LogOperationRequest).Operation == "dead_letter_transfer" {
+			deadLetterAudits++
+		}
+	}
+	assert.Equal(t, 2, deadLetterAudits)
+}
+
+func TestFundMonitoringService_ProcessPendingTransfers_AuditsOnlyCommittedDeadLetters(t *testing.T) {
+	provider := &scriptedTransferProvider{failures: map[string]error{
+		"TRF-REJECTED":    &PermanentTransferError{Reason: "account closed"},
+		"TRF-UNCOMMITTED": &PermanentTransferError{Reason: "account closed"},
+	}}
+	mockRepo := new(MockFundTransferRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service := NewFundMonitoringService(mockAuditService, nil, nil, mockRepo, provider, TransferRetryPolicy{MaxRetries: 3, BaseDelay: time.Minute, MaxDelay: 10 * time.Minute, BatchSize: 10})
+	ctx := context.Background()
+
+	rejected := &entities.FundTransfer{ID: uuid.New(), TransferNumber: "TRF-REJECTED", ProjectID: uuid.New(), Status: entities.TransferStatusPending, PaymentMethod: entities.PaymentMethodBankTransfer, BankAccount: "1234567890", NetAmount: idr("1000000"), MaxRetries: 3}
+	uncommitted := &entities.FundTransfer{ID: uuid.New(), TransferNumber: "TRF-UNCOMMITTED", ProjectID: uuid.New(), Status: entities.TransferStatusPending, PaymentMethod: entities.PaymentMethodBankTransfer, BankAccount: "1234567890", NetAmount: idr("1000000"), MaxRetries: 3}
+	mockRepo.UncommittedIDs = map[uuid.UUID]bool{uncommitted.ID: true}
+	mockRepo.On("ProcessDueTransfers", ctx, mock.AnythingOfType("time.Time"), 10).
+		Return([]*entities.FundTransfer{rejected, uncommitted}, errors.New("shard 1: failed to commit transfer attempt"))
+
+	processed, err := service.ProcessPendingTransfers(ctx)
+
+	// The shard's failure is reported; its transfer stays queued and is not audited
+	assert.ErrorContains(t, err, "shard 1")
+	assert.Equal(t, 1, processed)
+	var audited []uuid.UUID
+	for _, call := range mockAuditService.Calls {
+		if req := call.Arguments.Get(1).(*LogOperationRequest); req.Operation == "dead_letter_transfer" {
+			audited = append(audited, req.EntityID)
+		}
+	}
+	assert.Equal(t, []uuid.UUID{rejected.ID}, audited)
+}
+
+func TestFundMonitoringService_ProcessPendingTransfers_RequiresProvider(t *testing.T) {
+	mockRepo := new(MockFundTransferRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service := NewFundMonitoringService(mockAuditService, nil, nil, mockRepo, nil, TransferRetryPolicy{MaxRetries: 3, BaseDelay: time.Minute, MaxDelay: 10 * time.Minute, BatchSize: 10})
+
+	_, err := service.ProcessPendingTransfers(context.Background())
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "ProcessDueTransfers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFundMonitoringService_RequeueDeadLetteredTransfer(t *testing.T) {
+	mockRepo := new(MockFundTransferRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service := NewFundMonitoringService(mockAuditService, nil, nil, mockRepo, &scriptedTransferProvider{}, TransferRetryPolicy{MaxRetries: 3, BaseDelay: time.Minute, MaxDelay: 10 * time.Minute, BatchSize: 10})
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	deadLettered := &entities.FundTransfer{ID: uuid.New(), TransferNumber: "TRF-DEAD", ProjectID: uuid.New(), Status: entities.TransferStatusDeadLettered, PaymentMethod: entities.PaymentMethodBankTransfer, BankAccount: "1234567890", NetAmount: idr("1000000"), RetryCount: 3, MaxRetries: 3}
+	deadLetteredAt := time.Now().Add(-time.Hour)
+	deadLettered.DeadLetteredAt = &deadLetteredAt
+	mockRepo.On("GetTransfer", ctx, deadLettered.ID).Return(deadLettered, nil)
+	mockRepo.On("RequeueTransfer", ctx, deadLettered).Return(nil)
+
+	transfer, err := service.RequeueDeadLetteredTransfer(ctx, deadLettered.ID, &entities.RequeueFundTransferRequest{Notes: "beneficiary bank back online"}, adminID)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.TransferStatusPending, transfer.Status)
+	assert.Equal(t, 0, transfer.RetryCount)
+	assert.Nil(t, transfer.DeadLetteredAt)
+	require.NotNil(t, transfer.NextAttemptAt)
+	assert.WithinDuration(t, time.Now(), *transfer.NextAttemptAt, 5*time.Second)
+
+	completed := &entities.FundTransfer{ID: uuid.New(), TransferNumber: "TRF-DONE", ProjectID: uuid.New(), Status: entities.TransferStatusCompleted, PaymentMethod: entities.PaymentMethodBankTransfer, BankAccount: "1234567890", NetAmount: idr("1000000"), MaxRetries: 3}
+	mockRepo.On("GetTransfer", ctx, completed.ID).Return(completed, nil)
+
+	_, err = service.RequeueDeadLetteredTransfer(ctx, completed.ID, &entities.RequeueFundTransferRequest{}, adminID)
+
+	assert.Error(t, err)
+	mockRepo.AssertNumberOfCalls(t, "RequeueTransfer", 1)
+}
+
+func TestFundMonitoringService_CancelFundTransfer(t *testing.T) {
+	mockRepo := new(MockFundTransferRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service := NewFundMonitoringService(mockAuditService, nil, nil, mockRepo, &scriptedTransferProvider{}, TransferRetryPolicy{MaxRetries: 3, BaseDelay: time.Minute, MaxDelay: 10 * time.Minute, BatchSize: 10})
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	deadLettered := &entities.FundTransfer{ID: uuid.New(), TransferNumber: "TRF-DEAD", ProjectID: uuid.New(), Status: entities.TransferStatusDeadLettered, PaymentMethod: entities.PaymentMethodBankTransfer, BankAccount: "1234567890", NetAmount: idr("1000000"), RetryCount: 3, MaxRetries: 3}
+	mockRepo.On("GetTransfer", ctx, deadLettered.ID).Return(deadLettered, nil)
+	mockRepo.On("CancelTransfer", ctx, deadLettered).Return(nil)
+
+	err := service.CancelFundTransfer(ctx, deadLettered.ID, adminID, "member closed the account")
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.TransferStatusCancelled, deadLettered.Status)
+	assert.Equal(t, &adminID, deadLettered.CancelledBy)
+	assert.Equal(t, "member closed the account", deadLettered.CancellationReason)
+
+	completed := &entities.FundTransfer{ID: uuid.New(), TransferNumber: "TRF-DONE", ProjectID: uuid.New(), Status: entities.TransferStatusCompleted, PaymentMethod: entities.PaymentMethodBankTransfer, BankAccount: "1234567890", NetAmount: idr("1000000"), MaxRetries: 3}
+	mockRepo.On("GetTransfer", ctx, completed.ID).Return(completed, nil)
+
+	err = service.CancelFundTransfer(ctx, completed.ID, adminID, "too late")
+
+	assert.Error(t, err)
+	mockRepo.AssertNumberOfCalls(t, "CancelTransfer", 1)
+}
+
+func TestSimulatorPaymentProvider_ExecuteTransfer(t *testing.T) {
+	simulator := NewSimulatorPaymentProvider("secret", time.Hour)
+	ctx := context.Background()
+
+	transfer := &entities.FundTransfer{ID: uuid.New(), TransferNumber: "TRF-SIM", ProjectID: uuid.New(), Status: entities.TransferStatusPending, PaymentMethod: entities.PaymentMethodBankTransfer, BankAccount: "1234567890", NetAmount: idr("1000000"), MaxRetries: 3}
+	reference, err := simulator.ExecuteTransfer(ctx, transfer)
+	require.NoError(t, err)
+	assert.NotEmpty(t, reference)
+
+	// Executing the same transfer again returns the original reference
+	again, err := simulator.ExecuteTransfer(ctx, transfer)
+	require.NoError(t, err)
+	assert.Equal(t, reference, again)
+
+	missing := &entities.FundTransfer{ID: uuid.New(), TransferNumber: "TRF-NOACCOUNT", ProjectID: uuid.New(), Status: entities.TransferStatusPending, PaymentMethod: entities.PaymentMethodBankTransfer, BankAccount: "1234567890", NetAmount: idr("1000000"), MaxRetries: 3}
+	missing.BankAccount = ""
+	_, err = simulator.ExecuteTransfer(ctx, missing)
+	var permanent *PermanentTransferError
+	assert.ErrorAs(t, err, &permanent)
+
+	assert.Equal(t, simulator, TransferProviderFor(entities.PaymentProviderSimulator, simulator))
+	assert.Nil(t, TransferProviderFor("other", simulator))
+}
@@ -0,0 +1,390 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// FundingDeadlineService closes projects' fundraising when their funding
+// deadline passes. A project that reached its minimum funding is marked funded
+// and its success fee calculated; one that missed it is cancelled and its
+// confirmed investments refunded in full out of escrow. The project owner and investors are
+// notified either way.
+type FundingDeadlineService interface {
+	// CloseProjectFunding closes a project's funding once its deadline has passed
+	CloseProjectFunding(ctx context.Context, projectID, closerID uuid.UUID) (*entities.ProjectFundingClosure, error)
+	// ProcessDueProjects closes the funding of every project whose deadline has
+	// passed by asOf. Projects that fail to close are reported and left raising
+	// funds, so the next run retries them. Closures whose success fee or refund
+	// could not be recorded or whose outcome could not be notified are retried
+	// first.
+	ProcessDueProjects(ctx context.Context, asOf time.Time) (*entities.FundingDeadlineRun, error)
+	GetProjectClosure(ctx context.Context, projectID uuid.UUID) (*entities.ProjectFundingClosure, error)
+}
+
+type fundingDeadlineService struct {
+	fundingRepo          repositories.ProjectFundingRepository
+	profitSharingService ProfitSharingService
+	ledgerService        LedgerService
+	notificationService  NotificationService
+	currencyService      CurrencyService
+	auditService         AuditService
+}
+
+// NewFundingDeadlineService creates a new funding deadline service. Owners and
+// investors are notified of the outcome when notificationService is set.
+func NewFundingDeadlineService(fundingRepo repositories.ProjectFundingRepository, profitSharingService ProfitSharingService, ledgerService LedgerService, notificationService NotificationService, currencyService CurrencyService, auditService AuditService) FundingDeadlineService {
+	return &fundingDeadlineService{
+		fundingRepo:          fundingRepo,
+		profitSharingService: profitSharingService,
+		ledgerService:        ledgerService,
+		notificationService:  notificationService,
+		currencyService:      currencyService,
+		auditService:         auditService,
+	}
+}
+
+func (s *fundingDeadlineService) CloseProjectFunding(ctx context.Context, projectID, closerID uuid.UUID) (*entities.ProjectFundingClosure, error) {
+	project, err := s.fundingRepo.GetFundingProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.Status != entities.ProjectStatusActive {
+		return nil, fmt.Errorf("project is %s and not raising funds", project.Status)
+	}
+
+	now := time.Now()
+	if !project.DeadlinePassed(now) {
+		return nil, fmt.Errorf("project is raising funds until %s", project.FundingDeadline.Format("2006-01-02 15:04"))
+	}
+
+	return s.closeProject(ctx, project, closerID, now)
+}
+
+func (s *fundingDeadlineService) ProcessDueProjects(ctx context.Context, asOf time.Time) (*entities.FundingDeadlineRun, error) {
+	projects, err := s.fundingRepo.ListDueProjects(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &entities.FundingDeadlineRun{AsOf: asOf}
+
+	unfinished, err := s.fundingRepo.ListUnfinishedClosures(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, closure := range unfinished {
+		if err := s.retryClosure(ctx, closure); err != nil {
+			run.Failures = append(run.Failures, entities.FundingDeadlineFailure{ProjectID: closure.ProjectID, Error: err.Error()})
+			continue
+		}
+		run.Closures = append(run.Closures, closure)
+	}
+
+	for _, project := range projects {
+		closure, err := s.closeProject(ctx, project, uuid.Nil, time.Now())
+		if closure != nil {
+			run.Closures = append(run.Closures, closure)
+		}
+		if err != nil {
+			run.Failures = append(run.Failures, entities.FundingDeadlineFailure{ProjectID: project.ProjectID, Error: err.Error()})
+		}
+	}
+
+	return run, nil
+}
+
+// closeProject records the project's funding outcome. The closure is committed
+// before the success fee is charged, the refund is posted to the ledger and
+// anyone is notified, so that neither is recorded for funding that failed to
+// close; a closure is returned even when one of those steps fails.
+func (s *fundingDeadlineService) closeProject(ctx context.Context, project *entities.FundingProject, closerID uuid.UUID, now time.Time) (*entities.ProjectFundingClosure, error) {
+	currency := project.Currency
+	if currency == "" {
+		currency = s.currencyService.BaseCurrency()
+	}
+	funding := project.CurrentFunding.WithCurrency(currency)
+
+	investments, err := s.fundingRepo.ListConfirmedInvestments(ctx, project.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	closure := &entities.ProjectFundingClosure{
+		ID:              uuid.New(),
+		ProjectID:       project.ProjectID,
+		CooperativeID:   project.CooperativeID,
+		Currency:        currency,
+		FundingDeadline: project.FundingDeadline,
+		CurrentFunding:  funding,
+		MinimumFunding:  project.MinimumFunding.WithCurrency(currency),
+		InvestorCount:   len(investments),
+		ClosedBy:        closerID,
+		ClosedAt:        now,
+		CreatedAt:       now,
+	}
+
+	var refund *entities.FundRefund
+	var investorRefunds []*entities.InvestorRefund
+	if project.MeetsMinimum(funding) {
+		closure.Outcome = entities.ProjectFundingOutcomeFunded
+	} else {
+		closure.Outcome = entities.ProjectFundingOutcomeMinimumNotMet
+		if len(investments) > 0 {
+			refund, investorRefunds = newMinimumFundingRefund(project, currency, investments, closerID, now)
+			closure.FundRefundID = &refund.ID
+		}
+	}
+
+	if err := s.fundingRepo.CloseFunding(ctx, closure, refund, investorRefunds); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     closerID,
+		Operation:  "close_project_funding",
+		EntityType: entities.AuditEntityProject,
+		EntityID:   project.ProjectID,
+		NewValues:  closure,
+	})
+
+	if err := s.finishClosure(ctx, project, closure, investments, closerID); err != nil {
+		return closure, err
+	}
+
+	return closure, nil
+}
+
+// retryClosure finishes a closure whose success fee or refund could not be
+// recorded or whose outcome could not be notified when it closed
+func (s *fundingDeadlineService) retryClosure(ctx context.Context, closure *entities.ProjectFundingClosure) error {
+	project, err := s.fundingRepo.GetFundingProject(ctx, closure.ProjectID)
+	if err != nil {
+		return err
+	}
+	investments, err := s.fundingRepo.ListConfirmedInvestments(ctx, closure.ProjectID)
+	if err != nil {
+		return err
+	}
+	return s.finishClosure(ctx, project, closure, investments, uuid.Nil)
+}
+
+// finishClosure charges a funded project's success fee or posts a cancelled
+// project's refund, and notifies the project owner and investors of the
+// outcome. The outcome is notified even when the fee or refund cannot be
+// recorded yet; each step is retried until it succeeds.
+func (s *fundingDeadlineService) finishClosure(ctx context.Context, project *entities.FundingProject, closure *entities.ProjectFundingClosure, investments []*entities.Investment, closerID uuid.UUID) error {
+	var errs []error
+	if closure.Outcome == entities.ProjectFundingOutcomeFunded && closure.FeeCalculationID == nil {
+		if err := s.chargeSuccessFee(ctx, closure, closerID); err != nil {
+			errs = append(errs, fmt.Errorf("project %s funding closed but its success fee was not recorded: %w", closure.ProjectID, err))
+		}
+	}
+	if closure.FundRefundID != nil && closure.RefundPostedAt == nil {
+		if err := s.postRefund(ctx, closure, closerID); err != nil {
+			errs = append(errs, fmt.Errorf("project %s funding closed but its refund was not posted to the ledger: %w", closure.ProjectID, err))
+		}
+	}
+
+	if closure.NotifiedAt == nil {
+		if err := s.notifyOutcome(ctx, project, closure, investments); err != nil {
+			errs = append(errs, fmt.Errorf("project %s funding closed but notifications were not sent: %w", closure.ProjectID, err))
+			return errors.Join(errs...)
+		}
+
+		now := time.Now()
+		if err := s.fundingRepo.MarkClosureNotified(ctx, closure, now); err != nil {
+			errs = append(errs, fmt.Errorf("project %s funding closed and notified but the notification was not recorded: %w", closure.ProjectID, err))
+			return errors.Join(errs...)
+		}
+		closure.NotifiedAt = &now
+	}
+
+	return errors.Join(errs...)
+}
+
+// chargeSuccessFee calculates the success fee on the closed funding and records
+// it on the closure. The calculation is keyed by the closure, so a retry after
+// the fee failed to be recorded reuses it rather than charging again.
+func (s *fundingDeadlineService) chargeSuccessFee(ctx context.Context, closure *entities.ProjectFundingClosure, closerID uuid.UUID) error {
+	calculation, err := s.profitSharingService.CalculateProjectFee(ctx, &entities.CalculateProjectFeeRequest{
+		ProjectID:          closure.ProjectID,
+		TotalFundingAmount: closure.CurrentFunding,
+		CalculateDate:      closure.ClosedAt,
+		FundingClosureID:   &closure.ID,
+	}, closerID)
+	if err != nil {
+		return fmt.Errorf("failed to calculate success fee: %w", err)
+	}
+
+	charged := *closure
+	fee := calculation.FeeAmount.WithCurrency(closure.Currency)
+	charged.FeeCalculationID = &calculation.ID
+	charged.FeePercentage = &calculation.FeePercentage
+	charged.FeeAmount = &fee
+	if err := s.fundingRepo.RecordClosureFee(ctx, &charged); err != nil {
+		return err
+	}
+
+	*closure = charged
+	return nil
+}
+
+// postRefund pays a closure's refund out of the project's escrow in the ledger.
+// Ledger entries live on the cooperative's shard rather than the project's, so
+// the refund is posted once the closure commits; a refund that was posted
+// before its closure could record it is not posted again.
+func (s *fundingDeadlineService) postRefund(ctx context.Context, closure *entities.ProjectFundingClosure, posterID uuid.UUID) error {
+	refund, investorRefunds, err := s.fundingRepo.GetClosureRefund(ctx, closure)
+	if err != nil {
+		return err
+	}
+	if _, err := s.ledgerService.RecordRefund(ctx, refund, investorRefunds, posterID); err != nil && !errors.Is(err, repositories.ErrFundRefundPosted) {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.fundingRepo.MarkClosureRefundPosted(ctx, closure, now); err != nil {
+		return err
+	}
+	closure.RefundPostedAt = &now
+	return nil
+}
+
+// newMinimumFundingRefund refunds every confirmed investment in full; the
+// investors bear no fee for a project that failed to raise its minimum
+func newMinimumFundingRefund(project *entities.FundingProject, currency string, investments []*entities.Investment, initiatorID uuid.UUID, now time.Time) (*entities.FundRefund, []*entities.InvestorRefund) {
+	refund := &entities.FundRefund{
+		ID:               uuid.New(),
+		ProjectID:        project.ProjectID,
+		CooperativeID:    project.CooperativeID,
+		RefundType:       entities.FundRefundTypeMinimumFundingFailed,
+		RefundReason:     fmt.Sprintf("%s did not reach its minimum funding of %s by %s", project.Title, project.MinimumFunding.WithCurrency(currency), project.FundingDeadline.Format("2006-01-02")),
+		Currency:         currency,
+		RefundPercentage: 100,
+		ProcessingFee:    entities.ZeroMoney(currency),
+		Status:           entities.FundRefundStatusPending,
+		InitiatedBy:      initiatorID,
+		InitiatedAt:      now,
+		IsActive:         true,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	total := entities.ZeroMoney(currency)
+	investorRefunds := make([]*entities.InvestorRefund, 0, len(investments))
+	for _, investment := range investments {
+		amount := investment.Amount.WithCurrency(currency)
+		total = total.Add(amount)
+		investorRefunds = append(investorRefunds, &entities.InvestorRefund{
+			ID:                 uuid.New(),
+			FundRefundID:       refund.ID,
+			InvestmentID:       investment.ID,
+			InvestorID:         investment.InvestorID,
+			OriginalInvestment: amount,
+			RefundAmount:       amount,
+			ProcessingFee:      entities.ZeroMoney(currency),
+			NetRefundAmount:    amount,
+			Status:             entities.FundRefundStatusPending,
+			IsActive:           true,
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		})
+	}
+	refund.TotalRefundAmount = total
+	refund.NetRefundAmount = total
+
+	return refund, investorRefunds
+}
+
+// notifyOutcome tells the project owner and its investors how funding closed
+func (s *fundingDeadlineService) notifyOutcome(ctx context.Context, project *entities.FundingProject, closure *entities.ProjectFundingClosure, investments []*entities.Investment) error {
+	if s.notificationService == nil {
+		return nil
+	}
+
+	investorIDs := make([]uuid.UUID, 0, len(investments))
+	for _, investment := range investments {
+		investorIDs = append(investorIDs, investment.InvestorID)
+	}
+
+	if closure.Outcome == entities.ProjectFundingOutcomeFunded {
+		message := fmt.Sprintf("%s raised %s by its funding deadline.", project.Title, closure.CurrentFunding)
+		if closure.FeeAmount != nil {
+			message += fmt.Sprintf(" A success fee of %s applies.", closure.FeeAmount)
+		}
+		if err := s.notificationService.NotifyUsers(ctx, []uuid.UUID{project.OwnerID}, entities.NotificationTypeProjectFunded,
+			"Your project is funded", message, entities.AuditEntityProject, project.ProjectID); err != nil {
+			return err
+		}
+		if len(investorIDs) == 0 {
+			return nil
+		}
+		return s.notificationService.NotifyUsers(ctx, investorIDs, entities.NotificationTypeProjectFunded,
+			"A project you invested in is funded",
+			fmt.Sprintf("%s reached its funding by the deadline and will now go ahead.", project.Title),
+			entities.AuditEntityProject, project.ProjectID)
+	}
+
+	if err := s.notificationService.NotifyUsers(ctx, []uuid.UUID{project.OwnerID}, entities.NotificationTypeProjectFundingFailed,
+		"Your project did not reach its minimum funding",
+		fmt.Sprintf("%s raised %s of its %s minimum by the deadline and has been cancelled. Investors will be refunded.", project.Title, closure.CurrentFunding, closure.MinimumFunding),
+		entities.AuditEntityProject, project.ProjectID); err != nil {
+		return err
+	}
+	if len(investorIDs) == 0 {
+		return nil
+	}
+	return s.notificationService.NotifyUsers(ctx, investorIDs, entities.NotificationTypeProjectFundingFailed,
+		"A project you invested in did not reach its minimum funding",
+		fmt.Sprintf("%s did not reach its minimum funding by the deadline and has been cancelled. Your investment will be refunded in full.", project.Title),
+		entities.AuditEntityProject, project.ProjectID)
+}
+
+func (s *fundingDeadlineService) GetProjectClosure(ctx context.Context, projectID uuid.UUID) (*entities.ProjectFundingClosure, error) {
+	closure, err := s.fundingRepo.GetClosure(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if closure == nil {
+		return nil, fmt.Errorf("project funding has not been closed")
+	}
+	return closure, nil
+}
+
+// FundingDeadlineScheduler periodically closes the funding of projects whose
+// deadline has passed
+type FundingDeadlineScheduler struct {
+	*intervalScheduler
+	deadlineService FundingDeadlineService
+}
+
+// NewFundingDeadlineScheduler creates a scheduler that runs every interval
+func NewFundingDeadlineScheduler(deadlineService FundingDeadlineService, interval time.Duration) *FundingDeadlineScheduler {
+	s := &FundingDeadlineScheduler{deadlineService: deadlineService}
+	s.intervalScheduler = newIntervalScheduler("Funding deadline", interval, s.runOnce)
+	return s
+}
+
+func (s *FundingDeadlineScheduler) runOnce(ctx context.Context) error {
+	run, err := s.deadlineService.ProcessDueProjects(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, failure := range run.Failures {
+		log.Printf("Failed to close funding of project %s: %s", failure.ProjectID, failure.Error)
+	}
+	if len(run.Closures) > 0 {
+		log.Printf("Closed funding of %d projects past their deadline", len(run.Closures))
+	}
+	return nil
+}
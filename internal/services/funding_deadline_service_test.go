@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// notifiedUsers collects the recipients of every notification created
+func notifiedUsers(notificationRepo *MockNotificationRepository) map[uuid.UUID]string {
+	recipients := make(map[uuid.UUID]string)
+	notificationRepo.On("CreateNotifications", mock.Anything, mock.AnythingOfType("[]*entities.Notification")).Run(func(args mock.Arguments) {
+		for _, n := range args.Get(1).([]*entities.Notification) {
+			recipients[n.UserID] = n.Type
+		}
+	}).Return(nil)
+	return recipients
+}
+
+func TestFundingDeadlineService_CloseProjectFunding_Funded(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	fundingRepo := new(MockProjectFundingRepository)
+	notificationRepo := new(MockNotificationRepository)
+	profitService := NewProfitSharingService(mockAuditService, nil, nil, nil, nil, nil, nil, nil, nil)
+	ledgerRepo := new(MockLedgerRepository)
+	deadlineService := NewFundingDeadlineService(fundingRepo, profitService, NewLedgerService(ledgerRepo, mockAuditService), NewNotificationService(notificationRepo), currencyService, mockAuditService)
+	ctx := context.Background()
+
+	project := &entities.FundingProject{
+		ProjectID:       uuid.New(),
+		CooperativeID:   uuid.New(),
+		OwnerID:         uuid.New(),
+		Title:           "Rice mill expansion",
+		Status:          entities.ProjectStatusActive,
+		FundingDeadline: time.Now().Add(-time.Hour),
+		CurrentFunding:  idr("80000"),
+		MinimumFunding:  idr("50000"),
+	}
+	investments := []*entities.Investment{
+		{ID: uuid.New(), ProjectID: project.ProjectID, InvestorID: uuid.New(), Amount: idr("30000"), Status: "confirmed"},
+		{ID: uuid.New(), ProjectID: project.ProjectID, InvestorID: uuid.New(), Amount: idr("50000"), Status: "confirmed"},
+	}
+	fundingRepo.On("GetFundingProject", ctx, project.ProjectID).Return(project, nil)
+	fundingRepo.On("ListConfirmedInvestments", ctx, project.ProjectID).Return(investments, nil)
+	fundingRepo.On("CloseFunding", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure"), (*entities.FundRefund)(nil), ([]*entities.InvestorRefund)(nil)).Return(nil)
+	fundingRepo.On("RecordClosureFee", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure")).Return(nil)
+	fundingRepo.On("MarkClosureNotified", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure"), mock.AnythingOfType("time.Time")).Return(nil)
+	recipients := notifiedUsers(notificationRepo)
+
+	closure, err := deadlineService.CloseProjectFunding(ctx, project.ProjectID, uuid.New())
+	require.NoError(t, err)
+
+	// The 2% success fee is charged on the funds raised
+	assert.Equal(t, entities.ProjectFundingOutcomeFunded, closure.Outcome)
+	assert.Equal(t, 2, closure.InvestorCount)
+	require.NotNil(t, closure.FeeAmount)
+	assert.Equal(t, idr("1600"), *closure.FeeAmount)
+	assert.NotNil(t, closure.FeeCalculationID)
+	assert.Nil(t, closure.FundRefundID)
+	assert.NotNil(t, closure.NotifiedAt)
+
+	assert.Len(t, recipients, 3)
+	assert.Equal(t, entities.NotificationTypeProjectFunded, recipients[project.OwnerID])
+	assert.Equal(t, entities.NotificationTypeProjectFunded, recipients[investments[0].InvestorID])
+}
+
+func TestFundingDeadlineService_CloseProjectFunding_MinimumNotMet(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	fundingRepo := new(MockProjectFundingRepository)
+	notificationRepo := new(MockNotificationRepository)
+	profitService := NewProfitSharingService(mockAuditService, nil, nil, nil, nil, nil, nil, nil, nil)
+	ledgerRepo := new(MockLedgerRepository)
+	deadlineService := NewFundingDeadlineService(fundingRepo, profitService, NewLedgerService(ledgerRepo, mockAuditService), NewNotificationService(notificationRepo), currencyService, mockAuditService)
+	ctx := context.Background()
+
+	project := &entities.FundingProject{
+		ProjectID:       uuid.New(),
+		CooperativeID:   uuid.New(),
+		OwnerID:         uuid.New(),
+		Title:           "Rice mill expansion",
+		Status:          entities.ProjectStatusActive,
+		FundingDeadline: time.Now().Add(-time.Hour),
+		CurrentFunding:  idr("35000"),
+		MinimumFunding:  idr("50000"),
+	}
+	investments := []*entities.Investment{
+		{ID: uuid.New(), ProjectID: project.ProjectID, InvestorID: uuid.New(), Amount: idr("10000"), Status: "confirmed"},
+		{ID: uuid.New(), ProjectID: project.ProjectID, InvestorID: uuid.New(), Amount: idr("25000"), Status: "confirmed"},
+	}
+	fundingRepo.On("GetFundingProject", ctx, project.ProjectID).Return(project, nil)
+	fundingRepo.On("ListConfirmedInvestments", ctx, project.ProjectID).Return(investments, nil)
+	var refund *entities.FundRefund
+	var investorRefunds []*entities.InvestorRefund
+	fundingRepo.On("CloseFunding", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure"), mock.AnythingOfType("*entities.FundRefund"), mock.AnythingOfType("[]*entities.InvestorRefund")).Run(func(args mock.Arguments) {
+		refund = args.Get(2).(*entities.FundRefund)
+		investorRefunds = args.Get(3).([]*entities.InvestorRefund)
+		fundingRepo.On("GetClosureRefund", ctx, args.Get(1)).Return(refund, investorRefunds, nil)
+	}).Return(nil)
+	escrow := &entities.LedgerAccount{ID: uuid.New(), CooperativeID: project.CooperativeID, Currency: "IDR"}
+	ledgerRepo.On("GetAccountByCode", ctx, project.CooperativeID, mock.AnythingOfType("string"), "IDR").Return(escrow, nil)
+	var posted *entities.JournalEntry
+	ledgerRepo.On("PostFundedEntry", ctx, mock.AnythingOfType("*entities.JournalEntry"), escrow.ID).Run(func(args mock.Arguments) {
+		posted = args.Get(1).(*entities.JournalEntry)
+	}).Return(nil)
+	fundingRepo.On("MarkClosureRefundPosted", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure"), mock.AnythingOfType("time.Time")).Return(nil)
+	fundingRepo.On("MarkClosureNotified", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure"), mock.AnythingOfType("time.Time")).Return(nil)
+	recipients := notifiedUsers(notificationRepo)
+
+	closure, err := deadlineService.CloseProjectFunding(ctx, project.ProjectID, uuid.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.ProjectFundingOutcomeMinimumNotMet, closure.Outcome)
+	assert.Nil(t, closure.FeeAmount)
+
+	// Every confirmed investment is refunded in full, without a fee
+	require.NotNil(t, refund)
+	require.NotNil(t, closure.FundRefundID)
+	assert.Equal(t, refund.ID, *closure.FundRefundID)
+	assert.Equal(t, entities.FundRefundTypeMinimumFundingFailed, refund.RefundType)
+	assert.Equal(t, idr("35000"), refund.TotalRefundAmount)
+	assert.Equal(t, idr("35000"), refund.NetRefundAmount)
+	require.Len(t, investorRefunds, 2)
+	for i, ir := range investorRefunds {
+		assert.Equal(t, investments[i].ID, ir.InvestmentID)
+		assert.Equal(t, investments[i].Amount, ir.NetRefundAmount)
+		assert.Equal(t, refund.ID, ir.FundRefundID)
+	}
+
+	// The refund is paid out of the project's escrow in the ledger
+	require.NotNil(t, posted)
+	assert.Equal(t, entities.JournalEntryTypeRefund, posted.EntryType)
+	assert.Equal(t, refund.ID, posted.ReferenceID)
+	assert.Equal(t, idr("35000"), posted.TotalAmount)
+	assert.NotNil(t, closure.RefundPostedAt)
+
+	assert.Len(t, recipients, 3)
+	assert.Equal(t, entities.NotificationTypeProjectFundingFailed, recipients[project.OwnerID])
+	assert.Equal(t, entities.NotificationTypeProjectFundingFailed, recipients[investments[1].InvestorID])
+}
+
+func TestFundingDeadlineService_CloseProjectFunding_BeforeDeadline(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	fundingRepo := new(MockProjectFundingRepository)
+	profitService := NewProfitSharingService(mockAuditService, nil, nil, nil, nil, nil, nil, nil, nil)
+	ledgerRepo := new(MockLedgerRepository)
+	deadlineService := NewFundingDeadlineService(fundingRepo, profitService, NewLedgerService(ledgerRepo, mockAuditService), NewNotificationService(new(MockNotificationRepository)), currencyService, mockAuditService)
+	ctx := context.Background()
+
+	project := &entities.FundingProject{
+		ProjectID:       uuid.New(),
+		CooperativeID:   uuid.New(),
+		OwnerID:         uuid.New(),
+		Title:           "Rice mill expansion",
+		Status:          entities.ProjectStatusActive,
+		FundingDeadline: time.Now().Add(24 * time.Hour),
+		CurrentFunding:  idr("80000"),
+		MinimumFunding:  idr("50000"),
+	}
+	fundingRepo.On("GetFundingProject", ctx, project.ProjectID).Return(project, nil)
+
+	_, err := deadlineService.CloseProjectFunding(ctx, project.ProjectID, uuid.New())
+	assert.ErrorContains(t, err, "raising funds until")
+	fundingRepo.AssertNotCalled(t, "CloseFunding", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFundingDeadlineService_ProcessDueProjects_ContinuesPastFailures(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	fundingRepo := new(MockProjectFundingRepository)
+	notificationRepo := new(MockNotificationRepository)
+	profitService := NewProfitSharingService(mockAuditService, nil, nil, nil, nil, nil, nil, nil, nil)
+	ledgerRepo := new(MockLedgerRepository)
+	deadlineService := NewFundingDeadlineService(fundingRepo, profitService, NewLedgerService(ledgerRepo, mockAuditService), NewNotificationService(notificationRepo), currencyService, mockAuditService)
+	ctx := context.Background()
+
+	asOf := time.Now()
+	failing := &entities.FundingProject{
+		ProjectID:       uuid.New(),
+		CooperativeID:   uuid.New(),
+		OwnerID:         uuid.New(),
+		Title:           "Rice mill expansion",
+		Status:          entities.ProjectStatusActive,
+		FundingDeadline: asOf.Add(-time.Hour),
+		CurrentFunding:  idr("80000"),
+		MinimumFunding:  idr("50000"),
+	}
+	unfunded := &entities.FundingProject{
+		ProjectID:       uuid.New(),
+		CooperativeID:   uuid.New(),
+		OwnerID:         uuid.New(),
+		Title:           "Rice mill expansion",
+		Status:          entities.ProjectStatusActive,
+		FundingDeadline: asOf.Add(-time.Hour),
+		CurrentFunding:  idr("0"),
+		MinimumFunding:  idr("50000"),
+	}
+	fundingRepo.On("ListDueProjects", ctx, asOf).Return([]*entities.FundingProject{failing, unfunded}, nil)
+	fundingRepo.On("ListUnfinishedClosures", ctx).Return([]*entities.ProjectFundingClosure{}, nil)
+	fundingRepo.On("ListConfirmedInvestments", ctx, failing.ProjectID).Return(nil, errors.New("shard unavailable"))
+	fundingRepo.On("ListConfirmedInvestments", ctx, unfunded.ProjectID).Return([]*entities.Investment{}, nil)
+	fundingRepo.On("CloseFunding", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure"), (*entities.FundRefund)(nil), ([]*entities.InvestorRefund)(nil)).Return(nil)
+	fundingRepo.On("MarkClosureNotified", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure"), mock.AnythingOfType("time.Time")).Return(nil)
+	recipients := notifiedUsers(notificationRepo)
+
+	run, err := deadlineService.ProcessDueProjects(ctx, asOf)
+	require.NoError(t, err)
+
+	// A project nobody invested in closes without a refund; only its owner is told
+	require.Len(t, run.Closures, 1)
+	assert.Equal(t, unfunded.ProjectID, run.Closures[0].ProjectID)
+	assert.Equal(t, entities.ProjectFundingOutcomeMinimumNotMet, run.Closures[0].Outcome)
+	assert.Equal(t, uuid.Nil, run.Closures[0].ClosedBy)
+	require.Len(t, run.Failures, 1)
+	assert.Equal(t, failing.ProjectID, run.Failures[0].ProjectID)
+	assert.Len(t, recipients, 1)
+}
+
+func TestFundingDeadlineService_SuccessFeeChargedAfterClosing(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	fundingRepo := new(MockProjectFundingRepository)
+	notificationRepo := new(MockNotificationRepository)
+	profitService := NewProfitSharingService(mockAuditService, nil, nil, nil, nil, nil, nil, nil, nil)
+	ledgerRepo := new(MockLedgerRepository)
+	deadlineService := NewFundingDeadlineService(fundingRepo, profitService, NewLedgerService(ledgerRepo, mockAuditService), NewNotificationService(notificationRepo), currencyService, mockAuditService)
+	ctx := context.Background()
+
+	project := &entities.FundingProject{
+		ProjectID:       uuid.New(),
+		CooperativeID:   uuid.New(),
+		OwnerID:         uuid.New(),
+		Title:           "Rice mill expansion",
+		Status:          entities.ProjectStatusActive,
+		FundingDeadline: time.Now().Add(-time.Hour),
+		CurrentFunding:  idr("80000"),
+		MinimumFunding:  idr("50000"),
+	}
+	investments := []*entities.Investment{
+		{ID: uuid.New(), ProjectID: project.ProjectID, InvestorID: uuid.New(), Amount: idr("80000"), Status: "confirmed"},
+	}
+	fundingRepo.On("GetFundingProject", ctx, project.ProjectID).Return(project, nil)
+	fundingRepo.On("ListConfirmedInvestments", ctx, project.ProjectID).Return(investments, nil)
+	fundingRepo.On("CloseFunding", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure"), (*entities.FundRefund)(nil), ([]*entities.InvestorRefund)(nil)).Return(nil)
+	fundingRepo.On("RecordClosureFee", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure")).Return(errors.New("shard unavailable")).Once()
+	fundingRepo.On("MarkClosureNotified", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure"), mock.AnythingOfType("time.Time")).Return(nil)
+	recipients := notifiedUsers(notificationRepo)
+
+	// The funding stays closed and its outcome is notified without waiting for the fee
+	closure, err := deadlineService.CloseProjectFunding(ctx, project.ProjectID, uuid.New())
+	assert.ErrorContains(t, err, "success fee was not recorded")
+	require.NotNil(t, closure)
+	assert.Equal(t, entities.ProjectFundingOutcomeFunded, closure.Outcome)
+	assert.Nil(t, closure.FeeAmount)
+	assert.NotNil(t, closure.NotifiedAt)
+	assert.Len(t, recipients, 2)
+
+	// The next run charges the fee without notifying again
+	asOf := time.Now()
+	fundingRepo.On("ListDueProjects", ctx, asOf).Return([]*entities.FundingProject{}, nil)
+	fundingRepo.On("ListUnfinishedClosures", ctx).Return([]*entities.ProjectFundingClosure{closure}, nil)
+	fundingRepo.On("RecordClosureFee", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure")).Return(nil)
+
+	run, err := deadlineService.ProcessDueProjects(ctx, asOf)
+	require.NoError(t, err)
+	require.Len(t, run.Closures, 1)
+	assert.Empty(t, run.Failures)
+	require.NotNil(t, run.Closures[0].FeeAmount)
+	assert.Equal(t, idr("1600"), *run.Closures[0].FeeAmount)
+	notificationRepo.AssertNumberOfCalls(t, "CreateNotifications", 2)
+	fundingRepo.AssertNumberOfCalls(t, "MarkClosureNotified", 1)
+}
+
+func TestFundingDeadlineService_FailedNotificationRetried(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	fundingRepo := new(MockProjectFundingRepository)
+	notificationRepo := new(MockNotificationRepository)
+	profitService := NewProfitSharingService(mockAuditService, nil, nil, nil, nil, nil, nil, nil, nil)
+	ledgerRepo := new(MockLedgerRepository)
+	deadlineService := NewFundingDeadlineService(fundingRepo, profitService, NewLedgerService(ledgerRepo, mockAuditService), NewNotificationService(notificationRepo), currencyService, mockAuditService)
+	ctx := context.Background()
+
+	project := &entities.FundingProject{
+		ProjectID:       uuid.New(),
+		CooperativeID:   uuid.New(),
+		OwnerID:         uuid.New(),
+		Title:           "Rice mill expansion",
+		Status:          entities.ProjectStatusActive,
+		FundingDeadline: time.Now().Add(-time.Hour),
+		CurrentFunding:  idr("0"),
+		MinimumFunding:  idr("50000"),
+	}
+	fundingRepo.On("GetFundingProject", ctx, project.ProjectID).Return(project, nil)
+	fundingRepo.On("ListConfirmedInvestments", ctx, project.ProjectID).Return([]*entities.Investment{}, nil)
+	fundingRepo.On("CloseFunding", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure"), (*entities.FundRefund)(nil), ([]*entities.InvestorRefund)(nil)).Return(nil)
+	notificationRepo.On("CreateNotifications", ctx, mock.AnythingOfType("[]*entities.Notification")).Return(errors.New("shard unavailable")).Once()
+
+	closure, err := deadlineService.CloseProjectFunding(ctx, project.ProjectID, uuid.New())
+	assert.ErrorContains(t, err, "notifications were not sent")
+	require.NotNil(t, closure)
+	assert.Nil(t, closure.NotifiedAt)
+	fundingRepo.AssertNotCalled(t, "MarkClosureNotified", mock.Anything, mock.Anything, mock.Anything)
+
+	// The next run sends the notification again and records it
+	asOf := time.Now()
+	fundingRepo.On("ListDueProjects", ctx, asOf).Return([]*entities.FundingProject{}, nil)
+	fundingRepo.On("ListUnfinishedClosures", ctx).Return([]*entities.ProjectFundingClosure{closure}, nil)
+	fundingRepo.On("MarkClosureNotified", ctx, mock.AnythingOfType("*entities.ProjectFundingClosure"), mock.AnythingOfType("time.Time")).Return(nil)
+	recipients := notifiedUsers(notificationRepo)
+
+	run, err := deadlineService.ProcessDueProjects(ctx, asOf)
+	require.NoError(t, err)
+	assert.Empty(t, run.Failures)
+	require.Len(t, run.Closures, 1)
+	assert.NotNil(t, run.Closures[0].NotifiedAt)
+	assert.Equal(t, entities.NotificationTypeProjectFundingFailed, recipients[project.OwnerID])
+}
+
+func TestFundingDeadlineService_RefundPostedBeforeClosureRecordedIt(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	fundingRepo := new(MockProjectFundingRepository)
+	notificationRepo := new(MockNotificationRepository)
+	profitService := NewProfitSharingService(mockAuditService, nil, nil, nil, nil, nil, nil, nil, nil)
+	ledgerRepo := new(MockLedgerRepository)
+	deadlineService := NewFundingDeadlineService(fundingRepo, profitService, NewLedgerService(ledgerRepo, mockAuditService), NewNotificationService(notificationRepo), currencyService, mockAuditService)
+	ctx := context.Background()
+
+	refundID := uuid.New()
+	now := time.Now()
+	closure := &entities.ProjectFundingClosure{
+		ID:            uuid.New(),
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		Currency:      "IDR",
+		Outcome:       entities.ProjectFundingOutcomeMinimumNotMet,
+		FundRefundID:  &refundID,
+		ClosedAt:      now.Add(-time.Hour),
+		NotifiedAt:    &now,
+	}
+	refund := &entities.FundRefund{ID: refundID, ProjectID: closure.ProjectID, CooperativeID: closure.CooperativeID, Currency: "IDR",
+		RefundType: entities.FundRefundTypeMinimumFundingFailed}
+	investorRefunds := []*entities.InvestorRefund{
+		{ID: uuid.New(), FundRefundID: refundID, InvestmentID: uuid.New(), InvestorID: uuid.New(), RefundAmount: idr("35000"), ProcessingFee: idr("0"), NetRefundAmount: idr("35000")},
+	}
+	fundingRepo.On("ListDueProjects", ctx, now).Return([]*entities.FundingProject{}, nil)
+	fundingRepo.On("ListUnfinishedClosures", ctx).Return([]*entities.ProjectFundingClosure{closure}, nil)
+	fundingRepo.On("GetFundingProject", ctx, closure.ProjectID).Return(&entities.FundingProject{ProjectID: closure.ProjectID}, nil)
+	fundingRepo.On("ListConfirmedInvestments", ctx, closure.ProjectID).Return([]*entities.Investment{}, nil)
+	fundingRepo.On("GetClosureRefund", ctx, closure).Return(refund, investorRefunds, nil)
+	escrow := &entities.LedgerAccount{ID: uuid.New(), CooperativeID: closure.CooperativeID, Currency: "IDR"}
+	ledgerRepo.On("GetAccountByCode", ctx, closure.CooperativeID, mock.AnythingOfType("string"), "IDR").Return(escrow, nil)
+	ledgerRepo.On("PostFundedEntry", ctx, mock.AnythingOfType("*entities.JournalEntry"), escrow.ID).Return(repositories.ErrFundRefundPosted)
+	fundingRepo.On("MarkClosureRefundPosted", ctx, closure, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// A refund the ledger already holds is recorded on the closure without posting it twice
+	run, err := deadlineService.ProcessDueProjects(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, run.Failures)
+	require.Len(t, run.Closures, 1)
+	assert.NotNil(t, run.Closures[0].RefundPostedAt)
+	fundingRepo.AssertExpectations(t)
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// intervalScheduler runs a background job immediately and then every interval
+// until its context is done. A failed run is logged and the job is tried again
+// at the next tick.
+type intervalScheduler struct {
+	name     string
+	interval time.Duration
+	job      func(ctx context.Context) error
+}
+
+// newIntervalScheduler creates a scheduler for a job; name describes the job in
+// the log
+func newIntervalScheduler(name string, interval time.Duration, job func(ctx context.Context) error) *intervalScheduler {
+	return &intervalScheduler{
+		name:     name,
+		interval: interval,
+		job:      job,
+	}
+}
+
+// Run runs the job immediately and then every interval until ctx is done
+func (s *intervalScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.job(ctx); err != nil {
+			log.Printf("%s run failed: %v", s.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntervalScheduler_RunsUntilContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := 0
+	scheduler := newIntervalScheduler("Test", time.Millisecond, func(ctx context.Context) error {
+		runs++
+		// A failed run does not stop the scheduler
+		if runs == 3 {
+			cancel()
+		}
+		return errors.New("job failed")
+	})
+
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop when its context was done")
+	}
+	assert.Equal(t, 3, runs)
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
 
 	"github.com/google/uuid"
 )
@@ -15,33 +17,36 @@ import (
 type InvestmentFundingService interface {
 	// FR-041: Cooperative members can invest in approved projects
 	CreateInvestment(ctx context.Context, req *entities.CreateInvestmentExtendedRequest, investorID uuid.UUID) (*entities.InvestmentExtended, error)
-	ValidateInvestmentEligibility(ctx context.Context, investorID, projectID uuid.UUID, amount float64) (*entities.InvestmentEligibilityCheck, error)
+	ValidateInvestmentEligibility(ctx context.Context, investorID, projectID uuid.UUID, amount entities.Money) (*entities.InvestmentEligibilityCheck, error)
 
 	// FR-042: System shall validate investor eligibility and funds availability
 	CheckInvestorEligibility(ctx context.Context, investorID, projectID uuid.UUID) (bool, []string, error)
-	CheckFundsAvailability(ctx context.Context, investorID uuid.UUID, amount float64) (bool, float64, error)
-	ValidateInvestmentAmount(ctx context.Context, projectID uuid.UUID, amount float64) (bool, float64, float64, error) // min, max
+	CheckFundsAvailability(ctx context.Context, investorID uuid.UUID, amount entities.Money) (bool, entities.Money, error)
+	ValidateInvestmentAmount(ctx context.Context, projectID uuid.UUID, amount entities.Money) (bool, entities.Money, entities.Money, error) // min, max
 
 	// FR-043: Investments are transferred to cooperative's escrow account
 	TransferToEscrowAccount(ctx context.Context, investmentID uuid.UUID, cooperativeID uuid.UUID) error
-	GetEscrowAccount(ctx context.Context, cooperativeID uuid.UUID) (*entities.EscrowAccount, error)
-	UpdateEscrowBalance(ctx context.Context, escrowAccountID uuid.UUID, amount float64, operation string) error
+	ConfirmInvestmentPayment(ctx context.Context, investmentID, cooperativeID uuid.UUID, payment *entities.InvestmentPaymentConfirmation, confirmerID uuid.UUID) (*entities.InvestmentExtended, error)
+	GetEscrowAccounts(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.EscrowAccount, error)
+	UpdateEscrowBalance(ctx context.Context, escrowAccountID uuid.UUID, amount entities.Money, operation string) error
 
 	// FR-044: System shall support partial funding and multiple investors per project
 	GetProjectInvestments(ctx context.Context, projectID uuid.UUID, page, limit int) ([]*entities.InvestmentExtended, int, error)
-	GetProjectFundingProgress(ctx context.Context, projectID uuid.UUID) (float64, float64, int, error) // current, goal, investor count
-	CheckPartialFundingEligibility(ctx context.Context, projectID uuid.UUID, amount float64) (bool, error)
+	GetProjectFundingProgress(ctx context.Context, projectID uuid.UUID) (*entities.ProjectFundingProgress, error)
+	CheckPartialFundingEligibility(ctx context.Context, projectID uuid.UUID, amount entities.Money) (bool, error)
 
 	// FR-045: Minimum and maximum investment amounts can be set per project
-	SetProjectInvestmentLimits(ctx context.Context, projectID uuid.UUID, minAmount, maxAmount float64) error
-	GetProjectInvestmentLimits(ctx context.Context, projectID uuid.UUID) (float64, float64, error)
+	SetProjectInvestmentLimits(ctx context.Context, projectID uuid.UUID, minAmount, maxAmount entities.Money) error
+	GetProjectInvestmentLimits(ctx context.Context, projectID uuid.UUID) (entities.Money, entities.Money, error)
 
 	// Investment management
 	GetInvestment(ctx context.Context, investmentID uuid.UUID) (*entities.InvestmentExtended, error)
+	GetPendingInvestments(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.InvestmentExtended, error)
 	UpdateInvestment(ctx context.Context, investmentID uuid.UUID, req *entities.UpdateInvestmentRequest, updaterID uuid.UUID) (*entities.InvestmentExtended, error)
 	ApproveInvestment(ctx context.Context, req *entities.InvestmentApprovalRequest, approverID uuid.UUID) error
 	RejectInvestment(ctx context.Context, req *entities.InvestmentApprovalRequest, rejecterID uuid.UUID) error
 	CancelInvestment(ctx context.Context, investmentID, cancellerID uuid.UUID, reason string) error
+	ExpireReservations(ctx context.Context) (int, error)
 
 	// Investor portfolio
 	GetInvestorInvestments(ctx context.Context, investorID uuid.UUID, page, limit int) ([]*entities.InvestmentExtended, int, error)
@@ -54,21 +59,43 @@ type InvestmentFundingService interface {
 
 // investmentFundingService implements InvestmentFundingService
 type investmentFundingService struct {
-	auditService AuditService
+	auditService       AuditService
+	ledgerService      LedgerService
+	currencyService    CurrencyService
+	performanceService PortfolioPerformanceService
+	amlService         AMLMonitoringService
+	reservationRepo    repositories.InvestmentReservationRepository
+	reservationTTL     time.Duration
 	// Add repositories when implemented
 }
 
-// NewInvestmentFundingService creates a new investment funding service
-func NewInvestmentFundingService(auditService AuditService) InvestmentFundingService {
+// NewInvestmentFundingService creates a new investment funding service. When a
+// performance service is given, portfolios and project analytics are built
+// from the investments' cash flows; when an AML monitoring service is given,
+// every investment is screened against its rules. When a reservation
+// repository is given, each pending investment holds its amount against the
+// project's remaining funding goal for reservationTTL.
+func NewInvestmentFundingService(auditService AuditService, ledgerService LedgerService, currencyService CurrencyService, performanceService PortfolioPerformanceService, amlService AMLMonitoringService, reservationRepo repositories.InvestmentReservationRepository, reservationTTL time.Duration) InvestmentFundingService {
 	return &investmentFundingService{
-		auditService: auditService,
+		auditService:       auditService,
+		ledgerService:      ledgerService,
+		currencyService:    currencyService,
+		performanceService: performanceService,
+		amlService:         amlService,
+		reservationRepo:    reservationRepo,
+		reservationTTL:     reservationTTL,
 	}
 }
 
 // CreateInvestment implements FR-041: Cooperative members can invest in approved projects
 func (s *investmentFundingService) CreateInvestment(ctx context.Context, req *entities.CreateInvestmentExtendedRequest, investorID uuid.UUID) (*entities.InvestmentExtended, error) {
+	amount, err := req.Amount.InCurrency(req.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid investment amount: %w", err)
+	}
+
 	// Validate investment eligibility
-	eligibility, err := s.ValidateInvestmentEligibility(ctx, investorID, req.ProjectID, req.Amount)
+	eligibility, err := s.ValidateInvestmentEligibility(ctx, investorID, req.ProjectID, amount)
 	if err != nil {
 		return nil, fmt.Errorf("failed to validate investment eligibility: %w", err)
 	}
@@ -78,18 +105,20 @@ func (s *investmentFundingService) CreateInvestment(ctx context.Context, req *en
 	}
 
 	// Create investment record
+	investmentID := uuid.New()
 	investment := &entities.InvestmentExtended{
-		ID:                   uuid.New(),
+		ID:                   investmentID,
 		InvestorID:           investorID,
 		ProjectID:            req.ProjectID,
-		CooperativeID:        uuid.Nil, // Will be set based on project's cooperative
-		Amount:               req.Amount,
+		CooperativeID:        req.CooperativeID,
+		Amount:               amount,
 		Currency:             req.Currency,
 		InvestmentType:       req.InvestmentType,
 		InvestmentPercentage: 0, // Will be calculated
 		Status:               entities.InvestmentStatusPending,
 		ApprovalStatus:       "pending",
 		EscrowAccountID:      uuid.Nil, // Will be set during transfer
+		TransferReference:    entities.InvestmentPaymentReference(investmentID),
 		RiskLevel:            "medium", // Default
 		ShariaCompliant:      true,     // Default for cooperative projects
 		IsActive:             true,
@@ -97,20 +126,48 @@ func (s *investmentFundingService) CreateInvestment(ctx context.Context, req *en
 		UpdatedAt:            time.Now(),
 	}
 
+	// Alerts open a case for compliance review without holding the investment
+	if s.amlService != nil {
+		if _, err := s.amlService.MonitorInvestment(ctx, investment); err != nil {
+			return nil, fmt.Errorf("failed to monitor investment: %w", err)
+		}
+	}
+
+	// Hold the amount against the funding goal until the investment is approved
+	if s.reservationRepo != nil {
+		now := time.Now()
+		reservation := &entities.InvestmentReservation{
+			ID:           uuid.New(),
+			InvestmentID: investment.ID,
+			ProjectID:    investment.ProjectID,
+			InvestorID:   investorID,
+			Amount:       amount,
+			Currency:     amount.Currency(),
+			Status:       entities.InvestmentReservationStatusActive,
+			ExpiresAt:    now.Add(s.reservationTTL),
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if err := s.reservationRepo.Reserve(ctx, reservation, now); err != nil {
+			return nil, fmt.Errorf("failed to reserve investment amount: %w", err)
+		}
+		investment.ReservedUntil = &reservation.ExpiresAt
+	}
+
 	// Log audit trail
 	s.auditService.LogOperation(ctx, &LogOperationRequest{
 		UserID:     investorID,
 		Operation:  "create_investment",
 		EntityType: "investment",
 		EntityID:   investment.ID,
-		NewValues:  fmt.Sprintf("Created investment of %f %s in project %s", req.Amount, req.Currency, req.ProjectID),
+		NewValues:  fmt.Sprintf("Created investment of %s in project %s", amount, req.ProjectID),
 	})
 
 	return investment, nil
 }
 
 // ValidateInvestmentEligibility implements FR-042 validation
-func (s *investmentFundingService) ValidateInvestmentEligibility(ctx context.Context, investorID, projectID uuid.UUID, amount float64) (*entities.InvestmentEligibilityCheck, error) {
+func (s *investmentFundingService) ValidateInvestmentEligibility(ctx context.Context, investorID, projectID uuid.UUID, amount entities.Money) (*entities.InvestmentEligibilityCheck, error) {
 	check := &entities.InvestmentEligibilityCheck{
 		InvestorID: investorID,
 		ProjectID:  projectID,
@@ -173,34 +230,38 @@ func (s *investmentFundingService) CheckInvestorEligibility(ctx context.Context,
 }
 
 // CheckFundsAvailability checks if investor has sufficient funds
-func (s *investmentFundingService) CheckFundsAvailability(ctx context.Context, investorID uuid.UUID, amount float64) (bool, float64, error) {
+func (s *investmentFundingService) CheckFundsAvailability(ctx context.Context, investorID uuid.UUID, amount entities.Money) (bool, entities.Money, error) {
 	// Mock implementation - in real system would check:
 	// 1. Investor's account balance
 	// 2. Available credit limit
 	// 3. Pending transactions
 
 	// Mock available funds
-	availableFunds := 10000.0 // Mock value
-	return availableFunds >= amount, availableFunds, nil
+	availableFunds := entities.MustParseMoney("10000", amount.Currency()) // Mock value
+	return availableFunds.GreaterThanOrEqual(amount), availableFunds, nil
 }
 
 // ValidateInvestmentAmount validates investment amount against project limits
-func (s *investmentFundingService) ValidateInvestmentAmount(ctx context.Context, projectID uuid.UUID, amount float64) (bool, float64, float64, error) {
+func (s *investmentFundingService) ValidateInvestmentAmount(ctx context.Context, projectID uuid.UUID, amount entities.Money) (bool, entities.Money, entities.Money, error) {
 	// Mock implementation - in real system would get from project settings
-	minInvestment := 100.0  // Mock minimum
-	maxInvestment := 5000.0 // Mock maximum
+	minInvestment := entities.MustParseMoney("100", amount.Currency())  // Mock minimum
+	maxInvestment := entities.MustParseMoney("5000", amount.Currency()) // Mock maximum
 
-	return amount >= minInvestment && amount <= maxInvestment, minInvestment, maxInvestment, nil
+	return amount.GreaterThanOrEqual(minInvestment) && amount.LessThanOrEqual(maxInvestment), minInvestment, maxInvestment, nil
 }
 
 // TransferToEscrowAccount implements FR-043: Transfer to cooperative's escrow account
 func (s *investmentFundingService) TransferToEscrowAccount(ctx context.Context, investmentID uuid.UUID, cooperativeID uuid.UUID) error {
-	// Mock implementation - in real system would:
-	// 1. Get escrow account for cooperative
-	// 2. Update investment status
-	// 3. Update escrow account balance
-	// 4. Generate transfer reference
-	// 5. Log transaction
+	investment, err := s.GetInvestment(ctx, investmentID)
+	if err != nil {
+		return fmt.Errorf("failed to get investment: %w", err)
+	}
+
+	// Post the investment into the cooperative's escrow; the escrow balance is
+	// derived from the ledger so no separate balance update is needed
+	if _, err := s.ledgerService.RecordInvestment(ctx, investment, cooperativeID, investment.InvestorID); err != nil {
+		return fmt.Errorf("failed to record investment in ledger: %w", err)
+	}
 
 	// Log audit trail
 	s.auditService.LogOperation(ctx, &LogOperationRequest{
@@ -214,30 +275,92 @@ func (s *investmentFundingService) TransferToEscrowAccount(ctx context.Context,
 	return nil
 }
 
-// GetEscrowAccount gets cooperative's escrow account
-func (s *investmentFundingService) GetEscrowAccount(ctx context.Context, cooperativeID uuid.UUID) (*entities.EscrowAccount, error) {
-	// Mock implementation
-	return &entities.EscrowAccount{
-		ID:                 uuid.New(),
-		CooperativeID:      cooperativeID,
-		AccountNumber:      "ESC001",
-		AccountName:        "Cooperative Escrow Account",
-		BankName:           "Islamic Bank",
-		Currency:           "IDR",
-		Balance:            50000.0,
-		TotalInvestments:   100000.0,
-		TotalDistributions: 50000.0,
-		Status:             entities.EscrowAccountStatusActive,
-		IsActive:           true,
-		CreatedAt:          time.Now(),
-		UpdatedAt:          time.Now(),
-	}, nil
+// ConfirmInvestmentPayment confirms a pending investment once the investor's
+// payment has been received, posting it into the cooperative's escrow
+func (s *investmentFundingService) ConfirmInvestmentPayment(ctx context.Context, investmentID, cooperativeID uuid.UUID, payment *entities.InvestmentPaymentConfirmation, confirmerID uuid.UUID) (*entities.InvestmentExtended, error) {
+	investment, err := s.GetInvestment(ctx, investmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get investment: %w", err)
+	}
+
+	if investment.Status != entities.InvestmentStatusPending && investment.Status != entities.InvestmentStatusApproved {
+		return nil, fmt.Errorf("investment is %s and cannot be confirmed", investment.Status)
+	}
+	if payment.Amount.Currency() != investment.Amount.Currency() {
+		return nil, fmt.Errorf("payment currency %s does not match investment currency %s", payment.Amount.Currency(), investment.Amount.Currency())
+	}
+
+	investment.CooperativeID = cooperativeID
+	if _, err := s.ledgerService.RecordInvestment(ctx, investment, cooperativeID, confirmerID); err != nil {
+		return nil, fmt.Errorf("failed to record investment in ledger: %w", err)
+	}
+
+	paidAt := payment.PaidAt
+	investment.Status = entities.InvestmentStatusActive
+	investment.TransferDate = &paidAt
+	if payment.Reference != "" {
+		investment.TransferReference = payment.Reference
+	}
+	investment.UpdatedAt = time.Now()
+
+	// In real implementation, save to repository
+	// s.investmentRepo.Update(ctx, investment)
+
+	// Log audit trail
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     confirmerID,
+		Operation:  "confirm_investment_payment",
+		EntityType: "investment",
+		EntityID:   investmentID,
+		NewValues:  fmt.Sprintf("Confirmed payment of %s via %s (bank transaction %s)", payment.Amount, payment.Source, payment.BankTransactionID),
+	})
+
+	return investment, nil
+}
+
+// GetEscrowAccounts gets the cooperative's escrow accounts, one per currency it
+// holds escrow in
+func (s *investmentFundingService) GetEscrowAccounts(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.EscrowAccount, error) {
+	accounts, err := s.ledgerService.ListAccounts(ctx, cooperativeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escrow ledger accounts: %w", err)
+	}
+
+	escrowAccounts := []*entities.EscrowAccount{}
+	for _, account := range accounts {
+		if account.Category != entities.LedgerAccountCategoryEscrow {
+			continue
+		}
+
+		balance, err := s.ledgerService.GetAccountBalance(ctx, account.ID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get escrow balance: %w", err)
+		}
+
+		// Debits to escrow are funds received, credits are funds paid out
+		escrowAccounts = append(escrowAccounts, &entities.EscrowAccount{
+			ID:                 account.ID,
+			CooperativeID:      cooperativeID,
+			AccountNumber:      account.Code,
+			AccountName:        account.Name,
+			Currency:           account.Currency,
+			Balance:            balance.Balance,
+			TotalInvestments:   balance.TotalDebits,
+			TotalDistributions: balance.TotalCredits,
+			Status:             entities.EscrowAccountStatusActive,
+			IsActive:           account.IsActive,
+			CreatedAt:          account.CreatedAt,
+			UpdatedAt:          account.UpdatedAt,
+		})
+	}
+
+	return escrowAccounts, nil
 }
 
 // UpdateEscrowBalance updates escrow account balance
-func (s *investmentFundingService) UpdateEscrowBalance(ctx context.Context, escrowAccountID uuid.UUID, amount float64, operation string) error {
-	// Mock implementation - in real system would update database
-	return nil
+func (s *investmentFundingService) UpdateEscrowBalance(ctx context.Context, escrowAccountID uuid.UUID, amount entities.Money, operation string) error {
+	// Escrow balances are derived from posted journal entries and cannot be set directly
+	return errors.New("escrow balance is derived from the ledger; post a journal entry instead")
 }
 
 // GetProjectInvestments implements FR-044: Multiple investors per project
@@ -248,7 +371,7 @@ func (s *investmentFundingService) GetProjectInvestments(ctx context.Context, pr
 			ID:             uuid.New(),
 			InvestorID:     uuid.New(),
 			ProjectID:      projectID,
-			Amount:         1000.0,
+			Amount:         entities.MustParseMoney("1000", "IDR"),
 			Currency:       "IDR",
 			InvestmentType: entities.InvestmentTypePartial,
 			Status:         entities.InvestmentStatusActive,
@@ -261,25 +384,34 @@ func (s *investmentFundingService) GetProjectInvestments(ctx context.Context, pr
 	return investments, 1, nil
 }
 
-// GetProjectFundingProgress gets project funding progress
-func (s *investmentFundingService) GetProjectFundingProgress(ctx context.Context, projectID uuid.UUID) (float64, float64, int, error) {
+// GetProjectFundingProgress gets project funding progress, separating the
+// committed, reserved and available amounts
+func (s *investmentFundingService) GetProjectFundingProgress(ctx context.Context, projectID uuid.UUID) (*entities.ProjectFundingProgress, error) {
+	if s.reservationRepo != nil {
+		progress, err := s.reservationRepo.GetFundingProgress(ctx, projectID, s.currencyService.BaseCurrency(), time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project funding progress: %w", err)
+		}
+		return progress, nil
+	}
+
 	// Mock implementation
-	currentFunding := 50000.0
-	fundingGoal := 100000.0
+	currentFunding := entities.MustParseMoney("50000", "IDR")
+	fundingGoal := entities.MustParseMoney("100000", "IDR")
 	investorCount := 25
 
-	return currentFunding, fundingGoal, investorCount, nil
+	return entities.NewProjectFundingProgress(projectID, fundingGoal, currentFunding, entities.ZeroMoney("IDR"), investorCount, 0), nil
 }
 
 // CheckPartialFundingEligibility checks if partial funding is allowed
-func (s *investmentFundingService) CheckPartialFundingEligibility(ctx context.Context, projectID uuid.UUID, amount float64) (bool, error) {
+func (s *investmentFundingService) CheckPartialFundingEligibility(ctx context.Context, projectID uuid.UUID, amount entities.Money) (bool, error) {
 	// Mock implementation - check if project allows partial funding
 	return true, nil
 }
 
 // SetProjectInvestmentLimits implements FR-045: Set investment limits
-func (s *investmentFundingService) SetProjectInvestmentLimits(ctx context.Context, projectID uuid.UUID, minAmount, maxAmount float64) error {
-	if minAmount < 0 || maxAmount < 0 || minAmount > maxAmount {
+func (s *investmentFundingService) SetProjectInvestmentLimits(ctx context.Context, projectID uuid.UUID, minAmount, maxAmount entities.Money) error {
+	if minAmount.IsNegative() || maxAmount.IsNegative() || minAmount.GreaterThan(maxAmount) {
 		return errors.New("invalid investment limits")
 	}
 
@@ -291,16 +423,16 @@ func (s *investmentFundingService) SetProjectInvestmentLimits(ctx context.Contex
 		Operation:  "set_investment_limits",
 		EntityType: "project",
 		EntityID:   projectID,
-		NewValues:  fmt.Sprintf("Set investment limits: min=%f, max=%f", minAmount, maxAmount),
+		NewValues:  fmt.Sprintf("Set investment limits: min=%s, max=%s", minAmount.Decimal(), maxAmount.Decimal()),
 	})
 
 	return nil
 }
 
 // GetProjectInvestmentLimits gets project investment limits
-func (s *investmentFundingService) GetProjectInvestmentLimits(ctx context.Context, projectID uuid.UUID) (float64, float64, error) {
+func (s *investmentFundingService) GetProjectInvestmentLimits(ctx context.Context, projectID uuid.UUID) (entities.Money, entities.Money, error) {
 	// Mock implementation
-	return 100.0, 5000.0, nil
+	return entities.MustParseMoney("100", "IDR"), entities.MustParseMoney("5000", "IDR"), nil
 }
 
 // GetInvestment gets investment by ID
@@ -310,16 +442,23 @@ func (s *investmentFundingService) GetInvestment(ctx context.Context, investment
 		ID:             investmentID,
 		InvestorID:     uuid.New(),
 		ProjectID:      uuid.New(),
-		Amount:         1000.0,
+		Amount:         entities.MustParseMoney("1000", "IDR"),
 		Currency:       "IDR",
 		InvestmentType: entities.InvestmentTypePartial,
-		Status:         entities.InvestmentStatusActive,
+		Status:         entities.InvestmentStatusPending,
 		IsActive:       true,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}, nil
 }
 
+// GetPendingInvestments gets a cooperative's investments awaiting payment
+func (s *investmentFundingService) GetPendingInvestments(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.InvestmentExtended, error) {
+	// Mock implementation
+	// Would query investments of the cooperative's projects with status pending or approved
+	return []*entities.InvestmentExtended{}, nil
+}
+
 // UpdateInvestment updates investment
 func (s *investmentFundingService) UpdateInvestment(ctx context.Context, investmentID uuid.UUID, req *entities.UpdateInvestmentRequest, updaterID uuid.UUID) (*entities.InvestmentExtended, error) {
 	// Mock implementation
@@ -329,7 +468,11 @@ func (s *investmentFundingService) UpdateInvestment(ctx context.Context, investm
 	}
 
 	if req.Amount != nil {
-		investment.Amount = *req.Amount
+		amount, err := req.Amount.InCurrency(investment.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid investment amount: %w", err)
+		}
+		investment.Amount = amount
 	}
 	if req.InvestmentType != nil {
 		investment.InvestmentType = *req.InvestmentType
@@ -354,6 +497,17 @@ func (s *investmentFundingService) UpdateInvestment(ctx context.Context, investm
 
 // ApproveInvestment approves an investment
 func (s *investmentFundingService) ApproveInvestment(ctx context.Context, req *entities.InvestmentApprovalRequest, approverID uuid.UUID) error {
+	// Commit the reserved amount to the project's funding
+	if s.reservationRepo != nil {
+		reservation, err := s.reservationRepo.GetReservation(ctx, req.InvestmentID)
+		if err != nil {
+			return fmt.Errorf("failed to get investment reservation: %w", err)
+		}
+		if err := s.reservationRepo.ConvertReservation(ctx, reservation, time.Now()); err != nil {
+			return fmt.Errorf("failed to convert investment reservation: %w", err)
+		}
+	}
+
 	// Mock implementation
 	// Would update investment status to approved
 
@@ -372,6 +526,10 @@ func (s *investmentFundingService) ApproveInvestment(ctx context.Context, req *e
 
 // RejectInvestment rejects an investment
 func (s *investmentFundingService) RejectInvestment(ctx context.Context, req *entities.InvestmentApprovalRequest, rejecterID uuid.UUID) error {
+	if err := s.releaseReservation(ctx, req.InvestmentID, rejecterID, "investment rejected"); err != nil {
+		return err
+	}
+
 	// Mock implementation
 	// Would update investment status to rejected
 
@@ -390,6 +548,10 @@ func (s *investmentFundingService) RejectInvestment(ctx context.Context, req *en
 
 // CancelInvestment cancels an investment
 func (s *investmentFundingService) CancelInvestment(ctx context.Context, investmentID, cancellerID uuid.UUID, reason string) error {
+	if err := s.releaseReservation(ctx, investmentID, cancellerID, "investment cancelled"); err != nil {
+		return err
+	}
+
 	// Mock implementation
 	// Would update investment status to cancelled
 
@@ -406,6 +568,62 @@ func (s *investmentFundingService) CancelInvestment(ctx context.Context, investm
 	return nil
 }
 
+// releaseReservation returns a pending investment's reserved amount to its
+// project's available funding. Reservations already converted, released or
+// expired are left as they are.
+func (s *investmentFundingService) releaseReservation(ctx context.Context, investmentID, userID uuid.UUID, reason string) error {
+	if s.reservationRepo == nil {
+		return nil
+	}
+
+	reservation, err := s.reservationRepo.GetReservation(ctx, investmentID)
+	if err != nil {
+		return fmt.Errorf("failed to get investment reservation: %w", err)
+	}
+	if reservation.Status != entities.InvestmentReservationStatusActive {
+		return nil
+	}
+
+	now := time.Now()
+	reservation.Status = entities.InvestmentReservationStatusReleased
+	reservation.ReleasedAt = &now
+	reservation.ReleaseReason = reason
+	released, err := s.reservationRepo.ReleaseReservation(ctx, reservation)
+	if err != nil {
+		return fmt.Errorf("failed to release investment reservation: %w", err)
+	}
+	if !released {
+		return nil
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "release_investment_reservation",
+		EntityType: "investment_reservation",
+		EntityID:   reservation.ID,
+		OldValues:  map[string]interface{}{"status": entities.InvestmentReservationStatusActive},
+		NewValues:  map[string]interface{}{"status": reservation.Status, "investment_id": investmentID, "amount": reservation.Amount.String(), "reason": reason},
+		Status:     entities.AuditStatusSuccess,
+	})
+
+	return nil
+}
+
+// ExpireReservations releases the reservations of pending investments whose
+// hold has lapsed
+func (s *investmentFundingService) ExpireReservations(ctx context.Context) (int, error) {
+	if s.reservationRepo == nil {
+		return 0, nil
+	}
+
+	expired, err := s.reservationRepo.ExpireReservations(ctx, time.Now())
+	if err != nil {
+		return expired, fmt.Errorf("failed to expire investment reservations: %w", err)
+	}
+
+	return expired, nil
+}
+
 // GetInvestorInvestments gets investor's investments
 func (s *investmentFundingService) GetInvestorInvestments(ctx context.Context, investorID uuid.UUID, page, limit int) ([]*entities.InvestmentExtended, int, error) {
 	// Mock implementation
@@ -414,7 +632,7 @@ func (s *investmentFundingService) GetInvestorInvestments(ctx context.Context, i
 			ID:             uuid.New(),
 			InvestorID:     investorID,
 			ProjectID:      uuid.New(),
-			Amount:         1000.0,
+			Amount:         entities.MustParseMoney("1000", "IDR"),
 			Currency:       "IDR",
 			InvestmentType: entities.InvestmentTypePartial,
 			Status:         entities.InvestmentStatusActive,
@@ -427,20 +645,112 @@ func (s *investmentFundingService) GetInvestorInvestments(ctx context.Context, i
 	return investments, 1, nil
 }
 
-// GetInvestorPortfolio gets investor's portfolio summary
+// GetInvestorPortfolio gets investor's portfolio summary. Holdings are reported in
+// their original currency and totalled in the base currency at current rates.
 func (s *investmentFundingService) GetInvestorPortfolio(ctx context.Context, investorID uuid.UUID) (*entities.InvestmentSummary, error) {
-	// Mock implementation
-	return &entities.InvestmentSummary{
-		TotalInvestments:     5,
-		TotalAmount:          5000.0,
-		ActiveInvestments:    3,
-		ActiveAmount:         3000.0,
-		CompletedInvestments: 2,
-		CompletedAmount:      2000.0,
-		TotalReturns:         500.0,
-		AverageReturn:        10.0,
-		Currency:             "IDR",
-	}, nil
+	holdings, err := s.getInvestorHoldings(ctx, investorID)
+	if err != nil {
+		return nil, err
+	}
+
+	baseCurrency := s.currencyService.BaseCurrency()
+	now := time.Now()
+	portfolio := &entities.InvestmentSummary{
+		TotalAmount:     entities.ZeroMoney(baseCurrency),
+		ActiveAmount:    entities.ZeroMoney(baseCurrency),
+		CompletedAmount: entities.ZeroMoney(baseCurrency),
+		TotalReturns:    entities.ZeroMoney(baseCurrency),
+		Currency:        baseCurrency,
+	}
+
+	for _, holding := range holdings {
+		breakdown := &entities.InvestmentCurrencyBreakdown{
+			Currency:    holding.Currency,
+			Investments: holding.TotalInvestments,
+		}
+		conversions := []struct {
+			amount entities.Money
+			target *entities.ConvertedAmount
+			total  *entities.Money
+		}{
+			{holding.TotalAmount, &breakdown.TotalAmount, &portfolio.TotalAmount},
+			{holding.ActiveAmount, &breakdown.ActiveAmount, &portfolio.ActiveAmount},
+			{holding.CompletedAmount, &breakdown.CompletedAmount, &portfolio.CompletedAmount},
+			{holding.TotalReturns, &breakdown.TotalReturns, &portfolio.TotalReturns},
+		}
+		for _, c := range conversions {
+			converted, err := s.currencyService.ConvertToBase(ctx, c.amount, now)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert %s holdings: %w", holding.Currency, err)
+			}
+			*c.target = *converted
+			*c.total = c.total.Add(converted.Converted)
+		}
+
+		portfolio.TotalInvestments += holding.TotalInvestments
+		portfolio.ActiveInvestments += holding.ActiveInvestments
+		portfolio.CompletedInvestments += holding.CompletedInvestments
+		portfolio.ByCurrency = append(portfolio.ByCurrency, breakdown)
+	}
+
+	// Average return over the whole portfolio, weighted by base currency value
+	if portfolio.TotalAmount.IsPositive() {
+		ratio, _ := portfolio.TotalReturns.Ratio(portfolio.TotalAmount).Float64()
+		portfolio.AverageReturn = ratio * 100
+	}
+
+	return portfolio, nil
+}
+
+// getInvestorHoldings summarizes an investor's investments per currency.
+// Active investments are counted at their carrying value, completed ones at the
+// capital returned, and returns include both realized and unrealized results.
+func (s *investmentFundingService) getInvestorHoldings(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestmentSummary, error) {
+	if s.performanceService == nil {
+		// Mock holdings per currency
+		return []*entities.InvestmentSummary{
+			{
+				TotalInvestments:     3,
+				TotalAmount:          entities.MustParseMoney("5000", "IDR"),
+				ActiveInvestments:    2,
+				ActiveAmount:         entities.MustParseMoney("3000", "IDR"),
+				CompletedInvestments: 1,
+				CompletedAmount:      entities.MustParseMoney("2000", "IDR"),
+				TotalReturns:         entities.MustParseMoney("500", "IDR"),
+				Currency:             "IDR",
+			},
+			{
+				TotalInvestments:     2,
+				TotalAmount:          entities.MustParseMoney("1500", "MYR"),
+				ActiveInvestments:    1,
+				ActiveAmount:         entities.MustParseMoney("1000", "MYR"),
+				CompletedInvestments: 1,
+				CompletedAmount:      entities.MustParseMoney("500", "MYR"),
+				TotalReturns:         entities.MustParseMoney("150", "MYR"),
+				Currency:             "MYR",
+			},
+		}, nil
+	}
+
+	report, err := s.performanceService.GetInvestorPerformance(ctx, investorID, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure portfolio performance: %w", err)
+	}
+
+	holdings := make([]*entities.InvestmentSummary, 0, len(report.ByCurrency))
+	for _, performance := range report.ByCurrency {
+		holdings = append(holdings, &entities.InvestmentSummary{
+			TotalInvestments:     performance.Investments,
+			TotalAmount:          performance.Invested,
+			ActiveInvestments:    performance.OpenInvestments,
+			ActiveAmount:         performance.CurrentValue,
+			CompletedInvestments: performance.Investments - performance.OpenInvestments,
+			CompletedAmount:      performance.CapitalReturned,
+			TotalReturns:         performance.TotalResult,
+			Currency:             performance.Currency,
+		})
+	}
+	return holdings, nil
 }
 
 // GetInvestmentSummary gets investment summary for reporting
@@ -448,19 +758,44 @@ func (s *investmentFundingService) GetInvestmentSummary(ctx context.Context, coo
 	// Mock implementation
 	return &entities.InvestmentSummary{
 		TotalInvestments:     100,
-		TotalAmount:          1000000.0,
+		TotalAmount:          entities.MustParseMoney("1000000", "IDR"),
 		ActiveInvestments:    75,
-		ActiveAmount:         750000.0,
+		ActiveAmount:         entities.MustParseMoney("750000", "IDR"),
 		CompletedInvestments: 25,
-		CompletedAmount:      250000.0,
-		TotalReturns:         50000.0,
+		CompletedAmount:      entities.MustParseMoney("250000", "IDR"),
+		TotalReturns:         entities.MustParseMoney("50000", "IDR"),
 		AverageReturn:        5.0,
 		Currency:             "IDR",
 	}, nil
 }
 
-// GetProjectInvestmentAnalytics gets project investment analytics
+// GetProjectInvestmentAnalytics gets project investment analytics. Amounts are
+// in the base currency and the performance report breaks them down further.
 func (s *investmentFundingService) GetProjectInvestmentAnalytics(ctx context.Context, projectID uuid.UUID) (map[string]interface{}, error) {
+	if s.performanceService != nil {
+		report, err := s.performanceService.GetProjectPerformance(ctx, projectID, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to measure project performance: %w", err)
+		}
+
+		investors := make(map[uuid.UUID]bool)
+		for _, investment := range report.Investments {
+			investors[investment.InvestorID] = true
+		}
+		averageInvestment := entities.ZeroMoney(report.Total.Currency)
+		if report.Total.Investments > 0 {
+			averageInvestment = report.Total.Invested.MulRat(big.NewRat(1, int64(report.Total.Investments)), entities.RoundHalfUp)
+		}
+
+		return map[string]interface{}{
+			"total_investments":  report.Total.Investments,
+			"total_amount":       report.Total.Invested,
+			"average_investment": averageInvestment,
+			"investor_count":     len(investors),
+			"performance":        report,
+		}, nil
+	}
+
 	// Mock implementation
 	return map[string]interface{}{
 		"total_investments":  25,
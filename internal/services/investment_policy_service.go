@@ -27,17 +27,22 @@ type InvestmentPolicyService interface {
 	
 	// Validation and compliance
 	ValidatePolicyCompliance(ctx context.Context, projectID, policyID uuid.UUID) (bool, []string, error)
-	ValidateInvestmentAmount(ctx context.Context, cooperativeID uuid.UUID, amount float64) (bool, string, error)
+	ValidateInvestmentAmount(ctx context.Context, cooperativeID uuid.UUID, amount entities.Money) (bool, string, error)
 	ValidateInvestorEligibility(ctx context.Context, cooperativeID, userID uuid.UUID) (bool, []string, error)
 }
 
 type investmentPolicyService struct {
-	auditService AuditService
+	auditService     AuditService
+	screeningService ShariaScreeningService
 }
 
-func NewInvestmentPolicyService(auditService AuditService) InvestmentPolicyService {
+// NewInvestmentPolicyService creates a new investment policy service. Projects'
+// business activity is Sharia screened during policy validation when
+// screeningService is set.
+func NewInvestmentPolicyService(auditService AuditService, screeningService ShariaScreeningService) InvestmentPolicyService {
 	return &investmentPolicyService{
-		auditService: auditService,
+		auditService:     auditService,
+		screeningService: screeningService,
 	}
 }
 
@@ -152,14 +157,21 @@ func (s *investmentPolicyService) ValidatePolicyCompliance(ctx context.Context,
 	// - Project sector in allowed sectors
 	// - Project duration within limits
 	// - Risk level acceptable
-	// - Sharia compliance if required
 	// - Required documents provided
 
-	// For now, return compliant
-	return true, violations, nil
+	// Screen the project's business activity under the cooperative's Sharia rules
+	if s.screeningService != nil {
+		screening, err := s.screeningService.ScreenProject(ctx, projectID, nil)
+		if err != nil {
+			return false, violations, fmt.Errorf("failed to screen project: %w", err)
+		}
+		violations = append(violations, screening.Failures()...)
+	}
+
+	return len(violations) == 0, violations, nil
 }
 
-func (s *investmentPolicyService) ValidateInvestmentAmount(ctx context.Context, cooperativeID uuid.UUID, amount float64) (bool, string, error) {
+func (s *investmentPolicyService) ValidateInvestmentAmount(ctx context.Context, cooperativeID uuid.UUID, amount entities.Money) (bool, string, error) {
 	// Get active investment policies
 	policies, err := s.GetActiveInvestmentPolicies(ctx, cooperativeID)
 	if err != nil {
@@ -173,12 +185,16 @@ func (s *investmentPolicyService) ValidateInvestmentAmount(ctx context.Context,
 	// Check against the most recent policy
 	policy := policies[0] // Assuming sorted by creation date
 
-	if amount < policy.MinInvestmentAmount {
-		return false, fmt.Sprintf("Investment amount %.2f is below minimum of %.2f", amount, policy.MinInvestmentAmount), nil
+	if err := entities.CheckSameCurrency(amount, policy.MinInvestmentAmount, policy.MaxInvestmentAmount); err != nil {
+		return false, fmt.Sprintf("Investment amount %s is not in the currency of the policy limits", amount), nil
 	}
 
-	if amount > policy.MaxInvestmentAmount {
-		return false, fmt.Sprintf("Investment amount %.2f exceeds maximum of %.2f", amount, policy.MaxInvestmentAmount), nil
+	if amount.LessThan(policy.MinInvestmentAmount) {
+		return false, fmt.Sprintf("Investment amount %s is below minimum of %s", amount.Decimal(), policy.MinInvestmentAmount.Decimal()), nil
+	}
+
+	if amount.GreaterThan(policy.MaxInvestmentAmount) {
+		return false, fmt.Sprintf("Investment amount %s exceeds maximum of %s", amount.Decimal(), policy.MaxInvestmentAmount.Decimal()), nil
 	}
 
 	return true, "Investment amount is valid", nil
@@ -244,7 +260,11 @@ func (s *investmentPolicyService) DeactivateProfitSharingRules(ctx context.Conte
 
 // Helper validation methods
 func (s *investmentPolicyService) validateInvestmentPolicy(req *entities.CreateInvestmentPolicyRequest) error {
-	if req.MaxInvestmentAmount <= req.MinInvestmentAmount {
+	if err := entities.CheckSameCurrency(req.MinInvestmentAmount, req.MaxInvestmentAmount); err != nil {
+		return fmt.Errorf("minimum and maximum investment amounts must be in the same currency: %w", err)
+	}
+
+	if req.MaxInvestmentAmount.LessThanOrEqual(req.MinInvestmentAmount) {
 		return fmt.Errorf("maximum investment amount must be greater than minimum")
 	}
 
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ReservationExpiryScheduler periodically releases the funding reservations
+// of pending investments whose hold has lapsed
+type ReservationExpiryScheduler struct {
+	*intervalScheduler
+	investmentFundingService InvestmentFundingService
+}
+
+// NewReservationExpiryScheduler creates a scheduler that runs every interval
+func NewReservationExpiryScheduler(investmentFundingService InvestmentFundingService, interval time.Duration) *ReservationExpiryScheduler {
+	s := &ReservationExpiryScheduler{investmentFundingService: investmentFundingService}
+	s.intervalScheduler = newIntervalScheduler("Investment reservation expiry", interval, s.runOnce)
+	return s
+}
+
+func (s *ReservationExpiryScheduler) runOnce(ctx context.Context) error {
+	expired, err := s.investmentFundingService.ExpireReservations(ctx)
+	if expired > 0 {
+		log.Printf("Expired %d investment reservations", expired)
+	}
+	return err
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateInvestment_ReservesAmountForTTL(t *testing.T) {
+	mockRepo := new(MockInvestmentReservationRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	service := NewInvestmentFundingService(mockAuditService, nil, currencyService, nil, nil, mockRepo, 30*time.Minute)
+	projectID := uuid.New()
+
+	mockRepo.On("Reserve", mock.Anything, mock.AnythingOfType("*entities.InvestmentReservation"), mock.Anything).Return(nil)
+
+	before := time.Now()
+	investment, err := service.CreateInvestment(context.Background(), &entities.CreateInvestmentExtendedRequest{
+		ProjectID:      projectID,
+		Amount:         idr("1000"),
+		Currency:       "IDR",
+		InvestmentType: "partial",
+	}, uuid.New())
+	require.NoError(t, err)
+
+	reservation := mockRepo.Calls[0].Arguments.Get(1).(*entities.InvestmentReservation)
+	assert.Equal(t, investment.ID, reservation.InvestmentID)
+	assert.Equal(t, projectID, reservation.ProjectID)
+	assert.True(t, idr("1000").Equal(reservation.Amount))
+	assert.Equal(t, entities.InvestmentReservationStatusActive, reservation.Status)
+	assert.False(t, reservation.ExpiresAt.Before(before.Add(30*time.Minute)))
+	assert.False(t, reservation.CreatedAt.Before(before))
+	assert.Equal(t, reservation.CreatedAt, reservation.UpdatedAt)
+	require.NotNil(t, investment.ReservedUntil)
+	assert.Equal(t, reservation.ExpiresAt, *investment.ReservedUntil)
+}
+
+func TestCreateInvestment_FailsWithoutCapacity(t *testing.T) {
+	mockRepo := new(MockInvestmentReservationRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	service := NewInvestmentFundingService(mockAuditService, nil, currencyService, nil, nil, mockRepo, 30*time.Minute)
+
+	mockRepo.On("Reserve", mock.Anything, mock.Anything, mock.Anything).
+		Return(errors.New("project has only IDR 500.00 of its funding goal available"))
+
+	_, err := service.CreateInvestment(context.Background(), &entities.CreateInvestmentExtendedRequest{
+		ProjectID:      uuid.New(),
+		Amount:         idr("1000"),
+		Currency:       "IDR",
+		InvestmentType: "partial",
+	}, uuid.New())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "funding goal available")
+	mockAuditService.AssertNotCalled(t, "LogOperation", mock.Anything, mock.Anything)
+}
+
+func TestApproveInvestment_ConvertsReservation(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      string
+		expiresAt   time.Time
+		convertErr  error
+		expectedErr string
+	}{
+		{
+			name:      "Active reservation",
+			status:    entities.InvestmentReservationStatusActive,
+			expiresAt: time.Now().Add(time.Minute),
+		},
+		{
+			name:        "Expired reservation no longer fits",
+			status:      entities.InvestmentReservationStatusExpired,
+			expiresAt:   time.Now().Add(-time.Minute),
+			convertErr:  errors.New("investment reservation expired and the funding goal no longer has room for it"),
+			expectedErr: "no longer has room",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockInvestmentReservationRepository)
+			mockAuditService := new(MockAuditService)
+			mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+			currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+			service := NewInvestmentFundingService(mockAuditService, nil, currencyService, nil, nil, mockRepo, 30*time.Minute)
+			reservation := &entities.InvestmentReservation{ID: uuid.New(), InvestmentID: uuid.New(), ProjectID: uuid.New(), InvestorID: uuid.New(), Amount: idr("1000"), Currency: "IDR", Status: tc.status, ExpiresAt: tc.expiresAt}
+
+			mockRepo.On("GetReservation", mock.Anything, reservation.InvestmentID).Return(reservation, nil)
+			mockRepo.On("ConvertReservation", mock.Anything, reservation, mock.Anything).Return(tc.convertErr)
+
+			err := service.ApproveInvestment(context.Background(), &entities.InvestmentApprovalRequest{InvestmentID: reservation.InvestmentID}, uuid.New())
+
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRejectInvestment_ReleasesReservation(t *testing.T) {
+	mockRepo := new(MockInvestmentReservationRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	service := NewInvestmentFundingService(mockAuditService, nil, currencyService, nil, nil, mockRepo, 30*time.Minute)
+	reservation := &entities.InvestmentReservation{ID: uuid.New(), InvestmentID: uuid.New(), ProjectID: uuid.New(), InvestorID: uuid.New(), Amount: idr("1000"), Currency: "IDR", Status: entities.InvestmentReservationStatusActive, ExpiresAt: time.Now().Add(time.Minute)}
+
+	mockRepo.On("GetReservation", mock.Anything, reservation.InvestmentID).Return(reservation, nil)
+	mockRepo.On("ReleaseReservation", mock.Anything, reservation).Return(true, nil)
+
+	err := service.RejectInvestment(context.Background(), &entities.InvestmentApprovalRequest{InvestmentID: reservation.InvestmentID}, uuid.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.InvestmentReservationStatusReleased, reservation.Status)
+	assert.NotNil(t, reservation.ReleasedAt)
+	assert.Equal(t, "investment rejected", reservation.ReleaseReason)
+	mockAuditService.AssertCalled(t, "LogOperation", mock.Anything, mock.MatchedBy(func(req *LogOperationRequest) bool {
+		return req.Operation == "release_investment_reservation" && req.EntityID == reservation.ID
+	}))
+}
+
+func TestCancelInvestment_LeavesConvertedReservation(t *testing.T) {
+	mockRepo := new(MockInvestmentReservationRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	service := NewInvestmentFundingService(mockAuditService, nil, currencyService, nil, nil, mockRepo, 30*time.Minute)
+	reservation := &entities.InvestmentReservation{ID: uuid.New(), InvestmentID: uuid.New(), ProjectID: uuid.New(), InvestorID: uuid.New(), Amount: idr("1000"), Currency: "IDR", Status: entities.InvestmentReservationStatusConverted, ExpiresAt: time.Now().Add(time.Minute)}
+
+	mockRepo.On("GetReservation", mock.Anything, reservation.InvestmentID).Return(reservation, nil)
+
+	err := service.CancelInvestment(context.Background(), reservation.InvestmentID, uuid.New(), "changed my mind")
+	require.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "ReleaseReservation", mock.Anything, mock.Anything)
+}
+
+func TestGetProjectFundingProgress_SeparatesReservedFunding(t *testing.T) {
+	mockRepo := new(MockInvestmentReservationRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	service := NewInvestmentFundingService(mockAuditService, nil, currencyService, nil, nil, mockRepo, 30*time.Minute)
+	projectID := uuid.New()
+	progress := entities.NewProjectFundingProgress(projectID, idr("100000"), idr("60000"), idr("15000"), 4, 2)
+
+	mockRepo.On("GetFundingProgress", mock.Anything, projectID, "IDR", mock.Anything).Return(progress, nil)
+
+	result, err := service.GetProjectFundingProgress(context.Background(), projectID)
+	require.NoError(t, err)
+	assert.True(t, idr("60000").Equal(result.Committed))
+	assert.True(t, idr("15000").Equal(result.Reserved))
+	assert.True(t, idr("25000").Equal(result.Available))
+	assert.Equal(t, 2, result.ReservationCount)
+}
+
+func TestNewProjectFundingProgress_FloorsAvailableAtZero(t *testing.T) {
+	progress := entities.NewProjectFundingProgress(uuid.New(), idr("100000"), idr("90000"), idr("20000"), 3, 1)
+
+	assert.True(t, progress.Available.IsZero())
+	assert.Equal(t, "IDR", progress.Currency)
+}
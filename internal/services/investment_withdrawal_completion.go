@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// WithdrawalCompletionScheduler periodically settles the refunds of
+// withdrawals that left their project but were not posted to the ledger or
+// queued for payout
+type WithdrawalCompletionScheduler struct {
+	*intervalScheduler
+	withdrawalService InvestmentWithdrawalService
+}
+
+// NewWithdrawalCompletionScheduler creates a scheduler that runs every interval
+func NewWithdrawalCompletionScheduler(withdrawalService InvestmentWithdrawalService, interval time.Duration) *WithdrawalCompletionScheduler {
+	s := &WithdrawalCompletionScheduler{withdrawalService: withdrawalService}
+	s.intervalScheduler = newIntervalScheduler("Investment withdrawal completion", interval, s.runOnce)
+	return s
+}
+
+func (s *WithdrawalCompletionScheduler) runOnce(ctx context.Context) error {
+	completed, err := s.withdrawalService.ResumeCompletingWithdrawals(ctx)
+	if completed > 0 {
+		log.Printf("Completed %d investment withdrawals", completed)
+	}
+	return err
+}
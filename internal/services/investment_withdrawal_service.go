@@ -0,0 +1,468 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// NewWithdrawalTerms parses the notice period in days and the penalty, as a
+// fraction of the redemption value, that apply when a cooperative has no
+// active investment policy
+func NewWithdrawalTerms(noticeDays, penalty string) (entities.WithdrawalTerms, error) {
+	days, err := strconv.Atoi(noticeDays)
+	if err != nil || days < 0 {
+		return entities.WithdrawalTerms{}, fmt.Errorf("withdrawal notice must be a number of days: %q", noticeDays)
+	}
+
+	fraction, err := strconv.ParseFloat(penalty, 64)
+	if err != nil || fraction < 0 || fraction >= 1 {
+		return entities.WithdrawalTerms{}, fmt.Errorf("withdrawal penalty must be a fraction below 1: %q", penalty)
+	}
+
+	return entities.WithdrawalTerms{NoticeDays: days, Penalty: fraction}, nil
+}
+
+// InvestmentWithdrawalService lets investors exit active investments early.
+// A withdrawal gives the cooperative notice, is approved by the cooperative and
+// completes once the notice period has passed: the investor is refunded the
+// redemption value of the withdrawn capital less the early withdrawal penalty,
+// and the capital leaves the project's funding.
+type InvestmentWithdrawalService interface {
+	// RequestWithdrawal quotes and records a withdrawal from the investor's
+	// confirmed investment under the cooperative's withdrawal terms
+	RequestWithdrawal(ctx context.Context, investmentID uuid.UUID, req *entities.CreateInvestmentWithdrawalRequest, investorID uuid.UUID) (*entities.InvestmentWithdrawal, error)
+	CancelWithdrawal(ctx context.Context, projectID, withdrawalID, investorID uuid.UUID) (*entities.InvestmentWithdrawal, error)
+	// ReviewWithdrawal approves or rejects a requested withdrawal for the cooperative
+	ReviewWithdrawal(ctx context.Context, projectID, withdrawalID uuid.UUID, req *entities.ReviewInvestmentWithdrawalRequest, reviewerID uuid.UUID) (*entities.InvestmentWithdrawal, error)
+	// CompleteWithdrawal takes an approved withdrawal's capital out of the project
+	// on or after its exit date, records the refund in the ledger and queues it
+	// for payout
+	CompleteWithdrawal(ctx context.Context, projectID, withdrawalID, processorID uuid.UUID) (*entities.InvestmentWithdrawal, error)
+	// ResumeCompletingWithdrawals settles the refunds of withdrawals that left
+	// their project but were not posted or queued, returning how many completed
+	ResumeCompletingWithdrawals(ctx context.Context) (int, error)
+
+	GetWithdrawal(ctx context.Context, projectID, withdrawalID uuid.UUID) (*entities.InvestmentWithdrawal, error)
+	GetInvestorWithdrawals(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestmentWithdrawal, error)
+	GetCooperativeWithdrawals(ctx context.Context, cooperativeID uuid.UUID, status string) ([]*entities.InvestmentWithdrawal, error)
+}
+
+type investmentWithdrawalService struct {
+	withdrawalRepo  repositories.InvestmentWithdrawalRepository
+	policyService   InvestmentPolicyService
+	lossService     LossSharingService
+	ledgerService   LedgerService
+	payoutService   PayoutService
+	currencyService CurrencyService
+	auditService    AuditService
+	defaultTerms    entities.WithdrawalTerms
+}
+
+// NewInvestmentWithdrawalService creates a new investment withdrawal service.
+// Withdrawals follow the strictest of the cooperative's active investment
+// policies, or defaultTerms when it has none. Investors bear their share of
+// unrecovered project losses on withdrawn capital when lossService is set, and
+// refunds are queued for bank payout when payoutService is set.
+func NewInvestmentWithdrawalService(withdrawalRepo repositories.InvestmentWithdrawalRepository, policyService InvestmentPolicyService, lossService LossSharingService, ledgerService LedgerService, payoutService PayoutService, currencyService CurrencyService, auditService AuditService, defaultTerms entities.WithdrawalTerms) InvestmentWithdrawalService {
+	return &investmentWithdrawalService{
+		withdrawalRepo:  withdrawalRepo,
+		policyService:   policyService,
+		lossService:     lossService,
+		ledgerService:   ledgerService,
+		payoutService:   payoutService,
+		currencyService: currencyService,
+		auditService:    auditService,
+		defaultTerms:    defaultTerms,
+	}
+}
+
+func (s *investmentWithdrawalService) RequestWithdrawal(ctx context.Context, investmentID uuid.UUID, req *entities.CreateInvestmentWithdrawalRequest, investorID uuid.UUID) (*entities.InvestmentWithdrawal, error) {
+	position, err := s.withdrawalRepo.GetInvestmentPosition(ctx, req.ProjectID, investmentID)
+	if err != nil {
+		return nil, err
+	}
+	if position.InvestorID != investorID {
+		return nil, errors.New("investment does not belong to the investor")
+	}
+	if position.Status != "confirmed" {
+		return nil, fmt.Errorf("investment is %s; only active investments can be withdrawn from", position.Status)
+	}
+
+	open, err := s.withdrawalRepo.GetOpenWithdrawal(ctx, req.ProjectID, investmentID)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		return nil, fmt.Errorf("investment already has a %s withdrawal", open.Status)
+	}
+
+	currency := position.Currency
+	if currency == "" {
+		currency = s.currencyService.BaseCurrency()
+	}
+	invested := position.Amount.WithCurrency(currency)
+
+	withdrawn := invested
+	if req.Amount != nil {
+		if withdrawn, err = req.Amount.InCurrency(currency); err != nil {
+			return nil, fmt.Errorf("invalid withdrawal amount: %w", err)
+		}
+		if !withdrawn.IsPositive() {
+			return nil, errors.New("withdrawal amount must be positive")
+		}
+		if withdrawn.GreaterThan(invested) {
+			return nil, fmt.Errorf("withdrawal amount exceeds the investment of %s", invested)
+		}
+	}
+
+	terms, err := s.withdrawalTerms(ctx, position.CooperativeID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	noticeEnds := now.AddDate(0, 0, terms.NoticeDays)
+	exitDate := noticeEnds
+	if req.ExitDate != nil {
+		if req.ExitDate.Before(noticeEnds) {
+			return nil, fmt.Errorf("withdrawals need %d days' notice; the earliest exit date is %s", terms.NoticeDays, noticeEnds.Format("2006-01-02"))
+		}
+		exitDate = *req.ExitDate
+	}
+
+	withdrawal := &entities.InvestmentWithdrawal{
+		ID:              uuid.New(),
+		InvestmentID:    investmentID,
+		InvestorID:      investorID,
+		ProjectID:       position.ProjectID,
+		CooperativeID:   position.CooperativeID,
+		Currency:        currency,
+		InvestedAmount:  invested,
+		WithdrawnAmount: withdrawn,
+		Penalty:         terms.Penalty,
+		NoticeDays:      terms.NoticeDays,
+		RequestedAt:     now,
+		ExitDate:        exitDate,
+		Reason:          req.Reason,
+		BankAccount:     req.BankAccount,
+		Status:          entities.InvestmentWithdrawalStatusRequested,
+		OwnershipBefore: position.OwnershipPercentage,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.quoteRedemption(ctx, withdrawal); err != nil {
+		return nil, err
+	}
+
+	if err := s.withdrawalRepo.CreateWithdrawal(ctx, withdrawal); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     investorID,
+		Operation:  "request_investment_withdrawal",
+		EntityType: entities.AuditEntityInvestment,
+		EntityID:   investmentID,
+		NewValues:  withdrawal,
+	})
+
+	return withdrawal, nil
+}
+
+// withdrawalTerms returns the strictest notice and penalty among the
+// cooperative's active investment policies
+func (s *investmentWithdrawalService) withdrawalTerms(ctx context.Context, cooperativeID uuid.UUID) (entities.WithdrawalTerms, error) {
+	if s.policyService == nil {
+		return s.defaultTerms, nil
+	}
+
+	policies, err := s.policyService.GetActiveInvestmentPolicies(ctx, cooperativeID)
+	if err != nil {
+		return entities.WithdrawalTerms{}, fmt.Errorf("failed to get investment policies: %w", err)
+	}
+	if len(policies) == 0 {
+		return s.defaultTerms, nil
+	}
+
+	var terms entities.WithdrawalTerms
+	for _, policy := range policies {
+		if policy.WithdrawalNoticeDays > terms.NoticeDays {
+			terms.NoticeDays = policy.WithdrawalNoticeDays
+		}
+		if policy.WithdrawalPenalty > terms.Penalty {
+			terms.Penalty = policy.WithdrawalPenalty
+		}
+	}
+
+	return terms, nil
+}
+
+// quoteRedemption values the withdrawn capital. Under mudarabah and musyarakah
+// capital is not guaranteed: the withdrawn part of the investment bears its
+// pro-rata share of the investor's unrecovered losses on it, and the penalty
+// is charged on what remains.
+func (s *investmentWithdrawalService) quoteRedemption(ctx context.Context, w *entities.InvestmentWithdrawal) error {
+	unrecovered := entities.ZeroMoney(w.Currency)
+	if s.lossService != nil {
+		shares, err := s.lossService.GetInvestorLossShares(ctx, w.InvestorID)
+		if err != nil {
+			return fmt.Errorf("failed to get investor loss shares: %w", err)
+		}
+		for _, share := range shares {
+			if share.InvestmentID != w.InvestmentID {
+				continue
+			}
+			outstanding := share.LossAmount.Sub(share.RecoveredAmount).WithCurrency(w.Currency)
+			if outstanding.IsPositive() {
+				unrecovered = unrecovered.Add(outstanding)
+			}
+		}
+	}
+
+	loss := unrecovered.MulRat(w.WithdrawnAmount.Ratio(w.InvestedAmount), entities.RoundHalfUp)
+	w.UnrecoveredLoss = loss.Min(w.WithdrawnAmount)
+	w.RedemptionValue = w.WithdrawnAmount.Sub(w.UnrecoveredLoss)
+	w.PenaltyAmount = w.RedemptionValue.Mul(w.Penalty, entities.RoundHalfUp)
+	w.NetAmount = w.RedemptionValue.Sub(w.PenaltyAmount)
+	return nil
+}
+
+func (s *investmentWithdrawalService) CancelWithdrawal(ctx context.Context, projectID, withdrawalID, investorID uuid.UUID) (*entities.InvestmentWithdrawal, error) {
+	withdrawal, err := s.withdrawalRepo.GetWithdrawal(ctx, projectID, withdrawalID)
+	if err != nil {
+		return nil, err
+	}
+	if withdrawal.InvestorID != investorID {
+		return nil, errors.New("withdrawal does not belong to the investor")
+	}
+	if !withdrawal.IsOpen() {
+		return nil, fmt.Errorf("withdrawal is %s and can no longer be cancelled", withdrawal.Status)
+	}
+
+	fromStatus := withdrawal.Status
+	withdrawal.Status = entities.InvestmentWithdrawalStatusCancelled
+	withdrawal.UpdatedAt = time.Now()
+
+	updated, err := s.withdrawalRepo.UpdateWithdrawalStatus(ctx, withdrawal, fromStatus)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("withdrawal was updated concurrently")
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     investorID,
+		Operation:  "cancel_investment_withdrawal",
+		EntityType: entities.AuditEntityInvestment,
+		EntityID:   withdrawal.InvestmentID,
+		NewValues:  withdrawal,
+	})
+
+	return withdrawal, nil
+}
+
+func (s *investmentWithdrawalService) ReviewWithdrawal(ctx context.Context, projectID, withdrawalID uuid.UUID, req *entities.ReviewInvestmentWithdrawalRequest, reviewerID uuid.UUID) (*entities.InvestmentWithdrawal, error) {
+	withdrawal, err := s.withdrawalRepo.GetWithdrawal(ctx, projectID, withdrawalID)
+	if err != nil {
+		return nil, err
+	}
+	if withdrawal.Status != entities.InvestmentWithdrawalStatusRequested {
+		return nil, fmt.Errorf("withdrawal is %s and cannot be reviewed", withdrawal.Status)
+	}
+
+	now := time.Now()
+	withdrawal.Status = entities.InvestmentWithdrawalStatusRejected
+	operation := "reject_investment_withdrawal"
+	if req.Approve {
+		withdrawal.Status = entities.InvestmentWithdrawalStatusApproved
+		operation = "approve_investment_withdrawal"
+	}
+	withdrawal.ReviewedBy = &reviewerID
+	withdrawal.ReviewedAt = &now
+	withdrawal.ReviewNotes = req.Notes
+	withdrawal.UpdatedAt = now
+
+	updated, err := s.withdrawalRepo.UpdateWithdrawalStatus(ctx, withdrawal, entities.InvestmentWithdrawalStatusRequested)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("withdrawal was updated concurrently")
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     reviewerID,
+		Operation:  operation,
+		EntityType: entities.AuditEntityInvestment,
+		EntityID:   withdrawal.InvestmentID,
+		NewValues:  withdrawal,
+	})
+
+	return withdrawal, nil
+}
+
+// CompleteWithdrawal re-values the withdrawal against the losses outstanding
+// at exit. The exit and its refund are committed together before the refund is
+// posted to the ledger, which is on the cooperative's shard, and queued for
+// payout; a withdrawal whose settlement fails stays completing and is settled
+// by ResumeCompletingWithdrawals.
+func (s *investmentWithdrawalService) CompleteWithdrawal(ctx context.Context, projectID, withdrawalID, processorID uuid.UUID) (*entities.InvestmentWithdrawal, error) {
+	withdrawal, err := s.withdrawalRepo.GetWithdrawal(ctx, projectID, withdrawalID)
+	if err != nil {
+		return nil, err
+	}
+	if withdrawal.Status != entities.InvestmentWithdrawalStatusApproved {
+		return nil, fmt.Errorf("withdrawal is %s and cannot be completed", withdrawal.Status)
+	}
+	now := time.Now()
+	if now.Before(withdrawal.ExitDate) {
+		return nil, fmt.Errorf("withdrawal notice period runs until %s", withdrawal.ExitDate.Format("2006-01-02"))
+	}
+
+	if err := s.quoteRedemption(ctx, withdrawal); err != nil {
+		return nil, err
+	}
+
+	refund := &entities.FundRefund{
+		ID:                uuid.New(),
+		ProjectID:         withdrawal.ProjectID,
+		CooperativeID:     withdrawal.CooperativeID,
+		RefundType:        entities.FundRefundTypeInvestorRequest,
+		RefundReason:      withdrawal.Reason,
+		TotalRefundAmount: withdrawal.RedemptionValue,
+		Currency:          withdrawal.Currency,
+		ProcessingFee:     withdrawal.PenaltyAmount,
+		NetRefundAmount:   withdrawal.NetAmount,
+		Status:            entities.FundRefundStatusProcessing,
+		InitiatedBy:       processorID,
+		InitiatedAt:       now,
+		IsActive:          true,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	refund.RefundPercentage, _ = withdrawal.RedemptionValue.Ratio(withdrawal.InvestedAmount).Float64()
+	refund.RefundPercentage *= 100
+	investorRefund := &entities.InvestorRefund{
+		ID:                 uuid.New(),
+		FundRefundID:       refund.ID,
+		InvestmentID:       withdrawal.InvestmentID,
+		InvestorID:         withdrawal.InvestorID,
+		OriginalInvestment: withdrawal.InvestedAmount,
+		RefundAmount:       withdrawal.RedemptionValue,
+		ProcessingFee:      withdrawal.PenaltyAmount,
+		NetRefundAmount:    withdrawal.NetAmount,
+		Status:             entities.FundRefundStatusPending,
+		BankAccount:        withdrawal.BankAccount,
+		IsActive:           true,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	investorRefunds := []*entities.InvestorRefund{investorRefund}
+
+	withdrawal.Status = entities.InvestmentWithdrawalStatusCompleting
+	withdrawal.FundRefundID = &refund.ID
+	withdrawal.CompletedBy = &processorID
+	withdrawal.CompletedAt = &now
+	withdrawal.UpdatedAt = now
+	if err := s.withdrawalRepo.ExitWithdrawal(ctx, withdrawal, refund, investorRefunds); err != nil {
+		return nil, err
+	}
+
+	if err := s.settleWithdrawal(ctx, withdrawal, refund, investorRefunds, processorID); err != nil {
+		return nil, fmt.Errorf("withdrawal %s left the project but its refund was not settled and will be retried: %w", withdrawal.ID, err)
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     processorID,
+		Operation:  "complete_investment_withdrawal",
+		EntityType: entities.AuditEntityInvestment,
+		EntityID:   withdrawal.InvestmentID,
+		NewValues:  withdrawal,
+	})
+
+	return withdrawal, nil
+}
+
+// settleWithdrawal posts a withdrawal's refund out of escrow, queues it for
+// payout and marks the withdrawal completed. Steps already taken by an earlier
+// attempt are skipped.
+func (s *investmentWithdrawalService) settleWithdrawal(ctx context.Context, withdrawal *entities.InvestmentWithdrawal, refund *entities.FundRefund, investorRefunds []*entities.InvestorRefund, processorID uuid.UUID) error {
+	// Capital lost in full leaves nothing to refund
+	if withdrawal.RedemptionValue.IsPositive() {
+		if _, err := s.ledgerService.RecordRefund(ctx, refund, investorRefunds, processorID); err != nil && !errors.Is(err, repositories.ErrFundRefundPosted) {
+			return fmt.Errorf("failed to record withdrawal refund in ledger: %w", err)
+		}
+		if s.payoutService != nil {
+			if _, err := s.payoutService.QueueInvestorRefunds(ctx, refund, investorRefunds, processorID); err != nil && !errors.Is(err, repositories.ErrPayoutItemQueued) {
+				return fmt.Errorf("failed to queue withdrawal refund for payout: %w", err)
+			}
+		}
+	}
+
+	withdrawal.UpdatedAt = time.Now()
+	if err := s.withdrawalRepo.FinishWithdrawal(ctx, withdrawal); err != nil {
+		return err
+	}
+	withdrawal.Status = entities.InvestmentWithdrawalStatusCompleted
+	return nil
+}
+
+func (s *investmentWithdrawalService) ResumeCompletingWithdrawals(ctx context.Context) (int, error) {
+	withdrawals, err := s.withdrawalRepo.ListCompletingWithdrawals(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	completed := 0
+	var errs []error
+	for _, withdrawal := range withdrawals {
+		// Withdrawals claimed before exits recorded their refund never left the project
+		if withdrawal.FundRefundID == nil || withdrawal.CompletedBy == nil {
+			errs = append(errs, fmt.Errorf("withdrawal %s is completing without a recorded refund", withdrawal.ID))
+			continue
+		}
+
+		refund, investorRefunds, err := s.withdrawalRepo.GetWithdrawalRefund(ctx, withdrawal)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("withdrawal %s: %w", withdrawal.ID, err))
+			continue
+		}
+		if err := s.settleWithdrawal(ctx, withdrawal, refund, investorRefunds, *withdrawal.CompletedBy); err != nil {
+			errs = append(errs, fmt.Errorf("withdrawal %s: %w", withdrawal.ID, err))
+			continue
+		}
+		completed++
+
+		s.auditService.LogOperation(ctx, &LogOperationRequest{
+			UserID:     *withdrawal.CompletedBy,
+			Operation:  "complete_investment_withdrawal",
+			EntityType: entities.AuditEntityInvestment,
+			EntityID:   withdrawal.InvestmentID,
+			NewValues:  withdrawal,
+		})
+	}
+
+	return completed, errors.Join(errs...)
+}
+
+func (s *investmentWithdrawalService) GetWithdrawal(ctx context.Context, projectID, withdrawalID uuid.UUID) (*entities.InvestmentWithdrawal, error) {
+	return s.withdrawalRepo.GetWithdrawal(ctx, projectID, withdrawalID)
+}
+
+func (s *investmentWithdrawalService) GetInvestorWithdrawals(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestmentWithdrawal, error) {
+	return s.withdrawalRepo.ListInvestorWithdrawals(ctx, investorID)
+}
+
+func (s *investmentWithdrawalService) GetCooperativeWithdrawals(ctx context.Context, cooperativeID uuid.UUID, status string) ([]*entities.InvestmentWithdrawal, error) {
+	return s.withdrawalRepo.ListCooperativeWithdrawals(ctx, cooperativeID, status)
+}
@@ -0,0 +1,511 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubInvestmentPolicyService serves a fixed set of active investment policies
+type stubInvestmentPolicyService struct {
+	InvestmentPolicyService
+	policies []*entities.InvestmentPolicyExtended
+}
+
+func (s *stubInvestmentPolicyService) GetActiveInvestmentPolicies(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.InvestmentPolicyExtended, error) {
+	return s.policies, nil
+}
+
+// testInvestmentPosition returns a confirmed investment holding a quarter of its project
+func testInvestmentPosition(amount string) *entities.InvestmentPosition {
+	return &entities.InvestmentPosition{
+		InvestmentID:        uuid.New(),
+		InvestorID:          uuid.New(),
+		ProjectID:           uuid.New(),
+		CooperativeID:       uuid.New(),
+		Amount:              idr(amount),
+		Status:              "confirmed",
+		OwnershipPercentage: 25,
+		ProjectFunding:      idr("40000"),
+	}
+}
+
+func TestInvestmentWithdrawalService_RequestWithdrawal_BearsLossesAndPenalty(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	lossRepo := new(MockProjectLossRepository)
+	withdrawalRepo := new(MockInvestmentWithdrawalRepository)
+	withdrawalService := NewInvestmentWithdrawalService(withdrawalRepo, nil, NewLossSharingService(lossRepo, mockAuditService),
+		ledgerService, nil, currencyService, mockAuditService, entities.WithdrawalTerms{NoticeDays: 30, Penalty: 0.02})
+	ctx := context.Background()
+
+	position := testInvestmentPosition("10000")
+	withdrawalRepo.On("GetInvestmentPosition", ctx, position.ProjectID, position.InvestmentID).Return(position, nil)
+	withdrawalRepo.On("GetOpenWithdrawal", ctx, position.ProjectID, position.InvestmentID).Return(nil, nil)
+	withdrawalRepo.On("CreateWithdrawal", ctx, mock.AnythingOfType("*entities.InvestmentWithdrawal")).Return(nil)
+	lossRepo.On("ListInvestorLossShares", ctx, position.InvestorID).Return([]*entities.InvestorLossShare{
+		{InvestmentID: position.InvestmentID, LossAmount: idr("1000"), RecoveredAmount: idr("200")},
+		{InvestmentID: uuid.New(), LossAmount: idr("5000"), RecoveredAmount: idr("0")},
+	}, nil)
+
+	amount := idr("5000")
+	withdrawal, err := withdrawalService.RequestWithdrawal(ctx, position.InvestmentID, &entities.CreateInvestmentWithdrawalRequest{
+		ProjectID:   position.ProjectID,
+		Amount:      &amount,
+		Reason:      "Medical expenses",
+		BankAccount: "0098765432",
+	}, position.InvestorID)
+	require.NoError(t, err)
+
+	// Half the investment bears half of its 800 unrecovered loss
+	assert.Equal(t, entities.InvestmentWithdrawalStatusRequested, withdrawal.Status)
+	assert.Equal(t, idr("400"), withdrawal.UnrecoveredLoss)
+	assert.Equal(t, idr("4600"), withdrawal.RedemptionValue)
+	assert.Equal(t, idr("92"), withdrawal.PenaltyAmount)
+	assert.Equal(t, idr("4508"), withdrawal.NetAmount)
+	assert.Equal(t, 30, withdrawal.NoticeDays)
+	assert.False(t, withdrawal.ExitDate.Before(withdrawal.RequestedAt.AddDate(0, 0, 30)))
+}
+
+func TestInvestmentWithdrawalService_RequestWithdrawal_ExitInsideNoticePeriod(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	withdrawalRepo := new(MockInvestmentWithdrawalRepository)
+	withdrawalService := NewInvestmentWithdrawalService(withdrawalRepo, nil, NewLossSharingService(new(MockProjectLossRepository), mockAuditService),
+		ledgerService, nil, currencyService, mockAuditService, entities.WithdrawalTerms{NoticeDays: 30, Penalty: 0.02})
+	ctx := context.Background()
+
+	position := testInvestmentPosition("10000")
+	withdrawalRepo.On("GetInvestmentPosition", ctx, position.ProjectID, position.InvestmentID).Return(position, nil)
+	withdrawalRepo.On("GetOpenWithdrawal", ctx, position.ProjectID, position.InvestmentID).Return(nil, nil)
+
+	exitDate := time.Now().AddDate(0, 0, 7)
+	_, err := withdrawalService.RequestWithdrawal(ctx, position.InvestmentID, &entities.CreateInvestmentWithdrawalRequest{
+		ProjectID:   position.ProjectID,
+		ExitDate:    &exitDate,
+		Reason:      "Moving abroad",
+		BankAccount: "0098765432",
+	}, position.InvestorID)
+	assert.ErrorContains(t, err, "30 days' notice")
+	withdrawalRepo.AssertNotCalled(t, "CreateWithdrawal", mock.Anything, mock.Anything)
+}
+
+func TestInvestmentWithdrawalService_RequestWithdrawal_Rejected(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	withdrawalRepo := new(MockInvestmentWithdrawalRepository)
+	withdrawalService := NewInvestmentWithdrawalService(withdrawalRepo, nil, NewLossSharingService(new(MockProjectLossRepository), mockAuditService),
+		ledgerService, nil, currencyService, mockAuditService, entities.WithdrawalTerms{NoticeDays: 30, Penalty: 0.02})
+	ctx := context.Background()
+
+	position := testInvestmentPosition("10000")
+	pending := testInvestmentPosition("10000")
+	pending.Status = "pending"
+	open := testInvestmentPosition("10000")
+	withdrawalRepo.On("GetInvestmentPosition", ctx, position.ProjectID, position.InvestmentID).Return(position, nil)
+	withdrawalRepo.On("GetInvestmentPosition", ctx, pending.ProjectID, pending.InvestmentID).Return(pending, nil)
+	withdrawalRepo.On("GetInvestmentPosition", ctx, open.ProjectID, open.InvestmentID).Return(open, nil)
+	withdrawalRepo.On("GetOpenWithdrawal", ctx, position.ProjectID, position.InvestmentID).Return(nil, nil)
+	withdrawalRepo.On("GetOpenWithdrawal", ctx, open.ProjectID, open.InvestmentID).
+		Return(&entities.InvestmentWithdrawal{Status: entities.InvestmentWithdrawalStatusApproved}, nil)
+
+	tooMuch := idr("10000.01")
+	testCases := []struct {
+		name       string
+		position   *entities.InvestmentPosition
+		investorID uuid.UUID
+		amount     *entities.Money
+	}{
+		{"Someone else's investment", position, uuid.New(), nil},
+		{"Not yet active", pending, pending.InvestorID, nil},
+		{"Already withdrawing", open, open.InvestorID, nil},
+		{"More than invested", position, position.InvestorID, &tooMuch},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := withdrawalService.RequestWithdrawal(ctx, tc.position.InvestmentID, &entities.CreateInvestmentWithdrawalRequest{
+				ProjectID:   tc.position.ProjectID,
+				Amount:      tc.amount,
+				Reason:      "Need the funds",
+				BankAccount: "0098765432",
+			}, tc.investorID)
+			assert.Error(t, err)
+		})
+	}
+	withdrawalRepo.AssertNotCalled(t, "CreateWithdrawal", mock.Anything, mock.Anything)
+}
+
+func TestInvestmentWithdrawalService_RequestWithdrawal_StrictestPolicy(t *testing.T) {
+	policyService := &stubInvestmentPolicyService{policies: []*entities.InvestmentPolicyExtended{
+		{WithdrawalNoticeDays: 60, WithdrawalPenalty: 0.01},
+		{WithdrawalNoticeDays: 14, WithdrawalPenalty: 0.05},
+	}}
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	lossRepo := new(MockProjectLossRepository)
+	withdrawalRepo := new(MockInvestmentWithdrawalRepository)
+	withdrawalService := NewInvestmentWithdrawalService(withdrawalRepo, policyService, NewLossSharingService(lossRepo, mockAuditService),
+		ledgerService, nil, currencyService, mockAuditService, entities.WithdrawalTerms{NoticeDays: 30, Penalty: 0.02})
+	ctx := context.Background()
+
+	position := testInvestmentPosition("10000")
+	withdrawalRepo.On("GetInvestmentPosition", ctx, position.ProjectID, position.InvestmentID).Return(position, nil)
+	withdrawalRepo.On("GetOpenWithdrawal", ctx, position.ProjectID, position.InvestmentID).Return(nil, nil)
+	withdrawalRepo.On("CreateWithdrawal", ctx, mock.AnythingOfType("*entities.InvestmentWithdrawal")).Return(nil)
+	lossRepo.On("ListInvestorLossShares", ctx, position.InvestorID).Return([]*entities.InvestorLossShare{}, nil)
+
+	withdrawal, err := withdrawalService.RequestWithdrawal(ctx, position.InvestmentID, &entities.CreateInvestmentWithdrawalRequest{
+		ProjectID:   position.ProjectID,
+		Reason:      "Need the funds",
+		BankAccount: "0098765432",
+	}, position.InvestorID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 60, withdrawal.NoticeDays)
+	assert.Equal(t, 0.05, withdrawal.Penalty)
+	assert.Equal(t, idr("10000"), withdrawal.RedemptionValue)
+	assert.Equal(t, idr("500"), withdrawal.PenaltyAmount)
+	assert.True(t, withdrawal.IsFullExit())
+}
+
+func TestInvestmentWithdrawalService_ReviewWithdrawal(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	withdrawalRepo := new(MockInvestmentWithdrawalRepository)
+	withdrawalService := NewInvestmentWithdrawalService(withdrawalRepo, nil, NewLossSharingService(new(MockProjectLossRepository), mockAuditService),
+		ledgerService, nil, currencyService, mockAuditService, entities.WithdrawalTerms{NoticeDays: 30, Penalty: 0.02})
+	ctx := context.Background()
+
+	exitDate := time.Now().AddDate(0, 0, 30)
+	withdrawal := &entities.InvestmentWithdrawal{
+		ID:              uuid.New(),
+		InvestmentID:    uuid.New(),
+		InvestorID:      uuid.New(),
+		ProjectID:       uuid.New(),
+		CooperativeID:   uuid.New(),
+		Currency:        "IDR",
+		InvestedAmount:  idr("10000"),
+		WithdrawnAmount: idr("5000"),
+		Penalty:         0.02,
+		NoticeDays:      30,
+		RequestedAt:     exitDate.AddDate(0, 0, -30),
+		ExitDate:        exitDate,
+		Reason:          "Medical expenses",
+		BankAccount:     "0098765432",
+		Status:          entities.InvestmentWithdrawalStatusApproved,
+	}
+	withdrawal.Status = entities.InvestmentWithdrawalStatusRequested
+	withdrawalRepo.On("GetWithdrawal", ctx, withdrawal.ProjectID, withdrawal.ID).Return(withdrawal, nil)
+	withdrawalRepo.On("UpdateWithdrawalStatus", ctx, withdrawal, entities.InvestmentWithdrawalStatusRequested).Return(true, nil)
+
+	reviewerID := uuid.New()
+	reviewed, err := withdrawalService.ReviewWithdrawal(ctx, withdrawal.ProjectID, withdrawal.ID,
+		&entities.ReviewInvestmentWithdrawalRequest{Approve: true, Notes: "Liquidity available"}, reviewerID)
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.InvestmentWithdrawalStatusApproved, reviewed.Status)
+	assert.Equal(t, reviewerID, *reviewed.ReviewedBy)
+
+	// A decided withdrawal cannot be reviewed again
+	_, err = withdrawalService.ReviewWithdrawal(ctx, withdrawal.ProjectID, withdrawal.ID,
+		&entities.ReviewInvestmentWithdrawalRequest{Approve: false}, reviewerID)
+	assert.Error(t, err)
+}
+
+func TestInvestmentWithdrawalService_CompleteWithdrawal(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	lossRepo := new(MockProjectLossRepository)
+	withdrawalRepo := new(MockInvestmentWithdrawalRepository)
+	withdrawalService := NewInvestmentWithdrawalService(withdrawalRepo, nil, NewLossSharingService(lossRepo, mockAuditService),
+		ledgerService, nil, currencyService, mockAuditService, entities.WithdrawalTerms{NoticeDays: 30, Penalty: 0.02})
+	ctx := context.Background()
+
+	exitDate := time.Now().AddDate(0, 0, -1)
+	withdrawal := &entities.InvestmentWithdrawal{
+		ID:              uuid.New(),
+		InvestmentID:    uuid.New(),
+		InvestorID:      uuid.New(),
+		ProjectID:       uuid.New(),
+		CooperativeID:   uuid.New(),
+		Currency:        "IDR",
+		InvestedAmount:  idr("10000"),
+		WithdrawnAmount: idr("10000"),
+		Penalty:         0.02,
+		NoticeDays:      30,
+		RequestedAt:     exitDate.AddDate(0, 0, -30),
+		ExitDate:        exitDate,
+		Reason:          "Medical expenses",
+		BankAccount:     "0098765432",
+		Status:          entities.InvestmentWithdrawalStatusApproved,
+	}
+	withdrawalRepo.On("GetWithdrawal", ctx, withdrawal.ProjectID, withdrawal.ID).Return(withdrawal, nil)
+	var refund *entities.FundRefund
+	withdrawalRepo.On("ExitWithdrawal", ctx, withdrawal, mock.AnythingOfType("*entities.FundRefund"), mock.AnythingOfType("[]*entities.InvestorRefund")).Run(func(args mock.Arguments) {
+		refund = args.Get(2).(*entities.FundRefund)
+	}).Return(nil)
+	withdrawalRepo.On("FinishWithdrawal", ctx, withdrawal).Return(nil)
+	lossRepo.On("ListInvestorLossShares", ctx, withdrawal.InvestorID).Return([]*entities.InvestorLossShare{
+		{InvestmentID: withdrawal.InvestmentID, LossAmount: idr("1000"), RecoveredAmount: idr("1000")},
+	}, nil)
+	ledgerRepo.On("GetAccountByCode", ctx, withdrawal.CooperativeID, mock.AnythingOfType("string"), "IDR").
+		Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}, nil)
+	var posted *entities.JournalEntry
+	ledgerRepo.On("PostFundedEntry", ctx, mock.AnythingOfType("*entities.JournalEntry"), mock.AnythingOfType("uuid.UUID")).Run(func(args mock.Arguments) {
+		posted = args.Get(1).(*entities.JournalEntry)
+	}).Return(nil)
+
+	completed, err := withdrawalService.CompleteWithdrawal(ctx, withdrawal.ProjectID, withdrawal.ID, uuid.New())
+	require.NoError(t, err)
+
+	// Recovered losses no longer reduce the redemption value
+	assert.Equal(t, entities.InvestmentWithdrawalStatusCompleted, completed.Status)
+	assert.Equal(t, idr("10000"), completed.RedemptionValue)
+	assert.Equal(t, idr("200"), completed.PenaltyAmount)
+	assert.Equal(t, idr("9800"), completed.NetAmount)
+	require.NotNil(t, completed.FundRefundID)
+
+	// The refund is recorded with the exit
+	require.NotNil(t, refund)
+	assert.Equal(t, refund.ID, *completed.FundRefundID)
+	assert.Equal(t, idr("9800"), refund.NetRefundAmount)
+
+	// The investor is debited the redemption value; escrow pays the net and keeps the penalty as a fee
+	require.NotNil(t, posted)
+	require.Len(t, posted.Lines, 3)
+	assert.Equal(t, idr("10000"), posted.Lines[0].Debit)
+	assert.Equal(t, idr("9800"), posted.Lines[1].Credit)
+	assert.Equal(t, idr("200"), posted.Lines[2].Credit)
+}
+
+func TestInvestmentWithdrawalService_CompleteWithdrawal_LedgerFailureLeavesCompleting(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	lossRepo := new(MockProjectLossRepository)
+	withdrawalRepo := new(MockInvestmentWithdrawalRepository)
+	withdrawalService := NewInvestmentWithdrawalService(withdrawalRepo, nil, NewLossSharingService(lossRepo, mockAuditService),
+		ledgerService, nil, currencyService, mockAuditService, entities.WithdrawalTerms{NoticeDays: 30, Penalty: 0.02})
+	ctx := context.Background()
+
+	exitDate := time.Now().AddDate(0, 0, -1)
+	withdrawal := &entities.InvestmentWithdrawal{
+		ID:              uuid.New(),
+		InvestmentID:    uuid.New(),
+		InvestorID:      uuid.New(),
+		ProjectID:       uuid.New(),
+		CooperativeID:   uuid.New(),
+		Currency:        "IDR",
+		InvestedAmount:  idr("10000"),
+		WithdrawnAmount: idr("10000"),
+		Penalty:         0.02,
+		NoticeDays:      30,
+		RequestedAt:     exitDate.AddDate(0, 0, -30),
+		ExitDate:        exitDate,
+		Reason:          "Medical expenses",
+		BankAccount:     "0098765432",
+		Status:          entities.InvestmentWithdrawalStatusApproved,
+	}
+	withdrawalRepo.On("GetWithdrawal", ctx, withdrawal.ProjectID, withdrawal.ID).Return(withdrawal, nil)
+	withdrawalRepo.On("ExitWithdrawal", ctx, withdrawal, mock.AnythingOfType("*entities.FundRefund"), mock.AnythingOfType("[]*entities.InvestorRefund")).Return(nil)
+	lossRepo.On("ListInvestorLossShares", ctx, withdrawal.InvestorID).Return([]*entities.InvestorLossShare{}, nil)
+	ledgerRepo.On("GetAccountByCode", ctx, withdrawal.CooperativeID, mock.AnythingOfType("string"), "IDR").
+		Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}, nil)
+	ledgerRepo.On("PostFundedEntry", ctx, mock.AnythingOfType("*entities.JournalEntry"), mock.AnythingOfType("uuid.UUID")).Return(errors.New("ledger unavailable"))
+
+	_, err := withdrawalService.CompleteWithdrawal(ctx, withdrawal.ProjectID, withdrawal.ID, uuid.New())
+	assert.ErrorContains(t, err, "ledger unavailable")
+
+	// The exit and its refund are committed; the withdrawal stays completing until the refund is posted
+	withdrawalRepo.AssertCalled(t, "ExitWithdrawal", ctx, withdrawal, mock.Anything, mock.Anything)
+	withdrawalRepo.AssertNotCalled(t, "FinishWithdrawal", mock.Anything, mock.Anything)
+	assert.Equal(t, entities.InvestmentWithdrawalStatusCompleting, withdrawal.Status)
+}
+
+func TestInvestmentWithdrawalService_CompleteWithdrawal_NoticeNotElapsed(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	withdrawalRepo := new(MockInvestmentWithdrawalRepository)
+	withdrawalService := NewInvestmentWithdrawalService(withdrawalRepo, nil, NewLossSharingService(new(MockProjectLossRepository), mockAuditService),
+		ledgerService, nil, currencyService, mockAuditService, entities.WithdrawalTerms{NoticeDays: 30, Penalty: 0.02})
+	ctx := context.Background()
+
+	exitDate := time.Now().AddDate(0, 0, 5)
+	withdrawal := &entities.InvestmentWithdrawal{
+		ID:              uuid.New(),
+		InvestmentID:    uuid.New(),
+		InvestorID:      uuid.New(),
+		ProjectID:       uuid.New(),
+		CooperativeID:   uuid.New(),
+		Currency:        "IDR",
+		InvestedAmount:  idr("10000"),
+		WithdrawnAmount: idr("10000"),
+		Penalty:         0.02,
+		NoticeDays:      30,
+		RequestedAt:     exitDate.AddDate(0, 0, -30),
+		ExitDate:        exitDate,
+		Reason:          "Medical expenses",
+		BankAccount:     "0098765432",
+		Status:          entities.InvestmentWithdrawalStatusApproved,
+	}
+	withdrawalRepo.On("GetWithdrawal", ctx, withdrawal.ProjectID, withdrawal.ID).Return(withdrawal, nil)
+
+	_, err := withdrawalService.CompleteWithdrawal(ctx, withdrawal.ProjectID, withdrawal.ID, uuid.New())
+	assert.ErrorContains(t, err, "notice period")
+	withdrawalRepo.AssertNotCalled(t, "ExitWithdrawal", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	ledgerRepo.AssertNotCalled(t, "PostFundedEntry", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInvestmentWithdrawalService_CompleteWithdrawal_CapitalLost(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	lossRepo := new(MockProjectLossRepository)
+	withdrawalRepo := new(MockInvestmentWithdrawalRepository)
+	withdrawalService := NewInvestmentWithdrawalService(withdrawalRepo, nil, NewLossSharingService(lossRepo, mockAuditService),
+		ledgerService, nil, currencyService, mockAuditService, entities.WithdrawalTerms{NoticeDays: 30, Penalty: 0.02})
+	ctx := context.Background()
+
+	exitDate := time.Now().AddDate(0, 0, -1)
+	withdrawal := &entities.InvestmentWithdrawal{
+		ID:              uuid.New(),
+		InvestmentID:    uuid.New(),
+		InvestorID:      uuid.New(),
+		ProjectID:       uuid.New(),
+		CooperativeID:   uuid.New(),
+		Currency:        "IDR",
+		InvestedAmount:  idr("10000"),
+		WithdrawnAmount: idr("10000"),
+		Penalty:         0.02,
+		NoticeDays:      30,
+		RequestedAt:     exitDate.AddDate(0, 0, -30),
+		ExitDate:        exitDate,
+		Reason:          "Medical expenses",
+		BankAccount:     "0098765432",
+		Status:          entities.InvestmentWithdrawalStatusApproved,
+	}
+	withdrawalRepo.On("GetWithdrawal", ctx, withdrawal.ProjectID, withdrawal.ID).Return(withdrawal, nil)
+	withdrawalRepo.On("ExitWithdrawal", ctx, withdrawal, mock.AnythingOfType("*entities.FundRefund"), mock.AnythingOfType("[]*entities.InvestorRefund")).Return(nil)
+	withdrawalRepo.On("FinishWithdrawal", ctx, withdrawal).Return(nil)
+	lossRepo.On("ListInvestorLossShares", ctx, withdrawal.InvestorID).Return([]*entities.InvestorLossShare{
+		{InvestmentID: withdrawal.InvestmentID, LossAmount: idr("10000"), RecoveredAmount: idr("0")},
+	}, nil)
+
+	completed, err := withdrawalService.CompleteWithdrawal(ctx, withdrawal.ProjectID, withdrawal.ID, uuid.New())
+	require.NoError(t, err)
+
+	// The investor still exits, but there is nothing to refund
+	assert.Equal(t, entities.InvestmentWithdrawalStatusCompleted, completed.Status)
+	assert.True(t, completed.RedemptionValue.IsZero())
+	assert.True(t, completed.NetAmount.IsZero())
+	ledgerRepo.AssertNotCalled(t, "PostFundedEntry", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInvestmentWithdrawalService_ResumeCompletingWithdrawals(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	withdrawalRepo := new(MockInvestmentWithdrawalRepository)
+	withdrawalService := NewInvestmentWithdrawalService(withdrawalRepo, nil, nil, ledgerService, nil, currencyService,
+		mockAuditService, entities.WithdrawalTerms{NoticeDays: 30, Penalty: 0.02})
+	ctx := context.Background()
+
+	processorID := uuid.New()
+	refundID := uuid.New()
+	exited := &entities.InvestmentWithdrawal{
+		ID:              uuid.New(),
+		InvestmentID:    uuid.New(),
+		ProjectID:       uuid.New(),
+		CooperativeID:   uuid.New(),
+		Currency:        "IDR",
+		RedemptionValue: idr("10000"),
+		PenaltyAmount:   idr("200"),
+		NetAmount:       idr("9800"),
+		Status:          entities.InvestmentWithdrawalStatusCompleting,
+		FundRefundID:    &refundID,
+		CompletedBy:     &processorID,
+	}
+	refund := &entities.FundRefund{
+		ID:                refundID,
+		ProjectID:         exited.ProjectID,
+		CooperativeID:     exited.CooperativeID,
+		RefundType:        entities.FundRefundTypeInvestorRequest,
+		TotalRefundAmount: idr("10000"),
+		Currency:          "IDR",
+		ProcessingFee:     idr("200"),
+		NetRefundAmount:   idr("9800"),
+	}
+	investorRefunds := []*entities.InvestorRefund{{
+		ID:              uuid.New(),
+		FundRefundID:    refundID,
+		InvestmentID:    exited.InvestmentID,
+		RefundAmount:    idr("10000"),
+		ProcessingFee:   idr("200"),
+		NetRefundAmount: idr("9800"),
+		Status:          entities.FundRefundStatusPending,
+	}}
+	// Claimed before exits recorded their refund
+	stranded := &entities.InvestmentWithdrawal{
+		ID:        uuid.New(),
+		ProjectID: uuid.New(),
+		Currency:  "IDR",
+		Status:    entities.InvestmentWithdrawalStatusCompleting,
+	}
+	withdrawalRepo.On("ListCompletingWithdrawals", ctx).Return([]*entities.InvestmentWithdrawal{exited, stranded}, nil)
+	withdrawalRepo.On("GetWithdrawalRefund", ctx, exited).Return(refund, investorRefunds, nil)
+	withdrawalRepo.On("FinishWithdrawal", ctx, exited).Return(nil)
+	ledgerRepo.On("GetAccountByCode", ctx, exited.CooperativeID, mock.AnythingOfType("string"), "IDR").
+		Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}, nil)
+	// The earlier attempt posted the refund before failing
+	ledgerRepo.On("PostFundedEntry", ctx, mock.AnythingOfType("*entities.JournalEntry"), mock.AnythingOfType("uuid.UUID")).
+		Return(repositories.ErrFundRefundPosted)
+
+	completed, err := withdrawalService.ResumeCompletingWithdrawals(ctx)
+	assert.Equal(t, 1, completed)
+	assert.ErrorContains(t, err, stranded.ID.String())
+	assert.Equal(t, entities.InvestmentWithdrawalStatusCompleted, exited.Status)
+	withdrawalRepo.AssertNotCalled(t, "GetWithdrawalRefund", ctx, stranded)
+	withdrawalRepo.AssertNotCalled(t, "FinishWithdrawal", ctx, stranded)
+}
+
+func TestNewWithdrawalTerms(t *testing.T) {
+	terms, err := NewWithdrawalTerms("30", "0.02")
+	require.NoError(t, err)
+	assert.Equal(t, entities.WithdrawalTerms{NoticeDays: 30, Penalty: 0.02}, terms)
+
+	_, err = NewWithdrawalTerms("-1", "0.02")
+	assert.Error(t, err)
+	_, err = NewWithdrawalTerms("30", "2")
+	assert.Error(t, err)
+}
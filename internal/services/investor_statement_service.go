@@ -0,0 +1,566 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// maxStatementLogoSize caps the cooperative logo downloaded for statements
+const maxStatementLogoSize = 2 << 20
+
+// statementPeriodTypes are generated by the scheduled job, shortest first
+var statementPeriodTypes = []string{
+	entities.StatementPeriodMonthly,
+	entities.StatementPeriodQuarterly,
+	entities.StatementPeriodYearly,
+}
+
+// InvestorStatementService builds investors' periodic account statements from
+// the ledger and archives them as PDF and CSV
+type InvestorStatementService interface {
+	// GenerateStatements generates the statements of a past period for every
+	// investor account, skipping those already archived
+	GenerateStatements(ctx context.Context, req *entities.GenerateInvestorStatementsRequest, userID uuid.UUID) (*entities.InvestorStatementRun, error)
+	// GenerateDueStatements generates the statements of the last completed
+	// month, quarter and year
+	GenerateDueStatements(ctx context.Context, now time.Time) ([]*entities.InvestorStatementRun, error)
+	ListStatements(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestorStatement, error)
+	GetStatementFile(ctx context.Context, statementID, investorID uuid.UUID, format string) (*entities.InvestorStatementFile, error)
+}
+
+type investorStatementService struct {
+	statementRepo repositories.InvestorStatementRepository
+	auditService  AuditService
+	fetchLogo     func(ctx context.Context, url string) ([]byte, error)
+}
+
+// NewInvestorStatementService creates a new investor statement service.
+// Cooperative logos are downloaded when statements are rendered; a logo that
+// cannot be fetched or decoded leaves just the cooperative's name.
+func NewInvestorStatementService(statementRepo repositories.InvestorStatementRepository, auditService AuditService) InvestorStatementService {
+	return &investorStatementService{
+		statementRepo: statementRepo,
+		auditService:  auditService,
+		fetchLogo:     fetchStatementLogo,
+	}
+}
+
+func (s *investorStatementService) GenerateStatements(ctx context.Context, req *entities.GenerateInvestorStatementsRequest, userID uuid.UUID) (*entities.InvestorStatementRun, error) {
+	periodStart, periodEnd := entities.StatementPeriod(req.PeriodType, req.PeriodStart.UTC())
+	if periodEnd.After(time.Now()) {
+		return nil, fmt.Errorf("statement period has not ended yet")
+	}
+
+	run, err := s.generatePeriod(ctx, req.PeriodType, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "generate_investor_statements",
+		EntityType: "investor_statement",
+		NewValues:  run,
+	})
+
+	return run, nil
+}
+
+func (s *investorStatementService) GenerateDueStatements(ctx context.Context, now time.Time) ([]*entities.InvestorStatementRun, error) {
+	var runs []*entities.InvestorStatementRun
+	for _, periodType := range statementPeriodTypes {
+		currentStart, _ := entities.StatementPeriod(periodType, now.UTC())
+		periodStart, periodEnd := entities.StatementPeriod(periodType, currentStart.Add(-time.Nanosecond))
+
+		run, err := s.generatePeriod(ctx, periodType, periodStart, periodEnd)
+		if err != nil {
+			return runs, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+func (s *investorStatementService) generatePeriod(ctx context.Context, periodType string, periodStart, periodEnd time.Time) (*entities.InvestorStatementRun, error) {
+	accounts, err := s.statementRepo.ListInvestorAccounts(ctx, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &entities.InvestorStatementRun{
+		PeriodType:  periodType,
+		PeriodStart: periodStart,
+		Failures:    []entities.InvestorStatementFailure{},
+	}
+	// Branding is looked up once per cooperative for the run
+	brandings := make(map[uuid.UUID]*statementBranding)
+
+	for _, account := range accounts {
+		generated, err := s.generateStatement(ctx, account, periodType, periodStart, periodEnd, brandings)
+		if err != nil {
+			run.Failures = append(run.Failures, entities.InvestorStatementFailure{
+				InvestorID:    *account.OwnerID,
+				CooperativeID: account.CooperativeID,
+				Currency:      account.Currency,
+				Error:         err.Error(),
+			})
+			continue
+		}
+		if generated {
+			run.Generated++
+		} else {
+			run.Skipped++
+		}
+	}
+
+	return run, nil
+}
+
+// generateStatement archives an account's statement for the period. It returns
+// false without an error if the statement already exists or the account had
+// no position and no activity in the period.
+func (s *investorStatementService) generateStatement(ctx context.Context, account *entities.LedgerAccount, periodType string, periodStart, periodEnd time.Time, brandings map[uuid.UUID]*statementBranding) (bool, error) {
+	exists, err := s.statementRepo.StatementExists(ctx, account, periodType, periodStart)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	opening, err := s.statementRepo.GetAccountPosition(ctx, account, periodStart)
+	if err != nil {
+		return false, err
+	}
+	lines, err := s.statementRepo.GetStatementLines(ctx, account, periodStart, periodEnd)
+	if err != nil {
+		return false, err
+	}
+	if opening.IsZero() && len(lines) == 0 {
+		return false, nil
+	}
+
+	branding, ok := brandings[account.CooperativeID]
+	if !ok {
+		branding, err = s.loadBranding(ctx, account.CooperativeID)
+		if err != nil {
+			return false, err
+		}
+		brandings[account.CooperativeID] = branding
+	}
+
+	statement := buildInvestorStatement(account, periodType, periodStart, periodEnd, opening, lines)
+	statement.CooperativeName = branding.CooperativeName
+
+	pdfContent := renderStatementPDF(statement, branding.logo)
+	csvContent, err := renderStatementCSV(statement)
+	if err != nil {
+		return false, err
+	}
+	statement.Files = []*entities.InvestorStatementFile{
+		newStatementFile(statement, entities.StatementFormatPDF, pdfContent),
+		newStatementFile(statement, entities.StatementFormatCSV, csvContent),
+	}
+
+	return s.statementRepo.CreateStatement(ctx, statement)
+}
+
+func (s *investorStatementService) ListStatements(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestorStatement, error) {
+	return s.statementRepo.ListInvestorStatements(ctx, investorID)
+}
+
+func (s *investorStatementService) GetStatementFile(ctx context.Context, statementID, investorID uuid.UUID, format string) (*entities.InvestorStatementFile, error) {
+	if format == "" {
+		format = entities.StatementFormatPDF
+	}
+
+	statement, err := s.statementRepo.GetStatement(ctx, statementID)
+	if err != nil {
+		return nil, err
+	}
+	// Statements of other investors are reported as missing
+	if statement.InvestorID != investorID {
+		return nil, fmt.Errorf("investor statement not found")
+	}
+
+	for _, file := range statement.Files {
+		if file.Format == format && len(file.Content) > 0 {
+			return newStatementFile(statement, format, file.Content), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported statement format: %s", format)
+}
+
+// statementBranding is a cooperative's branding with its logo ready to embed
+type statementBranding struct {
+	entities.StatementBranding
+	logo *pdfImage
+}
+
+func (s *investorStatementService) loadBranding(ctx context.Context, cooperativeID uuid.UUID) (*statementBranding, error) {
+	branding, err := s.statementRepo.GetStatementBranding(ctx, cooperativeID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &statementBranding{StatementBranding: *branding}
+	if branding.LogoURL == "" {
+		return result, nil
+	}
+
+	data, err := s.fetchLogo(ctx, branding.LogoURL)
+	if err == nil {
+		result.logo, err = statementLogo(data)
+	}
+	if err != nil {
+		log.Printf("Statements of cooperative %s are rendered without a logo: %v", cooperativeID, err)
+	}
+
+	return result, nil
+}
+
+// fetchStatementLogo downloads a cooperative's logo
+func fetchStatementLogo(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid logo URL: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download logo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download logo: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxStatementLogoSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download logo: %w", err)
+	}
+	if len(data) > maxStatementLogoSize {
+		return nil, fmt.Errorf("logo exceeds %d bytes", maxStatementLogoSize)
+	}
+
+	return data, nil
+}
+
+// statementLogo decodes a PNG, JPEG or GIF logo and re-encodes it as an RGB
+// JPEG on a white background, which PDF viewers can display directly
+func statementLogo(data []byte) (*pdfImage, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode logo: %w", err)
+	}
+
+	bounds := src.Bounds()
+	flattened := image.NewRGBA(bounds)
+	draw.Draw(flattened, bounds, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flattened, bounds, src, bounds.Min, draw.Over)
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, flattened, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, fmt.Errorf("failed to encode logo: %w", err)
+	}
+
+	return &pdfImage{data: out.Bytes(), width: bounds.Dx(), height: bounds.Dy()}, nil
+}
+
+// buildInvestorStatement totals an account's postings in the period by entry
+// type. Tax withheld and refund fees are credited to shared accounts, so each
+// investor's part is apportioned by their share of the entry's investor
+// postings.
+func buildInvestorStatement(account *entities.LedgerAccount, periodType string, periodStart, periodEnd time.Time, opening entities.Money, ledgerLines []*entities.StatementLedgerLine) *entities.InvestorStatement {
+	currency := account.Currency
+	zero := entities.ZeroMoney(currency)
+
+	statement := &entities.InvestorStatement{
+		ID:             uuid.New(),
+		InvestorID:     *account.OwnerID,
+		CooperativeID:  account.CooperativeID,
+		Currency:       currency,
+		PeriodType:     periodType,
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodEnd,
+		OpeningBalance: opening,
+		Investments:    zero,
+		Distributions:  zero,
+		TaxWithheld:    zero,
+		Fees:           zero,
+		Refunds:        zero,
+		Transfers:      zero,
+		Adjustments:    zero,
+		Lines:          make([]entities.InvestorStatementLine, 0, len(ledgerLines)),
+		GeneratedAt:    time.Now(),
+	}
+
+	balance := opening
+	for _, ledgerLine := range ledgerLines {
+		amount := ledgerLine.Credit.Sub(ledgerLine.Debit)
+		tax, fee := zero, zero
+
+		switch ledgerLine.EntryType {
+		case entities.JournalEntryTypeInvestment:
+			statement.Investments = statement.Investments.Add(amount)
+		case entities.JournalEntryTypeProfitDistribution:
+			statement.Distributions = statement.Distributions.Add(amount)
+			tax = apportionStatementAmount(ledgerLine.EntryTax, ledgerLine.Credit, ledgerLine.EntryInvestor)
+			statement.TaxWithheld = statement.TaxWithheld.Add(tax)
+		case entities.JournalEntryTypeRefund:
+			statement.Refunds = statement.Refunds.Sub(amount)
+			fee = apportionStatementAmount(ledgerLine.EntryFees, ledgerLine.Debit, ledgerLine.EntryInvestor)
+			statement.Fees = statement.Fees.Add(fee)
+		case entities.JournalEntryTypeStakeTransfer:
+			statement.Transfers = statement.Transfers.Add(amount)
+		default:
+			statement.Adjustments = statement.Adjustments.Add(amount)
+		}
+
+		balance = balance.Add(amount)
+		statement.Lines = append(statement.Lines, entities.InvestorStatementLine{
+			PostedAt:    ledgerLine.PostedAt,
+			EntryNumber: ledgerLine.EntryNumber,
+			EntryType:   ledgerLine.EntryType,
+			Description: ledgerLine.Description,
+			ProjectID:   ledgerLine.ProjectID,
+			Amount:      amount,
+			TaxWithheld: tax,
+			Fee:         fee,
+			Balance:     balance,
+		})
+	}
+	statement.ClosingBalance = balance
+
+	return statement
+}
+
+// apportionStatementAmount returns the part of total that corresponds to part
+// of whole
+func apportionStatementAmount(total, part, whole entities.Money) entities.Money {
+	if total.IsZero() || !whole.IsPositive() {
+		return entities.ZeroMoney(total.Currency())
+	}
+	return total.MulRat(part.Ratio(whole), entities.RoundHalfUp)
+}
+
+// statementPeriodLabel names a statement period, e.g. 2026-09, 2026-Q3 or 2026
+func statementPeriodLabel(periodType string, periodStart time.Time) string {
+	switch periodType {
+	case entities.StatementPeriodYearly:
+		return periodStart.Format("2006")
+	case entities.StatementPeriodQuarterly:
+		return fmt.Sprintf("%d-Q%d", periodStart.Year(), (int(periodStart.Month())+2)/3)
+	default:
+		return periodStart.Format("2006-01")
+	}
+}
+
+func newStatementFile(statement *entities.InvestorStatement, format string, content []byte) *entities.InvestorStatementFile {
+	contentType := "application/pdf"
+	if format == entities.StatementFormatCSV {
+		contentType = "text/csv"
+	}
+
+	return &entities.InvestorStatementFile{
+		Format: format,
+		FileName: fmt.Sprintf("statement-%s-%s-%s.%s", statementPeriodLabel(statement.PeriodType, statement.PeriodStart),
+			statement.CooperativeID.String()[:8], statement.Currency, format),
+		ContentType: contentType,
+		Content:     content,
+	}
+}
+
+// renderStatementCSV writes the statement's movements between opening and
+// closing balance rows for import into personal accounting tools
+func renderStatementCSV(statement *entities.InvestorStatement) ([]byte, error) {
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+
+	rows := [][]string{
+		{"date", "entry_number", "type", "description", "project_id", "amount", "tax_withheld", "fee", "balance", "currency"},
+		{statement.PeriodStart.Format("2006-01-02"), "", "opening_balance", "Opening balance", "", "", "", "", statement.OpeningBalance.Decimal(), statement.Currency},
+	}
+	for _, line := range statement.Lines {
+		projectID := ""
+		if line.ProjectID != nil {
+			projectID = line.ProjectID.String()
+		}
+		rows = append(rows, []string{
+			line.PostedAt.UTC().Format("2006-01-02"), line.EntryNumber, line.EntryType, line.Description, projectID,
+			line.Amount.Decimal(), line.TaxWithheld.Decimal(), line.Fee.Decimal(), line.Balance.Decimal(), statement.Currency,
+		})
+	}
+	closingDate := statement.PeriodEnd.AddDate(0, 0, -1).Format("2006-01-02")
+	rows = append(rows, []string{closingDate, "", "closing_balance", "Closing balance", "", "", "", "", statement.ClosingBalance.Decimal(), statement.Currency})
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write statement CSV: %w", err)
+	}
+
+	return out.Bytes(), nil
+}
+
+// renderStatementPDF lays out the statement under the cooperative's logo and
+// name: a summary of the period followed by its movements, continued over as
+// many pages as needed
+func renderStatementPDF(statement *entities.InvestorStatement, logo *pdfImage) []byte {
+	w := newPDFWriter()
+	right := pdfPageWidth - pdfMargin
+	y := pdfPageHeight - pdfMargin
+
+	nameX := pdfMargin
+	if logo != nil && logo.width > 0 && logo.height > 0 {
+		w.image = logo
+		height := 48.0
+		width := height * float64(logo.width) / float64(logo.height)
+		if width > 144 {
+			width, height = 144, 144*float64(logo.height)/float64(logo.width)
+		}
+		w.drawImage(pdfMargin, y-height, width, height)
+		nameX += width + 12
+	}
+	w.text(nameX, y-20, 16, true, pdfTruncate(statement.CooperativeName, right-nameX, 16))
+	w.text(nameX, y-38, 11, false, "Investor Account Statement")
+	y -= 72
+
+	periodEnd := statement.PeriodEnd.AddDate(0, 0, -1)
+	w.text(pdfMargin, y, 10, false, fmt.Sprintf("Period: %s to %s (%s)",
+		statement.PeriodStart.Format("2 Jan 2006"), periodEnd.Format("2 Jan 2006"), statement.PeriodType))
+	w.text(pdfMargin, y-14, 10, false, "Investor: "+statement.InvestorID.String())
+	w.text(pdfMargin, y-28, 10, false, "Currency: "+statement.Currency)
+	w.textRight(right, y, 10, false, "Generated "+statement.GeneratedAt.UTC().Format("2 Jan 2006"))
+	y -= 52
+
+	w.text(pdfMargin, y, 12, true, "Summary")
+	y -= 6
+	w.line(pdfMargin, y, right, y)
+	y -= 16
+	summary := []struct {
+		label  string
+		amount entities.Money
+	}{
+		{"Opening position", statement.OpeningBalance},
+		{"New investments", statement.Investments},
+		{"Distributions received (net)", statement.Distributions},
+		{"Tax withheld on distributions", statement.TaxWithheld},
+		{"Refunds", statement.Refunds},
+		{"Fees deducted from refunds", statement.Fees},
+		{"Stake transfers", statement.Transfers},
+		{"Adjustments", statement.Adjustments},
+	}
+	for _, row := range summary {
+		w.text(pdfMargin, y, 10, false, row.label)
+		w.textRight(right, y, 10, false, row.amount.String())
+		y -= 15
+	}
+	w.line(pdfMargin, y+10, right, y+10)
+	y -= 4
+	w.text(pdfMargin, y, 10, true, "Closing position")
+	w.textRight(right, y, 10, true, statement.ClosingBalance.String())
+	y -= 36
+
+	columns := []float64{pdfMargin, pdfMargin + 58, pdfMargin + 148, right - 150, right - 75, right}
+	header := func() {
+		w.text(columns[0], y, 9, true, "Date")
+		w.text(columns[1], y, 9, true, "Entry")
+		w.text(columns[2], y, 9, true, "Description")
+		w.textRight(columns[4]-6, y, 9, true, "Amount")
+		w.textRight(columns[5], y, 9, true, "Balance")
+		y -= 5
+		w.line(pdfMargin, y, right, y)
+		y -= 13
+	}
+
+	w.text(pdfMargin, y, 12, true, "Transactions")
+	y -= 20
+	header()
+	if len(statement.Lines) == 0 {
+		w.text(pdfMargin, y, 9, false, "No transactions in this period")
+	}
+	for _, line := range statement.Lines {
+		if y < pdfMargin+20 {
+			w.addPage()
+			y = pdfPageHeight - pdfMargin
+			header()
+		}
+
+		description := strings.ReplaceAll(line.EntryType, "_", " ")
+		if line.Description != "" {
+			description = line.Description
+		}
+		if !line.TaxWithheld.IsZero() {
+			description += " (tax " + line.TaxWithheld.Decimal() + ")"
+		}
+		if !line.Fee.IsZero() {
+			description += " (fee " + line.Fee.Decimal() + ")"
+		}
+
+		w.text(columns[0], y, 8, false, line.PostedAt.UTC().Format("02 Jan 2006"))
+		w.text(columns[1], y, 8, false, pdfTruncate(line.EntryNumber, columns[2]-columns[1]-6, 8))
+		w.text(columns[2], y, 8, false, pdfTruncate(description, columns[3]-columns[2]+40, 8))
+		w.textRight(columns[4]-6, y, 8, false, line.Amount.Decimal())
+		w.textRight(columns[5], y, 8, false, line.Balance.Decimal())
+		y -= 13
+	}
+
+	for i, page := range w.pages {
+		w.current = page
+		w.textRight(right, pdfMargin-20, 8, false, fmt.Sprintf("Page %d of %d", i+1, len(w.pages)))
+	}
+
+	return w.bytes()
+}
+
+// InvestorStatementScheduler periodically generates the statements of
+// completed periods
+type InvestorStatementScheduler struct {
+	*intervalScheduler
+	statementService InvestorStatementService
+}
+
+// NewInvestorStatementScheduler creates a scheduler that runs every interval
+func NewInvestorStatementScheduler(statementService InvestorStatementService, interval time.Duration) *InvestorStatementScheduler {
+	s := &InvestorStatementScheduler{statementService: statementService}
+	s.intervalScheduler = newIntervalScheduler("Investor statement", interval, s.runOnce)
+	return s
+}
+
+func (s *InvestorStatementScheduler) runOnce(ctx context.Context) error {
+	runs, err := s.statementService.GenerateDueStatements(ctx, time.Now())
+
+	for _, run := range runs {
+		for _, failure := range run.Failures {
+			log.Printf("Failed to generate %s statement of investor %s with cooperative %s: %s",
+				run.PeriodType, failure.InvestorID, failure.CooperativeID, failure.Error)
+		}
+		if run.Generated > 0 {
+			log.Printf("Generated %d %s investor statements from %s", run.Generated, run.PeriodType, run.PeriodStart.Format("2006-01-02"))
+		}
+	}
+	return err
+}
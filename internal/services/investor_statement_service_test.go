@@ -0,0 +1,206 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementPeriod(t *testing.T) {
+	at := time.Date(2026, time.August, 17, 9, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		periodType string
+		start, end time.Time
+		label      string
+	}{
+		{entities.StatementPeriodMonthly, time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC), "2026-08"},
+		{entities.StatementPeriodQuarterly, time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), "2026-Q3"},
+		{entities.StatementPeriodYearly, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), "2026"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.periodType, func(t *testing.T) {
+			start, end := entities.StatementPeriod(tc.periodType, at)
+			assert.Equal(t, tc.start, start)
+			assert.Equal(t, tc.end, end)
+			assert.Equal(t, tc.label, statementPeriodLabel(tc.periodType, start))
+		})
+	}
+}
+
+func TestBuildInvestorStatement_TotalsAndApportionsTaxAndFees(t *testing.T) {
+	investorID := uuid.New()
+	account := &entities.LedgerAccount{ID: uuid.New(), CooperativeID: uuid.New(), Category: entities.LedgerAccountCategoryInvestor, OwnerID: &investorID, Currency: "IDR"}
+	start, end := entities.StatementPeriod(entities.StatementPeriodMonthly, time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC))
+	projectID := uuid.New()
+
+	lines := []*entities.StatementLedgerLine{
+		{PostedAt: start.AddDate(0, 0, 2), EntryNumber: "JE-1", EntryType: entities.JournalEntryTypeInvestment, ProjectID: &projectID,
+			Debit: idr("0"), Credit: idr("5000"), EntryTax: idr("0"), EntryFees: idr("0"), EntryInvestor: idr("5000")},
+		// The investor received 900 of 1350 net distributed; 150 tax was withheld in total
+		{PostedAt: start.AddDate(0, 0, 10), EntryNumber: "JE-2", EntryType: entities.JournalEntryTypeProfitDistribution, ProjectID: &projectID,
+			Debit: idr("0"), Credit: idr("900"), EntryTax: idr("150"), EntryFees: idr("0"), EntryInvestor: idr("1350")},
+		// A 1000 refund of which the investor's processing fee was 20
+		{PostedAt: start.AddDate(0, 0, 20), EntryNumber: "JE-3", EntryType: entities.JournalEntryTypeRefund, ProjectID: &projectID,
+			Debit: idr("1000"), Credit: idr("0"), EntryTax: idr("0"), EntryFees: idr("20"), EntryInvestor: idr("1000")},
+		{PostedAt: start.AddDate(0, 0, 25), EntryNumber: "JE-4", EntryType: entities.JournalEntryTypeStakeTransfer, ProjectID: &projectID,
+			Debit: idr("500"), Credit: idr("0"), EntryTax: idr("0"), EntryFees: idr("0"), EntryInvestor: idr("1000")},
+	}
+
+	statement := buildInvestorStatement(account, entities.StatementPeriodMonthly, start, end, idr("2000"), lines)
+
+	assert.Equal(t, *account.OwnerID, statement.InvestorID)
+	assert.Equal(t, idr("2000"), statement.OpeningBalance)
+	assert.Equal(t, idr("5000"), statement.Investments)
+	assert.Equal(t, idr("900"), statement.Distributions)
+	assert.Equal(t, idr("100"), statement.TaxWithheld)
+	assert.Equal(t, idr("1000"), statement.Refunds)
+	assert.Equal(t, idr("20"), statement.Fees)
+	assert.Equal(t, idr("-500"), statement.Transfers)
+	assert.Equal(t, idr("0"), statement.Adjustments)
+	assert.Equal(t, idr("6400"), statement.ClosingBalance)
+	require.Len(t, statement.Lines, 4)
+	assert.Equal(t, idr("7900"), statement.Lines[1].Balance)
+	assert.Equal(t, idr("100"), statement.Lines[1].TaxWithheld)
+	assert.Equal(t, idr("-1000"), statement.Lines[2].Amount)
+}
+
+func TestRenderStatementFiles(t *testing.T) {
+	investorID := uuid.New()
+	account := &entities.LedgerAccount{ID: uuid.New(), CooperativeID: uuid.New(), Category: entities.LedgerAccountCategoryInvestor, OwnerID: &investorID, Currency: "IDR"}
+	start, end := entities.StatementPeriod(entities.StatementPeriodMonthly, time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC))
+
+	var lines []*entities.StatementLedgerLine
+	for i := 0; i < 80; i++ {
+		lines = append(lines, &entities.StatementLedgerLine{
+			PostedAt: start.AddDate(0, 0, i%28), EntryNumber: "JE-" + uuid.NewString()[:8], EntryType: entities.JournalEntryTypeInvestment,
+			Description: "Investment (Koperasi Maju)", Debit: idr("0"), Credit: idr("100"),
+			EntryTax: idr("0"), EntryFees: idr("0"), EntryInvestor: idr("100"),
+		})
+	}
+	statement := buildInvestorStatement(account, entities.StatementPeriodMonthly, start, end, idr("0"), lines)
+	statement.CooperativeName = "Koperasi Maju Bersama"
+
+	logoImage := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	logoImage.Set(1, 1, color.RGBA{R: 200, A: 255})
+	var logoPNG bytes.Buffer
+	require.NoError(t, png.Encode(&logoPNG, logoImage))
+	logo, err := statementLogo(logoPNG.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, 4, logo.width)
+
+	pdf := string(renderStatementPDF(statement, logo))
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, "(Koperasi Maju Bersama) Tj")
+	assert.Contains(t, pdf, "\\(Koperasi Maju\\)")
+	assert.Contains(t, pdf, "/Filter /DCTDecode")
+	assert.Contains(t, pdf, "/Count 2")
+
+	csvContent, err := renderStatementCSV(statement)
+	require.NoError(t, err)
+	rows := strings.Split(strings.TrimSpace(string(csvContent)), "\n")
+	require.Len(t, rows, 83)
+	assert.Equal(t, "date,entry_number,type,description,project_id,amount,tax_withheld,fee,balance,currency", rows[0])
+	assert.Equal(t, "2026-09-30,,closing_balance,Closing balance,,,,,8000.00,IDR", rows[82])
+}
+
+func TestInvestorStatementService_GenerateDueStatements_SkipsArchivedAndInactive(t *testing.T) {
+	statementRepo := new(MockInvestorStatementRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service := NewInvestorStatementService(statementRepo, mockAuditService).(*investorStatementService)
+	service.fetchLogo = func(ctx context.Context, url string) ([]byte, error) {
+		return nil, errors.New("offline")
+	}
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 18, 3, 0, 0, 0, time.UTC)
+
+	activeInvestorID, inactiveInvestorID := uuid.New(), uuid.New()
+	active := &entities.LedgerAccount{ID: uuid.New(), CooperativeID: uuid.New(), Category: entities.LedgerAccountCategoryInvestor, OwnerID: &activeInvestorID, Currency: "IDR"}
+	inactive := &entities.LedgerAccount{ID: uuid.New(), CooperativeID: uuid.New(), Category: entities.LedgerAccountCategoryInvestor, OwnerID: &inactiveInvestorID, Currency: "IDR"}
+	monthStart := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	statementRepo.On("ListInvestorAccounts", ctx, mock.AnythingOfType("time.Time")).Return([]*entities.LedgerAccount{active, inactive}, nil)
+	statementRepo.On("StatementExists", ctx, mock.Anything, entities.StatementPeriodMonthly, monthStart).Return(false, nil)
+	// Last quarter's and last year's statements were archived earlier
+	statementRepo.On("StatementExists", ctx, mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	statementRepo.On("GetAccountPosition", ctx, active, monthStart).Return(idr("1000"), nil)
+	statementRepo.On("GetAccountPosition", ctx, inactive, monthStart).Return(idr("0"), nil)
+	statementRepo.On("GetStatementLines", ctx, active, monthStart, monthEnd).Return([]*entities.StatementLedgerLine{}, nil)
+	statementRepo.On("GetStatementLines", ctx, inactive, monthStart, monthEnd).Return([]*entities.StatementLedgerLine{}, nil)
+	statementRepo.On("GetStatementBranding", ctx, active.CooperativeID).Return(&entities.StatementBranding{
+		CooperativeName: "Koperasi Maju", LogoURL: "https://example.com/logo.png",
+	}, nil)
+	var archived *entities.InvestorStatement
+	statementRepo.On("CreateStatement", ctx, mock.AnythingOfType("*entities.InvestorStatement")).Run(func(args mock.Arguments) {
+		archived = args.Get(1).(*entities.InvestorStatement)
+	}).Return(true, nil)
+
+	runs, err := service.GenerateDueStatements(ctx, now)
+
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+	assert.Equal(t, monthStart, runs[0].PeriodStart)
+	assert.Equal(t, 1, runs[0].Generated)
+	assert.Equal(t, 1, runs[0].Skipped)
+	assert.Equal(t, time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC), runs[1].PeriodStart)
+	assert.Equal(t, 2, runs[1].Skipped)
+	assert.Equal(t, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), runs[2].PeriodStart)
+
+	require.NotNil(t, archived)
+	assert.Equal(t, "Koperasi Maju", archived.CooperativeName)
+	assert.Equal(t, idr("1000"), archived.ClosingBalance)
+	require.Len(t, archived.Files, 2)
+	assert.Equal(t, "application/pdf", archived.Files[0].ContentType)
+	assert.Contains(t, archived.Files[1].FileName, "statement-2026-09-")
+}
+
+func TestInvestorStatementService_GetStatementFile_OwnStatementsOnly(t *testing.T) {
+	statementRepo := new(MockInvestorStatementRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service := NewInvestorStatementService(statementRepo, mockAuditService).(*investorStatementService)
+	service.fetchLogo = func(ctx context.Context, url string) ([]byte, error) {
+		return nil, errors.New("offline")
+	}
+	ctx := context.Background()
+
+	statement := &entities.InvestorStatement{
+		ID:            uuid.New(),
+		InvestorID:    uuid.New(),
+		CooperativeID: uuid.New(),
+		Currency:      "IDR",
+		PeriodType:    entities.StatementPeriodYearly,
+		PeriodStart:   time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Files: []*entities.InvestorStatementFile{
+			{Format: entities.StatementFormatPDF, Content: []byte("%PDF-1.4")},
+			{Format: entities.StatementFormatCSV, Content: []byte("date")},
+		},
+	}
+	statementRepo.On("GetStatement", ctx, statement.ID).Return(statement, nil)
+
+	_, err := service.GetStatementFile(ctx, statement.ID, uuid.New(), entities.StatementFormatCSV)
+	assert.Error(t, err)
+
+	file, err := service.GetStatementFile(ctx, statement.ID, statement.InvestorID, entities.StatementFormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv", file.ContentType)
+	assert.Equal(t, "statement-2025-"+statement.CooperativeID.String()[:8]+"-IDR.csv", file.FileName)
+	assert.Equal(t, []byte("date"), file.Content)
+}
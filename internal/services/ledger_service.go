@@ -0,0 +1,784 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// LedgerService maintains the cooperative double-entry ledger. Every money
+// movement is posted as a balanced journal entry and all fund balances are
+// derived from the posted lines rather than stored separately.
+type LedgerService interface {
+	// Accounts
+	GetOrCreateAccount(ctx context.Context, cooperativeID uuid.UUID, category string, ownerID *uuid.UUID, currency string) (*entities.LedgerAccount, error)
+	ListAccounts(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.LedgerAccount, error)
+
+	// Journal entries
+	PostJournalEntry(ctx context.Context, req *entities.PostJournalEntryRequest, posterID uuid.UUID) (*entities.JournalEntry, error)
+	GetJournalEntry(ctx context.Context, entryID uuid.UUID) (*entities.JournalEntry, error)
+	GetAccountEntries(ctx context.Context, accountID uuid.UUID, page, limit int) ([]*entities.JournalEntry, int, error)
+
+	// Money movements
+	RecordInvestment(ctx context.Context, investment *entities.InvestmentExtended, cooperativeID, posterID uuid.UUID) (*entities.JournalEntry, error)
+	RecordDisbursement(ctx context.Context, disbursement *entities.FundDisbursement, posterID uuid.UUID) (*entities.JournalEntry, error)
+	RecordRefund(ctx context.Context, refund *entities.FundRefund, investorRefunds []*entities.InvestorRefund, posterID uuid.UUID) (*entities.JournalEntry, error)
+	RecordProfitDistribution(ctx context.Context, distribution *entities.ProfitDistributionExtended, shares []*entities.InvestorProfitShare, posterID uuid.UUID) (*entities.JournalEntry, error)
+	RecordProfitSharePayouts(ctx context.Context, distribution *entities.ProfitDistributionExtended, shares []*entities.InvestorProfitShare, posterID uuid.UUID) (*entities.JournalEntry, error)
+	RecordPayoutReturned(ctx context.Context, item *entities.PayoutItem, posterID uuid.UUID) (*entities.JournalEntry, error)
+	RecordPayoutRequeued(ctx context.Context, failed, retry *entities.PayoutItem, posterID uuid.UUID) (*entities.JournalEntry, error)
+	RecordFeeCollection(ctx context.Context, fee *entities.ProjectFeeCalculation, posterID uuid.UUID) (*entities.JournalEntry, error)
+	PrepareFeeInvoiceEntry(ctx context.Context, invoice *entities.FeeInvoice, posterID uuid.UUID) (*entities.JournalEntry, error)
+	RecordStakeTransfer(ctx context.Context, trade *entities.StakeTrade, posterID uuid.UUID) (*entities.JournalEntry, error)
+
+	// Balances
+	GetAccountBalance(ctx context.Context, accountID uuid.UUID, asOf *time.Time) (*entities.LedgerAccountBalance, error)
+	GetEscrowBalances(ctx context.Context, cooperativeID uuid.UUID) ([]entities.Money, error)
+	GetProjectEscrowBalance(ctx context.Context, projectID uuid.UUID, currency string) (entities.Money, error)
+	GetProjectEscrowBalances(ctx context.Context, projectID uuid.UUID) ([]entities.Money, error)
+	GetTrialBalance(ctx context.Context, cooperativeID uuid.UUID) (*entities.TrialBalance, error)
+}
+
+// DefaultLedgerCurrency is used when a movement does not carry a currency
+const DefaultLedgerCurrency = "IDR"
+
+// ledgerService implements LedgerService
+type ledgerService struct {
+	ledgerRepo   repositories.LedgerRepository
+	auditService AuditService
+}
+
+// NewLedgerService creates a new ledger service
+func NewLedgerService(ledgerRepo repositories.LedgerRepository, auditService AuditService) LedgerService {
+	return &ledgerService{
+		ledgerRepo:   ledgerRepo,
+		auditService: auditService,
+	}
+}
+
+// GetOrCreateAccount returns the ledger account for a category and owner, opening it on first use
+func (s *ledgerService) GetOrCreateAccount(ctx context.Context, cooperativeID uuid.UUID, category string, ownerID *uuid.UUID, currency string) (*entities.LedgerAccount, error) {
+	accountType := entities.LedgerAccountTypeForCategory(category)
+	if accountType == "" {
+		return nil, fmt.Errorf("unknown ledger account category: %s", category)
+	}
+	if currency == "" {
+		currency = DefaultLedgerCurrency
+	}
+
+	code, name := ledgerAccountCode(category, ownerID)
+
+	account, err := s.ledgerRepo.GetAccountByCode(ctx, cooperativeID, code, currency)
+	if err == nil {
+		return account, nil
+	}
+	if !errors.Is(err, repositories.ErrLedgerAccountNotFound) {
+		return nil, err
+	}
+
+	account = &entities.LedgerAccount{
+		ID:            uuid.New(),
+		CooperativeID: cooperativeID,
+		Code:          code,
+		Name:          name,
+		AccountType:   accountType,
+		Category:      category,
+		OwnerID:       ownerID,
+		Currency:      currency,
+		IsActive:      true,
+	}
+
+	if err := s.ledgerRepo.CreateAccount(ctx, account); err != nil {
+		// Another posting opened the account first; use theirs
+		if errors.Is(err, repositories.ErrLedgerAccountExists) {
+			return s.ledgerRepo.GetAccountByCode(ctx, cooperativeID, code, currency)
+		}
+		return nil, fmt.Errorf("failed to open ledger account: %w", err)
+	}
+
+	return account, nil
+}
+
+// ListAccounts lists all ledger accounts of a cooperative
+func (s *ledgerService) ListAccounts(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.LedgerAccount, error) {
+	return s.ledgerRepo.ListAccounts(ctx, cooperativeID)
+}
+
+// PostJournalEntry validates and posts a journal entry atomically
+func (s *ledgerService) PostJournalEntry(ctx context.Context, req *entities.PostJournalEntryRequest, posterID uuid.UUID) (*entities.JournalEntry, error) {
+	return s.postJournalEntry(ctx, req, nil, posterID)
+}
+
+// postJournalEntry posts a journal entry. When fundingAccountID is set the
+// entry draws on the project's funds in that account and is rejected unless
+// they cover it.
+func (s *ledgerService) postJournalEntry(ctx context.Context, req *entities.PostJournalEntryRequest, fundingAccountID *uuid.UUID, posterID uuid.UUID) (*entities.JournalEntry, error) {
+	entry, err := s.newJournalEntry(req, posterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if fundingAccountID != nil {
+		err = s.ledgerRepo.PostFundedEntry(ctx, entry, *fundingAccountID)
+	} else {
+		err = s.ledgerRepo.PostEntry(ctx, entry)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to post journal entry: %w", err)
+	}
+
+	s.logJournalEntry(ctx, entry)
+
+	return entry, nil
+}
+
+// newJournalEntry validates a journal entry request and builds the entry to post
+func (s *ledgerService) newJournalEntry(req *entities.PostJournalEntryRequest, posterID uuid.UUID) (*entities.JournalEntry, error) {
+	if len(req.Lines) < 2 {
+		return nil, errors.New("journal entry requires at least two lines")
+	}
+
+	now := time.Now()
+	entry := &entities.JournalEntry{
+		ID:            uuid.New(),
+		EntryNumber:   s.generateEntryNumber(),
+		CooperativeID: req.CooperativeID,
+		ProjectID:     req.ProjectID,
+		EntryType:     req.EntryType,
+		ReferenceType: req.ReferenceType,
+		ReferenceID:   req.ReferenceID,
+		Description:   req.Description,
+		Currency:      req.Currency,
+		PostedBy:      posterID,
+		PostedAt:      now,
+		CreatedAt:     now,
+	}
+
+	totalDebit, totalCredit := entities.ZeroMoney(req.Currency), entities.ZeroMoney(req.Currency)
+	for i, line := range req.Lines {
+		// Lines take the entry's currency; a line in another currency is not relabelled
+		debit, err := line.Debit.InCurrency(req.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		credit, err := line.Credit.InCurrency(req.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if debit.IsNegative() || credit.IsNegative() {
+			return nil, fmt.Errorf("line %d: amounts cannot be negative", i+1)
+		}
+		if debit.IsZero() == credit.IsZero() {
+			return nil, fmt.Errorf("line %d: exactly one of debit or credit must be set", i+1)
+		}
+
+		totalDebit = totalDebit.Add(debit)
+		totalCredit = totalCredit.Add(credit)
+
+		entry.Lines = append(entry.Lines, entities.JournalLine{
+			ID:        uuid.New(),
+			EntryID:   entry.ID,
+			AccountID: line.AccountID,
+			Debit:     debit,
+			Credit:    credit,
+			Memo:      line.Memo,
+			CreatedAt: now,
+		})
+	}
+
+	if !totalDebit.Equal(totalCredit) {
+		return nil, fmt.Errorf("journal entry is unbalanced: debits %s, credits %s", totalDebit.Decimal(), totalCredit.Decimal())
+	}
+	entry.TotalAmount = totalDebit
+
+	return entry, nil
+}
+
+// logJournalEntry records a posted entry in the audit trail
+func (s *ledgerService) logJournalEntry(ctx context.Context, entry *entities.JournalEntry) {
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     entry.PostedBy,
+		Operation:  "post_journal_entry",
+		EntityType: "journal_entry",
+		EntityID:   entry.ID,
+		NewValues:  fmt.Sprintf("Posted %s entry %s for %s", entry.EntryType, entry.EntryNumber, entry.TotalAmount),
+	})
+}
+
+// GetJournalEntry gets a journal entry with its lines
+func (s *ledgerService) GetJournalEntry(ctx context.Context, entryID uuid.UUID) (*entities.JournalEntry, error) {
+	return s.ledgerRepo.GetEntryByID(ctx, entryID)
+}
+
+// GetAccountEntries gets the journal entries touching an account
+func (s *ledgerService) GetAccountEntries(ctx context.Context, accountID uuid.UUID, page, limit int) ([]*entities.JournalEntry, int, error) {
+	account, err := s.ledgerRepo.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	return s.ledgerRepo.GetEntriesByAccount(ctx, account, limit, (page-1)*limit)
+}
+
+// RecordInvestment posts an investment received into escrow: Dr escrow, Cr investor
+func (s *ledgerService) RecordInvestment(ctx context.Context, investment *entities.InvestmentExtended, cooperativeID, posterID uuid.UUID) (*entities.JournalEntry, error) {
+	if !investment.Amount.IsPositive() {
+		return nil, errors.New("investment amount must be greater than zero")
+	}
+
+	escrow, err := s.GetOrCreateAccount(ctx, cooperativeID, entities.LedgerAccountCategoryEscrow, nil, investment.Currency)
+	if err != nil {
+		return nil, err
+	}
+	investor, err := s.GetOrCreateAccount(ctx, cooperativeID, entities.LedgerAccountCategoryInvestor, &investment.InvestorID, investment.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	projectID := investment.ProjectID
+	return s.PostJournalEntry(ctx, &entities.PostJournalEntryRequest{
+		CooperativeID: cooperativeID,
+		ProjectID:     &projectID,
+		EntryType:     entities.JournalEntryTypeInvestment,
+		ReferenceType: "investment",
+		ReferenceID:   investment.ID,
+		Description:   fmt.Sprintf("Investment %s received into escrow", investment.ID),
+		Currency:      escrow.Currency,
+		Lines: []entities.PostJournalLineRequest{
+			{AccountID: escrow.ID, Debit: investment.Amount, Memo: "Investment funds received"},
+			{AccountID: investor.ID, Credit: investment.Amount, Memo: "Investor capital"},
+		},
+	}, posterID)
+}
+
+// RecordDisbursement posts funds released from escrow to a business: Dr business, Cr escrow
+func (s *ledgerService) RecordDisbursement(ctx context.Context, disbursement *entities.FundDisbursement, posterID uuid.UUID) (*entities.JournalEntry, error) {
+	if !disbursement.DisbursementAmount.IsPositive() {
+		return nil, errors.New("disbursement amount must be greater than zero")
+	}
+
+	escrow, err := s.GetOrCreateAccount(ctx, disbursement.CooperativeID, entities.LedgerAccountCategoryEscrow, nil, disbursement.Currency)
+	if err != nil {
+		return nil, err
+	}
+	business, err := s.GetOrCreateAccount(ctx, disbursement.CooperativeID, entities.LedgerAccountCategoryBusiness, &disbursement.BusinessID, disbursement.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// The project's escrow balance is checked while the entry is posted, under
+	// a lock on the escrow account
+	projectID := disbursement.ProjectID
+	return s.postJournalEntry(ctx, &entities.PostJournalEntryRequest{
+		CooperativeID: disbursement.CooperativeID,
+		ProjectID:     &projectID,
+		EntryType:     entities.JournalEntryTypeDisbursement,
+		ReferenceType: "fund_disbursement",
+		ReferenceID:   disbursement.ID,
+		Description:   fmt.Sprintf("Disbursement %s to business %s", disbursement.ID, disbursement.BusinessID),
+		Currency:      escrow.Currency,
+		Lines: []entities.PostJournalLineRequest{
+			{AccountID: business.ID, Debit: disbursement.DisbursementAmount, Memo: "Funds disbursed to business"},
+			{AccountID: escrow.ID, Credit: disbursement.DisbursementAmount, Memo: "Released from escrow"},
+		},
+	}, &escrow.ID, posterID)
+}
+
+// RecordRefund posts investor refunds out of escrow: Dr investor, Cr escrow and Cr platform fee for processing fees.
+// A refund is posted once; posting it again fails with repositories.ErrFundRefundPosted.
+func (s *ledgerService) RecordRefund(ctx context.Context, refund *entities.FundRefund, investorRefunds []*entities.InvestorRefund, posterID uuid.UUID) (*entities.JournalEntry, error) {
+	if len(investorRefunds) == 0 {
+		return nil, errors.New("refund has no investor refunds")
+	}
+
+	escrow, err := s.GetOrCreateAccount(ctx, refund.CooperativeID, entities.LedgerAccountCategoryEscrow, nil, refund.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []entities.PostJournalLineRequest
+	totalNet, totalFees := entities.ZeroMoney(escrow.Currency), entities.ZeroMoney(escrow.Currency)
+	for _, ir := range investorRefunds {
+		investorID := ir.InvestorID
+		investor, err := s.GetOrCreateAccount(ctx, refund.CooperativeID, entities.LedgerAccountCategoryInvestor, &investorID, refund.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, entities.PostJournalLineRequest{
+			AccountID: investor.ID,
+			Debit:     ir.RefundAmount,
+			Memo:      fmt.Sprintf("Refund of investment %s", ir.InvestmentID),
+		})
+		totalNet = totalNet.Add(ir.NetRefundAmount)
+		totalFees = totalFees.Add(ir.ProcessingFee)
+	}
+
+	lines = append(lines, entities.PostJournalLineRequest{AccountID: escrow.ID, Credit: totalNet, Memo: "Refunds paid from escrow"})
+	if totalFees.IsPositive() {
+		platformFee, err := s.GetOrCreateAccount(ctx, refund.CooperativeID, entities.LedgerAccountCategoryPlatformFee, nil, refund.Currency)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, entities.PostJournalLineRequest{AccountID: platformFee.ID, Credit: totalFees, Memo: "Refund processing fees"})
+	}
+
+	// Refunds draw on the project's escrow balance, which is checked while the
+	// entry is posted
+	projectID := refund.ProjectID
+	return s.postJournalEntry(ctx, &entities.PostJournalEntryRequest{
+		CooperativeID: refund.CooperativeID,
+		ProjectID:     &projectID,
+		EntryType:     entities.JournalEntryTypeRefund,
+		ReferenceType: "fund_refund",
+		ReferenceID:   refund.ID,
+		Description:   fmt.Sprintf("Refund %s (%s)", refund.ID, refund.RefundType),
+		Currency:      escrow.Currency,
+		Lines:         lines,
+	}, &escrow.ID, posterID)
+}
+
+// RecordStakeTransfer moves traded capital between members' investor accounts:
+// Dr seller, Cr buyer. The price is paid between the members and does not pass
+// through escrow.
+func (s *ledgerService) RecordStakeTransfer(ctx context.Context, trade *entities.StakeTrade, posterID uuid.UUID) (*entities.JournalEntry, error) {
+	if !trade.Amount.IsPositive() {
+		return nil, errors.New("transferred stake must be greater than zero")
+	}
+
+	seller, err := s.GetOrCreateAccount(ctx, trade.CooperativeID, entities.LedgerAccountCategoryInvestor, &trade.SellerID, trade.Currency)
+	if err != nil {
+		return nil, err
+	}
+	buyer, err := s.GetOrCreateAccount(ctx, trade.CooperativeID, entities.LedgerAccountCategoryInvestor, &trade.BuyerID, trade.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	projectID := trade.ProjectID
+	return s.PostJournalEntry(ctx, &entities.PostJournalEntryRequest{
+		CooperativeID: trade.CooperativeID,
+		ProjectID:     &projectID,
+		EntryType:     entities.JournalEntryTypeStakeTransfer,
+		ReferenceType: "stake_trade",
+		ReferenceID:   trade.ID,
+		Description:   fmt.Sprintf("Stake of investment %s sold to %s", trade.SellerInvestmentID, trade.BuyerID),
+		Currency:      seller.Currency,
+		Lines: []entities.PostJournalLineRequest{
+			{AccountID: seller.ID, Debit: trade.Amount, Memo: "Stake sold"},
+			{AccountID: buyer.ID, Credit: trade.Amount, Memo: "Stake bought"},
+		},
+	}, posterID)
+}
+
+// RecordProfitDistribution posts profit received into escrow for investors: Dr escrow, Cr investor (net) and Cr tax payable
+func (s *ledgerService) RecordProfitDistribution(ctx context.Context, distribution *entities.ProfitDistributionExtended, shares []*entities.InvestorProfitShare, posterID uuid.UUID) (*entities.JournalEntry, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("profit distribution has no investor shares")
+	}
+
+	escrow, err := s.GetOrCreateAccount(ctx, distribution.CooperativeID, entities.LedgerAccountCategoryEscrow, nil, distribution.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []entities.PostJournalLineRequest
+	totalGross, totalTax := entities.ZeroMoney(escrow.Currency), entities.ZeroMoney(escrow.Currency)
+	for _, share := range shares {
+		investorID := share.InvestorID
+		investor, err := s.GetOrCreateAccount(ctx, distribution.CooperativeID, entities.LedgerAccountCategoryInvestor, &investorID, distribution.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, entities.PostJournalLineRequest{
+			AccountID: investor.ID,
+			Credit:    share.NetProfitShare,
+			Memo:      fmt.Sprintf("Profit share for investment %s", share.InvestmentID),
+		})
+		totalGross = totalGross.Add(share.NetProfitShare).Add(share.TaxAmount)
+		totalTax = totalTax.Add(share.TaxAmount)
+	}
+
+	if totalTax.IsPositive() {
+		taxPayable, err := s.GetOrCreateAccount(ctx, distribution.CooperativeID, entities.LedgerAccountCategoryTaxPayable, nil, distribution.Currency)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, entities.PostJournalLineRequest{AccountID: taxPayable.ID, Credit: totalTax, Memo: "Tax withheld on profit shares"})
+	}
+	lines = append([]entities.PostJournalLineRequest{{AccountID: escrow.ID, Debit: totalGross, Memo: "Profit received for distribution"}}, lines...)
+
+	projectID := distribution.ProjectID
+	return s.PostJournalEntry(ctx, &entities.PostJournalEntryRequest{
+		CooperativeID: distribution.CooperativeID,
+		ProjectID:     &projectID,
+		EntryType:     entities.JournalEntryTypeProfitDistribution,
+		ReferenceType: "profit_distribution",
+		ReferenceID:   distribution.ID,
+		Description:   fmt.Sprintf("Profit distribution %s", distribution.ID),
+		Currency:      escrow.Currency,
+		Lines:         lines,
+	}, posterID)
+}
+
+// RecordProfitSharePayouts posts the profit shares paid out to investors from
+// escrow: Dr investor, Cr escrow. Reinvested parts of the shares stay in escrow.
+func (s *ledgerService) RecordProfitSharePayouts(ctx context.Context, distribution *entities.ProfitDistributionExtended, shares []*entities.InvestorProfitShare, posterID uuid.UUID) (*entities.JournalEntry, error) {
+	escrow, err := s.GetOrCreateAccount(ctx, distribution.CooperativeID, entities.LedgerAccountCategoryEscrow, nil, distribution.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []entities.PostJournalLineRequest
+	total := entities.ZeroMoney(escrow.Currency)
+	for _, share := range shares {
+		amount := share.PayableAmount()
+		if !amount.IsPositive() {
+			continue
+		}
+
+		investorID := share.InvestorID
+		investor, err := s.GetOrCreateAccount(ctx, distribution.CooperativeID, entities.LedgerAccountCategoryInvestor, &investorID, distribution.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, entities.PostJournalLineRequest{
+			AccountID: investor.ID,
+			Debit:     amount,
+			Memo:      fmt.Sprintf("Profit share paid for investment %s", share.InvestmentID),
+		})
+		total = total.Add(amount)
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("profit distribution has no investor shares to pay out")
+	}
+	lines = append(lines, entities.PostJournalLineRequest{AccountID: escrow.ID, Credit: total, Memo: "Profit shares paid from escrow"})
+
+	projectID := distribution.ProjectID
+	return s.postJournalEntry(ctx, &entities.PostJournalEntryRequest{
+		CooperativeID: distribution.CooperativeID,
+		ProjectID:     &projectID,
+		EntryType:     entities.JournalEntryTypeProfitDistribution,
+		ReferenceType: "profit_distribution_payout",
+		ReferenceID:   distribution.ID,
+		Description:   fmt.Sprintf("Profit distribution %s paid out", distribution.ID),
+		Currency:      escrow.Currency,
+		Lines:         lines,
+	}, &escrow.ID, posterID)
+}
+
+// RecordPayoutReturned posts a payout the bank returned back into escrow, where
+// it is owed to its beneficiary until it is paid again: Dr escrow, Cr payouts pending
+func (s *ledgerService) RecordPayoutReturned(ctx context.Context, item *entities.PayoutItem, posterID uuid.UUID) (*entities.JournalEntry, error) {
+	escrow, pending, err := s.payoutAccounts(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.PostJournalEntry(ctx, &entities.PostJournalEntryRequest{
+		CooperativeID: item.CooperativeID,
+		EntryType:     payoutEntryType(item.PayoutType),
+		ReferenceType: "payout_item",
+		ReferenceID:   item.ID,
+		Description:   fmt.Sprintf("Payout %s returned by bank: %s", item.Reference, item.FailureReason),
+		Currency:      escrow.Currency,
+		Lines: []entities.PostJournalLineRequest{
+			{AccountID: escrow.ID, Debit: item.Amount, Memo: "Returned payout back in escrow"},
+			{AccountID: pending.ID, Credit: item.Amount, Memo: "Payout owed to beneficiary"},
+		},
+	}, posterID)
+}
+
+// RecordPayoutRequeued posts a returned payout queued to be paid again out of
+// escrow: Dr payouts pending, Cr escrow
+func (s *ledgerService) RecordPayoutRequeued(ctx context.Context, failed, retry *entities.PayoutItem, posterID uuid.UUID) (*entities.JournalEntry, error) {
+	escrow, pending, err := s.payoutAccounts(ctx, retry)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.PostJournalEntry(ctx, &entities.PostJournalEntryRequest{
+		CooperativeID: retry.CooperativeID,
+		EntryType:     payoutEntryType(retry.PayoutType),
+		ReferenceType: "payout_item",
+		ReferenceID:   retry.ID,
+		Description:   fmt.Sprintf("Returned payout %s queued again as %s", failed.Reference, retry.Reference),
+		Currency:      escrow.Currency,
+		Lines: []entities.PostJournalLineRequest{
+			{AccountID: pending.ID, Debit: retry.Amount, Memo: "Payout owed to beneficiary"},
+			{AccountID: escrow.ID, Credit: retry.Amount, Memo: "Payout queued from escrow"},
+		},
+	}, posterID)
+}
+
+// payoutAccounts returns the escrow and payouts pending accounts in a payout's currency
+func (s *ledgerService) payoutAccounts(ctx context.Context, item *entities.PayoutItem) (*entities.LedgerAccount, *entities.LedgerAccount, error) {
+	escrow, err := s.GetOrCreateAccount(ctx, item.CooperativeID, entities.LedgerAccountCategoryEscrow, nil, item.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	pending, err := s.GetOrCreateAccount(ctx, item.CooperativeID, entities.LedgerAccountCategoryPayoutsPending, nil, item.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	return escrow, pending, nil
+}
+
+// payoutEntryType returns the journal entry type of the money movement a payout pays out
+func payoutEntryType(payoutType string) string {
+	switch payoutType {
+	case entities.PayoutTypeDisbursement:
+		return entities.JournalEntryTypeDisbursement
+	case entities.PayoutTypeRefund:
+		return entities.JournalEntryTypeRefund
+	case entities.PayoutTypeProfitShare:
+		return entities.JournalEntryTypeProfitDistribution
+	default:
+		return entities.JournalEntryTypeAdjustment
+	}
+}
+
+// RecordFeeCollection posts a platform fee charged to a business in the fee's
+// currency: Dr business, Cr platform fee
+func (s *ledgerService) RecordFeeCollection(ctx context.Context, fee *entities.ProjectFeeCalculation, posterID uuid.UUID) (*entities.JournalEntry, error) {
+	req, err := s.feeCollectionRequest(ctx, fee.CooperativeID, fee.ProjectID, fee.BusinessID, fee.ID, fee.FeeAmount, fee.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.PostJournalEntry(ctx, req, posterID)
+}
+
+// PrepareFeeInvoiceEntry builds the ledger entry for a fee invoice in the
+// invoice's currency: Dr business, Cr platform fee. The entry is not posted
+// here; the fee repository posts it in the transaction that issues the invoice.
+func (s *ledgerService) PrepareFeeInvoiceEntry(ctx context.Context, invoice *entities.FeeInvoice, posterID uuid.UUID) (*entities.JournalEntry, error) {
+	req, err := s.feeCollectionRequest(ctx, invoice.CooperativeID, invoice.ProjectID, invoice.BusinessID, invoice.FeeCalculationID, invoice.NetAmount, invoice.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newJournalEntry(req, posterID)
+}
+
+// feeCollectionRequest builds the entry charging a platform fee to a business
+func (s *ledgerService) feeCollectionRequest(ctx context.Context, cooperativeID, projectID, businessID, calculationID uuid.UUID, amount entities.Money, currency string) (*entities.PostJournalEntryRequest, error) {
+	feeAmount, err := amount.InCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if !feeAmount.IsPositive() {
+		return nil, errors.New("fee amount must be greater than zero")
+	}
+	if feeAmount.Currency() == "" {
+		return nil, entities.ErrCurrencyRequired
+	}
+
+	business, err := s.GetOrCreateAccount(ctx, cooperativeID, entities.LedgerAccountCategoryBusiness, &businessID, feeAmount.Currency())
+	if err != nil {
+		return nil, err
+	}
+	platformFee, err := s.GetOrCreateAccount(ctx, cooperativeID, entities.LedgerAccountCategoryPlatformFee, nil, feeAmount.Currency())
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.PostJournalEntryRequest{
+		CooperativeID: cooperativeID,
+		ProjectID:     &projectID,
+		EntryType:     entities.JournalEntryTypeFeeCollection,
+		ReferenceType: "project_fee_calculation",
+		ReferenceID:   calculationID,
+		Description:   fmt.Sprintf("Platform fee for project %s", projectID),
+		Currency:      platformFee.Currency,
+		Lines: []entities.PostJournalLineRequest{
+			{AccountID: business.ID, Debit: feeAmount, Memo: "Platform fee charged"},
+			{AccountID: platformFee.ID, Credit: feeAmount, Memo: "Platform fee earned"},
+		},
+	}, nil
+}
+
+// GetAccountBalance derives an account balance from its journal lines
+func (s *ledgerService) GetAccountBalance(ctx context.Context, accountID uuid.UUID, asOf *time.Time) (*entities.LedgerAccountBalance, error) {
+	account, err := s.ledgerRepo.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.accountBalance(ctx, account, asOf)
+}
+
+// GetEscrowBalances returns the cooperative's escrow balance in each currency
+// it holds escrow in, derived from the ledger
+func (s *ledgerService) GetEscrowBalances(ctx context.Context, cooperativeID uuid.UUID) ([]entities.Money, error) {
+	accounts, err := s.ledgerRepo.ListAccounts(ctx, cooperativeID)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := []entities.Money{}
+	for _, account := range accounts {
+		if account.Category != entities.LedgerAccountCategoryEscrow {
+			continue
+		}
+
+		balance, err := s.accountBalance(ctx, account, nil)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, balance.Balance)
+	}
+
+	return balances, nil
+}
+
+// GetProjectEscrowBalance returns the escrow funds held for a project in a
+// currency derived from the ledger
+func (s *ledgerService) GetProjectEscrowBalance(ctx context.Context, projectID uuid.UUID, currency string) (entities.Money, error) {
+	if currency == "" {
+		return entities.Money{}, entities.ErrCurrencyRequired
+	}
+
+	balances, err := s.GetProjectEscrowBalances(ctx, projectID)
+	if err != nil {
+		return entities.Money{}, err
+	}
+
+	for _, balance := range balances {
+		if balance.Currency() == strings.ToUpper(currency) {
+			return balance, nil
+		}
+	}
+	return entities.ZeroMoney(currency), nil
+}
+
+// GetProjectEscrowBalances returns the escrow funds held for a project in each
+// currency derived from the ledger
+func (s *ledgerService) GetProjectEscrowBalances(ctx context.Context, projectID uuid.UUID) ([]entities.Money, error) {
+	totals, err := s.ledgerRepo.GetProjectCategoryTotals(ctx, projectID, entities.LedgerAccountCategoryEscrow)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := []entities.Money{}
+	for _, total := range totals {
+		balances = append(balances, total.TotalDebits.Sub(total.TotalCredits))
+	}
+	return balances, nil
+}
+
+// GetTrialBalance lists every account balance of a cooperative and checks that the ledger balances
+func (s *ledgerService) GetTrialBalance(ctx context.Context, cooperativeID uuid.UUID) (*entities.TrialBalance, error) {
+	accounts, err := s.ledgerRepo.ListAccounts(ctx, cooperativeID)
+	if err != nil {
+		return nil, err
+	}
+
+	trialBalance := &entities.TrialBalance{
+		CooperativeID: cooperativeID,
+		Accounts:      []*entities.LedgerAccountBalance{},
+		GeneratedAt:   time.Now(),
+	}
+
+	// Amounts in different currencies cannot be added, so the ledger balances per currency
+	totalsByCurrency := make(map[string]*entities.TrialBalanceTotals)
+	for _, account := range accounts {
+		balance, err := s.accountBalance(ctx, account, nil)
+		if err != nil {
+			return nil, err
+		}
+		trialBalance.Accounts = append(trialBalance.Accounts, balance)
+
+		currency := balance.TotalDebits.Currency()
+		totals, ok := totalsByCurrency[currency]
+		if !ok {
+			totals = &entities.TrialBalanceTotals{Currency: currency}
+			totalsByCurrency[currency] = totals
+			trialBalance.ByCurrency = append(trialBalance.ByCurrency, totals)
+		}
+		totals.TotalDebits = totals.TotalDebits.Add(balance.TotalDebits)
+		totals.TotalCredits = totals.TotalCredits.Add(balance.TotalCredits)
+	}
+
+	trialBalance.IsBalanced = true
+	for _, totals := range trialBalance.ByCurrency {
+		totals.IsBalanced = totals.TotalDebits.Equal(totals.TotalCredits)
+		trialBalance.IsBalanced = trialBalance.IsBalanced && totals.IsBalanced
+	}
+	if len(trialBalance.ByCurrency) == 1 {
+		trialBalance.TotalDebits = trialBalance.ByCurrency[0].TotalDebits
+		trialBalance.TotalCredits = trialBalance.ByCurrency[0].TotalCredits
+	}
+
+	return trialBalance, nil
+}
+
+func (s *ledgerService) accountBalance(ctx context.Context, account *entities.LedgerAccount, asOf *time.Time) (*entities.LedgerAccountBalance, error) {
+	debits, credits, err := s.ledgerRepo.GetAccountTotals(ctx, account, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	net := debits.Sub(credits)
+	if !entities.IsDebitNormal(account.AccountType) {
+		net = net.Neg()
+	}
+
+	balanceTime := time.Now()
+	if asOf != nil {
+		balanceTime = *asOf
+	}
+
+	return &entities.LedgerAccountBalance{
+		AccountID:    account.ID,
+		Code:         account.Code,
+		Name:         account.Name,
+		AccountType:  account.AccountType,
+		Category:     account.Category,
+		Currency:     account.Currency,
+		TotalDebits:  debits,
+		TotalCredits: credits,
+		Balance:      net,
+		AsOf:         balanceTime,
+	}, nil
+}
+
+func (s *ledgerService) generateEntryNumber() string {
+	// Generate unique journal entry number
+	timestamp := time.Now().Format("20060102150405")
+	return fmt.Sprintf("JE-%s-%s", timestamp, uuid.New().String()[:8])
+}
+
+// ledgerAccountNames are the display names of ledger account categories
+var ledgerAccountNames = map[string]string{
+	entities.LedgerAccountCategoryEscrow:         "Escrow",
+	entities.LedgerAccountCategoryInvestor:       "Investor Capital",
+	entities.LedgerAccountCategoryBusiness:       "Business Financing",
+	entities.LedgerAccountCategoryPlatformFee:    "Platform Fee Income",
+	entities.LedgerAccountCategoryTaxPayable:     "Tax Payable",
+	entities.LedgerAccountCategoryPayoutsPending: "Payouts Pending",
+}
+
+// ledgerAccountCode returns the account code and display name for a category and owner
+func ledgerAccountCode(category string, ownerID *uuid.UUID) (string, string) {
+	if ownerID == nil {
+		return strings.ToUpper(category), ledgerAccountNames[category]
+	}
+	return fmt.Sprintf("%s-%s", strings.ToUpper(category), ownerID), fmt.Sprintf("%s %s", ledgerAccountNames[category], ownerID)
+}
@@ -0,0 +1,481 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// idr returns an amount in rupiah
+func idr(amount string) entities.Money {
+	return entities.MustParseMoney(amount, "IDR")
+}
+
+// idrTotals returns ledger totals posted in IDR
+func idrTotals(debits, credits string) []*entities.LedgerCurrencyTotals {
+	return []*entities.LedgerCurrencyTotals{{Currency: "IDR", TotalDebits: idr(debits), TotalCredits: idr(credits)}}
+}
+
+func TestLedgerService_PostJournalEntry_Balanced(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(mockRepo, mockAuditService)
+	ctx := context.Background()
+
+	escrowID := uuid.New()
+	investorID := uuid.New()
+	req := &entities.PostJournalEntryRequest{
+		CooperativeID: uuid.New(),
+		EntryType:     entities.JournalEntryTypeAdjustment,
+		ReferenceType: "manual",
+		ReferenceID:   uuid.New(),
+		Description:   "Adjustment",
+		Currency:      "IDR",
+		Lines: []entities.PostJournalLineRequest{
+			{AccountID: escrowID, Debit: idr("100.10")},
+			{AccountID: investorID, Credit: idr("100.10")},
+		},
+	}
+
+	mockRepo.On("PostEntry", ctx, mock.AnythingOfType("*entities.JournalEntry")).Return(nil)
+
+	entry, err := ledgerService.PostJournalEntry(ctx, req, uuid.New())
+
+	assert.NoError(t, err)
+	assert.NotNil(t, entry)
+	assert.Equal(t, idr("100.10"), entry.TotalAmount)
+	assert.Len(t, entry.Lines, 2)
+	assert.Equal(t, entry.ID, entry.Lines[0].EntryID)
+	assert.Contains(t, entry.EntryNumber, "JE-")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLedgerService_PostJournalEntry_Unbalanced(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(mockRepo, mockAuditService)
+	ctx := context.Background()
+
+	req := &entities.PostJournalEntryRequest{
+		CooperativeID: uuid.New(),
+		EntryType:     entities.JournalEntryTypeAdjustment,
+		ReferenceType: "manual",
+		ReferenceID:   uuid.New(),
+		Description:   "Adjustment",
+		Currency:      "IDR",
+		Lines: []entities.PostJournalLineRequest{
+			{AccountID: uuid.New(), Debit: idr("100")},
+			{AccountID: uuid.New(), Credit: idr("99.99")},
+		},
+	}
+
+	entry, err := ledgerService.PostJournalEntry(ctx, req, uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, entry)
+	assert.Contains(t, err.Error(), "unbalanced")
+	mockRepo.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything)
+}
+
+func TestLedgerService_PostJournalEntry_InvalidLine(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+
+	req := &entities.PostJournalEntryRequest{
+		CooperativeID: uuid.New(),
+		EntryType:     entities.JournalEntryTypeAdjustment,
+		Currency:      "IDR",
+		Lines: []entities.PostJournalLineRequest{
+			{AccountID: uuid.New(), Debit: idr("50"), Credit: idr("50")},
+			{AccountID: uuid.New(), Credit: idr("0")},
+		},
+	}
+
+	_, err := ledgerService.PostJournalEntry(context.Background(), req, uuid.New())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exactly one of debit or credit")
+}
+
+func TestLedgerService_PostJournalEntry_RejectsLineInOtherCurrency(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(mockRepo, mockAuditService)
+
+	req := &entities.PostJournalEntryRequest{
+		CooperativeID: uuid.New(),
+		EntryType:     entities.JournalEntryTypeAdjustment,
+		Currency:      "IDR",
+		Lines: []entities.PostJournalLineRequest{
+			{AccountID: uuid.New(), Debit: entities.MustParseMoney("100", "USD")},
+			{AccountID: uuid.New(), Credit: idr("100")},
+		},
+	}
+
+	_, err := ledgerService.PostJournalEntry(context.Background(), req, uuid.New())
+
+	assert.ErrorIs(t, err, entities.ErrCurrencyMismatch)
+	mockRepo.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything)
+}
+
+func TestLedgerService_RecordInvestment(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(mockRepo, mockAuditService)
+	ctx := context.Background()
+
+	cooperativeID := uuid.New()
+	investment := &entities.InvestmentExtended{
+		ID:         uuid.New(),
+		InvestorID: uuid.New(),
+		ProjectID:  uuid.New(),
+		Amount:     idr("1500"),
+		Currency:   "IDR",
+	}
+
+	// Accounts are opened on first use
+	mockRepo.On("GetAccountByCode", ctx, cooperativeID, mock.AnythingOfType("string"), "IDR").Return(nil, repositories.ErrLedgerAccountNotFound)
+	mockRepo.On("CreateAccount", ctx, mock.AnythingOfType("*entities.LedgerAccount")).Return(nil)
+
+	var posted *entities.JournalEntry
+	mockRepo.On("PostEntry", ctx, mock.AnythingOfType("*entities.JournalEntry")).Run(func(args mock.Arguments) {
+		posted = args.Get(1).(*entities.JournalEntry)
+	}).Return(nil)
+
+	entry, err := ledgerService.RecordInvestment(ctx, investment, cooperativeID, investment.InvestorID)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, entry)
+	assert.Equal(t, entities.JournalEntryTypeInvestment, posted.EntryType)
+	assert.Equal(t, investment.ProjectID, *posted.ProjectID)
+	assert.Equal(t, idr("1500"), posted.TotalAmount)
+	assert.Equal(t, idr("1500"), posted.Lines[0].Debit)
+	assert.Equal(t, idr("1500"), posted.Lines[1].Credit)
+	mockRepo.AssertNumberOfCalls(t, "CreateAccount", 2)
+}
+
+func TestLedgerService_GetOrCreateAccount(t *testing.T) {
+	cooperativeID := uuid.New()
+	existing := &entities.LedgerAccount{ID: uuid.New(), CooperativeID: cooperativeID, Currency: "IDR"}
+
+	testCases := []struct {
+		name        string
+		setupMocks  func(*MockLedgerRepository)
+		expectedID  *uuid.UUID
+		expectError string
+	}{
+		{
+			name: "lookup failure is not treated as a missing account",
+			setupMocks: func(mockRepo *MockLedgerRepository) {
+				mockRepo.On("GetAccountByCode", mock.Anything, cooperativeID, mock.AnythingOfType("string"), "IDR").
+					Return(nil, errors.New("connection reset")).Once()
+			},
+			expectError: "connection reset",
+		},
+		{
+			name: "account opened concurrently is read back",
+			setupMocks: func(mockRepo *MockLedgerRepository) {
+				mockRepo.On("GetAccountByCode", mock.Anything, cooperativeID, mock.AnythingOfType("string"), "IDR").
+					Return(nil, repositories.ErrLedgerAccountNotFound).Once()
+				mockRepo.On("CreateAccount", mock.Anything, mock.AnythingOfType("*entities.LedgerAccount")).
+					Return(repositories.ErrLedgerAccountExists)
+				mockRepo.On("GetAccountByCode", mock.Anything, cooperativeID, mock.AnythingOfType("string"), "IDR").
+					Return(existing, nil).Once()
+			},
+			expectedID: &existing.ID,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAuditService := new(MockAuditService)
+			mockRepo := new(MockLedgerRepository)
+			ledgerService := NewLedgerService(mockRepo, mockAuditService)
+			tc.setupMocks(mockRepo)
+
+			account, err := ledgerService.GetOrCreateAccount(context.Background(), cooperativeID, entities.LedgerAccountCategoryEscrow, nil, "IDR")
+
+			if tc.expectError != "" {
+				assert.ErrorContains(t, err, tc.expectError)
+				assert.Nil(t, account)
+				mockRepo.AssertNotCalled(t, "CreateAccount", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, *tc.expectedID, account.ID)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLedgerService_RecordDisbursement_InsufficientEscrow(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(mockRepo, mockAuditService)
+	ctx := context.Background()
+
+	disbursement := &entities.FundDisbursement{
+		ID:                 uuid.New(),
+		ProjectID:          uuid.New(),
+		BusinessID:         uuid.New(),
+		CooperativeID:      uuid.New(),
+		DisbursementAmount: idr("5000"),
+		Currency:           "IDR",
+	}
+
+	account := &entities.LedgerAccount{ID: uuid.New(), CooperativeID: disbursement.CooperativeID, Currency: "IDR"}
+	mockRepo.On("GetAccountByCode", ctx, disbursement.CooperativeID, mock.AnythingOfType("string"), "IDR").Return(account, nil)
+	// The balance is checked by the repository under a lock on the escrow account
+	mockRepo.On("PostFundedEntry", ctx, mock.AnythingOfType("*entities.JournalEntry"), account.ID).
+		Return(errors.New("insufficient project escrow balance: available 3000.00, requested 5000.00"))
+
+	entry, err := ledgerService.RecordDisbursement(ctx, disbursement, uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, entry)
+	assert.Contains(t, err.Error(), "insufficient project escrow balance")
+	mockRepo.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything)
+}
+
+func TestLedgerService_RecordRefund_InsufficientEscrow(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(mockRepo, mockAuditService)
+	ctx := context.Background()
+
+	refund := &entities.FundRefund{
+		ID:            uuid.New(),
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		RefundType:    "investor_request",
+		Currency:      "IDR",
+	}
+	investorRefunds := []*entities.InvestorRefund{{
+		InvestmentID:    uuid.New(),
+		InvestorID:      uuid.New(),
+		RefundAmount:    idr("5000"),
+		ProcessingFee:   idr("0"),
+		NetRefundAmount: idr("5000"),
+	}}
+
+	escrow := &entities.LedgerAccount{ID: uuid.New(), CooperativeID: refund.CooperativeID, Currency: "IDR"}
+	mockRepo.On("GetAccountByCode", ctx, refund.CooperativeID, mock.AnythingOfType("string"), "IDR").Return(escrow, nil)
+	// Refunds are checked against the escrow account like disbursements
+	mockRepo.On("PostFundedEntry", ctx, mock.AnythingOfType("*entities.JournalEntry"), escrow.ID).
+		Return(errors.New("insufficient project escrow balance: available 3000.00, requested 5000.00"))
+
+	entry, err := ledgerService.RecordRefund(ctx, refund, investorRefunds, uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, entry)
+	assert.Contains(t, err.Error(), "insufficient project escrow balance")
+	mockRepo.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything)
+}
+
+func TestLedgerService_RecordProfitDistribution_WithTax(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(mockRepo, mockAuditService)
+	ctx := context.Background()
+
+	distribution := &entities.ProfitDistributionExtended{
+		ID:            uuid.New(),
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		Currency:      "IDR",
+	}
+	shares := []*entities.InvestorProfitShare{
+		{InvestmentID: uuid.New(), InvestorID: uuid.New(), NetProfitShare: idr("900"), TaxAmount: idr("100")},
+		{InvestmentID: uuid.New(), InvestorID: uuid.New(), NetProfitShare: idr("450"), TaxAmount: idr("50")},
+	}
+
+	mockRepo.On("GetAccountByCode", ctx, distribution.CooperativeID, mock.AnythingOfType("string"), "IDR").
+		Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}, nil)
+
+	var posted *entities.JournalEntry
+	mockRepo.On("PostEntry", ctx, mock.AnythingOfType("*entities.JournalEntry")).Run(func(args mock.Arguments) {
+		posted = args.Get(1).(*entities.JournalEntry)
+	}).Return(nil)
+
+	_, err := ledgerService.RecordProfitDistribution(ctx, distribution, shares, uuid.New())
+
+	assert.NoError(t, err)
+	assert.Equal(t, idr("1500"), posted.TotalAmount)
+	assert.Len(t, posted.Lines, 4) // escrow, two investors, tax payable
+	assert.Equal(t, idr("1500"), posted.Lines[0].Debit)
+	assert.Equal(t, idr("150"), posted.Lines[3].Credit)
+}
+
+func TestLedgerService_RecordProfitSharePayouts_ClosesEscrow(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(mockRepo, mockAuditService)
+	ctx := context.Background()
+
+	distribution := &entities.ProfitDistributionExtended{
+		ID:            uuid.New(),
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		Currency:      "IDR",
+	}
+	shares := []*entities.InvestorProfitShare{
+		{InvestmentID: uuid.New(), InvestorID: uuid.New(), NetProfitShare: idr("900"), ReinvestedAmount: idr("300")},
+		{InvestmentID: uuid.New(), InvestorID: uuid.New(), NetProfitShare: idr("450")},
+		{InvestmentID: uuid.New(), InvestorID: uuid.New(), NetProfitShare: idr("200"), ReinvestedAmount: idr("200")},
+	}
+
+	escrow := &entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}
+	mockRepo.On("GetAccountByCode", ctx, distribution.CooperativeID, "ESCROW", "IDR").Return(escrow, nil)
+	mockRepo.On("GetAccountByCode", ctx, distribution.CooperativeID, mock.AnythingOfType("string"), "IDR").
+		Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}, nil)
+
+	var posted *entities.JournalEntry
+	mockRepo.On("PostFundedEntry", ctx, mock.AnythingOfType("*entities.JournalEntry"), escrow.ID).Run(func(args mock.Arguments) {
+		posted = args.Get(1).(*entities.JournalEntry)
+	}).Return(nil)
+
+	_, err := ledgerService.RecordProfitSharePayouts(ctx, distribution, shares, uuid.New())
+
+	assert.NoError(t, err)
+	assert.Equal(t, idr("1050"), posted.TotalAmount)
+	assert.Len(t, posted.Lines, 3) // two investors paid, escrow
+	assert.Equal(t, escrow.ID, posted.Lines[2].AccountID)
+	assert.Equal(t, idr("1050"), posted.Lines[2].Credit)
+}
+
+func TestLedgerService_RecordFeeCollection_InFeeCurrency(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(mockRepo, mockAuditService)
+	ctx := context.Background()
+
+	fee := &entities.ProjectFeeCalculation{
+		ID:            uuid.New(),
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		BusinessID:    uuid.New(),
+		FeeAmount:     entities.MustParseMoney("25.50", "USD"),
+		Currency:      "USD",
+	}
+
+	mockRepo.On("GetAccountByCode", ctx, fee.CooperativeID, mock.AnythingOfType("string"), "USD").
+		Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "USD"}, nil)
+	var posted *entities.JournalEntry
+	mockRepo.On("PostEntry", ctx, mock.AnythingOfType("*entities.JournalEntry")).Run(func(args mock.Arguments) {
+		posted = args.Get(1).(*entities.JournalEntry)
+	}).Return(nil)
+
+	_, err := ledgerService.RecordFeeCollection(ctx, fee, uuid.New())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", posted.Currency)
+	assert.True(t, fee.FeeAmount.Equal(posted.TotalAmount))
+	mockRepo.AssertNotCalled(t, "GetAccountByCode", ctx, fee.CooperativeID, mock.AnythingOfType("string"), "IDR")
+}
+
+func TestLedgerService_GetAccountBalance_SignedByNormalSide(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(mockRepo, mockAuditService)
+	ctx := context.Background()
+
+	escrow := &entities.LedgerAccount{ID: uuid.New(), AccountType: entities.LedgerAccountTypeAsset}
+	investor := &entities.LedgerAccount{ID: uuid.New(), AccountType: entities.LedgerAccountTypeLiability}
+
+	mockRepo.On("GetAccountByID", ctx, escrow.ID).Return(escrow, nil)
+	mockRepo.On("GetAccountByID", ctx, investor.ID).Return(investor, nil)
+	mockRepo.On("GetAccountTotals", ctx, escrow, (*time.Time)(nil)).Return(idr("1000"), idr("400"), nil)
+	mockRepo.On("GetAccountTotals", ctx, investor, (*time.Time)(nil)).Return(idr("400"), idr("1000"), nil)
+
+	escrowBalance, err := ledgerService.GetAccountBalance(ctx, escrow.ID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, idr("600"), escrowBalance.Balance)
+
+	investorBalance, err := ledgerService.GetAccountBalance(ctx, investor.ID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, idr("600"), investorBalance.Balance)
+}
+
+func TestLedgerService_GetTrialBalance(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(mockRepo, mockAuditService)
+	ctx := context.Background()
+
+	cooperativeID := uuid.New()
+	escrow := &entities.LedgerAccount{ID: uuid.New(), AccountType: entities.LedgerAccountTypeAsset}
+	investor := &entities.LedgerAccount{ID: uuid.New(), AccountType: entities.LedgerAccountTypeLiability}
+
+	mockRepo.On("ListAccounts", ctx, cooperativeID).Return([]*entities.LedgerAccount{escrow, investor}, nil)
+	mockRepo.On("GetAccountTotals", ctx, escrow, (*time.Time)(nil)).Return(idr("1000"), idr("0"), nil)
+	mockRepo.On("GetAccountTotals", ctx, investor, (*time.Time)(nil)).Return(idr("0"), idr("1000"), nil)
+
+	trialBalance, err := ledgerService.GetTrialBalance(ctx, cooperativeID)
+
+	assert.NoError(t, err)
+	assert.True(t, trialBalance.IsBalanced)
+	assert.Equal(t, idr("1000"), trialBalance.TotalDebits)
+	assert.Len(t, trialBalance.Accounts, 2)
+}
+
+func TestLedgerService_GetEscrowBalances_PerCurrency(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(mockRepo, mockAuditService)
+	ctx := context.Background()
+
+	cooperativeID := uuid.New()
+	escrowIDR := &entities.LedgerAccount{ID: uuid.New(), Category: entities.LedgerAccountCategoryEscrow, AccountType: entities.LedgerAccountTypeAsset, Currency: "IDR"}
+	escrowUSD := &entities.LedgerAccount{ID: uuid.New(), Category: entities.LedgerAccountCategoryEscrow, AccountType: entities.LedgerAccountTypeAsset, Currency: "USD"}
+	investor := &entities.LedgerAccount{ID: uuid.New(), Category: entities.LedgerAccountCategoryInvestor, AccountType: entities.LedgerAccountTypeLiability, Currency: "IDR"}
+
+	mockRepo.On("ListAccounts", ctx, cooperativeID).Return([]*entities.LedgerAccount{escrowIDR, escrowUSD, investor}, nil)
+	mockRepo.On("GetAccountTotals", ctx, escrowIDR, (*time.Time)(nil)).Return(idr("1000"), idr("400"), nil)
+	mockRepo.On("GetAccountTotals", ctx, escrowUSD, (*time.Time)(nil)).Return(entities.MustParseMoney("25", "USD"), entities.ZeroMoney("USD"), nil)
+
+	balances, err := ledgerService.GetEscrowBalances(ctx, cooperativeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.Money{idr("600"), entities.MustParseMoney("25", "USD")}, balances)
+}
+
+func TestLedgerService_GetProjectEscrowBalance_InCurrency(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	mockRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(mockRepo, mockAuditService)
+	ctx := context.Background()
+
+	projectID := uuid.New()
+	mockRepo.On("GetProjectCategoryTotals", ctx, projectID, entities.LedgerAccountCategoryEscrow).Return(append(idrTotals("1000", "250"),
+		&entities.LedgerCurrencyTotals{Currency: "USD", TotalDebits: entities.MustParseMoney("40", "USD"), TotalCredits: entities.ZeroMoney("USD")}), nil)
+
+	balance, err := ledgerService.GetProjectEscrowBalance(ctx, projectID, "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, entities.MustParseMoney("40", "USD"), balance)
+
+	balance, err = ledgerService.GetProjectEscrowBalance(ctx, projectID, "MYR")
+	assert.NoError(t, err)
+	assert.Equal(t, entities.ZeroMoney("MYR"), balance)
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// LossSharingService implements FR-052: transparent handling of project losses
+// under mudarabah rules
+type LossSharingService interface {
+	// RecordLoss records a verified loss-making calculation and how the loss is borne
+	RecordLoss(ctx context.Context, calculation *entities.ProfitCalculation, investments []*entities.Investment, recorderID uuid.UUID) (*entities.ProjectLoss, error)
+	// GetOutstandingLoss returns the project's carried-forward losses still to be recovered
+	GetOutstandingLoss(ctx context.Context, projectID uuid.UUID) (entities.Money, error)
+	// RecoverLosses offsets a verified calculation's LossOffset against the project's
+	// oldest carried-forward losses
+	RecoverLosses(ctx context.Context, calculation *entities.ProfitCalculation, recorderID uuid.UUID) ([]*entities.ProjectLossRecovery, error)
+
+	GetProjectLossHistory(ctx context.Context, projectID uuid.UUID) (*entities.ProjectLossHistory, error)
+	GetInvestorLossShares(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestorLossShare, error)
+}
+
+type lossSharingService struct {
+	lossRepo     repositories.ProjectLossRepository
+	auditService AuditService
+}
+
+// NewLossSharingService creates a new loss sharing service
+func NewLossSharingService(lossRepo repositories.ProjectLossRepository, auditService AuditService) LossSharingService {
+	return &lossSharingService{
+		lossRepo:     lossRepo,
+		auditService: auditService,
+	}
+}
+
+// RecordLoss records the period's loss. The capital providers bear it in
+// proportion to their capital, their liability limited to that capital, unless
+// the cooperative absorbs it. A carried-forward loss is recovered from later
+// profits; a shared loss is written off against the investors' capital.
+func (s *lossSharingService) RecordLoss(ctx context.Context, calculation *entities.ProfitCalculation, investments []*entities.Investment, recorderID uuid.UUID) (*entities.ProjectLoss, error) {
+	if !calculation.TotalLoss.IsPositive() {
+		return nil, errors.New("profit calculation has no loss to record")
+	}
+	if calculation.VerificationStatus != entities.ProfitCalculationStatusVerified {
+		return nil, errors.New("profit calculation must be verified before its loss is recorded")
+	}
+
+	method := calculation.LossHandlingMethod
+	if method == "" {
+		method = entities.LossHandlingCarryForward
+	}
+
+	now := time.Now()
+	currency := calculation.TotalLoss.Currency()
+	loss := &entities.ProjectLoss{
+		ID:                  uuid.New(),
+		ProjectID:           calculation.ProjectID,
+		CooperativeID:       calculation.CooperativeID,
+		ProfitCalculationID: calculation.ID,
+		PeriodStart:         calculation.StartDate,
+		PeriodEnd:           calculation.EndDate,
+		LossHandlingMethod:  method,
+		BorneBy:             entities.LossBorneByInvestors,
+		LossAmount:          calculation.TotalLoss,
+		RecoveredAmount:     entities.ZeroMoney(currency),
+		Currency:            currency,
+		RecordedBy:          recorderID,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+
+	switch method {
+	case entities.LossHandlingCarryForward:
+		loss.Status = entities.ProjectLossStatusCarriedForward
+	case entities.LossHandlingShared:
+		loss.Status = entities.ProjectLossStatusWrittenOff
+	case entities.LossHandlingAbsorb:
+		loss.Status = entities.ProjectLossStatusAbsorbed
+		loss.BorneBy = entities.LossBorneByCooperative
+	default:
+		return nil, fmt.Errorf("unsupported loss handling method: %s", method)
+	}
+
+	if loss.BorneBy == entities.LossBorneByInvestors {
+		shares, err := allocateInvestorLossShares(loss, investments)
+		if err != nil {
+			return nil, err
+		}
+		loss.Shares = shares
+		loss.LossAmount = entities.ZeroMoney(currency)
+		for _, share := range shares {
+			loss.LossAmount = loss.LossAmount.Add(share.LossAmount)
+		}
+	}
+
+	if err := s.lossRepo.CreateLoss(ctx, loss, loss.Shares); err != nil {
+		return nil, fmt.Errorf("failed to record project loss: %w", err)
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     recorderID,
+		Operation:  "record_project_loss",
+		EntityType: "project_loss",
+		EntityID:   loss.ID,
+		NewValues:  fmt.Sprintf("Recorded loss of %s for project %s (%s, borne by %s)", loss.LossAmount, loss.ProjectID, method, loss.BorneBy),
+	})
+
+	return loss, nil
+}
+
+// allocateInvestorLossShares splits a loss across the investments in proportion
+// to their capital; no investor loses more than they invested
+func allocateInvestorLossShares(loss *entities.ProjectLoss, investments []*entities.Investment) ([]*entities.InvestorLossShare, error) {
+	if len(investments) == 0 {
+		return nil, errors.New("no confirmed investments to bear the loss")
+	}
+
+	holders := make([]entities.AllocationHolder, len(investments))
+	totalCapital := entities.ZeroMoney(loss.Currency)
+	for i, investment := range investments {
+		capital := investment.Amount
+		holders[i] = entities.AllocationHolder{Weight: entities.AllocationWeight(capital), Cap: &capital}
+		totalCapital = totalCapital.Add(capital)
+	}
+
+	amounts, err := entities.Allocate(loss.LossAmount.Min(totalCapital), holders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate loss: %w", err)
+	}
+
+	shares := make([]*entities.InvestorLossShare, len(investments))
+	for i, investment := range investments {
+		percentage, _ := investment.Amount.Ratio(totalCapital).Float64()
+		shares[i] = &entities.InvestorLossShare{
+			ID:                   uuid.New(),
+			ProjectLossID:        loss.ID,
+			ProjectID:            loss.ProjectID,
+			InvestmentID:         investment.ID,
+			InvestorID:           investment.InvestorID,
+			PeriodStart:          loss.PeriodStart,
+			PeriodEnd:            loss.PeriodEnd,
+			OriginalInvestment:   investment.Amount,
+			InvestmentPercentage: percentage * 100,
+			LossAmount:           amounts[i],
+			RecoveredAmount:      entities.ZeroMoney(loss.Currency),
+			Currency:             loss.Currency,
+			CreatedAt:            loss.CreatedAt,
+			UpdatedAt:            loss.CreatedAt,
+		}
+	}
+
+	return shares, nil
+}
+
+// GetOutstandingLoss returns the project's carried-forward losses still to be recovered
+func (s *lossSharingService) GetOutstandingLoss(ctx context.Context, projectID uuid.UUID) (entities.Money, error) {
+	losses, err := s.lossRepo.ListProjectLosses(ctx, projectID)
+	if err != nil {
+		return entities.Money{}, fmt.Errorf("failed to get project losses: %w", err)
+	}
+
+	var outstanding entities.Money
+	for _, loss := range losses {
+		outstanding = outstanding.Add(loss.OutstandingAmount())
+	}
+
+	return outstanding, nil
+}
+
+// RecoverLosses applies a profit calculation's loss offset to the oldest
+// carried-forward losses first. The investors' recovered amounts are re-split
+// from each loss's cumulative recovery, so they always add up to it.
+func (s *lossSharingService) RecoverLosses(ctx context.Context, calculation *entities.ProfitCalculation, recorderID uuid.UUID) ([]*entities.ProjectLossRecovery, error) {
+	if !calculation.LossOffset.IsPositive() {
+		return nil, nil
+	}
+	if calculation.VerificationStatus != entities.ProfitCalculationStatusVerified {
+		return nil, errors.New("profit calculation must be verified before it offsets losses")
+	}
+
+	// A calculation offsets losses once; distributing it again reuses its recoveries
+	existing, err := s.lossRepo.ListRecoveries(ctx, calculation.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loss recoveries: %w", err)
+	}
+	var applied []*entities.ProjectLossRecovery
+	for _, recovery := range existing {
+		if recovery.ProfitCalculationID == calculation.ID {
+			applied = append(applied, recovery)
+		}
+	}
+	if len(applied) > 0 {
+		return applied, nil
+	}
+
+	losses, err := s.lossRepo.ListProjectLosses(ctx, calculation.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project losses: %w", err)
+	}
+
+	now := time.Now()
+	remaining := calculation.LossOffset
+	var recoveries []*entities.ProjectLossRecovery
+	var recovered []*entities.ProjectLoss
+	var shares []*entities.InvestorLossShare
+	for _, loss := range losses {
+		if !remaining.IsPositive() {
+			break
+		}
+		outstanding := loss.OutstandingAmount()
+		if !outstanding.IsPositive() {
+			continue
+		}
+
+		amount := outstanding.Min(remaining)
+		remaining = remaining.Sub(amount)
+		loss.RecoveredAmount = loss.RecoveredAmount.Add(amount)
+		if loss.RecoveredAmount.Equal(loss.LossAmount) {
+			loss.Status = entities.ProjectLossStatusRecovered
+		}
+
+		lossShares, err := s.lossRepo.ListLossShares(ctx, loss)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get investor loss shares: %w", err)
+		}
+		if err := allocateLossRecovery(loss, lossShares); err != nil {
+			return nil, err
+		}
+
+		recoveries = append(recoveries, &entities.ProjectLossRecovery{
+			ID:                  uuid.New(),
+			ProjectLossID:       loss.ID,
+			ProjectID:           loss.ProjectID,
+			ProfitCalculationID: calculation.ID,
+			Amount:              amount,
+			Currency:            loss.Currency,
+			RecordedBy:          recorderID,
+			CreatedAt:           now,
+		})
+		recovered = append(recovered, loss)
+		shares = append(shares, lossShares...)
+	}
+
+	if remaining.IsPositive() {
+		return nil, errors.New("carried-forward losses changed since the profit calculation; recalculate it")
+	}
+
+	if err := s.lossRepo.ApplyRecoveries(ctx, calculation.ProjectID, recoveries, recovered, shares); err != nil {
+		return nil, fmt.Errorf("failed to apply loss recoveries: %w", err)
+	}
+
+	for _, recovery := range recoveries {
+		s.auditService.LogOperation(ctx, &LogOperationRequest{
+			UserID:     recorderID,
+			Operation:  "recover_project_loss",
+			EntityType: "project_loss",
+			EntityID:   recovery.ProjectLossID,
+			NewValues:  fmt.Sprintf("Recovered %s from profit calculation %s", recovery.Amount, calculation.ID),
+		})
+	}
+
+	return recoveries, nil
+}
+
+// allocateLossRecovery splits a loss's cumulative recovery across the investors
+// in proportion to their share of the loss
+func allocateLossRecovery(loss *entities.ProjectLoss, shares []*entities.InvestorLossShare) error {
+	if len(shares) == 0 {
+		return nil
+	}
+
+	holders := make([]entities.AllocationHolder, len(shares))
+	for i, share := range shares {
+		lossAmount := share.LossAmount
+		holders[i] = entities.AllocationHolder{Weight: entities.AllocationWeight(lossAmount), Cap: &lossAmount}
+	}
+
+	amounts, err := entities.Allocate(loss.RecoveredAmount, holders)
+	if err != nil {
+		return fmt.Errorf("failed to allocate loss recovery: %w", err)
+	}
+
+	for i, share := range shares {
+		share.RecoveredAmount = amounts[i]
+	}
+	return nil
+}
+
+// GetProjectLossHistory returns a project's losses period by period with the
+// investors' shares and the profits that recovered them
+func (s *lossSharingService) GetProjectLossHistory(ctx context.Context, projectID uuid.UUID) (*entities.ProjectLossHistory, error) {
+	losses, err := s.lossRepo.ListProjectLosses(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project losses: %w", err)
+	}
+
+	recoveries, err := s.lossRepo.ListRecoveries(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loss recoveries: %w", err)
+	}
+
+	history := &entities.ProjectLossHistory{
+		ProjectID:  projectID,
+		Losses:     losses,
+		Recoveries: recoveries,
+	}
+	for _, loss := range losses {
+		shares, err := s.lossRepo.ListLossShares(ctx, loss)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get investor loss shares: %w", err)
+		}
+		loss.Shares = shares
+
+		history.Currency = loss.Currency
+		history.TotalLoss = history.TotalLoss.Add(loss.LossAmount)
+		history.TotalRecovered = history.TotalRecovered.Add(loss.RecoveredAmount)
+		history.CarriedForward = history.CarriedForward.Add(loss.OutstandingAmount())
+	}
+
+	return history, nil
+}
+
+// GetInvestorLossShares returns an investor's shares of project losses, oldest first
+func (s *lossSharingService) GetInvestorLossShares(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestorLossShare, error) {
+	shares, err := s.lossRepo.ListInvestorLossShares(ctx, investorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get investor loss shares: %w", err)
+	}
+	return shares, nil
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLossSharingService_RecordLoss(t *testing.T) {
+	testCases := []struct {
+		name         string
+		method       string
+		verification string
+		totalLoss    string
+		invested     []string
+		expectedErr  string
+		status       string
+		borneBy      string
+		lossAmount   string
+		shareAmounts []string
+	}{
+		{
+			// Losses are carried forward and borne by the capital providers by default
+			name:         "Borne by capital providers",
+			verification: entities.ProfitCalculationStatusVerified,
+			totalLoss:    "1000",
+			invested:     []string{"10000", "10000", "10000"},
+			status:       entities.ProjectLossStatusCarriedForward,
+			borneBy:      entities.LossBorneByInvestors,
+			lossAmount:   "1000",
+			shareAmounts: []string{"333.34", "333.33", "333.33"},
+		},
+		{
+			name:         "Limited to capital",
+			method:       entities.LossHandlingShared,
+			verification: entities.ProfitCalculationStatusVerified,
+			totalLoss:    "80000",
+			invested:     []string{"20000", "30000"},
+			status:       entities.ProjectLossStatusWrittenOff,
+			borneBy:      entities.LossBorneByInvestors,
+			lossAmount:   "50000",
+			shareAmounts: []string{"20000", "30000"},
+		},
+		{
+			name:         "Absorbed by the cooperative",
+			method:       entities.LossHandlingAbsorb,
+			verification: entities.ProfitCalculationStatusVerified,
+			totalLoss:    "5000",
+			status:       entities.ProjectLossStatusAbsorbed,
+			borneBy:      entities.LossBorneByCooperative,
+			lossAmount:   "5000",
+		},
+		{
+			name:         "Calculation not verified",
+			verification: entities.ProfitCalculationStatusPending,
+			totalLoss:    "5000",
+			expectedErr:  "verified",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lossRepo := new(MockProjectLossRepository)
+			mockAuditService := new(MockAuditService)
+			mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+			lossService := NewLossSharingService(lossRepo, mockAuditService)
+			ctx := context.Background()
+
+			calculation := &entities.ProfitCalculation{
+				ID:                 uuid.New(),
+				ProjectID:          uuid.New(),
+				CooperativeID:      uuid.New(),
+				StartDate:          time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:            time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+				NetProfit:          idr("0"),
+				TotalLoss:          idr(tc.totalLoss),
+				LossHandlingMethod: tc.method,
+				VerificationStatus: tc.verification,
+			}
+			var investments []*entities.Investment
+			for _, amount := range tc.invested {
+				investments = append(investments, &entities.Investment{ID: uuid.New(), InvestorID: uuid.New(), Amount: idr(amount)})
+			}
+			lossRepo.On("CreateLoss", ctx, mock.AnythingOfType("*entities.ProjectLoss"), mock.AnythingOfType("[]*entities.InvestorLossShare")).Return(nil)
+
+			loss, err := lossService.RecordLoss(ctx, calculation, investments, uuid.New())
+
+			if tc.expectedErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				assert.Nil(t, loss)
+				lossRepo.AssertNotCalled(t, "CreateLoss", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.status, loss.Status)
+			assert.Equal(t, tc.borneBy, loss.BorneBy)
+			assert.Equal(t, idr(tc.lossAmount), loss.LossAmount)
+			assert.Equal(t, calculation.EndDate, loss.PeriodEnd)
+			require.Len(t, loss.Shares, len(tc.shareAmounts))
+			for i, share := range loss.Shares {
+				assert.Equal(t, idr(tc.shareAmounts[i]), share.LossAmount)
+				assert.Equal(t, investments[i].InvestorID, share.InvestorID)
+				assert.Equal(t, calculation.EndDate, share.PeriodEnd)
+			}
+			if tc.borneBy == entities.LossBorneByCooperative {
+				assert.True(t, loss.OutstandingAmount().IsZero())
+			}
+			lossRepo.AssertCalled(t, "CreateLoss", ctx, loss, loss.Shares)
+		})
+	}
+}
+
+func TestLossSharingService_RecoverLosses_OldestFirst(t *testing.T) {
+	lossRepo := new(MockProjectLossRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	lossService := NewLossSharingService(lossRepo, mockAuditService)
+	ctx := context.Background()
+
+	projectID := uuid.New()
+	older := &entities.ProjectLoss{ID: uuid.New(), ProjectID: projectID, PeriodEnd: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+		LossAmount: idr("1000"), RecoveredAmount: idr("400"), Currency: "IDR", Status: entities.ProjectLossStatusCarriedForward}
+	written := &entities.ProjectLoss{ID: uuid.New(), ProjectID: projectID, PeriodEnd: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		LossAmount: idr("700"), RecoveredAmount: idr("0"), Currency: "IDR", Status: entities.ProjectLossStatusWrittenOff}
+	newer := &entities.ProjectLoss{ID: uuid.New(), ProjectID: projectID, PeriodEnd: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		LossAmount: idr("900"), RecoveredAmount: idr("0"), Currency: "IDR", Status: entities.ProjectLossStatusCarriedForward}
+	olderShares := []*entities.InvestorLossShare{
+		{ID: uuid.New(), LossAmount: idr("500"), RecoveredAmount: idr("200")},
+		{ID: uuid.New(), LossAmount: idr("500"), RecoveredAmount: idr("200")},
+	}
+	newerShares := []*entities.InvestorLossShare{
+		{ID: uuid.New(), LossAmount: idr("300")},
+		{ID: uuid.New(), LossAmount: idr("300")},
+		{ID: uuid.New(), LossAmount: idr("300")},
+	}
+
+	lossRepo.On("ListRecoveries", ctx, projectID).Return([]*entities.ProjectLossRecovery{}, nil)
+	lossRepo.On("ListProjectLosses", ctx, projectID).Return([]*entities.ProjectLoss{older, written, newer}, nil)
+	lossRepo.On("ListLossShares", ctx, older).Return(olderShares, nil)
+	lossRepo.On("ListLossShares", ctx, newer).Return(newerShares, nil)
+	lossRepo.On("ApplyRecoveries", ctx, projectID, mock.Anything, []*entities.ProjectLoss{older, newer}, mock.Anything).Return(nil)
+
+	calculation := &entities.ProfitCalculation{
+		ID:                 uuid.New(),
+		ProjectID:          projectID,
+		LossOffset:         idr("700"),
+		VerificationStatus: entities.ProfitCalculationStatusVerified,
+	}
+
+	recoveries, err := lossService.RecoverLosses(ctx, calculation, uuid.New())
+
+	// 600 closes the older loss; the remaining 100 goes to the newer one and the
+	// written-off loss is never recovered
+	require.NoError(t, err)
+	require.Len(t, recoveries, 2)
+	assert.Equal(t, idr("600"), recoveries[0].Amount)
+	assert.Equal(t, idr("100"), recoveries[1].Amount)
+	assert.Equal(t, entities.ProjectLossStatusRecovered, older.Status)
+	assert.Equal(t, idr("1000"), older.RecoveredAmount)
+	assert.Equal(t, entities.ProjectLossStatusCarriedForward, newer.Status)
+	assert.Equal(t, idr("800"), newer.OutstandingAmount())
+	assert.Equal(t, idr("500"), olderShares[0].RecoveredAmount)
+	assert.Equal(t, idr("33.34"), newerShares[0].RecoveredAmount)
+	assert.Equal(t, idr("33.33"), newerShares[2].RecoveredAmount)
+	assert.Equal(t, idr("0"), written.RecoveredAmount)
+}
+
+func TestLossSharingService_RecoverLosses_OnlyOncePerCalculation(t *testing.T) {
+	lossRepo := new(MockProjectLossRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	lossService := NewLossSharingService(lossRepo, mockAuditService)
+	ctx := context.Background()
+
+	calculation := &entities.ProfitCalculation{
+		ID:                 uuid.New(),
+		ProjectID:          uuid.New(),
+		LossOffset:         idr("700"),
+		VerificationStatus: entities.ProfitCalculationStatusVerified,
+	}
+	applied := &entities.ProjectLossRecovery{ID: uuid.New(), ProfitCalculationID: calculation.ID, Amount: idr("700")}
+	lossRepo.On("ListRecoveries", ctx, calculation.ProjectID).Return([]*entities.ProjectLossRecovery{
+		{ID: uuid.New(), ProfitCalculationID: uuid.New(), Amount: idr("50")},
+		applied,
+	}, nil)
+
+	recoveries, err := lossService.RecoverLosses(ctx, calculation, uuid.New())
+
+	require.NoError(t, err)
+	assert.Equal(t, []*entities.ProjectLossRecovery{applied}, recoveries)
+	lossRepo.AssertNotCalled(t, "ApplyRecoveries", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLossSharingService_RecoverLosses_StaleCalculation(t *testing.T) {
+	lossRepo := new(MockProjectLossRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	lossService := NewLossSharingService(lossRepo, mockAuditService)
+	ctx := context.Background()
+
+	projectID := uuid.New()
+	loss := &entities.ProjectLoss{ID: uuid.New(), ProjectID: projectID, PeriodEnd: time.Now(),
+		LossAmount: idr("500"), RecoveredAmount: idr("0"), Currency: "IDR", Status: entities.ProjectLossStatusCarriedForward}
+	lossRepo.On("ListRecoveries", ctx, projectID).Return([]*entities.ProjectLossRecovery{}, nil)
+	lossRepo.On("ListProjectLosses", ctx, projectID).Return([]*entities.ProjectLoss{loss}, nil)
+	lossRepo.On("ListLossShares", ctx, loss).Return([]*entities.InvestorLossShare{}, nil)
+
+	recoveries, err := lossService.RecoverLosses(ctx, &entities.ProfitCalculation{
+		ID:                 uuid.New(),
+		ProjectID:          projectID,
+		LossOffset:         idr("800"),
+		VerificationStatus: entities.ProfitCalculationStatusVerified,
+	}, uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, recoveries)
+	assert.Contains(t, err.Error(), "recalculate")
+	lossRepo.AssertNotCalled(t, "ApplyRecoveries", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProfitSharingService_CarriedForwardLossOffsetsProfit(t *testing.T) {
+	lossRepo := new(MockProjectLossRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	lossService := NewLossSharingService(lossRepo, mockAuditService)
+	profitService := NewProfitSharingService(mockAuditService, nil, nil, lossService, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	projectID := uuid.New()
+	lossRepo.On("ListProjectLosses", ctx, projectID).Return([]*entities.ProjectLoss{
+		{ID: uuid.New(), ProjectID: projectID, PeriodEnd: time.Now().AddDate(0, -3, 0), LossAmount: idr("100000"),
+			RecoveredAmount: idr("40000"), Currency: "IDR", Status: entities.ProjectLossStatusCarriedForward},
+	}, nil)
+
+	calculation, err := profitService.CreateProfitCalculation(ctx, &entities.CreateProfitCalculationRequest{
+		ProjectID:          projectID,
+		CalculationPeriod:  entities.ProfitCalculationPeriodQuarterly,
+		StartDate:          time.Now().AddDate(0, -3, 0),
+		EndDate:            time.Now(),
+		TotalRevenue:       idr("1000000"),
+		TotalExpenses:      idr("700000"),
+		ProfitSharingRatio: map[string]float64{"investor": 70, "business": 25, "cooperative": 5},
+	}, uuid.New())
+
+	// The outstanding 60000 is recovered before the remaining 240000 is shared
+	require.NoError(t, err)
+	assert.Equal(t, idr("300000"), calculation.NetProfit)
+	assert.Equal(t, idr("60000"), calculation.LossOffset)
+	assert.Equal(t, entities.LossHandlingCarryForward, calculation.LossHandlingMethod)
+	assert.Equal(t, idr("168000"), calculation.InvestorShare)
+	assert.Equal(t, idr("60000"), calculation.BusinessShare)
+	assert.Equal(t, idr("12000"), calculation.CooperativeShare)
+
+	// A loss-making period is reported as a loss borne by the capital providers
+	netProfit, shares, err := profitService.CalculateShariaCompliantProfit(ctx, projectID, idr("1000"), idr("1500"))
+	require.NoError(t, err)
+	assert.Equal(t, idr("-500"), netProfit)
+	assert.Equal(t, 100.0, shares["investor"])
+}
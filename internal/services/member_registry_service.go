@@ -46,20 +46,23 @@ type MemberRegistryService interface {
 }
 
 type memberRegistryService struct {
-	userRepo        repositories.UserRepositorySharded
-	cooperativeRepo repositories.CooperativeRepository
-	auditService    AuditService
+	userRepo         repositories.UserRepositorySharded
+	cooperativeRepo  repositories.CooperativeRepository
+	auditService     AuditService
+	sanctionsService SanctionsScreeningService
 }
 
 func NewMemberRegistryService(
 	userRepo repositories.UserRepositorySharded,
 	cooperativeRepo repositories.CooperativeRepository,
 	auditService AuditService,
+	sanctionsService SanctionsScreeningService,
 ) MemberRegistryService {
 	return &memberRegistryService{
-		userRepo:        userRepo,
-		cooperativeRepo: cooperativeRepo,
-		auditService:    auditService,
+		userRepo:         userRepo,
+		cooperativeRepo:  cooperativeRepo,
+		auditService:     auditService,
+		sanctionsService: sanctionsService,
 	}
 }
 
@@ -90,6 +93,11 @@ func (s *memberRegistryService) AddMemberToCooperative(ctx context.Context, coop
 		return fmt.Errorf("user is not eligible for membership: %v", violations)
 	}
 
+	// Screen against sanctions lists before admitting the member
+	if err := requireSanctionsClearance(ctx, s.sanctionsService, individualSanctionsSubject(user, entities.SanctionsPurposeMembership, &cooperativeID)); err != nil {
+		return err
+	}
+
 	// Update user's cooperative membership
 	user.CooperativeID = &cooperativeID
 
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"comfunds/internal/entities"
 
@@ -291,60 +292,1798 @@ type MockInvestmentPolicyService struct {
 	mock.Mock
 }
 
-func (m *MockInvestmentPolicyService) CreatePolicy(ctx context.Context, req *entities.CreateInvestmentPolicyRequest, creatorID uuid.UUID) (*entities.InvestmentPolicy, error) {
-	args := m.Called(ctx, req, creatorID)
+func (m *MockInvestmentPolicyService) CreateInvestmentPolicy(ctx context.Context, cooperativeID uuid.UUID, req *entities.CreateInvestmentPolicyRequest, creatorID uuid.UUID) (*entities.InvestmentPolicyExtended, error) {
+	args := m.Called(ctx, cooperativeID, req, creatorID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*entities.InvestmentPolicy), args.Error(1)
+	return args.Get(0).(*entities.InvestmentPolicyExtended), args.Error(1)
 }
 
-func (m *MockInvestmentPolicyService) CreateInvestmentPolicy(ctx context.Context, req *entities.CreateInvestmentPolicyRequest, creatorID uuid.UUID) (*entities.InvestmentPolicy, error) {
-	args := m.Called(ctx, req, creatorID)
+func (m *MockInvestmentPolicyService) GetInvestmentPolicy(ctx context.Context, cooperativeID, policyID uuid.UUID) (*entities.InvestmentPolicyExtended, error) {
+	args := m.Called(ctx, cooperativeID, policyID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*entities.InvestmentPolicy), args.Error(1)
+	return args.Get(0).(*entities.InvestmentPolicyExtended), args.Error(1)
+}
+
+func (m *MockInvestmentPolicyService) GetActiveInvestmentPolicies(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.InvestmentPolicyExtended, error) {
+	args := m.Called(ctx, cooperativeID)
+	return args.Get(0).([]*entities.InvestmentPolicyExtended), args.Error(1)
+}
+
+func (m *MockInvestmentPolicyService) UpdateInvestmentPolicy(ctx context.Context, cooperativeID, policyID uuid.UUID, req *entities.UpdateInvestmentPolicyRequest, updaterID uuid.UUID) (*entities.InvestmentPolicyExtended, error) {
+	args := m.Called(ctx, cooperativeID, policyID, req, updaterID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.InvestmentPolicyExtended), args.Error(1)
+}
+
+func (m *MockInvestmentPolicyService) DeactivateInvestmentPolicy(ctx context.Context, cooperativeID, policyID, deactivatorID uuid.UUID) error {
+	args := m.Called(ctx, cooperativeID, policyID, deactivatorID)
+	return args.Error(0)
+}
+
+func (m *MockInvestmentPolicyService) CreateProfitSharingRules(ctx context.Context, cooperativeID uuid.UUID, req *entities.CreateProfitSharingRulesRequest, creatorID uuid.UUID) (*entities.ProfitSharingRulesExtended, error) {
+	args := m.Called(ctx, cooperativeID, req, creatorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProfitSharingRulesExtended), args.Error(1)
+}
+
+func (m *MockInvestmentPolicyService) GetProfitSharingRules(ctx context.Context, cooperativeID, rulesID uuid.UUID) (*entities.ProfitSharingRulesExtended, error) {
+	args := m.Called(ctx, cooperativeID, rulesID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProfitSharingRulesExtended), args.Error(1)
+}
+
+func (m *MockInvestmentPolicyService) GetActiveProfitSharingRules(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.ProfitSharingRulesExtended, error) {
+	args := m.Called(ctx, cooperativeID)
+	return args.Get(0).([]*entities.ProfitSharingRulesExtended), args.Error(1)
+}
+
+func (m *MockInvestmentPolicyService) UpdateProfitSharingRules(ctx context.Context, cooperativeID, rulesID uuid.UUID, req *entities.UpdateProfitSharingRulesRequest, updaterID uuid.UUID) (*entities.ProfitSharingRulesExtended, error) {
+	args := m.Called(ctx, cooperativeID, rulesID, req, updaterID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProfitSharingRulesExtended), args.Error(1)
+}
+
+func (m *MockInvestmentPolicyService) DeactivateProfitSharingRules(ctx context.Context, cooperativeID, rulesID, deactivatorID uuid.UUID) error {
+	args := m.Called(ctx, cooperativeID, rulesID, deactivatorID)
+	return args.Error(0)
+}
+
+func (m *MockInvestmentPolicyService) ValidatePolicyCompliance(ctx context.Context, projectID, policyID uuid.UUID) (bool, []string, error) {
+	args := m.Called(ctx, projectID, policyID)
+	return args.Bool(0), args.Get(1).([]string), args.Error(2)
+}
+
+func (m *MockInvestmentPolicyService) ValidateInvestmentAmount(ctx context.Context, cooperativeID uuid.UUID, amount entities.Money) (bool, string, error) {
+	args := m.Called(ctx, cooperativeID, amount)
+	return args.Bool(0), args.String(1), args.Error(2)
+}
+
+func (m *MockInvestmentPolicyService) ValidateInvestorEligibility(ctx context.Context, cooperativeID, userID uuid.UUID) (bool, []string, error) {
+	args := m.Called(ctx, cooperativeID, userID)
+	return args.Bool(0), args.Get(1).([]string), args.Error(2)
 }
 
 type MockProjectApprovalService struct {
 	mock.Mock
 }
 
-func (m *MockProjectApprovalService) ApproveProject(ctx context.Context, projectID, approverID uuid.UUID, comments string) error {
-	args := m.Called(ctx, projectID, approverID, comments)
+func (m *MockProjectApprovalService) SubmitProjectForApproval(ctx context.Context, req *entities.SubmitProjectApprovalRequest, submitterID uuid.UUID) (*entities.ProjectApproval, error) {
+	args := m.Called(ctx, req, submitterID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectApproval), args.Error(1)
+}
+
+func (m *MockProjectApprovalService) ReviewProjectApproval(ctx context.Context, approvalID uuid.UUID, req *entities.ReviewProjectApprovalRequest, reviewerID uuid.UUID) (*entities.ProjectApproval, error) {
+	args := m.Called(ctx, approvalID, req, reviewerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectApproval), args.Error(1)
+}
+
+func (m *MockProjectApprovalService) UpdateProjectApproval(ctx context.Context, approvalID uuid.UUID, req *entities.UpdateProjectApprovalRequest, updaterID uuid.UUID) (*entities.ProjectApproval, error) {
+	args := m.Called(ctx, approvalID, req, updaterID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectApproval), args.Error(1)
+}
+
+func (m *MockProjectApprovalService) WithdrawProjectApproval(ctx context.Context, approvalID, submitterID uuid.UUID) error {
+	args := m.Called(ctx, approvalID, submitterID)
 	return args.Error(0)
 }
 
-func (m *MockProjectApprovalService) CalculateApprovalScore(ctx context.Context, projectID uuid.UUID) (float64, error) {
-	args := m.Called(ctx, projectID)
-	return args.Get(0).(float64), args.Error(1)
+func (m *MockProjectApprovalService) GetProjectApproval(ctx context.Context, approvalID uuid.UUID) (*entities.ProjectApproval, error) {
+	args := m.Called(ctx, approvalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectApproval), args.Error(1)
+}
+
+func (m *MockProjectApprovalService) GetProjectApprovals(ctx context.Context, filter *entities.ProjectApprovalFilter) ([]*entities.ProjectApproval, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*entities.ProjectApproval), args.Int(1), args.Error(2)
+}
+
+func (m *MockProjectApprovalService) GetPendingApprovals(ctx context.Context, cooperativeID uuid.UUID, page, limit int) ([]*entities.ProjectApproval, int, error) {
+	args := m.Called(ctx, cooperativeID, page, limit)
+	return args.Get(0).([]*entities.ProjectApproval), args.Int(1), args.Error(2)
+}
+
+func (m *MockProjectApprovalService) GetApprovalHistory(ctx context.Context, approvalID uuid.UUID) ([]*entities.ProjectApprovalHistory, error) {
+	args := m.Called(ctx, approvalID)
+	return args.Get(0).([]*entities.ProjectApprovalHistory), args.Error(1)
+}
+
+func (m *MockProjectApprovalService) SubmitCommitteeVote(ctx context.Context, approvalID, memberID uuid.UUID, vote string, comments string) error {
+	args := m.Called(ctx, approvalID, memberID, vote, comments)
+	return args.Error(0)
+}
+
+func (m *MockProjectApprovalService) GetCommitteeVotes(ctx context.Context, approvalID uuid.UUID) ([]entities.CommitteeVote, error) {
+	args := m.Called(ctx, approvalID)
+	return args.Get(0).([]entities.CommitteeVote), args.Error(1)
+}
+
+func (m *MockProjectApprovalService) CalculateApprovalScore(ctx context.Context, approvalID uuid.UUID) (*entities.EvaluationCriteria, error) {
+	args := m.Called(ctx, approvalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.EvaluationCriteria), args.Error(1)
+}
+
+func (m *MockProjectApprovalService) GetExpiringApprovals(ctx context.Context, cooperativeID uuid.UUID, days int) ([]*entities.ProjectApproval, error) {
+	args := m.Called(ctx, cooperativeID, days)
+	return args.Get(0).([]*entities.ProjectApproval), args.Error(1)
+}
+
+func (m *MockProjectApprovalService) SendApprovalNotifications(ctx context.Context, approvalID uuid.UUID) error {
+	args := m.Called(ctx, approvalID)
+	return args.Error(0)
 }
 
 type MockFundMonitoringService struct {
 	mock.Mock
 }
 
-func (m *MockFundMonitoringService) MonitorFunds(ctx context.Context, cooperativeID uuid.UUID) error {
-	args := m.Called(ctx, cooperativeID)
+func (m *MockFundMonitoringService) CreateFundTransfer(ctx context.Context, req *entities.CreateFundTransferRequest, initiatorID uuid.UUID) (*entities.FundTransfer, error) {
+	args := m.Called(ctx, req, initiatorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.FundTransfer), args.Error(1)
+}
+
+func (m *MockFundMonitoringService) UpdateFundTransfer(ctx context.Context, transferID uuid.UUID, req *entities.UpdateFundTransferRequest, updaterID uuid.UUID) (*entities.FundTransfer, error) {
+	args := m.Called(ctx, transferID, req, updaterID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.FundTransfer), args.Error(1)
+}
+
+func (m *MockFundMonitoringService) GetFundTransfer(ctx context.Context, transferID uuid.UUID) (*entities.FundTransfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.FundTransfer), args.Error(1)
+}
+
+func (m *MockFundMonitoringService) GetFundTransfers(ctx context.Context, filter *entities.FundTransferFilter) ([]*entities.FundTransfer, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*entities.FundTransfer), args.Int(1), args.Error(2)
+}
+
+func (m *MockFundMonitoringService) CancelFundTransfer(ctx context.Context, transferID, cancellerID uuid.UUID, reason string) error {
+	args := m.Called(ctx, transferID, cancellerID, reason)
 	return args.Error(0)
 }
 
-func (m *MockFundMonitoringService) ApproveProfitDistribution(ctx context.Context, distributionID uuid.UUID, approverID uuid.UUID) error {
+func (m *MockFundMonitoringService) ProcessPendingTransfers(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockFundMonitoringService) ListTransferAttempts(ctx context.Context, transferID uuid.UUID) ([]*entities.FundTransferAttempt, error) {
+	args := m.Called(ctx, transferID)
+	return args.Get(0).([]*entities.FundTransferAttempt), args.Error(1)
+}
+
+func (m *MockFundMonitoringService) ListDeadLetteredTransfers(ctx context.Context, page, limit int) ([]*entities.FundTransfer, int, error) {
+	args := m.Called(ctx, page, limit)
+	return args.Get(0).([]*entities.FundTransfer), args.Int(1), args.Error(2)
+}
+
+func (m *MockFundMonitoringService) RequeueDeadLetteredTransfer(ctx context.Context, transferID uuid.UUID, req *entities.RequeueFundTransferRequest, adminID uuid.UUID) (*entities.FundTransfer, error) {
+	args := m.Called(ctx, transferID, req, adminID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.FundTransfer), args.Error(1)
+}
+
+func (m *MockFundMonitoringService) CreateProfitDistribution(ctx context.Context, req *entities.CreateProfitDistributionRequest, calculatorID uuid.UUID) (*entities.ProfitDistributionMonitoring, error) {
+	args := m.Called(ctx, req, calculatorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProfitDistributionMonitoring), args.Error(1)
+}
+
+func (m *MockFundMonitoringService) ApproveProfitDistribution(ctx context.Context, distributionID, approverID uuid.UUID) error {
 	args := m.Called(ctx, distributionID, approverID)
 	return args.Error(0)
 }
 
+func (m *MockFundMonitoringService) DistributeProfits(ctx context.Context, distributionID, distributorID uuid.UUID) error {
+	args := m.Called(ctx, distributionID, distributorID)
+	return args.Error(0)
+}
+
+func (m *MockFundMonitoringService) GetProfitDistribution(ctx context.Context, distributionID uuid.UUID) (*entities.ProfitDistributionMonitoring, error) {
+	args := m.Called(ctx, distributionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProfitDistributionMonitoring), args.Error(1)
+}
+
+func (m *MockFundMonitoringService) GetProfitDistributions(ctx context.Context, filter *entities.ProfitDistributionFilter) ([]*entities.ProfitDistributionMonitoring, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*entities.ProfitDistributionMonitoring), args.Int(1), args.Error(2)
+}
+
+func (m *MockFundMonitoringService) GetCooperativeFundSummary(ctx context.Context, cooperativeID uuid.UUID, startDate, endDate time.Time) (map[string]interface{}, error) {
+	args := m.Called(ctx, cooperativeID, startDate, endDate)
+	return args.Get(0).(map[string]interface{}), args.Error(1)
+}
+
+func (m *MockFundMonitoringService) GetProjectFundingStatus(ctx context.Context, projectID uuid.UUID) (map[string]interface{}, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).(map[string]interface{}), args.Error(1)
+}
+
+func (m *MockFundMonitoringService) GenerateFinancialReport(ctx context.Context, cooperativeID uuid.UUID, period string) (map[string]interface{}, error) {
+	args := m.Called(ctx, cooperativeID, period)
+	return args.Get(0).(map[string]interface{}), args.Error(1)
+}
+
+func (m *MockFundMonitoringService) DetectSuspiciousTransactions(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.FundTransfer, error) {
+	args := m.Called(ctx, cooperativeID)
+	return args.Get(0).([]*entities.FundTransfer), args.Error(1)
+}
+
 type MockMemberRegistryService struct {
 	mock.Mock
 }
 
-func (m *MockMemberRegistryService) RegisterMember(ctx context.Context, cooperativeID, userID, registrarID uuid.UUID) error {
-	args := m.Called(ctx, cooperativeID, userID, registrarID)
+func (m *MockMemberRegistryService) AddMemberToCooperative(ctx context.Context, cooperativeID, userID, adderID uuid.UUID, membershipType string) error {
+	args := m.Called(ctx, cooperativeID, userID, adderID, membershipType)
 	return args.Error(0)
 }
 
-func (m *MockMemberRegistryService) AddMemberToCooperative(ctx context.Context, cooperativeID, userID, adderID uuid.UUID) error {
-	args := m.Called(ctx, cooperativeID, userID, adderID)
+func (m *MockMemberRegistryService) RemoveMemberFromCooperative(ctx context.Context, cooperativeID, userID, removerID uuid.UUID, reason string) error {
+	args := m.Called(ctx, cooperativeID, userID, removerID, reason)
+	return args.Error(0)
+}
+
+func (m *MockMemberRegistryService) UpdateMemberStatus(ctx context.Context, cooperativeID, userID, updaterID uuid.UUID, status string) error {
+	args := m.Called(ctx, cooperativeID, userID, updaterID, status)
+	return args.Error(0)
+}
+
+func (m *MockMemberRegistryService) UpdateMembershipType(ctx context.Context, cooperativeID, userID, updaterID uuid.UUID, membershipType string) error {
+	args := m.Called(ctx, cooperativeID, userID, updaterID, membershipType)
+	return args.Error(0)
+}
+
+func (m *MockMemberRegistryService) GetCooperativeMembers(ctx context.Context, cooperativeID uuid.UUID, status string, page, limit int) ([]*entities.User, int, error) {
+	args := m.Called(ctx, cooperativeID, status, page, limit)
+	return args.Get(0).([]*entities.User), args.Int(1), args.Error(2)
+}
+
+func (m *MockMemberRegistryService) GetMembershipHistory(ctx context.Context, cooperativeID, userID uuid.UUID) ([]*MembershipHistory, error) {
+	args := m.Called(ctx, cooperativeID, userID)
+	return args.Get(0).([]*MembershipHistory), args.Error(1)
+}
+
+func (m *MockMemberRegistryService) GetMemberStatistics(ctx context.Context, cooperativeID uuid.UUID) (map[string]interface{}, error) {
+	args := m.Called(ctx, cooperativeID)
+	return args.Get(0).(map[string]interface{}), args.Error(1)
+}
+
+func (m *MockMemberRegistryService) SearchMembers(ctx context.Context, cooperativeID uuid.UUID, query string, page, limit int) ([]*entities.User, int, error) {
+	args := m.Called(ctx, cooperativeID, query, page, limit)
+	return args.Get(0).([]*entities.User), args.Int(1), args.Error(2)
+}
+
+func (m *MockMemberRegistryService) VerifyMemberEligibility(ctx context.Context, userID uuid.UUID, cooperativeID uuid.UUID) (bool, []string, error) {
+	args := m.Called(ctx, userID, cooperativeID)
+	return args.Bool(0), args.Get(1).([]string), args.Error(2)
+}
+
+func (m *MockMemberRegistryService) ValidateMembershipRequirements(ctx context.Context, userID uuid.UUID, membershipType string) (bool, []string, error) {
+	args := m.Called(ctx, userID, membershipType)
+	return args.Bool(0), args.Get(1).([]string), args.Error(2)
+}
+
+func (m *MockMemberRegistryService) CheckMemberActiveStatus(ctx context.Context, cooperativeID, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, cooperativeID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMemberRegistryService) AssignMemberRole(ctx context.Context, cooperativeID, userID, assignerID uuid.UUID, role string) error {
+	args := m.Called(ctx, cooperativeID, userID, assignerID, role)
+	return args.Error(0)
+}
+
+func (m *MockMemberRegistryService) RemoveMemberRole(ctx context.Context, cooperativeID, userID, removerID uuid.UUID, role string) error {
+	args := m.Called(ctx, cooperativeID, userID, removerID, role)
+	return args.Error(0)
+}
+
+func (m *MockMemberRegistryService) GetMemberRoles(ctx context.Context, cooperativeID, userID uuid.UUID) ([]string, error) {
+	args := m.Called(ctx, cooperativeID, userID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockMemberRegistryService) RecordMemberContribution(ctx context.Context, cooperativeID, userID uuid.UUID, contribution *MemberContribution) error {
+	args := m.Called(ctx, cooperativeID, userID, contribution)
+	return args.Error(0)
+}
+
+func (m *MockMemberRegistryService) GetMemberContributions(ctx context.Context, cooperativeID, userID uuid.UUID, startDate, endDate time.Time) ([]*MemberContribution, error) {
+	args := m.Called(ctx, cooperativeID, userID, startDate, endDate)
+	return args.Get(0).([]*MemberContribution), args.Error(1)
+}
+
+func (m *MockMemberRegistryService) CalculateMemberBenefits(ctx context.Context, cooperativeID, userID uuid.UUID, period string) (map[string]interface{}, error) {
+	args := m.Called(ctx, cooperativeID, userID, period)
+	return args.Get(0).(map[string]interface{}), args.Error(1)
+}
+
+func (m *MockMemberRegistryService) NotifyMemberStatusChange(ctx context.Context, cooperativeID, userID uuid.UUID, oldStatus, newStatus string) error {
+	args := m.Called(ctx, cooperativeID, userID, oldStatus, newStatus)
+	return args.Error(0)
+}
+
+func (m *MockMemberRegistryService) SendMembershipWelcome(ctx context.Context, cooperativeID, userID uuid.UUID) error {
+	args := m.Called(ctx, cooperativeID, userID)
+	return args.Error(0)
+}
+
+func (m *MockMemberRegistryService) SendMembershipReminder(ctx context.Context, cooperativeID uuid.UUID, reminderType string) (int, error) {
+	args := m.Called(ctx, cooperativeID, reminderType)
+	return args.Int(0), args.Error(1)
+}
+
+type MockLedgerRepository struct {
+	mock.Mock
+}
+
+func (m *MockLedgerRepository) CreateAccount(ctx context.Context, account *entities.LedgerAccount) error {
+	args := m.Called(ctx, account)
+	return args.Error(0)
+}
+
+func (m *MockLedgerRepository) GetAccountByID(ctx context.Context, id uuid.UUID) (*entities.LedgerAccount, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.LedgerAccount), args.Error(1)
+}
+
+func (m *MockLedgerRepository) GetAccountByCode(ctx context.Context, cooperativeID uuid.UUID, code, currency string) (*entities.LedgerAccount, error) {
+	args := m.Called(ctx, cooperativeID, code, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.LedgerAccount), args.Error(1)
+}
+
+func (m *MockLedgerRepository) ListAccounts(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.LedgerAccount, error) {
+	args := m.Called(ctx, cooperativeID)
+	return args.Get(0).([]*entities.LedgerAccount), args.Error(1)
+}
+
+func (m *MockLedgerRepository) PostEntry(ctx context.Context, entry *entities.JournalEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockLedgerRepository) PostFundedEntry(ctx context.Context, entry *entities.JournalEntry, fundingAccountID uuid.UUID) error {
+	args := m.Called(ctx, entry, fundingAccountID)
+	return args.Error(0)
+}
+
+func (m *MockLedgerRepository) GetEntryByID(ctx context.Context, id uuid.UUID) (*entities.JournalEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.JournalEntry), args.Error(1)
+}
+
+func (m *MockLedgerRepository) GetEntriesByAccount(ctx context.Context, account *entities.LedgerAccount, limit, offset int) ([]*entities.JournalEntry, int, error) {
+	args := m.Called(ctx, account, limit, offset)
+	return args.Get(0).([]*entities.JournalEntry), args.Int(1), args.Error(2)
+}
+
+func (m *MockLedgerRepository) GetAccountTotals(ctx context.Context, account *entities.LedgerAccount, asOf *time.Time) (entities.Money, entities.Money, error) {
+	args := m.Called(ctx, account, asOf)
+	return args.Get(0).(entities.Money), args.Get(1).(entities.Money), args.Error(2)
+}
+
+func (m *MockLedgerRepository) GetProjectCategoryTotals(ctx context.Context, projectID uuid.UUID, category string) ([]*entities.LedgerCurrencyTotals, error) {
+	args := m.Called(ctx, projectID, category)
+	return args.Get(0).([]*entities.LedgerCurrencyTotals), args.Error(1)
+}
+
+// MockCurrencyRepository for testing
+type MockCurrencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockCurrencyRepository) UpsertCurrency(ctx context.Context, currency *entities.Currency) error {
+	args := m.Called(ctx, currency)
+	return args.Error(0)
+}
+
+func (m *MockCurrencyRepository) GetCurrency(ctx context.Context, code string) (*entities.Currency, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Currency), args.Error(1)
+}
+
+func (m *MockCurrencyRepository) ListCurrencies(ctx context.Context, activeOnly bool) ([]*entities.Currency, error) {
+	args := m.Called(ctx, activeOnly)
+	return args.Get(0).([]*entities.Currency), args.Error(1)
+}
+
+func (m *MockCurrencyRepository) CreateExchangeRate(ctx context.Context, rate *entities.ExchangeRate) error {
+	args := m.Called(ctx, rate)
 	return args.Error(0)
 }
+
+func (m *MockCurrencyRepository) GetEffectiveRate(ctx context.Context, base, quote string, at time.Time) (*entities.ExchangeRate, error) {
+	args := m.Called(ctx, base, quote, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockCurrencyRepository) ListExchangeRates(ctx context.Context, base, quote string, limit, offset int) ([]*entities.ExchangeRate, int, error) {
+	args := m.Called(ctx, base, quote, limit, offset)
+	return args.Get(0).([]*entities.ExchangeRate), args.Int(1), args.Error(2)
+}
+
+// MockBankReconciliationRepository for testing
+type MockBankReconciliationRepository struct {
+	mock.Mock
+}
+
+func (m *MockBankReconciliationRepository) CreateStatement(ctx context.Context, statement *entities.BankStatement, lines []*entities.BankStatementLine) error {
+	args := m.Called(ctx, statement, lines)
+	return args.Error(0)
+}
+
+func (m *MockBankReconciliationRepository) GetStatement(ctx context.Context, id uuid.UUID) (*entities.BankStatement, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BankStatement), args.Error(1)
+}
+
+func (m *MockBankReconciliationRepository) GetStatementByFileHash(ctx context.Context, cooperativeID uuid.UUID, fileHash string) (*entities.BankStatement, error) {
+	args := m.Called(ctx, cooperativeID, fileHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BankStatement), args.Error(1)
+}
+
+func (m *MockBankReconciliationRepository) ListStatements(ctx context.Context, cooperativeID uuid.UUID, limit, offset int) ([]*entities.BankStatement, int, error) {
+	args := m.Called(ctx, cooperativeID, limit, offset)
+	return args.Get(0).([]*entities.BankStatement), args.Int(1), args.Error(2)
+}
+
+func (m *MockBankReconciliationRepository) RefreshStatementCounts(ctx context.Context, statement *entities.BankStatement) error {
+	args := m.Called(ctx, statement)
+	return args.Error(0)
+}
+
+func (m *MockBankReconciliationRepository) GetLine(ctx context.Context, id uuid.UUID) (*entities.BankStatementLine, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BankStatementLine), args.Error(1)
+}
+
+func (m *MockBankReconciliationRepository) ListLines(ctx context.Context, filter *entities.BankStatementLineFilter) ([]*entities.BankStatementLine, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*entities.BankStatementLine), args.Int(1), args.Error(2)
+}
+
+func (m *MockBankReconciliationRepository) ListOpenLines(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.BankStatementLine, error) {
+	args := m.Called(ctx, cooperativeID)
+	return args.Get(0).([]*entities.BankStatementLine), args.Error(1)
+}
+
+func (m *MockBankReconciliationRepository) UpdateLine(ctx context.Context, line *entities.BankStatementLine) error {
+	args := m.Called(ctx, line)
+	return args.Error(0)
+}
+
+func (m *MockBankReconciliationRepository) ExistingTransactionIDs(ctx context.Context, cooperativeID uuid.UUID, transactionIDs []string) (map[string]bool, error) {
+	args := m.Called(ctx, cooperativeID, transactionIDs)
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *MockBankReconciliationRepository) MatchedEntityIDs(ctx context.Context, cooperativeID uuid.UUID, entityIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	args := m.Called(ctx, cooperativeID, entityIDs)
+	return args.Get(0).(map[uuid.UUID]bool), args.Error(1)
+}
+
+// MockPaymentRepository for testing
+type MockPaymentRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaymentRepository) Create(ctx context.Context, payment *entities.PaymentRequest) error {
+	args := m.Called(ctx, payment)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.PaymentRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.PaymentRequest), args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetByProviderReference(ctx context.Context, provider, providerReference string) (*entities.PaymentRequest, error) {
+	args := m.Called(ctx, provider, providerReference)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.PaymentRequest), args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetPendingByInvestment(ctx context.Context, cooperativeID, investmentID uuid.UUID) (*entities.PaymentRequest, error) {
+	args := m.Called(ctx, cooperativeID, investmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.PaymentRequest), args.Error(1)
+}
+
+func (m *MockPaymentRepository) UpdateStatus(ctx context.Context, payment *entities.PaymentRequest, fromStatus string) (bool, error) {
+	args := m.Called(ctx, payment, fromStatus)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockPayoutRepository for testing
+type MockPayoutRepository struct {
+	mock.Mock
+}
+
+func (m *MockPayoutRepository) CreateItems(ctx context.Context, items []*entities.PayoutItem) error {
+	args := m.Called(ctx, items)
+	return args.Error(0)
+}
+
+func (m *MockPayoutRepository) GetItem(ctx context.Context, id uuid.UUID) (*entities.PayoutItem, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.PayoutItem), args.Error(1)
+}
+
+func (m *MockPayoutRepository) ListQueuedItems(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.PayoutItem, error) {
+	args := m.Called(ctx, cooperativeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.PayoutItem), args.Error(1)
+}
+
+func (m *MockPayoutRepository) UpdateItem(ctx context.Context, item *entities.PayoutItem) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockPayoutRepository) CreateBatch(ctx context.Context, batch *entities.PayoutBatch, items []*entities.PayoutItem) error {
+	args := m.Called(ctx, batch, items)
+	return args.Error(0)
+}
+
+func (m *MockPayoutRepository) GetBatch(ctx context.Context, id uuid.UUID) (*entities.PayoutBatch, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.PayoutBatch), args.Error(1)
+}
+
+func (m *MockPayoutRepository) ListBatches(ctx context.Context, cooperativeID uuid.UUID, limit, offset int) ([]*entities.PayoutBatch, int, error) {
+	args := m.Called(ctx, cooperativeID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*entities.PayoutBatch), args.Int(1), args.Error(2)
+}
+
+func (m *MockPayoutRepository) ListBatchItems(ctx context.Context, batch *entities.PayoutBatch) ([]*entities.PayoutItem, error) {
+	args := m.Called(ctx, batch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.PayoutItem), args.Error(1)
+}
+
+func (m *MockPayoutRepository) UpdateBatch(ctx context.Context, batch *entities.PayoutBatch) error {
+	args := m.Called(ctx, batch)
+	return args.Error(0)
+}
+
+func (m *MockPayoutRepository) SaveReturn(ctx context.Context, batch *entities.PayoutBatch, items []*entities.PayoutItem) error {
+	args := m.Called(ctx, batch, items)
+	return args.Error(0)
+}
+
+// MockProjectLossRepository for testing
+type MockProjectLossRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectLossRepository) CreateLoss(ctx context.Context, loss *entities.ProjectLoss, shares []*entities.InvestorLossShare) error {
+	args := m.Called(ctx, loss, shares)
+	return args.Error(0)
+}
+
+func (m *MockProjectLossRepository) ListProjectLosses(ctx context.Context, projectID uuid.UUID) ([]*entities.ProjectLoss, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ProjectLoss), args.Error(1)
+}
+
+func (m *MockProjectLossRepository) ListLossShares(ctx context.Context, loss *entities.ProjectLoss) ([]*entities.InvestorLossShare, error) {
+	args := m.Called(ctx, loss)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.InvestorLossShare), args.Error(1)
+}
+
+func (m *MockProjectLossRepository) ListInvestorLossShares(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestorLossShare, error) {
+	args := m.Called(ctx, investorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.InvestorLossShare), args.Error(1)
+}
+
+func (m *MockProjectLossRepository) ListRecoveries(ctx context.Context, projectID uuid.UUID) ([]*entities.ProjectLossRecovery, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ProjectLossRecovery), args.Error(1)
+}
+
+func (m *MockProjectLossRepository) ApplyRecoveries(ctx context.Context, projectID uuid.UUID, recoveries []*entities.ProjectLossRecovery, losses []*entities.ProjectLoss, shares []*entities.InvestorLossShare) error {
+	args := m.Called(ctx, projectID, recoveries, losses, shares)
+	return args.Error(0)
+}
+
+type MockReinvestmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockReinvestmentRepository) UpsertPreference(ctx context.Context, preference *entities.ReinvestmentPreference) error {
+	args := m.Called(ctx, preference)
+	return args.Error(0)
+}
+
+func (m *MockReinvestmentRepository) GetPreference(ctx context.Context, cooperativeID, investorID uuid.UUID) (*entities.ReinvestmentPreference, error) {
+	args := m.Called(ctx, cooperativeID, investorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ReinvestmentPreference), args.Error(1)
+}
+
+func (m *MockReinvestmentRepository) ListActivePreferences(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.ReinvestmentPreference, error) {
+	args := m.Called(ctx, cooperativeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ReinvestmentPreference), args.Error(1)
+}
+
+func (m *MockReinvestmentRepository) DeactivatePreference(ctx context.Context, cooperativeID, investorID uuid.UUID) error {
+	args := m.Called(ctx, cooperativeID, investorID)
+	return args.Error(0)
+}
+
+func (m *MockReinvestmentRepository) CreateReinvestments(ctx context.Context, reinvestments []*entities.Reinvestment) error {
+	args := m.Called(ctx, reinvestments)
+	return args.Error(0)
+}
+
+func (m *MockReinvestmentRepository) ListInvestorReinvestments(ctx context.Context, cooperativeID, investorID uuid.UUID) ([]*entities.Reinvestment, error) {
+	args := m.Called(ctx, cooperativeID, investorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Reinvestment), args.Error(1)
+}
+
+func (m *MockReinvestmentRepository) ListOpenProjects(ctx context.Context, cooperativeID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, cooperativeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+// MockZakatRepository for testing
+type MockZakatRepository struct {
+	mock.Mock
+}
+
+func (m *MockZakatRepository) UpsertRecipient(ctx context.Context, recipient *entities.ZakatRecipient) error {
+	args := m.Called(ctx, recipient)
+	return args.Error(0)
+}
+
+func (m *MockZakatRepository) GetRecipient(ctx context.Context, cooperativeID uuid.UUID) (*entities.ZakatRecipient, error) {
+	args := m.Called(ctx, cooperativeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ZakatRecipient), args.Error(1)
+}
+
+func (m *MockZakatRepository) CreatePayment(ctx context.Context, payment *entities.ZakatPayment) error {
+	args := m.Called(ctx, payment)
+	return args.Error(0)
+}
+
+func (m *MockZakatRepository) GetPayment(ctx context.Context, cooperativeID, paymentID uuid.UUID) (*entities.ZakatPayment, error) {
+	args := m.Called(ctx, cooperativeID, paymentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ZakatPayment), args.Error(1)
+}
+
+func (m *MockZakatRepository) UpdatePaymentStatus(ctx context.Context, payment *entities.ZakatPayment, fromStatus string) (bool, error) {
+	args := m.Called(ctx, payment, fromStatus)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockZakatRepository) ListInvestorPayments(ctx context.Context, investorID uuid.UUID) ([]*entities.ZakatPayment, error) {
+	args := m.Called(ctx, investorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ZakatPayment), args.Error(1)
+}
+
+// MockShariaScreeningRepository for testing
+type MockShariaScreeningRepository struct {
+	mock.Mock
+}
+
+func (m *MockShariaScreeningRepository) CreateRuleSet(ctx context.Context, ruleSet *entities.ShariaScreeningRuleSet) error {
+	args := m.Called(ctx, ruleSet)
+	return args.Error(0)
+}
+
+func (m *MockShariaScreeningRepository) UpdateRuleSet(ctx context.Context, ruleSet *entities.ShariaScreeningRuleSet) error {
+	args := m.Called(ctx, ruleSet)
+	return args.Error(0)
+}
+
+func (m *MockShariaScreeningRepository) GetRuleSet(ctx context.Context, cooperativeID, ruleSetID uuid.UUID) (*entities.ShariaScreeningRuleSet, error) {
+	args := m.Called(ctx, cooperativeID, ruleSetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ShariaScreeningRuleSet), args.Error(1)
+}
+
+func (m *MockShariaScreeningRepository) ListActiveRuleSets(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.ShariaScreeningRuleSet, error) {
+	args := m.Called(ctx, cooperativeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ShariaScreeningRuleSet), args.Error(1)
+}
+
+func (m *MockShariaScreeningRepository) DeactivateRuleSet(ctx context.Context, cooperativeID, ruleSetID uuid.UUID) error {
+	args := m.Called(ctx, cooperativeID, ruleSetID)
+	return args.Error(0)
+}
+
+func (m *MockShariaScreeningRepository) GetProjectActivity(ctx context.Context, projectID uuid.UUID) (*entities.ShariaProjectActivity, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ShariaProjectActivity), args.Error(1)
+}
+
+// MockProjectContractRepository for testing
+type MockProjectContractRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectContractRepository) SaveContract(ctx context.Context, contract *entities.ProjectContract, installments []*entities.MurabahahInstallment) error {
+	args := m.Called(ctx, contract, installments)
+	return args.Error(0)
+}
+
+func (m *MockProjectContractRepository) GetContract(ctx context.Context, projectID uuid.UUID) (*entities.ProjectContract, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectContract), args.Error(1)
+}
+
+func (m *MockProjectContractRepository) ListInstallments(ctx context.Context, projectID uuid.UUID) ([]*entities.MurabahahInstallment, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.MurabahahInstallment), args.Error(1)
+}
+
+func (m *MockProjectContractRepository) GetInstallment(ctx context.Context, projectID, installmentID uuid.UUID) (*entities.MurabahahInstallment, error) {
+	args := m.Called(ctx, projectID, installmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.MurabahahInstallment), args.Error(1)
+}
+
+func (m *MockProjectContractRepository) UpdateInstallment(ctx context.Context, installment *entities.MurabahahInstallment, fromStatus string) (bool, error) {
+	args := m.Called(ctx, installment, fromStatus)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockInvestmentWithdrawalRepository for testing
+type MockInvestmentWithdrawalRepository struct {
+	mock.Mock
+}
+
+func (m *MockInvestmentWithdrawalRepository) GetInvestmentPosition(ctx context.Context, projectID, investmentID uuid.UUID) (*entities.InvestmentPosition, error) {
+	args := m.Called(ctx, projectID, investmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.InvestmentPosition), args.Error(1)
+}
+
+func (m *MockInvestmentWithdrawalRepository) CreateWithdrawal(ctx context.Context, withdrawal *entities.InvestmentWithdrawal) error {
+	args := m.Called(ctx, withdrawal)
+	return args.Error(0)
+}
+
+func (m *MockInvestmentWithdrawalRepository) GetWithdrawal(ctx context.Context, projectID, withdrawalID uuid.UUID) (*entities.InvestmentWithdrawal, error) {
+	args := m.Called(ctx, projectID, withdrawalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.InvestmentWithdrawal), args.Error(1)
+}
+
+func (m *MockInvestmentWithdrawalRepository) GetOpenWithdrawal(ctx context.Context, projectID, investmentID uuid.UUID) (*entities.InvestmentWithdrawal, error) {
+	args := m.Called(ctx, projectID, investmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.InvestmentWithdrawal), args.Error(1)
+}
+
+func (m *MockInvestmentWithdrawalRepository) ListInvestorWithdrawals(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestmentWithdrawal, error) {
+	args := m.Called(ctx, investorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.InvestmentWithdrawal), args.Error(1)
+}
+
+func (m *MockInvestmentWithdrawalRepository) ListCooperativeWithdrawals(ctx context.Context, cooperativeID uuid.UUID, status string) ([]*entities.InvestmentWithdrawal, error) {
+	args := m.Called(ctx, cooperativeID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.InvestmentWithdrawal), args.Error(1)
+}
+
+func (m *MockInvestmentWithdrawalRepository) UpdateWithdrawalStatus(ctx context.Context, withdrawal *entities.InvestmentWithdrawal, fromStatus string) (bool, error) {
+	args := m.Called(ctx, withdrawal, fromStatus)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockInvestmentWithdrawalRepository) ExitWithdrawal(ctx context.Context, withdrawal *entities.InvestmentWithdrawal, refund *entities.FundRefund, investorRefunds []*entities.InvestorRefund) error {
+	args := m.Called(ctx, withdrawal, refund, investorRefunds)
+	return args.Error(0)
+}
+
+func (m *MockInvestmentWithdrawalRepository) FinishWithdrawal(ctx context.Context, withdrawal *entities.InvestmentWithdrawal) error {
+	args := m.Called(ctx, withdrawal)
+	return args.Error(0)
+}
+
+func (m *MockInvestmentWithdrawalRepository) ListCompletingWithdrawals(ctx context.Context) ([]*entities.InvestmentWithdrawal, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*entities.InvestmentWithdrawal), args.Error(1)
+}
+
+func (m *MockInvestmentWithdrawalRepository) GetWithdrawalRefund(ctx context.Context, withdrawal *entities.InvestmentWithdrawal) (*entities.FundRefund, []*entities.InvestorRefund, error) {
+	args := m.Called(ctx, withdrawal)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*entities.FundRefund), args.Get(1).([]*entities.InvestorRefund), args.Error(2)
+}
+
+// MockStakeMarketRepository for testing
+type MockStakeMarketRepository struct {
+	mock.Mock
+}
+
+func (m *MockStakeMarketRepository) GetInvestmentPosition(ctx context.Context, projectID, investmentID uuid.UUID) (*entities.InvestmentPosition, error) {
+	args := m.Called(ctx, projectID, investmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.InvestmentPosition), args.Error(1)
+}
+
+func (m *MockStakeMarketRepository) GetInvestorPosition(ctx context.Context, projectID, investorID uuid.UUID) (*entities.InvestmentPosition, error) {
+	args := m.Called(ctx, projectID, investorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.InvestmentPosition), args.Error(1)
+}
+
+func (m *MockStakeMarketRepository) CreateListing(ctx context.Context, listing *entities.StakeListing) error {
+	args := m.Called(ctx, listing)
+	return args.Error(0)
+}
+
+func (m *MockStakeMarketRepository) GetListing(ctx context.Context, projectID, listingID uuid.UUID) (*entities.StakeListing, error) {
+	args := m.Called(ctx, projectID, listingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.StakeListing), args.Error(1)
+}
+
+func (m *MockStakeMarketRepository) ListOpenListings(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.StakeListing, error) {
+	args := m.Called(ctx, cooperativeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.StakeListing), args.Error(1)
+}
+
+func (m *MockStakeMarketRepository) UpdateListingStatus(ctx context.Context, listing *entities.StakeListing, fromStatus string) (bool, error) {
+	args := m.Called(ctx, listing, fromStatus)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStakeMarketRepository) CreateBid(ctx context.Context, bid *entities.StakeBid) error {
+	args := m.Called(ctx, bid)
+	return args.Error(0)
+}
+
+func (m *MockStakeMarketRepository) GetBid(ctx context.Context, projectID, bidID uuid.UUID) (*entities.StakeBid, error) {
+	args := m.Called(ctx, projectID, bidID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.StakeBid), args.Error(1)
+}
+
+func (m *MockStakeMarketRepository) ListBids(ctx context.Context, projectID, listingID uuid.UUID) ([]*entities.StakeBid, error) {
+	args := m.Called(ctx, projectID, listingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.StakeBid), args.Error(1)
+}
+
+func (m *MockStakeMarketRepository) UpdateBidStatus(ctx context.Context, bid *entities.StakeBid, fromStatus string) (bool, error) {
+	args := m.Called(ctx, bid, fromStatus)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStakeMarketRepository) CreateTrade(ctx context.Context, trade *entities.StakeTrade) error {
+	args := m.Called(ctx, trade)
+	return args.Error(0)
+}
+
+func (m *MockStakeMarketRepository) GetTrade(ctx context.Context, projectID, tradeID uuid.UUID) (*entities.StakeTrade, error) {
+	args := m.Called(ctx, projectID, tradeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.StakeTrade), args.Error(1)
+}
+
+func (m *MockStakeMarketRepository) ListCooperativeTrades(ctx context.Context, cooperativeID uuid.UUID, status string) ([]*entities.StakeTrade, error) {
+	args := m.Called(ctx, cooperativeID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.StakeTrade), args.Error(1)
+}
+
+func (m *MockStakeMarketRepository) RejectTrade(ctx context.Context, trade *entities.StakeTrade) error {
+	args := m.Called(ctx, trade)
+	return args.Error(0)
+}
+
+func (m *MockStakeMarketRepository) TransferTrade(ctx context.Context, trade *entities.StakeTrade) error {
+	args := m.Called(ctx, trade)
+	return args.Error(0)
+}
+
+func (m *MockStakeMarketRepository) FinishTrade(ctx context.Context, trade *entities.StakeTrade) error {
+	args := m.Called(ctx, trade)
+	return args.Error(0)
+}
+
+func (m *MockStakeMarketRepository) ListCompletingTrades(ctx context.Context) ([]*entities.StakeTrade, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*entities.StakeTrade), args.Error(1)
+}
+
+// MockProjectFundingRepository for testing
+type MockProjectFundingRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectFundingRepository) GetFundingProject(ctx context.Context, projectID uuid.UUID) (*entities.FundingProject, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.FundingProject), args.Error(1)
+}
+
+func (m *MockProjectFundingRepository) ListDueProjects(ctx context.Context, asOf time.Time) ([]*entities.FundingProject, error) {
+	args := m.Called(ctx, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.FundingProject), args.Error(1)
+}
+
+func (m *MockProjectFundingRepository) ListConfirmedInvestments(ctx context.Context, projectID uuid.UUID) ([]*entities.Investment, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Investment), args.Error(1)
+}
+
+func (m *MockProjectFundingRepository) GetClosure(ctx context.Context, projectID uuid.UUID) (*entities.ProjectFundingClosure, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectFundingClosure), args.Error(1)
+}
+
+func (m *MockProjectFundingRepository) CloseFunding(ctx context.Context, closure *entities.ProjectFundingClosure, refund *entities.FundRefund, investorRefunds []*entities.InvestorRefund) error {
+	args := m.Called(ctx, closure, refund, investorRefunds)
+	return args.Error(0)
+}
+
+func (m *MockProjectFundingRepository) ListUnfinishedClosures(ctx context.Context) ([]*entities.ProjectFundingClosure, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ProjectFundingClosure), args.Error(1)
+}
+
+func (m *MockProjectFundingRepository) RecordClosureFee(ctx context.Context, closure *entities.ProjectFundingClosure) error {
+	args := m.Called(ctx, closure)
+	return args.Error(0)
+}
+
+func (m *MockProjectFundingRepository) GetClosureRefund(ctx context.Context, closure *entities.ProjectFundingClosure) (*entities.FundRefund, []*entities.InvestorRefund, error) {
+	args := m.Called(ctx, closure)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*entities.FundRefund), args.Get(1).([]*entities.InvestorRefund), args.Error(2)
+}
+
+func (m *MockProjectFundingRepository) MarkClosureRefundPosted(ctx context.Context, closure *entities.ProjectFundingClosure, postedAt time.Time) error {
+	args := m.Called(ctx, closure, postedAt)
+	return args.Error(0)
+}
+
+func (m *MockProjectFundingRepository) MarkClosureNotified(ctx context.Context, closure *entities.ProjectFundingClosure, notifiedAt time.Time) error {
+	args := m.Called(ctx, closure, notifiedAt)
+	return args.Error(0)
+}
+
+func (m *MockProjectFundingRepository) MarkFunded(ctx context.Context, projectID uuid.UUID, fundedAt time.Time) (bool, error) {
+	args := m.Called(ctx, projectID, fundedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockNotificationRepository for testing
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) CreateNotifications(ctx context.Context, notifications []*entities.Notification) error {
+	args := m.Called(ctx, notifications)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) ListUserNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]*entities.Notification, error) {
+	args := m.Called(ctx, userID, unreadOnly, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID, notificationID uuid.UUID, readAt time.Time) (bool, error) {
+	args := m.Called(ctx, userID, notificationID, readAt)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockDisbursementRepository for testing
+type MockDisbursementRepository struct {
+	mock.Mock
+}
+
+func (m *MockDisbursementRepository) GetDisbursementProject(ctx context.Context, projectID uuid.UUID) (*entities.DisbursementProject, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.DisbursementProject), args.Error(1)
+}
+
+func (m *MockDisbursementRepository) CreateTranches(ctx context.Context, projectID uuid.UUID, fundedAmount entities.Money, tranches []*entities.DisbursementTranche) error {
+	args := m.Called(ctx, projectID, fundedAmount, tranches)
+	return args.Error(0)
+}
+
+func (m *MockDisbursementRepository) ListTranches(ctx context.Context, projectID uuid.UUID) ([]*entities.DisbursementTranche, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.DisbursementTranche), args.Error(1)
+}
+
+func (m *MockDisbursementRepository) GetTranche(ctx context.Context, projectID, trancheID uuid.UUID) (*entities.DisbursementTranche, error) {
+	args := m.Called(ctx, projectID, trancheID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.DisbursementTranche), args.Error(1)
+}
+
+func (m *MockDisbursementRepository) GetMilestoneTranche(ctx context.Context, projectID, milestoneID uuid.UUID) (*entities.DisbursementTranche, error) {
+	args := m.Called(ctx, projectID, milestoneID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.DisbursementTranche), args.Error(1)
+}
+
+func (m *MockDisbursementRepository) UpdateTrancheMilestone(ctx context.Context, tranche *entities.DisbursementTranche, fromStatus, fromMilestoneStatus string) (bool, error) {
+	args := m.Called(ctx, tranche, fromStatus, fromMilestoneStatus)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDisbursementRepository) CloseTranche(ctx context.Context, projectID, trancheID, closerID uuid.UUID, reason string, closedAt time.Time) (*entities.DisbursementTranche, error) {
+	args := m.Called(ctx, projectID, trancheID, closerID, reason, closedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.DisbursementTranche), args.Error(1)
+}
+
+func (m *MockDisbursementRepository) CreateDisbursement(ctx context.Context, disbursement *entities.FundDisbursement, requesterID uuid.UUID) error {
+	args := m.Called(ctx, disbursement, requesterID)
+	return args.Error(0)
+}
+
+func (m *MockDisbursementRepository) GetDisbursement(ctx context.Context, disbursementID uuid.UUID) (*entities.FundDisbursement, error) {
+	args := m.Called(ctx, disbursementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.FundDisbursement), args.Error(1)
+}
+
+func (m *MockDisbursementRepository) ListProjectDisbursements(ctx context.Context, projectID uuid.UUID, limit, offset int) ([]*entities.FundDisbursement, int, error) {
+	args := m.Called(ctx, projectID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*entities.FundDisbursement), args.Int(1), args.Error(2)
+}
+
+func (m *MockDisbursementRepository) GetOutstandingAmount(ctx context.Context, projectID uuid.UUID, currency string) (entities.Money, error) {
+	args := m.Called(ctx, projectID, currency)
+	return args.Get(0).(entities.Money), args.Error(1)
+}
+
+func (m *MockDisbursementRepository) UpdateDisbursementStatus(ctx context.Context, disbursement *entities.FundDisbursement, fromStatus string) (bool, error) {
+	args := m.Called(ctx, disbursement, fromStatus)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDisbursementRepository) MarkDisbursed(ctx context.Context, disbursement *entities.FundDisbursement, disbursedAt time.Time) (*entities.DisbursementTranche, error) {
+	args := m.Called(ctx, disbursement, disbursedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.DisbursementTranche), args.Error(1)
+}
+
+// MockFeeRepository for testing
+type MockFeeRepository struct {
+	mock.Mock
+}
+
+func (m *MockFeeRepository) SaveFee(ctx context.Context, fee *entities.ComFundsFee) error {
+	args := m.Called(ctx, fee)
+	return args.Error(0)
+}
+
+func (m *MockFeeRepository) GetFee(ctx context.Context, feeID uuid.UUID) (*entities.ComFundsFee, error) {
+	args := m.Called(ctx, feeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ComFundsFee), args.Error(1)
+}
+
+func (m *MockFeeRepository) ListFees(ctx context.Context, filter *entities.ComFundsFeeFilter) ([]*entities.ComFundsFee, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*entities.ComFundsFee), args.Int(1), args.Error(2)
+}
+
+func (m *MockFeeRepository) ListActiveFees(ctx context.Context, feeType string) ([]*entities.ComFundsFee, error) {
+	args := m.Called(ctx, feeType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ComFundsFee), args.Error(1)
+}
+
+func (m *MockFeeRepository) CreateWaiver(ctx context.Context, waiver *entities.FeeWaiver) error {
+	args := m.Called(ctx, waiver)
+	return args.Error(0)
+}
+
+func (m *MockFeeRepository) RevokeWaiver(ctx context.Context, waiverID, revokerID uuid.UUID, revokedAt time.Time) error {
+	args := m.Called(ctx, waiverID, revokerID, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockFeeRepository) ListWaivers(ctx context.Context, feeType string, activeOnly bool) ([]*entities.FeeWaiver, error) {
+	args := m.Called(ctx, feeType, activeOnly)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.FeeWaiver), args.Error(1)
+}
+
+func (m *MockFeeRepository) GetFeeProject(ctx context.Context, projectID uuid.UUID) (*entities.FeeProject, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.FeeProject), args.Error(1)
+}
+
+func (m *MockFeeRepository) CreateCalculation(ctx context.Context, calculation *entities.ProjectFeeCalculation, creditNote *entities.FeeCreditNote) error {
+	args := m.Called(ctx, calculation, creditNote)
+	return args.Error(0)
+}
+
+func (m *MockFeeRepository) GetClosureCalculation(ctx context.Context, cooperativeID, closureID uuid.UUID) (*entities.ProjectFeeCalculation, error) {
+	args := m.Called(ctx, cooperativeID, closureID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectFeeCalculation), args.Error(1)
+}
+
+func (m *MockFeeRepository) GetCalculation(ctx context.Context, calculationID uuid.UUID) (*entities.ProjectFeeCalculation, error) {
+	args := m.Called(ctx, calculationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectFeeCalculation), args.Error(1)
+}
+
+func (m *MockFeeRepository) ListCalculations(ctx context.Context, cooperativeID uuid.UUID, filter *entities.ProjectFeeCalculationFilter) ([]*entities.ProjectFeeCalculation, int, error) {
+	args := m.Called(ctx, cooperativeID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*entities.ProjectFeeCalculation), args.Int(1), args.Error(2)
+}
+
+func (m *MockFeeRepository) CollectFee(ctx context.Context, calculation *entities.ProjectFeeCalculation, invoice *entities.FeeInvoice, entry *entities.JournalEntry) error {
+	args := m.Called(ctx, calculation, invoice, entry)
+	return args.Error(0)
+}
+
+func (m *MockFeeRepository) WaiveFee(ctx context.Context, calculation *entities.ProjectFeeCalculation, creditNote *entities.FeeCreditNote) error {
+	args := m.Called(ctx, calculation, creditNote)
+	return args.Error(0)
+}
+
+func (m *MockFeeRepository) ListInvoices(ctx context.Context, cooperativeID uuid.UUID, limit, offset int) ([]*entities.FeeInvoice, int, error) {
+	args := m.Called(ctx, cooperativeID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*entities.FeeInvoice), args.Int(1), args.Error(2)
+}
+
+func (m *MockFeeRepository) ListCreditNotes(ctx context.Context, cooperativeID uuid.UUID, limit, offset int) ([]*entities.FeeCreditNote, int, error) {
+	args := m.Called(ctx, cooperativeID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*entities.FeeCreditNote), args.Int(1), args.Error(2)
+}
+
+// MockInvestorStatementRepository for testing
+type MockInvestorStatementRepository struct {
+	mock.Mock
+}
+
+func (m *MockInvestorStatementRepository) ListInvestorAccounts(ctx context.Context, openedBefore time.Time) ([]*entities.LedgerAccount, error) {
+	args := m.Called(ctx, openedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.LedgerAccount), args.Error(1)
+}
+
+func (m *MockInvestorStatementRepository) GetStatementBranding(ctx context.Context, cooperativeID uuid.UUID) (*entities.StatementBranding, error) {
+	args := m.Called(ctx, cooperativeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.StatementBranding), args.Error(1)
+}
+
+func (m *MockInvestorStatementRepository) GetAccountPosition(ctx context.Context, account *entities.LedgerAccount, before time.Time) (entities.Money, error) {
+	args := m.Called(ctx, account, before)
+	return args.Get(0).(entities.Money), args.Error(1)
+}
+
+func (m *MockInvestorStatementRepository) GetStatementLines(ctx context.Context, account *entities.LedgerAccount, start, end time.Time) ([]*entities.StatementLedgerLine, error) {
+	args := m.Called(ctx, account, start, end)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.StatementLedgerLine), args.Error(1)
+}
+
+func (m *MockInvestorStatementRepository) StatementExists(ctx context.Context, account *entities.LedgerAccount, periodType string, periodStart time.Time) (bool, error) {
+	args := m.Called(ctx, account, periodType, periodStart)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockInvestorStatementRepository) CreateStatement(ctx context.Context, statement *entities.InvestorStatement) (bool, error) {
+	args := m.Called(ctx, statement)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockInvestorStatementRepository) ListInvestorStatements(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestorStatement, error) {
+	args := m.Called(ctx, investorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.InvestorStatement), args.Error(1)
+}
+
+func (m *MockInvestorStatementRepository) GetStatement(ctx context.Context, statementID uuid.UUID) (*entities.InvestorStatement, error) {
+	args := m.Called(ctx, statementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.InvestorStatement), args.Error(1)
+}
+
+// MockTaxRepository for testing
+type MockTaxRepository struct {
+	mock.Mock
+}
+
+func (m *MockTaxRepository) CreateRate(ctx context.Context, rate *entities.TaxWithholdingRate) error {
+	args := m.Called(ctx, rate)
+	return args.Error(0)
+}
+
+func (m *MockTaxRepository) DeactivateRate(ctx context.Context, rateID uuid.UUID) error {
+	args := m.Called(ctx, rateID)
+	return args.Error(0)
+}
+
+func (m *MockTaxRepository) GetRate(ctx context.Context, rateID uuid.UUID) (*entities.TaxWithholdingRate, error) {
+	args := m.Called(ctx, rateID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.TaxWithholdingRate), args.Error(1)
+}
+
+func (m *MockTaxRepository) ListRates(ctx context.Context, jurisdiction string) ([]*entities.TaxWithholdingRate, error) {
+	args := m.Called(ctx, jurisdiction)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.TaxWithholdingRate), args.Error(1)
+}
+
+func (m *MockTaxRepository) GetCooperativeJurisdiction(ctx context.Context, cooperativeID uuid.UUID) (string, error) {
+	args := m.Called(ctx, cooperativeID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTaxRepository) SetCooperativeJurisdiction(ctx context.Context, cooperativeID uuid.UUID, jurisdiction string, userID uuid.UUID) error {
+	args := m.Called(ctx, cooperativeID, jurisdiction, userID)
+	return args.Error(0)
+}
+
+func (m *MockTaxRepository) GetInvestorProfile(ctx context.Context, investorID uuid.UUID) (*entities.InvestorTaxProfile, error) {
+	args := m.Called(ctx, investorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.InvestorTaxProfile), args.Error(1)
+}
+
+func (m *MockTaxRepository) SaveInvestorProfile(ctx context.Context, profile *entities.InvestorTaxProfile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
+
+func (m *MockTaxRepository) CreateWithholdings(ctx context.Context, cooperativeID uuid.UUID, withholdings []*entities.TaxWithholding) error {
+	args := m.Called(ctx, cooperativeID, withholdings)
+	return args.Error(0)
+}
+
+func (m *MockTaxRepository) ListWithholdings(ctx context.Context, cooperativeID uuid.UUID, filter *entities.TaxWithholdingFilter) ([]*entities.TaxWithholding, error) {
+	args := m.Called(ctx, cooperativeID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.TaxWithholding), args.Error(1)
+}
+
+func (m *MockTaxRepository) SummarizeWithholdings(ctx context.Context, cooperativeID uuid.UUID, filter *entities.TaxWithholdingFilter) ([]entities.TaxWithholdingReportLine, error) {
+	args := m.Called(ctx, cooperativeID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.TaxWithholdingReportLine), args.Error(1)
+}
+
+// MockPortfolioPerformanceRepository for testing
+type MockPortfolioPerformanceRepository struct {
+	mock.Mock
+}
+
+func (m *MockPortfolioPerformanceRepository) ListPositions(ctx context.Context, filter *entities.PerformanceFilter) ([]*entities.PerformancePosition, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.PerformancePosition), args.Error(1)
+}
+
+func (m *MockPortfolioPerformanceRepository) ListEvents(ctx context.Context, filter *entities.PerformanceFilter) ([]*entities.PerformanceEvent, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.PerformanceEvent), args.Error(1)
+}
+
+func (m *MockPortfolioPerformanceRepository) ListDistributions(ctx context.Context, filter *entities.PerformanceFilter) ([]*entities.PerformanceEvent, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.PerformanceEvent), args.Error(1)
+}
+
+// MockAMLRepository for testing
+type MockAMLRepository struct {
+	mock.Mock
+}
+
+func (m *MockAMLRepository) CreateRule(ctx context.Context, rule *entities.AMLRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockAMLRepository) GetRule(ctx context.Context, ruleID uuid.UUID) (*entities.AMLRule, error) {
+	args := m.Called(ctx, ruleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.AMLRule), args.Error(1)
+}
+
+func (m *MockAMLRepository) DeactivateRule(ctx context.Context, ruleID uuid.UUID) error {
+	args := m.Called(ctx, ruleID)
+	return args.Error(0)
+}
+
+func (m *MockAMLRepository) ListRules(ctx context.Context, cooperativeID *uuid.UUID) ([]*entities.AMLRule, error) {
+	args := m.Called(ctx, cooperativeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.AMLRule), args.Error(1)
+}
+
+func (m *MockAMLRepository) GetProjectCooperativeID(ctx context.Context, projectID uuid.UUID) (uuid.UUID, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockAMLRepository) CreateTransaction(ctx context.Context, transaction *entities.AMLTransaction) error {
+	args := m.Called(ctx, transaction)
+	return args.Error(0)
+}
+
+func (m *MockAMLRepository) ListSubjectTransactions(ctx context.Context, cooperativeID, subjectID uuid.UUID, since time.Time) ([]*entities.AMLTransaction, error) {
+	args := m.Called(ctx, cooperativeID, subjectID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.AMLTransaction), args.Error(1)
+}
+
+func (m *MockAMLRepository) ListBankAccountUses(ctx context.Context, cooperativeID, subjectID uuid.UUID) ([]*entities.AMLBankAccountUse, error) {
+	args := m.Called(ctx, cooperativeID, subjectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.AMLBankAccountUse), args.Error(1)
+}
+
+func (m *MockAMLRepository) ListFlaggedTransactions(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.AMLTransaction, error) {
+	args := m.Called(ctx, cooperativeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.AMLTransaction), args.Error(1)
+}
+
+func (m *MockAMLRepository) RaiseAlerts(ctx context.Context, cooperativeID, subjectID uuid.UUID, alerts []*entities.AMLAlert, raisedAt time.Time) (*entities.AMLCase, error) {
+	args := m.Called(ctx, cooperativeID, subjectID, alerts, raisedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.AMLCase), args.Error(1)
+}
+
+func (m *MockAMLRepository) GetCase(ctx context.Context, cooperativeID, caseID uuid.UUID) (*entities.AMLCase, error) {
+	args := m.Called(ctx, cooperativeID, caseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.AMLCase), args.Error(1)
+}
+
+func (m *MockAMLRepository) ListCases(ctx context.Context, cooperativeID uuid.UUID, filter *entities.AMLCaseFilter) ([]*entities.AMLCase, int, error) {
+	args := m.Called(ctx, cooperativeID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*entities.AMLCase), args.Int(1), args.Error(2)
+}
+
+func (m *MockAMLRepository) TransitionCase(ctx context.Context, amlCase *entities.AMLCase, fromStatus string, event *entities.AMLCaseEvent) error {
+	args := m.Called(ctx, amlCase, fromStatus, event)
+	return args.Error(0)
+}
+
+// MockSanctionsScreeningRepository for testing
+type MockSanctionsScreeningRepository struct {
+	mock.Mock
+}
+
+func (m *MockSanctionsScreeningRepository) CreateScreening(ctx context.Context, screening *entities.SanctionsScreening) error {
+	args := m.Called(ctx, screening)
+	return args.Error(0)
+}
+
+func (m *MockSanctionsScreeningRepository) GetScreening(ctx context.Context, screeningID uuid.UUID) (*entities.SanctionsScreening, error) {
+	args := m.Called(ctx, screeningID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.SanctionsScreening), args.Error(1)
+}
+
+func (m *MockSanctionsScreeningRepository) ListScreenings(ctx context.Context, filter *entities.SanctionsScreeningFilter) ([]*entities.SanctionsScreening, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*entities.SanctionsScreening), args.Int(1), args.Error(2)
+}
+
+func (m *MockSanctionsScreeningRepository) ListSubjectReviews(ctx context.Context, subjectType, reference string) ([]*entities.SanctionsScreening, error) {
+	args := m.Called(ctx, subjectType, reference)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.SanctionsScreening), args.Error(1)
+}
+
+func (m *MockSanctionsScreeningRepository) ReviewScreening(ctx context.Context, screening *entities.SanctionsScreening) error {
+	args := m.Called(ctx, screening)
+	return args.Error(0)
+}
+
+// MockFundTransferRepository for testing
+type MockFundTransferRepository struct {
+	mock.Mock
+	Attempts       []*entities.FundTransferAttempt
+	UncommittedIDs map[uuid.UUID]bool
+}
+
+func (m *MockFundTransferRepository) GetProjectCooperativeID(ctx context.Context, projectID uuid.UUID) (uuid.UUID, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockFundTransferRepository) CreateTransfer(ctx context.Context, transfer *entities.FundTransfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockFundTransferRepository) GetTransfer(ctx context.Context, transferID uuid.UUID) (*entities.FundTransfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.FundTransfer), args.Error(1)
+}
+
+func (m *MockFundTransferRepository) ListDeadLettered(ctx context.Context, limit, offset int) ([]*entities.FundTransfer, int, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*entities.FundTransfer), args.Int(1), args.Error(2)
+}
+
+func (m *MockFundTransferRepository) ListAttempts(ctx context.Context, transfer *entities.FundTransfer) ([]*entities.FundTransferAttempt, error) {
+	args := m.Called(ctx, transfer)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.FundTransferAttempt), args.Error(1)
+}
+
+// ProcessDueTransfers hands each of the due transfers it is set up with to
+// process and records the attempts in Attempts. Transfers in UncommittedIDs are
+// processed but not returned, as if their commit failed.
+func (m *MockFundTransferRepository) ProcessDueTransfers(ctx context.Context, now time.Time, limit int, process func(*entities.FundTransfer) *entities.FundTransferAttempt) ([]*entities.FundTransfer, error) {
+	args := m.Called(ctx, now, limit)
+	var committed []*entities.FundTransfer
+	for _, transfer := range args.Get(0).([]*entities.FundTransfer) {
+		m.Attempts = append(m.Attempts, process(transfer))
+		if !m.UncommittedIDs[transfer.ID] {
+			committed = append(committed, transfer)
+		}
+	}
+	return committed, args.Error(1)
+}
+
+func (m *MockFundTransferRepository) RequeueTransfer(ctx context.Context, transfer *entities.FundTransfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockFundTransferRepository) CancelTransfer(ctx context.Context, transfer *entities.FundTransfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+// MockInvestmentReservationRepository for testing
+type MockInvestmentReservationRepository struct {
+	mock.Mock
+}
+
+func (m *MockInvestmentReservationRepository) Reserve(ctx context.Context, reservation *entities.InvestmentReservation, now time.Time) error {
+	args := m.Called(ctx, reservation, now)
+	return args.Error(0)
+}
+
+func (m *MockInvestmentReservationRepository) GetReservation(ctx context.Context, investmentID uuid.UUID) (*entities.InvestmentReservation, error) {
+	args := m.Called(ctx, investmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.InvestmentReservation), args.Error(1)
+}
+
+func (m *MockInvestmentReservationRepository) ConvertReservation(ctx context.Context, reservation *entities.InvestmentReservation, now time.Time) error {
+	args := m.Called(ctx, reservation, now)
+	return args.Error(0)
+}
+
+func (m *MockInvestmentReservationRepository) ReleaseReservation(ctx context.Context, reservation *entities.InvestmentReservation) (bool, error) {
+	args := m.Called(ctx, reservation)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockInvestmentReservationRepository) ExpireReservations(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockInvestmentReservationRepository) GetFundingProgress(ctx context.Context, projectID uuid.UUID, defaultCurrency string, now time.Time) (*entities.ProjectFundingProgress, error) {
+	args := m.Called(ctx, projectID, defaultCurrency, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectFundingProgress), args.Error(1)
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// NotificationService delivers in-app notifications to users
+type NotificationService interface {
+	// NotifyUsers sends the same notification about an entity to each user
+	NotifyUsers(ctx context.Context, userIDs []uuid.UUID, notificationType, title, message, entityType string, entityID uuid.UUID) error
+	GetUserNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]*entities.Notification, error)
+	MarkAsRead(ctx context.Context, userID, notificationID uuid.UUID) error
+}
+
+// maxListedNotifications caps how many notifications are listed at once
+const maxListedNotifications = 100
+
+type notificationService struct {
+	notificationRepo repositories.NotificationRepository
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(notificationRepo repositories.NotificationRepository) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *notificationService) NotifyUsers(ctx context.Context, userIDs []uuid.UUID, notificationType, title, message, entityType string, entityID uuid.UUID) error {
+	now := time.Now()
+	seen := make(map[uuid.UUID]bool, len(userIDs))
+	notifications := make([]*entities.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		id := entityID
+		notifications = append(notifications, &entities.Notification{
+			ID:         uuid.New(),
+			UserID:     userID,
+			Type:       notificationType,
+			Title:      title,
+			Message:    message,
+			EntityType: entityType,
+			EntityID:   &id,
+			CreatedAt:  now,
+		})
+	}
+
+	return s.notificationRepo.CreateNotifications(ctx, notifications)
+}
+
+func (s *notificationService) GetUserNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]*entities.Notification, error) {
+	return s.notificationRepo.ListUserNotifications(ctx, userID, unreadOnly, maxListedNotifications)
+}
+
+func (s *notificationService) MarkAsRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	updated, err := s.notificationRepo.MarkRead(ctx, userID, notificationID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.New("notification not found or already read")
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// PaymentProvider collects investor payments through an external gateway such as
+// a bank virtual account or an e-wallet. Providers notify payment results through
+// asynchronous callbacks and can also be polled for status.
+type PaymentProvider interface {
+	// Name identifies the provider in payment requests and callback URLs
+	Name() string
+	// CreatePayment issues payment instructions, e.g. a virtual account number, for a payment request
+	CreatePayment(ctx context.Context, payment *entities.PaymentRequest) (*entities.PaymentInstruction, error)
+	// ParseCallback authenticates and decodes an asynchronous payment notification
+	ParseCallback(ctx context.Context, payload []byte, headers http.Header) (*entities.PaymentEvent, error)
+	// GetPaymentStatus queries the current status of a payment
+	GetPaymentStatus(ctx context.Context, providerReference string) (*entities.PaymentEvent, error)
+}
+
+// SimulatorSignatureHeader carries the HMAC-SHA256 signature of simulator callbacks
+const SimulatorSignatureHeader = "X-Simulator-Signature"
+
+// simulatorBankCode is the bank code printed on simulated virtual accounts
+const simulatorBankCode = "SIMBANK"
+
+// SimulatorPaymentProvider is a built-in payment provider for local development and
+// tests. It issues closed-amount virtual accounts and wallet checkouts held in
+// memory, and produces signed callbacks when a payment is made or expires.
+type SimulatorPaymentProvider struct {
+	secret    []byte
+	ttl       time.Duration
+	mu        sync.Mutex
+	payments  map[string]*simulatedPayment
+	transfers map[string]string // transfer number to transaction reference
+	sequence  int64
+}
+
+type simulatedPayment struct {
+	event     entities.PaymentEvent
+	expiresAt time.Time
+}
+
+// simulatorCallback is the callback body sent by the simulator
+type simulatorCallback struct {
+	Reference     string         `json:"reference"`
+	TransactionID string         `json:"transaction_id"`
+	Status        string         `json:"status"`
+	Amount        entities.Money `json:"amount"`
+	PaidAt        *time.Time     `json:"paid_at,omitempty"`
+	FailureReason string         `json:"failure_reason,omitempty"`
+}
+
+// NewSimulatorPaymentProvider creates a simulator whose payment requests expire after ttl
+func NewSimulatorPaymentProvider(secret string, ttl time.Duration) *SimulatorPaymentProvider {
+	return &SimulatorPaymentProvider{
+		secret:    []byte(secret),
+		ttl:       ttl,
+		payments:  make(map[string]*simulatedPayment),
+		transfers: make(map[string]string),
+	}
+}
+
+// Name identifies the simulator
+func (p *SimulatorPaymentProvider) Name() string {
+	return entities.PaymentProviderSimulator
+}
+
+// CreatePayment issues a virtual account or a wallet checkout link
+func (p *SimulatorPaymentProvider) CreatePayment(ctx context.Context, payment *entities.PaymentRequest) (*entities.PaymentInstruction, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sequence++
+	instruction := &entities.PaymentInstruction{
+		ProviderReference: fmt.Sprintf("SIM-%s", strings.ToUpper(uuid.New().String()[:13])),
+		ExpiresAt:         time.Now().Add(p.ttl),
+	}
+
+	switch payment.Method {
+	case entities.PaymentMethodBankTransfer:
+		instruction.VirtualAccountNumber = fmt.Sprintf("8808%012d", p.sequence)
+		instruction.BankCode = simulatorBankCode
+	case entities.PaymentMethodDigitalWallet:
+		instruction.PaymentURL = fmt.Sprintf("https://simulator.comfunds.local/checkout/%s", instruction.ProviderReference)
+	default:
+		return nil, fmt.Errorf("simulator does not support payment method %s", payment.Method)
+	}
+
+	p.payments[instruction.ProviderReference] = &simulatedPayment{
+		event: entities.PaymentEvent{
+			ProviderReference: instruction.ProviderReference,
+			Status:            entities.PaymentRequestStatusPending,
+			Amount:            payment.Amount,
+		},
+		expiresAt: instruction.ExpiresAt,
+	}
+
+	return instruction, nil
+}
+
+// Pay simulates the investor paying a payment request and returns the signed
+// callback the provider would send. Virtual accounts are closed-amount, so a
+// different amount is refused the way a bank would refuse it.
+func (p *SimulatorPaymentProvider) Pay(ctx context.Context, providerReference string, amount *entities.Money) ([]byte, http.Header, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, err := p.pendingPayment(providerReference)
+	if err != nil {
+		return nil, nil, err
+	}
+	if amount != nil && !amount.Equal(payment.event.Amount) {
+		return nil, nil, fmt.Errorf("amount %s does not match the billed amount %s", amount, payment.event.Amount)
+	}
+
+	paidAt := time.Now()
+	payment.event.Status = entities.PaymentRequestStatusPaid
+	payment.event.ProviderTransactionID = fmt.Sprintf("SIMTX-%d-%s", paidAt.Unix(), providerReference[4:12])
+	payment.event.PaidAt = &paidAt
+
+	return p.callback(payment.event)
+}
+
+// Expire simulates a payment request lapsing unpaid and returns the signed callback
+func (p *SimulatorPaymentProvider) Expire(ctx context.Context, providerReference string) ([]byte, http.Header, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, err := p.pendingPayment(providerReference)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payment.event.Status = entities.PaymentRequestStatusExpired
+	payment.event.FailureReason = "payment window elapsed"
+
+	return p.callback(payment.event)
+}
+
+// ParseCallback verifies the callback signature and decodes the payment event
+func (p *SimulatorPaymentProvider) ParseCallback(ctx context.Context, payload []byte, headers http.Header) (*entities.PaymentEvent, error) {
+	signature, err := hex.DecodeString(headers.Get(SimulatorSignatureHeader))
+	if err != nil || !hmac.Equal(signature, p.sign(payload)) {
+		return nil, fmt.Errorf("invalid callback signature")
+	}
+
+	var callback simulatorCallback
+	if err := json.Unmarshal(payload, &callback); err != nil {
+		return nil, fmt.Errorf("invalid callback payload: %w", err)
+	}
+
+	return &entities.PaymentEvent{
+		ProviderReference:     callback.Reference,
+		ProviderTransactionID: callback.TransactionID,
+		Status:                callback.Status,
+		Amount:                callback.Amount,
+		PaidAt:                callback.PaidAt,
+		FailureReason:         callback.FailureReason,
+	}, nil
+}
+
+// GetPaymentStatus reports the simulated payment's status
+func (p *SimulatorPaymentProvider) GetPaymentStatus(ctx context.Context, providerReference string) (*entities.PaymentEvent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[providerReference]
+	if !ok {
+		return nil, fmt.Errorf("simulated payment %s not found", providerReference)
+	}
+	if payment.event.Status == entities.PaymentRequestStatusPending && time.Now().After(payment.expiresAt) {
+		payment.event.Status = entities.PaymentRequestStatusExpired
+		payment.event.FailureReason = "payment window elapsed"
+	}
+
+	event := payment.event
+	return &event, nil
+}
+
+// pendingPayment returns a payment that can still be paid; callers hold p.mu
+func (p *SimulatorPaymentProvider) pendingPayment(providerReference string) (*simulatedPayment, error) {
+	payment, ok := p.payments[providerReference]
+	if !ok {
+		return nil, fmt.Errorf("simulated payment %s not found", providerReference)
+	}
+	if payment.event.Status != entities.PaymentRequestStatusPending {
+		return nil, fmt.Errorf("simulated payment %s is %s", providerReference, payment.event.Status)
+	}
+	if time.Now().After(payment.expiresAt) {
+		return nil, fmt.Errorf("simulated payment %s has expired", providerReference)
+	}
+	return payment, nil
+}
+
+func (p *SimulatorPaymentProvider) callback(event entities.PaymentEvent) ([]byte, http.Header, error) {
+	payload, err := json.Marshal(simulatorCallback{
+		Reference:     event.ProviderReference,
+		TransactionID: event.ProviderTransactionID,
+		Status:        event.Status,
+		Amount:        event.Amount,
+		PaidAt:        event.PaidAt,
+		FailureReason: event.FailureReason,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode callback: %w", err)
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set(SimulatorSignatureHeader, hex.EncodeToString(p.sign(payload)))
+	return payload, headers, nil
+}
+
+func (p *SimulatorPaymentProvider) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// PaymentService collects investment payments through payment providers and
+// confirms investments into escrow when a provider reports them paid
+type PaymentService interface {
+	CreatePaymentRequest(ctx context.Context, investment *entities.InvestmentExtended, method, provider string) (*entities.PaymentRequest, error)
+	GetPaymentRequest(ctx context.Context, paymentID uuid.UUID) (*entities.PaymentRequest, error)
+	HandleCallback(ctx context.Context, provider string, payload []byte, headers http.Header) (*entities.PaymentRequest, error)
+	RefreshPaymentStatus(ctx context.Context, paymentID uuid.UUID) (*entities.PaymentRequest, error)
+	Providers() []string
+}
+
+// paymentService implements PaymentService
+type paymentService struct {
+	paymentRepo              repositories.PaymentRepository
+	investmentFundingService InvestmentFundingService
+	auditService             AuditService
+	defaultProvider          string
+	providers                map[string]PaymentProvider
+}
+
+// NewPaymentService creates a new payment service. Payment requests that do not
+// name a provider go to defaultProvider.
+func NewPaymentService(
+	paymentRepo repositories.PaymentRepository,
+	investmentFundingService InvestmentFundingService,
+	auditService AuditService,
+	defaultProvider string,
+	providers ...PaymentProvider,
+) PaymentService {
+	registry := make(map[string]PaymentProvider, len(providers))
+	for _, provider := range providers {
+		registry[provider.Name()] = provider
+	}
+
+	return &paymentService{
+		paymentRepo:              paymentRepo,
+		investmentFundingService: investmentFundingService,
+		auditService:             auditService,
+		defaultProvider:          defaultProvider,
+		providers:                registry,
+	}
+}
+
+// CreatePaymentRequest asks a provider for payment instructions for a pending
+// investment. An unexpired open request for the investment is returned as is.
+func (s *paymentService) CreatePaymentRequest(ctx context.Context, investment *entities.InvestmentExtended, method, provider string) (*entities.PaymentRequest, error) {
+	if provider == "" {
+		provider = s.defaultProvider
+	}
+	paymentProvider, err := s.provider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	if investment.Status != entities.InvestmentStatusPending && investment.Status != entities.InvestmentStatusApproved {
+		return nil, fmt.Errorf("investment is %s and cannot be paid", investment.Status)
+	}
+	if investment.CooperativeID == uuid.Nil {
+		return nil, fmt.Errorf("investment has no cooperative")
+	}
+
+	existing, err := s.paymentRepo.GetPendingByInvestment(ctx, investment.CooperativeID, investment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && time.Now().Before(existing.ExpiresAt) {
+		return existing, nil
+	}
+
+	now := time.Now()
+	payment := &entities.PaymentRequest{
+		ID:            uuid.New(),
+		InvestmentID:  investment.ID,
+		CooperativeID: investment.CooperativeID,
+		InvestorID:    investment.InvestorID,
+		Provider:      paymentProvider.Name(),
+		Method:        method,
+		Status:        entities.PaymentRequestStatusPending,
+		Amount:        investment.Amount,
+		Currency:      investment.Amount.Currency(),
+		Reference:     investment.TransferReference,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	instruction, err := paymentProvider.CreatePayment(ctx, payment)
+	if err != nil {
+		return nil, fmt.Errorf("payment provider %s failed to create payment: %w", payment.Provider, err)
+	}
+	payment.ProviderReference = instruction.ProviderReference
+	payment.VirtualAccountNumber = instruction.VirtualAccountNumber
+	payment.BankCode = instruction.BankCode
+	payment.PaymentURL = instruction.PaymentURL
+	payment.ExpiresAt = instruction.ExpiresAt
+
+	// Only one request per investment may be pending, so the stale one lapses first
+	if existing != nil {
+		existing.Status = entities.PaymentRequestStatusExpired
+		existing.FailureReason = "superseded by a new payment request"
+		if _, err := s.paymentRepo.UpdateStatus(ctx, existing, entities.PaymentRequestStatusPending); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.paymentRepo.Create(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     investment.InvestorID,
+		Operation:  "create_payment_request",
+		EntityType: "payment_request",
+		EntityID:   payment.ID,
+		NewValues:  fmt.Sprintf("Requested %s payment of %s for investment %s via %s", method, payment.Amount, investment.ID, payment.Provider),
+	})
+
+	return payment, nil
+}
+
+// GetPaymentRequest gets a payment request
+func (s *paymentService) GetPaymentRequest(ctx context.Context, paymentID uuid.UUID) (*entities.PaymentRequest, error) {
+	return s.paymentRepo.GetByID(ctx, paymentID)
+}
+
+// HandleCallback applies an asynchronous notification from a provider. Providers
+// retry until the callback succeeds, so notifications that were already applied
+// are acknowledged without effect.
+func (s *paymentService) HandleCallback(ctx context.Context, provider string, payload []byte, headers http.Header) (*entities.PaymentRequest, error) {
+	paymentProvider, err := s.provider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := paymentProvider.ParseCallback(ctx, payload, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := s.paymentRepo.GetByProviderReference(ctx, paymentProvider.Name(), event.ProviderReference)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.applyEvent(ctx, payment, event)
+}
+
+// RefreshPaymentStatus polls the provider for payments whose callback may have been lost
+func (s *paymentService) RefreshPaymentStatus(ctx context.Context, paymentID uuid.UUID) (*entities.PaymentRequest, error) {
+	payment, err := s.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status == entities.PaymentRequestStatusPaid {
+		return payment, nil
+	}
+
+	paymentProvider, err := s.provider(payment.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := paymentProvider.GetPaymentStatus(ctx, payment.ProviderReference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment status from %s: %w", payment.Provider, err)
+	}
+
+	return s.applyEvent(ctx, payment, event)
+}
+
+// Providers lists the registered provider names
+func (s *paymentService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyEvent moves a payment request to the status reported by its provider.
+// Money can arrive after a request expired or was cancelled, so a paid event is
+// honoured from any status other than paid.
+func (s *paymentService) applyEvent(ctx context.Context, payment *entities.PaymentRequest, event *entities.PaymentEvent) (*entities.PaymentRequest, error) {
+	if event.Status == payment.Status {
+		return payment, nil
+	}
+
+	switch event.Status {
+	case entities.PaymentRequestStatusPending:
+		return payment, nil
+	case entities.PaymentRequestStatusPaid:
+		if payment.Status == entities.PaymentRequestStatusPaid {
+			return payment, nil
+		}
+		return s.confirmPaid(ctx, payment, event)
+	case entities.PaymentRequestStatusExpired, entities.PaymentRequestStatusFailed:
+		if payment.IsFinal() {
+			return payment, nil
+		}
+		payment.Status = event.Status
+		payment.FailureReason = event.FailureReason
+		if _, err := s.paymentRepo.UpdateStatus(ctx, payment, entities.PaymentRequestStatusPending); err != nil {
+			return nil, err
+		}
+
+		s.auditService.LogOperation(ctx, &LogOperationRequest{
+			UserID:     uuid.Nil, // System operation
+			Operation:  "payment_request_" + event.Status,
+			EntityType: "payment_request",
+			EntityID:   payment.ID,
+			NewValues:  fmt.Sprintf("Payment request for investment %s %s: %s", payment.InvestmentID, event.Status, event.FailureReason),
+		})
+		return payment, nil
+	default:
+		return nil, fmt.Errorf("unknown payment status %q from %s", event.Status, payment.Provider)
+	}
+}
+
+// confirmPaid claims the payment request as paid and confirms the investment.
+// If confirmation fails the claim is released so that the provider's retry, or a
+// status refresh, can try again.
+func (s *paymentService) confirmPaid(ctx context.Context, payment *entities.PaymentRequest, event *entities.PaymentEvent) (*entities.PaymentRequest, error) {
+	if !event.Amount.Equal(payment.Amount) {
+		return nil, fmt.Errorf("paid amount %s does not match requested amount %s", event.Amount, payment.Amount)
+	}
+
+	paidAt := time.Now()
+	if event.PaidAt != nil {
+		paidAt = *event.PaidAt
+	}
+
+	fromStatus := payment.Status
+	paidAmount := event.Amount
+	payment.Status = entities.PaymentRequestStatusPaid
+	payment.PaidAmount = &paidAmount
+	payment.PaidAt = &paidAt
+	payment.ProviderTransactionID = event.ProviderTransactionID
+	payment.FailureReason = ""
+
+	claimed, err := s.paymentRepo.UpdateStatus(ctx, payment, fromStatus)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		// A concurrent callback got there first
+		return s.paymentRepo.GetByID(ctx, payment.ID)
+	}
+
+	_, err = s.investmentFundingService.ConfirmInvestmentPayment(ctx, payment.InvestmentID, payment.CooperativeID, &entities.InvestmentPaymentConfirmation{
+		Amount:            paidAmount,
+		BankTransactionID: event.ProviderTransactionID,
+		Source:            entities.PaymentConfirmationSourcePaymentProvider,
+		PaidAt:            paidAt,
+	}, uuid.Nil)
+	if err != nil {
+		payment.Status = fromStatus
+		payment.PaidAmount = nil
+		payment.PaidAt = nil
+		payment.ProviderTransactionID = ""
+		if _, releaseErr := s.paymentRepo.UpdateStatus(ctx, payment, entities.PaymentRequestStatusPaid); releaseErr != nil {
+			return nil, fmt.Errorf("failed to confirm investment: %v (and to release payment request: %v)", err, releaseErr)
+		}
+		return nil, fmt.Errorf("failed to confirm investment: %w", err)
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     uuid.Nil, // System operation
+		Operation:  "payment_request_paid",
+		EntityType: "payment_request",
+		EntityID:   payment.ID,
+		NewValues:  fmt.Sprintf("Received %s for investment %s via %s (transaction %s)", paidAmount, payment.InvestmentID, payment.Provider, event.ProviderTransactionID),
+	})
+
+	return payment, nil
+}
+
+func (s *paymentService) provider(name string) (PaymentProvider, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown payment provider: %s", name)
+	}
+	return provider, nil
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type paymentTestFixture struct {
+	paymentService           PaymentService
+	investmentFundingService InvestmentFundingService
+	simulator                *SimulatorPaymentProvider
+	paymentRepo              *MockPaymentRepository
+	ledgerRepo               *MockLedgerRepository
+}
+
+func newPaymentTestFixture() *paymentTestFixture {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerRepo.On("GetAccountByCode", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "IDR").Return(nil, repositories.ErrLedgerAccountNotFound)
+	ledgerRepo.On("CreateAccount", mock.Anything, mock.AnythingOfType("*entities.LedgerAccount")).Return(nil)
+
+	investmentFundingService := NewInvestmentFundingService(mockAuditService, NewLedgerService(ledgerRepo, mockAuditService), nil, nil, nil, nil, 0)
+	simulator := NewSimulatorPaymentProvider("test-secret", time.Hour)
+	paymentRepo := new(MockPaymentRepository)
+
+	return &paymentTestFixture{
+		paymentService:           NewPaymentService(paymentRepo, investmentFundingService, mockAuditService, entities.PaymentProviderSimulator, simulator),
+		investmentFundingService: investmentFundingService,
+		simulator:                simulator,
+		paymentRepo:              paymentRepo,
+		ledgerRepo:               ledgerRepo,
+	}
+}
+
+// createInvestmentPayment creates an investment and a virtual account payment request for it
+func (f *paymentTestFixture) createInvestmentPayment(t *testing.T, ctx context.Context) (*entities.InvestmentExtended, *entities.PaymentRequest) {
+	f.paymentRepo.On("GetPendingByInvestment", ctx, mock.Anything, mock.Anything).Return(nil, nil)
+	f.paymentRepo.On("Create", ctx, mock.AnythingOfType("*entities.PaymentRequest")).Return(nil)
+
+	investment, err := f.investmentFundingService.CreateInvestment(ctx, &entities.CreateInvestmentExtendedRequest{
+		ProjectID:      uuid.New(),
+		CooperativeID:  uuid.New(),
+		Amount:         idr("1000"),
+		Currency:       "IDR",
+		InvestmentType: entities.InvestmentTypePartial,
+	}, uuid.New())
+	require.NoError(t, err)
+
+	payment, err := f.paymentService.CreatePaymentRequest(ctx, investment, entities.PaymentMethodBankTransfer, "")
+	require.NoError(t, err)
+
+	f.paymentRepo.On("GetByProviderReference", ctx, entities.PaymentProviderSimulator, payment.ProviderReference).Return(payment, nil)
+	return investment, payment
+}
+
+func TestPaymentService_SimulatorFlow(t *testing.T) {
+	f := newPaymentTestFixture()
+	ctx := context.Background()
+
+	investment, payment := f.createInvestmentPayment(t, ctx)
+
+	assert.Equal(t, entities.PaymentRequestStatusPending, payment.Status)
+	assert.Equal(t, investment.CooperativeID, payment.CooperativeID)
+	assert.Equal(t, investment.TransferReference, payment.Reference)
+	assert.Equal(t, idr("1000"), payment.Amount)
+	assert.True(t, strings.HasPrefix(payment.VirtualAccountNumber, "8808"))
+	assert.True(t, payment.ExpiresAt.After(time.Now()))
+
+	var posted *entities.JournalEntry
+	f.ledgerRepo.On("PostEntry", ctx, mock.AnythingOfType("*entities.JournalEntry")).Run(func(args mock.Arguments) {
+		posted = args.Get(1).(*entities.JournalEntry)
+	}).Return(nil)
+	f.paymentRepo.On("UpdateStatus", ctx, payment, entities.PaymentRequestStatusPending).Return(true, nil)
+
+	// The investor pays the virtual account and the provider calls back
+	payload, headers, err := f.simulator.Pay(ctx, payment.ProviderReference, nil)
+	require.NoError(t, err)
+
+	result, err := f.paymentService.HandleCallback(ctx, entities.PaymentProviderSimulator, payload, headers)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.PaymentRequestStatusPaid, result.Status)
+	assert.Equal(t, idr("1000"), *result.PaidAmount)
+	assert.NotEmpty(t, result.ProviderTransactionID)
+
+	// The investment is posted into the cooperative's escrow
+	require.NotNil(t, posted)
+	assert.Equal(t, entities.JournalEntryTypeInvestment, posted.EntryType)
+	assert.Equal(t, investment.CooperativeID, posted.CooperativeID)
+	assert.Equal(t, investment.ID, posted.ReferenceID)
+
+	// Providers retry callbacks; a repeat is acknowledged without posting again
+	result, err = f.paymentService.HandleCallback(ctx, entities.PaymentProviderSimulator, payload, headers)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entities.PaymentRequestStatusPaid, result.Status)
+	f.ledgerRepo.AssertNumberOfCalls(t, "PostEntry", 1)
+}
+
+func TestPaymentService_HandleCallback_InvalidSignature(t *testing.T) {
+	f := newPaymentTestFixture()
+	ctx := context.Background()
+
+	_, payment := f.createInvestmentPayment(t, ctx)
+
+	payload, headers, err := f.simulator.Pay(ctx, payment.ProviderReference, nil)
+	require.NoError(t, err)
+	tampered := []byte(strings.Replace(string(payload), `"1000.00"`, `"1.00"`, 1))
+
+	result, err := f.paymentService.HandleCallback(ctx, entities.PaymentProviderSimulator, tampered, headers)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "invalid callback signature")
+	f.paymentRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPaymentService_HandleCallback_UnknownProvider(t *testing.T) {
+	f := newPaymentTestFixture()
+
+	result, err := f.paymentService.HandleCallback(context.Background(), "acmepay", []byte(`{}`), http.Header{})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "unknown payment provider")
+}
+
+func TestPaymentService_ConfirmationFailureReleasesPayment(t *testing.T) {
+	f := newPaymentTestFixture()
+	ctx := context.Background()
+
+	_, payment := f.createInvestmentPayment(t, ctx)
+
+	f.ledgerRepo.On("PostEntry", ctx, mock.AnythingOfType("*entities.JournalEntry")).Return(errors.New("shard unavailable"))
+	f.paymentRepo.On("UpdateStatus", ctx, payment, entities.PaymentRequestStatusPending).Return(true, nil)
+	f.paymentRepo.On("UpdateStatus", ctx, payment, entities.PaymentRequestStatusPaid).Return(true, nil)
+
+	payload, headers, err := f.simulator.Pay(ctx, payment.ProviderReference, nil)
+	require.NoError(t, err)
+
+	result, err := f.paymentService.HandleCallback(ctx, entities.PaymentProviderSimulator, payload, headers)
+
+	// The callback fails so the provider retries, and the request is pending again
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, entities.PaymentRequestStatusPending, payment.Status)
+	assert.Nil(t, payment.PaidAt)
+	f.paymentRepo.AssertCalled(t, "UpdateStatus", ctx, payment, entities.PaymentRequestStatusPaid)
+}
+
+func TestPaymentService_Expiry(t *testing.T) {
+	f := newPaymentTestFixture()
+	ctx := context.Background()
+
+	_, payment := f.createInvestmentPayment(t, ctx)
+	f.paymentRepo.On("UpdateStatus", ctx, payment, entities.PaymentRequestStatusPending).Return(true, nil)
+
+	payload, headers, err := f.simulator.Expire(ctx, payment.ProviderReference)
+	require.NoError(t, err)
+
+	result, err := f.paymentService.HandleCallback(ctx, entities.PaymentProviderSimulator, payload, headers)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.PaymentRequestStatusExpired, result.Status)
+	assert.Equal(t, "payment window elapsed", result.FailureReason)
+
+	// An expired virtual account no longer accepts money
+	_, _, err = f.simulator.Pay(ctx, payment.ProviderReference, nil)
+	assert.Error(t, err)
+}
+
+func TestPaymentService_RefreshPaymentStatus(t *testing.T) {
+	f := newPaymentTestFixture()
+	ctx := context.Background()
+
+	_, payment := f.createInvestmentPayment(t, ctx)
+	f.paymentRepo.On("GetByID", ctx, payment.ID).Return(payment, nil)
+	f.paymentRepo.On("UpdateStatus", ctx, payment, entities.PaymentRequestStatusPending).Return(true, nil)
+	f.ledgerRepo.On("PostEntry", ctx, mock.AnythingOfType("*entities.JournalEntry")).Return(nil)
+
+	// The payment is made but its callback never arrives
+	_, _, err := f.simulator.Pay(ctx, payment.ProviderReference, nil)
+	require.NoError(t, err)
+
+	result, err := f.paymentService.RefreshPaymentStatus(ctx, payment.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.PaymentRequestStatusPaid, result.Status)
+	f.ledgerRepo.AssertNumberOfCalls(t, "PostEntry", 1)
+}
+
+func TestSimulatorPaymentProvider_ClosedAmount(t *testing.T) {
+	f := newPaymentTestFixture()
+	ctx := context.Background()
+
+	_, payment := f.createInvestmentPayment(t, ctx)
+	underpaid := idr("999")
+
+	_, _, err := f.simulator.Pay(ctx, payment.ProviderReference, &underpaid)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match the billed amount")
+}
+
+func TestPaymentService_CreatePaymentRequest_ReusesOpenRequest(t *testing.T) {
+	f := newPaymentTestFixture()
+	ctx := context.Background()
+
+	investment := &entities.InvestmentExtended{
+		ID:            uuid.New(),
+		CooperativeID: uuid.New(),
+		Amount:        idr("1000"),
+		Status:        entities.InvestmentStatusPending,
+	}
+	open := &entities.PaymentRequest{ID: uuid.New(), Status: entities.PaymentRequestStatusPending, ExpiresAt: time.Now().Add(time.Hour)}
+	f.paymentRepo.On("GetPendingByInvestment", ctx, investment.CooperativeID, investment.ID).Return(open, nil)
+
+	payment, err := f.paymentService.CreatePaymentRequest(ctx, investment, entities.PaymentMethodDigitalWallet, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, open, payment)
+	f.paymentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,493 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"comfunds/internal/entities"
+)
+
+// pain001Namespace is the ISO 20022 customer credit transfer initiation version
+// accepted by the supported banks
+const pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+
+// bicPattern matches SWIFT BICs; other bank codes are sent as proprietary identifiers
+var bicPattern = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+
+// PayoutCSVFormat describes a bank's CSV bulk transfer layout. The same delimiter
+// is used to read the bank's CSV return files.
+type PayoutCSVFormat struct {
+	Columns    []string // column names from payoutCSVColumns, in file order
+	Delimiter  rune
+	Header     bool
+	DateLayout string
+}
+
+// DefaultPayoutCSVFormat is used unless a bank's layout is configured
+var DefaultPayoutCSVFormat = PayoutCSVFormat{
+	Columns:    []string{"reference", "beneficiary_account", "beneficiary_bank_code", "beneficiary_name", "amount", "currency", "description"},
+	Delimiter:  ',',
+	Header:     true,
+	DateLayout: "2006-01-02",
+}
+
+// payoutCSVColumns are the fields a CSV bulk transfer file can contain
+var payoutCSVColumns = map[string]bool{
+	"batch_reference":       true,
+	"execution_date":        true,
+	"debtor_account":        true,
+	"debtor_bank_code":      true,
+	"reference":             true,
+	"beneficiary_name":      true,
+	"beneficiary_account":   true,
+	"beneficiary_bank_code": true,
+	"amount":                true,
+	"amount_minor_units":    true,
+	"currency":              true,
+	"description":           true,
+}
+
+// payoutCSVField returns the value of a CSV column for a payout
+func payoutCSVField(column string, batch *entities.PayoutBatch, item *entities.PayoutItem, dateLayout string) string {
+	switch column {
+	case "batch_reference":
+		return batch.Reference
+	case "execution_date":
+		return batch.ExecutionDate.Format(dateLayout)
+	case "debtor_account":
+		return batch.DebtorAccount
+	case "debtor_bank_code":
+		return batch.DebtorBankCode
+	case "reference":
+		return item.Reference
+	case "beneficiary_name":
+		return item.BeneficiaryName
+	case "beneficiary_account":
+		return item.BeneficiaryAccount
+	case "beneficiary_bank_code":
+		return item.BeneficiaryBankCode
+	case "amount":
+		return item.Amount.Decimal()
+	case "amount_minor_units":
+		return strconv.FormatInt(item.Amount.MinorUnits(), 10)
+	case "currency":
+		return item.Currency
+	case "description":
+		return item.Description
+	default:
+		return ""
+	}
+}
+
+// NewPayoutCSVFormat builds a CSV layout from a comma-separated column list. An
+// empty column list keeps the default columns; "tab" selects a tab delimiter.
+func NewPayoutCSVFormat(columns, delimiter string, header bool) (PayoutCSVFormat, error) {
+	format := DefaultPayoutCSVFormat
+	format.Header = header
+
+	if columns != "" {
+		format.Columns = nil
+		for _, column := range strings.Split(columns, ",") {
+			column = strings.ToLower(strings.TrimSpace(column))
+			if !payoutCSVColumns[column] {
+				return PayoutCSVFormat{}, fmt.Errorf("unknown payout CSV column: %s", column)
+			}
+			format.Columns = append(format.Columns, column)
+		}
+	}
+
+	switch {
+	case delimiter == "":
+	case strings.EqualFold(delimiter, "tab"):
+		format.Delimiter = '\t'
+	case utf8.RuneCountInString(delimiter) == 1:
+		format.Delimiter, _ = utf8.DecodeRuneInString(delimiter)
+	default:
+		return PayoutCSVFormat{}, fmt.Errorf("payout CSV delimiter must be a single character: %q", delimiter)
+	}
+
+	return format, nil
+}
+
+// generatePayoutCSV writes the batch as a CSV bulk transfer file
+func generatePayoutCSV(batch *entities.PayoutBatch, items []*entities.PayoutItem, format PayoutCSVFormat) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Comma = format.Delimiter
+
+	if format.Header {
+		if err := writer.Write(format.Columns); err != nil {
+			return nil, fmt.Errorf("failed to write CSV header: %w", err)
+		}
+	}
+
+	for _, item := range items {
+		record := make([]string, len(format.Columns))
+		for i, column := range format.Columns {
+			record[i] = payoutCSVField(column, batch, item, format.DateLayout)
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, fmt.Errorf("failed to write payout %s: %w", item.Reference, err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+type pain001Document struct {
+	XMLName    xml.Name          `xml:"Document"`
+	Namespace  string            `xml:"xmlns,attr"`
+	Initiation pain001Initiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type pain001Initiation struct {
+	GroupHeader pain001GroupHeader `xml:"GrpHdr"`
+	PaymentInfo pain001PaymentInfo `xml:"PmtInf"`
+}
+
+type pain001GroupHeader struct {
+	MessageID            string       `xml:"MsgId"`
+	CreatedAt            string       `xml:"CreDtTm"`
+	NumberOfTransactions int          `xml:"NbOfTxs"`
+	ControlSum           string       `xml:"CtrlSum"`
+	InitiatingParty      pain001Party `xml:"InitgPty"`
+}
+
+type pain001PaymentInfo struct {
+	PaymentInfoID          string               `xml:"PmtInfId"`
+	PaymentMethod          string               `xml:"PmtMtd"`
+	BatchBooking           bool                 `xml:"BtchBookg"`
+	NumberOfTransactions   int                  `xml:"NbOfTxs"`
+	ControlSum             string               `xml:"CtrlSum"`
+	RequestedExecutionDate string               `xml:"ReqdExctnDt"`
+	Debtor                 pain001Party         `xml:"Dbtr"`
+	DebtorAccount          pain001Account       `xml:"DbtrAcct"`
+	DebtorAgent            pain001Agent         `xml:"DbtrAgt"`
+	Transactions           []pain001Transaction `xml:"CdtTrfTxInf"`
+}
+
+type pain001Transaction struct {
+	EndToEndID      string         `xml:"PmtId>EndToEndId"`
+	Amount          pain001Amount  `xml:"Amt>InstdAmt"`
+	CreditorAgent   *pain001Agent  `xml:"CdtrAgt,omitempty"`
+	Creditor        pain001Party   `xml:"Cdtr"`
+	CreditorAccount pain001Account `xml:"CdtrAcct"`
+	RemittanceInfo  string         `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+type pain001Party struct {
+	Name string `xml:"Nm,omitempty"`
+}
+
+type pain001Account struct {
+	ID       string `xml:"Id>Othr>Id"`
+	Currency string `xml:"Ccy,omitempty"`
+}
+
+type pain001Agent struct {
+	BIC   string `xml:"FinInstnId>BIC,omitempty"`
+	Other string `xml:"FinInstnId>Othr>Id,omitempty"`
+}
+
+type pain001Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+func newPain001Agent(bankCode string) pain001Agent {
+	code := strings.ToUpper(strings.TrimSpace(bankCode))
+	if bicPattern.MatchString(code) {
+		return pain001Agent{BIC: code}
+	}
+	return pain001Agent{Other: bankCode}
+}
+
+// generatePain001 writes the batch as an ISO 20022 pain.001 credit transfer
+// initiation with a single payment information block debiting the escrow account
+func generatePain001(batch *entities.PayoutBatch, items []*entities.PayoutItem, createdAt time.Time) ([]byte, error) {
+	total := entities.ZeroMoney(batch.Currency)
+	transactions := make([]pain001Transaction, 0, len(items))
+	for _, item := range items {
+		total = total.Add(item.Amount)
+
+		transaction := pain001Transaction{
+			EndToEndID:      item.Reference,
+			Amount:          pain001Amount{Currency: item.Currency, Value: item.Amount.Decimal()},
+			Creditor:        pain001Party{Name: truncateRunes(item.BeneficiaryName, 140)},
+			CreditorAccount: pain001Account{ID: item.BeneficiaryAccount},
+			RemittanceInfo:  truncateRunes(item.Description, 140),
+		}
+		if item.BeneficiaryBankCode != "" {
+			agent := newPain001Agent(item.BeneficiaryBankCode)
+			transaction.CreditorAgent = &agent
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	document := pain001Document{
+		Namespace: pain001Namespace,
+		Initiation: pain001Initiation{
+			GroupHeader: pain001GroupHeader{
+				MessageID:            batch.Reference,
+				CreatedAt:            createdAt.UTC().Format("2006-01-02T15:04:05"),
+				NumberOfTransactions: len(items),
+				ControlSum:           total.Decimal(),
+				InitiatingParty:      pain001Party{Name: truncateRunes(batch.DebtorName, 140)},
+			},
+			PaymentInfo: pain001PaymentInfo{
+				PaymentInfoID:          batch.Reference,
+				PaymentMethod:          "TRF",
+				BatchBooking:           true,
+				NumberOfTransactions:   len(items),
+				ControlSum:             total.Decimal(),
+				RequestedExecutionDate: batch.ExecutionDate.Format("2006-01-02"),
+				Debtor:                 pain001Party{Name: truncateRunes(batch.DebtorName, 140)},
+				DebtorAccount:          pain001Account{ID: batch.DebtorAccount, Currency: batch.Currency},
+				DebtorAgent:            newPain001Agent(batch.DebtorBankCode),
+				Transactions:           transactions,
+			},
+		},
+	}
+
+	content, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pain.001: %w", err)
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+// payoutReturnFile is a parsed bank return file
+type payoutReturnFile struct {
+	OriginalMessageID string // batch reference the file reports on, when the format carries it
+	Lines             []*entities.PayoutReturnLine
+	// Rejected is set when the bank rejected the whole file; Reason explains why
+	Rejected bool
+	Reason   string
+}
+
+// parsePayoutReturn parses a bank return file in the given format
+func parsePayoutReturn(format string, r io.Reader, csvFormat PayoutCSVFormat) (*payoutReturnFile, error) {
+	switch format {
+	case entities.PayoutReturnFormatPain002:
+		return parsePain002(r)
+	case entities.PayoutReturnFormatCSV:
+		return parsePayoutReturnCSV(r, csvFormat.Delimiter)
+	default:
+		return nil, fmt.Errorf("unsupported payout return format: %s", format)
+	}
+}
+
+type pain002Document struct {
+	Report struct {
+		OriginalGroup struct {
+			MessageID string          `xml:"OrgnlMsgId"`
+			Status    string          `xml:"GrpSts"`
+			Reasons   []pain002Reason `xml:"StsRsnInf"`
+		} `xml:"OrgnlGrpInfAndSts"`
+		PaymentInfos []struct {
+			Status       string          `xml:"PmtInfSts"`
+			Reasons      []pain002Reason `xml:"StsRsnInf"`
+			Transactions []struct {
+				EndToEndID               string          `xml:"OrgnlEndToEndId"`
+				Status                   string          `xml:"TxSts"`
+				Reasons                  []pain002Reason `xml:"StsRsnInf"`
+				AccountServicerReference string          `xml:"AcctSvcrRef"`
+			} `xml:"TxInfAndSts"`
+		} `xml:"OrgnlPmtInfAndSts"`
+	} `xml:"CstmrPmtStsRpt"`
+}
+
+type pain002Reason struct {
+	Code           string   `xml:"Rsn>Cd"`
+	Proprietary    string   `xml:"Rsn>Prtry"`
+	AdditionalInfo []string `xml:"AddtlInf"`
+}
+
+// parsePain002 parses an ISO 20022 pain.002 payment status report
+func parsePain002(r io.Reader) (*payoutReturnFile, error) {
+	var document pain002Document
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid pain.002 file: %w", err)
+	}
+
+	report := document.Report
+	if report.OriginalGroup.MessageID == "" {
+		return nil, fmt.Errorf("pain.002 file has no original message ID")
+	}
+
+	file := &payoutReturnFile{OriginalMessageID: report.OriginalGroup.MessageID}
+	if report.OriginalGroup.Status == "RJCT" {
+		file.Rejected = true
+		file.Reason = pain002ReasonText(report.OriginalGroup.Reasons)
+	}
+
+	for _, paymentInfo := range report.PaymentInfos {
+		if paymentInfo.Status == "RJCT" && len(paymentInfo.Transactions) == 0 {
+			file.Rejected = true
+			file.Reason = pain002ReasonText(paymentInfo.Reasons)
+		}
+		for _, transaction := range paymentInfo.Transactions {
+			file.Lines = append(file.Lines, &entities.PayoutReturnLine{
+				Reference:     strings.TrimSpace(transaction.EndToEndID),
+				Status:        pain002Status(transaction.Status),
+				Reason:        pain002ReasonText(transaction.Reasons),
+				BankReference: strings.TrimSpace(transaction.AccountServicerReference),
+			})
+		}
+	}
+
+	if len(file.Lines) == 0 && !file.Rejected {
+		return nil, fmt.Errorf("pain.002 file has no transaction statuses")
+	}
+	return file, nil
+}
+
+// pain002Status maps an ISO 20022 transaction status to a payout result. Only
+// settlement on the beneficiary side completes a payout; accepted statuses are
+// still pending.
+func pain002Status(code string) string {
+	switch strings.ToUpper(strings.TrimSpace(code)) {
+	case "ACSC", "ACCC":
+		return entities.PayoutReturnStatusCompleted
+	case "RJCT":
+		return entities.PayoutReturnStatusFailed
+	default:
+		return entities.PayoutReturnStatusPending
+	}
+}
+
+func pain002ReasonText(reasons []pain002Reason) string {
+	var parts []string
+	for _, reason := range reasons {
+		code := reason.Code
+		if code == "" {
+			code = reason.Proprietary
+		}
+		text := strings.TrimSpace(strings.Join(reason.AdditionalInfo, " "))
+		switch {
+		case code != "" && text != "":
+			parts = append(parts, code+": "+text)
+		case code != "":
+			parts = append(parts, code)
+		case text != "":
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// payoutReturnCSVColumns maps the header names used by the supported banks' CSV
+// return files to return line fields
+var payoutReturnCSVColumns = map[string]string{
+	"reference":          "reference",
+	"end_to_end_id":      "reference",
+	"payment_reference":  "reference",
+	"customer_reference": "reference",
+	"status":             "status",
+	"result":             "status",
+	"reason":             "reason",
+	"failure_reason":     "reason",
+	"message":            "reason",
+	"remarks":            "reason",
+	"bank_reference":     "bank_reference",
+	"transaction_id":     "bank_reference",
+}
+
+// parsePayoutReturnCSV parses a CSV return file with a header row
+func parsePayoutReturnCSV(r io.Reader, delimiter rune) (*payoutReturnFile, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		key = strings.ReplaceAll(key, " ", "_")
+		if field, ok := payoutReturnCSVColumns[key]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["reference"]; !ok {
+		return nil, fmt.Errorf("CSV return file has no reference column")
+	}
+	if _, ok := columns["status"]; !ok {
+		return nil, fmt.Errorf("CSV return file has no status column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	file := &payoutReturnFile{}
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		reference := field(record, "reference")
+		if reference == "" {
+			return nil, fmt.Errorf("row %d: no reference", row)
+		}
+
+		file.Lines = append(file.Lines, &entities.PayoutReturnLine{
+			Reference:     reference,
+			Status:        payoutReturnCSVStatus(field(record, "status")),
+			Reason:        field(record, "reason"),
+			BankReference: field(record, "bank_reference"),
+		})
+	}
+
+	if len(file.Lines) == 0 {
+		return nil, fmt.Errorf("CSV return file has no payouts")
+	}
+	return file, nil
+}
+
+func payoutReturnCSVStatus(status string) string {
+	switch strings.ToLower(status) {
+	case "completed", "success", "successful", "ok", "settled", "acsc", "accc":
+		return entities.PayoutReturnStatusCompleted
+	case "failed", "failure", "rejected", "returned", "error", "rjct":
+		return entities.PayoutReturnStatusFailed
+	default:
+		return entities.PayoutReturnStatusPending
+	}
+}
+
+// truncateRunes shortens s to at most n characters
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
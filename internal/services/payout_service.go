@@ -0,0 +1,650 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// PayoutService turns approved disbursements, investor refunds and profit shares
+// into bank payment files. Payouts are queued per source escrow account, batched,
+// exported as pain.001 XML or CSV, and settled from the bank's return file.
+type PayoutService interface {
+	// Queueing approved payouts
+	QueueDisbursement(ctx context.Context, disbursement *entities.FundDisbursement, requesterID uuid.UUID) (*entities.PayoutItem, error)
+	QueueInvestorRefunds(ctx context.Context, refund *entities.FundRefund, investorRefunds []*entities.InvestorRefund, requesterID uuid.UUID) ([]*entities.PayoutItem, error)
+	QueueProfitShares(ctx context.Context, distribution *entities.ProfitDistributionExtended, shares []*entities.InvestorProfitShare, requesterID uuid.UUID) ([]*entities.PayoutItem, error)
+	GetPayoutQueue(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.PayoutQueueGroup, error)
+	UpdatePayoutBeneficiary(ctx context.Context, itemID uuid.UUID, req *entities.UpdatePayoutBeneficiaryRequest, updaterID uuid.UUID) (*entities.PayoutItem, error)
+
+	// Batches and bank files
+	CreatePayoutBatch(ctx context.Context, req *entities.CreatePayoutBatchRequest, creatorID uuid.UUID) (*entities.PayoutBatch, error)
+	GetPayoutBatch(ctx context.Context, batchID uuid.UUID) (*entities.PayoutBatch, error)
+	GetPayoutBatches(ctx context.Context, cooperativeID uuid.UUID, page, limit int) ([]*entities.PayoutBatch, int, error)
+	ExportPayoutBatch(ctx context.Context, batchID uuid.UUID, format string, exporterID uuid.UUID) (*entities.PayoutFile, error)
+	ImportPayoutReturn(ctx context.Context, batchID uuid.UUID, req *entities.ImportPayoutReturnRequest, r io.Reader, importerID uuid.UUID) (*entities.PayoutReturnResult, error)
+}
+
+// payoutService implements PayoutService
+type payoutService struct {
+	payoutRepo       repositories.PayoutRepository
+	ledgerService    LedgerService
+	auditService     AuditService
+	csvFormat        PayoutCSVFormat
+	sanctionsService SanctionsScreeningService
+}
+
+// NewPayoutService creates a new payout service. csvFormat is the layout of the
+// bank's CSV bulk transfer and return files. Beneficiaries are screened against
+// sanctions lists before they are batched when sanctionsService is set.
+func NewPayoutService(payoutRepo repositories.PayoutRepository, ledgerService LedgerService, auditService AuditService, csvFormat PayoutCSVFormat, sanctionsService SanctionsScreeningService) PayoutService {
+	return &payoutService{
+		payoutRepo:       payoutRepo,
+		ledgerService:    ledgerService,
+		auditService:     auditService,
+		csvFormat:        csvFormat,
+		sanctionsService: sanctionsService,
+	}
+}
+
+// QueueDisbursement queues an approved disbursement for payment to the business
+func (s *payoutService) QueueDisbursement(ctx context.Context, disbursement *entities.FundDisbursement, requesterID uuid.UUID) (*entities.PayoutItem, error) {
+	if disbursement.Status != entities.FundDisbursementStatusApproved {
+		return nil, fmt.Errorf("disbursement is %s and cannot be paid out", disbursement.Status)
+	}
+	if !disbursement.DisbursementAmount.IsPositive() {
+		return nil, fmt.Errorf("disbursement amount must be positive")
+	}
+
+	escrowAccountID, err := s.escrowAccountID(ctx, disbursement.CooperativeID, disbursement.EscrowAccountID, disbursement.DisbursementAmount.Currency())
+	if err != nil {
+		return nil, err
+	}
+
+	item := newPayoutItem(disbursement.CooperativeID, escrowAccountID, entities.PayoutTypeDisbursement, disbursement.ID,
+		disbursement.BankAccount, disbursement.DisbursementAmount, disbursement.DisbursementReason)
+	if err := s.payoutRepo.CreateItems(ctx, []*entities.PayoutItem{item}); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     requesterID,
+		Operation:  "queue_payout",
+		EntityType: "fund_disbursement",
+		EntityID:   disbursement.ID,
+		NewValues:  fmt.Sprintf("Queued payout %s of %s", item.Reference, item.Amount),
+	})
+
+	return item, nil
+}
+
+// QueueInvestorRefunds queues the investor refunds of a refund being processed.
+// Refunds with nothing left to pay after fees are not queued.
+func (s *payoutService) QueueInvestorRefunds(ctx context.Context, refund *entities.FundRefund, investorRefunds []*entities.InvestorRefund, requesterID uuid.UUID) ([]*entities.PayoutItem, error) {
+	var items []*entities.PayoutItem
+	for _, investorRefund := range investorRefunds {
+		if investorRefund.Status != entities.FundRefundStatusPending && investorRefund.Status != entities.FundRefundStatusProcessing {
+			return nil, fmt.Errorf("investor refund %s is %s and cannot be paid out", investorRefund.ID, investorRefund.Status)
+		}
+		if !investorRefund.NetRefundAmount.IsPositive() {
+			continue
+		}
+
+		escrowAccountID, err := s.escrowAccountID(ctx, refund.CooperativeID, refund.EscrowAccountID, investorRefund.NetRefundAmount.Currency())
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, newPayoutItem(refund.CooperativeID, escrowAccountID, entities.PayoutTypeRefund, investorRefund.ID,
+			investorRefund.BankAccount, investorRefund.NetRefundAmount, "Refund: "+refund.RefundReason))
+	}
+
+	if err := s.payoutRepo.CreateItems(ctx, items); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     requesterID,
+		Operation:  "queue_payout",
+		EntityType: "fund_refund",
+		EntityID:   refund.ID,
+		NewValues:  fmt.Sprintf("Queued %d investor refund payouts", len(items)),
+	})
+
+	return items, nil
+}
+
+// QueueProfitShares queues the investor profit shares of a distribution being
+// processed. Shares with nothing left to pay after tax are not queued.
+func (s *payoutService) QueueProfitShares(ctx context.Context, distribution *entities.ProfitDistributionExtended, shares []*entities.InvestorProfitShare, requesterID uuid.UUID) ([]*entities.PayoutItem, error) {
+	description := fmt.Sprintf("Profit share %s", distribution.DistributionDate.Format("2006-01"))
+
+	var items []*entities.PayoutItem
+	for _, share := range shares {
+		if share.Status != entities.InvestorProfitShareStatusPending && share.Status != entities.InvestorProfitShareStatusProcessed {
+			return nil, fmt.Errorf("profit share %s is %s and cannot be paid out", share.ID, share.Status)
+		}
+		// Any reinvested part stays in escrow for the new investment
+		amount := share.PayableAmount()
+		if !amount.IsPositive() {
+			continue
+		}
+
+		escrowAccountID, err := s.escrowAccountID(ctx, distribution.CooperativeID, distribution.EscrowAccountID, amount.Currency())
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, newPayoutItem(distribution.CooperativeID, escrowAccountID, entities.PayoutTypeProfitShare, share.ID,
+			share.BankAccount, amount, description))
+	}
+
+	if err := s.payoutRepo.CreateItems(ctx, items); err != nil {
+		return nil, err
+	}
+
+	// Close the investors' shares against escrow so that escrow reconciles
+	// with the bank once the payouts leave it
+	if len(items) > 0 {
+		if _, err := s.ledgerService.RecordProfitSharePayouts(ctx, distribution, shares, requesterID); err != nil {
+			return nil, fmt.Errorf("profit share payouts queued but not recorded in ledger: %w", err)
+		}
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     requesterID,
+		Operation:  "queue_payout",
+		EntityType: "profit_distribution",
+		EntityID:   distribution.ID,
+		NewValues:  fmt.Sprintf("Queued %d profit share payouts", len(items)),
+	})
+
+	return items, nil
+}
+
+// GetPayoutQueue summarises a cooperative's queued payouts per escrow account and currency
+func (s *payoutService) GetPayoutQueue(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.PayoutQueueGroup, error) {
+	items, err := s.payoutRepo.ListQueuedItems(ctx, cooperativeID)
+	if err != nil {
+		return nil, err
+	}
+
+	type groupKey struct {
+		escrowAccountID uuid.UUID
+		currency        string
+	}
+	groups := make(map[groupKey]*entities.PayoutQueueGroup)
+	var ordered []*entities.PayoutQueueGroup
+	for _, item := range items {
+		key := groupKey{item.EscrowAccountID, item.Currency}
+		group, ok := groups[key]
+		if !ok {
+			group = &entities.PayoutQueueGroup{
+				EscrowAccountID: item.EscrowAccountID,
+				Currency:        item.Currency,
+				TotalAmount:     entities.ZeroMoney(item.Currency),
+			}
+			groups[key] = group
+			ordered = append(ordered, group)
+		}
+
+		if item.BeneficiaryAccount == "" {
+			group.MissingBeneficiary++
+			continue
+		}
+		group.ItemCount++
+		group.TotalAmount = group.TotalAmount.Add(item.Amount)
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].EscrowAccountID != ordered[j].EscrowAccountID {
+			return ordered[i].EscrowAccountID.String() < ordered[j].EscrowAccountID.String()
+		}
+		return ordered[i].Currency < ordered[j].Currency
+	})
+
+	return ordered, nil
+}
+
+// UpdatePayoutBeneficiary supplies or corrects a payout's beneficiary account. A
+// queued payout is updated in place; a failed payout is queued again as a new
+// payout, drawn from escrow again, so the failed attempt stays on its batch.
+func (s *payoutService) UpdatePayoutBeneficiary(ctx context.Context, itemID uuid.UUID, req *entities.UpdatePayoutBeneficiaryRequest, updaterID uuid.UUID) (*entities.PayoutItem, error) {
+	item, err := s.payoutRepo.GetItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch item.Status {
+	case entities.PayoutItemStatusQueued:
+		item.BeneficiaryName = req.BeneficiaryName
+		item.BeneficiaryAccount = req.BeneficiaryAccount
+		item.BeneficiaryBankCode = req.BeneficiaryBankCode
+		if err := s.payoutRepo.UpdateItem(ctx, item); err != nil {
+			return nil, err
+		}
+	case entities.PayoutItemStatusFailed:
+		failedReference := item.Reference
+		retry := newPayoutItem(item.CooperativeID, item.EscrowAccountID, item.PayoutType, item.SourceID,
+			req.BeneficiaryAccount, item.Amount, item.Description)
+		retry.BeneficiaryName = req.BeneficiaryName
+		retry.BeneficiaryBankCode = req.BeneficiaryBankCode
+		if err := s.payoutRepo.CreateItems(ctx, []*entities.PayoutItem{retry}); err != nil {
+			return nil, err
+		}
+		// The returned amount is drawn from escrow again for the new payout
+		if _, err := s.ledgerService.RecordPayoutRequeued(ctx, item, retry, updaterID); err != nil {
+			return nil, fmt.Errorf("payout %s re-queued but not recorded in ledger: %w", retry.Reference, err)
+		}
+		item = retry
+
+		s.auditService.LogOperation(ctx, &LogOperationRequest{
+			UserID:     updaterID,
+			Operation:  "requeue_payout",
+			EntityType: "payout_item",
+			EntityID:   item.ID,
+			NewValues:  fmt.Sprintf("Re-queued failed payout %s as %s", failedReference, item.Reference),
+		})
+	default:
+		return nil, fmt.Errorf("payout is %s and its beneficiary can no longer be changed", item.Status)
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     updaterID,
+		Operation:  "update_payout_beneficiary",
+		EntityType: "payout_item",
+		EntityID:   item.ID,
+		NewValues:  fmt.Sprintf("Beneficiary set to account %s (%s)", item.BeneficiaryAccount, item.BeneficiaryName),
+	})
+
+	return item, nil
+}
+
+// CreatePayoutBatch batches the queued payouts of an escrow account that have a
+// beneficiary account. Payouts still missing one stay queued.
+func (s *payoutService) CreatePayoutBatch(ctx context.Context, req *entities.CreatePayoutBatchRequest, creatorID uuid.UUID) (*entities.PayoutBatch, error) {
+	now := time.Now()
+	executionDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if req.ExecutionDate != nil {
+		requested := *req.ExecutionDate
+		if requested.Before(executionDate) {
+			return nil, fmt.Errorf("execution date cannot be in the past")
+		}
+		executionDate = time.Date(requested.Year(), requested.Month(), requested.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	queued, err := s.payoutRepo.ListQueuedItems(ctx, req.CooperativeID)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := strings.ToUpper(req.Currency)
+	total := entities.ZeroMoney(currency)
+	var items []*entities.PayoutItem
+	held := 0
+	for _, item := range queued {
+		if item.EscrowAccountID != req.EscrowAccountID || item.Currency != currency || item.BeneficiaryAccount == "" {
+			continue
+		}
+		blocked, err := s.heldForSanctionsReview(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			held++
+			continue
+		}
+		items = append(items, item)
+		total = total.Add(item.Amount)
+	}
+	if len(items) == 0 {
+		if held > 0 {
+			return nil, fmt.Errorf("no queued payouts ready for escrow account %s in %s; %d held for sanctions review", req.EscrowAccountID, currency, held)
+		}
+		return nil, fmt.Errorf("no queued payouts ready for escrow account %s in %s", req.EscrowAccountID, currency)
+	}
+
+	batchID := uuid.New()
+	batch := &entities.PayoutBatch{
+		ID:              batchID,
+		CooperativeID:   req.CooperativeID,
+		EscrowAccountID: req.EscrowAccountID,
+		Reference:       entities.PayoutReference(batchID),
+		Currency:        currency,
+		DebtorName:      req.DebtorName,
+		DebtorAccount:   req.DebtorAccount,
+		DebtorBankCode:  req.DebtorBankCode,
+		ExecutionDate:   executionDate,
+		Status:          entities.PayoutBatchStatusDraft,
+		ItemCount:       len(items),
+		TotalAmount:     total,
+		CreatedBy:       creatorID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := s.payoutRepo.CreateBatch(ctx, batch, items); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		item.BatchID = &batch.ID
+		item.Status = entities.PayoutItemStatusBatched
+	}
+	batch.Items = items
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     creatorID,
+		Operation:  "create_payout_batch",
+		EntityType: "payout_batch",
+		EntityID:   batch.ID,
+		NewValues:  fmt.Sprintf("Batched %d payouts totalling %s from escrow account %s", batch.ItemCount, batch.TotalAmount, batch.EscrowAccountID),
+	})
+	if held > 0 {
+		s.auditService.LogOperation(ctx, &LogOperationRequest{
+			UserID:     creatorID,
+			Operation:  "hold_payouts",
+			EntityType: "payout_batch",
+			EntityID:   batch.ID,
+			NewValues:  fmt.Sprintf("Held %d payouts from escrow account %s for sanctions review", held, batch.EscrowAccountID),
+		})
+	}
+
+	return batch, nil
+}
+
+// GetPayoutBatch gets a batch with its payouts
+func (s *payoutService) GetPayoutBatch(ctx context.Context, batchID uuid.UUID) (*entities.PayoutBatch, error) {
+	batch, err := s.payoutRepo.GetBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	batch.Items, err = s.payoutRepo.ListBatchItems(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// GetPayoutBatches lists a cooperative's batches, newest first
+func (s *payoutService) GetPayoutBatches(ctx context.Context, cooperativeID uuid.UUID, page, limit int) ([]*entities.PayoutBatch, int, error) {
+	page, limit = normalizePage(page, limit)
+	return s.payoutRepo.ListBatches(ctx, cooperativeID, limit, (page-1)*limit)
+}
+
+// ExportPayoutBatch generates the bank file for a batch. A batch can be exported
+// again, e.g. in the other format, until the bank reports on it; after that a new
+// file could make the bank pay twice.
+func (s *payoutService) ExportPayoutBatch(ctx context.Context, batchID uuid.UUID, format string, exporterID uuid.UUID) (*entities.PayoutFile, error) {
+	batch, err := s.GetPayoutBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status != entities.PayoutBatchStatusDraft && batch.Status != entities.PayoutBatchStatusExported {
+		return nil, fmt.Errorf("payout batch is %s and can no longer be exported", batch.Status)
+	}
+	if batch.CompletedCount > 0 || batch.FailedCount > 0 {
+		return nil, fmt.Errorf("the bank has already reported on payout batch %s", batch.Reference)
+	}
+
+	exportedAt := time.Now()
+	if batch.ExportedAt != nil {
+		exportedAt = *batch.ExportedAt
+	}
+
+	file := &entities.PayoutFile{}
+	switch format {
+	case entities.PayoutFileFormatPain001:
+		file.FileName = fmt.Sprintf("payout-%s.xml", batch.Reference)
+		file.ContentType = "application/xml"
+		file.Content, err = generatePain001(batch, batch.Items, exportedAt)
+	case entities.PayoutFileFormatCSV:
+		file.FileName = fmt.Sprintf("payout-%s.csv", batch.Reference)
+		file.ContentType = "text/csv"
+		file.Content, err = generatePayoutCSV(batch, batch.Items, s.csvFormat)
+	default:
+		return nil, fmt.Errorf("unsupported payout file format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	batch.Status = entities.PayoutBatchStatusExported
+	batch.ExportFormat = format
+	batch.ExportedBy = &exporterID
+	batch.ExportedAt = &exportedAt
+	if err := s.payoutRepo.UpdateBatch(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     exporterID,
+		Operation:  "export_payout_batch",
+		EntityType: "payout_batch",
+		EntityID:   batch.ID,
+		NewValues:  fmt.Sprintf("Exported %s as %s", batch.Reference, format),
+	})
+
+	return file, nil
+}
+
+// ImportPayoutReturn applies the bank's return file to an exported batch, marking
+// each payout completed or failed. Failed payouts are posted back into escrow,
+// owed to their beneficiaries until queued again. Payouts the bank has not
+// settled yet stay batched, so later return files for the same batch can be
+// imported too.
+func (s *payoutService) ImportPayoutReturn(ctx context.Context, batchID uuid.UUID, req *entities.ImportPayoutReturnRequest, r io.Reader, importerID uuid.UUID) (*entities.PayoutReturnResult, error) {
+	batch, err := s.GetPayoutBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status == entities.PayoutBatchStatusDraft {
+		return nil, fmt.Errorf("payout batch %s has not been exported", batch.Reference)
+	}
+
+	file, err := parsePayoutReturn(req.Format, r, s.csvFormat)
+	if err != nil {
+		return nil, err
+	}
+	if file.OriginalMessageID != "" && file.OriginalMessageID != batch.Reference {
+		return nil, fmt.Errorf("return file reports on %s, not on payout batch %s", file.OriginalMessageID, batch.Reference)
+	}
+
+	byReference := make(map[string]*entities.PayoutItem, len(batch.Items))
+	for _, item := range batch.Items {
+		byReference[item.Reference] = item
+	}
+
+	result := &entities.PayoutReturnResult{Batch: batch}
+	reported := make(map[string]bool)
+	var changed []*entities.PayoutItem
+	now := time.Now()
+
+	for _, line := range file.Lines {
+		item, ok := byReference[line.Reference]
+		if !ok {
+			result.Unmatched = append(result.Unmatched, line.Reference)
+			continue
+		}
+		reported[item.Reference] = true
+
+		switch {
+		case line.Status == entities.PayoutReturnStatusPending:
+			result.Pending++
+		case item.IsFinal():
+			result.Unchanged++
+		case line.Status == entities.PayoutReturnStatusCompleted:
+			item.Status = entities.PayoutItemStatusCompleted
+			item.BankReference = line.BankReference
+			item.CompletedAt = &now
+			changed = append(changed, item)
+			result.Completed = append(result.Completed, item)
+		default:
+			item.Status = entities.PayoutItemStatusFailed
+			item.FailureReason = line.Reason
+			if item.FailureReason == "" {
+				item.FailureReason = "rejected by bank"
+			}
+			item.BankReference = line.BankReference
+			changed = append(changed, item)
+			result.Failed = append(result.Failed, item)
+		}
+	}
+
+	// A rejected file fails every payout the bank did not report on separately
+	if file.Rejected {
+		for _, item := range batch.Items {
+			if reported[item.Reference] || item.IsFinal() {
+				continue
+			}
+			item.Status = entities.PayoutItemStatusFailed
+			item.FailureReason = file.Reason
+			if item.FailureReason == "" {
+				item.FailureReason = "batch rejected by bank"
+			}
+			changed = append(changed, item)
+			result.Failed = append(result.Failed, item)
+		}
+	}
+
+	if len(changed) > 0 {
+		settlePayoutBatch(batch)
+		if err := s.payoutRepo.SaveReturn(ctx, batch, changed); err != nil {
+			return nil, err
+		}
+
+		for _, item := range changed {
+			s.auditService.LogOperation(ctx, &LogOperationRequest{
+				UserID:     importerID,
+				Operation:  "payout_" + item.Status,
+				EntityType: payoutSourceEntityType(item.PayoutType),
+				EntityID:   item.SourceID,
+				NewValues:  fmt.Sprintf("Payout %s of %s %s in batch %s %s", item.Reference, item.Amount, item.Status, batch.Reference, item.FailureReason),
+			})
+		}
+
+		// Money the bank returned is back in escrow, owed to the beneficiary
+		// until the payout is queued again
+		var ledgerErrs []error
+		for _, item := range result.Failed {
+			if _, err := s.ledgerService.RecordPayoutReturned(ctx, item, importerID); err != nil {
+				ledgerErrs = append(ledgerErrs, fmt.Errorf("payout %s returned but not recorded in ledger: %w", item.Reference, err))
+			}
+		}
+		if err := errors.Join(ledgerErrs...); err != nil {
+			return nil, err
+		}
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     importerID,
+		Operation:  "import_payout_return",
+		EntityType: "payout_batch",
+		EntityID:   batch.ID,
+		NewValues:  fmt.Sprintf("Imported %s return: %d completed, %d failed, %d pending, %d unmatched", req.Format, len(result.Completed), len(result.Failed), result.Pending, len(result.Unmatched)),
+	})
+
+	return result, nil
+}
+
+// escrowAccountID returns the escrow account a payout is drawn from, falling back
+// to the cooperative's escrow ledger account in the payout's currency
+func (s *payoutService) escrowAccountID(ctx context.Context, cooperativeID, escrowAccountID uuid.UUID, currency string) (uuid.UUID, error) {
+	if escrowAccountID != uuid.Nil {
+		return escrowAccountID, nil
+	}
+
+	account, err := s.ledgerService.GetOrCreateAccount(ctx, cooperativeID, entities.LedgerAccountCategoryEscrow, nil, currency)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get escrow account: %w", err)
+	}
+	return account.ID, nil
+}
+
+// heldForSanctionsReview screens a payout's beneficiary against sanctions
+// lists; payouts to a beneficiary held for review stay queued
+func (s *payoutService) heldForSanctionsReview(ctx context.Context, item *entities.PayoutItem) (bool, error) {
+	if s.sanctionsService == nil || item.BeneficiaryName == "" {
+		return false, nil
+	}
+
+	itemID, cooperativeID := item.ID, item.CooperativeID
+	screening, err := s.sanctionsService.ScreenSubject(ctx, &entities.SanctionsScreeningSubject{
+		SubjectType:   entities.SanctionsSubjectBeneficiary,
+		Reference:     "beneficiary:" + item.BeneficiaryBankCode + "/" + item.BeneficiaryAccount,
+		SubjectID:     &itemID,
+		Name:          item.BeneficiaryName,
+		Purpose:       entities.SanctionsPurposePayout,
+		CooperativeID: &cooperativeID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to screen beneficiary of payout %s: %w", item.Reference, err)
+	}
+	return screening.IsBlocking(), nil
+}
+
+// settlePayoutBatch recounts a batch's results and closes it once every payout is settled
+func settlePayoutBatch(batch *entities.PayoutBatch) {
+	batch.CompletedCount, batch.FailedCount = 0, 0
+	for _, item := range batch.Items {
+		switch item.Status {
+		case entities.PayoutItemStatusCompleted:
+			batch.CompletedCount++
+		case entities.PayoutItemStatusFailed:
+			batch.FailedCount++
+		}
+	}
+
+	switch {
+	case batch.CompletedCount+batch.FailedCount < batch.ItemCount:
+		batch.Status = entities.PayoutBatchStatusExported
+	case batch.FailedCount == 0:
+		batch.Status = entities.PayoutBatchStatusCompleted
+	case batch.CompletedCount == 0:
+		batch.Status = entities.PayoutBatchStatusFailed
+	default:
+		batch.Status = entities.PayoutBatchStatusPartiallyCompleted
+	}
+}
+
+func newPayoutItem(cooperativeID, escrowAccountID uuid.UUID, payoutType string, sourceID uuid.UUID, beneficiaryAccount string, amount entities.Money, description string) *entities.PayoutItem {
+	now := time.Now()
+	id := uuid.New()
+	return &entities.PayoutItem{
+		ID:                 id,
+		CooperativeID:      cooperativeID,
+		EscrowAccountID:    escrowAccountID,
+		PayoutType:         payoutType,
+		SourceID:           sourceID,
+		BeneficiaryAccount: beneficiaryAccount,
+		Amount:             amount,
+		Currency:           amount.Currency(),
+		Reference:          entities.PayoutReference(id),
+		Description:        truncateRunes(description, 140),
+		Status:             entities.PayoutItemStatusQueued,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+}
+
+// payoutSourceEntityType is the audit entity type of a payout's source record
+func payoutSourceEntityType(payoutType string) string {
+	switch payoutType {
+	case entities.PayoutTypeDisbursement:
+		return "fund_disbursement"
+	case entities.PayoutTypeRefund:
+		return "investor_refund"
+	default:
+		return "investor_profit_share"
+	}
+}
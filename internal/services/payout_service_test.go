@@ -0,0 +1,633 @@
+package services
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// payoutItem returns a queued profit share payout to a beneficiary account
+func payoutItem(escrowAccountID uuid.UUID, amount, account string) *entities.PayoutItem {
+	item := newPayoutItem(uuid.New(), escrowAccountID, entities.PayoutTypeProfitShare, uuid.New(), account, idr(amount), "Profit share 2024-03")
+	item.BeneficiaryName = "Investor " + account
+	return item
+}
+
+// exportedPayoutBatch builds an exported batch of the given items
+func exportedPayoutBatch(items ...*entities.PayoutItem) *entities.PayoutBatch {
+	batchID := uuid.New()
+	exportedAt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	batch := &entities.PayoutBatch{
+		ID:              batchID,
+		CooperativeID:   items[0].CooperativeID,
+		EscrowAccountID: items[0].EscrowAccountID,
+		Reference:       entities.PayoutReference(batchID),
+		Currency:        "IDR",
+		DebtorName:      "Koperasi Syariah Maju",
+		DebtorAccount:   "0012345678",
+		DebtorBankCode:  "BSMDIDJA",
+		ExecutionDate:   time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+		Status:          entities.PayoutBatchStatusExported,
+		ItemCount:       len(items),
+		TotalAmount:     idr("0"),
+		ExportFormat:    entities.PayoutFileFormatPain001,
+		ExportedAt:      &exportedAt,
+	}
+	for _, item := range items {
+		item.BatchID = &batch.ID
+		item.Status = entities.PayoutItemStatusBatched
+		batch.TotalAmount = batch.TotalAmount.Add(item.Amount)
+	}
+	return batch
+}
+
+// expectPayoutLedger serves the escrow and payouts pending ledger accounts and
+// collects the journal entries posted against them
+func expectPayoutLedger(ledgerRepo *MockLedgerRepository) (escrow, pending *entities.LedgerAccount, posted *[]*entities.JournalEntry) {
+	escrow = &entities.LedgerAccount{ID: uuid.New(), Category: entities.LedgerAccountCategoryEscrow, Currency: "IDR"}
+	pending = &entities.LedgerAccount{ID: uuid.New(), Category: entities.LedgerAccountCategoryPayoutsPending, Currency: "IDR"}
+	ledgerRepo.On("GetAccountByCode", mock.Anything, mock.Anything, "ESCROW", "IDR").Return(escrow, nil)
+	ledgerRepo.On("GetAccountByCode", mock.Anything, mock.Anything, "PAYOUTS_PENDING", "IDR").Return(pending, nil)
+
+	posted = new([]*entities.JournalEntry)
+	ledgerRepo.On("PostEntry", mock.Anything, mock.AnythingOfType("*entities.JournalEntry")).Run(func(args mock.Arguments) {
+		*posted = append(*posted, args.Get(1).(*entities.JournalEntry))
+	}).Return(nil)
+	return escrow, pending, posted
+}
+
+func TestPayoutService_QueueDisbursement(t *testing.T) {
+	ledgerRepo := new(MockLedgerRepository)
+	payoutRepo := new(MockPayoutRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(ledgerRepo, mockAuditService), mockAuditService, DefaultPayoutCSVFormat, nil)
+	ctx := context.Background()
+
+	disbursement := &entities.FundDisbursement{
+		ID:                 uuid.New(),
+		CooperativeID:      uuid.New(),
+		DisbursementAmount: idr("50000"),
+		Currency:           "IDR",
+		DisbursementReason: "Equipment purchase milestone completed",
+		Status:             entities.FundDisbursementStatusApproved,
+		BankAccount:        "1234567890",
+	}
+
+	// Without an explicit escrow account the cooperative's escrow ledger account is used
+	var escrow *entities.LedgerAccount
+	ledgerRepo.On("GetAccountByCode", ctx, disbursement.CooperativeID, mock.AnythingOfType("string"), "IDR").Return(nil, repositories.ErrLedgerAccountNotFound)
+	ledgerRepo.On("CreateAccount", ctx, mock.AnythingOfType("*entities.LedgerAccount")).Run(func(args mock.Arguments) {
+		escrow = args.Get(1).(*entities.LedgerAccount)
+	}).Return(nil)
+	payoutRepo.On("CreateItems", ctx, mock.AnythingOfType("[]*entities.PayoutItem")).Return(nil)
+
+	item, err := payoutService.QueueDisbursement(ctx, disbursement, uuid.New())
+
+	require.NoError(t, err)
+	require.NotNil(t, escrow)
+	assert.Equal(t, entities.LedgerAccountCategoryEscrow, escrow.Category)
+	assert.Equal(t, escrow.ID, item.EscrowAccountID)
+	assert.Equal(t, entities.PayoutTypeDisbursement, item.PayoutType)
+	assert.Equal(t, disbursement.ID, item.SourceID)
+	assert.Equal(t, "1234567890", item.BeneficiaryAccount)
+	assert.Equal(t, idr("50000"), item.Amount)
+	assert.Equal(t, entities.PayoutItemStatusQueued, item.Status)
+	assert.Len(t, item.Reference, 32)
+}
+
+func TestPayoutService_QueueDisbursement_NotApproved(t *testing.T) {
+	payoutRepo := new(MockPayoutRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(new(MockLedgerRepository), mockAuditService), mockAuditService, DefaultPayoutCSVFormat, nil)
+
+	item, err := payoutService.QueueDisbursement(context.Background(), &entities.FundDisbursement{
+		ID:                 uuid.New(),
+		DisbursementAmount: idr("50000"),
+		Status:             entities.FundDisbursementStatusPending,
+	}, uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, item)
+	assert.Contains(t, err.Error(), "cannot be paid out")
+	payoutRepo.AssertNotCalled(t, "CreateItems", mock.Anything, mock.Anything)
+}
+
+func TestPayoutService_QueueInvestorRefunds(t *testing.T) {
+	payoutRepo := new(MockPayoutRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(new(MockLedgerRepository), mockAuditService), mockAuditService, DefaultPayoutCSVFormat, nil)
+	ctx := context.Background()
+
+	refund := &entities.FundRefund{ID: uuid.New(), CooperativeID: uuid.New(), EscrowAccountID: uuid.New(), RefundReason: "Minimum funding not met"}
+	refunds := []*entities.InvestorRefund{
+		{ID: uuid.New(), NetRefundAmount: idr("24750"), Status: entities.FundRefundStatusProcessing, BankAccount: "111"},
+		{ID: uuid.New(), NetRefundAmount: idr("0"), Status: entities.FundRefundStatusProcessing, BankAccount: "222"},
+		{ID: uuid.New(), NetRefundAmount: idr("44550"), Status: entities.FundRefundStatusProcessing},
+	}
+	payoutRepo.On("CreateItems", ctx, mock.AnythingOfType("[]*entities.PayoutItem")).Return(nil)
+
+	items, err := payoutService.QueueInvestorRefunds(ctx, refund, refunds, uuid.New())
+
+	// Nothing is paid for a refund consumed by fees; a missing account is supplied later
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, refunds[0].ID, items[0].SourceID)
+	assert.Equal(t, refund.EscrowAccountID, items[0].EscrowAccountID)
+	assert.Equal(t, "Refund: Minimum funding not met", items[0].Description)
+	assert.Equal(t, refunds[2].ID, items[1].SourceID)
+	assert.Empty(t, items[1].BeneficiaryAccount)
+}
+
+func TestPayoutService_CreatePayoutBatch_GroupsByEscrowAccount(t *testing.T) {
+	payoutRepo := new(MockPayoutRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(new(MockLedgerRepository), mockAuditService), mockAuditService, DefaultPayoutCSVFormat, nil)
+	ctx := context.Background()
+
+	escrowA, escrowB := uuid.New(), uuid.New()
+	cooperativeID := uuid.New()
+	queued := []*entities.PayoutItem{
+		payoutItem(escrowA, "1000", "111"),
+		payoutItem(escrowB, "2000", "222"),
+		payoutItem(escrowA, "3000", ""),
+		payoutItem(escrowA, "4000", "444"),
+	}
+	for _, item := range queued {
+		item.CooperativeID = cooperativeID
+	}
+	payoutRepo.On("ListQueuedItems", ctx, cooperativeID).Return(queued, nil)
+
+	var batched []*entities.PayoutItem
+	payoutRepo.On("CreateBatch", ctx, mock.AnythingOfType("*entities.PayoutBatch"), mock.AnythingOfType("[]*entities.PayoutItem")).Run(func(args mock.Arguments) {
+		batched = args.Get(2).([]*entities.PayoutItem)
+	}).Return(nil)
+
+	queue, err := payoutService.GetPayoutQueue(ctx, cooperativeID)
+
+	require.NoError(t, err)
+	require.Len(t, queue, 2)
+	for _, group := range queue {
+		if group.EscrowAccountID == escrowA {
+			assert.Equal(t, 2, group.ItemCount)
+			assert.Equal(t, idr("5000"), group.TotalAmount)
+			assert.Equal(t, 1, group.MissingBeneficiary)
+		}
+	}
+
+	batch, err := payoutService.CreatePayoutBatch(ctx, &entities.CreatePayoutBatchRequest{
+		CooperativeID:   cooperativeID,
+		EscrowAccountID: escrowA,
+		Currency:        "idr",
+		DebtorName:      "Koperasi Syariah Maju",
+		DebtorAccount:   "0012345678",
+		DebtorBankCode:  "BSMDIDJA",
+	}, uuid.New())
+
+	// Only escrow A's payouts with a beneficiary account are batched
+	require.NoError(t, err)
+	assert.Equal(t, entities.PayoutBatchStatusDraft, batch.Status)
+	assert.Equal(t, 2, batch.ItemCount)
+	assert.Equal(t, idr("5000"), batch.TotalAmount)
+	assert.Equal(t, "IDR", batch.Currency)
+	assert.Equal(t, []*entities.PayoutItem{queued[0], queued[3]}, batched)
+	for _, item := range batch.Items {
+		assert.Equal(t, entities.PayoutItemStatusBatched, item.Status)
+		assert.Equal(t, batch.ID, *item.BatchID)
+	}
+}
+
+func TestPayoutService_CreatePayoutBatch_NothingReady(t *testing.T) {
+	payoutRepo := new(MockPayoutRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(new(MockLedgerRepository), mockAuditService), mockAuditService, DefaultPayoutCSVFormat, nil)
+	ctx := context.Background()
+
+	cooperativeID := uuid.New()
+	payoutRepo.On("ListQueuedItems", ctx, cooperativeID).Return([]*entities.PayoutItem{payoutItem(uuid.New(), "1000", "")}, nil)
+
+	batch, err := payoutService.CreatePayoutBatch(ctx, &entities.CreatePayoutBatchRequest{
+		CooperativeID:   cooperativeID,
+		EscrowAccountID: uuid.New(),
+		Currency:        "IDR",
+	}, uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, batch)
+	assert.Contains(t, err.Error(), "no queued payouts ready")
+}
+
+func TestPayoutService_CreatePayoutBatch_HoldsBeneficiaryForSanctionsReview(t *testing.T) {
+	sanctionsService, screeningRepo := newTestSanctionsScreeningService(t)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutRepo := new(MockPayoutRepository)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(new(MockLedgerRepository), mockAuditService), mockAuditService, DefaultPayoutCSVFormat, sanctionsService)
+	ctx := context.Background()
+
+	escrowAccountID, cooperativeID := uuid.New(), uuid.New()
+	cleared := payoutItem(escrowAccountID, "1000", "111")
+	cleared.BeneficiaryName = "Siti Rahmawati"
+	sanctioned := payoutItem(escrowAccountID, "2000", "222")
+	sanctioned.BeneficiaryName = "Hambali Isamudin"
+	for _, item := range []*entities.PayoutItem{cleared, sanctioned} {
+		item.CooperativeID = cooperativeID
+	}
+	payoutRepo.On("ListQueuedItems", ctx, cooperativeID).Return([]*entities.PayoutItem{cleared, sanctioned}, nil)
+	payoutRepo.On("CreateBatch", ctx, mock.AnythingOfType("*entities.PayoutBatch"), []*entities.PayoutItem{cleared}).Return(nil)
+	screeningRepo.On("ListSubjectReviews", ctx, entities.SanctionsSubjectBeneficiary, "beneficiary:/222").Return(nil, nil)
+	screeningRepo.On("CreateScreening", ctx, mock.AnythingOfType("*entities.SanctionsScreening")).Return(nil)
+
+	batch, err := payoutService.CreatePayoutBatch(ctx, &entities.CreatePayoutBatchRequest{
+		CooperativeID:   cooperativeID,
+		EscrowAccountID: escrowAccountID,
+		Currency:        "IDR",
+		DebtorName:      "Koperasi Syariah Maju",
+		DebtorAccount:   "0012345678",
+		DebtorBankCode:  "BSMDIDJA",
+	}, uuid.New())
+
+	// The potential match stays queued until it is reviewed
+	require.NoError(t, err)
+	assert.Equal(t, 1, batch.ItemCount)
+	assert.Equal(t, idr("1000"), batch.TotalAmount)
+	assert.Equal(t, entities.PayoutItemStatusQueued, sanctioned.Status)
+	screeningRepo.AssertNumberOfCalls(t, "CreateScreening", 2)
+}
+
+func TestPayoutService_ExportPain001(t *testing.T) {
+	payoutRepo := new(MockPayoutRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(new(MockLedgerRepository), mockAuditService), mockAuditService, DefaultPayoutCSVFormat, nil)
+	ctx := context.Background()
+
+	first := payoutItem(uuid.New(), "1500000", "7001234567")
+	first.BeneficiaryBankCode = "bmriidja"
+	second := payoutItem(first.EscrowAccountID, "250000.50", "8009876543")
+	second.BeneficiaryBankCode = "014"
+	second.Description = strings.Repeat("x", 200)
+	batch := exportedPayoutBatch(first, second)
+	batch.Status = entities.PayoutBatchStatusDraft
+	batch.ExportedAt = nil
+
+	payoutRepo.On("GetBatch", ctx, batch.ID).Return(batch, nil)
+	payoutRepo.On("ListBatchItems", ctx, batch).Return([]*entities.PayoutItem{first, second}, nil)
+	payoutRepo.On("UpdateBatch", ctx, batch).Return(nil)
+	exporterID := uuid.New()
+
+	file, err := payoutService.ExportPayoutBatch(ctx, batch.ID, entities.PayoutFileFormatPain001, exporterID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "payout-"+batch.Reference+".xml", file.FileName)
+	assert.Equal(t, entities.PayoutBatchStatusExported, batch.Status)
+	assert.Equal(t, exporterID, *batch.ExportedBy)
+	assert.Contains(t, string(file.Content), `xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"`)
+
+	var document struct {
+		MessageID     string `xml:"CstmrCdtTrfInitn>GrpHdr>MsgId"`
+		Count         int    `xml:"CstmrCdtTrfInitn>GrpHdr>NbOfTxs"`
+		ControlSum    string `xml:"CstmrCdtTrfInitn>GrpHdr>CtrlSum"`
+		ExecutionDate string `xml:"CstmrCdtTrfInitn>PmtInf>ReqdExctnDt"`
+		DebtorAccount string `xml:"CstmrCdtTrfInitn>PmtInf>DbtrAcct>Id>Othr>Id"`
+		DebtorBIC     string `xml:"CstmrCdtTrfInitn>PmtInf>DbtrAgt>FinInstnId>BIC"`
+		Transactions  []struct {
+			EndToEndID string `xml:"PmtId>EndToEndId"`
+			Amount     struct {
+				Currency string `xml:"Ccy,attr"`
+				Value    string `xml:",chardata"`
+			} `xml:"Amt>InstdAmt"`
+			BIC        string `xml:"CdtrAgt>FinInstnId>BIC"`
+			BankCode   string `xml:"CdtrAgt>FinInstnId>Othr>Id"`
+			Name       string `xml:"Cdtr>Nm"`
+			Account    string `xml:"CdtrAcct>Id>Othr>Id"`
+			Remittance string `xml:"RmtInf>Ustrd"`
+		} `xml:"CstmrCdtTrfInitn>PmtInf>CdtTrfTxInf"`
+	}
+	require.NoError(t, xml.Unmarshal(file.Content, &document))
+
+	assert.Equal(t, batch.Reference, document.MessageID)
+	assert.Equal(t, 2, document.Count)
+	assert.Equal(t, "1750000.50", document.ControlSum)
+	assert.Equal(t, "2024-03-04", document.ExecutionDate)
+	assert.Equal(t, "0012345678", document.DebtorAccount)
+	assert.Equal(t, "BSMDIDJA", document.DebtorBIC)
+	require.Len(t, document.Transactions, 2)
+	assert.Equal(t, first.Reference, document.Transactions[0].EndToEndID)
+	assert.Equal(t, "IDR", document.Transactions[0].Amount.Currency)
+	assert.Equal(t, "1500000.00", document.Transactions[0].Amount.Value)
+	assert.Equal(t, "BMRIIDJA", document.Transactions[0].BIC)
+	assert.Equal(t, "Investor 7001234567", document.Transactions[0].Name)
+	assert.Equal(t, "7001234567", document.Transactions[0].Account)
+	// Domestic bank codes that are not BICs are sent as proprietary identifiers
+	assert.Equal(t, "014", document.Transactions[1].BankCode)
+	assert.Len(t, document.Transactions[1].Remittance, 140)
+}
+
+func TestPayoutService_ExportRefusedOnceBankReported(t *testing.T) {
+	payoutRepo := new(MockPayoutRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(new(MockLedgerRepository), mockAuditService), mockAuditService, DefaultPayoutCSVFormat, nil)
+	ctx := context.Background()
+
+	item := payoutItem(uuid.New(), "1000", "111")
+	batch := exportedPayoutBatch(item)
+	batch.Status = entities.PayoutBatchStatusCompleted
+	payoutRepo.On("GetBatch", ctx, batch.ID).Return(batch, nil)
+	payoutRepo.On("ListBatchItems", ctx, batch).Return([]*entities.PayoutItem{item}, nil)
+
+	file, err := payoutService.ExportPayoutBatch(ctx, batch.ID, entities.PayoutFileFormatCSV, uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, file)
+	payoutRepo.AssertNotCalled(t, "UpdateBatch", mock.Anything, mock.Anything)
+}
+
+func TestGeneratePayoutCSV_ConfiguredLayout(t *testing.T) {
+	format, err := NewPayoutCSVFormat("reference, beneficiary_account,amount_minor_units,currency,execution_date,beneficiary_name", ";", false)
+	require.NoError(t, err)
+
+	first := payoutItem(uuid.New(), "1500000", "7001234567")
+	second := payoutItem(first.EscrowAccountID, "250000.50", "8009876543")
+	second.BeneficiaryName = "Siti; Aminah"
+	batch := exportedPayoutBatch(first, second)
+
+	content, err := generatePayoutCSV(batch, []*entities.PayoutItem{first, second}, format)
+
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%s;7001234567;150000000;IDR;2024-03-04;Investor 7001234567\n%s;8009876543;25000050;IDR;2024-03-04;\"Siti; Aminah\"\n",
+		first.Reference, second.Reference), string(content))
+}
+
+func TestNewPayoutCSVFormat_Invalid(t *testing.T) {
+	_, err := NewPayoutCSVFormat("reference,iban", ",", true)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown payout CSV column: iban")
+
+	_, err = NewPayoutCSVFormat("", "||", true)
+	assert.Error(t, err)
+
+	format, err := NewPayoutCSVFormat("", "tab", true)
+	require.NoError(t, err)
+	assert.Equal(t, '\t', format.Delimiter)
+	assert.Equal(t, DefaultPayoutCSVFormat.Columns, format.Columns)
+}
+
+const testPain002 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.002.001.03">
+  <CstmrPmtStsRpt>
+    <GrpHdr><MsgId>STS-0001</MsgId><CreDtTm>2024-03-04T15:00:00</CreDtTm></GrpHdr>
+    <OrgnlGrpInfAndSts>
+      <OrgnlMsgId>%s</OrgnlMsgId>
+      <OrgnlMsgNmId>pain.001.001.03</OrgnlMsgNmId>
+      <GrpSts>PART</GrpSts>
+    </OrgnlGrpInfAndSts>
+    <OrgnlPmtInfAndSts>
+      <OrgnlPmtInfId>%[1]s</OrgnlPmtInfId>
+      <TxInfAndSts>
+        <OrgnlEndToEndId>%s</OrgnlEndToEndId>
+        <TxSts>ACSC</TxSts>
+        <AcctSvcrRef>BNK-778899</AcctSvcrRef>
+      </TxInfAndSts>
+      <TxInfAndSts>
+        <OrgnlEndToEndId>%s</OrgnlEndToEndId>
+        <TxSts>RJCT</TxSts>
+        <StsRsnInf><Rsn><Cd>AC04</Cd></Rsn><AddtlInf>Closed account</AddtlInf></StsRsnInf>
+      </TxInfAndSts>
+      <TxInfAndSts>
+        <OrgnlEndToEndId>%s</OrgnlEndToEndId>
+        <TxSts>ACSP</TxSts>
+      </TxInfAndSts>
+    </OrgnlPmtInfAndSts>
+  </CstmrPmtStsRpt>
+</Document>`
+
+func TestPayoutService_ImportPayoutReturn_Pain002(t *testing.T) {
+	ledgerRepo := new(MockLedgerRepository)
+	payoutRepo := new(MockPayoutRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(ledgerRepo, mockAuditService), mockAuditService, DefaultPayoutCSVFormat, nil)
+	ctx := context.Background()
+	escrow, pending, posted := expectPayoutLedger(ledgerRepo)
+
+	paid := payoutItem(uuid.New(), "1000", "111")
+	rejected := payoutItem(paid.EscrowAccountID, "2000", "222")
+	inFlight := payoutItem(paid.EscrowAccountID, "3000", "333")
+	batch := exportedPayoutBatch(paid, rejected, inFlight)
+	payoutRepo.On("GetBatch", ctx, batch.ID).Return(batch, nil)
+	payoutRepo.On("ListBatchItems", ctx, batch).Return([]*entities.PayoutItem{paid, rejected, inFlight}, nil)
+	payoutRepo.On("SaveReturn", ctx, batch, []*entities.PayoutItem{paid, rejected}).Return(nil)
+
+	report := fmt.Sprintf(testPain002, batch.Reference, paid.Reference, rejected.Reference, inFlight.Reference)
+	req := &entities.ImportPayoutReturnRequest{Format: entities.PayoutReturnFormatPain002}
+
+	result, err := payoutService.ImportPayoutReturn(ctx, batch.ID, req, strings.NewReader(report), uuid.New())
+
+	require.NoError(t, err)
+	assert.Equal(t, []*entities.PayoutItem{paid}, result.Completed)
+	assert.Equal(t, []*entities.PayoutItem{rejected}, result.Failed)
+	assert.Equal(t, 1, result.Pending)
+	assert.Equal(t, entities.PayoutItemStatusCompleted, paid.Status)
+	assert.Equal(t, "BNK-778899", paid.BankReference)
+	assert.NotNil(t, paid.CompletedAt)
+	assert.Equal(t, entities.PayoutItemStatusFailed, rejected.Status)
+	assert.Equal(t, "AC04: Closed account", rejected.FailureReason)
+	assert.Equal(t, entities.PayoutItemStatusBatched, inFlight.Status)
+	// The batch stays open until the bank settles every payout
+	assert.Equal(t, entities.PayoutBatchStatusExported, batch.Status)
+	assert.Equal(t, 1, batch.CompletedCount)
+	assert.Equal(t, 1, batch.FailedCount)
+
+	// Only the rejected payout goes back into escrow, owed to its beneficiary
+	require.Len(t, *posted, 1)
+	entry := (*posted)[0]
+	assert.Equal(t, rejected.ID, entry.ReferenceID)
+	assert.Equal(t, entities.JournalEntryTypeProfitDistribution, entry.EntryType)
+	require.Len(t, entry.Lines, 2)
+	assert.Equal(t, escrow.ID, entry.Lines[0].AccountID)
+	assert.Equal(t, idr("2000"), entry.Lines[0].Debit)
+	assert.Equal(t, pending.ID, entry.Lines[1].AccountID)
+	assert.Equal(t, idr("2000"), entry.Lines[1].Credit)
+}
+
+func TestPayoutService_ImportPayoutReturn_CSV(t *testing.T) {
+	format, err := NewPayoutCSVFormat("", ";", true)
+	require.NoError(t, err)
+	ledgerRepo := new(MockLedgerRepository)
+	payoutRepo := new(MockPayoutRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(ledgerRepo, mockAuditService), mockAuditService, format, nil)
+	ctx := context.Background()
+	expectPayoutLedger(ledgerRepo)
+
+	paid := payoutItem(uuid.New(), "1000", "111")
+	rejected := payoutItem(paid.EscrowAccountID, "2000", "222")
+	done := payoutItem(paid.EscrowAccountID, "3000", "333")
+	batch := exportedPayoutBatch(paid, rejected, done)
+	done.Status = entities.PayoutItemStatusCompleted
+	payoutRepo.On("GetBatch", ctx, batch.ID).Return(batch, nil)
+	payoutRepo.On("ListBatchItems", ctx, batch).Return([]*entities.PayoutItem{paid, rejected, done}, nil)
+	payoutRepo.On("SaveReturn", ctx, batch, []*entities.PayoutItem{paid, rejected}).Return(nil)
+
+	returnFile := "End To End ID;Status;Reason;Transaction ID\n" +
+		paid.Reference + ";SUCCESS;;TRX-1\n" +
+		rejected.Reference + ";Rejected;Invalid account number;TRX-2\n" +
+		done.Reference + ";success;;TRX-3\n" +
+		"UNKNOWNREF;success;;TRX-4\n"
+	req := &entities.ImportPayoutReturnRequest{Format: entities.PayoutReturnFormatCSV}
+
+	result, err := payoutService.ImportPayoutReturn(ctx, batch.ID, req, strings.NewReader(returnFile), uuid.New())
+
+	require.NoError(t, err)
+	assert.Len(t, result.Completed, 1)
+	assert.Len(t, result.Failed, 1)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Equal(t, []string{"UNKNOWNREF"}, result.Unmatched)
+	assert.Equal(t, "Invalid account number", rejected.FailureReason)
+	assert.Equal(t, entities.PayoutBatchStatusPartiallyCompleted, batch.Status)
+}
+
+func TestPayoutService_ImportPayoutReturn_RejectedFile(t *testing.T) {
+	ledgerRepo := new(MockLedgerRepository)
+	payoutRepo := new(MockPayoutRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(ledgerRepo, mockAuditService), mockAuditService, DefaultPayoutCSVFormat, nil)
+	ctx := context.Background()
+	_, _, posted := expectPayoutLedger(ledgerRepo)
+
+	first := payoutItem(uuid.New(), "1000", "111")
+	second := payoutItem(first.EscrowAccountID, "2000", "222")
+	batch := exportedPayoutBatch(first, second)
+	payoutRepo.On("GetBatch", ctx, batch.ID).Return(batch, nil)
+	payoutRepo.On("ListBatchItems", ctx, batch).Return([]*entities.PayoutItem{first, second}, nil)
+	payoutRepo.On("SaveReturn", ctx, batch, mock.AnythingOfType("[]*entities.PayoutItem")).Return(nil)
+
+	report := `<Document><CstmrPmtStsRpt><OrgnlGrpInfAndSts><OrgnlMsgId>` + batch.Reference + `</OrgnlMsgId>
+		<GrpSts>RJCT</GrpSts><StsRsnInf><Rsn><Cd>AM05</Cd></Rsn><AddtlInf>Duplicate file</AddtlInf></StsRsnInf>
+		</OrgnlGrpInfAndSts></CstmrPmtStsRpt></Document>`
+	req := &entities.ImportPayoutReturnRequest{Format: entities.PayoutReturnFormatPain002}
+
+	result, err := payoutService.ImportPayoutReturn(ctx, batch.ID, req, strings.NewReader(report), uuid.New())
+
+	require.NoError(t, err)
+	assert.Len(t, result.Failed, 2)
+	assert.Equal(t, "AM05: Duplicate file", first.FailureReason)
+	assert.Equal(t, entities.PayoutBatchStatusFailed, batch.Status)
+	assert.Len(t, *posted, 2)
+}
+
+func TestPayoutService_ImportPayoutReturn_LedgerFailure(t *testing.T) {
+	ledgerRepo := new(MockLedgerRepository)
+	payoutRepo := new(MockPayoutRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(ledgerRepo, mockAuditService), mockAuditService, DefaultPayoutCSVFormat, nil)
+	ctx := context.Background()
+
+	item := payoutItem(uuid.New(), "1000", "111")
+	batch := exportedPayoutBatch(item)
+	payoutRepo.On("GetBatch", ctx, batch.ID).Return(batch, nil)
+	payoutRepo.On("ListBatchItems", ctx, batch).Return([]*entities.PayoutItem{item}, nil)
+	payoutRepo.On("SaveReturn", ctx, batch, []*entities.PayoutItem{item}).Return(nil)
+	ledgerRepo.On("GetAccountByCode", ctx, item.CooperativeID, mock.AnythingOfType("string"), "IDR").
+		Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}, nil)
+	ledgerRepo.On("PostEntry", ctx, mock.AnythingOfType("*entities.JournalEntry")).Return(errors.New("ledger unavailable"))
+
+	report := `<Document><CstmrPmtStsRpt><OrgnlGrpInfAndSts><OrgnlMsgId>` + batch.Reference + `</OrgnlMsgId>
+		<GrpSts>RJCT</GrpSts></OrgnlGrpInfAndSts></CstmrPmtStsRpt></Document>`
+	req := &entities.ImportPayoutReturnRequest{Format: entities.PayoutReturnFormatPain002}
+
+	result, err := payoutService.ImportPayoutReturn(ctx, batch.ID, req, strings.NewReader(report), uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "returned but not recorded in ledger")
+}
+
+func TestPayoutService_ImportPayoutReturn_OtherBatch(t *testing.T) {
+	payoutRepo := new(MockPayoutRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(new(MockLedgerRepository), mockAuditService), mockAuditService, DefaultPayoutCSVFormat, nil)
+	ctx := context.Background()
+
+	item := payoutItem(uuid.New(), "1000", "111")
+	batch := exportedPayoutBatch(item)
+	payoutRepo.On("GetBatch", ctx, batch.ID).Return(batch, nil)
+	payoutRepo.On("ListBatchItems", ctx, batch).Return([]*entities.PayoutItem{item}, nil)
+
+	report := fmt.Sprintf(testPain002, "SOMEOTHERBATCH", item.Reference, "X", "Y")
+	req := &entities.ImportPayoutReturnRequest{Format: entities.PayoutReturnFormatPain002}
+
+	result, err := payoutService.ImportPayoutReturn(ctx, batch.ID, req, strings.NewReader(report), uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "not on payout batch")
+	assert.Equal(t, entities.PayoutItemStatusBatched, item.Status)
+	payoutRepo.AssertNotCalled(t, "SaveReturn", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPayoutService_UpdatePayoutBeneficiary_RequeuesFailedPayout(t *testing.T) {
+	ledgerRepo := new(MockLedgerRepository)
+	payoutRepo := new(MockPayoutRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	payoutService := NewPayoutService(payoutRepo, NewLedgerService(ledgerRepo, mockAuditService), mockAuditService, DefaultPayoutCSVFormat, nil)
+	ctx := context.Background()
+	escrow, pending, posted := expectPayoutLedger(ledgerRepo)
+
+	failed := payoutItem(uuid.New(), "2000", "222")
+	exportedPayoutBatch(failed)
+	failed.Status = entities.PayoutItemStatusFailed
+	failed.FailureReason = "AC04: Closed account"
+	payoutRepo.On("GetItem", ctx, failed.ID).Return(failed, nil)
+	payoutRepo.On("CreateItems", ctx, mock.AnythingOfType("[]*entities.PayoutItem")).Return(nil)
+
+	retry, err := payoutService.UpdatePayoutBeneficiary(ctx, failed.ID, &entities.UpdatePayoutBeneficiaryRequest{
+		BeneficiaryName:    "Siti Aminah",
+		BeneficiaryAccount: "999",
+	}, uuid.New())
+
+	// The failed attempt stays on its batch and a new payout is queued
+	require.NoError(t, err)
+	assert.NotEqual(t, failed.ID, retry.ID)
+	assert.NotEqual(t, failed.Reference, retry.Reference)
+	assert.Equal(t, failed.SourceID, retry.SourceID)
+	assert.Equal(t, failed.Amount, retry.Amount)
+	assert.Equal(t, "999", retry.BeneficiaryAccount)
+	assert.Equal(t, entities.PayoutItemStatusQueued, retry.Status)
+	assert.Nil(t, retry.BatchID)
+	assert.Equal(t, entities.PayoutItemStatusFailed, failed.Status)
+	payoutRepo.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+
+	// The amount owed to the beneficiary is drawn from escrow again
+	require.Len(t, *posted, 1)
+	entry := (*posted)[0]
+	assert.Equal(t, retry.ID, entry.ReferenceID)
+	require.Len(t, entry.Lines, 2)
+	assert.Equal(t, pending.ID, entry.Lines[0].AccountID)
+	assert.Equal(t, idr("2000"), entry.Lines[0].Debit)
+	assert.Equal(t, escrow.ID, entry.Lines[1].AccountID)
+	assert.Equal(t, idr("2000"), entry.Lines[1].Credit)
+}
@@ -0,0 +1,464 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// PortfolioPerformanceService measures investment performance from dated cash
+// flows: investments, distributions, refunds, exits and stake trades
+type PortfolioPerformanceService interface {
+	// Each report covers [start, end); a nil start measures from the first
+	// investment and a nil end up to now
+	GetInvestorPerformance(ctx context.Context, investorID uuid.UUID, start, end *time.Time) (*entities.PerformanceReport, error)
+	GetProjectPerformance(ctx context.Context, projectID uuid.UUID, start, end *time.Time) (*entities.PerformanceReport, error)
+	GetCooperativePerformance(ctx context.Context, cooperativeID uuid.UUID, start, end *time.Time) (*entities.PerformanceReport, error)
+}
+
+type portfolioPerformanceService struct {
+	performanceRepo repositories.PortfolioPerformanceRepository
+	currencyService CurrencyService
+}
+
+// NewPortfolioPerformanceService creates a new portfolio performance service
+func NewPortfolioPerformanceService(performanceRepo repositories.PortfolioPerformanceRepository, currencyService CurrencyService) PortfolioPerformanceService {
+	return &portfolioPerformanceService{
+		performanceRepo: performanceRepo,
+		currencyService: currencyService,
+	}
+}
+
+func (s *portfolioPerformanceService) GetInvestorPerformance(ctx context.Context, investorID uuid.UUID, start, end *time.Time) (*entities.PerformanceReport, error) {
+	return s.getReport(ctx, entities.PerformanceScopeInvestor, investorID, &entities.PerformanceFilter{
+		InvestorID: &investorID,
+		Start:      start,
+		End:        end,
+	})
+}
+
+func (s *portfolioPerformanceService) GetProjectPerformance(ctx context.Context, projectID uuid.UUID, start, end *time.Time) (*entities.PerformanceReport, error) {
+	return s.getReport(ctx, entities.PerformanceScopeProject, projectID, &entities.PerformanceFilter{
+		ProjectID: &projectID,
+		Start:     start,
+		End:       end,
+	})
+}
+
+func (s *portfolioPerformanceService) GetCooperativePerformance(ctx context.Context, cooperativeID uuid.UUID, start, end *time.Time) (*entities.PerformanceReport, error) {
+	return s.getReport(ctx, entities.PerformanceScopeCooperative, cooperativeID, &entities.PerformanceFilter{
+		CooperativeID: &cooperativeID,
+		Start:         start,
+		End:           end,
+	})
+}
+
+func (s *portfolioPerformanceService) getReport(ctx context.Context, scope string, scopeID uuid.UUID, filter *entities.PerformanceFilter) (*entities.PerformanceReport, error) {
+	end := time.Now()
+	if filter.End != nil {
+		end = *filter.End
+	}
+	if filter.Start != nil && !filter.Start.Before(end) {
+		return nil, errors.New("start date must be before end date")
+	}
+	filter.End = &end
+
+	positions, err := s.performanceRepo.ListPositions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.performanceRepo.ListEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	distributions, err := s.performanceRepo.ListDistributions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	baseCurrency := s.currencyService.BaseCurrency()
+	byInvestment := make(map[uuid.UUID]*entities.PerformancePosition, len(positions))
+	byHolding := make(map[[2]uuid.UUID]*entities.PerformancePosition, len(positions))
+	for _, position := range positions {
+		if position.Currency == "" {
+			position.Currency = baseCurrency
+		}
+		byInvestment[position.InvestmentID] = position
+		byHolding[[2]uuid.UUID{position.ProjectID, position.InvestorID}] = position
+	}
+
+	// An investor holds one investment per project, which their share of each
+	// distribution is credited for
+	for _, distribution := range distributions {
+		position, ok := byHolding[[2]uuid.UUID{distribution.ProjectID, distribution.InvestorID}]
+		if !ok {
+			continue
+		}
+		distribution.InvestmentID = position.InvestmentID
+		events = append(events, distribution)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].OccurredAt.Before(events[j].OccurredAt) })
+
+	eventsByInvestment := make(map[uuid.UUID][]*entities.PerformanceEvent)
+	for _, event := range events {
+		position, ok := byInvestment[event.InvestmentID]
+		if !ok {
+			continue
+		}
+		event.Cash = event.Cash.WithCurrency(position.Currency)
+		event.PrincipalChange = event.PrincipalChange.WithCurrency(position.Currency)
+		event.LossChange = event.LossChange.WithCurrency(position.Currency)
+		eventsByInvestment[event.InvestmentID] = append(eventsByInvestment[event.InvestmentID], event)
+	}
+
+	report := &entities.PerformanceReport{
+		Scope:       scope,
+		ScopeID:     scopeID,
+		Start:       filter.Start,
+		End:         end,
+		ByCurrency:  []*entities.CurrencyPerformance{},
+		Investments: []*entities.InvestmentPerformance{},
+	}
+
+	sort.SliceStable(positions, func(i, j int) bool { return positions[i].InvestedAt.Before(positions[j].InvestedAt) })
+	var currencies []string
+	groups := make(map[string]*performanceGroup)
+	for _, position := range positions {
+		investmentEvents := eventsByInvestment[position.InvestmentID]
+		if !activeInPeriod(investmentEvents, filter.Start) {
+			continue
+		}
+
+		investment := &entities.InvestmentPerformance{
+			PerformancePosition: *position,
+			PerformanceResult:   measurePerformance(investmentEvents, filter.Start, end, position.Currency),
+		}
+		investment.ExpectedAnnualReturn = position.AnnualizedExpectedReturn()
+		investment.ReturnVsExpected = returnVsExpected(investment.XIRR, investment.ExpectedAnnualReturn)
+		report.Investments = append(report.Investments, investment)
+
+		group, ok := groups[position.Currency]
+		if !ok {
+			group = &performanceGroup{}
+			groups[position.Currency] = group
+			currencies = append(currencies, position.Currency)
+		}
+		group.add(investmentEvents, investment.ExpectedAnnualReturn)
+	}
+
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		report.ByCurrency = append(report.ByCurrency, groups[currency].summarize(currency, filter.Start, end))
+	}
+
+	switch {
+	case len(currencies) == 0:
+		report.Total = (&performanceGroup{}).summarize(baseCurrency, filter.Start, end)
+	case len(currencies) == 1 && currencies[0] == baseCurrency:
+		total := *report.ByCurrency[0]
+		report.Total = &total
+	default:
+		group := &performanceGroup{}
+		for _, investment := range report.Investments {
+			converted, err := s.convertToBase(ctx, eventsByInvestment[investment.InvestmentID])
+			if err != nil {
+				return nil, err
+			}
+			group.add(converted, investment.ExpectedAnnualReturn)
+		}
+		report.Total = group.summarize(baseCurrency, filter.Start, end)
+	}
+
+	return report, nil
+}
+
+// convertToBase converts each event's amounts at the rate on its date, so
+// positions are carried at their historical base currency cost
+func (s *portfolioPerformanceService) convertToBase(ctx context.Context, events []*entities.PerformanceEvent) ([]*entities.PerformanceEvent, error) {
+	baseCurrency := s.currencyService.BaseCurrency()
+	converted := make([]*entities.PerformanceEvent, 0, len(events))
+	for _, event := range events {
+		baseEvent := *event
+		for _, amount := range []*entities.Money{&baseEvent.Cash, &baseEvent.PrincipalChange, &baseEvent.LossChange} {
+			if amount.IsZero() {
+				*amount = entities.ZeroMoney(baseCurrency)
+				continue
+			}
+			result, err := s.currencyService.ConvertToBase(ctx, *amount, event.OccurredAt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert %s flows: %w", amount.Currency(), err)
+			}
+			*amount = result.Converted
+		}
+		converted = append(converted, &baseEvent)
+	}
+	return converted, nil
+}
+
+// performanceGroup collects the events of investments held in one currency
+type performanceGroup struct {
+	investments     [][]*entities.PerformanceEvent
+	expectedReturns []*float64
+}
+
+func (g *performanceGroup) add(events []*entities.PerformanceEvent, expectedReturn *float64) {
+	g.investments = append(g.investments, events)
+	g.expectedReturns = append(g.expectedReturns, expectedReturn)
+}
+
+// summarize totals the group's investments. Amounts add up investment by
+// investment, while XIRR and TWR treat the group as one portfolio. The expected
+// return is weighted by the capital each investment put to work in the period.
+func (g *performanceGroup) summarize(currency string, start *time.Time, end time.Time) *entities.CurrencyPerformance {
+	zero := entities.ZeroMoney(currency)
+	summary := &entities.CurrencyPerformance{
+		Currency: currency,
+		PerformanceResult: entities.PerformanceResult{
+			OpeningValue: zero, Invested: zero, Distributions: zero, CapitalReturned: zero, CurrentValue: zero,
+			RealizedResult: zero, UnrealizedResult: zero, TotalResult: zero,
+		},
+		Investments: len(g.investments),
+	}
+
+	var combined []*entities.PerformanceEvent
+	var weighted, totalWeight float64
+	for i, events := range g.investments {
+		result := measurePerformance(events, start, end, currency)
+		summary.OpeningValue = summary.OpeningValue.Add(result.OpeningValue)
+		summary.Invested = summary.Invested.Add(result.Invested)
+		summary.Distributions = summary.Distributions.Add(result.Distributions)
+		summary.CapitalReturned = summary.CapitalReturned.Add(result.CapitalReturned)
+		summary.CurrentValue = summary.CurrentValue.Add(result.CurrentValue)
+		summary.RealizedResult = summary.RealizedResult.Add(result.RealizedResult)
+		summary.UnrealizedResult = summary.UnrealizedResult.Add(result.UnrealizedResult)
+		summary.TotalResult = summary.TotalResult.Add(result.TotalResult)
+		if result.CurrentValue.IsPositive() {
+			summary.OpenInvestments++
+		}
+
+		if expected := g.expectedReturns[i]; expected != nil {
+			weight := result.OpeningValue.Add(result.Invested).Float64()
+			weighted += *expected * weight
+			totalWeight += weight
+		}
+		combined = append(combined, events...)
+	}
+
+	sort.SliceStable(combined, func(i, j int) bool { return combined[i].OccurredAt.Before(combined[j].OccurredAt) })
+	portfolio := measurePerformance(combined, start, end, currency)
+	summary.XIRR, summary.TWR = portfolio.XIRR, portfolio.TWR
+	if totalWeight > 0 {
+		expected := weighted / totalWeight
+		summary.ExpectedAnnualReturn = &expected
+	}
+	summary.ReturnVsExpected = returnVsExpected(summary.XIRR, summary.ExpectedAnnualReturn)
+
+	return summary
+}
+
+// measurePerformance replays an investment's events, ordered by date, over
+// [start, end). Positions are carried at their outstanding capital less
+// unrecovered losses; capital leaving a position realizes the difference from
+// its share of the cost, and what is still held is unrealized against it.
+func measurePerformance(events []*entities.PerformanceEvent, start *time.Time, end time.Time, currency string) entities.PerformanceResult {
+	zero := entities.ZeroMoney(currency)
+	result := entities.PerformanceResult{
+		OpeningValue: zero, Invested: zero, Distributions: zero, CapitalReturned: zero, CurrentValue: zero,
+		RealizedResult: zero, UnrealizedResult: zero, TotalResult: zero,
+	}
+
+	principal, loss, cost := zero, zero, zero
+	carryingValue := func() entities.Money {
+		return principal.Sub(loss).Max(zero)
+	}
+
+	var flows []cashFlow
+	i := 0
+	if start != nil {
+		for ; i < len(events) && events[i].OccurredAt.Before(*start); i++ {
+			principal = principal.Add(events[i].PrincipalChange)
+			loss = loss.Add(events[i].LossChange)
+		}
+		result.OpeningValue = carryingValue()
+		cost = result.OpeningValue
+		if result.OpeningValue.IsPositive() {
+			flows = append(flows, cashFlow{amount: -result.OpeningValue.Float64(), at: *start})
+		}
+	}
+
+	// Time-weighted return chains each interval's growth between events, so
+	// money paid in or out does not count as return
+	growth, measured := 1.0, false
+	previousValue := result.OpeningValue
+	for ; i < len(events); i++ {
+		event := events[i]
+		principalBefore := principal
+		principal = principal.Add(event.PrincipalChange)
+		loss = loss.Add(event.LossChange)
+
+		switch {
+		case event.Cash.IsNegative():
+			result.Invested = result.Invested.Sub(event.Cash)
+		case event.EventType == entities.PerformanceEventDistribution:
+			result.Distributions = result.Distributions.Add(event.Cash)
+			result.RealizedResult = result.RealizedResult.Add(event.Cash)
+		case event.Cash.IsPositive():
+			result.CapitalReturned = result.CapitalReturned.Add(event.Cash)
+		}
+
+		switch {
+		case event.PrincipalChange.IsPositive():
+			cost = cost.Sub(event.Cash)
+		case event.PrincipalChange.IsNegative():
+			exitedCost := cost
+			if exited := event.PrincipalChange.Neg(); principalBefore.GreaterThan(exited) {
+				exitedCost = cost.MulRat(exited.Ratio(principalBefore), entities.RoundHalfUp)
+			}
+			cost = cost.Sub(exitedCost)
+			result.RealizedResult = result.RealizedResult.Add(event.Cash.Sub(exitedCost))
+		}
+
+		if !event.Cash.IsZero() {
+			flows = append(flows, cashFlow{amount: event.Cash.Float64(), at: event.OccurredAt})
+		}
+
+		value := carryingValue()
+		if previousValue.IsPositive() {
+			growth *= value.Add(event.Cash).Float64() / previousValue.Float64()
+			measured = true
+		}
+		previousValue = value
+	}
+
+	result.CurrentValue = carryingValue()
+	result.UnrealizedResult = result.CurrentValue.Sub(cost)
+	result.TotalResult = result.RealizedResult.Add(result.UnrealizedResult)
+	if result.CurrentValue.IsPositive() {
+		flows = append(flows, cashFlow{amount: result.CurrentValue.Float64(), at: end})
+	}
+
+	result.XIRR = xirr(flows)
+	if measured {
+		twr := (growth - 1) * 100
+		result.TWR = &twr
+	}
+
+	return result
+}
+
+// activeInPeriod reports whether an investment was held at the start of the
+// period or changed during it
+func activeInPeriod(events []*entities.PerformanceEvent, start *time.Time) bool {
+	if len(events) == 0 {
+		return false
+	}
+	if start == nil || !events[len(events)-1].OccurredAt.Before(*start) {
+		return true
+	}
+
+	var principal, loss entities.Money
+	for _, event := range events {
+		principal = principal.Add(event.PrincipalChange)
+		loss = loss.Add(event.LossChange)
+	}
+	return principal.Sub(loss).IsPositive()
+}
+
+func returnVsExpected(actual, expected *float64) *float64 {
+	if actual == nil || expected == nil {
+		return nil
+	}
+	difference := *actual - *expected
+	return &difference
+}
+
+// cashFlow is a dated amount received (positive) or paid (negative)
+type cashFlow struct {
+	amount float64
+	at     time.Time
+}
+
+// xirr returns the annual rate, as a percentage, at which the flows' net
+// present value is zero, or nil without both money paid in and received
+func xirr(flows []cashFlow) *float64 {
+	var paid, received bool
+	for _, flow := range flows {
+		paid = paid || flow.amount < 0
+		received = received || flow.amount > 0
+	}
+	if !paid || !received {
+		return nil
+	}
+
+	first := flows[0].at
+	for _, flow := range flows {
+		if flow.at.Before(first) {
+			first = flow.at
+		}
+	}
+	years := make([]float64, len(flows))
+	for i, flow := range flows {
+		years[i] = flow.at.Sub(first).Hours() / 24 / 365
+	}
+	npv := func(rate float64) (float64, float64) {
+		var value, derivative float64
+		for i, flow := range flows {
+			discount := math.Pow(1+rate, years[i])
+			value += flow.amount / discount
+			derivative -= years[i] * flow.amount / (discount * (1 + rate))
+		}
+		return value, derivative
+	}
+
+	// Newton's method converges quickly from a plausible guess; bisection
+	// catches the flows it does not
+	rate := 0.1
+	for iteration := 0; iteration < 100; iteration++ {
+		value, derivative := npv(rate)
+		if math.Abs(value) < 1e-7 {
+			percentage := rate * 100
+			return &percentage
+		}
+		if derivative == 0 {
+			break
+		}
+		next := rate - value/derivative
+		if math.IsNaN(next) || math.IsInf(next, 0) || next <= -1 {
+			break
+		}
+		rate = next
+	}
+
+	low, high := -0.999999, 1.0
+	lowValue, _ := npv(low)
+	highValue, _ := npv(high)
+	for lowValue*highValue > 0 && high < 1e6 {
+		high *= 2
+		highValue, _ = npv(high)
+	}
+	if lowValue*highValue > 0 {
+		return nil
+	}
+	for iteration := 0; iteration < 200; iteration++ {
+		rate = (low + high) / 2
+		value, _ := npv(rate)
+		if math.Abs(value) < 1e-7 || high-low < 1e-12 {
+			break
+		}
+		if (value > 0) == (lowValue > 0) {
+			low, lowValue = rate, value
+		} else {
+			high = rate
+		}
+	}
+	percentage := rate * 100
+	return &percentage
+}
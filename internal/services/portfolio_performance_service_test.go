@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXIRR(t *testing.T) {
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tenPercent, halfLost := 10.0, -29.289
+
+	testCases := []struct {
+		name     string
+		flows    []cashFlow
+		expected *float64
+	}{
+		{"One year at ten percent", []cashFlow{{amount: -1000, at: start}, {amount: 1100, at: start.AddDate(0, 0, 365)}}, &tenPercent},
+		// Losing half over two years is about -29.3% a year
+		{"Half lost over two years", []cashFlow{{amount: -1000, at: start}, {amount: 500, at: start.AddDate(0, 0, 730)}}, &halfLost},
+		{"No return yet", []cashFlow{{amount: -1000, at: start}}, nil},
+		{"No cash flows", nil, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rate := xirr(tc.flows)
+			if tc.expected == nil {
+				assert.Nil(t, rate)
+				return
+			}
+			require.NotNil(t, rate)
+			assert.InDelta(t, *tc.expected, *rate, 0.001)
+		})
+	}
+}
+
+func TestMeasurePerformance_RealizedAndUnrealized(t *testing.T) {
+	invested := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	events := []*entities.PerformanceEvent{
+		{EventType: entities.PerformanceEventInvestment, OccurredAt: invested, Cash: idr("-1000"), PrincipalChange: idr("1000"), LossChange: idr("0")},
+		{EventType: entities.PerformanceEventLoss, OccurredAt: invested.AddDate(0, 3, 0), Cash: idr("0"), PrincipalChange: idr("0"), LossChange: idr("100")},
+		// Half the position exits, taking its share of the loss with it
+		{EventType: entities.PerformanceEventExit, OccurredAt: invested.AddDate(0, 6, 0), Cash: idr("450"), PrincipalChange: idr("-500"), LossChange: idr("-50")},
+		{EventType: entities.PerformanceEventDistribution, OccurredAt: invested.AddDate(0, 9, 0), Cash: idr("60"), PrincipalChange: idr("0"), LossChange: idr("0")},
+	}
+
+	result := measurePerformance(events, nil, invested.AddDate(1, 0, 0), "IDR")
+
+	assert.Equal(t, idr("0"), result.OpeningValue)
+	assert.Equal(t, idr("1000"), result.Invested)
+	assert.Equal(t, idr("450"), result.CapitalReturned)
+	assert.Equal(t, idr("60"), result.Distributions)
+	assert.Equal(t, idr("450"), result.CurrentValue)
+	// The exit lost 50 against its cost and the distribution earned 60
+	assert.Equal(t, idr("10"), result.RealizedResult)
+	assert.Equal(t, idr("-50"), result.UnrealizedResult)
+	assert.Equal(t, idr("-40"), result.TotalResult)
+	// 0.9 over the loss, 1 over the exit and 510/450 over the distribution
+	require.NotNil(t, result.TWR)
+	assert.InDelta(t, 2, *result.TWR, 0.0001)
+	require.NotNil(t, result.XIRR)
+	assert.Less(t, *result.XIRR, 0.0)
+}
+
+func TestMeasurePerformance_OpensAtCarryingValue(t *testing.T) {
+	invested := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	events := []*entities.PerformanceEvent{
+		{EventType: entities.PerformanceEventInvestment, OccurredAt: invested, Cash: idr("-1000"), PrincipalChange: idr("1000"), LossChange: idr("0")},
+		{EventType: entities.PerformanceEventDistribution, OccurredAt: invested.AddDate(0, 6, 0), Cash: idr("100"), PrincipalChange: idr("0"), LossChange: idr("0")},
+		{EventType: entities.PerformanceEventDistribution, OccurredAt: start.AddDate(0, 6, 0), Cash: idr("100"), PrincipalChange: idr("0"), LossChange: idr("0")},
+	}
+
+	result := measurePerformance(events, &start, start.AddDate(1, 0, 0), "IDR")
+
+	assert.Equal(t, idr("1000"), result.OpeningValue)
+	assert.Equal(t, idr("0"), result.Invested)
+	// Only the distribution in the period counts
+	assert.Equal(t, idr("100"), result.Distributions)
+	assert.Equal(t, idr("100"), result.TotalResult)
+	require.NotNil(t, result.TWR)
+	assert.InDelta(t, 10, *result.TWR, 0.0001)
+	require.NotNil(t, result.XIRR)
+	assert.InDelta(t, 10.52, *result.XIRR, 0.01)
+}
+
+func TestPortfolioPerformanceService_GetInvestorPerformance(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	performanceRepo := new(MockPortfolioPerformanceRepository)
+	service := NewPortfolioPerformanceService(performanceRepo, currencyService)
+	ctx := context.Background()
+
+	investorID := uuid.New()
+	invested := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := invested.AddDate(0, 0, 365)
+	expectedReturn := 12.0
+	position := &entities.PerformancePosition{
+		InvestmentID:   uuid.New(),
+		InvestorID:     investorID,
+		ProjectID:      uuid.New(),
+		Status:         "confirmed",
+		InvestedAt:     invested,
+		ExpectedReturn: &expectedReturn,
+	}
+
+	investment := &entities.PerformanceEvent{
+		InvestmentID:    position.InvestmentID,
+		EventType:       entities.PerformanceEventInvestment,
+		OccurredAt:      invested,
+		Cash:            idr("-1000"),
+		PrincipalChange: idr("1000"),
+		LossChange:      idr("0"),
+	}
+	// Distributions are credited per project and investor
+	distribution := &entities.PerformanceEvent{
+		InvestorID:      investorID,
+		ProjectID:       position.ProjectID,
+		EventType:       entities.PerformanceEventDistribution,
+		OccurredAt:      end.AddDate(0, 0, -1),
+		Cash:            idr("150"),
+		PrincipalChange: idr("0"),
+		LossChange:      idr("0"),
+	}
+	otherProject := &entities.PerformanceEvent{
+		InvestorID:      investorID,
+		ProjectID:       uuid.New(),
+		EventType:       entities.PerformanceEventDistribution,
+		OccurredAt:      end.AddDate(0, 0, -1),
+		Cash:            idr("999"),
+		PrincipalChange: idr("0"),
+		LossChange:      idr("0"),
+	}
+
+	performanceRepo.On("ListPositions", ctx, mock.Anything).Return([]*entities.PerformancePosition{position}, nil)
+	performanceRepo.On("ListEvents", ctx, mock.Anything).Return([]*entities.PerformanceEvent{investment}, nil)
+	performanceRepo.On("ListDistributions", ctx, mock.Anything).Return([]*entities.PerformanceEvent{distribution, otherProject}, nil)
+
+	report, err := service.GetInvestorPerformance(ctx, investorID, nil, &end)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.PerformanceScopeInvestor, report.Scope)
+	require.Len(t, report.Investments, 1)
+	result := report.Investments[0]
+	assert.Equal(t, "IDR", result.Currency)
+	assert.Equal(t, idr("150"), result.Distributions)
+	assert.Equal(t, idr("1000"), result.CurrentValue)
+	require.NotNil(t, result.XIRR)
+	assert.InDelta(t, 15, *result.XIRR, 0.1)
+	require.NotNil(t, result.ReturnVsExpected)
+	assert.InDelta(t, 3, *result.ReturnVsExpected, 0.1)
+
+	require.Len(t, report.ByCurrency, 1)
+	assert.Equal(t, 1, report.ByCurrency[0].Investments)
+	assert.Equal(t, 1, report.ByCurrency[0].OpenInvestments)
+	assert.InDelta(t, 12, *report.ByCurrency[0].ExpectedAnnualReturn, 0.0001)
+	assert.Equal(t, report.ByCurrency[0], report.Total)
+
+	_, err = service.GetInvestorPerformance(ctx, investorID, &end, &invested)
+	assert.Error(t, err)
+}
+
+func TestPerformancePosition_AnnualizedExpectedReturn(t *testing.T) {
+	expectedReturn := 21.0
+	position := &entities.PerformancePosition{ExpectedReturn: &expectedReturn, ExpectedReturnPeriod: 24}
+
+	// 21% over two years compounds from 10% a year
+	assert.InDelta(t, 10, *position.AnnualizedExpectedReturn(), 0.0001)
+
+	position.ExpectedReturn = nil
+	assert.Nil(t, position.AnnualizedExpectedReturn())
+}
@@ -0,0 +1,456 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ProfitProjectionService projects a project's profit sharing under uncertain
+// revenue and expenses (FR-035)
+type ProfitProjectionService interface {
+	// SimulateProjection runs a Monte Carlo simulation of the project's
+	// distribution periods under its contract and loss handling rules
+	SimulateProjection(ctx context.Context, projectID uuid.UUID, req *entities.ProfitProjectionRequest) (*entities.ProfitProjectionSimulation, error)
+}
+
+type profitProjectionService struct {
+	contractService ProjectContractService
+	fundingRepo     repositories.ProjectFundingRepository
+	lossService     LossSharingService
+}
+
+// NewProfitProjectionService creates a new profit projection service. Without a
+// contract service projects are projected as mudarabah under the default
+// ratio, and without a loss service no losses are carried forward. Without a
+// funding repository the request must give the investors' capital.
+func NewProfitProjectionService(contractService ProjectContractService, fundingRepo repositories.ProjectFundingRepository, lossService LossSharingService) ProfitProjectionService {
+	return &profitProjectionService{
+		contractService: contractService,
+		fundingRepo:     fundingRepo,
+		lossService:     lossService,
+	}
+}
+
+const (
+	defaultProjectionSimulations  = 10000
+	defaultProjectionPeriodMonths = 3
+)
+
+func (s *profitProjectionService) SimulateProjection(ctx context.Context, projectID uuid.UUID, req *entities.ProfitProjectionRequest) (*entities.ProfitProjectionSimulation, error) {
+	if req.Periods < 1 {
+		return nil, errors.New("projection needs at least one distribution period")
+	}
+
+	contract := defaultProjectContract(projectID)
+	if s.contractService != nil {
+		var err error
+		contract, err = s.contractService.GetContract(ctx, projectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project contract: %w", err)
+		}
+	}
+	if contract.ContractType == entities.ContractTypeMurabahah {
+		return nil, errors.New("murabahah projects return their markup through installments, not profit sharing")
+	}
+
+	currency := contract.Currency
+	capital := req.InvestorCapital
+	if s.fundingRepo != nil {
+		project, err := s.fundingRepo.GetFundingProject(ctx, projectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project: %w", err)
+		}
+		if currency == "" {
+			currency = project.Currency
+		}
+		if !capital.IsPositive() {
+			capital = project.CurrentFunding
+		}
+	}
+	if currency == "" {
+		currency = req.Revenue.Base.Currency()
+	}
+	capital, err := capital.InCurrency(currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid investor capital: %w", err)
+	}
+	if !capital.IsPositive() {
+		return nil, errors.New("project has no investor capital to project returns on")
+	}
+
+	revenue, err := normalizeProjectionAssumption("revenue", req.Revenue, currency, true)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := normalizeProjectionAssumption("expenses", req.Expenses, currency, false)
+	if err != nil {
+		return nil, err
+	}
+
+	method := req.LossHandlingMethod
+	carriedForward := entities.ZeroMoney(currency)
+	if s.lossService != nil {
+		history, err := s.lossService.GetProjectLossHistory(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		carriedForward = carriedForward.Add(history.CarriedForward)
+		if method == "" && len(history.Losses) > 0 {
+			method = history.Losses[len(history.Losses)-1].LossHandlingMethod
+		}
+	}
+	if method == "" {
+		method = entities.LossHandlingCarryForward
+	}
+
+	ratio := contract.ProfitSharingRatio
+	if len(ratio) == 0 {
+		ratio = defaultProfitSharingRatio()
+	}
+	// Losses are borne in proportion to capital, so a musyarakah partner bears
+	// their share of any loss
+	investorLossRatio := 100.0
+	if contract.ContractType == entities.ContractTypeMusyarakah {
+		contract.InvestorCapital = capital
+		investorLossRatio = contract.InvestorCapitalRatio()
+	}
+
+	seed := time.Now().UnixNano()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+	simulations := req.Simulations
+	if simulations == 0 {
+		simulations = defaultProjectionSimulations
+	}
+	periodMonths := req.PeriodMonths
+	if periodMonths == 0 {
+		periodMonths = defaultProjectionPeriodMonths
+	}
+	start := time.Now()
+	if req.StartDate != nil {
+		start = *req.StartDate
+	}
+
+	projection := &projectionModel{
+		revenue:           revenue,
+		expenses:          expenses,
+		periods:           req.Periods,
+		method:            method,
+		capital:           capital.MinorUnits(),
+		carriedForward:    carriedForward.MinorUnits(),
+		investorRatio:     ratio["investor"],
+		investorLossRatio: investorLossRatio,
+	}
+	outcomes := projection.simulate(simulations, rand.New(rand.NewSource(seed)))
+
+	simulation := outcomes.summarize(currency, capital, start, periodMonths)
+	simulation.ProjectID = projectID
+	simulation.ContractType = contract.ContractType
+	simulation.LossHandlingMethod = method
+	simulation.InvestorCapital = capital
+	simulation.CarriedForwardLoss = carriedForward
+	simulation.InvestorRatio = ratio["investor"]
+	simulation.Seed = seed
+
+	return simulation, nil
+}
+
+// normalizeProjectionAssumption puts the assumption's amounts in the project's
+// currency and checks they describe its distribution. Worst must be at or
+// below Base and Best at or above it for revenue, the other way round for
+// expenses.
+func normalizeProjectionAssumption(name string, assumption entities.ProjectionAssumption, currency string, higherIsBetter bool) (entities.ProjectionAssumption, error) {
+	for _, amount := range []*entities.Money{&assumption.Base, &assumption.Worst, &assumption.Best, &assumption.StdDev} {
+		converted, err := amount.InCurrency(currency)
+		if err != nil {
+			return assumption, fmt.Errorf("invalid %s amount: %w", name, err)
+		}
+		*amount = converted
+	}
+
+	for _, amount := range []entities.Money{assumption.Base, assumption.Worst, assumption.Best, assumption.StdDev} {
+		if amount.IsNegative() {
+			return assumption, fmt.Errorf("%s amounts cannot be negative", name)
+		}
+	}
+
+	switch assumption.Distribution {
+	case entities.ProjectionDistributionFixed:
+	case entities.ProjectionDistributionScenario, entities.ProjectionDistributionTriangular:
+		low, high := assumption.Worst, assumption.Best
+		if !higherIsBetter {
+			low, high = high, low
+		}
+		if low.GreaterThan(assumption.Base) || assumption.Base.GreaterThan(high) {
+			return assumption, fmt.Errorf("%s base case must lie between its worst and best cases", name)
+		}
+		if assumption.Distribution == entities.ProjectionDistributionScenario {
+			if assumption.WorstProbability == 0 && assumption.BestProbability == 0 {
+				assumption.WorstProbability, assumption.BestProbability = 25, 25
+			}
+			if assumption.WorstProbability+assumption.BestProbability > 100 {
+				return assumption, fmt.Errorf("%s worst and best case probabilities exceed 100%%", name)
+			}
+		}
+	case entities.ProjectionDistributionNormal:
+		if !assumption.StdDev.IsPositive() {
+			return assumption, fmt.Errorf("%s standard deviation must be positive", name)
+		}
+	default:
+		return assumption, fmt.Errorf("unsupported %s distribution: %s", name, assumption.Distribution)
+	}
+
+	return assumption, nil
+}
+
+// drawProjectionAmount draws an assumption's amount in minor units
+func drawProjectionAmount(assumption entities.ProjectionAssumption, rng *rand.Rand) int64 {
+	base := float64(assumption.Base.MinorUnits())
+	worst := float64(assumption.Worst.MinorUnits())
+	best := float64(assumption.Best.MinorUnits())
+
+	var amount float64
+	switch assumption.Distribution {
+	case entities.ProjectionDistributionScenario:
+		amount = base
+		switch draw := rng.Float64() * 100; {
+		case draw < assumption.WorstProbability:
+			amount = worst
+		case draw < assumption.WorstProbability+assumption.BestProbability:
+			amount = best
+		}
+	case entities.ProjectionDistributionTriangular:
+		low, high := math.Min(worst, best), math.Max(worst, best)
+		amount = base
+		if high > low {
+			// Inverse of the triangular distribution's cumulative distribution
+			draw := rng.Float64()
+			if draw < (base-low)/(high-low) {
+				amount = low + math.Sqrt(draw*(high-low)*(base-low))
+			} else {
+				amount = high - math.Sqrt((1-draw)*(high-low)*(high-base))
+			}
+		}
+	case entities.ProjectionDistributionNormal:
+		amount = math.Max(base+rng.NormFloat64()*float64(assumption.StdDev.MinorUnits()), 0)
+	default:
+		amount = base
+	}
+	return int64(math.Round(amount))
+}
+
+// projectionModel is a project's profit sharing in minor units. Each period's
+// net profit first recovers carried-forward losses and the investors receive
+// their ratio of the rest. A net loss is borne by the investors' capital, up to
+// what is left of it, and either carried forward, written off or absorbed by
+// the cooperative.
+type projectionModel struct {
+	revenue           entities.ProjectionAssumption
+	expenses          entities.ProjectionAssumption
+	periods           int
+	method            string
+	capital           int64
+	carriedForward    int64
+	investorRatio     float64 // percentage of distributable profit
+	investorLossRatio float64 // percentage of a loss the investors bear
+}
+
+// projectionOutcomes holds every run's per-period distribution, loss and
+// cumulative return
+type projectionOutcomes struct {
+	distributions [][]int64 // [period][run]
+	losses        [][]int64
+	cumulative    [][]int64
+}
+
+func (m *projectionModel) simulate(simulations int, rng *rand.Rand) *projectionOutcomes {
+	outcomes := &projectionOutcomes{
+		distributions: make([][]int64, m.periods),
+		losses:        make([][]int64, m.periods),
+		cumulative:    make([][]int64, m.periods),
+	}
+	for period := 0; period < m.periods; period++ {
+		outcomes.distributions[period] = make([]int64, simulations)
+		outcomes.losses[period] = make([]int64, simulations)
+		outcomes.cumulative[period] = make([]int64, simulations)
+	}
+
+	for run := 0; run < simulations; run++ {
+		persistentRevenue := drawProjectionAmount(m.revenue, rng)
+		persistentExpenses := drawProjectionAmount(m.expenses, rng)
+
+		outstanding, writtenOff, distributed := m.carriedForward, int64(0), int64(0)
+		for period := 0; period < m.periods; period++ {
+			revenue, expenses := persistentRevenue, persistentExpenses
+			if !m.revenue.Persistent {
+				revenue = drawProjectionAmount(m.revenue, rng)
+			}
+			if !m.expenses.Persistent {
+				expenses = drawProjectionAmount(m.expenses, rng)
+			}
+
+			var distribution, loss int64
+			if net := revenue - expenses; net >= 0 {
+				if m.method == entities.LossHandlingCarryForward {
+					offset := minInt64(outstanding, net)
+					outstanding -= offset
+					net -= offset
+				}
+				distribution = int64(math.Round(float64(net) * m.investorRatio / 100))
+			} else if m.method != entities.LossHandlingAbsorb {
+				loss = int64(math.Round(float64(-net) * m.investorLossRatio / 100))
+				loss = minInt64(loss, maxInt64(m.capital-outstanding-writtenOff, 0))
+				if m.method == entities.LossHandlingCarryForward {
+					outstanding += loss
+				} else {
+					writtenOff += loss
+				}
+			}
+
+			distributed += distribution
+			outcomes.distributions[period][run] = distribution
+			outcomes.losses[period][run] = loss
+			outcomes.cumulative[period][run] = distributed - (outstanding - m.carriedForward) - writtenOff
+		}
+	}
+
+	return outcomes
+}
+
+// summarize reports the outcomes' percentiles per distribution period and
+// over the whole projection
+func (o *projectionOutcomes) summarize(currency string, capital entities.Money, start time.Time, periodMonths int) *entities.ProfitProjectionSimulation {
+	simulation := &entities.ProfitProjectionSimulation{
+		Currency:     currency,
+		Periods:      make([]entities.ProjectedDistributionPeriod, len(o.distributions)),
+		CalculatedAt: time.Now(),
+	}
+
+	total := entities.ZeroMoney(currency)
+	for period := range o.distributions {
+		simulation.Simulations = len(o.distributions[period])
+		projected := entities.ProjectedDistributionPeriod{
+			DistributionPeriod: entities.DistributionPeriod{
+				Period: projectionPeriodName(period, periodMonths),
+				Date:   start.AddDate(0, periodMonths*(period+1), 0),
+				Status: entities.DistributionPeriodStatusProjected,
+			},
+			Distribution:              projectionPercentiles(o.distributions[period], currency),
+			CumulativeReturn:          projectionPercentiles(o.cumulative[period], currency),
+			LossProbability:           projectionProbability(o.losses[period], func(loss int64) bool { return loss > 0 }),
+			CumulativeLossProbability: projectionProbability(o.cumulative[period], func(result int64) bool { return result < 0 }),
+		}
+		projected.Amount = projected.Distribution.Mean
+		total = total.Add(projected.Amount)
+		simulation.Periods[period] = projected
+	}
+
+	for i := range simulation.Periods {
+		if total.IsPositive() {
+			percentage, _ := simulation.Periods[i].Amount.Ratio(total).Float64()
+			simulation.Periods[i].Percentage = percentage * 100
+		}
+	}
+
+	if len(o.cumulative) > 0 {
+		final := o.cumulative[len(o.cumulative)-1]
+		simulation.TotalReturn = projectionPercentiles(final, currency)
+		simulation.LossProbability = projectionProbability(final, func(result int64) bool { return result < 0 })
+
+		simulation.ReturnRate = make(map[string]float64)
+		for name, amount := range map[string]entities.Money{
+			"p5": simulation.TotalReturn.P5, "p25": simulation.TotalReturn.P25, "p50": simulation.TotalReturn.P50,
+			"p75": simulation.TotalReturn.P75, "p95": simulation.TotalReturn.P95, "mean": simulation.TotalReturn.Mean,
+		} {
+			rate, _ := amount.Ratio(capital).Float64()
+			simulation.ReturnRate[name] = rate * 100
+		}
+	}
+
+	return simulation
+}
+
+func projectionPeriodName(period, periodMonths int) string {
+	switch periodMonths {
+	case 1:
+		return fmt.Sprintf("M%d", period+1)
+	case 6:
+		return fmt.Sprintf("H%d", period+1)
+	case 12:
+		return fmt.Sprintf("Y%d", period+1)
+	default:
+		return fmt.Sprintf("Q%d", period+1)
+	}
+}
+
+// projectionPercentiles returns the nearest-rank percentiles of the runs'
+// amounts, in minor units
+func projectionPercentiles(amounts []int64, currency string) entities.ProjectionPercentiles {
+	sorted := append([]int64(nil), amounts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	percentile := func(p float64) entities.Money {
+		if len(sorted) == 0 {
+			return entities.ZeroMoney(currency)
+		}
+		rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		return entities.NewMoney(sorted[rank], currency)
+	}
+
+	var sum float64
+	for _, amount := range sorted {
+		sum += float64(amount)
+	}
+	mean := entities.ZeroMoney(currency)
+	if len(sorted) > 0 {
+		mean = entities.NewMoney(int64(math.Round(sum/float64(len(sorted)))), currency)
+	}
+
+	return entities.ProjectionPercentiles{
+		P5:   percentile(5),
+		P25:  percentile(25),
+		P50:  percentile(50),
+		P75:  percentile(75),
+		P95:  percentile(95),
+		Mean: mean,
+	}
+}
+
+// projectionProbability returns the percentage of runs matching
+func projectionProbability(amounts []int64, matches func(int64) bool) float64 {
+	if len(amounts) == 0 {
+		return 0
+	}
+	count := 0
+	for _, amount := range amounts {
+		if matches(amount) {
+			count++
+		}
+	}
+	return float64(count) / float64(len(amounts)) * 100
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
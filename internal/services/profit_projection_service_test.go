@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func fixedProjection(amount string) entities.ProjectionAssumption {
+	return entities.ProjectionAssumption{Distribution: entities.ProjectionDistributionFixed, Base: idr(amount)}
+}
+
+func TestProfitProjectionService_SimulateProjection_Fixed(t *testing.T) {
+	service := NewProfitProjectionService(nil, nil, nil)
+	seed := int64(1)
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	simulation, err := service.SimulateProjection(context.Background(), uuid.New(), &entities.ProfitProjectionRequest{
+		Revenue:         fixedProjection("1000"),
+		Expenses:        fixedProjection("600"),
+		Periods:         4,
+		StartDate:       &start,
+		InvestorCapital: idr("10000"),
+		Simulations:     100,
+		Seed:            &seed,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.ContractTypeMudarabah, simulation.ContractType)
+	assert.Equal(t, entities.LossHandlingCarryForward, simulation.LossHandlingMethod)
+	assert.Equal(t, 100, simulation.Simulations)
+	require.Len(t, simulation.Periods, 4)
+	// The default ratio gives investors 70% of each period's 400 profit
+	first := simulation.Periods[0]
+	assert.Equal(t, "Q1", first.Period)
+	assert.Equal(t, time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC), first.Date)
+	assert.Equal(t, idr("280"), first.Amount)
+	assert.Equal(t, idr("280"), first.Distribution.P5)
+	assert.Equal(t, idr("280"), first.Distribution.P95)
+	assert.InDelta(t, 25, first.Percentage, 0.0001)
+	assert.Equal(t, idr("1120"), simulation.TotalReturn.P50)
+	assert.InDelta(t, 11.2, simulation.ReturnRate["p50"], 0.0001)
+	assert.Zero(t, simulation.LossProbability)
+}
+
+func TestProjectionModel_LossHandling(t *testing.T) {
+	testCases := []struct {
+		name              string
+		method            string
+		investorLossRatio float64
+		capital           int64
+		cumulative        []int64
+	}{
+		{"Carried forward", entities.LossHandlingCarryForward, 100, 1000000, []int64{-30000, -60000}},
+		{"Shared", entities.LossHandlingShared, 100, 1000000, []int64{-30000, -60000}},
+		{"Absorbed by the cooperative", entities.LossHandlingAbsorb, 100, 1000000, []int64{0, 0}},
+		{"Musyarakah partner bears half", entities.LossHandlingCarryForward, 50, 1000000, []int64{-15000, -30000}},
+		{"Limited to the investors' capital", entities.LossHandlingShared, 100, 50000, []int64{-30000, -50000}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			model := &projectionModel{
+				revenue:           fixedProjection("500"),
+				expenses:          fixedProjection("800"),
+				periods:           2,
+				method:            tc.method,
+				capital:           tc.capital,
+				investorRatio:     70,
+				investorLossRatio: tc.investorLossRatio,
+			}
+
+			outcomes := model.simulate(1, nil)
+
+			assert.Equal(t, tc.cumulative, []int64{outcomes.cumulative[0][0], outcomes.cumulative[1][0]})
+			assert.Zero(t, outcomes.distributions[0][0])
+		})
+	}
+}
+
+func TestProjectionModel_RecoversCarriedForwardLossFirst(t *testing.T) {
+	model := &projectionModel{
+		revenue:           fixedProjection("1000"),
+		expenses:          fixedProjection("600"),
+		periods:           2,
+		method:            entities.LossHandlingCarryForward,
+		capital:           1000000,
+		carriedForward:    50000,
+		investorRatio:     70,
+		investorLossRatio: 100,
+	}
+
+	outcomes := model.simulate(1, nil)
+
+	// The first period's profit recovers 400 of the 500 carried forward and
+	// the second recovers the rest before sharing 300
+	assert.Equal(t, []int64{0, 21000}, []int64{outcomes.distributions[0][0], outcomes.distributions[1][0]})
+	assert.Equal(t, []int64{40000, 71000}, []int64{outcomes.cumulative[0][0], outcomes.cumulative[1][0]})
+}
+
+func TestProfitProjectionService_SimulateProjection_ScenarioUnderProjectRules(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	contractRepo := new(MockProjectContractRepository)
+	contractService := NewProjectContractService(contractRepo, mockAuditService)
+	lossRepo := new(MockProjectLossRepository)
+	lossService := NewLossSharingService(lossRepo, mockAuditService)
+	fundingRepo := new(MockProjectFundingRepository)
+	service := NewProfitProjectionService(contractService, fundingRepo, lossService)
+	ctx := context.Background()
+	projectID := uuid.New()
+
+	contractRepo.On("GetContract", ctx, projectID).Return(&entities.ProjectContract{
+		ProjectID:          projectID,
+		ContractType:       entities.ContractTypeMusyarakah,
+		Currency:           "IDR",
+		ProfitSharingRatio: map[string]float64{"investor": 60, "business": 35, "cooperative": 5},
+		PartnerCapital:     idr("10000"),
+	}, nil)
+	fundingRepo.On("GetFundingProject", ctx, projectID).Return(&entities.FundingProject{ProjectID: projectID, Currency: "IDR", CurrentFunding: idr("30000")}, nil)
+	lastLoss := &entities.ProjectLoss{ID: uuid.New(), LossHandlingMethod: entities.LossHandlingShared, LossAmount: idr("100"), RecoveredAmount: idr("0"), Currency: "IDR", Status: entities.ProjectLossStatusWrittenOff}
+	lossRepo.On("ListProjectLosses", ctx, projectID).Return([]*entities.ProjectLoss{lastLoss}, nil)
+	lossRepo.On("ListLossShares", ctx, lastLoss).Return([]*entities.InvestorLossShare{}, nil)
+	lossRepo.On("ListRecoveries", ctx, projectID).Return([]*entities.ProjectLossRecovery{}, nil)
+
+	// "What if revenue comes in 30% below plan?"
+	seed := int64(42)
+	simulation, err := service.SimulateProjection(ctx, projectID, &entities.ProfitProjectionRequest{
+		Revenue: entities.ProjectionAssumption{
+			Distribution:     entities.ProjectionDistributionScenario,
+			Worst:            idr("700"),
+			Base:             idr("1000"),
+			Best:             idr("1200"),
+			WorstProbability: 30,
+			BestProbability:  20,
+		},
+		Expenses:    fixedProjection("800"),
+		Periods:     1,
+		Simulations: 5000,
+		Seed:        &seed,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.LossHandlingShared, simulation.LossHandlingMethod)
+	assert.Equal(t, idr("30000"), simulation.InvestorCapital)
+	period := simulation.Periods[0]
+	assert.InDelta(t, 30, period.LossProbability, 2)
+	assert.InDelta(t, 30, simulation.LossProbability, 2)
+	// Investors bear 75% of the worst case's 100 loss, as they put up 75% of
+	// the capital, and receive 60% of the best case's 400 profit
+	assert.Equal(t, idr("-75"), simulation.TotalReturn.P5)
+	assert.Equal(t, idr("120"), simulation.TotalReturn.P50)
+	assert.Equal(t, idr("240"), period.Distribution.P95)
+}
+
+func TestProfitProjectionService_SimulateProjection_RejectsInvalidAssumptions(t *testing.T) {
+	service := NewProfitProjectionService(nil, nil, nil)
+	ctx := context.Background()
+
+	testCases := []struct {
+		name string
+		req  *entities.ProfitProjectionRequest
+	}{
+		{"No capital", &entities.ProfitProjectionRequest{Revenue: fixedProjection("1000"), Expenses: fixedProjection("600"), Periods: 4}},
+		{"Revenue worst case above base", &entities.ProfitProjectionRequest{
+			Revenue:  entities.ProjectionAssumption{Distribution: entities.ProjectionDistributionTriangular, Worst: idr("1100"), Base: idr("1000"), Best: idr("1200")},
+			Expenses: fixedProjection("600"), Periods: 4, InvestorCapital: idr("10000"),
+		}},
+		{"Expenses worst case below base", &entities.ProfitProjectionRequest{
+			Revenue:  fixedProjection("1000"),
+			Expenses: entities.ProjectionAssumption{Distribution: entities.ProjectionDistributionScenario, Worst: idr("500"), Base: idr("600"), Best: idr("550")},
+			Periods:  4, InvestorCapital: idr("10000"),
+		}},
+		{"Normal without deviation", &entities.ProfitProjectionRequest{
+			Revenue:  entities.ProjectionAssumption{Distribution: entities.ProjectionDistributionNormal, Base: idr("1000")},
+			Expenses: fixedProjection("600"), Periods: 4, InvestorCapital: idr("10000"),
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := service.SimulateProjection(ctx, uuid.New(), tc.req)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDrawProjectionAmount_ParametricDistributions(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	triangular := entities.ProjectionAssumption{Distribution: entities.ProjectionDistributionTriangular, Worst: idr("600"), Base: idr("900"), Best: idr("1200")}
+	normal := entities.ProjectionAssumption{Distribution: entities.ProjectionDistributionNormal, Base: idr("100"), StdDev: idr("100")}
+
+	var triangularSum, normalSum float64
+	const draws = 20000
+	for i := 0; i < draws; i++ {
+		amount := drawProjectionAmount(triangular, rng)
+		assert.True(t, amount >= 60000 && amount <= 120000)
+		triangularSum += float64(amount)
+
+		amount = drawProjectionAmount(normal, rng)
+		assert.GreaterOrEqual(t, amount, int64(0))
+		normalSum += float64(amount)
+	}
+
+	// A triangular distribution averages its three points; a normal one cut
+	// off at zero averages above its mean
+	assert.InDelta(t, 90000, triangularSum/draws, 500)
+	assert.Greater(t, normalSum/draws, 10000.0)
+}
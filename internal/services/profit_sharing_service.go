@@ -19,7 +19,7 @@ type ProfitSharingService interface {
 	GetProfitCalculation(ctx context.Context, calculationID uuid.UUID) (*entities.ProfitCalculation, error)
 	GetProjectProfitCalculations(ctx context.Context, projectID uuid.UUID, page, limit int) ([]*entities.ProfitCalculation, int, error)
 	SearchProfitCalculations(ctx context.Context, filter *entities.ProfitCalculationFilter) ([]*entities.ProfitCalculation, int, error)
-	CalculateShariaCompliantProfit(ctx context.Context, projectID uuid.UUID, revenue, expenses float64) (float64, map[string]float64, error)
+	CalculateShariaCompliantProfit(ctx context.Context, projectID uuid.UUID, revenue, expenses entities.Money) (entities.Money, map[string]float64, error)
 
 	// FR-054 to FR-056: Distribution Process
 	CreateProfitDistribution(ctx context.Context, req *entities.CreateProfitDistributionExtendedRequest, creatorID uuid.UUID) (*entities.ProfitDistributionExtended, error)
@@ -28,6 +28,8 @@ type ProfitSharingService interface {
 	GetProjectProfitDistributions(ctx context.Context, projectID uuid.UUID, page, limit int) ([]*entities.ProfitDistributionExtended, int, error)
 	SearchProfitDistributions(ctx context.Context, filter *entities.ProfitDistributionExtendedFilter) ([]*entities.ProfitDistributionExtended, int, error)
 	CalculateInvestorProfitShares(ctx context.Context, distributionID uuid.UUID) ([]*entities.InvestorProfitShare, error)
+	// SettleInstallment distributes a paid murabahah installment to the project's investors
+	SettleInstallment(ctx context.Context, projectID, installmentID, processorID uuid.UUID) (*entities.ProfitDistributionExtended, error)
 
 	// FR-057: Tax Documentation
 	CreateTaxDocumentation(ctx context.Context, req *entities.CreateTaxDocumentationRequest, creatorID uuid.UUID) (*entities.TaxDocumentation, error)
@@ -61,21 +63,48 @@ type ProfitSharingService interface {
 
 // profitSharingService implements ProfitSharingService
 type profitSharingService struct {
-	auditService AuditService
+	auditService        AuditService
+	ledgerService       LedgerService
+	payoutService       PayoutService
+	lossService         LossSharingService
+	reinvestmentService ReinvestmentService
+	screeningService    ShariaScreeningService
+	contractService     ProjectContractService
+	feeService          FeeService
+	taxService          TaxService
 	// Add repositories when implemented
 }
 
-// NewProfitSharingService creates a new profit sharing service
-func NewProfitSharingService(auditService AuditService) ProfitSharingService {
+// NewProfitSharingService creates a new profit sharing service. Processed profit
+// shares are queued for bank payout when payoutService is set, and project losses
+// are tracked and carried forward when lossService is set. Investors' reinvestment
+// preferences are applied before payout when reinvestmentService is set. Profit
+// calculations are screened under the cooperative's Sharia rules when
+// screeningService is set, and under the default rules otherwise. Profit and
+// loss follow each project's contract when contractService is set; without it
+// every project is a mudarabah under the default ratio. Fee schedules, project
+// fees and their invoices and credit notes are kept by feeService when it is set.
+// Tax is withheld under the cooperative's jurisdiction and documented from the
+// recorded withholdings when taxService is set; otherwise a flat 10% is
+// withheld from profit distributions.
+func NewProfitSharingService(auditService AuditService, ledgerService LedgerService, payoutService PayoutService, lossService LossSharingService, reinvestmentService ReinvestmentService, screeningService ShariaScreeningService, contractService ProjectContractService, feeService FeeService, taxService TaxService) ProfitSharingService {
 	return &profitSharingService{
-		auditService: auditService,
+		auditService:        auditService,
+		ledgerService:       ledgerService,
+		payoutService:       payoutService,
+		lossService:         lossService,
+		reinvestmentService: reinvestmentService,
+		screeningService:    screeningService,
+		contractService:     contractService,
+		feeService:          feeService,
+		taxService:          taxService,
 	}
 }
 
 // CreateProfitCalculation implements FR-050 to FR-053: Sharia-compliant profit calculation
 func (s *profitSharingService) CreateProfitCalculation(ctx context.Context, req *entities.CreateProfitCalculationRequest, creatorID uuid.UUID) (*entities.ProfitCalculation, error) {
 	// Validate profit calculation request
-	if req.TotalRevenue < 0 || req.TotalExpenses < 0 {
+	if req.TotalRevenue.IsNegative() || req.TotalExpenses.IsNegative() {
 		return nil, errors.New("revenue and expenses must be non-negative")
 	}
 
@@ -83,32 +112,67 @@ func (s *profitSharingService) CreateProfitCalculation(ctx context.Context, req
 		return nil, errors.New("start date must be before end date")
 	}
 
+	contract, err := s.projectContract(ctx, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.ContractType == entities.ContractTypeMurabahah {
+		return nil, errors.New("murabahah projects return their markup through installments, not profit sharing")
+	}
+	if len(req.ProfitSharingRatio) == 0 {
+		req.ProfitSharingRatio = contract.ProfitSharingRatio
+	}
+
 	// Calculate net profit/loss
-	netProfit := req.TotalRevenue - req.TotalExpenses
-	totalLoss := 0.0
-	if netProfit < 0 {
-		totalLoss = -netProfit
-		netProfit = 0
+	netProfit := req.TotalRevenue.Sub(req.TotalExpenses)
+	currency := netProfit.Currency()
+	totalLoss := entities.ZeroMoney(currency)
+	partnerLoss := entities.ZeroMoney(currency)
+	if netProfit.IsNegative() {
+		totalLoss = netProfit.Neg()
+		netProfit = entities.ZeroMoney(currency)
+
+		// A musyarakah partner's capital bears its share of the loss
+		if contract.ContractType == entities.ContractTypeMusyarakah {
+			totalLoss, partnerLoss, err = splitMusyarakahLoss(contract, totalLoss)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	lossHandlingMethod := req.LossHandlingMethod
+	if lossHandlingMethod == "" {
+		lossHandlingMethod = entities.LossHandlingCarryForward
 	}
 
+	// Capital lost in earlier periods is restored before any profit is shared
+	lossOffset, err := s.lossOffset(ctx, req.ProjectID, netProfit)
+	if err != nil {
+		return nil, err
+	}
+	distributableProfit := netProfit.Sub(lossOffset)
+
 	// Calculate profit shares based on Sharia-compliant principles
-	investorShare := 0.0
-	businessShare := 0.0
-	cooperativeShare := 0.0
+	investorShare := entities.ZeroMoney(currency)
+	businessShare := entities.ZeroMoney(currency)
+	cooperativeShare := entities.ZeroMoney(currency)
 
-	if netProfit > 0 {
-		// Default ratios: 70% investor, 25% business, 5% cooperative
+	if distributableProfit.IsPositive() {
 		investorRatio := req.ProfitSharingRatio["investor"]
 		businessRatio := req.ProfitSharingRatio["business"]
 		cooperativeRatio := req.ProfitSharingRatio["cooperative"]
 
-		investorShare = (netProfit * investorRatio) / 100
-		businessShare = (netProfit * businessRatio) / 100
-		cooperativeShare = (netProfit * cooperativeRatio) / 100
+		investorShare = distributableProfit.Percent(investorRatio, entities.RoundHalfUp)
+		businessShare = distributableProfit.Percent(businessRatio, entities.RoundHalfUp)
+		cooperativeShare = distributableProfit.Percent(cooperativeRatio, entities.RoundHalfUp)
 	}
 
 	// Check Sharia compliance
-	shariaCompliant := s.checkShariaCompliance(req.TotalRevenue, req.TotalExpenses, req.ProfitSharingRatio)
+	screening, err := s.screenProfitCalculation(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create profit calculation record
 	calculation := &entities.ProfitCalculation{
@@ -116,6 +180,7 @@ func (s *profitSharingService) CreateProfitCalculation(ctx context.Context, req
 		ProjectID:          req.ProjectID,
 		BusinessID:         uuid.Nil, // Will be set based on project's business
 		CooperativeID:      uuid.Nil, // Will be set based on project's cooperative
+		ContractType:       contract.ContractType,
 		CalculationPeriod:  req.CalculationPeriod,
 		StartDate:          req.StartDate,
 		EndDate:            req.EndDate,
@@ -123,11 +188,15 @@ func (s *profitSharingService) CreateProfitCalculation(ctx context.Context, req
 		TotalExpenses:      req.TotalExpenses,
 		NetProfit:          netProfit,
 		TotalLoss:          totalLoss,
+		PartnerLoss:        partnerLoss,
+		LossOffset:         lossOffset,
+		LossHandlingMethod: lossHandlingMethod,
 		ProfitSharingRatio: req.ProfitSharingRatio,
 		InvestorShare:      investorShare,
 		BusinessShare:      businessShare,
 		CooperativeShare:   cooperativeShare,
-		ShariaCompliant:    shariaCompliant,
+		ShariaCompliant:    screening.Compliant,
+		ShariaScreening:    screening,
 		ComplianceNotes:    req.ComplianceNotes,
 		VerificationStatus: entities.ProfitCalculationStatusPending,
 		Documents:          req.Documents,
@@ -142,12 +211,58 @@ func (s *profitSharingService) CreateProfitCalculation(ctx context.Context, req
 		Operation:  "create_profit_calculation",
 		EntityType: "profit_calculation",
 		EntityID:   calculation.ID,
-		NewValues:  fmt.Sprintf("Created profit calculation: revenue=%f, expenses=%f, net_profit=%f", req.TotalRevenue, req.TotalExpenses, netProfit),
+		NewValues:  fmt.Sprintf("Created %s profit calculation: revenue=%s, expenses=%s, net_profit=%s, loss_offset=%s, partner_loss=%s", contract.ContractType, req.TotalRevenue.Decimal(), req.TotalExpenses.Decimal(), netProfit.Decimal(), lossOffset.Decimal(), partnerLoss.Decimal()),
 	})
 
 	return calculation, nil
 }
 
+// projectContract returns the contract a project's profit and loss are shared under
+func (s *profitSharingService) projectContract(ctx context.Context, projectID uuid.UUID) (*entities.ProjectContract, error) {
+	if s.contractService == nil {
+		return defaultProjectContract(projectID), nil
+	}
+
+	contract, err := s.contractService.GetContract(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project contract: %w", err)
+	}
+	return contract, nil
+}
+
+// splitMusyarakahLoss splits a musyarakah loss between the investors and the
+// business partner in proportion to the capital each contributed
+func splitMusyarakahLoss(contract *entities.ProjectContract, loss entities.Money) (investorLoss, partnerLoss entities.Money, err error) {
+	if !contract.PartnerCapital.IsPositive() {
+		return loss, entities.ZeroMoney(loss.Currency()), nil
+	}
+
+	parts, err := entities.AllocateByWeight(loss, []entities.Money{contract.InvestorCapital, contract.PartnerCapital})
+	if err != nil {
+		return entities.Money{}, entities.Money{}, fmt.Errorf("failed to split musyarakah loss: %w", err)
+	}
+	return parts[0], parts[1], nil
+}
+
+// lossOffset returns how much of a period's net profit goes to recovering the
+// project's carried-forward losses
+func (s *profitSharingService) lossOffset(ctx context.Context, projectID uuid.UUID, netProfit entities.Money) (entities.Money, error) {
+	offset := entities.ZeroMoney(netProfit.Currency())
+	if s.lossService == nil || !netProfit.IsPositive() {
+		return offset, nil
+	}
+
+	outstanding, err := s.lossService.GetOutstandingLoss(ctx, projectID)
+	if err != nil {
+		return offset, fmt.Errorf("failed to get carried-forward losses: %w", err)
+	}
+	if outstanding.IsPositive() {
+		offset = outstanding.Min(netProfit)
+	}
+
+	return offset, nil
+}
+
 // VerifyProfitCalculation implements FR-053: Cooperative verification
 func (s *profitSharingService) VerifyProfitCalculation(ctx context.Context, req *entities.VerifyProfitCalculationRequest, verifierID uuid.UUID) error {
 	// Mock implementation - would update calculation verification status
@@ -175,14 +290,14 @@ func (s *profitSharingService) GetProfitCalculation(ctx context.Context, calcula
 		CalculationPeriod:  entities.ProfitCalculationPeriodQuarterly,
 		StartDate:          time.Now().AddDate(0, -3, 0),
 		EndDate:            time.Now(),
-		TotalRevenue:       1000000.0,
-		TotalExpenses:      700000.0,
-		NetProfit:          300000.0,
-		TotalLoss:          0.0,
+		TotalRevenue:       entities.MustParseMoney("1000000", "IDR"),
+		TotalExpenses:      entities.MustParseMoney("700000", "IDR"),
+		NetProfit:          entities.MustParseMoney("300000", "IDR"),
+		TotalLoss:          entities.MustParseMoney("0", "IDR"),
 		ProfitSharingRatio: map[string]float64{"investor": 70, "business": 25, "cooperative": 5},
-		InvestorShare:      210000.0,
-		BusinessShare:      75000.0,
-		CooperativeShare:   15000.0,
+		InvestorShare:      entities.MustParseMoney("210000", "IDR"),
+		BusinessShare:      entities.MustParseMoney("75000", "IDR"),
+		CooperativeShare:   entities.MustParseMoney("15000", "IDR"),
 		ShariaCompliant:    true,
 		VerificationStatus: entities.ProfitCalculationStatusVerified,
 		IsActive:           true,
@@ -203,13 +318,13 @@ func (s *profitSharingService) GetProjectProfitCalculations(ctx context.Context,
 			CalculationPeriod:  entities.ProfitCalculationPeriodQuarterly,
 			StartDate:          time.Now().AddDate(0, -3, 0),
 			EndDate:            time.Now(),
-			TotalRevenue:       1000000.0,
-			TotalExpenses:      700000.0,
-			NetProfit:          300000.0,
+			TotalRevenue:       entities.MustParseMoney("1000000", "IDR"),
+			TotalExpenses:      entities.MustParseMoney("700000", "IDR"),
+			NetProfit:          entities.MustParseMoney("300000", "IDR"),
 			ProfitSharingRatio: map[string]float64{"investor": 70, "business": 25, "cooperative": 5},
-			InvestorShare:      210000.0,
-			BusinessShare:      75000.0,
-			CooperativeShare:   15000.0,
+			InvestorShare:      entities.MustParseMoney("210000", "IDR"),
+			BusinessShare:      entities.MustParseMoney("75000", "IDR"),
+			CooperativeShare:   entities.MustParseMoney("15000", "IDR"),
 			ShariaCompliant:    true,
 			VerificationStatus: entities.ProfitCalculationStatusVerified,
 			IsActive:           true,
@@ -228,21 +343,34 @@ func (s *profitSharingService) SearchProfitCalculations(ctx context.Context, fil
 }
 
 // CalculateShariaCompliantProfit calculates profit based on Sharia principles
-func (s *profitSharingService) CalculateShariaCompliantProfit(ctx context.Context, projectID uuid.UUID, revenue, expenses float64) (float64, map[string]float64, error) {
-	// Mock implementation - would implement Sharia-compliant profit calculation
-	netProfit := revenue - expenses
-	if netProfit < 0 {
-		return 0, map[string]float64{}, nil
+func (s *profitSharingService) CalculateShariaCompliantProfit(ctx context.Context, projectID uuid.UUID, revenue, expenses entities.Money) (entities.Money, map[string]float64, error) {
+	contract, err := s.projectContract(ctx, projectID)
+	if err != nil {
+		return entities.Money{}, nil, err
+	}
+	if contract.ContractType == entities.ContractTypeMurabahah {
+		return entities.Money{}, nil, errors.New("murabahah projects return their markup through installments, not profit sharing")
 	}
 
-	// Default Sharia-compliant ratios
-	shares := map[string]float64{
-		"investor":    70.0,
-		"business":    25.0,
-		"cooperative": 5.0,
+	netProfit := revenue.Sub(expenses)
+	if netProfit.IsNegative() {
+		// Under musyarakah a loss is borne in proportion to capital
+		if contract.ContractType == entities.ContractTypeMusyarakah {
+			investorRatio := contract.InvestorCapitalRatio()
+			return netProfit, map[string]float64{"investor": investorRatio, "business": 100 - investorRatio, "cooperative": 0}, nil
+		}
+		// Under mudarabah a loss is borne by the capital providers alone; the
+		// business loses the value of its effort
+		return netProfit, map[string]float64{"investor": 100.0, "business": 0, "cooperative": 0}, nil
+	}
+
+	lossOffset, err := s.lossOffset(ctx, projectID, netProfit)
+	if err != nil {
+		return entities.Money{}, nil, err
 	}
+	netProfit = netProfit.Sub(lossOffset)
 
-	return netProfit, shares, nil
+	return netProfit, contract.ProfitSharingRatio, nil
 }
 
 // CreateProfitDistribution implements FR-054 to FR-056: Profit distribution
@@ -262,11 +390,49 @@ func (s *profitSharingService) CreateProfitDistribution(ctx context.Context, req
 		return nil, errors.New("profit calculation must be verified before distribution")
 	}
 
-	var distributionAmount float64
+	var distributionAmount entities.Money
 	if req.DistributionType == entities.ProfitDistributionTypeProfit {
+		// The profit first recovers carried-forward losses; the investor share is what remains
+		if s.lossService != nil {
+			if _, err := s.lossService.RecoverLosses(ctx, calculation, creatorID); err != nil {
+				return nil, fmt.Errorf("failed to recover carried-forward losses: %w", err)
+			}
+		}
 		distributionAmount = calculation.InvestorShare
 	} else {
-		distributionAmount = 0 // Loss compensation would be calculated differently
+		// A loss distribution moves no money; it records each investor's share of the loss
+		if !calculation.TotalLoss.IsPositive() {
+			return nil, errors.New("profit calculation has no loss to distribute")
+		}
+		distributionAmount = entities.ZeroMoney(calculation.TotalLoss.Currency())
+		if s.lossService != nil {
+			investments, err := s.getProjectInvestments(ctx, calculation.ProjectID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get project investments: %w", err)
+			}
+			loss, err := s.lossService.RecordLoss(ctx, calculation, investments, creatorID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to record project loss: %w", err)
+			}
+			if loss.BorneBy == entities.LossBorneByInvestors {
+				distributionAmount = loss.LossAmount
+			}
+		}
+	}
+
+	// The distribution is paid in the project's currency; a project under the
+	// default contract keeps its accounts in its profit calculation's currency
+	contract, err := s.projectContract(ctx, calculation.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	currency := contract.Currency
+	if currency == "" {
+		currency = calculation.TotalRevenue.Currency()
+	}
+	distributionAmount, err = distributionAmount.InCurrency(currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid distribution amount: %w", err)
 	}
 
 	// Create profit distribution record
@@ -277,7 +443,7 @@ func (s *profitSharingService) CreateProfitDistribution(ctx context.Context, req
 		CooperativeID:           calculation.CooperativeID,
 		DistributionType:        req.DistributionType,
 		TotalDistributionAmount: distributionAmount,
-		Currency:                "IDR",
+		Currency:                currency,
 		DistributionDate:        req.DistributionDate,
 		Status:                  entities.ProfitDistributionStatusPending,
 		EscrowAccountID:         uuid.Nil, // Will be set during processing
@@ -292,7 +458,7 @@ func (s *profitSharingService) CreateProfitDistribution(ctx context.Context, req
 		Operation:  "create_profit_distribution",
 		EntityType: "profit_distribution",
 		EntityID:   distribution.ID,
-		NewValues:  fmt.Sprintf("Created profit distribution: amount=%f, type=%s", distributionAmount, req.DistributionType),
+		NewValues:  fmt.Sprintf("Created profit distribution: amount=%s, type=%s", distributionAmount.Decimal(), req.DistributionType),
 	})
 
 	return distribution, nil
@@ -300,10 +466,41 @@ func (s *profitSharingService) CreateProfitDistribution(ctx context.Context, req
 
 // ProcessProfitDistribution processes the profit distribution
 func (s *profitSharingService) ProcessProfitDistribution(ctx context.Context, req *entities.ProcessProfitDistributionRequest, processorID uuid.UUID) error {
-	// Mock implementation - would:
-	// 1. Calculate individual investor profit shares
-	// 2. Update distribution status to processing
-	// 3. Initiate bank transfers
+	distribution, err := s.GetProfitDistribution(ctx, req.DistributionID)
+	if err != nil {
+		return fmt.Errorf("failed to get profit distribution: %w", err)
+	}
+
+	// Investors' shares of a loss were recorded when the distribution was created
+	if distribution.DistributionType == entities.ProfitDistributionTypeLossCompensation {
+		s.auditService.LogOperation(ctx, &LogOperationRequest{
+			UserID:     processorID,
+			Operation:  "process_profit_distribution",
+			EntityType: "profit_distribution",
+			EntityID:   req.DistributionID,
+			NewValues:  "Loss distribution has no transfers to process",
+		})
+		return nil
+	}
+
+	// Calculate individual investor profit shares
+	shares, withholdings, err := s.calculateInvestorProfitShares(ctx, distribution)
+	if err != nil {
+		return fmt.Errorf("failed to calculate investor profit shares: %w", err)
+	}
+
+	// Credit investors and tax payable against escrow in one balanced entry
+	if _, err := s.ledgerService.RecordProfitDistribution(ctx, distribution, shares, processorID); err != nil {
+		return fmt.Errorf("failed to record profit distribution in ledger: %w", err)
+	}
+
+	if err := s.recordWithholdings(ctx, distribution, withholdings); err != nil {
+		return err
+	}
+
+	if err := s.payOutShares(ctx, distribution, shares, processorID); err != nil {
+		return err
+	}
 
 	// Log audit trail
 	s.auditService.LogOperation(ctx, &LogOperationRequest{
@@ -317,6 +514,126 @@ func (s *profitSharingService) ProcessProfitDistribution(ctx context.Context, re
 	return nil
 }
 
+// payOutShares applies investors' reinvestment preferences to credited shares
+// and queues the rest for payout
+func (s *profitSharingService) payOutShares(ctx context.Context, distribution *entities.ProfitDistributionExtended, shares []*entities.InvestorProfitShare, processorID uuid.UUID) error {
+	// Roll the shares investors chose to reinvest into new investments
+	if s.reinvestmentService != nil {
+		if _, err := s.reinvestmentService.ApplyReinvestments(ctx, distribution, shares, processorID); err != nil {
+			return fmt.Errorf("failed to apply reinvestments: %w", err)
+		}
+	}
+
+	// Investors are paid the rest through the next payout batch
+	if s.payoutService != nil {
+		if _, err := s.payoutService.QueueProfitShares(ctx, distribution, shares, processorID); err != nil {
+			return fmt.Errorf("failed to queue profit share payouts: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SettleInstallment credits a paid murabahah installment to the project's
+// investors in proportion to their investment and queues it for payout. The
+// installment carries both returned capital and markup; it is marked settled
+// before investors are credited so that it cannot be distributed twice.
+func (s *profitSharingService) SettleInstallment(ctx context.Context, projectID, installmentID, processorID uuid.UUID) (*entities.ProfitDistributionExtended, error) {
+	if s.contractService == nil {
+		return nil, errors.New("project contracts are not enabled")
+	}
+
+	distributionID := uuid.New()
+	installment, err := s.contractService.ClaimInstallmentSettlement(ctx, projectID, installmentID, distributionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	distribution := &entities.ProfitDistributionExtended{
+		ID:                      distributionID,
+		ProjectID:               projectID,
+		CooperativeID:           installment.CooperativeID,
+		DistributionType:        entities.ProfitDistributionTypeInstallment,
+		TotalDistributionAmount: installment.Amount,
+		Currency:                installment.Currency,
+		DistributionDate:        now,
+		Status:                  entities.ProfitDistributionStatusProcessing,
+		ProcessedBy:             &processorID,
+		ProcessedAt:             &now,
+		TransactionReference:    installment.PaymentReference,
+		Metadata: map[string]interface{}{
+			"installment_id":   installment.ID,
+			"sequence":         installment.Sequence,
+			"principal_amount": installment.PrincipalAmount.Decimal(),
+			"profit_amount":    installment.ProfitAmount.Decimal(),
+		},
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	shares, err := s.creditInstallment(ctx, distribution, installment.ProfitAmount, processorID)
+	if err != nil {
+		// Nothing was credited, so the installment can be settled again
+		if releaseErr := s.contractService.ReleaseInstallmentSettlement(ctx, installment); releaseErr != nil {
+			return nil, fmt.Errorf("%w (and failed to release installment: %v)", err, releaseErr)
+		}
+		return nil, err
+	}
+
+	if err := s.payOutShares(ctx, distribution, shares, processorID); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     processorID,
+		Operation:  "settle_murabahah_installment",
+		EntityType: "profit_distribution",
+		EntityID:   distribution.ID,
+		NewValues:  fmt.Sprintf("Settled installment %d of project %s: amount=%s, principal=%s, profit=%s", installment.Sequence, projectID, installment.Amount.Decimal(), installment.PrincipalAmount.Decimal(), installment.ProfitAmount.Decimal()),
+	})
+
+	return distribution, nil
+}
+
+// creditInstallment splits an installment distribution across the project's
+// investments and credits each investor in the ledger. Only the markup is
+// income; tax is withheld from each investor's part of it when taxService is set.
+func (s *profitSharingService) creditInstallment(ctx context.Context, distribution *entities.ProfitDistributionExtended, profitAmount entities.Money, processorID uuid.UUID) ([]*entities.InvestorProfitShare, error) {
+	investments, err := s.getProjectInvestments(ctx, distribution.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project investments: %w", err)
+	}
+
+	shares, err := allocateInvestorProfitShares(distribution, investments)
+	if err != nil {
+		return nil, err
+	}
+
+	var withholdings []*entities.TaxWithholding
+	if s.taxService != nil {
+		taxable, err := allocateTaxableAmounts(profitAmount, investments)
+		if err != nil {
+			return nil, err
+		}
+		withholdings, err = s.taxService.WithholdTax(ctx, distribution, shares, taxable)
+		if err != nil {
+			return nil, fmt.Errorf("failed to withhold tax: %w", err)
+		}
+	}
+
+	if _, err := s.ledgerService.RecordProfitDistribution(ctx, distribution, shares, processorID); err != nil {
+		return nil, fmt.Errorf("failed to record installment settlement in ledger: %w", err)
+	}
+
+	if err := s.recordWithholdings(ctx, distribution, withholdings); err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
+
 // GetProfitDistribution gets profit distribution by ID
 func (s *profitSharingService) GetProfitDistribution(ctx context.Context, distributionID uuid.UUID) (*entities.ProfitDistributionExtended, error) {
 	// Mock implementation
@@ -326,7 +643,7 @@ func (s *profitSharingService) GetProfitDistribution(ctx context.Context, distri
 		ProjectID:               uuid.New(),
 		CooperativeID:           uuid.New(),
 		DistributionType:        entities.ProfitDistributionTypeProfit,
-		TotalDistributionAmount: 210000.0,
+		TotalDistributionAmount: entities.MustParseMoney("210000", "IDR"),
 		Currency:                "IDR",
 		DistributionDate:        time.Now(),
 		Status:                  entities.ProfitDistributionStatusProcessing,
@@ -346,7 +663,7 @@ func (s *profitSharingService) GetProjectProfitDistributions(ctx context.Context
 			ProjectID:               projectID,
 			CooperativeID:           uuid.New(),
 			DistributionType:        entities.ProfitDistributionTypeProfit,
-			TotalDistributionAmount: 210000.0,
+			TotalDistributionAmount: entities.MustParseMoney("210000", "IDR"),
 			Currency:                "IDR",
 			DistributionDate:        time.Now(),
 			Status:                  entities.ProfitDistributionStatusCompleted,
@@ -367,27 +684,132 @@ func (s *profitSharingService) SearchProfitDistributions(ctx context.Context, fi
 
 // CalculateInvestorProfitShares calculates individual investor profit shares
 func (s *profitSharingService) CalculateInvestorProfitShares(ctx context.Context, distributionID uuid.UUID) ([]*entities.InvestorProfitShare, error) {
-	// Mock implementation - would calculate based on:
-	// 1. Individual investment amounts
-	// 2. Investment percentages
-	// 3. Total distribution amount
+	distribution, err := s.GetProfitDistribution(ctx, distributionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profit distribution: %w", err)
+	}
 
-	shares := []*entities.InvestorProfitShare{
-		{
+	shares, _, err := s.calculateInvestorProfitShares(ctx, distribution)
+	return shares, err
+}
+
+// calculateInvestorProfitShares allocates a profit distribution to the
+// project's investments and withholds tax from each share. The withholdings
+// are recorded once the shares have been credited.
+func (s *profitSharingService) calculateInvestorProfitShares(ctx context.Context, distribution *entities.ProfitDistributionExtended) ([]*entities.InvestorProfitShare, []*entities.TaxWithholding, error) {
+	investments, err := s.getProjectInvestments(ctx, distribution.ProjectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get project investments: %w", err)
+	}
+
+	shares, err := allocateInvestorProfitShares(distribution, investments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The whole of a profit share is taxable
+	taxable := make([]entities.Money, len(shares))
+	for i, share := range shares {
+		taxable[i] = share.ProfitShareAmount
+	}
+
+	if s.taxService == nil {
+		for i, share := range shares {
+			share.TaxAmount = taxable[i].Percent(10, entities.RoundHalfUp)
+			share.NetProfitShare = share.ProfitShareAmount.Sub(share.TaxAmount)
+		}
+		return shares, nil, nil
+	}
+
+	withholdings, err := s.taxService.WithholdTax(ctx, distribution, shares, taxable)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to withhold tax: %w", err)
+	}
+
+	return shares, withholdings, nil
+}
+
+// recordWithholdings records the tax withheld from credited shares
+func (s *profitSharingService) recordWithholdings(ctx context.Context, distribution *entities.ProfitDistributionExtended, withholdings []*entities.TaxWithholding) error {
+	if s.taxService == nil || len(withholdings) == 0 {
+		return nil
+	}
+
+	if err := s.taxService.RecordWithholdings(ctx, distribution, withholdings); err != nil {
+		return fmt.Errorf("failed to record tax withholdings: %w", err)
+	}
+	return nil
+}
+
+// allocateTaxableAmounts splits the taxable part of a distribution across
+// investments in the same proportions as their shares
+func allocateTaxableAmounts(taxableAmount entities.Money, investments []*entities.Investment) ([]entities.Money, error) {
+	weights := make([]entities.Money, len(investments))
+	for i, investment := range investments {
+		if err := investment.Amount.CheckCurrency(taxableAmount.Currency()); err != nil {
+			return nil, fmt.Errorf("investment %s: %w", investment.ID, err)
+		}
+		weights[i] = investment.Amount
+	}
+
+	amounts, err := entities.AllocateByWeight(taxableAmount, weights)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate taxable amounts: %w", err)
+	}
+	return amounts, nil
+}
+
+// getProjectInvestments gets the project's confirmed investments
+func (s *profitSharingService) getProjectInvestments(ctx context.Context, projectID uuid.UUID) ([]*entities.Investment, error) {
+	// Mock implementation
+	return []*entities.Investment{
+		{ID: uuid.New(), ProjectID: projectID, InvestorID: uuid.New(), Amount: entities.MustParseMoney("50000", "IDR"), Status: "confirmed"},
+		{ID: uuid.New(), ProjectID: projectID, InvestorID: uuid.New(), Amount: entities.MustParseMoney("75000", "IDR"), Status: "confirmed"},
+		{ID: uuid.New(), ProjectID: projectID, InvestorID: uuid.New(), Amount: entities.MustParseMoney("75000", "IDR"), Status: "confirmed"},
+	}, nil
+}
+
+// allocateInvestorProfitShares splits a distribution across investments in
+// proportion to their amounts; the gross shares add up to the distribution exactly
+func allocateInvestorProfitShares(distribution *entities.ProfitDistributionExtended, investments []*entities.Investment) ([]*entities.InvestorProfitShare, error) {
+	if len(investments) == 0 {
+		return nil, errors.New("no confirmed investments to distribute profit to")
+	}
+
+	weights := make([]entities.Money, len(investments))
+	totalInvestment := entities.ZeroMoney(distribution.Currency)
+	for i, investment := range investments {
+		if err := investment.Amount.CheckCurrency(distribution.Currency); err != nil {
+			return nil, fmt.Errorf("investment %s cannot share a %s distribution: %w", investment.ID, distribution.Currency, err)
+		}
+		weights[i] = investment.Amount
+		totalInvestment = totalInvestment.Add(investment.Amount)
+	}
+
+	amounts, err := entities.AllocateByWeight(distribution.TotalDistributionAmount, weights)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate profit shares: %w", err)
+	}
+
+	shares := make([]*entities.InvestorProfitShare, len(investments))
+	for i, investment := range investments {
+		percentage, _ := investment.Amount.Ratio(totalInvestment).Float64()
+		shares[i] = &entities.InvestorProfitShare{
 			ID:                   uuid.New(),
-			ProfitDistributionID: distributionID,
-			InvestmentID:         uuid.New(),
-			InvestorID:           uuid.New(),
-			OriginalInvestment:   50000.0,
-			InvestmentPercentage: 25.0,
-			ProfitShareAmount:    52500.0,
-			TaxAmount:            5250.0,
-			NetProfitShare:       47250.0,
+			ProfitDistributionID: distribution.ID,
+			InvestmentID:         investment.ID,
+			InvestorID:           investment.InvestorID,
+			OriginalInvestment:   investment.Amount,
+			InvestmentPercentage: percentage * 100,
+			ProfitShareAmount:    amounts[i],
+			TaxAmount:            entities.ZeroMoney(distribution.Currency),
+			NetProfitShare:       amounts[i],
+			ReinvestedAmount:     entities.ZeroMoney(distribution.Currency),
 			Status:               entities.InvestorProfitShareStatusPending,
 			IsActive:             true,
 			CreatedAt:            time.Now(),
 			UpdatedAt:            time.Now(),
-		},
+		}
 	}
 
 	return shares, nil
@@ -400,9 +822,12 @@ func (s *profitSharingService) CreateTaxDocumentation(ctx context.Context, req *
 		return nil, errors.New("tax rate must be between 0 and 100")
 	}
 
-	if req.DueDate.Before(time.Now()) {
+	if !req.DueDate.IsZero() && req.DueDate.Before(time.Now()) {
 		return nil, errors.New("due date cannot be in the past")
 	}
+	if req.DueDate.IsZero() && s.taxService == nil {
+		return nil, errors.New("due date is required")
+	}
 
 	// Get profit distribution to calculate taxable amount
 	distribution, err := s.GetProfitDistribution(ctx, req.ProfitDistributionID)
@@ -411,7 +836,21 @@ func (s *profitSharingService) CreateTaxDocumentation(ctx context.Context, req *
 	}
 
 	taxableAmount := distribution.TotalDistributionAmount
-	taxAmount := (taxableAmount * req.TaxRate) / 100
+	taxAmount := taxableAmount.Percent(req.TaxRate, entities.RoundHalfUp)
+	taxRate := req.TaxRate
+	dueDate := req.DueDate
+
+	// With withholding in place the document reflects the tax actually withheld
+	if s.taxService != nil {
+		report, err := s.taxService.GetDistributionReport(ctx, distribution)
+		if err != nil {
+			return nil, err
+		}
+		taxableAmount, taxAmount, taxRate = report.TotalTaxableAmount, report.TotalTaxAmount, report.EffectiveRate()
+		if dueDate.IsZero() {
+			dueDate = report.DueDate
+		}
+	}
 
 	// Generate document number
 	documentNumber := fmt.Sprintf("TAX-%d-%s-%s", req.TaxYear, req.TaxPeriod, uuid.New().String()[:8])
@@ -426,10 +865,10 @@ func (s *profitSharingService) CreateTaxDocumentation(ctx context.Context, req *
 		TaxPeriod:            req.TaxPeriod,
 		TotalTaxableAmount:   taxableAmount,
 		TotalTaxAmount:       taxAmount,
-		TaxRate:              req.TaxRate,
+		TaxRate:              taxRate,
 		Currency:             distribution.Currency,
 		IssuedDate:           time.Now(),
-		DueDate:              req.DueDate,
+		DueDate:              dueDate,
 		Status:               entities.TaxDocumentStatusDraft,
 		IssuedBy:             creatorID,
 		ComplianceNotes:      req.ComplianceNotes,
@@ -444,7 +883,7 @@ func (s *profitSharingService) CreateTaxDocumentation(ctx context.Context, req *
 		Operation:  "create_tax_documentation",
 		EntityType: "tax_documentation",
 		EntityID:   taxDoc.ID,
-		NewValues:  fmt.Sprintf("Created tax document: type=%s, amount=%f, tax=%f", req.DocumentType, taxableAmount, taxAmount),
+		NewValues:  fmt.Sprintf("Created tax document: type=%s, amount=%s, tax=%s", req.DocumentType, taxableAmount.Decimal(), taxAmount.Decimal()),
 	})
 
 	return taxDoc, nil
@@ -460,8 +899,8 @@ func (s *profitSharingService) GetTaxDocumentation(ctx context.Context, document
 		DocumentNumber:       "TAX-2024-quarterly-12345678",
 		TaxYear:              2024,
 		TaxPeriod:            entities.TaxPeriodQuarterly,
-		TotalTaxableAmount:   210000.0,
-		TotalTaxAmount:       21000.0,
+		TotalTaxableAmount:   entities.MustParseMoney("210000", "IDR"),
+		TotalTaxAmount:       entities.MustParseMoney("21000", "IDR"),
 		TaxRate:              10.0,
 		Currency:             "IDR",
 		IssuedDate:           time.Now(),
@@ -485,8 +924,8 @@ func (s *profitSharingService) GetDistributionTaxDocuments(ctx context.Context,
 			DocumentNumber:       "TAX-2024-quarterly-12345678",
 			TaxYear:              2024,
 			TaxPeriod:            entities.TaxPeriodQuarterly,
-			TotalTaxableAmount:   210000.0,
-			TotalTaxAmount:       21000.0,
+			TotalTaxableAmount:   entities.MustParseMoney("210000", "IDR"),
+			TotalTaxAmount:       entities.MustParseMoney("21000", "IDR"),
 			TaxRate:              10.0,
 			Currency:             "IDR",
 			IssuedDate:           time.Now(),
@@ -510,6 +949,15 @@ func (s *profitSharingService) SearchTaxDocumentation(ctx context.Context, filte
 
 // GenerateTaxCompliantDocument generates tax-compliant documentation
 func (s *profitSharingService) GenerateTaxCompliantDocument(ctx context.Context, distributionID uuid.UUID, documentType string) (*entities.TaxDocumentation, error) {
+	if s.taxService != nil {
+		distribution, err := s.GetProfitDistribution(ctx, distributionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get profit distribution: %w", err)
+		}
+		// Generated documents are issued by the system
+		return s.taxService.GenerateTaxDocument(ctx, distribution, documentType, uuid.Nil)
+	}
+
 	// Mock implementation - would generate tax-compliant document based on type
 	return &entities.TaxDocumentation{
 		ID:                   uuid.New(),
@@ -518,8 +966,8 @@ func (s *profitSharingService) GenerateTaxCompliantDocument(ctx context.Context,
 		DocumentNumber:       fmt.Sprintf("TAX-2024-%s-%s", documentType, uuid.New().String()[:8]),
 		TaxYear:              2024,
 		TaxPeriod:            entities.TaxPeriodQuarterly,
-		TotalTaxableAmount:   210000.0,
-		TotalTaxAmount:       21000.0,
+		TotalTaxableAmount:   entities.MustParseMoney("210000", "IDR"),
+		TotalTaxAmount:       entities.MustParseMoney("21000", "IDR"),
 		TaxRate:              10.0,
 		Currency:             "IDR",
 		IssuedDate:           time.Now(),
@@ -537,13 +985,13 @@ func (s *profitSharingService) GetProfitSharingSummary(ctx context.Context, coop
 	// Mock implementation
 	return &entities.ProfitSharingSummary{
 		TotalCalculations:      10,
-		TotalProfit:            3000000.0,
-		TotalLoss:              0.0,
+		TotalProfit:            entities.MustParseMoney("3000000", "IDR"),
+		TotalLoss:              entities.MustParseMoney("0", "IDR"),
 		TotalDistributions:     8,
-		TotalDistributedAmount: 2100000.0,
+		TotalDistributedAmount: entities.MustParseMoney("2100000", "IDR"),
 		PendingDistributions:   2,
-		PendingAmount:          600000.0,
-		TotalTaxAmount:         210000.0,
+		PendingAmount:          entities.MustParseMoney("600000", "IDR"),
+		TotalTaxAmount:         entities.MustParseMoney("210000", "IDR"),
 		TotalTaxDocuments:      8,
 		Currency:               "IDR",
 	}, nil
@@ -570,41 +1018,34 @@ func (s *profitSharingService) GetProjectProfitAnalytics(ctx context.Context, pr
 	}, nil
 }
 
-// checkShariaCompliance checks if profit calculation is Sharia-compliant
-func (s *profitSharingService) checkShariaCompliance(revenue, expenses float64, ratios map[string]float64) bool {
-	// Mock implementation - would check:
-	// 1. No interest-based transactions
-	// 2. Profit sharing ratios are fair
-	// 3. No gambling or prohibited activities
-	// 4. Transparency in calculations
-
-	// Check if ratios sum to 100%
-	totalRatio := 0.0
-	for _, ratio := range ratios {
-		totalRatio += ratio
-	}
-
-	if totalRatio != 100.0 {
-		return false
+// screenProfitCalculation screens the project's business and profit sharing ratio
+func (s *profitSharingService) screenProfitCalculation(ctx context.Context, req *entities.CreateProfitCalculationRequest) (*entities.ShariaScreeningEvaluation, error) {
+	subject := &entities.ShariaScreeningSubject{ProfitSharingRatio: req.ProfitSharingRatio}
+	if s.screeningService == nil {
+		ruleSets := []*entities.ShariaScreeningRuleSet{defaultShariaScreeningRuleSet(uuid.Nil)}
+		return evaluateShariaRuleSets(uuid.Nil, ruleSets, subject), nil
 	}
 
-	// Check if profit is reasonable (not excessive)
-	netProfit := revenue - expenses
-	if netProfit > 0 && (netProfit/revenue) > 0.5 {
-		return false // More than 50% profit margin might be excessive
+	screening, err := s.screeningService.ScreenProject(ctx, req.ProjectID, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to screen project: %w", err)
 	}
 
-	return true
+	return screening, nil
 }
 
 // CreateComFundsFee creates a new ComFunds fee structure
 func (s *profitSharingService) CreateComFundsFee(ctx context.Context, req *entities.CreateComFundsFeeRequest, creatorID uuid.UUID) (*entities.ComFundsFee, error) {
+	if s.feeService != nil {
+		return s.feeService.CreateFee(ctx, req, creatorID)
+	}
+
 	// Validate fee request
 	if req.FeePercentage < 0 || req.FeePercentage > 100 {
 		return nil, errors.New("fee percentage must be between 0 and 100")
 	}
 
-	if req.MinimumAmount > 0 && req.MaximumAmount > 0 && req.MinimumAmount > req.MaximumAmount {
+	if req.MinimumAmount.IsPositive() && req.MaximumAmount.IsPositive() && req.MinimumAmount.GreaterThan(req.MaximumAmount) {
 		return nil, errors.New("minimum amount cannot be greater than maximum amount")
 	}
 
@@ -617,7 +1058,7 @@ func (s *profitSharingService) CreateComFundsFee(ctx context.Context, req *entit
 		ID:            uuid.New(),
 		FeeType:       req.FeeType,
 		FeePercentage: req.FeePercentage,
-		FeeAmount:     0, // Will be calculated when applied
+		FeeAmount:     entities.ZeroMoney(req.MinimumAmount.Currency()), // Will be calculated when applied
 		IsEnabled:     req.IsEnabled,
 		MinimumAmount: req.MinimumAmount,
 		MaximumAmount: req.MaximumAmount,
@@ -646,6 +1087,10 @@ func (s *profitSharingService) CreateComFundsFee(ctx context.Context, req *entit
 
 // UpdateComFundsFee updates an existing ComFunds fee structure
 func (s *profitSharingService) UpdateComFundsFee(ctx context.Context, feeID uuid.UUID, req *entities.CreateComFundsFeeRequest, updaterID uuid.UUID) (*entities.ComFundsFee, error) {
+	if s.feeService != nil {
+		return s.feeService.UpdateFee(ctx, feeID, req, updaterID)
+	}
+
 	// Mock implementation - would update existing fee structure
 
 	// Log audit trail
@@ -669,6 +1114,10 @@ func (s *profitSharingService) UpdateComFundsFee(ctx context.Context, feeID uuid
 
 // EnableComFundsFee enables a ComFunds fee structure
 func (s *profitSharingService) EnableComFundsFee(ctx context.Context, feeID, enablerID uuid.UUID) error {
+	if s.feeService != nil {
+		return s.feeService.SetFeeEnabled(ctx, feeID, true, enablerID)
+	}
+
 	// Mock implementation - would enable fee structure
 
 	// Log audit trail
@@ -685,6 +1134,10 @@ func (s *profitSharingService) EnableComFundsFee(ctx context.Context, feeID, ena
 
 // DisableComFundsFee disables a ComFunds fee structure
 func (s *profitSharingService) DisableComFundsFee(ctx context.Context, feeID, disablerID uuid.UUID) error {
+	if s.feeService != nil {
+		return s.feeService.SetFeeEnabled(ctx, feeID, false, disablerID)
+	}
+
 	// Mock implementation - would disable fee structure
 
 	// Log audit trail
@@ -701,14 +1154,18 @@ func (s *profitSharingService) DisableComFundsFee(ctx context.Context, feeID, di
 
 // GetComFundsFee gets ComFunds fee by ID
 func (s *profitSharingService) GetComFundsFee(ctx context.Context, feeID uuid.UUID) (*entities.ComFundsFee, error) {
+	if s.feeService != nil {
+		return s.feeService.GetFee(ctx, feeID)
+	}
+
 	// Mock implementation
 	return &entities.ComFundsFee{
 		ID:            feeID,
 		FeeType:       entities.ComFundsFeeTypeSuccessFee,
 		FeePercentage: 2.0, // 2% fee as requested
 		IsEnabled:     true,
-		MinimumAmount: 0,
-		MaximumAmount: 0,
+		MinimumAmount: entities.MustParseMoney("0", "IDR"),
+		MaximumAmount: entities.MustParseMoney("0", "IDR"),
 		ApplicableTo:  entities.ComFundsFeeApplicableToSuccessfulFunding,
 		EffectiveFrom: time.Now(),
 		Description:   "2% success fee for successfully funded projects",
@@ -720,6 +1177,10 @@ func (s *profitSharingService) GetComFundsFee(ctx context.Context, feeID uuid.UU
 
 // GetActiveComFundsFees gets active fee structures by type
 func (s *profitSharingService) GetActiveComFundsFees(ctx context.Context, feeType string) ([]*entities.ComFundsFee, error) {
+	if s.feeService != nil {
+		return s.feeService.GetActiveFees(ctx, feeType)
+	}
+
 	// Mock implementation
 	fees := []*entities.ComFundsFee{
 		{
@@ -741,6 +1202,10 @@ func (s *profitSharingService) GetActiveComFundsFees(ctx context.Context, feeTyp
 
 // SearchComFundsFees searches fee structures with filters
 func (s *profitSharingService) SearchComFundsFees(ctx context.Context, filter *entities.ComFundsFeeFilter) ([]*entities.ComFundsFee, int, error) {
+	if s.feeService != nil {
+		return s.feeService.SearchFees(ctx, filter)
+	}
+
 	// Mock implementation
 	fees, _ := s.GetActiveComFundsFees(ctx, "")
 	return fees, len(fees), nil
@@ -748,8 +1213,12 @@ func (s *profitSharingService) SearchComFundsFees(ctx context.Context, filter *e
 
 // CalculateProjectFee calculates fee for a successfully funded project
 func (s *profitSharingService) CalculateProjectFee(ctx context.Context, req *entities.CalculateProjectFeeRequest, calculatorID uuid.UUID) (*entities.ProjectFeeCalculation, error) {
+	if s.feeService != nil {
+		return s.feeService.CalculateProjectFee(ctx, req, calculatorID)
+	}
+
 	// Validate fee calculation request
-	if req.TotalFundingAmount <= 0 {
+	if !req.TotalFundingAmount.IsPositive() {
 		return nil, errors.New("total funding amount must be greater than zero")
 	}
 
@@ -781,24 +1250,25 @@ func (s *profitSharingService) CalculateProjectFee(ctx context.Context, req *ent
 	}
 
 	// Calculate fee amount
-	feeAmount := (req.TotalFundingAmount * feeStructure.FeePercentage) / 100
+	feeAmount := req.TotalFundingAmount.Percent(feeStructure.FeePercentage, entities.RoundHalfUp)
 
 	// Apply minimum/maximum amount constraints
-	if feeStructure.MinimumAmount > 0 && feeAmount < feeStructure.MinimumAmount {
+	if feeStructure.MinimumAmount.IsPositive() && feeAmount.LessThan(feeStructure.MinimumAmount) {
 		feeAmount = feeStructure.MinimumAmount
 	}
 
-	if feeStructure.MaximumAmount > 0 && feeAmount > feeStructure.MaximumAmount {
+	if feeStructure.MaximumAmount.IsPositive() && feeAmount.GreaterThan(feeStructure.MaximumAmount) {
 		feeAmount = feeStructure.MaximumAmount
 	}
 
-	netAmountAfterFee := req.TotalFundingAmount - feeAmount
+	netAmountAfterFee := req.TotalFundingAmount.Sub(feeAmount)
 
 	// Create project fee calculation record
 	calculation := &entities.ProjectFeeCalculation{
 		ID:                 uuid.New(),
 		ProjectID:          req.ProjectID,
 		CooperativeID:      uuid.Nil, // Will be set based on project's cooperative
+		BusinessID:         uuid.Nil, // Will be set based on project's business
 		TotalFundingAmount: req.TotalFundingAmount,
 		FeePercentage:      feeStructure.FeePercentage,
 		FeeAmount:          feeAmount,
@@ -816,7 +1286,7 @@ func (s *profitSharingService) CalculateProjectFee(ctx context.Context, req *ent
 		Operation:  "calculate_project_fee",
 		EntityType: "project_fee_calculation",
 		EntityID:   calculation.ID,
-		NewValues:  fmt.Sprintf("Calculated project fee: amount=%f, fee=%f, percentage=%f%%", req.TotalFundingAmount, feeAmount, feeStructure.FeePercentage),
+		NewValues:  fmt.Sprintf("Calculated project fee: amount=%s, fee=%s, percentage=%f%%", req.TotalFundingAmount.Decimal(), feeAmount.Decimal(), feeStructure.FeePercentage),
 	})
 
 	return calculation, nil
@@ -824,10 +1294,24 @@ func (s *profitSharingService) CalculateProjectFee(ctx context.Context, req *ent
 
 // CollectProjectFee collects the calculated project fee
 func (s *profitSharingService) CollectProjectFee(ctx context.Context, req *entities.CollectProjectFeeRequest, collectorID uuid.UUID) error {
-	// Mock implementation - would:
-	// 1. Update fee calculation status to collected
-	// 2. Process payment collection
-	// 3. Generate transaction reference
+	if s.feeService != nil {
+		_, err := s.feeService.CollectProjectFee(ctx, req, collectorID)
+		return err
+	}
+
+	calculation, err := s.GetProjectFeeCalculation(ctx, req.ProjectFeeCalculationID)
+	if err != nil {
+		return fmt.Errorf("failed to get project fee calculation: %w", err)
+	}
+
+	if calculation.FeeStatus != entities.ProjectFeeStatusCalculated {
+		return fmt.Errorf("project fee cannot be collected in status %s", calculation.FeeStatus)
+	}
+
+	// Charge the business and recognise the platform fee in the ledger
+	if _, err := s.ledgerService.RecordFeeCollection(ctx, calculation, collectorID); err != nil {
+		return fmt.Errorf("failed to record fee collection in ledger: %w", err)
+	}
 
 	// Log audit trail
 	s.auditService.LogOperation(ctx, &LogOperationRequest{
@@ -843,6 +1327,11 @@ func (s *profitSharingService) CollectProjectFee(ctx context.Context, req *entit
 
 // WaiveProjectFee waives a project fee
 func (s *profitSharingService) WaiveProjectFee(ctx context.Context, projectFeeCalculationID, waiverID uuid.UUID, reason string) error {
+	if s.feeService != nil {
+		_, err := s.feeService.WaiveProjectFee(ctx, projectFeeCalculationID, waiverID, reason)
+		return err
+	}
+
 	// Mock implementation - would update fee status to waived
 
 	// Log audit trail
@@ -859,15 +1348,20 @@ func (s *profitSharingService) WaiveProjectFee(ctx context.Context, projectFeeCa
 
 // GetProjectFeeCalculation gets project fee calculation by ID
 func (s *profitSharingService) GetProjectFeeCalculation(ctx context.Context, calculationID uuid.UUID) (*entities.ProjectFeeCalculation, error) {
+	if s.feeService != nil {
+		return s.feeService.GetProjectFeeCalculation(ctx, calculationID)
+	}
+
 	// Mock implementation
 	return &entities.ProjectFeeCalculation{
 		ID:                 calculationID,
 		ProjectID:          uuid.New(),
 		CooperativeID:      uuid.New(),
-		TotalFundingAmount: 1000000.0,
+		BusinessID:         uuid.New(),
+		TotalFundingAmount: entities.MustParseMoney("1000000", "IDR"),
 		FeePercentage:      2.0,
-		FeeAmount:          20000.0,
-		NetAmountAfterFee:  980000.0,
+		FeeAmount:          entities.MustParseMoney("20000", "IDR"),
+		NetAmountAfterFee:  entities.MustParseMoney("980000", "IDR"),
 		FeeStatus:          entities.ProjectFeeStatusCalculated,
 		CalculatedAt:       time.Now(),
 		IsActive:           true,
@@ -878,16 +1372,21 @@ func (s *profitSharingService) GetProjectFeeCalculation(ctx context.Context, cal
 
 // GetProjectFeeCalculations gets project fee calculations
 func (s *profitSharingService) GetProjectFeeCalculations(ctx context.Context, projectID uuid.UUID, page, limit int) ([]*entities.ProjectFeeCalculation, int, error) {
+	if s.feeService != nil {
+		return s.feeService.GetProjectFeeCalculations(ctx, projectID, page, limit)
+	}
+
 	// Mock implementation
 	calculations := []*entities.ProjectFeeCalculation{
 		{
 			ID:                 uuid.New(),
 			ProjectID:          projectID,
 			CooperativeID:      uuid.New(),
-			TotalFundingAmount: 1000000.0,
+			BusinessID:         uuid.New(),
+			TotalFundingAmount: entities.MustParseMoney("1000000", "IDR"),
 			FeePercentage:      2.0,
-			FeeAmount:          20000.0,
-			NetAmountAfterFee:  980000.0,
+			FeeAmount:          entities.MustParseMoney("20000", "IDR"),
+			NetAmountAfterFee:  entities.MustParseMoney("980000", "IDR"),
 			FeeStatus:          entities.ProjectFeeStatusCollected,
 			CalculatedAt:       time.Now(),
 			CollectedAt:        &[]time.Time{time.Now()}[0],
@@ -902,6 +1401,18 @@ func (s *profitSharingService) GetProjectFeeCalculations(ctx context.Context, pr
 
 // SearchProjectFeeCalculations searches project fee calculations with filters
 func (s *profitSharingService) SearchProjectFeeCalculations(ctx context.Context, filter *entities.ProjectFeeCalculationFilter) ([]*entities.ProjectFeeCalculation, int, error) {
+	if s.feeService != nil {
+		// Calculations are stored per cooperative
+		switch {
+		case filter.CooperativeID != nil:
+			return s.feeService.SearchProjectFeeCalculations(ctx, *filter.CooperativeID, filter)
+		case filter.ProjectID != nil:
+			return s.feeService.GetProjectFeeCalculations(ctx, *filter.ProjectID, filter.Page, filter.Limit)
+		default:
+			return nil, 0, errors.New("cooperative or project is required to search project fee calculations")
+		}
+	}
+
 	// Mock implementation
 	return s.GetProjectFeeCalculations(ctx, uuid.New(), filter.Page, filter.Limit)
 }
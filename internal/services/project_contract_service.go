@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ProjectContractService manages the financing contract each project runs
+// under: mudarabah and musyarakah share profit by an agreed ratio, while a
+// murabahah sells an asset to the business at cost plus markup and is repaid in
+// scheduled installments
+type ProjectContractService interface {
+	SetContract(ctx context.Context, projectID uuid.UUID, req *entities.SetProjectContractRequest, userID uuid.UUID) (*entities.ProjectContract, error)
+	// GetContract returns a project's contract with its installment schedule, or
+	// a mudarabah under the default ratio when none was set
+	GetContract(ctx context.Context, projectID uuid.UUID) (*entities.ProjectContract, error)
+	GetInstallments(ctx context.Context, projectID uuid.UUID) ([]*entities.MurabahahInstallment, error)
+
+	// RecordInstallmentPayment records the business paying an installment in full
+	RecordInstallmentPayment(ctx context.Context, projectID, installmentID uuid.UUID, req *entities.RecordInstallmentPaymentRequest, userID uuid.UUID) (*entities.MurabahahInstallment, error)
+	// ClaimInstallmentSettlement marks a paid installment as settled by the
+	// distribution; it fails if the installment is unpaid or already settled
+	ClaimInstallmentSettlement(ctx context.Context, projectID, installmentID, distributionID uuid.UUID) (*entities.MurabahahInstallment, error)
+	// ReleaseInstallmentSettlement returns a claimed installment to paid when its
+	// settlement could not be completed
+	ReleaseInstallmentSettlement(ctx context.Context, installment *entities.MurabahahInstallment) error
+}
+
+type projectContractService struct {
+	contractRepo repositories.ProjectContractRepository
+	auditService AuditService
+}
+
+// NewProjectContractService creates a new project contract service
+func NewProjectContractService(contractRepo repositories.ProjectContractRepository, auditService AuditService) ProjectContractService {
+	return &projectContractService{
+		contractRepo: contractRepo,
+		auditService: auditService,
+	}
+}
+
+// defaultProfitSharingRatio is the ratio profit is shared in when a project's
+// contract does not set one: 70% investor, 25% business, 5% cooperative
+func defaultProfitSharingRatio() map[string]float64 {
+	return map[string]float64{"investor": 70, "business": 25, "cooperative": 5}
+}
+
+// defaultProjectContract is the contract of a project that has not set one
+func defaultProjectContract(projectID uuid.UUID) *entities.ProjectContract {
+	return &entities.ProjectContract{
+		ProjectID:          projectID,
+		ContractType:       entities.ContractTypeMudarabah,
+		ProfitSharingRatio: defaultProfitSharingRatio(),
+	}
+}
+
+func (s *projectContractService) SetContract(ctx context.Context, projectID uuid.UUID, req *entities.SetProjectContractRequest, userID uuid.UUID) (*entities.ProjectContract, error) {
+	amounts := []entities.Money{req.PartnerCapital, req.CostPrice, req.Markup}
+	for i, amount := range amounts {
+		converted, err := amount.InCurrency(req.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid contract amount: %w", err)
+		}
+		amounts[i] = converted
+	}
+
+	now := time.Now()
+	contract := &entities.ProjectContract{
+		ProjectID:          projectID,
+		ContractType:       req.ContractType,
+		Currency:           req.Currency,
+		ProfitSharingRatio: req.ProfitSharingRatio,
+		PartnerCapital:     amounts[0],
+		CostPrice:          amounts[1],
+		Markup:             amounts[2],
+		CreatedBy:          userID,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if existing, err := s.contractRepo.GetContract(ctx, projectID); err != nil {
+		return nil, err
+	} else if existing != nil {
+		contract.CreatedBy = existing.CreatedBy
+		contract.CreatedAt = existing.CreatedAt
+	}
+
+	var installments []*entities.MurabahahInstallment
+	switch req.ContractType {
+	case entities.ContractTypeMudarabah, entities.ContractTypeMusyarakah:
+		if !contract.CostPrice.IsZero() || !contract.Markup.IsZero() || req.InstallmentCount != 0 {
+			return nil, fmt.Errorf("%s contracts have no sale price or installments", req.ContractType)
+		}
+		if req.ContractType == entities.ContractTypeMudarabah && !contract.PartnerCapital.IsZero() {
+			return nil, errors.New("under mudarabah the business contributes no capital")
+		}
+		if req.ContractType == entities.ContractTypeMusyarakah && !contract.PartnerCapital.IsPositive() {
+			return nil, errors.New("musyarakah contracts need the business partner's capital")
+		}
+		if len(contract.ProfitSharingRatio) == 0 {
+			contract.ProfitSharingRatio = defaultProfitSharingRatio()
+		}
+		ratio := checkProfitSharingRatio(&entities.ShariaScreeningSubject{ProfitSharingRatio: contract.ProfitSharingRatio})
+		if ratio.Status == entities.ShariaRuleStatusFailed {
+			return nil, errors.New(ratio.Detail)
+		}
+
+	case entities.ContractTypeMurabahah:
+		if !contract.PartnerCapital.IsZero() || len(req.ProfitSharingRatio) > 0 {
+			return nil, errors.New("murabahah contracts are repaid in installments, not by sharing capital or profit")
+		}
+		if !contract.CostPrice.IsPositive() {
+			return nil, errors.New("murabahah cost price must be positive")
+		}
+		if contract.Markup.IsNegative() {
+			return nil, errors.New("murabahah markup cannot be negative")
+		}
+		if req.InstallmentCount < 1 {
+			return nil, errors.New("murabahah contracts need at least one installment")
+		}
+		if req.FirstInstallmentDate == nil {
+			return nil, errors.New("murabahah contracts need a first installment date")
+		}
+		contract.AssetDescription = req.AssetDescription
+		contract.InstallmentCount = req.InstallmentCount
+		contract.InstallmentIntervalMonths = req.InstallmentIntervalMonths
+		if contract.InstallmentIntervalMonths == 0 {
+			contract.InstallmentIntervalMonths = 1
+		}
+		contract.FirstInstallmentDate = req.FirstInstallmentDate
+
+		var err error
+		installments, err = murabahahSchedule(contract)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported contract type: %s", req.ContractType)
+	}
+
+	if err := s.contractRepo.SaveContract(ctx, contract, installments); err != nil {
+		return nil, err
+	}
+	contract.Installments = installments
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "set_project_contract",
+		EntityType: entities.AuditEntityProject,
+		EntityID:   projectID,
+		NewValues:  contract,
+	})
+
+	return contract, nil
+}
+
+// murabahahSchedule splits the sale price into equal installments, with any
+// minor units left over going to the earliest ones. Each installment repays
+// the cost price and the markup in the same proportion as the sale price.
+func murabahahSchedule(contract *entities.ProjectContract) ([]*entities.MurabahahInstallment, error) {
+	weights := make([]entities.Money, contract.InstallmentCount)
+	for i := range weights {
+		weights[i] = entities.MustParseMoney("1", contract.Currency)
+	}
+	amounts, err := entities.AllocateByWeight(contract.SalePrice(), weights)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split sale price into installments: %w", err)
+	}
+	principals, err := entities.AllocateByWeight(contract.CostPrice, amounts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split cost price across installments: %w", err)
+	}
+
+	now := time.Now()
+	installments := make([]*entities.MurabahahInstallment, len(amounts))
+	for i, amount := range amounts {
+		installments[i] = &entities.MurabahahInstallment{
+			ID:              uuid.New(),
+			ProjectID:       contract.ProjectID,
+			CooperativeID:   contract.CooperativeID,
+			Sequence:        i + 1,
+			DueDate:         contract.FirstInstallmentDate.AddDate(0, i*contract.InstallmentIntervalMonths, 0),
+			Amount:          amount,
+			PrincipalAmount: principals[i],
+			ProfitAmount:    amount.Sub(principals[i]),
+			Currency:        contract.Currency,
+			Status:          entities.MurabahahInstallmentStatusScheduled,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+	}
+
+	return installments, nil
+}
+
+func (s *projectContractService) GetContract(ctx context.Context, projectID uuid.UUID) (*entities.ProjectContract, error) {
+	contract, err := s.contractRepo.GetContract(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if contract == nil {
+		return defaultProjectContract(projectID), nil
+	}
+
+	if contract.ContractType == entities.ContractTypeMurabahah {
+		contract.Installments, err = s.contractRepo.ListInstallments(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return contract, nil
+}
+
+func (s *projectContractService) GetInstallments(ctx context.Context, projectID uuid.UUID) ([]*entities.MurabahahInstallment, error) {
+	return s.contractRepo.ListInstallments(ctx, projectID)
+}
+
+func (s *projectContractService) RecordInstallmentPayment(ctx context.Context, projectID, installmentID uuid.UUID, req *entities.RecordInstallmentPaymentRequest, userID uuid.UUID) (*entities.MurabahahInstallment, error) {
+	installment, err := s.contractRepo.GetInstallment(ctx, projectID, installmentID)
+	if err != nil {
+		return nil, err
+	}
+	if installment.Status != entities.MurabahahInstallmentStatusScheduled {
+		return nil, fmt.Errorf("installment %d is already %s", installment.Sequence, installment.Status)
+	}
+	amount, err := req.Amount.InCurrency(installment.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid installment payment: %w", err)
+	}
+	if !amount.Equal(installment.Amount) {
+		return nil, fmt.Errorf("installment %d is %s, not %s", installment.Sequence, installment.Amount, amount)
+	}
+
+	paidAt := time.Now()
+	if req.PaidAt != nil {
+		paidAt = *req.PaidAt
+	}
+	installment.Status = entities.MurabahahInstallmentStatusPaid
+	installment.PaidAt = &paidAt
+	installment.PaymentReference = req.PaymentReference
+	installment.UpdatedAt = time.Now()
+
+	updated, err := s.contractRepo.UpdateInstallment(ctx, installment, entities.MurabahahInstallmentStatusScheduled)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("installment %d was paid concurrently", installment.Sequence)
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "record_murabahah_installment_payment",
+		EntityType: entities.AuditEntityProject,
+		EntityID:   projectID,
+		NewValues:  installment,
+	})
+
+	return installment, nil
+}
+
+func (s *projectContractService) ClaimInstallmentSettlement(ctx context.Context, projectID, installmentID, distributionID uuid.UUID) (*entities.MurabahahInstallment, error) {
+	installment, err := s.contractRepo.GetInstallment(ctx, projectID, installmentID)
+	if err != nil {
+		return nil, err
+	}
+	if installment.Status != entities.MurabahahInstallmentStatusPaid {
+		return nil, fmt.Errorf("installment %d is %s and cannot be settled", installment.Sequence, installment.Status)
+	}
+
+	now := time.Now()
+	installment.Status = entities.MurabahahInstallmentStatusSettled
+	installment.ProfitDistributionID = &distributionID
+	installment.SettledAt = &now
+	installment.UpdatedAt = now
+
+	updated, err := s.contractRepo.UpdateInstallment(ctx, installment, entities.MurabahahInstallmentStatusPaid)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("installment %d was settled concurrently", installment.Sequence)
+	}
+
+	return installment, nil
+}
+
+func (s *projectContractService) ReleaseInstallmentSettlement(ctx context.Context, installment *entities.MurabahahInstallment) error {
+	installment.Status = entities.MurabahahInstallmentStatusPaid
+	installment.ProfitDistributionID = nil
+	installment.SettledAt = nil
+	installment.UpdatedAt = time.Now()
+
+	if _, err := s.contractRepo.UpdateInstallment(ctx, installment, entities.MurabahahInstallmentStatusSettled); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectContractService_SetContract_MurabahahSchedule(t *testing.T) {
+	contractRepo := new(MockProjectContractRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	contractService := NewProjectContractService(contractRepo, mockAuditService)
+	ctx := context.Background()
+
+	projectID := uuid.New()
+	firstDue := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	contractRepo.On("GetContract", ctx, projectID).Return(nil, nil)
+	contractRepo.On("SaveContract", ctx, mock.AnythingOfType("*entities.ProjectContract"), mock.Anything).Return(nil)
+
+	contract, err := contractService.SetContract(ctx, projectID, &entities.SetProjectContractRequest{
+		ContractType:         entities.ContractTypeMurabahah,
+		Currency:             "IDR",
+		AssetDescription:     "Coffee roaster",
+		CostPrice:            idr("1000000"),
+		Markup:               idr("100000"),
+		InstallmentCount:     3,
+		FirstInstallmentDate: &firstDue,
+	}, uuid.New())
+	require.NoError(t, err)
+
+	// The sale price is split evenly, the leftover minor unit going to the earliest installments
+	require.Len(t, contract.Installments, 3)
+	assert.Equal(t, idr("1100000"), contract.SalePrice())
+	assert.Equal(t, 1, contract.InstallmentIntervalMonths)
+	amounts := []string{"366666.67", "366666.67", "366666.66"}
+	principal, profit := idr("0"), idr("0")
+	for i, installment := range contract.Installments {
+		assert.Equal(t, i+1, installment.Sequence)
+		assert.Equal(t, idr(amounts[i]), installment.Amount)
+		assert.Equal(t, installment.Amount, installment.PrincipalAmount.Add(installment.ProfitAmount))
+		assert.Equal(t, entities.MurabahahInstallmentStatusScheduled, installment.Status)
+		principal = principal.Add(installment.PrincipalAmount)
+		profit = profit.Add(installment.ProfitAmount)
+	}
+	assert.Equal(t, idr("1000000"), principal)
+	assert.Equal(t, idr("100000"), profit)
+	assert.Equal(t, firstDue, contract.Installments[0].DueDate)
+	assert.Equal(t, firstDue.AddDate(0, 2, 0), contract.Installments[2].DueDate)
+}
+
+func TestProjectContractService_SetContract_RejectsInvalidTerms(t *testing.T) {
+	contractRepo := new(MockProjectContractRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	contractService := NewProjectContractService(contractRepo, mockAuditService)
+	ctx := context.Background()
+
+	projectID := uuid.New()
+	contractRepo.On("GetContract", ctx, projectID).Return(nil, nil)
+
+	testCases := []struct {
+		name string
+		req  *entities.SetProjectContractRequest
+	}{
+		{"musyarakah without partner capital", &entities.SetProjectContractRequest{ContractType: entities.ContractTypeMusyarakah, Currency: "IDR"}},
+		{"mudarabah with partner capital", &entities.SetProjectContractRequest{ContractType: entities.ContractTypeMudarabah, Currency: "IDR", PartnerCapital: idr("1000")}},
+		{"ratio not adding up", &entities.SetProjectContractRequest{ContractType: entities.ContractTypeMudarabah, Currency: "IDR", ProfitSharingRatio: map[string]float64{"investor": 70, "business": 20}}},
+		{"murabahah sharing profit", &entities.SetProjectContractRequest{ContractType: entities.ContractTypeMurabahah, Currency: "IDR", CostPrice: idr("1000"), InstallmentCount: 2, ProfitSharingRatio: map[string]float64{"investor": 100}}},
+		{"murabahah without first installment date", &entities.SetProjectContractRequest{ContractType: entities.ContractTypeMurabahah, Currency: "IDR", CostPrice: idr("1000"), InstallmentCount: 2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := contractService.SetContract(ctx, projectID, tc.req, uuid.New())
+			assert.Error(t, err)
+		})
+	}
+	contractRepo.AssertNotCalled(t, "SaveContract", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProjectContractService_RecordInstallmentPayment(t *testing.T) {
+	contractRepo := new(MockProjectContractRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	contractService := NewProjectContractService(contractRepo, mockAuditService)
+	ctx := context.Background()
+
+	installment := &entities.MurabahahInstallment{
+		ID:        uuid.New(),
+		ProjectID: uuid.New(),
+		Sequence:  1,
+		Amount:    idr("366666.67"),
+		Currency:  "IDR",
+		Status:    entities.MurabahahInstallmentStatusScheduled,
+	}
+	contractRepo.On("GetInstallment", ctx, installment.ProjectID, installment.ID).Return(installment, nil)
+	contractRepo.On("UpdateInstallment", ctx, installment, entities.MurabahahInstallmentStatusScheduled).Return(true, nil)
+
+	// Installments are paid in full
+	_, err := contractService.RecordInstallmentPayment(ctx, installment.ProjectID, installment.ID, &entities.RecordInstallmentPaymentRequest{
+		Amount:           idr("300000"),
+		PaymentReference: "TRF-001",
+	}, uuid.New())
+	assert.Error(t, err)
+
+	paid, err := contractService.RecordInstallmentPayment(ctx, installment.ProjectID, installment.ID, &entities.RecordInstallmentPaymentRequest{
+		Amount:           idr("366666.67"),
+		PaymentReference: "TRF-001",
+	}, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, entities.MurabahahInstallmentStatusPaid, paid.Status)
+	assert.NotNil(t, paid.PaidAt)
+	assert.Equal(t, "TRF-001", paid.PaymentReference)
+	contractRepo.AssertNumberOfCalls(t, "UpdateInstallment", 1)
+}
+
+func TestProfitSharingService_MusyarakahLossSharedByCapital(t *testing.T) {
+	contractRepo := new(MockProjectContractRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	contractService := NewProjectContractService(contractRepo, mockAuditService)
+	profitService := NewProfitSharingService(mockAuditService, nil, nil, nil, nil, nil, contractService, nil, nil)
+	ctx := context.Background()
+
+	projectID := uuid.New()
+	contractRepo.On("GetContract", ctx, projectID).Return(&entities.ProjectContract{
+		ProjectID:          projectID,
+		ContractType:       entities.ContractTypeMusyarakah,
+		Currency:           "IDR",
+		ProfitSharingRatio: map[string]float64{"investor": 60, "business": 35, "cooperative": 5},
+		InvestorCapital:    idr("300000"),
+		PartnerCapital:     idr("100000"),
+	}, nil)
+
+	req := &entities.CreateProfitCalculationRequest{
+		ProjectID:         projectID,
+		CalculationPeriod: entities.ProfitCalculationPeriodQuarterly,
+		StartDate:         time.Now().AddDate(0, -3, 0),
+		EndDate:           time.Now(),
+		TotalRevenue:      idr("100000"),
+		TotalExpenses:     idr("140000"),
+	}
+
+	// Investors put in three quarters of the capital and bear three quarters of the loss
+	calculation, err := profitService.CreateProfitCalculation(ctx, req, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, entities.ContractTypeMusyarakah, calculation.ContractType)
+	assert.Equal(t, idr("30000"), calculation.TotalLoss)
+	assert.Equal(t, idr("10000"), calculation.PartnerLoss)
+
+	// Profit is shared by the contract's agreed ratio
+	req.TotalRevenue, req.TotalExpenses = idr("140000"), idr("100000")
+	calculation, err = profitService.CreateProfitCalculation(ctx, req, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, idr("24000"), calculation.InvestorShare)
+	assert.Equal(t, idr("14000"), calculation.BusinessShare)
+
+	_, shares, err := profitService.CalculateShariaCompliantProfit(ctx, projectID, idr("1000"), idr("1500"))
+	require.NoError(t, err)
+	assert.Equal(t, 75.0, shares["investor"])
+	assert.Equal(t, 25.0, shares["business"])
+}
+
+func TestProfitSharingService_MurabahahHasNoProfitCalculation(t *testing.T) {
+	contractRepo := new(MockProjectContractRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	contractService := NewProjectContractService(contractRepo, mockAuditService)
+	profitService := NewProfitSharingService(mockAuditService, nil, nil, nil, nil, nil, contractService, nil, nil)
+	ctx := context.Background()
+
+	projectID := uuid.New()
+	contractRepo.On("GetContract", ctx, projectID).Return(&entities.ProjectContract{ProjectID: projectID, ContractType: entities.ContractTypeMurabahah}, nil)
+	contractRepo.On("ListInstallments", ctx, projectID).Return([]*entities.MurabahahInstallment{}, nil)
+
+	_, err := profitService.CreateProfitCalculation(ctx, &entities.CreateProfitCalculationRequest{
+		ProjectID:         projectID,
+		CalculationPeriod: entities.ProfitCalculationPeriodQuarterly,
+		StartDate:         time.Now().AddDate(0, -3, 0),
+		EndDate:           time.Now(),
+		TotalRevenue:      idr("140000"),
+		TotalExpenses:     idr("100000"),
+	}, uuid.New())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "installments")
+	mockAuditService.AssertNotCalled(t, "LogOperation", mock.Anything, mock.Anything)
+}
+
+func TestProfitSharingService_CreateProfitDistribution_InProjectCurrency(t *testing.T) {
+	contractRepo := new(MockProjectContractRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	contractService := NewProjectContractService(contractRepo, mockAuditService)
+	profitService := NewProfitSharingService(mockAuditService, nil, nil, nil, nil, nil, contractService, nil, nil)
+	ctx := context.Background()
+
+	req := &entities.CreateProfitDistributionExtendedRequest{
+		ProfitCalculationID: uuid.New(),
+		DistributionType:    entities.ProfitDistributionTypeProfit,
+		DistributionDate:    time.Now().AddDate(0, 0, 1),
+	}
+
+	contractRepo.On("GetContract", ctx, mock.Anything).Return(&entities.ProjectContract{ContractType: entities.ContractTypeMudarabah, Currency: "IDR"}, nil).Once()
+	distribution, err := profitService.CreateProfitDistribution(ctx, req, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, "IDR", distribution.Currency)
+	assert.Equal(t, idr("210000"), distribution.TotalDistributionAmount)
+
+	// A calculation kept in another currency than the project's is not distributed
+	contractRepo.On("GetContract", ctx, mock.Anything).Return(&entities.ProjectContract{ContractType: entities.ContractTypeMudarabah, Currency: "MYR"}, nil).Once()
+	_, err = profitService.CreateProfitDistribution(ctx, req, uuid.New())
+	assert.ErrorIs(t, err, entities.ErrCurrencyMismatch)
+}
+
+func TestProfitSharingService_SettleInstallment(t *testing.T) {
+	contractRepo := new(MockProjectContractRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	contractService := NewProjectContractService(contractRepo, mockAuditService)
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	profitService := NewProfitSharingService(mockAuditService, ledgerService, nil, nil, nil, nil, contractService, nil, nil)
+	ctx := context.Background()
+
+	projectID := uuid.New()
+	paidAt := time.Now()
+	installment := &entities.MurabahahInstallment{
+		ID:               uuid.New(),
+		ProjectID:        projectID,
+		CooperativeID:    uuid.New(),
+		Sequence:         2,
+		Amount:           idr("1100"),
+		PrincipalAmount:  idr("1000"),
+		ProfitAmount:     idr("100"),
+		Currency:         "IDR",
+		Status:           entities.MurabahahInstallmentStatusPaid,
+		PaidAt:           &paidAt,
+		PaymentReference: "TRF-002",
+	}
+	contractRepo.On("GetInstallment", ctx, projectID, installment.ID).Return(installment, nil)
+	contractRepo.On("UpdateInstallment", ctx, installment, entities.MurabahahInstallmentStatusPaid).Return(true, nil)
+	ledgerRepo.On("GetAccountByCode", ctx, installment.CooperativeID, mock.AnythingOfType("string"), "IDR").
+		Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}, nil)
+	var posted *entities.JournalEntry
+	ledgerRepo.On("PostEntry", ctx, mock.AnythingOfType("*entities.JournalEntry")).Run(func(args mock.Arguments) {
+		posted = args.Get(1).(*entities.JournalEntry)
+	}).Return(nil)
+
+	distribution, err := profitService.SettleInstallment(ctx, projectID, installment.ID, uuid.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.ProfitDistributionTypeInstallment, distribution.DistributionType)
+	assert.Equal(t, idr("1100"), distribution.TotalDistributionAmount)
+	assert.Equal(t, entities.MurabahahInstallmentStatusSettled, installment.Status)
+	assert.Equal(t, distribution.ID, *installment.ProfitDistributionID)
+
+	// The whole installment is credited to investors by their share of the capital
+	require.NotNil(t, posted)
+	assert.Equal(t, idr("1100"), posted.TotalAmount)
+	assert.Equal(t, idr("275"), posted.Lines[1].Credit)
+	assert.Equal(t, idr("412.50"), posted.Lines[2].Credit)
+}
+
+func TestProfitSharingService_SettleInstallment_NotPaid(t *testing.T) {
+	contractRepo := new(MockProjectContractRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	contractService := NewProjectContractService(contractRepo, mockAuditService)
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	profitService := NewProfitSharingService(mockAuditService, ledgerService, nil, nil, nil, nil, contractService, nil, nil)
+	ctx := context.Background()
+
+	projectID := uuid.New()
+	installment := &entities.MurabahahInstallment{
+		ID:            uuid.New(),
+		ProjectID:     projectID,
+		CooperativeID: uuid.New(),
+		Sequence:      2,
+		Amount:        idr("1100"),
+		Currency:      "IDR",
+		Status:        entities.MurabahahInstallmentStatusScheduled,
+	}
+	contractRepo.On("GetInstallment", ctx, projectID, installment.ID).Return(installment, nil)
+
+	_, err := profitService.SettleInstallment(ctx, projectID, installment.ID, uuid.New())
+	assert.Error(t, err)
+	contractRepo.AssertNotCalled(t, "UpdateInstallment", mock.Anything, mock.Anything, mock.Anything)
+	ledgerRepo.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything)
+}
+
+func TestProfitSharingService_SettleInstallment_ReleasedWhenLedgerFails(t *testing.T) {
+	contractRepo := new(MockProjectContractRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	contractService := NewProjectContractService(contractRepo, mockAuditService)
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	profitService := NewProfitSharingService(mockAuditService, ledgerService, nil, nil, nil, nil, contractService, nil, nil)
+	ctx := context.Background()
+
+	projectID := uuid.New()
+	paidAt := time.Now()
+	installment := &entities.MurabahahInstallment{
+		ID:               uuid.New(),
+		ProjectID:        projectID,
+		CooperativeID:    uuid.New(),
+		Sequence:         2,
+		Amount:           idr("1100"),
+		PrincipalAmount:  idr("1000"),
+		ProfitAmount:     idr("100"),
+		Currency:         "IDR",
+		Status:           entities.MurabahahInstallmentStatusPaid,
+		PaidAt:           &paidAt,
+		PaymentReference: "TRF-002",
+	}
+	contractRepo.On("GetInstallment", ctx, projectID, installment.ID).Return(installment, nil)
+	contractRepo.On("UpdateInstallment", ctx, installment, entities.MurabahahInstallmentStatusPaid).Return(true, nil)
+	contractRepo.On("UpdateInstallment", ctx, installment, entities.MurabahahInstallmentStatusSettled).Return(true, nil)
+	ledgerRepo.On("GetAccountByCode", ctx, installment.CooperativeID, mock.AnythingOfType("string"), "IDR").
+		Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}, nil)
+	ledgerRepo.On("PostEntry", ctx, mock.AnythingOfType("*entities.JournalEntry")).Return(errors.New("shard unavailable"))
+
+	_, err := profitService.SettleInstallment(ctx, projectID, installment.ID, uuid.New())
+	assert.Error(t, err)
+
+	// The installment can be settled again once the ledger is back
+	assert.Equal(t, entities.MurabahahInstallmentStatusPaid, installment.Status)
+	assert.Nil(t, installment.ProfitDistributionID)
+	contractRepo.AssertCalled(t, "UpdateInstallment", ctx, installment, entities.MurabahahInstallmentStatusSettled)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
 
 	"github.com/google/uuid"
 )
@@ -48,8 +49,8 @@ type ProjectManagementService interface {
 	
 	// FR-039: Funding Deadlines and Requirements
 	CheckFundingDeadline(ctx context.Context, projectID uuid.UUID) (bool, error)
-	ValidateMinimumFunding(ctx context.Context, projectID uuid.UUID, currentFunding float64) (bool, error)
-	UpdateFundingProgress(ctx context.Context, projectID uuid.UUID, newFunding float64) error
+	ValidateMinimumFunding(ctx context.Context, projectID uuid.UUID, currentFunding entities.Money) (bool, error)
+	UpdateFundingProgress(ctx context.Context, projectID uuid.UUID, newFunding entities.Money) error
 	MarkProjectAsFunded(ctx context.Context, projectID uuid.UUID, fundedAt time.Time) error
 	
 	// FR-040: Project Progress and Milestones
@@ -71,11 +72,15 @@ type ProjectManagementService interface {
 
 type projectManagementService struct {
 	auditService AuditService
+	fundingRepo  repositories.ProjectFundingRepository
 }
 
-func NewProjectManagementService(auditService AuditService) ProjectManagementService {
+// NewProjectManagementService creates a new project management service. Funding
+// deadlines and minimums are checked against fundingRepo when it is set.
+func NewProjectManagementService(auditService AuditService, fundingRepo repositories.ProjectFundingRepository) ProjectManagementService {
 	return &projectManagementService{
 		auditService: auditService,
+		fundingRepo:  fundingRepo,
 	}
 }
 
@@ -97,13 +102,18 @@ func (s *projectManagementService) CreateProject(ctx context.Context, req *entit
 	// Calculate project duration
 	duration := int(req.EndDate.Sub(req.StartDate).Hours() / 24)
 
+	contractType := req.ContractType
+	if contractType == "" {
+		contractType = entities.ContractTypeMudarabah
+	}
+
 	// Calculate default profit sharing terms
 	profitSharingTerms := &entities.ProfitSharingTerms{
 		InvestorShare:       60.0, // 60% for investors
 		BusinessOwnerShare:  30.0, // 30% for business owner
 		CooperativeShare:    10.0, // 10% for cooperative
 		DistributionMethod:  "quarterly",
-		MinProfitThreshold:  1000.0,
+		MinProfitThreshold:  entities.MustParseMoney("1000", req.Currency),
 		FirstDistribution:   3, // 3 months after completion
 		RiskAdjustment:      1.0,
 		CustomTerms:         make(map[string]interface{}),
@@ -119,7 +129,7 @@ func (s *projectManagementService) CreateProject(ctx context.Context, req *entit
 		Category:              req.Category,
 		FundingGoal:           req.FundingGoal,
 		Currency:              req.Currency,
-		CurrentFunding:        0.0,
+		CurrentFunding:        entities.ZeroMoney(req.Currency),
 		FundingProgress:       0.0,
 		MinFundingRequired:    req.MinFundingRequired,
 		StartDate:             req.StartDate,
@@ -127,6 +137,7 @@ func (s *projectManagementService) CreateProject(ctx context.Context, req *entit
 		Duration:              duration,
 		Timeline:              []entities.ProjectMilestone{},
 		ProfitSharingTerms:    profitSharingTerms,
+		ContractType:          contractType,
 		IntendedUseOfFunds:    req.IntendedUseOfFunds,
 		DetailedUseOfFunds:    req.DetailedUseOfFunds,
 		RiskLevel:             req.RiskLevel,
@@ -197,38 +208,38 @@ func (s *projectManagementService) CalculateProfitSharingProjection(ctx context.
 	// Mock calculation - in real implementation, would fetch project data
 	projection := &entities.ProfitSharingProjection{
 		ProjectID:          projectID,
-		TotalInvestment:    100000.0,
-		ExpectedProfit:     15000.0,
+		TotalInvestment:    entities.MustParseMoney("100000", "IDR"),
+		ExpectedProfit:     entities.MustParseMoney("15000", "IDR"),
 		ExpectedReturnRate: 15.0,
-		InvestorShare:      9000.0,  // 60% of profit
-		BusinessOwnerShare: 4500.0,  // 30% of profit
-		CooperativeShare:   1500.0,  // 10% of profit
+		InvestorShare:      entities.MustParseMoney("9000", "IDR"),  // 60% of profit
+		BusinessOwnerShare: entities.MustParseMoney("4500", "IDR"),  // 30% of profit
+		CooperativeShare:   entities.MustParseMoney("1500", "IDR"),  // 10% of profit
 		DistributionSchedule: []entities.DistributionPeriod{
 			{
 				Period:     "Q1",
 				Date:       time.Now().AddDate(0, 3, 0),
-				Amount:     2250.0,
+				Amount:     entities.MustParseMoney("2250", "IDR"),
 				Percentage: 25.0,
 				Status:     "scheduled",
 			},
 			{
 				Period:     "Q2",
 				Date:       time.Now().AddDate(0, 6, 0),
-				Amount:     2250.0,
+				Amount:     entities.MustParseMoney("2250", "IDR"),
 				Percentage: 25.0,
 				Status:     "scheduled",
 			},
 			{
 				Period:     "Q3",
 				Date:       time.Now().AddDate(0, 9, 0),
-				Amount:     2250.0,
+				Amount:     entities.MustParseMoney("2250", "IDR"),
 				Percentage: 25.0,
 				Status:     "scheduled",
 			},
 			{
 				Period:     "Q4",
 				Date:       time.Now().AddDate(0, 12, 0),
-				Amount:     2250.0,
+				Amount:     entities.MustParseMoney("2250", "IDR"),
 				Percentage: 25.0,
 				Status:     "scheduled",
 			},
@@ -298,7 +309,7 @@ func (s *projectManagementService) CreateMilestone(ctx context.Context, projectI
 		Status:      entities.MilestoneStatusPending,
 		Progress:    0.0,
 		Budget:      req.Budget,
-		Spent:       0.0,
+		Spent:       entities.ZeroMoney(req.Budget.Currency()),
 		Deliverables: req.Deliverables,
 		Notes:       req.Notes,
 		AssignedTo:  req.AssignedTo,
@@ -405,7 +416,7 @@ func (s *projectManagementService) validateProjectCreationData(req *entities.Cre
 		return fmt.Errorf("funding deadline cannot be in the past")
 	}
 
-	if req.MinFundingRequired > req.FundingGoal {
+	if req.MinFundingRequired.GreaterThan(req.FundingGoal) {
 		return fmt.Errorf("minimum funding required cannot exceed funding goal")
 	}
 
@@ -494,20 +505,52 @@ func (s *projectManagementService) ValidateProjectForInvestment(ctx context.Cont
 	return true, []string{}, nil
 }
 
+// CheckFundingDeadline reports whether the project is still within its funding deadline
 func (s *projectManagementService) CheckFundingDeadline(ctx context.Context, projectID uuid.UUID) (bool, error) {
-	return true, nil
+	if s.fundingRepo == nil {
+		return false, fmt.Errorf("not implemented - requires repository")
+	}
+
+	project, err := s.fundingRepo.GetFundingProject(ctx, projectID)
+	if err != nil {
+		return false, err
+	}
+
+	return !project.DeadlinePassed(time.Now()), nil
 }
 
-func (s *projectManagementService) ValidateMinimumFunding(ctx context.Context, projectID uuid.UUID, currentFunding float64) (bool, error) {
-	return true, nil
+// ValidateMinimumFunding reports whether currentFunding reaches the project's minimum
+func (s *projectManagementService) ValidateMinimumFunding(ctx context.Context, projectID uuid.UUID, currentFunding entities.Money) (bool, error) {
+	if s.fundingRepo == nil {
+		return false, fmt.Errorf("not implemented - requires repository")
+	}
+
+	project, err := s.fundingRepo.GetFundingProject(ctx, projectID)
+	if err != nil {
+		return false, err
+	}
+
+	return project.MeetsMinimum(currentFunding), nil
 }
 
-func (s *projectManagementService) UpdateFundingProgress(ctx context.Context, projectID uuid.UUID, newFunding float64) error {
+func (s *projectManagementService) UpdateFundingProgress(ctx context.Context, projectID uuid.UUID, newFunding entities.Money) error {
 	return fmt.Errorf("not implemented - requires repository")
 }
 
 func (s *projectManagementService) MarkProjectAsFunded(ctx context.Context, projectID uuid.UUID, fundedAt time.Time) error {
-	return fmt.Errorf("not implemented - requires repository")
+	if s.fundingRepo == nil {
+		return fmt.Errorf("not implemented - requires repository")
+	}
+
+	updated, err := s.fundingRepo.MarkFunded(ctx, projectID, fundedAt)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return fmt.Errorf("project is not raising funds")
+	}
+
+	return nil
 }
 
 func (s *projectManagementService) UpdateMilestone(ctx context.Context, milestoneID uuid.UUID, req *entities.UpdateMilestoneRequest, updaterID uuid.UUID) (*entities.ProjectMilestone, error) {
@@ -17,7 +17,7 @@ import (
 func TestProjectManagementService_CreateProject(t *testing.T) {
 	// Setup
 	mockAuditService := new(MockAuditService)
-	service := NewProjectManagementService(mockAuditService)
+	service := NewProjectManagementService(mockAuditService, nil)
 	ctx := context.Background()
 
 	// Test data
@@ -26,9 +26,9 @@ func TestProjectManagementService_CreateProject(t *testing.T) {
 		Title:                 "Test Project",
 		Description:           "A comprehensive test project for funding",
 		Category:              "technology",
-		FundingGoal:           50000.0,
+		FundingGoal:           entities.MustParseMoney("50000", "USD"),
 		Currency:              "USD",
-		MinFundingRequired:    5000.0,
+		MinFundingRequired:    entities.MustParseMoney("5000", "USD"),
 		StartDate:             time.Now(),
 		EndDate:               time.Now().AddDate(0, 6, 0),
 		IntendedUseOfFunds:    "Equipment purchase and marketing campaigns",
@@ -58,7 +58,7 @@ func TestProjectManagementService_CreateProject(t *testing.T) {
 	assert.Equal(t, req.Currency, project.Currency)
 	assert.Equal(t, ownerID, project.OwnerID)
 	assert.Equal(t, entities.ProjectExtendedStatusDraft, project.Status)
-	assert.Equal(t, 0.0, project.CurrentFunding)
+	assert.Equal(t, entities.ZeroMoney("USD"), project.CurrentFunding)
 	assert.Equal(t, 0.0, project.FundingProgress)
 	assert.False(t, project.IsFunded)
 	assert.True(t, project.IsActive)
@@ -74,7 +74,7 @@ func TestProjectManagementService_CreateProject(t *testing.T) {
 func TestProjectManagementService_CreateProject_ValidationErrors(t *testing.T) {
 	// Setup
 	mockAuditService := new(MockAuditService)
-	service := NewProjectManagementService(mockAuditService)
+	service := NewProjectManagementService(mockAuditService, nil)
 	ctx := context.Background()
 
 	// Test cases
@@ -89,9 +89,9 @@ func TestProjectManagementService_CreateProject_ValidationErrors(t *testing.T) {
 				Title:                 "Test Project",
 				Description:           "A comprehensive test project",
 				Category:              "technology",
-				FundingGoal:           50000.0,
+				FundingGoal:           entities.MustParseMoney("50000", "USD"),
 				Currency:              "USD",
-				MinFundingRequired:    5000.0,
+				MinFundingRequired:    entities.MustParseMoney("5000", "USD"),
 				StartDate:             time.Now().AddDate(0, 6, 0),
 				EndDate:               time.Now(),
 				IntendedUseOfFunds:    "Equipment purchase",
@@ -108,9 +108,9 @@ func TestProjectManagementService_CreateProject_ValidationErrors(t *testing.T) {
 				Title:                 "Test Project",
 				Description:           "A comprehensive test project",
 				Category:              "technology",
-				FundingGoal:           50000.0,
+				FundingGoal:           entities.MustParseMoney("50000", "USD"),
 				Currency:              "USD",
-				MinFundingRequired:    5000.0,
+				MinFundingRequired:    entities.MustParseMoney("5000", "USD"),
 				StartDate:             time.Now(),
 				EndDate:               time.Now().AddDate(0, 6, 0),
 				IntendedUseOfFunds:    "Equipment purchase",
@@ -127,9 +127,9 @@ func TestProjectManagementService_CreateProject_ValidationErrors(t *testing.T) {
 				Title:                 "Test Project",
 				Description:           "A comprehensive test project",
 				Category:              "technology",
-				FundingGoal:           50000.0,
+				FundingGoal:           entities.MustParseMoney("50000", "USD"),
 				Currency:              "USD",
-				MinFundingRequired:    60000.0,
+				MinFundingRequired:    entities.MustParseMoney("60000", "USD"),
 				StartDate:             time.Now(),
 				EndDate:               time.Now().AddDate(0, 6, 0),
 				IntendedUseOfFunds:    "Equipment purchase",
@@ -146,9 +146,9 @@ func TestProjectManagementService_CreateProject_ValidationErrors(t *testing.T) {
 				Title:                 "Test Project",
 				Description:           "A comprehensive test project",
 				Category:              "technology",
-				FundingGoal:           50000.0,
+				FundingGoal:           entities.MustParseMoney("50000", "USD"),
 				Currency:              "USD",
-				MinFundingRequired:    5000.0,
+				MinFundingRequired:    entities.MustParseMoney("5000", "USD"),
 				StartDate:             time.Now(),
 				EndDate:               time.Now().AddDate(0, 6, 0),
 				IntendedUseOfFunds:    "Equipment purchase",
@@ -177,7 +177,7 @@ func TestProjectManagementService_CreateProject_ValidationErrors(t *testing.T) {
 func TestProjectManagementService_ValidateIntendedUseOfFunds(t *testing.T) {
 	// Setup
 	mockAuditService := new(MockAuditService)
-	service := NewProjectManagementService(mockAuditService)
+	service := NewProjectManagementService(mockAuditService, nil)
 	ctx := context.Background()
 
 	// Test cases
@@ -242,7 +242,7 @@ func TestProjectManagementService_ValidateIntendedUseOfFunds(t *testing.T) {
 func TestProjectManagementService_CalculateProfitSharingProjection(t *testing.T) {
 	// Setup
 	mockAuditService := new(MockAuditService)
-	service := NewProjectManagementService(mockAuditService)
+	service := NewProjectManagementService(mockAuditService, nil)
 	ctx := context.Background()
 	projectID := uuid.New()
 
@@ -253,12 +253,12 @@ func TestProjectManagementService_CalculateProfitSharingProjection(t *testing.T)
 	assert.NoError(t, err)
 	assert.NotNil(t, projection)
 	assert.Equal(t, projectID, projection.ProjectID)
-	assert.Equal(t, 100000.0, projection.TotalInvestment)
-	assert.Equal(t, 15000.0, projection.ExpectedProfit)
+	assert.Equal(t, entities.NewMoney(10000000, "IDR"), projection.TotalInvestment)
+	assert.Equal(t, entities.NewMoney(1500000, "IDR"), projection.ExpectedProfit)
 	assert.Equal(t, 15.0, projection.ExpectedReturnRate)
-	assert.Equal(t, 9000.0, projection.InvestorShare)
-	assert.Equal(t, 4500.0, projection.BusinessOwnerShare)
-	assert.Equal(t, 1500.0, projection.CooperativeShare)
+	assert.Equal(t, entities.NewMoney(900000, "IDR"), projection.InvestorShare)
+	assert.Equal(t, entities.NewMoney(450000, "IDR"), projection.BusinessOwnerShare)
+	assert.Equal(t, entities.NewMoney(150000, "IDR"), projection.CooperativeShare)
 	assert.Len(t, projection.DistributionSchedule, 4)
 	assert.NotNil(t, projection.RiskFactors)
 	assert.Equal(t, "medium", projection.RiskFactors["market_risk"])
@@ -269,7 +269,7 @@ func TestProjectManagementService_CalculateProfitSharingProjection(t *testing.T)
 func TestProjectManagementService_SubmitProjectForApproval(t *testing.T) {
 	// Setup
 	mockAuditService := new(MockAuditService)
-	service := NewProjectManagementService(mockAuditService)
+	service := NewProjectManagementService(mockAuditService, nil)
 	ctx := context.Background()
 	projectID := uuid.New()
 	submitterID := uuid.New()
@@ -288,7 +288,7 @@ func TestProjectManagementService_SubmitProjectForApproval(t *testing.T) {
 func TestProjectManagementService_ApproveProject(t *testing.T) {
 	// Setup
 	mockAuditService := new(MockAuditService)
-	service := NewProjectManagementService(mockAuditService)
+	service := NewProjectManagementService(mockAuditService, nil)
 	ctx := context.Background()
 	projectID := uuid.New()
 	approverID := uuid.New()
@@ -316,7 +316,7 @@ func TestProjectManagementService_ApproveProject(t *testing.T) {
 func TestProjectManagementService_CreateMilestone(t *testing.T) {
 	// Setup
 	mockAuditService := new(MockAuditService)
-	service := NewProjectManagementService(mockAuditService)
+	service := NewProjectManagementService(mockAuditService, nil)
 	ctx := context.Background()
 	projectID := uuid.New()
 	creatorID := uuid.New()
@@ -326,7 +326,7 @@ func TestProjectManagementService_CreateMilestone(t *testing.T) {
 		Description:  "Complete initial project planning and requirements gathering",
 		Type:         "planning",
 		DueDate:      time.Now().AddDate(0, 1, 0),
-		Budget:       5000.0,
+		Budget:       entities.MustParseMoney("5000", "USD"),
 		Deliverables: []string{"Project plan", "Requirements document", "Timeline"},
 		Notes:        "Critical milestone for project success",
 		Metadata:     map[string]interface{}{"priority": "high"},
@@ -349,7 +349,7 @@ func TestProjectManagementService_CreateMilestone(t *testing.T) {
 	assert.Equal(t, entities.MilestoneStatusPending, milestone.Status)
 	assert.Equal(t, 0.0, milestone.Progress)
 	assert.Equal(t, req.Budget, milestone.Budget)
-	assert.Equal(t, 0.0, milestone.Spent)
+	assert.Equal(t, entities.ZeroMoney("USD"), milestone.Spent)
 	assert.Equal(t, req.Deliverables, milestone.Deliverables)
 	assert.Equal(t, req.Notes, milestone.Notes)
 	assert.Equal(t, req.Metadata, milestone.Metadata)
@@ -362,7 +362,7 @@ func TestProjectManagementService_CreateMilestone(t *testing.T) {
 func TestProjectManagementService_CreateProgressReport(t *testing.T) {
 	// Setup
 	mockAuditService := new(MockAuditService)
-	service := NewProjectManagementService(mockAuditService)
+	service := NewProjectManagementService(mockAuditService, nil)
 	ctx := context.Background()
 	projectID := uuid.New()
 	reporterID := uuid.New()
@@ -427,7 +427,7 @@ func TestProjectManagementService_CreateProgressReport(t *testing.T) {
 func TestProjectManagementService_GetProjectAnalytics(t *testing.T) {
 	// Setup
 	mockAuditService := new(MockAuditService)
-	service := NewProjectManagementService(mockAuditService)
+	service := NewProjectManagementService(mockAuditService, nil)
 	ctx := context.Background()
 	projectID := uuid.New()
 
@@ -478,10 +478,9 @@ func TestProjectManagementService_GetProjectAnalytics(t *testing.T) {
 func TestProjectManagementService_CompleteWorkflow(t *testing.T) {
 	// Setup
 	mockAuditService := new(MockAuditService)
-	service := NewProjectManagementService(mockAuditService)
+	service := NewProjectManagementService(mockAuditService, nil)
 	ctx := context.Background()
 	ownerID := uuid.New()
-	projectID := uuid.New()
 
 	// Mock audit service for all operations
 	mockAuditService.On("LogOperation", ctx, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
@@ -491,9 +490,9 @@ func TestProjectManagementService_CompleteWorkflow(t *testing.T) {
 		Title:                 "Complete Workflow Test Project",
 		Description:           "Testing the complete project workflow",
 		Category:              "technology",
-		FundingGoal:           100000.0,
+		FundingGoal:           entities.MustParseMoney("100000", "USD"),
 		Currency:              "USD",
-		MinFundingRequired:    10000.0,
+		MinFundingRequired:    entities.MustParseMoney("10000", "USD"),
 		StartDate:             time.Now(),
 		EndDate:               time.Now().AddDate(0, 12, 0),
 		IntendedUseOfFunds:    "Development of new software platform and marketing",
@@ -536,7 +535,7 @@ func TestProjectManagementService_CompleteWorkflow(t *testing.T) {
 		Description:  "Complete project planning phase",
 		Type:         "planning",
 		DueDate:      time.Now().AddDate(0, 1, 0),
-		Budget:       10000.0,
+		Budget:       entities.MustParseMoney("10000", "USD"),
 		Deliverables: []string{"Project plan", "Requirements doc"},
 	}
 	milestone, err := service.CreateMilestone(ctx, project.ID, milestoneReq, ownerID)
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ReinvestmentService rolls investors' profit shares into new investments
+// according to their reinvestment preferences
+type ReinvestmentService interface {
+	SetPreference(ctx context.Context, req *entities.SetReinvestmentPreferenceRequest, investorID uuid.UUID) (*entities.ReinvestmentPreference, error)
+	GetPreference(ctx context.Context, cooperativeID, investorID uuid.UUID) (*entities.ReinvestmentPreference, error)
+	RemovePreference(ctx context.Context, cooperativeID, investorID uuid.UUID) error
+
+	// ApplyReinvestments creates investments for the shares whose investors asked
+	// to reinvest and sets each share's ReinvestedAmount; the rest is paid out
+	ApplyReinvestments(ctx context.Context, distribution *entities.ProfitDistributionExtended, shares []*entities.InvestorProfitShare, processorID uuid.UUID) ([]*entities.Reinvestment, error)
+	GetInvestorReinvestments(ctx context.Context, cooperativeID, investorID uuid.UUID) ([]*entities.Reinvestment, error)
+}
+
+type reinvestmentService struct {
+	reinvestmentRepo repositories.ReinvestmentRepository
+	fundingService   InvestmentFundingService
+	policyService    InvestmentPolicyService
+	auditService     AuditService
+}
+
+// NewReinvestmentService creates a new reinvestment service. Reinvestments are
+// made through fundingService so they pass the same eligibility checks and
+// limits as any other investment.
+func NewReinvestmentService(reinvestmentRepo repositories.ReinvestmentRepository, fundingService InvestmentFundingService, policyService InvestmentPolicyService, auditService AuditService) ReinvestmentService {
+	return &reinvestmentService{
+		reinvestmentRepo: reinvestmentRepo,
+		fundingService:   fundingService,
+		policyService:    policyService,
+		auditService:     auditService,
+	}
+}
+
+// SetPreference creates or replaces the investor's preference for a cooperative
+func (s *reinvestmentService) SetPreference(ctx context.Context, req *entities.SetReinvestmentPreferenceRequest, investorID uuid.UUID) (*entities.ReinvestmentPreference, error) {
+	if req.TargetType == entities.ReinvestmentTargetProject {
+		if req.TargetProjectID == nil || *req.TargetProjectID == uuid.Nil {
+			return nil, errors.New("target project is required when reinvesting into a chosen project")
+		}
+	} else if req.TargetProjectID != nil {
+		return nil, fmt.Errorf("target project cannot be set when reinvesting into %s", req.TargetType)
+	}
+
+	allowed, maxRate, err := s.reinvestmentLimit(ctx, req.CooperativeID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.New("cooperative does not offer reinvestment of profit shares")
+	}
+	if req.Percentage > maxRate {
+		return nil, fmt.Errorf("cooperative allows at most %.2f%% of profit shares to be reinvested", maxRate)
+	}
+
+	now := time.Now()
+	preference := &entities.ReinvestmentPreference{
+		ID:              uuid.New(),
+		InvestorID:      investorID,
+		CooperativeID:   req.CooperativeID,
+		Percentage:      req.Percentage,
+		TargetType:      req.TargetType,
+		TargetProjectID: req.TargetProjectID,
+		IsActive:        true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := s.reinvestmentRepo.UpsertPreference(ctx, preference); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     investorID,
+		Operation:  "set_reinvestment_preference",
+		EntityType: "reinvestment_preference",
+		EntityID:   preference.ID,
+		NewValues:  fmt.Sprintf("Reinvest %.2f%% into %s", preference.Percentage, preference.TargetType),
+	})
+
+	return preference, nil
+}
+
+// GetPreference returns the investor's active preference, or nil when profit shares are paid out in full
+func (s *reinvestmentService) GetPreference(ctx context.Context, cooperativeID, investorID uuid.UUID) (*entities.ReinvestmentPreference, error) {
+	return s.reinvestmentRepo.GetPreference(ctx, cooperativeID, investorID)
+}
+
+// RemovePreference stops reinvesting the investor's profit shares
+func (s *reinvestmentService) RemovePreference(ctx context.Context, cooperativeID, investorID uuid.UUID) error {
+	if err := s.reinvestmentRepo.DeactivatePreference(ctx, cooperativeID, investorID); err != nil {
+		return err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     investorID,
+		Operation:  "remove_reinvestment_preference",
+		EntityType: "reinvestment_preference",
+		EntityID:   cooperativeID,
+		NewValues:  "Profit shares will be paid out in full",
+	})
+
+	return nil
+}
+
+// ApplyReinvestments reinvests each share's preferred percentage of its net
+// amount. The target is tried through the normal eligibility checks; a share
+// that cannot be reinvested anywhere is paid out in full and the reason kept.
+func (s *reinvestmentService) ApplyReinvestments(ctx context.Context, distribution *entities.ProfitDistributionExtended, shares []*entities.InvestorProfitShare, processorID uuid.UUID) ([]*entities.Reinvestment, error) {
+	preferences, err := s.reinvestmentRepo.ListActivePreferences(ctx, distribution.CooperativeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(preferences) == 0 {
+		return nil, nil
+	}
+
+	allowed, maxRate, err := s.reinvestmentLimit(ctx, distribution.CooperativeID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, nil
+	}
+
+	byInvestor := make(map[uuid.UUID]*entities.ReinvestmentPreference, len(preferences))
+	for _, preference := range preferences {
+		byInvestor[preference.InvestorID] = preference
+	}
+
+	var openProjects []uuid.UUID
+	openProjectsLoaded := false
+
+	var reinvestments []*entities.Reinvestment
+	for _, share := range shares {
+		preference, ok := byInvestor[share.InvestorID]
+		if !ok {
+			continue
+		}
+
+		// A rate lowered by the cooperative after the preference was set still applies
+		rate := preference.Percentage
+		if rate > maxRate {
+			rate = maxRate
+		}
+		amount := share.NetProfitShare.Percent(rate, entities.RoundDown)
+		if !amount.IsPositive() {
+			continue
+		}
+
+		var candidates []uuid.UUID
+		switch preference.TargetType {
+		case entities.ReinvestmentTargetSameProject:
+			candidates = []uuid.UUID{distribution.ProjectID}
+		case entities.ReinvestmentTargetProject:
+			candidates = []uuid.UUID{*preference.TargetProjectID}
+		case entities.ReinvestmentTargetAnyEligible:
+			if !openProjectsLoaded {
+				if openProjects, err = s.reinvestmentRepo.ListOpenProjects(ctx, distribution.CooperativeID); err != nil {
+					return nil, err
+				}
+				openProjectsLoaded = true
+			}
+			candidates = openProjects
+		}
+
+		reinvestment, err := s.reinvest(ctx, distribution, share, preference, amount, candidates)
+		if err != nil {
+			return nil, err
+		}
+		if reinvestment.Status == entities.ReinvestmentStatusInvested {
+			share.ReinvestedAmount = amount
+		}
+		reinvestments = append(reinvestments, reinvestment)
+	}
+
+	if err := s.reinvestmentRepo.CreateReinvestments(ctx, reinvestments); err != nil {
+		return nil, err
+	}
+
+	invested := 0
+	for _, reinvestment := range reinvestments {
+		if reinvestment.Status == entities.ReinvestmentStatusInvested {
+			invested++
+		}
+	}
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     processorID,
+		Operation:  "apply_reinvestments",
+		EntityType: "profit_distribution",
+		EntityID:   distribution.ID,
+		NewValues:  fmt.Sprintf("Reinvested %d of %d profit shares", invested, len(reinvestments)),
+	})
+
+	return reinvestments, nil
+}
+
+// reinvest invests amount in the first candidate project the investor is eligible for
+func (s *reinvestmentService) reinvest(ctx context.Context, distribution *entities.ProfitDistributionExtended, share *entities.InvestorProfitShare, preference *entities.ReinvestmentPreference, amount entities.Money, candidates []uuid.UUID) (*entities.Reinvestment, error) {
+	reinvestment := &entities.Reinvestment{
+		ID:                   uuid.New(),
+		PreferenceID:         preference.ID,
+		ProfitDistributionID: distribution.ID,
+		ProfitShareID:        share.ID,
+		InvestorID:           share.InvestorID,
+		CooperativeID:        distribution.CooperativeID,
+		SourceProjectID:      distribution.ProjectID,
+		Amount:               amount,
+		Currency:             amount.Currency(),
+		Status:               entities.ReinvestmentStatusSkipped,
+		CreatedAt:            time.Now(),
+	}
+
+	if len(candidates) == 0 {
+		reinvestment.Reason = "no open project to reinvest in"
+		return reinvestment, nil
+	}
+
+	var reasons []string
+	for _, projectID := range candidates {
+		eligibility, err := s.fundingService.ValidateInvestmentEligibility(ctx, share.InvestorID, projectID, amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate reinvestment eligibility: %w", err)
+		}
+		if !eligibility.IsEligible {
+			reasons = append(reasons, fmt.Sprintf("project %s: %s", projectID, strings.Join(eligibility.Reasons, ", ")))
+			continue
+		}
+
+		investment, err := s.fundingService.CreateInvestment(ctx, &entities.CreateInvestmentExtendedRequest{
+			ProjectID:      projectID,
+			CooperativeID:  distribution.CooperativeID,
+			Amount:         amount,
+			Currency:       amount.Currency(),
+			InvestmentType: "partial",
+		}, share.InvestorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create reinvestment: %w", err)
+		}
+
+		targetProjectID, investmentID := projectID, investment.ID
+		reinvestment.TargetProjectID = &targetProjectID
+		reinvestment.InvestmentID = &investmentID
+		reinvestment.Status = entities.ReinvestmentStatusInvested
+		return reinvestment, nil
+	}
+
+	if len(candidates) == 1 {
+		reinvestment.TargetProjectID = &candidates[0]
+	}
+	reinvestment.Reason = "not eligible: " + strings.Join(reasons, "; ")
+	return reinvestment, nil
+}
+
+// GetInvestorReinvestments lists the investor's reinvestments in a cooperative, newest first
+func (s *reinvestmentService) GetInvestorReinvestments(ctx context.Context, cooperativeID, investorID uuid.UUID) ([]*entities.Reinvestment, error) {
+	return s.reinvestmentRepo.ListInvestorReinvestments(ctx, cooperativeID, investorID)
+}
+
+// reinvestmentLimit reports whether the cooperative's active profit sharing rules
+// allow reinvestment and the largest percentage that may be reinvested. Without
+// any rules the investor's own preference applies unchanged.
+func (s *reinvestmentService) reinvestmentLimit(ctx context.Context, cooperativeID uuid.UUID) (bool, float64, error) {
+	if s.policyService == nil {
+		return true, 100, nil
+	}
+
+	rules, err := s.policyService.GetActiveProfitSharingRules(ctx, cooperativeID)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to get profit sharing rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return true, 100, nil
+	}
+
+	for _, rule := range rules {
+		if !rule.ReinvestmentOption {
+			continue
+		}
+		if rule.ReinvestmentRate > 0 && rule.ReinvestmentRate < 100 {
+			return true, rule.ReinvestmentRate, nil
+		}
+		return true, 100, nil
+	}
+
+	return false, 0, nil
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// profitSharingRulesPolicy serves fixed profit sharing rules
+type profitSharingRulesPolicy struct {
+	InvestmentPolicyService
+	rules []*entities.ProfitSharingRulesExtended
+}
+
+func (p *profitSharingRulesPolicy) GetActiveProfitSharingRules(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.ProfitSharingRulesExtended, error) {
+	return p.rules, nil
+}
+
+func TestReinvestmentService_SetPreference(t *testing.T) {
+	targetProjectID := uuid.New()
+	offered := &entities.ProfitSharingRulesExtended{ReinvestmentOption: true, ReinvestmentRate: 50, IsActive: true}
+
+	testCases := []struct {
+		name        string
+		rules       []*entities.ProfitSharingRulesExtended
+		req         *entities.SetReinvestmentPreferenceRequest
+		expectedErr string
+	}{
+		{
+			name:        "Chosen project without a target",
+			req:         &entities.SetReinvestmentPreferenceRequest{Percentage: 50, TargetType: entities.ReinvestmentTargetProject},
+			expectedErr: "target project is required",
+		},
+		{
+			name:        "Target set for any eligible project",
+			req:         &entities.SetReinvestmentPreferenceRequest{Percentage: 50, TargetType: entities.ReinvestmentTargetAnyEligible, TargetProjectID: &targetProjectID},
+			expectedErr: "target project cannot be set",
+		},
+		{
+			name:        "Cooperative does not offer reinvestment",
+			rules:       []*entities.ProfitSharingRulesExtended{{IsActive: true}},
+			req:         &entities.SetReinvestmentPreferenceRequest{Percentage: 60, TargetType: entities.ReinvestmentTargetSameProject},
+			expectedErr: "does not offer reinvestment",
+		},
+		{
+			name:        "Above the cooperative's rate",
+			rules:       []*entities.ProfitSharingRulesExtended{offered},
+			req:         &entities.SetReinvestmentPreferenceRequest{Percentage: 60, TargetType: entities.ReinvestmentTargetSameProject},
+			expectedErr: "at most 50.00%",
+		},
+		{
+			name:  "Within the cooperative's rate",
+			rules: []*entities.ProfitSharingRulesExtended{offered},
+			req:   &entities.SetReinvestmentPreferenceRequest{Percentage: 50, TargetType: entities.ReinvestmentTargetSameProject},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAuditService := new(MockAuditService)
+			mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+			reinvestmentRepo := new(MockReinvestmentRepository)
+			policyService := &profitSharingRulesPolicy{InvestmentPolicyService: NewInvestmentPolicyService(mockAuditService, nil), rules: tc.rules}
+			reinvestmentService := NewReinvestmentService(reinvestmentRepo, NewInvestmentFundingService(mockAuditService, nil, nil, nil, nil, nil, 0), policyService, mockAuditService)
+			ctx := context.Background()
+
+			tc.req.CooperativeID = uuid.New()
+			investorID := uuid.New()
+			reinvestmentRepo.On("UpsertPreference", ctx, mock.AnythingOfType("*entities.ReinvestmentPreference")).Return(nil)
+
+			preference, err := reinvestmentService.SetPreference(ctx, tc.req, investorID)
+
+			if tc.expectedErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				reinvestmentRepo.AssertNotCalled(t, "UpsertPreference", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, investorID, preference.InvestorID)
+			assert.Equal(t, tc.req.Percentage, preference.Percentage)
+			assert.True(t, preference.IsActive)
+		})
+	}
+}
+
+func TestReinvestmentService_ApplyReinvestments_SameProject(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	reinvestmentRepo := new(MockReinvestmentRepository)
+	policyService := &profitSharingRulesPolicy{InvestmentPolicyService: NewInvestmentPolicyService(mockAuditService, nil)}
+	reinvestmentService := NewReinvestmentService(reinvestmentRepo, NewInvestmentFundingService(mockAuditService, nil, nil, nil, nil, nil, 0), policyService, mockAuditService)
+	ctx := context.Background()
+
+	distribution := &entities.ProfitDistributionExtended{ID: uuid.New(), ProjectID: uuid.New(), CooperativeID: uuid.New(), Currency: "IDR"}
+	reinvesting := &entities.InvestorProfitShare{ID: uuid.New(), ProfitDistributionID: distribution.ID, InvestmentID: uuid.New(), InvestorID: uuid.New(),
+		NetProfitShare: idr("4725.55"), ReinvestedAmount: idr("0"), Status: entities.InvestorProfitShareStatusPending}
+	cashOnly := &entities.InvestorProfitShare{ID: uuid.New(), ProfitDistributionID: distribution.ID, InvestmentID: uuid.New(), InvestorID: uuid.New(),
+		NetProfitShare: idr("7087.50"), ReinvestedAmount: idr("0"), Status: entities.InvestorProfitShareStatusPending}
+	shares := []*entities.InvestorProfitShare{reinvesting, cashOnly}
+
+	reinvestmentRepo.On("ListActivePreferences", ctx, distribution.CooperativeID).Return([]*entities.ReinvestmentPreference{
+		{ID: uuid.New(), InvestorID: reinvesting.InvestorID, CooperativeID: distribution.CooperativeID, Percentage: 33.33,
+			TargetType: entities.ReinvestmentTargetSameProject, IsActive: true},
+	}, nil)
+	reinvestmentRepo.On("CreateReinvestments", ctx, mock.AnythingOfType("[]*entities.Reinvestment")).Return(nil)
+
+	reinvestments, err := reinvestmentService.ApplyReinvestments(ctx, distribution, shares, uuid.New())
+
+	require.NoError(t, err)
+	require.Len(t, reinvestments, 1)
+	reinvestment := reinvestments[0]
+	assert.Equal(t, entities.ReinvestmentStatusInvested, reinvestment.Status)
+	assert.Equal(t, reinvesting.ID, reinvestment.ProfitShareID)
+	assert.Equal(t, distribution.ProjectID, *reinvestment.TargetProjectID)
+	assert.NotNil(t, reinvestment.InvestmentID)
+
+	// 33.33% of 4725.55 is 1575.0258..., rounded down to the sen
+	assert.Equal(t, idr("1575.02"), reinvestment.Amount)
+	assert.Equal(t, idr("1575.02"), reinvesting.ReinvestedAmount)
+	assert.Equal(t, idr("3150.53"), reinvesting.PayableAmount())
+	assert.Equal(t, idr("7087.50"), cashOnly.PayableAmount())
+	reinvestmentRepo.AssertCalled(t, "CreateReinvestments", ctx, reinvestments)
+}
+
+func TestReinvestmentService_ApplyReinvestments_Skipped(t *testing.T) {
+	targetProjectID := uuid.New()
+
+	testCases := []struct {
+		name            string
+		netProfitShare  string
+		targetType      string
+		targetProjectID *uuid.UUID
+		openProjects    []uuid.UUID
+		reason          string
+	}{
+		{
+			// 8000 is above the project's maximum investment
+			name:            "Ineligible investment paid out",
+			netProfitShare:  "8000",
+			targetType:      entities.ReinvestmentTargetProject,
+			targetProjectID: &targetProjectID,
+			reason:          "investment amount outside allowed range",
+		},
+		{
+			name:           "No open project",
+			netProfitShare: "1000",
+			targetType:     entities.ReinvestmentTargetAnyEligible,
+			openProjects:   []uuid.UUID{},
+			reason:         "no open project to reinvest in",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAuditService := new(MockAuditService)
+			mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+			reinvestmentRepo := new(MockReinvestmentRepository)
+			policyService := &profitSharingRulesPolicy{InvestmentPolicyService: NewInvestmentPolicyService(mockAuditService, nil)}
+			reinvestmentService := NewReinvestmentService(reinvestmentRepo, NewInvestmentFundingService(mockAuditService, nil, nil, nil, nil, nil, 0), policyService, mockAuditService)
+			ctx := context.Background()
+
+			distribution := &entities.ProfitDistributionExtended{ID: uuid.New(), ProjectID: uuid.New(), CooperativeID: uuid.New(), Currency: "IDR"}
+			share := &entities.InvestorProfitShare{ID: uuid.New(), ProfitDistributionID: distribution.ID, InvestmentID: uuid.New(), InvestorID: uuid.New(),
+				NetProfitShare: idr(tc.netProfitShare), ReinvestedAmount: idr("0"), Status: entities.InvestorProfitShareStatusPending}
+			reinvestmentRepo.On("ListActivePreferences", ctx, distribution.CooperativeID).Return([]*entities.ReinvestmentPreference{
+				{ID: uuid.New(), InvestorID: share.InvestorID, CooperativeID: distribution.CooperativeID, Percentage: 100,
+					TargetType: tc.targetType, TargetProjectID: tc.targetProjectID, IsActive: true},
+			}, nil)
+			reinvestmentRepo.On("ListOpenProjects", ctx, distribution.CooperativeID).Return(tc.openProjects, nil)
+			reinvestmentRepo.On("CreateReinvestments", ctx, mock.AnythingOfType("[]*entities.Reinvestment")).Return(nil)
+
+			reinvestments, err := reinvestmentService.ApplyReinvestments(ctx, distribution, []*entities.InvestorProfitShare{share}, uuid.New())
+
+			// The whole share is paid out instead
+			require.NoError(t, err)
+			require.Len(t, reinvestments, 1)
+			assert.Equal(t, entities.ReinvestmentStatusSkipped, reinvestments[0].Status)
+			assert.Contains(t, reinvestments[0].Reason, tc.reason)
+			assert.Nil(t, reinvestments[0].InvestmentID)
+			if tc.targetProjectID != nil {
+				assert.Equal(t, *tc.targetProjectID, *reinvestments[0].TargetProjectID)
+			}
+			assert.True(t, share.ReinvestedAmount.IsZero())
+			assert.Equal(t, idr(tc.netProfitShare), share.PayableAmount())
+		})
+	}
+}
+
+func TestReinvestmentService_ApplyReinvestments_AnyEligibleProject(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	reinvestmentRepo := new(MockReinvestmentRepository)
+	policyService := &profitSharingRulesPolicy{
+		InvestmentPolicyService: NewInvestmentPolicyService(mockAuditService, nil),
+		rules:                   []*entities.ProfitSharingRulesExtended{{ReinvestmentOption: true, ReinvestmentRate: 40, IsActive: true}},
+	}
+	reinvestmentService := NewReinvestmentService(reinvestmentRepo, NewInvestmentFundingService(mockAuditService, nil, nil, nil, nil, nil, 0), policyService, mockAuditService)
+	ctx := context.Background()
+
+	distribution := &entities.ProfitDistributionExtended{ID: uuid.New(), ProjectID: uuid.New(), CooperativeID: uuid.New(), Currency: "IDR"}
+	first := &entities.InvestorProfitShare{ID: uuid.New(), ProfitDistributionID: distribution.ID, InvestmentID: uuid.New(), InvestorID: uuid.New(),
+		NetProfitShare: idr("5000"), ReinvestedAmount: idr("0"), Status: entities.InvestorProfitShareStatusPending}
+	second := &entities.InvestorProfitShare{ID: uuid.New(), ProfitDistributionID: distribution.ID, InvestmentID: uuid.New(), InvestorID: uuid.New(),
+		NetProfitShare: idr("2000"), ReinvestedAmount: idr("0"), Status: entities.InvestorProfitShareStatusPending}
+	openProjectID := uuid.New()
+
+	reinvestmentRepo.On("ListActivePreferences", ctx, distribution.CooperativeID).Return([]*entities.ReinvestmentPreference{
+		{ID: uuid.New(), InvestorID: first.InvestorID, CooperativeID: distribution.CooperativeID, Percentage: 80, TargetType: entities.ReinvestmentTargetAnyEligible, IsActive: true},
+		{ID: uuid.New(), InvestorID: second.InvestorID, CooperativeID: distribution.CooperativeID, Percentage: 25, TargetType: entities.ReinvestmentTargetAnyEligible, IsActive: true},
+	}, nil)
+	reinvestmentRepo.On("ListOpenProjects", ctx, distribution.CooperativeID).Return([]uuid.UUID{openProjectID}, nil).Once()
+	reinvestmentRepo.On("CreateReinvestments", ctx, mock.AnythingOfType("[]*entities.Reinvestment")).Return(nil)
+
+	reinvestments, err := reinvestmentService.ApplyReinvestments(ctx, distribution, []*entities.InvestorProfitShare{first, second}, uuid.New())
+
+	require.NoError(t, err)
+	require.Len(t, reinvestments, 2)
+	for _, reinvestment := range reinvestments {
+		assert.Equal(t, entities.ReinvestmentStatusInvested, reinvestment.Status)
+		assert.Equal(t, openProjectID, *reinvestment.TargetProjectID)
+	}
+
+	// The cooperative's 40% limit applies to a preference set before it was lowered
+	assert.Equal(t, idr("2000"), first.ReinvestedAmount)
+	assert.Equal(t, idr("500"), second.ReinvestedAmount)
+	reinvestmentRepo.AssertNumberOfCalls(t, "ListOpenProjects", 1)
+}
+
+func TestReinvestmentService_ApplyReinvestments_WithoutPreferences(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	reinvestmentRepo := new(MockReinvestmentRepository)
+	policyService := &profitSharingRulesPolicy{InvestmentPolicyService: NewInvestmentPolicyService(mockAuditService, nil)}
+	reinvestmentService := NewReinvestmentService(reinvestmentRepo, NewInvestmentFundingService(mockAuditService, nil, nil, nil, nil, nil, 0), policyService, mockAuditService)
+	ctx := context.Background()
+
+	distribution := &entities.ProfitDistributionExtended{ID: uuid.New(), ProjectID: uuid.New(), CooperativeID: uuid.New(), Currency: "IDR"}
+	share := &entities.InvestorProfitShare{ID: uuid.New(), ProfitDistributionID: distribution.ID, InvestmentID: uuid.New(), InvestorID: uuid.New(),
+		NetProfitShare: idr("1000"), ReinvestedAmount: idr("0"), Status: entities.InvestorProfitShareStatusPending}
+	reinvestmentRepo.On("ListActivePreferences", ctx, distribution.CooperativeID).Return(nil, nil)
+
+	reinvestments, err := reinvestmentService.ApplyReinvestments(ctx, distribution, []*entities.InvestorProfitShare{share}, uuid.New())
+
+	require.NoError(t, err)
+	assert.Empty(t, reinvestments)
+	assert.Equal(t, idr("1000"), share.PayableAmount())
+	reinvestmentRepo.AssertNotCalled(t, "CreateReinvestments", mock.Anything, mock.Anything)
+}
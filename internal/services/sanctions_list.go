@@ -0,0 +1,341 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"comfunds/internal/entities"
+)
+
+// SanctionsListSource is a watchlist file that ops refresh from the
+// government's published list
+type SanctionsListSource struct {
+	Name   string
+	Path   string
+	Format string // csv, xml
+}
+
+// NewSanctionsListSources parses a comma-separated list of watchlist files,
+// each given as path or name=path. The format follows the file extension and
+// the name defaults to the file name without it.
+func NewSanctionsListSources(spec string) ([]SanctionsListSource, error) {
+	var sources []SanctionsListSource
+	names := make(map[string]bool)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		source := SanctionsListSource{Path: item}
+		if name, path, ok := strings.Cut(item, "="); ok {
+			source.Name, source.Path = strings.TrimSpace(name), strings.TrimSpace(path)
+		}
+
+		ext := strings.ToLower(filepath.Ext(source.Path))
+		switch ext {
+		case ".csv":
+			source.Format = entities.SanctionsListFormatCSV
+		case ".xml":
+			source.Format = entities.SanctionsListFormatXML
+		default:
+			return nil, fmt.Errorf("sanctions list must be a .csv or .xml file: %s", source.Path)
+		}
+		if source.Name == "" {
+			source.Name = strings.TrimSuffix(filepath.Base(source.Path), filepath.Ext(source.Path))
+		}
+
+		if names[source.Name] {
+			return nil, fmt.Errorf("duplicate sanctions list name: %s", source.Name)
+		}
+		names[source.Name] = true
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
+// loadSanctionsList reads a watchlist file's entries. A file without entries
+// is an error so that a truncated download does not empty the list.
+func loadSanctionsList(source SanctionsListSource) ([]*entities.SanctionsListEntry, error) {
+	file, err := os.Open(source.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sanctions list: %w", err)
+	}
+	defer file.Close()
+
+	var entries []*entities.SanctionsListEntry
+	switch source.Format {
+	case entities.SanctionsListFormatCSV:
+		entries, err = parseSanctionsCSV(file, source.Name)
+	case entities.SanctionsListFormatXML:
+		entries, err = parseSanctionsXML(file, source.Name)
+	default:
+		err = fmt.Errorf("unsupported sanctions list format: %s", source.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("sanctions list %s has no entries", source.Name)
+	}
+
+	return entries, nil
+}
+
+// parseSanctionsCSV reads a CSV watchlist with a header row naming its
+// columns: name is required; id, type (individual or entity), aliases and
+// dates_of_birth (both separated by semicolons), nationality and program are
+// optional. Dates of birth are YYYY-MM-DD, YYYY-MM or YYYY.
+func parseSanctionsCSV(r io.Reader, listName string) ([]*entities.SanctionsListEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sanctions list header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("sanctions list has no name column")
+	}
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var entries []*entities.SanctionsListEntry
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sanctions list line %d: %w", line, err)
+		}
+
+		entry := &entities.SanctionsListEntry{
+			ListName:    listName,
+			EntryID:     field(record, "id"),
+			Name:        field(record, "name"),
+			Nationality: field(record, "nationality"),
+			Program:     field(record, "program"),
+		}
+		if entry.Name == "" {
+			continue
+		}
+		if entry.EntryID == "" {
+			entry.EntryID = strconv.Itoa(line)
+		}
+
+		switch entryType := strings.ToLower(field(record, "type")); entryType {
+		case "", entities.SanctionsEntryTypeIndividual, entities.SanctionsEntryTypeEntity:
+			entry.EntryType = entryType
+		default:
+			return nil, fmt.Errorf("sanctions list line %d: unknown entry type %q", line, entryType)
+		}
+
+		for _, alias := range strings.Split(field(record, "aliases"), ";") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				entry.Aliases = append(entry.Aliases, alias)
+			}
+		}
+		for _, value := range strings.Split(field(record, "dates_of_birth"), ";") {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			date, err := parseSanctionsBirthDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("sanctions list line %d: %w", line, err)
+			}
+			entry.BirthDates = append(entry.BirthDates, date)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// parseSanctionsBirthDate parses a date of birth given as YYYY-MM-DD, YYYY-MM
+// or YYYY
+func parseSanctionsBirthDate(value string) (entities.SanctionsBirthDate, error) {
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+
+		date := entities.SanctionsBirthDate{Year: t.Year()}
+		if len(layout) >= len("2006-01") {
+			date.Month = int(t.Month())
+		}
+		if len(layout) == len("2006-01-02") {
+			date.Day = t.Day()
+		}
+		return date, nil
+	}
+	return entities.SanctionsBirthDate{}, fmt.Errorf("invalid date of birth %q", value)
+}
+
+// unConsolidatedList is the XML layout of the UN Security Council consolidated
+// list, which national lists such as DTTOT are also published in
+type unConsolidatedList struct {
+	Individuals []unListIndividual `xml:"INDIVIDUALS>INDIVIDUAL"`
+	Entities    []unListEntity     `xml:"ENTITIES>ENTITY"`
+}
+
+type unListIndividual struct {
+	DataID          string            `xml:"DATAID"`
+	ReferenceNumber string            `xml:"REFERENCE_NUMBER"`
+	FirstName       string            `xml:"FIRST_NAME"`
+	SecondName      string            `xml:"SECOND_NAME"`
+	ThirdName       string            `xml:"THIRD_NAME"`
+	FourthName      string            `xml:"FOURTH_NAME"`
+	ListType        string            `xml:"UN_LIST_TYPE"`
+	Nationalities   []string          `xml:"NATIONALITY>VALUE"`
+	Aliases         []unListAlias     `xml:"INDIVIDUAL_ALIAS"`
+	BirthDates      []unListBirthDate `xml:"INDIVIDUAL_DATE_OF_BIRTH"`
+}
+
+type unListEntity struct {
+	DataID          string        `xml:"DATAID"`
+	ReferenceNumber string        `xml:"REFERENCE_NUMBER"`
+	Name            string        `xml:"FIRST_NAME"`
+	ListType        string        `xml:"UN_LIST_TYPE"`
+	Aliases         []unListAlias `xml:"ENTITY_ALIAS"`
+}
+
+type unListAlias struct {
+	Name string `xml:"ALIAS_NAME"`
+}
+
+type unListBirthDate struct {
+	Date     string `xml:"DATE"`
+	Year     string `xml:"YEAR"`
+	FromYear string `xml:"FROM_YEAR"`
+	ToYear   string `xml:"TO_YEAR"`
+}
+
+// parseSanctionsXML reads a watchlist in the UN consolidated list layout
+func parseSanctionsXML(r io.Reader, listName string) ([]*entities.SanctionsListEntry, error) {
+	var list unConsolidatedList
+	if err := xml.NewDecoder(r).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to parse sanctions list XML: %w", err)
+	}
+
+	var entries []*entities.SanctionsListEntry
+	for _, individual := range list.Individuals {
+		name := strings.Join(strings.Fields(strings.Join([]string{
+			individual.FirstName, individual.SecondName, individual.ThirdName, individual.FourthName,
+		}, " ")), " ")
+		if name == "" {
+			continue
+		}
+
+		entry := &entities.SanctionsListEntry{
+			ListName:    listName,
+			EntryID:     unListEntryID(individual.ReferenceNumber, individual.DataID),
+			EntryType:   entities.SanctionsEntryTypeIndividual,
+			Name:        name,
+			Aliases:     unListAliasNames(individual.Aliases),
+			Nationality: strings.Join(individual.Nationalities, ", "),
+			Program:     strings.TrimSpace(individual.ListType),
+		}
+		for _, birthDate := range individual.BirthDates {
+			dates, err := unListBirthDates(birthDate)
+			if err != nil {
+				return nil, fmt.Errorf("sanctions list entry %s: %w", entry.EntryID, err)
+			}
+			entry.BirthDates = append(entry.BirthDates, dates...)
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, entity := range list.Entities {
+		name := strings.TrimSpace(entity.Name)
+		if name == "" {
+			continue
+		}
+
+		entries = append(entries, &entities.SanctionsListEntry{
+			ListName:  listName,
+			EntryID:   unListEntryID(entity.ReferenceNumber, entity.DataID),
+			EntryType: entities.SanctionsEntryTypeEntity,
+			Name:      name,
+			Aliases:   unListAliasNames(entity.Aliases),
+			Program:   strings.TrimSpace(entity.ListType),
+		})
+	}
+
+	return entries, nil
+}
+
+func unListEntryID(referenceNumber, dataID string) string {
+	if id := strings.TrimSpace(referenceNumber); id != "" {
+		return id
+	}
+	return strings.TrimSpace(dataID)
+}
+
+func unListAliasNames(aliases []unListAlias) []string {
+	var names []string
+	for _, alias := range aliases {
+		if name := strings.TrimSpace(alias.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// maxSanctionsBirthYearRange bounds how many years a FROM_YEAR to TO_YEAR
+// range expands to
+const maxSanctionsBirthYearRange = 20
+
+// unListBirthDates reads an exact date, a year or a range of years
+func unListBirthDates(birthDate unListBirthDate) ([]entities.SanctionsBirthDate, error) {
+	if value := strings.TrimSpace(birthDate.Date); value != "" {
+		date, err := parseSanctionsBirthDate(value)
+		if err != nil {
+			return nil, err
+		}
+		return []entities.SanctionsBirthDate{date}, nil
+	}
+	if value := strings.TrimSpace(birthDate.Year); value != "" {
+		date, err := parseSanctionsBirthDate(value)
+		if err != nil {
+			return nil, err
+		}
+		return []entities.SanctionsBirthDate{date}, nil
+	}
+
+	from, fromErr := strconv.Atoi(strings.TrimSpace(birthDate.FromYear))
+	to, toErr := strconv.Atoi(strings.TrimSpace(birthDate.ToYear))
+	if fromErr != nil || toErr != nil || to < from || to-from > maxSanctionsBirthYearRange {
+		return nil, nil
+	}
+
+	var dates []entities.SanctionsBirthDate
+	for year := from; year <= to; year++ {
+		dates = append(dates, entities.SanctionsBirthDate{Year: year})
+	}
+	return dates, nil
+}
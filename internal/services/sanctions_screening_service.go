@@ -0,0 +1,534 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// SanctionsMatchThresholds govern how closely a subject must resemble a list
+// entry to be held for review
+type SanctionsMatchThresholds struct {
+	// NameScore is the least name similarity, from 0 to 1, for a match whose
+	// date of birth agrees with the entry's
+	NameScore float64
+	// NameOnlyScore is the least name similarity for a match when dates of
+	// birth cannot be compared
+	NameOnlyScore float64
+	// BirthYearTolerance is how many years apart dates of birth may be and
+	// still agree
+	BirthYearTolerance int
+}
+
+// NewSanctionsMatchThresholds parses the name similarity thresholds, with and
+// without a corroborating date of birth, and the birth year tolerance
+func NewSanctionsMatchThresholds(nameScore, nameOnlyScore, birthYearTolerance string) (SanctionsMatchThresholds, error) {
+	score, err := strconv.ParseFloat(nameScore, 64)
+	if err != nil || score <= 0 || score > 1 {
+		return SanctionsMatchThresholds{}, fmt.Errorf("sanctions name score must be above 0 and at most 1: %q", nameScore)
+	}
+
+	onlyScore, err := strconv.ParseFloat(nameOnlyScore, 64)
+	if err != nil || onlyScore < score || onlyScore > 1 {
+		return SanctionsMatchThresholds{}, fmt.Errorf("sanctions name-only score must be between the name score and 1: %q", nameOnlyScore)
+	}
+
+	years, err := strconv.Atoi(birthYearTolerance)
+	if err != nil || years < 0 {
+		return SanctionsMatchThresholds{}, fmt.Errorf("sanctions birth year tolerance must be a number of years: %q", birthYearTolerance)
+	}
+
+	return SanctionsMatchThresholds{NameScore: score, NameOnlyScore: onlyScore, BirthYearTolerance: years}, nil
+}
+
+// SanctionsScreeningService screens members, businesses and payout
+// beneficiaries against government watchlists loaded from local files.
+// Potential matches hold the action being screened until a compliance officer
+// clears or confirms them.
+type SanctionsScreeningService interface {
+	// ScreenSubject matches the subject against the loaded lists and records
+	// the screening. Matches a reviewer has cleared for the subject are not
+	// raised again; while a screening of the subject is pending review, or an
+	// entry it matches has been confirmed, that screening is returned instead.
+	ScreenSubject(ctx context.Context, subject *entities.SanctionsScreeningSubject) (*entities.SanctionsScreening, error)
+
+	// Lists
+	// ReloadLists re-reads the list files that changed since they were last
+	// loaded; a list that fails to load keeps its previous entries
+	ReloadLists(ctx context.Context) []*entities.SanctionsList
+	GetLists(ctx context.Context) []*entities.SanctionsList
+
+	// Reviews
+	GetScreening(ctx context.Context, screeningID uuid.UUID) (*entities.SanctionsScreening, error)
+	ListScreenings(ctx context.Context, filter *entities.SanctionsScreeningFilter) ([]*entities.SanctionsScreening, int, error)
+	ReviewScreening(ctx context.Context, screeningID uuid.UUID, req *entities.ReviewSanctionsScreeningRequest, reviewerID uuid.UUID) (*entities.SanctionsScreening, error)
+}
+
+// sanctionsScreeningService implements SanctionsScreeningService
+type sanctionsScreeningService struct {
+	screeningRepo repositories.SanctionsScreeningRepository
+	auditService  AuditService
+	sources       []SanctionsListSource
+	thresholds    SanctionsMatchThresholds
+
+	mu      sync.RWMutex
+	lists   []*entities.SanctionsList // by source
+	entries [][]*entities.SanctionsListEntry
+}
+
+// NewSanctionsScreeningService creates a new sanctions screening service. The
+// lists are loaded by ReloadLists; until every list has loaded, screening
+// fails. With no lists configured every subject is clear and nothing is
+// recorded.
+func NewSanctionsScreeningService(screeningRepo repositories.SanctionsScreeningRepository, auditService AuditService, sources []SanctionsListSource, thresholds SanctionsMatchThresholds) SanctionsScreeningService {
+	lists := make([]*entities.SanctionsList, len(sources))
+	for i, source := range sources {
+		lists[i] = &entities.SanctionsList{Name: source.Name, Path: source.Path, Format: source.Format}
+	}
+
+	return &sanctionsScreeningService{
+		screeningRepo: screeningRepo,
+		auditService:  auditService,
+		sources:       sources,
+		thresholds:    thresholds,
+		lists:         lists,
+		entries:       make([][]*entities.SanctionsListEntry, len(sources)),
+	}
+}
+
+// ScreenSubject screens a subject and records the outcome
+func (s *sanctionsScreeningService) ScreenSubject(ctx context.Context, subject *entities.SanctionsScreeningSubject) (*entities.SanctionsScreening, error) {
+	now := time.Now()
+	screening := &entities.SanctionsScreening{
+		ID:                 uuid.New(),
+		SubjectType:        subject.SubjectType,
+		SubjectReference:   subject.Reference,
+		SubjectID:          subject.SubjectID,
+		SubjectName:        subject.Name,
+		SubjectDateOfBirth: subject.DateOfBirth,
+		Purpose:            subject.Purpose,
+		CooperativeID:      subject.CooperativeID,
+		Status:             entities.SanctionsScreeningStatusClear,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if len(s.sources) == 0 {
+		return screening, nil
+	}
+
+	matches, err := s.match(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) > 0 {
+		reviews, err := s.screeningRepo.ListSubjectReviews(ctx, subject.SubjectType, subject.Reference)
+		if err != nil {
+			return nil, err
+		}
+
+		cleared := make(map[string]bool)
+		for _, review := range reviews {
+			if review.Status == entities.SanctionsScreeningStatusPendingReview {
+				return review, nil
+			}
+			for _, reviewed := range review.Matches {
+				if review.Status == entities.SanctionsScreeningStatusCleared {
+					cleared[reviewed.Key()] = true
+					continue
+				}
+				for _, match := range matches {
+					if match.Key() == reviewed.Key() {
+						return review, nil
+					}
+				}
+			}
+		}
+
+		for _, match := range matches {
+			if !cleared[match.Key()] {
+				screening.Matches = append(screening.Matches, match)
+			}
+		}
+		if len(screening.Matches) > 0 {
+			screening.Status = entities.SanctionsScreeningStatusPendingReview
+		}
+	}
+
+	if err := s.screeningRepo.CreateScreening(ctx, screening); err != nil {
+		return nil, err
+	}
+
+	if screening.Status == entities.SanctionsScreeningStatusPendingReview {
+		s.auditService.LogOperation(ctx, &LogOperationRequest{
+			UserID:     uuid.Nil,
+			Operation:  "sanctions_potential_match",
+			EntityType: "sanctions_screening",
+			EntityID:   screening.ID,
+			NewValues: fmt.Sprintf("Held %s %s for review: %d potential sanctions list matches",
+				subject.Purpose, subject.Name, len(screening.Matches)),
+		})
+	}
+
+	return screening, nil
+}
+
+// match finds the entries on the loaded lists the subject resembles, best
+// first
+func (s *sanctionsScreeningService) match(subject *entities.SanctionsScreeningSubject) ([]*entities.SanctionsMatch, error) {
+	name := normalizeSanctionsName(subject.Name)
+	if name == "" {
+		return nil, fmt.Errorf("subject has no name to screen")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matches []*entities.SanctionsMatch
+	for i, list := range s.lists {
+		if list.LoadedAt.IsZero() {
+			return nil, fmt.Errorf("sanctions list %s is not loaded", list.Name)
+		}
+
+		for _, entry := range s.entries[i] {
+			if !sanctionsEntryApplies(subject.SubjectType, entry.EntryType) {
+				continue
+			}
+			if match := matchSanctionsEntry(name, subject.DateOfBirth, entry, s.thresholds); match != nil {
+				matches = append(matches, match)
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].NameScore > matches[j].NameScore
+	})
+	return matches, nil
+}
+
+// sanctionsEntryApplies reports whether a kind of subject is screened against
+// a kind of entry; payout beneficiaries may be people or organizations
+func sanctionsEntryApplies(subjectType, entryType string) bool {
+	switch {
+	case entryType == "":
+		return true
+	case subjectType == entities.SanctionsSubjectIndividual:
+		return entryType == entities.SanctionsEntryTypeIndividual
+	case subjectType == entities.SanctionsSubjectBusiness:
+		return entryType == entities.SanctionsEntryTypeEntity
+	}
+	return true
+}
+
+// matchSanctionsEntry compares a normalized name and optional date of birth
+// with a list entry. A date of birth that disagrees with all of the entry's
+// rules the entry out; one that agrees lets a looser name match through.
+func matchSanctionsEntry(name string, dateOfBirth *time.Time, entry *entities.SanctionsListEntry, thresholds SanctionsMatchThresholds) *entities.SanctionsMatch {
+	var bestName string
+	var bestScore float64
+	for _, candidate := range append([]string{entry.Name}, entry.Aliases...) {
+		if score := sanctionsNameSimilarity(name, normalizeSanctionsName(candidate)); score > bestScore {
+			bestName, bestScore = candidate, score
+		}
+	}
+	if bestScore < thresholds.NameScore {
+		return nil
+	}
+
+	dateOfBirthMatched := false
+	if dateOfBirth != nil && len(entry.BirthDates) > 0 {
+		for _, birthDate := range entry.BirthDates {
+			if sanctionsBirthDateAgrees(*dateOfBirth, birthDate, thresholds.BirthYearTolerance) {
+				dateOfBirthMatched = true
+				break
+			}
+		}
+		if !dateOfBirthMatched {
+			return nil
+		}
+	}
+	if !dateOfBirthMatched && bestScore < thresholds.NameOnlyScore {
+		return nil
+	}
+
+	match := &entities.SanctionsMatch{
+		ListName:           entry.ListName,
+		EntryID:            entry.EntryID,
+		EntryName:          entry.Name,
+		MatchedName:        bestName,
+		NameScore:          float64(int(bestScore*1000+0.5)) / 1000,
+		DateOfBirthMatched: dateOfBirthMatched,
+		Program:            entry.Program,
+	}
+	for _, birthDate := range entry.BirthDates {
+		match.BirthDates = append(match.BirthDates, birthDate.String())
+	}
+	return match
+}
+
+// sanctionsBirthDateAgrees reports whether a date of birth agrees with a list
+// date as far as the list gives it, allowing the year to be off by tolerance
+func sanctionsBirthDateAgrees(dateOfBirth time.Time, birthDate entities.SanctionsBirthDate, tolerance int) bool {
+	years := dateOfBirth.Year() - birthDate.Year
+	if years < -tolerance || years > tolerance {
+		return false
+	}
+	if birthDate.Month != 0 && int(dateOfBirth.Month()) != birthDate.Month {
+		return false
+	}
+	return birthDate.Day == 0 || dateOfBirth.Day() == birthDate.Day
+}
+
+// normalizeSanctionsName lowercases a name and reduces it to words of letters
+// and digits separated by single spaces
+func normalizeSanctionsName(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// sanctionsNameSimilarity scores two normalized names from 0 to 1 with
+// Jaro-Winkler, as written and with their words in alphabetical order so
+// that family and given names may be swapped
+func sanctionsNameSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+
+	sortWords := func(name string) string {
+		words := strings.Fields(name)
+		sort.Strings(words)
+		return strings.Join(words, " ")
+	}
+
+	score := jaroWinkler(a, b)
+	if sorted := jaroWinkler(sortWords(a), sortWords(b)); sorted > score {
+		score = sorted
+	}
+	return score
+}
+
+// jaroWinkler is the Jaro similarity of two strings boosted by the length of
+// their common prefix, up to four characters
+func jaroWinkler(a, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0
+	}
+
+	window := maxInt(len(s1), len(s2))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matched1 := make([]bool, len(s1))
+	matched2 := make([]bool, len(s2))
+	matches := 0
+	for i := range s1 {
+		start := maxInt(0, i-window)
+		end := i + window + 1
+		if end > len(s2) {
+			end = len(s2)
+		}
+		for j := start; j < end; j++ {
+			if !matched2[j] && s1[i] == s2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if s1[i] != s2[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < 4 && prefix < len(s1) && prefix < len(s2) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// ReloadLists re-reads the list files that changed since they were loaded
+func (s *sanctionsScreeningService) ReloadLists(ctx context.Context) []*entities.SanctionsList {
+	for i, source := range s.sources {
+		s.mu.RLock()
+		loadedModifiedAt := s.lists[i].ModifiedAt
+		s.mu.RUnlock()
+
+		info, err := os.Stat(source.Path)
+		if err == nil && info.ModTime().Equal(loadedModifiedAt) {
+			continue
+		}
+
+		var entries []*entities.SanctionsListEntry
+		if err == nil {
+			entries, err = loadSanctionsList(source)
+		}
+
+		s.mu.Lock()
+		list := s.lists[i]
+		if err != nil {
+			list.LoadError = err.Error()
+		} else {
+			s.entries[i] = entries
+			list.EntryCount = len(entries)
+			list.ModifiedAt = info.ModTime()
+			list.LoadedAt = time.Now()
+			list.LoadError = ""
+		}
+		s.mu.Unlock()
+	}
+
+	return s.GetLists(ctx)
+}
+
+// GetLists returns the configured lists as last loaded
+func (s *sanctionsScreeningService) GetLists(ctx context.Context) []*entities.SanctionsList {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lists := make([]*entities.SanctionsList, len(s.lists))
+	for i, list := range s.lists {
+		copied := *list
+		lists[i] = &copied
+	}
+	return lists
+}
+
+// GetScreening gets a screening with its matches
+func (s *sanctionsScreeningService) GetScreening(ctx context.Context, screeningID uuid.UUID) (*entities.SanctionsScreening, error) {
+	return s.screeningRepo.GetScreening(ctx, screeningID)
+}
+
+// ListScreenings lists screenings, optionally by status or subject
+func (s *sanctionsScreeningService) ListScreenings(ctx context.Context, filter *entities.SanctionsScreeningFilter) ([]*entities.SanctionsScreening, int, error) {
+	return s.screeningRepo.ListScreenings(ctx, filter)
+}
+
+// ReviewScreening clears a screening's matches as false positives, letting
+// the subject through, or confirms them, keeping the subject blocked
+func (s *sanctionsScreeningService) ReviewScreening(ctx context.Context, screeningID uuid.UUID, req *entities.ReviewSanctionsScreeningRequest, reviewerID uuid.UUID) (*entities.SanctionsScreening, error) {
+	screening, err := s.screeningRepo.GetScreening(ctx, screeningID)
+	if err != nil {
+		return nil, err
+	}
+	if screening.Status != entities.SanctionsScreeningStatusPendingReview {
+		return nil, fmt.Errorf("screening is %s and cannot be reviewed", screening.Status)
+	}
+
+	now := time.Now()
+	screening.Status = req.Decision
+	screening.ReviewedBy = &reviewerID
+	screening.ReviewedAt = &now
+	screening.ReviewNotes = req.Notes
+	screening.UpdatedAt = now
+
+	if err := s.screeningRepo.ReviewScreening(ctx, screening); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     reviewerID,
+		Operation:  "review_sanctions_screening",
+		EntityType: "sanctions_screening",
+		EntityID:   screening.ID,
+		NewValues:  fmt.Sprintf("Marked %s screening of %s as %s", screening.Purpose, screening.SubjectName, screening.Status),
+	})
+
+	return screening, nil
+}
+
+// requireSanctionsClearance screens a subject and fails while a potential
+// match holds it for review or a match has been confirmed. Nothing is
+// screened without a screening service.
+func requireSanctionsClearance(ctx context.Context, sanctionsService SanctionsScreeningService, subject *entities.SanctionsScreeningSubject) error {
+	if sanctionsService == nil {
+		return nil
+	}
+
+	screening, err := sanctionsService.ScreenSubject(ctx, subject)
+	if err != nil {
+		return fmt.Errorf("failed to screen %s against sanctions lists: %w", subject.Name, err)
+	}
+
+	switch screening.Status {
+	case entities.SanctionsScreeningStatusPendingReview:
+		return fmt.Errorf("%s is held for sanctions review (screening %s)", subject.Name, screening.ID)
+	case entities.SanctionsScreeningStatusConfirmed:
+		return fmt.Errorf("%s matches a sanctions list entry (screening %s)", subject.Name, screening.ID)
+	}
+	return nil
+}
+
+// individualSanctionsSubject screens a member, identified across screenings
+// by email so that registration and membership share reviews
+func individualSanctionsSubject(user *entities.User, purpose string, cooperativeID *uuid.UUID) *entities.SanctionsScreeningSubject {
+	userID := user.ID
+	return &entities.SanctionsScreeningSubject{
+		SubjectType:   entities.SanctionsSubjectIndividual,
+		Reference:     "user:" + strings.ToLower(strings.TrimSpace(user.Email)),
+		SubjectID:     &userID,
+		Name:          user.Name,
+		DateOfBirth:   user.DateOfBirth,
+		Purpose:       purpose,
+		CooperativeID: cooperativeID,
+	}
+}
+
+// SanctionsListScheduler reloads changed sanctions list files periodically
+type SanctionsListScheduler struct {
+	*intervalScheduler
+	screeningService SanctionsScreeningService
+}
+
+// NewSanctionsListScheduler creates a scheduler that runs every interval
+func NewSanctionsListScheduler(screeningService SanctionsScreeningService, interval time.Duration) *SanctionsListScheduler {
+	s := &SanctionsListScheduler{screeningService: screeningService}
+	s.intervalScheduler = newIntervalScheduler("Sanctions list reload", interval, s.runOnce)
+	return s
+}
+
+func (s *SanctionsListScheduler) runOnce(ctx context.Context) error {
+	started := time.Now()
+	for _, list := range s.screeningService.ReloadLists(ctx) {
+		if list.LoadError != "" {
+			log.Printf("Failed to load sanctions list %s from %s: %s", list.Name, list.Path, list.LoadError)
+			continue
+		}
+		if !list.LoadedAt.Before(started) {
+			log.Printf("Loaded %d entries from sanctions list %s", list.EntryCount, list.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,427 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testSanctionsCSV = `id,name,type,aliases,dates_of_birth,nationality,program
+QDi.001,Abu Bakar Ba'asyir,individual,Abu Bakar Baasyir;Abdus Samad,1938-08-17,Indonesia,Al-Qaida
+QDe.002,Jemaah Islamiyah,entity,Jema'ah Islamiyah,,,Al-Qaida
+T-3,Hambali Isamuddin,individual,,,Indonesia,DTTOT
+`
+
+var testSanctionsThresholds = SanctionsMatchThresholds{NameScore: 0.85, NameOnlyScore: 0.92, BirthYearTolerance: 1}
+
+// writeTestSanctionsList writes a sanctions list file into the test's temporary directory
+func writeTestSanctionsList(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// newTestSanctionsScreeningService returns a screening service with testSanctionsCSV loaded as the dttot list
+func newTestSanctionsScreeningService(t *testing.T) (SanctionsScreeningService, *MockSanctionsScreeningRepository) {
+	sources, err := NewSanctionsListSources("dttot=" + writeTestSanctionsList(t, "dttot.csv", testSanctionsCSV))
+	require.NoError(t, err)
+
+	mockRepo := new(MockSanctionsScreeningRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service := NewSanctionsScreeningService(mockRepo, mockAuditService, sources, testSanctionsThresholds)
+	for _, list := range service.ReloadLists(context.Background()) {
+		require.Empty(t, list.LoadError)
+	}
+	return service, mockRepo
+}
+
+const testSanctionsXML = `<?xml version="1.0" encoding="UTF-8"?>
+<CONSOLIDATED_LIST dateGenerated="2024-03-01T00:00:00">
+  <INDIVIDUALS>
+    <INDIVIDUAL>
+      <DATAID>6908048</DATAID>
+      <FIRST_NAME>NURJAMAN</FIRST_NAME>
+      <SECOND_NAME>RIDUAN</SECOND_NAME>
+      <THIRD_NAME>ISAMUDDIN</THIRD_NAME>
+      <UN_LIST_TYPE>Al-Qaida</UN_LIST_TYPE>
+      <REFERENCE_NUMBER>QDi.087</REFERENCE_NUMBER>
+      <NATIONALITY><VALUE>Indonesia</VALUE></NATIONALITY>
+      <INDIVIDUAL_ALIAS><QUALITY>Good</QUALITY><ALIAS_NAME>Hambali</ALIAS_NAME></INDIVIDUAL_ALIAS>
+      <INDIVIDUAL_DATE_OF_BIRTH><TYPE_OF_DATE>EXACT</TYPE_OF_DATE><DATE>1964-04-04</DATE></INDIVIDUAL_DATE_OF_BIRTH>
+      <INDIVIDUAL_DATE_OF_BIRTH><TYPE_OF_DATE>BETWEEN</TYPE_OF_DATE><FROM_YEAR>1965</FROM_YEAR><TO_YEAR>1966</TO_YEAR></INDIVIDUAL_DATE_OF_BIRTH>
+    </INDIVIDUAL>
+  </INDIVIDUALS>
+  <ENTITIES>
+    <ENTITY>
+      <DATAID>110426</DATAID>
+      <FIRST_NAME>JEMAAH ISLAMIYAH</FIRST_NAME>
+      <UN_LIST_TYPE>Al-Qaida</UN_LIST_TYPE>
+      <REFERENCE_NUMBER>QDe.092</REFERENCE_NUMBER>
+      <ENTITY_ALIAS><ALIAS_NAME>Jema'ah Islamiyah</ALIAS_NAME></ENTITY_ALIAS>
+    </ENTITY>
+  </ENTITIES>
+</CONSOLIDATED_LIST>
+`
+
+func TestSanctionsScreeningService_ScreenSubject(t *testing.T) {
+	testCases := []struct {
+		name          string
+		subjectType   string
+		subjectName   string
+		dateOfBirth   string
+		expectedEntry string // empty when the subject is clear
+		dobMatched    bool
+	}{
+		{
+			name:          "Misspelt name with agreeing date of birth",
+			subjectType:   entities.SanctionsSubjectIndividual,
+			subjectName:   "Abu Bakar Basyir",
+			dateOfBirth:   "1938-08-17",
+			expectedEntry: "QDi.001",
+			dobMatched:    true,
+		},
+		{
+			name:        "Same name with disagreeing date of birth",
+			subjectType: entities.SanctionsSubjectIndividual,
+			subjectName: "Abu Bakar Baasyir",
+			dateOfBirth: "1990-01-01",
+		},
+		{
+			name:          "Close name where dates of birth cannot be compared",
+			subjectType:   entities.SanctionsSubjectIndividual,
+			subjectName:   "Isamudin Hambali",
+			dateOfBirth:   "1964-04-04",
+			expectedEntry: "T-3",
+		},
+		{
+			name:        "Loose name without a date of birth to corroborate it",
+			subjectType: entities.SanctionsSubjectIndividual,
+			subjectName: "Hambal Ismudin",
+		},
+		{
+			name:          "Business with its legal form in the name",
+			subjectType:   entities.SanctionsSubjectBusiness,
+			subjectName:   "PT Jemaah Islamiyah",
+			expectedEntry: "QDe.002",
+		},
+		{
+			name:          "Business named like an entity",
+			subjectType:   entities.SanctionsSubjectBusiness,
+			subjectName:   "Jemaah Islamiya",
+			expectedEntry: "QDe.002",
+		},
+		{
+			name:        "Business is not screened against individuals",
+			subjectType: entities.SanctionsSubjectBusiness,
+			subjectName: "Abu Bakar Baasyir",
+		},
+		{
+			name:        "Unrelated name",
+			subjectType: entities.SanctionsSubjectIndividual,
+			subjectName: "Siti Rahmawati",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, screeningRepo := newTestSanctionsScreeningService(t)
+			ctx := context.Background()
+			subject := &entities.SanctionsScreeningSubject{
+				SubjectType: tc.subjectType,
+				Reference:   "user:" + tc.subjectName,
+				Name:        tc.subjectName,
+				Purpose:     entities.SanctionsPurposeRegistration,
+			}
+			if tc.dateOfBirth != "" {
+				dateOfBirth, err := time.Parse("2006-01-02", tc.dateOfBirth)
+				require.NoError(t, err)
+				subject.DateOfBirth = &dateOfBirth
+			}
+			screeningRepo.On("ListSubjectReviews", ctx, subject.SubjectType, subject.Reference).Return(nil, nil)
+			screeningRepo.On("CreateScreening", ctx, mock.AnythingOfType("*entities.SanctionsScreening")).Return(nil)
+
+			screening, err := service.ScreenSubject(ctx, subject)
+
+			require.NoError(t, err)
+			screeningRepo.AssertCalled(t, "CreateScreening", ctx, screening)
+			if tc.expectedEntry == "" {
+				assert.Equal(t, entities.SanctionsScreeningStatusClear, screening.Status)
+				assert.Empty(t, screening.Matches)
+				return
+			}
+			assert.Equal(t, entities.SanctionsScreeningStatusPendingReview, screening.Status)
+			assert.True(t, screening.IsBlocking())
+			require.NotEmpty(t, screening.Matches)
+			assert.Equal(t, tc.expectedEntry, screening.Matches[0].EntryID)
+			assert.Equal(t, tc.dobMatched, screening.Matches[0].DateOfBirthMatched)
+		})
+	}
+}
+
+func TestSanctionsScreeningService_ScreenSubject_ClearedMatchNotRaisedAgain(t *testing.T) {
+	service, screeningRepo := newTestSanctionsScreeningService(t)
+	ctx := context.Background()
+	subject := &entities.SanctionsScreeningSubject{
+		SubjectType: entities.SanctionsSubjectIndividual,
+		Reference:   "user:Hambali Isamuddin",
+		Name:        "Hambali Isamuddin",
+		Purpose:     entities.SanctionsPurposeRegistration,
+	}
+
+	cleared := &entities.SanctionsScreening{
+		ID:      uuid.New(),
+		Status:  entities.SanctionsScreeningStatusCleared,
+		Matches: []*entities.SanctionsMatch{{ListName: "dttot", EntryID: "T-3"}},
+	}
+	screeningRepo.On("ListSubjectReviews", ctx, subject.SubjectType, subject.Reference).Return([]*entities.SanctionsScreening{cleared}, nil)
+	screeningRepo.On("CreateScreening", ctx, mock.AnythingOfType("*entities.SanctionsScreening")).Return(nil)
+
+	screening, err := service.ScreenSubject(ctx, subject)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, cleared.ID, screening.ID)
+	assert.Equal(t, entities.SanctionsScreeningStatusClear, screening.Status)
+	assert.NoError(t, requireSanctionsClearance(ctx, service, subject))
+}
+
+func TestSanctionsScreeningService_ScreenSubject_HeldUntilReviewed(t *testing.T) {
+	for _, status := range []string{entities.SanctionsScreeningStatusPendingReview, entities.SanctionsScreeningStatusConfirmed} {
+		t.Run(status, func(t *testing.T) {
+			service, screeningRepo := newTestSanctionsScreeningService(t)
+			ctx := context.Background()
+			subject := &entities.SanctionsScreeningSubject{
+				SubjectType: entities.SanctionsSubjectIndividual,
+				Reference:   "user:Hambali Isamuddin",
+				Name:        "Hambali Isamuddin",
+				Purpose:     entities.SanctionsPurposeRegistration,
+			}
+
+			previous := &entities.SanctionsScreening{
+				ID:      uuid.New(),
+				Status:  status,
+				Matches: []*entities.SanctionsMatch{{ListName: "dttot", EntryID: "T-3"}},
+			}
+			screeningRepo.On("ListSubjectReviews", ctx, subject.SubjectType, subject.Reference).Return([]*entities.SanctionsScreening{previous}, nil)
+
+			screening, err := service.ScreenSubject(ctx, subject)
+
+			require.NoError(t, err)
+			assert.Equal(t, previous, screening)
+			screeningRepo.AssertNotCalled(t, "CreateScreening", mock.Anything, mock.Anything)
+
+			err = requireSanctionsClearance(ctx, service, subject)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), previous.ID.String())
+		})
+	}
+}
+
+func TestSanctionsScreeningService_ScreenSubject_ListNotLoaded(t *testing.T) {
+	sources, err := NewSanctionsListSources(filepath.Join(t.TempDir(), "missing.csv"))
+	require.NoError(t, err)
+	screeningRepo := new(MockSanctionsScreeningRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service := NewSanctionsScreeningService(screeningRepo, mockAuditService, sources, testSanctionsThresholds)
+
+	lists := service.ReloadLists(context.Background())
+	require.Len(t, lists, 1)
+	assert.Equal(t, "missing", lists[0].Name)
+	assert.NotEmpty(t, lists[0].LoadError)
+
+	_, err = service.ScreenSubject(context.Background(), &entities.SanctionsScreeningSubject{
+		SubjectType: entities.SanctionsSubjectIndividual,
+		Reference:   "user:Siti Rahmawati",
+		Name:        "Siti Rahmawati",
+		Purpose:     entities.SanctionsPurposeRegistration,
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sanctions list missing is not loaded")
+	screeningRepo.AssertNotCalled(t, "CreateScreening", mock.Anything, mock.Anything)
+}
+
+func TestSanctionsScreeningService_ScreenSubject_NoListsConfigured(t *testing.T) {
+	screeningRepo := new(MockSanctionsScreeningRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service := NewSanctionsScreeningService(screeningRepo, mockAuditService, nil, testSanctionsThresholds)
+
+	screening, err := service.ScreenSubject(context.Background(), &entities.SanctionsScreeningSubject{
+		SubjectType: entities.SanctionsSubjectIndividual,
+		Reference:   "user:Abu Bakar Baasyir",
+		Name:        "Abu Bakar Baasyir",
+		Purpose:     entities.SanctionsPurposeRegistration,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.SanctionsScreeningStatusClear, screening.Status)
+	screeningRepo.AssertNotCalled(t, "CreateScreening", mock.Anything, mock.Anything)
+}
+
+func TestSanctionsScreeningService_ReloadLists_PicksUpChangedFile(t *testing.T) {
+	path := writeTestSanctionsList(t, "dttot.csv", testSanctionsCSV)
+	sources, err := NewSanctionsListSources(path)
+	require.NoError(t, err)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service := NewSanctionsScreeningService(new(MockSanctionsScreeningRepository), mockAuditService, sources, testSanctionsThresholds)
+
+	lists := service.ReloadLists(context.Background())
+	require.Equal(t, 3, lists[0].EntryCount)
+	loadedAt := lists[0].LoadedAt
+
+	// An unchanged file is not read again
+	lists = service.ReloadLists(context.Background())
+	assert.Equal(t, loadedAt, lists[0].LoadedAt)
+
+	require.NoError(t, os.WriteFile(path, []byte("name\nNoordin Mohammad Top\n"), 0o644))
+	modifiedAt := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, modifiedAt, modifiedAt))
+
+	lists = service.ReloadLists(context.Background())
+	assert.Equal(t, 1, lists[0].EntryCount)
+	assert.Empty(t, lists[0].LoadError)
+
+	// A file emptied by a failed download keeps the previous entries
+	require.NoError(t, os.WriteFile(path, []byte("name\n"), 0o644))
+	modifiedAt = modifiedAt.Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, modifiedAt, modifiedAt))
+
+	lists = service.ReloadLists(context.Background())
+	assert.Equal(t, 1, lists[0].EntryCount)
+	assert.Contains(t, lists[0].LoadError, "has no entries")
+}
+
+func TestSanctionsScreeningService_ReviewScreening(t *testing.T) {
+	service, screeningRepo := newTestSanctionsScreeningService(t)
+	ctx := context.Background()
+	reviewerID := uuid.New()
+
+	pending := &entities.SanctionsScreening{ID: uuid.New(), Status: entities.SanctionsScreeningStatusPendingReview}
+	screeningRepo.On("GetScreening", ctx, pending.ID).Return(pending, nil)
+	screeningRepo.On("ReviewScreening", ctx, pending).Return(nil)
+
+	screening, err := service.ReviewScreening(ctx, pending.ID, &entities.ReviewSanctionsScreeningRequest{
+		Decision: entities.SanctionsScreeningStatusCleared,
+		Notes:    "Different person; passport checked",
+	}, reviewerID)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.SanctionsScreeningStatusCleared, screening.Status)
+	assert.Equal(t, reviewerID, *screening.ReviewedBy)
+	assert.NotNil(t, screening.ReviewedAt)
+	assert.False(t, screening.IsBlocking())
+
+	// A reviewed screening cannot be reviewed again
+	_, err = service.ReviewScreening(ctx, pending.ID, &entities.ReviewSanctionsScreeningRequest{
+		Decision: entities.SanctionsScreeningStatusConfirmed,
+		Notes:    "Second look",
+	}, reviewerID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be reviewed")
+}
+
+func TestParseSanctionsXML_UNConsolidatedList(t *testing.T) {
+	entries, err := loadSanctionsList(SanctionsListSource{
+		Name:   "un",
+		Path:   writeTestSanctionsList(t, "consolidated.xml", testSanctionsXML),
+		Format: entities.SanctionsListFormatXML,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	individual := entries[0]
+	assert.Equal(t, "QDi.087", individual.EntryID)
+	assert.Equal(t, entities.SanctionsEntryTypeIndividual, individual.EntryType)
+	assert.Equal(t, "NURJAMAN RIDUAN ISAMUDDIN", individual.Name)
+	assert.Equal(t, []string{"Hambali"}, individual.Aliases)
+	assert.Equal(t, "Indonesia", individual.Nationality)
+	assert.Equal(t, []entities.SanctionsBirthDate{{Year: 1964, Month: 4, Day: 4}, {Year: 1965}, {Year: 1966}}, individual.BirthDates)
+
+	entity := entries[1]
+	assert.Equal(t, "QDe.092", entity.EntryID)
+	assert.Equal(t, entities.SanctionsEntryTypeEntity, entity.EntryType)
+	assert.Equal(t, "JEMAAH ISLAMIYAH", entity.Name)
+	assert.Equal(t, "Al-Qaida", entity.Program)
+}
+
+func TestParseSanctionsCSV_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		message string
+	}{
+		{"No name column", "id,full_name\n1,Someone\n", "no name column"},
+		{"Unknown entry type", "name,type\nSomeone,vessel\n", "unknown entry type"},
+		{"Invalid date of birth", "name,dates_of_birth\nSomeone,17/08/1938\n", "invalid date of birth"},
+		{"No entries", "name\n", "has no entries"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := loadSanctionsList(SanctionsListSource{
+				Name:   "test",
+				Path:   writeTestSanctionsList(t, "test.csv", tc.content),
+				Format: entities.SanctionsListFormatCSV,
+			})
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.message)
+		})
+	}
+}
+
+func TestNewSanctionsListSources(t *testing.T) {
+	sources, err := NewSanctionsListSources(" dttot=/srv/lists/dttot.csv, /srv/lists/consolidated.XML ,")
+
+	require.NoError(t, err)
+	assert.Equal(t, []SanctionsListSource{
+		{Name: "dttot", Path: "/srv/lists/dttot.csv", Format: entities.SanctionsListFormatCSV},
+		{Name: "consolidated", Path: "/srv/lists/consolidated.XML", Format: entities.SanctionsListFormatXML},
+	}, sources)
+
+	_, err = NewSanctionsListSources("/srv/lists/dttot.pdf")
+	assert.Error(t, err)
+
+	_, err = NewSanctionsListSources("a/dttot.csv,b/dttot.xml")
+	assert.Error(t, err)
+}
+
+func TestNewSanctionsMatchThresholds_Invalid(t *testing.T) {
+	testCases := []struct {
+		name, nameScore, nameOnlyScore, birthYearTolerance string
+	}{
+		{"Name score above 1", "1.5", "1", "1"},
+		{"Name score zero", "0", "0.9", "1"},
+		{"Name-only score below name score", "0.9", "0.8", "1"},
+		{"Negative tolerance", "0.85", "0.92", "-1"},
+		{"Not a number", "high", "0.92", "1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewSanctionsMatchThresholds(tc.nameScore, tc.nameOnlyScore, tc.birthYearTolerance)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	assert.InDelta(t, 0.961, jaroWinkler("martha", "marhta"), 0.001)
+	assert.InDelta(t, 0.840, jaroWinkler("dwayne", "duane"), 0.001)
+	assert.InDelta(t, 0.813, jaroWinkler("dixon", "dicksonx"), 0.001)
+	assert.Equal(t, 1.0, jaroWinkler("hambali", "hambali"))
+	assert.Equal(t, 0.0, jaroWinkler("abc", "xyz"))
+}
@@ -0,0 +1,437 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ShariaScreeningService evaluates businesses and profit terms against the
+// screening rules each cooperative's Sharia supervisory boards set
+type ShariaScreeningService interface {
+	CreateRuleSet(ctx context.Context, cooperativeID uuid.UUID, req *entities.CreateShariaScreeningRuleSetRequest, creatorID uuid.UUID) (*entities.ShariaScreeningRuleSet, error)
+	UpdateRuleSet(ctx context.Context, cooperativeID, ruleSetID uuid.UUID, req *entities.CreateShariaScreeningRuleSetRequest, updaterID uuid.UUID) (*entities.ShariaScreeningRuleSet, error)
+	DeactivateRuleSet(ctx context.Context, cooperativeID, ruleSetID, deactivatorID uuid.UUID) error
+	// GetActiveRuleSets returns the rule sets a cooperative is screened under,
+	// falling back to the default rules when it has configured none
+	GetActiveRuleSets(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.ShariaScreeningRuleSet, error)
+
+	// Evaluate screens the subject against every active rule set of the cooperative
+	Evaluate(ctx context.Context, cooperativeID uuid.UUID, subject *entities.ShariaScreeningSubject) (*entities.ShariaScreeningEvaluation, error)
+	// ScreenProject screens a project's business activity together with any
+	// profit terms and financials given in subject
+	ScreenProject(ctx context.Context, projectID uuid.UUID, subject *entities.ShariaScreeningSubject) (*entities.ShariaScreeningEvaluation, error)
+}
+
+type shariaScreeningService struct {
+	screeningRepo repositories.ShariaScreeningRepository
+	auditService  AuditService
+}
+
+// NewShariaScreeningService creates a new Sharia screening service
+func NewShariaScreeningService(screeningRepo repositories.ShariaScreeningRepository, auditService AuditService) ShariaScreeningService {
+	return &shariaScreeningService{
+		screeningRepo: screeningRepo,
+		auditService:  auditService,
+	}
+}
+
+// defaultShariaScreeningRuleSet follows the DSN-MUI criteria for Sharia
+// securities: no prohibited activity, interest-bearing debt at most 45% of
+// assets and interest income at most 10% of revenue
+func defaultShariaScreeningRuleSet(cooperativeID uuid.UUID) *entities.ShariaScreeningRuleSet {
+	maxDebtToAsset := 45.0
+	maxInterestIncome := 10.0
+	return &entities.ShariaScreeningRuleSet{
+		CooperativeID:    cooperativeID,
+		SupervisoryBoard: "DSN-MUI",
+		Name:             "Default screening",
+		ProhibitedSectors: []string{
+			"gambling", "alcohol", "pork", "conventional_finance", "conventional_insurance", "adult_entertainment",
+		},
+		ProhibitedKeywords: []string{
+			"casino", "betting", "lottery", "liquor", "brewery", "pork", "riba",
+		},
+		MaxDebtToAssetRatio:    &maxDebtToAsset,
+		MaxInterestIncomeRatio: &maxInterestIncome,
+		GuaranteedReturnPhrases: []string{
+			"guaranteed return", "guaranteed profit", "fixed return", "fixed interest", "principal guaranteed",
+			"imbal hasil tetap", "keuntungan pasti",
+		},
+		IsActive: true,
+	}
+}
+
+func (s *shariaScreeningService) CreateRuleSet(ctx context.Context, cooperativeID uuid.UUID, req *entities.CreateShariaScreeningRuleSetRequest, creatorID uuid.UUID) (*entities.ShariaScreeningRuleSet, error) {
+	existing, err := s.screeningRepo.ListActiveRuleSets(ctx, cooperativeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ruleSet := range existing {
+		if strings.EqualFold(ruleSet.SupervisoryBoard, strings.TrimSpace(req.SupervisoryBoard)) {
+			return nil, fmt.Errorf("%s already has an active rule set for this cooperative", ruleSet.SupervisoryBoard)
+		}
+	}
+
+	now := time.Now()
+	ruleSet := &entities.ShariaScreeningRuleSet{
+		ID:            uuid.New(),
+		CooperativeID: cooperativeID,
+		IsActive:      true,
+		CreatedBy:     creatorID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	applyShariaRuleSetRequest(ruleSet, req)
+
+	if err := s.screeningRepo.CreateRuleSet(ctx, ruleSet); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     creatorID,
+		Operation:  "create_sharia_screening_rule_set",
+		EntityType: entities.AuditEntityCooperative,
+		EntityID:   cooperativeID,
+		NewValues:  ruleSet,
+	})
+
+	return ruleSet, nil
+}
+
+func (s *shariaScreeningService) UpdateRuleSet(ctx context.Context, cooperativeID, ruleSetID uuid.UUID, req *entities.CreateShariaScreeningRuleSetRequest, updaterID uuid.UUID) (*entities.ShariaScreeningRuleSet, error) {
+	ruleSet, err := s.screeningRepo.GetRuleSet(ctx, cooperativeID, ruleSetID)
+	if err != nil {
+		return nil, err
+	}
+	if !ruleSet.IsActive {
+		return nil, errors.New("sharia screening rule set is no longer active")
+	}
+
+	applyShariaRuleSetRequest(ruleSet, req)
+	ruleSet.UpdatedAt = time.Now()
+
+	if err := s.screeningRepo.UpdateRuleSet(ctx, ruleSet); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     updaterID,
+		Operation:  "update_sharia_screening_rule_set",
+		EntityType: entities.AuditEntityCooperative,
+		EntityID:   cooperativeID,
+		NewValues:  ruleSet,
+	})
+
+	return ruleSet, nil
+}
+
+func (s *shariaScreeningService) DeactivateRuleSet(ctx context.Context, cooperativeID, ruleSetID, deactivatorID uuid.UUID) error {
+	if err := s.screeningRepo.DeactivateRuleSet(ctx, cooperativeID, ruleSetID); err != nil {
+		return err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     deactivatorID,
+		Operation:  "deactivate_sharia_screening_rule_set",
+		EntityType: entities.AuditEntityCooperative,
+		EntityID:   cooperativeID,
+		NewValues:  fmt.Sprintf("Deactivated sharia screening rule set %s", ruleSetID),
+	})
+
+	return nil
+}
+
+func (s *shariaScreeningService) GetActiveRuleSets(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.ShariaScreeningRuleSet, error) {
+	ruleSets, err := s.screeningRepo.ListActiveRuleSets(ctx, cooperativeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ruleSets) == 0 {
+		return []*entities.ShariaScreeningRuleSet{defaultShariaScreeningRuleSet(cooperativeID)}, nil
+	}
+
+	return ruleSets, nil
+}
+
+func (s *shariaScreeningService) Evaluate(ctx context.Context, cooperativeID uuid.UUID, subject *entities.ShariaScreeningSubject) (*entities.ShariaScreeningEvaluation, error) {
+	ruleSets, err := s.GetActiveRuleSets(ctx, cooperativeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluateShariaRuleSets(cooperativeID, ruleSets, subject), nil
+}
+
+func (s *shariaScreeningService) ScreenProject(ctx context.Context, projectID uuid.UUID, subject *entities.ShariaScreeningSubject) (*entities.ShariaScreeningEvaluation, error) {
+	activity, err := s.screeningRepo.GetProjectActivity(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	screened := entities.ShariaScreeningSubject{}
+	if subject != nil {
+		screened = *subject
+	}
+	if screened.BusinessName == "" {
+		screened.BusinessName = activity.BusinessName
+	}
+	if screened.Sector == "" {
+		screened.Sector = activity.BusinessType
+	}
+	screened.Activity = strings.Join(nonEmpty(screened.Activity, activity.BusinessDescription, activity.ProjectTitle, activity.ProjectDescription), "\n")
+	// Projects describe what investors can expect in their description
+	screened.ProfitTerms = strings.Join(nonEmpty(screened.ProfitTerms, activity.ProjectDescription), "\n")
+
+	evaluation, err := s.Evaluate(ctx, activity.CooperativeID, &screened)
+	if err != nil {
+		return nil, err
+	}
+	evaluation.ProjectID = &projectID
+
+	return evaluation, nil
+}
+
+func applyShariaRuleSetRequest(ruleSet *entities.ShariaScreeningRuleSet, req *entities.CreateShariaScreeningRuleSetRequest) {
+	ruleSet.SupervisoryBoard = strings.TrimSpace(req.SupervisoryBoard)
+	ruleSet.Name = strings.TrimSpace(req.Name)
+	ruleSet.ProhibitedSectors = normalizeShariaTerms(req.ProhibitedSectors, normalizeSector)
+	ruleSet.ProhibitedKeywords = normalizeShariaTerms(req.ProhibitedKeywords, strings.ToLower)
+	ruleSet.MaxDebtToAssetRatio = req.MaxDebtToAssetRatio
+	ruleSet.MaxInterestIncomeRatio = req.MaxInterestIncomeRatio
+	ruleSet.GuaranteedReturnPhrases = normalizeShariaTerms(req.GuaranteedReturnPhrases, strings.ToLower)
+}
+
+// normalizeShariaTerms trims, normalizes and de-duplicates configured terms
+func normalizeShariaTerms(terms []string, normalize func(string) string) []string {
+	seen := make(map[string]bool)
+	normalized := []string{}
+	for _, term := range terms {
+		term = normalize(strings.TrimSpace(term))
+		if term == "" || seen[term] {
+			continue
+		}
+		seen[term] = true
+		normalized = append(normalized, term)
+	}
+	return normalized
+}
+
+// normalizeSector lets "Conventional Finance" match "conventional_finance"
+func normalizeSector(sector string) string {
+	sector = strings.ToLower(strings.TrimSpace(sector))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(sector)
+}
+
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// evaluateShariaRuleSets screens the subject under each rule set
+func evaluateShariaRuleSets(cooperativeID uuid.UUID, ruleSets []*entities.ShariaScreeningRuleSet, subject *entities.ShariaScreeningSubject) *entities.ShariaScreeningEvaluation {
+	evaluation := &entities.ShariaScreeningEvaluation{
+		CooperativeID: cooperativeID,
+		Compliant:     true,
+		EvaluatedAt:   time.Now(),
+	}
+
+	for _, ruleSet := range ruleSets {
+		result := evaluateShariaRuleSet(ruleSet, subject)
+		evaluation.Compliant = evaluation.Compliant && result.Compliant
+		evaluation.Results = append(evaluation.Results, result)
+	}
+
+	return evaluation
+}
+
+func evaluateShariaRuleSet(ruleSet *entities.ShariaScreeningRuleSet, subject *entities.ShariaScreeningSubject) *entities.ShariaScreeningResult {
+	result := &entities.ShariaScreeningResult{
+		RuleSetID:        ruleSet.ID,
+		SupervisoryBoard: ruleSet.SupervisoryBoard,
+		RuleSetName:      ruleSet.Name,
+		Rules: []entities.ShariaRuleResult{
+			checkProfitSharingRatio(subject),
+			checkProhibitedSector(ruleSet, subject),
+			checkProhibitedKeywords(ruleSet, subject),
+			checkDebtToAssetRatio(ruleSet, subject),
+			checkInterestIncomeRatio(ruleSet, subject),
+			checkGuaranteedReturn(ruleSet, subject),
+		},
+	}
+
+	result.Compliant = true
+	for _, rule := range result.Rules {
+		if rule.Status == entities.ShariaRuleStatusFailed {
+			result.Compliant = false
+		}
+	}
+
+	return result
+}
+
+func passedShariaRule(rule, detail string, args ...interface{}) entities.ShariaRuleResult {
+	return entities.ShariaRuleResult{Rule: rule, Status: entities.ShariaRuleStatusPassed, Detail: fmt.Sprintf(detail, args...)}
+}
+
+func failedShariaRule(rule, detail string, args ...interface{}) entities.ShariaRuleResult {
+	return entities.ShariaRuleResult{Rule: rule, Status: entities.ShariaRuleStatusFailed, Detail: fmt.Sprintf(detail, args...)}
+}
+
+func notApplicableShariaRule(rule, detail string) entities.ShariaRuleResult {
+	return entities.ShariaRuleResult{Rule: rule, Status: entities.ShariaRuleStatusNotApplicable, Detail: detail}
+}
+
+// checkProfitSharingRatio requires profit to be shared by ratio, with every
+// party's share non-negative and the shares adding up to the whole profit
+func checkProfitSharingRatio(subject *entities.ShariaScreeningSubject) entities.ShariaRuleResult {
+	rule := entities.ShariaRuleProfitSharingRatio
+	if len(subject.ProfitSharingRatio) == 0 {
+		return notApplicableShariaRule(rule, "no profit sharing ratio given")
+	}
+
+	total := 0.0
+	for party, ratio := range subject.ProfitSharingRatio {
+		if ratio < 0 {
+			return failedShariaRule(rule, "profit sharing ratio for %s is negative", party)
+		}
+		total += ratio
+	}
+
+	// Compare in basis points so that ratios such as 33.3/33.3/33.4 are not rejected by float drift
+	if math.Round(total*100) != 10000 {
+		return failedShariaRule(rule, "profit sharing ratios add up to %.2f%%, not 100%%", total)
+	}
+
+	return passedShariaRule(rule, "profit sharing ratios add up to 100%%")
+}
+
+func checkProhibitedSector(ruleSet *entities.ShariaScreeningRuleSet, subject *entities.ShariaScreeningSubject) entities.ShariaRuleResult {
+	rule := entities.ShariaRuleProhibitedSector
+	if len(ruleSet.ProhibitedSectors) == 0 {
+		return notApplicableShariaRule(rule, "no prohibited sectors configured")
+	}
+	if strings.TrimSpace(subject.Sector) == "" {
+		return notApplicableShariaRule(rule, "business sector not given")
+	}
+
+	sector := normalizeSector(subject.Sector)
+	for _, prohibited := range ruleSet.ProhibitedSectors {
+		if normalizeSector(prohibited) == sector {
+			return failedShariaRule(rule, "sector %s is prohibited", sector)
+		}
+	}
+
+	return passedShariaRule(rule, "sector %s is permitted", sector)
+}
+
+func checkProhibitedKeywords(ruleSet *entities.ShariaScreeningRuleSet, subject *entities.ShariaScreeningSubject) entities.ShariaRuleResult {
+	rule := entities.ShariaRuleProhibitedKeyword
+	if len(ruleSet.ProhibitedKeywords) == 0 {
+		return notApplicableShariaRule(rule, "no prohibited keywords configured")
+	}
+
+	text := strings.Join(nonEmpty(subject.BusinessName, subject.Sector, subject.Activity), "\n")
+	if text == "" {
+		return notApplicableShariaRule(rule, "no business activity given")
+	}
+
+	matches := matchShariaPhrases(text, ruleSet.ProhibitedKeywords)
+	if len(matches) > 0 {
+		return failedShariaRule(rule, "business activity mentions prohibited %s", strings.Join(matches, ", "))
+	}
+
+	return passedShariaRule(rule, "business activity mentions no prohibited keywords")
+}
+
+// checkDebtToAssetRatio limits how much of the business is financed by debt
+func checkDebtToAssetRatio(ruleSet *entities.ShariaScreeningRuleSet, subject *entities.ShariaScreeningSubject) entities.ShariaRuleResult {
+	rule := entities.ShariaRuleDebtToAssetRatio
+	if ruleSet.MaxDebtToAssetRatio == nil {
+		return notApplicableShariaRule(rule, "no debt-to-asset limit configured")
+	}
+	report := subject.FinancialReport
+	if report == nil || report.Assets <= 0 {
+		return notApplicableShariaRule(rule, "no assets reported")
+	}
+
+	ratio := report.Liabilities / report.Assets * 100
+	limit := *ruleSet.MaxDebtToAssetRatio
+	if ratio > limit {
+		return failedShariaRule(rule, "debt-to-asset ratio %.2f%% exceeds the %.2f%% limit", ratio, limit)
+	}
+
+	return passedShariaRule(rule, "debt-to-asset ratio %.2f%% is within the %.2f%% limit", ratio, limit)
+}
+
+// checkInterestIncomeRatio limits how much of the business's revenue comes from interest
+func checkInterestIncomeRatio(ruleSet *entities.ShariaScreeningRuleSet, subject *entities.ShariaScreeningSubject) entities.ShariaRuleResult {
+	rule := entities.ShariaRuleInterestIncomeRatio
+	if ruleSet.MaxInterestIncomeRatio == nil {
+		return notApplicableShariaRule(rule, "no interest income limit configured")
+	}
+	report := subject.FinancialReport
+	if report == nil || report.TotalRevenue <= 0 {
+		return notApplicableShariaRule(rule, "no revenue reported")
+	}
+
+	ratio := report.InterestIncome / report.TotalRevenue * 100
+	limit := *ruleSet.MaxInterestIncomeRatio
+	if ratio > limit {
+		return failedShariaRule(rule, "interest income is %.2f%% of revenue, above the %.2f%% limit", ratio, limit)
+	}
+
+	return passedShariaRule(rule, "interest income is %.2f%% of revenue, within the %.2f%% limit", ratio, limit)
+}
+
+// checkGuaranteedReturn rejects profit terms that promise investors a return
+// regardless of how the business performs
+func checkGuaranteedReturn(ruleSet *entities.ShariaScreeningRuleSet, subject *entities.ShariaScreeningSubject) entities.ShariaRuleResult {
+	rule := entities.ShariaRuleGuaranteedReturn
+	if subject.FixedReturnRate != nil && *subject.FixedReturnRate > 0 {
+		return failedShariaRule(rule, "profit terms promise a fixed return of %.2f%%", *subject.FixedReturnRate)
+	}
+	if strings.TrimSpace(subject.ProfitTerms) == "" {
+		if subject.FixedReturnRate != nil {
+			return passedShariaRule(rule, "profit terms promise no fixed return")
+		}
+		return notApplicableShariaRule(rule, "no profit terms given")
+	}
+	if len(ruleSet.GuaranteedReturnPhrases) == 0 {
+		return notApplicableShariaRule(rule, "no guaranteed return phrases configured")
+	}
+
+	matches := matchShariaPhrases(subject.ProfitTerms, ruleSet.GuaranteedReturnPhrases)
+	if len(matches) > 0 {
+		return failedShariaRule(rule, "profit terms promise a return: %s", strings.Join(matches, ", "))
+	}
+
+	return passedShariaRule(rule, "profit terms promise no guaranteed return")
+}
+
+// matchShariaPhrases returns the phrases that appear in text as whole words,
+// ignoring case
+func matchShariaPhrases(text string, phrases []string) []string {
+	var matches []string
+	for _, phrase := range phrases {
+		pattern := `(?i)\b` + regexp.QuoteMeta(phrase) + `\b`
+		if regexp.MustCompile(pattern).MatchString(text) {
+			matches = append(matches, phrase)
+		}
+	}
+	return matches
+}
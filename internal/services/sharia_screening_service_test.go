@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func shariaRuleStatus(t *testing.T, result *entities.ShariaScreeningResult, rule string) entities.ShariaRuleResult {
+	for _, r := range result.Rules {
+		if r.Rule == rule {
+			return r
+		}
+	}
+	t.Fatalf("rule %s not evaluated", rule)
+	return entities.ShariaRuleResult{}
+}
+
+func TestShariaScreeningService_Evaluate(t *testing.T) {
+	fixedRate := 8.0
+
+	testCases := []struct {
+		name      string
+		subject   *entities.ShariaScreeningSubject
+		compliant bool
+		status    string            // status of every rule, when they agree
+		details   map[string]string // part of the explanation of a rule
+		failures  int
+	}{
+		{
+			name: "Compliant under the default rules",
+			subject: &entities.ShariaScreeningSubject{
+				BusinessName:       "Kopi Nusantara",
+				Sector:             "Food and Beverage",
+				Activity:           "Coffee roasting and distribution",
+				ProfitTerms:        "Investors share 70% of net profit each quarter",
+				ProfitSharingRatio: map[string]float64{"investor": 70, "business": 25, "cooperative": 5},
+				FinancialReport:    &entities.BusinessFinancialReport{TotalRevenue: 1000, InterestIncome: 20, Assets: 5000, Liabilities: 1500},
+			},
+			compliant: true,
+			status:    entities.ShariaRuleStatusPassed,
+			details:   map[string]string{entities.ShariaRuleDebtToAssetRatio: "30.00%"},
+		},
+		{
+			name: "Failures are explained",
+			subject: &entities.ShariaScreeningSubject{
+				BusinessName:       "Lucky Star Resort",
+				Sector:             "conventional-finance",
+				Activity:           "Hotel with a casino floor",
+				ProfitTerms:        "A guaranteed return of 12% a year",
+				ProfitSharingRatio: map[string]float64{"investor": 70, "business": 20},
+				FinancialReport:    &entities.BusinessFinancialReport{TotalRevenue: 1000, InterestIncome: 150, Assets: 1000, Liabilities: 600},
+			},
+			status: entities.ShariaRuleStatusFailed,
+			details: map[string]string{
+				entities.ShariaRuleProhibitedKeyword:   "casino",
+				entities.ShariaRuleInterestIncomeRatio: "15.00%",
+				entities.ShariaRuleGuaranteedReturn:    "guaranteed return",
+			},
+			failures: 6,
+		},
+		{
+			name:      "Missing inputs are not applicable",
+			subject:   &entities.ShariaScreeningSubject{},
+			compliant: true,
+			status:    entities.ShariaRuleStatusNotApplicable,
+		},
+		{
+			name:     "Fixed return rate",
+			subject:  &entities.ShariaScreeningSubject{FixedReturnRate: &fixedRate},
+			details:  map[string]string{entities.ShariaRuleGuaranteedReturn: "8.00%"},
+			failures: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			screeningRepo := new(MockShariaScreeningRepository)
+			mockAuditService := new(MockAuditService)
+			mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+			screeningService := NewShariaScreeningService(screeningRepo, mockAuditService)
+			ctx := context.Background()
+			cooperativeID := uuid.New()
+			screeningRepo.On("ListActiveRuleSets", mock.Anything, cooperativeID).Return([]*entities.ShariaScreeningRuleSet{}, nil)
+
+			evaluation, err := screeningService.Evaluate(ctx, cooperativeID, tc.subject)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.compliant, evaluation.Compliant)
+			require.Len(t, evaluation.Results, 1)
+			result := evaluation.Results[0]
+			assert.Equal(t, "DSN-MUI", result.SupervisoryBoard)
+			if tc.status != "" {
+				for _, rule := range result.Rules {
+					assert.Equal(t, tc.status, rule.Status, rule.Rule)
+				}
+			}
+			for rule, detail := range tc.details {
+				assert.Contains(t, shariaRuleStatus(t, result, rule).Detail, detail)
+			}
+			assert.Len(t, evaluation.Failures(), tc.failures)
+			for _, failure := range evaluation.Failures() {
+				assert.Contains(t, failure, "DSN-MUI: ")
+			}
+		})
+	}
+}
+
+func TestShariaScreeningService_Evaluate_EveryBoardMustPass(t *testing.T) {
+	screeningRepo := new(MockShariaScreeningRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	screeningService := NewShariaScreeningService(screeningRepo, mockAuditService)
+	ctx := context.Background()
+	cooperativeID := uuid.New()
+
+	strictDebt := 30.0
+	screeningRepo.On("ListActiveRuleSets", mock.Anything, cooperativeID).Return([]*entities.ShariaScreeningRuleSet{
+		defaultShariaScreeningRuleSet(cooperativeID),
+		{ID: uuid.New(), CooperativeID: cooperativeID, SupervisoryBoard: "DPS Koperasi", Name: "Board rules", MaxDebtToAssetRatio: &strictDebt, IsActive: true},
+	}, nil)
+
+	evaluation, err := screeningService.Evaluate(ctx, cooperativeID, &entities.ShariaScreeningSubject{
+		Sector:          "agriculture",
+		FinancialReport: &entities.BusinessFinancialReport{Assets: 1000, Liabilities: 400},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, evaluation.Results, 2)
+	assert.True(t, evaluation.Results[0].Compliant)
+	assert.False(t, evaluation.Results[1].Compliant)
+	assert.False(t, evaluation.Compliant)
+	assert.Equal(t, []string{"DPS Koperasi: debt-to-asset ratio 40.00% exceeds the 30.00% limit"}, evaluation.Failures())
+}
+
+func TestShariaScreeningService_CreateRuleSet(t *testing.T) {
+	screeningRepo := new(MockShariaScreeningRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	screeningService := NewShariaScreeningService(screeningRepo, mockAuditService)
+	ctx := context.Background()
+	cooperativeID := uuid.New()
+	screeningRepo.On("ListActiveRuleSets", mock.Anything, cooperativeID).Return([]*entities.ShariaScreeningRuleSet{
+		{ID: uuid.New(), CooperativeID: cooperativeID, SupervisoryBoard: "DSN-MUI", IsActive: true},
+	}, nil)
+	screeningRepo.On("CreateRuleSet", mock.Anything, mock.AnythingOfType("*entities.ShariaScreeningRuleSet")).Return(nil)
+
+	_, err := screeningService.CreateRuleSet(ctx, cooperativeID, &entities.CreateShariaScreeningRuleSetRequest{
+		SupervisoryBoard: "dsn-mui",
+		Name:             "Duplicate board",
+	}, uuid.New())
+	require.Error(t, err)
+
+	ruleSet, err := screeningService.CreateRuleSet(ctx, cooperativeID, &entities.CreateShariaScreeningRuleSetRequest{
+		SupervisoryBoard:   " DPS Koperasi ",
+		Name:               "Board rules",
+		ProhibitedSectors:  []string{"Tobacco Products", "tobacco-products", ""},
+		ProhibitedKeywords: []string{"Cigarette", "cigarette"},
+	}, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, "DPS Koperasi", ruleSet.SupervisoryBoard)
+	assert.Equal(t, []string{"tobacco_products"}, ruleSet.ProhibitedSectors)
+	assert.Equal(t, []string{"cigarette"}, ruleSet.ProhibitedKeywords)
+	screeningRepo.AssertCalled(t, "CreateRuleSet", mock.Anything, ruleSet)
+}
+
+func TestShariaScreeningService_ScreenProject(t *testing.T) {
+	screeningRepo := new(MockShariaScreeningRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	screeningService := NewShariaScreeningService(screeningRepo, mockAuditService)
+	ctx := context.Background()
+	cooperativeID := uuid.New()
+	projectID := uuid.New()
+	screeningRepo.On("GetProjectActivity", mock.Anything, projectID).Return(&entities.ShariaProjectActivity{
+		ProjectID:           projectID,
+		CooperativeID:       cooperativeID,
+		ProjectTitle:        "Second outlet",
+		ProjectDescription:  "Open a second outlet. Investors receive a fixed return every month.",
+		BusinessName:        "Bakso Pak Kumis",
+		BusinessType:        "restaurant",
+		BusinessDescription: "Meatball restaurant",
+	}, nil)
+	screeningRepo.On("ListActiveRuleSets", mock.Anything, cooperativeID).Return([]*entities.ShariaScreeningRuleSet{}, nil)
+
+	evaluation, err := screeningService.ScreenProject(ctx, projectID, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, cooperativeID, evaluation.CooperativeID)
+	require.NotNil(t, evaluation.ProjectID)
+	assert.False(t, evaluation.Compliant)
+	result := evaluation.Results[0]
+	assert.Equal(t, entities.ShariaRuleStatusPassed, shariaRuleStatus(t, result, entities.ShariaRuleProhibitedSector).Status)
+	assert.Equal(t, entities.ShariaRuleStatusFailed, shariaRuleStatus(t, result, entities.ShariaRuleGuaranteedReturn).Status)
+
+	// Policy validation reports the screening failures as violations
+	policyService := NewInvestmentPolicyService(mockAuditService, screeningService)
+	compliant, violations, err := policyService.ValidatePolicyCompliance(ctx, projectID, uuid.New())
+	require.NoError(t, err)
+	assert.False(t, compliant)
+	assert.Equal(t, []string{"DSN-MUI: profit terms promise a return: fixed return"}, violations)
+}
+
+func TestProfitSharingService_CreateProfitCalculation_ShariaScreening(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	profitService := NewProfitSharingService(mockAuditService, nil, nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	req := &entities.CreateProfitCalculationRequest{
+		ProjectID:          uuid.New(),
+		CalculationPeriod:  "quarterly",
+		StartDate:          time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:            time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		TotalRevenue:       idr("1000"),
+		TotalExpenses:      idr("200"),
+		ProfitSharingRatio: map[string]float64{"investor": 70, "business": 25, "cooperative": 5},
+	}
+
+	// A high margin alone is not a Sharia concern
+	calculation, err := profitService.CreateProfitCalculation(ctx, req, uuid.New())
+	require.NoError(t, err)
+	assert.True(t, calculation.ShariaCompliant)
+	require.NotNil(t, calculation.ShariaScreening)
+
+	req.ProfitSharingRatio = map[string]float64{"investor": 70, "business": 25}
+	calculation, err = profitService.CreateProfitCalculation(ctx, req, uuid.New())
+	require.NoError(t, err)
+	assert.False(t, calculation.ShariaCompliant)
+	assert.Equal(t, []string{"DSN-MUI: profit sharing ratios add up to 95.00%, not 100%"}, calculation.ShariaScreening.Failures())
+}
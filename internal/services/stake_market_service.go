@@ -0,0 +1,539 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// StakeMarketService lets members sell all or part of an active investment to
+// other members of the same cooperative before the project ends. A seller
+// lists a stake at an asking price; members buy at the ask or bid, and the
+// seller accepts a bid. The agreed trade is settled between the members and
+// approved by the cooperative, at which point the capital, its future profit
+// shares and the project's ownership move to the buyer together.
+type StakeMarketService interface {
+	CreateListing(ctx context.Context, investmentID uuid.UUID, req *entities.CreateStakeListingRequest, sellerID uuid.UUID) (*entities.StakeListing, error)
+	CancelListing(ctx context.Context, projectID, listingID, sellerID uuid.UUID) (*entities.StakeListing, error)
+	// GetListing returns a listing with its bids
+	GetListing(ctx context.Context, projectID, listingID uuid.UUID) (*entities.StakeListing, error)
+	GetCooperativeListings(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.StakeListing, error)
+
+	// PlaceBid bids for a listed stake on behalf of an eligible member
+	PlaceBid(ctx context.Context, projectID, listingID uuid.UUID, req *entities.PlaceStakeBidRequest, buyerID uuid.UUID) (*entities.StakeBid, error)
+	WithdrawBid(ctx context.Context, projectID, bidID, buyerID uuid.UUID) (*entities.StakeBid, error)
+	// AcceptBid agrees a trade with the bidder, subject to the cooperative's approval
+	AcceptBid(ctx context.Context, projectID, bidID, sellerID uuid.UUID) (*entities.StakeTrade, error)
+	// BuyAtAsk agrees a trade at the listing's asking price, subject to the
+	// cooperative's approval
+	BuyAtAsk(ctx context.Context, projectID, listingID, buyerID uuid.UUID) (*entities.StakeTrade, error)
+
+	// ReviewTrade approves or rejects a trade for the cooperative. Approval
+	// re-checks the buyer's eligibility and transfers the stake.
+	ReviewTrade(ctx context.Context, projectID, tradeID uuid.UUID, req *entities.ReviewStakeTradeRequest, reviewerID uuid.UUID) (*entities.StakeTrade, error)
+	GetTrade(ctx context.Context, projectID, tradeID uuid.UUID) (*entities.StakeTrade, error)
+	GetCooperativeTrades(ctx context.Context, cooperativeID uuid.UUID, status string) ([]*entities.StakeTrade, error)
+	// ResumeCompletingTrades records the transfers of trades whose stake moved
+	// but was not recorded in the ledger, returning how many completed
+	ResumeCompletingTrades(ctx context.Context) (int, error)
+}
+
+type stakeMarketService struct {
+	marketRepo      repositories.StakeMarketRepository
+	policyService   InvestmentPolicyService
+	memberService   MemberRegistryService
+	ledgerService   LedgerService
+	currencyService CurrencyService
+	auditService    AuditService
+}
+
+// NewStakeMarketService creates a new stake market service. Buyers must stay
+// within the investment limits of the cooperative's active policies and, when
+// memberService is set, be active members of the cooperative.
+func NewStakeMarketService(marketRepo repositories.StakeMarketRepository, policyService InvestmentPolicyService, memberService MemberRegistryService, ledgerService LedgerService, currencyService CurrencyService, auditService AuditService) StakeMarketService {
+	return &stakeMarketService{
+		marketRepo:      marketRepo,
+		policyService:   policyService,
+		memberService:   memberService,
+		ledgerService:   ledgerService,
+		currencyService: currencyService,
+		auditService:    auditService,
+	}
+}
+
+func (s *stakeMarketService) CreateListing(ctx context.Context, investmentID uuid.UUID, req *entities.CreateStakeListingRequest, sellerID uuid.UUID) (*entities.StakeListing, error) {
+	position, err := s.marketRepo.GetInvestmentPosition(ctx, req.ProjectID, investmentID)
+	if err != nil {
+		return nil, err
+	}
+	if position.InvestorID != sellerID {
+		return nil, errors.New("investment does not belong to the investor")
+	}
+	if position.Status != "confirmed" {
+		return nil, fmt.Errorf("investment is %s; only active investments can be listed", position.Status)
+	}
+
+	currency := position.Currency
+	if currency == "" {
+		currency = s.currencyService.BaseCurrency()
+	}
+	invested := position.Amount.WithCurrency(currency)
+
+	amount := invested
+	if req.Amount != nil {
+		if amount, err = req.Amount.InCurrency(currency); err != nil {
+			return nil, fmt.Errorf("invalid listed amount: %w", err)
+		}
+		if !amount.IsPositive() {
+			return nil, errors.New("listed amount must be positive")
+		}
+		if amount.GreaterThan(invested) {
+			return nil, fmt.Errorf("listed amount exceeds the investment of %s", invested)
+		}
+	}
+
+	askPrice, err := req.AskPrice.InCurrency(currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ask price: %w", err)
+	}
+	if err := checkStakePrice(position.ContractType, amount, askPrice); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	listing := &entities.StakeListing{
+		ID:            uuid.New(),
+		InvestmentID:  investmentID,
+		SellerID:      sellerID,
+		ProjectID:     position.ProjectID,
+		CooperativeID: position.CooperativeID,
+		Currency:      currency,
+		Amount:        amount,
+		AskPrice:      askPrice,
+		Status:        entities.StakeListingStatusOpen,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.marketRepo.CreateListing(ctx, listing); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     sellerID,
+		Operation:  "create_stake_listing",
+		EntityType: entities.AuditEntityInvestment,
+		EntityID:   investmentID,
+		NewValues:  listing,
+	})
+
+	return listing, nil
+}
+
+// checkStakePrice validates the price of a stake. A murabahah stake is a share
+// of a receivable, which may only change hands at face value.
+func checkStakePrice(contractType string, amount, price entities.Money) error {
+	if !price.IsPositive() {
+		return errors.New("price must be positive")
+	}
+	if contractType == entities.ContractTypeMurabahah && !price.Equal(amount) {
+		return fmt.Errorf("murabahah stakes trade at face value of %s", amount)
+	}
+	return nil
+}
+
+func (s *stakeMarketService) CancelListing(ctx context.Context, projectID, listingID, sellerID uuid.UUID) (*entities.StakeListing, error) {
+	listing, err := s.marketRepo.GetListing(ctx, projectID, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if listing.SellerID != sellerID {
+		return nil, errors.New("listing does not belong to the investor")
+	}
+	if listing.Status != entities.StakeListingStatusOpen {
+		return nil, fmt.Errorf("listing is %s and can no longer be cancelled", listing.Status)
+	}
+
+	listing.Status = entities.StakeListingStatusCancelled
+	listing.UpdatedAt = time.Now()
+
+	updated, err := s.marketRepo.UpdateListingStatus(ctx, listing, entities.StakeListingStatusOpen)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("listing was updated concurrently")
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     sellerID,
+		Operation:  "cancel_stake_listing",
+		EntityType: entities.AuditEntityInvestment,
+		EntityID:   listing.InvestmentID,
+		NewValues:  listing,
+	})
+
+	return listing, nil
+}
+
+func (s *stakeMarketService) GetListing(ctx context.Context, projectID, listingID uuid.UUID) (*entities.StakeListing, error) {
+	listing, err := s.marketRepo.GetListing(ctx, projectID, listingID)
+	if err != nil {
+		return nil, err
+	}
+
+	bids, err := s.marketRepo.ListBids(ctx, projectID, listingID)
+	if err != nil {
+		return nil, err
+	}
+	for _, bid := range bids {
+		bid.Price = bid.Price.WithCurrency(listing.Currency)
+	}
+	listing.Bids = bids
+
+	return listing, nil
+}
+
+func (s *stakeMarketService) GetCooperativeListings(ctx context.Context, cooperativeID uuid.UUID) ([]*entities.StakeListing, error) {
+	return s.marketRepo.ListOpenListings(ctx, cooperativeID)
+}
+
+func (s *stakeMarketService) PlaceBid(ctx context.Context, projectID, listingID uuid.UUID, req *entities.PlaceStakeBidRequest, buyerID uuid.UUID) (*entities.StakeBid, error) {
+	listing, err := s.marketRepo.GetListing(ctx, projectID, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if listing.Status != entities.StakeListingStatusOpen {
+		return nil, fmt.Errorf("listing is %s and no longer accepts bids", listing.Status)
+	}
+
+	price, err := req.Price.InCurrency(listing.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bid price: %w", err)
+	}
+	if err := s.checkListingPrice(ctx, listing, price); err != nil {
+		return nil, err
+	}
+	if err := s.checkBuyer(ctx, listing.ProjectID, listing.CooperativeID, listing.SellerID, buyerID, listing.Amount); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	bid := &entities.StakeBid{
+		ID:        uuid.New(),
+		ListingID: listing.ID,
+		ProjectID: listing.ProjectID,
+		BuyerID:   buyerID,
+		Price:     price,
+		Status:    entities.StakeBidStatusOpen,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.marketRepo.CreateBid(ctx, bid); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     buyerID,
+		Operation:  "place_stake_bid",
+		EntityType: entities.AuditEntityInvestment,
+		EntityID:   listing.InvestmentID,
+		NewValues:  bid,
+	})
+
+	return bid, nil
+}
+
+// checkListingPrice validates a price offered for a listing against the
+// project's contract
+func (s *stakeMarketService) checkListingPrice(ctx context.Context, listing *entities.StakeListing, price entities.Money) error {
+	position, err := s.marketRepo.GetInvestmentPosition(ctx, listing.ProjectID, listing.InvestmentID)
+	if err != nil {
+		return err
+	}
+	return checkStakePrice(position.ContractType, listing.Amount, price)
+}
+
+// checkBuyer checks that a member may take on a stake: they must be an active
+// member of the cooperative other than the seller, and their holding in the
+// project after the trade must be within every active investment policy's
+// limits
+func (s *stakeMarketService) checkBuyer(ctx context.Context, projectID, cooperativeID, sellerID, buyerID uuid.UUID, amount entities.Money) error {
+	if buyerID == sellerID {
+		return errors.New("members cannot buy their own stake")
+	}
+
+	if s.memberService != nil {
+		active, err := s.memberService.CheckMemberActiveStatus(ctx, cooperativeID, buyerID)
+		if err != nil {
+			return fmt.Errorf("failed to check membership: %w", err)
+		}
+		if !active {
+			return errors.New("only active members of the cooperative can buy stakes")
+		}
+	}
+
+	holding := entities.ZeroMoney(amount.Currency())
+	position, err := s.marketRepo.GetInvestorPosition(ctx, projectID, buyerID)
+	if err != nil {
+		return err
+	}
+	if position != nil {
+		switch position.Status {
+		case "pending":
+			return errors.New("buyer has a pending investment in the project")
+		case "confirmed":
+			holding = position.Amount.WithCurrency(amount.Currency())
+		}
+	}
+	holding = holding.Add(amount)
+
+	if s.policyService == nil {
+		return nil
+	}
+	policies, err := s.policyService.GetActiveInvestmentPolicies(ctx, cooperativeID)
+	if err != nil {
+		return fmt.Errorf("failed to get investment policies: %w", err)
+	}
+	for _, policy := range policies {
+		if err := entities.CheckSameCurrency(holding, policy.MinInvestmentAmount, policy.MaxInvestmentAmount); err != nil {
+			return fmt.Errorf("policy %s limits investments in another currency: %w", policy.Name, err)
+		}
+		if limit := policy.MaxInvestmentAmount.WithCurrency(amount.Currency()); limit.IsPositive() && holding.GreaterThan(limit) {
+			return fmt.Errorf("buyer's investment of %s would exceed the maximum of %s under policy %s", holding, limit, policy.Name)
+		}
+		if limit := policy.MinInvestmentAmount.WithCurrency(amount.Currency()); holding.LessThan(limit) {
+			return fmt.Errorf("buyer's investment of %s would be below the minimum of %s under policy %s", holding, limit, policy.Name)
+		}
+	}
+
+	return nil
+}
+
+func (s *stakeMarketService) WithdrawBid(ctx context.Context, projectID, bidID, buyerID uuid.UUID) (*entities.StakeBid, error) {
+	bid, err := s.marketRepo.GetBid(ctx, projectID, bidID)
+	if err != nil {
+		return nil, err
+	}
+	if bid.BuyerID != buyerID {
+		return nil, errors.New("bid does not belong to the member")
+	}
+	if bid.Status != entities.StakeBidStatusOpen {
+		return nil, fmt.Errorf("bid is %s and can no longer be withdrawn", bid.Status)
+	}
+
+	bid.Status = entities.StakeBidStatusWithdrawn
+	bid.UpdatedAt = time.Now()
+
+	updated, err := s.marketRepo.UpdateBidStatus(ctx, bid, entities.StakeBidStatusOpen)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("bid was updated concurrently")
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     buyerID,
+		Operation:  "withdraw_stake_bid",
+		EntityType: entities.AuditEntityInvestment,
+		EntityID:   bid.ListingID,
+		NewValues:  bid,
+	})
+
+	return bid, nil
+}
+
+func (s *stakeMarketService) AcceptBid(ctx context.Context, projectID, bidID, sellerID uuid.UUID) (*entities.StakeTrade, error) {
+	bid, err := s.marketRepo.GetBid(ctx, projectID, bidID)
+	if err != nil {
+		return nil, err
+	}
+	if bid.Status != entities.StakeBidStatusOpen {
+		return nil, fmt.Errorf("bid is %s and can no longer be accepted", bid.Status)
+	}
+
+	listing, err := s.marketRepo.GetListing(ctx, projectID, bid.ListingID)
+	if err != nil {
+		return nil, err
+	}
+	if listing.SellerID != sellerID {
+		return nil, errors.New("listing does not belong to the investor")
+	}
+
+	return s.agreeTrade(ctx, listing, &bid.ID, bid.BuyerID, bid.Price.WithCurrency(listing.Currency), sellerID)
+}
+
+func (s *stakeMarketService) BuyAtAsk(ctx context.Context, projectID, listingID, buyerID uuid.UUID) (*entities.StakeTrade, error) {
+	listing, err := s.marketRepo.GetListing(ctx, projectID, listingID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.agreeTrade(ctx, listing, nil, buyerID, listing.AskPrice, buyerID)
+}
+
+// agreeTrade records a trade for the listing at price, taking the listing off
+// the market until the cooperative reviews it
+func (s *stakeMarketService) agreeTrade(ctx context.Context, listing *entities.StakeListing, bidID *uuid.UUID, buyerID uuid.UUID, price entities.Money, userID uuid.UUID) (*entities.StakeTrade, error) {
+	if listing.Status != entities.StakeListingStatusOpen {
+		return nil, fmt.Errorf("listing is %s and can no longer be traded", listing.Status)
+	}
+	if err := s.checkBuyer(ctx, listing.ProjectID, listing.CooperativeID, listing.SellerID, buyerID, listing.Amount); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	trade := &entities.StakeTrade{
+		ID:                 uuid.New(),
+		ListingID:          listing.ID,
+		BidID:              bidID,
+		ProjectID:          listing.ProjectID,
+		CooperativeID:      listing.CooperativeID,
+		SellerID:           listing.SellerID,
+		BuyerID:            buyerID,
+		SellerInvestmentID: listing.InvestmentID,
+		Currency:           listing.Currency,
+		Amount:             listing.Amount,
+		Price:              price,
+		Status:             entities.StakeTradeStatusPendingApproval,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := s.marketRepo.CreateTrade(ctx, trade); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "agree_stake_trade",
+		EntityType: entities.AuditEntityInvestment,
+		EntityID:   listing.InvestmentID,
+		NewValues:  trade,
+	})
+
+	return trade, nil
+}
+
+// ReviewTrade moves an approved trade's stake in the transaction that claims
+// the trade, so that a stake cannot be transferred twice, and then records the
+// transfer in the ledger, which is on the cooperative's shard. A trade whose
+// transfer cannot be recorded stays completing and is recorded by
+// ResumeCompletingTrades.
+func (s *stakeMarketService) ReviewTrade(ctx context.Context, projectID, tradeID uuid.UUID, req *entities.ReviewStakeTradeRequest, reviewerID uuid.UUID) (*entities.StakeTrade, error) {
+	trade, err := s.marketRepo.GetTrade(ctx, projectID, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	if trade.Status != entities.StakeTradeStatusPendingApproval {
+		return nil, fmt.Errorf("trade is %s and cannot be reviewed", trade.Status)
+	}
+
+	now := time.Now()
+	trade.ReviewedBy = &reviewerID
+	trade.ReviewedAt = &now
+	trade.ReviewNotes = req.Notes
+	trade.UpdatedAt = now
+
+	if !req.Approve {
+		trade.Status = entities.StakeTradeStatusRejected
+		if err := s.marketRepo.RejectTrade(ctx, trade); err != nil {
+			return nil, err
+		}
+
+		s.auditService.LogOperation(ctx, &LogOperationRequest{
+			UserID:     reviewerID,
+			Operation:  "reject_stake_trade",
+			EntityType: entities.AuditEntityInvestment,
+			EntityID:   trade.SellerInvestmentID,
+			NewValues:  trade,
+		})
+		return trade, nil
+	}
+
+	// Membership and policy limits may have changed since the trade was agreed
+	if err := s.checkBuyer(ctx, trade.ProjectID, trade.CooperativeID, trade.SellerID, trade.BuyerID, trade.Amount); err != nil {
+		return nil, err
+	}
+
+	trade.Status = entities.StakeTradeStatusCompleting
+	trade.PaymentReference = req.PaymentReference
+	if err := s.marketRepo.TransferTrade(ctx, trade); err != nil {
+		return nil, err
+	}
+
+	if err := s.settleTrade(ctx, trade, reviewerID); err != nil {
+		return nil, fmt.Errorf("trade %s was transferred but not recorded in the ledger and will be retried: %w", trade.ID, err)
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     reviewerID,
+		Operation:  "complete_stake_trade",
+		EntityType: entities.AuditEntityInvestment,
+		EntityID:   trade.SellerInvestmentID,
+		NewValues:  trade,
+	})
+
+	return trade, nil
+}
+
+// settleTrade records a transferred trade in the ledger, unless an earlier
+// attempt did, and marks it completed
+func (s *stakeMarketService) settleTrade(ctx context.Context, trade *entities.StakeTrade, posterID uuid.UUID) error {
+	if _, err := s.ledgerService.RecordStakeTransfer(ctx, trade, posterID); err != nil && !errors.Is(err, repositories.ErrStakeTransferPosted) {
+		return fmt.Errorf("failed to record stake transfer in ledger: %w", err)
+	}
+
+	trade.UpdatedAt = time.Now()
+	if err := s.marketRepo.FinishTrade(ctx, trade); err != nil {
+		return err
+	}
+	trade.Status = entities.StakeTradeStatusCompleted
+	return nil
+}
+
+func (s *stakeMarketService) ResumeCompletingTrades(ctx context.Context) (int, error) {
+	trades, err := s.marketRepo.ListCompletingTrades(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	completed := 0
+	var errs []error
+	for _, trade := range trades {
+		// Trades claimed before approvals moved the stake were never transferred
+		if trade.BuyerInvestmentID == nil || trade.ReviewedBy == nil {
+			errs = append(errs, fmt.Errorf("trade %s is completing without a transferred stake", trade.ID))
+			continue
+		}
+
+		if err := s.settleTrade(ctx, trade, *trade.ReviewedBy); err != nil {
+			errs = append(errs, fmt.Errorf("trade %s: %w", trade.ID, err))
+			continue
+		}
+		completed++
+
+		s.auditService.LogOperation(ctx, &LogOperationRequest{
+			UserID:     *trade.ReviewedBy,
+			Operation:  "complete_stake_trade",
+			EntityType: entities.AuditEntityInvestment,
+			EntityID:   trade.SellerInvestmentID,
+			NewValues:  trade,
+		})
+	}
+
+	return completed, errors.Join(errs...)
+}
+
+func (s *stakeMarketService) GetTrade(ctx context.Context, projectID, tradeID uuid.UUID) (*entities.StakeTrade, error) {
+	return s.marketRepo.GetTrade(ctx, projectID, tradeID)
+}
+
+func (s *stakeMarketService) GetCooperativeTrades(ctx context.Context, cooperativeID uuid.UUID, status string) ([]*entities.StakeTrade, error) {
+	return s.marketRepo.ListCooperativeTrades(ctx, cooperativeID, status)
+}
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubMemberRegistryService treats a fixed set of users as active members
+type stubMemberRegistryService struct {
+	MemberRegistryService
+	active map[uuid.UUID]bool
+}
+
+func (s *stubMemberRegistryService) CheckMemberActiveStatus(ctx context.Context, cooperativeID, userID uuid.UUID) (bool, error) {
+	return s.active[userID], nil
+}
+
+func TestStakeMarketService_CreateListing_PartOfInvestment(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	marketRepo := new(MockStakeMarketRepository)
+	marketService := NewStakeMarketService(marketRepo, nil, nil, ledgerService, currencyService, mockAuditService)
+	ctx := context.Background()
+
+	position := testInvestmentPosition("10000")
+	marketRepo.On("GetInvestmentPosition", ctx, position.ProjectID, position.InvestmentID).Return(position, nil)
+	marketRepo.On("CreateListing", ctx, mock.AnythingOfType("*entities.StakeListing")).Return(nil)
+
+	amount := idr("4000")
+	listing, err := marketService.CreateListing(ctx, position.InvestmentID, &entities.CreateStakeListingRequest{
+		ProjectID: position.ProjectID,
+		Amount:    &amount,
+		AskPrice:  idr("4200"),
+	}, position.InvestorID)
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.StakeListingStatusOpen, listing.Status)
+	assert.Equal(t, idr("4000"), listing.Amount)
+	assert.Equal(t, idr("4200"), listing.AskPrice)
+	assert.Equal(t, position.CooperativeID, listing.CooperativeID)
+}
+
+func TestStakeMarketService_CreateListing_MurabahahAtFaceValue(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	marketRepo := new(MockStakeMarketRepository)
+	marketService := NewStakeMarketService(marketRepo, nil, nil, ledgerService, currencyService, mockAuditService)
+	ctx := context.Background()
+
+	position := testInvestmentPosition("10000")
+	position.ContractType = entities.ContractTypeMurabahah
+	marketRepo.On("GetInvestmentPosition", ctx, position.ProjectID, position.InvestmentID).Return(position, nil)
+
+	_, err := marketService.CreateListing(ctx, position.InvestmentID, &entities.CreateStakeListingRequest{
+		ProjectID: position.ProjectID,
+		AskPrice:  idr("10500"),
+	}, position.InvestorID)
+	assert.ErrorContains(t, err, "face value")
+	marketRepo.AssertNotCalled(t, "CreateListing", mock.Anything, mock.Anything)
+}
+
+func TestStakeMarketService_PlaceBid_NonMember(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	marketRepo := new(MockStakeMarketRepository)
+	marketService := NewStakeMarketService(marketRepo, nil, &stubMemberRegistryService{}, ledgerService, currencyService, mockAuditService)
+	ctx := context.Background()
+
+	listing := &entities.StakeListing{
+		ID:            uuid.New(),
+		InvestmentID:  uuid.New(),
+		SellerID:      uuid.New(),
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		Currency:      "IDR",
+		Amount:        idr("5000"),
+		AskPrice:      idr("5000"),
+		Status:        entities.StakeListingStatusOpen,
+	}
+	marketRepo.On("GetListing", ctx, listing.ProjectID, listing.ID).Return(listing, nil)
+	marketRepo.On("GetInvestmentPosition", ctx, listing.ProjectID, listing.InvestmentID).Return(testInvestmentPosition("10000"), nil)
+
+	_, err := marketService.PlaceBid(ctx, listing.ProjectID, listing.ID, &entities.PlaceStakeBidRequest{Price: idr("4800")}, uuid.New())
+	assert.ErrorContains(t, err, "active members")
+	marketRepo.AssertNotCalled(t, "CreateBid", mock.Anything, mock.Anything)
+}
+
+func TestStakeMarketService_PlaceBid_ExceedsPolicyMaximum(t *testing.T) {
+	policyService := &stubInvestmentPolicyService{policies: []*entities.InvestmentPolicyExtended{
+		{Name: "Standard", MinInvestmentAmount: idr("1000"), MaxInvestmentAmount: idr("10000")},
+	}}
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	marketRepo := new(MockStakeMarketRepository)
+	marketService := NewStakeMarketService(marketRepo, policyService, nil, ledgerService, currencyService, mockAuditService)
+	ctx := context.Background()
+
+	listing := &entities.StakeListing{
+		ID:            uuid.New(),
+		InvestmentID:  uuid.New(),
+		SellerID:      uuid.New(),
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		Currency:      "IDR",
+		Amount:        idr("5000"),
+		AskPrice:      idr("5000"),
+		Status:        entities.StakeListingStatusOpen,
+	}
+	buyerID := uuid.New()
+	holding := testInvestmentPosition("8000")
+	holding.InvestorID = buyerID
+	marketRepo.On("GetListing", ctx, listing.ProjectID, listing.ID).Return(listing, nil)
+	marketRepo.On("GetInvestmentPosition", ctx, listing.ProjectID, listing.InvestmentID).Return(testInvestmentPosition("10000"), nil)
+	marketRepo.On("GetInvestorPosition", ctx, listing.ProjectID, buyerID).Return(holding, nil)
+
+	// The buyer's existing 8000 plus the 5000 stake exceeds the 10000 maximum
+	_, err := marketService.PlaceBid(ctx, listing.ProjectID, listing.ID, &entities.PlaceStakeBidRequest{Price: idr("5000")}, buyerID)
+	assert.ErrorContains(t, err, "exceed the maximum")
+	marketRepo.AssertNotCalled(t, "CreateBid", mock.Anything, mock.Anything)
+}
+
+func TestStakeMarketService_AcceptBid(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	marketRepo := new(MockStakeMarketRepository)
+	marketService := NewStakeMarketService(marketRepo, nil, nil, ledgerService, currencyService, mockAuditService)
+	ctx := context.Background()
+
+	listing := &entities.StakeListing{
+		ID:            uuid.New(),
+		InvestmentID:  uuid.New(),
+		SellerID:      uuid.New(),
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		Currency:      "IDR",
+		Amount:        idr("5000"),
+		AskPrice:      idr("5500"),
+		Status:        entities.StakeListingStatusOpen,
+	}
+	bid := &entities.StakeBid{
+		ID:        uuid.New(),
+		ListingID: listing.ID,
+		ProjectID: listing.ProjectID,
+		BuyerID:   uuid.New(),
+		Price:     idr("5200"),
+		Status:    entities.StakeBidStatusOpen,
+	}
+	marketRepo.On("GetBid", ctx, listing.ProjectID, bid.ID).Return(bid, nil)
+	marketRepo.On("GetListing", ctx, listing.ProjectID, listing.ID).Return(listing, nil)
+	marketRepo.On("GetInvestorPosition", ctx, listing.ProjectID, bid.BuyerID).Return(nil, nil)
+	marketRepo.On("CreateTrade", ctx, mock.AnythingOfType("*entities.StakeTrade")).Return(nil)
+
+	trade, err := marketService.AcceptBid(ctx, listing.ProjectID, bid.ID, listing.SellerID)
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.StakeTradeStatusPendingApproval, trade.Status)
+	assert.Equal(t, bid.BuyerID, trade.BuyerID)
+	assert.Equal(t, idr("5000"), trade.Amount)
+	assert.Equal(t, idr("5200"), trade.Price)
+	require.NotNil(t, trade.BidID)
+	assert.Equal(t, bid.ID, *trade.BidID)
+}
+
+func TestStakeMarketService_BuyAtAsk_OwnListing(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	marketRepo := new(MockStakeMarketRepository)
+	marketService := NewStakeMarketService(marketRepo, nil, nil, ledgerService, currencyService, mockAuditService)
+	ctx := context.Background()
+
+	listing := &entities.StakeListing{
+		ID:            uuid.New(),
+		InvestmentID:  uuid.New(),
+		SellerID:      uuid.New(),
+		ProjectID:     uuid.New(),
+		CooperativeID: uuid.New(),
+		Currency:      "IDR",
+		Amount:        idr("5000"),
+		AskPrice:      idr("5000"),
+		Status:        entities.StakeListingStatusOpen,
+	}
+	marketRepo.On("GetListing", ctx, listing.ProjectID, listing.ID).Return(listing, nil)
+
+	_, err := marketService.BuyAtAsk(ctx, listing.ProjectID, listing.ID, listing.SellerID)
+	assert.ErrorContains(t, err, "own stake")
+	marketRepo.AssertNotCalled(t, "CreateTrade", mock.Anything, mock.Anything)
+}
+
+func TestStakeMarketService_ReviewTrade_Approve(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	marketRepo := new(MockStakeMarketRepository)
+	marketService := NewStakeMarketService(marketRepo, nil, nil, ledgerService, currencyService, mockAuditService)
+	ctx := context.Background()
+
+	trade := &entities.StakeTrade{
+		ID:                 uuid.New(),
+		ListingID:          uuid.New(),
+		ProjectID:          uuid.New(),
+		CooperativeID:      uuid.New(),
+		SellerID:           uuid.New(),
+		BuyerID:            uuid.New(),
+		SellerInvestmentID: uuid.New(),
+		Currency:           "IDR",
+		Amount:             idr("5000"),
+		Price:              idr("5100"),
+		Status:             entities.StakeTradeStatusPendingApproval,
+	}
+	marketRepo.On("GetTrade", ctx, trade.ProjectID, trade.ID).Return(trade, nil)
+	marketRepo.On("GetInvestorPosition", ctx, trade.ProjectID, trade.BuyerID).Return(nil, nil)
+	marketRepo.On("TransferTrade", ctx, trade).Return(nil)
+	marketRepo.On("FinishTrade", ctx, trade).Return(nil)
+	ledgerRepo.On("GetAccountByCode", ctx, trade.CooperativeID, mock.AnythingOfType("string"), "IDR").
+		Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}, nil)
+	var posted *entities.JournalEntry
+	ledgerRepo.On("PostEntry", ctx, mock.AnythingOfType("*entities.JournalEntry")).Run(func(args mock.Arguments) {
+		posted = args.Get(1).(*entities.JournalEntry)
+	}).Return(nil)
+
+	reviewed, err := marketService.ReviewTrade(ctx, trade.ProjectID, trade.ID, &entities.ReviewStakeTradeRequest{
+		Approve:          true,
+		PaymentReference: "TRF-20261018-01",
+	}, uuid.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.StakeTradeStatusCompleted, reviewed.Status)
+	assert.Equal(t, "TRF-20261018-01", reviewed.PaymentReference)
+	require.NotNil(t, reviewed.ReviewedBy)
+
+	// The capital, not the price paid between members, moves between investor accounts
+	require.NotNil(t, posted)
+	require.Len(t, posted.Lines, 2)
+	assert.Equal(t, entities.JournalEntryTypeStakeTransfer, posted.EntryType)
+	assert.Equal(t, idr("5000"), posted.Lines[0].Debit)
+	assert.Equal(t, idr("5000"), posted.Lines[1].Credit)
+}
+
+func TestStakeMarketService_ReviewTrade_LedgerFailureLeavesCompleting(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	marketRepo := new(MockStakeMarketRepository)
+	marketService := NewStakeMarketService(marketRepo, nil, nil, ledgerService, currencyService, mockAuditService)
+	ctx := context.Background()
+
+	trade := &entities.StakeTrade{
+		ID:                 uuid.New(),
+		ListingID:          uuid.New(),
+		ProjectID:          uuid.New(),
+		CooperativeID:      uuid.New(),
+		SellerID:           uuid.New(),
+		BuyerID:            uuid.New(),
+		SellerInvestmentID: uuid.New(),
+		Currency:           "IDR",
+		Amount:             idr("5000"),
+		Price:              idr("5000"),
+		Status:             entities.StakeTradeStatusPendingApproval,
+	}
+	marketRepo.On("GetTrade", ctx, trade.ProjectID, trade.ID).Return(trade, nil)
+	marketRepo.On("GetInvestorPosition", ctx, trade.ProjectID, trade.BuyerID).Return(nil, nil)
+	marketRepo.On("TransferTrade", ctx, trade).Return(nil)
+	ledgerRepo.On("GetAccountByCode", ctx, trade.CooperativeID, mock.AnythingOfType("string"), "IDR").
+		Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}, nil)
+	ledgerRepo.On("PostEntry", ctx, mock.AnythingOfType("*entities.JournalEntry")).Return(errors.New("ledger unavailable"))
+
+	_, err := marketService.ReviewTrade(ctx, trade.ProjectID, trade.ID, &entities.ReviewStakeTradeRequest{
+		Approve:          true,
+		PaymentReference: "TRF-20261018-03",
+	}, uuid.New())
+	assert.ErrorContains(t, err, "ledger unavailable")
+
+	// The stake has moved; the trade stays completing until the transfer is recorded
+	marketRepo.AssertCalled(t, "TransferTrade", ctx, trade)
+	marketRepo.AssertNotCalled(t, "FinishTrade", mock.Anything, mock.Anything)
+	assert.Equal(t, entities.StakeTradeStatusCompleting, trade.Status)
+}
+
+func TestStakeMarketService_ResumeCompletingTrades(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	marketRepo := new(MockStakeMarketRepository)
+	marketService := NewStakeMarketService(marketRepo, nil, nil, ledgerService, currencyService, mockAuditService)
+	ctx := context.Background()
+
+	reviewerID := uuid.New()
+	buyerInvestmentID := uuid.New()
+	transferred := &entities.StakeTrade{
+		ID:                 uuid.New(),
+		ListingID:          uuid.New(),
+		ProjectID:          uuid.New(),
+		CooperativeID:      uuid.New(),
+		SellerID:           uuid.New(),
+		BuyerID:            uuid.New(),
+		SellerInvestmentID: uuid.New(),
+		BuyerInvestmentID:  &buyerInvestmentID,
+		Currency:           "IDR",
+		Amount:             idr("5000"),
+		Price:              idr("5000"),
+		Status:             entities.StakeTradeStatusCompleting,
+		ReviewedBy:         &reviewerID,
+	}
+	// Claimed before approvals moved the stake
+	stranded := &entities.StakeTrade{
+		ID:        uuid.New(),
+		ProjectID: uuid.New(),
+		Currency:  "IDR",
+		Amount:    idr("5000"),
+		Status:    entities.StakeTradeStatusCompleting,
+	}
+	marketRepo.On("ListCompletingTrades", ctx).Return([]*entities.StakeTrade{transferred, stranded}, nil)
+	marketRepo.On("FinishTrade", ctx, transferred).Return(nil)
+	ledgerRepo.On("GetAccountByCode", ctx, transferred.CooperativeID, mock.AnythingOfType("string"), "IDR").
+		Return(&entities.LedgerAccount{ID: uuid.New(), Currency: "IDR"}, nil)
+	// The earlier attempt recorded the transfer before failing
+	ledgerRepo.On("PostEntry", ctx, mock.AnythingOfType("*entities.JournalEntry")).Return(repositories.ErrStakeTransferPosted)
+
+	completed, err := marketService.ResumeCompletingTrades(ctx)
+	assert.Equal(t, 1, completed)
+	assert.ErrorContains(t, err, stranded.ID.String())
+	assert.Equal(t, entities.StakeTradeStatusCompleted, transferred.Status)
+	marketRepo.AssertNotCalled(t, "FinishTrade", ctx, stranded)
+}
+
+func TestStakeMarketService_ReviewTrade_RechecksPolicyLimits(t *testing.T) {
+	policyService := &stubInvestmentPolicyService{policies: []*entities.InvestmentPolicyExtended{
+		{Name: "Tightened", MinInvestmentAmount: idr("1000"), MaxInvestmentAmount: idr("4000")},
+	}}
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerService := NewLedgerService(new(MockLedgerRepository), mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	marketRepo := new(MockStakeMarketRepository)
+	marketService := NewStakeMarketService(marketRepo, policyService, nil, ledgerService, currencyService, mockAuditService)
+	ctx := context.Background()
+
+	trade := &entities.StakeTrade{
+		ID:                 uuid.New(),
+		ListingID:          uuid.New(),
+		ProjectID:          uuid.New(),
+		CooperativeID:      uuid.New(),
+		SellerID:           uuid.New(),
+		BuyerID:            uuid.New(),
+		SellerInvestmentID: uuid.New(),
+		Currency:           "IDR",
+		Amount:             idr("5000"),
+		Price:              idr("5000"),
+		Status:             entities.StakeTradeStatusPendingApproval,
+	}
+	marketRepo.On("GetTrade", ctx, trade.ProjectID, trade.ID).Return(trade, nil)
+	marketRepo.On("GetInvestorPosition", ctx, trade.ProjectID, trade.BuyerID).Return(nil, nil)
+
+	_, err := marketService.ReviewTrade(ctx, trade.ProjectID, trade.ID, &entities.ReviewStakeTradeRequest{
+		Approve:          true,
+		PaymentReference: "TRF-20261018-02",
+	}, uuid.New())
+	assert.ErrorContains(t, err, "exceed the maximum")
+	marketRepo.AssertNotCalled(t, "TransferTrade", mock.Anything, mock.Anything)
+}
+
+func TestStakeMarketService_ReviewTrade_Reject(t *testing.T) {
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	ledgerRepo := new(MockLedgerRepository)
+	ledgerService := NewLedgerService(ledgerRepo, mockAuditService)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	marketRepo := new(MockStakeMarketRepository)
+	marketService := NewStakeMarketService(marketRepo, nil, nil, ledgerService, currencyService, mockAuditService)
+	ctx := context.Background()
+
+	trade := &entities.StakeTrade{
+		ID:                 uuid.New(),
+		ListingID:          uuid.New(),
+		ProjectID:          uuid.New(),
+		CooperativeID:      uuid.New(),
+		SellerID:           uuid.New(),
+		BuyerID:            uuid.New(),
+		SellerInvestmentID: uuid.New(),
+		Currency:           "IDR",
+		Amount:             idr("5000"),
+		Price:              idr("5000"),
+		Status:             entities.StakeTradeStatusPendingApproval,
+	}
+	marketRepo.On("GetTrade", ctx, trade.ProjectID, trade.ID).Return(trade, nil)
+	marketRepo.On("RejectTrade", ctx, trade).Return(nil)
+
+	reviewed, err := marketService.ReviewTrade(ctx, trade.ProjectID, trade.ID, &entities.ReviewStakeTradeRequest{
+		Notes: "Payment not evidenced",
+	}, uuid.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.StakeTradeStatusRejected, reviewed.Status)
+	assert.Equal(t, "Payment not evidenced", reviewed.ReviewNotes)
+	marketRepo.AssertNotCalled(t, "TransferTrade", mock.Anything, mock.Anything)
+	ledgerRepo.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything)
+}
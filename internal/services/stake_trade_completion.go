@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// StakeTradeCompletionScheduler periodically records the ledger transfers of
+// approved stake trades whose stake moved but was not recorded
+type StakeTradeCompletionScheduler struct {
+	*intervalScheduler
+	stakeMarketService StakeMarketService
+}
+
+// NewStakeTradeCompletionScheduler creates a scheduler that runs every interval
+func NewStakeTradeCompletionScheduler(stakeMarketService StakeMarketService, interval time.Duration) *StakeTradeCompletionScheduler {
+	s := &StakeTradeCompletionScheduler{stakeMarketService: stakeMarketService}
+	s.intervalScheduler = newIntervalScheduler("Stake trade completion", interval, s.runOnce)
+	return s
+}
+
+func (s *StakeTradeCompletionScheduler) runOnce(ctx context.Context) error {
+	completed, err := s.stakeMarketService.ResumeCompletingTrades(ctx)
+	if completed > 0 {
+		log.Printf("Completed %d stake trades", completed)
+	}
+	return err
+}
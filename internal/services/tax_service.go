@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// TaxService withholds tax from investors' profit shares under the rates of
+// the cooperative's jurisdiction and reports the tax withheld for remittance
+type TaxService interface {
+	CreateRate(ctx context.Context, req *entities.CreateTaxWithholdingRateRequest, userID uuid.UUID) (*entities.TaxWithholdingRate, error)
+	DeactivateRate(ctx context.Context, rateID, userID uuid.UUID) error
+	GetJurisdictionProfile(ctx context.Context, jurisdiction string) (*entities.TaxJurisdictionProfile, error)
+	GetCooperativeJurisdiction(ctx context.Context, cooperativeID uuid.UUID) (string, error)
+	SetCooperativeJurisdiction(ctx context.Context, cooperativeID uuid.UUID, req *entities.SetCooperativeTaxJurisdictionRequest, userID uuid.UUID) error
+	GetInvestorProfile(ctx context.Context, investorID uuid.UUID) (*entities.InvestorTaxProfile, error)
+	SaveInvestorProfile(ctx context.Context, investorID uuid.UUID, req *entities.SaveInvestorTaxProfileRequest) (*entities.InvestorTaxProfile, error)
+
+	// WithholdTax sets the tax withheld from each share, where taxable[i] is
+	// the part of shares[i] that is taxable, and returns the withholdings to
+	// record once the shares are credited
+	WithholdTax(ctx context.Context, distribution *entities.ProfitDistributionExtended, shares []*entities.InvestorProfitShare, taxable []entities.Money) ([]*entities.TaxWithholding, error)
+	RecordWithholdings(ctx context.Context, distribution *entities.ProfitDistributionExtended, withholdings []*entities.TaxWithholding) error
+
+	// GetWithholdingReport totals the tax a cooperative withheld in the month,
+	// quarter or year containing at
+	GetWithholdingReport(ctx context.Context, cooperativeID uuid.UUID, taxPeriod string, at time.Time, currency string) (*entities.TaxWithholdingReport, error)
+	GetDistributionReport(ctx context.Context, distribution *entities.ProfitDistributionExtended) (*entities.TaxWithholdingReport, error)
+	// GenerateTaxDocument documents the tax withheld on a distribution, or in
+	// the month or year it was withheld for withholding tax returns and annual
+	// reports
+	GenerateTaxDocument(ctx context.Context, distribution *entities.ProfitDistributionExtended, documentType string, issuerID uuid.UUID) (*entities.TaxDocumentation, error)
+}
+
+type taxService struct {
+	taxRepo             repositories.TaxRepository
+	auditService        AuditService
+	defaultJurisdiction string
+}
+
+// NewTaxService creates a new tax service. Cooperatives that have not chosen a
+// jurisdiction withhold under defaultJurisdiction, and investors without a tax
+// profile are treated as individuals without a tax ID.
+func NewTaxService(taxRepo repositories.TaxRepository, auditService AuditService, defaultJurisdiction string) (TaxService, error) {
+	if _, ok := entities.TaxJurisdictions[defaultJurisdiction]; !ok {
+		return nil, fmt.Errorf("unknown tax jurisdiction: %s", defaultJurisdiction)
+	}
+
+	return &taxService{
+		taxRepo:             taxRepo,
+		auditService:        auditService,
+		defaultJurisdiction: defaultJurisdiction,
+	}, nil
+}
+
+func (s *taxService) CreateRate(ctx context.Context, req *entities.CreateTaxWithholdingRateRequest, userID uuid.UUID) (*entities.TaxWithholdingRate, error) {
+	if req.EffectiveTo != nil && !req.EffectiveTo.After(req.EffectiveFrom) {
+		return nil, errors.New("effective to must be after effective from")
+	}
+
+	now := time.Now()
+	rate := &entities.TaxWithholdingRate{
+		ID:            uuid.New(),
+		Jurisdiction:  req.Jurisdiction,
+		InvestorType:  req.InvestorType,
+		HasTaxID:      req.HasTaxID,
+		Rate:          req.Rate,
+		EffectiveFrom: req.EffectiveFrom,
+		EffectiveTo:   req.EffectiveTo,
+		Description:   req.Description,
+		IsActive:      true,
+		CreatedBy:     userID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.taxRepo.CreateRate(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "create_tax_withholding_rate",
+		EntityType: "tax_withholding_rate",
+		EntityID:   rate.ID,
+		NewValues:  rate,
+	})
+
+	return rate, nil
+}
+
+func (s *taxService) DeactivateRate(ctx context.Context, rateID, userID uuid.UUID) error {
+	rate, err := s.taxRepo.GetRate(ctx, rateID)
+	if err != nil {
+		return err
+	}
+	if !rate.IsActive {
+		return errors.New("withholding rate is already inactive")
+	}
+
+	if err := s.taxRepo.DeactivateRate(ctx, rateID); err != nil {
+		return err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "deactivate_tax_withholding_rate",
+		EntityType: "tax_withholding_rate",
+		EntityID:   rateID,
+		OldValues:  rate,
+	})
+
+	return nil
+}
+
+func (s *taxService) GetJurisdictionProfile(ctx context.Context, jurisdiction string) (*entities.TaxJurisdictionProfile, error) {
+	definition, ok := entities.TaxJurisdictions[jurisdiction]
+	if !ok {
+		return nil, fmt.Errorf("unknown tax jurisdiction: %s", jurisdiction)
+	}
+
+	rates, err := s.taxRepo.ListRates(ctx, jurisdiction)
+	if err != nil {
+		return nil, err
+	}
+	if rates == nil {
+		rates = []*entities.TaxWithholdingRate{}
+	}
+
+	return &entities.TaxJurisdictionProfile{TaxJurisdiction: definition, Rates: rates}, nil
+}
+
+func (s *taxService) GetCooperativeJurisdiction(ctx context.Context, cooperativeID uuid.UUID) (string, error) {
+	jurisdiction, err := s.taxRepo.GetCooperativeJurisdiction(ctx, cooperativeID)
+	if err != nil {
+		return "", err
+	}
+	if jurisdiction == "" {
+		return s.defaultJurisdiction, nil
+	}
+	return jurisdiction, nil
+}
+
+func (s *taxService) SetCooperativeJurisdiction(ctx context.Context, cooperativeID uuid.UUID, req *entities.SetCooperativeTaxJurisdictionRequest, userID uuid.UUID) error {
+	if _, ok := entities.TaxJurisdictions[req.Jurisdiction]; !ok {
+		return fmt.Errorf("unknown tax jurisdiction: %s", req.Jurisdiction)
+	}
+
+	if err := s.taxRepo.SetCooperativeJurisdiction(ctx, cooperativeID, req.Jurisdiction, userID); err != nil {
+		return err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     userID,
+		Operation:  "set_cooperative_tax_jurisdiction",
+		EntityType: entities.AuditEntityCooperative,
+		EntityID:   cooperativeID,
+		NewValues:  req,
+	})
+
+	return nil
+}
+
+func (s *taxService) GetInvestorProfile(ctx context.Context, investorID uuid.UUID) (*entities.InvestorTaxProfile, error) {
+	profile, err := s.taxRepo.GetInvestorProfile(ctx, investorID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, errors.New("investor tax profile not found")
+	}
+	return profile, nil
+}
+
+func (s *taxService) SaveInvestorProfile(ctx context.Context, investorID uuid.UUID, req *entities.SaveInvestorTaxProfileRequest) (*entities.InvestorTaxProfile, error) {
+	profile := &entities.InvestorTaxProfile{
+		InvestorID:   investorID,
+		InvestorType: req.InvestorType,
+		TaxID:        req.TaxID,
+		TaxResidence: req.TaxResidence,
+	}
+
+	if err := s.taxRepo.SaveInvestorProfile(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     investorID,
+		Operation:  "save_investor_tax_profile",
+		EntityType: entities.AuditEntityUser,
+		EntityID:   investorID,
+		NewValues:  profile,
+	})
+
+	return profile, nil
+}
+
+func (s *taxService) WithholdTax(ctx context.Context, distribution *entities.ProfitDistributionExtended, shares []*entities.InvestorProfitShare, taxable []entities.Money) ([]*entities.TaxWithholding, error) {
+	if len(taxable) != len(shares) {
+		return nil, errors.New("a taxable amount is required for each profit share")
+	}
+
+	jurisdiction, err := s.GetCooperativeJurisdiction(ctx, distribution.CooperativeID)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := s.taxRepo.ListRates(ctx, jurisdiction)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	profiles := make(map[uuid.UUID]*entities.InvestorTaxProfile)
+	withholdings := make([]*entities.TaxWithholding, 0, len(shares))
+
+	for i, share := range shares {
+		if taxable[i].GreaterThan(share.ProfitShareAmount) {
+			return nil, fmt.Errorf("taxable amount exceeds the profit share of investment %s", share.InvestmentID)
+		}
+
+		profile, ok := profiles[share.InvestorID]
+		if !ok {
+			profile, err = s.taxRepo.GetInvestorProfile(ctx, share.InvestorID)
+			if err != nil {
+				return nil, err
+			}
+			profiles[share.InvestorID] = profile
+		}
+
+		investorType, hasTaxID := entities.TaxInvestorTypeIndividual, false
+		if profile != nil {
+			investorType, hasTaxID = profile.InvestorType, profile.HasTaxIDIn(jurisdiction)
+		}
+
+		rate := selectWithholdingRate(rates, jurisdiction, investorType, hasTaxID, now)
+		if rate == nil {
+			return nil, fmt.Errorf("no %s withholding rate for %s investors %s a tax ID", jurisdiction, investorType, withOrWithout(hasTaxID))
+		}
+
+		share.TaxAmount = taxable[i].Percent(rate.Rate, entities.RoundHalfUp)
+		share.NetProfitShare = share.ProfitShareAmount.Sub(share.TaxAmount)
+
+		withholdings = append(withholdings, &entities.TaxWithholding{
+			ID:             uuid.New(),
+			DistributionID: distribution.ID,
+			CooperativeID:  distribution.CooperativeID,
+			ProjectID:      distribution.ProjectID,
+			InvestmentID:   share.InvestmentID,
+			InvestorID:     share.InvestorID,
+			Jurisdiction:   jurisdiction,
+			InvestorType:   investorType,
+			HasTaxID:       hasTaxID,
+			RateID:         rate.ID,
+			Rate:           rate.Rate,
+			TaxableAmount:  taxable[i],
+			TaxAmount:      share.TaxAmount,
+			Currency:       distribution.Currency,
+			WithheldAt:     now,
+		})
+	}
+
+	return withholdings, nil
+}
+
+func (s *taxService) RecordWithholdings(ctx context.Context, distribution *entities.ProfitDistributionExtended, withholdings []*entities.TaxWithholding) error {
+	if len(withholdings) == 0 {
+		return nil
+	}
+	return s.taxRepo.CreateWithholdings(ctx, distribution.CooperativeID, withholdings)
+}
+
+func (s *taxService) GetWithholdingReport(ctx context.Context, cooperativeID uuid.UUID, taxPeriod string, at time.Time, currency string) (*entities.TaxWithholdingReport, error) {
+	jurisdiction, err := s.GetCooperativeJurisdiction(ctx, cooperativeID)
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		currency = entities.TaxJurisdictions[jurisdiction].Currency
+	}
+
+	start, end := entities.TaxReportPeriod(taxPeriod, at.UTC())
+	lines, err := s.taxRepo.SummarizeWithholdings(ctx, cooperativeID, &entities.TaxWithholdingFilter{
+		Start:    &start,
+		End:      &end,
+		Currency: currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buildWithholdingReport(cooperativeID, jurisdiction, taxPeriod, start, end, currency, lines), nil
+}
+
+func (s *taxService) GetDistributionReport(ctx context.Context, distribution *entities.ProfitDistributionExtended) (*entities.TaxWithholdingReport, error) {
+	withholdings, err := s.taxRepo.ListWithholdings(ctx, distribution.CooperativeID, &entities.TaxWithholdingFilter{
+		DistributionID: &distribution.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(withholdings) == 0 {
+		return nil, errors.New("no tax has been withheld on this distribution")
+	}
+
+	// A distribution's tax is remitted with the rest withheld that month
+	first := withholdings[0]
+	start, end := entities.TaxReportPeriod(entities.TaxPeriodMonthly, first.WithheldAt.UTC())
+	lines, err := s.taxRepo.SummarizeWithholdings(ctx, distribution.CooperativeID, &entities.TaxWithholdingFilter{
+		DistributionID: &distribution.ID,
+		Currency:       first.Currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := buildWithholdingReport(distribution.CooperativeID, first.Jurisdiction, entities.TaxPeriodMonthly, start, end, first.Currency, lines)
+	report.DistributionID = &distribution.ID
+	return report, nil
+}
+
+func (s *taxService) GenerateTaxDocument(ctx context.Context, distribution *entities.ProfitDistributionExtended, documentType string, issuerID uuid.UUID) (*entities.TaxDocumentation, error) {
+	report, err := s.GetDistributionReport(ctx, distribution)
+	if err != nil {
+		return nil, err
+	}
+
+	switch documentType {
+	case entities.TaxDocumentTypeWithholdingTax:
+		report, err = s.GetWithholdingReport(ctx, distribution.CooperativeID, entities.TaxPeriodMonthly, report.PeriodStart, report.Currency)
+	case entities.TaxDocumentTypeAnnualReport:
+		report, err = s.GetWithholdingReport(ctx, distribution.CooperativeID, entities.TaxPeriodAnnual, report.PeriodStart, report.Currency)
+	case entities.TaxDocumentTypeTaxCertificate:
+	default:
+		return nil, fmt.Errorf("unsupported tax document type: %s", documentType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &entities.TaxDocumentation{
+		ID:                   uuid.New(),
+		ProfitDistributionID: distribution.ID,
+		DocumentType:         documentType,
+		DocumentNumber:       fmt.Sprintf("TAX-%d-%s-%s", report.PeriodStart.Year(), report.TaxPeriod, uuid.New().String()[:8]),
+		TaxYear:              report.PeriodStart.Year(),
+		TaxPeriod:            report.TaxPeriod,
+		TotalTaxableAmount:   report.TotalTaxableAmount,
+		TotalTaxAmount:       report.TotalTaxAmount,
+		TaxRate:              report.EffectiveRate(),
+		Currency:             report.Currency,
+		IssuedDate:           now,
+		DueDate:              report.DueDate,
+		Status:               entities.TaxDocumentStatusDraft,
+		IssuedBy:             issuerID,
+		IsActive:             true,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}, nil
+}
+
+// selectWithholdingRate returns the applicable rate that took effect most recently
+func selectWithholdingRate(rates []*entities.TaxWithholdingRate, jurisdiction, investorType string, hasTaxID bool, at time.Time) *entities.TaxWithholdingRate {
+	var selected *entities.TaxWithholdingRate
+	for _, rate := range rates {
+		if !rate.AppliesTo(jurisdiction, investorType, hasTaxID, at) {
+			continue
+		}
+		if selected == nil || rate.EffectiveFrom.After(selected.EffectiveFrom) {
+			selected = rate
+		}
+	}
+	return selected
+}
+
+// buildWithholdingReport totals report lines; withheld tax is due the
+// jurisdiction's remittance days after the period ends
+func buildWithholdingReport(cooperativeID uuid.UUID, jurisdiction, taxPeriod string, start, end time.Time, currency string, lines []entities.TaxWithholdingReportLine) *entities.TaxWithholdingReport {
+	report := &entities.TaxWithholdingReport{
+		CooperativeID:      cooperativeID,
+		Jurisdiction:       jurisdiction,
+		TaxPeriod:          taxPeriod,
+		PeriodStart:        start,
+		PeriodEnd:          end,
+		DueDate:            end.AddDate(0, 0, entities.TaxJurisdictions[jurisdiction].RemittanceDays-1),
+		Currency:           currency,
+		Lines:              lines,
+		TotalTaxableAmount: entities.ZeroMoney(currency),
+		TotalTaxAmount:     entities.ZeroMoney(currency),
+	}
+	if report.Lines == nil {
+		report.Lines = []entities.TaxWithholdingReportLine{}
+	}
+
+	for _, line := range lines {
+		report.TotalTaxableAmount = report.TotalTaxableAmount.Add(line.TaxableAmount)
+		report.TotalTaxAmount = report.TotalTaxAmount.Add(line.TaxAmount)
+	}
+
+	return report
+}
+
+func withOrWithout(with bool) string {
+	if with {
+		return "with"
+	}
+	return "without"
+}
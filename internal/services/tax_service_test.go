@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTaxService_RejectsUnknownJurisdiction(t *testing.T) {
+	_, err := NewTaxService(new(MockTaxRepository), new(MockAuditService), "SG")
+	assert.Error(t, err)
+}
+
+func TestSelectWithholdingRate(t *testing.T) {
+	at := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	expired := at.AddDate(0, -1, 0)
+
+	indonesia := entities.TaxJurisdictionIndonesia
+	individual, corporate := entities.TaxInvestorTypeIndividual, entities.TaxInvestorTypeCorporate
+	rates := []*entities.TaxWithholdingRate{
+		{ID: uuid.New(), Jurisdiction: indonesia, InvestorType: individual, HasTaxID: true, Rate: 15, EffectiveFrom: at.AddDate(-2, 0, 0), IsActive: true},
+		{ID: uuid.New(), Jurisdiction: indonesia, InvestorType: individual, HasTaxID: true, Rate: 10, EffectiveFrom: at.AddDate(-1, 0, 0), IsActive: true},
+		// Not yet in force, or already withdrawn
+		{ID: uuid.New(), Jurisdiction: indonesia, InvestorType: individual, HasTaxID: true, Rate: 5, EffectiveFrom: at.AddDate(0, 1, 0), IsActive: true},
+		{ID: uuid.New(), Jurisdiction: indonesia, InvestorType: individual, HasTaxID: true, Rate: 1, EffectiveFrom: at.AddDate(0, -2, 0), EffectiveTo: &expired, IsActive: true},
+		{ID: uuid.New(), Jurisdiction: indonesia, InvestorType: individual, HasTaxID: false, Rate: 20, EffectiveFrom: at.AddDate(-2, 0, 0), IsActive: true},
+		{ID: uuid.New(), Jurisdiction: indonesia, InvestorType: corporate, HasTaxID: true, Rate: 15, EffectiveFrom: at.AddDate(-2, 0, 0), IsActive: true},
+	}
+
+	testCases := []struct {
+		name         string
+		jurisdiction string
+		investorType string
+		hasTaxID     bool
+		expected     *entities.TaxWithholdingRate
+	}{
+		{name: "Latest rate in force", jurisdiction: indonesia, investorType: individual, hasTaxID: true, expected: rates[1]},
+		{name: "Without a tax ID", jurisdiction: indonesia, investorType: individual, expected: rates[4]},
+		{name: "Corporate investor", jurisdiction: indonesia, investorType: corporate, hasTaxID: true, expected: rates[5]},
+		{name: "No rate for the profile", jurisdiction: indonesia, investorType: corporate},
+		{name: "No rate in the jurisdiction", jurisdiction: entities.TaxJurisdictionMalaysia, investorType: individual, hasTaxID: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, selectWithholdingRate(rates, tc.jurisdiction, tc.investorType, tc.hasTaxID, at))
+		})
+	}
+}
+
+func TestTaxService_WithholdTax_ByInvestorProfile(t *testing.T) {
+	taxRepo := new(MockTaxRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service, err := NewTaxService(taxRepo, mockAuditService, entities.TaxJurisdictionIndonesia)
+	require.NoError(t, err)
+	ctx := context.Background()
+	effective := time.Now().AddDate(-1, 0, 0)
+
+	distribution := &entities.ProfitDistributionExtended{
+		ID:            uuid.New(),
+		CooperativeID: uuid.New(),
+		ProjectID:     uuid.New(),
+		Currency:      "IDR",
+	}
+	resident := &entities.InvestorTaxProfile{InvestorID: uuid.New(), InvestorType: entities.TaxInvestorTypeIndividual, TaxID: "01.234.567.8-901.000", TaxResidence: "ID"}
+	// A Malaysian tax ID does not count under Indonesian rules
+	foreign := &entities.InvestorTaxProfile{InvestorID: uuid.New(), InvestorType: entities.TaxInvestorTypeCorporate, TaxID: "C1234567890", TaxResidence: "MY"}
+	unknown := uuid.New()
+
+	taxRepo.On("GetCooperativeJurisdiction", ctx, distribution.CooperativeID).Return("", nil)
+	taxRepo.On("ListRates", ctx, entities.TaxJurisdictionIndonesia).Return([]*entities.TaxWithholdingRate{
+		{ID: uuid.New(), Jurisdiction: entities.TaxJurisdictionIndonesia, InvestorType: entities.TaxInvestorTypeIndividual, HasTaxID: true, Rate: 10, EffectiveFrom: effective, IsActive: true},
+		{ID: uuid.New(), Jurisdiction: entities.TaxJurisdictionIndonesia, InvestorType: entities.TaxInvestorTypeIndividual, HasTaxID: false, Rate: 20, EffectiveFrom: effective, IsActive: true},
+		{ID: uuid.New(), Jurisdiction: entities.TaxJurisdictionIndonesia, InvestorType: entities.TaxInvestorTypeCorporate, HasTaxID: false, Rate: 25, EffectiveFrom: effective, IsActive: true},
+	}, nil)
+	taxRepo.On("GetInvestorProfile", ctx, resident.InvestorID).Return(resident, nil).Once()
+	taxRepo.On("GetInvestorProfile", ctx, foreign.InvestorID).Return(foreign, nil).Once()
+	taxRepo.On("GetInvestorProfile", ctx, unknown).Return(nil, nil).Once()
+
+	shares := []*entities.InvestorProfitShare{
+		{InvestmentID: uuid.New(), InvestorID: resident.InvestorID, ProfitShareAmount: idr("1000")},
+		{InvestmentID: uuid.New(), InvestorID: foreign.InvestorID, ProfitShareAmount: idr("2000")},
+		{InvestmentID: uuid.New(), InvestorID: unknown, ProfitShareAmount: idr("3000")},
+		// The same investor's second investment reuses the profile
+		{InvestmentID: uuid.New(), InvestorID: resident.InvestorID, ProfitShareAmount: idr("500")},
+	}
+	taxable := []entities.Money{idr("1000"), idr("2000"), idr("1500"), idr("500")}
+
+	withholdings, err := service.WithholdTax(ctx, distribution, shares, taxable)
+
+	require.NoError(t, err)
+	require.Len(t, withholdings, 4)
+	assert.Equal(t, idr("100"), shares[0].TaxAmount)
+	assert.Equal(t, idr("900"), shares[0].NetProfitShare)
+	assert.Equal(t, idr("500"), shares[1].TaxAmount)
+	assert.False(t, withholdings[1].HasTaxID)
+	assert.Equal(t, entities.TaxInvestorTypeCorporate, withholdings[1].InvestorType)
+	// Only the taxable part of the share is taxed
+	assert.Equal(t, idr("300"), shares[2].TaxAmount)
+	assert.Equal(t, idr("2700"), shares[2].NetProfitShare)
+	assert.Equal(t, entities.TaxInvestorTypeIndividual, withholdings[2].InvestorType)
+	assert.Equal(t, idr("50"), shares[3].TaxAmount)
+	assert.Equal(t, distribution.ID, withholdings[3].DistributionID)
+	assert.Equal(t, entities.TaxJurisdictionIndonesia, withholdings[3].Jurisdiction)
+	taxRepo.AssertExpectations(t)
+}
+
+func TestTaxService_WithholdTax_FailsWithoutApplicableRate(t *testing.T) {
+	taxRepo := new(MockTaxRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service, err := NewTaxService(taxRepo, mockAuditService, entities.TaxJurisdictionIndonesia)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	distribution := &entities.ProfitDistributionExtended{ID: uuid.New(), CooperativeID: uuid.New(), Currency: "MYR"}
+	investorID := uuid.New()
+
+	taxRepo.On("GetCooperativeJurisdiction", ctx, distribution.CooperativeID).Return(entities.TaxJurisdictionMalaysia, nil)
+	taxRepo.On("ListRates", ctx, entities.TaxJurisdictionMalaysia).Return([]*entities.TaxWithholdingRate{}, nil)
+	taxRepo.On("GetInvestorProfile", ctx, investorID).Return(nil, nil)
+
+	shares := []*entities.InvestorProfitShare{
+		{InvestmentID: uuid.New(), InvestorID: investorID, ProfitShareAmount: entities.MustParseMoney("100", "MYR")},
+	}
+	_, err = service.WithholdTax(ctx, distribution, shares, []entities.Money{entities.MustParseMoney("100", "MYR")})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no MY withholding rate for individual investors without a tax ID")
+}
+
+func TestTaxService_GetWithholdingReport_TotalsAndDueDate(t *testing.T) {
+	taxRepo := new(MockTaxRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service, err := NewTaxService(taxRepo, mockAuditService, entities.TaxJurisdictionIndonesia)
+	require.NoError(t, err)
+	ctx := context.Background()
+	cooperativeID := uuid.New()
+
+	start := time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	taxRepo.On("GetCooperativeJurisdiction", ctx, cooperativeID).Return("", nil)
+	taxRepo.On("SummarizeWithholdings", ctx, cooperativeID, &entities.TaxWithholdingFilter{Start: &start, End: &end, Currency: "IDR"}).Return([]entities.TaxWithholdingReportLine{
+		{InvestorType: entities.TaxInvestorTypeIndividual, HasTaxID: true, Rate: 10, WithholdingCount: 3, InvestorCount: 2, TaxableAmount: idr("6000"), TaxAmount: idr("600")},
+		{InvestorType: entities.TaxInvestorTypeIndividual, HasTaxID: false, Rate: 20, WithholdingCount: 1, InvestorCount: 1, TaxableAmount: idr("2000"), TaxAmount: idr("400")},
+	}, nil)
+
+	report, err := service.GetWithholdingReport(ctx, cooperativeID, entities.TaxPeriodQuarterly, time.Date(2026, time.August, 17, 0, 0, 0, 0, time.UTC), "")
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.TaxJurisdictionIndonesia, report.Jurisdiction)
+	assert.Equal(t, "IDR", report.Currency)
+	assert.Equal(t, start, report.PeriodStart)
+	assert.Equal(t, time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC), report.DueDate)
+	assert.Equal(t, idr("8000"), report.TotalTaxableAmount)
+	assert.Equal(t, idr("1000"), report.TotalTaxAmount)
+	assert.InDelta(t, 12.5, report.EffectiveRate(), 0.0001)
+}
+
+func TestTaxService_GenerateTaxDocument_UsesDistributionWithholdings(t *testing.T) {
+	taxRepo := new(MockTaxRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	service, err := NewTaxService(taxRepo, mockAuditService, entities.TaxJurisdictionIndonesia)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	distribution := &entities.ProfitDistributionExtended{ID: uuid.New(), CooperativeID: uuid.New(), Currency: "IDR"}
+	withheldAt := time.Date(2026, time.September, 20, 10, 0, 0, 0, time.UTC)
+
+	taxRepo.On("ListWithholdings", ctx, distribution.CooperativeID, mock.Anything).Return([]*entities.TaxWithholding{
+		{DistributionID: distribution.ID, Jurisdiction: entities.TaxJurisdictionIndonesia, Currency: "IDR", WithheldAt: withheldAt},
+	}, nil)
+	taxRepo.On("SummarizeWithholdings", ctx, distribution.CooperativeID, mock.Anything).Return([]entities.TaxWithholdingReportLine{
+		{InvestorType: entities.TaxInvestorTypeCorporate, Rate: 15, TaxableAmount: idr("10000"), TaxAmount: idr("1500")},
+	}, nil)
+
+	document, err := service.GenerateTaxDocument(ctx, distribution, entities.TaxDocumentTypeTaxCertificate, uuid.Nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, distribution.ID, document.ProfitDistributionID)
+	assert.Equal(t, idr("1500"), document.TotalTaxAmount)
+	assert.InDelta(t, 15, document.TaxRate, 0.0001)
+	assert.Equal(t, 2026, document.TaxYear)
+	assert.Equal(t, time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC), document.DueDate)
+
+	_, err = service.GenerateTaxDocument(ctx, distribution, "receipt", uuid.Nil)
+	assert.Error(t, err)
+}
+
+func TestAllocateTaxableAmounts_ProportionalToInvestments(t *testing.T) {
+	investments := []*entities.Investment{
+		{ID: uuid.New(), Amount: idr("50000")},
+		{ID: uuid.New(), Amount: idr("150000")},
+	}
+
+	amounts, err := allocateTaxableAmounts(idr("1000"), investments)
+
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Money{idr("250"), idr("750")}, amounts)
+}
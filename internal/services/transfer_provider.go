@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// TransferProvider sends fund transfers out to bank accounts and e-wallets. It
+// is implemented by payment providers whose gateway also disburses funds.
+type TransferProvider interface {
+	// Name identifies the provider in transfer attempts
+	Name() string
+	// ExecuteTransfer sends a transfer's net amount and returns the provider's
+	// transaction reference. The transfer number is the idempotency key, so
+	// executing a transfer again after it succeeded returns the same reference
+	// without sending the funds twice. Failures that retrying cannot fix are
+	// returned as a *PermanentTransferError; any other error is transient.
+	ExecuteTransfer(ctx context.Context, transfer *entities.FundTransfer) (string, error)
+}
+
+// PermanentTransferError is a transfer failure that retrying cannot fix, such
+// as a closed or unknown destination account
+type PermanentTransferError struct {
+	Reason string
+}
+
+func (e *PermanentTransferError) Error() string {
+	return e.Reason
+}
+
+// TransferProviderFor returns the payment provider with the given name if it
+// can execute transfers, or nil
+func TransferProviderFor(name string, providers ...PaymentProvider) TransferProvider {
+	for _, provider := range providers {
+		if transferProvider, ok := provider.(TransferProvider); ok && provider.Name() == name {
+			return transferProvider
+		}
+	}
+	return nil
+}
+
+// ExecuteTransfer simulates a bank or wallet disbursement. Transfers without a
+// destination account are refused permanently.
+func (p *SimulatorPaymentProvider) ExecuteTransfer(ctx context.Context, transfer *entities.FundTransfer) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if reference, ok := p.transfers[transfer.TransferNumber]; ok {
+		return reference, nil
+	}
+
+	switch transfer.PaymentMethod {
+	case entities.PaymentMethodBankTransfer, entities.PaymentMethodDigitalWallet:
+	default:
+		return "", &PermanentTransferError{Reason: fmt.Sprintf("simulator does not support payment method %s", transfer.PaymentMethod)}
+	}
+	if transfer.BankAccount == "" {
+		return "", &PermanentTransferError{Reason: "destination account is missing"}
+	}
+
+	reference := fmt.Sprintf("SIMTRF-%s", strings.ToUpper(uuid.New().String()[:13]))
+	p.transfers[transfer.TransferNumber] = reference
+	return reference, nil
+}
@@ -29,17 +29,20 @@ type userServiceAuth struct {
 	userRepo         repositories.UserRepositorySharded
 	cooperativeRepo  repositories.CooperativeRepository
 	jwtManager       *auth.JWTManager
+	sanctionsService SanctionsScreeningService
 }
 
 func NewUserServiceAuth(
 	userRepo repositories.UserRepositorySharded,
 	cooperativeRepo repositories.CooperativeRepository,
 	jwtManager *auth.JWTManager,
+	sanctionsService SanctionsScreeningService,
 ) UserServiceAuth {
 	return &userServiceAuth{
-		userRepo:        userRepo,
-		cooperativeRepo: cooperativeRepo,
-		jwtManager:      jwtManager,
+		userRepo:         userRepo,
+		cooperativeRepo:  cooperativeRepo,
+		jwtManager:       jwtManager,
+		sanctionsService: sanctionsService,
 	}
 }
 
@@ -88,9 +91,15 @@ func (s *userServiceAuth) Register(ctx context.Context, req *entities.CreateUser
 		CooperativeID: req.CooperativeID,
 		Roles:         req.Roles,
 		KYCStatus:     "pending",
+		DateOfBirth:   req.DateOfBirth,
 		IsActive:      true,
 	}
 
+	// Screen against sanctions lists before onboarding
+	if err := requireSanctionsClearance(ctx, s.sanctionsService, individualSanctionsSubject(user, entities.SanctionsPurposeRegistration, req.CooperativeID)); err != nil {
+		return nil, "", "", err
+	}
+
 	// Create user in database
 	createdUser, err := s.userRepo.Create(ctx, user)
 	if err != nil {
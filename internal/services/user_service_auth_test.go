@@ -22,7 +22,7 @@ func TestUserServiceAuth_Register_Success(t *testing.T) {
 	mockCooperativeRepo := new(MockCooperativeRepository)
 	jwtManager := auth.NewJWTManager("test-secret", time.Hour)
 
-	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager)
+	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager, nil)
 
 	cooperativeID := uuid.New()
 	req := &entities.CreateUserRequest{
@@ -78,7 +78,7 @@ func TestUserServiceAuth_Register_UserAlreadyExists(t *testing.T) {
 	mockCooperativeRepo := new(MockCooperativeRepository)
 	jwtManager := auth.NewJWTManager("test-secret", time.Hour)
 
-	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager)
+	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager, nil)
 
 	req := &entities.CreateUserRequest{
 		Email:    "test@example.com",
@@ -115,7 +115,7 @@ func TestUserServiceAuth_Register_WeakPassword(t *testing.T) {
 	mockCooperativeRepo := new(MockCooperativeRepository)
 	jwtManager := auth.NewJWTManager("test-secret", time.Hour)
 
-	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager)
+	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager, nil)
 
 	req := &entities.CreateUserRequest{
 		Email:    "test@example.com",
@@ -147,7 +147,7 @@ func TestUserServiceAuth_Login_Success(t *testing.T) {
 	mockCooperativeRepo := new(MockCooperativeRepository)
 	jwtManager := auth.NewJWTManager("test-secret", time.Hour)
 
-	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager)
+	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager, nil)
 
 	email := "test@example.com"
 	password := "TestPassword123!"
@@ -185,7 +185,7 @@ func TestUserServiceAuth_Login_InvalidCredentials(t *testing.T) {
 	mockCooperativeRepo := new(MockCooperativeRepository)
 	jwtManager := auth.NewJWTManager("test-secret", time.Hour)
 
-	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager)
+	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager, nil)
 
 	email := "test@example.com"
 	password := "wrongpassword"
@@ -211,7 +211,7 @@ func TestUserServiceAuth_RefreshToken_Success(t *testing.T) {
 	mockCooperativeRepo := new(MockCooperativeRepository)
 	jwtManager := auth.NewJWTManager("test-secret", time.Hour)
 
-	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager)
+	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager, nil)
 
 	userID := uuid.New()
 	user := &entities.User{
@@ -243,7 +243,7 @@ func TestUserServiceAuth_ValidatePasswordComplexity(t *testing.T) {
 	mockCooperativeRepo := new(MockCooperativeRepository)
 	jwtManager := auth.NewJWTManager("test-secret", time.Hour)
 
-	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager).(*userServiceAuth)
+	service := NewUserServiceAuth(mockUserRepo, mockCooperativeRepo, jwtManager, nil).(*userServiceAuth)
 
 	tests := []struct {
 		password    string
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"comfunds/internal/entities"
+	"comfunds/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ZakatConfig holds the zakat rules investors' portfolios are assessed under
+type ZakatConfig struct {
+	Nisab    entities.Money // minimum zakatable wealth, in the base currency
+	Rate     float64        // percentage of zakatable wealth due
+	HaulDays int            // holding period before wealth is zakatable
+	HaulRule string         // per_holding or portfolio
+}
+
+// NewZakatConfig builds the zakat rules from configuration values. Under the
+// per_holding rule each investment must complete its own haul; under the
+// portfolio rule wealth added during the year joins the haul already running.
+func NewZakatConfig(nisab, currency, rate, haulDays, haulRule string) (ZakatConfig, error) {
+	nisabAmount, err := entities.ParseMoney(nisab, currency)
+	if err != nil {
+		return ZakatConfig{}, fmt.Errorf("invalid zakat nisab: %w", err)
+	}
+	if nisabAmount.IsNegative() {
+		return ZakatConfig{}, errors.New("zakat nisab cannot be negative")
+	}
+
+	ratePct, err := strconv.ParseFloat(rate, 64)
+	if err != nil || ratePct <= 0 || ratePct > 100 {
+		return ZakatConfig{}, fmt.Errorf("zakat rate must be a percentage above zero: %q", rate)
+	}
+
+	days, err := strconv.Atoi(haulDays)
+	if err != nil || days < 0 {
+		return ZakatConfig{}, fmt.Errorf("zakat haul must be a number of days: %q", haulDays)
+	}
+
+	rule := strings.ToLower(haulRule)
+	if rule != entities.ZakatHaulRulePerHolding && rule != entities.ZakatHaulRulePortfolio {
+		return ZakatConfig{}, fmt.Errorf("unknown zakat haul rule: %s", haulRule)
+	}
+
+	return ZakatConfig{Nisab: nisabAmount, Rate: ratePct, HaulDays: days, HaulRule: rule}, nil
+}
+
+// ZakatService assesses zakat on investors' portfolios and records zakat paid
+// to the recipients cooperatives designate
+type ZakatService interface {
+	// GetZakatStatement values the investor's active investments and undistributed
+	// profit at asOf and applies the nisab, rate and haul rules
+	GetZakatStatement(ctx context.Context, investorID uuid.UUID, asOf time.Time) (*entities.ZakatStatement, error)
+
+	SetRecipient(ctx context.Context, cooperativeID uuid.UUID, req *entities.SetZakatRecipientRequest, updaterID uuid.UUID) (*entities.ZakatRecipient, error)
+	GetRecipient(ctx context.Context, cooperativeID uuid.UUID) (*entities.ZakatRecipient, error)
+
+	// CreatePayment gives the investor the recipient's account and a reference to transfer zakat with
+	CreatePayment(ctx context.Context, req *entities.CreateZakatPaymentRequest, investorID uuid.UUID) (*entities.ZakatPayment, error)
+	ConfirmPayment(ctx context.Context, cooperativeID, paymentID uuid.UUID, req *entities.ConfirmZakatPaymentRequest, confirmerID uuid.UUID) (*entities.ZakatPayment, error)
+	GetInvestorPayments(ctx context.Context, investorID uuid.UUID) ([]*entities.ZakatPayment, error)
+}
+
+type zakatService struct {
+	zakatRepo       repositories.ZakatRepository
+	fundingService  InvestmentFundingService
+	currencyService CurrencyService
+	auditService    AuditService
+	config          ZakatConfig
+}
+
+// NewZakatService creates a new zakat service
+func NewZakatService(zakatRepo repositories.ZakatRepository, fundingService InvestmentFundingService, currencyService CurrencyService, auditService AuditService, config ZakatConfig) ZakatService {
+	return &zakatService{
+		zakatRepo:       zakatRepo,
+		fundingService:  fundingService,
+		currencyService: currencyService,
+		auditService:    auditService,
+		config:          config,
+	}
+}
+
+// zakatInvestmentPageSize is how many investments are read per page when valuing a portfolio
+const zakatInvestmentPageSize = 100
+
+func (s *zakatService) GetZakatStatement(ctx context.Context, investorID uuid.UUID, asOf time.Time) (*entities.ZakatStatement, error) {
+	investments, err := s.getInvestorInvestments(ctx, investorID)
+	if err != nil {
+		return nil, err
+	}
+
+	baseCurrency := s.currencyService.BaseCurrency()
+	statement := &entities.ZakatStatement{
+		InvestorID:     investorID,
+		AsOf:           asOf,
+		Currency:       baseCurrency,
+		Nisab:          s.config.Nisab,
+		Rate:           s.config.Rate,
+		HaulDays:       s.config.HaulDays,
+		HaulRule:       s.config.HaulRule,
+		TotalValue:     entities.ZeroMoney(baseCurrency),
+		ZakatableValue: entities.ZeroMoney(baseCurrency),
+		ZakatDue:       entities.ZeroMoney(baseCurrency),
+		ZakatPaid:      entities.ZeroMoney(baseCurrency),
+		GeneratedAt:    time.Now(),
+	}
+
+	haulStart := asOf.AddDate(0, 0, -s.config.HaulDays)
+	portfolioHaulCompleted := false
+	for _, investment := range investments {
+		heldSince := investment.CreatedAt
+		if investment.TransferDate != nil {
+			heldSince = *investment.TransferDate
+		}
+		if investment.Status != entities.InvestmentStatusActive || heldSince.After(asOf) {
+			continue
+		}
+
+		principal := investment.Amount.WithCurrency(investment.Currency)
+		profit := entities.ZeroMoney(investment.Currency)
+		if investment.ProfitSharingDate != nil && !investment.ProfitSharingDate.After(asOf) {
+			profit = investment.ProfitSharingAmount.WithCurrency(investment.Currency)
+		}
+
+		value, err := s.currencyService.ConvertToBase(ctx, principal.Add(profit), asOf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to value investment %s: %w", investment.ID, err)
+		}
+
+		holding := &entities.ZakatHolding{
+			InvestmentID:        investment.ID,
+			ProjectID:           investment.ProjectID,
+			CooperativeID:       investment.CooperativeID,
+			Principal:           principal,
+			UndistributedProfit: profit,
+			Currency:            investment.Currency,
+			HeldSince:           heldSince,
+			HaulCompleted:       !heldSince.After(haulStart),
+			Value:               *value,
+		}
+		portfolioHaulCompleted = portfolioHaulCompleted || holding.HaulCompleted
+
+		statement.Holdings = append(statement.Holdings, holding)
+		statement.TotalValue = statement.TotalValue.Add(value.Converted)
+	}
+
+	// Profit follows its capital: an investment's undistributed profit is
+	// zakatable together with the investment
+	for _, holding := range statement.Holdings {
+		switch s.config.HaulRule {
+		case entities.ZakatHaulRulePortfolio:
+			holding.Zakatable = portfolioHaulCompleted
+		default:
+			holding.Zakatable = holding.HaulCompleted
+		}
+		if holding.Zakatable {
+			statement.ZakatableValue = statement.ZakatableValue.Add(holding.Value.Converted)
+		}
+	}
+
+	statement.NisabReached = statement.ZakatableValue.IsPositive() && statement.ZakatableValue.GreaterThanOrEqual(s.config.Nisab)
+	if statement.NisabReached {
+		statement.ZakatDue = statement.ZakatableValue.Percent(s.config.Rate, entities.RoundHalfUp)
+	}
+
+	paid, err := s.zakatPaid(ctx, investorID, haulStart, asOf)
+	if err != nil {
+		return nil, err
+	}
+	statement.ZakatPaid = paid
+	statement.ZakatOutstanding = statement.ZakatDue.Sub(paid).Max(entities.ZeroMoney(baseCurrency))
+
+	return statement, nil
+}
+
+// getInvestorInvestments reads all of the investor's investments
+func (s *zakatService) getInvestorInvestments(ctx context.Context, investorID uuid.UUID) ([]*entities.InvestmentExtended, error) {
+	var investments []*entities.InvestmentExtended
+	for page := 1; ; page++ {
+		batch, total, err := s.fundingService.GetInvestorInvestments(ctx, investorID, page, zakatInvestmentPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get investor investments: %w", err)
+		}
+		investments = append(investments, batch...)
+		if len(batch) == 0 || len(investments) >= total {
+			return investments, nil
+		}
+	}
+}
+
+// zakatPaid totals the investor's confirmed zakat payments within the haul ending at asOf
+func (s *zakatService) zakatPaid(ctx context.Context, investorID uuid.UUID, haulStart, asOf time.Time) (entities.Money, error) {
+	payments, err := s.zakatRepo.ListInvestorPayments(ctx, investorID)
+	if err != nil {
+		return entities.Money{}, err
+	}
+
+	paid := entities.ZeroMoney(s.currencyService.BaseCurrency())
+	for _, payment := range payments {
+		if payment.Status != entities.ZakatPaymentStatusPaid || payment.PaidAt == nil {
+			continue
+		}
+		if !payment.PaidAt.After(haulStart) || payment.PaidAt.After(asOf) {
+			continue
+		}
+
+		converted, err := s.currencyService.ConvertToBase(ctx, payment.Amount, *payment.PaidAt)
+		if err != nil {
+			return entities.Money{}, fmt.Errorf("failed to value zakat payment %s: %w", payment.ID, err)
+		}
+		paid = paid.Add(converted.Converted)
+	}
+
+	return paid, nil
+}
+
+func (s *zakatService) SetRecipient(ctx context.Context, cooperativeID uuid.UUID, req *entities.SetZakatRecipientRequest, updaterID uuid.UUID) (*entities.ZakatRecipient, error) {
+	now := time.Now()
+	recipient := &entities.ZakatRecipient{
+		CooperativeID: cooperativeID,
+		Name:          req.Name,
+		AccountName:   req.AccountName,
+		AccountNumber: req.AccountNumber,
+		BankCode:      strings.ToUpper(req.BankCode),
+		IsActive:      true,
+		UpdatedBy:     updaterID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.zakatRepo.UpsertRecipient(ctx, recipient); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     updaterID,
+		Operation:  "set_zakat_recipient",
+		EntityType: entities.AuditEntityCooperative,
+		EntityID:   cooperativeID,
+		NewValues:  fmt.Sprintf("Zakat recipient %s, account %s at %s", recipient.Name, recipient.AccountNumber, recipient.BankCode),
+	})
+
+	return recipient, nil
+}
+
+func (s *zakatService) GetRecipient(ctx context.Context, cooperativeID uuid.UUID) (*entities.ZakatRecipient, error) {
+	recipient, err := s.zakatRepo.GetRecipient(ctx, cooperativeID)
+	if err != nil {
+		return nil, err
+	}
+	if recipient == nil {
+		return nil, errors.New("cooperative has not designated a zakat recipient")
+	}
+
+	return recipient, nil
+}
+
+func (s *zakatService) CreatePayment(ctx context.Context, req *entities.CreateZakatPaymentRequest, investorID uuid.UUID) (*entities.ZakatPayment, error) {
+	amount, err := req.Amount.InCurrency(req.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zakat amount: %w", err)
+	}
+	if !amount.IsPositive() {
+		return nil, errors.New("zakat amount must be greater than zero")
+	}
+
+	recipient, err := s.GetRecipient(ctx, req.CooperativeID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	paymentID := uuid.New()
+	payment := &entities.ZakatPayment{
+		ID:                paymentID,
+		InvestorID:        investorID,
+		CooperativeID:     req.CooperativeID,
+		RecipientName:     recipient.AccountName,
+		RecipientAccount:  recipient.AccountNumber,
+		RecipientBankCode: recipient.BankCode,
+		Amount:            amount,
+		Currency:          amount.Currency(),
+		Reference:         entities.ZakatPaymentReference(paymentID),
+		Status:            entities.ZakatPaymentStatusPending,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	if err := s.zakatRepo.CreatePayment(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     investorID,
+		Operation:  "create_zakat_payment",
+		EntityType: "zakat_payment",
+		EntityID:   payment.ID,
+		NewValues:  fmt.Sprintf("Zakat of %s to %s, reference %s", amount, recipient.Name, payment.Reference),
+	})
+
+	return payment, nil
+}
+
+func (s *zakatService) ConfirmPayment(ctx context.Context, cooperativeID, paymentID uuid.UUID, req *entities.ConfirmZakatPaymentRequest, confirmerID uuid.UUID) (*entities.ZakatPayment, error) {
+	payment, err := s.zakatRepo.GetPayment(ctx, cooperativeID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != entities.ZakatPaymentStatusPending {
+		return nil, fmt.Errorf("zakat payment is %s and cannot be confirmed", payment.Status)
+	}
+
+	paidAt := time.Now()
+	if req.PaidAt != nil {
+		if req.PaidAt.After(paidAt) {
+			return nil, errors.New("zakat payment cannot be confirmed as paid in the future")
+		}
+		paidAt = *req.PaidAt
+	}
+
+	payment.Status = entities.ZakatPaymentStatusPaid
+	payment.BankReference = req.BankReference
+	payment.PaidAt = &paidAt
+	payment.ConfirmedBy = &confirmerID
+
+	updated, err := s.zakatRepo.UpdatePaymentStatus(ctx, payment, entities.ZakatPaymentStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("zakat payment changed while confirming; retry")
+	}
+
+	s.auditService.LogOperation(ctx, &LogOperationRequest{
+		UserID:     confirmerID,
+		Operation:  "confirm_zakat_payment",
+		EntityType: "zakat_payment",
+		EntityID:   payment.ID,
+		NewValues:  fmt.Sprintf("Zakat of %s received, bank reference %s", payment.Amount, payment.BankReference),
+	})
+
+	return payment, nil
+}
+
+func (s *zakatService) GetInvestorPayments(ctx context.Context, investorID uuid.UUID) ([]*entities.ZakatPayment, error) {
+	return s.zakatRepo.ListInvestorPayments(ctx, investorID)
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"comfunds/internal/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedInvestmentsFunding serves a fixed list of investor investments
+type fixedInvestmentsFunding struct {
+	InvestmentFundingService
+	investments []*entities.InvestmentExtended
+}
+
+func (f *fixedInvestmentsFunding) GetInvestorInvestments(ctx context.Context, investorID uuid.UUID, page, limit int) ([]*entities.InvestmentExtended, int, error) {
+	start := (page - 1) * limit
+	if start >= len(f.investments) {
+		return nil, len(f.investments), nil
+	}
+	end := start + limit
+	if end > len(f.investments) {
+		end = len(f.investments)
+	}
+	return f.investments[start:end], len(f.investments), nil
+}
+
+var zakatAsOf = time.Date(2025, 6, 30, 23, 59, 59, 0, time.UTC)
+
+func TestNewZakatConfig_Validation(t *testing.T) {
+	config, err := NewZakatConfig("85000000", "IDR", "2.5", "354", "Portfolio")
+	require.NoError(t, err)
+	assert.Equal(t, idr("85000000"), config.Nisab)
+	assert.Equal(t, 2.5, config.Rate)
+	assert.Equal(t, 354, config.HaulDays)
+	assert.Equal(t, entities.ZakatHaulRulePortfolio, config.HaulRule)
+
+	testCases := []struct {
+		name                        string
+		nisab, rate, haulDays, rule string
+	}{
+		{name: "Invalid nisab", nisab: "abc", rate: "2.5", haulDays: "354", rule: "per_holding"},
+		{name: "Zero rate", nisab: "85000000", rate: "0", haulDays: "354", rule: "per_holding"},
+		{name: "Negative haul", nisab: "85000000", rate: "2.5", haulDays: "-1", rule: "per_holding"},
+		{name: "Unknown haul rule", nisab: "85000000", rate: "2.5", haulDays: "354", rule: "lunar"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewZakatConfig(tc.nisab, "IDR", tc.rate, tc.haulDays, tc.rule)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestZakatService_GetZakatStatement(t *testing.T) {
+	yearAgo, twoYearsAgo := zakatAsOf.AddDate(-1, 0, 0), zakatAsOf.AddDate(-2, 0, 0)
+	twoMonthsAgo, tomorrow := zakatAsOf.AddDate(0, -2, 0), zakatAsOf.AddDate(0, 0, 1)
+	sharedAt, paidInHaul, paidLastYear := zakatAsOf.AddDate(0, -1, 0), zakatAsOf.AddDate(0, -3, 0), zakatAsOf.AddDate(-1, -1, 0)
+
+	testCases := []struct {
+		name           string
+		haulRule       string
+		investments    []*entities.InvestmentExtended
+		payments       []*entities.ZakatPayment
+		zakatable      []bool
+		zakatableValue string
+		nisabReached   bool
+		zakatDue       string
+		zakatPaid      string
+		outstanding    string
+	}{
+		{
+			// Only holdings kept for a full haul are zakatable
+			name:     "Per holding haul",
+			haulRule: entities.ZakatHaulRulePerHolding,
+			investments: []*entities.InvestmentExtended{
+				{ID: uuid.New(), Amount: idr("90000000"), Currency: "IDR", Status: entities.InvestmentStatusActive, TransferDate: &yearAgo, CreatedAt: yearAgo},
+				{ID: uuid.New(), Amount: idr("20000000"), Currency: "IDR", Status: entities.InvestmentStatusActive, TransferDate: &twoMonthsAgo, CreatedAt: twoMonthsAgo},
+			},
+			zakatable:      []bool{true, false},
+			zakatableValue: "90000000",
+			nisabReached:   true,
+			zakatDue:       "2250000",
+			zakatPaid:      "0",
+			outstanding:    "2250000",
+		},
+		{
+			name:     "Portfolio haul",
+			haulRule: entities.ZakatHaulRulePortfolio,
+			investments: []*entities.InvestmentExtended{
+				{ID: uuid.New(), Amount: idr("90000000"), Currency: "IDR", Status: entities.InvestmentStatusActive, TransferDate: &yearAgo, CreatedAt: yearAgo},
+				{ID: uuid.New(), Amount: idr("20000000"), Currency: "IDR", Status: entities.InvestmentStatusActive, TransferDate: &twoMonthsAgo, CreatedAt: twoMonthsAgo},
+			},
+			zakatable:      []bool{true, true},
+			zakatableValue: "110000000",
+			nisabReached:   true,
+			zakatDue:       "2750000",
+			zakatPaid:      "0",
+			outstanding:    "2750000",
+		},
+		{
+			name:     "Below nisab",
+			haulRule: entities.ZakatHaulRulePerHolding,
+			investments: []*entities.InvestmentExtended{
+				{ID: uuid.New(), Amount: idr("50000000"), Currency: "IDR", Status: entities.InvestmentStatusActive, TransferDate: &twoYearsAgo, CreatedAt: twoYearsAgo},
+			},
+			zakatable:      []bool{true},
+			zakatableValue: "50000000",
+			zakatDue:       "0",
+			zakatPaid:      "0",
+			outstanding:    "0",
+		},
+		{
+			// Investments that are no longer active or not yet held are not valued
+			name:     "Includes undistributed profit",
+			haulRule: entities.ZakatHaulRulePerHolding,
+			investments: []*entities.InvestmentExtended{
+				{ID: uuid.New(), Amount: idr("80000000"), Currency: "IDR", Status: entities.InvestmentStatusActive, TransferDate: &yearAgo, CreatedAt: yearAgo,
+					ProfitSharingAmount: idr("6000000"), ProfitSharingDate: &sharedAt},
+				{ID: uuid.New(), Amount: idr("40000000"), Currency: "IDR", Status: entities.InvestmentStatusCompleted, TransferDate: &twoYearsAgo, CreatedAt: twoYearsAgo},
+				{ID: uuid.New(), Amount: idr("40000000"), Currency: "IDR", Status: entities.InvestmentStatusActive, TransferDate: &tomorrow, CreatedAt: tomorrow},
+			},
+			zakatable:      []bool{true},
+			zakatableValue: "86000000",
+			nisabReached:   true,
+			zakatDue:       "2150000",
+			zakatPaid:      "0",
+			outstanding:    "2150000",
+		},
+		{
+			// Only payments made within the haul count against the zakat due
+			name:     "Deducts payments in haul",
+			haulRule: entities.ZakatHaulRulePerHolding,
+			investments: []*entities.InvestmentExtended{
+				{ID: uuid.New(), Amount: idr("100000000"), Currency: "IDR", Status: entities.InvestmentStatusActive, TransferDate: &yearAgo, CreatedAt: yearAgo},
+			},
+			payments: []*entities.ZakatPayment{
+				{ID: uuid.New(), Amount: idr("1000000"), Currency: "IDR", Status: entities.ZakatPaymentStatusPaid, PaidAt: &paidInHaul},
+				{ID: uuid.New(), Amount: idr("2500000"), Currency: "IDR", Status: entities.ZakatPaymentStatusPaid, PaidAt: &paidLastYear},
+				{ID: uuid.New(), Amount: idr("500000"), Currency: "IDR", Status: entities.ZakatPaymentStatusPending},
+			},
+			zakatable:      []bool{true},
+			zakatableValue: "100000000",
+			nisabReached:   true,
+			zakatDue:       "2500000",
+			zakatPaid:      "1000000",
+			outstanding:    "1500000",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := NewZakatConfig("85000000", "IDR", "2.5", "354", tc.haulRule)
+			require.NoError(t, err)
+			zakatRepo := new(MockZakatRepository)
+			mockAuditService := new(MockAuditService)
+			mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+			currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+			zakatService := NewZakatService(zakatRepo, &fixedInvestmentsFunding{investments: tc.investments}, currencyService, mockAuditService, config)
+
+			investorID := uuid.New()
+			payments := tc.payments
+			if payments == nil {
+				payments = []*entities.ZakatPayment{}
+			}
+			zakatRepo.On("ListInvestorPayments", mock.Anything, investorID).Return(payments, nil)
+
+			statement, err := zakatService.GetZakatStatement(context.Background(), investorID, zakatAsOf)
+
+			require.NoError(t, err)
+			require.Len(t, statement.Holdings, len(tc.zakatable))
+			for i, holding := range statement.Holdings {
+				assert.Equal(t, tc.zakatable[i], holding.Zakatable)
+			}
+			assert.Equal(t, idr(tc.zakatableValue), statement.ZakatableValue)
+			assert.Equal(t, tc.nisabReached, statement.NisabReached)
+			assert.Equal(t, idr(tc.zakatDue), statement.ZakatDue)
+			assert.Equal(t, idr(tc.zakatPaid), statement.ZakatPaid)
+			assert.Equal(t, idr(tc.outstanding), statement.ZakatOutstanding)
+		})
+	}
+}
+
+func TestZakatService_CreatePayment_RequiresRecipient(t *testing.T) {
+	config, err := NewZakatConfig("85000000", "IDR", "2.5", "354", entities.ZakatHaulRulePerHolding)
+	require.NoError(t, err)
+	zakatRepo := new(MockZakatRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	zakatService := NewZakatService(zakatRepo, &fixedInvestmentsFunding{}, currencyService, mockAuditService, config)
+
+	cooperativeID := uuid.New()
+	zakatRepo.On("GetRecipient", mock.Anything, cooperativeID).Return(nil, nil)
+
+	_, err = zakatService.CreatePayment(context.Background(), &entities.CreateZakatPaymentRequest{
+		CooperativeID: cooperativeID,
+		Amount:        idr("2500000"),
+		Currency:      "IDR",
+	}, uuid.New())
+	require.Error(t, err)
+	zakatRepo.AssertNotCalled(t, "CreatePayment", mock.Anything, mock.Anything)
+}
+
+func TestZakatService_CreateAndConfirmPayment(t *testing.T) {
+	config, err := NewZakatConfig("85000000", "IDR", "2.5", "354", entities.ZakatHaulRulePerHolding)
+	require.NoError(t, err)
+	zakatRepo := new(MockZakatRepository)
+	mockAuditService := new(MockAuditService)
+	mockAuditService.On("LogOperation", mock.Anything, mock.AnythingOfType("*services.LogOperationRequest")).Return(nil)
+	currencyService := NewCurrencyService(new(MockCurrencyRepository), mockAuditService, "IDR")
+	zakatService := NewZakatService(zakatRepo, &fixedInvestmentsFunding{}, currencyService, mockAuditService, config)
+	ctx := context.Background()
+
+	cooperativeID := uuid.New()
+	investorID := uuid.New()
+	zakatRepo.On("GetRecipient", mock.Anything, cooperativeID).Return(&entities.ZakatRecipient{
+		CooperativeID: cooperativeID,
+		Name:          "Amil Koperasi",
+		AccountName:   "LAZ KOPERASI",
+		AccountNumber: "1234567890",
+		BankCode:      "BMRIIDJA",
+		IsActive:      true,
+	}, nil)
+	zakatRepo.On("CreatePayment", mock.Anything, mock.AnythingOfType("*entities.ZakatPayment")).Return(nil)
+
+	payment, err := zakatService.CreatePayment(ctx, &entities.CreateZakatPaymentRequest{
+		CooperativeID: cooperativeID,
+		Amount:        idr("2500000"),
+		Currency:      "idr",
+	}, investorID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ZakatPaymentStatusPending, payment.Status)
+	assert.Equal(t, "1234567890", payment.RecipientAccount)
+	assert.Equal(t, entities.ZakatPaymentReference(payment.ID), payment.Reference)
+	assert.Equal(t, "IDR", payment.Currency)
+
+	zakatRepo.On("GetPayment", mock.Anything, cooperativeID, payment.ID).Return(payment, nil)
+	zakatRepo.On("UpdatePaymentStatus", mock.Anything, payment, entities.ZakatPaymentStatusPending).Return(true, nil)
+
+	future := time.Now().Add(time.Hour)
+	_, err = zakatService.ConfirmPayment(ctx, cooperativeID, payment.ID, &entities.ConfirmZakatPaymentRequest{
+		BankReference: "TRX-1",
+		PaidAt:        &future,
+	}, uuid.New())
+	require.Error(t, err)
+
+	confirmed, err := zakatService.ConfirmPayment(ctx, cooperativeID, payment.ID, &entities.ConfirmZakatPaymentRequest{
+		BankReference: "TRX-1",
+	}, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, entities.ZakatPaymentStatusPaid, confirmed.Status)
+	require.NotNil(t, confirmed.PaidAt)
+
+	_, err = zakatService.ConfirmPayment(ctx, cooperativeID, payment.ID, &entities.ConfirmZakatPaymentRequest{
+		BankReference: "TRX-2",
+	}, uuid.New())
+	require.Error(t, err)
+}
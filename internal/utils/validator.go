@@ -2,8 +2,11 @@ package utils
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
+	"comfunds/internal/entities"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -11,6 +14,15 @@ var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+
+	// Validate money amounts by their decimal value so that tags such as
+	// required, min and gtfield keep working on entities.Money fields
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if money, ok := field.Interface().(entities.Money); ok {
+			return money.Float64()
+		}
+		return nil
+	}, entities.Money{})
 }
 
 func ValidateStruct(s interface{}) error {
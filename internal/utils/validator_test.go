@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 
+	"comfunds/internal/entities"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -155,3 +157,29 @@ func TestValidateStruct_NilPointer(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+type MoneyTestStruct struct {
+	MinAmount entities.Money  `validate:"required,gt=0"`
+	MaxAmount entities.Money  `validate:"required,gtfield=MinAmount"`
+	Fee       *entities.Money `validate:"omitempty,min=0"`
+}
+
+func TestValidateStruct_MoneyFields(t *testing.T) {
+	negativeFee := entities.MustParseMoney("-1", "IDR")
+
+	valid := MoneyTestStruct{
+		MinAmount: entities.MustParseMoney("100", "IDR"),
+		MaxAmount: entities.MustParseMoney("100.01", "IDR"),
+	}
+	assert.NoError(t, ValidateStruct(valid))
+
+	invalid := MoneyTestStruct{
+		MaxAmount: entities.MustParseMoney("50", "IDR"),
+		Fee:       &negativeFee,
+	}
+	err := ValidateStruct(invalid)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "MinAmount is required")
+	assert.Contains(t, err.Error(), "Fee is min")
+}
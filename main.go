@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"comfunds/internal/config"
 	"comfunds/internal/controllers"
 	"comfunds/internal/database"
+	"comfunds/internal/middleware"
 	"comfunds/internal/repositories"
 	"comfunds/internal/services"
 
@@ -56,18 +58,190 @@ func main() {
 	auditRepo := repositories.NewAuditRepository(shardMgr)
 	auditService := services.NewAuditService(auditRepo)
 
+	// Initialize ledger repository and service
+	ledgerRepo := repositories.NewLedgerRepository(shardMgr)
+	ledgerService := services.NewLedgerService(ledgerRepo, auditService)
+
+	// Initialize currency registry and load currency precision
+	currencyRepo := repositories.NewCurrencyRepository(shardMgr)
+	currencyService := services.NewCurrencyService(currencyRepo, auditService, cfg.BaseCurrency)
+	if err := currencyService.LoadCurrencies(context.Background()); err != nil {
+		log.Printf("Failed to load currency registry: %v", err)
+	}
+
+	// Initialize Sharia screening; each cooperative's supervisory boards configure the rules
+	shariaScreeningRepo := repositories.NewShariaScreeningRepository(shardMgr)
+	shariaScreeningService := services.NewShariaScreeningService(shariaScreeningRepo, auditService)
+
+	// Initialize AML monitoring; transfers and investments are screened against the configured rules
+	amlRepo := repositories.NewAMLRepository(shardMgr)
+	amlMonitoringService := services.NewAMLMonitoringService(amlRepo, currencyService, auditService)
+
+	// Initialize sanctions screening; members, businesses and payout beneficiaries are screened against the watchlist files
+	sanctionsListSources, err := services.NewSanctionsListSources(cfg.SanctionsLists)
+	if err != nil {
+		log.Fatal("Invalid sanctions lists:", err)
+	}
+	sanctionsMatchThresholds, err := services.NewSanctionsMatchThresholds(cfg.SanctionsNameScore, cfg.SanctionsNameOnlyScore, cfg.SanctionsBirthYearTolerance)
+	if err != nil {
+		log.Fatal("Invalid sanctions match thresholds:", err)
+	}
+	sanctionsReloadInterval, err := time.ParseDuration(cfg.SanctionsReloadInterval)
+	if err != nil || sanctionsReloadInterval <= 0 {
+		log.Fatal("Invalid sanctions reload interval:", cfg.SanctionsReloadInterval)
+	}
+	sanctionsScreeningRepo := repositories.NewSanctionsScreeningRepository(shardMgr)
+	sanctionsScreeningService := services.NewSanctionsScreeningService(sanctionsScreeningRepo, auditService, sanctionsListSources, sanctionsMatchThresholds)
+	go services.NewSanctionsListScheduler(sanctionsScreeningService, sanctionsReloadInterval).Run(context.Background())
+
+	// Initialize payment providers; the simulator stands in for a real gateway outside production
+	var paymentProviders []services.PaymentProvider
+	var paymentSimulator *services.SimulatorPaymentProvider
+	if cfg.Environment != "production" {
+		paymentSimulator = services.NewSimulatorPaymentProvider(cfg.PaymentSimulatorSecret, 24*time.Hour)
+		paymentProviders = append(paymentProviders, paymentSimulator)
+	}
+
+	// Initialize fund transfers; the transfer worker executes due transfers through the payment provider and retries transient failures
+	transferRetryPolicy, err := services.NewTransferRetryPolicy(cfg.TransferMaxRetries, cfg.TransferRetryBaseDelay, cfg.TransferRetryMaxDelay, cfg.TransferBatchSize)
+	if err != nil {
+		log.Fatal("Invalid transfer retry policy:", err)
+	}
+	transferInterval, err := time.ParseDuration(cfg.TransferInterval)
+	if err != nil || transferInterval <= 0 {
+		log.Fatal("Invalid transfer interval:", cfg.TransferInterval)
+	}
+	fundTransferRepo := repositories.NewFundTransferRepository(shardMgr)
+	transferProvider := services.TransferProviderFor(cfg.PaymentProvider, paymentProviders...)
+
 	// Initialize specialized services for cooperative management
-	investmentPolicyService := services.NewInvestmentPolicyService(auditService)
+	investmentPolicyService := services.NewInvestmentPolicyService(auditService, shariaScreeningService)
 	projectApprovalService := services.NewProjectApprovalService(auditService)
-	fundMonitoringService := services.NewFundMonitoringService(auditService)
-	memberRegistryService := services.NewMemberRegistryService(userRepo, cooperativeRepo, auditService)
-	businessManagementService := services.NewBusinessManagementService(auditService)
-	investmentFundingService := services.NewInvestmentFundingService(auditService)
-	fundManagementService := services.NewFundManagementService(auditService)
-	profitSharingService := services.NewProfitSharingService(auditService)
+	fundMonitoringService := services.NewFundMonitoringService(auditService, currencyService, amlMonitoringService, fundTransferRepo, transferProvider, transferRetryPolicy)
+	memberRegistryService := services.NewMemberRegistryService(userRepo, cooperativeRepo, auditService, sanctionsScreeningService)
+	businessManagementService := services.NewBusinessManagementService(auditService, sanctionsScreeningService)
+
+	// Start the transfer worker when the payment provider can send transfers
+	if transferProvider != nil {
+		go services.NewTransferRetryScheduler(fundMonitoringService, transferInterval).Run(context.Background())
+	}
+
+	// Initialize performance analytics; returns are measured from the investments' dated cash flows
+	portfolioPerformanceRepo := repositories.NewPortfolioPerformanceRepository(shardMgr)
+	portfolioPerformanceService := services.NewPortfolioPerformanceService(portfolioPerformanceRepo, currencyService)
+
+	// Initialize investment funding; pending investments reserve their amount against the funding goal until approved, rejected or expired
+	investmentReservationTTL, err := time.ParseDuration(cfg.InvestmentReservationTTL)
+	if err != nil || investmentReservationTTL <= 0 {
+		log.Fatal("Invalid investment reservation TTL:", cfg.InvestmentReservationTTL)
+	}
+	investmentReservationExpiryInterval, err := time.ParseDuration(cfg.InvestmentReservationExpiryInterval)
+	if err != nil || investmentReservationExpiryInterval <= 0 {
+		log.Fatal("Invalid investment reservation expiry interval:", cfg.InvestmentReservationExpiryInterval)
+	}
+	investmentReservationRepo := repositories.NewInvestmentReservationRepository(shardMgr)
+	investmentFundingService := services.NewInvestmentFundingService(auditService, ledgerService, currencyService, portfolioPerformanceService, amlMonitoringService, investmentReservationRepo, investmentReservationTTL)
+	go services.NewReservationExpiryScheduler(investmentFundingService, investmentReservationExpiryInterval).Run(context.Background())
+
+	// Initialize payout batching; approved payouts are queued as they are processed
+	payoutCSVFormat, err := services.NewPayoutCSVFormat(cfg.PayoutCSVColumns, cfg.PayoutCSVDelimiter, cfg.PayoutCSVHeader)
+	if err != nil {
+		log.Fatal("Invalid payout CSV format:", err)
+	}
+	payoutRepo := repositories.NewPayoutRepository(shardMgr)
+	payoutService := services.NewPayoutService(payoutRepo, ledgerService, auditService, payoutCSVFormat, sanctionsScreeningService)
+
+	// Initialize milestone disbursements; funded projects are released tranche by tranche
+	disbursementRepo := repositories.NewDisbursementRepository(shardMgr)
+	fundManagementService := services.NewFundManagementService(disbursementRepo, currencyService, auditService, ledgerService, payoutService)
+
+	// Initialize loss tracking; carried-forward losses are recovered from later profits
+	projectLossRepo := repositories.NewProjectLossRepository(shardMgr)
+	lossSharingService := services.NewLossSharingService(projectLossRepo, auditService)
+
+	// Initialize reinvestment; preferred shares of processed profits become new investments
+	reinvestmentRepo := repositories.NewReinvestmentRepository(shardMgr)
+	reinvestmentService := services.NewReinvestmentService(reinvestmentRepo, investmentFundingService, investmentPolicyService, auditService)
+
+	// Initialize project contracts; profit, loss and murabahah installments follow each project's contract
+	projectContractRepo := repositories.NewProjectContractRepository(shardMgr)
+	projectContractService := services.NewProjectContractService(projectContractRepo, auditService)
+
+	// Initialize fee schedules; project fees are invoiced and waived per cooperative
+	feeRepo := repositories.NewFeeRepository(shardMgr)
+	feeService := services.NewFeeService(feeRepo, currencyService, auditService, ledgerService)
+
+	// Initialize tax withholding; rates follow each cooperative's jurisdiction and the investor's tax profile
+	taxRepo := repositories.NewTaxRepository(shardMgr)
+	taxService, err := services.NewTaxService(taxRepo, auditService, cfg.TaxDefaultJurisdiction)
+	if err != nil {
+		log.Fatal("Invalid tax configuration:", err)
+	}
+	profitSharingService := services.NewProfitSharingService(auditService, ledgerService, payoutService, lossSharingService, reinvestmentService, shariaScreeningService, projectContractService, feeService, taxService)
+
+	// Initialize zakat statements over investor portfolios
+	zakatConfig, err := services.NewZakatConfig(cfg.ZakatNisab, cfg.BaseCurrency, cfg.ZakatRate, cfg.ZakatHaulDays, cfg.ZakatHaulRule)
+	if err != nil {
+		log.Fatal("Invalid zakat configuration:", err)
+	}
+	zakatRepo := repositories.NewZakatRepository(shardMgr)
+	zakatService := services.NewZakatService(zakatRepo, investmentFundingService, currencyService, auditService, zakatConfig)
+
+	// Initialize early withdrawals; exits need notice and cooperative approval before they are refunded
+	withdrawalTerms, err := services.NewWithdrawalTerms(cfg.WithdrawalNoticeDays, cfg.WithdrawalPenalty)
+	if err != nil {
+		log.Fatal("Invalid withdrawal terms:", err)
+	}
+	investmentWithdrawalRepo := repositories.NewInvestmentWithdrawalRepository(shardMgr)
+	investmentWithdrawalService := services.NewInvestmentWithdrawalService(investmentWithdrawalRepo, investmentPolicyService, lossSharingService, ledgerService, payoutService, currencyService, auditService, withdrawalTerms)
+	withdrawalCompletionInterval, err := time.ParseDuration(cfg.WithdrawalCompletionInterval)
+	if err != nil || withdrawalCompletionInterval <= 0 {
+		log.Fatal("Invalid withdrawal completion interval:", cfg.WithdrawalCompletionInterval)
+	}
+	go services.NewWithdrawalCompletionScheduler(investmentWithdrawalService, withdrawalCompletionInterval).Run(context.Background())
+
+	// Initialize the secondary market; members trade stakes subject to cooperative approval
+	stakeMarketRepo := repositories.NewStakeMarketRepository(shardMgr)
+	stakeMarketService := services.NewStakeMarketService(stakeMarketRepo, investmentPolicyService, memberRegistryService, ledgerService, currencyService, auditService)
+	stakeTradeCompletionInterval, err := time.ParseDuration(cfg.StakeTradeCompletionInterval)
+	if err != nil || stakeTradeCompletionInterval <= 0 {
+		log.Fatal("Invalid stake trade completion interval:", cfg.StakeTradeCompletionInterval)
+	}
+	go services.NewStakeTradeCompletionScheduler(stakeMarketService, stakeTradeCompletionInterval).Run(context.Background())
+
+	// Initialize funding deadlines; projects are closed as funded or refunded once their deadline passes
+	notificationRepo := repositories.NewNotificationRepository(shardMgr)
+	notificationService := services.NewNotificationService(notificationRepo)
+	projectFundingRepo := repositories.NewProjectFundingRepository(shardMgr)
+	fundingDeadlineService := services.NewFundingDeadlineService(projectFundingRepo, profitSharingService, ledgerService, notificationService, currencyService, auditService)
+	fundingDeadlineInterval, err := time.ParseDuration(cfg.FundingDeadlineInterval)
+	if err != nil || fundingDeadlineInterval <= 0 {
+		log.Fatal("Invalid funding deadline interval:", cfg.FundingDeadlineInterval)
+	}
+	go services.NewFundingDeadlineScheduler(fundingDeadlineService, fundingDeadlineInterval).Run(context.Background())
+
+	// Initialize profit projections; Monte Carlo runs under each project's contract and loss handling
+	profitProjectionService := services.NewProfitProjectionService(projectContractService, projectFundingRepo, lossSharingService)
+
+	// Initialize investor statements; completed months, quarters and years are archived as PDF and CSV
+	investorStatementRepo := repositories.NewInvestorStatementRepository(shardMgr)
+	investorStatementService := services.NewInvestorStatementService(investorStatementRepo, auditService)
+	statementInterval, err := time.ParseDuration(cfg.StatementInterval)
+	if err != nil || statementInterval <= 0 {
+		log.Fatal("Invalid statement interval:", cfg.StatementInterval)
+	}
+	go services.NewInvestorStatementScheduler(investorStatementService, statementInterval).Run(context.Background())
+
+	// Initialize payments through the configured payment providers
+	paymentRepo := repositories.NewPaymentRepository(shardMgr)
+	paymentService := services.NewPaymentService(paymentRepo, investmentFundingService, auditService, cfg.PaymentProvider, paymentProviders...)
+
+	// Initialize bank statement reconciliation
+	bankReconciliationRepo := repositories.NewBankReconciliationRepository(shardMgr)
+	bankReconciliationService := services.NewBankReconciliationService(bankReconciliationRepo, investmentFundingService, fundMonitoringService, auditService, services.DefaultReconciliationTolerance)
 
 	// Initialize services
-	userService := services.NewUserServiceAuth(userRepo, cooperativeRepo, jwtManager)
+	userService := services.NewUserServiceAuth(userRepo, cooperativeRepo, jwtManager, sanctionsScreeningService)
 	userServiceWithAudit := services.NewUserServiceWithAudit(userService, auditService, userRepo)
 	cooperativeService := services.NewCooperativeService(cooperativeRepo, userRepo, auditService, investmentPolicyService, projectApprovalService, fundMonitoringService, memberRegistryService)
 
@@ -78,9 +252,30 @@ func main() {
 	userControllerWithAudit := controllers.NewUserControllerWithAudit(userServiceWithAudit)
 	cooperativeController := controllers.NewCooperativeController(cooperativeService)
 	businessController := controllers.NewBusinessController(businessManagementService)
-	investmentFundingController := controllers.NewInvestmentFundingController(investmentFundingService)
+	investmentFundingController := controllers.NewInvestmentFundingController(investmentFundingService, paymentService)
 	fundManagementController := controllers.NewFundManagementController(fundManagementService)
-	profitSharingController := controllers.NewProfitSharingController(profitSharingService)
+	profitSharingController := controllers.NewProfitSharingController(profitSharingService, lossSharingService)
+	ledgerController := controllers.NewLedgerController(ledgerService)
+	currencyController := controllers.NewCurrencyController(currencyService)
+	bankReconciliationController := controllers.NewBankReconciliationController(bankReconciliationService)
+	paymentController := controllers.NewPaymentController(paymentService, paymentSimulator)
+	payoutController := controllers.NewPayoutController(payoutService)
+	reinvestmentController := controllers.NewReinvestmentController(reinvestmentService)
+	zakatController := controllers.NewZakatController(zakatService)
+	investmentWithdrawalController := controllers.NewInvestmentWithdrawalController(investmentWithdrawalService)
+	stakeMarketController := controllers.NewStakeMarketController(stakeMarketService)
+	notificationController := controllers.NewNotificationController(notificationService)
+	fundingDeadlineController := controllers.NewFundingDeadlineController(fundingDeadlineService)
+	shariaScreeningController := controllers.NewShariaScreeningController(shariaScreeningService)
+	projectContractController := controllers.NewProjectContractController(projectContractService, profitSharingService)
+	feeController := controllers.NewFeeController(feeService)
+	investorStatementController := controllers.NewInvestorStatementController(investorStatementService)
+	taxController := controllers.NewTaxController(taxService)
+	amlController := controllers.NewAMLController(amlMonitoringService)
+	sanctionsScreeningController := controllers.NewSanctionsScreeningController(sanctionsScreeningService)
+	fundTransferController := controllers.NewFundTransferController(fundMonitoringService)
+	portfolioPerformanceController := controllers.NewPortfolioPerformanceController(portfolioPerformanceService)
+	profitProjectionController := controllers.NewProfitProjectionController(profitProjectionService)
 
 	// Initialize permission middleware
 	permissionMiddleware := auth.NewPermissionMiddleware()
@@ -102,6 +297,9 @@ func main() {
 		c.Next()
 	})
 
+	// Reject requests that combine amounts in different currencies
+	router.Use(middleware.CurrencyMismatchRecovery())
+
 	// API routes
 	v1 := router.Group("/api/v1")
 	{
@@ -138,6 +336,9 @@ func main() {
 		// Role information (public)
 		v1.GET("/roles/info", roleController.GetRoleInfo)
 
+		// Payment provider callbacks (authenticated by provider signature)
+		v1.POST("/payments/callbacks/:provider", paymentController.HandleCallback)
+
 		// Protected routes (require authentication)
 		protected := v1.Group("/")
 		protected.Use(auth.AuthMiddleware(jwtManager))
@@ -192,8 +393,17 @@ func main() {
 				investments.POST("/project/:project_id/limits", permissionMiddleware.RequireAdminRole(), investmentFundingController.SetProjectInvestmentLimits) // Set limits
 
 				// Investor portfolio
-				investments.GET("/portfolio", investmentFundingController.GetInvestorPortfolio)        // Portfolio summary
-				investments.GET("/my-investments", investmentFundingController.GetInvestorInvestments) // My investments
+				investments.GET("/portfolio", investmentFundingController.GetInvestorPortfolio)            // Portfolio summary
+				investments.GET("/portfolio/zakat", zakatController.GetZakatStatement)                     // Zakat statement (?as_of=YYYY-MM-DD)
+				investments.GET("/performance", portfolioPerformanceController.GetMyPerformance)           // XIRR and time-weighted returns (?start_date&end_date)
+				investments.GET("/my-investments", investmentFundingController.GetInvestorInvestments)     // My investments
+				investments.GET("/statements", investorStatementController.GetMyStatements)                // Archived account statements
+				investments.GET("/statements/:id/download", investorStatementController.DownloadStatement) // Download statement (?format=pdf|csv)
+
+				// Early withdrawal (exit before the project completes)
+				investments.POST("/:id/withdrawals", investmentWithdrawalController.RequestWithdrawal)                           // Request withdrawal with notice
+				investments.GET("/withdrawals", investmentWithdrawalController.GetMyWithdrawals)                                 // My withdrawals
+				investments.POST("/project/:project_id/withdrawals/:id/cancel", investmentWithdrawalController.CancelWithdrawal) // Cancel open withdrawal
 			}
 
 			// Investment approval routes (admin/cooperative admin)
@@ -205,6 +415,54 @@ func main() {
 				investmentAdmin.GET("/summary/:cooperative_id", investmentFundingController.GetInvestmentSummary) // Investment summary
 			}
 
+			// Early withdrawal approval and refunds (admin/cooperative admin)
+			withdrawalAdmin := protected.Group("/admin/investment-withdrawals")
+			withdrawalAdmin.Use(permissionMiddleware.RequireAdminRole())
+			{
+				withdrawalAdmin.GET("/cooperatives/:cooperative_id", investmentWithdrawalController.GetCooperativeWithdrawals) // List withdrawals (?status=)
+				withdrawalAdmin.GET("/projects/:project_id/:id", investmentWithdrawalController.GetWithdrawal)                 // Withdrawal details
+				withdrawalAdmin.POST("/projects/:project_id/:id/review", investmentWithdrawalController.ReviewWithdrawal)      // Approve or reject
+				withdrawalAdmin.POST("/projects/:project_id/:id/complete", investmentWithdrawalController.CompleteWithdrawal)  // Refund after notice period
+			}
+
+			// Notifications for the current user
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", notificationController.GetMyNotifications)   // Latest notifications (?unread=true)
+				notifications.POST("/:id/read", notificationController.MarkAsRead) // Mark read
+			}
+
+			// Funding deadlines (admin/cooperative admin); the scheduler closes due projects automatically
+			fundingDeadlines := protected.Group("/admin/funding-deadlines")
+			fundingDeadlines.Use(permissionMiddleware.RequireAdminRole())
+			{
+				fundingDeadlines.POST("/run", fundingDeadlineController.ProcessDueProjects)                         // Close all due projects now
+				fundingDeadlines.GET("/projects/:project_id", fundingDeadlineController.GetProjectClosure)          // Funding outcome
+				fundingDeadlines.POST("/projects/:project_id/close", fundingDeadlineController.CloseProjectFunding) // Close one project past its deadline
+			}
+
+			// Secondary market: members trade investment stakes within their cooperative
+			market := protected.Group("/stake-market")
+			{
+				market.GET("/cooperatives/:cooperative_id/listings", stakeMarketController.GetCooperativeListings) // Open listings
+				market.POST("/investments/:id/listings", stakeMarketController.CreateListing)                      // List all or part of an investment
+				market.GET("/projects/:project_id/listings/:id", stakeMarketController.GetListing)                 // Listing with bids
+				market.POST("/projects/:project_id/listings/:id/cancel", stakeMarketController.CancelListing)      // Cancel open listing
+				market.POST("/projects/:project_id/listings/:id/bids", stakeMarketController.PlaceBid)             // Bid for a stake
+				market.POST("/projects/:project_id/listings/:id/buy", stakeMarketController.BuyAtAsk)              // Buy at the asking price
+				market.POST("/projects/:project_id/bids/:id/withdraw", stakeMarketController.WithdrawBid)          // Withdraw open bid
+				market.POST("/projects/:project_id/bids/:id/accept", stakeMarketController.AcceptBid)              // Seller accepts a bid
+			}
+
+			// Secondary market trade approval (admin/cooperative admin)
+			marketAdmin := protected.Group("/admin/stake-market")
+			marketAdmin.Use(permissionMiddleware.RequireAdminRole())
+			{
+				marketAdmin.GET("/cooperatives/:cooperative_id/trades", stakeMarketController.GetCooperativeTrades) // List trades (?status=)
+				marketAdmin.GET("/projects/:project_id/trades/:id", stakeMarketController.GetTrade)                 // Trade details
+				marketAdmin.POST("/projects/:project_id/trades/:id/review", stakeMarketController.ReviewTrade)      // Approve (transfer) or reject
+			}
+
 			// FR-046 to FR-049: Fund Management System
 			funds := protected.Group("/funds")
 			{
@@ -216,6 +474,10 @@ func main() {
 				funds.POST("/disbursements/:id/reject", fundManagementController.RejectFundDisbursement)           // Reject disbursement
 				funds.POST("/disbursements/:id/process", fundManagementController.ProcessFundDisbursement)         // Process disbursement
 
+				// Milestone tranches (FR-046)
+				funds.GET("/projects/:project_id/disbursement-plan", fundManagementController.GetDisbursementPlan)          // Tranches and released funds
+				funds.POST("/projects/:project_id/tranches/:id/evidence", fundManagementController.SubmitMilestoneEvidence) // Owner completes milestone
+
 				// Fund usage tracking (FR-047)
 				funds.POST("/usage", fundManagementController.CreateFundUsage)                                   // Create fund usage
 				funds.GET("/usage/:id", fundManagementController.GetFundUsage)                                   // Get fund usage details
@@ -239,8 +501,11 @@ func main() {
 			fundAdmin := protected.Group("/admin/funds")
 			fundAdmin.Use(permissionMiddleware.RequireAdminRole())
 			{
-				fundAdmin.GET("/summary/:cooperative_id", fundManagementController.GetFundManagementSummary)       // Fund management summary
-				fundAdmin.GET("/projects/:project_id/analytics", fundManagementController.GetProjectFundAnalytics) // Project fund analytics
+				fundAdmin.GET("/summary/:cooperative_id", fundManagementController.GetFundManagementSummary)                  // Fund management summary
+				fundAdmin.GET("/projects/:project_id/analytics", fundManagementController.GetProjectFundAnalytics)            // Project fund analytics
+				fundAdmin.POST("/projects/:project_id/tranches", fundManagementController.DefineDisbursementTranches)         // Split funding over milestones
+				fundAdmin.POST("/projects/:project_id/tranches/:id/verify", fundManagementController.VerifyMilestone)         // Verify milestone evidence
+				fundAdmin.POST("/projects/:project_id/tranches/:id/close", fundManagementController.CloseDisbursementTranche) // Keep unused budget for refund
 			}
 
 			// Admin routes (cooperative administrators)
@@ -315,6 +580,23 @@ func main() {
 				profitSharing.GET("/distributions/:id", profitSharingController.GetProfitDistribution)                          // Get distribution details
 				profitSharing.GET("/projects/:project_id/distributions", profitSharingController.GetProjectProfitDistributions) // Get project distributions
 
+				// Loss sharing (FR-052)
+				profitSharing.GET("/projects/:project_id/losses", profitSharingController.GetProjectLossHistory) // Get project loss history
+				profitSharing.GET("/loss-shares", profitSharingController.GetMyLossShares)                       // Get current investor's loss shares
+
+				// Project contracts
+				profitSharing.GET("/projects/:project_id/contract", projectContractController.GetContract)         // Get project contract and terms
+				profitSharing.GET("/projects/:project_id/installments", projectContractController.GetInstallments) // Get murabahah installment schedule
+
+				// Profit projections
+				profitSharing.POST("/projects/:project_id/projections", profitProjectionController.SimulateProjection) // Monte Carlo projection of investor returns
+
+				// Reinvestment of profit shares
+				profitSharing.PUT("/reinvestment-preference", reinvestmentController.SetPreference)                                    // Set current investor's preference
+				profitSharing.GET("/cooperatives/:cooperative_id/reinvestment-preference", reinvestmentController.GetPreference)       // Get current investor's preference
+				profitSharing.DELETE("/cooperatives/:cooperative_id/reinvestment-preference", reinvestmentController.RemovePreference) // Stop reinvesting
+				profitSharing.GET("/cooperatives/:cooperative_id/reinvestments", reinvestmentController.GetReinvestments)              // Current investor's reinvestments
+
 				// Tax documentation (FR-057)
 				profitSharing.POST("/tax-documents", profitSharingController.CreateTaxDocumentation)                                   // Create tax document
 				profitSharing.GET("/tax-documents/:id", profitSharingController.GetTaxDocumentation)                                   // Get tax document
@@ -344,6 +626,188 @@ func main() {
 				profitSharingAdmin.GET("/projects/:project_id/analytics", profitSharingController.GetProjectProfitAnalytics) // Project profit analytics
 				profitSharingAdmin.GET("/fees/analytics", profitSharingController.GetComFundsFeeAnalytics)                   // Fee analytics
 			}
+
+			// Double-entry ledger (admin/cooperative admin)
+			ledger := protected.Group("/admin/ledger")
+			ledger.Use(permissionMiddleware.RequireAdminRole())
+			{
+				ledger.GET("/cooperatives/:cooperative_id/accounts", ledgerController.GetCooperativeAccounts) // List ledger accounts
+				ledger.GET("/cooperatives/:cooperative_id/trial-balance", ledgerController.GetTrialBalance)   // Trial balance
+				ledger.GET("/accounts/:id/balance", ledgerController.GetAccountBalance)                       // Account balance
+				ledger.GET("/accounts/:id/entries", ledgerController.GetAccountEntries)                       // Account journal entries
+				ledger.GET("/entries/:id", ledgerController.GetJournalEntry)                                  // Journal entry details
+				ledger.POST("/entries", ledgerController.PostJournalEntry)                                    // Post adjusting entry
+			}
+
+			// Currencies and exchange rates
+			currencies := protected.Group("/currencies")
+			{
+				currencies.GET("", currencyController.ListCurrencies)          // List currencies
+				currencies.GET("/rates", currencyController.ListExchangeRates) // Exchange rate history
+				currencies.GET("/rate", currencyController.GetExchangeRate)    // Rate effective at a time
+				currencies.POST("/convert", currencyController.ConvertAmount)  // Convert an amount
+			}
+
+			currencyAdmin := protected.Group("/admin/currencies")
+			currencyAdmin.Use(permissionMiddleware.RequireAdminRole())
+			{
+				currencyAdmin.POST("", currencyController.CreateCurrency)           // Add or update currency
+				currencyAdmin.POST("/rates", currencyController.CreateExchangeRate) // Record exchange rate
+			}
+
+			// Investment payments
+			payments := protected.Group("/payments")
+			{
+				payments.GET("/:id", paymentController.GetPaymentRequest)               // Payment instructions and status
+				payments.POST("/:id/refresh", paymentController.RefreshPaymentStatus)   // Poll provider for status
+				payments.POST("/:id/simulate/pay", paymentController.SimulatePayment)   // Pay via simulator (non-production)
+				payments.POST("/:id/simulate/expire", paymentController.SimulateExpiry) // Expire via simulator (non-production)
+			}
+
+			// Bank statement reconciliation (admin/cooperative admin)
+			reconciliation := protected.Group("/admin/reconciliation")
+			reconciliation.Use(permissionMiddleware.RequireAdminRole())
+			{
+				reconciliation.POST("/statements", bankReconciliationController.ImportStatement)                                              // Upload CSV/MT940 statement
+				reconciliation.GET("/statements/:id", bankReconciliationController.GetStatement)                                              // Statement details
+				reconciliation.GET("/statements/:id/lines", bankReconciliationController.GetStatementLines)                                   // Statement lines
+				reconciliation.GET("/cooperatives/:cooperative_id/statements", bankReconciliationController.GetStatements)                    // List statements
+				reconciliation.GET("/cooperatives/:cooperative_id/exceptions", bankReconciliationController.GetExceptions)                    // Exceptions queue
+				reconciliation.POST("/cooperatives/:cooperative_id/reconcile", bankReconciliationController.Reconcile)                        // Re-run matching
+				reconciliation.GET("/cooperatives/:cooperative_id/validate-reference", bankReconciliationController.ValidatePaymentReference) // Validate payment reference
+				reconciliation.POST("/lines/:id/match", bankReconciliationController.ManualMatch)                                             // Manually match exception
+				reconciliation.POST("/lines/:id/ignore", bankReconciliationController.IgnoreLine)                                             // Ignore exception
+			}
+
+			// Payout batching and bank files (admin/cooperative admin)
+			payouts := protected.Group("/admin/payouts")
+			payouts.Use(permissionMiddleware.RequireAdminRole())
+			{
+				payouts.GET("/cooperatives/:cooperative_id/queue", payoutController.GetPayoutQueue)     // Queued payouts per escrow account
+				payouts.GET("/cooperatives/:cooperative_id/batches", payoutController.GetPayoutBatches) // List batches
+				payouts.POST("/batches", payoutController.CreatePayoutBatch)                            // Batch an escrow account's queue
+				payouts.GET("/batches/:id", payoutController.GetPayoutBatch)                            // Batch details
+				payouts.GET("/batches/:id/export", payoutController.ExportPayoutBatch)                  // Download pain.001 or CSV file
+				payouts.POST("/batches/:id/returns", payoutController.ImportPayoutReturn)               // Upload pain.002 or CSV return file
+				payouts.PUT("/items/:id/beneficiary", payoutController.UpdatePayoutBeneficiary)         // Supply or correct beneficiary, re-queue failed payout
+			}
+
+			// Zakat payments to cooperatives' designated recipients
+			zakat := protected.Group("/zakat")
+			{
+				zakat.GET("/cooperatives/:cooperative_id/recipient", zakatController.GetZakatRecipient) // Designated recipient account
+				zakat.POST("/payments", zakatController.CreateZakatPayment)                             // Start a zakat payment
+				zakat.GET("/payments", zakatController.GetMyZakatPayments)                              // Current investor's zakat payments
+			}
+
+			zakatAdmin := protected.Group("/admin/zakat")
+			zakatAdmin.Use(permissionMiddleware.RequireAdminRole())
+			{
+				zakatAdmin.PUT("/cooperatives/:cooperative_id/recipient", zakatController.SetZakatRecipient)               // Designate recipient account
+				zakatAdmin.POST("/cooperatives/:cooperative_id/payments/:id/confirm", zakatController.ConfirmZakatPayment) // Confirm received payment
+			}
+
+			// Sharia screening rules (admin/cooperative admin)
+			shariaScreening := protected.Group("/admin/sharia-screening")
+			shariaScreening.Use(permissionMiddleware.RequireAdminRole())
+			{
+				shariaScreening.GET("/cooperatives/:cooperative_id/rule-sets", shariaScreeningController.GetRuleSets)              // Rule sets in force
+				shariaScreening.POST("/cooperatives/:cooperative_id/rule-sets", shariaScreeningController.CreateRuleSet)           // Add a board's rule set
+				shariaScreening.PUT("/cooperatives/:cooperative_id/rule-sets/:id", shariaScreeningController.UpdateRuleSet)        // Replace a board's rules
+				shariaScreening.DELETE("/cooperatives/:cooperative_id/rule-sets/:id", shariaScreeningController.DeactivateRuleSet) // Stop screening under a rule set
+				shariaScreening.POST("/cooperatives/:cooperative_id/evaluate", shariaScreeningController.Evaluate)                 // Screen a business and its profit terms
+				shariaScreening.GET("/projects/:project_id", shariaScreeningController.ScreenProject)                              // Screen a project's business
+			}
+
+			// Project contracts and murabahah installments (admin/cooperative admin)
+			projectContracts := protected.Group("/admin/project-contracts")
+			projectContracts.Use(permissionMiddleware.RequireAdminRole())
+			{
+				projectContracts.PUT("/projects/:project_id", projectContractController.SetContract)                                         // Set contract type and terms
+				projectContracts.POST("/projects/:project_id/installments/:id/payments", projectContractController.RecordInstallmentPayment) // Record business's installment payment
+				projectContracts.POST("/projects/:project_id/installments/:id/settle", projectContractController.SettleInstallment)          // Settle paid installment to investors
+			}
+
+			// Promotional fee waivers and fee documents (admin/cooperative admin)
+			feeAdmin := protected.Group("/admin/fees")
+			feeAdmin.Use(permissionMiddleware.RequireAdminRole())
+			{
+				feeAdmin.POST("/waivers", feeController.CreateWaiver)                                    // Create promotional waiver
+				feeAdmin.GET("/waivers", feeController.GetWaivers)                                       // Waivers in force
+				feeAdmin.DELETE("/waivers/:id", feeController.RevokeWaiver)                              // End waiver early
+				feeAdmin.GET("/cooperatives/:cooperative_id/invoices", feeController.GetInvoices)        // Cooperative's fee invoices
+				feeAdmin.GET("/cooperatives/:cooperative_id/credit-notes", feeController.GetCreditNotes) // Cooperative's fee credit notes
+			}
+
+			// Investor statement generation (admin/cooperative admin)
+			statementAdmin := protected.Group("/admin/statements")
+			statementAdmin.Use(permissionMiddleware.RequireAdminRole())
+			{
+				statementAdmin.POST("/generate", investorStatementController.GenerateStatements) // Generate or backfill a period's statements
+			}
+
+			// Tax withholding profiles, rates and reports
+			tax := protected.Group("/tax")
+			{
+				tax.GET("/profile", taxController.GetMyTaxProfile)  // Current investor's tax profile
+				tax.PUT("/profile", taxController.SaveMyTaxProfile) // Set investor type, tax ID and residence
+			}
+
+			taxAdmin := protected.Group("/admin/tax")
+			taxAdmin.Use(permissionMiddleware.RequireAdminRole())
+			{
+				taxAdmin.GET("/jurisdictions/:jurisdiction", taxController.GetJurisdiction)                          // Jurisdiction with its withholding rates
+				taxAdmin.POST("/rates", taxController.CreateRate)                                                    // Configure a withholding rate
+				taxAdmin.DELETE("/rates/:id", taxController.DeactivateRate)                                          // Withdraw a withholding rate
+				taxAdmin.PUT("/cooperatives/:cooperative_id/jurisdiction", taxController.SetCooperativeJurisdiction) // Jurisdiction a cooperative withholds under
+				taxAdmin.GET("/cooperatives/:cooperative_id/withholding-report", taxController.GetWithholdingReport) // Tax withheld per period
+			}
+
+			// Portfolio performance analytics (admin/cooperative admin)
+			performanceAdmin := protected.Group("/admin/performance")
+			performanceAdmin.Use(permissionMiddleware.RequireAdminRole())
+			{
+				performanceAdmin.GET("/investors/:investor_id", portfolioPerformanceController.GetInvestorPerformance)          // Investor's portfolio performance
+				performanceAdmin.GET("/projects/:project_id", portfolioPerformanceController.GetProjectPerformance)             // Project's investment performance
+				performanceAdmin.GET("/cooperatives/:cooperative_id", portfolioPerformanceController.GetCooperativePerformance) // Cooperative's investment performance
+			}
+
+			// AML transaction monitoring rules and compliance cases
+			amlAdmin := protected.Group("/admin/aml")
+			amlAdmin.Use(permissionMiddleware.RequireAdminRole())
+			{
+				amlAdmin.GET("/rules", amlController.ListRules)                                                      // Active rules, optionally for a cooperative
+				amlAdmin.POST("/rules", amlController.CreateRule)                                                    // Configure a monitoring rule
+				amlAdmin.DELETE("/rules/:id", amlController.DeactivateRule)                                          // Withdraw a monitoring rule
+				amlAdmin.GET("/cooperatives/:cooperative_id/cases", amlController.ListCases)                         // Cooperative's alert cases
+				amlAdmin.GET("/cooperatives/:cooperative_id/cases/:case_id", amlController.GetCase)                  // Case with its alerts, transactions and history
+				amlAdmin.POST("/cooperatives/:cooperative_id/cases/:case_id/investigate", amlController.Investigate) // Take up an open case
+				amlAdmin.POST("/cooperatives/:cooperative_id/cases/:case_id/escalate", amlController.Escalate)       // Escalate a case
+				amlAdmin.POST("/cooperatives/:cooperative_id/cases/:case_id/close", amlController.Close)             // Close a case with a reason
+				amlAdmin.GET("/cooperatives/:cooperative_id/cases/:case_id/report", amlController.ExportReport)      // Suspicious transaction report (CSV)
+			}
+
+			// Sanctions watchlists and screening reviews
+			sanctionsAdmin := protected.Group("/admin/sanctions")
+			sanctionsAdmin.Use(permissionMiddleware.RequireAdminRole())
+			{
+				sanctionsAdmin.GET("/lists", sanctionsScreeningController.GetLists)                         // Watchlists as last loaded
+				sanctionsAdmin.POST("/lists/reload", sanctionsScreeningController.ReloadLists)              // Reload changed list files
+				sanctionsAdmin.GET("/screenings", sanctionsScreeningController.ListScreenings)              // Screenings, e.g. ?status=pending_review
+				sanctionsAdmin.GET("/screenings/:id", sanctionsScreeningController.GetScreening)            // Screening with its potential matches
+				sanctionsAdmin.POST("/screenings/:id/review", sanctionsScreeningController.ReviewScreening) // Clear or confirm the matches
+			}
+
+			// Fund transfer retries and the dead-letter queue
+			transfersAdmin := protected.Group("/admin/transfers")
+			transfersAdmin.Use(permissionMiddleware.RequireAdminRole())
+			{
+				transfersAdmin.GET("/dead-letter", fundTransferController.ListDeadLettered) // Transfers that failed permanently or exhausted their retries
+				transfersAdmin.GET("/:id", fundTransferController.GetTransfer)              // Transfer with its retry state
+				transfersAdmin.GET("/:id/attempts", fundTransferController.ListAttempts)    // Every execution attempt
+				transfersAdmin.POST("/:id/requeue", fundTransferController.RequeueTransfer) // Retry a dead-lettered transfer
+				transfersAdmin.POST("/:id/cancel", fundTransferController.CancelTransfer)   // Cancel a transfer not yet executed
+			}
 		}
 	}
 
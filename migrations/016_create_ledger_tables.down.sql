@@ -0,0 +1,15 @@
+DROP TRIGGER IF EXISTS check_journal_lines_balanced ON journal_lines;
+DROP FUNCTION IF EXISTS check_journal_entry_balanced();
+DROP TRIGGER IF EXISTS update_ledger_accounts_updated_at ON ledger_accounts;
+DROP INDEX IF EXISTS idx_journal_lines_account_id;
+DROP INDEX IF EXISTS idx_journal_lines_entry_id;
+DROP INDEX IF EXISTS idx_journal_entries_posted_at;
+DROP INDEX IF EXISTS idx_journal_entries_reference;
+DROP INDEX IF EXISTS idx_journal_entries_project_id;
+DROP INDEX IF EXISTS idx_journal_entries_cooperative_id;
+DROP INDEX IF EXISTS idx_ledger_accounts_owner_id;
+DROP INDEX IF EXISTS idx_ledger_accounts_category;
+DROP INDEX IF EXISTS idx_ledger_accounts_cooperative_id;
+DROP TABLE IF EXISTS journal_lines;
+DROP TABLE IF EXISTS journal_entries;
+DROP TABLE IF EXISTS ledger_accounts;
@@ -0,0 +1,95 @@
+-- Create ledger accounts table
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cooperative_id UUID NOT NULL,
+    code VARCHAR(100) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    account_type VARCHAR(20) NOT NULL,
+    category VARCHAR(30) NOT NULL,
+    owner_id UUID,
+    currency VARCHAR(3) NOT NULL DEFAULT 'IDR',
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_ledger_account_type CHECK (account_type IN ('asset', 'liability', 'equity', 'income', 'expense')),
+    CONSTRAINT chk_ledger_account_category CHECK (category IN ('escrow', 'investor', 'business', 'platform_fee', 'tax_payable')),
+    CONSTRAINT unique_ledger_account_code UNIQUE (cooperative_id, code, currency)
+);
+
+-- Create journal entries table
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    entry_number VARCHAR(50) UNIQUE NOT NULL,
+    cooperative_id UUID NOT NULL,
+    project_id UUID,
+    entry_type VARCHAR(30) NOT NULL,
+    reference_type VARCHAR(50) NOT NULL,
+    reference_id UUID NOT NULL,
+    description TEXT NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'IDR',
+    total_amount DECIMAL(15,2) NOT NULL CHECK (total_amount > 0),
+    posted_by UUID NOT NULL,
+    posted_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_journal_entry_type CHECK (entry_type IN ('investment', 'disbursement', 'refund', 'profit_distribution', 'fee_collection', 'adjustment'))
+);
+
+-- Create journal lines table
+CREATE TABLE IF NOT EXISTS journal_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    entry_id UUID NOT NULL,
+    account_id UUID NOT NULL,
+    debit DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (debit >= 0),
+    credit DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (credit >= 0),
+    memo VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_journal_lines_entry FOREIGN KEY (entry_id) REFERENCES journal_entries(id) ON DELETE CASCADE,
+    CONSTRAINT fk_journal_lines_account FOREIGN KEY (account_id) REFERENCES ledger_accounts(id),
+    CONSTRAINT chk_journal_line_side CHECK ((debit > 0 AND credit = 0) OR (credit > 0 AND debit = 0))
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_ledger_accounts_cooperative_id ON ledger_accounts(cooperative_id);
+CREATE INDEX idx_ledger_accounts_category ON ledger_accounts(category);
+CREATE INDEX idx_ledger_accounts_owner_id ON ledger_accounts(owner_id);
+CREATE INDEX idx_journal_entries_cooperative_id ON journal_entries(cooperative_id);
+CREATE INDEX idx_journal_entries_project_id ON journal_entries(project_id);
+CREATE INDEX idx_journal_entries_reference ON journal_entries(reference_type, reference_id);
+CREATE INDEX idx_journal_entries_posted_at ON journal_entries(posted_at);
+CREATE INDEX idx_journal_lines_entry_id ON journal_lines(entry_id);
+CREATE INDEX idx_journal_lines_account_id ON journal_lines(account_id);
+
+-- Create trigger for updated_at
+CREATE TRIGGER update_ledger_accounts_updated_at
+    BEFORE UPDATE ON ledger_accounts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Reject journal entries whose debits and credits do not balance at commit time
+CREATE OR REPLACE FUNCTION check_journal_entry_balanced()
+RETURNS TRIGGER AS $$
+DECLARE
+    total_debit DECIMAL(15,2);
+    total_credit DECIMAL(15,2);
+BEGIN
+    SELECT COALESCE(SUM(debit), 0), COALESCE(SUM(credit), 0)
+    INTO total_debit, total_credit
+    FROM journal_lines
+    WHERE entry_id = NEW.entry_id;
+
+    IF total_debit <> total_credit THEN
+        RAISE EXCEPTION 'journal entry % is unbalanced: debits % credits %', NEW.entry_id, total_debit, total_credit;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER check_journal_lines_balanced
+    AFTER INSERT OR UPDATE ON journal_lines
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_journal_entry_balanced();
+
+COMMENT ON TABLE journal_entries IS 'Double-entry journal; every entry must have equal debits and credits';
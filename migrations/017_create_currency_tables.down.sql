@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS update_currencies_updated_at ON currencies;
+DROP INDEX IF EXISTS idx_exchange_rates_pair_effective_at;
+DROP TABLE IF EXISTS exchange_rates;
+DROP TABLE IF EXISTS currencies;
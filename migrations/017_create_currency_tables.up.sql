@@ -0,0 +1,44 @@
+-- Create currencies registry table
+-- Reference data: replicated to every shard
+CREATE TABLE IF NOT EXISTS currencies (
+    code VARCHAR(3) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    symbol VARCHAR(10) NOT NULL DEFAULT '',
+    minor_units SMALLINT NOT NULL DEFAULT 2,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_currency_minor_units CHECK (minor_units BETWEEN 0 AND 4)
+);
+
+-- Create exchange rates table
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    base_currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    quote_currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    rate NUMERIC(24,12) NOT NULL CHECK (rate > 0),
+    source VARCHAR(30) NOT NULL DEFAULT 'manual',
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_exchange_rate_pair CHECK (base_currency <> quote_currency),
+    CONSTRAINT chk_exchange_rate_source CHECK (source IN ('manual', 'bank_indonesia', 'bank_negara', 'provider'))
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_exchange_rates_pair_effective_at ON exchange_rates(base_currency, quote_currency, effective_at DESC);
+
+-- Create trigger for updated_at
+CREATE TRIGGER update_currencies_updated_at
+    BEFORE UPDATE ON currencies
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Seed supported currencies
+INSERT INTO currencies (code, name, symbol, minor_units) VALUES
+    ('IDR', 'Indonesian Rupiah', 'Rp', 2),
+    ('MYR', 'Malaysian Ringgit', 'RM', 2),
+    ('USD', 'US Dollar', '$', 2),
+    ('SGD', 'Singapore Dollar', 'S$', 2)
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS update_bank_statement_lines_updated_at ON bank_statement_lines;
+DROP INDEX IF EXISTS idx_bank_statement_lines_matched_entity;
+DROP INDEX IF EXISTS idx_bank_statement_lines_cooperative_status;
+DROP INDEX IF EXISTS idx_bank_statement_lines_statement_id;
+DROP INDEX IF EXISTS idx_bank_statements_cooperative_id;
+DROP TABLE IF EXISTS bank_statement_lines;
+DROP TABLE IF EXISTS bank_statements;
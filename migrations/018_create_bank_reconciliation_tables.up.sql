@@ -0,0 +1,69 @@
+-- Create bank statements table
+CREATE TABLE IF NOT EXISTS bank_statements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cooperative_id UUID NOT NULL,
+    escrow_account_id UUID,
+    format VARCHAR(10) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    file_hash VARCHAR(64) NOT NULL,
+    statement_ref VARCHAR(100),
+    account_number VARCHAR(50),
+    currency VARCHAR(3) NOT NULL,
+    opening_balance NUMERIC(20,4),
+    closing_balance NUMERIC(20,4),
+    period_start DATE,
+    period_end DATE,
+    line_count INTEGER NOT NULL DEFAULT 0,
+    matched_count INTEGER NOT NULL DEFAULT 0,
+    exception_count INTEGER NOT NULL DEFAULT 0,
+    imported_by UUID NOT NULL,
+    imported_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_bank_statement_format CHECK (format IN ('csv', 'mt940')),
+    CONSTRAINT unique_bank_statement_file UNIQUE (cooperative_id, file_hash)
+);
+
+-- Create bank statement lines table
+CREATE TABLE IF NOT EXISTS bank_statement_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    statement_id UUID NOT NULL REFERENCES bank_statements(id) ON DELETE CASCADE,
+    cooperative_id UUID NOT NULL,
+    line_number INTEGER NOT NULL,
+    value_date DATE NOT NULL,
+    booking_date DATE,
+    direction VARCHAR(10) NOT NULL,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount >= 0),
+    currency VARCHAR(3) NOT NULL,
+    reference VARCHAR(255),
+    description TEXT,
+    counterparty_name VARCHAR(255),
+    counterparty_account VARCHAR(50),
+    bank_transaction_id VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'unmatched',
+    exception_reason VARCHAR(30),
+    match_type VARCHAR(20),
+    matched_entity_id UUID,
+    match_method VARCHAR(20),
+    matched_by UUID,
+    matched_at TIMESTAMP WITH TIME ZONE,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_statement_line_direction CHECK (direction IN ('credit', 'debit')),
+    CONSTRAINT chk_statement_line_status CHECK (status IN ('unmatched', 'matched', 'exception', 'ignored')),
+    CONSTRAINT chk_statement_line_match_type CHECK (match_type IS NULL OR match_type IN ('investment', 'fund_transfer')),
+    CONSTRAINT unique_statement_line_transaction UNIQUE (cooperative_id, bank_transaction_id)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_bank_statements_cooperative_id ON bank_statements(cooperative_id);
+CREATE INDEX IF NOT EXISTS idx_bank_statement_lines_statement_id ON bank_statement_lines(statement_id);
+CREATE INDEX IF NOT EXISTS idx_bank_statement_lines_cooperative_status ON bank_statement_lines(cooperative_id, status);
+-- An investment or transfer can be settled by only one statement line
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bank_statement_lines_matched_entity ON bank_statement_lines(matched_entity_id) WHERE status = 'matched';
+
+-- Create trigger for updated_at
+CREATE TRIGGER update_bank_statement_lines_updated_at
+    BEFORE UPDATE ON bank_statement_lines
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
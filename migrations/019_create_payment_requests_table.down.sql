@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS update_payment_requests_updated_at ON payment_requests;
+DROP INDEX IF EXISTS idx_payment_requests_cooperative_status;
+DROP INDEX IF EXISTS idx_payment_requests_investment_id;
+DROP INDEX IF EXISTS idx_payment_requests_pending_investment;
+DROP TABLE IF EXISTS payment_requests;
@@ -0,0 +1,38 @@
+-- Create payment requests table for investments paid through a payment provider
+CREATE TABLE IF NOT EXISTS payment_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    investment_id UUID NOT NULL,
+    cooperative_id UUID NOT NULL,
+    investor_id UUID NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    reference VARCHAR(50) NOT NULL,
+    provider_reference VARCHAR(100) NOT NULL,
+    virtual_account_number VARCHAR(50),
+    bank_code VARCHAR(20),
+    payment_url TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    paid_amount NUMERIC(20,4),
+    paid_at TIMESTAMP WITH TIME ZONE,
+    provider_transaction_id VARCHAR(100),
+    failure_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_payment_request_status CHECK (status IN ('pending', 'paid', 'expired', 'failed', 'cancelled')),
+    CONSTRAINT chk_payment_request_method CHECK (method IN ('bank_transfer', 'digital_wallet')),
+    CONSTRAINT unique_payment_provider_reference UNIQUE (provider, provider_reference)
+);
+
+-- At most one open payment request per investment
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_requests_pending_investment ON payment_requests(investment_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_payment_requests_investment_id ON payment_requests(investment_id);
+CREATE INDEX IF NOT EXISTS idx_payment_requests_cooperative_status ON payment_requests(cooperative_id, status);
+
+-- Create trigger for updated_at
+CREATE TRIGGER update_payment_requests_updated_at
+    BEFORE UPDATE ON payment_requests
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
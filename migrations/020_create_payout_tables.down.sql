@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS update_payout_items_updated_at ON payout_items;
+DROP TRIGGER IF EXISTS update_payout_batches_updated_at ON payout_batches;
+DROP INDEX IF EXISTS idx_payout_items_source;
+DROP INDEX IF EXISTS idx_payout_items_cooperative_status;
+DROP INDEX IF EXISTS idx_payout_items_batch_id;
+DROP INDEX IF EXISTS idx_payout_batches_cooperative_id;
+DROP TABLE IF EXISTS payout_items;
+DROP TABLE IF EXISTS payout_batches;
+ALTER TABLE ledger_accounts
+DROP CONSTRAINT IF EXISTS chk_ledger_account_category,
+ADD CONSTRAINT chk_ledger_account_category CHECK (category IN ('escrow', 'investor', 'business', 'platform_fee', 'tax_payable'));
@@ -0,0 +1,74 @@
+-- Payouts the bank returns are held in a payouts pending ledger account until paid again
+ALTER TABLE ledger_accounts
+DROP CONSTRAINT IF EXISTS chk_ledger_account_category,
+ADD CONSTRAINT chk_ledger_account_category CHECK (category IN ('escrow', 'investor', 'business', 'platform_fee', 'tax_payable', 'payouts_pending'));
+
+-- Create payout batches table
+CREATE TABLE IF NOT EXISTS payout_batches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cooperative_id UUID NOT NULL,
+    escrow_account_id UUID NOT NULL,
+    reference VARCHAR(35) NOT NULL UNIQUE,
+    currency VARCHAR(3) NOT NULL,
+    debtor_name VARCHAR(140) NOT NULL,
+    debtor_account VARCHAR(34) NOT NULL,
+    debtor_bank_code VARCHAR(35) NOT NULL,
+    execution_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    item_count INTEGER NOT NULL DEFAULT 0,
+    total_amount NUMERIC(20,4) NOT NULL CHECK (total_amount >= 0),
+    completed_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    export_format VARCHAR(10),
+    exported_by UUID,
+    exported_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_payout_batch_status CHECK (status IN ('draft', 'exported', 'completed', 'partially_completed', 'failed')),
+    CONSTRAINT chk_payout_batch_export_format CHECK (export_format IS NULL OR export_format IN ('pain001', 'csv'))
+);
+
+-- Create payout items table
+CREATE TABLE IF NOT EXISTS payout_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cooperative_id UUID NOT NULL,
+    escrow_account_id UUID NOT NULL,
+    batch_id UUID REFERENCES payout_batches(id),
+    payout_type VARCHAR(20) NOT NULL,
+    source_id UUID NOT NULL,
+    beneficiary_name VARCHAR(140),
+    beneficiary_account VARCHAR(34),
+    beneficiary_bank_code VARCHAR(35),
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    reference VARCHAR(35) NOT NULL UNIQUE,
+    description VARCHAR(140),
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    failure_reason TEXT,
+    bank_reference VARCHAR(100),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_payout_item_type CHECK (payout_type IN ('disbursement', 'refund', 'profit_share')),
+    CONSTRAINT chk_payout_item_status CHECK (status IN ('queued', 'batched', 'completed', 'failed')),
+    CONSTRAINT chk_payout_item_batched CHECK (status = 'queued' OR batch_id IS NOT NULL)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_payout_batches_cooperative_id ON payout_batches(cooperative_id);
+CREATE INDEX IF NOT EXISTS idx_payout_items_batch_id ON payout_items(batch_id);
+CREATE INDEX IF NOT EXISTS idx_payout_items_cooperative_status ON payout_items(cooperative_id, status);
+-- A source is paid by at most one payout; failed payouts are re-queued as a new item
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payout_items_source ON payout_items(payout_type, source_id) WHERE status <> 'failed';
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_payout_batches_updated_at
+    BEFORE UPDATE ON payout_batches
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_payout_items_updated_at
+    BEFORE UPDATE ON payout_items
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
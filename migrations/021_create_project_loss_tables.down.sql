@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS update_investor_loss_shares_updated_at ON investor_loss_shares;
+DROP TRIGGER IF EXISTS update_project_losses_updated_at ON project_losses;
+DROP INDEX IF EXISTS idx_project_loss_recoveries_project_id;
+DROP INDEX IF EXISTS idx_investor_loss_shares_investor_id;
+DROP INDEX IF EXISTS idx_investor_loss_shares_loss_id;
+DROP INDEX IF EXISTS idx_project_losses_project_period;
+DROP TABLE IF EXISTS project_loss_recoveries;
+DROP TABLE IF EXISTS investor_loss_shares;
+DROP TABLE IF EXISTS project_losses;
@@ -0,0 +1,72 @@
+-- Create project losses table
+CREATE TABLE IF NOT EXISTS project_losses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL,
+    cooperative_id UUID NOT NULL,
+    profit_calculation_id UUID NOT NULL UNIQUE,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    loss_handling_method VARCHAR(20) NOT NULL,
+    borne_by VARCHAR(20) NOT NULL,
+    loss_amount NUMERIC(20,4) NOT NULL CHECK (loss_amount > 0),
+    recovered_amount NUMERIC(20,4) NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    recorded_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_project_loss_method CHECK (loss_handling_method IN ('carry_forward', 'shared', 'absorb')),
+    CONSTRAINT chk_project_loss_borne_by CHECK (borne_by IN ('investors', 'cooperative')),
+    CONSTRAINT chk_project_loss_status CHECK (status IN ('carried_forward', 'recovered', 'written_off', 'absorbed')),
+    CONSTRAINT chk_project_loss_recovered CHECK (recovered_amount >= 0 AND recovered_amount <= loss_amount)
+);
+
+-- Create investor loss shares table
+CREATE TABLE IF NOT EXISTS investor_loss_shares (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_loss_id UUID NOT NULL REFERENCES project_losses(id),
+    project_id UUID NOT NULL,
+    investment_id UUID NOT NULL,
+    investor_id UUID NOT NULL,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    original_investment NUMERIC(20,4) NOT NULL,
+    investment_percentage DECIMAL(7,4) NOT NULL,
+    loss_amount NUMERIC(20,4) NOT NULL CHECK (loss_amount >= 0),
+    recovered_amount NUMERIC(20,4) NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_investor_loss_share_recovered CHECK (recovered_amount >= 0 AND recovered_amount <= loss_amount)
+);
+
+-- Create project loss recoveries table
+CREATE TABLE IF NOT EXISTS project_loss_recoveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_loss_id UUID NOT NULL REFERENCES project_losses(id),
+    project_id UUID NOT NULL,
+    profit_calculation_id UUID NOT NULL,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    recorded_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_project_loss_recovery UNIQUE (project_loss_id, profit_calculation_id)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_project_losses_project_period ON project_losses(project_id, period_end);
+CREATE INDEX IF NOT EXISTS idx_investor_loss_shares_loss_id ON investor_loss_shares(project_loss_id);
+CREATE INDEX IF NOT EXISTS idx_investor_loss_shares_investor_id ON investor_loss_shares(investor_id);
+CREATE INDEX IF NOT EXISTS idx_project_loss_recoveries_project_id ON project_loss_recoveries(project_id);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_project_losses_updated_at
+    BEFORE UPDATE ON project_losses
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_investor_loss_shares_updated_at
+    BEFORE UPDATE ON investor_loss_shares
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS update_reinvestment_preferences_updated_at ON reinvestment_preferences;
+DROP INDEX IF EXISTS idx_reinvestments_distribution;
+DROP INDEX IF EXISTS idx_reinvestments_investor;
+DROP INDEX IF EXISTS idx_reinvestment_preferences_cooperative;
+DROP TABLE IF EXISTS reinvestments;
+DROP TABLE IF EXISTS reinvestment_preferences;
@@ -0,0 +1,48 @@
+-- Create reinvestment preferences table
+CREATE TABLE IF NOT EXISTS reinvestment_preferences (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    investor_id UUID NOT NULL,
+    cooperative_id UUID NOT NULL,
+    percentage DECIMAL(5,2) NOT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_project_id UUID,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_reinvestment_preference UNIQUE (investor_id, cooperative_id),
+    CONSTRAINT chk_reinvestment_percentage CHECK (percentage > 0 AND percentage <= 100),
+    CONSTRAINT chk_reinvestment_target_type CHECK (target_type IN ('same_project', 'project', 'any_eligible')),
+    CONSTRAINT chk_reinvestment_target_project CHECK ((target_type = 'project') = (target_project_id IS NOT NULL))
+);
+
+-- Create reinvestments table
+CREATE TABLE IF NOT EXISTS reinvestments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    preference_id UUID NOT NULL REFERENCES reinvestment_preferences(id),
+    profit_distribution_id UUID NOT NULL,
+    profit_share_id UUID NOT NULL UNIQUE,
+    investor_id UUID NOT NULL,
+    cooperative_id UUID NOT NULL,
+    source_project_id UUID NOT NULL,
+    target_project_id UUID,
+    investment_id UUID,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_reinvestment_status CHECK (status IN ('invested', 'skipped')),
+    CONSTRAINT chk_reinvestment_investment CHECK ((status = 'invested') = (investment_id IS NOT NULL))
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_reinvestment_preferences_cooperative ON reinvestment_preferences(cooperative_id, is_active);
+CREATE INDEX IF NOT EXISTS idx_reinvestments_investor ON reinvestments(cooperative_id, investor_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_reinvestments_distribution ON reinvestments(profit_distribution_id);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_reinvestment_preferences_updated_at
+    BEFORE UPDATE ON reinvestment_preferences
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS update_zakat_payments_updated_at ON zakat_payments;
+DROP TRIGGER IF EXISTS update_zakat_recipients_updated_at ON zakat_recipients;
+DROP INDEX IF EXISTS idx_zakat_payments_cooperative_status;
+DROP INDEX IF EXISTS idx_zakat_payments_investor;
+DROP TABLE IF EXISTS zakat_payments;
+DROP TABLE IF EXISTS zakat_recipients;
@@ -0,0 +1,47 @@
+-- Create zakat recipients table
+CREATE TABLE IF NOT EXISTS zakat_recipients (
+    cooperative_id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    account_name VARCHAR(140) NOT NULL,
+    account_number VARCHAR(34) NOT NULL,
+    bank_code VARCHAR(11) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create zakat payments table
+CREATE TABLE IF NOT EXISTS zakat_payments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    investor_id UUID NOT NULL,
+    cooperative_id UUID NOT NULL,
+    recipient_name VARCHAR(255) NOT NULL,
+    recipient_account VARCHAR(34) NOT NULL,
+    recipient_bank_code VARCHAR(11) NOT NULL,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    reference VARCHAR(35) NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    bank_reference VARCHAR(100),
+    paid_at TIMESTAMP WITH TIME ZONE,
+    confirmed_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_zakat_payment_status CHECK (status IN ('pending', 'paid', 'cancelled')),
+    CONSTRAINT chk_zakat_payment_paid CHECK ((status = 'paid') = (paid_at IS NOT NULL))
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_zakat_payments_investor ON zakat_payments(investor_id, paid_at);
+CREATE INDEX IF NOT EXISTS idx_zakat_payments_cooperative_status ON zakat_payments(cooperative_id, status);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_zakat_recipients_updated_at
+    BEFORE UPDATE ON zakat_recipients
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_zakat_payments_updated_at
+    BEFORE UPDATE ON zakat_payments
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS update_sharia_screening_rule_sets_updated_at ON sharia_screening_rule_sets;
+DROP INDEX IF EXISTS uq_sharia_rule_sets_active_board;
+DROP TABLE IF EXISTS sharia_screening_rule_sets;
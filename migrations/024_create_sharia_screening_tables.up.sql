@@ -0,0 +1,28 @@
+-- Create Sharia screening rule sets table
+CREATE TABLE IF NOT EXISTS sharia_screening_rule_sets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cooperative_id UUID NOT NULL,
+    supervisory_board VARCHAR(100) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    prohibited_sectors TEXT[] NOT NULL DEFAULT '{}',
+    prohibited_keywords TEXT[] NOT NULL DEFAULT '{}',
+    max_debt_to_asset_ratio DECIMAL(5,2),
+    max_interest_income_ratio DECIMAL(5,2),
+    guaranteed_return_phrases TEXT[] NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_sharia_debt_to_asset_ratio CHECK (max_debt_to_asset_ratio IS NULL OR max_debt_to_asset_ratio BETWEEN 0 AND 100),
+    CONSTRAINT chk_sharia_interest_income_ratio CHECK (max_interest_income_ratio IS NULL OR max_interest_income_ratio BETWEEN 0 AND 100)
+);
+
+-- Each supervisory board keeps one active rule set per cooperative
+CREATE UNIQUE INDEX IF NOT EXISTS uq_sharia_rule_sets_active_board
+    ON sharia_screening_rule_sets(cooperative_id, supervisory_board) WHERE is_active;
+
+-- Create trigger for updated_at
+CREATE TRIGGER update_sharia_screening_rule_sets_updated_at
+    BEFORE UPDATE ON sharia_screening_rule_sets
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
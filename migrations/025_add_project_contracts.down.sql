@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS update_murabahah_installments_updated_at ON murabahah_installments;
+DROP TRIGGER IF EXISTS update_project_contracts_updated_at ON project_contracts;
+DROP INDEX IF EXISTS idx_murabahah_installments_due;
+DROP INDEX IF EXISTS idx_project_contracts_cooperative;
+DROP INDEX IF EXISTS idx_projects_contract_type;
+DROP TABLE IF EXISTS murabahah_installments;
+DROP TABLE IF EXISTS project_contracts;
+ALTER TABLE projects
+DROP CONSTRAINT IF EXISTS chk_project_contract_type,
+DROP COLUMN IF EXISTS contract_type;
@@ -0,0 +1,72 @@
+-- Add the financing contract type to projects
+ALTER TABLE projects
+ADD COLUMN contract_type VARCHAR(20) NOT NULL DEFAULT 'mudarabah',
+ADD CONSTRAINT chk_project_contract_type CHECK (contract_type IN ('mudarabah', 'musyarakah', 'murabahah'));
+
+COMMENT ON COLUMN projects.contract_type IS 'Islamic financing contract the project runs under';
+
+-- Create project contracts table
+CREATE TABLE IF NOT EXISTS project_contracts (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    cooperative_id UUID NOT NULL,
+    contract_type VARCHAR(20) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    profit_sharing_ratio JSONB,
+    partner_capital NUMERIC(20,4) NOT NULL DEFAULT 0,
+    asset_description TEXT,
+    cost_price NUMERIC(20,4) NOT NULL DEFAULT 0,
+    markup NUMERIC(20,4) NOT NULL DEFAULT 0,
+    installment_count INTEGER NOT NULL DEFAULT 0,
+    installment_interval_months INTEGER NOT NULL DEFAULT 0,
+    first_installment_date TIMESTAMP WITH TIME ZONE,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_project_contracts_type CHECK (contract_type IN ('mudarabah', 'musyarakah', 'murabahah')),
+    CONSTRAINT chk_project_contracts_amounts CHECK (partner_capital >= 0 AND cost_price >= 0 AND markup >= 0),
+    CONSTRAINT chk_project_contracts_murabahah CHECK (
+        contract_type <> 'murabahah'
+        OR (cost_price > 0 AND installment_count > 0 AND installment_interval_months > 0 AND first_installment_date IS NOT NULL)
+    )
+);
+
+-- Create murabahah installments table
+CREATE TABLE IF NOT EXISTS murabahah_installments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES project_contracts(project_id) ON DELETE CASCADE,
+    cooperative_id UUID NOT NULL,
+    sequence INTEGER NOT NULL,
+    due_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    principal_amount NUMERIC(20,4) NOT NULL CHECK (principal_amount >= 0),
+    profit_amount NUMERIC(20,4) NOT NULL CHECK (profit_amount >= 0),
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    paid_at TIMESTAMP WITH TIME ZONE,
+    payment_reference VARCHAR(255),
+    profit_distribution_id UUID,
+    settled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_murabahah_installment_sequence UNIQUE (project_id, sequence),
+    CONSTRAINT chk_murabahah_installment_split CHECK (principal_amount + profit_amount = amount),
+    CONSTRAINT chk_murabahah_installment_status CHECK (status IN ('scheduled', 'paid', 'settled')),
+    CONSTRAINT chk_murabahah_installment_paid CHECK ((status = 'scheduled') = (paid_at IS NULL)),
+    CONSTRAINT chk_murabahah_installment_settled CHECK ((status = 'settled') = (profit_distribution_id IS NOT NULL))
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_projects_contract_type ON projects(contract_type);
+CREATE INDEX IF NOT EXISTS idx_project_contracts_cooperative ON project_contracts(cooperative_id, contract_type);
+CREATE INDEX IF NOT EXISTS idx_murabahah_installments_due ON murabahah_installments(status, due_date);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_project_contracts_updated_at
+    BEFORE UPDATE ON project_contracts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_murabahah_installments_updated_at
+    BEFORE UPDATE ON murabahah_installments
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
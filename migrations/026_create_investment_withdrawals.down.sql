@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS update_investment_withdrawals_updated_at ON investment_withdrawals;
+DROP INDEX IF EXISTS idx_investment_withdrawals_cooperative;
+DROP INDEX IF EXISTS idx_investment_withdrawals_investor;
+DROP INDEX IF EXISTS idx_investment_withdrawals_open;
+DROP TABLE IF EXISTS investment_withdrawals;
@@ -0,0 +1,52 @@
+-- Create investment withdrawals table
+CREATE TABLE IF NOT EXISTS investment_withdrawals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    investment_id UUID NOT NULL REFERENCES investments(id) ON DELETE CASCADE,
+    investor_id UUID NOT NULL,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    cooperative_id UUID NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    invested_amount NUMERIC(20,4) NOT NULL CHECK (invested_amount > 0),
+    withdrawn_amount NUMERIC(20,4) NOT NULL CHECK (withdrawn_amount > 0),
+    unrecovered_loss NUMERIC(20,4) NOT NULL DEFAULT 0 CHECK (unrecovered_loss >= 0),
+    redemption_value NUMERIC(20,4) NOT NULL CHECK (redemption_value >= 0),
+    penalty DECIMAL(5,4) NOT NULL DEFAULT 0 CHECK (penalty >= 0 AND penalty <= 1),
+    penalty_amount NUMERIC(20,4) NOT NULL DEFAULT 0 CHECK (penalty_amount >= 0),
+    net_amount NUMERIC(20,4) NOT NULL CHECK (net_amount >= 0),
+    notice_days INTEGER NOT NULL DEFAULT 0 CHECK (notice_days >= 0),
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    exit_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT NOT NULL,
+    bank_account VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'requested',
+    reviewed_by UUID,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    review_notes TEXT,
+    fund_refund_id UUID,
+    ownership_before DECIMAL(5,2) NOT NULL DEFAULT 0,
+    ownership_after DECIMAL(5,2) NOT NULL DEFAULT 0,
+    completed_by UUID,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_investment_withdrawal_status CHECK (status IN ('requested', 'approved', 'completing', 'rejected', 'cancelled', 'completed')),
+    CONSTRAINT chk_investment_withdrawal_amounts CHECK (
+        withdrawn_amount <= invested_amount
+        AND redemption_value + unrecovered_loss = withdrawn_amount
+        AND net_amount + penalty_amount = redemption_value
+    ),
+    CONSTRAINT chk_investment_withdrawal_notice CHECK (exit_date >= requested_at + notice_days * INTERVAL '1 day'),
+    CONSTRAINT chk_investment_withdrawal_completed CHECK ((status = 'completed') = (fund_refund_id IS NOT NULL))
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_investment_withdrawals_open ON investment_withdrawals(investment_id)
+    WHERE status IN ('requested', 'approved', 'completing');
+CREATE INDEX IF NOT EXISTS idx_investment_withdrawals_investor ON investment_withdrawals(investor_id, requested_at);
+CREATE INDEX IF NOT EXISTS idx_investment_withdrawals_cooperative ON investment_withdrawals(cooperative_id, status, exit_date);
+
+-- Create trigger for updated_at
+CREATE TRIGGER update_investment_withdrawals_updated_at
+    BEFORE UPDATE ON investment_withdrawals
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
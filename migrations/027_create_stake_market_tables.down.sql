@@ -0,0 +1,18 @@
+DROP TRIGGER IF EXISTS update_stake_trades_updated_at ON stake_trades;
+DROP TRIGGER IF EXISTS update_stake_bids_updated_at ON stake_bids;
+DROP TRIGGER IF EXISTS update_stake_listings_updated_at ON stake_listings;
+DROP INDEX IF EXISTS idx_stake_trades_cooperative;
+DROP INDEX IF EXISTS idx_stake_trades_pending;
+DROP INDEX IF EXISTS idx_stake_bids_buyer;
+DROP INDEX IF EXISTS idx_stake_bids_listing;
+DROP INDEX IF EXISTS idx_stake_listings_investment;
+DROP INDEX IF EXISTS idx_stake_listings_cooperative;
+DROP TABLE IF EXISTS stake_trades;
+DROP TABLE IF EXISTS stake_bids;
+DROP TABLE IF EXISTS stake_listings;
+ALTER TABLE journal_entries
+DROP CONSTRAINT IF EXISTS chk_journal_entry_type,
+ADD CONSTRAINT chk_journal_entry_type CHECK (entry_type IN ('investment', 'disbursement', 'refund', 'profit_distribution', 'fee_collection', 'adjustment'));
+ALTER TABLE investments
+DROP CONSTRAINT IF EXISTS chk_investment_status,
+ADD CONSTRAINT chk_investment_status CHECK (status IN ('pending', 'confirmed', 'refunded', 'cancelled'));
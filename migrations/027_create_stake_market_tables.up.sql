@@ -0,0 +1,91 @@
+-- Investments sold in full to another member are transferred
+ALTER TABLE investments
+DROP CONSTRAINT IF EXISTS chk_investment_status,
+ADD CONSTRAINT chk_investment_status CHECK (status IN ('pending', 'confirmed', 'refunded', 'cancelled', 'transferred'));
+
+-- Record stake transfers between members in the ledger
+ALTER TABLE journal_entries
+DROP CONSTRAINT IF EXISTS chk_journal_entry_type,
+ADD CONSTRAINT chk_journal_entry_type CHECK (entry_type IN ('investment', 'disbursement', 'refund', 'profit_distribution', 'fee_collection', 'stake_transfer', 'adjustment'));
+
+-- Create stake listings table
+CREATE TABLE IF NOT EXISTS stake_listings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    investment_id UUID NOT NULL REFERENCES investments(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    cooperative_id UUID NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    ask_price NUMERIC(20,4) NOT NULL CHECK (ask_price > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_stake_listing_status CHECK (status IN ('open', 'pending_approval', 'sold', 'cancelled'))
+);
+
+-- Create stake bids table
+CREATE TABLE IF NOT EXISTS stake_bids (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    listing_id UUID NOT NULL REFERENCES stake_listings(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL,
+    buyer_id UUID NOT NULL,
+    price NUMERIC(20,4) NOT NULL CHECK (price > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_stake_bid_status CHECK (status IN ('open', 'accepted', 'rejected', 'withdrawn'))
+);
+
+-- Create stake trades table
+CREATE TABLE IF NOT EXISTS stake_trades (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    listing_id UUID NOT NULL REFERENCES stake_listings(id) ON DELETE CASCADE,
+    bid_id UUID REFERENCES stake_bids(id),
+    project_id UUID NOT NULL,
+    cooperative_id UUID NOT NULL,
+    seller_id UUID NOT NULL,
+    buyer_id UUID NOT NULL,
+    seller_investment_id UUID NOT NULL,
+    buyer_investment_id UUID,
+    currency VARCHAR(3) NOT NULL,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    price NUMERIC(20,4) NOT NULL CHECK (price > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending_approval',
+    payment_reference VARCHAR(255),
+    reviewed_by UUID,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    review_notes TEXT,
+    seller_ownership_after DECIMAL(5,2) NOT NULL DEFAULT 0,
+    buyer_ownership_after DECIMAL(5,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_stake_trade_status CHECK (status IN ('pending_approval', 'completing', 'completed', 'rejected')),
+    CONSTRAINT chk_stake_trade_parties CHECK (seller_id <> buyer_id),
+    CONSTRAINT chk_stake_trade_completed CHECK ((status = 'completed') = (buyer_investment_id IS NOT NULL))
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_stake_listings_cooperative ON stake_listings(cooperative_id, status);
+CREATE INDEX IF NOT EXISTS idx_stake_listings_investment ON stake_listings(investment_id, status);
+CREATE INDEX IF NOT EXISTS idx_stake_bids_listing ON stake_bids(listing_id, status);
+CREATE INDEX IF NOT EXISTS idx_stake_bids_buyer ON stake_bids(buyer_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stake_trades_pending ON stake_trades(listing_id)
+    WHERE status IN ('pending_approval', 'completing');
+CREATE INDEX IF NOT EXISTS idx_stake_trades_cooperative ON stake_trades(cooperative_id, status);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_stake_listings_updated_at
+    BEFORE UPDATE ON stake_listings
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_stake_bids_updated_at
+    BEFORE UPDATE ON stake_bids
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_stake_trades_updated_at
+    BEFORE UPDATE ON stake_trades
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS update_investor_refunds_updated_at ON investor_refunds;
+DROP TRIGGER IF EXISTS update_fund_refunds_updated_at ON fund_refunds;
+DROP INDEX IF EXISTS idx_notifications_user_unread;
+DROP INDEX IF EXISTS idx_notifications_user_created;
+DROP INDEX IF EXISTS idx_project_funding_closures_awaiting_fee;
+DROP INDEX IF EXISTS idx_projects_active_funding_deadline;
+DROP INDEX IF EXISTS idx_investor_refunds_investor_id;
+DROP INDEX IF EXISTS idx_fund_refunds_cooperative_status;
+DROP INDEX IF EXISTS idx_fund_refunds_project_id;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS project_funding_closures;
+DROP TABLE IF EXISTS investor_refunds;
+DROP TABLE IF EXISTS fund_refunds;
+ALTER TABLE projects DROP COLUMN IF EXISTS funded_at;
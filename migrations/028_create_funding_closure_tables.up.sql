@@ -0,0 +1,104 @@
+-- Record when a project reached its funding
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS funded_at TIMESTAMP WITH TIME ZONE;
+
+-- Create fund refunds table (FR-049)
+CREATE TABLE IF NOT EXISTS fund_refunds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    cooperative_id UUID NOT NULL,
+    refund_type VARCHAR(30) NOT NULL,
+    refund_reason TEXT NOT NULL,
+    total_refund_amount NUMERIC(20,4) NOT NULL CHECK (total_refund_amount >= 0),
+    currency VARCHAR(3) NOT NULL,
+    refund_percentage DECIMAL(5,2) NOT NULL DEFAULT 100,
+    processing_fee NUMERIC(20,4) NOT NULL DEFAULT 0 CHECK (processing_fee >= 0),
+    net_refund_amount NUMERIC(20,4) NOT NULL CHECK (net_refund_amount >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    initiated_by UUID NOT NULL,
+    initiated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    processed_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_fund_refund_type CHECK (refund_type IN ('minimum_funding_failed', 'project_cancelled', 'investor_request')),
+    CONSTRAINT chk_fund_refund_status CHECK (status IN ('pending', 'processing', 'completed', 'failed', 'cancelled'))
+);
+
+-- Create investor refunds table
+CREATE TABLE IF NOT EXISTS investor_refunds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    fund_refund_id UUID NOT NULL REFERENCES fund_refunds(id) ON DELETE CASCADE,
+    investment_id UUID NOT NULL REFERENCES investments(id) ON DELETE CASCADE,
+    investor_id UUID NOT NULL,
+    original_investment NUMERIC(20,4) NOT NULL CHECK (original_investment > 0),
+    refund_amount NUMERIC(20,4) NOT NULL CHECK (refund_amount >= 0),
+    processing_fee NUMERIC(20,4) NOT NULL DEFAULT 0 CHECK (processing_fee >= 0),
+    net_refund_amount NUMERIC(20,4) NOT NULL CHECK (net_refund_amount >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    bank_account VARCHAR(50),
+    transaction_reference VARCHAR(100),
+    processed_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_investor_refund_status CHECK (status IN ('pending', 'processing', 'completed', 'failed')),
+    CONSTRAINT unique_investor_refund_investment UNIQUE (fund_refund_id, investment_id)
+);
+
+-- Create project funding closures table; one per project, recorded when its funding deadline passes
+CREATE TABLE IF NOT EXISTS project_funding_closures (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL UNIQUE REFERENCES projects(id) ON DELETE CASCADE,
+    cooperative_id UUID NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    funding_deadline TIMESTAMP WITH TIME ZONE NOT NULL,
+    current_funding NUMERIC(20,4) NOT NULL CHECK (current_funding >= 0),
+    minimum_funding NUMERIC(20,4) NOT NULL CHECK (minimum_funding >= 0),
+    investor_count INTEGER NOT NULL DEFAULT 0 CHECK (investor_count >= 0),
+    fee_calculation_id UUID,
+    fee_percentage DECIMAL(5,2),
+    fee_amount NUMERIC(20,4) CHECK (fee_amount >= 0),
+    fund_refund_id UUID REFERENCES fund_refunds(id),
+    closed_by UUID NOT NULL,
+    closed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_funding_closure_outcome CHECK (outcome IN ('funded', 'minimum_not_met')),
+    CONSTRAINT chk_funding_closure_fee CHECK (outcome = 'funded' OR fee_amount IS NULL)
+);
+
+-- Create notifications table; notifications live on their recipient's shard
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    entity_type VARCHAR(50),
+    entity_id UUID,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_fund_refunds_project_id ON fund_refunds(project_id);
+CREATE INDEX IF NOT EXISTS idx_fund_refunds_cooperative_status ON fund_refunds(cooperative_id, status);
+CREATE INDEX IF NOT EXISTS idx_investor_refunds_investor_id ON investor_refunds(investor_id);
+CREATE INDEX IF NOT EXISTS idx_projects_active_funding_deadline ON projects(funding_deadline) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_project_funding_closures_awaiting_fee ON project_funding_closures(closed_at)
+    WHERE outcome = 'funded' AND fee_calculation_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_fund_refunds_updated_at
+    BEFORE UPDATE ON fund_refunds
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_investor_refunds_updated_at
+    BEFORE UPDATE ON investor_refunds
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
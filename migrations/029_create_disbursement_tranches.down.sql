@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS update_fund_disbursements_updated_at ON fund_disbursements;
+DROP TRIGGER IF EXISTS update_disbursement_tranches_updated_at ON disbursement_tranches;
+DROP INDEX IF EXISTS idx_fund_disbursements_tranche_status;
+DROP INDEX IF EXISTS idx_fund_disbursements_project_id;
+DROP TABLE IF EXISTS fund_disbursements;
+DROP TABLE IF EXISTS disbursement_tranches;
@@ -0,0 +1,73 @@
+-- Create disbursement tranches table; one per milestone of a funded project (FR-046)
+CREATE TABLE IF NOT EXISTS disbursement_tranches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    cooperative_id UUID NOT NULL,
+    milestone_id UUID NOT NULL,
+    milestone_title VARCHAR(255) NOT NULL,
+    milestone_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    sequence INTEGER NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    budget NUMERIC(20,4) NOT NULL CHECK (budget > 0),
+    released_amount NUMERIC(20,4) NOT NULL DEFAULT 0 CHECK (released_amount >= 0),
+    unused_amount NUMERIC(20,4) NOT NULL DEFAULT 0 CHECK (unused_amount >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'planned',
+    evidence_documents TEXT[] NOT NULL DEFAULT '{}',
+    completion_notes TEXT,
+    completed_by UUID,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    verified_by UUID,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    verification_notes TEXT,
+    closed_by UUID,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    close_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_disbursement_tranche_status CHECK (status IN ('planned', 'open', 'released', 'closed')),
+    CONSTRAINT chk_disbursement_tranche_milestone_status CHECK (milestone_status IN ('pending', 'completed')),
+    CONSTRAINT chk_disbursement_tranche_budget CHECK (released_amount + unused_amount <= budget),
+    CONSTRAINT unique_disbursement_tranche_milestone UNIQUE (project_id, milestone_id),
+    CONSTRAINT unique_disbursement_tranche_sequence UNIQUE (project_id, sequence)
+);
+
+-- Create fund disbursements table; each draws on a milestone's tranche
+CREATE TABLE IF NOT EXISTS fund_disbursements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    business_id UUID NOT NULL,
+    cooperative_id UUID NOT NULL,
+    milestone_id UUID NOT NULL,
+    tranche_id UUID NOT NULL REFERENCES disbursement_tranches(id),
+    disbursement_amount NUMERIC(20,4) NOT NULL CHECK (disbursement_amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    disbursement_type VARCHAR(20) NOT NULL,
+    disbursement_reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    approved_by UUID,
+    approved_at TIMESTAMP WITH TIME ZONE,
+    disbursed_at TIMESTAMP WITH TIME ZONE,
+    rejection_reason TEXT,
+    bank_account VARCHAR(50) NOT NULL,
+    transaction_reference VARCHAR(100),
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_fund_disbursement_type CHECK (disbursement_type IN ('milestone', 'partial', 'final')),
+    CONSTRAINT chk_fund_disbursement_status CHECK (status IN ('pending', 'approved', 'disbursed', 'rejected', 'cancelled'))
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_fund_disbursements_project_id ON fund_disbursements(project_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_fund_disbursements_tranche_status ON fund_disbursements(tranche_id, status);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_disbursement_tranches_updated_at
+    BEFORE UPDATE ON disbursement_tranches
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_fund_disbursements_updated_at
+    BEFORE UPDATE ON fund_disbursements
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
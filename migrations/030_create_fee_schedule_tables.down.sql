@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS update_project_fee_calculations_updated_at ON project_fee_calculations;
+DROP TRIGGER IF EXISTS update_fee_waivers_updated_at ON fee_waivers;
+DROP TRIGGER IF EXISTS update_comfunds_fees_updated_at ON comfunds_fees;
+DROP INDEX IF EXISTS idx_fee_credit_notes_cooperative_id;
+DROP INDEX IF EXISTS idx_fee_invoices_cooperative_id;
+DROP INDEX IF EXISTS idx_project_fee_calculations_project_id;
+DROP INDEX IF EXISTS idx_fee_waivers_fee_type;
+DROP INDEX IF EXISTS idx_comfunds_fees_fee_type;
+DROP TABLE IF EXISTS fee_credit_notes;
+DROP TABLE IF EXISTS fee_invoices;
+DROP TABLE IF EXISTS fee_document_sequences;
+DROP TABLE IF EXISTS project_fee_calculations;
+DROP TABLE IF EXISTS fee_waivers;
+DROP TABLE IF EXISTS comfunds_fees;
@@ -0,0 +1,152 @@
+-- Create ComFunds fee schedules table; replicated to every shard
+CREATE TABLE IF NOT EXISTS comfunds_fees (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    fee_type VARCHAR(30) NOT NULL,
+    fee_percentage DECIMAL(7,4) NOT NULL DEFAULT 0,
+    tier_mode VARCHAR(20) NOT NULL DEFAULT 'flat',
+    tiers JSONB NOT NULL DEFAULT '[]',
+    priority INTEGER NOT NULL DEFAULT 0,
+    currency VARCHAR(3),
+    is_enabled BOOLEAN NOT NULL DEFAULT true,
+    minimum_amount NUMERIC(20,4) NOT NULL DEFAULT 0,
+    maximum_amount NUMERIC(20,4) NOT NULL DEFAULT 0,
+    applicable_to VARCHAR(30) NOT NULL,
+    project_id UUID,
+    cooperative_id UUID,
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    effective_to TIMESTAMP WITH TIME ZONE,
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_comfunds_fee_type CHECK (fee_type IN ('platform_fee', 'success_fee', 'transaction_fee')),
+    CONSTRAINT chk_comfunds_fee_tier_mode CHECK (tier_mode IN ('flat', 'banded', 'tiered')),
+    CONSTRAINT chk_comfunds_fee_percentage CHECK (fee_percentage >= 0 AND fee_percentage <= 100),
+    CONSTRAINT chk_comfunds_fee_applicable_to CHECK (applicable_to IN ('all_projects', 'successful_funding', 'specific_projects'))
+);
+
+-- Create fee waivers table; promotional discounts replicated to every shard
+CREATE TABLE IF NOT EXISTS fee_waivers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    fee_type VARCHAR(30) NOT NULL,
+    cooperative_id UUID,
+    project_id UUID,
+    waiver_percentage DECIMAL(7,4) NOT NULL,
+    valid_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID NOT NULL,
+    revoked_by UUID,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_fee_waiver_percentage CHECK (waiver_percentage > 0 AND waiver_percentage <= 100),
+    CONSTRAINT chk_fee_waiver_validity CHECK (expires_at > valid_from)
+);
+
+-- Create project fee calculations table; stored on the cooperative's shard
+CREATE TABLE IF NOT EXISTS project_fee_calculations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL,
+    cooperative_id UUID NOT NULL,
+    business_id UUID NOT NULL,
+    total_funding_amount NUMERIC(20,4) NOT NULL CHECK (total_funding_amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    fee_schedule_id UUID,
+    fee_percentage DECIMAL(7,4) NOT NULL,
+    gross_fee_amount NUMERIC(20,4) NOT NULL DEFAULT 0,
+    waiver_id UUID,
+    waived_amount NUMERIC(20,4) NOT NULL DEFAULT 0,
+    fee_amount NUMERIC(20,4) NOT NULL,
+    net_amount_after_fee NUMERIC(20,4) NOT NULL,
+    fee_status VARCHAR(20) NOT NULL DEFAULT 'calculated',
+    calculated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    collected_at TIMESTAMP WITH TIME ZONE,
+    collected_by UUID,
+    transaction_reference VARCHAR(100),
+    invoice_id UUID,
+    credit_note_id UUID,
+    notes TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_project_fee_status CHECK (fee_status IN ('pending', 'calculated', 'collected', 'waived')),
+    CONSTRAINT chk_project_fee_amounts CHECK (fee_amount >= 0 AND waived_amount <= gross_fee_amount)
+);
+
+-- Create fee document sequences table; numbers invoices and credit notes per cooperative and year
+CREATE TABLE IF NOT EXISTS fee_document_sequences (
+    cooperative_id UUID NOT NULL,
+    document_type VARCHAR(20) NOT NULL,
+    year INTEGER NOT NULL,
+    last_number INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (cooperative_id, document_type, year),
+    CONSTRAINT chk_fee_document_type CHECK (document_type IN ('invoice', 'credit_note'))
+);
+
+-- Create fee invoices table
+CREATE TABLE IF NOT EXISTS fee_invoices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_number VARCHAR(30) NOT NULL,
+    cooperative_id UUID NOT NULL,
+    project_id UUID NOT NULL,
+    business_id UUID NOT NULL,
+    fee_calculation_id UUID NOT NULL REFERENCES project_fee_calculations(id),
+    currency VARCHAR(3) NOT NULL,
+    gross_amount NUMERIC(20,4) NOT NULL,
+    discount_amount NUMERIC(20,4) NOT NULL DEFAULT 0,
+    net_amount NUMERIC(20,4) NOT NULL CHECK (net_amount > 0),
+    collection_method VARCHAR(20) NOT NULL,
+    transaction_reference VARCHAR(100),
+    issued_by UUID NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_fee_invoice_number UNIQUE (cooperative_id, invoice_number),
+    CONSTRAINT unique_fee_invoice_calculation UNIQUE (fee_calculation_id)
+);
+
+-- Create fee credit notes table
+CREATE TABLE IF NOT EXISTS fee_credit_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    credit_note_number VARCHAR(30) NOT NULL,
+    cooperative_id UUID NOT NULL,
+    project_id UUID NOT NULL,
+    business_id UUID NOT NULL,
+    fee_calculation_id UUID NOT NULL REFERENCES project_fee_calculations(id),
+    currency VARCHAR(3) NOT NULL,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount >= 0),
+    reason TEXT NOT NULL,
+    issued_by UUID NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_fee_credit_note_number UNIQUE (cooperative_id, credit_note_number),
+    CONSTRAINT unique_fee_credit_note_calculation UNIQUE (fee_calculation_id)
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_comfunds_fees_fee_type ON comfunds_fees(fee_type, is_active);
+CREATE INDEX IF NOT EXISTS idx_fee_waivers_fee_type ON fee_waivers(fee_type, expires_at);
+CREATE INDEX IF NOT EXISTS idx_project_fee_calculations_project_id ON project_fee_calculations(project_id);
+CREATE INDEX IF NOT EXISTS idx_fee_invoices_cooperative_id ON fee_invoices(cooperative_id, issued_at);
+CREATE INDEX IF NOT EXISTS idx_fee_credit_notes_cooperative_id ON fee_credit_notes(cooperative_id, issued_at);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_comfunds_fees_updated_at
+    BEFORE UPDATE ON comfunds_fees
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_fee_waivers_updated_at
+    BEFORE UPDATE ON fee_waivers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_project_fee_calculations_updated_at
+    BEFORE UPDATE ON project_fee_calculations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_investor_statements_investor_id;
+DROP TABLE IF EXISTS investor_statements;
@@ -0,0 +1,32 @@
+-- Create investor statements table; archived statements with their PDF and CSV renderings
+CREATE TABLE IF NOT EXISTS investor_statements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    investor_id UUID NOT NULL,
+    cooperative_id UUID NOT NULL,
+    cooperative_name VARCHAR(255) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    period_type VARCHAR(20) NOT NULL,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    opening_balance NUMERIC(20,4) NOT NULL DEFAULT 0,
+    investments NUMERIC(20,4) NOT NULL DEFAULT 0,
+    distributions NUMERIC(20,4) NOT NULL DEFAULT 0,
+    tax_withheld NUMERIC(20,4) NOT NULL DEFAULT 0,
+    fees NUMERIC(20,4) NOT NULL DEFAULT 0,
+    refunds NUMERIC(20,4) NOT NULL DEFAULT 0,
+    transfers NUMERIC(20,4) NOT NULL DEFAULT 0,
+    adjustments NUMERIC(20,4) NOT NULL DEFAULT 0,
+    closing_balance NUMERIC(20,4) NOT NULL DEFAULT 0,
+    lines JSONB NOT NULL DEFAULT '[]',
+    pdf_content BYTEA NOT NULL,
+    csv_content BYTEA NOT NULL,
+    generated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_investor_statement_period_type CHECK (period_type IN ('monthly', 'quarterly', 'yearly')),
+    CONSTRAINT chk_investor_statement_period CHECK (period_end > period_start),
+    CONSTRAINT unique_investor_statement_period UNIQUE (investor_id, cooperative_id, currency, period_type, period_start)
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_investor_statements_investor_id ON investor_statements(investor_id, period_end);
@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS update_cooperative_tax_jurisdictions_updated_at ON cooperative_tax_jurisdictions;
+DROP TRIGGER IF EXISTS update_investor_tax_profiles_updated_at ON investor_tax_profiles;
+DROP TRIGGER IF EXISTS update_tax_withholding_rates_updated_at ON tax_withholding_rates;
+DROP INDEX IF EXISTS idx_tax_withholdings_distribution_id;
+DROP INDEX IF EXISTS idx_tax_withholdings_cooperative_id;
+DROP INDEX IF EXISTS idx_tax_withholding_rates_jurisdiction;
+DROP TABLE IF EXISTS tax_withholdings;
+DROP TABLE IF EXISTS cooperative_tax_jurisdictions;
+DROP TABLE IF EXISTS investor_tax_profiles;
+DROP TABLE IF EXISTS tax_withholding_rates;
@@ -0,0 +1,84 @@
+-- Create tax withholding rates table; rates per jurisdiction and investor type, replicated to every shard
+CREATE TABLE IF NOT EXISTS tax_withholding_rates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    jurisdiction VARCHAR(2) NOT NULL,
+    investor_type VARCHAR(20) NOT NULL,
+    has_tax_id BOOLEAN NOT NULL,
+    rate DECIMAL(7,4) NOT NULL,
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    effective_to TIMESTAMP WITH TIME ZONE,
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_tax_withholding_rate_jurisdiction CHECK (jurisdiction IN ('ID', 'MY')),
+    CONSTRAINT chk_tax_withholding_rate_investor_type CHECK (investor_type IN ('individual', 'corporate')),
+    CONSTRAINT chk_tax_withholding_rate CHECK (rate >= 0 AND rate <= 100),
+    CONSTRAINT chk_tax_withholding_rate_effective CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+-- Create investor tax profiles table; stored on the investor's shard
+CREATE TABLE IF NOT EXISTS investor_tax_profiles (
+    investor_id UUID PRIMARY KEY,
+    investor_type VARCHAR(20) NOT NULL,
+    tax_id VARCHAR(50),
+    tax_residence VARCHAR(2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_investor_tax_profile_investor_type CHECK (investor_type IN ('individual', 'corporate'))
+);
+
+-- Create cooperative tax jurisdictions table; stored on the cooperative's shard
+CREATE TABLE IF NOT EXISTS cooperative_tax_jurisdictions (
+    cooperative_id UUID PRIMARY KEY,
+    jurisdiction VARCHAR(2) NOT NULL,
+    updated_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_cooperative_tax_jurisdiction CHECK (jurisdiction IN ('ID', 'MY'))
+);
+
+-- Create tax withholdings table; tax withheld from each investor's share, stored on the cooperative's shard
+CREATE TABLE IF NOT EXISTS tax_withholdings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    distribution_id UUID NOT NULL,
+    cooperative_id UUID NOT NULL,
+    project_id UUID NOT NULL,
+    investment_id UUID NOT NULL,
+    investor_id UUID NOT NULL,
+    jurisdiction VARCHAR(2) NOT NULL,
+    investor_type VARCHAR(20) NOT NULL,
+    has_tax_id BOOLEAN NOT NULL,
+    rate_id UUID NOT NULL,
+    rate DECIMAL(7,4) NOT NULL,
+    taxable_amount NUMERIC(20,4) NOT NULL,
+    tax_amount NUMERIC(20,4) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    withheld_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_tax_withholding_amounts CHECK (taxable_amount >= 0 AND tax_amount >= 0 AND tax_amount <= taxable_amount),
+    UNIQUE(distribution_id, investment_id)
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_tax_withholding_rates_jurisdiction ON tax_withholding_rates(jurisdiction, investor_type, has_tax_id, effective_from);
+CREATE INDEX IF NOT EXISTS idx_tax_withholdings_cooperative_id ON tax_withholdings(cooperative_id, withheld_at);
+CREATE INDEX IF NOT EXISTS idx_tax_withholdings_distribution_id ON tax_withholdings(distribution_id);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_tax_withholding_rates_updated_at
+    BEFORE UPDATE ON tax_withholding_rates
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_investor_tax_profiles_updated_at
+    BEFORE UPDATE ON investor_tax_profiles
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_cooperative_tax_jurisdictions_updated_at
+    BEFORE UPDATE ON cooperative_tax_jurisdictions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_investor_loss_shares_investment_id;
+DROP INDEX IF EXISTS idx_investment_withdrawals_investment_id;
+DROP INDEX IF EXISTS idx_investor_refunds_investment_id;
+
+ALTER TABLE projects
+DROP CONSTRAINT IF EXISTS chk_project_expected_return_period,
+DROP CONSTRAINT IF EXISTS chk_project_expected_return,
+DROP COLUMN IF EXISTS expected_return_period,
+DROP COLUMN IF EXISTS expected_return;
@@ -0,0 +1,13 @@
+-- Add the return projects expect to pay investors; performance is compared against it
+ALTER TABLE projects
+ADD COLUMN IF NOT EXISTS expected_return DECIMAL(5,2),
+ADD COLUMN IF NOT EXISTS expected_return_period INTEGER,
+ADD CONSTRAINT chk_project_expected_return CHECK (expected_return IS NULL OR (expected_return >= 0 AND expected_return <= 100)),
+ADD CONSTRAINT chk_project_expected_return_period CHECK (expected_return_period IS NULL OR expected_return_period > 0);
+
+COMMENT ON COLUMN projects.expected_return IS 'Expected return as a percentage of the investment over expected_return_period months';
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_investor_refunds_investment_id ON investor_refunds(investment_id);
+CREATE INDEX IF NOT EXISTS idx_investment_withdrawals_investment_id ON investment_withdrawals(investment_id, status);
+CREATE INDEX IF NOT EXISTS idx_investor_loss_shares_investment_id ON investor_loss_shares(investment_id);
@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS update_aml_cases_updated_at ON aml_cases;
+DROP TRIGGER IF EXISTS update_aml_rules_updated_at ON aml_rules;
+DROP INDEX IF EXISTS idx_aml_case_events_case_id;
+DROP INDEX IF EXISTS idx_aml_alerts_case_id;
+DROP INDEX IF EXISTS idx_aml_cases_cooperative_status;
+DROP INDEX IF EXISTS idx_aml_cases_subject_unclosed;
+DROP INDEX IF EXISTS idx_aml_transactions_subject;
+DROP TABLE IF EXISTS aml_case_events;
+DROP TABLE IF EXISTS aml_alerts;
+DROP TABLE IF EXISTS aml_cases;
+DROP TABLE IF EXISTS aml_case_sequences;
+DROP TABLE IF EXISTS aml_transactions;
+DROP TABLE IF EXISTS aml_rules;
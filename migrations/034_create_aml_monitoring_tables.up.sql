@@ -0,0 +1,122 @@
+-- Create AML rules table; platform-wide and cooperative rules, replicated to every shard
+CREATE TABLE IF NOT EXISTS aml_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cooperative_id UUID,
+    name VARCHAR(255) NOT NULL,
+    rule_type VARCHAR(30) NOT NULL,
+    threshold NUMERIC(20,4) NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    min_count INTEGER NOT NULL DEFAULT 0,
+    window_hours INTEGER NOT NULL DEFAULT 0,
+    margin_percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+    rounding_unit NUMERIC(20,4) NOT NULL DEFAULT 0,
+    severity VARCHAR(10) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_aml_rule_type CHECK (rule_type IN ('amount_threshold', 'velocity', 'structuring', 'round_amount', 'invest_then_withdraw', 'new_bank_account')),
+    CONSTRAINT chk_aml_rule_severity CHECK (severity IN ('low', 'medium', 'high')),
+    CONSTRAINT chk_aml_rule_amounts CHECK (threshold >= 0 AND rounding_unit >= 0 AND min_count >= 0 AND window_hours >= 0),
+    CONSTRAINT chk_aml_rule_margin CHECK (margin_percent >= 0 AND margin_percent <= 100)
+);
+
+-- Create AML transactions table; every monitored transfer and investment, stored on the cooperative's shard
+CREATE TABLE IF NOT EXISTS aml_transactions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cooperative_id UUID NOT NULL,
+    project_id UUID NOT NULL,
+    subject_id UUID NOT NULL,
+    source_type VARCHAR(20) NOT NULL,
+    source_id UUID NOT NULL,
+    reference VARCHAR(100),
+    transaction_type VARCHAR(30) NOT NULL,
+    direction VARCHAR(10) NOT NULL,
+    amount NUMERIC(20,4) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    bank_account VARCHAR(100),
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_aml_transaction_source_type CHECK (source_type IN ('fund_transfer', 'investment')),
+    CONSTRAINT chk_aml_transaction_direction CHECK (direction IN ('inbound', 'outbound')),
+    UNIQUE(source_type, source_id)
+);
+
+-- Create AML case number sequences table; one sequence per cooperative and year
+CREATE TABLE IF NOT EXISTS aml_case_sequences (
+    cooperative_id UUID NOT NULL,
+    year INTEGER NOT NULL,
+    last_number INTEGER NOT NULL,
+
+    PRIMARY KEY (cooperative_id, year)
+);
+
+-- Create AML cases table; a subject has at most one case that is not closed
+CREATE TABLE IF NOT EXISTS aml_cases (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    case_number VARCHAR(50) NOT NULL,
+    cooperative_id UUID NOT NULL,
+    subject_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    severity VARCHAR(10) NOT NULL,
+    assigned_to UUID,
+    closure_reason VARCHAR(30),
+    closure_notes TEXT,
+    opened_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    escalated_at TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    closed_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_aml_case_status CHECK (status IN ('open', 'investigating', 'escalated', 'closed')),
+    CONSTRAINT chk_aml_case_severity CHECK (severity IN ('low', 'medium', 'high')),
+    CONSTRAINT chk_aml_case_closure_reason CHECK (closure_reason IS NULL OR closure_reason IN ('false_positive', 'no_further_action', 'reported')),
+    CONSTRAINT chk_aml_case_closed CHECK ((status = 'closed') = (closure_reason IS NOT NULL)),
+    UNIQUE(cooperative_id, case_number)
+);
+
+-- Create AML alerts table; each rule a transaction triggered, grouped into the subject's case
+CREATE TABLE IF NOT EXISTS aml_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    case_id UUID NOT NULL REFERENCES aml_cases(id) ON DELETE CASCADE,
+    transaction_id UUID NOT NULL REFERENCES aml_transactions(id),
+    rule_id UUID NOT NULL,
+    rule_name VARCHAR(255) NOT NULL,
+    rule_type VARCHAR(30) NOT NULL,
+    severity VARCHAR(10) NOT NULL,
+    description TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(transaction_id, rule_id)
+);
+
+-- Create AML case events table; the case's workflow history
+CREATE TABLE IF NOT EXISTS aml_case_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    case_id UUID NOT NULL REFERENCES aml_cases(id) ON DELETE CASCADE,
+    action VARCHAR(30) NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    notes TEXT,
+    actor_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_aml_transactions_subject ON aml_transactions(cooperative_id, subject_id, occurred_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_aml_cases_subject_unclosed ON aml_cases(cooperative_id, subject_id) WHERE status <> 'closed';
+CREATE INDEX IF NOT EXISTS idx_aml_cases_cooperative_status ON aml_cases(cooperative_id, status, opened_at);
+CREATE INDEX IF NOT EXISTS idx_aml_alerts_case_id ON aml_alerts(case_id);
+CREATE INDEX IF NOT EXISTS idx_aml_case_events_case_id ON aml_case_events(case_id, created_at);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_aml_rules_updated_at
+    BEFORE UPDATE ON aml_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_aml_cases_updated_at
+    BEFORE UPDATE ON aml_cases
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS update_sanctions_screenings_updated_at ON sanctions_screenings;
+DROP INDEX IF EXISTS idx_sanctions_screenings_status;
+DROP INDEX IF EXISTS idx_sanctions_screenings_subject;
+DROP TABLE IF EXISTS sanctions_screenings;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS date_of_birth;
@@ -0,0 +1,36 @@
+-- Add date of birth to users; screened against sanctions lists alongside the name
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS date_of_birth DATE;
+
+-- Create sanctions screenings table; every screening of a member, business or payout beneficiary
+-- against the loaded watchlists, replicated to every shard
+CREATE TABLE IF NOT EXISTS sanctions_screenings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subject_type VARCHAR(20) NOT NULL,
+    subject_reference VARCHAR(255) NOT NULL,
+    subject_id UUID,
+    subject_name VARCHAR(255) NOT NULL,
+    subject_date_of_birth DATE,
+    purpose VARCHAR(20) NOT NULL,
+    cooperative_id UUID,
+    status VARCHAR(20) NOT NULL,
+    matches JSONB NOT NULL DEFAULT '[]',
+    reviewed_by UUID,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    review_notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_sanctions_screening_subject_type CHECK (subject_type IN ('individual', 'business', 'beneficiary')),
+    CONSTRAINT chk_sanctions_screening_purpose CHECK (purpose IN ('registration', 'membership', 'business', 'payout')),
+    CONSTRAINT chk_sanctions_screening_status CHECK (status IN ('clear', 'pending_review', 'cleared', 'confirmed'))
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_sanctions_screenings_subject ON sanctions_screenings(subject_type, subject_reference, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_sanctions_screenings_status ON sanctions_screenings(status, created_at DESC);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_sanctions_screenings_updated_at
+    BEFORE UPDATE ON sanctions_screenings
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS update_fund_transfers_updated_at ON fund_transfers;
+DROP INDEX IF EXISTS idx_fund_transfer_attempts_transfer_id;
+DROP INDEX IF EXISTS idx_fund_transfers_dead_lettered;
+DROP INDEX IF EXISTS idx_fund_transfers_due;
+DROP INDEX IF EXISTS idx_fund_transfers_project_id;
+DROP TABLE IF EXISTS fund_transfer_attempts;
+DROP TABLE IF EXISTS fund_transfers;
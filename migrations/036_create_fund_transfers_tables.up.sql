@@ -0,0 +1,74 @@
+-- Create fund transfers table; transfers live on their project's shard (FR-021)
+CREATE TABLE IF NOT EXISTS fund_transfers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transfer_number VARCHAR(50) NOT NULL UNIQUE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    investment_id UUID,
+    cooperative_id UUID NOT NULL,
+    from_account_id UUID NOT NULL,
+    to_account_id UUID NOT NULL,
+    from_user_id UUID,
+    to_user_id UUID,
+    transfer_type VARCHAR(30) NOT NULL,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    exchange_rate NUMERIC(24,12) NOT NULL DEFAULT 1,
+    settlement_currency VARCHAR(3) NOT NULL,
+    settlement_amount NUMERIC(20,4) NOT NULL,
+    fee NUMERIC(20,4) NOT NULL DEFAULT 0,
+    net_amount NUMERIC(20,4) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    payment_method VARCHAR(30) NOT NULL,
+    payment_reference VARCHAR(100),
+    bank_account VARCHAR(100),
+    bank_transaction_id VARCHAR(100),
+    description TEXT NOT NULL,
+    notes TEXT,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    scheduled_at TIMESTAMP WITH TIME ZONE,
+    processed_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    failed_at TIMESTAMP WITH TIME ZONE,
+    failure_reason TEXT,
+    retry_count INTEGER NOT NULL DEFAULT 0 CHECK (retry_count >= 0),
+    max_retries INTEGER NOT NULL DEFAULT 3 CHECK (max_retries >= 0),
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    dead_lettered_at TIMESTAMP WITH TIME ZONE,
+    initiated_by UUID NOT NULL,
+    approved_by UUID,
+    cancelled_by UUID,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    cancellation_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_fund_transfer_type CHECK (transfer_type IN ('investment', 'profit_distribution', 'withdrawal', 'refund', 'fee')),
+    CONSTRAINT chk_fund_transfer_status CHECK (status IN ('pending', 'processing', 'completed', 'failed', 'dead_lettered', 'cancelled'))
+);
+
+-- Create fund transfer attempts table; one row per execution of a transfer
+CREATE TABLE IF NOT EXISTS fund_transfer_attempts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transfer_id UUID NOT NULL REFERENCES fund_transfers(id) ON DELETE CASCADE,
+    attempt_number INTEGER NOT NULL CHECK (attempt_number > 0),
+    provider VARCHAR(30) NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    provider_reference VARCHAR(100),
+    error_message TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_fund_transfer_attempt_outcome CHECK (outcome IN ('succeeded', 'transient_failure', 'permanent_failure'))
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_fund_transfers_project_id ON fund_transfers(project_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_fund_transfers_due ON fund_transfers(next_attempt_at) WHERE status IN ('pending', 'failed');
+CREATE INDEX IF NOT EXISTS idx_fund_transfers_dead_lettered ON fund_transfers(dead_lettered_at DESC) WHERE status = 'dead_lettered';
+CREATE INDEX IF NOT EXISTS idx_fund_transfer_attempts_transfer_id ON fund_transfer_attempts(transfer_id, attempt_number);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_fund_transfers_updated_at
+    BEFORE UPDATE ON fund_transfers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS update_investment_reservations_updated_at ON investment_reservations;
+DROP INDEX IF EXISTS idx_investment_reservations_active_expiry;
+DROP INDEX IF EXISTS idx_investment_reservations_active_project;
+DROP TABLE IF EXISTS investment_reservations;
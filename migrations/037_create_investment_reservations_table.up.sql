@@ -0,0 +1,27 @@
+-- Create investment reservations table; a pending investment holds its amount against the project's funding goal until approval, rejection or expiry
+CREATE TABLE IF NOT EXISTS investment_reservations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    investment_id UUID NOT NULL UNIQUE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    investor_id UUID NOT NULL,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    converted_at TIMESTAMP WITH TIME ZONE,
+    released_at TIMESTAMP WITH TIME ZONE,
+    release_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_investment_reservation_status CHECK (status IN ('active', 'converted', 'released', 'expired'))
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_investment_reservations_active_project ON investment_reservations(project_id, expires_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_investment_reservations_active_expiry ON investment_reservations(expires_at) WHERE status = 'active';
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_investment_reservations_updated_at
+    BEFORE UPDATE ON investment_reservations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,40 @@
+CREATE OR REPLACE FUNCTION check_journal_entry_balanced()
+RETURNS TRIGGER AS $$
+DECLARE
+    total_debit DECIMAL(15,2);
+    total_credit DECIMAL(15,2);
+BEGIN
+    SELECT COALESCE(SUM(debit), 0), COALESCE(SUM(credit), 0)
+    INTO total_debit, total_credit
+    FROM journal_lines
+    WHERE entry_id = NEW.entry_id;
+
+    IF total_debit <> total_credit THEN
+        RAISE EXCEPTION 'journal entry % is unbalanced: debits % credits %', NEW.entry_id, total_debit, total_credit;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE journal_lines
+    ALTER COLUMN credit TYPE DECIMAL(15,2),
+    ALTER COLUMN debit TYPE DECIMAL(15,2);
+
+ALTER TABLE journal_entries
+    ALTER COLUMN total_amount TYPE DECIMAL(15,2);
+
+ALTER TABLE investment_returns
+    ALTER COLUMN return_amount TYPE DECIMAL(15,2);
+
+ALTER TABLE profit_distributions
+    ALTER COLUMN total_distributed TYPE DECIMAL(15,2),
+    ALTER COLUMN business_profit TYPE DECIMAL(15,2);
+
+ALTER TABLE investments
+    ALTER COLUMN amount TYPE DECIMAL(15,2);
+
+ALTER TABLE projects
+    ALTER COLUMN current_funding TYPE DECIMAL(15,2),
+    ALTER COLUMN minimum_funding TYPE DECIMAL(15,2),
+    ALTER COLUMN funding_goal TYPE DECIMAL(15,2);
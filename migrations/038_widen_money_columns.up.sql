@@ -0,0 +1,42 @@
+-- Widen baseline and ledger money columns to hold the largest registered currency scale (4 minor units)
+ALTER TABLE projects
+    ALTER COLUMN funding_goal TYPE NUMERIC(20,4),
+    ALTER COLUMN minimum_funding TYPE NUMERIC(20,4),
+    ALTER COLUMN current_funding TYPE NUMERIC(20,4);
+
+ALTER TABLE investments
+    ALTER COLUMN amount TYPE NUMERIC(20,4);
+
+ALTER TABLE profit_distributions
+    ALTER COLUMN business_profit TYPE NUMERIC(20,4),
+    ALTER COLUMN total_distributed TYPE NUMERIC(20,4);
+
+ALTER TABLE investment_returns
+    ALTER COLUMN return_amount TYPE NUMERIC(20,4);
+
+ALTER TABLE journal_entries
+    ALTER COLUMN total_amount TYPE NUMERIC(20,4);
+
+ALTER TABLE journal_lines
+    ALTER COLUMN debit TYPE NUMERIC(20,4),
+    ALTER COLUMN credit TYPE NUMERIC(20,4);
+
+-- Sum the widened journal lines without rounding them back to two decimals
+CREATE OR REPLACE FUNCTION check_journal_entry_balanced()
+RETURNS TRIGGER AS $$
+DECLARE
+    total_debit NUMERIC(20,4);
+    total_credit NUMERIC(20,4);
+BEGIN
+    SELECT COALESCE(SUM(debit), 0), COALESCE(SUM(credit), 0)
+    INTO total_debit, total_credit
+    FROM journal_lines
+    WHERE entry_id = NEW.entry_id;
+
+    IF total_debit <> total_credit THEN
+        RAISE EXCEPTION 'journal entry % is unbalanced: debits % credits %', NEW.entry_id, total_debit, total_credit;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
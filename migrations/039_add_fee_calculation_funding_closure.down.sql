@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_project_fee_calculations_funding_closure;
+
+ALTER TABLE project_fee_calculations
+    DROP COLUMN IF EXISTS funding_closure_id;
@@ -0,0 +1,6 @@
+-- Link success fees to the funding closure they were charged on; a closure is charged at most once
+ALTER TABLE project_fee_calculations
+    ADD COLUMN IF NOT EXISTS funding_closure_id UUID;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_fee_calculations_funding_closure ON project_fee_calculations(funding_closure_id)
+    WHERE funding_closure_id IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_project_funding_closures_unnotified;
+
+ALTER TABLE project_funding_closures
+    DROP COLUMN IF EXISTS notified_at;
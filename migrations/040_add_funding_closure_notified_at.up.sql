@@ -0,0 +1,10 @@
+-- Record when a closure's owner and investors were told the outcome, so failed notifications are retried
+ALTER TABLE project_funding_closures
+    ADD COLUMN IF NOT EXISTS notified_at TIMESTAMP WITH TIME ZONE;
+
+-- Closures already finished were notified when they closed
+UPDATE project_funding_closures SET notified_at = closed_at
+WHERE outcome = 'minimum_not_met' OR fee_calculation_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_project_funding_closures_unnotified ON project_funding_closures(closed_at)
+    WHERE notified_at IS NULL;
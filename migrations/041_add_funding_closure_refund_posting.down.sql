@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_project_funding_closures_refund_unposted;
+
+ALTER TABLE project_funding_closures
+    DROP COLUMN IF EXISTS refund_posted_at;
+
+DROP INDEX IF EXISTS idx_journal_entries_fund_refund;
@@ -0,0 +1,10 @@
+-- A fund refund is paid out of escrow by exactly one journal entry
+CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_entries_fund_refund ON journal_entries(reference_id)
+    WHERE reference_type = 'fund_refund' AND entry_type = 'refund';
+
+-- Record when a closure's minimum funding refund was posted to the ledger, so failed postings are retried
+ALTER TABLE project_funding_closures
+    ADD COLUMN IF NOT EXISTS refund_posted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_project_funding_closures_refund_unposted ON project_funding_closures(closed_at)
+    WHERE fund_refund_id IS NOT NULL AND refund_posted_at IS NULL;
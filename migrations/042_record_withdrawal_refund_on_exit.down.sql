@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_investment_withdrawals_completing;
+
+ALTER TABLE investment_withdrawals
+    DROP CONSTRAINT IF EXISTS chk_investment_withdrawal_completed;
+
+ALTER TABLE investment_withdrawals
+    ADD CONSTRAINT chk_investment_withdrawal_completed
+    CHECK ((status = 'completed') = (fund_refund_id IS NOT NULL)) NOT VALID;
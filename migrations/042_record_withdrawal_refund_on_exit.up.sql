@@ -0,0 +1,13 @@
+-- A withdrawal's refund is recorded in the transaction that takes its capital out of the project,
+-- so a completing withdrawal already has one. Withdrawals left completing without a refund before
+-- this change are not re-checked.
+ALTER TABLE investment_withdrawals
+    DROP CONSTRAINT IF EXISTS chk_investment_withdrawal_completed;
+
+ALTER TABLE investment_withdrawals
+    ADD CONSTRAINT chk_investment_withdrawal_completed
+    CHECK ((status IN ('completing', 'completed')) = (fund_refund_id IS NOT NULL)) NOT VALID;
+
+-- Find withdrawals whose refund still has to be posted and paid out
+CREATE INDEX IF NOT EXISTS idx_investment_withdrawals_completing ON investment_withdrawals(updated_at)
+    WHERE status = 'completing';
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_stake_trades_completing;
+
+DROP INDEX IF EXISTS idx_journal_entries_stake_trade;
+
+ALTER TABLE stake_trades
+    DROP CONSTRAINT IF EXISTS chk_stake_trade_completed;
+
+ALTER TABLE stake_trades
+    ADD CONSTRAINT chk_stake_trade_completed
+    CHECK ((status = 'completed') = (buyer_investment_id IS NOT NULL)) NOT VALID;
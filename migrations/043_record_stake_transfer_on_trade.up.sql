@@ -0,0 +1,16 @@
+-- A stake trade moves its capital in the transaction that approves it, so a completing trade already
+-- has the buyer's investment. Trades left completing before this change are not re-checked.
+ALTER TABLE stake_trades
+    DROP CONSTRAINT IF EXISTS chk_stake_trade_completed;
+
+ALTER TABLE stake_trades
+    ADD CONSTRAINT chk_stake_trade_completed
+    CHECK ((status IN ('completing', 'completed')) = (buyer_investment_id IS NOT NULL)) NOT VALID;
+
+-- A stake trade is recorded in the ledger by exactly one journal entry
+CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_entries_stake_trade ON journal_entries(reference_id)
+    WHERE reference_type = 'stake_trade' AND entry_type = 'stake_transfer';
+
+-- Find trades whose transfer still has to be recorded in the ledger
+CREATE INDEX IF NOT EXISTS idx_stake_trades_completing ON stake_trades(updated_at)
+    WHERE status = 'completing';